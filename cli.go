@@ -0,0 +1,331 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"market-watch-go/internal/app"
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+)
+
+// commandUsage lists the subcommands; serve is the default when the first argument is a flag or missing
+const commandUsage = `Usage: market-watch [command] [flags]
+
+Commands:
+  serve          Run the collector, schedulers and web server (default)
+  backfill       Fill the gaps in the history of watched symbols
+  scan-patterns  Run pattern detection once
+  export         Write stored data to CSV or Parquet
+  import         Load CSV or Parquet data
+  cleanup        Compact and delete data and archived records past the retention policy
+  migrate        Apply pending schema migrations
+
+Run "market-watch <command> -h" for a command's flags.
+`
+
+// runCommand runs an admin subcommand against the configured database without starting the web server
+func runCommand(command string, args []string) error {
+	switch command {
+	case "export", "import":
+		return runDataCommand(command, args)
+	case "backfill":
+		return runBackfill(args)
+	case "scan-patterns":
+		return runScanPatterns(args)
+	case "cleanup":
+		return runCleanup(args)
+	case "migrate":
+		return runMigrateCommand(args)
+	case "help", "-h", "--help":
+		fmt.Print(commandUsage)
+		return nil
+	default:
+		fmt.Fprint(os.Stderr, commandUsage)
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
+
+// openCommandDatabase loads the configuration and opens its database for a subcommand
+func openCommandDatabase(configPath string, manualMigrations bool) (*config.Config, *database.Database, error) {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if manualMigrations {
+		cfg.Database.ManualMigrations = true
+	}
+
+	db, err := database.New(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+	return cfg, db, nil
+}
+
+// buildCommandApp loads the configuration and builds the server's services without starting them, so a
+// subcommand runs the same wiring, saved settings and defaults as the server
+func buildCommandApp(configPath string, configure func(*config.Config)) (*app.App, error) {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if configure != nil {
+		configure(cfg)
+	}
+
+	server, err := app.BuildServices(cfg, app.Options{ConfigPath: configPath})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build services: %w", err)
+	}
+	return server, nil
+}
+
+// closeCommandApp stops a subcommand's services and closes its database
+func closeCommandApp(server *app.App) {
+	if err := server.Shutdown(context.Background()); err != nil {
+		log.Printf("Failed to close database: %v", err)
+	}
+}
+
+// commandSymbols returns the comma-separated symbols given to a subcommand, or the watched symbols
+func commandSymbols(db *database.Database, list string) ([]string, error) {
+	if list == "" {
+		symbols, err := db.GetWatchedSymbols()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get watched symbols: %w", err)
+		}
+		return symbols, nil
+	}
+
+	var symbols []string
+	for _, symbol := range strings.Split(list, ",") {
+		if symbol = strings.ToUpper(strings.TrimSpace(symbol)); symbol != "" {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols, nil
+}
+
+// runDataCommand runs the export or import subcommand against the configured database
+func runDataCommand(command string, args []string) error {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	configPath := flags.String("config", "configs/config.yaml", "Path to configuration file")
+	dataset := flags.String("dataset", models.DatasetPrice, "Dataset: "+strings.Join(models.ExportDatasets, ", "))
+	format := flags.String("format", "", "csv or parquet (default: from the file extension, else csv)")
+	file := flags.String("file", "", "File to write (export) or read (import); default stdout/stdin")
+	symbol := flags.String("symbol", "", "Symbol to export (default: all symbols)")
+	from := flags.String("from", "", "Export start time, RFC 3339 or YYYY-MM-DD")
+	to := flags.String("to", "", "Export end time, RFC 3339 or YYYY-MM-DD")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *format == "" && strings.EqualFold(filepath.Ext(*file), ".parquet") {
+		*format = models.FormatParquet
+	}
+
+	_, db, err := openCommandDatabase(*configPath, false)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	exportService := services.NewExportService(db)
+
+	if command == "import" {
+		var in io.Reader = os.Stdin
+		if *file != "" {
+			f, err := os.Open(*file)
+			if err != nil {
+				return fmt.Errorf("failed to open %s: %w", *file, err)
+			}
+			defer f.Close()
+			in = f
+		}
+
+		result, err := exportService.Import(in, *dataset, *format)
+		if err != nil {
+			return err
+		}
+		log.Printf("Imported %d of %d %s rows", result.Imported, result.Rows, result.Dataset)
+		return nil
+	}
+
+	fromTime, toTime, err := services.ParseExportRange(*from, *to)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if *file != "" {
+		f, err := os.Create(*file)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", *file, err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	count, err := exportService.Export(out, &models.ExportRequest{
+		Dataset: *dataset,
+		Format:  *format,
+		Symbol:  *symbol,
+		From:    fromTime,
+		To:      toTime,
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("Exported %d %s rows", count, *dataset)
+	return nil
+}
+
+// runBackfill fills the gaps in the recent history of watched or given symbols
+func runBackfill(args []string) error {
+	flags := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	configPath := flags.String("config", "configs/config.yaml", "Path to configuration file")
+	days := flags.Int("days", 7, "Days of history to check for gaps")
+	symbolList := flags.String("symbols", "", "Comma-separated symbols (default: the watchlist)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *days <= 0 {
+		return fmt.Errorf("days must be positive")
+	}
+
+	server, err := buildCommandApp(*configPath, nil)
+	if err != nil {
+		return err
+	}
+	defer closeCommandApp(server)
+	collector := server.Services.Collector
+
+	symbols, err := commandSymbols(server.DB, *symbolList)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, symbol := range symbols {
+		stored, err := collector.CollectHistoricalSymbol(symbol, *days)
+		if err != nil {
+			failed++
+			fmt.Printf("%-8s failed: %v\n", symbol, err)
+			if errors.Is(err, services.ErrBudgetExhausted) {
+				break
+			}
+			continue
+		}
+		fmt.Printf("%-8s stored %d bars\n", symbol, stored)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d symbols failed", failed, len(symbols))
+	}
+	return nil
+}
+
+// runScanPatterns runs pattern detection once for watched or given symbols
+func runScanPatterns(args []string) error {
+	flags := flag.NewFlagSet("scan-patterns", flag.ContinueOnError)
+	configPath := flags.String("config", "configs/config.yaml", "Path to configuration file")
+	symbolList := flags.String("symbols", "", "Comma-separated symbols (default: the watchlist)")
+	notify := flags.Bool("notify", false, "Send email alerts for detected patterns as the server would")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	server, err := buildCommandApp(*configPath, func(cfg *config.Config) {
+		if !*notify {
+			cfg.Email.Enabled = false
+		}
+	})
+	if err != nil {
+		return err
+	}
+	defer closeCommandApp(server)
+	patternService := server.Services.Patterns
+
+	symbols, err := commandSymbols(server.DB, *symbolList)
+	if err != nil {
+		return err
+	}
+	for _, symbol := range symbols {
+		if err := patternService.AutoDetectPatternsForSymbol(context.Background(), symbol); err != nil {
+			return fmt.Errorf("pattern detection failed for %s: %w", symbol, err)
+		}
+	}
+	log.Printf("Scanned %d symbols for patterns", len(symbols))
+	return nil
+}
+
+// runCleanup applies the data retention policy once, as the nightly cleanup and purge jobs do
+func runCleanup(args []string) error {
+	flags := flag.NewFlagSet("cleanup", flag.ContinueOnError)
+	configPath := flags.String("config", "configs/config.yaml", "Path to configuration file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	server, err := buildCommandApp(*configPath, nil)
+	if err != nil {
+		return err
+	}
+	defer closeCommandApp(server)
+
+	server.Services.Collector.CleanupOldData()
+	server.Services.Collector.PurgeArchived()
+	return nil
+}
+
+// runMigrateCommand applies pending schema migrations, or only lists them with -status
+func runMigrateCommand(args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	configPath := flags.String("config", "configs/config.yaml", "Path to configuration file")
+	statusOnly := flags.Bool("status", false, "List migrations without applying pending ones")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	_, db, err := openCommandDatabase(*configPath, true)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return runMigrate(db, *statusOnly)
+}
+
+// runMigrate applies pending schema migrations unless statusOnly, then prints the state of every migration
+func runMigrate(db *database.Database, statusOnly bool) error {
+	if !statusOnly {
+		applied, err := db.Migrate()
+		if err != nil {
+			return err
+		}
+		log.Printf("Applied %d schema migrations", len(applied))
+	}
+
+	status, err := db.MigrationStatus()
+	if err != nil {
+		return err
+	}
+	for _, m := range status {
+		state := "pending"
+		if m.Applied {
+			state = "applied " + m.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%04d  %-40s %s\n", m.Version, m.Name, state)
+	}
+	return nil
+}
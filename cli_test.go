@@ -0,0 +1,209 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// writeTestConfig writes a config file for a temporary SQLite database and returns its path
+func writeTestConfig(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	yaml := `server:
+  port: 8080
+database:
+  path: ` + filepath.Join(dir, "market-watch.db") + `
+polygon:
+  api_key: test
+collection:
+  interval: 1m
+  default_watched_symbols: [AAPL, MSFT]
+data_retention:
+  days: 30
+  compact_after_days: 7
+`
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+// TestRunCommand tests subcommands are dispatched by name and unknown ones are rejected
+func TestRunCommand(t *testing.T) {
+	if err := runCommand("help", nil); err != nil {
+		t.Errorf("expected help to succeed, got %v", err)
+	}
+	if err := runCommand("frobnicate", nil); err == nil || !strings.Contains(err.Error(), `unknown command "frobnicate"`) {
+		t.Errorf("expected an unknown command error, got %v", err)
+	}
+}
+
+// TestCommandFlags tests subcommands reject bad flags and values before touching the database
+func TestCommandFlags(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.yaml")
+
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		want    string
+	}{
+		{"unknown flag", "migrate", []string{"-bogus"}, "flag provided but not defined"},
+		{"non-numeric days", "backfill", []string{"-days", "week"}, "invalid value"},
+		{"non-positive days", "backfill", []string{"-days", "0"}, "days must be positive"},
+		{"invalid notify", "scan-patterns", []string{"-notify=maybe"}, "invalid boolean value"},
+		{"missing config", "cleanup", []string{"-config", missing}, "failed to load configuration"},
+		{"missing migrate config", "migrate", []string{"-config", missing, "-status"}, "failed to load configuration"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runCommand(tt.command, tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if err := runCommand("cleanup", []string{"-h"}); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("expected -h to return flag.ErrHelp, got %v", err)
+	}
+}
+
+// TestCommandSymbols tests the symbol list given to a subcommand, falling back to the watchlist
+func TestCommandSymbols(t *testing.T) {
+	_, db, err := openCommandDatabase(writeTestConfig(t), false)
+	if err != nil {
+		t.Fatalf("openCommandDatabase failed: %v", err)
+	}
+	defer db.Close()
+	if err := db.AddWatchedSymbol("NVDA", "NVIDIA"); err != nil {
+		t.Fatalf("AddWatchedSymbol failed: %v", err)
+	}
+	watched, err := db.GetWatchedSymbols()
+	if err != nil {
+		t.Fatalf("GetWatchedSymbols failed: %v", err)
+	}
+
+	tests := []struct {
+		list string
+		want []string
+	}{
+		{"", watched},
+		{"tsla", []string{"TSLA"}},
+		{" aapl, ,msft ,", []string{"AAPL", "MSFT"}},
+		{",", nil},
+	}
+
+	for _, tt := range tests {
+		symbols, err := commandSymbols(db, tt.list)
+		if err != nil {
+			t.Fatalf("commandSymbols(%q) failed: %v", tt.list, err)
+		}
+		if !reflect.DeepEqual(symbols, tt.want) {
+			t.Errorf("commandSymbols(%q): expected %v, got %v", tt.list, tt.want, symbols)
+		}
+	}
+}
+
+// TestRunMigrateCommand tests -status only lists pending migrations and a plain run applies them
+func TestRunMigrateCommand(t *testing.T) {
+	configPath := writeTestConfig(t)
+
+	if err := runMigrateCommand([]string{"-config", configPath, "-status"}); err != nil {
+		t.Fatalf("migrate -status failed: %v", err)
+	}
+	if applied := migrationsApplied(t, configPath); applied != 0 {
+		t.Errorf("expected -status to apply nothing, got %d applied", applied)
+	}
+
+	if err := runMigrateCommand([]string{"-config", configPath}); err != nil {
+		t.Fatalf("migrate failed: %v", err)
+	}
+	if applied := migrationsApplied(t, configPath); applied == 0 {
+		t.Error("expected the migrations applied")
+	}
+
+	// Running again finds nothing pending
+	if err := runMigrateCommand([]string{"-config", configPath}); err != nil {
+		t.Fatalf("second migrate failed: %v", err)
+	}
+}
+
+// migrationsApplied returns how many migrations the configured database has applied, failing the test unless
+// it is all or none of them
+func migrationsApplied(t *testing.T, configPath string) int {
+	t.Helper()
+
+	_, db, err := openCommandDatabase(configPath, true)
+	if err != nil {
+		t.Fatalf("openCommandDatabase failed: %v", err)
+	}
+	defer db.Close()
+
+	status, err := db.MigrationStatus()
+	if err != nil {
+		t.Fatalf("MigrationStatus failed: %v", err)
+	}
+	applied := 0
+	for _, m := range status {
+		if m.Applied {
+			applied++
+		}
+	}
+	if applied != 0 && applied != len(status) {
+		t.Fatalf("expected all or none of %d migrations applied, got %d", len(status), applied)
+	}
+	return applied
+}
+
+// TestRunCleanup tests cleanup compacts the bars older than the retention policy
+func TestRunCleanup(t *testing.T) {
+	configPath := writeTestConfig(t)
+
+	_, db, err := openCommandDatabase(configPath, false)
+	if err != nil {
+		t.Fatalf("openCommandDatabase failed: %v", err)
+	}
+	now := time.Now().UTC().Truncate(time.Minute)
+	old := time.Date(now.Year(), now.Month(), now.Day(), 14, 30, 0, 0, time.UTC).AddDate(0, 0, -20)
+	var bars []*models.PriceData
+	for _, at := range []time.Time{old, old.Add(time.Minute), now.Add(-time.Hour)} {
+		bars = append(bars, &models.PriceData{Symbol: "TEST", Timestamp: at, Open: 100, High: 101, Low: 99, Close: 100, Volume: 10})
+	}
+	err = db.InsertPriceDataBatch(bars)
+	db.Close()
+	if err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+
+	if err := runCleanup([]string{"-config", configPath}); err != nil {
+		t.Fatalf("cleanup failed: %v", err)
+	}
+
+	_, db, err = openCommandDatabase(configPath, false)
+	if err != nil {
+		t.Fatalf("openCommandDatabase failed: %v", err)
+	}
+	defer db.Close()
+
+	from, to := old.AddDate(0, 0, -1), now.Add(time.Minute)
+	minutes, err := db.GetPriceDataRange("TEST", from, to)
+	if err != nil || len(minutes) != 1 || !minutes[0].Timestamp.Equal(now.Add(-time.Hour)) {
+		t.Fatalf("expected only the recent minute bar left, got %d (%v)", len(minutes), err)
+	}
+	daily, err := db.GetPriceDataRangeTimeframe("TEST", from, old.Add(24*time.Hour), models.Timeframe1d)
+	if err != nil || len(daily) != 1 || daily[0].Volume != 20 {
+		t.Errorf("expected the old bars compacted into one daily candle, got %+v (%v)", daily, err)
+	}
+}
@@ -0,0 +1,12589 @@
+// Code generated by swaggo/swag. DO NOT EDIT.
+
+package docs
+
+import "github.com/swaggo/swag"
+
+const docTemplate = `{
+    "schemes": {{ marshal .Schemes }},
+    "swagger": "2.0",
+    "info": {
+        "description": "{{escape .Description}}",
+        "title": "{{.Title}}",
+        "contact": {},
+        "version": "{{.Version}}"
+    },
+    "host": "{{.Host}}",
+    "basePath": "{{.BasePath}}",
+    "paths": {
+        "/api/v1/admin/collector/pause": {
+            "post": {
+                "description": "Skip scheduled collections and refuse forced ones until resumed, to stop market data provider requests during incidents or maintenance. Collection stats are kept; a restart resumes collection.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Pause data collection",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ScheduleStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/collector/resume": {
+            "post": {
+                "description": "Resume scheduled and forced collections paused with POST /api/v1/admin/collector/pause",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Resume data collection",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ScheduleStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/config/reload": {
+            "post": {
+                "description": "Re-read and validate the config file, then apply the collection interval, default watched symbols, email and Telegram notification settings and logging level without a restart. Other changed sections are listed as needing a restart; an invalid file changes nothing.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reload config file",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ConfigReloadResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/jobs/pause": {
+            "post": {
+                "description": "Disable every schedule and job type except collection. Queued jobs wait and running items finish.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Pause background jobs",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/jobs/resume": {
+            "post": {
+                "description": "Enable every schedule and job type except collection",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Resume background jobs",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/schedules": {
+            "get": {
+                "description": "List the background schedules and job types, whether each is enabled and the runs skipped while disabled",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List background schedules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/schedules/{name}": {
+            "put": {
+                "description": "Enable or disable one schedule or job type: collection, cleanup, pattern_scan, backfill, indicator_recompute, news, calendar, reference_data, symbol_stats, digest or automations. Disabled job types keep their queued jobs until enabled.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Enable or disable a background schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schedule name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Whether the schedule runs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.scheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ScheduleStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/alerts": {
+            "get": {
+                "description": "Get all alert rules, optionally only active ones",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "List alert rules",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only return active rules",
+                        "name": "active",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AlertRule"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a rule such as \"rsi_14 \u003c 30 AND support_distance_percent \u003c= 1\". Conditions compare a field with a value, or with another field's value via value_field (e.g. price \u003e vwap). Legs in then are matched in order after the conditions, each within_minutes of the previous leg last matching, and the rule fires when the last leg matches.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Create an alert rule",
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.AlertRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/alerts/evaluate": {
+            "post": {
+                "description": "Evaluate all active alert rules against watched symbols without waiting for the next collection cycle",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Evaluate alert rules now",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/alerts/history": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Get trigger history for all alert rules",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by symbol",
+                        "name": "symbol",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of triggers",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AlertTrigger"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/alerts/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Get an alert rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AlertRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an alert rule; sequences in progress start over",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Update an alert rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AlertRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete an alert rule and its trigger history",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Delete an alert rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/alerts/{id}/history": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Get trigger history for an alert rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of triggers",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AlertTrigger"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/alerts/{id}/state": {
+            "get": {
+                "description": "Get how many legs of a sequenced rule each symbol has matched, when the last one matched and when the next leg's window closes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Get the sequences in progress of an alert rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/analytics/sectors": {
+            "get": {
+                "description": "Group watchlist stocks by sector, compute each stock's return minus the benchmark return over each window of daily sessions, and rank sectors by their average relative strength",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analytics"
+                ],
+                "summary": "Rank watchlist sectors by relative strength",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated windows in trading days (default from config, e.g. 5,20,60)",
+                        "name": "windows",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Benchmark symbol (default from config, e.g. SPY)",
+                        "name": "benchmark",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SectorStrengthReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/anomalies": {
+            "get": {
+                "description": "Get flagged volume spikes, price gaps and potential halts, most recent bar first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "anomalies"
+                ],
+                "summary": "List market anomalies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by symbol",
+                        "name": "symbol",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by type: volume_spike, price_gap, halt",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only anomalies on bars of the last N hours (default 24, max 720)",
+                        "name": "hours",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of anomalies (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/anomalies/scan": {
+            "post": {
+                "description": "Check the latest bars of a symbol, or of every watched symbol, for volume spikes, price gaps and potential halts. Only anomalies not flagged before are returned and notified.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "anomalies"
+                ],
+                "summary": "Scan for market anomalies now",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Symbol to scan (default all watched symbols)",
+                        "name": "symbol",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/anomalies/{symbol}": {
+            "get": {
+                "description": "Get the volume spikes, price gaps and potential halts flagged on a symbol's bars, most recent bar first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "anomalies"
+                ],
+                "summary": "List a symbol's market anomalies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by type: volume_spike, price_gap, halt",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only anomalies on bars of the last N hours (default 24, max 720)",
+                        "name": "hours",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of anomalies (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/audit": {
+            "get": {
+                "description": "Get the recorded POST, PUT, PATCH and DELETE requests, newest first, with their actor (the X-Actor header or client IP), payload with secrets redacted, status and error",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "List audit log entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by actor",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by HTTP method",
+                        "name": "method",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by path prefix, e.g. /api/v1/watchlist",
+                        "name": "path",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only successful or only failed requests",
+                        "name": "success",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start time (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End time (RFC3339 or YYYY-MM-DD, inclusive)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entries per page (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AuditLogResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/broker/orders": {
+            "get": {
+                "description": "Get the bracket orders placed for triggered setups, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "broker"
+                ],
+                "summary": "List broker orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by status: submitted, filled, closed, canceled, failed",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by symbol",
+                        "name": "symbol",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only orders with working legs at the broker",
+                        "name": "open",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of orders (default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/broker/orders/sync": {
+            "post": {
+                "description": "Refresh open orders from the broker without waiting for the next interval; a filled entry opens a portfolio position and a filled stop or target closes it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "broker"
+                ],
+                "summary": "Sync broker orders now",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/broker/orders/{id}/cancel": {
+            "post": {
+                "description": "Cancel a bracket order whose entry has not filled, along with its stop and target",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "broker"
+                ],
+                "summary": "Cancel a broker order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Broker order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BrokerOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/broker/status": {
+            "get": {
+                "description": "Get whether orders are placed with the broker, on the paper or live account, which strategies trade automatically and the last order sync",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "broker"
+                ],
+                "summary": "Get the broker status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BrokerStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/calendar": {
+            "get": {
+                "description": "Get earnings and economic events over the next number of days",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "List upcoming calendar events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by symbol (market-wide events are included)",
+                        "name": "symbol",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by event type: earnings, economic",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of days ahead (default 30)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CalendarEvent"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/calendar/events": {
+            "post": {
+                "description": "Add a manual earnings date or a market-wide economic event (leave symbol empty)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "Add a calendar event",
+                "parameters": [
+                    {
+                        "description": "Calendar event",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.addEventRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.CalendarEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/calendar/events/{id}": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "Delete a calendar event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/calendar/refresh": {
+            "post": {
+                "description": "Fetch upcoming earnings dates for all watched symbols from Polygon",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "Refresh earnings dates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/calendar/{symbol}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "Get upcoming events for a symbol",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of days ahead (default 30)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CalendarEvent"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/charts/{symbol}": {
+            "get": {
+                "description": "Get the annotations and layout a user saved for a symbol's chart, so the dashboard can restore them. The user is named by the X-Actor header.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "charts"
+                ],
+                "summary": "Get a user's saved chart of a symbol",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User, defaults to 'default'",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/charts/{symbol}/annotations": {
+            "get": {
+                "description": "List the trend lines, levels and notes a user drew on a symbol's chart, in the order they were drawn",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "charts"
+                ],
+                "summary": "List a user's annotations of a symbol's chart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only annotations drawn on this timeframe or on every timeframe",
+                        "name": "timeframe",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User, defaults to 'default'",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Save a trend line (2 points), level (price) or note (text at 1 point) a user drew on a symbol's chart",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "charts"
+                ],
+                "summary": "Save an annotation on a symbol's chart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User, defaults to 'default'",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Annotation",
+                        "name": "annotation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.chartAnnotationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChartAnnotation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete all of a user's annotations on a symbol's chart, keeping its layout",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "charts"
+                ],
+                "summary": "Delete every annotation from a symbol's chart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User, defaults to 'default'",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/charts/{symbol}/annotations/{id}": {
+            "put": {
+                "description": "Replace the drawing of one of a user's annotations on a symbol's chart, such as after it was moved",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "charts"
+                ],
+                "summary": "Update an annotation on a symbol's chart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Annotation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User, defaults to 'default'",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Annotation",
+                        "name": "annotation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.chartAnnotationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChartAnnotation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete one of a user's annotations on a symbol's chart",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "charts"
+                ],
+                "summary": "Delete an annotation from a symbol's chart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Annotation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User, defaults to 'default'",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/charts/{symbol}/layout": {
+            "get": {
+                "description": "Get the layout preferences, such as timeframe, indicators and zoom, a user saved for a symbol's chart",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "charts"
+                ],
+                "summary": "Get a user's layout of a symbol's chart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User, defaults to 'default'",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChartLayout"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Save the layout preferences of a symbol's chart as a JSON object, replacing the ones saved before",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "charts"
+                ],
+                "summary": "Save a user's layout of a symbol's chart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User, defaults to 'default'",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Layout",
+                        "name": "layout",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.chartLayoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChartLayout"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete the layout preferences a user saved for a symbol's chart, so the dashboard falls back to its defaults",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "charts"
+                ],
+                "summary": "Delete a user's layout of a symbol's chart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User, defaults to 'default'",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/compare": {
+            "get": {
+                "description": "Get each symbol's percent change over the range from its stored regular session bars, one value per timestamp shared by every series, so the series chart together without downloading candles. Series start at the first timestamp every symbol has a close at.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analytics"
+                ],
+                "summary": "Compare the relative performance of symbols",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated symbols, up to 10",
+                        "name": "symbols",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "1M",
+                        "description": "Range: 1D, 1W, 2W, 1M, 3M, 6M or 1Y",
+                        "name": "range",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Candle timeframe: 1m, 5m, 15m, 1h or 1d (default picked from the range)",
+                        "name": "timeframe",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RelativePerformance"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/dashboard/overview": {
+            "get": {
+                "description": "Top gainers and losers among watched symbols on the latest trading date, volume leaders, open setups by status, active patterns by phase, recent alerts, market anomalies of the last day and collector health in one response",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dashboard"
+                ],
+                "summary": "Dashboard overview",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Symbols per mover list (default 5, max 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DashboardOverview"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/debug/coverage": {
+            "get": {
+                "description": "Report how much of each trading day's regular session the stored bars cover, with the missing ranges. Weekends and exchange holidays expect no bars.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "debug"
+                ],
+                "summary": "Data coverage per symbol",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Trading history to check in days (default 5, max 60)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only check this symbol (default: all watched symbols)",
+                        "name": "symbol",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/export/{dataset}": {
+            "get": {
+                "description": "Download price or volume bars, support/resistance levels, setups or patterns as CSV or Parquet",
+                "produces": [
+                    "text/csv",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Export a dataset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dataset: price, volume, levels, setups or patterns",
+                        "name": "dataset",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stock symbol (default: all symbols)",
+                        "name": "symbol",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start time, RFC 3339 or YYYY-MM-DD (default: all history)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End time, RFC 3339 or YYYY-MM-DD (default: now)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv (default) or parquet",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/head-shoulders/patterns": {
+            "get": {
+                "description": "Get all head and shoulders patterns with filtering options",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "head-shoulders"
+                ],
+                "summary": "Get all head and shoulders patterns",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by symbol",
+                        "name": "symbol",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by pattern type: 'inverse_head_shoulders' or 'head_shoulders'",
+                        "name": "pattern_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by phase: 'formation', 'breakout', 'target_pursuit', 'completed'",
+                        "name": "phase",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by completion status",
+                        "name": "is_complete",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum symmetry score",
+                        "name": "min_symmetry",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Maximum number of patterns to return",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.HeadShouldersPattern"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/head-shoulders/patterns/monitor": {
+            "post": {
+                "description": "Manually trigger the monitoring cycle for all active head and shoulders patterns",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "head-shoulders"
+                ],
+                "summary": "Trigger monitoring of all active patterns",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/head-shoulders/patterns/stats": {
+            "get": {
+                "description": "Get comprehensive statistics about all head and shoulders patterns",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "head-shoulders"
+                ],
+                "summary": "Get pattern statistics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/head-shoulders/patterns/{id}/alerts": {
+            "get": {
+                "description": "Get all alerts generated for a specific head and shoulders pattern",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "head-shoulders"
+                ],
+                "summary": "Get alerts for a pattern",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pattern ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PatternAlert"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/head-shoulders/patterns/{id}/details": {
+            "get": {
+                "description": "Get comprehensive details about a head and shoulders pattern including thesis components",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "head-shoulders"
+                ],
+                "summary": "Get detailed information about a specific pattern",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pattern ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HeadShouldersPattern"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/head-shoulders/patterns/{id}/performance": {
+            "get": {
+                "description": "Get performance analysis for a specific head and shoulders pattern",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "head-shoulders"
+                ],
+                "summary": "Get pattern performance analysis",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pattern ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/head-shoulders/patterns/{id}/thesis": {
+            "get": {
+                "description": "Get all thesis components with their completion status for a specific pattern",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "head-shoulders"
+                ],
+                "summary": "Get thesis components for a pattern",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pattern ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HeadShouldersThesis"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/head-shoulders/patterns/{id}/thesis/{component}": {
+            "put": {
+                "description": "Manually update the status of a thesis component",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "head-shoulders"
+                ],
+                "summary": "Update a specific thesis component",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pattern ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Component name",
+                        "name": "component",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Component update data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/head-shoulders/patterns/{symbol}": {
+            "get": {
+                "description": "Get all head and shoulders patterns for a specific symbol",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "head-shoulders"
+                ],
+                "summary": "Get patterns for a specific symbol",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by phase",
+                        "name": "phase",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by completion status",
+                        "name": "is_complete",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.HeadShouldersPattern"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/head-shoulders/patterns/{symbol}/detect": {
+            "post": {
+                "description": "Trigger head and shoulders pattern detection for a specific symbol",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "head-shoulders"
+                ],
+                "summary": "Manually trigger pattern detection for a symbol",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "'inverse_head_shoulders' (default, bullish) or 'head_shoulders' (bearish)",
+                        "name": "pattern_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bar size: 1m, 5m, 15m, 1h or 1d (default: configured bar_interval)",
+                        "name": "timeframe",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Days of bars to scan (default: configured lookback_days)",
+                        "name": "lookback_days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "0.25-4; above 1 finds smaller swings and shallower heads (default: configured sensitivity)",
+                        "name": "sensitivity",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HeadShouldersPattern"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/import/{dataset}": {
+            "post": {
+                "description": "Load a CSV or Parquet file produced by the export endpoint or an external dataset. The file is sent as the request body or as the multipart field \"file\". Price and volume bars replace bars at the same timestamp; levels, setups and patterns are added as new rows.",
+                "consumes": [
+                    "text/csv",
+                    "application/octet-stream",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Import a dataset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dataset: price, volume, levels, setups or patterns",
+                        "name": "dataset",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv (default) or parquet",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/indicators/{symbol}/macd": {
+            "get": {
+                "description": "Get the MACD line, signal line and histogram for every bar in a date range, for charting",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "technical-analysis"
+                ],
+                "summary": "Get historical MACD series for a symbol",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (RFC3339 format)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (RFC3339 format)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Candle timeframe (1m, 5m, 15m, 1h, 1d)",
+                        "name": "timeframe",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MACDPoint"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/insiders/{symbol}": {
+            "get": {
+                "description": "Get a stock's SEC Form 4 insider transactions, newest first, with open market purchase and sale totals and whether insiders are cluster buying",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "insiders"
+                ],
+                "summary": "Get a stock's insider transactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Days of transactions (default 90)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Fetch new Form 4 filings from EDGAR first",
+                        "name": "refresh",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.InsiderSummary"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/jobs": {
+            "get": {
+                "description": "List recent background jobs, newest first, without per-item results",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "List jobs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Job status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Maximum number of jobs",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/jobs/backfill": {
+            "post": {
+                "description": "Backfill volume and price history for the given symbols, or all watched symbols and the market benchmarks",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Queue a historical backfill",
+                "parameters": [
+                    {
+                        "description": "Days and symbols",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.backfillRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/jobs/indicators": {
+            "post": {
+                "description": "Recompute technical indicators for the given symbols, or all watched symbols",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Queue an indicator recomputation",
+                "parameters": [
+                    {
+                        "description": "Symbols",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.indicatorsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/jobs/{id}": {
+            "get": {
+                "description": "Get a job's progress and per-item results",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Get a job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Job"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/jobs/{id}/cancel": {
+            "post": {
+                "description": "Stop dispatching a job's remaining items; items already running finish",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Cancel a job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/market/context": {
+            "get": {
+                "description": "Get each benchmark's daily close against its 20 and 50 day SMAs, the share of watched symbols above their SMAs, the advance/decline of watched symbols on the latest session, and the market regime setups are scored against",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "market"
+                ],
+                "summary": "Get the market context",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Recompute instead of reusing the context from the last few minutes",
+                        "name": "refresh",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MarketContext"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/market/gaps": {
+            "get": {
+                "description": "Get watched symbols ranked by their pre-market gap from the prior regular session close, with pre-market volume and RVOL against prior sessions by the same time. Gappers meet the gap_scan thresholds.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "market"
+                ],
+                "summary": "Get the morning gap report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Trading date (YYYY-MM-DD, default the latest scan)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order by gap (default) or rvol",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GapReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/market/gaps/scan": {
+            "post": {
+                "description": "Rank today's pre-market gaps of every watched symbol and store the report, replacing the day's earlier scan, as the morning job does before the open. Tagging makes the gappers the only stocks of the gap_scan strategy (Today by default).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "market"
+                ],
+                "summary": "Scan pre-market gaps now",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Tag the gappers into the strategy (default gap_scan.auto_tag)",
+                        "name": "tag",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GapReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/market/holidays": {
+            "get": {
+                "description": "Get the full-day closures for a year",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "market"
+                ],
+                "summary": "List exchange holidays",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Year (default current year)",
+                        "name": "year",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/market/regime": {
+            "get": {
+                "description": "Classify the regime index's latest trading day as trending_up, trending_down, choppy or high_volatility from its 50-day SMA, 14-day ADX and 14-day over 50-day ATR. Setups the regime rules out are not detected.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "market"
+                ],
+                "summary": "Get the market regime",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Classify again instead of reusing the label from the last few minutes",
+                        "name": "refresh",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MarketRegimeLabel"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/market/regime/history": {
+            "get": {
+                "description": "Get the stored daily regime labels of the regime index, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "market"
+                ],
+                "summary": "List daily market regime labels",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Calendar days of history (default 90, max 365)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/market/status": {
+            "get": {
+                "description": "Get the exchange session (pre-market, regular, post-market or closed), holiday and early close information, and whether the collector is running",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "market"
+                ],
+                "summary": "Get market status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Time to evaluate instead of now (RFC3339)",
+                        "name": "at",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MarketStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/news/refresh": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "news"
+                ],
+                "summary": "Refresh news for watched symbols",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/news/{symbol}": {
+            "get": {
+                "description": "Get stored headlines with keyword sentiment tags, optionally fetching the latest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "news"
+                ],
+                "summary": "Get recent news for a symbol",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of articles (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Fetch the latest headlines before returning",
+                        "name": "refresh",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.NewsArticle"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/notifications": {
+            "get": {
+                "description": "Get notification center entries, newest first, with the number of unread ones",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "List notifications",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by category: pattern, setup, alert, anomaly, system",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only return unread notifications",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of notifications (default 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of notifications to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete every notification, or only the read ones",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Clear notifications",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only delete read notifications",
+                        "name": "read",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/notifications/read": {
+            "post": {
+                "description": "Mark every unread notification, or every unread one of a category, as read",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Mark all notifications read",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only mark this category: pattern, setup, alert, anomaly, system",
+                        "name": "category",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/notifications/{id}/read": {
+            "put": {
+                "description": "Mark one notification center entry as read",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Mark a notification read",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Notification ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/options/{symbol}/collect": {
+            "post": {
+                "description": "Fetch the options chain for a symbol and store an aggregated snapshot",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "options"
+                ],
+                "summary": "Collect an options snapshot now",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OptionsSnapshot"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/options/{symbol}/summary": {
+            "get": {
+                "description": "Get the latest options snapshot, IV rank, put/call ratios, unusual volume and snapshot history",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "options"
+                ],
+                "summary": "Get options flow summary for a symbol",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of days of history (default 30)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OptionsSummary"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/paper-trading/equity": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "paper-trading"
+                ],
+                "summary": "Get the paper trading equity curve",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of days of history (default 30)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PaperEquityPoint"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/paper-trading/reset": {
+            "post": {
+                "description": "Delete all paper trades and the equity curve",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "paper-trading"
+                ],
+                "summary": "Reset paper trading",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/paper-trading/run": {
+            "post": {
+                "description": "Simulate fills and exits on new bars and take qualifying setups without waiting for the next interval",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "paper-trading"
+                ],
+                "summary": "Run a paper trading cycle now",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaperTradingCycleResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/paper-trading/status": {
+            "get": {
+                "description": "Get simulated equity, cash, P\u0026L and trade counts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "paper-trading"
+                ],
+                "summary": "Get paper trading account status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaperTradingStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/paper-trading/trades": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "paper-trading"
+                ],
+                "summary": "List paper trades",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by status: pending, open, closed, expired, cancelled",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by symbol",
+                        "name": "symbol",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of trades (default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PaperTrade"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/paper-trading/trades/{id}/close": {
+            "post": {
+                "description": "Cancel a pending trade or close an open trade at the latest price",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "paper-trading"
+                ],
+                "summary": "Close a paper trade",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Paper trade ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaperTrade"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/patterns/head-shoulders/{id}/edits": {
+            "get": {
+                "description": "Get the audit trail of manual key point corrections of a pattern, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "patterns"
+                ],
+                "summary": "Get a head and shoulders pattern's edit history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pattern ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PatternEdit"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/patterns/head-shoulders/{id}/key-points": {
+            "put": {
+                "description": "Move the shoulders, head or reaction points of a detected pattern to the bars nearest the given timestamps, or pin the neckline. The neckline, targets, symmetry, thesis and linked setup are recalculated, the pattern is marked as human-edited so rescans keep the corrections, and the change is added to its audit trail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "patterns"
+                ],
+                "summary": "Correct a head and shoulders pattern's key points",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pattern ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Key points to move",
+                        "name": "edit",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.HeadShouldersEditRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/portfolio": {
+            "get": {
+                "description": "Get the portfolio summary with open positions valued at the latest prices",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Get portfolio overview",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/portfolio/performance": {
+            "get": {
+                "description": "Attribute realized and unrealized P\u0026L to the setup or pattern type that generated each trade",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Get performance by setup type",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SetupPerformance"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/portfolio/positions": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "List positions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by status: 'open' or 'closed'",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by symbol",
+                        "name": "symbol",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by originating setup type",
+                        "name": "setup_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of positions",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Position"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Record an entry, optionally on a detected setup. Setup levels are used for omitted fields and the setup is marked triggered.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Record a new position",
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Position"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/portfolio/positions/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Get a position",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Position ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Position"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Delete a position",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Position ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/portfolio/positions/{id}/close": {
+            "post": {
+                "description": "Close an open position. Without exit_price the latest collected price is used. A quantity below the open quantity sells part of it: the sold part is returned as its own closed position and the rest stays open.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Close a position",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Position ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Position"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reports/{period}": {
+            "get": {
+                "description": "Compile the digest emailed on a schedule: new high-quality setups, pattern activity, triggered alerts and collection health since the previous trading day (daily) or over the last seven days (weekly)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get a digest report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Report period: daily, weekly",
+                        "name": "period",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DigestReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/screener/run": {
+            "post": {
+                "description": "Scan the market or the watchlist with a saved screen or inline criteria",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "screener"
+                ],
+                "summary": "Run a screen",
+                "parameters": [
+                    {
+                        "description": "Saved screen ID or criteria",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.runScreenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ScreenerRun"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/screener/screens": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "screener"
+                ],
+                "summary": "List saved screens",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SavedScreen"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "screener"
+                ],
+                "summary": "Save a screen",
+                "parameters": [
+                    {
+                        "description": "Screen definition",
+                        "name": "screen",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SavedScreen"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SavedScreen"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/screener/screens/{id}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "screener"
+                ],
+                "summary": "Update a saved screen",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Screen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Screen definition",
+                        "name": "screen",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SavedScreen"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SavedScreen"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "screener"
+                ],
+                "summary": "Delete a saved screen",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Screen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/screener/watch": {
+            "post": {
+                "description": "Add the symbol to a watchlist strategy and start collecting its data",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "screener"
+                ],
+                "summary": "Add a screener result to the watchlist",
+                "parameters": [
+                    {
+                        "description": "Symbol and strategy",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.watchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Stock"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/settings": {
+            "get": {
+                "description": "Get the setup scoring, pattern detection, S/R detection and display settings currently in effect, keyed by section",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Get all settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/settings/{section}": {
+            "get": {
+                "description": "Get one settings section currently in effect: setups (models.SetupScoringConfig), patterns (models.PatternSettings), sr (models.SRDetectionConfig) or display (models.DisplaySettings)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Get settings section",
+                "parameters": [
+                    {
+                        "enum": [
+                            "setups",
+                            "patterns",
+                            "sr",
+                            "display"
+                        ],
+                        "type": "string",
+                        "description": "Settings section",
+                        "name": "section",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Merge a JSON object into a settings section, then validate, save and apply it without a restart. Fields left out keep their current values; saved settings override the config file on the next start. Setup scoring weights must add up to 100 and durations are in nanoseconds.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Update settings section",
+                "parameters": [
+                    {
+                        "enum": [
+                            "setups",
+                            "patterns",
+                            "sr",
+                            "display"
+                        ],
+                        "type": "string",
+                        "description": "Settings section",
+                        "name": "section",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Discard a section's saved settings and restore the config file and built-in defaults in effect at startup",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Reset settings section",
+                "parameters": [
+                    {
+                        "enum": [
+                            "setups",
+                            "patterns",
+                            "sr",
+                            "display"
+                        ],
+                        "type": "string",
+                        "description": "Settings section",
+                        "name": "section",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/setups": {
+            "get": {
+                "description": "Get a page of trading setups across all watched symbols or a specific list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setups"
+                ],
+                "summary": "Get setups for multiple symbols",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated list of symbols (optional)",
+                        "name": "symbols",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Setup type filter",
+                        "name": "setup_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Direction filter",
+                        "name": "direction",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "default": 60,
+                        "description": "Minimum quality score",
+                        "name": "min_quality",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Filter for active setups only",
+                        "name": "is_active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Include archived setups",
+                        "name": "include_archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Setups per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field: 'quality_score', 'detected_at', 'expires_at', 'symbol' or 'risk_reward_ratio'",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort order: 'asc' or 'desc'",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PagedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/setups/calibration": {
+            "get": {
+                "description": "Get the scorer in use, the active weight version and the latest calibrated versions with each checklist item's win rates, lift and weight",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setups"
+                ],
+                "summary": "Get setup score calibration",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of versions (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CalibrationStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/setups/calibration/deactivate": {
+            "post": {
+                "description": "Score setups detected from now on with the configured category weights",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setups"
+                ],
+                "summary": "Return to the default setup scorer",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/setups/calibration/run": {
+            "post": {
+                "description": "Learn checklist item weights from every setup that completed or stopped out and store them as a new version. The version is not used for scoring until it is activated, unless auto_activate is set and a version is already active.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setups"
+                ],
+                "summary": "Calibrate setup scoring weights now",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ScoringWeightVersion"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/setups/calibration/versions/{id}/activate": {
+            "post": {
+                "description": "Opt into the calibrated scorer: setups detected from now on score the sum of the calibrated weights of their completed checklist items",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setups"
+                ],
+                "summary": "Score setups with a calibrated weight version",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Weight version ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ScoringWeightVersion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/setups/cleanup": {
+            "post": {
+                "description": "Archive the setups created more than the given number of days ago. Archived setups are kept for analytics but left out of setup lists; they are deleted by the purge job once data_retention.purge_archived_after_days have passed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setups"
+                ],
+                "summary": "Archive old setups",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 90,
+                        "description": "Days to keep setups unarchived",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/setups/expire": {
+            "post": {
+                "description": "Mark old setups as expired based on their expiration time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setups"
+                ],
+                "summary": "Expire old setups",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/setups/high-quality": {
+            "get": {
+                "description": "Get only high quality trading setups (score \u003e= 80) across all symbols",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setups"
+                ],
+                "summary": "Get high quality setups",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Maximum number of setups to return",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TradingSetup"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/setups/id/{id}": {
+            "get": {
+                "description": "Get detailed information about a specific trading setup",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setups"
+                ],
+                "summary": "Get a specific trading setup by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Setup ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TradingSetup"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/setups/id/{id}/archive": {
+            "post": {
+                "description": "Archive a setup so it is left out of setup lists while its history is kept",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setups"
+                ],
+                "summary": "Archive a trading setup",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Setup ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TradingSetup"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/setups/id/{id}/checklist": {
+            "get": {
+                "description": "Get the detailed checklist for a specific setup",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setups"
+                ],
+                "summary": "Get setup checklist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Setup ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SetupChecklist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/setups/id/{id}/status": {
+            "put": {
+                "description": "Update the status of a specific trading setup",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setups"
+                ],
+                "summary": "Update the status of a trading setup",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Setup ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status update",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/setups/id/{id}/unarchive": {
+            "post": {
+                "description": "Restore an archived setup to the setup lists",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setups"
+                ],
+                "summary": "Restore an archived trading setup",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Setup ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TradingSetup"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/setups/risk": {
+            "get": {
+                "description": "Get the open risk across active and triggered setups, each sized under the risk settings, against the heat limit",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setups"
+                ],
+                "summary": "Get portfolio heat",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PortfolioHeat"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/setups/stats": {
+            "get": {
+                "description": "Get comprehensive statistics about all trading setups",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setups"
+                ],
+                "summary": "Get setup statistics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/setups/{symbol}": {
+            "get": {
+                "description": "Get all trading setups for a specific symbol with filtering options",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setups"
+                ],
+                "summary": "Get trading setups for a symbol",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Setup type filter",
+                        "name": "setup_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Direction filter: 'bullish' or 'bearish'",
+                        "name": "direction",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status filter: 'active', 'triggered', 'completed', 'stopped_out', 'invalidated', 'expired'",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum quality score",
+                        "name": "min_quality",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Confidence filter: 'high', 'medium', 'low'",
+                        "name": "confidence",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter for active setups only",
+                        "name": "is_active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Include archived setups",
+                        "name": "include_archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Setups per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field: 'quality_score', 'detected_at', 'expires_at', 'symbol' or 'risk_reward_ratio'",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort order: 'asc' or 'desc'",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SetupResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/setups/{symbol}/detect": {
+            "post": {
+                "description": "Analyze market data and detect potential trading setups with scoring. A setup at the key level of an active setup of the same type refreshes that setup instead of adding another.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setups"
+                ],
+                "summary": "Detect trading setups for a symbol",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SetupDetectionResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/setups/{symbol}/summary": {
+            "get": {
+                "description": "Get comprehensive statistics about trading setups for a symbol",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setups"
+                ],
+                "summary": "Get setup summary statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SetupSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/short-interest/{symbol}": {
+            "get": {
+                "description": "Get a stock's FINRA daily short volume over the lookback days, its latest short interest reports with days to cover and percent of float, and whether any of them is elevated",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "short-interest"
+                ],
+                "summary": "Get a stock's short volume and short interest",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Fetch the missing short volume days and the latest short interest first",
+                        "name": "refresh",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ShortInterestSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stream/status": {
+            "get": {
+                "description": "Get the number of connected streaming clients and their subscriptions",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "streaming"
+                ],
+                "summary": "Get streaming status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/support-resistance/cleanup": {
+            "post": {
+                "description": "Remove old support/resistance data based on retention policy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "support-resistance"
+                ],
+                "summary": "Cleanup old S/R data",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 90,
+                        "description": "Days to retain data",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/support-resistance/deactivate": {
+            "post": {
+                "description": "Deactivate support/resistance levels that haven't been touched recently",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "support-resistance"
+                ],
+                "summary": "Deactivate old S/R levels",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 720,
+                        "description": "Maximum age in hours for active levels",
+                        "name": "max_age_hours",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/support-resistance/detect": {
+            "post": {
+                "description": "Detect S/R levels for all watched symbols or a specific list on the shared scanner worker pool. A failing symbol is reported in the run's errors without stopping the others.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "support-resistance"
+                ],
+                "summary": "Detect support and resistance levels for multiple symbols",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated list of symbols (optional)",
+                        "name": "symbols",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Candle timeframe (1m, 5m, 15m, 1h, 1d)",
+                        "name": "timeframe",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SRDetectionRun"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/support-resistance/levels": {
+            "get": {
+                "description": "Get support and resistance levels for all watched symbols or a specific list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "support-resistance"
+                ],
+                "summary": "Get S/R levels for multiple symbols",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated list of symbols (optional)",
+                        "name": "symbols",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "both",
+                        "description": "Level type filter",
+                        "name": "level_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "default": 20,
+                        "description": "Minimum strength score",
+                        "name": "min_strength",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.SRResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/support-resistance/{symbol}/detect": {
+            "post": {
+                "description": "Analyze price data and detect new support and resistance levels",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "support-resistance"
+                ],
+                "summary": "Detect support and resistance levels for a symbol",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Candle timeframe (1m, 5m, 15m, 1h, 1d)",
+                        "name": "timeframe",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SRAnalysisResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/support-resistance/{symbol}/levels": {
+            "get": {
+                "description": "Get all active support and resistance levels for a specific symbol, with the current session's daily and weekly floor pivots (PP, R1-R3, S1-S3) in floor_pivots",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "support-resistance"
+                ],
+                "summary": "Get support and resistance levels for a symbol",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "both",
+                        "description": "Level type: 'support', 'resistance', or 'both'",
+                        "name": "level_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum strength score",
+                        "name": "min_strength",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum number of touches (default 2 for pivot levels, 0 otherwise)",
+                        "name": "min_touches",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "pivot",
+                        "description": "Level class: 'pivot', 'fibonacci' (retracements and extensions of the latest significant swing) or 'all'",
+                        "name": "class",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Levels per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field: 'strength', 'touches', 'level' or 'last_touch'",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort order: 'asc' or 'desc'",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SRResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/support-resistance/{symbol}/nearest": {
+            "get": {
+                "description": "Get the nearest support level below and resistance level above current price",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "support-resistance"
+                ],
+                "summary": "Get nearest support and resistance levels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/support-resistance/{symbol}/pivots": {
+            "get": {
+                "description": "Get detected pivot highs and lows for analysis",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "support-resistance"
+                ],
+                "summary": "Get pivot points for a symbol",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (RFC3339 format)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (RFC3339 format)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pivot type: 'high', 'low', or empty for both",
+                        "name": "pivot_type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PivotPoint"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/support-resistance/{symbol}/recalculate": {
+            "post": {
+                "description": "Detect a symbol's levels again on the sr_recalculation timeframe and diff them against the stored active levels. Levels detected for the first time are new; stored levels no longer detected are deactivated as invalidated. Significant changes are sent to the notification center.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "support-resistance"
+                ],
+                "summary": "Recalculate support and resistance levels for a symbol",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SRRecalculation"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/support-resistance/{symbol}/summary": {
+            "get": {
+                "description": "Get comprehensive statistics about support and resistance levels",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "support-resistance"
+                ],
+                "summary": "Get summary statistics for S/R levels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SRLevelSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/support-resistance/{symbol}/touches": {
+            "get": {
+                "description": "Get recent instances where price touched support or resistance levels",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "support-resistance"
+                ],
+                "summary": "Get recent touches of support/resistance levels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 24,
+                        "description": "Hours to look back for touches",
+                        "name": "hours",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Maximum number of touches to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Touch type: 'test', 'break' or 'bounce', or empty for all",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SRLevelTouch"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/support-resistance/{symbol}/zones": {
+            "get": {
+                "description": "Merge the symbol's active levels into price bands and score each zone's confluence with round numbers, the prior day high/low and session VWAP",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "support-resistance"
+                ],
+                "summary": "Get support and resistance zones",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SRZoneResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/symbols/search": {
+            "get": {
+                "description": "Search Polygon's active reference tickers by symbol or company name for the watchlist typeahead, best matches first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "symbols"
+                ],
+                "summary": "Search tickers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Symbol or company name, e.g. appl or Apple",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of results (default 10, max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SymbolSearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/symbols/stats": {
+            "get": {
+                "description": "Get the stored trading statistics of every symbol they were computed for",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "symbols"
+                ],
+                "summary": "List trading statistics of every symbol",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/symbols/stats/refresh": {
+            "post": {
+                "description": "Recompute and store the trading statistics of every watched symbol with enough daily history, as the daily job does after the close",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "symbols"
+                ],
+                "summary": "Recompute every symbol's trading statistics now",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/symbols/{symbol}/stats": {
+            "get": {
+                "description": "Get a symbol's 14-day ATR, average regular session range, gap frequency and average volume by session, as computed after the latest close. Stats never computed before are computed on request.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "symbols"
+                ],
+                "summary": "Get a symbol's trading statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Recompute the stats from the stored bars first",
+                        "name": "refresh",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SymbolStats"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/technical-analysis/cache/clear": {
+            "post": {
+                "description": "Clear expired cache entries for time series data",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "technical-analysis"
+                ],
+                "summary": "Clear technical analysis cache",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/technical-analysis/cache/status": {
+            "get": {
+                "description": "Get information about cached time series data",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "technical-analysis"
+                ],
+                "summary": "Get technical analysis cache status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/technical-analysis/indicators": {
+            "get": {
+                "description": "Get technical indicators for all watched symbols or a specific list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "technical-analysis"
+                ],
+                "summary": "Get technical indicators for multiple symbols",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated list of symbols (optional)",
+                        "name": "symbols",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.TechnicalIndicatorsResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/technical-analysis/stats": {
+            "get": {
+                "description": "Get statistics about technical indicators data",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "technical-analysis"
+                ],
+                "summary": "Get technical indicators statistics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/technical-analysis/{symbol}/alerts": {
+            "get": {
+                "description": "Check if any indicator-based alerts should be triggered for a symbol",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "technical-analysis"
+                ],
+                "summary": "Check indicator alerts for a symbol",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.IndicatorAlert"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/technical-analysis/{symbol}/alerts/active": {
+            "get": {
+                "description": "Get all active indicator alerts from the database for a symbol",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "technical-analysis"
+                ],
+                "summary": "Get active alerts for a symbol",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.IndicatorAlert"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/technical-analysis/{symbol}/cache/invalidate": {
+            "post": {
+                "description": "Remove cached time series data for a specific symbol",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "technical-analysis"
+                ],
+                "summary": "Invalidate cache for a specific symbol",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/technical-analysis/{symbol}/historical": {
+            "get": {
+                "description": "Get technical indicators for a symbol within a time range",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "technical-analysis"
+                ],
+                "summary": "Get historical technical indicators",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (RFC3339 format)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (RFC3339 format)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of records",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of records to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TechnicalIndicators"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/technical-analysis/{symbol}/indicators": {
+            "get": {
+                "description": "Get all technical indicators (RSI, MACD, MA, etc.) for a specific symbol",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "technical-analysis"
+                ],
+                "summary": "Get technical indicators for a symbol",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Candle timeframe (1m, 5m, 15m, 1h, 1d)",
+                        "name": "timeframe",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TechnicalIndicatorsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/technical-analysis/{symbol}/summary": {
+            "get": {
+                "description": "Get a comprehensive summary of technical indicators with sentiment analysis",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "technical-analysis"
+                ],
+                "summary": "Get technical indicators summary for a symbol",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Candle timeframe (1m, 5m, 15m, 1h, 1d)",
+                        "name": "timeframe",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.IndicatorSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/technical-analysis/{symbol}/update": {
+            "post": {
+                "description": "Force update of technical indicators for a specific symbol",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "technical-analysis"
+                ],
+                "summary": "Update technical indicators for a symbol",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TechnicalIndicatorsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/volume-profile/{symbol}": {
+            "get": {
+                "description": "Get a symbol's volume by price with the point of control, value area and high-volume nodes, for use as a chart overlay. Without days or buckets the stored profile for the configured window is returned, recomputed when stale; custom windows are computed on demand and not stored.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "volume"
+                ],
+                "summary": "Get volume profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock symbol",
+                        "name": "symbol",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Calendar days of bars in the profile (default from config)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of price buckets (default from config)",
+                        "name": "buckets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Recompute and store the configured profile now",
+                        "name": "refresh",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.VolumeProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/watchlist/stocks/bulk/add": {
+            "post": {
+                "description": "Add symbols to watchlist strategies in one request, reporting the outcome per symbol. Newly added symbols are collected from now on and their history is backfilled by a single job.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Add several stocks",
+                "parameters": [
+                    {
+                        "description": "Symbols, strategies, tags and backfill days",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WatchlistBulkAddRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WatchlistBulkResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/watchlist/stocks/bulk/move": {
+            "post": {
+                "description": "Move symbols out of one strategy, or all of their strategies, into another in one request, reporting the outcome per symbol",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Move several stocks",
+                "parameters": [
+                    {
+                        "description": "Symbols and strategies",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WatchlistBulkMoveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WatchlistBulkResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/watchlist/stocks/bulk/remove": {
+            "post": {
+                "description": "Remove symbols from the watchlist, or from one strategy, in one request, reporting the outcome per symbol",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Remove several stocks",
+                "parameters": [
+                    {
+                        "description": "Symbols, optional strategy and whether to stop collecting them",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WatchlistBulkRemoveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WatchlistBulkResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/watchlist/strategies/{id}/auto-trade": {
+            "put": {
+                "description": "When on, a setup that triggers on a stock of the strategy places a bracket order (entry, stop and first target) with the broker, sized by the risk settings. Orders are only placed while the broker is enabled in the config.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Turn a strategy's auto trading on or off",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Strategy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Auto trading",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.autoTradeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/watchlist/strategies/{id}/automations": {
+            "get": {
+                "description": "Get the scans and alerts attached to a watchlist strategy",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "List a strategy's automations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Strategy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Attach a pattern scan, e.g. falling wedges every weekday after the close (\"30 16 * * 1-5\"), or an alert, e.g. any member with rsi_14 \u003c 30, to a watchlist strategy. Schedules are cron expressions in exchange time.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Attach an automation to a strategy",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Strategy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.StrategyAutomation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/watchlist/strategies/{id}/automations/runs": {
+            "get": {
+                "description": "Get the runs of a strategy's automations, newest first, optionally for one automation",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Get a strategy's automation run history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Strategy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only runs of this automation",
+                        "name": "automation_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of runs",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.StrategyAutomationRun"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/watchlist/strategies/{id}/automations/{automationId}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Update a strategy automation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Strategy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Automation ID",
+                        "name": "automationId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StrategyAutomation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a strategy automation and its run history",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Delete a strategy automation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Strategy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Automation ID",
+                        "name": "automationId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/watchlist/strategies/{id}/automations/{automationId}/run": {
+            "post": {
+                "description": "Run an automation against the strategy's member stocks without waiting for its schedule; the run is recorded in the strategy's history",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Run a strategy automation now",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Strategy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Automation ID",
+                        "name": "automationId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StrategyAutomationRun"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/watchlist/strategies/{id}/regimes": {
+            "get": {
+                "description": "Get the regimes the strategy's bullish (long) and bearish (short) setups are detected in; an empty list allows any regime",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Get the market regimes a strategy trades in",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Strategy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StrategyRegimes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Detect the strategy's stocks' bullish setups only in the long regimes and bearish setups only in the short regimes (trending_up, trending_down, choppy, high_volatility). A stock in several strategies keeps a setup when any strategy trades it. Empty lists remove the declaration.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Declare the market regimes a strategy trades in",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Strategy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Regimes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.strategyRegimesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StrategyRegimes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/watchlist/strategies/{id}/vwap-setups": {
+            "get": {
+                "description": "Get whether VWAP reclaim and rejection setups are detected for the strategy's stocks, and the volume they need",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Get a strategy's VWAP setup settings",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Strategy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StrategyVWAPSetups"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Detect VWAP reclaim (long) and rejection (short) setups for the strategy's stocks during the regular session. The signal bar needs volume_multiplier times the session's average bar volume. Turning both off removes the settings.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Turn a strategy's VWAP setups on or off",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Strategy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "VWAP setup settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.vwapSetupsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StrategyVWAPSetups"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/dead-letters": {
+            "get": {
+                "description": "Get the events that could not be delivered to an endpoint after every retry, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List undelivered webhook events",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of dead letters (default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/dead-letters/{id}/retry": {
+            "post": {
+                "description": "Post a dead-lettered event once more to its endpoint with its original ID and a fresh signature. The dead letter is deleted when the endpoint accepts it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Resend an undelivered webhook event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Dead letter ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/test": {
+            "post": {
+                "description": "Post a signed test event to every configured endpoint, whatever events it subscribes to, and report which received it. Test events are not retried or dead-lettered.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Send a test webhook",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Report that the server process is up, without checking any dependency",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LivenessReport"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Check the database, the market data provider's reachability and request budget, the collector's recent runs and the collection scheduler. Responds 503 while the database or the scheduler fails; a failing provider or collector reports the server as degraded but ready. Provider checks are reused for a minute to spare its quota.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReadinessReport"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReadinessReport"
+                        }
+                    }
+                }
+            }
+        },
+        "/ws/stream": {
+            "get": {
+                "description": "Upgrade to a WebSocket that pushes new price/volume bars, indicator updates and pattern alerts.\nSend {\"action\":\"subscribe\",\"symbols\":[\"AAPL\"]} or {\"action\":\"unsubscribe\",\"symbols\":[\"AAPL\"]} to manage subscriptions; \"*\" subscribes to all symbols.",
+                "tags": [
+                    "streaming"
+                ],
+                "summary": "Stream real-time market data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated symbols to subscribe to on connect",
+                        "name": "symbols",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.addEventRequest": {
+            "type": "object",
+            "properties": {
+                "details": {
+                    "type": "string"
+                },
+                "event_date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "event_time": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "importance": {
+                    "type": "integer"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.autoTradeRequest": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.backfillRequest": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "integer"
+                },
+                "symbols": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.chartAnnotationRequest": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ChartPoint"
+                    }
+                },
+                "price": {
+                    "type": "number"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "timeframe": {
+                    "description": "empty to show on every timeframe",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.chartLayoutRequest": {
+            "type": "object",
+            "properties": {
+                "layout": {
+                    "type": "object"
+                }
+            }
+        },
+        "handlers.indicatorsRequest": {
+            "type": "object",
+            "properties": {
+                "symbols": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.runScreenRequest": {
+            "type": "object",
+            "properties": {
+                "criteria": {
+                    "$ref": "#/definitions/models.ScreenCriteria"
+                },
+                "screen_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.scheduleRequest": {
+            "type": "object",
+            "required": [
+                "enabled"
+            ],
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.strategyRegimesRequest": {
+            "type": "object",
+            "properties": {
+                "long": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "short": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.vwapSetupsRequest": {
+            "type": "object",
+            "properties": {
+                "reclaim": {
+                    "type": "boolean"
+                },
+                "rejection": {
+                    "type": "boolean"
+                },
+                "volume_multiplier": {
+                    "description": "defaults to 1.5",
+                    "type": "number"
+                }
+            }
+        },
+        "handlers.watchRequest": {
+            "type": "object",
+            "properties": {
+                "strategy_id": {
+                    "type": "integer"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AlertCondition": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "operator": {
+                    "description": "'\u003c', '\u003c=', '\u003e', '\u003e=', '=='",
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                },
+                "value_field": {
+                    "description": "field compared against instead of value",
+                    "type": "string"
+                }
+            }
+        },
+        "models.AlertRule": {
+            "type": "object",
+            "properties": {
+                "conditions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AlertCondition"
+                    }
+                },
+                "cooldown_minutes": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "last_triggered_at": {
+                    "type": "string"
+                },
+                "logic": {
+                    "description": "'AND' or 'OR'",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notify_email": {
+                    "type": "boolean"
+                },
+                "symbol": {
+                    "description": "empty applies to all watched symbols",
+                    "type": "string"
+                },
+                "then": {
+                    "description": "legs matched in order after the conditions",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AlertStep"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AlertStep": {
+            "type": "object",
+            "properties": {
+                "conditions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AlertCondition"
+                    }
+                },
+                "logic": {
+                    "description": "'AND' or 'OR'",
+                    "type": "string"
+                },
+                "within_minutes": {
+                    "description": "WithinMinutes is how long after the previous leg last matched this leg may match, 0 for no limit",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AlertTrigger": {
+            "type": "object",
+            "properties": {
+                "email_sent": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "rule_id": {
+                    "type": "integer"
+                },
+                "rule_name": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "triggered_at": {
+                    "type": "string"
+                },
+                "values": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "models.AssetClass": {
+            "type": "string",
+            "enum": [
+                "equity",
+                "crypto",
+                "forex"
+            ],
+            "x-enum-comments": {
+                "AssetClassCrypto": "X:BTCUSD",
+                "AssetClassForex": "C:EURUSD"
+            },
+            "x-enum-varnames": [
+                "AssetClassEquity",
+                "AssetClassCrypto",
+                "AssetClassForex"
+            ]
+        },
+        "models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "the X-Actor header, or the client IP without one",
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "the request body with secrets redacted, truncated",
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "route": {
+                    "description": "the matched route pattern, e.g. /api/v1/setups/:id/status",
+                    "type": "string"
+                },
+                "status_code": {
+                    "type": "integer"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AuditLogResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AuditEntry"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/models.Pagination"
+                }
+            }
+        },
+        "models.AutomationMatch": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "description": "the pattern found or the conditions met",
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "values": {
+                    "description": "alert: values of the fields in the conditions",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "models.BollingerBandsData": {
+            "type": "object",
+            "properties": {
+                "lower": {
+                    "type": "number"
+                },
+                "middle": {
+                    "type": "number"
+                },
+                "upper": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.BrokerOrder": {
+            "type": "object",
+            "properties": {
+                "broker": {
+                    "type": "string"
+                },
+                "broker_order_id": {
+                    "type": "string"
+                },
+                "client_order_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "entry_price": {
+                    "description": "limit price of the entry",
+                    "type": "number"
+                },
+                "error": {
+                    "description": "why the order failed or was canceled",
+                    "type": "string"
+                },
+                "exit_at": {
+                    "type": "string"
+                },
+                "exit_price": {
+                    "type": "number"
+                },
+                "filled_at": {
+                    "type": "string"
+                },
+                "filled_price": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "position_id": {
+                    "description": "portfolio position opened when the entry filled",
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "setup_id": {
+                    "type": "integer"
+                },
+                "side": {
+                    "description": "'long' or 'short'",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "stop_loss": {
+                    "type": "number"
+                },
+                "strategy_id": {
+                    "description": "strategy whose auto trading placed the order",
+                    "type": "integer"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "target_price": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BrokerStatus": {
+            "type": "object",
+            "properties": {
+                "auto_trade_strategies": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "broker": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_sync": {
+                    "type": "string"
+                },
+                "live": {
+                    "description": "orders go to the live account rather than the paper account",
+                    "type": "boolean"
+                },
+                "open_orders": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CalendarEvent": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "string"
+                },
+                "event_date": {
+                    "type": "string"
+                },
+                "event_time": {
+                    "description": "'bmo' (before market open), 'amc' (after market close) or a clock time",
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "importance": {
+                    "description": "0-5, higher is more market moving",
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "description": "empty for market-wide events such as FOMC or CPI",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CalibrationStatus": {
+            "type": "object",
+            "properties": {
+                "active_version": {
+                    "$ref": "#/definitions/models.ScoringWeightVersion"
+                },
+                "enabled": {
+                    "description": "weights are recomputed in the background",
+                    "type": "boolean"
+                },
+                "min_samples": {
+                    "type": "integer"
+                },
+                "resolved_setups": {
+                    "type": "integer"
+                },
+                "scorer": {
+                    "description": "'default' or 'calibrated'",
+                    "type": "string"
+                },
+                "versions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ScoringWeightVersion"
+                    }
+                }
+            }
+        },
+        "models.ChartAnnotation": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "points": {
+                    "description": "two for a trend line, one for a note",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ChartPoint"
+                    }
+                },
+                "price": {
+                    "description": "price of a level",
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "timeframe": {
+                    "description": "chart timeframe it was drawn on, empty for all",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "'trend_line', 'level' or 'note'",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ChartLayout": {
+            "type": "object",
+            "properties": {
+                "layout": {
+                    "type": "object"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ChartPoint": {
+            "type": "object",
+            "properties": {
+                "price": {
+                    "type": "number"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ChecklistItem": {
+            "type": "object",
+            "properties": {
+                "auto_detected": {
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "is_completed": {
+                    "type": "boolean"
+                },
+                "is_required": {
+                    "type": "boolean"
+                },
+                "last_checked": {
+                    "type": "string"
+                },
+                "manual_override": {
+                    "type": "boolean"
+                },
+                "max_points": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "number"
+                },
+                "threshold": {
+                    "description": "What the value had to meet",
+                    "type": "string"
+                },
+                "value": {
+                    "description": "Value measured when the item was last checked",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ChecklistItemWeight": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "integer"
+                },
+                "completed_win_rate": {
+                    "description": "posterior win rate of setups completing the item",
+                    "type": "number"
+                },
+                "completed_wins": {
+                    "type": "integer"
+                },
+                "key": {
+                    "description": "the checklist item's JSON key, e.g. 'volume_spike'",
+                    "type": "string"
+                },
+                "lift": {
+                    "description": "completed over missed win rate; above 1 the item predicts wins",
+                    "type": "number"
+                },
+                "missed": {
+                    "type": "integer"
+                },
+                "missed_win_rate": {
+                    "description": "posterior win rate of setups missing it",
+                    "type": "number"
+                },
+                "missed_wins": {
+                    "type": "integer"
+                },
+                "points": {
+                    "description": "weight out of 100 across all items",
+                    "type": "number"
+                }
+            }
+        },
+        "models.CollectorHealth": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "why the collector is unhealthy",
+                    "type": "string"
+                },
+                "failed_runs": {
+                    "type": "integer"
+                },
+                "healthy": {
+                    "type": "boolean"
+                },
+                "is_running": {
+                    "type": "boolean"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_run": {
+                    "type": "string"
+                },
+                "latest_bar": {
+                    "description": "newest stored bar of any watched symbol",
+                    "type": "string"
+                },
+                "next_run": {
+                    "type": "string"
+                },
+                "successful_runs": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ConfidenceFactor": {
+            "type": "object",
+            "properties": {
+                "contribution": {
+                    "type": "number"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "weight": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.ConfigReloadResult": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "description": "settings now in effect, e.g. collection.interval",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "path": {
+                    "type": "string"
+                },
+                "reloaded_at": {
+                    "type": "string"
+                },
+                "restart_required": {
+                    "description": "changed sections that only take effect after a restart",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.DashboardOverview": {
+            "type": "object",
+            "properties": {
+                "collector": {
+                    "$ref": "#/definitions/models.CollectorHealth"
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "patterns_by_phase": {
+                    "description": "incomplete patterns of every family",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "recent_alerts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Notification"
+                    }
+                },
+                "recent_anomalies": {
+                    "description": "anomalies on bars of the last day",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MarketAnomaly"
+                    }
+                },
+                "setups_by_status": {
+                    "description": "active and triggered setups",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "top_gainers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SymbolMover"
+                    }
+                },
+                "top_losers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SymbolMover"
+                    }
+                },
+                "trading_date": {
+                    "description": "US/Eastern date of the newest bar, YYYY-MM-DD",
+                    "type": "string"
+                },
+                "volume_leaders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SymbolMover"
+                    }
+                },
+                "watched_symbols": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DependencyCheck": {
+            "type": "object",
+            "properties": {
+                "cached": {
+                    "description": "the result of an earlier check, to spare the provider's quota",
+                    "type": "boolean"
+                },
+                "checked_at": {
+                    "type": "string"
+                },
+                "critical": {
+                    "description": "the server is not ready while a critical dependency fails",
+                    "type": "boolean"
+                },
+                "details": {},
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DigestCollection": {
+            "type": "object",
+            "properties": {
+                "failed_runs": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_run": {
+                    "type": "string"
+                },
+                "stale_symbols": {
+                    "description": "watched symbols without a bar during the period",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "successful_runs": {
+                    "description": "since the server started",
+                    "type": "integer"
+                },
+                "watched_symbols": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DigestPattern": {
+            "type": "object",
+            "properties": {
+                "completion_percent": {
+                    "type": "number"
+                },
+                "detected_at": {
+                    "type": "string"
+                },
+                "family": {
+                    "description": "'head_shoulders', 'falling_wedge', 'triangle' or 'flag'",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_updated": {
+                    "type": "string"
+                },
+                "new": {
+                    "description": "detected during the period",
+                    "type": "boolean"
+                },
+                "pattern_type": {
+                    "type": "string"
+                },
+                "phase": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DigestReport": {
+            "type": "object",
+            "properties": {
+                "alerts": {
+                    "description": "alert rule triggers, newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AlertTrigger"
+                    }
+                },
+                "collection": {
+                    "$ref": "#/definitions/models.DigestCollection"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "min_quality_score": {
+                    "type": "number"
+                },
+                "patterns": {
+                    "description": "patterns detected or moved past formation, most recent first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DigestPattern"
+                    }
+                },
+                "period": {
+                    "description": "'daily' or 'weekly'",
+                    "type": "string"
+                },
+                "setups": {
+                    "description": "new setups at or above the minimum quality score, best first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TradingSetup"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Stable error code clients can branch on, e.g. not_found",
+                    "type": "string"
+                },
+                "details": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FibonacciSwing": {
+            "type": "object",
+            "properties": {
+                "direction": {
+                    "description": "'up' or 'down'",
+                    "type": "string"
+                },
+                "end_price": {
+                    "type": "number"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "start_price": {
+                    "type": "number"
+                },
+                "start_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FloorPivotLevel": {
+            "type": "object",
+            "properties": {
+                "level": {
+                    "type": "number"
+                },
+                "level_class": {
+                    "description": "Always SRClassFloorPivot",
+                    "type": "string"
+                },
+                "level_type": {
+                    "description": "'support', 'resistance' or 'pivot'",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "PP, R1-R3 or S1-S3",
+                    "type": "string"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "trading_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GapReport": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GapScanEntry"
+                    }
+                },
+                "gappers": {
+                    "type": "integer"
+                },
+                "min_gap_percent": {
+                    "type": "number"
+                },
+                "min_rvol": {
+                    "type": "number"
+                },
+                "scanned_at": {
+                    "type": "string"
+                },
+                "skipped": {
+                    "description": "symbols without a prior close or pre-market bars",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "strategy": {
+                    "description": "watchlist strategy the gappers were tagged into",
+                    "type": "string"
+                },
+                "trade_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GapScanEntry": {
+            "type": "object",
+            "properties": {
+                "gap_percent": {
+                    "type": "number"
+                },
+                "gapper": {
+                    "description": "meets the gap and RVOL thresholds",
+                    "type": "boolean"
+                },
+                "pre_market_high": {
+                    "type": "number"
+                },
+                "pre_market_low": {
+                    "type": "number"
+                },
+                "pre_market_price": {
+                    "description": "latest pre-market close",
+                    "type": "number"
+                },
+                "pre_market_volume": {
+                    "type": "integer"
+                },
+                "prev_close": {
+                    "type": "number"
+                },
+                "rank": {
+                    "description": "1 for the largest gap either way",
+                    "type": "integer"
+                },
+                "rvol": {
+                    "description": "pre-market volume against prior sessions by the same time, 0 when unknown",
+                    "type": "number"
+                },
+                "scanned_at": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "trade_date": {
+                    "description": "YYYY-MM-DD, exchange time",
+                    "type": "string"
+                }
+            }
+        },
+        "models.HeadShouldersEditRequest": {
+            "type": "object",
+            "properties": {
+                "annotation": {
+                    "type": "string"
+                },
+                "editor": {
+                    "description": "who edited it",
+                    "type": "string"
+                },
+                "head": {
+                    "type": "string"
+                },
+                "left_reaction": {
+                    "type": "string"
+                },
+                "left_shoulder": {
+                    "type": "string"
+                },
+                "neckline_level": {
+                    "description": "NecklineLevel pins the neckline instead of averaging the two reaction points",
+                    "type": "number"
+                },
+                "note": {
+                    "description": "why the pattern was edited, kept in the audit trail",
+                    "type": "string"
+                },
+                "right_reaction": {
+                    "type": "string"
+                },
+                "right_shoulder": {
+                    "type": "string"
+                },
+                "timeframe": {
+                    "description": "Timeframe is the bar size the points are read from, the configured bar interval when empty",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Timeframe"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.HeadShouldersPattern": {
+            "type": "object",
+            "properties": {
+                "alert_count": {
+                    "description": "Pattern alerts triggered so far",
+                    "type": "integer"
+                },
+                "annotation": {
+                    "type": "string"
+                },
+                "archived": {
+                    "description": "Archived records are kept for analytics but left out of lists unless asked for",
+                    "type": "boolean"
+                },
+                "archived_at": {
+                    "type": "string"
+                },
+                "current_phase": {
+                    "type": "string"
+                },
+                "detected_at": {
+                    "description": "Status",
+                    "type": "string"
+                },
+                "head_high": {
+                    "$ref": "#/definitions/models.PatternPoint"
+                },
+                "head_low": {
+                    "$ref": "#/definitions/models.PatternPoint"
+                },
+                "human_edited": {
+                    "description": "Manual corrections: an edited pattern keeps its key points when rescans find the formation again",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_complete": {
+                    "type": "boolean"
+                },
+                "last_updated": {
+                    "type": "string"
+                },
+                "left_shoulder_high": {
+                    "description": "Pattern Points",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PatternPoint"
+                        }
+                    ]
+                },
+                "left_shoulder_low": {
+                    "$ref": "#/definitions/models.PatternPoint"
+                },
+                "neckline_level": {
+                    "description": "Neckline Analysis",
+                    "type": "number"
+                },
+                "neckline_slope": {
+                    "type": "number"
+                },
+                "neckline_touch1": {
+                    "$ref": "#/definitions/models.PatternPoint"
+                },
+                "neckline_touch2": {
+                    "$ref": "#/definitions/models.PatternPoint"
+                },
+                "pattern_height": {
+                    "type": "number"
+                },
+                "pattern_type": {
+                    "description": "\"inverse_head_shoulders\" or \"head_shoulders\"",
+                    "type": "string"
+                },
+                "pattern_width": {
+                    "description": "Pattern Measurements",
+                    "type": "integer"
+                },
+                "right_shoulder_high": {
+                    "$ref": "#/definitions/models.PatternPoint"
+                },
+                "right_shoulder_low": {
+                    "$ref": "#/definitions/models.PatternPoint"
+                },
+                "setup_id": {
+                    "type": "integer"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "symmetry": {
+                    "description": "0-100 score",
+                    "type": "number"
+                },
+                "thesis_components": {
+                    "description": "Thesis Tracking",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.HeadShouldersThesis"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.HeadShouldersThesis": {
+            "type": "object",
+            "properties": {
+                "breakout_volume": {
+                    "$ref": "#/definitions/models.ThesisComponent"
+                },
+                "completed_components": {
+                    "description": "Overall Status",
+                    "type": "integer"
+                },
+                "completion_percent": {
+                    "type": "number"
+                },
+                "current_phase": {
+                    "type": "string"
+                },
+                "full_target": {
+                    "$ref": "#/definitions/models.ThesisComponent"
+                },
+                "head_formed": {
+                    "description": "Head Components",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ThesisComponent"
+                        }
+                    ]
+                },
+                "head_higher_high": {
+                    "description": "For regular H\u0026S",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ThesisComponent"
+                        }
+                    ]
+                },
+                "head_lower_low": {
+                    "description": "For inverse H\u0026S",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ThesisComponent"
+                        }
+                    ]
+                },
+                "head_volume_spike": {
+                    "$ref": "#/definitions/models.ThesisComponent"
+                },
+                "left_shoulder_formed": {
+                    "description": "Left Shoulder Components",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ThesisComponent"
+                        }
+                    ]
+                },
+                "left_shoulder_volume": {
+                    "$ref": "#/definitions/models.ThesisComponent"
+                },
+                "neckline_breakout": {
+                    "$ref": "#/definitions/models.ThesisComponent"
+                },
+                "neckline_established": {
+                    "description": "Neckline Components",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ThesisComponent"
+                        }
+                    ]
+                },
+                "neckline_retest": {
+                    "$ref": "#/definitions/models.ThesisComponent"
+                },
+                "partial_fill_t1": {
+                    "$ref": "#/definitions/models.ThesisComponent"
+                },
+                "partial_fill_t2": {
+                    "$ref": "#/definitions/models.ThesisComponent"
+                },
+                "right_shoulder_formed": {
+                    "description": "Right Shoulder Components",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ThesisComponent"
+                        }
+                    ]
+                },
+                "right_shoulder_symmetry": {
+                    "$ref": "#/definitions/models.ThesisComponent"
+                },
+                "right_shoulder_volume": {
+                    "$ref": "#/definitions/models.ThesisComponent"
+                },
+                "score_breakdown": {
+                    "description": "Points each component contributed to the completion percent",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ScoreBreakdown"
+                        }
+                    ]
+                },
+                "target_projected": {
+                    "description": "Target Components",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ThesisComponent"
+                        }
+                    ]
+                },
+                "total_components": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ImportResult": {
+            "type": "object",
+            "properties": {
+                "dataset": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.IndexTrend": {
+            "type": "object",
+            "properties": {
+                "above_sma_20": {
+                    "type": "boolean"
+                },
+                "above_sma_50": {
+                    "type": "boolean"
+                },
+                "as_of": {
+                    "description": "session of the latest close",
+                    "type": "string"
+                },
+                "change_percent": {
+                    "description": "latest close against the prior session's",
+                    "type": "number"
+                },
+                "close": {
+                    "type": "number"
+                },
+                "sma_20": {
+                    "type": "number"
+                },
+                "sma_50": {
+                    "description": "0 until 50 sessions are stored",
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "trend": {
+                    "description": "one of the market regimes",
+                    "type": "string"
+                }
+            }
+        },
+        "models.IndicatorAlert": {
+            "type": "object",
+            "properties": {
+                "alert_type": {
+                    "description": "'rsi_oversold', 'rsi_overbought', 'macd_bullish', 'macd_bearish'",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "indicator": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "number"
+                },
+                "triggered_at": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.IndicatorSummary": {
+            "type": "object",
+            "properties": {
+                "active_alerts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.IndicatorAlert"
+                    }
+                },
+                "bollinger_bands": {
+                    "$ref": "#/definitions/models.BollingerBandsData"
+                },
+                "current_price": {
+                    "type": "number"
+                },
+                "last_update": {
+                    "type": "string"
+                },
+                "macd": {
+                    "$ref": "#/definitions/models.MACDData"
+                },
+                "moving_averages": {
+                    "$ref": "#/definitions/models.MovingAverageData"
+                },
+                "overall_sentiment": {
+                    "description": "'strong_buy', 'buy', 'neutral', 'sell', 'strong_sell'",
+                    "type": "string"
+                },
+                "rsi": {
+                    "$ref": "#/definitions/models.RSIData"
+                },
+                "stochastic": {
+                    "$ref": "#/definitions/models.StochasticData"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "trend_direction": {
+                    "description": "'bullish', 'bearish', 'neutral'",
+                    "type": "string"
+                },
+                "trend_strength": {
+                    "$ref": "#/definitions/models.TrendStrengthData"
+                },
+                "volatility": {
+                    "$ref": "#/definitions/models.VolatilityData"
+                },
+                "volume": {
+                    "$ref": "#/definitions/models.VolumeAnalysisData"
+                }
+            }
+        },
+        "models.InsiderSummary": {
+            "type": "object",
+            "properties": {
+                "buy_value": {
+                    "type": "number"
+                },
+                "cluster_buyers": {
+                    "description": "Insiders with open market purchases in the cluster window",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "cluster_buying": {
+                    "type": "boolean"
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "net_value": {
+                    "description": "Buy value less sell value",
+                    "type": "number"
+                },
+                "purchases": {
+                    "description": "Open market purchases",
+                    "type": "integer"
+                },
+                "sales": {
+                    "description": "Open market sales",
+                    "type": "integer"
+                },
+                "sell_value": {
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "transactions": {
+                    "description": "Newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InsiderTransaction"
+                    }
+                }
+            }
+        },
+        "models.InsiderTransaction": {
+            "type": "object",
+            "properties": {
+                "accession_number": {
+                    "description": "EDGAR filing the transaction was reported in",
+                    "type": "string"
+                },
+                "acquired_disposed": {
+                    "description": "'A' acquired or 'D' disposed",
+                    "type": "string"
+                },
+                "filed_date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "insider_name": {
+                    "type": "string"
+                },
+                "insider_title": {
+                    "description": "Officer title, Director or 10% Owner",
+                    "type": "string"
+                },
+                "is_derivative": {
+                    "description": "Options, warrants and other derivatives",
+                    "type": "boolean"
+                },
+                "is_director": {
+                    "type": "boolean"
+                },
+                "is_officer": {
+                    "type": "boolean"
+                },
+                "is_ten_percent_owner": {
+                    "type": "boolean"
+                },
+                "line": {
+                    "description": "Position of the transaction in the filing",
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "security_title": {
+                    "type": "string"
+                },
+                "shares": {
+                    "type": "number"
+                },
+                "shares_owned_after": {
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "transaction_code": {
+                    "description": "'P', 'S', 'A', 'M', 'F', 'G' or another Form 4 code",
+                    "type": "string"
+                },
+                "transaction_date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.Job": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "description": "Items processed, including failures",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "params": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "progress": {
+                    "description": "Percent of items processed",
+                    "type": "number"
+                },
+                "results": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LivenessReport": {
+            "type": "object",
+            "properties": {
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "uptime_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MACDData": {
+            "type": "object",
+            "properties": {
+                "histogram": {
+                    "type": "number"
+                },
+                "macd": {
+                    "type": "number"
+                },
+                "signal": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.MACDPoint": {
+            "type": "object",
+            "properties": {
+                "histogram": {
+                    "type": "number"
+                },
+                "macd": {
+                    "type": "number"
+                },
+                "signal": {
+                    "type": "number"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MarketAnomaly": {
+            "type": "object",
+            "properties": {
+                "bar_time": {
+                    "description": "the unusual bar, or the first bar after a halt",
+                    "type": "string"
+                },
+                "detected_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "price": {
+                    "description": "the bar's close",
+                    "type": "number"
+                },
+                "severity": {
+                    "description": "'high' or 'medium'",
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "number"
+                },
+                "type": {
+                    "description": "one of AnomalyTypes",
+                    "type": "string"
+                },
+                "value": {
+                    "description": "volume z-score, gap percent or minutes without bars",
+                    "type": "number"
+                }
+            }
+        },
+        "models.MarketBreadth": {
+            "type": "object",
+            "properties": {
+                "above_sma_20": {
+                    "type": "integer"
+                },
+                "above_sma_20_percent": {
+                    "type": "number"
+                },
+                "above_sma_50": {
+                    "type": "integer"
+                },
+                "above_sma_50_percent": {
+                    "description": "of the symbols with 50 daily closes",
+                    "type": "number"
+                },
+                "advance_decline": {
+                    "description": "advancing minus declining",
+                    "type": "integer"
+                },
+                "advancing": {
+                    "type": "integer"
+                },
+                "declining": {
+                    "type": "integer"
+                },
+                "symbols": {
+                    "description": "watched stocks and ETFs with at least 20 daily closes",
+                    "type": "integer"
+                },
+                "unchanged": {
+                    "type": "integer"
+                },
+                "with_sma_50": {
+                    "description": "symbols with 50 daily closes",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MarketContext": {
+            "type": "object",
+            "properties": {
+                "benchmarks": {
+                    "description": "every collected benchmark, in configured order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.IndexTrend"
+                    }
+                },
+                "breadth": {
+                    "description": "watched symbols, benchmarks not included",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.MarketBreadth"
+                        }
+                    ]
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "index": {
+                    "description": "the regime index",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.IndexTrend"
+                        }
+                    ]
+                },
+                "regime": {
+                    "type": "string"
+                },
+                "skipped": {
+                    "description": "symbols without 20 daily closes",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.MarketRegimeLabel": {
+            "type": "object",
+            "properties": {
+                "adx": {
+                    "type": "number"
+                },
+                "atr_percent": {
+                    "description": "14-day ATR as a percent of the close",
+                    "type": "number"
+                },
+                "close": {
+                    "type": "number"
+                },
+                "minus_di": {
+                    "type": "number"
+                },
+                "plus_di": {
+                    "type": "number"
+                },
+                "regime": {
+                    "type": "string"
+                },
+                "sma_50": {
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "trade_date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "volatility_ratio": {
+                    "description": "14-day ATR over the 50-day ATR",
+                    "type": "number"
+                }
+            }
+        },
+        "models.MarketSession": {
+            "type": "string",
+            "enum": [
+                "closed",
+                "pre_market",
+                "regular",
+                "post_market"
+            ],
+            "x-enum-varnames": [
+                "SessionClosed",
+                "SessionPreMarket",
+                "SessionRegular",
+                "SessionPostMarket"
+            ]
+        },
+        "models.MarketStatus": {
+            "type": "object",
+            "properties": {
+                "closes_at": {
+                    "type": "string"
+                },
+                "collecting": {
+                    "description": "whether the collector runs at this time",
+                    "type": "boolean"
+                },
+                "exchange": {
+                    "type": "string"
+                },
+                "holiday": {
+                    "type": "string"
+                },
+                "is_half_day": {
+                    "type": "boolean"
+                },
+                "is_open": {
+                    "description": "regular session",
+                    "type": "boolean"
+                },
+                "is_trading_day": {
+                    "type": "boolean"
+                },
+                "next_open": {
+                    "type": "string"
+                },
+                "opens_at": {
+                    "description": "regular session open, trading days only",
+                    "type": "string"
+                },
+                "session": {
+                    "$ref": "#/definitions/models.MarketSession"
+                },
+                "time": {
+                    "description": "exchange local time",
+                    "type": "string"
+                }
+            }
+        },
+        "models.MovingAverageData": {
+            "type": "object",
+            "properties": {
+                "ema_20": {
+                    "type": "number"
+                },
+                "ema_50": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.NewsArticle": {
+            "type": "object",
+            "properties": {
+                "article_id": {
+                    "description": "Provider article ID",
+                    "type": "string"
+                },
+                "author": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "keywords": {
+                    "description": "Comma separated",
+                    "type": "string"
+                },
+                "published_at": {
+                    "type": "string"
+                },
+                "publisher": {
+                    "type": "string"
+                },
+                "sentiment": {
+                    "description": "'positive', 'negative', 'neutral'",
+                    "type": "string"
+                },
+                "sentiment_score": {
+                    "description": "-1 (negative) to 1 (positive)",
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Notification": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "description": "one of NotificationCategories",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_read": {
+                    "type": "boolean"
+                },
+                "link": {
+                    "description": "dashboard page or API path with details",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "read_at": {
+                    "type": "string"
+                },
+                "severity": {
+                    "description": "'high', 'medium', 'low'",
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OptionsSnapshot": {
+            "type": "object",
+            "properties": {
+                "atm_implied_volatility": {
+                    "description": "Nearest expiration, nearest strike",
+                    "type": "number"
+                },
+                "avg_implied_volatility": {
+                    "description": "Open interest weighted",
+                    "type": "number"
+                },
+                "call_open_interest": {
+                    "type": "integer"
+                },
+                "call_volume": {
+                    "type": "integer"
+                },
+                "contract_count": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "put_call_oi_ratio": {
+                    "type": "number"
+                },
+                "put_call_volume_ratio": {
+                    "type": "number"
+                },
+                "put_open_interest": {
+                    "type": "integer"
+                },
+                "put_volume": {
+                    "type": "integer"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "underlying_price": {
+                    "type": "number"
+                },
+                "unusual_contracts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UnusualOptionActivity"
+                    }
+                },
+                "unusual_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OptionsSummary": {
+            "type": "object",
+            "properties": {
+                "avg_put_call_volume_ratio": {
+                    "type": "number"
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OptionsSnapshot"
+                    }
+                },
+                "iv_high": {
+                    "type": "number"
+                },
+                "iv_low": {
+                    "type": "number"
+                },
+                "iv_rank": {
+                    "description": "0-100 position of latest IV within the period's range",
+                    "type": "number"
+                },
+                "latest": {
+                    "$ref": "#/definitions/models.OptionsSnapshot"
+                },
+                "recent_unusual": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UnusualOptionActivity"
+                    }
+                },
+                "snapshot_count": {
+                    "type": "integer"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PagedResponse": {
+            "type": "object",
+            "properties": {
+                "items": {},
+                "pagination": {
+                    "$ref": "#/definitions/models.Pagination"
+                }
+            }
+        },
+        "models.Pagination": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "order": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "sort": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PaperEquityPoint": {
+            "type": "object",
+            "properties": {
+                "cash": {
+                    "type": "number"
+                },
+                "equity": {
+                    "type": "number"
+                },
+                "open_trades": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "unrealized_pnl": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.PaperTrade": {
+            "type": "object",
+            "properties": {
+                "closed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "current_price": {
+                    "description": "Populated for open trades",
+                    "type": "number"
+                },
+                "entry_price": {
+                    "type": "number"
+                },
+                "exit_price": {
+                    "type": "number"
+                },
+                "exit_reason": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "filled_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_checked_at": {
+                    "description": "timestamp of the last bar simulated",
+                    "type": "string"
+                },
+                "quality_score": {
+                    "type": "number"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "realized_pnl": {
+                    "type": "number"
+                },
+                "setup_type": {
+                    "type": "string"
+                },
+                "side": {
+                    "description": "'long' or 'short'",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "stop_loss": {
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "target_price": {
+                    "type": "number"
+                },
+                "unrealized_pnl": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PaperTradingCycleResult": {
+            "type": "object",
+            "properties": {
+                "closed": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "expired": {
+                    "type": "integer"
+                },
+                "filled": {
+                    "type": "integer"
+                },
+                "run_at": {
+                    "type": "string"
+                },
+                "taken": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PaperTrade"
+                    }
+                }
+            }
+        },
+        "models.PaperTradingStatus": {
+            "type": "object",
+            "properties": {
+                "cash": {
+                    "type": "number"
+                },
+                "closed_trades": {
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "equity": {
+                    "type": "number"
+                },
+                "last_run": {
+                    "type": "string"
+                },
+                "losses": {
+                    "type": "integer"
+                },
+                "min_quality_score": {
+                    "type": "number"
+                },
+                "open_trades": {
+                    "type": "integer"
+                },
+                "pending_trades": {
+                    "type": "integer"
+                },
+                "realized_pnl": {
+                    "type": "number"
+                },
+                "return_percent": {
+                    "type": "number"
+                },
+                "starting_capital": {
+                    "type": "number"
+                },
+                "unrealized_pnl": {
+                    "type": "number"
+                },
+                "win_rate": {
+                    "type": "number"
+                },
+                "wins": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PatternAlert": {
+            "type": "object",
+            "properties": {
+                "alert_type": {
+                    "description": "'component_completed', 'breakout_confirmed', 'target_reached'",
+                    "type": "string"
+                },
+                "component_name": {
+                    "type": "string"
+                },
+                "email_sent": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "notification_sent": {
+                    "type": "boolean"
+                },
+                "pattern_id": {
+                    "type": "integer"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "triggered_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PatternEdit": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "description": "by key point",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.PatternEditDiff"
+                    }
+                },
+                "edited_at": {
+                    "type": "string"
+                },
+                "editor": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "pattern_id": {
+                    "type": "integer"
+                },
+                "pattern_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PatternEditDiff": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PatternPoint": {
+            "type": "object",
+            "properties": {
+                "price": {
+                    "type": "number"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "volume": {
+                    "type": "integer"
+                },
+                "volume_ratio": {
+                    "description": "vs average",
+                    "type": "number"
+                }
+            }
+        },
+        "models.PivotPoint": {
+            "type": "object",
+            "properties": {
+                "confirmed": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "pivot_type": {
+                    "description": "'high' or 'low'",
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "strength": {
+                    "type": "integer"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "volume": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PortfolioHeat": {
+            "type": "object",
+            "properties": {
+                "account_size": {
+                    "type": "number"
+                },
+                "active_setups": {
+                    "type": "integer"
+                },
+                "block_over_heat": {
+                    "type": "boolean"
+                },
+                "heat_percent": {
+                    "description": "Open risk as a percent of the account",
+                    "type": "number"
+                },
+                "max_heat_percent": {
+                    "type": "number"
+                },
+                "open_risk": {
+                    "type": "number"
+                },
+                "over_limit": {
+                    "type": "boolean"
+                },
+                "risk_percent": {
+                    "description": "Percent of the account risked per setup",
+                    "type": "number"
+                },
+                "triggered_setups": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Position": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "current_price": {
+                    "description": "Live valuation, populated for open positions",
+                    "type": "number"
+                },
+                "entry_price": {
+                    "type": "number"
+                },
+                "entry_time": {
+                    "type": "string"
+                },
+                "exit_price": {
+                    "type": "number"
+                },
+                "exit_time": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "market_value": {
+                    "type": "number"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "realized_pnl": {
+                    "description": "Populated for closed positions",
+                    "type": "number"
+                },
+                "realized_pnl_percent": {
+                    "type": "number"
+                },
+                "setup_id": {
+                    "description": "Attribution to the setup that generated the trade",
+                    "type": "integer"
+                },
+                "setup_type": {
+                    "description": "e.g. 'support_bounce', 'inverse_head_shoulders', 'manual'",
+                    "type": "string"
+                },
+                "side": {
+                    "description": "'long' or 'short'",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "'open' or 'closed'",
+                    "type": "string"
+                },
+                "stop_loss": {
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "target_price": {
+                    "type": "number"
+                },
+                "unrealized_pnl": {
+                    "type": "number"
+                },
+                "unrealized_pnl_percent": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PositionSizing": {
+            "type": "object",
+            "properties": {
+                "blocked": {
+                    "description": "New entries are sized at zero while portfolio heat is over the limit",
+                    "type": "boolean"
+                },
+                "dollar_risk": {
+                    "description": "Loss if the stop is hit, shares times risk per share",
+                    "type": "number"
+                },
+                "position_value": {
+                    "type": "number"
+                },
+                "risk_per_share": {
+                    "description": "Distance from entry to stop",
+                    "type": "number"
+                },
+                "shares": {
+                    "type": "integer"
+                },
+                "warning": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RSIData": {
+            "type": "object",
+            "properties": {
+                "rsi_14": {
+                    "type": "number"
+                },
+                "rsi_30": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.ReadinessReport": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DependencyCheck"
+                    }
+                },
+                "ready": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RelativePerformance": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "missing": {
+                    "description": "symbols without bars in the range",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "range": {
+                    "type": "string"
+                },
+                "series": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RelativePerformanceSeries"
+                    }
+                },
+                "symbols": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timeframe": {
+                    "$ref": "#/definitions/models.Timeframe"
+                },
+                "times": {
+                    "description": "Unix seconds of each point, shared by every series",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RelativePerformanceSeries": {
+            "type": "object",
+            "properties": {
+                "base_close": {
+                    "description": "close at the first shared timestamp",
+                    "type": "number"
+                },
+                "change": {
+                    "description": "percent change at the last timestamp",
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "values": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "models.SRAnalysisResult": {
+            "type": "object",
+            "properties": {
+                "analysis_time": {
+                    "type": "string"
+                },
+                "confluence_refs": {
+                    "$ref": "#/definitions/models.SRConfluenceRefs"
+                },
+                "current_price": {
+                    "type": "number"
+                },
+                "fibonacci_levels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SupportResistanceLevel"
+                    }
+                },
+                "fibonacci_swing": {
+                    "$ref": "#/definitions/models.FibonacciSwing"
+                },
+                "key_levels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SupportResistanceLevel"
+                    }
+                },
+                "level_summary": {
+                    "$ref": "#/definitions/models.SRLevelSummary"
+                },
+                "nearest_resistance": {
+                    "$ref": "#/definitions/models.SupportResistanceLevel"
+                },
+                "nearest_support": {
+                    "$ref": "#/definitions/models.SupportResistanceLevel"
+                },
+                "recent_touches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SRLevelTouch"
+                    }
+                },
+                "resistance_levels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SupportResistanceLevel"
+                    }
+                },
+                "resistance_zones": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SRZone"
+                    }
+                },
+                "support_levels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SupportResistanceLevel"
+                    }
+                },
+                "support_zones": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SRZone"
+                    }
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SRConfluenceRefs": {
+            "type": "object",
+            "properties": {
+                "high_volume_nodes": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "point_of_control": {
+                    "description": "From the symbol's volume profile",
+                    "type": "number"
+                },
+                "prior_day_high": {
+                    "type": "number"
+                },
+                "prior_day_low": {
+                    "type": "number"
+                },
+                "vwap": {
+                    "description": "Current session VWAP",
+                    "type": "number"
+                }
+            }
+        },
+        "models.SRDetectionRun": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.SRAnalysisResult"
+                    }
+                },
+                "run": {
+                    "$ref": "#/definitions/models.ScanRun"
+                }
+            }
+        },
+        "models.SRLevelSummary": {
+            "type": "object",
+            "properties": {
+                "avg_strength": {
+                    "type": "number"
+                },
+                "last_analysis": {
+                    "type": "string"
+                },
+                "last_touch_time": {
+                    "type": "string"
+                },
+                "recent_touch_count": {
+                    "type": "integer"
+                },
+                "resistance_count": {
+                    "type": "integer"
+                },
+                "strongest_level": {
+                    "type": "number"
+                },
+                "support_count": {
+                    "type": "integer"
+                },
+                "total_levels": {
+                    "type": "integer"
+                },
+                "weakest_level": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.SRLevelTouch": {
+            "type": "object",
+            "properties": {
+                "bounce_confirmed": {
+                    "type": "boolean"
+                },
+                "bounce_percent": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "distance_percent": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "level": {
+                    "type": "number"
+                },
+                "level_id": {
+                    "type": "integer"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "time_at_level": {
+                    "type": "integer"
+                },
+                "touch_price": {
+                    "type": "number"
+                },
+                "touch_time": {
+                    "type": "string"
+                },
+                "touch_type": {
+                    "description": "'test', 'break', 'bounce'",
+                    "type": "string"
+                },
+                "volume_at_touch": {
+                    "type": "integer"
+                },
+                "volume_spike": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.SRRecalculation": {
+            "type": "object",
+            "properties": {
+                "active_levels": {
+                    "type": "integer"
+                },
+                "invalidated_levels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SupportResistanceLevel"
+                    }
+                },
+                "new_levels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SupportResistanceLevel"
+                    }
+                },
+                "notified": {
+                    "description": "new and invalidated levels strong enough to notify",
+                    "type": "integer"
+                },
+                "recalculated_at": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "timeframe": {
+                    "$ref": "#/definitions/models.Timeframe"
+                }
+            }
+        },
+        "models.SRResponse": {
+            "type": "object",
+            "properties": {
+                "floor_pivots": {
+                    "description": "Daily and weekly floor pivots of the current session",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FloorPivotLevel"
+                    }
+                },
+                "levels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SupportResistanceLevel"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "pagination": {
+                    "$ref": "#/definitions/models.Pagination"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "summary": {
+                    "$ref": "#/definitions/models.SRLevelSummary"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SRZone": {
+            "type": "object",
+            "properties": {
+                "confluence": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "confluence_score": {
+                    "type": "number"
+                },
+                "high": {
+                    "type": "number"
+                },
+                "level": {
+                    "description": "Strength-weighted center of the merged levels",
+                    "type": "number"
+                },
+                "level_type": {
+                    "description": "'support' or 'resistance'",
+                    "type": "string"
+                },
+                "levels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SupportResistanceLevel"
+                    }
+                },
+                "low": {
+                    "type": "number"
+                },
+                "strength": {
+                    "description": "Strongest merged level",
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "touches": {
+                    "type": "integer"
+                },
+                "zone_strength": {
+                    "description": "0-100: level strength plus merged touches and confluence",
+                    "type": "number"
+                }
+            }
+        },
+        "models.SRZoneResult": {
+            "type": "object",
+            "properties": {
+                "confluence_refs": {
+                    "$ref": "#/definitions/models.SRConfluenceRefs"
+                },
+                "resistance_zones": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SRZone"
+                    }
+                },
+                "support_zones": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SRZone"
+                    }
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SavedScreen": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "criteria": {
+                    "$ref": "#/definitions/models.ScreenCriteria"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ScanRun": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "description": "failure per symbol",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "succeeded": {
+                    "type": "integer"
+                },
+                "symbols": {
+                    "type": "integer"
+                },
+                "task": {
+                    "type": "string"
+                },
+                "total_ms": {
+                    "type": "number"
+                },
+                "workers": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ScheduleStatus": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "disabled_at": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "skipped_runs": {
+                    "description": "since the schedule was last disabled",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ScoreBreakdown": {
+            "type": "object",
+            "properties": {
+                "adjustments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ScoreBreakdownItem"
+                    }
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ScoreBreakdownItem"
+                    }
+                },
+                "max_score": {
+                    "description": "Most points the items can add up to",
+                    "type": "number"
+                },
+                "score": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.ScoreBreakdownItem": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "max_points": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "passed": {
+                    "type": "boolean"
+                },
+                "points": {
+                    "type": "number"
+                },
+                "reason": {
+                    "description": "Why a failed item scored nothing",
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ScoringWeightVersion": {
+            "type": "object",
+            "properties": {
+                "activated_at": {
+                    "type": "string"
+                },
+                "base_win_rate": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ChecklistItemWeight"
+                    }
+                },
+                "losses": {
+                    "description": "setups that stopped out",
+                    "type": "integer"
+                },
+                "samples": {
+                    "description": "resolved setups the weights were learned from",
+                    "type": "integer"
+                },
+                "wins": {
+                    "description": "setups that completed at their target",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ScreenCriteria": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "description": "Maximum results (default 100)",
+                    "type": "integer"
+                },
+                "match_any": {
+                    "description": "Match when any criterion passes instead of all",
+                    "type": "boolean"
+                },
+                "max_price": {
+                    "description": "Universe filter",
+                    "type": "number"
+                },
+                "min_avg_volume": {
+                    "description": "Universe filter on 20-day average daily volume",
+                    "type": "integer"
+                },
+                "min_level_touches": {
+                    "description": "Touches required for a level to count as strong (default 2)",
+                    "type": "integer"
+                },
+                "min_price": {
+                    "description": "Universe filter",
+                    "type": "number"
+                },
+                "min_volume_ratio": {
+                    "description": "Match when today's volume is at least this multiple of average",
+                    "type": "number"
+                },
+                "near_level_percent": {
+                    "description": "Match when price is within this percent of a strong S/R level",
+                    "type": "number"
+                },
+                "rsi_above": {
+                    "description": "Match when daily RSI is above this (overbought)",
+                    "type": "number"
+                },
+                "rsi_below": {
+                    "description": "Match when daily RSI is below this (oversold)",
+                    "type": "number"
+                },
+                "symbols": {
+                    "description": "Restrict the universe to these symbols",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "universe": {
+                    "description": "'market' (default) or 'watchlist'",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ScreenerResult": {
+            "type": "object",
+            "properties": {
+                "avg_volume": {
+                    "type": "integer"
+                },
+                "change_percent": {
+                    "type": "number"
+                },
+                "in_watchlist": {
+                    "type": "boolean"
+                },
+                "level_distance_percent": {
+                    "type": "number"
+                },
+                "level_touches": {
+                    "type": "integer"
+                },
+                "level_type": {
+                    "description": "'support' or 'resistance'",
+                    "type": "string"
+                },
+                "matches": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "nearest_level": {
+                    "type": "number"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "rsi": {
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "volume": {
+                    "type": "integer"
+                },
+                "volume_ratio": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.ScreenerRun": {
+            "type": "object",
+            "properties": {
+                "as_of": {
+                    "description": "Date of the latest daily bar used",
+                    "type": "string"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "criteria": {
+                    "$ref": "#/definitions/models.ScreenCriteria"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ScreenerResult"
+                    }
+                },
+                "run_at": {
+                    "type": "string"
+                },
+                "scanned": {
+                    "type": "integer"
+                },
+                "screen_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SectorStrength": {
+            "type": "object",
+            "properties": {
+                "rank": {
+                    "type": "integer"
+                },
+                "relative_strength": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "score": {
+                    "type": "number"
+                },
+                "sector": {
+                    "type": "string"
+                },
+                "stocks": {
+                    "description": "strongest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SymbolStrength"
+                    }
+                }
+            }
+        },
+        "models.SectorStrengthReport": {
+            "type": "object",
+            "properties": {
+                "benchmark": {
+                    "type": "string"
+                },
+                "benchmark_returns": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "sectors": {
+                    "description": "strongest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SectorStrength"
+                    }
+                },
+                "skipped": {
+                    "description": "symbols without enough daily history",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "windows": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.SetupChecklist": {
+            "type": "object",
+            "properties": {
+                "approach_volume": {
+                    "description": "5 points",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    ]
+                },
+                "bollinger_bands": {
+                    "description": "5 points",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    ]
+                },
+                "bounce_strength": {
+                    "description": "5 points",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    ]
+                },
+                "completed_items": {
+                    "type": "integer"
+                },
+                "completion_percent": {
+                    "type": "number"
+                },
+                "entry_precision": {
+                    "description": "5 points",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    ]
+                },
+                "exit_strategy": {
+                    "description": "5 points",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    ]
+                },
+                "last_updated": {
+                    "type": "string"
+                },
+                "level_duration": {
+                    "description": "5 points",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    ]
+                },
+                "macd_signal": {
+                    "description": "5 points",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    ]
+                },
+                "min_level_touches": {
+                    "description": "Price Action Criteria (25 points max)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    ]
+                },
+                "momentum_divergence": {
+                    "description": "5 points",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    ]
+                },
+                "moving_average": {
+                    "description": "5 points",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    ]
+                },
+                "position_size": {
+                    "description": "5 points",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    ]
+                },
+                "rejection_candle": {
+                    "description": "5 points",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    ]
+                },
+                "relative_volume": {
+                    "description": "5 points",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    ]
+                },
+                "risk_reward_ratio": {
+                    "description": "5 points",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    ]
+                },
+                "rsi_condition": {
+                    "description": "Technical Indicators (25 points max)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    ]
+                },
+                "setup_id": {
+                    "type": "integer"
+                },
+                "short_interest": {
+                    "description": "Market context, reported without points",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    ]
+                },
+                "stop_loss_defined": {
+                    "description": "Risk Management (25 points max)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    ]
+                },
+                "time_at_level": {
+                    "description": "5 points",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    ]
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_score": {
+                    "description": "Calculated scores",
+                    "type": "number"
+                },
+                "volume_confirmation": {
+                    "description": "5 points",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    ]
+                },
+                "volume_spike": {
+                    "description": "Volume Criteria (25 points max)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    ]
+                },
+                "vwap_relationship": {
+                    "description": "5 points",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.SetupDetectionResult": {
+            "type": "object",
+            "properties": {
+                "active_setups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TradingSetup"
+                    }
+                },
+                "detection_time": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "expired_setups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TradingSetup"
+                    }
+                },
+                "refreshed": {
+                    "description": "Found setups that refreshed the active setup of their type at their key level",
+                    "type": "integer"
+                },
+                "regime": {
+                    "description": "classified market regime setups were gated by",
+                    "type": "string"
+                },
+                "setups_found": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TradingSetup"
+                    }
+                },
+                "summary": {
+                    "$ref": "#/definitions/models.SetupSummary"
+                },
+                "suppressed": {
+                    "description": "setup types left out in the market regime",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SetupPerformance": {
+            "type": "object",
+            "properties": {
+                "avg_return_percent": {
+                    "type": "number"
+                },
+                "best_return_percent": {
+                    "type": "number"
+                },
+                "losses": {
+                    "type": "integer"
+                },
+                "open_positions": {
+                    "type": "integer"
+                },
+                "realized_pnl": {
+                    "type": "number"
+                },
+                "setup_type": {
+                    "type": "string"
+                },
+                "trades": {
+                    "type": "integer"
+                },
+                "unrealized_pnl": {
+                    "type": "number"
+                },
+                "win_rate": {
+                    "type": "number"
+                },
+                "wins": {
+                    "type": "integer"
+                },
+                "worst_return_percent": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.SetupResponse": {
+            "type": "object",
+            "properties": {
+                "heat": {
+                    "$ref": "#/definitions/models.PortfolioHeat"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "pagination": {
+                    "$ref": "#/definitions/models.Pagination"
+                },
+                "setups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TradingSetup"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "summary": {
+                    "$ref": "#/definitions/models.SetupSummary"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SetupStopAdjustment": {
+            "type": "object",
+            "properties": {
+                "atr": {
+                    "type": "number"
+                },
+                "best_price": {
+                    "description": "Highest high since entry, or lowest low for bearish setups",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "previous_stop": {
+                    "type": "number"
+                },
+                "price": {
+                    "description": "Close of the bar the adjustment was suggested on",
+                    "type": "number"
+                },
+                "reason": {
+                    "description": "one of the StopAdjustment values",
+                    "type": "string"
+                },
+                "setup_id": {
+                    "type": "integer"
+                },
+                "suggested_at": {
+                    "type": "string"
+                },
+                "suggested_stop": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.SetupSummary": {
+            "type": "object",
+            "properties": {
+                "active_count": {
+                    "type": "integer"
+                },
+                "avg_quality_score": {
+                    "type": "number"
+                },
+                "avg_risk_reward": {
+                    "type": "number"
+                },
+                "bearish_count": {
+                    "type": "integer"
+                },
+                "best_setup": {
+                    "$ref": "#/definitions/models.TradingSetup"
+                },
+                "bullish_count": {
+                    "type": "integer"
+                },
+                "by_type": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "high_quality_count": {
+                    "type": "integer"
+                },
+                "last_detection": {
+                    "type": "string"
+                },
+                "low_quality_count": {
+                    "type": "integer"
+                },
+                "medium_quality_count": {
+                    "type": "integer"
+                },
+                "total_setups": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ShortInterest": {
+            "type": "object",
+            "properties": {
+                "avg_daily_volume": {
+                    "type": "integer"
+                },
+                "days_to_cover": {
+                    "type": "number"
+                },
+                "settlement_date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "short_interest": {
+                    "description": "Shares sold short and not yet covered",
+                    "type": "integer"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ShortInterestSummary": {
+            "type": "object",
+            "properties": {
+                "avg_short_volume_ratio": {
+                    "description": "Short volume percent over the daily short volume below",
+                    "type": "number"
+                },
+                "elevated": {
+                    "type": "boolean"
+                },
+                "latest": {
+                    "description": "Latest short interest report",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ShortInterest"
+                        }
+                    ]
+                },
+                "reasons": {
+                    "description": "Measures past their elevated thresholds",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "short_interest": {
+                    "description": "Newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ShortInterest"
+                    }
+                },
+                "short_percent_float": {
+                    "description": "Latest short interest as a percent of the float, when the float is known",
+                    "type": "number"
+                },
+                "short_volume": {
+                    "description": "Newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ShortVolume"
+                    }
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ShortVolume": {
+            "type": "object",
+            "properties": {
+                "short_exempt_volume": {
+                    "type": "integer"
+                },
+                "short_volume": {
+                    "type": "integer"
+                },
+                "short_volume_ratio": {
+                    "description": "Short volume as a percent of the total",
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "total_volume": {
+                    "description": "Volume reported to FINRA facilities, not the consolidated volume",
+                    "type": "integer"
+                },
+                "trade_date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                }
+            }
+        },
+        "models.StochasticData": {
+            "type": "object",
+            "properties": {
+                "d": {
+                    "type": "number"
+                },
+                "k": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.Stock": {
+            "type": "object",
+            "properties": {
+                "added_at": {
+                    "type": "string"
+                },
+                "change": {
+                    "type": "number"
+                },
+                "change_percent": {
+                    "type": "number"
+                },
+                "ema_200": {
+                    "type": "number"
+                },
+                "ema_50": {
+                    "type": "number"
+                },
+                "ema_9": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "market_cap": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "reference": {
+                    "description": "Sector, market cap, float and average volume, once fetched",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SymbolReference"
+                        }
+                    ]
+                },
+                "strategies": {
+                    "description": "Associated strategies",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Strategy"
+                    }
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "Free-form user tags, lower case",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "volume": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Strategy": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "description": "Hex color for UI",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "stocks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Stock"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.StrategyAutomation": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "'pattern_scan' or 'alert'",
+                    "type": "string"
+                },
+                "conditions": {
+                    "description": "alert: conditions a member must meet",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AlertCondition"
+                    }
+                },
+                "cooldown_minutes": {
+                    "description": "CooldownMinutes keeps an alert from notifying about the same stock again within the window",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "logic": {
+                    "description": "alert: 'AND' or 'OR'",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "patterns": {
+                    "description": "pattern_scan: families to run, empty for all",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "schedule": {
+                    "description": "cron expression in exchange time, e.g. \"30 16 * * 1-5\"",
+                    "type": "string"
+                },
+                "strategy_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.StrategyAutomationRun": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "automation_id": {
+                    "type": "integer"
+                },
+                "automation_name": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "type": "number"
+                },
+                "error": {
+                    "description": "why the whole run failed",
+                    "type": "string"
+                },
+                "errors": {
+                    "description": "failure per symbol",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "matches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AutomationMatch"
+                    }
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "'succeeded' or 'failed'",
+                    "type": "string"
+                },
+                "strategy_id": {
+                    "type": "integer"
+                },
+                "symbols": {
+                    "type": "integer"
+                },
+                "trigger": {
+                    "description": "'scheduled' or 'manual'",
+                    "type": "string"
+                }
+            }
+        },
+        "models.StrategyRegimes": {
+            "type": "object",
+            "properties": {
+                "long": {
+                    "description": "regimes bullish setups are detected in, empty for any",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "short": {
+                    "description": "regimes bearish setups are detected in, empty for any",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "strategy_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.StrategyVWAPSetups": {
+            "type": "object",
+            "properties": {
+                "reclaim": {
+                    "description": "long setups on a reclaim from below",
+                    "type": "boolean"
+                },
+                "rejection": {
+                    "description": "short setups on a rejection from below",
+                    "type": "boolean"
+                },
+                "strategy_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "volume_multiplier": {
+                    "description": "signal bar volume over the session's average bar volume",
+                    "type": "number"
+                }
+            }
+        },
+        "models.SupportResistanceLevel": {
+            "type": "object",
+            "properties": {
+                "avg_bounce_percent": {
+                    "type": "number"
+                },
+                "avg_volume": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "fib_ratio": {
+                    "type": "number"
+                },
+                "first_touch": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "last_touch": {
+                    "type": "string"
+                },
+                "last_validated": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "number"
+                },
+                "level_class": {
+                    "description": "SRClassPivot or SRClassFibonacci",
+                    "type": "string"
+                },
+                "level_type": {
+                    "description": "'support' or 'resistance'",
+                    "type": "string"
+                },
+                "max_bounce_percent": {
+                    "type": "number"
+                },
+                "strength": {
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "timeframe_origin": {
+                    "type": "string"
+                },
+                "touches": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "volume_confirmed": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.SymbolMatch": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "asset_class": {
+                    "$ref": "#/definitions/models.AssetClass"
+                },
+                "exchange": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "CS for common stock, ETF, ...",
+                    "type": "string"
+                }
+            }
+        },
+        "models.SymbolMover": {
+            "type": "object",
+            "properties": {
+                "change": {
+                    "type": "number"
+                },
+                "change_percent": {
+                    "type": "number"
+                },
+                "previous_close": {
+                    "description": "last close of the previous trading date, 0 when unknown",
+                    "type": "number"
+                },
+                "price": {
+                    "description": "latest close",
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "volume": {
+                    "description": "volume traded on the latest trading date",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SymbolReference": {
+            "type": "object",
+            "properties": {
+                "avg_volume": {
+                    "description": "average daily volume over recent sessions",
+                    "type": "integer"
+                },
+                "exchange": {
+                    "type": "string"
+                },
+                "float_shares": {
+                    "description": "0 when the data plan has no float data",
+                    "type": "integer"
+                },
+                "industry": {
+                    "type": "string"
+                },
+                "market_cap": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "sector": {
+                    "type": "string"
+                },
+                "shares_outstanding": {
+                    "type": "integer"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SymbolSearchResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SymbolMatch"
+                    }
+                }
+            }
+        },
+        "models.SymbolStats": {
+            "type": "object",
+            "properties": {
+                "atr_14": {
+                    "description": "14-day average true range of regular session candles",
+                    "type": "number"
+                },
+                "atr_percent": {
+                    "description": "ATR14 as a percent of the close",
+                    "type": "number"
+                },
+                "avg_gap_percent": {
+                    "description": "average absolute open vs prior close move",
+                    "type": "number"
+                },
+                "avg_post_market_volume": {
+                    "type": "integer"
+                },
+                "avg_pre_market_volume": {
+                    "type": "integer"
+                },
+                "avg_range": {
+                    "description": "average regular session high minus low",
+                    "type": "number"
+                },
+                "avg_range_percent": {
+                    "type": "number"
+                },
+                "avg_regular_volume": {
+                    "type": "integer"
+                },
+                "close": {
+                    "description": "latest regular session close",
+                    "type": "number"
+                },
+                "computed_at": {
+                    "type": "string"
+                },
+                "days": {
+                    "description": "trading days averaged",
+                    "type": "integer"
+                },
+                "gap_down_days": {
+                    "description": "days opening at least gap_percent below it",
+                    "type": "integer"
+                },
+                "gap_frequency": {
+                    "description": "share of days that gapped, 0 to 1",
+                    "type": "number"
+                },
+                "gap_percent": {
+                    "description": "move from the prior close counted as a gap",
+                    "type": "number"
+                },
+                "gap_up_days": {
+                    "description": "days opening at least gap_percent above the prior close",
+                    "type": "integer"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "trading_date": {
+                    "description": "latest trading day included, YYYY-MM-DD",
+                    "type": "string"
+                }
+            }
+        },
+        "models.SymbolStrength": {
+            "type": "object",
+            "properties": {
+                "relative_strength": {
+                    "description": "return minus the benchmark return, in percentage points",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "returns": {
+                    "description": "percent change over each window",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "score": {
+                    "description": "average relative strength across windows",
+                    "type": "number"
+                },
+                "sector": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TechnicalIndicators": {
+            "type": "object",
+            "properties": {
+                "adx_14": {
+                    "type": "number"
+                },
+                "atr_14": {
+                    "type": "number"
+                },
+                "bb_lower": {
+                    "type": "number"
+                },
+                "bb_middle": {
+                    "type": "number"
+                },
+                "bb_upper": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "ema_20": {
+                    "type": "number"
+                },
+                "ema_50": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "macd": {
+                    "type": "number"
+                },
+                "macd_histogram": {
+                    "type": "number"
+                },
+                "macd_signal": {
+                    "type": "number"
+                },
+                "minus_di": {
+                    "type": "number"
+                },
+                "obv": {
+                    "type": "number"
+                },
+                "plus_di": {
+                    "type": "number"
+                },
+                "rsi_14": {
+                    "type": "number"
+                },
+                "rsi_30": {
+                    "type": "number"
+                },
+                "stoch_d": {
+                    "type": "number"
+                },
+                "stoch_k": {
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "volume_ratio": {
+                    "type": "number"
+                },
+                "vwap": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.TechnicalIndicatorsResponse": {
+            "type": "object",
+            "properties": {
+                "indicators": {
+                    "$ref": "#/definitions/models.TechnicalIndicators"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ThesisComponent": {
+            "type": "object",
+            "properties": {
+                "auto_detected": {
+                    "type": "boolean"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "confidence_level": {
+                    "description": "0-100",
+                    "type": "number"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "evidence": {
+                    "description": "Supporting data points",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "is_completed": {
+                    "type": "boolean"
+                },
+                "is_required": {
+                    "type": "boolean"
+                },
+                "last_checked": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notification_sent": {
+                    "type": "boolean"
+                },
+                "weight": {
+                    "description": "Importance weight",
+                    "type": "number"
+                }
+            }
+        },
+        "models.Timeframe": {
+            "type": "string",
+            "enum": [
+                "1m",
+                "5m",
+                "15m",
+                "1h",
+                "1d",
+                "1m"
+            ],
+            "x-enum-varnames": [
+                "Timeframe1m",
+                "Timeframe5m",
+                "Timeframe15m",
+                "Timeframe1h",
+                "Timeframe1d",
+                "DefaultTimeframe"
+            ]
+        },
+        "models.TradingSetup": {
+            "type": "object",
+            "properties": {
+                "alert_count": {
+                    "description": "Setup alerts triggered so far",
+                    "type": "integer"
+                },
+                "archived": {
+                    "description": "Archived records are kept for analytics but left out of lists unless asked for",
+                    "type": "boolean"
+                },
+                "archived_at": {
+                    "type": "string"
+                },
+                "checklist": {
+                    "description": "Checklist items",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SetupChecklist"
+                        }
+                    ]
+                },
+                "confidence": {
+                    "description": "'high', 'medium', 'low'",
+                    "type": "string"
+                },
+                "confidence_factors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ConfidenceFactor"
+                    }
+                },
+                "confidence_score": {
+                    "description": "Confidence score (0-100) the confidence level was derived from, and what each factor contributed to it",
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "current_price": {
+                    "description": "Price levels",
+                    "type": "number"
+                },
+                "detected_at": {
+                    "type": "string"
+                },
+                "direction": {
+                    "description": "'bullish', 'bearish'",
+                    "type": "string"
+                },
+                "entry_price": {
+                    "type": "number"
+                },
+                "event_penalty": {
+                    "type": "number"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_manual": {
+                    "type": "boolean"
+                },
+                "key_level": {
+                    "$ref": "#/definitions/models.SupportResistanceLevel"
+                },
+                "key_level_id": {
+                    "description": "Stored key level; detection refreshes the active setup of a type at it",
+                    "type": "integer"
+                },
+                "key_zone": {
+                    "$ref": "#/definitions/models.SRZone"
+                },
+                "last_updated": {
+                    "type": "string"
+                },
+                "market_regime": {
+                    "description": "Market regime at detection and the points deducted for trading against it",
+                    "type": "string"
+                },
+                "notes": {
+                    "description": "Metadata",
+                    "type": "string"
+                },
+                "price_action_score": {
+                    "description": "Scoring breakdown",
+                    "type": "number"
+                },
+                "quality_score": {
+                    "description": "0-100 overall score",
+                    "type": "number"
+                },
+                "regime_penalty": {
+                    "type": "number"
+                },
+                "resistance_level": {
+                    "$ref": "#/definitions/models.SupportResistanceLevel"
+                },
+                "reward_potential": {
+                    "type": "number"
+                },
+                "risk_amount": {
+                    "description": "Risk/Reward metrics",
+                    "type": "number"
+                },
+                "risk_reward_ratio": {
+                    "type": "number"
+                },
+                "risk_reward_score": {
+                    "type": "number"
+                },
+                "score_breakdown": {
+                    "description": "Points each checklist item and adjustment contributed to the quality score",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ScoreBreakdown"
+                        }
+                    ]
+                },
+                "setup_type": {
+                    "description": "'support_bounce', 'resistance_bounce', 'breakout', etc.",
+                    "type": "string"
+                },
+                "sizing": {
+                    "description": "Recommended position under the risk settings, filled in for API responses",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PositionSizing"
+                        }
+                    ]
+                },
+                "status": {
+                    "description": "one of the SetupStatus values",
+                    "type": "string"
+                },
+                "stop_history": {
+                    "description": "Stop adjustments suggested since entry, oldest first, and the latest suggested stop",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SetupStopAdjustment"
+                    }
+                },
+                "stop_loss": {
+                    "type": "number"
+                },
+                "suggested_stop": {
+                    "type": "number"
+                },
+                "support_level": {
+                    "description": "Associated levels",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SupportResistanceLevel"
+                        }
+                    ]
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "target1": {
+                    "type": "number"
+                },
+                "target2": {
+                    "type": "number"
+                },
+                "target3": {
+                    "type": "number"
+                },
+                "technical_score": {
+                    "type": "number"
+                },
+                "upcoming_events": {
+                    "description": "Calendar events inside the setup window (earnings, economic releases)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CalendarEvent"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "volume_score": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.TrendStrengthData": {
+            "type": "object",
+            "properties": {
+                "adx": {
+                    "type": "number"
+                },
+                "minus_di": {
+                    "type": "number"
+                },
+                "plus_di": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.UnusualOptionActivity": {
+            "type": "object",
+            "properties": {
+                "contract_ticker": {
+                    "type": "string"
+                },
+                "contract_type": {
+                    "type": "string"
+                },
+                "expiration_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "implied_volatility": {
+                    "type": "number"
+                },
+                "open_interest": {
+                    "type": "integer"
+                },
+                "snapshot_id": {
+                    "type": "integer"
+                },
+                "strike_price": {
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "volume": {
+                    "type": "integer"
+                },
+                "volume_oi_ratio": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.VolatilityData": {
+            "type": "object",
+            "properties": {
+                "atr": {
+                    "type": "number"
+                },
+                "atr_percent": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.VolumeAnalysisData": {
+            "type": "object",
+            "properties": {
+                "avg_volume": {
+                    "type": "number"
+                },
+                "obv": {
+                    "type": "number"
+                },
+                "volume_ratio": {
+                    "type": "number"
+                },
+                "vwap": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.VolumeProfile": {
+            "type": "object",
+            "properties": {
+                "bucket_size": {
+                    "type": "number"
+                },
+                "buckets": {
+                    "description": "Lowest price first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.VolumeProfileBucket"
+                    }
+                },
+                "computed_at": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "high_volume_nodes": {
+                    "description": "Midpoints of local volume peaks, highest volume first",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "point_of_control": {
+                    "description": "Midpoint of the highest volume bucket",
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total_volume": {
+                    "type": "number"
+                },
+                "value_area_high": {
+                    "type": "number"
+                },
+                "value_area_low": {
+                    "type": "number"
+                },
+                "window_days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.VolumeProfileBucket": {
+            "type": "object",
+            "properties": {
+                "in_value_area": {
+                    "type": "boolean"
+                },
+                "is_hvn": {
+                    "type": "boolean"
+                },
+                "percent": {
+                    "description": "Share of the profile's total volume",
+                    "type": "number"
+                },
+                "price": {
+                    "description": "Band midpoint",
+                    "type": "number"
+                },
+                "price_high": {
+                    "type": "number"
+                },
+                "price_low": {
+                    "type": "number"
+                },
+                "volume": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.WatchlistBulkAddRequest": {
+            "type": "object",
+            "properties": {
+                "backfill_days": {
+                    "description": "history backfilled for the newly added symbols (default 30, -1 for none)",
+                    "type": "integer"
+                },
+                "strategy_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "symbols": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.WatchlistBulkItem": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.WatchlistBulkMoveRequest": {
+            "type": "object",
+            "properties": {
+                "from_strategy_id": {
+                    "description": "strategy to move out of; all of the stock's strategies when 0",
+                    "type": "integer"
+                },
+                "symbols": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "to_strategy_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.WatchlistBulkRemoveRequest": {
+            "type": "object",
+            "properties": {
+                "strategy_id": {
+                    "description": "only remove from this strategy; stocks left without one are deleted",
+                    "type": "integer"
+                },
+                "symbols": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "unwatch": {
+                    "description": "also stop collecting data for the deleted stocks",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.WatchlistBulkResult": {
+            "type": "object",
+            "properties": {
+                "backfill_job": {
+                    "description": "one job backfilling every newly added symbol",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Job"
+                        }
+                    ]
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WatchlistBulkItem"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer"
+                }
+            }
+        }
+    }
+}`
+
+// SwaggerInfo holds exported Swagger Info so clients can modify it
+var SwaggerInfo = &swag.Spec{
+	Version:          "1.0",
+	Host:             "",
+	BasePath:         "/",
+	Schemes:          []string{},
+	Title:            "Market Watch API",
+	Description:      "Market data collection, technical analysis, pattern detection and trading setups.\nRoutes are served under /api/v1; the unversioned /api paths remain as aliases.",
+	InfoInstanceName: "swagger",
+	SwaggerTemplate:  docTemplate,
+}
+
+func init() {
+	swag.Register(SwaggerInfo.InstanceName(), SwaggerInfo)
+}
@@ -0,0 +1,757 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Options are the serve flags that change how the server is built and started
+type Options struct {
+	ConfigPath     string // file watched for safe config changes when WatchConfig is set
+	HistoricalDays int    // days of history collected on start; 0 collects the default 30
+	ResetWatchlist bool   // reset watchlist strategies to the config defaults
+	WatchConfig    bool   // apply config file changes without a restart
+	WebDir         string // templates and static files; defaults to "web"
+
+	// Provider replaces the configured market data provider, e.g. with a stub in tests
+	Provider services.MarketDataProvider
+}
+
+// Services are the long-running services behind the router
+type Services struct {
+	Provider          services.MarketDataProvider
+	Polygon           *services.PolygonService
+	TechnicalAnalysis *services.TechnicalAnalysisService
+	Streaming         *services.StreamingService
+	Notifications     *services.NotificationService
+	Email             *services.EmailService
+	AlertRules        *services.AlertRuleService
+	MarketCalendar    *services.MarketCalendar
+	RVOL              *services.RVOLService
+	Anomalies         *services.AnomalyService
+	SRBreaks          *services.SRBreakService
+	SRTouches         *services.SRTouchService
+	Collector         *services.CollectorService
+	Health            *services.HealthService
+	Schedules         *services.ScheduleControl
+	OptionsChain      *services.OptionsService
+	Realtime          *services.PolygonStream
+	SupportResistance *services.SupportResistanceService
+	SRRecalculation   *services.SRRecalculationService
+	FloorPivots       *services.FloorPivotService
+	VolumeProfile     *services.VolumeProfileService
+	Setups            *services.SetupDetectionService
+	Calendar          *services.CalendarService
+	News              *services.NewsService
+	ShortInterest     *services.ShortInterestService
+	Insiders          *services.InsiderService
+	ReferenceData     *services.ReferenceDataService
+	SymbolSearch      *services.SymbolSearchService
+	SectorStrength    *services.SectorStrengthService
+	MarketContext     *services.MarketContextService
+	MarketRegimes     *services.MarketRegimeService
+	Compare           *services.RelativePerformanceService
+	Display           *services.DisplayService
+	SymbolStats       *services.SymbolStatsService
+	GapScan           *services.GapScanService
+	Telegram          *services.TelegramService
+	Webhooks          *services.WebhookService
+	Charts            *services.ChartService
+	PatternTimelines  *services.PatternTimelineService
+	ConfigReloader    *services.ConfigReloader
+	Portfolio         *services.PortfolioService
+	PaperTrading      *services.PaperTradingService
+	Risk              *services.RiskService
+	Broker            *services.BrokerService
+	HeadShoulders     *services.HeadShouldersDetectionService
+	FallingWedge      *services.FallingWedgeDetectionService
+	Triangle          *services.TriangleDetectionService
+	Flag              *services.FlagDetectionService
+	Patterns          *services.PatternDetectionService
+	Automations       *services.StrategyAutomationService
+	Settings          *services.SettingsService
+	Calibration       *services.CalibrationService
+	Replay            *services.ReplayService // only set when replaying
+	Digest            *services.DigestService
+	Jobs              *services.JobService
+	Scanner           *services.WorkerPool
+	PriceCache        *services.PriceCache // nil when price_cache.days is -1
+	Screener          *services.ScreenerService
+	EMA               *services.PolygonEMAService
+	Stocks            *services.StockService
+}
+
+// App is the fully wired server: BuildServer constructs it without starting background work, Start runs the
+// collector and schedulers, ListenAndServe serves the router and Shutdown stops everything in order
+type App struct {
+	Config   *config.Config
+	DB       *database.Database
+	Router   *gin.Engine
+	Services *Services
+
+	opts         Options
+	webDir       string
+	server       *http.Server
+	replaySource *database.Database
+	replayClock  *services.SimulatedClock
+}
+
+// BuildServer opens the configured database and creates every service, handler and route
+func BuildServer(cfg *config.Config, opts Options) (*App, error) {
+	a, err := BuildServices(cfg, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	a.Router = a.buildRouter()
+	a.server.Handler = a.Router
+	return a, nil
+}
+
+// BuildServices opens the configured database and creates the services without the router, for commands
+// that run one task with the server's wiring
+func BuildServices(cfg *config.Config, opts Options) (*App, error) {
+	a := &App{Config: cfg, opts: opts, webDir: opts.WebDir}
+	if a.webDir == "" {
+		a.webDir = "web"
+	}
+
+	if err := a.openDatabase(); err != nil {
+		a.closeDatabases()
+		return nil, err
+	}
+	if err := a.seedDefaults(); err != nil {
+		a.closeDatabases()
+		return nil, err
+	}
+	if err := a.buildServices(); err != nil {
+		a.closeDatabases()
+		return nil, err
+	}
+
+	a.server = &http.Server{
+		Addr:         cfg.GetAddress(),
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+	}
+	return a, nil
+}
+
+// openDatabase opens the configured database, or for a replay a fresh one beside the stored bars it reads
+func (a *App) openDatabase() error {
+	cfg := a.Config
+
+	db, err := database.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	a.DB = db
+
+	// With manual migrations the server refuses to run against an outdated schema
+	if cfg.Database.ManualMigrations {
+		pending, err := db.PendingMigrations()
+		if err != nil {
+			return fmt.Errorf("failed to check schema migrations: %w", err)
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("%d schema migrations are pending; run with -migrate before starting the server", len(pending))
+		}
+	}
+
+	// Replays read the stored bars and write into a fresh database so every run starts the same
+	if cfg.Replay.Enabled {
+		a.replaySource = db
+		a.DB, err = openReplayDatabase(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to initialize replay database: %w", err)
+		}
+
+		a.replayClock = services.NewSimulatedClock(time.Time{})
+		services.SetClock(a.replayClock)
+
+		// Historical patterns must not page anyone or place orders, and live feeds have no place in a replay
+		cfg.Email.Enabled = false
+		cfg.Telegram.Enabled = false
+		cfg.Webhooks.Enabled = false
+		cfg.Broker.Enabled = false
+		cfg.Calibration.Enabled = false
+		cfg.Collection.Realtime.Enabled = false
+		cfg.Collection.Options.Enabled = false
+		cfg.ReferenceData.Enabled = false
+		cfg.SymbolSearch.Enabled = false
+	}
+
+	return nil
+}
+
+// seedDefaults adds the config's default watched symbols and watchlist strategies to an empty database
+func (a *App) seedDefaults() error {
+	cfg, db := a.Config, a.DB
+
+	// Ensure default watched symbols are present if watchlist is empty
+	watched, err := db.GetWatchedSymbols()
+	if err != nil {
+		return fmt.Errorf("failed to check watched symbols: %w", err)
+	}
+	if len(watched) == 0 && len(cfg.Collection.DefaultWatchedSymbols) > 0 && !cfg.Replay.Enabled {
+		log.Printf("No watched symbols found in DB. Adding default watched symbols from config: %v", cfg.Collection.DefaultWatchedSymbols)
+		if err := db.EnsureConfigSymbolsWatched(cfg.Collection.DefaultWatchedSymbols); err != nil {
+			return fmt.Errorf("failed to add default watched symbols: %w", err)
+		}
+	}
+
+	// Ensure strategies and stocks are properly associated in the database if resetWatchlist is set
+	if a.opts.ResetWatchlist {
+		log.Printf("Resetting watchlist to config defaults...")
+		if len(cfg.WatchlistDefaults.Strategies) > 0 {
+			if err := db.InitializeData(cfg.WatchlistDefaults.Strategies); err != nil {
+				return fmt.Errorf("failed to reset watchlist strategies: %w", err)
+			}
+		}
+		return nil
+	}
+
+	// Only apply defaults if database is empty
+	strategies, err := db.GetStrategies()
+	if err != nil {
+		return fmt.Errorf("failed to check existing strategies: %w", err)
+	}
+	if len(strategies) == 0 && len(cfg.WatchlistDefaults.Strategies) > 0 {
+		log.Printf("No strategies found in DB. Adding default strategies from config.")
+		if err := db.InitializeData(cfg.WatchlistDefaults.Strategies); err != nil {
+			return fmt.Errorf("failed to ensure default strategies: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// buildServices creates the services and wires their optional dependencies; nothing runs until Start
+func (a *App) buildServices() error {
+	cfg, db := a.Config, a.DB
+	s := &Services{}
+	a.Services = s
+
+	// Initialize Polygon service
+	s.Polygon = services.NewPolygonService(cfg)
+
+	// Initialize market data provider selected in config, or serve stored bars when replaying
+	switch {
+	case a.opts.Provider != nil:
+		s.Provider = a.opts.Provider
+	case cfg.Replay.Enabled:
+		s.Provider = services.NewReplayProvider(a.replaySource, a.replayClock)
+	default:
+		provider, err := services.NewMarketDataProvider(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to initialize market data provider: %w", err)
+		}
+		s.Provider = provider
+	}
+
+	// Initialize technical analysis and streaming services
+	s.TechnicalAnalysis = services.NewTechnicalAnalysisService(db, nil)
+	s.Streaming = services.NewStreamingService(s.TechnicalAnalysis)
+
+	// In-app notification center, recording alerts whether or not email is configured
+	s.Notifications = services.NewNotificationService(db)
+	s.Notifications.SetStreamingService(s.Streaming)
+
+	// Initialize email service
+	s.Email = services.NewEmailService(cfg)
+	s.Email.SetNotificationService(s.Notifications)
+
+	// Initialize alert rule service, evaluated after each collection cycle
+	s.AlertRules = services.NewAlertRuleService(db, s.TechnicalAnalysis, s.Email)
+	s.AlertRules.SetNotificationService(s.Notifications)
+
+	// Exchange trading calendar: holidays, early closes and pre/post market sessions
+	s.MarketCalendar = services.NewMarketCalendar(cfg.MarketHours)
+
+	// Time zone chart and summary responses are localized to
+	s.Display = services.NewDisplayService(cfg)
+
+	// Intraday relative volume against the same time of day on prior sessions
+	s.RVOL = services.NewRVOLService(cfg, db, s.MarketCalendar)
+	s.AlertRules.SetRVOLService(s.RVOL)
+
+	// Volume spikes, price gaps and potential halts flagged on collected bars
+	s.Anomalies = services.NewAnomalyService(cfg, db, s.MarketCalendar)
+	s.Anomalies.SetNotificationService(s.Notifications)
+	s.AlertRules.SetAnomalyService(s.Anomalies)
+
+	// Closes beyond strong support and resistance levels on high volume
+	s.SRBreaks = services.NewSRBreakService(cfg, db, s.MarketCalendar)
+	s.SRBreaks.SetNotificationService(s.Notifications)
+
+	// Initialize collector service
+	s.Collector = services.NewCollectorService(db, s.Provider, cfg)
+	s.Collector.SetMarketCalendar(s.MarketCalendar)
+	s.Collector.SetStreamingService(s.Streaming)
+	s.Collector.SetAlertRuleService(s.AlertRules)
+	s.Collector.SetAnomalyService(s.Anomalies)
+	s.Collector.SetSRBreakService(s.SRBreaks)
+	s.Collector.SetNotificationService(s.Notifications)
+
+	// Dependency checks behind the liveness and readiness probes
+	s.Health = services.NewHealthService(db, s.Provider, s.Collector)
+
+	// Options chain snapshots, collected alongside bars when enabled
+	s.OptionsChain = services.NewOptionsService(cfg, db)
+	if !cfg.Replay.Enabled {
+		s.Collector.SetOptionsService(s.OptionsChain)
+	}
+
+	// Optional Polygon WebSocket ingestion; REST polling covers symbols while the socket is down
+	s.Realtime = services.NewPolygonStream(cfg, db.GetWatchedSymbols, s.Collector.IngestRealtimeBars)
+	s.Collector.SetRealtimeStream(s.Realtime)
+
+	// Initialize services
+	s.SupportResistance = services.NewSupportResistanceService(db, s.TechnicalAnalysis)
+	s.VolumeProfile = services.NewVolumeProfileService(cfg, db)
+	s.SupportResistance.SetVolumeProfileService(s.VolumeProfile)
+
+	// Touches, bounces and breaks of active levels recorded as bars are collected
+	s.SRTouches = services.NewSRTouchService(cfg, db, s.SupportResistance)
+	s.Collector.SetSRTouchService(s.SRTouches)
+
+	// Scheduled S/R recalculation, notifying about significant new and invalidated levels
+	s.SRRecalculation = services.NewSRRecalculationService(cfg, db, s.SupportResistance, s.MarketCalendar)
+	s.SRRecalculation.SetNotificationService(s.Notifications)
+
+	// Daily and weekly floor pivots, listed with S/R levels and optionally scored as setup confluence
+	s.FloorPivots = services.NewFloorPivotService(db, s.MarketCalendar)
+
+	s.Setups = services.NewSetupDetectionService(db, s.TechnicalAnalysis, s.SupportResistance)
+	s.Setups.SetNotificationService(s.Notifications)
+	s.Setups.SetMarketCalendar(s.MarketCalendar)
+	s.Setups.SetFloorPivotService(s.FloorPivots)
+	s.Collector.SetSetupService(s.Setups)
+
+	// Earnings and economic calendar used to flag setups spanning scheduled events
+	s.Calendar = services.NewCalendarService(cfg, db)
+	s.Setups.SetCalendarService(s.Calendar)
+
+	// Headline ingestion for watched symbols
+	s.News = services.NewNewsService(cfg, db)
+
+	// FINRA short volume and short interest, flagged in setup checklists when elevated
+	s.ShortInterest = services.NewShortInterestService(cfg, db, s.MarketCalendar)
+	s.Setups.SetShortInterestService(s.ShortInterest)
+
+	// SEC Form 4 insider transactions, with cluster buying alerts for stocks with active bullish setups
+	s.Insiders = services.NewInsiderService(cfg, db)
+	s.Insiders.SetNotificationService(s.Notifications)
+
+	// Sector, market cap, float and average volume for watchlist symbols
+	s.ReferenceData = services.NewReferenceDataService(cfg, db)
+
+	// Ticker typeahead and checks of newly watched symbols
+	s.SymbolSearch = services.NewSymbolSearchService(cfg)
+
+	// Sector relative strength against the benchmark
+	s.SectorStrength = services.NewSectorStrengthService(cfg, db)
+
+	// Benchmark trends, watchlist breadth and the market regime setups are scored against
+	s.MarketContext = services.NewMarketContextService(cfg, db)
+	s.Setups.SetMarketContextService(s.MarketContext)
+
+	// Daily regime labels of the regime index gating which setups are detected
+	s.MarketRegimes = services.NewMarketRegimeService(cfg, db, s.MarketContext.RegimeIndex())
+	s.Setups.SetMarketRegimeService(s.MarketRegimes)
+	if !cfg.Replay.Enabled {
+		s.Collector.SetBenchmarks(s.MarketContext.Benchmarks())
+	}
+
+	// Percent change series of several symbols for comparison charts
+	s.Compare = services.NewRelativePerformanceService(db, s.MarketCalendar)
+
+	// Daily ATR, range, gap and session volume stats of each watched symbol
+	s.SymbolStats = services.NewSymbolStatsService(cfg, db, s.MarketCalendar)
+
+	// Morning ranking of pre-market gappers, optionally tagged into a watchlist strategy
+	s.GapScan = services.NewGapScanService(cfg, db, s.MarketCalendar, s.RVOL)
+
+	// Telegram alerts and bot commands
+	s.Telegram = services.NewTelegramService(cfg, db, s.Setups)
+	s.Email.SetTelegramService(s.Telegram)
+
+	// Pattern charts rendered for alert emails and the API
+	s.Charts = services.NewChartService(db)
+	s.Email.SetChartService(s.Charts)
+
+	// Thesis timelines of stored patterns for the Pattern Watcher page
+	s.PatternTimelines = services.NewPatternTimelineService(db)
+	s.Setups.SetTelegramService(s.Telegram)
+
+	// Signed pattern and setup lifecycle events for external automation
+	s.Webhooks = services.NewWebhookService(cfg, db)
+	s.Setups.SetWebhookService(s.Webhooks)
+
+	// Config file changes to collection, notification and logging settings apply without a restart
+	s.ConfigReloader = services.NewConfigReloader(a.opts.ConfigPath, cfg, db, s.Collector, s.Email, s.Telegram)
+	s.ConfigReloader.SetLogLevelFunc(setGinMode)
+	s.ConfigReloader.SetNotificationService(s.Notifications)
+
+	// Portfolio tracking of positions taken on setups
+	s.Portfolio = services.NewPortfolioService(db)
+
+	// Opt-in paper trading of high-quality setups
+	s.PaperTrading = services.NewPaperTradingService(cfg, db, s.Setups)
+
+	// Position sizing and portfolio heat for setup responses
+	s.Risk = services.NewRiskService(cfg, db)
+
+	// Opt-in broker orders for triggered setups of auto trading strategies
+	s.Broker = services.NewBrokerService(cfg, db, s.Risk, s.Portfolio)
+	s.Setups.SetBrokerService(s.Broker)
+
+	// Initialize pattern detection services
+	s.FallingWedge = services.NewFallingWedgeDetectionService(db, s.TechnicalAnalysis, s.Email)
+	s.HeadShoulders = services.NewHeadShouldersDetectionService(db, s.Setups, s.TechnicalAnalysis, s.Email)
+	s.HeadShoulders.SetStreamingService(s.Streaming)
+	s.Triangle = services.NewTriangleDetectionService(db, s.TechnicalAnalysis, s.Email)
+	s.Flag = services.NewFlagDetectionService(db, s.TechnicalAnalysis, s.Email)
+	s.HeadShoulders.SetWebhookService(s.Webhooks)
+	s.FallingWedge.SetWebhookService(s.Webhooks)
+	s.Triangle.SetWebhookService(s.Webhooks)
+	s.Flag.SetWebhookService(s.Webhooks)
+	if cfg.PatternDetection.HeadShoulders != nil {
+		s.HeadShoulders.SetConfig(cfg.PatternDetection.HeadShoulders)
+	}
+	if cfg.PatternDetection.FallingWedge != nil {
+		s.FallingWedge.SetConfig(cfg.PatternDetection.FallingWedge)
+	}
+	if cfg.PatternDetection.Triangle != nil {
+		s.Triangle.SetConfig(cfg.PatternDetection.Triangle)
+	}
+	if cfg.PatternDetection.Flag != nil {
+		s.Flag.SetConfig(cfg.PatternDetection.Flag)
+	}
+	s.Patterns = services.NewPatternDetectionService(db, s.TechnicalAnalysis, s.HeadShoulders, s.FallingWedge, s.Triangle, s.Flag, s.Email)
+	s.Patterns.SetWebhookService(s.Webhooks)
+
+	// Recent bars kept in memory for indicator and pattern scans, maintained by the collector
+	var detectionPrices services.PriceReader = db
+	if cfg.PriceCache.Days >= 0 {
+		s.PriceCache = services.NewPriceCache(cfg, db)
+		s.Collector.SetPriceCache(s.PriceCache)
+		s.TechnicalAnalysis.SetPriceCache(s.PriceCache)
+		detectionPrices = s.PriceCache
+	}
+
+	// Pattern and S/R detection can leave pre- and post-market bars out
+	if cfg.PatternDetection.RegularHoursOnly {
+		detectionPrices = services.NewRegularSessionReader(detectionPrices, s.MarketCalendar)
+	}
+	s.SupportResistance.SetPriceReader(detectionPrices)
+	s.HeadShoulders.SetPriceReader(detectionPrices)
+	s.FallingWedge.SetPriceReader(detectionPrices)
+	s.Triangle.SetPriceReader(detectionPrices)
+	s.Flag.SetPriceReader(detectionPrices)
+
+	// Scoring and detection settings tuned through the API override the config file and defaults
+	s.Settings = services.NewSettingsService(db, s.Setups, s.SupportResistance, s.HeadShoulders, s.FallingWedge, s.Triangle, s.Flag, s.Display)
+	if err := s.Settings.Load(); err != nil {
+		log.Printf("Failed to load saved settings: %v", err)
+	}
+
+	// Opt-in checklist item weights learned from setup outcomes
+	s.Calibration = services.NewCalibrationService(cfg, db, s.Setups)
+	if err := s.Calibration.Load(); err != nil {
+		log.Printf("Failed to load calibrated scoring weights: %v", err)
+	}
+
+	// A replay runs the pattern scans itself on the simulated clock
+	if cfg.Replay.Enabled {
+		s.Replay = services.NewReplayService(cfg, db, a.replaySource, a.replayClock, s.Collector, s.Patterns)
+	}
+
+	// Scheduled daily and weekly digest emails
+	s.Digest = services.NewDigestService(cfg, db, s.Collector, s.Email, s.MarketCalendar)
+
+	// Bounded worker pool for long-running scans, backfills and recomputations
+	s.Jobs = services.NewJobService(cfg.Jobs.Workers)
+
+	// Worker pool shared by pattern scans, indicator refreshes and S/R detection across many symbols
+	s.Scanner = services.NewWorkerPool(cfg.Scanner.Workers)
+	s.Patterns.SetWorkerPool(s.Scanner)
+
+	// Scans and alerts attached to watchlist strategies, run on their schedules
+	s.Automations = services.NewStrategyAutomationService(db, s.Patterns, s.AlertRules, s.MarketCalendar)
+	s.Automations.SetNotificationService(s.Notifications)
+	s.Automations.SetWorkerPool(s.Scanner)
+
+	// Market-wide screener over Polygon grouped daily bars
+	s.Screener = services.NewScreenerService(cfg, db, s.TechnicalAnalysis)
+
+	// Initialize Polygon EMA service
+	s.EMA = services.NewPolygonEMAService(cfg.Polygon.APIKey, cfg.Polygon.Timeout)
+
+	// Initialize stock service
+	s.Stocks = services.NewStockService(db, s.Polygon, s.EMA)
+
+	// Background schedules and job types that can be disabled at runtime
+	s.Schedules = services.NewScheduleControl()
+	s.Collector.SetScheduleControl(s.Schedules)
+	s.Jobs.SetScheduleControl(s.Schedules)
+	s.Patterns.SetScheduleControl(s.Schedules)
+	s.News.SetScheduleControl(s.Schedules)
+	s.ShortInterest.SetScheduleControl(s.Schedules)
+	s.Insiders.SetScheduleControl(s.Schedules)
+	s.Calendar.SetScheduleControl(s.Schedules)
+	s.ReferenceData.SetScheduleControl(s.Schedules)
+	s.SymbolStats.SetScheduleControl(s.Schedules)
+	s.GapScan.SetScheduleControl(s.Schedules)
+	s.Digest.SetScheduleControl(s.Schedules)
+	s.Automations.SetScheduleControl(s.Schedules)
+	s.SRRecalculation.SetScheduleControl(s.Schedules)
+
+	return nil
+}
+
+// Start validates the market data provider, collects history and starts the collector and schedulers
+func (a *App) Start() error {
+	cfg, s := a.Config, a.Services
+
+	// Validate provider credentials/connectivity
+	if err := s.Provider.HealthCheck(); err != nil {
+		return fmt.Errorf("failed to validate %s market data provider: %w", s.Provider.Name(), err)
+	}
+	log.Printf("Using %s market data provider", s.Provider.Name())
+
+	// Replays load their own history and step the collector on the simulated clock
+	if !cfg.Replay.Enabled {
+		// Collect historical data if requested, or default minimum for dashboard functionality
+		historicalDays := a.opts.HistoricalDays
+		if historicalDays == 0 {
+			// Default to 30 days to support all dashboard time ranges (1D, 1W, 2W, 1M)
+			historicalDays = 30
+			log.Printf("Auto-collecting 30 days of historical data to support all dashboard time ranges...")
+		} else {
+			log.Printf("Collecting historical data for %d days...", historicalDays)
+		}
+
+		if err := s.Collector.CollectHistoricalData(historicalDays); err != nil {
+			log.Printf("Failed to collect historical data: %v", err)
+		} else {
+			log.Printf("Historical data collection completed")
+		}
+
+		// Start the collector service
+		if err := s.Collector.Start(); err != nil {
+			return fmt.Errorf("failed to start collector service: %w", err)
+		}
+
+		s.Realtime.Start()
+
+		// Force initial collection to ensure we have some data
+		log.Printf("Triggering initial data collection...")
+		if err := s.Collector.ForceCollection(); err != nil {
+			log.Printf("Warning: Failed to trigger initial collection: %v", err)
+		}
+	}
+
+	s.Calendar.Start()
+	s.News.Start()
+	s.ShortInterest.Start()
+	s.Insiders.Start()
+	s.ReferenceData.Start()
+
+	s.Telegram.Start()
+	if a.opts.WatchConfig {
+		s.ConfigReloader.Start()
+	}
+	s.PaperTrading.Start()
+	s.Broker.Start()
+	s.Calibration.Start()
+
+	// Periodically scan watched symbols for patterns and update active pattern theses; a replay
+	// runs the scans itself on the simulated clock instead
+	if s.Replay != nil {
+		if err := s.Replay.Start(); err != nil {
+			return fmt.Errorf("failed to start replay: %w", err)
+		}
+	} else {
+		s.Patterns.StartPeriodicPatternDetection(cfg.PatternDetection.ScanInterval, cfg.PatternDetection.MonitorInterval)
+	}
+
+	// A replay has no mornings to send digests on or closes to compute stats after, and runs its own pattern scans
+	if !cfg.Replay.Enabled {
+		if err := s.Digest.Start(); err != nil {
+			log.Printf("Failed to schedule digests: %v", err)
+		}
+		if err := s.SymbolStats.Start(); err != nil {
+			log.Printf("Failed to schedule symbol stats: %v", err)
+		}
+		if err := s.GapScan.Start(); err != nil {
+			log.Printf("Failed to schedule pre-market gap scan: %v", err)
+		}
+		if err := s.Automations.Start(); err != nil {
+			log.Printf("Failed to schedule strategy automations: %v", err)
+		}
+		if err := s.SRRecalculation.Start(); err != nil {
+			log.Printf("Failed to schedule S/R recalculation: %v", err)
+		}
+	}
+
+	s.Jobs.Start()
+
+	return nil
+}
+
+// ListenAndServe serves the router until Shutdown is called
+func (a *App) ListenAndServe() error {
+	addr := a.Config.GetAddress()
+	log.Printf("Starting server on %s", addr)
+	log.Printf("Dashboard available at: http://%s", addr)
+	log.Printf("Pattern Watcher available at: http://%s/pattern-watcher", addr)
+	log.Printf("Unified Patterns API available at: http://%s/api/v1/patterns/", addr)
+	log.Printf("API available at: http://%s/api/v1 (docs at /api/docs)", addr)
+	log.Printf("WebSocket stream available at: ws://%s/ws/stream", addr)
+
+	if err := a.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown stops accepting requests, stops background work so pending batch inserts finish, then closes the
+// databases. Services that were never started stop immediately, so it is safe after BuildServer alone.
+func (a *App) Shutdown(ctx context.Context) error {
+	s := a.Services
+
+	// Stop accepting new requests and let in-flight requests complete
+	if err := a.server.Shutdown(ctx); err != nil {
+		log.Printf("HTTP server shutdown error: %v", err)
+	}
+
+	// Disconnect streaming clients (hijacked WebSocket connections are not tracked by the server)
+	s.Streaming.Stop()
+
+	if s.Replay != nil {
+		if err := s.Replay.Stop(ctx); err != nil {
+			log.Printf("Replay shutdown error: %v", err)
+		}
+	}
+	if err := s.Realtime.Stop(ctx); err != nil {
+		log.Printf("WebSocket ingestion shutdown error: %v", err)
+	}
+	if err := s.Collector.Shutdown(ctx); err != nil {
+		log.Printf("Collector shutdown error: %v", err)
+	}
+	if err := s.Patterns.Stop(ctx); err != nil {
+		log.Printf("Pattern detection shutdown error: %v", err)
+	}
+	if err := s.PaperTrading.Stop(ctx); err != nil {
+		log.Printf("Paper trading shutdown error: %v", err)
+	}
+	if err := s.Broker.Stop(ctx); err != nil {
+		log.Printf("Broker shutdown error: %v", err)
+	}
+	if err := s.Calibration.Stop(ctx); err != nil {
+		log.Printf("Calibration shutdown error: %v", err)
+	}
+	if err := s.Telegram.Stop(ctx); err != nil {
+		log.Printf("Telegram shutdown error: %v", err)
+	}
+	if err := s.ConfigReloader.Stop(ctx); err != nil {
+		log.Printf("Config watcher shutdown error: %v", err)
+	}
+	if err := s.Calendar.Stop(ctx); err != nil {
+		log.Printf("Calendar shutdown error: %v", err)
+	}
+	if err := s.News.Stop(ctx); err != nil {
+		log.Printf("News shutdown error: %v", err)
+	}
+	if err := s.ShortInterest.Stop(ctx); err != nil {
+		log.Printf("Short interest shutdown error: %v", err)
+	}
+	if err := s.Insiders.Stop(ctx); err != nil {
+		log.Printf("Insiders shutdown error: %v", err)
+	}
+	if err := s.ReferenceData.Stop(ctx); err != nil {
+		log.Printf("Reference data shutdown error: %v", err)
+	}
+	if err := s.Digest.Stop(ctx); err != nil {
+		log.Printf("Digest shutdown error: %v", err)
+	}
+	if err := s.SymbolStats.Stop(ctx); err != nil {
+		log.Printf("Symbol stats shutdown error: %v", err)
+	}
+	if err := s.GapScan.Stop(ctx); err != nil {
+		log.Printf("Gap scan shutdown error: %v", err)
+	}
+	if err := s.Automations.Stop(ctx); err != nil {
+		log.Printf("Strategy automation shutdown error: %v", err)
+	}
+	if err := s.SRRecalculation.Stop(ctx); err != nil {
+		log.Printf("S/R recalculation shutdown error: %v", err)
+	}
+	if err := s.Jobs.Stop(ctx); err != nil {
+		log.Printf("Job queue shutdown error: %v", err)
+	}
+	// Last, as the services above emit webhooks until they stop
+	if err := s.Webhooks.Stop(ctx); err != nil {
+		log.Printf("Webhook shutdown error: %v", err)
+	}
+
+	return a.closeDatabases()
+}
+
+// closeDatabases closes the app's database and, for a replay, the stored database it reads
+func (a *App) closeDatabases() error {
+	var errs []error
+	if a.DB != nil {
+		if err := a.DB.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close database: %w", err))
+		}
+	}
+	if a.replaySource != nil && a.replaySource != a.DB {
+		if err := a.replaySource.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close stored database: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// setGinMode enables gin's debug output only at the debug logging level
+func setGinMode(level string) {
+	if level == "debug" {
+		gin.SetMode(gin.DebugMode)
+	} else {
+		gin.SetMode(gin.ReleaseMode)
+	}
+}
+
+// openReplayDatabase recreates the SQLite database a replay writes its bars, patterns and setups to
+func openReplayDatabase(cfg *config.Config) (*database.Database, error) {
+	path := cfg.Replay.DatabasePath
+	if path == "" {
+		path = "./data/replay.db"
+	}
+	if cfg.Database.Driver != "postgres" && path == cfg.Database.Path {
+		return nil, fmt.Errorf("replay database path must differ from the stored database path")
+	}
+
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		if err := os.Remove(path + suffix); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove previous replay database: %w", err)
+		}
+	}
+
+	replayCfg := *cfg
+	replayCfg.Database = config.DatabaseConfig{Driver: "sqlite3", Path: path}
+	return database.New(&replayCfg)
+}
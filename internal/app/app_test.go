@@ -0,0 +1,275 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// stubProvider serves no bars, so the router can be exercised without network access
+type stubProvider struct{}
+
+func (stubProvider) Name() string { return "stub" }
+func (stubProvider) GetLatestAggregates(string, int) ([]*models.VolumeData, error) {
+	return nil, nil
+}
+func (stubProvider) GetLatestPriceAggregates(string, int) ([]*models.PriceData, error) {
+	return nil, nil
+}
+func (stubProvider) GetHistoricalData(string, int) ([]*models.VolumeData, error) { return nil, nil }
+func (stubProvider) GetHistoricalPriceData(string, int) ([]*models.PriceData, error) {
+	return nil, nil
+}
+func (stubProvider) GetAggregates(string, time.Time, time.Time) ([]*models.VolumeData, error) {
+	return nil, nil
+}
+func (stubProvider) GetPriceAggregates(string, time.Time, time.Time) ([]*models.PriceData, error) {
+	return nil, nil
+}
+func (stubProvider) HealthCheck() error { return nil }
+
+// newTestApp builds the full server over a temporary SQLite database
+func newTestApp(t *testing.T) *App {
+	t.Helper()
+
+	cfg := &config.Config{}
+	cfg.Database.Path = filepath.Join(t.TempDir(), "market-watch.db")
+	cfg.Polygon.APIKey = "test"
+	cfg.Collection.DefaultWatchedSymbols = []string{"AAPL", "MSFT"}
+	cfg.WatchlistDefaults.Strategies = []config.WatchlistStrategyConfig{{Name: "Breakouts", Color: "#00ff00", Stocks: []string{"NVDA"}}}
+
+	a, err := BuildServer(cfg, Options{WebDir: filepath.Join("..", "..", "web"), Provider: stubProvider{}})
+	if err != nil {
+		t.Fatalf("BuildServer failed: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := a.Shutdown(ctx); err != nil {
+			t.Errorf("Shutdown failed: %v", err)
+		}
+	})
+	return a
+}
+
+// TestBuildServerRoutes tests that the built router serves the API, its unversioned aliases and the dashboard
+func TestBuildServerRoutes(t *testing.T) {
+	a := newTestApp(t)
+
+	tests := []struct {
+		method, path string
+		status       int
+		contains     string
+	}{
+		{"GET", "/api/v1/health", http.StatusOK, `"stub"`},
+		{"GET", "/api/v1/symbols", http.StatusOK, "MSFT"},
+		{"GET", "/api/symbols", http.StatusOK, "AAPL"},
+		{"GET", "/api/v1/watchlist/strategies", http.StatusOK, "Breakouts"},
+		{"GET", "/api/v1/notifications", http.StatusOK, `"unread":0`},
+		{"GET", "/api/v1/price/AAPL?from=2025-01-01&to=2025-03-01", http.StatusOK, `"total_records":0`},
+		{"GET", "/api/v1/price/AAPL/chart?range=1M&style=line", http.StatusOK, `"method":"none"`},
+		{"GET", "/api/v1/price/AAPL/chart?max_points=5", http.StatusBadRequest, ""},
+		{"GET", "/api/v1/dashboard/overview", http.StatusOK, `"watched_symbols":2`},
+		{"POST", "/api/v1/support-resistance/detect?symbols=AAPL", http.StatusOK, `"symbols":1`},
+		{"POST", "/api/v1/support-resistance/aapl/recalculate", http.StatusOK, `"symbol":"AAPL"`},
+		{"GET", "/api/v1/symbols/search?q=apple", http.StatusServiceUnavailable, "symbol_search.enabled"},
+		{"GET", "/api/v1/setups/id/abc", http.StatusBadRequest, ""},
+		{"POST", "/api/v1/setups/id/42/archive", http.StatusNotFound, "trading setup not found"},
+		{"POST", "/api/v1/patterns/triangle/42/archive", http.StatusNotFound, "triangle pattern not found"},
+		{"POST", "/api/v1/patterns/wedge/42/unarchive", http.StatusBadRequest, "Invalid family"},
+		{"POST", "/api/v1/patterns/archive?days=30", http.StatusOK, `"archived_count":0`},
+		{"OPTIONS", "/api/v1/symbols", http.StatusNoContent, ""},
+		{"GET", "/healthz", http.StatusOK, `"uptime_seconds"`},
+		{"GET", "/readyz", http.StatusServiceUnavailable, "collection is not scheduled"},
+		{"POST", "/api/v1/admin/collector/pause", http.StatusOK, `"enabled":false`},
+		{"POST", "/api/v1/collection/force", http.StatusServiceUnavailable, ""},
+		{"GET", "/api/v1/collection/status", http.StatusOK, `"paused":true`},
+		{"POST", "/api/v1/admin/collector/resume", http.StatusOK, `"enabled":true`},
+		{"GET", "/", http.StatusOK, "<html"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		a.Router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+		if w.Code != tt.status {
+			t.Errorf("%s %s: expected status %d, got %d: %s", tt.method, tt.path, tt.status, w.Code, w.Body.String())
+			continue
+		}
+		if !strings.Contains(w.Body.String(), tt.contains) {
+			t.Errorf("%s %s: expected body to contain %q, got %s", tt.method, tt.path, tt.contains, w.Body.String())
+		}
+	}
+}
+
+// TestBuildServerAddSymbol tests that a symbol added through the API is stored in the app's database
+func TestBuildServerAddSymbol(t *testing.T) {
+	a := newTestApp(t)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/api/v1/symbols", strings.NewReader(`{"symbol":"TSLA","name":"Tesla"}`))
+	req.Header.Set("Content-Type", "application/json")
+	a.Router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK && w.Code != http.StatusCreated {
+		t.Fatalf("expected the symbol to be added, got %d: %s", w.Code, w.Body.String())
+	}
+
+	symbols, err := a.DB.GetWatchedSymbols()
+	if err != nil {
+		t.Fatalf("GetWatchedSymbols failed: %v", err)
+	}
+	found := false
+	for _, symbol := range symbols {
+		found = found || symbol == "TSLA"
+	}
+	if !found {
+		t.Errorf("expected TSLA to be watched, got %v", symbols)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Errorf("expected a JSON response: %v", err)
+	}
+}
+
+// TestBuildServerAddCryptoSymbol tests that crypto and FX tickers can be watched and malformed ones are rejected
+func TestBuildServerAddCryptoSymbol(t *testing.T) {
+	a := newTestApp(t)
+
+	tests := []struct {
+		symbol string
+		status int
+	}{
+		{"x:btcusd", http.StatusCreated},
+		{"C:EURUSD", http.StatusCreated},
+		{"C:EURO", http.StatusBadRequest},
+		{"BTC/USD", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/api/v1/symbols", strings.NewReader(`{"symbol":"`+tt.symbol+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		a.Router.ServeHTTP(w, req)
+		if w.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d: %s", tt.symbol, tt.status, w.Code, w.Body.String())
+		}
+	}
+
+	symbols, err := a.DB.GetWatchedSymbols()
+	if err != nil {
+		t.Fatalf("GetWatchedSymbols failed: %v", err)
+	}
+	watched := strings.Join(symbols, ",")
+	if !strings.Contains(watched, "X:BTCUSD") || !strings.Contains(watched, "C:EURUSD") {
+		t.Errorf("expected the crypto and FX symbols to be watched, got %v", symbols)
+	}
+}
+
+// TestBuildServerBulkAddStocks tests that symbols added in bulk are reported per symbol and backfilled by one job
+func TestBuildServerBulkAddStocks(t *testing.T) {
+	a := newTestApp(t)
+
+	strategies, err := a.DB.GetStrategies()
+	if err != nil || len(strategies) == 0 {
+		t.Fatalf("expected seeded strategies, got %v (%v)", strategies, err)
+	}
+
+	w := httptest.NewRecorder()
+	body := fmt.Sprintf(`{"symbols":["PLTR","SOFI","??"],"strategy_ids":[%d],"backfill_days":5}`, strategies[0].ID)
+	req := httptest.NewRequest("POST", "/api/v1/watchlist/stocks/bulk/add", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	a.Router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected the bulk add to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var result models.WatchlistBulkResult
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("expected a bulk result: %v", err)
+	}
+	if result.Succeeded != 2 || result.Failed != 1 {
+		t.Errorf("expected 2 symbols added and 1 failure, got %+v", result)
+	}
+	if result.BackfillJob == nil || result.BackfillJob.Type != models.JobTypeBackfill || result.BackfillJob.Total != 2 {
+		t.Errorf("expected one backfill job for both new symbols, got %+v", result.BackfillJob)
+	}
+}
+
+// TestBuildServerStrategyAutomations tests attaching an automation to a strategy, running it and reading the
+// strategy's run history
+func TestBuildServerStrategyAutomations(t *testing.T) {
+	a := newTestApp(t)
+
+	strategies, err := a.DB.GetStrategies()
+	if err != nil || len(strategies) == 0 {
+		t.Fatalf("expected seeded strategies, got %v (%v)", strategies, err)
+	}
+	base := fmt.Sprintf("/api/v1/watchlist/strategies/%d/automations", strategies[0].ID)
+
+	w := httptest.NewRecorder()
+	body := `{"name":"Daily wedges","action":"pattern_scan","patterns":["falling_wedge"],"schedule":"30 16 * * 1-5"}`
+	req := httptest.NewRequest("POST", base, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	a.Router.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected the automation to be created, got %d: %s", w.Code, w.Body.String())
+	}
+	var automation models.StrategyAutomation
+	if err := json.Unmarshal(w.Body.Bytes(), &automation); err != nil || automation.ID == 0 || !automation.IsActive {
+		t.Fatalf("expected the created automation, got %s (%v)", w.Body.String(), err)
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("POST", base, strings.NewReader(`{"name":"Bad","action":"pattern_scan","schedule":"daily"}`))
+	req.Header.Set("Content-Type", "application/json")
+	a.Router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected an invalid schedule to be rejected, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	a.Router.ServeHTTP(w, httptest.NewRequest("POST", fmt.Sprintf("%s/%d/run", base, automation.ID), nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected the automation to run, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	a.Router.ServeHTTP(w, httptest.NewRequest("GET", base+"/runs", nil))
+	var runs []models.StrategyAutomationRun
+	if err := json.Unmarshal(w.Body.Bytes(), &runs); err != nil || len(runs) != 1 || runs[0].Trigger != models.AutomationTriggerManual {
+		t.Errorf("expected the manual run in the strategy's history, got %s (%v)", w.Body.String(), err)
+	}
+
+	w = httptest.NewRecorder()
+	a.Router.ServeHTTP(w, httptest.NewRequest("DELETE", fmt.Sprintf("/api/v1/watchlist/strategies/%d/automations/%d", strategies[0].ID+1000, automation.ID), nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected another strategy's automation to be not found, got %d", w.Code)
+	}
+}
+
+// TestBuildServerCancelledRequest tests that an API request whose client has gone away doesn't run its queries
+func TestBuildServerCancelledRequest(t *testing.T) {
+	a := newTestApp(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w := httptest.NewRecorder()
+	a.Router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/symbols", nil).WithContext(ctx))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected the cancelled query to fail, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	a.Router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/symbols", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected a live request to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+}
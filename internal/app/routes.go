@@ -0,0 +1,610 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	_ "market-watch-go/docs"
+	"market-watch-go/internal/handlers"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+)
+
+// buildRouter creates the handlers over the app's services and registers the dashboard, API and docs routes
+func (a *App) buildRouter() *gin.Engine {
+	s := a.Services
+
+	// Initialize handlers
+	volumeHandler := handlers.NewVolumeHandler(a.DB, s.Collector, s.Provider, s.Patterns)
+	volumeHandler.SetRVOLService(s.RVOL)
+	volumeHandler.SetSymbolSearch(s.SymbolSearch)
+	volumeHandler.SetDisplayService(s.Display)
+	priceHandler := handlers.NewPriceHandler(a.DB, s.Collector, s.Provider)
+	priceHandler.SetMarketCalendar(s.MarketCalendar)
+	priceHandler.SetDisplayService(s.Display)
+	marketHandler := handlers.NewMarketHandler(s.MarketCalendar, s.MarketContext, s.MarketRegimes)
+	healthHandler := handlers.NewHealthHandler(s.Health)
+	dashboardHandler := handlers.NewDashboardHandler(filepath.Join(a.webDir, "templates"), filepath.Join(a.webDir, "static"), a.DB)
+	dashboardHandler.SetCollector(s.Collector)
+	debugHandler := handlers.NewDebugHandler(a.DB, s.Collector)
+	taHandler := handlers.NewTechnicalAnalysisHandler(a.DB, s.TechnicalAnalysis)
+	taHandler.SetWorkerPool(s.Scanner)
+	setupHandler := handlers.NewSetupHandler(a.DB, s.Setups)
+	setupHandler.SetRiskService(s.Risk)
+	calibrationHandler := handlers.NewCalibrationHandler(s.Calibration)
+	srHandler := handlers.NewSupportResistanceHandler(a.DB, s.SupportResistance)
+	srHandler.SetWorkerPool(s.Scanner)
+	srHandler.SetRecalculationService(s.SRRecalculation)
+	srHandler.SetFloorPivotService(s.FloorPivots)
+	fallingWedgeHandler := handlers.NewFallingWedgeHandler(a.DB, s.FallingWedge)
+	patternsHandler := handlers.NewPatternsHandler(a.DB, s.Patterns, s.HeadShoulders, s.FallingWedge, s.Triangle, s.Flag)
+	patternsHandler.SetJobService(s.Jobs)
+	patternsHandler.SetChartService(s.Charts)
+	patternsHandler.SetTimelineService(s.PatternTimelines)
+	patternsHandler.SetHeadShouldersEditor(s.HeadShoulders)
+	emailHandler := handlers.NewEmailHandler(s.Email)
+	reportsHandler := handlers.NewReportsHandler(s.Digest)
+	notificationsHandler := handlers.NewNotificationsHandler(a.DB)
+	webhooksHandler := handlers.NewWebhooksHandler(a.DB, s.Webhooks)
+	watchlistHandler := handlers.NewWatchlistHandler(a.DB, s.Stocks)
+	watchlistHandler.SetReferenceDataService(s.ReferenceData)
+	watchlistHandler.SetSymbolSearch(s.SymbolSearch)
+	watchlistHandler.SetBackfill(s.Jobs, s.Collector)
+	watchlistHandler.SetStrategyAutomation(s.Automations)
+	streamingHandler := handlers.NewStreamingHandler(s.Streaming)
+	alertsHandler := handlers.NewAlertsHandler(a.DB, s.AlertRules)
+	anomaliesHandler := handlers.NewAnomaliesHandler(a.DB, s.Anomalies)
+	portfolioHandler := handlers.NewPortfolioHandler(a.DB, s.Portfolio)
+	paperTradingHandler := handlers.NewPaperTradingHandler(s.PaperTrading)
+	brokerHandler := handlers.NewBrokerHandler(a.DB, s.Broker)
+	vwapSetupsHandler := handlers.NewVWAPSetupsHandler(a.DB)
+	strategyRegimesHandler := handlers.NewStrategyRegimesHandler(a.DB)
+	calendarHandler := handlers.NewCalendarHandler(a.DB, s.Calendar)
+	symbolStatsHandler := handlers.NewSymbolStatsHandler(a.DB, s.SymbolStats)
+	gapScanHandler := handlers.NewGapScanHandler(s.GapScan)
+	chartsHandler := handlers.NewChartsHandler(a.DB)
+	newsHandler := handlers.NewNewsHandler(s.News)
+	shortInterestHandler := handlers.NewShortInterestHandler(s.ShortInterest)
+	insidersHandler := handlers.NewInsidersHandler(s.Insiders)
+	optionsHandler := handlers.NewOptionsHandler(s.OptionsChain)
+	screenerHandler := handlers.NewScreenerHandler(a.DB, s.Screener)
+	jobsHandler := handlers.NewJobsHandler(a.DB, s.Jobs, s.Collector, s.TechnicalAnalysis)
+	exportService := services.NewExportService(a.DB)
+	exportService.SetPriceCache(s.PriceCache)
+	exportHandler := handlers.NewExportHandler(exportService)
+	analyticsHandler := handlers.NewAnalyticsHandler(s.SectorStrength, s.Compare)
+	volumeProfileHandler := handlers.NewVolumeProfileHandler(s.VolumeProfile)
+	settingsHandler := handlers.NewSettingsHandler(s.Settings)
+	adminHandler := handlers.NewAdminHandler(s.ConfigReloader, s.Schedules)
+	auditHandler := handlers.NewAuditHandler(a.DB)
+
+	// Polygon EMA handlers
+	polygonEMAHandler := handlers.PolygonEMAHandler(s.EMA)
+	polygonEMABatchHandler := handlers.PolygonEMABatchHandler(s.EMA)
+
+	// Set up Gin router
+	setGinMode(a.Config.Logging.Level)
+
+	router := gin.New()
+	router.Use(gin.Logger())
+	router.Use(gin.Recovery())
+	// Compresses responses with Brotli or gzip, including streamed ones as they are flushed
+	router.Use(handlers.CompressionMiddleware())
+	// Records mutating requests in the audit log, outside ErrorMiddleware so the final status is recorded
+	router.Use(handlers.AuditMiddleware(a.DB))
+	// Logs handler errors and answers them with a status code, error code and sanitized message
+	router.Use(handlers.ErrorMiddleware())
+
+	// CORS middleware
+	router.Use(func(c *gin.Context) {
+		c.Header("Access-Control-Allow-Origin", "*")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-CSRF-Token, If-None-Match, If-Modified-Since")
+		c.Header("Access-Control-Expose-Headers", "ETag, Last-Modified")
+		c.Header("Access-Control-Allow-Credentials", "true")
+
+		if c.Request.Method == "OPTIONS" {
+			c.AbortWithStatus(204)
+			return
+		}
+
+		c.Next()
+	})
+
+	// Load HTML templates
+	router.LoadHTMLGlob(filepath.Join(a.webDir, "templates", "*"))
+
+	// Add middleware to disable caching for static files in development
+	router.Use(func(c *gin.Context) {
+		if strings.HasPrefix(c.Request.URL.Path, "/static/") {
+			c.Header("Cache-Control", "no-cache, no-store, must-revalidate")
+			c.Header("Pragma", "no-cache")
+			c.Header("Expires", "0")
+		}
+		c.Next()
+	})
+
+	// Static files with no-cache headers for development
+	router.Static("/static", filepath.Join(a.webDir, "static"))
+
+	// Liveness and readiness probes
+	router.GET("/healthz", healthHandler.Liveness)
+	router.GET("/readyz", healthHandler.Readiness)
+
+	// Dashboard routes
+	router.GET("/", dashboardHandler.Index)
+
+	// Pattern Watcher page
+	router.GET("/pattern-watcher", func(c *gin.Context) {
+		c.HTML(200, "pattern-watcher.html", gin.H{
+			"title": "Pattern Watcher",
+		})
+	})
+
+	// Watchlist page
+	router.GET("/watchlist", watchlistHandler.RenderWatchlistPage)
+
+	// Sector strength page
+	router.GET("/sectors", func(c *gin.Context) {
+		c.HTML(200, "sectors.html", gin.H{
+			"title": "Sector Strength",
+		})
+	})
+
+	// WebSocket streaming of real-time price/volume bars, indicators and alerts
+	router.GET("/ws/stream", streamingHandler.Stream)
+
+	// API routes are versioned under /api/v1; the unversioned /api paths remain as aliases
+	registerAPIRoutes := func(api *gin.RouterGroup) {
+		// Health check
+		api.GET("/health", volumeHandler.HealthCheck)
+
+		// Streaming status
+		api.GET("/stream/status", streamingHandler.GetStatus)
+
+		// Volume data endpoints
+		volume := api.Group("/volume")
+		{
+			volume.GET("/:symbol", volumeHandler.GetVolumeData)
+			volume.GET("/:symbol/latest", volumeHandler.GetLatestVolumeData)
+			volume.GET("/:symbol/chart", volumeHandler.GetChartData)
+			volume.GET("/:symbol/rvol", volumeHandler.GetRVOL)
+		}
+
+		// Volume by price
+		api.GET("/volume-profile/:symbol", volumeProfileHandler.GetVolumeProfile)
+
+		// Price data endpoints for TradingView
+		price := api.Group("/price")
+		{
+			price.GET("/:symbol", priceHandler.GetPriceData)
+			price.GET("/:symbol/latest", priceHandler.GetLatestPriceData)
+			price.GET("/:symbol/chart", priceHandler.GetPriceChartData)
+			price.GET("/:symbol/stats", priceHandler.GetPriceStats)
+		}
+
+		// Dashboard endpoints
+		dashboard := api.Group("/dashboard")
+		{
+			dashboard.GET("/summary", volumeHandler.GetDashboardSummary)
+			dashboard.GET("/overview", dashboardHandler.GetOverview)
+		}
+
+		// Collection management endpoints
+		collection := api.Group("/collection")
+		{
+			collection.GET("/status", volumeHandler.GetCollectionStatus)
+			collection.POST("/force", volumeHandler.ForceCollection)
+		}
+
+		// Exchange session, holiday calendar and market context endpoints
+		market := api.Group("/market")
+		{
+			market.GET("/status", marketHandler.GetStatus)
+			market.GET("/holidays", marketHandler.GetHolidays)
+			market.GET("/context", marketHandler.GetContext)
+			market.GET("/regime", marketHandler.GetRegime)
+			market.GET("/regime/history", marketHandler.GetRegimeHistory)
+			market.GET("/gaps", gapScanHandler.GetGapReport)
+			market.POST("/gaps/scan", gapScanHandler.ScanGaps)
+		}
+
+		// Symbol management endpoints
+		api.GET("/symbols", volumeHandler.GetWatchedSymbols)
+		api.POST("/symbols", volumeHandler.AddWatchedSymbol)
+		api.GET("/symbols/search", volumeHandler.SearchSymbols)
+		api.DELETE("/symbols/:symbol", volumeHandler.RemoveWatchedSymbol)
+		api.GET("/symbols/:symbol/check", volumeHandler.CheckSymbolData)
+		api.POST("/symbols/:symbol/collect", volumeHandler.CollectSymbolData)
+
+		// Daily trading statistics for stop and target sizing
+		api.GET("/symbols/stats", symbolStatsHandler.GetAllSymbolStats)
+		api.POST("/symbols/stats/refresh", symbolStatsHandler.RefreshSymbolStats)
+		api.GET("/symbols/:symbol/stats", symbolStatsHandler.GetSymbolStats)
+
+		// Chart annotations and layouts saved per user, named by the X-Actor header
+		charts := api.Group("/charts")
+		{
+			charts.GET("/:symbol", chartsHandler.GetChart)
+			charts.GET("/:symbol/annotations", chartsHandler.GetChartAnnotations)
+			charts.POST("/:symbol/annotations", chartsHandler.CreateChartAnnotation)
+			charts.DELETE("/:symbol/annotations", chartsHandler.ClearChartAnnotations)
+			charts.PUT("/:symbol/annotations/:id", chartsHandler.UpdateChartAnnotation)
+			charts.DELETE("/:symbol/annotations/:id", chartsHandler.DeleteChartAnnotation)
+			charts.GET("/:symbol/layout", chartsHandler.GetChartLayout)
+			charts.PUT("/:symbol/layout", chartsHandler.SaveChartLayout)
+			charts.DELETE("/:symbol/layout", chartsHandler.DeleteChartLayout)
+		}
+
+		// Debug endpoints
+		debug := api.Group("/debug")
+		{
+			debug.GET("/count", debugHandler.GetDataCount)
+			debug.GET("/coverage", debugHandler.GetCoverage)
+		}
+
+		// Technical Analysis endpoints
+		indicators := api.Group("/indicators")
+		{
+			indicators.GET("/:symbol", taHandler.GetIndicators)
+			indicators.GET("/:symbol/summary", taHandler.GetIndicatorsSummary)
+			indicators.GET("/:symbol/historical", taHandler.GetHistoricalIndicators)
+			indicators.GET("/:symbol/macd", taHandler.GetMACDSeries)
+			indicators.POST("/:symbol/update", taHandler.UpdateIndicators)
+			indicators.GET("/:symbol/alerts", taHandler.CheckAlerts)
+			indicators.GET("/:symbol/alerts/active", taHandler.GetActiveAlerts)
+			indicators.POST("/:symbol/cache/invalidate", taHandler.InvalidateSymbolCache)
+		}
+
+		technicalAnalysis := api.Group("/technical-analysis")
+		{
+			technicalAnalysis.GET("/indicators", taHandler.GetMultipleIndicators)
+			technicalAnalysis.GET("/stats", taHandler.GetStats)
+			technicalAnalysis.GET("/cache/status", taHandler.GetCacheStatus)
+			technicalAnalysis.POST("/cache/clear", taHandler.ClearCache)
+		}
+
+		// Setup Detection endpoints
+		setups := api.Group("/setups")
+		{
+			setups.GET("/high-quality", setupHandler.GetHighQualitySetups)
+			setups.GET("/", setupHandler.GetMultipleSetups)
+			setups.POST("/expire", setupHandler.ExpireOldSetups)
+			setups.POST("/cleanup", setupHandler.CleanupOldSetups)
+			setups.GET("/stats", setupHandler.GetSetupsStats)
+			setups.GET("/risk", setupHandler.GetPortfolioHeat)
+			setups.GET("/calibration", calibrationHandler.GetCalibration)
+			setups.POST("/calibration/run", calibrationHandler.RunCalibration)
+			setups.POST("/calibration/versions/:id/activate", calibrationHandler.ActivateCalibration)
+			setups.POST("/calibration/deactivate", calibrationHandler.DeactivateCalibration)
+			setups.GET("/:symbol", setupHandler.GetSetups)
+			setups.POST("/:symbol/detect", setupHandler.DetectSetups)
+			setups.GET("/:symbol/summary", setupHandler.GetSetupSummary)
+			setups.GET("/id/:id", setupHandler.GetSetupByID)
+			setups.PUT("/id/:id/status", setupHandler.UpdateSetupStatus)
+			setups.POST("/id/:id/archive", setupHandler.ArchiveSetup)
+			setups.POST("/id/:id/unarchive", setupHandler.UnarchiveSetup)
+			setups.GET("/id/:id/checklist", setupHandler.GetSetupChecklist)
+		}
+
+		// Alert rule endpoints
+		alerts := api.Group("/alerts")
+		{
+			alerts.GET("", alertsHandler.GetRules)
+			alerts.POST("", alertsHandler.CreateRule)
+			alerts.GET("/history", alertsHandler.GetHistory)
+			alerts.POST("/evaluate", alertsHandler.EvaluateRules)
+			alerts.GET("/:id", alertsHandler.GetRule)
+			alerts.PUT("/:id", alertsHandler.UpdateRule)
+			alerts.DELETE("/:id", alertsHandler.DeleteRule)
+			alerts.GET("/:id/history", alertsHandler.GetRuleHistory)
+			alerts.GET("/:id/state", alertsHandler.GetRuleState)
+		}
+
+		// Market anomaly endpoints
+		anomalies := api.Group("/anomalies")
+		{
+			anomalies.GET("", anomaliesHandler.GetAnomalies)
+			anomalies.POST("/scan", anomaliesHandler.ScanAnomalies)
+			anomalies.GET("/:symbol", anomaliesHandler.GetSymbolAnomalies)
+		}
+
+		// Portfolio endpoints
+		portfolio := api.Group("/portfolio")
+		{
+			portfolio.GET("", portfolioHandler.GetPortfolio)
+			portfolio.GET("/performance", portfolioHandler.GetPerformance)
+			portfolio.GET("/positions", portfolioHandler.GetPositions)
+			portfolio.POST("/positions", portfolioHandler.OpenPosition)
+			portfolio.GET("/positions/:id", portfolioHandler.GetPosition)
+			portfolio.POST("/positions/:id/close", portfolioHandler.ClosePosition)
+			portfolio.DELETE("/positions/:id", portfolioHandler.DeletePosition)
+		}
+
+		// Paper trading endpoints
+		paperTrading := api.Group("/paper-trading")
+		{
+			paperTrading.GET("/status", paperTradingHandler.GetStatus)
+			paperTrading.GET("/trades", paperTradingHandler.GetTrades)
+			paperTrading.GET("/equity", paperTradingHandler.GetEquityCurve)
+			paperTrading.POST("/run", paperTradingHandler.RunCycle)
+			paperTrading.POST("/reset", paperTradingHandler.Reset)
+			paperTrading.POST("/trades/:id/close", paperTradingHandler.CloseTrade)
+		}
+
+		// Broker order endpoints
+		broker := api.Group("/broker")
+		{
+			broker.GET("/status", brokerHandler.GetBrokerStatus)
+			broker.GET("/orders", brokerHandler.GetBrokerOrders)
+			broker.POST("/orders/sync", brokerHandler.SyncBrokerOrders)
+			broker.POST("/orders/:id/cancel", brokerHandler.CancelBrokerOrder)
+		}
+
+		// Earnings and economic calendar endpoints
+		calendar := api.Group("/calendar")
+		{
+			calendar.GET("", calendarHandler.GetEvents)
+			calendar.POST("/events", calendarHandler.AddEvent)
+			calendar.DELETE("/events/:id", calendarHandler.DeleteEvent)
+			calendar.POST("/refresh", calendarHandler.RefreshEarnings)
+			calendar.GET("/:symbol", calendarHandler.GetSymbolEvents)
+		}
+
+		// News endpoints
+		news := api.Group("/news")
+		{
+			news.POST("/refresh", newsHandler.RefreshNews)
+			news.GET("/:symbol", newsHandler.GetSymbolNews)
+		}
+
+		// Short volume and short interest endpoints
+		api.GET("/short-interest/:symbol", shortInterestHandler.GetShortInterest)
+
+		// SEC Form 4 insider transaction endpoints
+		api.GET("/insiders/:symbol", insidersHandler.GetInsiders)
+
+		// Options flow endpoints
+		options := api.Group("/options")
+		{
+			options.GET("/:symbol/summary", optionsHandler.GetSummary)
+			options.POST("/:symbol/collect", optionsHandler.CollectSnapshot)
+		}
+
+		// Screener endpoints
+		screener := api.Group("/screener")
+		{
+			screener.POST("/run", screenerHandler.RunScreen)
+			screener.POST("/watch", screenerHandler.AddToWatchlist)
+			screener.GET("/screens", screenerHandler.GetScreens)
+			screener.POST("/screens", screenerHandler.CreateScreen)
+			screener.PUT("/screens/:id", screenerHandler.UpdateScreen)
+			screener.DELETE("/screens/:id", screenerHandler.DeleteScreen)
+		}
+
+		// Watchlist analytics endpoints
+		analytics := api.Group("/analytics")
+		{
+			analytics.GET("/sectors", analyticsHandler.GetSectorStrength)
+		}
+
+		// Relative performance of several symbols for comparison charts
+		api.GET("/compare", analyticsHandler.GetRelativePerformance)
+
+		// Runtime settings endpoints
+		settings := api.Group("/settings")
+		{
+			settings.GET("", settingsHandler.GetAllSettings)
+			settings.GET("/:section", settingsHandler.GetSettings)
+			settings.PUT("/:section", settingsHandler.UpdateSettings)
+			settings.DELETE("/:section", settingsHandler.ResetSettings)
+		}
+
+		// Email endpoints
+		email := api.Group("/email")
+		{
+			email.GET("/status", emailHandler.GetEmailStatus)
+			email.POST("/test", emailHandler.SendTestEmail)
+		}
+
+		// Notification center endpoints
+		notifications := api.Group("/notifications")
+		{
+			notifications.GET("", notificationsHandler.GetNotifications)
+			notifications.POST("/read", notificationsHandler.MarkAllNotificationsRead)
+			notifications.PUT("/:id/read", notificationsHandler.MarkNotificationRead)
+			notifications.DELETE("", notificationsHandler.ClearNotifications)
+		}
+
+		// Webhook endpoints
+		webhooks := api.Group("/webhooks")
+		{
+			webhooks.POST("/test", webhooksHandler.SendTestWebhook)
+			webhooks.GET("/dead-letters", webhooksHandler.GetWebhookDeadLetters)
+			webhooks.POST("/dead-letters/:id/retry", webhooksHandler.RetryWebhookDeadLetter)
+		}
+
+		// Digest report endpoints
+		reports := api.Group("/reports")
+		{
+			reports.GET("/:period", reportsHandler.GetReport)
+		}
+
+		// Admin endpoints
+		admin := api.Group("/admin")
+		{
+			admin.POST("/config/reload", adminHandler.ReloadConfig)
+			admin.POST("/collector/pause", adminHandler.PauseCollector)
+			admin.POST("/collector/resume", adminHandler.ResumeCollector)
+			admin.POST("/jobs/pause", adminHandler.PauseJobs)
+			admin.POST("/jobs/resume", adminHandler.ResumeJobs)
+			admin.GET("/schedules", adminHandler.GetSchedules)
+			admin.PUT("/schedules/:name", adminHandler.SetSchedule)
+		}
+
+		// Audit log of mutating requests
+		api.GET("/audit", auditHandler.GetAuditLog)
+
+		// Background job endpoints
+		jobs := api.Group("/jobs")
+		{
+			jobs.GET("", jobsHandler.GetJobs)
+			jobs.POST("/backfill", jobsHandler.QueueBackfill)
+			jobs.POST("/indicators", jobsHandler.QueueIndicatorRecompute)
+			jobs.GET("/:id", jobsHandler.GetJob)
+			jobs.POST("/:id/cancel", jobsHandler.CancelJob)
+		}
+
+		// CSV/Parquet export and import of stored data
+		api.GET("/export/:dataset", exportHandler.Export)
+		api.POST("/import/:dataset", exportHandler.Import)
+
+		// Support/Resistance endpoints
+		supportResistance := api.Group("/support-resistance")
+		{
+			supportResistance.GET("/levels", srHandler.GetMultipleLevels)
+			supportResistance.POST("/cleanup", srHandler.CleanupOldData)
+			supportResistance.POST("/deactivate", srHandler.DeactivateOldLevels)
+			supportResistance.POST("/detect", srHandler.DetectMultipleSupportResistance)
+			supportResistance.GET("/:symbol/levels", srHandler.GetSupportResistanceLevels)
+			supportResistance.POST("/:symbol/detect", srHandler.DetectSupportResistance)
+			supportResistance.POST("/:symbol/recalculate", srHandler.RecalculateSupportResistance)
+			supportResistance.GET("/:symbol/nearest", srHandler.GetNearestLevels)
+			supportResistance.GET("/:symbol/zones", srHandler.GetZones)
+			supportResistance.GET("/:symbol/touches", srHandler.GetLevelTouches)
+			supportResistance.GET("/:symbol/pivots", srHandler.GetPivotPoints)
+			supportResistance.GET("/:symbol/summary", srHandler.GetLevelSummary)
+		}
+
+		// Unified Patterns API - handles all pattern types
+		patterns := api.Group("/patterns")
+		{
+			patterns.POST("/scan", patternsHandler.ScanAllPatterns)
+			patterns.POST("/scan/:symbol", patternsHandler.ScanSymbolPatterns)
+			patterns.POST("/monitor", patternsHandler.MonitorPatterns)
+			patterns.GET("/", patternsHandler.GetAllPatterns)
+			patterns.GET("/:symbol", patternsHandler.GetPatternsBySymbol)
+			patterns.GET("/:symbol/chart.png", patternsHandler.GetPatternChart)
+			patterns.GET("/:symbol/timeline", patternsHandler.GetPatternTimeline)
+			patterns.GET("/stats", patternsHandler.GetPatternStatistics)
+			patterns.GET("/scheduler", patternsHandler.GetSchedulerStatus)
+			patterns.GET("/links", patternsHandler.GetPatternLinks)
+			patterns.POST("/archive", patternsHandler.ArchiveOldPatterns)
+			patterns.POST("/:family/:id/archive", patternsHandler.ArchivePattern)
+			patterns.POST("/:family/:id/unarchive", patternsHandler.UnarchivePattern)
+			patterns.PUT("/head-shoulders/:id/key-points", patternsHandler.EditHeadShouldersKeyPoints)
+			patterns.GET("/head-shoulders/:id/edits", patternsHandler.GetHeadShouldersEdits)
+		}
+
+		// Head & Shoulders Pattern routes removed - use unified /api/patterns/ instead
+
+		// Falling Wedge Pattern routes (legacy compatibility)
+		fw := api.Group("/falling-wedge")
+		{
+			fw.GET("/patterns", fallingWedgeHandler.GetPatterns)
+			fw.GET("/patterns/active", fallingWedgeHandler.GetActivePatterns)
+			fw.GET("/patterns/stats", fallingWedgeHandler.GetPatternStatistics)
+			fw.GET("/symbols/:symbol/patterns", fallingWedgeHandler.GetPatternsBySymbol)
+			fw.POST("/symbols/:symbol/detect", fallingWedgeHandler.DetectPattern)
+			fw.GET("/patterns/:id", fallingWedgeHandler.GetPatternDetails)
+			fw.POST("/patterns/scan", fallingWedgeHandler.ScanPatterns)
+		}
+
+		// Watchlist routes
+		watchlist := api.Group("/watchlist")
+		{
+			// Strategies
+			watchlist.GET("/strategies", watchlistHandler.GetStrategies)
+			watchlist.POST("/strategies", watchlistHandler.CreateStrategy)
+			watchlist.PUT("/strategies/:id", watchlistHandler.UpdateStrategy)
+			watchlist.DELETE("/strategies/:id", watchlistHandler.DeleteStrategy)
+
+			// Scans and alerts attached to a strategy, with their run history
+			watchlist.GET("/strategies/:id/automations", watchlistHandler.GetStrategyAutomations)
+			watchlist.POST("/strategies/:id/automations", watchlistHandler.CreateStrategyAutomation)
+			watchlist.GET("/strategies/:id/automations/runs", watchlistHandler.GetStrategyAutomationRuns)
+			watchlist.PUT("/strategies/:id/automations/:automationId", watchlistHandler.UpdateStrategyAutomation)
+			watchlist.DELETE("/strategies/:id/automations/:automationId", watchlistHandler.DeleteStrategyAutomation)
+			watchlist.POST("/strategies/:id/automations/:automationId/run", watchlistHandler.RunStrategyAutomation)
+
+			// Broker orders for the strategy's triggered setups
+			watchlist.PUT("/strategies/:id/auto-trade", brokerHandler.SetStrategyAutoTrade)
+
+			// VWAP reclaim and rejection setups for the strategy's stocks
+			watchlist.GET("/strategies/:id/vwap-setups", vwapSetupsHandler.GetStrategyVWAPSetups)
+			watchlist.PUT("/strategies/:id/vwap-setups", vwapSetupsHandler.SetStrategyVWAPSetups)
+			watchlist.GET("/strategies/:id/regimes", strategyRegimesHandler.GetStrategyRegimes)
+			watchlist.PUT("/strategies/:id/regimes", strategyRegimesHandler.SetStrategyRegimes)
+
+			// Backward compatibility routes (categories -> strategies)
+			watchlist.GET("/categories", watchlistHandler.GetStrategies)
+			watchlist.POST("/categories", watchlistHandler.CreateStrategy)
+			watchlist.PUT("/categories/:id", watchlistHandler.UpdateStrategy)
+			watchlist.DELETE("/categories/:id", watchlistHandler.DeleteStrategy)
+
+			// Stocks
+			watchlist.GET("/stocks", watchlistHandler.GetStocks)
+			watchlist.POST("/stocks", watchlistHandler.AddStock)
+			watchlist.PUT("/stocks/:id", watchlistHandler.UpdateStock)
+			watchlist.DELETE("/stocks/:id", watchlistHandler.RemoveStock)
+
+			// Add, remove or move many symbols at once
+			watchlist.POST("/stocks/bulk/add", watchlistHandler.BulkAddStocks)
+			watchlist.POST("/stocks/bulk/remove", watchlistHandler.BulkRemoveStocks)
+			watchlist.POST("/stocks/bulk/move", watchlistHandler.BulkMoveStocks)
+
+			// Stock tags
+			watchlist.GET("/tags", watchlistHandler.GetTags)
+			watchlist.POST("/stocks/:id/tags", watchlistHandler.AddStockTags)
+			watchlist.PUT("/stocks/:id/tags", watchlistHandler.SetStockTags)
+			watchlist.DELETE("/stocks/:id/tags/:tag", watchlistHandler.RemoveStockTag)
+
+			// Journal entries per stock and setup, and search across notes, tags and the journal
+			watchlist.GET("/journal", watchlistHandler.GetJournalEntries)
+			watchlist.POST("/journal", watchlistHandler.CreateJournalEntry)
+			watchlist.GET("/journal/:id", watchlistHandler.GetJournalEntry)
+			watchlist.PUT("/journal/:id", watchlistHandler.UpdateJournalEntry)
+			watchlist.DELETE("/journal/:id", watchlistHandler.DeleteJournalEntry)
+			watchlist.GET("/search", watchlistHandler.Search)
+
+			// Company reference data
+			watchlist.GET("/reference/:symbol", watchlistHandler.GetReference)
+			watchlist.POST("/reference/:symbol/refresh", watchlistHandler.RefreshReference)
+
+			// Import from and export to CSV, TradingView and thinkorswim watchlist files
+			watchlist.POST("/import", watchlistHandler.ImportWatchlist)
+			watchlist.GET("/export", watchlistHandler.ExportWatchlist)
+
+			// Refresh prices and EMAs for all stocks
+			watchlist.POST("/refresh", handlers.WatchlistRefreshHandler(a.DB, s.Stocks))
+		}
+
+		// Polygon EMA endpoints
+		api.GET("/polygon/ema", polygonEMAHandler)
+		api.GET("/polygon/ema/batch", polygonEMABatchHandler)
+	}
+	// API requests run under the configured timeout; handlers pass the request context on to their queries
+	// and Polygon calls, so those are cancelled when it expires or the client disconnects
+	requestTimeout := a.Config.GetRequestTimeout()
+	withRequestTimeout := func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+	registerAPIRoutes(router.Group("/api/v1", withRequestTimeout))
+	registerAPIRoutes(router.Group("/api", withRequestTimeout))
+
+	// OpenAPI spec and Swagger UI
+	router.GET("/api/docs", func(c *gin.Context) {
+		c.Redirect(http.StatusMovedPermanently, "/api/docs/index.html")
+	})
+	router.GET("/api/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	return router
+}
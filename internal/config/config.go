@@ -2,47 +2,146 @@ package config
 
 import (
 	"fmt"
+	"net/mail"
+	"net/url"
 	"os"
+	"strings"
 	"time"
 
+	"market-watch-go/internal/models"
+
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Server            ServerConfig        `yaml:"server"`
-	Database          DatabaseConfig      `yaml:"database"`
-	Polygon           PolygonConfig       `yaml:"polygon"`
-	Collection        CollectionConfig    `yaml:"collection"`
-	Logging           LoggingConfig       `yaml:"logging"`
-	DataRetention     DataRetentionConfig `yaml:"data_retention"`
-	Email             EmailConfig         `yaml:"email"`
-	WatchlistDefaults WatchlistDefaults   `yaml:"watchlist_defaults"`
+	Server            ServerConfig           `yaml:"server"`
+	Database          DatabaseConfig         `yaml:"database"`
+	Polygon           PolygonConfig          `yaml:"polygon"`
+	MarketData        MarketDataConfig       `yaml:"market_data"`
+	Collection        CollectionConfig       `yaml:"collection"`
+	MarketHours       MarketHoursConfig      `yaml:"market_hours"`
+	Display           DisplayConfig          `yaml:"display"`
+	PatternDetection  PatternDetectionConfig `yaml:"pattern_detection"`
+	Logging           LoggingConfig          `yaml:"logging"`
+	DataRetention     DataRetentionConfig    `yaml:"data_retention"`
+	Email             EmailConfig            `yaml:"email"`
+	Telegram          TelegramConfig         `yaml:"telegram"`
+	Webhooks          WebhooksConfig         `yaml:"webhooks"`
+	Broker            BrokerConfig           `yaml:"broker"`
+	PaperTrading      PaperTradingConfig     `yaml:"paper_trading"`
+	Risk              RiskConfig             `yaml:"risk"`
+	Calibration       CalibrationConfig      `yaml:"calibration"`
+	Calendar          CalendarConfig         `yaml:"calendar"`
+	News              NewsConfig             `yaml:"news"`
+	ShortInterest     ShortInterestConfig    `yaml:"short_interest"`
+	Insiders          InsidersConfig         `yaml:"insiders"`
+	ReferenceData     ReferenceDataConfig    `yaml:"reference_data"`
+	SymbolSearch      SymbolSearchConfig     `yaml:"symbol_search"`
+	Analytics         AnalyticsConfig        `yaml:"analytics"`
+	VolumeProfile     VolumeProfileConfig    `yaml:"volume_profile"`
+	RVOL              RVOLConfig             `yaml:"rvol"`
+	Anomalies         AnomaliesConfig        `yaml:"anomalies"`
+	SRBreaks          SRBreaksConfig         `yaml:"sr_breaks"`
+	SRRecalculation   SRRecalculationConfig  `yaml:"sr_recalculation"`
+	SRTouches         SRTouchesConfig        `yaml:"sr_touches"`
+	SymbolStats       SymbolStatsConfig      `yaml:"symbol_stats"`
+	GapScan           GapScanConfig          `yaml:"gap_scan"`
+	Digest            DigestConfig           `yaml:"digest"`
+	Jobs              JobsConfig             `yaml:"jobs"`
+	Scanner           ScannerConfig          `yaml:"scanner"`
+	PriceCache        PriceCacheConfig       `yaml:"price_cache"`
+	Replay            ReplayConfig           `yaml:"replay"`
+	WatchlistDefaults WatchlistDefaults      `yaml:"watchlist_defaults"`
 }
 
 type ServerConfig struct {
-	Port         int           `yaml:"port"`
-	Host         string        `yaml:"host"`
-	ReadTimeout  time.Duration `yaml:"read_timeout"`
-	WriteTimeout time.Duration `yaml:"write_timeout"`
+	Port            int           `yaml:"port"`
+	Host            string        `yaml:"host"`
+	ReadTimeout     time.Duration `yaml:"read_timeout"`
+	WriteTimeout    time.Duration `yaml:"write_timeout"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"` // Max time to wait for in-flight work on shutdown
+	RequestTimeout  time.Duration `yaml:"request_timeout"`  // Max time an API request's queries and Polygon calls may run
 }
 
 type DatabaseConfig struct {
+	Driver          string        `yaml:"driver"` // 'sqlite3' (default) or 'postgres'
 	Path            string        `yaml:"path"`
+	DSN             string        `yaml:"dsn"` // Connection string when driver is postgres
 	MaxOpenConns    int           `yaml:"max_open_conns"`
 	MaxIdleConns    int           `yaml:"max_idle_conns"`
 	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	JournalMode     string        `yaml:"journal_mode"` // SQLite journal mode (default WAL)
+	BusyTimeout     time.Duration `yaml:"busy_timeout"` // How long SQLite writers wait for a lock (default 5s)
+	// ManualMigrations stops pending schema migrations from being applied at startup; run them with -migrate
+	ManualMigrations bool `yaml:"manual_migrations"`
 }
 
 type PolygonConfig struct {
-	APIKey        string        `yaml:"api_key"`
-	BaseURL       string        `yaml:"base_url"`
-	Timeout       time.Duration `yaml:"timeout"`
-	RetryAttempts int           `yaml:"retry_attempts"`
+	APIKey            string        `yaml:"api_key"`
+	BaseURL           string        `yaml:"base_url"`
+	Timeout           time.Duration `yaml:"timeout"`
+	RetryAttempts     int           `yaml:"retry_attempts"`
+	RequestsPerMinute int           `yaml:"requests_per_minute"` // Token bucket refill rate (default 5, the free tier limit)
+	Burst             int           `yaml:"burst"`               // Requests allowed back to back before pacing kicks in (default requests_per_minute)
+	DailyBudget       int           `yaml:"daily_budget"`        // Maximum requests per UTC day, 0 for unlimited
+	MaxBackoff        time.Duration `yaml:"max_backoff"`         // Upper bound for exponential backoff after 429s (default 1m)
+}
+
+type MarketDataConfig struct {
+	Provider string      `yaml:"provider"` // 'polygon' (default) or 'yahoo'
+	Yahoo    YahooConfig `yaml:"yahoo"`
+}
+
+type YahooConfig struct {
+	BaseURL string        `yaml:"base_url"`
+	Timeout time.Duration `yaml:"timeout"`
 }
 
 type CollectionConfig struct {
-	Interval              time.Duration `yaml:"interval"`
-	DefaultWatchedSymbols []string      `yaml:"default_watched_symbols"`
+	Interval              time.Duration            `yaml:"interval"`
+	DefaultWatchedSymbols []string                 `yaml:"default_watched_symbols"`
+	Options               OptionsCollectionConfig  `yaml:"options"`
+	Realtime              RealtimeCollectionConfig `yaml:"realtime"`
+	RegularHoursOnly      bool                     `yaml:"regular_hours_only"` // Request and store regular session bars only, without pre/post-market (default false)
+}
+
+type RealtimeCollectionConfig struct {
+	Enabled        bool          `yaml:"enabled"`         // Stream watched symbols from Polygon's WebSocket instead of polling REST
+	URL            string        `yaml:"url"`             // Socket endpoint (default wss://delayed.polygon.io/stocks)
+	Channel        string        `yaml:"channel"`         // AM (minute aggregates, default), A (second aggregates) or T (trades)
+	ReconnectDelay time.Duration `yaml:"reconnect_delay"` // Initial delay before reconnecting, doubled up to 1m (default 5s)
+}
+
+type MarketHoursConfig struct {
+	Exchange        string   `yaml:"exchange"`          // NYSE (default) or NASDAQ, which share a holiday calendar
+	CollectSessions string   `yaml:"collect_sessions"`  // 'extended' (default), 'regular' or 'always'
+	PreMarketOpen   string   `yaml:"pre_market_open"`   // Start of pre-market trading, exchange time (default 04:00)
+	PostMarketClose string   `yaml:"post_market_close"` // End of post-market trading, exchange time (default 20:00)
+	ExtraHolidays   []string `yaml:"extra_holidays"`    // Unscheduled closures as YYYY-MM-DD, e.g. national days of mourning
+}
+
+type DisplayConfig struct {
+	Timezone string `yaml:"timezone"` // IANA time zone API responses and the dashboard show times in, e.g. America/New_York (default UTC)
+}
+
+type OptionsCollectionConfig struct {
+	Enabled            bool          `yaml:"enabled"`              // Pull options chain snapshots during collection
+	Interval           time.Duration `yaml:"interval"`             // Minimum time between snapshots per symbol (default 1h)
+	MaxContracts       int           `yaml:"max_contracts"`        // Contracts read per chain (default 1000)
+	UnusualVolumeRatio float64       `yaml:"unusual_volume_ratio"` // Volume/open interest ratio flagged as unusual (default 2)
+	MinUnusualVolume   int64         `yaml:"min_unusual_volume"`   // Ignore contracts trading fewer than this (default 500)
+}
+
+type PatternDetectionConfig struct {
+	ScanInterval     time.Duration `yaml:"scan_interval"`      // How often watched symbols are scanned for new patterns (default 30m)
+	MonitorInterval  time.Duration `yaml:"monitor_interval"`   // How often active pattern theses are updated (default 5m)
+	RegularHoursOnly bool          `yaml:"regular_hours_only"` // Detect patterns and S/R levels on regular session bars only (default false)
+
+	// Per-pattern detection settings; fields left out of the config file keep their defaults
+	HeadShoulders *models.HeadShouldersConfig `yaml:"head_shoulders"`
+	FallingWedge  *models.FallingWedgeConfig  `yaml:"falling_wedge"`
+	Triangle      *models.TriangleConfig      `yaml:"triangle"`
+	Flag          *models.FlagConfig          `yaml:"flag"`
 }
 
 type LoggingConfig struct {
@@ -54,6 +153,10 @@ type LoggingConfig struct {
 type DataRetentionConfig struct {
 	Days            int           `yaml:"days"`
 	CleanupInterval time.Duration `yaml:"cleanup_interval"`
+	// CompactAfterDays rolls 1-minute bars older than this into hourly and daily candles (0 disables)
+	CompactAfterDays int `yaml:"compact_after_days"`
+	// PurgeArchivedAfterDays deletes setups and patterns archived longer ago than this (0 keeps them)
+	PurgeArchivedAfterDays int `yaml:"purge_archived_after_days"`
 }
 
 type EmailConfig struct {
@@ -64,6 +167,244 @@ type EmailConfig struct {
 	FromName    string `yaml:"from_name"`
 	FromAddress string `yaml:"from_address"`
 	Enabled     bool   `yaml:"enabled"`
+
+	DashboardURL string          `yaml:"dashboard_url"` // Linked from alert emails (default http://localhost:8080/)
+	Recipients   EmailRecipients `yaml:"recipients"`    // Alert recipients per severity
+}
+
+// EmailRecipients lists who receives alerts of each severity; a severity without recipients goes to from_address
+type EmailRecipients struct {
+	High   []string `yaml:"high"`
+	Medium []string `yaml:"medium"`
+	Low    []string `yaml:"low"`
+}
+
+type TelegramConfig struct {
+	Enabled     bool          `yaml:"enabled"`
+	BotToken    string        `yaml:"bot_token"`
+	ChatID      string        `yaml:"chat_id"`      // Only this chat receives alerts and may send commands
+	BaseURL     string        `yaml:"base_url"`     // Defaults to https://api.telegram.org
+	PollTimeout time.Duration `yaml:"poll_timeout"` // Long-poll timeout for incoming commands (default 30s)
+}
+
+type WebhooksConfig struct {
+	Enabled     bool              `yaml:"enabled"`      // Post pattern and setup lifecycle events to the endpoints
+	Endpoints   []WebhookEndpoint `yaml:"endpoints"`    // URLs events are posted to
+	MaxAttempts int               `yaml:"max_attempts"` // Deliveries tried before an event is dead-lettered (default 5)
+	RetryDelay  time.Duration     `yaml:"retry_delay"`  // Wait before the first retry, doubled after each (default 2s)
+	Timeout     time.Duration     `yaml:"timeout"`      // Time allowed for each delivery (default 10s)
+}
+
+// WebhookEndpoint receives the lifecycle events it subscribes to
+type WebhookEndpoint struct {
+	URL    string   `yaml:"url"`
+	Secret string   `yaml:"secret"` // Signs each delivery with HMAC-SHA256; unsigned when empty
+	Events []string `yaml:"events"` // Event types posted to this URL, all of them when empty
+}
+
+type BrokerConfig struct {
+	Enabled      bool          `yaml:"enabled"`       // Place bracket orders for triggered setups of strategies with auto trading on
+	Provider     string        `yaml:"provider"`      // 'alpaca' (default)
+	Alpaca       AlpacaConfig  `yaml:"alpaca"`        // Alpaca account credentials
+	TimeInForce  string        `yaml:"time_in_force"` // 'day' (default) or 'gtc'; an entry still unfilled then is canceled
+	SyncInterval time.Duration `yaml:"sync_interval"` // How often order status is synced into the portfolio (default 1m)
+}
+
+type AlpacaConfig struct {
+	APIKey    string        `yaml:"api_key"`
+	APISecret string        `yaml:"api_secret"`
+	Live      bool          `yaml:"live"`     // Trade the live account instead of the paper account
+	BaseURL   string        `yaml:"base_url"` // Overrides the paper or live trading API URL
+	Timeout   time.Duration `yaml:"timeout"`  // Per request (default 10s)
+}
+
+type PaperTradingConfig struct {
+	Enabled         bool          `yaml:"enabled"`           // Automatically take setups in the background
+	MinQualityScore float64       `yaml:"min_quality_score"` // Minimum setup score to take (default 80)
+	StartingCapital float64       `yaml:"starting_capital"`  // Simulated account size (default 100000)
+	RiskPercent     float64       `yaml:"risk_percent"`      // Percent of equity risked per trade (default 1)
+	MaxOpenTrades   int           `yaml:"max_open_trades"`   // Pending plus open trades (default 5)
+	Interval        time.Duration `yaml:"interval"`          // How often setups are taken and fills simulated (default 5m)
+}
+
+type RiskConfig struct {
+	AccountSize    float64 `yaml:"account_size"`     // Account size positions are sized against (default 100000)
+	RiskPercent    float64 `yaml:"risk_percent"`     // Percent of the account risked per setup (default 1)
+	MaxHeatPercent float64 `yaml:"max_heat_percent"` // Open risk across active and triggered setups that triggers warnings (default 6)
+	BlockOverHeat  bool    `yaml:"block_over_heat"`  // Size new entries at zero shares, instead of only warning, while over max heat
+}
+
+type CalibrationConfig struct {
+	Enabled       bool          `yaml:"enabled"`        // Recompute checklist item weights from setup outcomes in the background
+	Interval      time.Duration `yaml:"interval"`       // How often weights are recomputed (default 24h)
+	MinSamples    int           `yaml:"min_samples"`    // Resolved setups needed before weights are computed (default 30)
+	PriorStrength float64       `yaml:"prior_strength"` // Pseudo-setups pulling each item's win rate toward the overall rate (default 10)
+	AutoActivate  bool          `yaml:"auto_activate"`  // Score with each new version once the calibrated scorer is in use
+}
+
+type CalendarConfig struct {
+	Enabled         bool          `yaml:"enabled"`          // Fetch upcoming earnings for watched symbols from Polygon
+	RefreshInterval time.Duration `yaml:"refresh_interval"` // How often earnings dates are refreshed (default 12h)
+	LookaheadDays   int           `yaml:"lookahead_days"`   // How far ahead to fetch events (default 45)
+}
+
+type NewsConfig struct {
+	Enabled           bool          `yaml:"enabled"`             // Periodically fetch headlines for watched symbols
+	RefreshInterval   time.Duration `yaml:"refresh_interval"`    // How often headlines are fetched (default 30m)
+	ArticlesPerSymbol int           `yaml:"articles_per_symbol"` // Headlines fetched per symbol per refresh (default 20)
+	RetentionDays     int           `yaml:"retention_days"`      // How long articles are kept (default 30)
+}
+
+type ShortInterestConfig struct {
+	Enabled              bool          `yaml:"enabled"`                // Fetch FINRA daily short volume and Polygon short interest for watched symbols
+	RefreshInterval      time.Duration `yaml:"refresh_interval"`       // How often new short data is looked for (default 6h)
+	LookbackDays         int           `yaml:"lookback_days"`          // Trading days of short volume fetched and averaged (default 20)
+	RetentionDays        int           `yaml:"retention_days"`         // How long daily short volume is kept (default 365)
+	ElevatedVolumeRatio  float64       `yaml:"elevated_volume_ratio"`  // Average short volume percent counted as elevated (default 50)
+	ElevatedDaysToCover  float64       `yaml:"elevated_days_to_cover"` // Days to cover counted as elevated (default 5)
+	ElevatedFloatPercent float64       `yaml:"elevated_float_percent"` // Short interest percent of the float counted as elevated (default 20)
+	ShortVolumeURL       string        `yaml:"short_volume_url"`       // Base URL of FINRA's daily short volume files (default https://cdn.finra.org/equity/regsho/daily)
+}
+
+type InsidersConfig struct {
+	Enabled            bool          `yaml:"enabled"`              // Ingest SEC Form 4 insider transactions for watched stocks
+	RefreshInterval    time.Duration `yaml:"refresh_interval"`     // How often new filings are looked for (default 6h)
+	LookbackDays       int           `yaml:"lookback_days"`        // How far back filings are fetched (default 90)
+	UserAgent          string        `yaml:"user_agent"`           // Name and contact email SEC EDGAR requires of automated clients
+	ClusterBuyers      int           `yaml:"cluster_buyers"`       // Distinct insiders buying on the open market that make cluster buying (default 3)
+	ClusterDays        int           `yaml:"cluster_days"`         // Days the cluster's purchases fall within (default 14)
+	AlertClusterBuying bool          `yaml:"alert_cluster_buying"` // Notify when cluster buying hits a stock with an active bullish setup
+	SECURL             string        `yaml:"sec_url"`              // EDGAR archives base URL (default https://www.sec.gov)
+	SECDataURL         string        `yaml:"sec_data_url"`         // EDGAR submissions API base URL (default https://data.sec.gov)
+}
+
+type ReferenceDataConfig struct {
+	Enabled         bool          `yaml:"enabled"`          // Fetch sector, market cap, float and average volume for watchlist symbols from Polygon
+	RefreshInterval time.Duration `yaml:"refresh_interval"` // How old reference data may get before it is refetched (default 168h)
+	AvgVolumeDays   int           `yaml:"avg_volume_days"`  // Sessions averaged for average daily volume (default 30)
+}
+
+type SymbolSearchConfig struct {
+	Enabled  bool          `yaml:"enabled"`   // Search Polygon's reference tickers and check symbols exist and trade before they are watched
+	CacheTTL time.Duration `yaml:"cache_ttl"` // How long search results and ticker lookups are cached (default 24h)
+}
+
+type AnalyticsConfig struct {
+	Benchmark   string   `yaml:"benchmark"`    // Symbol relative strength is measured against, collected automatically (default SPY)
+	Windows     []int    `yaml:"windows"`      // Relative strength windows in trading days (default 5, 20 and 60)
+	Benchmarks  []string `yaml:"benchmarks"`   // Indexes and sector ETFs collected whether or not they are watched (default SPY, QQQ, IWM and the SPDR sector ETFs)
+	RegimeIndex string   `yaml:"regime_index"` // Benchmark whose trend sets the market regime used in setup scoring (default the relative strength benchmark)
+	// Daily regime classification of the regime index
+	RegimeTrendADX        float64 `yaml:"regime_trend_adx"`        // ADX at or above which the index is trending (default 25)
+	RegimeVolatilityRatio float64 `yaml:"regime_volatility_ratio"` // 14-day over 50-day ATR at or above which the market is highly volatile (default 1.5)
+}
+
+type VolumeProfileConfig struct {
+	WindowDays       int           `yaml:"window_days"`        // Calendar days of bars bucketed into the profile (default 20)
+	Buckets          int           `yaml:"buckets"`            // Price buckets between the window low and high (default 50)
+	ValueAreaPercent float64       `yaml:"value_area_percent"` // Share of volume in the value area around the point of control (default 70)
+	HVNRatio         float64       `yaml:"hvn_ratio"`          // Minimum bucket volume, as a multiple of the average bucket, for a high-volume node (default 1.5)
+	RefreshInterval  time.Duration `yaml:"refresh_interval"`   // How old a stored profile may get before it is recomputed (default 1h)
+}
+
+type RVOLConfig struct {
+	LookbackDays int `yaml:"lookback_days"` // Prior trading days averaged for each minute of the day (default 10)
+}
+
+type AnomaliesConfig struct {
+	Enabled      bool    `yaml:"enabled"`        // Flag unusual bars after each collection cycle and notify about them
+	VolumeZScore float64 `yaml:"volume_zscore"`  // Standard deviations above the prior bars' mean volume that flag a spike (default 4)
+	LookbackBars int     `yaml:"lookback_bars"`  // Prior bars the volume mean and deviation are taken over (default 100)
+	GapPercent   float64 `yaml:"gap_percent"`    // Move from the previous bar's close to a bar's open that flags a gap (default 3)
+	HaltMinutes  int     `yaml:"halt_minutes"`   // Minutes of the regular session without bars that flag a potential halt (default 10)
+	MinAvgVolume int64   `yaml:"min_avg_volume"` // Skip volume spikes when the prior bars average fewer shares (default 1000)
+}
+
+type SRBreaksConfig struct {
+	Enabled      bool    `yaml:"enabled"`       // Alert when a bar closes beyond a strong S/R level on high volume, for every watched symbol
+	MinStrength  float64 `yaml:"min_strength"`  // Weakest level strength (0-100) that alerts (default 60)
+	BreakPercent float64 `yaml:"break_percent"` // How far beyond the level, in percent of it, the close must be (default 0.25)
+	VolumeRatio  float64 `yaml:"volume_ratio"`  // Break bar volume as a multiple of the prior bars' average (default 1.5)
+	LookbackBars int     `yaml:"lookback_bars"` // Prior bars the average volume is taken over (default 20)
+	// Symbols overrides the settings above per symbol; a symbol can be enabled while breaks are disabled globally
+	Symbols map[string]SRBreakSymbolConfig `yaml:"symbols"`
+}
+
+type SRBreakSymbolConfig struct {
+	Enabled      *bool   `yaml:"enabled"`       // Unset follows the global setting
+	MinStrength  float64 `yaml:"min_strength"`  // 0 uses the global setting
+	BreakPercent float64 `yaml:"break_percent"` // 0 uses the global setting
+	VolumeRatio  float64 `yaml:"volume_ratio"`  // 0 uses the global setting
+}
+
+type SRRecalculationConfig struct {
+	Enabled     bool          `yaml:"enabled"`      // Recalculate the S/R levels of watched symbols during market hours
+	Interval    time.Duration `yaml:"interval"`     // Time between recalculations of a symbol (default 30m)
+	Timeframe   string        `yaml:"timeframe"`    // Candle timeframe levels are detected on (default 5m)
+	MinStrength float64       `yaml:"min_strength"` // Weakest new or invalidated level (0-100) that is notified (default 50)
+	// Intervals overrides the interval per symbol
+	Intervals map[string]time.Duration `yaml:"intervals"`
+}
+
+type SRTouchesConfig struct {
+	Enabled       bool    `yaml:"enabled"`        // Record touches, bounces and breaks of active S/R levels after each collection cycle
+	BouncePercent float64 `yaml:"bounce_percent"` // Move away from the level that confirms a bounce (default: the S/R detection min_bounce_percent)
+	BounceBars    int     `yaml:"bounce_bars"`    // Bars after a touch the bounce must happen within (default 12)
+	BreakPercent  float64 `yaml:"break_percent"`  // How far beyond the level, in percent of it, a close breaks it (default 0.25)
+	LookbackBars  int     `yaml:"lookback_bars"`  // Prior bars the volume at a touch is compared to (default 20)
+}
+
+type SymbolStatsConfig struct {
+	LookbackDays int     `yaml:"lookback_days"` // Trading days the ranges, gaps and session volumes are averaged over (default 20)
+	GapPercent   float64 `yaml:"gap_percent"`   // Move from the prior close to the open counted as a gap day (default 1)
+	Time         string  `yaml:"time"`          // HH:MM exchange time the stats are recomputed on trading days (default 16:30)
+}
+
+type GapScanConfig struct {
+	Time          string  `yaml:"time"`            // HH:MM exchange time before the open the scan runs on trading days (default 09:00)
+	MinGapPercent float64 `yaml:"min_gap_percent"` // Smallest gap from the prior close, either way, counted as a gapper (default 2)
+	MinRVOL       float64 `yaml:"min_rvol"`        // Lowest pre-market RVOL counted as a gapper (default 0, any)
+	AutoTag       bool    `yaml:"auto_tag"`        // Make the day's gappers the only stocks of the tag strategy
+	Strategy      string  `yaml:"strategy"`        // Watchlist strategy gappers are tagged into, created when missing (default Today)
+}
+
+type DigestConfig struct {
+	Enabled         bool             `yaml:"enabled"`           // Send scheduled digest emails
+	MinQualityScore float64          `yaml:"min_quality_score"` // Setups listed from this score (default 80, the high quality threshold)
+	Schedules       []DigestSchedule `yaml:"schedules"`         // One per recipient group
+}
+
+// DigestSchedule sends a daily or weekly digest to its recipients
+type DigestSchedule struct {
+	Name       string   `yaml:"name"`
+	Frequency  string   `yaml:"frequency"`  // 'daily' (trading days) or 'weekly'
+	Time       string   `yaml:"time"`       // HH:MM exchange time (default 08:00)
+	Weekday    string   `yaml:"weekday"`    // Day weekly digests are sent (default monday)
+	Recipients []string `yaml:"recipients"` // Defaults to the low severity email recipients
+}
+
+type JobsConfig struct {
+	Workers int `yaml:"workers"` // Concurrent job items such as per-symbol scans (default 4)
+}
+
+type ScannerConfig struct {
+	Workers int `yaml:"workers"` // Symbols processed at once by pattern scans, indicator refreshes and S/R detection (default 4)
+}
+
+type PriceCacheConfig struct {
+	Days    int `yaml:"days"`     // Days of 1-minute bars kept in memory per symbol, capped at compact_after_days (default 60, -1 disables)
+	MaxBars int `yaml:"max_bars"` // Most bars kept per symbol, oldest dropped first (default 40000)
+}
+
+type ReplayConfig struct {
+	Enabled      bool          `yaml:"enabled"`       // Run collection and detection off stored bars on a simulated clock (also -replay)
+	DatabasePath string        `yaml:"database_path"` // SQLite file the replay writes to, recreated on every run (default ./data/replay.db)
+	Symbols      []string      `yaml:"symbols"`       // Symbols to replay (default: watched symbols of the stored database)
+	Start        time.Time     `yaml:"start"`         // First simulated time (default: 5 days before end)
+	End          time.Time     `yaml:"end"`           // Last simulated time (default: latest stored bar)
+	Step         time.Duration `yaml:"step"`          // Simulated time advanced per collection cycle (default 5m, at most 2h)
+	Speed        float64       `yaml:"speed"`         // Simulated seconds per wall-clock second, 0 for as fast as possible (default 0)
+	WarmupDays   int           `yaml:"warmup_days"`   // History loaded before start so detectors have a lookback (default 30)
 }
 
 type WatchlistDefaults struct {
@@ -76,15 +417,22 @@ type WatchlistStrategyConfig struct {
 	Stocks []string `yaml:"stocks"`
 }
 
-// Load reads configuration from file only (no environment variable overrides)
+// Load reads configuration from the YAML file, then applies environment variable overrides (see EnvPrefix)
 func Load(configPath string) (*Config, error) {
 	cfg := &Config{}
+	cfg.PatternDetection.HeadShoulders = models.DefaultHeadShouldersConfig()
+	cfg.PatternDetection.FallingWedge = models.DefaultFallingWedgeConfig()
+	cfg.PatternDetection.Triangle = models.DefaultTriangleConfig()
+	cfg.PatternDetection.Flag = models.DefaultFlagConfig()
 	if configPath == "" {
 		return nil, fmt.Errorf("config file path is required")
 	}
 	if err := loadFromYAML(cfg, configPath); err != nil {
 		return nil, fmt.Errorf("failed to load config from YAML: %w", err)
 	}
+	if err := applyEnvOverrides(cfg); err != nil {
+		return nil, fmt.Errorf("failed to apply environment overrides: %w", err)
+	}
 	if err := validate(cfg); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
 	}
@@ -105,8 +453,30 @@ func loadFromYAML(cfg *Config, configPath string) error {
 }
 
 func validate(cfg *Config) error {
-	if cfg.Polygon.APIKey == "" || cfg.Polygon.APIKey == "your_polygon_api_key_here" {
-		return fmt.Errorf("polygon API key is required. Please set POLYGON_API_KEY environment variable or update the config file. Get your free API key at https://polygon.io/")
+	switch cfg.MarketData.Provider {
+	case "", "polygon":
+		// Replays read stored bars and never call Polygon
+		if !cfg.Replay.Enabled && (cfg.Polygon.APIKey == "" || cfg.Polygon.APIKey == "your_polygon_api_key_here") {
+			return fmt.Errorf("polygon API key is required. Please set POLYGON_API_KEY environment variable or update the config file. Get your free API key at https://polygon.io/ (or set market_data.provider to \"yahoo\")")
+		}
+	case "yahoo":
+	default:
+		return fmt.Errorf("unsupported market data provider: %s", cfg.MarketData.Provider)
+	}
+
+	switch cfg.Database.Driver {
+	case "", "sqlite3":
+		switch strings.ToUpper(cfg.Database.JournalMode) {
+		case "", "WAL", "DELETE", "TRUNCATE", "PERSIST", "MEMORY", "OFF":
+		default:
+			return fmt.Errorf("invalid database journal_mode: %s", cfg.Database.JournalMode)
+		}
+	case "postgres":
+		if cfg.Database.DSN == "" {
+			return fmt.Errorf("database dsn is required when using the postgres driver")
+		}
+	default:
+		return fmt.Errorf("unsupported database driver: %s", cfg.Database.Driver)
 	}
 
 	if cfg.Server.Port <= 0 || cfg.Server.Port > 65535 {
@@ -121,9 +491,284 @@ func validate(cfg *Config) error {
 		return fmt.Errorf("collection interval must be at least 1 minute")
 	}
 
+	for _, window := range cfg.Analytics.Windows {
+		if window < 1 || window > models.MaxRelativeStrengthWindow {
+			return fmt.Errorf("analytics.windows must be between 1 and %d trading days", models.MaxRelativeStrengthWindow)
+		}
+	}
+	if cfg.Analytics.RegimeTrendADX < 0 || cfg.Analytics.RegimeVolatilityRatio < 0 {
+		return fmt.Errorf("analytics.regime_trend_adx and regime_volatility_ratio must not be negative")
+	}
+
+	if vp := cfg.VolumeProfile; vp.WindowDays < 0 || vp.Buckets < 0 || vp.Buckets > models.MaxVolumeProfileBuckets || vp.ValueAreaPercent < 0 || vp.ValueAreaPercent > 100 {
+		return fmt.Errorf("volume_profile requires a non-negative window_days, at most %d buckets and a value_area_percent between 0 and 100", models.MaxVolumeProfileBuckets)
+	}
+
+	if cfg.RVOL.LookbackDays < 0 || cfg.RVOL.LookbackDays > models.MaxRVOLLookbackDays {
+		return fmt.Errorf("rvol lookback_days must be between 0 and %d", models.MaxRVOLLookbackDays)
+	}
+
+	if risk := cfg.Risk; risk.AccountSize < 0 || risk.RiskPercent < 0 || risk.RiskPercent > 100 || risk.MaxHeatPercent < 0 || risk.MaxHeatPercent > 100 {
+		return fmt.Errorf("risk requires a non-negative account_size and risk_percent and max_heat_percent between 0 and 100")
+	}
+
+	if cfg.PriceCache.Days < -1 || cfg.PriceCache.MaxBars < 0 {
+		return fmt.Errorf("price_cache requires days of at least -1 and a non-negative max_bars")
+	}
+
+	if cfg.DataRetention.CompactAfterDays < 0 {
+		return fmt.Errorf("data_retention compact_after_days must not be negative")
+	}
+
+	if cfg.DataRetention.PurgeArchivedAfterDays < 0 {
+		return fmt.Errorf("data_retention purge_archived_after_days must not be negative")
+	}
+
+	if err := validateMarketHours(&cfg.MarketHours); err != nil {
+		return err
+	}
+	if tz := cfg.Display.Timezone; tz != "" {
+		if _, err := time.LoadLocation(tz); err != nil {
+			return fmt.Errorf("display.timezone: unknown time zone %q", tz)
+		}
+	}
+
+	if err := validateDigest(&cfg.Digest); err != nil {
+		return err
+	}
+
+	for _, recipient := range append(append(append([]string{}, cfg.Email.Recipients.High...), cfg.Email.Recipients.Medium...), cfg.Email.Recipients.Low...) {
+		if _, err := mail.ParseAddress(recipient); err != nil {
+			return fmt.Errorf("invalid email recipient %q: %w", recipient, err)
+		}
+	}
+
+	if a := cfg.Anomalies; a.VolumeZScore < 0 || a.LookbackBars < 0 || a.GapPercent < 0 || a.HaltMinutes < 0 || a.MinAvgVolume < 0 {
+		return fmt.Errorf("anomalies requires non-negative thresholds")
+	}
+
+	if err := validateSRBreaks(&cfg.SRBreaks); err != nil {
+		return err
+	}
+	if err := validateSRRecalculation(&cfg.SRRecalculation); err != nil {
+		return err
+	}
+	if t := cfg.SRTouches; t.BouncePercent < 0 || t.BounceBars < 0 || t.BreakPercent < 0 || t.LookbackBars < 0 {
+		return fmt.Errorf("sr_touches requires non-negative thresholds and bar counts")
+	}
+
+	if s := cfg.SymbolStats; s.LookbackDays < 0 || s.LookbackDays > models.MaxRelativeStrengthWindow || s.GapPercent < 0 {
+		return fmt.Errorf("symbol_stats requires lookback_days between 0 and %d and a non-negative gap_percent", models.MaxRelativeStrengthWindow)
+	}
+	if at := cfg.SymbolStats.Time; at != "" {
+		if _, err := time.Parse("15:04", at); err != nil {
+			return fmt.Errorf("symbol_stats.time must be HH:MM")
+		}
+	}
+
+	if g := cfg.GapScan; g.MinGapPercent < 0 || g.MinRVOL < 0 {
+		return fmt.Errorf("gap_scan requires a non-negative min_gap_percent and min_rvol")
+	}
+	if at := cfg.GapScan.Time; at != "" {
+		if t, err := time.Parse("15:04", at); err != nil || t.Hour()*60+t.Minute() >= 9*60+30 {
+			return fmt.Errorf("gap_scan.time must be HH:MM before the 09:30 open")
+		}
+	}
+
+	if c := cfg.Calibration; c.Interval < 0 || c.MinSamples < 0 || c.PriorStrength < 0 {
+		return fmt.Errorf("calibration requires a non-negative interval, min_samples and prior_strength")
+	}
+
+	if err := validateWebhooks(&cfg.Webhooks); err != nil {
+		return err
+	}
+
+	if err := validateBroker(&cfg.Broker); err != nil {
+		return err
+	}
+
+	if hs := cfg.PatternDetection.HeadShoulders; hs != nil {
+		if err := validatePatternScan("head_shoulders", hs.BarInterval, hs.LookbackDays, hs.Sensitivity); err != nil {
+			return err
+		}
+	}
+	if fw := cfg.PatternDetection.FallingWedge; fw != nil {
+		if err := validatePatternScan("falling_wedge", fw.BarInterval, fw.LookbackDays, fw.Sensitivity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateDigest checks the frequency, time, weekday and recipients of each digest schedule
+func validateDigest(digest *DigestConfig) error {
+	if digest.MinQualityScore < 0 || digest.MinQualityScore > 100 {
+		return fmt.Errorf("digest min_quality_score must be between 0 and 100")
+	}
+
+	for i, schedule := range digest.Schedules {
+		name := schedule.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		if !models.IsDigestPeriod(schedule.Frequency) {
+			return fmt.Errorf("digest schedule %s: frequency must be %s or %s", name, models.DigestDaily, models.DigestWeekly)
+		}
+		if schedule.Time != "" {
+			if _, err := time.Parse("15:04", schedule.Time); err != nil {
+				return fmt.Errorf("digest schedule %s: time must be HH:MM", name)
+			}
+		}
+		if schedule.Weekday != "" {
+			if _, err := models.ParseWeekday(schedule.Weekday); err != nil {
+				return fmt.Errorf("digest schedule %s: %w", name, err)
+			}
+		}
+		for _, recipient := range schedule.Recipients {
+			if _, err := mail.ParseAddress(recipient); err != nil {
+				return fmt.Errorf("digest schedule %s: invalid recipient %q: %w", name, recipient, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateWebhooks checks the URL and event types of each webhook endpoint
+func validateWebhooks(webhooks *WebhooksConfig) error {
+	if webhooks.MaxAttempts < 0 || webhooks.RetryDelay < 0 || webhooks.Timeout < 0 {
+		return fmt.Errorf("webhooks max_attempts, retry_delay and timeout must not be negative")
+	}
+
+	for _, endpoint := range webhooks.Endpoints {
+		parsed, err := url.Parse(endpoint.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("webhook endpoint %q: url must be an absolute http or https URL", endpoint.URL)
+		}
+		for _, event := range endpoint.Events {
+			if !models.IsWebhookEvent(event) {
+				return fmt.Errorf("webhook endpoint %s: unknown event %q, must be one of %s", endpoint.URL, event, strings.Join(models.WebhookEvents, ", "))
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateBroker checks the provider, credentials and order settings of an enabled broker
+func validateBroker(broker *BrokerConfig) error {
+	if broker.SyncInterval < 0 || broker.Alpaca.Timeout < 0 {
+		return fmt.Errorf("broker sync_interval and alpaca timeout must not be negative")
+	}
+
+	switch broker.TimeInForce {
+	case "", "day", "gtc":
+	default:
+		return fmt.Errorf("invalid broker time_in_force %q: must be day or gtc", broker.TimeInForce)
+	}
+
+	if !broker.Enabled {
+		return nil
+	}
+
+	switch broker.Provider {
+	case "", "alpaca":
+		if broker.Alpaca.APIKey == "" || broker.Alpaca.APISecret == "" {
+			return fmt.Errorf("broker alpaca api_key and api_secret are required when the broker is enabled")
+		}
+	default:
+		return fmt.Errorf("unsupported broker provider: %s", broker.Provider)
+	}
+
+	return nil
+}
+
+// validateSRBreaks checks the global and per-symbol S/R break alert thresholds
+func validateSRBreaks(sr *SRBreaksConfig) error {
+	if sr.MinStrength < 0 || sr.MinStrength > 100 || sr.BreakPercent < 0 || sr.VolumeRatio < 0 || sr.LookbackBars < 0 {
+		return fmt.Errorf("sr_breaks requires non-negative thresholds and a min_strength of at most 100")
+	}
+	for symbol, override := range sr.Symbols {
+		if override.MinStrength < 0 || override.MinStrength > 100 || override.BreakPercent < 0 || override.VolumeRatio < 0 {
+			return fmt.Errorf("sr_breaks.symbols.%s requires non-negative thresholds and a min_strength of at most 100", symbol)
+		}
+	}
 	return nil
 }
 
+// validateSRRecalculation checks the S/R recalculation intervals, timeframe and notified strength
+func validateSRRecalculation(sr *SRRecalculationConfig) error {
+	if sr.Interval < 0 || sr.MinStrength < 0 || sr.MinStrength > 100 {
+		return fmt.Errorf("sr_recalculation requires a non-negative interval and a min_strength between 0 and 100")
+	}
+	if _, err := models.ParseTimeframe(sr.Timeframe); err != nil {
+		return fmt.Errorf("sr_recalculation.timeframe: %w", err)
+	}
+	for symbol, interval := range sr.Intervals {
+		if interval < time.Minute {
+			return fmt.Errorf("sr_recalculation.intervals.%s must be at least 1m", symbol)
+		}
+	}
+	return nil
+}
+
+// validateMarketHours checks the exchange, collection schedule, session times and holiday overrides
+func validateMarketHours(mh *MarketHoursConfig) error {
+	switch mh.Exchange {
+	case "", "NYSE", "NASDAQ":
+	default:
+		return fmt.Errorf("unsupported market_hours.exchange: %s", mh.Exchange)
+	}
+
+	switch mh.CollectSessions {
+	case "", models.CollectSessionsExtended, models.CollectSessionsRegular, models.CollectSessionsAlways:
+	default:
+		return fmt.Errorf("market_hours.collect_sessions must be extended, regular or always")
+	}
+
+	for name, value := range map[string]string{"pre_market_open": mh.PreMarketOpen, "post_market_close": mh.PostMarketClose} {
+		if value == "" {
+			continue
+		}
+		if _, err := time.Parse("15:04", value); err != nil {
+			return fmt.Errorf("market_hours.%s must be HH:MM: %w", name, err)
+		}
+	}
+
+	for _, day := range mh.ExtraHolidays {
+		if _, err := time.Parse("2006-01-02", day); err != nil {
+			return fmt.Errorf("market_hours.extra_holidays: invalid date %q", day)
+		}
+	}
+
+	return nil
+}
+
+// validatePatternScan checks a detector's configured bar interval, lookback and sensitivity
+func validatePatternScan(name string, barInterval models.Timeframe, lookbackDays int, sensitivity float64) error {
+	if err := models.ValidatePatternScan(barInterval, lookbackDays, sensitivity); err != nil {
+		return fmt.Errorf("pattern_detection.%s.%w", name, err)
+	}
+	return nil
+}
+
+// GetShutdownTimeout returns the graceful shutdown timeout, defaulting to 30 seconds
+func (c *Config) GetShutdownTimeout() time.Duration {
+	if c.Server.ShutdownTimeout <= 0 {
+		return 30 * time.Second
+	}
+	return c.Server.ShutdownTimeout
+}
+
+// GetRequestTimeout returns the API request timeout, defaulting to 30 seconds
+func (c *Config) GetRequestTimeout() time.Duration {
+	if c.Server.RequestTimeout <= 0 {
+		return 30 * time.Second
+	}
+	return c.Server.RequestTimeout
+}
+
 // GetAddress returns the server address in host:port format
 func (c *Config) GetAddress() string {
 	return fmt.Sprintf("%s:%d", c.Server.Host, c.Server.Port)
@@ -4,9 +4,29 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"reflect"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// EnvPrefix starts the environment variable overriding each config setting. The rest of the name is the
+// setting's YAML path in upper case joined by underscores, e.g. MW_SERVER_PORT for server.port.
+const EnvPrefix = "MW_"
+
+// envAliases are shorter or conventional names for common settings, applied before the MW_ names
+var envAliases = map[string]string{
+	"POLYGON_API_KEY":    "MW_POLYGON_API_KEY",
+	"TELEGRAM_BOT_TOKEN": "MW_TELEGRAM_BOT_TOKEN",
+	"MW_DB_DRIVER":       "MW_DATABASE_DRIVER",
+	"MW_DB_PATH":         "MW_DATABASE_PATH",
+	"MW_DB_DSN":          "MW_DATABASE_DSN",
+	"MW_SMTP_HOST":       "MW_EMAIL_SMTP_HOST",
+	"MW_SMTP_PORT":       "MW_EMAIL_SMTP_PORT",
+	"MW_SMTP_USERNAME":   "MW_EMAIL_USERNAME",
+	"MW_SMTP_PASSWORD":   "MW_EMAIL_PASSWORD",
+}
+
 // LoadEnvFile loads environment variables from a .env file
 func LoadEnvFile(filename string) error {
 	file, err := os.Open(filename)
@@ -46,3 +66,122 @@ func LoadEnvFile(filename string) error {
 
 	return nil
 }
+
+// applyEnvOverrides sets every config field that has an environment variable, layered over the YAML file.
+// Lists are comma separated; durations use Go syntax (e.g. 5m) and times RFC 3339 or YYYY-MM-DD.
+func applyEnvOverrides(cfg *Config) error {
+	values := make(map[string]string)
+	for alias, name := range envAliases {
+		if value, ok := os.LookupEnv(alias); ok {
+			values[name] = value
+		}
+	}
+	for _, entry := range os.Environ() {
+		if name, value, ok := strings.Cut(entry, "="); ok && strings.HasPrefix(name, EnvPrefix) {
+			values[name] = value
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	return overrideFields(reflect.ValueOf(cfg).Elem(), strings.TrimSuffix(EnvPrefix, "_"), values)
+}
+
+// overrideFields walks a config struct, setting the fields named in values
+func overrideFields(v reflect.Value, prefix string, values map[string]string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+		if tag == "" || tag == "-" || !t.Field(i).IsExported() {
+			continue
+		}
+		name := prefix + "_" + strings.ToUpper(tag)
+		field := v.Field(i)
+
+		// Nested sections, including pattern settings that hold their defaults behind a pointer
+		if field.Kind() == reflect.Ptr && !field.IsNil() && field.Elem().Kind() == reflect.Struct {
+			field = field.Elem()
+		}
+		if field.Kind() == reflect.Struct && field.Type() != reflect.TypeOf(time.Time{}) {
+			if err := overrideFields(field, name, values); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, ok := values[name]
+		if !ok {
+			continue
+		}
+		if err := setField(field, value); err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// setField parses an environment variable value into a config field
+func setField(field reflect.Value, value string) error {
+	switch field.Interface().(type) {
+	case time.Duration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	case time.Time:
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			if t, err = time.Parse("2006-01-02", value); err != nil {
+				return fmt.Errorf("expected RFC 3339 or YYYY-MM-DD")
+			}
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		var parts []string
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		slice := reflect.MakeSlice(field.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if slice.Index(i).Kind() == reflect.Struct {
+				return fmt.Errorf("lists of sections can only be set in the config file")
+			}
+			if err := setField(slice.Index(i), part); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+	default:
+		return fmt.Errorf("unsupported setting type %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestApplyEnvOverrides tests environment variables override the settings loaded from the YAML file
+func TestApplyEnvOverrides(t *testing.T) {
+	// yamlConfig is the config as loaded from the file, before overrides
+	yamlConfig := func() *Config {
+		cfg := &Config{}
+		cfg.Server.Port = 8080
+		cfg.Server.ReadTimeout = 10 * time.Second
+		cfg.Database.Path = "data/market.db"
+		cfg.Email.Password = "from-yaml"
+		cfg.Collection.DefaultWatchedSymbols = []string{"AAPL"}
+		cfg.PatternDetection.HeadShoulders = models.DefaultHeadShouldersConfig()
+		return cfg
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr bool
+		check   func(cfg *Config) bool
+	}{
+		{
+			name:  "no overrides",
+			check: func(cfg *Config) bool { return reflect.DeepEqual(cfg, yamlConfig()) },
+		},
+		{
+			name: "nested section",
+			env:  map[string]string{"MW_SERVER_PORT": "9090", "MW_MARKET_DATA_YAHOO_BASE_URL": "http://yahoo.test"},
+			check: func(cfg *Config) bool {
+				return cfg.Server.Port == 9090 && cfg.MarketData.Yahoo.BaseURL == "http://yahoo.test"
+			},
+		},
+		{
+			name:  "database path alias",
+			env:   map[string]string{"MW_DB_PATH": "/tmp/alias.db"},
+			check: func(cfg *Config) bool { return cfg.Database.Path == "/tmp/alias.db" },
+		},
+		{
+			name:  "SMTP password alias",
+			env:   map[string]string{"MW_SMTP_PASSWORD": "secret"},
+			check: func(cfg *Config) bool { return cfg.Email.Password == "secret" },
+		},
+		{
+			name: "canonical name wins over alias",
+			env:  map[string]string{"MW_DB_PATH": "/tmp/alias.db", "MW_DATABASE_PATH": "/tmp/canonical.db", "MW_SMTP_PASSWORD": "alias", "MW_EMAIL_PASSWORD": "canonical"},
+			check: func(cfg *Config) bool {
+				return cfg.Database.Path == "/tmp/canonical.db" && cfg.Email.Password == "canonical"
+			},
+		},
+		{
+			name:  "duration",
+			env:   map[string]string{"MW_SERVER_READ_TIMEOUT": "1m30s"},
+			check: func(cfg *Config) bool { return cfg.Server.ReadTimeout == 90*time.Second },
+		},
+		{
+			name: "slice",
+			env:  map[string]string{"MW_COLLECTION_DEFAULT_WATCHED_SYMBOLS": "MSFT, NVDA,,TSLA "},
+			check: func(cfg *Config) bool {
+				return reflect.DeepEqual(cfg.Collection.DefaultWatchedSymbols, []string{"MSFT", "NVDA", "TSLA"})
+			},
+		},
+		{
+			name:  "bool",
+			env:   map[string]string{"MW_EMAIL_ENABLED": "true", "MW_COLLECTION_REGULAR_HOURS_ONLY": "1"},
+			check: func(cfg *Config) bool { return cfg.Email.Enabled && cfg.Collection.RegularHoursOnly },
+		},
+		{
+			name:  "float",
+			env:   map[string]string{"MW_COLLECTION_OPTIONS_UNUSUAL_VOLUME_RATIO": "2.5"},
+			check: func(cfg *Config) bool { return cfg.Collection.Options.UnusualVolumeRatio == 2.5 },
+		},
+		{
+			name:  "pointer section",
+			env:   map[string]string{"MW_PATTERN_DETECTION_HEAD_SHOULDERS_SENSITIVITY": "1.5"},
+			check: func(cfg *Config) bool { return cfg.PatternDetection.HeadShoulders.Sensitivity == 1.5 },
+		},
+		{
+			name:  "nil pointer section",
+			env:   map[string]string{"MW_PATTERN_DETECTION_TRIANGLE_SENSITIVITY": "1.5"},
+			check: func(cfg *Config) bool { return cfg.PatternDetection.Triangle == nil },
+		},
+		{
+			name:    "invalid int",
+			env:     map[string]string{"MW_SERVER_PORT": "eighty"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid duration",
+			env:     map[string]string{"MW_SERVER_READ_TIMEOUT": "10"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid bool",
+			env:     map[string]string{"MW_EMAIL_ENABLED": "sometimes"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid alias value",
+			env:     map[string]string{"MW_SMTP_PORT": "smtp"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			cfg := yamlConfig()
+			err := applyEnvOverrides(cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got none with %+v", cfg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyEnvOverrides failed: %v", err)
+			}
+			if !tt.check(cfg) {
+				t.Errorf("unexpected config %+v", cfg)
+			}
+		})
+	}
+}
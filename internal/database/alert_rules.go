@@ -0,0 +1,404 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// CreateAlertRuleTables creates the alert rule and trigger history tables
+func (db *DB) CreateAlertRuleTables() error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS alert_rules (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			symbol TEXT NOT NULL DEFAULT '',
+			conditions TEXT NOT NULL DEFAULT '[]',
+			logic TEXT NOT NULL DEFAULT 'AND' CHECK (logic IN ('AND', 'OR')),
+			is_active BOOLEAN DEFAULT TRUE,
+			notify_email BOOLEAN DEFAULT TRUE,
+			cooldown_minutes INTEGER NOT NULL DEFAULT 60,
+			last_triggered_at DATETIME,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS alert_rule_triggers (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			rule_id INTEGER NOT NULL,
+			rule_name TEXT NOT NULL,
+			symbol TEXT NOT NULL,
+			message TEXT NOT NULL,
+			field_values TEXT NOT NULL DEFAULT '{}',
+			email_sent BOOLEAN DEFAULT FALSE,
+			triggered_at DATETIME NOT NULL,
+			FOREIGN KEY (rule_id) REFERENCES alert_rules(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_alert_rules_active ON alert_rules(is_active)`,
+		`CREATE INDEX IF NOT EXISTS idx_alert_rule_triggers_rule ON alert_rule_triggers(rule_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_alert_rule_triggers_time ON alert_rule_triggers(triggered_at)`,
+	}
+
+	for _, query := range queries {
+		if _, err := db.conn.Exec(query); err != nil {
+			return fmt.Errorf("failed to create alert rule tables: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// InsertAlertRule inserts a new alert rule
+func (db *DB) InsertAlertRule(rule *models.AlertRule) error {
+	conditionsJSON, stepsJSON, err := marshalAlertLegs(rule)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	query := `
+		INSERT INTO alert_rules (
+			name, symbol, conditions, logic, is_active, notify_email,
+			cooldown_minutes, steps, last_triggered_at, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := db.conn.Exec(query,
+		rule.Name, rule.Symbol, conditionsJSON, rule.Logic, rule.IsActive, rule.NotifyEmail,
+		rule.CooldownMinutes, stepsJSON, rule.LastTriggeredAt, rule.CreatedAt, rule.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert alert rule: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	rule.ID = id
+	return nil
+}
+
+const alertRuleColumns = `id, name, symbol, conditions, logic, is_active, notify_email,
+	cooldown_minutes, steps, last_triggered_at, created_at, updated_at`
+
+// GetAlertRules retrieves alert rules, optionally only the active ones
+func (db *DB) GetAlertRules(activeOnly bool) ([]*models.AlertRule, error) {
+	query := "SELECT " + alertRuleColumns + " FROM alert_rules"
+	args := []interface{}{}
+	if activeOnly {
+		query += " WHERE is_active = ?"
+		args = append(args, true)
+	}
+	query += " ORDER BY id ASC"
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alert rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make([]*models.AlertRule, 0)
+	for rows.Next() {
+		rule, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alert rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// GetAlertRuleByID retrieves a specific alert rule by ID
+func (db *DB) GetAlertRuleByID(id int64) (*models.AlertRule, error) {
+	row := db.conn.QueryRow("SELECT "+alertRuleColumns+" FROM alert_rules WHERE id = ?", id)
+
+	rule, err := scanAlertRule(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return rule, err
+}
+
+// UpdateAlertRule updates an existing alert rule
+func (db *DB) UpdateAlertRule(rule *models.AlertRule) error {
+	conditionsJSON, stepsJSON, err := marshalAlertLegs(rule)
+	if err != nil {
+		return err
+	}
+
+	rule.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE alert_rules SET
+			name = ?, symbol = ?, conditions = ?, logic = ?, is_active = ?, notify_email = ?,
+			cooldown_minutes = ?, steps = ?, last_triggered_at = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	_, err = db.conn.Exec(query,
+		rule.Name, rule.Symbol, conditionsJSON, rule.Logic, rule.IsActive, rule.NotifyEmail,
+		rule.CooldownMinutes, stepsJSON, rule.LastTriggeredAt, rule.UpdatedAt, rule.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update alert rule: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteAlertRule deletes an alert rule, its trigger history and its sequences in progress
+func (db *DB) DeleteAlertRule(id int64) error {
+	if _, err := db.conn.Exec("DELETE FROM alert_rule_triggers WHERE rule_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete alert rule triggers: %w", err)
+	}
+	if err := db.DeleteAlertRuleStates(id); err != nil {
+		return err
+	}
+
+	result, err := db.conn.Exec("DELETE FROM alert_rules WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete alert rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("alert rule %w", ErrNotFound)
+	}
+
+	return nil
+}
+
+// InsertAlertTrigger records a firing of an alert rule
+func (db *DB) InsertAlertTrigger(trigger *models.AlertTrigger) error {
+	valuesJSON, err := json.Marshal(trigger.Values)
+	if err != nil {
+		return fmt.Errorf("failed to marshal trigger values: %w", err)
+	}
+
+	query := `
+		INSERT INTO alert_rule_triggers (
+			rule_id, rule_name, symbol, message, field_values, email_sent, triggered_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := db.conn.Exec(query,
+		trigger.RuleID, trigger.RuleName, trigger.Symbol, trigger.Message,
+		string(valuesJSON), trigger.EmailSent, trigger.TriggeredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert alert trigger: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	trigger.ID = id
+	return nil
+}
+
+// GetAlertTriggers retrieves alert trigger history, newest first
+func (db *DB) GetAlertTriggers(filter *models.AlertTriggerFilter) ([]*models.AlertTrigger, error) {
+	query := `SELECT id, rule_id, rule_name, symbol, message, field_values, email_sent, triggered_at
+		FROM alert_rule_triggers WHERE 1=1`
+	args := []interface{}{}
+
+	limit := 100
+	if filter != nil {
+		if filter.RuleID > 0 {
+			query += " AND rule_id = ?"
+			args = append(args, filter.RuleID)
+		}
+		if filter.Symbol != "" {
+			query += " AND symbol = ?"
+			args = append(args, filter.Symbol)
+		}
+		if !filter.Since.IsZero() {
+			query += " AND triggered_at >= ?"
+			args = append(args, filter.Since)
+		}
+		if filter.Limit > 0 {
+			limit = filter.Limit
+		}
+	}
+
+	query += " ORDER BY triggered_at DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alert triggers: %w", err)
+	}
+	defer rows.Close()
+
+	triggers := make([]*models.AlertTrigger, 0)
+	for rows.Next() {
+		trigger := &models.AlertTrigger{}
+		var valuesJSON string
+
+		err := rows.Scan(
+			&trigger.ID, &trigger.RuleID, &trigger.RuleName, &trigger.Symbol,
+			&trigger.Message, &valuesJSON, &trigger.EmailSent, &trigger.TriggeredAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert trigger: %w", err)
+		}
+
+		if err := json.Unmarshal([]byte(valuesJSON), &trigger.Values); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal trigger values: %w", err)
+		}
+
+		triggers = append(triggers, trigger)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alert triggers: %w", err)
+	}
+
+	return triggers, nil
+}
+
+// GetLastAlertTriggerTime returns when a rule last fired on a symbol, or nil if it never has
+func (db *DB) GetLastAlertTriggerTime(ruleID int64, symbol string) (*time.Time, error) {
+	var triggeredAt time.Time
+	err := db.conn.QueryRow(`SELECT triggered_at FROM alert_rule_triggers
+		WHERE rule_id = ? AND symbol = ? ORDER BY triggered_at DESC LIMIT 1`, ruleID, symbol).Scan(&triggeredAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last alert trigger: %w", err)
+	}
+	return &triggeredAt, nil
+}
+
+// scanAlertRule scans an alert rule from a database row
+func scanAlertRule(row interface{ Scan(...interface{}) error }) (*models.AlertRule, error) {
+	rule := &models.AlertRule{}
+	var conditionsJSON, stepsJSON string
+	var lastTriggeredAt sql.NullTime
+
+	err := row.Scan(
+		&rule.ID, &rule.Name, &rule.Symbol, &conditionsJSON, &rule.Logic,
+		&rule.IsActive, &rule.NotifyEmail, &rule.CooldownMinutes, &stepsJSON, &lastTriggeredAt,
+		&rule.CreatedAt, &rule.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan alert rule: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(conditionsJSON), &rule.Conditions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal alert conditions: %w", err)
+	}
+	if err := json.Unmarshal([]byte(stepsJSON), &rule.Then); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal alert rule legs: %w", err)
+	}
+	if len(rule.Then) == 0 {
+		rule.Then = nil
+	}
+
+	if lastTriggeredAt.Valid {
+		rule.LastTriggeredAt = &lastTriggeredAt.Time
+	}
+
+	return rule, nil
+}
+
+// marshalAlertLegs encodes a rule's conditions and sequenced legs for storage
+func marshalAlertLegs(rule *models.AlertRule) (string, string, error) {
+	conditionsJSON, err := json.Marshal(rule.Conditions)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal alert conditions: %w", err)
+	}
+	stepsJSON, err := json.Marshal(append([]models.AlertStep{}, rule.Then...))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal alert rule legs: %w", err)
+	}
+	return string(conditionsJSON), string(stepsJSON), nil
+}
+
+// UpsertAlertRuleState stores the progress of a sequenced rule on a symbol
+func (db *DB) UpsertAlertRuleState(state *models.AlertRuleState) error {
+	valuesJSON, err := json.Marshal(state.Values)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert rule state values: %w", err)
+	}
+
+	columns := []string{"rule_id", "symbol", "step", "started_at", "step_at", "field_values"}
+	query := upsertQuery("alert_rule_states", columns, columns[:2], columns[2:], 1)
+	if _, err := db.conn.Exec(query, state.RuleID, state.Symbol, state.Step, state.StartedAt, state.StepAt, string(valuesJSON)); err != nil {
+		return fmt.Errorf("failed to store state of alert rule %d on %s: %w", state.RuleID, state.Symbol, err)
+	}
+	return nil
+}
+
+// GetAlertRuleStates retrieves the sequences in progress of a rule, or of every rule when ruleID is 0
+func (db *DB) GetAlertRuleStates(ruleID int64) ([]*models.AlertRuleState, error) {
+	query := "SELECT rule_id, symbol, step, started_at, step_at, field_values FROM alert_rule_states"
+	args := []interface{}{}
+	if ruleID > 0 {
+		query += " WHERE rule_id = ?"
+		args = append(args, ruleID)
+	}
+
+	rows, err := db.conn.Query(query+" ORDER BY rule_id, symbol", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alert rule states: %w", err)
+	}
+	defer rows.Close()
+
+	states := make([]*models.AlertRuleState, 0)
+	for rows.Next() {
+		state := &models.AlertRuleState{}
+		var valuesJSON string
+		if err := rows.Scan(&state.RuleID, &state.Symbol, &state.Step, &state.StartedAt, &state.StepAt, &valuesJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan alert rule state: %w", err)
+		}
+		if err := json.Unmarshal([]byte(valuesJSON), &state.Values); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal alert rule state values: %w", err)
+		}
+		states = append(states, state)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alert rule states: %w", err)
+	}
+
+	return states, nil
+}
+
+// DeleteAlertRuleState ends a rule's sequence on a symbol
+func (db *DB) DeleteAlertRuleState(ruleID int64, symbol string) error {
+	if _, err := db.conn.Exec("DELETE FROM alert_rule_states WHERE rule_id = ? AND symbol = ?", ruleID, symbol); err != nil {
+		return fmt.Errorf("failed to delete state of alert rule %d on %s: %w", ruleID, symbol, err)
+	}
+	return nil
+}
+
+// DeleteAlertRuleStates ends a rule's sequences on every symbol
+func (db *DB) DeleteAlertRuleStates(ruleID int64) error {
+	if _, err := db.conn.Exec("DELETE FROM alert_rule_states WHERE rule_id = ?", ruleID); err != nil {
+		return fmt.Errorf("failed to delete states of alert rule %d: %w", ruleID, err)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// InsertMarketAnomaly stores an anomaly, reporting false when the bar was already flagged for the same type
+func (db *DB) InsertMarketAnomaly(anomaly *models.MarketAnomaly) (bool, error) {
+	if anomaly.DetectedAt.IsZero() {
+		anomaly.DetectedAt = time.Now()
+	}
+
+	result, err := db.conn.Exec(`
+		INSERT OR IGNORE INTO market_anomalies (symbol, type, severity, bar_time, value, threshold, price, message, detected_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, anomaly.Symbol, anomaly.Type, anomaly.Severity, anomaly.BarTime, anomaly.Value, anomaly.Threshold, anomaly.Price,
+		anomaly.Message, anomaly.DetectedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to insert market anomaly: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// GetMarketAnomalies retrieves anomalies, most recent bar first
+func (db *DB) GetMarketAnomalies(filter *models.AnomalyFilter) ([]*models.MarketAnomaly, error) {
+	query := `SELECT id, symbol, type, severity, bar_time, value, threshold, price, message, detected_at
+		FROM market_anomalies WHERE 1=1`
+	args := []interface{}{}
+
+	if filter != nil {
+		if filter.Symbol != "" {
+			query += " AND symbol = ?"
+			args = append(args, filter.Symbol)
+		}
+		if filter.Type != "" {
+			query += " AND type = ?"
+			args = append(args, filter.Type)
+		}
+		if !filter.Since.IsZero() {
+			query += " AND bar_time >= ?"
+			args = append(args, filter.Since)
+		}
+	}
+
+	query += " ORDER BY bar_time DESC, id DESC"
+
+	if filter != nil && filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query market anomalies: %w", err)
+	}
+	defer rows.Close()
+
+	anomalies := make([]*models.MarketAnomaly, 0)
+	for rows.Next() {
+		anomaly := &models.MarketAnomaly{}
+		err := rows.Scan(
+			&anomaly.ID, &anomaly.Symbol, &anomaly.Type, &anomaly.Severity, &anomaly.BarTime, &anomaly.Value,
+			&anomaly.Threshold, &anomaly.Price, &anomaly.Message, &anomaly.DetectedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan market anomaly: %w", err)
+		}
+		anomalies = append(anomalies, anomaly)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating market anomalies: %w", err)
+	}
+
+	return anomalies, nil
+}
+
+// CountMarketAnomalies counts a symbol's anomalies on bars at or after since
+func (db *DB) CountMarketAnomalies(symbol string, since time.Time) (int, error) {
+	var count int
+	err := db.conn.QueryRow("SELECT COUNT(*) FROM market_anomalies WHERE symbol = ? AND bar_time >= ?", symbol, since).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count market anomalies: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteMarketAnomaliesBefore deletes anomalies on bars before the cutoff
+func (db *DB) DeleteMarketAnomaliesBefore(cutoff time.Time) (int64, error) {
+	result, err := db.conn.Exec("DELETE FROM market_anomalies WHERE bar_time < ?", cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old market anomalies: %w", err)
+	}
+	return result.RowsAffected()
+}
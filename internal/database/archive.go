@@ -0,0 +1,168 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// patternTables maps each pattern family, as named by the pattern_family of the pattern list, to its table
+var patternTables = map[string]string{
+	models.PatternFamilyHeadShoulders: "head_shoulders_patterns",
+	models.PatternFallingWedge:        "falling_wedge_patterns",
+	"triangle":                        "triangle_patterns",
+	"flag":                            "flag_patterns",
+}
+
+// patternFamilies lists the pattern families in a fixed order
+var patternFamilies = []string{models.PatternFamilyHeadShoulders, models.PatternFallingWedge, "triangle", "flag"}
+
+// SetTradingSetupArchived archives or restores a setup. Archived setups are left out of setup lists.
+func (db *Database) SetTradingSetupArchived(id int64, archived bool) error {
+	return db.setArchived("trading_setups", "trading setup", id, archived)
+}
+
+// SetPatternArchived archives or restores a pattern of a family. Archived patterns are left out of pattern lists.
+func (db *DB) SetPatternArchived(family string, id int64, archived bool) error {
+	table, ok := patternTables[family]
+	if !ok {
+		return fmt.Errorf("unknown pattern family %q", family)
+	}
+	return db.setArchived(table, family+" pattern", id, archived)
+}
+
+// setArchived sets the archived flag of a row, keeping the time it was first archived
+func (db *DB) setArchived(table, name string, id int64, archived bool) error {
+	var archivedAt interface{}
+	if archived {
+		archivedAt = time.Now()
+	}
+
+	result, err := db.conn.Exec(
+		`UPDATE `+table+` SET archived = ?, archived_at = CASE WHEN archived = ? THEN archived_at ELSE ? END WHERE id = ?`,
+		archived, archived, archivedAt, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to archive %s: %w", name, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s %w", name, ErrNotFound)
+	}
+	return nil
+}
+
+// ArchiveOldSetups archives the setups created more than days ago, returning how many were archived
+func (db *Database) ArchiveOldSetups(days int) (int64, error) {
+	cutoff := time.Now().AddDate(0, 0, -days)
+	result, err := db.conn.Exec(
+		`UPDATE trading_setups SET archived = TRUE, archived_at = ? WHERE archived = FALSE AND created_at < ?`,
+		time.Now(), cutoff,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive old trading setups: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// ArchiveOldPatterns archives the patterns of every family detected more than days ago, returning how many
+// were archived
+func (db *DB) ArchiveOldPatterns(days int) (int64, error) {
+	cutoff := time.Now().AddDate(0, 0, -days)
+	now := time.Now()
+
+	var archived int64
+	err := db.WithTx(func(tx *DB) error {
+		for _, family := range patternFamilies {
+			result, err := tx.conn.Exec(
+				`UPDATE `+patternTables[family]+` SET archived = TRUE, archived_at = ? WHERE archived = FALSE AND detected_at < ?`,
+				now, cutoff,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to archive old %s patterns: %w", family, err)
+			}
+			count, _ := result.RowsAffected()
+			archived += count
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return archived, nil
+}
+
+// PurgeArchivedSetups deletes the setups archived before the cutoff with their checklists, alerts and stop
+// adjustments, returning how many setups were deleted
+func (db *Database) PurgeArchivedSetups(cutoff time.Time) (int64, error) {
+	const archivedSetups = `(SELECT id FROM trading_setups WHERE archived = TRUE AND archived_at < ?)`
+
+	var purged int64
+	err := db.WithTx(func(tx *DB) error {
+		for _, table := range []string{"setup_checklists", "setup_alerts", "setup_stop_adjustments"} {
+			if _, err := tx.conn.Exec(`DELETE FROM `+table+` WHERE setup_id IN `+archivedSetups, cutoff); err != nil {
+				return fmt.Errorf("failed to purge archived %s: %w", table, err)
+			}
+		}
+
+		result, err := tx.conn.Exec(`DELETE FROM trading_setups WHERE archived = TRUE AND archived_at < ?`, cutoff)
+		if err != nil {
+			return fmt.Errorf("failed to purge archived trading setups: %w", err)
+		}
+		purged, _ = result.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+// PurgeArchivedPatterns deletes the patterns of every family archived before the cutoff with their thesis
+// components, alerts, edits and links, returning how many patterns were deleted
+func (db *DB) PurgeArchivedPatterns(cutoff time.Time) (int64, error) {
+	var purged int64
+	err := db.WithTx(func(tx *DB) error {
+		for _, family := range patternFamilies {
+			table := patternTables[family]
+			archived := `(SELECT id FROM ` + table + ` WHERE archived = TRUE AND archived_at < ?)`
+
+			if family == models.PatternFamilyHeadShoulders {
+				for _, dependent := range []string{"thesis_components", "pattern_alerts"} {
+					if _, err := tx.conn.Exec(`DELETE FROM `+dependent+` WHERE pattern_id IN `+archived, cutoff); err != nil {
+						return fmt.Errorf("failed to purge archived %s: %w", dependent, err)
+					}
+				}
+			}
+			if _, err := tx.conn.Exec(
+				`DELETE FROM pattern_edits WHERE pattern_type = ? AND pattern_id IN `+archived, family, cutoff,
+			); err != nil {
+				return fmt.Errorf("failed to purge archived pattern edits: %w", err)
+			}
+			if _, err := tx.conn.Exec(
+				`DELETE FROM pattern_links WHERE (family = ? AND pattern_id IN `+archived+`)
+				 OR (linked_family = ? AND linked_pattern_id IN `+archived+`)`,
+				family, cutoff, family, cutoff,
+			); err != nil {
+				return fmt.Errorf("failed to purge archived pattern links: %w", err)
+			}
+
+			result, err := tx.conn.Exec(`DELETE FROM `+table+` WHERE archived = TRUE AND archived_at < ?`, cutoff)
+			if err != nil {
+				return fmt.Errorf("failed to purge archived %s patterns: %w", family, err)
+			}
+			count, _ := result.RowsAffected()
+			purged += count
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
@@ -0,0 +1,97 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestArchiveAndPurge tests that archived setups and patterns leave the lists but stay readable until purged
+func TestArchiveAndPurge(t *testing.T) {
+	db := newTestDB(t)
+
+	now := time.Now()
+	var setups []*models.TradingSetup
+	for _, symbol := range []string{"AAA", "BBB"} {
+		setup := &models.TradingSetup{
+			Symbol: symbol, SetupType: "breakout", Direction: "bullish", Confidence: "medium", Status: "completed",
+			QualityScore: 70, DetectedAt: now, ExpiresAt: now.Add(time.Hour), CurrentPrice: 10, EntryPrice: 10, StopLoss: 9,
+			Checklist: &models.SetupChecklist{TotalItems: 20},
+		}
+		if err := db.InsertTradingSetupWithChecklist(setup); err != nil {
+			t.Fatalf("InsertTradingSetupWithChecklist failed: %v", err)
+		}
+		setups = append(setups, setup)
+	}
+	triangle := &models.TrianglePattern{Symbol: "AAA", PatternType: "ascending_triangle", CurrentPhase: models.PhaseFormation, DetectedAt: now, LastUpdated: now}
+	if err := db.InsertTrianglePattern(triangle); err != nil {
+		t.Fatalf("InsertTrianglePattern failed: %v", err)
+	}
+
+	if err := db.SetTradingSetupArchived(setups[0].ID, true); err != nil {
+		t.Fatalf("SetTradingSetupArchived failed: %v", err)
+	}
+	if err := db.SetTradingSetupArchived(999, true); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected an unknown setup to be not found, got %v", err)
+	}
+
+	if count, _ := db.CountTradingSetups(&models.SetupFilter{}); count != 1 {
+		t.Errorf("expected the archived setup to be left out, got %d setups", count)
+	}
+	if count, _ := db.CountTradingSetups(&models.SetupFilter{IncludeArchived: true}); count != 2 {
+		t.Errorf("expected both setups when including archived, got %d", count)
+	}
+	archived, err := db.GetTradingSetupByID(setups[0].ID)
+	if err != nil || archived == nil || !archived.Archived || archived.ArchivedAt == nil {
+		t.Fatalf("expected the archived setup to stay readable by ID, got %+v, %v", archived, err)
+	}
+
+	if err := db.SetPatternArchived("triangle", triangle.ID, true); err != nil {
+		t.Fatalf("SetPatternArchived failed: %v", err)
+	}
+	if err := db.SetPatternArchived("unknown", triangle.ID, true); err == nil {
+		t.Error("expected an unknown pattern family to be rejected")
+	}
+	if patterns, _ := db.GetTrianglePatterns(nil); len(patterns) != 0 {
+		t.Errorf("expected the archived triangle to be left out, got %d", len(patterns))
+	}
+	if counts, _ := db.GetActivePatternPhaseCounts(); counts[models.PhaseFormation] != 0 {
+		t.Errorf("expected archived patterns to be left out of the phase counts, got %v", counts)
+	}
+
+	// Restoring clears the archive time
+	if err := db.SetPatternArchived("triangle", triangle.ID, false); err != nil {
+		t.Fatalf("SetPatternArchived failed: %v", err)
+	}
+	restored, err := db.GetTrianglePatternByID(triangle.ID)
+	if err != nil || restored == nil || restored.Archived || restored.ArchivedAt != nil {
+		t.Fatalf("expected the triangle to be restored, got %+v, %v", restored, err)
+	}
+
+	if count, err := db.ArchiveOldPatterns(0); err != nil || count != 1 {
+		t.Errorf("expected the triangle to be archived as old, got %d, %v", count, err)
+	}
+	if count, err := db.ArchiveOldSetups(0); err != nil || count != 1 {
+		t.Errorf("expected only the unarchived setup to be archived as old, got %d, %v", count, err)
+	}
+
+	// Nothing was archived before the cutoff yet
+	if count, err := db.PurgeArchivedSetups(now.Add(-time.Hour)); err != nil || count != 0 {
+		t.Errorf("expected no setups to be purged, got %d, %v", count, err)
+	}
+
+	if count, err := db.PurgeArchivedSetups(time.Now().Add(time.Minute)); err != nil || count != 2 {
+		t.Errorf("expected both archived setups to be purged, got %d, %v", count, err)
+	}
+	if checklist, err := db.GetSetupChecklist(setups[0].ID); checklist != nil || err != nil {
+		t.Errorf("expected the purged setup's checklist to be deleted, got %+v, %v", checklist, err)
+	}
+	if count, err := db.PurgeArchivedPatterns(time.Now().Add(time.Minute)); err != nil || count != 1 {
+		t.Errorf("expected the archived triangle to be purged, got %d, %v", count, err)
+	}
+	if pattern, _ := db.GetTrianglePatternByID(triangle.ID); pattern != nil {
+		t.Errorf("expected the purged triangle to be deleted, got %+v", pattern)
+	}
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// InsertAuditEntry stores an audit log entry
+func (db *DB) InsertAuditEntry(entry *models.AuditEntry) error {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	result, err := db.conn.Exec(`
+		INSERT INTO audit_log (timestamp, actor, method, path, route, query, payload, status_code, success, error, duration_ms)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, entry.Timestamp, entry.Actor, entry.Method, entry.Path, entry.Route, entry.Query, entry.Payload,
+		entry.StatusCode, entry.Success, entry.Error, entry.DurationMS,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit entry: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+	entry.ID = id
+	return nil
+}
+
+// GetAuditEntries retrieves audit log entries, newest first
+func (db *DB) GetAuditEntries(filter *models.AuditFilter) ([]*models.AuditEntry, error) {
+	where, args := auditWhere(filter)
+	query := `SELECT id, timestamp, actor, method, path, route, query, payload, status_code, success, error, duration_ms
+		FROM audit_log` + where + ` ORDER BY timestamp DESC, id DESC`
+	query, args = limitOffset(query, args, filter.Limit, filter.Offset)
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*models.AuditEntry, 0)
+	for rows.Next() {
+		entry := &models.AuditEntry{}
+		err := rows.Scan(
+			&entry.ID, &entry.Timestamp, &entry.Actor, &entry.Method, &entry.Path, &entry.Route, &entry.Query,
+			&entry.Payload, &entry.StatusCode, &entry.Success, &entry.Error, &entry.DurationMS,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit log: %w", err)
+	}
+
+	return entries, nil
+}
+
+// CountAuditEntries counts the audit log entries matching a filter, ignoring its limit and offset
+func (db *DB) CountAuditEntries(filter *models.AuditFilter) (int, error) {
+	where, args := auditWhere(filter)
+	return db.countRows("audit_log", where, args)
+}
+
+// auditWhere builds the WHERE clause for an AuditFilter
+func auditWhere(filter *models.AuditFilter) (string, []interface{}) {
+	where := " WHERE 1=1"
+	args := []interface{}{}
+
+	if filter.Actor != "" {
+		where += " AND actor = ?"
+		args = append(args, filter.Actor)
+	}
+	if filter.Method != "" {
+		where += " AND method = ?"
+		args = append(args, filter.Method)
+	}
+	if filter.Path != "" {
+		where += " AND path LIKE ?"
+		args = append(args, filter.Path+"%")
+	}
+	if filter.Success != nil {
+		where += " AND success = ?"
+		args = append(args, *filter.Success)
+	}
+	if !filter.From.IsZero() {
+		where += " AND timestamp >= ?"
+		args = append(args, filter.From)
+	}
+	if !filter.To.IsZero() {
+		where += " AND timestamp <= ?"
+		args = append(args, filter.To)
+	}
+
+	return where, args
+}
@@ -0,0 +1,120 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// batchInsertRows is the number of rows per multi-row INSERT; a chunk of price bars binds 800
+// parameters, below SQLite's historical limit of 999
+const batchInsertRows = 100
+
+// writeStats records batch insert throughput per table
+type writeStats struct {
+	mutex  sync.Mutex
+	tables map[string]*models.InsertStats
+}
+
+// record adds a committed batch to a table's throughput
+func (ws *writeStats) record(table string, rows int, elapsed time.Duration) {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	stats, ok := ws.tables[table]
+	if !ok {
+		stats = &models.InsertStats{}
+		ws.tables[table] = stats
+	}
+	millis := float64(elapsed.Microseconds()) / 1000
+	stats.Batches++
+	stats.Rows += int64(rows)
+	stats.TotalMillis += millis
+	if stats.TotalMillis > 0 {
+		stats.RowsPerSecond = float64(stats.Rows) / stats.TotalMillis * 1000
+	}
+	stats.LastBatchRows = rows
+	stats.LastBatchMillis = millis
+	stats.LastBatchAt = time.Now()
+}
+
+// InsertStats returns the batch insert throughput of each table written since the server started
+func (db *DB) InsertStats() map[string]*models.InsertStats {
+	ws := db.pool.writes
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	result := make(map[string]*models.InsertStats, len(ws.tables))
+	for table, stats := range ws.tables {
+		copied := *stats
+		result[table] = &copied
+	}
+	return result
+}
+
+// upsertBatch writes rows with multi-row INSERT ... ON CONFLICT DO UPDATE statements of up to batchInsertRows
+// rows in a single transaction, preparing one statement per chunk size. Rows must not repeat a key: Postgres
+// rejects a statement that updates the same row twice.
+func (db *DB) upsertBatch(table string, columns, key, update []string, rows [][]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	err := db.WithTx(func(tx *DB) error {
+		statements := make(map[int]*sql.Stmt)
+		defer func() {
+			for _, stmt := range statements {
+				stmt.Close()
+			}
+		}()
+
+		for offset := 0; offset < len(rows); offset += batchInsertRows {
+			chunk := rows[offset:min(offset+batchInsertRows, len(rows))]
+
+			stmt, ok := statements[len(chunk)]
+			if !ok {
+				var err error
+				stmt, err = tx.conn.Prepare(upsertQuery(table, columns, key, update, len(chunk)))
+				if err != nil {
+					return fmt.Errorf("failed to prepare %s batch insert: %w", table, err)
+				}
+				statements[len(chunk)] = stmt
+			}
+
+			args := make([]interface{}, 0, len(chunk)*len(columns))
+			for _, row := range chunk {
+				args = append(args, row...)
+			}
+			if _, err := stmt.Exec(args...); err != nil {
+				return fmt.Errorf("failed to execute %s batch insert: %w", table, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	db.pool.writes.record(table, len(rows), time.Since(start))
+	return nil
+}
+
+// upsertQuery builds a multi-row INSERT of n rows that updates the given columns of rows already stored
+func upsertQuery(table string, columns, key, update []string, n int) string {
+	row := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+	values := strings.TrimSuffix(strings.Repeat(row+", ", n), ", ")
+
+	sets := make([]string, len(update))
+	for i, col := range update {
+		sets[i] = fmt.Sprintf("%s = excluded.%s", col, col)
+	}
+
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s ON CONFLICT (%s) DO UPDATE SET %s",
+		table, strings.Join(columns, ", "), values, strings.Join(key, ", "), strings.Join(sets, ", "))
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestInsertPriceDataBatch tests chunked multi-row upserts, WAL mode and insert throughput stats
+func TestInsertPriceDataBatch(t *testing.T) {
+	db := newTestDB(t)
+
+	var journalMode string
+	if err := db.conn.QueryRow("PRAGMA journal_mode").Scan(&journalMode); err != nil || journalMode != "wal" {
+		t.Errorf("expected WAL journal mode, got %q (%v)", journalMode, err)
+	}
+
+	start := time.Date(2024, time.March, 4, 14, 30, 0, 0, time.UTC)
+	bar := func(i int, close float64) *models.PriceData {
+		return &models.PriceData{
+			Symbol:    "TEST",
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			Open:      close, High: close, Low: close, Close: close,
+			Volume:    1000,
+			CreatedAt: start,
+		}
+	}
+
+	// Two and a half chunks, with the last bar repeated
+	var bars []*models.PriceData
+	for i := 0; i < 250; i++ {
+		bars = append(bars, bar(i, 100))
+	}
+	bars = append(bars, bar(249, 101))
+	if err := db.InsertPriceDataBatch(bars); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+
+	first, err := db.GetPriceData(&models.PriceDataFilter{Symbol: "TEST", From: start, To: start.Add(time.Hour * 5)})
+	if err != nil || len(first) != 250 {
+		t.Fatalf("expected 250 bars, got %d (%v)", len(first), err)
+	}
+	if first[249].Close != 101 {
+		t.Errorf("expected the repeated bar's last close, got %v", first[249].Close)
+	}
+
+	// Restating a bar updates it in place
+	if err := db.InsertPriceDataBatch([]*models.PriceData{bar(0, 99)}); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+	updated, err := db.GetPriceData(&models.PriceDataFilter{Symbol: "TEST", From: start, To: start})
+	if err != nil || len(updated) != 1 || updated[0].Close != 99 || updated[0].ID != first[0].ID {
+		t.Errorf("expected bar %d updated to 99, got %+v (%v)", first[0].ID, updated, err)
+	}
+
+	stats := db.InsertStats()["price_data"]
+	if stats == nil || stats.Batches != 2 || stats.Rows != 251 || stats.LastBatchRows != 1 {
+		t.Errorf("unexpected insert stats %+v", stats)
+	}
+}
@@ -0,0 +1,218 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// SetStrategyAutoTrade turns placing broker orders for the triggered setups of a strategy's stocks on or off
+func (db *DB) SetStrategyAutoTrade(strategyID int, enabled bool) error {
+	var err error
+	if enabled {
+		_, err = db.conn.Exec("INSERT OR IGNORE INTO broker_strategies (strategy_id, enabled_at) VALUES (?, ?)", strategyID, time.Now())
+	} else {
+		_, err = db.conn.Exec("DELETE FROM broker_strategies WHERE strategy_id = ?", strategyID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set auto trading of strategy %d: %w", strategyID, err)
+	}
+	return nil
+}
+
+// GetAutoTradeStrategyIDs returns the strategies with auto trading on
+func (db *DB) GetAutoTradeStrategyIDs() ([]int, error) {
+	return db.queryStrategyIDs("SELECT strategy_id FROM broker_strategies ORDER BY strategy_id")
+}
+
+// GetAutoTradeStrategiesForSymbol returns the strategies with auto trading on that the symbol's stock belongs to
+func (db *DB) GetAutoTradeStrategiesForSymbol(symbol string) ([]int, error) {
+	return db.queryStrategyIDs(`
+		SELECT bs.strategy_id
+		FROM broker_strategies bs
+		INNER JOIN stock_strategies ss ON ss.strategy_id = bs.strategy_id
+		INNER JOIN stocks s ON s.id = ss.stock_id
+		WHERE s.symbol = ?
+		ORDER BY bs.strategy_id
+	`, symbol)
+}
+
+// queryStrategyIDs runs a query selecting strategy IDs
+func (db *DB) queryStrategyIDs(query string, args ...interface{}) ([]int, error) {
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query auto trading strategies: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan strategy ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating auto trading strategies: %w", err)
+	}
+
+	return ids, nil
+}
+
+// InsertBrokerOrder records a bracket order placed for a setup
+func (db *DB) InsertBrokerOrder(order *models.BrokerOrder) error {
+	now := time.Now()
+	order.CreatedAt = now
+	order.UpdatedAt = now
+
+	result, err := db.conn.Exec(`
+		INSERT INTO broker_orders (
+			setup_id, strategy_id, position_id, broker, broker_order_id, client_order_id, symbol, side, quantity,
+			entry_price, stop_loss, target_price, status, filled_price, filled_at, exit_price, exit_at, error,
+			created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, order.SetupID, order.StrategyID, order.PositionID, order.Broker, order.BrokerOrderID, order.ClientOrderID,
+		order.Symbol, order.Side, order.Quantity, order.EntryPrice, order.StopLoss, order.TargetPrice, order.Status,
+		order.FilledPrice, order.FilledAt, order.ExitPrice, order.ExitAt, order.Error, order.CreatedAt, order.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert broker order: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	order.ID = id
+	return nil
+}
+
+// UpdateBrokerOrder saves a broker order's status, fills and position
+func (db *DB) UpdateBrokerOrder(order *models.BrokerOrder) error {
+	order.UpdatedAt = time.Now()
+
+	_, err := db.conn.Exec(`
+		UPDATE broker_orders SET
+			position_id = ?, broker_order_id = ?, status = ?, filled_price = ?, filled_at = ?, exit_price = ?,
+			exit_at = ?, error = ?, updated_at = ?
+		WHERE id = ?
+	`, order.PositionID, order.BrokerOrderID, order.Status, order.FilledPrice, order.FilledAt, order.ExitPrice,
+		order.ExitAt, order.Error, order.UpdatedAt, order.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update broker order: %w", err)
+	}
+
+	return nil
+}
+
+const brokerOrderColumns = `id, setup_id, strategy_id, position_id, broker, broker_order_id, client_order_id, symbol,
+	side, quantity, entry_price, stop_loss, target_price, status, filled_price, filled_at, exit_price, exit_at, error,
+	created_at, updated_at`
+
+// GetBrokerOrders retrieves broker orders, newest first
+func (db *DB) GetBrokerOrders(filter *models.BrokerOrderFilter) ([]*models.BrokerOrder, error) {
+	query := "SELECT " + brokerOrderColumns + " FROM broker_orders WHERE 1=1"
+	args := []interface{}{}
+
+	if filter != nil {
+		if filter.Symbol != "" {
+			query += " AND symbol = ?"
+			args = append(args, filter.Symbol)
+		}
+		if filter.Status != "" {
+			query += " AND status = ?"
+			args = append(args, filter.Status)
+		}
+		if filter.Open {
+			query += " AND status IN (?, ?)"
+			args = append(args, models.BrokerOrderSubmitted, models.BrokerOrderFilled)
+		}
+	}
+
+	query += " ORDER BY created_at DESC, id DESC"
+
+	if filter != nil && filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query broker orders: %w", err)
+	}
+	defer rows.Close()
+
+	orders := make([]*models.BrokerOrder, 0)
+	for rows.Next() {
+		order, err := scanBrokerOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating broker orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+// GetBrokerOrderByID retrieves a broker order, or nil if there is none
+func (db *DB) GetBrokerOrderByID(id int64) (*models.BrokerOrder, error) {
+	return db.getBrokerOrder("id", id)
+}
+
+// GetBrokerOrderBySetupID retrieves the broker order placed for a setup, or nil if there is none
+func (db *DB) GetBrokerOrderBySetupID(setupID int64) (*models.BrokerOrder, error) {
+	return db.getBrokerOrder("setup_id", setupID)
+}
+
+// getBrokerOrder retrieves the broker order with the given value of a unique column
+func (db *DB) getBrokerOrder(column string, value int64) (*models.BrokerOrder, error) {
+	row := db.conn.QueryRow("SELECT "+brokerOrderColumns+" FROM broker_orders WHERE "+column+" = ?", value)
+
+	order, err := scanBrokerOrder(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return order, err
+}
+
+// scanBrokerOrder scans a broker order from a database row
+func scanBrokerOrder(row interface{ Scan(...interface{}) error }) (*models.BrokerOrder, error) {
+	order := &models.BrokerOrder{}
+	var positionID sql.NullInt64
+	var filledAt, exitAt sql.NullTime
+
+	err := row.Scan(
+		&order.ID, &order.SetupID, &order.StrategyID, &positionID, &order.Broker, &order.BrokerOrderID,
+		&order.ClientOrderID, &order.Symbol, &order.Side, &order.Quantity, &order.EntryPrice, &order.StopLoss,
+		&order.TargetPrice, &order.Status, &order.FilledPrice, &filledAt, &order.ExitPrice, &exitAt, &order.Error,
+		&order.CreatedAt, &order.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan broker order: %w", err)
+	}
+
+	if positionID.Valid {
+		order.PositionID = &positionID.Int64
+	}
+	if filledAt.Valid {
+		order.FilledAt = &filledAt.Time
+	}
+	if exitAt.Valid {
+		order.ExitAt = &exitAt.Time
+	}
+
+	return order, nil
+}
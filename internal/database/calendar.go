@@ -0,0 +1,153 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// CreateCalendarTables creates the calendar events table
+func (db *DB) CreateCalendarTables() error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS calendar_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			symbol TEXT NOT NULL DEFAULT '',
+			event_type TEXT NOT NULL,
+			title TEXT NOT NULL DEFAULT '',
+			event_date DATETIME NOT NULL,
+			event_time TEXT DEFAULT '',
+			importance INTEGER DEFAULT 0,
+			source TEXT NOT NULL DEFAULT 'manual',
+			details TEXT DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(symbol, event_type, event_date, title)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_calendar_events_symbol_date ON calendar_events(symbol, event_date)`,
+		`CREATE INDEX IF NOT EXISTS idx_calendar_events_date ON calendar_events(event_date)`,
+	}
+
+	for _, query := range queries {
+		if _, err := db.conn.Exec(query); err != nil {
+			return fmt.Errorf("failed to create calendar tables: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// UpsertCalendarEvent inserts a calendar event or refreshes an existing one for the same symbol, type, date and title
+func (db *DB) UpsertCalendarEvent(event *models.CalendarEvent) error {
+	now := time.Now()
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = now
+	}
+	event.UpdatedAt = now
+
+	query := `
+		INSERT OR REPLACE INTO calendar_events (
+			symbol, event_type, title, event_date, event_time, importance, source, details, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := db.conn.Exec(query,
+		event.Symbol, event.EventType, event.Title, event.EventDate, event.EventTime,
+		event.Importance, event.Source, event.Details, event.CreatedAt, event.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert calendar event: %w", err)
+	}
+
+	return nil
+}
+
+// GetCalendarEvents retrieves calendar events ordered by date
+func (db *DB) GetCalendarEvents(filter *models.CalendarEventFilter) ([]*models.CalendarEvent, error) {
+	query := `SELECT id, symbol, event_type, title, event_date, event_time, importance, source, details, created_at, updated_at
+		FROM calendar_events WHERE 1=1`
+	args := []interface{}{}
+
+	if filter != nil {
+		if filter.Symbol != "" {
+			if filter.IncludeMarket {
+				query += " AND (symbol = ? OR symbol = '')"
+			} else {
+				query += " AND symbol = ?"
+			}
+			args = append(args, filter.Symbol)
+		}
+		if filter.EventType != "" {
+			query += " AND event_type = ?"
+			args = append(args, filter.EventType)
+		}
+		if !filter.From.IsZero() {
+			query += " AND event_date >= ?"
+			args = append(args, filter.From)
+		}
+		if !filter.To.IsZero() {
+			query += " AND event_date <= ?"
+			args = append(args, filter.To)
+		}
+	}
+
+	query += " ORDER BY event_date ASC"
+
+	if filter != nil && filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query calendar events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]*models.CalendarEvent, 0)
+	for rows.Next() {
+		event := &models.CalendarEvent{}
+		err := rows.Scan(
+			&event.ID, &event.Symbol, &event.EventType, &event.Title, &event.EventDate, &event.EventTime,
+			&event.Importance, &event.Source, &event.Details, &event.CreatedAt, &event.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan calendar event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating calendar events: %w", err)
+	}
+
+	return events, nil
+}
+
+// DeleteCalendarEvent deletes a calendar event
+func (db *DB) DeleteCalendarEvent(id int64) error {
+	result, err := db.conn.Exec("DELETE FROM calendar_events WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete calendar event: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("calendar event %w", ErrNotFound)
+	}
+
+	return nil
+}
+
+// DeleteCalendarEventsBefore removes past events older than the cutoff
+func (db *DB) DeleteCalendarEventsBefore(cutoff time.Time) (int64, error) {
+	result, err := db.conn.Exec("DELETE FROM calendar_events WHERE event_date < ?", cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old calendar events: %w", err)
+	}
+	return result.RowsAffected()
+}
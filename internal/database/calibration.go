@@ -0,0 +1,144 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// InsertScoringWeightVersion stores a calibrated set of checklist item weights
+func (db *DB) InsertScoringWeightVersion(version *models.ScoringWeightVersion) error {
+	itemsJSON, err := json.Marshal(version.Items)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checklist item weights: %w", err)
+	}
+
+	version.CreatedAt = time.Now()
+
+	result, err := db.conn.Exec(`
+		INSERT INTO scoring_weight_versions (samples, wins, losses, base_win_rate, items, is_active, created_at, activated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, version.Samples, version.Wins, version.Losses, version.BaseWinRate, string(itemsJSON), version.IsActive,
+		version.CreatedAt, version.ActivatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert scoring weight version: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	version.ID = id
+	return nil
+}
+
+const scoringWeightVersionColumns = `id, samples, wins, losses, base_win_rate, items, is_active, created_at, activated_at`
+
+// GetScoringWeightVersions retrieves calibrated weight versions, newest first
+func (db *DB) GetScoringWeightVersions(limit int) ([]*models.ScoringWeightVersion, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+
+	rows, err := db.conn.Query("SELECT "+scoringWeightVersionColumns+" FROM scoring_weight_versions ORDER BY id DESC LIMIT ?", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scoring weight versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make([]*models.ScoringWeightVersion, 0)
+	for rows.Next() {
+		version, err := scanScoringWeightVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating scoring weight versions: %w", err)
+	}
+
+	return versions, nil
+}
+
+// GetScoringWeightVersionByID retrieves a calibrated weight version, or nil if there is none
+func (db *DB) GetScoringWeightVersionByID(id int64) (*models.ScoringWeightVersion, error) {
+	row := db.conn.QueryRow("SELECT "+scoringWeightVersionColumns+" FROM scoring_weight_versions WHERE id = ?", id)
+
+	version, err := scanScoringWeightVersion(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return version, err
+}
+
+// GetActiveScoringWeightVersion retrieves the weight version setups are scored with, or nil when the default
+// scorer is in use
+func (db *DB) GetActiveScoringWeightVersion() (*models.ScoringWeightVersion, error) {
+	row := db.conn.QueryRow("SELECT " + scoringWeightVersionColumns + " FROM scoring_weight_versions WHERE is_active = TRUE ORDER BY id DESC LIMIT 1")
+
+	version, err := scanScoringWeightVersion(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return version, err
+}
+
+// ActivateScoringWeightVersion makes a weight version the only active one
+func (db *DB) ActivateScoringWeightVersion(id int64) error {
+	return db.WithTx(func(tx *DB) error {
+		if err := tx.DeactivateScoringWeightVersions(); err != nil {
+			return err
+		}
+
+		result, err := tx.conn.Exec("UPDATE scoring_weight_versions SET is_active = TRUE, activated_at = ? WHERE id = ?", time.Now(), id)
+		if err != nil {
+			return fmt.Errorf("failed to activate scoring weight version: %w", err)
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return fmt.Errorf("scoring weight version %d %w", id, ErrNotFound)
+		}
+		return nil
+	})
+}
+
+// DeactivateScoringWeightVersions returns setup scoring to the default scorer
+func (db *DB) DeactivateScoringWeightVersions() error {
+	if _, err := db.conn.Exec("UPDATE scoring_weight_versions SET is_active = FALSE WHERE is_active = TRUE"); err != nil {
+		return fmt.Errorf("failed to deactivate scoring weight versions: %w", err)
+	}
+	return nil
+}
+
+// scanScoringWeightVersion scans a weight version from a database row
+func scanScoringWeightVersion(row interface{ Scan(...interface{}) error }) (*models.ScoringWeightVersion, error) {
+	version := &models.ScoringWeightVersion{}
+	var itemsJSON string
+	var activatedAt sql.NullTime
+
+	err := row.Scan(
+		&version.ID, &version.Samples, &version.Wins, &version.Losses, &version.BaseWinRate, &itemsJSON,
+		&version.IsActive, &version.CreatedAt, &activatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan scoring weight version: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(itemsJSON), &version.Items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal checklist item weights: %w", err)
+	}
+	if activatedAt.Valid {
+		version.ActivatedAt = &activatedAt.Time
+	}
+
+	return version, nil
+}
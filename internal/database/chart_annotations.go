@@ -0,0 +1,204 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"market-watch-go/internal/models"
+)
+
+// chartAnnotationColumns are the chart_annotations columns in scan order
+const chartAnnotationColumns = `id, user_name, symbol, annotation_type, timeframe, points, price, text, color, created_at, updated_at`
+
+// InsertChartAnnotation stores a user's chart annotation
+func (db *DB) InsertChartAnnotation(annotation *models.ChartAnnotation) error {
+	pointsJSON, err := json.Marshal(chartPoints(annotation.Points))
+	if err != nil {
+		return fmt.Errorf("failed to marshal chart annotation points: %w", err)
+	}
+
+	result, err := db.conn.Exec(`
+		INSERT INTO chart_annotations (
+			user_name, symbol, annotation_type, timeframe, points, price, text, color, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, annotation.User, annotation.Symbol, annotation.Type, annotation.Timeframe, string(pointsJSON),
+		annotation.Price, annotation.Text, annotation.Color, annotation.CreatedAt, annotation.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert chart annotation: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	annotation.ID = id
+	return nil
+}
+
+// UpdateChartAnnotation updates the drawing of one of a user's chart annotations
+func (db *DB) UpdateChartAnnotation(annotation *models.ChartAnnotation) error {
+	pointsJSON, err := json.Marshal(chartPoints(annotation.Points))
+	if err != nil {
+		return fmt.Errorf("failed to marshal chart annotation points: %w", err)
+	}
+
+	result, err := db.conn.Exec(`
+		UPDATE chart_annotations SET
+			annotation_type = ?, timeframe = ?, points = ?, price = ?, text = ?, color = ?, updated_at = ?
+		WHERE id = ? AND user_name = ? AND symbol = ?
+	`, annotation.Type, annotation.Timeframe, string(pointsJSON), annotation.Price, annotation.Text,
+		annotation.Color, annotation.UpdatedAt, annotation.ID, annotation.User, annotation.Symbol)
+	if err != nil {
+		return fmt.Errorf("failed to update chart annotation: %w", err)
+	}
+
+	return chartRowAffected(result, "chart annotation")
+}
+
+// DeleteChartAnnotation deletes one of a user's chart annotations of a symbol
+func (db *DB) DeleteChartAnnotation(user, symbol string, id int64) error {
+	result, err := db.conn.Exec("DELETE FROM chart_annotations WHERE id = ? AND user_name = ? AND symbol = ?", id, user, symbol)
+	if err != nil {
+		return fmt.Errorf("failed to delete chart annotation: %w", err)
+	}
+
+	return chartRowAffected(result, "chart annotation")
+}
+
+// GetChartAnnotation retrieves one of a user's chart annotations of a symbol
+func (db *DB) GetChartAnnotation(user, symbol string, id int64) (*models.ChartAnnotation, error) {
+	row := db.conn.QueryRow("SELECT "+chartAnnotationColumns+
+		" FROM chart_annotations WHERE id = ? AND user_name = ? AND symbol = ?", id, user, symbol)
+	annotation, err := scanChartAnnotation(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("chart annotation %w", ErrNotFound)
+	}
+	return annotation, err
+}
+
+// GetChartAnnotations retrieves a user's annotations of a symbol in the order they were drawn. With a timeframe,
+// only the ones drawn on it or on every timeframe are returned.
+func (db *DB) GetChartAnnotations(user, symbol, timeframe string) ([]*models.ChartAnnotation, error) {
+	query := "SELECT " + chartAnnotationColumns + " FROM chart_annotations WHERE user_name = ? AND symbol = ?"
+	args := []interface{}{user, symbol}
+	if timeframe != "" {
+		query += " AND (timeframe = ? OR timeframe = '')"
+		args = append(args, timeframe)
+	}
+
+	rows, err := db.conn.Query(query+" ORDER BY created_at ASC, id ASC", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chart annotations: %w", err)
+	}
+	defer rows.Close()
+
+	annotations := make([]*models.ChartAnnotation, 0)
+	for rows.Next() {
+		annotation, err := scanChartAnnotation(rows)
+		if err != nil {
+			return nil, err
+		}
+		annotations = append(annotations, annotation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating chart annotations: %w", err)
+	}
+
+	return annotations, nil
+}
+
+// DeleteChartAnnotations deletes all of a user's annotations of a symbol and returns how many there were
+func (db *DB) DeleteChartAnnotations(user, symbol string) (int64, error) {
+	result, err := db.conn.Exec("DELETE FROM chart_annotations WHERE user_name = ? AND symbol = ?", user, symbol)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete chart annotations: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return deleted, nil
+}
+
+// SaveChartLayout stores a user's layout of a symbol's chart, replacing the one saved before
+func (db *DB) SaveChartLayout(layout *models.ChartLayout) error {
+	columns := []string{"user_name", "symbol", "layout", "updated_at"}
+	query := upsertQuery("chart_layouts", columns, columns[:2], columns[2:], 1)
+	if _, err := db.conn.Exec(query, layout.User, layout.Symbol, string(layout.Layout), layout.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save chart layout: %w", err)
+	}
+	return nil
+}
+
+// GetChartLayout retrieves a user's layout of a symbol's chart, or nil when none was saved
+func (db *DB) GetChartLayout(user, symbol string) (*models.ChartLayout, error) {
+	layout := &models.ChartLayout{}
+	var layoutJSON string
+	err := db.conn.QueryRow(
+		"SELECT user_name, symbol, layout, updated_at FROM chart_layouts WHERE user_name = ? AND symbol = ?", user, symbol,
+	).Scan(&layout.User, &layout.Symbol, &layoutJSON, &layout.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chart layout: %w", err)
+	}
+
+	layout.Layout = json.RawMessage(layoutJSON)
+	return layout, nil
+}
+
+// DeleteChartLayout deletes a user's layout of a symbol's chart
+func (db *DB) DeleteChartLayout(user, symbol string) error {
+	result, err := db.conn.Exec("DELETE FROM chart_layouts WHERE user_name = ? AND symbol = ?", user, symbol)
+	if err != nil {
+		return fmt.Errorf("failed to delete chart layout: %w", err)
+	}
+
+	return chartRowAffected(result, "chart layout")
+}
+
+// chartRowAffected returns ErrNotFound for the named record when a statement changed no row
+func chartRowAffected(result sql.Result, name string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s %w", name, ErrNotFound)
+	}
+	return nil
+}
+
+// chartPoints stores annotations without points as an empty list
+func chartPoints(points []models.ChartPoint) []models.ChartPoint {
+	if points == nil {
+		return []models.ChartPoint{}
+	}
+	return points
+}
+
+// scanChartAnnotation scans a chart annotation row
+func scanChartAnnotation(row interface{ Scan(...interface{}) error }) (*models.ChartAnnotation, error) {
+	annotation := &models.ChartAnnotation{}
+	var pointsJSON string
+	err := row.Scan(
+		&annotation.ID, &annotation.User, &annotation.Symbol, &annotation.Type, &annotation.Timeframe, &pointsJSON,
+		&annotation.Price, &annotation.Text, &annotation.Color, &annotation.CreatedAt, &annotation.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan chart annotation: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(pointsJSON), &annotation.Points); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chart annotation points: %w", err)
+	}
+	return annotation, nil
+}
@@ -0,0 +1,175 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// rollupTables are the tables holding candles compacted from 1-minute bars, by timeframe
+var rollupTables = map[models.Timeframe]string{
+	models.Timeframe1h: "price_data_1h",
+	models.Timeframe1d: "price_data_1d",
+}
+
+// CreateRollupTables creates the hourly and daily candle tables old 1-minute bars are compacted into
+func (db *DB) CreateRollupTables() error {
+	for _, table := range []string{rollupTables[models.Timeframe1h], rollupTables[models.Timeframe1d]} {
+		queries := []string{
+			fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				symbol TEXT NOT NULL,
+				timestamp DATETIME NOT NULL,
+				open_price REAL NOT NULL,
+				high_price REAL NOT NULL,
+				low_price REAL NOT NULL,
+				close_price REAL NOT NULL,
+				volume INTEGER NOT NULL,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE(symbol, timestamp)
+			)`, table),
+			fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_%s_symbol_timestamp ON %s(symbol, timestamp)`, table, table),
+		}
+
+		for _, query := range queries {
+			if _, err := db.conn.Exec(query); err != nil {
+				return fmt.Errorf("failed to create rollup tables: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// CompactPriceData rolls the 1-minute bars stored before the cutoff's trading day up into hourly and daily
+// candles, then deletes them along with their volume bars. Only whole trading days are compacted, so a
+// daily candle is never written from part of a day.
+func (db *DB) CompactPriceData(cutoff time.Time) (*models.CompactionResult, error) {
+	result := &models.CompactionResult{Cutoff: timeframeBucket(cutoff, models.Timeframe1d).UTC()}
+
+	rows, err := db.conn.Query(`SELECT DISTINCT symbol FROM price_data WHERE timestamp < ?`, result.Cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query symbols to compact: %w", err)
+	}
+	var symbols []string
+	for rows.Next() {
+		var symbol string
+		if err := rows.Scan(&symbol); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan symbol: %w", err)
+		}
+		symbols = append(symbols, symbol)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating symbols: %w", err)
+	}
+
+	for _, symbol := range symbols {
+		err := db.WithTx(func(tx *DB) error {
+			return tx.compactSymbol(symbol, result)
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to compact %s: %w", symbol, err)
+		}
+		result.Symbols++
+	}
+
+	return result, nil
+}
+
+// compactSymbol compacts one symbol's bars before the result's cutoff
+func (db *DB) compactSymbol(symbol string, result *models.CompactionResult) error {
+	rows, err := db.conn.Query(`
+		SELECT id, symbol, timestamp, open_price, high_price, low_price, close_price, volume, session, created_at
+		FROM price_data
+		WHERE symbol = ? AND timestamp < ?
+		ORDER BY timestamp ASC`, symbol, result.Cutoff)
+	if err != nil {
+		return fmt.Errorf("failed to query price data: %w", err)
+	}
+	bars, err := scanPriceDataRows(rows)
+	rows.Close()
+	if err != nil {
+		return err
+	}
+
+	hourly := AggregatePriceData(bars, models.Timeframe1h)
+	daily := AggregatePriceData(bars, models.Timeframe1d)
+	if err := db.upsertCandles(rollupTables[models.Timeframe1h], hourly); err != nil {
+		return err
+	}
+	if err := db.upsertCandles(rollupTables[models.Timeframe1d], daily); err != nil {
+		return err
+	}
+
+	deleted, err := db.conn.Exec(`DELETE FROM price_data WHERE symbol = ? AND timestamp < ?`, symbol, result.Cutoff)
+	if err != nil {
+		return fmt.Errorf("failed to delete compacted price data: %w", err)
+	}
+	if _, err := db.conn.Exec(`DELETE FROM volume_data WHERE symbol = ? AND timestamp < ?`, symbol, result.Cutoff); err != nil {
+		return fmt.Errorf("failed to delete compacted volume data: %w", err)
+	}
+
+	count, err := deleted.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	result.BarsCompacted += count
+	result.HourlyCandles += len(hourly)
+	result.DailyCandles += len(daily)
+	return nil
+}
+
+// upsertCandles writes candles to a rollup table, replacing a candle already stored for the same bucket
+func (db *DB) upsertCandles(table string, candles []*models.PriceData) error {
+	rows := make([][]interface{}, 0, len(candles))
+	for _, candle := range candles {
+		rows = append(rows, []interface{}{
+			candle.Symbol, candle.Timestamp.UTC(), candle.Open, candle.High, candle.Low, candle.Close, candle.Volume, candle.CreatedAt,
+		})
+	}
+
+	return db.upsertBatch(table,
+		[]string{"symbol", "timestamp", "open_price", "high_price", "low_price", "close_price", "volume", "created_at"},
+		[]string{"symbol", "timestamp"},
+		[]string{"open_price", "high_price", "low_price", "close_price", "volume"},
+		rows)
+}
+
+// getRollupCandles retrieves compacted candles of a timeframe within a time range, or none when the
+// timeframe isn't compacted
+func (db *DB) getRollupCandles(symbol string, from, to time.Time, timeframe models.Timeframe) ([]*models.PriceData, error) {
+	table, ok := rollupTables[timeframe]
+	if !ok {
+		return nil, nil
+	}
+
+	rows, err := db.conn.Query(fmt.Sprintf(`
+		SELECT id, symbol, timestamp, open_price, high_price, low_price, close_price, volume, '' AS session, created_at
+		FROM %s
+		WHERE symbol = ? AND timestamp BETWEEN ? AND ?
+		ORDER BY timestamp ASC`, table), symbol, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query compacted price data: %w", err)
+	}
+	defer rows.Close()
+
+	return scanPriceDataRows(rows)
+}
+
+// prependRollups puts compacted candles older than the first candle rolled up from 1-minute bars in front of them
+func prependRollups(rollups, candles []*models.PriceData) []*models.PriceData {
+	if len(rollups) == 0 {
+		return candles
+	}
+	if len(candles) > 0 {
+		n := 0
+		for n < len(rollups) && rollups[n].Timestamp.Before(candles[0].Timestamp) {
+			n++
+		}
+		rollups = rollups[:n]
+	}
+	return append(rollups, candles...)
+}
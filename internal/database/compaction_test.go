@@ -0,0 +1,72 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestCompactPriceData tests rolling old 1-minute bars into hourly and daily candles
+func TestCompactPriceData(t *testing.T) {
+	db := newTestDB(t)
+
+	// Two hours of bars on each of three trading days
+	var bars []*models.PriceData
+	for day := 0; day < 3; day++ {
+		open := time.Date(2024, time.March, 4+day, 14, 30, 0, 0, time.UTC)
+		for i := 0; i < 120; i++ {
+			price := 100 + float64(day*10) + float64(i)/100
+			bars = append(bars, &models.PriceData{
+				Symbol:    "TEST",
+				Timestamp: open.Add(time.Duration(i) * time.Minute),
+				Open:      price, High: price + 1, Low: price - 1, Close: price,
+				Volume:    10,
+				CreatedAt: open,
+			})
+		}
+	}
+	if err := db.InsertPriceDataBatch(bars); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+
+	// A cutoff during the third day compacts only the first two
+	result, err := db.CompactPriceData(time.Date(2024, time.March, 6, 16, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("CompactPriceData failed: %v", err)
+	}
+	if result.Symbols != 1 || result.BarsCompacted != 240 || result.DailyCandles != 2 || result.HourlyCandles != 6 {
+		t.Errorf("unexpected compaction result: %+v", result)
+	}
+
+	from := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.March, 7, 0, 0, 0, 0, time.UTC)
+	minutes, err := db.GetPriceDataRange("TEST", from, to)
+	if err != nil || len(minutes) != 120 {
+		t.Fatalf("expected the third day's 120 minute bars to remain, got %d (%v)", len(minutes), err)
+	}
+
+	daily, err := db.GetPriceDataRangeTimeframe("TEST", from, to, models.Timeframe1d)
+	if err != nil || len(daily) != 3 {
+		t.Fatalf("expected 3 daily candles, got %d (%v)", len(daily), err)
+	}
+	if first := daily[0]; first.Open != 100 || first.Close != 101.19 || first.High != 102.19 || first.Volume != 1200 {
+		t.Errorf("unexpected compacted daily candle: %+v", first)
+	}
+	for i := 1; i < len(daily); i++ {
+		if !daily[i].Timestamp.After(daily[i-1].Timestamp) {
+			t.Errorf("daily candles out of order: %s then %s", daily[i-1].Timestamp, daily[i].Timestamp)
+		}
+	}
+
+	hourly, err := db.GetPriceDataRangeTimeframe("TEST", from, to, models.Timeframe1h)
+	if err != nil || len(hourly) != 9 {
+		t.Fatalf("expected 9 hourly candles, got %d (%v)", len(hourly), err)
+	}
+
+	// Compacting again finds nothing left before the cutoff
+	again, err := db.CompactPriceData(time.Date(2024, time.March, 6, 16, 0, 0, 0, time.UTC))
+	if err != nil || again.BarsCompacted != 0 {
+		t.Errorf("expected nothing to compact, got %+v (%v)", again, err)
+	}
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// GetSymbolMovers returns every active watched symbol's latest close, previous close and volume on the trading
+// date of the newest stored bar, which it also returns (zero when there are no bars)
+func (db *DB) GetSymbolMovers() (time.Time, []*models.SymbolMover, error) {
+	var latest time.Time
+	err := db.conn.QueryRow(`
+		SELECT timestamp FROM price_data
+		WHERE symbol IN (SELECT symbol FROM watched_symbols WHERE is_active = TRUE)
+		ORDER BY timestamp DESC LIMIT 1`).Scan(&latest)
+	if err != nil && err != sql.ErrNoRows {
+		return time.Time{}, nil, fmt.Errorf("failed to get latest bar: %w", err)
+	}
+
+	// Bars from the start of the latest trading date make up the day's move and volume
+	since := timeframeBucket(latest, models.Timeframe1d).UTC()
+
+	rows, err := db.conn.Query(`
+		SELECT w.symbol,
+			COALESCE((SELECT p.close_price FROM price_data p WHERE p.symbol = w.symbol
+				ORDER BY p.timestamp DESC LIMIT 1), 0),
+			COALESCE((SELECT p.close_price FROM price_data p WHERE p.symbol = w.symbol AND p.timestamp < ?
+				ORDER BY p.timestamp DESC LIMIT 1), 0),
+			COALESCE((SELECT SUM(p.volume) FROM price_data p WHERE p.symbol = w.symbol AND p.timestamp >= ?), 0)
+		FROM watched_symbols w
+		WHERE w.is_active = TRUE
+		ORDER BY w.symbol`, since, since)
+	if err != nil {
+		return latest, nil, fmt.Errorf("failed to query symbol movers: %w", err)
+	}
+	defer rows.Close()
+
+	movers := make([]*models.SymbolMover, 0)
+	for rows.Next() {
+		mover := &models.SymbolMover{}
+		if err := rows.Scan(&mover.Symbol, &mover.Price, &mover.PreviousClose, &mover.Volume); err != nil {
+			return latest, nil, fmt.Errorf("failed to scan symbol mover: %w", err)
+		}
+		if mover.PreviousClose > 0 {
+			mover.Change = mover.Price - mover.PreviousClose
+			mover.ChangePercent = mover.Change / mover.PreviousClose * 100
+		}
+		movers = append(movers, mover)
+	}
+	if err := rows.Err(); err != nil {
+		return latest, nil, fmt.Errorf("error iterating symbol movers: %w", err)
+	}
+
+	return latest, movers, nil
+}
+
+// GetOpenSetupCounts counts the active and triggered trading setups by status, leaving out archived setups
+func (db *DB) GetOpenSetupCounts() (map[string]int, error) {
+	return db.groupCounts(`
+		SELECT status, COUNT(*) FROM trading_setups
+		WHERE status IN ('active', 'triggered') AND archived = FALSE
+		GROUP BY status`)
+}
+
+// GetActivePatternPhaseCounts counts the incomplete, unarchived patterns of every family by phase
+func (db *DB) GetActivePatternPhaseCounts() (map[string]int, error) {
+	return db.groupCounts(`
+		SELECT current_phase, COUNT(*) FROM (
+			SELECT current_phase FROM head_shoulders_patterns WHERE is_complete = FALSE AND archived = FALSE
+			UNION ALL SELECT current_phase FROM falling_wedge_patterns WHERE is_complete = FALSE AND archived = FALSE
+			UNION ALL SELECT current_phase FROM triangle_patterns WHERE is_complete = FALSE AND archived = FALSE
+			UNION ALL SELECT current_phase FROM flag_patterns WHERE is_complete = FALSE AND archived = FALSE
+		) AS active_patterns
+		GROUP BY current_phase`)
+}
+
+// groupCounts runs a query of (key, count) rows into a map
+func (db *DB) groupCounts(query string) (map[string]int, error) {
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var key sql.NullString
+		var count int
+		if err := rows.Scan(&key, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan count: %w", err)
+		}
+		counts[key.String] += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating counts: %w", err)
+	}
+	return counts, nil
+}
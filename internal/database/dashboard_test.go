@@ -0,0 +1,90 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestDashboardOverviewQueries tests the day's move and volume of watched symbols and the open setup and
+// active pattern counts
+func TestDashboardOverviewQueries(t *testing.T) {
+	db := newTestDB(t)
+
+	for _, symbol := range []string{"AAPL", "MSFT", "IDLE"} {
+		if err := db.AddWatchedSymbol(symbol, symbol); err != nil {
+			t.Fatalf("AddWatchedSymbol failed: %v", err)
+		}
+	}
+
+	// Tuesday and Wednesday 15:00 UTC are mid-session on consecutive trading dates
+	previous := time.Date(2025, 3, 4, 15, 0, 0, 0, time.UTC)
+	latest := previous.Add(24 * time.Hour)
+	bar := func(symbol string, ts time.Time, close float64, volume int64) *models.PriceData {
+		return &models.PriceData{Symbol: symbol, Timestamp: ts, Open: close, High: close, Low: close, Close: close, Volume: volume}
+	}
+	if err := db.InsertPriceDataBatch([]*models.PriceData{
+		bar("AAPL", previous, 100, 500), bar("AAPL", latest, 104, 700), bar("AAPL", latest.Add(time.Minute), 105, 300),
+		bar("MSFT", previous, 200, 900), bar("MSFT", latest, 190, 200),
+		bar("OTHER", latest.Add(time.Hour), 50, 100),
+	}); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+
+	newest, movers, err := db.GetSymbolMovers()
+	if err != nil {
+		t.Fatalf("GetSymbolMovers failed: %v", err)
+	}
+	if !newest.Equal(latest.Add(time.Minute)) {
+		t.Errorf("expected the newest watched bar at %v, got %v", latest.Add(time.Minute), newest)
+	}
+	if len(movers) != 3 {
+		t.Fatalf("expected a mover per watched symbol, got %+v", movers)
+	}
+	aapl, idle, msft := movers[0], movers[1], movers[2]
+	if aapl.Price != 105 || aapl.PreviousClose != 100 || aapl.ChangePercent != 5 || aapl.Volume != 1000 {
+		t.Errorf("unexpected AAPL move: %+v", aapl)
+	}
+	if msft.ChangePercent != -5 || msft.Volume != 200 {
+		t.Errorf("unexpected MSFT move: %+v", msft)
+	}
+	if idle.Price != 0 || idle.ChangePercent != 0 || idle.Volume != 0 {
+		t.Errorf("expected no move for a symbol without bars, got %+v", idle)
+	}
+
+	now := time.Now()
+	for _, status := range []string{"active", "active", "triggered", "expired"} {
+		setup := &models.TradingSetup{
+			Symbol: "AAPL", SetupType: "support_bounce", Direction: "bullish", Confidence: "medium", Status: status,
+			DetectedAt: now, ExpiresAt: now.Add(time.Hour), EntryPrice: 100, StopLoss: 95, Target1: 110,
+		}
+		if err := db.InsertTradingSetup(setup); err != nil {
+			t.Fatalf("InsertTradingSetup failed: %v", err)
+		}
+	}
+	setups, err := db.GetOpenSetupCounts()
+	if err != nil {
+		t.Fatalf("GetOpenSetupCounts failed: %v", err)
+	}
+	if setups["active"] != 2 || setups["triggered"] != 1 || len(setups) != 2 {
+		t.Errorf("expected 2 active and 1 triggered setups, got %v", setups)
+	}
+
+	if err := db.InsertTrianglePattern(&models.TrianglePattern{Symbol: "AAPL", PatternType: "ascending_triangle", CurrentPhase: models.PhaseFormation, DetectedAt: now, LastUpdated: now}); err != nil {
+		t.Fatalf("InsertTrianglePattern failed: %v", err)
+	}
+	if err := db.InsertFlagPattern(&models.FlagPattern{Symbol: "AAPL", PatternType: "bull_flag", CurrentPhase: models.PhaseBreakout, DetectedAt: now, LastUpdated: now}); err != nil {
+		t.Fatalf("InsertFlagPattern failed: %v", err)
+	}
+	if err := db.InsertFlagPattern(&models.FlagPattern{Symbol: "MSFT", PatternType: "bull_flag", CurrentPhase: models.PhaseFormation, DetectedAt: now, LastUpdated: now, IsComplete: true}); err != nil {
+		t.Fatalf("InsertFlagPattern failed: %v", err)
+	}
+	phases, err := db.GetActivePatternPhaseCounts()
+	if err != nil {
+		t.Fatalf("GetActivePatternPhaseCounts failed: %v", err)
+	}
+	if phases[models.PhaseFormation] != 1 || phases[models.PhaseBreakout] != 1 {
+		t.Errorf("expected one forming and one breaking out active pattern, got %v", phases)
+	}
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"market-watch-go/internal/models"
+)
+
+// GetPriceDataVersion returns the version of a symbol's price bars
+func (db *DB) GetPriceDataVersion(symbol string) (*models.DataVersion, error) {
+	return db.getDataVersion("price_data", symbol, "open_price", "high_price", "low_price", "close_price", "volume")
+}
+
+// GetVolumeDataVersion returns the version of a symbol's volume bars
+func (db *DB) GetVolumeDataVersion(symbol string) (*models.DataVersion, error) {
+	return db.getDataVersion("volume_data", symbol, "volume")
+}
+
+// GetTechnicalIndicatorsVersion returns the version of a symbol's stored technical indicators
+func (db *DB) GetTechnicalIndicatorsVersion(symbol string) (*models.DataVersion, error) {
+	return db.getDataVersion("technical_indicators", symbol, "rsi_14", "macd_line", "macd_signal", "created_at")
+}
+
+// getDataVersion returns the newest row's timestamp and content columns and the row count of a symbol in a
+// table, or an empty version when the symbol has no rows
+func (db *DB) getDataVersion(table, symbol string, contentColumns ...string) (*models.DataVersion, error) {
+	version := &models.DataVersion{}
+	content := make([]interface{}, len(contentColumns))
+	dest := []interface{}{&version.Latest, &version.Rows}
+	for i := range content {
+		dest = append(dest, &content[i])
+	}
+
+	err := db.conn.QueryRow(fmt.Sprintf(`
+		SELECT timestamp, (SELECT COUNT(*) FROM %s WHERE symbol = ?), %s
+		FROM %s
+		WHERE symbol = ?
+		ORDER BY timestamp DESC
+		LIMIT 1`, table, strings.Join(contentColumns, ", "), table), symbol, symbol).Scan(dest...)
+	if err == sql.ErrNoRows {
+		return &models.DataVersion{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s version: %w", table, err)
+	}
+
+	version.Content = fmt.Sprint(content...)
+	return version, nil
+}
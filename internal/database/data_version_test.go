@@ -0,0 +1,62 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestGetPriceDataVersion tests that the version changes with new, backfilled and updated bars only
+func TestGetPriceDataVersion(t *testing.T) {
+	db := newTestDB(t)
+
+	empty, err := db.GetPriceDataVersion("AAPL")
+	if err != nil || !empty.Latest.IsZero() || empty.Rows != 0 {
+		t.Fatalf("expected an empty version without bars, got %+v %v", empty, err)
+	}
+
+	start := time.Date(2025, 3, 4, 15, 0, 0, 0, time.UTC)
+	bar := func(ts time.Time, close float64) *models.PriceData {
+		return &models.PriceData{Symbol: "AAPL", Timestamp: ts, Open: close, High: close, Low: close, Close: close, Volume: 100}
+	}
+	version := func() models.DataVersion {
+		t.Helper()
+		v, err := db.GetPriceDataVersion("AAPL")
+		if err != nil {
+			t.Fatalf("GetPriceDataVersion failed: %v", err)
+		}
+		return *v
+	}
+
+	if err := db.InsertPriceDataBatch([]*models.PriceData{bar(start, 100), bar(start.Add(time.Minute), 101)}); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+	first := version()
+	if !first.Latest.Equal(start.Add(time.Minute)) || first.Rows != 2 {
+		t.Fatalf("expected 2 bars up to %v, got %+v", start.Add(time.Minute), first)
+	}
+	if again := version(); again != first {
+		t.Errorf("expected an unchanged version, got %+v then %+v", first, again)
+	}
+
+	steps := []struct {
+		name string
+		bars []*models.PriceData
+	}{
+		{"the newest bar updated in place", []*models.PriceData{bar(start.Add(time.Minute), 102)}},
+		{"a backfilled bar", []*models.PriceData{bar(start.Add(-time.Hour), 99)}},
+		{"a new bar", []*models.PriceData{bar(start.Add(2*time.Minute), 103)}},
+	}
+	previous := first
+	for _, step := range steps {
+		if err := db.InsertPriceDataBatch(step.bars); err != nil {
+			t.Fatalf("InsertPriceDataBatch failed: %v", err)
+		}
+		current := version()
+		if current == previous {
+			t.Errorf("expected %s to change the version, got %+v", step.name, current)
+		}
+		previous = current
+	}
+}
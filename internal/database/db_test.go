@@ -0,0 +1,21 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+
+	"market-watch-go/internal/config"
+)
+
+// newTestDB opens a fresh database in the test's temporary directory, closed when the test ends
+func newTestDB(t *testing.T) *DB {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Database.Path = filepath.Join(t.TempDir(), "test.db")
+	db, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
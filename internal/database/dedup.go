@@ -0,0 +1,49 @@
+package database
+
+import (
+	"fmt"
+	"log"
+)
+
+// ensureUniqueBars removes repeated bars from a bar table created before it had UNIQUE(symbol, timestamp), keeping
+// the most recently written one, and adds a unique index so upserts work. Tables that already enforce uniqueness
+// are left alone, so this runs once per table.
+func (db *DB) ensureUniqueBars(table string) error {
+	// Postgres tables have been created with the constraint from the start
+	if db.pool.dialect.isPostgres() {
+		return nil
+	}
+
+	var unique int
+	err := db.conn.QueryRow(`SELECT COUNT(*) FROM pragma_index_list(?) WHERE "unique" = 1`, table).Scan(&unique)
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s indexes: %w", table, err)
+	}
+	if unique > 0 {
+		return nil
+	}
+
+	var removed int64
+	err = db.WithTx(func(tx *DB) error {
+		result, err := tx.conn.Exec(fmt.Sprintf(
+			`DELETE FROM %s WHERE id NOT IN (SELECT MAX(id) FROM %s GROUP BY symbol, timestamp)`, table, table))
+		if err != nil {
+			return fmt.Errorf("failed to delete duplicate %s bars: %w", table, err)
+		}
+		if removed, err = result.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if _, err := tx.conn.Exec(fmt.Sprintf(
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_unique_bar ON %s(symbol, timestamp)`, table, table)); err != nil {
+			return fmt.Errorf("failed to create unique %s index: %w", table, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Removed %d duplicate bars from %s and added a unique (symbol, timestamp) index", removed, table)
+	return nil
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestEnsureUniqueBars tests deduplicating a price_data table created before it had a unique constraint
+func TestEnsureUniqueBars(t *testing.T) {
+	db := newTestDB(t)
+
+	// Recreate price_data as older versions did, then store every bar twice
+	legacy := []string{
+		`DROP TABLE price_data`,
+		`CREATE TABLE price_data (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			symbol TEXT NOT NULL,
+			timestamp DATETIME NOT NULL,
+			open_price REAL NOT NULL,
+			high_price REAL NOT NULL,
+			low_price REAL NOT NULL,
+			close_price REAL NOT NULL,
+			volume INTEGER NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		// The legacy table predates bar sessions too
+		`DELETE FROM schema_migrations WHERE name = 'price_bar_sessions'`,
+	}
+	for _, query := range legacy {
+		if _, err := db.conn.Exec(query); err != nil {
+			t.Fatalf("failed to create legacy table: %v", err)
+		}
+	}
+
+	start := time.Date(2024, time.March, 4, 14, 30, 0, 0, time.UTC)
+	for pass := 0; pass < 2; pass++ {
+		for i := 0; i < 3; i++ {
+			price := 100 + float64(pass)
+			_, err := db.conn.Exec(`INSERT INTO price_data (symbol, timestamp, open_price, high_price, low_price, close_price, volume)
+				VALUES ('TEST', ?, ?, ?, ?, ?, 1000)`, start.Add(time.Duration(i)*time.Minute), price, price, price, price)
+			if err != nil {
+				t.Fatalf("failed to insert legacy bar: %v", err)
+			}
+		}
+	}
+
+	if err := db.runMigrations(); err != nil {
+		t.Fatalf("runMigrations failed: %v", err)
+	}
+	if _, err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	bars, err := db.GetPriceDataRange("TEST", start, start.Add(time.Hour))
+	if err != nil || len(bars) != 3 {
+		t.Fatalf("expected 3 bars after deduplication, got %d (%v)", len(bars), err)
+	}
+	if bars[0].Close != 101 {
+		t.Errorf("expected the most recently written bar to be kept, got close %v", bars[0].Close)
+	}
+
+	// The unique index lets restated bars update in place
+	restated := &models.PriceData{Symbol: "TEST", Timestamp: start, Open: 102, High: 102, Low: 102, Close: 102, Volume: 1000, CreatedAt: start}
+	if err := db.InsertPriceDataBatch([]*models.PriceData{restated}); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+	if count, err := db.GetPriceDataCount(); err != nil || count != 3 {
+		t.Errorf("expected 3 bars after restating one, got %d (%v)", count, err)
+	}
+
+	// Later startups leave the table alone
+	if err := db.runMigrations(); err != nil {
+		t.Fatalf("second runMigrations failed: %v", err)
+	}
+}
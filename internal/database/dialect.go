@@ -0,0 +1,465 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Supported database drivers
+const (
+	DriverSQLite   = "sqlite3"
+	DriverPostgres = "postgres"
+)
+
+// dialect rewrites the SQLite flavoured SQL used throughout this package for other backends.
+// Queries are written once (SQLite syntax, ? placeholders) and translated on the way to the driver.
+type dialect struct {
+	driver string
+
+	mutex sync.RWMutex
+	// tables records, per table created through this connection, whether it has an
+	// auto-increment id column and which columns form its natural unique key
+	tables map[string]*tableInfo
+}
+
+// tableInfo describes the parts of a table definition needed for query translation
+type tableInfo struct {
+	hasID     bool
+	uniqueKey []string
+}
+
+var (
+	autoIncrementRe = regexp.MustCompile(`(?i)\bINTEGER\s+PRIMARY\s+KEY\s+AUTOINCREMENT\b`)
+	integerRe       = regexp.MustCompile(`(?i)\bINTEGER\b`)
+	realRe          = regexp.MustCompile(`(?i)\bREAL\b`)
+	datetimeTypeRe  = regexp.MustCompile(`(?i)\bDATETIME\b`)
+	boolDefaultRe   = regexp.MustCompile(`(?i)\bBOOLEAN\s+DEFAULT\s+([01])\b`)
+	addColumnRe     = regexp.MustCompile(`(?i)\bADD\s+COLUMN\s+`)
+
+	// datetime('now', '-' || ? || ' days')
+	datetimeParamRe = regexp.MustCompile(`(?i)datetime\(\s*'now'\s*,\s*'-'\s*\|\|\s*\?\s*\|\|\s*'\s*(\w+?)s?'\s*\)`)
+	// datetime('now', '-24 hours')
+	datetimeLiteralRe = regexp.MustCompile(`(?i)datetime\(\s*'now'\s*,\s*'-(\d+)\s+(\w+)'\s*\)`)
+	// datetime('now')
+	datetimeNowRe = regexp.MustCompile(`(?i)datetime\(\s*'now'\s*\)`)
+
+	insertOrReplaceRe = regexp.MustCompile(`(?is)^\s*INSERT\s+OR\s+REPLACE\s+INTO\s+(\w+)\s*\(([^)]*)\)`)
+	orReplaceRe       = regexp.MustCompile(`(?i)INSERT\s+OR\s+REPLACE\s+INTO`)
+	insertOrIgnoreRe  = regexp.MustCompile(`(?is)^\s*INSERT\s+OR\s+IGNORE\s+INTO\s+`)
+	insertIntoRe      = regexp.MustCompile(`(?is)^\s*INSERT\s+INTO\s+(\w+)`)
+	createTableRe     = regexp.MustCompile(`(?is)CREATE\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?(\w+)\s*\(`)
+)
+
+// newDialect creates a dialect for the given driver name
+func newDialect(driver string) *dialect {
+	return &dialect{
+		driver: driver,
+		tables: make(map[string]*tableInfo),
+	}
+}
+
+// isPostgres reports whether queries need translating for Postgres
+func (d *dialect) isPostgres() bool {
+	return d.driver == DriverPostgres
+}
+
+// translate rewrites a query for the target backend. The returned flag reports whether the
+// query is an INSERT into a table with an id column, which needs RETURNING id on Postgres.
+func (d *dialect) translate(query string) (string, bool) {
+	if !d.isPostgres() {
+		return query, false
+	}
+
+	trimmed := strings.TrimSpace(query)
+	upper := strings.ToUpper(trimmed)
+
+	// Schema statements: record table shape, then convert column types
+	if strings.Contains(upper, "CREATE TABLE") || strings.HasPrefix(upper, "ALTER TABLE") {
+		d.registerTables(query)
+
+		query = autoIncrementRe.ReplaceAllString(query, "BIGSERIAL PRIMARY KEY")
+		query = integerRe.ReplaceAllString(query, "BIGINT")
+		query = realRe.ReplaceAllString(query, "DOUBLE PRECISION")
+		query = datetimeTypeRe.ReplaceAllString(query, "TIMESTAMPTZ")
+		query = boolDefaultRe.ReplaceAllStringFunc(query, func(m string) string {
+			if strings.HasSuffix(m, "1") {
+				return "BOOLEAN DEFAULT TRUE"
+			}
+			return "BOOLEAN DEFAULT FALSE"
+		})
+		if strings.HasPrefix(upper, "ALTER TABLE") {
+			query = addColumnRe.ReplaceAllString(query, "ADD COLUMN IF NOT EXISTS ")
+		}
+		return d.rebind(query), false
+	}
+
+	// Date arithmetic
+	query = datetimeParamRe.ReplaceAllString(query, "(NOW() - CAST(? AS INTEGER) * INTERVAL '1 $1')")
+	query = datetimeLiteralRe.ReplaceAllString(query, "(NOW() - INTERVAL '$1 $2')")
+	query = datetimeNowRe.ReplaceAllString(query, "NOW()")
+
+	// Upserts
+	if m := insertOrReplaceRe.FindStringSubmatch(query); m != nil {
+		query = d.rewriteInsertOrReplace(query, m[1], m[2])
+	} else if insertOrIgnoreRe.MatchString(query) {
+		query = insertOrIgnoreRe.ReplaceAllString(query, "INSERT INTO ")
+		query = strings.TrimRight(strings.TrimSpace(query), ";") + " ON CONFLICT DO NOTHING"
+	}
+
+	needsReturning := false
+	if m := insertIntoRe.FindStringSubmatch(query); m != nil && !strings.Contains(strings.ToUpper(query), "RETURNING") {
+		d.mutex.RLock()
+		info := d.tables[strings.ToLower(m[1])]
+		d.mutex.RUnlock()
+		needsReturning = info != nil && info.hasID
+	}
+
+	return d.rebind(query), needsReturning
+}
+
+// rewriteInsertOrReplace converts INSERT OR REPLACE into INSERT ... ON CONFLICT DO UPDATE
+func (d *dialect) rewriteInsertOrReplace(query, table, columnList string) string {
+	query = orReplaceRe.ReplaceAllString(query, "INSERT INTO")
+	query = strings.TrimRight(strings.TrimSpace(query), ";")
+
+	d.mutex.RLock()
+	info := d.tables[strings.ToLower(table)]
+	d.mutex.RUnlock()
+
+	if info == nil || len(info.uniqueKey) == 0 {
+		return query + " ON CONFLICT DO NOTHING"
+	}
+
+	isKey := make(map[string]bool)
+	for _, col := range info.uniqueKey {
+		isKey[col] = true
+	}
+
+	var updates []string
+	for _, col := range strings.Split(columnList, ",") {
+		col = strings.ToLower(strings.TrimSpace(col))
+		if col == "" || isKey[col] {
+			continue
+		}
+		updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", col, col))
+	}
+
+	if len(updates) == 0 {
+		return fmt.Sprintf("%s ON CONFLICT (%s) DO NOTHING", query, strings.Join(info.uniqueKey, ", "))
+	}
+
+	return fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s",
+		query, strings.Join(info.uniqueKey, ", "), strings.Join(updates, ", "))
+}
+
+// registerTables records id columns and unique keys of CREATE TABLE statements
+func (d *dialect) registerTables(query string) {
+	for _, loc := range createTableRe.FindAllStringSubmatchIndex(query, -1) {
+		table := strings.ToLower(query[loc[2]:loc[3]])
+		body := matchParens(query[loc[1]:])
+
+		info := &tableInfo{}
+		var columnUnique []string
+		for _, part := range splitTopLevel(body) {
+			part = strings.TrimSpace(part)
+			upperPart := strings.ToUpper(part)
+			fields := strings.Fields(part)
+			if len(fields) == 0 {
+				continue
+			}
+
+			switch {
+			case strings.HasPrefix(upperPart, "UNIQUE"):
+				if info.uniqueKey == nil {
+					info.uniqueKey = splitColumns(matchParens(part[strings.Index(part, "(")+1:]))
+				}
+			case strings.EqualFold(fields[0], "id") && strings.Contains(upperPart, "PRIMARY KEY"):
+				info.hasID = true
+			case strings.Contains(upperPart, " UNIQUE") && columnUnique == nil:
+				columnUnique = []string{strings.ToLower(fields[0])}
+			}
+		}
+		if info.uniqueKey == nil {
+			info.uniqueKey = columnUnique
+		}
+
+		d.mutex.Lock()
+		d.tables[table] = info
+		d.mutex.Unlock()
+	}
+}
+
+// rebind converts ? placeholders to $1, $2, ... outside of string literals
+func (d *dialect) rebind(query string) string {
+	if !d.isPostgres() || !strings.Contains(query, "?") {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query) + 16)
+
+	inQuote := false
+	n := 0
+	for _, r := range query {
+		switch {
+		case r == '\'':
+			inQuote = !inQuote
+			b.WriteRune(r)
+		case r == '?' && !inQuote:
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// isUniqueViolation reports whether err is a unique constraint violation on any backend
+func isUniqueViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "UNIQUE constraint failed") || strings.Contains(msg, "duplicate key value violates unique constraint")
+}
+
+// matchParens returns the text up to the parenthesis closing an already opened one
+func matchParens(s string) string {
+	depth := 1
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return s[:i]
+			}
+		}
+	}
+	return s
+}
+
+// splitTopLevel splits a table body on commas that are not nested in parentheses
+func splitTopLevel(body string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range body {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, body[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, body[start:])
+}
+
+// splitColumns splits a column list into lowercase column names
+func splitColumns(list string) []string {
+	var columns []string
+	for _, col := range strings.Split(list, ",") {
+		if col = strings.ToLower(strings.TrimSpace(col)); col != "" {
+			columns = append(columns, col)
+		}
+	}
+	return columns
+}
+
+// insertResult is the sql.Result of a Postgres INSERT ... RETURNING id
+type insertResult struct {
+	id       int64
+	affected int64
+}
+
+func (r insertResult) LastInsertId() (int64, error) { return r.id, nil }
+func (r insertResult) RowsAffected() (int64, error) { return r.affected, nil }
+
+// queryer is the subset of *sql.DB and *sql.Tx used for dialect-aware execution
+type queryer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+	Prepare(query string) (*sql.Stmt, error)
+}
+
+// contextQueryer is the context-aware counterpart of queryer, implemented by *sql.DB and *sql.Tx as well as
+// the translating dbConn and Tx wrappers
+type contextQueryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// execWithDialect runs a statement, emulating LastInsertId on Postgres via RETURNING id
+func execWithDialect(ctx context.Context, q contextQueryer, d *dialect, query string, args ...interface{}) (sql.Result, error) {
+	translated, needsReturning := d.translate(query)
+	if !needsReturning {
+		return q.ExecContext(ctx, translated, args...)
+	}
+
+	var id int64
+	err := q.QueryRowContext(ctx, translated+" RETURNING id", args...).Scan(&id)
+	if err == sql.ErrNoRows {
+		// ON CONFLICT DO NOTHING skipped the row
+		return insertResult{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return insertResult{id: id, affected: 1}, nil
+}
+
+// dbConn wraps *sql.DB and translates queries for the configured backend
+type dbConn struct {
+	*sql.DB
+	dialect *dialect
+	writes  *writeStats
+}
+
+// Exec executes a statement
+func (c *dbConn) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return c.ExecContext(context.Background(), query, args...)
+}
+
+// ExecContext executes a statement, giving up when ctx is done
+func (c *dbConn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return execWithDialect(ctx, c.DB, c.dialect, query, args...)
+}
+
+// Query executes a query that returns rows
+func (c *dbConn) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return c.QueryContext(context.Background(), query, args...)
+}
+
+// QueryContext executes a query that returns rows, giving up when ctx is done
+func (c *dbConn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	translated, _ := c.dialect.translate(query)
+	return c.DB.QueryContext(ctx, translated, args...)
+}
+
+// QueryRow executes a query that returns at most one row
+func (c *dbConn) QueryRow(query string, args ...interface{}) *sql.Row {
+	return c.QueryRowContext(context.Background(), query, args...)
+}
+
+// QueryRowContext executes a query that returns at most one row, giving up when ctx is done
+func (c *dbConn) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	translated, _ := c.dialect.translate(query)
+	return c.DB.QueryRowContext(ctx, translated, args...)
+}
+
+// Prepare creates a prepared statement
+func (c *dbConn) Prepare(query string) (*sql.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext creates a prepared statement, giving up when ctx is done
+func (c *dbConn) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	translated, _ := c.dialect.translate(query)
+	return c.DB.PrepareContext(ctx, translated)
+}
+
+// Begin starts a transaction
+func (c *dbConn) Begin() (*Tx, error) {
+	return c.BeginTx(context.Background())
+}
+
+// BeginTx starts a transaction that is rolled back if ctx is done before it commits
+func (c *dbConn) BeginTx(ctx context.Context) (*Tx, error) {
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, dialect: c.dialect}, nil
+}
+
+// Tx wraps *sql.Tx and translates queries for the configured backend
+type Tx struct {
+	*sql.Tx
+	dialect *dialect
+}
+
+// Exec executes a statement within the transaction
+func (t *Tx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return t.ExecContext(context.Background(), query, args...)
+}
+
+// ExecContext executes a statement within the transaction, giving up when ctx is done
+func (t *Tx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return execWithDialect(ctx, t.Tx, t.dialect, query, args...)
+}
+
+// Query executes a query that returns rows within the transaction
+func (t *Tx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return t.QueryContext(context.Background(), query, args...)
+}
+
+// QueryContext executes a query that returns rows within the transaction, giving up when ctx is done
+func (t *Tx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	translated, _ := t.dialect.translate(query)
+	return t.Tx.QueryContext(ctx, translated, args...)
+}
+
+// QueryRow executes a query that returns at most one row within the transaction
+func (t *Tx) QueryRow(query string, args ...interface{}) *sql.Row {
+	return t.QueryRowContext(context.Background(), query, args...)
+}
+
+// QueryRowContext executes a query that returns at most one row within the transaction, giving up when ctx is done
+func (t *Tx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	translated, _ := t.dialect.translate(query)
+	return t.Tx.QueryRowContext(ctx, translated, args...)
+}
+
+// Prepare creates a prepared statement within the transaction
+func (t *Tx) Prepare(query string) (*sql.Stmt, error) {
+	return t.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext creates a prepared statement within the transaction, giving up when ctx is done
+func (t *Tx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	translated, _ := t.dialect.translate(query)
+	return t.Tx.PrepareContext(ctx, translated)
+}
+
+// translatingConn is a pool or transaction wrapper offering both the plain and context-aware query methods
+type translatingConn interface {
+	queryer
+	contextQueryer
+}
+
+// boundConn runs every query of a connection or transaction under a fixed context, so the
+// existing ctx-less query methods can be cancelled without threading ctx through each of them
+type boundConn struct {
+	ctx  context.Context
+	conn translatingConn
+}
+
+func (b *boundConn) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return b.conn.ExecContext(b.ctx, query, args...)
+}
+
+func (b *boundConn) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return b.conn.QueryContext(b.ctx, query, args...)
+}
+
+func (b *boundConn) QueryRow(query string, args ...interface{}) *sql.Row {
+	return b.conn.QueryRowContext(b.ctx, query, args...)
+}
+
+func (b *boundConn) Prepare(query string) (*sql.Stmt, error) {
+	return b.conn.PrepareContext(b.ctx, query)
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestPostgresDialectTranslate tests the SQLite to Postgres query translation
+func TestPostgresDialectTranslate(t *testing.T) {
+	d := newDialect(DriverPostgres)
+
+	schema, _ := d.translate(`CREATE TABLE IF NOT EXISTS price_data (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		symbol TEXT NOT NULL,
+		timestamp DATETIME NOT NULL,
+		close_price REAL NOT NULL,
+		volume INTEGER NOT NULL,
+		is_active BOOLEAN DEFAULT 1,
+		UNIQUE(symbol, timestamp)
+	)`)
+
+	for _, want := range []string{"BIGSERIAL PRIMARY KEY", "TIMESTAMPTZ NOT NULL", "DOUBLE PRECISION", "volume BIGINT", "BOOLEAN DEFAULT TRUE"} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("expected schema to contain %q, got:\n%s", want, schema)
+		}
+	}
+
+	upsert, returning := d.translate(`INSERT OR REPLACE INTO price_data (symbol, timestamp, close_price, volume) VALUES (?, ?, ?, ?)`)
+	wantUpsert := "INSERT INTO price_data (symbol, timestamp, close_price, volume) VALUES ($1, $2, $3, $4) ON CONFLICT (symbol, timestamp) DO UPDATE SET close_price = EXCLUDED.close_price, volume = EXCLUDED.volume"
+	if upsert != wantUpsert {
+		t.Errorf("unexpected upsert translation:\n got: %s\nwant: %s", upsert, wantUpsert)
+	}
+	if !returning {
+		t.Errorf("expected insert into table with id to need RETURNING id")
+	}
+
+	cleanup, _ := d.translate(`DELETE FROM price_data WHERE created_at < datetime('now', '-' || ? || ' days') AND symbol = ?`)
+	wantCleanup := "DELETE FROM price_data WHERE created_at < (NOW() - CAST($1 AS INTEGER) * INTERVAL '1 day') AND symbol = $2"
+	if cleanup != wantCleanup {
+		t.Errorf("unexpected cleanup translation:\n got: %s\nwant: %s", cleanup, wantCleanup)
+	}
+}
+
+// TestSQLiteDialectPassthrough tests that SQLite queries are left untouched
+func TestSQLiteDialectPassthrough(t *testing.T) {
+	d := newDialect(DriverSQLite)
+
+	query := `INSERT OR REPLACE INTO price_data (symbol) VALUES (?)`
+	translated, returning := d.translate(query)
+	if translated != query || returning {
+		t.Errorf("expected sqlite query to pass through unchanged, got %q (returning=%v)", translated, returning)
+	}
+}
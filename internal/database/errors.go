@@ -0,0 +1,6 @@
+package database
+
+import "errors"
+
+// ErrNotFound is returned, wrapped with what was missing, when a requested record doesn't exist
+var ErrNotFound = errors.New("not found")
@@ -62,6 +62,7 @@ func (db *DB) CreateFallingWedgePatternTables() error {
 		`CREATE INDEX IF NOT EXISTS idx_falling_wedge_symbol ON falling_wedge_patterns(symbol)`,
 		`CREATE INDEX IF NOT EXISTS idx_falling_wedge_phase ON falling_wedge_patterns(current_phase)`,
 		`CREATE INDEX IF NOT EXISTS idx_falling_wedge_complete ON falling_wedge_patterns(is_complete)`,
+		`CREATE INDEX IF NOT EXISTS idx_falling_wedge_type ON falling_wedge_patterns(pattern_type)`,
 		`CREATE INDEX IF NOT EXISTS idx_falling_wedge_detected ON falling_wedge_patterns(detected_at)`,
 		`CREATE INDEX IF NOT EXISTS idx_falling_wedge_symbol_phase ON falling_wedge_patterns(symbol, current_phase)`,
 		`CREATE INDEX IF NOT EXISTS idx_falling_wedge_symbol_complete ON falling_wedge_patterns(symbol, is_complete)`,
@@ -112,8 +113,8 @@ func (db *DB) InsertFallingWedgePattern(pattern *models.FallingWedgePattern) err
 			upper_trend_line_1, upper_trend_line_2, lower_trend_line_1, lower_trend_line_2,
 			upper_slope, lower_slope, breakout_level, pattern_width, pattern_height, convergence,
 			volume_profile, thesis_components,
-			detected_at, last_updated, is_complete, current_phase
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			detected_at, last_updated, is_complete, current_phase, fingerprint
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	result, err := db.conn.Exec(query,
@@ -122,7 +123,7 @@ func (db *DB) InsertFallingWedgePattern(pattern *models.FallingWedgePattern) err
 		pattern.UpperSlope, pattern.LowerSlope, pattern.BreakoutLevel,
 		pattern.PatternWidth, pattern.PatternHeight, pattern.Convergence,
 		pattern.VolumeProfile, string(thesisJSON),
-		pattern.DetectedAt, pattern.LastUpdated, pattern.IsComplete, pattern.CurrentPhase,
+		pattern.DetectedAt, pattern.LastUpdated, pattern.IsComplete, pattern.CurrentPhase, pattern.Fingerprint(),
 	)
 
 	if err != nil {
@@ -140,54 +141,20 @@ func (db *DB) InsertFallingWedgePattern(pattern *models.FallingWedgePattern) err
 
 // GetFallingWedgePatterns retrieves falling wedge patterns with optional filtering
 func (db *DB) GetFallingWedgePatterns(filter *models.FallingWedgeFilter) ([]*models.FallingWedgePattern, error) {
+	if filter == nil {
+		filter = &models.FallingWedgeFilter{Limit: 100}
+	}
+
+	where, args := fallingWedgeWhere(filter)
 	query := `
 		SELECT id, setup_id, symbol, pattern_type,
 		       upper_trend_line_1, upper_trend_line_2, lower_trend_line_1, lower_trend_line_2,
 		       upper_slope, lower_slope, breakout_level, pattern_width, pattern_height, convergence,
 		       volume_profile, thesis_components,
-		       detected_at, last_updated, is_complete, current_phase
-		FROM falling_wedge_patterns 
-		WHERE 1=1
-	`
-	args := []interface{}{}
-
-	// Add optional filters
-	if filter != nil {
-		if filter.Symbol != "" {
-			query += " AND symbol = ?"
-			args = append(args, filter.Symbol)
-		}
-
-		if filter.Phase != "" {
-			query += " AND current_phase = ?"
-			args = append(args, filter.Phase)
-		}
-
-		if filter.IsComplete != nil {
-			query += " AND is_complete = ?"
-			args = append(args, *filter.IsComplete)
-		}
-
-		if filter.MinConvergence > 0 {
-			query += " AND convergence >= ?"
-			args = append(args, filter.MinConvergence)
-		}
-
-		// Order by detected date descending
-		query += " ORDER BY detected_at DESC"
-
-		if filter.Limit > 0 {
-			query += " LIMIT ?"
-			args = append(args, filter.Limit)
-		}
-
-		if filter.Offset > 0 {
-			query += " OFFSET ?"
-			args = append(args, filter.Offset)
-		}
-	} else {
-		query += " ORDER BY detected_at DESC LIMIT 100"
-	}
+		       detected_at, last_updated, is_complete, current_phase, archived, archived_at
+		FROM falling_wedge_patterns` + where
+	query += orderBy(filter.Sort, filter.Order, models.PatternSortFields, "detected_at DESC")
+	query, args = limitOffset(query, args, filter.Limit, filter.Offset)
 
 	rows, err := db.conn.Query(query, args...)
 	if err != nil {
@@ -202,6 +169,7 @@ func (db *DB) GetFallingWedgePatterns(filter *models.FallingWedgeFilter) ([]*mod
 		pattern := &models.FallingWedgePattern{}
 		var upperLine1JSON, upperLine2JSON, lowerLine1JSON, lowerLine2JSON string
 		var thesisJSON string
+		var archivedAt sql.NullTime
 
 		err := rows.Scan(
 			&pattern.ID, &pattern.SetupID, &pattern.Symbol, &pattern.PatternType,
@@ -210,10 +178,14 @@ func (db *DB) GetFallingWedgePatterns(filter *models.FallingWedgeFilter) ([]*mod
 			&pattern.PatternWidth, &pattern.PatternHeight, &pattern.Convergence,
 			&pattern.VolumeProfile, &thesisJSON,
 			&pattern.DetectedAt, &pattern.LastUpdated, &pattern.IsComplete, &pattern.CurrentPhase,
+			&pattern.Archived, &archivedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan falling wedge pattern: %w", err)
 		}
+		if archivedAt.Valid {
+			pattern.ArchivedAt = &archivedAt.Time
+		}
 
 		// Unmarshal pattern points
 		if err := json.Unmarshal([]byte(upperLine1JSON), &pattern.UpperTrendLine1); err != nil {
@@ -247,6 +219,52 @@ func (db *DB) GetFallingWedgePatterns(filter *models.FallingWedgeFilter) ([]*mod
 	return patterns, nil
 }
 
+// CountFallingWedgePatterns counts the patterns matching a filter, ignoring its limit and offset
+func (db *DB) CountFallingWedgePatterns(filter *models.FallingWedgeFilter) (int, error) {
+	where, args := fallingWedgeWhere(filter)
+	return db.countRows("falling_wedge_patterns", where, args)
+}
+
+// fallingWedgeWhere builds the WHERE clause for a FallingWedgeFilter
+func fallingWedgeWhere(filter *models.FallingWedgeFilter) (string, []interface{}) {
+	where := " WHERE 1=1"
+	args := []interface{}{}
+	if filter == nil {
+		return where + " AND archived = FALSE", args
+	}
+
+	if filter.Symbol != "" {
+		where += " AND symbol = ?"
+		args = append(args, filter.Symbol)
+	}
+
+	if filter.PatternType != "" {
+		where += " AND pattern_type = ?"
+		args = append(args, filter.PatternType)
+	}
+
+	if filter.Phase != "" {
+		where += " AND current_phase = ?"
+		args = append(args, filter.Phase)
+	}
+
+	if filter.IsComplete != nil {
+		where += " AND is_complete = ?"
+		args = append(args, *filter.IsComplete)
+	}
+
+	if filter.MinConvergence > 0 {
+		where += " AND convergence >= ?"
+		args = append(args, filter.MinConvergence)
+	}
+
+	if !filter.IncludeArchived {
+		where += " AND archived = FALSE"
+	}
+
+	return where, args
+}
+
 // GetFallingWedgePatternByID retrieves a specific falling wedge pattern by ID
 func (db *DB) GetFallingWedgePatternByID(id int64) (*models.FallingWedgePattern, error) {
 	query := `
@@ -254,7 +272,7 @@ func (db *DB) GetFallingWedgePatternByID(id int64) (*models.FallingWedgePattern,
 		       upper_trend_line_1, upper_trend_line_2, lower_trend_line_1, lower_trend_line_2,
 		       upper_slope, lower_slope, breakout_level, pattern_width, pattern_height, convergence,
 		       volume_profile, thesis_components,
-		       detected_at, last_updated, is_complete, current_phase
+		       detected_at, last_updated, is_complete, current_phase, archived, archived_at
 		FROM falling_wedge_patterns 
 		WHERE id = ?
 	`
@@ -264,6 +282,7 @@ func (db *DB) GetFallingWedgePatternByID(id int64) (*models.FallingWedgePattern,
 	pattern := &models.FallingWedgePattern{}
 	var upperLine1JSON, upperLine2JSON, lowerLine1JSON, lowerLine2JSON string
 	var thesisJSON string
+	var archivedAt sql.NullTime
 
 	err := row.Scan(
 		&pattern.ID, &pattern.SetupID, &pattern.Symbol, &pattern.PatternType,
@@ -272,6 +291,7 @@ func (db *DB) GetFallingWedgePatternByID(id int64) (*models.FallingWedgePattern,
 		&pattern.PatternWidth, &pattern.PatternHeight, &pattern.Convergence,
 		&pattern.VolumeProfile, &thesisJSON,
 		&pattern.DetectedAt, &pattern.LastUpdated, &pattern.IsComplete, &pattern.CurrentPhase,
+		&pattern.Archived, &archivedAt,
 	)
 
 	if err != nil {
@@ -281,6 +301,10 @@ func (db *DB) GetFallingWedgePatternByID(id int64) (*models.FallingWedgePattern,
 		return nil, fmt.Errorf("failed to get falling wedge pattern: %w", err)
 	}
 
+	if archivedAt.Valid {
+		pattern.ArchivedAt = &archivedAt.Time
+	}
+
 	// Unmarshal pattern points
 	if err := json.Unmarshal([]byte(upperLine1JSON), &pattern.UpperTrendLine1); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal upper trend line 1: %w", err)
@@ -0,0 +1,286 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// CreateFlagPatternTables creates all flag pattern related tables
+func (db *DB) CreateFlagPatternTables() error {
+	query := `CREATE TABLE IF NOT EXISTS flag_patterns (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		symbol TEXT NOT NULL,
+		pattern_type TEXT NOT NULL CHECK (pattern_type IN ('bull_flag', 'bear_flag')),
+
+		-- Flagpole and flag points (JSON stored as TEXT)
+		pole_start TEXT NOT NULL,
+		pole_end TEXT NOT NULL,
+		flag_high TEXT NOT NULL,
+		flag_low TEXT NOT NULL,
+
+		-- Pattern metrics
+		pole_height REAL NOT NULL,
+		pole_change REAL NOT NULL,
+		retracement REAL NOT NULL,
+		flag_slope REAL NOT NULL,
+		breakout_level REAL NOT NULL,
+		pattern_width INTEGER NOT NULL,
+
+		-- Volume analysis
+		volume_profile TEXT DEFAULT 'unknown',
+
+		-- Thesis components (JSON stored as TEXT)
+		thesis_components TEXT NOT NULL DEFAULT '{}',
+
+		-- Status
+		detected_at DATETIME NOT NULL,
+		last_updated DATETIME DEFAULT CURRENT_TIMESTAMP,
+		is_complete BOOLEAN DEFAULT FALSE,
+		current_phase TEXT DEFAULT 'formation' CHECK (current_phase IN ('formation', 'breakout', 'target_pursuit', 'completed'))
+	)`
+
+	if _, err := db.conn.Exec(query); err != nil {
+		return fmt.Errorf("failed to create flag pattern table: %w", err)
+	}
+
+	indexes := []string{
+		`CREATE INDEX IF NOT EXISTS idx_flag_symbol ON flag_patterns(symbol)`,
+		`CREATE INDEX IF NOT EXISTS idx_flag_type ON flag_patterns(pattern_type)`,
+		`CREATE INDEX IF NOT EXISTS idx_flag_complete ON flag_patterns(is_complete)`,
+		`CREATE INDEX IF NOT EXISTS idx_flag_detected ON flag_patterns(detected_at)`,
+	}
+
+	for _, indexQuery := range indexes {
+		if _, err := db.conn.Exec(indexQuery); err != nil {
+			return fmt.Errorf("failed to create flag pattern index: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// InsertFlagPattern inserts a new flag pattern
+func (db *DB) InsertFlagPattern(pattern *models.FlagPattern) error {
+	points, err := marshalPatternPoints(pattern.PoleStart, pattern.PoleEnd, pattern.FlagHigh, pattern.FlagLow)
+	if err != nil {
+		return err
+	}
+
+	thesisJSON, err := json.Marshal(pattern.ThesisComponents)
+	if err != nil {
+		return fmt.Errorf("failed to marshal thesis components: %w", err)
+	}
+
+	query := `
+		INSERT INTO flag_patterns (
+			symbol, pattern_type,
+			pole_start, pole_end, flag_high, flag_low,
+			pole_height, pole_change, retracement, flag_slope, breakout_level, pattern_width,
+			volume_profile, thesis_components,
+			detected_at, last_updated, is_complete, current_phase, fingerprint
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := db.conn.Exec(query,
+		pattern.Symbol, pattern.PatternType,
+		points[0], points[1], points[2], points[3],
+		pattern.PoleHeight, pattern.PoleChange, pattern.Retracement,
+		pattern.FlagSlope, pattern.BreakoutLevel, pattern.PatternWidth,
+		pattern.VolumeProfile, string(thesisJSON),
+		pattern.DetectedAt, pattern.LastUpdated, pattern.IsComplete, pattern.CurrentPhase, pattern.Fingerprint(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert flag pattern: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	pattern.ID = id
+	return nil
+}
+
+const flagColumns = `id, symbol, pattern_type,
+	pole_start, pole_end, flag_high, flag_low,
+	pole_height, pole_change, retracement, flag_slope, breakout_level, pattern_width,
+	volume_profile, thesis_components,
+	detected_at, last_updated, is_complete, current_phase, archived, archived_at`
+
+// GetFlagPatterns retrieves flag patterns with optional filtering
+func (db *DB) GetFlagPatterns(filter *models.FlagFilter) ([]*models.FlagPattern, error) {
+	if filter == nil {
+		filter = &models.FlagFilter{Limit: 100}
+	}
+
+	where, args := flagWhere(filter)
+	query := "SELECT " + flagColumns + " FROM flag_patterns" + where
+	query += orderBy(filter.Sort, filter.Order, models.PatternSortFields, "detected_at DESC")
+	query, args = limitOffset(query, args, filter.Limit, filter.Offset)
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query flag patterns: %w", err)
+	}
+	defer rows.Close()
+
+	patterns := make([]*models.FlagPattern, 0)
+	for rows.Next() {
+		pattern, err := scanFlagPattern(rows)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating flag patterns: %w", err)
+	}
+
+	return patterns, nil
+}
+
+// CountFlagPatterns counts the patterns matching a filter, ignoring its limit and offset
+func (db *DB) CountFlagPatterns(filter *models.FlagFilter) (int, error) {
+	where, args := flagWhere(filter)
+	return db.countRows("flag_patterns", where, args)
+}
+
+// flagWhere builds the WHERE clause for a FlagFilter
+func flagWhere(filter *models.FlagFilter) (string, []interface{}) {
+	where := " WHERE 1=1"
+	args := []interface{}{}
+	if filter == nil {
+		return where + " AND archived = FALSE", args
+	}
+
+	if filter.Symbol != "" {
+		where += " AND symbol = ?"
+		args = append(args, filter.Symbol)
+	}
+
+	if filter.PatternType != "" {
+		where += " AND pattern_type = ?"
+		args = append(args, filter.PatternType)
+	}
+
+	if filter.Phase != "" {
+		where += " AND current_phase = ?"
+		args = append(args, filter.Phase)
+	}
+
+	if filter.IsComplete != nil {
+		where += " AND is_complete = ?"
+		args = append(args, *filter.IsComplete)
+	}
+
+	if !filter.IncludeArchived {
+		where += " AND archived = FALSE"
+	}
+
+	return where, args
+}
+
+// GetFlagPatternByID retrieves a specific flag pattern by ID
+func (db *DB) GetFlagPatternByID(id int64) (*models.FlagPattern, error) {
+	row := db.conn.QueryRow("SELECT "+flagColumns+" FROM flag_patterns WHERE id = ?", id)
+
+	pattern, err := scanFlagPattern(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return pattern, err
+}
+
+// UpdateFlagPattern updates an existing flag pattern
+func (db *DB) UpdateFlagPattern(pattern *models.FlagPattern) error {
+	thesisJSON, err := json.Marshal(pattern.ThesisComponents)
+	if err != nil {
+		return fmt.Errorf("failed to marshal thesis components: %w", err)
+	}
+
+	query := `
+		UPDATE flag_patterns SET
+			breakout_level = ?, volume_profile = ?, thesis_components = ?,
+			last_updated = ?, is_complete = ?, current_phase = ?
+		WHERE id = ?
+	`
+
+	pattern.LastUpdated = time.Now()
+
+	_, err = db.conn.Exec(query,
+		pattern.BreakoutLevel, pattern.VolumeProfile, string(thesisJSON),
+		pattern.LastUpdated, pattern.IsComplete, pattern.CurrentPhase,
+		pattern.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update flag pattern: %w", err)
+	}
+
+	return nil
+}
+
+// GetActiveFlagPatterns retrieves all active flag patterns
+func (db *DB) GetActiveFlagPatterns() ([]*models.FlagPattern, error) {
+	isComplete := false
+	return db.GetFlagPatterns(&models.FlagFilter{
+		IsComplete: &isComplete,
+		Limit:      1000,
+	})
+}
+
+// GetFlagPatternsBySymbol retrieves all flag patterns for a specific symbol
+func (db *DB) GetFlagPatternsBySymbol(symbol string) ([]*models.FlagPattern, error) {
+	return db.GetFlagPatterns(&models.FlagFilter{
+		Symbol: symbol,
+		Limit:  100,
+	})
+}
+
+// scanFlagPattern scans a flag pattern row and decodes its JSON columns
+func scanFlagPattern(row interface{ Scan(...interface{}) error }) (*models.FlagPattern, error) {
+	pattern := &models.FlagPattern{}
+	var poleStartJSON, poleEndJSON, flagHighJSON, flagLowJSON string
+	var thesisJSON string
+	var archivedAt sql.NullTime
+
+	err := row.Scan(
+		&pattern.ID, &pattern.Symbol, &pattern.PatternType,
+		&poleStartJSON, &poleEndJSON, &flagHighJSON, &flagLowJSON,
+		&pattern.PoleHeight, &pattern.PoleChange, &pattern.Retracement,
+		&pattern.FlagSlope, &pattern.BreakoutLevel, &pattern.PatternWidth,
+		&pattern.VolumeProfile, &thesisJSON,
+		&pattern.DetectedAt, &pattern.LastUpdated, &pattern.IsComplete, &pattern.CurrentPhase,
+		&pattern.Archived, &archivedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan flag pattern: %w", err)
+	}
+
+	if archivedAt.Valid {
+		pattern.ArchivedAt = &archivedAt.Time
+	}
+
+	if err := unmarshalPatternPoints(
+		[]string{poleStartJSON, poleEndJSON, flagHighJSON, flagLowJSON},
+		&pattern.PoleStart, &pattern.PoleEnd, &pattern.FlagHigh, &pattern.FlagLow,
+	); err != nil {
+		return nil, err
+	}
+
+	if thesisJSON != "" {
+		if err := json.Unmarshal([]byte(thesisJSON), &pattern.ThesisComponents); err != nil {
+			fmt.Printf("Failed to unmarshal thesis components for flag pattern %d: %v\n", pattern.ID, err)
+		}
+	}
+
+	return pattern, nil
+}
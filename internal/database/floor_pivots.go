@@ -0,0 +1,56 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"market-watch-go/internal/models"
+)
+
+// floorPivotColumns are the floor_pivots columns in scan order
+var floorPivotColumns = []string{
+	"symbol", "period", "trading_date", "high", "low", "close",
+	"pp", "r1", "r2", "r3", "s1", "s2", "s3", "computed_at",
+}
+
+// UpsertFloorPivots stores a symbol's pivots for a session, replacing the ones computed before
+func (db *DB) UpsertFloorPivots(pivots *models.FloorPivots) error {
+	query := upsertQuery("floor_pivots", floorPivotColumns, floorPivotColumns[:3], floorPivotColumns[3:], 1)
+	_, err := db.conn.Exec(query,
+		pivots.Symbol, pivots.Period, pivots.TradingDate, pivots.High, pivots.Low, pivots.Close,
+		pivots.PP, pivots.R1, pivots.R2, pivots.R3, pivots.S1, pivots.S2, pivots.S3, pivots.ComputedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert floor pivots: %w", err)
+	}
+	return nil
+}
+
+// GetFloorPivots retrieves a symbol's stored pivots for a session, daily before weekly
+func (db *DB) GetFloorPivots(symbol, tradingDate string) ([]*models.FloorPivots, error) {
+	rows, err := db.conn.Query("SELECT "+strings.Join(floorPivotColumns, ", ")+
+		" FROM floor_pivots WHERE symbol = ? AND trading_date = ? ORDER BY period", symbol, tradingDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query floor pivots: %w", err)
+	}
+	defer rows.Close()
+
+	all := make([]*models.FloorPivots, 0)
+	for rows.Next() {
+		pivots := &models.FloorPivots{}
+		err := rows.Scan(
+			&pivots.Symbol, &pivots.Period, &pivots.TradingDate, &pivots.High, &pivots.Low, &pivots.Close,
+			&pivots.PP, &pivots.R1, &pivots.R2, &pivots.R3, &pivots.S1, &pivots.S2, &pivots.S3, &pivots.ComputedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan floor pivots: %w", err)
+		}
+		all = append(all, pivots)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating floor pivots: %w", err)
+	}
+
+	return all, nil
+}
@@ -155,45 +155,51 @@ func (db *DB) InsertHeadShouldersPattern(pattern *models.HeadShouldersPattern) e
 		 head_high, head_low, right_shoulder_high, right_shoulder_low,
 		 neckline_level, neckline_slope, neckline_touch1, neckline_touch2,
 		 pattern_width, pattern_height, symmetry, thesis_components,
-		 detected_at, last_updated, is_complete, current_phase)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 detected_at, last_updated, is_complete, current_phase, fingerprint)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	result, err := db.conn.Exec(query,
-		pattern.SetupID, pattern.Symbol, pattern.PatternType,
-		string(leftShoulderHigh), string(leftShoulderLow),
-		string(headHigh), string(headLow),
-		string(rightShoulderHigh), string(rightShoulderLow),
-		pattern.NecklineLevel, pattern.NecklineSlope,
-		string(necklineTouch1), string(necklineTouch2),
-		pattern.PatternWidth, pattern.PatternHeight, pattern.Symmetry,
-		string(thesisData), pattern.DetectedAt, pattern.LastUpdated,
-		pattern.IsComplete, pattern.CurrentPhase,
-	)
+	return db.WithTx(func(tx *DB) error {
+		result, err := tx.conn.Exec(query,
+			pattern.SetupID, pattern.Symbol, pattern.PatternType,
+			string(leftShoulderHigh), string(leftShoulderLow),
+			string(headHigh), string(headLow),
+			string(rightShoulderHigh), string(rightShoulderLow),
+			pattern.NecklineLevel, pattern.NecklineSlope,
+			string(necklineTouch1), string(necklineTouch2),
+			pattern.PatternWidth, pattern.PatternHeight, pattern.Symmetry,
+			string(thesisData), pattern.DetectedAt, pattern.LastUpdated,
+			pattern.IsComplete, pattern.CurrentPhase, pattern.Fingerprint(),
+		)
 
-	if err != nil {
-		return fmt.Errorf("failed to insert head and shoulders pattern: %w", err)
-	}
+		if err != nil {
+			return fmt.Errorf("failed to insert head and shoulders pattern: %w", err)
+		}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get pattern ID: %w", err)
-	}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get pattern ID: %w", err)
+		}
 
-	pattern.ID = id
+		pattern.ID = id
 
-	// Insert thesis components
-	err = db.insertThesisComponents(pattern.ID, &pattern.ThesisComponents)
-	if err != nil {
-		return fmt.Errorf("failed to insert thesis components: %w", err)
-	}
+		// Insert thesis components
+		err = tx.insertThesisComponents(pattern.ID, &pattern.ThesisComponents)
+		if err != nil {
+			return fmt.Errorf("failed to insert thesis components: %w", err)
+		}
 
-	return nil
+		return nil
+	})
 }
 
 // UpdateHeadShouldersPattern updates an existing head and shoulders pattern
 func (db *DB) UpdateHeadShouldersPattern(pattern *models.HeadShouldersPattern) error {
 	// Marshal pattern points to JSON
+	leftShoulderHigh, _ := models.MarshalPatternPointsJSON(pattern.LeftShoulderHigh)
+	leftShoulderLow, _ := models.MarshalPatternPointsJSON(pattern.LeftShoulderLow)
+	headHigh, _ := models.MarshalPatternPointsJSON(pattern.HeadHigh)
+	headLow, _ := models.MarshalPatternPointsJSON(pattern.HeadLow)
 	rightShoulderHigh, _ := models.MarshalPatternPointsJSON(pattern.RightShoulderHigh)
 	rightShoulderLow, _ := models.MarshalPatternPointsJSON(pattern.RightShoulderLow)
 	necklineTouch1, _ := models.MarshalPatternPointsJSON(pattern.NecklineTouch1)
@@ -207,155 +213,187 @@ func (db *DB) UpdateHeadShouldersPattern(pattern *models.HeadShouldersPattern) e
 
 	query := `
 		UPDATE head_shoulders_patterns 
-		SET right_shoulder_high = ?, right_shoulder_low = ?,
+		SET left_shoulder_high = ?, left_shoulder_low = ?,
+		    head_high = ?, head_low = ?,
+		    right_shoulder_high = ?, right_shoulder_low = ?,
 		    neckline_level = ?, neckline_slope = ?,
 		    neckline_touch1 = ?, neckline_touch2 = ?,
 		    pattern_width = ?, pattern_height = ?, symmetry = ?,
 		    thesis_components = ?, last_updated = ?,
-		    is_complete = ?, current_phase = ?
+		    is_complete = ?, current_phase = ?,
+		    human_edited = ?, annotation = ?
 		WHERE id = ?
 	`
 
 	pattern.LastUpdated = time.Now()
 
-	_, err = db.conn.Exec(query,
-		string(rightShoulderHigh), string(rightShoulderLow),
-		pattern.NecklineLevel, pattern.NecklineSlope,
-		string(necklineTouch1), string(necklineTouch2),
-		pattern.PatternWidth, pattern.PatternHeight, pattern.Symmetry,
-		string(thesisData), pattern.LastUpdated,
-		pattern.IsComplete, pattern.CurrentPhase, pattern.ID,
-	)
+	return db.WithTx(func(tx *DB) error {
+		_, err := tx.conn.Exec(query,
+			string(leftShoulderHigh), string(leftShoulderLow),
+			string(headHigh), string(headLow),
+			string(rightShoulderHigh), string(rightShoulderLow),
+			pattern.NecklineLevel, pattern.NecklineSlope,
+			string(necklineTouch1), string(necklineTouch2),
+			pattern.PatternWidth, pattern.PatternHeight, pattern.Symmetry,
+			string(thesisData), pattern.LastUpdated,
+			pattern.IsComplete, pattern.CurrentPhase,
+			pattern.HumanEdited, pattern.Annotation, pattern.ID,
+		)
+
+		if err != nil {
+			return fmt.Errorf("failed to update head and shoulders pattern: %w", err)
+		}
 
+		// Update thesis components
+		err = tx.updateThesisComponents(pattern.ID, &pattern.ThesisComponents)
+		if err != nil {
+			return fmt.Errorf("failed to update thesis components: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetHeadShouldersPatterns retrieves patterns based on filter criteria
+func (db *DB) GetHeadShouldersPatterns(filter *models.PatternFilter) ([]*models.HeadShouldersPattern, error) {
+	where, args := headShouldersWhere(filter)
+	query := `SELECT ` + headShouldersPatternColumns + ` FROM head_shoulders_patterns` + where
+	query += orderBy(filter.Sort, filter.Order, models.PatternSortFields, "detected_at DESC")
+	query, args = limitOffset(query, args, filter.Limit, filter.Offset)
+
+	rows, err := db.conn.Query(query, args...)
 	if err != nil {
-		return fmt.Errorf("failed to update head and shoulders pattern: %w", err)
+		return nil, fmt.Errorf("failed to query head and shoulders patterns: %w", err)
 	}
+	defer rows.Close()
 
-	// Update thesis components
-	err = db.updateThesisComponents(pattern.ID, &pattern.ThesisComponents)
-	if err != nil {
-		return fmt.Errorf("failed to update thesis components: %w", err)
+	// Initialize empty slice to ensure we never return nil
+	patterns := make([]*models.HeadShouldersPattern, 0)
+
+	for rows.Next() {
+		pattern, err := scanHeadShouldersPattern(rows)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, pattern)
 	}
 
-	return nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating head and shoulders patterns: %w", err)
+	}
+
+	return patterns, nil
 }
 
-// GetHeadShouldersPatterns retrieves patterns based on filter criteria
-func (db *DB) GetHeadShouldersPatterns(filter *models.PatternFilter) ([]*models.HeadShouldersPattern, error) {
-	query := `
-		SELECT id, setup_id, symbol, pattern_type,
-		       left_shoulder_high, left_shoulder_low, head_high, head_low,
-		       right_shoulder_high, right_shoulder_low,
-		       neckline_level, neckline_slope, neckline_touch1, neckline_touch2,
-		       pattern_width, pattern_height, symmetry, thesis_components,
-		       detected_at, last_updated, is_complete, current_phase
-		FROM head_shoulders_patterns 
-		WHERE 1=1
-	`
+// CountHeadShouldersPatterns counts the patterns matching a filter, ignoring its limit and offset
+func (db *DB) CountHeadShouldersPatterns(filter *models.PatternFilter) (int, error) {
+	where, args := headShouldersWhere(filter)
+	return db.countRows("head_shoulders_patterns", where, args)
+}
+
+// headShouldersWhere builds the WHERE clause for a PatternFilter
+func headShouldersWhere(filter *models.PatternFilter) (string, []interface{}) {
+	where := " WHERE 1=1"
 	args := []interface{}{}
 
 	// Add optional filters
 	if filter.Symbol != "" {
-		query += " AND symbol = ?"
+		where += " AND symbol = ?"
 		args = append(args, filter.Symbol)
 	}
 
 	if filter.PatternType != "" {
-		query += " AND pattern_type = ?"
+		where += " AND pattern_type = ?"
 		args = append(args, filter.PatternType)
 	}
 
 	if filter.Phase != "" {
-		query += " AND current_phase = ?"
+		where += " AND current_phase = ?"
 		args = append(args, filter.Phase)
 	}
 
 	if filter.IsComplete != nil {
-		query += " AND is_complete = ?"
+		where += " AND is_complete = ?"
 		args = append(args, *filter.IsComplete)
 	}
 
 	if filter.MinSymmetry > 0 {
-		query += " AND symmetry >= ?"
+		where += " AND symmetry >= ?"
 		args = append(args, filter.MinSymmetry)
 	}
 
-	// Order by detected date descending
-	query += " ORDER BY detected_at DESC"
-
-	if filter.Limit > 0 {
-		query += " LIMIT ?"
-		args = append(args, filter.Limit)
-	}
-
-	if filter.Offset > 0 {
-		query += " OFFSET ?"
-		args = append(args, filter.Offset)
+	if !filter.IncludeArchived {
+		where += " AND archived = FALSE"
 	}
 
-	rows, err := db.conn.Query(query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query head and shoulders patterns: %w", err)
-	}
-	defer rows.Close()
-
-	// Initialize empty slice to ensure we never return nil
-	patterns := make([]*models.HeadShouldersPattern, 0)
-
-	for rows.Next() {
-		pattern := &models.HeadShouldersPattern{}
-		var leftShoulderHighJSON, leftShoulderLowJSON, headHighJSON, headLowJSON string
-		var rightShoulderHighJSON, rightShoulderLowJSON string
-		var necklineTouch1JSON, necklineTouch2JSON string
-		var thesisJSON string
-
-		err := rows.Scan(
-			&pattern.ID, &pattern.SetupID, &pattern.Symbol, &pattern.PatternType,
-			&leftShoulderHighJSON, &leftShoulderLowJSON, &headHighJSON, &headLowJSON,
-			&rightShoulderHighJSON, &rightShoulderLowJSON,
-			&pattern.NecklineLevel, &pattern.NecklineSlope,
-			&necklineTouch1JSON, &necklineTouch2JSON,
-			&pattern.PatternWidth, &pattern.PatternHeight, &pattern.Symmetry,
-			&thesisJSON, &pattern.DetectedAt, &pattern.LastUpdated,
-			&pattern.IsComplete, &pattern.CurrentPhase,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan head and shoulders pattern: %w", err)
-		}
-
-		// Unmarshal pattern points
-		pattern.LeftShoulderHigh, _ = models.UnmarshalPatternPointsJSON([]byte(leftShoulderHighJSON))
-		pattern.LeftShoulderLow, _ = models.UnmarshalPatternPointsJSON([]byte(leftShoulderLowJSON))
-		pattern.HeadHigh, _ = models.UnmarshalPatternPointsJSON([]byte(headHighJSON))
-		pattern.HeadLow, _ = models.UnmarshalPatternPointsJSON([]byte(headLowJSON))
-		pattern.RightShoulderHigh, _ = models.UnmarshalPatternPointsJSON([]byte(rightShoulderHighJSON))
-		pattern.RightShoulderLow, _ = models.UnmarshalPatternPointsJSON([]byte(rightShoulderLowJSON))
-		pattern.NecklineTouch1, _ = models.UnmarshalPatternPointsJSON([]byte(necklineTouch1JSON))
-		pattern.NecklineTouch2, _ = models.UnmarshalPatternPointsJSON([]byte(necklineTouch2JSON))
+	return where, args
+}
 
-		// Unmarshal thesis components
-		pattern.ThesisComponents, _ = models.UnmarshalThesisJSON([]byte(thesisJSON))
+// GetHeadShouldersPatternByID retrieves a specific pattern with its thesis components and alert count, or nil if it doesn't exist
+func (db *DB) GetHeadShouldersPatternByID(id int64) (*models.HeadShouldersPattern, error) {
+	row := db.conn.QueryRow(`SELECT `+headShouldersPatternColumns+` FROM head_shoulders_patterns WHERE id = ?`, id)
 
-		patterns = append(patterns, pattern)
+	pattern, err := scanHeadShouldersPattern(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
 	}
-
-	return patterns, nil
+	return pattern, err
 }
 
-// GetHeadShouldersPatternByID retrieves a specific pattern by ID
-func (db *DB) GetHeadShouldersPatternByID(id int64) (*models.HeadShouldersPattern, error) {
-	patterns, err := db.GetHeadShouldersPatterns(&models.PatternFilter{Limit: 1})
+// headShouldersPatternColumns are the columns read by scanHeadShouldersPattern
+const headShouldersPatternColumns = `id, setup_id, symbol, pattern_type,
+	left_shoulder_high, left_shoulder_low, head_high, head_low,
+	right_shoulder_high, right_shoulder_low,
+	neckline_level, neckline_slope, neckline_touch1, neckline_touch2,
+	pattern_width, pattern_height, symmetry, thesis_components,
+	detected_at, last_updated, is_complete, current_phase, human_edited, annotation, archived, archived_at,
+	(SELECT COUNT(*) FROM pattern_alerts WHERE pattern_alerts.pattern_id = head_shoulders_patterns.id)`
+
+// scanHeadShouldersPattern scans a pattern from a database row
+func scanHeadShouldersPattern(row interface{ Scan(...interface{}) error }) (*models.HeadShouldersPattern, error) {
+	pattern := &models.HeadShouldersPattern{}
+	var leftShoulderHighJSON, leftShoulderLowJSON, headHighJSON, headLowJSON string
+	var rightShoulderHighJSON, rightShoulderLowJSON string
+	var necklineTouch1JSON, necklineTouch2JSON string
+	var thesisJSON string
+	var archivedAt sql.NullTime
+
+	err := row.Scan(
+		&pattern.ID, &pattern.SetupID, &pattern.Symbol, &pattern.PatternType,
+		&leftShoulderHighJSON, &leftShoulderLowJSON, &headHighJSON, &headLowJSON,
+		&rightShoulderHighJSON, &rightShoulderLowJSON,
+		&pattern.NecklineLevel, &pattern.NecklineSlope,
+		&necklineTouch1JSON, &necklineTouch2JSON,
+		&pattern.PatternWidth, &pattern.PatternHeight, &pattern.Symmetry,
+		&thesisJSON, &pattern.DetectedAt, &pattern.LastUpdated,
+		&pattern.IsComplete, &pattern.CurrentPhase, &pattern.HumanEdited, &pattern.Annotation,
+		&pattern.Archived, &archivedAt, &pattern.AlertCount,
+	)
 	if err != nil {
-		return nil, err
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan head and shoulders pattern: %w", err)
 	}
 
-	for _, pattern := range patterns {
-		if pattern.ID == id {
-			return pattern, nil
-		}
+	if archivedAt.Valid {
+		pattern.ArchivedAt = &archivedAt.Time
 	}
 
-	return nil, sql.ErrNoRows
+	// Unmarshal pattern points
+	pattern.LeftShoulderHigh, _ = models.UnmarshalPatternPointsJSON([]byte(leftShoulderHighJSON))
+	pattern.LeftShoulderLow, _ = models.UnmarshalPatternPointsJSON([]byte(leftShoulderLowJSON))
+	pattern.HeadHigh, _ = models.UnmarshalPatternPointsJSON([]byte(headHighJSON))
+	pattern.HeadLow, _ = models.UnmarshalPatternPointsJSON([]byte(headLowJSON))
+	pattern.RightShoulderHigh, _ = models.UnmarshalPatternPointsJSON([]byte(rightShoulderHighJSON))
+	pattern.RightShoulderLow, _ = models.UnmarshalPatternPointsJSON([]byte(rightShoulderLowJSON))
+	pattern.NecklineTouch1, _ = models.UnmarshalPatternPointsJSON([]byte(necklineTouch1JSON))
+	pattern.NecklineTouch2, _ = models.UnmarshalPatternPointsJSON([]byte(necklineTouch2JSON))
+
+	// Unmarshal thesis components
+	pattern.ThesisComponents, _ = models.UnmarshalThesisJSON([]byte(thesisJSON))
+
+	return pattern, nil
 }
 
 // GetActiveHeadShouldersPatterns retrieves all active (incomplete) patterns
@@ -525,48 +563,3 @@ func (db *DB) GetPendingPatternAlerts() ([]*models.PatternAlert, error) {
 
 	return alerts, nil
 }
-
-// CleanupOldPatternData removes old pattern data based on retention policy
-func (db *DB) CleanupOldPatternData(days int) (int64, error) {
-	var totalDeleted int64
-
-	// Cleanup old thesis components
-	thesisResult, err := db.conn.Exec(
-		`DELETE FROM thesis_components WHERE pattern_id IN 
-		 (SELECT id FROM head_shoulders_patterns WHERE detected_at < datetime('now', '-' || ? || ' days'))`,
-		days,
-	)
-	if err != nil {
-		return 0, fmt.Errorf("failed to cleanup old thesis components: %w", err)
-	}
-
-	thesisDeleted, _ := thesisResult.RowsAffected()
-	totalDeleted += thesisDeleted
-
-	// Cleanup old alerts
-	alertResult, err := db.conn.Exec(
-		`DELETE FROM pattern_alerts WHERE pattern_id IN 
-		 (SELECT id FROM head_shoulders_patterns WHERE detected_at < datetime('now', '-' || ? || ' days'))`,
-		days,
-	)
-	if err != nil {
-		return 0, fmt.Errorf("failed to cleanup old pattern alerts: %w", err)
-	}
-
-	alertsDeleted, _ := alertResult.RowsAffected()
-	totalDeleted += alertsDeleted
-
-	// Cleanup old patterns
-	patternResult, err := db.conn.Exec(
-		"DELETE FROM head_shoulders_patterns WHERE detected_at < datetime('now', '-' || ? || ' days')",
-		days,
-	)
-	if err != nil {
-		return 0, fmt.Errorf("failed to cleanup old head and shoulders patterns: %w", err)
-	}
-
-	patternsDeleted, _ := patternResult.RowsAffected()
-	totalDeleted += patternsDeleted
-
-	return totalDeleted, nil
-}
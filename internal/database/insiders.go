@@ -0,0 +1,94 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// insiderTransactionColumns are the insider_transactions columns written by UpsertInsiderTransactions, in scan
+// order after the ID
+var insiderTransactionColumns = []string{
+	"symbol", "accession_number", "line", "insider_name", "insider_title", "is_director", "is_officer",
+	"is_ten_percent_owner", "security_title", "is_derivative", "transaction_date", "filed_date",
+	"transaction_code", "acquired_disposed", "shares", "price", "value", "shares_owned_after",
+}
+
+// UpsertInsiderTransactions stores the transactions of a Form 4 filing and records the filing as fetched, in
+// one transaction
+func (db *DB) UpsertInsiderTransactions(symbol, accessionNumber, filedDate string, transactions []*models.InsiderTransaction) error {
+	rows := make([][]interface{}, len(transactions))
+	for i, t := range transactions {
+		rows[i] = []interface{}{
+			t.Symbol, t.AccessionNumber, t.Line, t.InsiderName, t.InsiderTitle, t.IsDirector, t.IsOfficer,
+			t.IsTenPercentOwner, t.SecurityTitle, t.IsDerivative, t.TransactionDate, t.FiledDate,
+			t.TransactionCode, t.AcquiredDisposed, t.Shares, t.Price, t.Value, t.SharesOwnedAfter,
+		}
+	}
+
+	return db.WithTx(func(tx *DB) error {
+		if err := tx.upsertBatch("insider_transactions", insiderTransactionColumns, insiderTransactionColumns[:3], insiderTransactionColumns[3:], rows); err != nil {
+			return fmt.Errorf("failed to upsert insider transactions: %w", err)
+		}
+
+		columns := []string{"symbol", "accession_number", "filed_date", "fetched_at"}
+		query := upsertQuery("insider_filings", columns, columns[:2], columns[2:], 1)
+		if _, err := tx.conn.Exec(query, symbol, accessionNumber, filedDate, time.Now()); err != nil {
+			return fmt.Errorf("failed to record insider filing: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetInsiderFilings returns the accession numbers of a stock's Form 4 filings already fetched
+func (db *DB) GetInsiderFilings(symbol string) (map[string]bool, error) {
+	rows, err := db.conn.Query("SELECT accession_number FROM insider_filings WHERE symbol = ?", symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query insider filings: %w", err)
+	}
+	defer rows.Close()
+
+	filings := make(map[string]bool)
+	for rows.Next() {
+		var accession string
+		if err := rows.Scan(&accession); err != nil {
+			return nil, fmt.Errorf("failed to scan insider filing: %w", err)
+		}
+		filings[accession] = true
+	}
+
+	return filings, rows.Err()
+}
+
+// GetInsiderTransactions retrieves a stock's insider transactions since a date, newest first
+func (db *DB) GetInsiderTransactions(symbol, since string) ([]*models.InsiderTransaction, error) {
+	rows, err := db.conn.Query("SELECT id, "+strings.Join(insiderTransactionColumns, ", ")+
+		" FROM insider_transactions WHERE symbol = ? AND transaction_date >= ?"+
+		" ORDER BY transaction_date DESC, filed_date DESC, accession_number, line", symbol, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query insider transactions: %w", err)
+	}
+	defer rows.Close()
+
+	transactions := make([]*models.InsiderTransaction, 0)
+	for rows.Next() {
+		t := &models.InsiderTransaction{}
+		err := rows.Scan(
+			&t.ID, &t.Symbol, &t.AccessionNumber, &t.Line, &t.InsiderName, &t.InsiderTitle, &t.IsDirector, &t.IsOfficer,
+			&t.IsTenPercentOwner, &t.SecurityTitle, &t.IsDerivative, &t.TransactionDate, &t.FiledDate,
+			&t.TransactionCode, &t.AcquiredDisposed, &t.Shares, &t.Price, &t.Value, &t.SharesOwnedAfter,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan insider transaction: %w", err)
+		}
+		transactions = append(transactions, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating insider transactions: %w", err)
+	}
+
+	return transactions, nil
+}
@@ -0,0 +1,342 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// CreateJournalTables creates the stock tag and journal entry tables
+func (db *DB) CreateJournalTables() error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS stock_tags (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			stock_id INTEGER NOT NULL,
+			tag TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (stock_id) REFERENCES stocks(id) ON DELETE CASCADE,
+			UNIQUE(stock_id, tag)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_stock_tags_tag ON stock_tags(tag)`,
+		`CREATE TABLE IF NOT EXISTS journal_entries (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			symbol TEXT NOT NULL,
+			setup_id INTEGER,
+			entry_type TEXT NOT NULL DEFAULT 'note' CHECK (entry_type IN ('note', 'entry', 'exit', 'review')),
+			entry_date DATETIME NOT NULL,
+			title TEXT DEFAULT '',
+			body TEXT DEFAULT '',
+			tags TEXT DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_journal_entries_symbol_date ON journal_entries(symbol, entry_date)`,
+		`CREATE INDEX IF NOT EXISTS idx_journal_entries_setup ON journal_entries(setup_id)`,
+	}
+
+	for _, query := range queries {
+		if _, err := db.conn.Exec(query); err != nil {
+			return fmt.Errorf("failed to create journal tables: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Stock Tag Operations
+
+// GetStockTags returns the tags of a stock in alphabetical order
+func (db *DB) GetStockTags(stockID int) ([]string, error) {
+	rows, err := db.conn.Query(`SELECT tag FROM stock_tags WHERE stock_id = ? ORDER BY tag`, stockID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stock tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := make([]string, 0)
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("failed to scan stock tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// AddStockTags adds normalized tags to a stock, ignoring ones it already has
+func (db *DB) AddStockTags(stockID int, tags []string) error {
+	return db.WithTx(func(tx *DB) error {
+		for _, tag := range tags {
+			if _, err := tx.conn.Exec(`INSERT INTO stock_tags (stock_id, tag) VALUES (?, ?)`, stockID, tag); err != nil && !isUniqueViolation(err) {
+				return fmt.Errorf("failed to add stock tag: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// SetStockTags replaces the tags of a stock
+func (db *DB) SetStockTags(stockID int, tags []string) error {
+	return db.WithTx(func(tx *DB) error {
+		if err := tx.RemoveAllStockTags(stockID); err != nil {
+			return err
+		}
+		return tx.AddStockTags(stockID, tags)
+	})
+}
+
+// RemoveStockTag removes a tag from a stock
+func (db *DB) RemoveStockTag(stockID int, tag string) error {
+	if _, err := db.conn.Exec(`DELETE FROM stock_tags WHERE stock_id = ? AND tag = ?`, stockID, tag); err != nil {
+		return fmt.Errorf("failed to remove stock tag: %w", err)
+	}
+	return nil
+}
+
+// RemoveAllStockTags removes every tag of a stock
+func (db *DB) RemoveAllStockTags(stockID int) error {
+	if _, err := db.conn.Exec(`DELETE FROM stock_tags WHERE stock_id = ?`, stockID); err != nil {
+		return fmt.Errorf("failed to remove stock tags: %w", err)
+	}
+	return nil
+}
+
+// GetTagCounts returns every tag in use with the number of stocks carrying it
+func (db *DB) GetTagCounts() ([]models.TagCount, error) {
+	rows, err := db.conn.Query(`SELECT tag, COUNT(*) FROM stock_tags GROUP BY tag ORDER BY tag`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make([]models.TagCount, 0)
+	for rows.Next() {
+		var count models.TagCount
+		if err := rows.Scan(&count.Tag, &count.Stocks); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		counts = append(counts, count)
+	}
+
+	return counts, rows.Err()
+}
+
+// SearchStocks returns the watchlist stocks whose symbol, name, notes or one of whose tags
+// contains the query, case insensitive
+func (db *DB) SearchStocks(q string) ([]models.Stock, error) {
+	pattern := "%" + strings.ToLower(q) + "%"
+	rows, err := db.conn.Query(`SELECT symbol FROM stocks
+		WHERE LOWER(symbol) LIKE ? OR LOWER(name) LIKE ? OR LOWER(notes) LIKE ?
+		   OR id IN (SELECT stock_id FROM stock_tags WHERE tag LIKE ?)
+		ORDER BY symbol`, pattern, pattern, pattern, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search stocks: %w", err)
+	}
+
+	var symbols []string
+	for rows.Next() {
+		var symbol string
+		if err := rows.Scan(&symbol); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan stock: %w", err)
+		}
+		symbols = append(symbols, symbol)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stocks: %w", err)
+	}
+
+	stocks := make([]models.Stock, 0, len(symbols))
+	for _, symbol := range symbols {
+		stock, err := db.GetStockBySymbol(symbol)
+		if err != nil {
+			return nil, err
+		}
+		stocks = append(stocks, *stock)
+	}
+	return stocks, nil
+}
+
+// Journal Entry Operations
+
+const journalEntryColumns = `id, symbol, setup_id, entry_type, entry_date, title, body, tags, created_at, updated_at`
+
+// CreateJournalEntry stores a validated journal entry
+func (db *DB) CreateJournalEntry(entry *models.JournalEntry) error {
+	now := time.Now()
+	if entry.EntryDate.IsZero() {
+		entry.EntryDate = now
+	}
+	entry.CreatedAt = now
+	entry.UpdatedAt = now
+
+	result, err := db.conn.Exec(`INSERT INTO journal_entries (
+			symbol, setup_id, entry_type, entry_date, title, body, tags, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.Symbol, entry.SetupID, entry.EntryType, entry.EntryDate, entry.Title, entry.Body,
+		strings.Join(entry.Tags, ","), entry.CreatedAt, entry.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert journal entry: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+	entry.ID = id
+
+	return nil
+}
+
+// GetJournalEntry returns a journal entry, or nil if it doesn't exist
+func (db *DB) GetJournalEntry(id int64) (*models.JournalEntry, error) {
+	row := db.conn.QueryRow(`SELECT `+journalEntryColumns+` FROM journal_entries WHERE id = ?`, id)
+
+	entry, err := scanJournalEntry(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return entry, err
+}
+
+// UpdateJournalEntry replaces the content of a validated journal entry
+func (db *DB) UpdateJournalEntry(entry *models.JournalEntry) error {
+	entry.UpdatedAt = time.Now()
+
+	result, err := db.conn.Exec(`UPDATE journal_entries
+		SET symbol = ?, setup_id = ?, entry_type = ?, entry_date = ?, title = ?, body = ?, tags = ?, updated_at = ?
+		WHERE id = ?`,
+		entry.Symbol, entry.SetupID, entry.EntryType, entry.EntryDate, entry.Title, entry.Body,
+		strings.Join(entry.Tags, ","), entry.UpdatedAt, entry.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update journal entry: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("journal entry %w", ErrNotFound)
+	}
+
+	return nil
+}
+
+// DeleteJournalEntry deletes a journal entry
+func (db *DB) DeleteJournalEntry(id int64) error {
+	result, err := db.conn.Exec(`DELETE FROM journal_entries WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete journal entry: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("journal entry %w", ErrNotFound)
+	}
+
+	return nil
+}
+
+// GetJournalEntries retrieves journal entries matching a filter, most recent entry date first
+func (db *DB) GetJournalEntries(filter *models.JournalFilter) ([]*models.JournalEntry, error) {
+	query := `SELECT ` + journalEntryColumns + ` FROM journal_entries WHERE 1=1`
+	var args []interface{}
+
+	if filter != nil {
+		if filter.Symbol != "" {
+			query += " AND symbol = ?"
+			args = append(args, strings.ToUpper(filter.Symbol))
+		}
+		if filter.SetupID > 0 {
+			query += " AND setup_id = ?"
+			args = append(args, filter.SetupID)
+		}
+		if filter.EntryType != "" {
+			query += " AND entry_type = ?"
+			args = append(args, filter.EntryType)
+		}
+		if filter.Tag != "" {
+			query += " AND (',' || tags || ',') LIKE ?"
+			args = append(args, "%,"+strings.ToLower(filter.Tag)+",%")
+		}
+		if filter.Query != "" {
+			query += " AND (LOWER(title) LIKE ? OR LOWER(body) LIKE ?)"
+			pattern := "%" + strings.ToLower(filter.Query) + "%"
+			args = append(args, pattern, pattern)
+		}
+		if !filter.From.IsZero() {
+			query += " AND entry_date >= ?"
+			args = append(args, filter.From)
+		}
+		if !filter.To.IsZero() {
+			query += " AND entry_date <= ?"
+			args = append(args, filter.To)
+		}
+	}
+
+	query += " ORDER BY entry_date DESC, id DESC"
+	if filter != nil {
+		query, args = limitOffset(query, args, filter.Limit, 0)
+	}
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query journal entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*models.JournalEntry, 0)
+	for rows.Next() {
+		entry, err := scanJournalEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating journal entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// scanJournalEntry scans a journal entry from a database row
+func scanJournalEntry(row interface{ Scan(...interface{}) error }) (*models.JournalEntry, error) {
+	entry := &models.JournalEntry{}
+	var setupID sql.NullInt64
+	var tags string
+
+	err := row.Scan(
+		&entry.ID, &entry.Symbol, &setupID, &entry.EntryType, &entry.EntryDate,
+		&entry.Title, &entry.Body, &tags, &entry.CreatedAt, &entry.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan journal entry: %w", err)
+	}
+
+	if setupID.Valid {
+		entry.SetupID = &setupID.Int64
+	}
+	entry.Tags = []string{}
+	if tags != "" {
+		entry.Tags = strings.Split(tags, ",")
+	}
+
+	return entry, nil
+}
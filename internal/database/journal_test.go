@@ -0,0 +1,97 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestStockTagsAndJournal tests stock tags, journal entry filters and watchlist search
+func TestStockTagsAndJournal(t *testing.T) {
+	db := newTestDB(t)
+
+	aapl, err := db.AddStock(models.Stock{Symbol: "AAPL", Notes: "Services margin story", Tags: []string{"earnings", "megacap"}})
+	if err != nil {
+		t.Fatalf("AddStock failed: %v", err)
+	}
+	if _, err := db.AddStock(models.Stock{Symbol: "XOM", Tags: []string{"energy"}}); err != nil {
+		t.Fatalf("AddStock failed: %v", err)
+	}
+	if err := db.AddStockTags(aapl.ID, []string{"earnings", "breakout"}); err != nil {
+		t.Fatalf("AddStockTags failed: %v", err)
+	}
+
+	stock, err := db.GetStockBySymbol("AAPL")
+	if err != nil {
+		t.Fatalf("GetStockBySymbol failed: %v", err)
+	}
+	if len(stock.Tags) != 3 || stock.Tags[0] != "breakout" {
+		t.Errorf("expected 3 sorted tags, got %v", stock.Tags)
+	}
+	if err := db.SetStockTags(aapl.ID, []string{"megacap"}); err != nil {
+		t.Fatalf("SetStockTags failed: %v", err)
+	}
+	counts, err := db.GetTagCounts()
+	if err != nil || len(counts) != 2 {
+		t.Errorf("expected energy and megacap in use, got %v (%v)", counts, err)
+	}
+
+	day := time.Date(2025, 6, 2, 15, 0, 0, 0, time.UTC)
+	entries := []*models.JournalEntry{
+		{Symbol: "AAPL", EntryType: models.JournalTypeEntry, EntryDate: day, Title: "Bought the breakout", Tags: []string{"breakout"}},
+		{Symbol: "AAPL", EntryType: models.JournalTypeReview, EntryDate: day.AddDate(0, 0, 5), Body: "Sold into strength too early"},
+		{Symbol: "XOM", EntryDate: day.AddDate(0, 0, 1), Title: "Watching crude inventories"},
+	}
+	for _, entry := range entries {
+		if err := entry.Validate(); err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		if err := db.CreateJournalEntry(entry); err != nil {
+			t.Fatalf("CreateJournalEntry failed: %v", err)
+		}
+	}
+
+	aaplEntries, err := db.GetJournalEntries(&models.JournalFilter{Symbol: "aapl"})
+	if err != nil {
+		t.Fatalf("GetJournalEntries failed: %v", err)
+	}
+	if len(aaplEntries) != 2 || aaplEntries[0].EntryType != models.JournalTypeReview {
+		t.Errorf("expected AAPL entries newest first, got %d", len(aaplEntries))
+	}
+	tagged, _ := db.GetJournalEntries(&models.JournalFilter{Tag: "breakout"})
+	if len(tagged) != 1 || tagged[0].Title != "Bought the breakout" {
+		t.Errorf("expected the breakout entry, got %v", tagged)
+	}
+	ranged, _ := db.GetJournalEntries(&models.JournalFilter{From: day, To: day.AddDate(0, 0, 2)})
+	if len(ranged) != 2 {
+		t.Errorf("expected 2 entries in range, got %d", len(ranged))
+	}
+
+	entries[2].Body = "Inventories drew down"
+	if err := db.UpdateJournalEntry(entries[2]); err != nil {
+		t.Fatalf("UpdateJournalEntry failed: %v", err)
+	}
+	found, _ := db.GetJournalEntries(&models.JournalFilter{Query: "DREW"})
+	if len(found) != 1 || found[0].Symbol != "XOM" {
+		t.Errorf("expected the updated XOM entry, got %v", found)
+	}
+
+	stocks, err := db.SearchStocks("margin")
+	if err != nil || len(stocks) != 1 || stocks[0].Symbol != "AAPL" {
+		t.Errorf("expected AAPL from its notes, got %v (%v)", stocks, err)
+	}
+	if stocks, _ := db.SearchStocks("ENERGY"); len(stocks) != 1 || stocks[0].Symbol != "XOM" {
+		t.Errorf("expected XOM from its tag, got %v", stocks)
+	}
+
+	if err := db.DeleteJournalEntry(entries[0].ID); err != nil {
+		t.Fatalf("DeleteJournalEntry failed: %v", err)
+	}
+	if entry, err := db.GetJournalEntry(entries[0].ID); err != nil || entry != nil {
+		t.Errorf("expected the entry to be gone, got %v (%v)", entry, err)
+	}
+	if err := db.DeleteJournalEntry(entries[0].ID); err == nil {
+		t.Error("expected an error deleting a missing entry")
+	}
+}
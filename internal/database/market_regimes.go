@@ -0,0 +1,149 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"market-watch-go/internal/models"
+)
+
+// marketRegimeColumns are the market_regimes columns in scan order
+var marketRegimeColumns = []string{
+	"symbol", "trade_date", "regime", "close", "sma_50", "adx", "plus_di", "minus_di", "atr_percent", "volatility_ratio",
+}
+
+// UpsertMarketRegimes stores daily regime labels, replacing the days stored before
+func (db *DB) UpsertMarketRegimes(labels []*models.MarketRegimeLabel) error {
+	rows := make([][]interface{}, len(labels))
+	for i, l := range labels {
+		rows[i] = []interface{}{l.Symbol, l.TradeDate, l.Regime, l.Close, l.SMA50, l.ADX, l.PlusDI, l.MinusDI, l.ATRPercent, l.VolatilityRatio}
+	}
+	if err := db.upsertBatch("market_regimes", marketRegimeColumns, marketRegimeColumns[:2], marketRegimeColumns[2:], rows); err != nil {
+		return fmt.Errorf("failed to upsert market regimes: %w", err)
+	}
+	return nil
+}
+
+// GetMarketRegimes retrieves an index's regime labels since a date (YYYY-MM-DD), newest first
+func (db *DB) GetMarketRegimes(symbol, since string) ([]*models.MarketRegimeLabel, error) {
+	rows, err := db.conn.Query("SELECT "+strings.Join(marketRegimeColumns, ", ")+
+		" FROM market_regimes WHERE symbol = ? AND trade_date >= ? ORDER BY trade_date DESC", symbol, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query market regimes: %w", err)
+	}
+	defer rows.Close()
+
+	labels := make([]*models.MarketRegimeLabel, 0)
+	for rows.Next() {
+		l := &models.MarketRegimeLabel{}
+		if err := rows.Scan(&l.Symbol, &l.TradeDate, &l.Regime, &l.Close, &l.SMA50, &l.ADX, &l.PlusDI, &l.MinusDI, &l.ATRPercent, &l.VolatilityRatio); err != nil {
+			return nil, fmt.Errorf("failed to scan market regime: %w", err)
+		}
+		labels = append(labels, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating market regimes: %w", err)
+	}
+
+	return labels, nil
+}
+
+// SetStrategyRegimes stores the regimes a strategy's setups trade in, removing them when neither side declares any
+func (db *DB) SetStrategyRegimes(regimes *models.StrategyRegimes) error {
+	if len(regimes.Long) == 0 && len(regimes.Short) == 0 {
+		return db.DeleteStrategyRegimes(regimes.StrategyID)
+	}
+
+	longJSON, err := json.Marshal(append([]string{}, regimes.Long...))
+	if err != nil {
+		return fmt.Errorf("failed to marshal long regimes: %w", err)
+	}
+	shortJSON, err := json.Marshal(append([]string{}, regimes.Short...))
+	if err != nil {
+		return fmt.Errorf("failed to marshal short regimes: %w", err)
+	}
+
+	columns := []string{"strategy_id", "long_regimes", "short_regimes", "updated_at"}
+	query := upsertQuery("strategy_regimes", columns, columns[:1], columns[1:], 1)
+	if _, err := db.conn.Exec(query, regimes.StrategyID, string(longJSON), string(shortJSON), regimes.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to set regimes of strategy %d: %w", regimes.StrategyID, err)
+	}
+	return nil
+}
+
+// DeleteStrategyRegimes lets a strategy's setups trade in any regime
+func (db *DB) DeleteStrategyRegimes(strategyID int) error {
+	if _, err := db.conn.Exec("DELETE FROM strategy_regimes WHERE strategy_id = ?", strategyID); err != nil {
+		return fmt.Errorf("failed to delete regimes of strategy %d: %w", strategyID, err)
+	}
+	return nil
+}
+
+// GetStrategyRegimes retrieves the regimes a strategy's setups trade in, or nil when it declares none
+func (db *DB) GetStrategyRegimes(strategyID int) (*models.StrategyRegimes, error) {
+	row := db.conn.QueryRow(`
+		SELECT strategy_id, long_regimes, short_regimes, updated_at
+		FROM strategy_regimes
+		WHERE strategy_id = ?
+	`, strategyID)
+	regimes, err := scanStrategyRegimes(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return regimes, err
+}
+
+// GetRegimesForSymbol retrieves the declared regimes of the strategies the symbol's stock belongs to
+func (db *DB) GetRegimesForSymbol(symbol string) ([]*models.StrategyRegimes, error) {
+	rows, err := db.conn.Query(`
+		SELECT sr.strategy_id, sr.long_regimes, sr.short_regimes, sr.updated_at
+		FROM strategy_regimes sr
+		INNER JOIN stock_strategies ss ON ss.strategy_id = sr.strategy_id
+		INNER JOIN stocks s ON s.id = ss.stock_id
+		WHERE s.symbol = ?
+		ORDER BY sr.strategy_id
+	`, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query strategy regimes: %w", err)
+	}
+	defer rows.Close()
+
+	all := make([]*models.StrategyRegimes, 0)
+	for rows.Next() {
+		regimes, err := scanStrategyRegimes(rows)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, regimes)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating strategy regimes: %w", err)
+	}
+
+	return all, nil
+}
+
+// scanStrategyRegimes scans a strategy's declared regimes from a database row
+func scanStrategyRegimes(row interface{ Scan(...interface{}) error }) (*models.StrategyRegimes, error) {
+	regimes := &models.StrategyRegimes{}
+	var longJSON, shortJSON string
+	err := row.Scan(&regimes.StrategyID, &longJSON, &shortJSON, &regimes.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan strategy regimes: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(longJSON), &regimes.Long); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal long regimes: %w", err)
+	}
+	if err := json.Unmarshal([]byte(shortJSON), &regimes.Short); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal short regimes: %w", err)
+	}
+	return regimes, nil
+}
@@ -0,0 +1,202 @@
+package database
+
+import (
+	"embed"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// migrationFiles are the versioned schema changes, named NNNN_description.sql and applied in version order.
+// A change that needs different SQL per driver ships as NNNN_description.sqlite3.sql and
+// NNNN_description.postgres.sql, sharing a version. Applied files must never be edited; ship a new file instead.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migration is an embedded SQL file
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// loadMigrations reads the embedded migrations for a driver, ordered by version
+func loadMigrations(driver string) ([]migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	var migrations []migration
+	seen := make(map[int]string)
+	for _, entry := range entries {
+		prefix, name, ok := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), "_")
+		version, err := strconv.Atoi(prefix)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration file name %q: must be NNNN_description.sql", entry.Name())
+		}
+		if base, only, found := strings.Cut(name, "."); found {
+			if only != DriverSQLite && only != DriverPostgres {
+				return nil, fmt.Errorf("invalid migration file name %q: unknown driver %q", entry.Name(), only)
+			}
+			if only != driver {
+				continue
+			}
+			name = base
+		}
+		if other, exists := seen[version]; exists {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", other, entry.Name(), version)
+		}
+		seen[version] = entry.Name()
+
+		content, err := migrationFiles.ReadFile(path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+		migrations = append(migrations, migration{version: version, name: name, sql: string(content)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+	return migrations, nil
+}
+
+// splitStatements splits a migration into statements ending with a semicolon at the end of a line, dropping
+// comment lines
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
+
+// CreateMigrationTables creates the table recording which schema migrations have been applied
+func (db *DB) CreateMigrationTables() error {
+	_, err := db.conn.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at DATETIME NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create migration tables: %w", err)
+	}
+	return nil
+}
+
+// MigrationStatus lists every embedded migration and whether it has been applied
+func (db *DB) MigrationStatus() ([]*models.Migration, error) {
+	migrations, err := loadMigrations(db.pool.dialect.driver)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.conn.Query(`SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating applied migrations: %w", err)
+	}
+
+	status := make([]*models.Migration, 0, len(migrations))
+	for _, m := range migrations {
+		entry := &models.Migration{Version: m.version, Name: m.name}
+		if appliedAt, ok := applied[m.version]; ok {
+			entry.Applied = true
+			entry.AppliedAt = &appliedAt
+		}
+		status = append(status, entry)
+	}
+	return status, nil
+}
+
+// PendingMigrations lists the migrations not yet applied
+func (db *DB) PendingMigrations() ([]*models.Migration, error) {
+	status, err := db.MigrationStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []*models.Migration
+	for _, m := range status {
+		if !m.Applied {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// Migrate applies the pending migrations in version order, each in its own transaction, and returns the ones applied
+func (db *DB) Migrate() ([]*models.Migration, error) {
+	migrations, err := loadMigrations(db.pool.dialect.driver)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := db.PendingMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.version] = m
+	}
+
+	applied := make([]*models.Migration, 0, len(pending))
+	for _, entry := range pending {
+		m := byVersion[entry.Version]
+		appliedAt := time.Now().UTC()
+
+		err := db.WithTx(func(tx *DB) error {
+			for _, statement := range splitStatements(m.sql) {
+				if _, err := tx.conn.Exec(statement); err != nil {
+					return err
+				}
+			}
+			_, err := tx.conn.Exec(`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+				m.version, m.name, appliedAt)
+			return err
+		})
+		if err != nil {
+			return applied, fmt.Errorf("failed to apply migration %04d_%s: %w", m.version, m.name, err)
+		}
+
+		log.Printf("Applied schema migration %04d_%s", m.version, m.name)
+		entry.Applied = true
+		entry.AppliedAt = &appliedAt
+		applied = append(applied, entry)
+	}
+
+	return applied, nil
+}
@@ -0,0 +1,4 @@
+-- The unique (symbol, timestamp) index on each bar table already serves symbol and time range lookups,
+-- so the plain indexes on the same columns only slow down writes and take up space.
+DROP INDEX IF EXISTS idx_price_data_symbol_timestamp;
+DROP INDEX IF EXISTS idx_volume_data_symbol_timestamp;
@@ -0,0 +1,4 @@
+-- Setups now advance to completed when a target is hit and stopped_out when the stop is hit after entry.
+ALTER TABLE trading_setups DROP CONSTRAINT IF EXISTS trading_setups_status_check;
+ALTER TABLE trading_setups ADD CONSTRAINT trading_setups_status_check
+    CHECK (status IN ('active', 'triggered', 'completed', 'stopped_out', 'expired', 'invalidated'));
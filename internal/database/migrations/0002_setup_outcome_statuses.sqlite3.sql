@@ -0,0 +1,47 @@
+-- Setups now advance to completed when a target is hit and stopped_out when the stop is hit after entry.
+-- SQLite can't alter a CHECK constraint, so the table is rebuilt with the wider status list.
+CREATE TABLE trading_setups_rebuilt (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	symbol TEXT NOT NULL,
+	setup_type TEXT NOT NULL,
+	direction TEXT NOT NULL CHECK (direction IN ('bullish', 'bearish')),
+	quality_score REAL NOT NULL DEFAULT 0,
+	confidence TEXT DEFAULT 'low' CHECK (confidence IN ('high', 'medium', 'low')),
+	status TEXT DEFAULT 'active' CHECK (status IN ('active', 'triggered', 'completed', 'stopped_out', 'expired', 'invalidated')),
+	detected_at DATETIME NOT NULL,
+	expires_at DATETIME NOT NULL,
+	last_updated DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+	current_price REAL NOT NULL,
+	entry_price REAL NOT NULL,
+	stop_loss REAL NOT NULL,
+	target1 REAL DEFAULT 0,
+	target2 REAL DEFAULT 0,
+	target3 REAL DEFAULT 0,
+
+	risk_amount REAL DEFAULT 0,
+	reward_potential REAL DEFAULT 0,
+	risk_reward_ratio REAL DEFAULT 0,
+
+	price_action_score REAL DEFAULT 0,
+	volume_score REAL DEFAULT 0,
+	technical_score REAL DEFAULT 0,
+	risk_reward_score REAL DEFAULT 0,
+
+	notes TEXT DEFAULT '',
+	is_manual BOOLEAN DEFAULT FALSE,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO trading_setups_rebuilt SELECT * FROM trading_setups;
+DROP TABLE trading_setups;
+ALTER TABLE trading_setups_rebuilt RENAME TO trading_setups;
+CREATE INDEX IF NOT EXISTS idx_setups_symbol ON trading_setups(symbol);
+CREATE INDEX IF NOT EXISTS idx_setups_type ON trading_setups(setup_type);
+CREATE INDEX IF NOT EXISTS idx_setups_direction ON trading_setups(direction);
+CREATE INDEX IF NOT EXISTS idx_setups_status ON trading_setups(status);
+CREATE INDEX IF NOT EXISTS idx_setups_quality ON trading_setups(quality_score);
+CREATE INDEX IF NOT EXISTS idx_setups_detected ON trading_setups(detected_at);
+CREATE INDEX IF NOT EXISTS idx_setups_expires ON trading_setups(expires_at);
+CREATE INDEX IF NOT EXISTS idx_setups_symbol_status ON trading_setups(symbol, status);
+CREATE INDEX IF NOT EXISTS idx_setups_symbol_quality ON trading_setups(symbol, quality_score);
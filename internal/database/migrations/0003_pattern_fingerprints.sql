@@ -0,0 +1,10 @@
+-- Detectors look up a formation by its fingerprint (a hash of its key points) before storing it, so rescans
+-- refresh the stored pattern instead of adding a duplicate row. Patterns stored before this have none.
+ALTER TABLE head_shoulders_patterns ADD COLUMN fingerprint TEXT NOT NULL DEFAULT '';
+ALTER TABLE falling_wedge_patterns ADD COLUMN fingerprint TEXT NOT NULL DEFAULT '';
+ALTER TABLE triangle_patterns ADD COLUMN fingerprint TEXT NOT NULL DEFAULT '';
+ALTER TABLE flag_patterns ADD COLUMN fingerprint TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_head_shoulders_patterns_fingerprint ON head_shoulders_patterns(symbol, fingerprint);
+CREATE INDEX IF NOT EXISTS idx_falling_wedge_patterns_fingerprint ON falling_wedge_patterns(symbol, fingerprint);
+CREATE INDEX IF NOT EXISTS idx_triangle_patterns_fingerprint ON triangle_patterns(symbol, fingerprint);
+CREATE INDEX IF NOT EXISTS idx_flag_patterns_fingerprint ON flag_patterns(symbol, fingerprint);
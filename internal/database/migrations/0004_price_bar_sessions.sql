@@ -0,0 +1,3 @@
+-- Bars record the market session (pre_market, regular or post_market) they were traded in, so detectors can
+-- leave extended-hours bars out. Bars stored before this have none and are classified by the calendar on read.
+ALTER TABLE price_data ADD COLUMN session TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,35 @@
+-- Scans and alerts attached to a watchlist strategy, run on a schedule against its member stocks, and the
+-- history of their runs
+CREATE TABLE IF NOT EXISTS strategy_automations (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	strategy_id INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	action TEXT NOT NULL,
+	patterns TEXT NOT NULL DEFAULT '[]',
+	conditions TEXT NOT NULL DEFAULT '[]',
+	logic TEXT NOT NULL DEFAULT '',
+	schedule TEXT NOT NULL,
+	is_active BOOLEAN DEFAULT TRUE,
+	cooldown_minutes INTEGER NOT NULL DEFAULT 0,
+	last_run_at DATETIME,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE IF NOT EXISTS strategy_automation_runs (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	automation_id INTEGER NOT NULL,
+	automation_name TEXT NOT NULL,
+	strategy_id INTEGER NOT NULL,
+	action TEXT NOT NULL,
+	run_trigger TEXT NOT NULL,
+	status TEXT NOT NULL,
+	symbols INTEGER NOT NULL DEFAULT 0,
+	matches TEXT NOT NULL DEFAULT '[]',
+	errors TEXT NOT NULL DEFAULT '{}',
+	error TEXT NOT NULL DEFAULT '',
+	started_at DATETIME NOT NULL,
+	duration_ms REAL NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_strategy_automations_strategy ON strategy_automations(strategy_id);
+CREATE INDEX IF NOT EXISTS idx_strategy_automation_runs_strategy ON strategy_automation_runs(strategy_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_strategy_automation_runs_automation ON strategy_automation_runs(automation_id, started_at);
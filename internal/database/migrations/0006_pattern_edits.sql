@@ -0,0 +1,14 @@
+-- Detected patterns can be corrected by hand. Edited patterns are flagged so rescans keep their key points,
+-- and every edit is kept with the key points it changed.
+ALTER TABLE head_shoulders_patterns ADD COLUMN human_edited BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE head_shoulders_patterns ADD COLUMN annotation TEXT NOT NULL DEFAULT '';
+CREATE TABLE IF NOT EXISTS pattern_edits (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	pattern_type TEXT NOT NULL,
+	pattern_id INTEGER NOT NULL,
+	changes TEXT NOT NULL DEFAULT '{}',
+	note TEXT NOT NULL DEFAULT '',
+	editor TEXT NOT NULL DEFAULT '',
+	edited_at DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_pattern_edits_pattern ON pattern_edits(pattern_type, pattern_id);
@@ -0,0 +1,13 @@
+-- Webhook events that could not be delivered after every retry, kept so they can be inspected and resent
+CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	event_id TEXT NOT NULL,
+	event_type TEXT NOT NULL,
+	symbol TEXT NOT NULL DEFAULT '',
+	url TEXT NOT NULL,
+	payload TEXT NOT NULL,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT NOT NULL DEFAULT '',
+	failed_at DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_dead_letters_failed_at ON webhook_dead_letters(failed_at);
@@ -0,0 +1,30 @@
+-- Strategies whose member stocks' triggered setups place broker orders
+CREATE TABLE IF NOT EXISTS broker_strategies (
+	strategy_id INTEGER PRIMARY KEY,
+	enabled_at DATETIME NOT NULL
+);
+-- Bracket orders placed with the broker for triggered setups, one per setup, synced into the portfolio
+CREATE TABLE IF NOT EXISTS broker_orders (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	setup_id INTEGER NOT NULL UNIQUE,
+	strategy_id INTEGER NOT NULL,
+	position_id INTEGER,
+	broker TEXT NOT NULL,
+	broker_order_id TEXT NOT NULL DEFAULT '',
+	client_order_id TEXT NOT NULL,
+	symbol TEXT NOT NULL,
+	side TEXT NOT NULL,
+	quantity REAL NOT NULL,
+	entry_price REAL NOT NULL,
+	stop_loss REAL NOT NULL,
+	target_price REAL NOT NULL,
+	status TEXT NOT NULL,
+	filled_price REAL NOT NULL DEFAULT 0,
+	filled_at DATETIME,
+	exit_price REAL NOT NULL DEFAULT 0,
+	exit_at DATETIME,
+	error TEXT NOT NULL DEFAULT '',
+	created_at DATETIME NOT NULL,
+	updated_at DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_broker_orders_status ON broker_orders(status);
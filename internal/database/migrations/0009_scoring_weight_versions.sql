@@ -0,0 +1,12 @@
+-- Checklist item weights calibrated from setup outcomes; at most one version is active and used for scoring
+CREATE TABLE IF NOT EXISTS scoring_weight_versions (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	samples INTEGER NOT NULL,
+	wins INTEGER NOT NULL,
+	losses INTEGER NOT NULL,
+	base_win_rate REAL NOT NULL,
+	items TEXT NOT NULL,
+	is_active BOOLEAN NOT NULL DEFAULT FALSE,
+	created_at DATETIME NOT NULL,
+	activated_at DATETIME
+);
@@ -0,0 +1,15 @@
+-- Statistically unusual bars flagged by the anomaly detector, at most one per symbol, type and bar
+CREATE TABLE IF NOT EXISTS market_anomalies (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	symbol TEXT NOT NULL,
+	type TEXT NOT NULL,
+	severity TEXT NOT NULL,
+	bar_time DATETIME NOT NULL,
+	value REAL NOT NULL,
+	threshold REAL NOT NULL,
+	price REAL NOT NULL DEFAULT 0,
+	message TEXT NOT NULL DEFAULT '',
+	detected_at DATETIME NOT NULL,
+	UNIQUE (symbol, type, bar_time)
+);
+CREATE INDEX IF NOT EXISTS idx_market_anomalies_bar_time ON market_anomalies(bar_time);
@@ -0,0 +1,4 @@
+-- Market anomalies are recorded in the notification center under their own category.
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_category_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_category_check
+    CHECK (category IN ('pattern', 'setup', 'alert', 'anomaly', 'system'));
@@ -0,0 +1,18 @@
+-- Market anomalies are recorded in the notification center under their own category.
+-- SQLite can't alter a CHECK constraint, so the table is rebuilt with the wider category list.
+CREATE TABLE notifications_rebuilt (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	category TEXT NOT NULL CHECK (category IN ('pattern', 'setup', 'alert', 'anomaly', 'system')),
+	severity TEXT NOT NULL DEFAULT 'low' CHECK (severity IN ('high', 'medium', 'low')),
+	symbol TEXT NOT NULL DEFAULT '',
+	title TEXT NOT NULL,
+	message TEXT NOT NULL DEFAULT '',
+	link TEXT NOT NULL DEFAULT '',
+	is_read BOOLEAN DEFAULT FALSE,
+	read_at DATETIME,
+	created_at DATETIME NOT NULL
+);
+INSERT INTO notifications_rebuilt SELECT * FROM notifications;
+DROP TABLE notifications;
+ALTER TABLE notifications_rebuilt RENAME TO notifications;
+CREATE INDEX IF NOT EXISTS idx_notifications_read_created ON notifications(is_read, created_at);
@@ -0,0 +1,20 @@
+-- Each symbol's latest daily trading statistics, replaced when recomputed after the close
+CREATE TABLE IF NOT EXISTS symbol_stats (
+	symbol TEXT PRIMARY KEY,
+	trading_date TEXT NOT NULL,
+	days INTEGER NOT NULL,
+	close REAL NOT NULL DEFAULT 0,
+	atr_14 REAL NOT NULL DEFAULT 0,
+	atr_percent REAL NOT NULL DEFAULT 0,
+	avg_range REAL NOT NULL DEFAULT 0,
+	avg_range_percent REAL NOT NULL DEFAULT 0,
+	gap_percent REAL NOT NULL DEFAULT 0,
+	gap_up_days INTEGER NOT NULL DEFAULT 0,
+	gap_down_days INTEGER NOT NULL DEFAULT 0,
+	gap_frequency REAL NOT NULL DEFAULT 0,
+	avg_gap_percent REAL NOT NULL DEFAULT 0,
+	avg_pre_market_volume INTEGER NOT NULL DEFAULT 0,
+	avg_regular_volume INTEGER NOT NULL DEFAULT 0,
+	avg_post_market_volume INTEGER NOT NULL DEFAULT 0,
+	computed_at DATETIME NOT NULL
+);
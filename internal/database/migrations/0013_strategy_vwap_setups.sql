@@ -0,0 +1,8 @@
+-- Strategies whose member stocks get VWAP reclaim and rejection setups
+CREATE TABLE IF NOT EXISTS strategy_vwap_setups (
+	strategy_id INTEGER PRIMARY KEY,
+	reclaim BOOLEAN NOT NULL DEFAULT FALSE,
+	rejection BOOLEAN NOT NULL DEFAULT FALSE,
+	volume_multiplier REAL NOT NULL,
+	updated_at DATETIME NOT NULL
+);
@@ -0,0 +1,16 @@
+-- Mutating API requests with their actor, payload summary and outcome
+CREATE TABLE IF NOT EXISTS audit_log (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	timestamp DATETIME NOT NULL,
+	actor TEXT NOT NULL DEFAULT '',
+	method TEXT NOT NULL,
+	path TEXT NOT NULL,
+	route TEXT NOT NULL DEFAULT '',
+	query TEXT NOT NULL DEFAULT '',
+	payload TEXT NOT NULL DEFAULT '',
+	status_code INTEGER NOT NULL,
+	success BOOLEAN NOT NULL DEFAULT FALSE,
+	error TEXT NOT NULL DEFAULT '',
+	duration_ms INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_audit_log_timestamp ON audit_log(timestamp);
@@ -0,0 +1,17 @@
+-- Setups and patterns are archived instead of deleted so their history stays available for analytics.
+-- Lists leave archived rows out unless asked, and archived rows are purged once past the retention window.
+ALTER TABLE trading_setups ADD COLUMN archived BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE trading_setups ADD COLUMN archived_at DATETIME;
+ALTER TABLE head_shoulders_patterns ADD COLUMN archived BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE head_shoulders_patterns ADD COLUMN archived_at DATETIME;
+ALTER TABLE falling_wedge_patterns ADD COLUMN archived BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE falling_wedge_patterns ADD COLUMN archived_at DATETIME;
+ALTER TABLE triangle_patterns ADD COLUMN archived BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE triangle_patterns ADD COLUMN archived_at DATETIME;
+ALTER TABLE flag_patterns ADD COLUMN archived BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE flag_patterns ADD COLUMN archived_at DATETIME;
+CREATE INDEX IF NOT EXISTS idx_trading_setups_archived ON trading_setups(archived, archived_at);
+CREATE INDEX IF NOT EXISTS idx_head_shoulders_patterns_archived ON head_shoulders_patterns(archived, archived_at);
+CREATE INDEX IF NOT EXISTS idx_falling_wedge_patterns_archived ON falling_wedge_patterns(archived, archived_at);
+CREATE INDEX IF NOT EXISTS idx_triangle_patterns_archived ON triangle_patterns(archived, archived_at);
+CREATE INDEX IF NOT EXISTS idx_flag_patterns_archived ON flag_patterns(archived, archived_at);
@@ -0,0 +1,43 @@
+-- The base schema created support_resistance_levels without the detection columns and no touch or pivot
+-- tables; bring them up to the schema the S/R queries use.
+ALTER TABLE support_resistance_levels ADD COLUMN volume_confirmed BOOLEAN DEFAULT FALSE;
+ALTER TABLE support_resistance_levels ADD COLUMN avg_volume REAL DEFAULT 0;
+ALTER TABLE support_resistance_levels ADD COLUMN max_bounce_percent REAL DEFAULT 0;
+ALTER TABLE support_resistance_levels ADD COLUMN avg_bounce_percent REAL DEFAULT 0;
+ALTER TABLE support_resistance_levels ADD COLUMN timeframe_origin TEXT DEFAULT '1m';
+ALTER TABLE support_resistance_levels ADD COLUMN last_validated DATETIME;
+CREATE INDEX IF NOT EXISTS idx_sr_levels_symbol_type ON support_resistance_levels(symbol, level_type);
+
+CREATE TABLE IF NOT EXISTS pivot_points (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	symbol TEXT NOT NULL,
+	timestamp DATETIME NOT NULL,
+	price REAL NOT NULL,
+	pivot_type TEXT NOT NULL CHECK (pivot_type IN ('high', 'low')),
+	strength INTEGER NOT NULL DEFAULT 1,
+	volume INTEGER NOT NULL DEFAULT 0,
+	confirmed BOOLEAN DEFAULT FALSE,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_pivot_points_symbol_timestamp ON pivot_points(symbol, timestamp);
+
+CREATE TABLE IF NOT EXISTS sr_level_touches (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	level_id INTEGER NOT NULL,
+	symbol TEXT NOT NULL,
+	touch_time DATETIME NOT NULL,
+	touch_price REAL NOT NULL,
+	level REAL NOT NULL,
+	distance_percent REAL NOT NULL,
+	bounce_percent REAL DEFAULT 0,
+	volume_at_touch INTEGER DEFAULT 0,
+	volume_spike BOOLEAN DEFAULT FALSE,
+	bounce_confirmed BOOLEAN DEFAULT FALSE,
+	time_at_level INTEGER DEFAULT 0,
+	touch_type TEXT DEFAULT 'test' CHECK (touch_type IN ('test', 'break', 'bounce')),
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_sr_touches_symbol_time ON sr_level_touches(symbol, touch_time);
+
+-- S/R level breaks are recorded once per level and bar, so rescans of the latest bars don't alert twice
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sr_touches_level_break ON sr_level_touches(level_id, touch_time) WHERE touch_type = 'break';
@@ -0,0 +1,4 @@
+-- S/R levels are clustered pivots or the Fibonacci retracements and extensions of the latest significant swing
+ALTER TABLE support_resistance_levels ADD COLUMN level_class TEXT NOT NULL DEFAULT 'pivot';
+ALTER TABLE support_resistance_levels ADD COLUMN fib_ratio REAL NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_sr_levels_symbol_class ON support_resistance_levels(symbol, level_class, is_active);
@@ -0,0 +1,19 @@
+-- Classic floor trader pivot points of each symbol per session, from the prior day's and prior week's
+-- regular session high, low and close
+CREATE TABLE IF NOT EXISTS floor_pivots (
+	symbol TEXT NOT NULL,
+	period TEXT NOT NULL,
+	trading_date TEXT NOT NULL,
+	high REAL NOT NULL,
+	low REAL NOT NULL,
+	close REAL NOT NULL,
+	pp REAL NOT NULL,
+	r1 REAL NOT NULL,
+	r2 REAL NOT NULL,
+	r3 REAL NOT NULL,
+	s1 REAL NOT NULL,
+	s2 REAL NOT NULL,
+	s3 REAL NOT NULL,
+	computed_at DATETIME NOT NULL,
+	PRIMARY KEY (symbol, period, trading_date)
+);
@@ -0,0 +1,3 @@
+-- Tests and bounces recorded by the live touch pipeline are recorded once per level and bar, so rescans of the
+-- latest bars don't count a touch twice
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sr_touches_level_touch ON sr_level_touches(level_id, touch_time) WHERE touch_type IN ('test', 'bounce');
@@ -0,0 +1,5 @@
+-- Detected setups record the S/R level they trade; detection refreshes the active setup of a type at a level
+-- instead of inserting another one
+ALTER TABLE trading_setups ADD COLUMN key_level_id INTEGER;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_setups_active_key_level ON trading_setups(symbol, setup_type, key_level_id)
+	WHERE status = 'active' AND archived = FALSE AND key_level_id IS NOT NULL;
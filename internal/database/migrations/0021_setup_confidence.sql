@@ -0,0 +1,3 @@
+-- Setups keep the confidence score their confidence level was derived from and each factor's contribution
+ALTER TABLE trading_setups ADD COLUMN confidence_score REAL DEFAULT 0;
+ALTER TABLE trading_setups ADD COLUMN confidence_factors TEXT;
@@ -0,0 +1,2 @@
+-- Setups keep the breakdown of their quality score into checklist item points and adjustments
+ALTER TABLE trading_setups ADD COLUMN score_breakdown TEXT;
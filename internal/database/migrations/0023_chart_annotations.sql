@@ -0,0 +1,22 @@
+-- Chart drawings and layout preferences, saved per user and symbol so the dashboard can restore them
+CREATE TABLE IF NOT EXISTS chart_annotations (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_name TEXT NOT NULL DEFAULT '',
+	symbol TEXT NOT NULL,
+	annotation_type TEXT NOT NULL,
+	timeframe TEXT NOT NULL DEFAULT '',
+	points TEXT NOT NULL DEFAULT '[]',
+	price REAL NOT NULL DEFAULT 0,
+	text TEXT NOT NULL DEFAULT '',
+	color TEXT NOT NULL DEFAULT '',
+	created_at DATETIME NOT NULL,
+	updated_at DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_chart_annotations_user_symbol ON chart_annotations(user_name, symbol);
+CREATE TABLE IF NOT EXISTS chart_layouts (
+	user_name TEXT NOT NULL DEFAULT '',
+	symbol TEXT NOT NULL,
+	layout TEXT NOT NULL DEFAULT '{}',
+	updated_at DATETIME NOT NULL,
+	PRIMARY KEY (user_name, symbol)
+);
@@ -0,0 +1,21 @@
+-- FINRA daily short volume and bi-monthly short interest per symbol, and the setup checklist signal they raise
+CREATE TABLE IF NOT EXISTS short_volume (
+	symbol TEXT NOT NULL,
+	trade_date TEXT NOT NULL,
+	short_volume INTEGER NOT NULL DEFAULT 0,
+	short_exempt_volume INTEGER NOT NULL DEFAULT 0,
+	total_volume INTEGER NOT NULL DEFAULT 0,
+	short_volume_ratio REAL NOT NULL DEFAULT 0,
+	PRIMARY KEY (symbol, trade_date)
+);
+CREATE INDEX IF NOT EXISTS idx_short_volume_trade_date ON short_volume(trade_date);
+CREATE TABLE IF NOT EXISTS short_interest (
+	symbol TEXT NOT NULL,
+	settlement_date TEXT NOT NULL,
+	short_interest INTEGER NOT NULL DEFAULT 0,
+	avg_daily_volume INTEGER NOT NULL DEFAULT 0,
+	days_to_cover REAL NOT NULL DEFAULT 0,
+	PRIMARY KEY (symbol, settlement_date)
+);
+ALTER TABLE setup_checklists ADD COLUMN short_interest_completed BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE setup_checklists ADD COLUMN short_interest_value TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,31 @@
+-- SEC Form 4 insider transactions of watched stocks, and the filings already fetched
+CREATE TABLE IF NOT EXISTS insider_transactions (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	symbol TEXT NOT NULL,
+	accession_number TEXT NOT NULL,
+	line INTEGER NOT NULL,
+	insider_name TEXT NOT NULL DEFAULT '',
+	insider_title TEXT NOT NULL DEFAULT '',
+	is_director BOOLEAN NOT NULL DEFAULT FALSE,
+	is_officer BOOLEAN NOT NULL DEFAULT FALSE,
+	is_ten_percent_owner BOOLEAN NOT NULL DEFAULT FALSE,
+	security_title TEXT NOT NULL DEFAULT '',
+	is_derivative BOOLEAN NOT NULL DEFAULT FALSE,
+	transaction_date TEXT NOT NULL,
+	filed_date TEXT NOT NULL,
+	transaction_code TEXT NOT NULL DEFAULT '',
+	acquired_disposed TEXT NOT NULL DEFAULT '',
+	shares REAL NOT NULL DEFAULT 0,
+	price REAL NOT NULL DEFAULT 0,
+	value REAL NOT NULL DEFAULT 0,
+	shares_owned_after REAL NOT NULL DEFAULT 0,
+	UNIQUE (symbol, accession_number, line)
+);
+CREATE INDEX IF NOT EXISTS idx_insider_transactions_symbol_date ON insider_transactions(symbol, transaction_date);
+CREATE TABLE IF NOT EXISTS insider_filings (
+	symbol TEXT NOT NULL,
+	accession_number TEXT NOT NULL,
+	filed_date TEXT NOT NULL,
+	fetched_at DATETIME NOT NULL,
+	PRIMARY KEY (symbol, accession_number)
+);
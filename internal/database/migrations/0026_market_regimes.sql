@@ -0,0 +1,20 @@
+-- Daily market regime labels of the regime index, and the regimes watchlist strategies' setups trade in
+CREATE TABLE IF NOT EXISTS market_regimes (
+	symbol TEXT NOT NULL,
+	trade_date TEXT NOT NULL,
+	regime TEXT NOT NULL,
+	close REAL NOT NULL DEFAULT 0,
+	sma_50 REAL NOT NULL DEFAULT 0,
+	adx REAL NOT NULL DEFAULT 0,
+	plus_di REAL NOT NULL DEFAULT 0,
+	minus_di REAL NOT NULL DEFAULT 0,
+	atr_percent REAL NOT NULL DEFAULT 0,
+	volatility_ratio REAL NOT NULL DEFAULT 0,
+	PRIMARY KEY (symbol, trade_date)
+);
+CREATE TABLE IF NOT EXISTS strategy_regimes (
+	strategy_id INTEGER PRIMARY KEY,
+	long_regimes TEXT NOT NULL DEFAULT '[]',
+	short_regimes TEXT NOT NULL DEFAULT '[]',
+	updated_at DATETIME NOT NULL
+);
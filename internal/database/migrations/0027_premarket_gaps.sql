@@ -0,0 +1,16 @@
+-- Pre-market gaps of watched symbols ranked by the morning gap scan
+CREATE TABLE IF NOT EXISTS premarket_gaps (
+	trade_date TEXT NOT NULL,
+	symbol TEXT NOT NULL,
+	rank INTEGER NOT NULL DEFAULT 0,
+	prev_close REAL NOT NULL DEFAULT 0,
+	pre_market_price REAL NOT NULL DEFAULT 0,
+	gap_percent REAL NOT NULL DEFAULT 0,
+	pre_market_high REAL NOT NULL DEFAULT 0,
+	pre_market_low REAL NOT NULL DEFAULT 0,
+	pre_market_volume INTEGER NOT NULL DEFAULT 0,
+	rvol REAL NOT NULL DEFAULT 0,
+	gapper BOOLEAN NOT NULL DEFAULT 0,
+	scanned_at DATETIME NOT NULL,
+	PRIMARY KEY (trade_date, symbol)
+);
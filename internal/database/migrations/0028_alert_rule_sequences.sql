@@ -0,0 +1,11 @@
+-- Alert rules can sequence legs after their conditions, with each rule's progress per symbol kept across restarts
+ALTER TABLE alert_rules ADD COLUMN steps TEXT NOT NULL DEFAULT '[]';
+CREATE TABLE IF NOT EXISTS alert_rule_states (
+	rule_id INTEGER NOT NULL,
+	symbol TEXT NOT NULL,
+	step INTEGER NOT NULL DEFAULT 0,
+	started_at DATETIME NOT NULL,
+	step_at DATETIME NOT NULL,
+	field_values TEXT NOT NULL DEFAULT '{}',
+	PRIMARY KEY (rule_id, symbol)
+);
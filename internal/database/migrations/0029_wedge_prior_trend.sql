@@ -0,0 +1,2 @@
+-- The first wedge thesis component is the trend before the wedge, a downtrend or an uptrend for rising wedges
+UPDATE falling_wedge_patterns SET thesis_components = REPLACE(thesis_components, '"downtrend_established":', '"prior_trend_established":');
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"market-watch-go/internal/config"
+)
+
+// TestMigrate tests that migrations are applied once, in order, and recorded
+func TestMigrate(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Database.Path = filepath.Join(t.TempDir(), "migrate.db")
+	cfg.Database.ManualMigrations = true
+	db, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer db.Close()
+
+	pending, err := db.PendingMigrations()
+	if err != nil || len(pending) == 0 || pending[0].Version != 1 {
+		t.Fatalf("expected pending migrations starting at version 1, got %+v (%v)", pending, err)
+	}
+
+	applied, err := db.Migrate()
+	if err != nil || len(applied) != len(pending) {
+		t.Fatalf("expected %d migrations applied, got %d (%v)", len(pending), len(applied), err)
+	}
+	for i := 1; i < len(applied); i++ {
+		if applied[i].Version <= applied[i-1].Version {
+			t.Errorf("migrations applied out of order: %d then %d", applied[i-1].Version, applied[i].Version)
+		}
+	}
+
+	var indexes int
+	if err := db.conn.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'idx_price_data_symbol_timestamp'`).Scan(&indexes); err != nil || indexes != 0 {
+		t.Errorf("expected the redundant bar index to be dropped, found %d (%v)", indexes, err)
+	}
+
+	status, err := db.MigrationStatus()
+	if err != nil {
+		t.Fatalf("MigrationStatus failed: %v", err)
+	}
+	for _, m := range status {
+		if !m.Applied || m.AppliedAt == nil {
+			t.Errorf("expected migration %d to be recorded as applied", m.Version)
+		}
+	}
+
+	// Nothing is left to apply
+	if again, err := db.Migrate(); err != nil || len(again) != 0 {
+		t.Errorf("expected no migrations on the second run, got %d (%v)", len(again), err)
+	}
+}
+
+// TestSplitStatements tests splitting a migration script into statements
+func TestSplitStatements(t *testing.T) {
+	script := "-- comment\nALTER TABLE a ADD COLUMN b TEXT;\n\nCREATE INDEX i\n  ON a(b);\nDROP TABLE c"
+	statements := splitStatements(script)
+	if len(statements) != 3 || statements[0] != "ALTER TABLE a ADD COLUMN b TEXT" || statements[1] != "CREATE INDEX i\n  ON a(b)" || statements[2] != "DROP TABLE c" {
+		t.Errorf("unexpected statements: %q", statements)
+	}
+}
+
+// TestLoadMigrationsPerDriver tests that driver-specific migrations are only loaded for their driver
+func TestLoadMigrationsPerDriver(t *testing.T) {
+	for _, driver := range []string{DriverSQLite, DriverPostgres} {
+		migrations, err := loadMigrations(driver)
+		if err != nil {
+			t.Fatalf("%s: loadMigrations failed: %v", driver, err)
+		}
+		var outcomes []migration
+		for _, m := range migrations {
+			if m.version == 2 {
+				outcomes = append(outcomes, m)
+			}
+		}
+		if len(outcomes) != 1 || outcomes[0].name != "setup_outcome_statuses" {
+			t.Fatalf("%s: expected one setup_outcome_statuses migration, got %+v", driver, outcomes)
+		}
+		if rebuilds := strings.Contains(outcomes[0].sql, "trading_setups_rebuilt"); rebuilds != (driver == DriverSQLite) {
+			t.Errorf("%s: expected the table rebuild only on SQLite", driver)
+		}
+	}
+}
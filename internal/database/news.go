@@ -0,0 +1,130 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// CreateNewsTables creates the news articles table
+func (db *DB) CreateNewsTables() error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS news_articles (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			symbol TEXT NOT NULL,
+			article_id TEXT NOT NULL,
+			title TEXT NOT NULL,
+			author TEXT DEFAULT '',
+			publisher TEXT DEFAULT '',
+			url TEXT DEFAULT '',
+			image_url TEXT DEFAULT '',
+			description TEXT DEFAULT '',
+			keywords TEXT DEFAULT '',
+			published_at DATETIME NOT NULL,
+			sentiment TEXT NOT NULL DEFAULT 'neutral' CHECK (sentiment IN ('positive', 'negative', 'neutral')),
+			sentiment_score REAL DEFAULT 0,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(symbol, article_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_news_articles_symbol_published ON news_articles(symbol, published_at)`,
+	}
+
+	for _, query := range queries {
+		if _, err := db.conn.Exec(query); err != nil {
+			return fmt.Errorf("failed to create news tables: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// UpsertNewsArticle inserts a news article or refreshes an existing one for the same symbol
+func (db *DB) UpsertNewsArticle(article *models.NewsArticle) error {
+	if article.CreatedAt.IsZero() {
+		article.CreatedAt = time.Now()
+	}
+
+	query := `
+		INSERT OR REPLACE INTO news_articles (
+			symbol, article_id, title, author, publisher, url, image_url, description, keywords,
+			published_at, sentiment, sentiment_score, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := db.conn.Exec(query,
+		article.Symbol, article.ArticleID, article.Title, article.Author, article.Publisher, article.URL,
+		article.ImageURL, article.Description, article.Keywords, article.PublishedAt, article.Sentiment,
+		article.SentimentScore, article.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert news article: %w", err)
+	}
+
+	return nil
+}
+
+// GetNewsArticles retrieves news articles, newest first
+func (db *DB) GetNewsArticles(filter *models.NewsFilter) ([]*models.NewsArticle, error) {
+	query := `SELECT id, symbol, article_id, title, author, publisher, url, image_url, description, keywords,
+		published_at, sentiment, sentiment_score, created_at
+		FROM news_articles WHERE 1=1`
+	args := []interface{}{}
+
+	if filter != nil {
+		if filter.Symbol != "" {
+			query += " AND symbol = ?"
+			args = append(args, filter.Symbol)
+		}
+		if filter.Sentiment != "" {
+			query += " AND sentiment = ?"
+			args = append(args, filter.Sentiment)
+		}
+		if !filter.Since.IsZero() {
+			query += " AND published_at >= ?"
+			args = append(args, filter.Since)
+		}
+	}
+
+	query += " ORDER BY published_at DESC"
+
+	if filter != nil && filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query news articles: %w", err)
+	}
+	defer rows.Close()
+
+	articles := make([]*models.NewsArticle, 0)
+	for rows.Next() {
+		article := &models.NewsArticle{}
+		err := rows.Scan(
+			&article.ID, &article.Symbol, &article.ArticleID, &article.Title, &article.Author, &article.Publisher,
+			&article.URL, &article.ImageURL, &article.Description, &article.Keywords, &article.PublishedAt,
+			&article.Sentiment, &article.SentimentScore, &article.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan news article: %w", err)
+		}
+		articles = append(articles, article)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating news articles: %w", err)
+	}
+
+	return articles, nil
+}
+
+// DeleteNewsArticlesBefore removes articles published before the cutoff
+func (db *DB) DeleteNewsArticlesBefore(cutoff time.Time) (int64, error) {
+	result, err := db.conn.Exec("DELETE FROM news_articles WHERE published_at < ?", cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old news articles: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,190 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// CreateNotificationTables creates the notification center table
+func (db *DB) CreateNotificationTables() error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS notifications (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			category TEXT NOT NULL CHECK (category IN ('pattern', 'setup', 'alert', 'anomaly', 'system')),
+			severity TEXT NOT NULL DEFAULT 'low' CHECK (severity IN ('high', 'medium', 'low')),
+			symbol TEXT NOT NULL DEFAULT '',
+			title TEXT NOT NULL,
+			message TEXT NOT NULL DEFAULT '',
+			link TEXT NOT NULL DEFAULT '',
+			is_read BOOLEAN DEFAULT FALSE,
+			read_at DATETIME,
+			created_at DATETIME NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_notifications_read_created ON notifications(is_read, created_at)`,
+	}
+
+	for _, query := range queries {
+		if _, err := db.conn.Exec(query); err != nil {
+			return fmt.Errorf("failed to create notification tables: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// InsertNotification stores a new unread notification
+func (db *DB) InsertNotification(notification *models.Notification) error {
+	if notification.CreatedAt.IsZero() {
+		notification.CreatedAt = time.Now()
+	}
+
+	result, err := db.conn.Exec(`
+		INSERT INTO notifications (category, severity, symbol, title, message, link, is_read, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, FALSE, ?)`,
+		notification.Category, notification.Severity, notification.Symbol, notification.Title,
+		notification.Message, notification.Link, notification.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert notification: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	notification.ID = id
+	notification.IsRead = false
+	return nil
+}
+
+// GetNotifications retrieves notifications, newest first
+func (db *DB) GetNotifications(filter *models.NotificationFilter) ([]*models.Notification, error) {
+	query := `SELECT id, category, severity, symbol, title, message, link, is_read, read_at, created_at
+		FROM notifications WHERE 1=1`
+	args := []interface{}{}
+
+	limit := 50
+	offset := 0
+	if filter != nil {
+		if filter.Category != "" {
+			query += " AND category = ?"
+			args = append(args, filter.Category)
+		}
+		if filter.UnreadOnly {
+			query += " AND is_read = FALSE"
+		}
+		if filter.Limit > 0 {
+			limit = filter.Limit
+		}
+		offset = filter.Offset
+	}
+
+	query += " ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notifications: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := make([]*models.Notification, 0)
+	for rows.Next() {
+		notification := &models.Notification{}
+		var readAt sql.NullTime
+		err := rows.Scan(
+			&notification.ID, &notification.Category, &notification.Severity, &notification.Symbol,
+			&notification.Title, &notification.Message, &notification.Link, &notification.IsRead,
+			&readAt, &notification.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		if readAt.Valid {
+			notification.ReadAt = &readAt.Time
+		}
+		notifications = append(notifications, notification)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+// CountUnreadNotifications counts the unread notifications, optionally of one category
+func (db *DB) CountUnreadNotifications(category string) (int, error) {
+	query := "SELECT COUNT(*) FROM notifications WHERE is_read = FALSE"
+	args := []interface{}{}
+	if category != "" {
+		query += " AND category = ?"
+		args = append(args, category)
+	}
+
+	var count int
+	if err := db.conn.QueryRow(query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+// MarkNotificationRead marks a notification read; it returns false when the notification doesn't exist
+func (db *DB) MarkNotificationRead(id int64) (bool, error) {
+	result, err := db.conn.Exec(
+		"UPDATE notifications SET is_read = TRUE, read_at = COALESCE(read_at, ?) WHERE id = ?",
+		time.Now(), id,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark notification read: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// MarkAllNotificationsRead marks every unread notification, optionally of one category, read
+func (db *DB) MarkAllNotificationsRead(category string) (int64, error) {
+	query := "UPDATE notifications SET is_read = TRUE, read_at = ? WHERE is_read = FALSE"
+	args := []interface{}{time.Now()}
+	if category != "" {
+		query += " AND category = ?"
+		args = append(args, category)
+	}
+
+	result, err := db.conn.Exec(query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// DeleteNotifications clears the notification center, or only its read notifications
+func (db *DB) DeleteNotifications(readOnly bool) (int64, error) {
+	query := "DELETE FROM notifications"
+	if readOnly {
+		query += " WHERE is_read = TRUE"
+	}
+
+	result, err := db.conn.Exec(query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete notifications: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// DeleteNotificationsBefore removes notifications created before the cutoff
+func (db *DB) DeleteNotificationsBefore(cutoff time.Time) (int64, error) {
+	result, err := db.conn.Exec("DELETE FROM notifications WHERE created_at < ?", cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old notifications: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestNotifications tests the unread count, marking notifications read and clearing read ones
+func TestNotifications(t *testing.T) {
+	db := newTestDB(t)
+
+	now := time.Now()
+	for i, category := range []string{models.NotificationPattern, models.NotificationSetup, models.NotificationSystem} {
+		err := db.InsertNotification(&models.Notification{
+			Category:  category,
+			Severity:  "medium",
+			Title:     category,
+			CreatedAt: now.Add(time.Duration(i) * time.Minute),
+		})
+		if err != nil {
+			t.Fatalf("InsertNotification failed: %v", err)
+		}
+	}
+
+	notifications, err := db.GetNotifications(nil)
+	if err != nil || len(notifications) != 3 || notifications[0].Category != models.NotificationSystem {
+		t.Fatalf("expected 3 notifications newest first, got %v (%v)", notifications, err)
+	}
+
+	if found, err := db.MarkNotificationRead(notifications[0].ID); err != nil || !found {
+		t.Fatalf("MarkNotificationRead failed: %v", err)
+	}
+	if found, _ := db.MarkNotificationRead(9999); found {
+		t.Error("expected a missing notification not to be found")
+	}
+	if unread, _ := db.CountUnreadNotifications(""); unread != 2 {
+		t.Errorf("expected 2 unread notifications, got %d", unread)
+	}
+
+	unread, err := db.GetNotifications(&models.NotificationFilter{UnreadOnly: true, Category: models.NotificationSetup})
+	if err != nil || len(unread) != 1 || unread[0].IsRead || unread[0].ReadAt != nil {
+		t.Errorf("expected the unread setup notification, got %v (%v)", unread, err)
+	}
+
+	if marked, _ := db.MarkAllNotificationsRead(models.NotificationPattern); marked != 1 {
+		t.Errorf("expected 1 pattern notification marked read, got %d", marked)
+	}
+	if deleted, _ := db.DeleteNotifications(true); deleted != 2 {
+		t.Errorf("expected 2 read notifications deleted, got %d", deleted)
+	}
+	if remaining, _ := db.GetNotifications(nil); len(remaining) != 1 || remaining[0].Category != models.NotificationSetup {
+		t.Errorf("expected only the unread setup notification to remain, got %v", remaining)
+	}
+}
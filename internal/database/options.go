@@ -0,0 +1,184 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// CreateOptionsTables creates the options snapshot and unusual activity tables
+func (db *DB) CreateOptionsTables() error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS options_snapshots (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			symbol TEXT NOT NULL,
+			timestamp DATETIME NOT NULL,
+			underlying_price REAL DEFAULT 0,
+			call_volume INTEGER DEFAULT 0,
+			put_volume INTEGER DEFAULT 0,
+			call_open_interest INTEGER DEFAULT 0,
+			put_open_interest INTEGER DEFAULT 0,
+			put_call_volume_ratio REAL DEFAULT 0,
+			put_call_oi_ratio REAL DEFAULT 0,
+			avg_implied_volatility REAL DEFAULT 0,
+			atm_implied_volatility REAL DEFAULT 0,
+			contract_count INTEGER DEFAULT 0,
+			unusual_count INTEGER DEFAULT 0
+		)`,
+		`CREATE TABLE IF NOT EXISTS options_unusual_activity (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			snapshot_id INTEGER NOT NULL,
+			symbol TEXT NOT NULL,
+			contract_ticker TEXT NOT NULL,
+			contract_type TEXT NOT NULL CHECK (contract_type IN ('call', 'put')),
+			strike_price REAL NOT NULL,
+			expiration_date DATETIME NOT NULL,
+			volume INTEGER DEFAULT 0,
+			open_interest INTEGER DEFAULT 0,
+			volume_oi_ratio REAL DEFAULT 0,
+			implied_volatility REAL DEFAULT 0,
+			timestamp DATETIME NOT NULL,
+			FOREIGN KEY (snapshot_id) REFERENCES options_snapshots(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_options_snapshots_symbol_time ON options_snapshots(symbol, timestamp)`,
+		`CREATE INDEX IF NOT EXISTS idx_options_unusual_symbol_time ON options_unusual_activity(symbol, timestamp)`,
+	}
+
+	for _, query := range queries {
+		if _, err := db.conn.Exec(query); err != nil {
+			return fmt.Errorf("failed to create options tables: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// InsertOptionsSnapshot inserts an options snapshot along with its unusual contracts
+func (db *DB) InsertOptionsSnapshot(snapshot *models.OptionsSnapshot) error {
+	return db.WithTx(func(tx *DB) error {
+		result, err := tx.conn.Exec(`
+			INSERT INTO options_snapshots (
+				symbol, timestamp, underlying_price, call_volume, put_volume, call_open_interest, put_open_interest,
+				put_call_volume_ratio, put_call_oi_ratio, avg_implied_volatility, atm_implied_volatility,
+				contract_count, unusual_count
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			snapshot.Symbol, snapshot.Timestamp, snapshot.UnderlyingPrice, snapshot.CallVolume, snapshot.PutVolume,
+			snapshot.CallOpenInterest, snapshot.PutOpenInterest, snapshot.PutCallVolumeRatio, snapshot.PutCallOIRatio,
+			snapshot.AvgImpliedVolatility, snapshot.ATMImpliedVolatility, snapshot.ContractCount, snapshot.UnusualCount,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert options snapshot: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+		snapshot.ID = id
+
+		for _, activity := range snapshot.UnusualContracts {
+			activity.SnapshotID = id
+			_, err := tx.conn.Exec(`
+				INSERT INTO options_unusual_activity (
+					snapshot_id, symbol, contract_ticker, contract_type, strike_price, expiration_date,
+					volume, open_interest, volume_oi_ratio, implied_volatility, timestamp
+				) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				activity.SnapshotID, activity.Symbol, activity.ContractTicker, activity.ContractType, activity.StrikePrice,
+				activity.ExpirationDate, activity.Volume, activity.OpenInterest, activity.VolumeOIRatio,
+				activity.ImpliedVolatility, activity.Timestamp,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to insert unusual option activity: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetOptionsSnapshots retrieves options snapshots for a symbol since a point in time, oldest first
+func (db *DB) GetOptionsSnapshots(symbol string, since time.Time) ([]*models.OptionsSnapshot, error) {
+	rows, err := db.conn.Query(`
+		SELECT id, symbol, timestamp, underlying_price, call_volume, put_volume, call_open_interest,
+			put_open_interest, put_call_volume_ratio, put_call_oi_ratio, avg_implied_volatility,
+			atm_implied_volatility, contract_count, unusual_count
+		FROM options_snapshots
+		WHERE symbol = ? AND timestamp >= ?
+		ORDER BY timestamp ASC`,
+		symbol, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query options snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := make([]*models.OptionsSnapshot, 0)
+	for rows.Next() {
+		s := &models.OptionsSnapshot{}
+		err := rows.Scan(
+			&s.ID, &s.Symbol, &s.Timestamp, &s.UnderlyingPrice, &s.CallVolume, &s.PutVolume, &s.CallOpenInterest,
+			&s.PutOpenInterest, &s.PutCallVolumeRatio, &s.PutCallOIRatio, &s.AvgImpliedVolatility,
+			&s.ATMImpliedVolatility, &s.ContractCount, &s.UnusualCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan options snapshot: %w", err)
+		}
+		snapshots = append(snapshots, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating options snapshots: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+// GetUnusualOptionActivity retrieves unusual contracts for a symbol since a point in time, newest first
+func (db *DB) GetUnusualOptionActivity(symbol string, since time.Time, limit int) ([]*models.UnusualOptionActivity, error) {
+	rows, err := db.conn.Query(`
+		SELECT id, snapshot_id, symbol, contract_ticker, contract_type, strike_price, expiration_date,
+			volume, open_interest, volume_oi_ratio, implied_volatility, timestamp
+		FROM options_unusual_activity
+		WHERE symbol = ? AND timestamp >= ?
+		ORDER BY timestamp DESC, volume_oi_ratio DESC
+		LIMIT ?`,
+		symbol, since, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unusual option activity: %w", err)
+	}
+	defer rows.Close()
+
+	activity := make([]*models.UnusualOptionActivity, 0)
+	for rows.Next() {
+		a := &models.UnusualOptionActivity{}
+		err := rows.Scan(
+			&a.ID, &a.SnapshotID, &a.Symbol, &a.ContractTicker, &a.ContractType, &a.StrikePrice, &a.ExpirationDate,
+			&a.Volume, &a.OpenInterest, &a.VolumeOIRatio, &a.ImpliedVolatility, &a.Timestamp,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan unusual option activity: %w", err)
+		}
+		activity = append(activity, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating unusual option activity: %w", err)
+	}
+
+	return activity, nil
+}
+
+// DeleteOptionsDataBefore removes options snapshots and unusual activity older than the cutoff
+func (db *DB) DeleteOptionsDataBefore(cutoff time.Time) (int64, error) {
+	if _, err := db.conn.Exec("DELETE FROM options_unusual_activity WHERE timestamp < ?", cutoff); err != nil {
+		return 0, fmt.Errorf("failed to delete old unusual option activity: %w", err)
+	}
+
+	result, err := db.conn.Exec("DELETE FROM options_snapshots WHERE timestamp < ?", cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old options snapshots: %w", err)
+	}
+	return result.RowsAffected()
+}
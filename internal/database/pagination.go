@@ -0,0 +1,43 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+)
+
+// orderBy builds an ORDER BY clause for a whitelisted sort field, falling back to the default
+// ordering when sort is empty or unknown. The id tie-break keeps pages stable.
+func orderBy(sort, order string, fields []string, defaultOrder string) string {
+	for _, field := range fields {
+		if field == sort {
+			direction := "DESC"
+			if strings.EqualFold(order, "asc") {
+				direction = "ASC"
+			}
+			return " ORDER BY " + field + " " + direction + ", id " + direction
+		}
+	}
+	return " ORDER BY " + defaultOrder
+}
+
+// limitOffset appends LIMIT and OFFSET clauses to a query
+func limitOffset(query string, args []interface{}, limit, offset int) (string, []interface{}) {
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	if offset > 0 {
+		query += " OFFSET ?"
+		args = append(args, offset)
+	}
+	return query, args
+}
+
+// countRows counts the rows of a table matching a WHERE clause built for a list query
+func (db *DB) countRows(table, where string, args []interface{}) (int, error) {
+	var count int
+	if err := db.conn.QueryRow("SELECT COUNT(*) FROM "+table+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count %s: %w", table, err)
+	}
+	return count, nil
+}
@@ -0,0 +1,252 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// CreatePaperTradingTables creates the paper trade and equity curve tables
+func (db *DB) CreatePaperTradingTables() error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS paper_trades (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			symbol TEXT NOT NULL,
+			setup_type TEXT NOT NULL,
+			side TEXT NOT NULL CHECK (side IN ('long', 'short')),
+			quality_score REAL DEFAULT 0,
+			entry_price REAL NOT NULL,
+			stop_loss REAL DEFAULT 0,
+			target_price REAL DEFAULT 0,
+			quantity REAL NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'open', 'closed', 'expired', 'cancelled')),
+			exit_price REAL,
+			exit_reason TEXT DEFAULT '',
+			realized_pnl REAL DEFAULT 0,
+			expires_at DATETIME NOT NULL,
+			filled_at DATETIME,
+			closed_at DATETIME,
+			last_checked_at DATETIME NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS paper_equity (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			timestamp DATETIME NOT NULL,
+			equity REAL NOT NULL,
+			cash REAL NOT NULL,
+			unrealized_pnl REAL DEFAULT 0,
+			open_trades INTEGER DEFAULT 0
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_paper_trades_status ON paper_trades(status)`,
+		`CREATE INDEX IF NOT EXISTS idx_paper_trades_symbol ON paper_trades(symbol)`,
+		`CREATE INDEX IF NOT EXISTS idx_paper_equity_timestamp ON paper_equity(timestamp)`,
+	}
+
+	for _, query := range queries {
+		if _, err := db.conn.Exec(query); err != nil {
+			return fmt.Errorf("failed to create paper trading tables: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// InsertPaperTrade inserts a new paper trade
+func (db *DB) InsertPaperTrade(trade *models.PaperTrade) error {
+	now := time.Now()
+	trade.CreatedAt = now
+	trade.UpdatedAt = now
+
+	query := `
+		INSERT INTO paper_trades (
+			symbol, setup_type, side, quality_score, entry_price, stop_loss, target_price, quantity,
+			status, exit_price, exit_reason, realized_pnl, expires_at, filled_at, closed_at,
+			last_checked_at, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := db.conn.Exec(query,
+		trade.Symbol, trade.SetupType, trade.Side, trade.QualityScore, trade.EntryPrice, trade.StopLoss,
+		trade.TargetPrice, trade.Quantity, trade.Status, trade.ExitPrice, trade.ExitReason, trade.RealizedPnL,
+		trade.ExpiresAt, trade.FilledAt, trade.ClosedAt, trade.LastCheckedAt, trade.CreatedAt, trade.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert paper trade: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	trade.ID = id
+	return nil
+}
+
+// UpdatePaperTrade updates the lifecycle fields of a paper trade
+func (db *DB) UpdatePaperTrade(trade *models.PaperTrade) error {
+	trade.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE paper_trades SET
+			status = ?, exit_price = ?, exit_reason = ?, realized_pnl = ?, filled_at = ?,
+			closed_at = ?, last_checked_at = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	_, err := db.conn.Exec(query,
+		trade.Status, trade.ExitPrice, trade.ExitReason, trade.RealizedPnL, trade.FilledAt,
+		trade.ClosedAt, trade.LastCheckedAt, trade.UpdatedAt, trade.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update paper trade: %w", err)
+	}
+
+	return nil
+}
+
+const paperTradeColumns = `id, symbol, setup_type, side, quality_score, entry_price, stop_loss, target_price,
+	quantity, status, exit_price, exit_reason, realized_pnl, expires_at, filled_at, closed_at,
+	last_checked_at, created_at, updated_at`
+
+// GetPaperTrades retrieves paper trades, newest first
+func (db *DB) GetPaperTrades(filter *models.PaperTradeFilter) ([]*models.PaperTrade, error) {
+	query := "SELECT " + paperTradeColumns + " FROM paper_trades WHERE 1=1"
+	args := []interface{}{}
+
+	if filter != nil {
+		if filter.Symbol != "" {
+			query += " AND symbol = ?"
+			args = append(args, filter.Symbol)
+		}
+		if filter.Status != "" {
+			query += " AND status = ?"
+			args = append(args, filter.Status)
+		}
+	}
+
+	query += " ORDER BY created_at DESC"
+
+	if filter != nil && filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query paper trades: %w", err)
+	}
+	defer rows.Close()
+
+	trades := make([]*models.PaperTrade, 0)
+	for rows.Next() {
+		trade, err := scanPaperTrade(rows)
+		if err != nil {
+			return nil, err
+		}
+		trades = append(trades, trade)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating paper trades: %w", err)
+	}
+
+	return trades, nil
+}
+
+// GetPaperTradeByID retrieves a specific paper trade by ID
+func (db *DB) GetPaperTradeByID(id int64) (*models.PaperTrade, error) {
+	row := db.conn.QueryRow("SELECT "+paperTradeColumns+" FROM paper_trades WHERE id = ?", id)
+
+	trade, err := scanPaperTrade(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return trade, err
+}
+
+// InsertPaperEquityPoint appends a point to the paper trading equity curve
+func (db *DB) InsertPaperEquityPoint(point *models.PaperEquityPoint) error {
+	_, err := db.conn.Exec(
+		`INSERT INTO paper_equity (timestamp, equity, cash, unrealized_pnl, open_trades) VALUES (?, ?, ?, ?, ?)`,
+		point.Timestamp, point.Equity, point.Cash, point.UnrealizedPnL, point.OpenTrades,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert paper equity point: %w", err)
+	}
+	return nil
+}
+
+// GetPaperEquityCurve retrieves the equity curve since a point in time, oldest first
+func (db *DB) GetPaperEquityCurve(since time.Time) ([]*models.PaperEquityPoint, error) {
+	rows, err := db.conn.Query(
+		`SELECT timestamp, equity, cash, unrealized_pnl, open_trades
+		FROM paper_equity WHERE timestamp >= ? ORDER BY timestamp ASC`,
+		since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query paper equity curve: %w", err)
+	}
+	defer rows.Close()
+
+	points := make([]*models.PaperEquityPoint, 0)
+	for rows.Next() {
+		point := &models.PaperEquityPoint{}
+		if err := rows.Scan(&point.Timestamp, &point.Equity, &point.Cash, &point.UnrealizedPnL, &point.OpenTrades); err != nil {
+			return nil, fmt.Errorf("failed to scan paper equity point: %w", err)
+		}
+		points = append(points, point)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating paper equity curve: %w", err)
+	}
+
+	return points, nil
+}
+
+// ResetPaperTrading deletes all paper trades and the equity curve
+func (db *DB) ResetPaperTrading() error {
+	if _, err := db.conn.Exec("DELETE FROM paper_trades"); err != nil {
+		return fmt.Errorf("failed to delete paper trades: %w", err)
+	}
+	if _, err := db.conn.Exec("DELETE FROM paper_equity"); err != nil {
+		return fmt.Errorf("failed to delete paper equity curve: %w", err)
+	}
+	return nil
+}
+
+// scanPaperTrade scans a paper trade from a database row
+func scanPaperTrade(row interface{ Scan(...interface{}) error }) (*models.PaperTrade, error) {
+	trade := &models.PaperTrade{}
+	var exitPrice sql.NullFloat64
+	var filledAt, closedAt sql.NullTime
+
+	err := row.Scan(
+		&trade.ID, &trade.Symbol, &trade.SetupType, &trade.Side, &trade.QualityScore, &trade.EntryPrice,
+		&trade.StopLoss, &trade.TargetPrice, &trade.Quantity, &trade.Status, &exitPrice, &trade.ExitReason,
+		&trade.RealizedPnL, &trade.ExpiresAt, &filledAt, &closedAt, &trade.LastCheckedAt,
+		&trade.CreatedAt, &trade.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan paper trade: %w", err)
+	}
+
+	if exitPrice.Valid {
+		trade.ExitPrice = &exitPrice.Float64
+	}
+	if filledAt.Valid {
+		trade.FilledAt = &filledAt.Time
+	}
+	if closedAt.Valid {
+		trade.ClosedAt = &closedAt.Time
+	}
+
+	return trade, nil
+}
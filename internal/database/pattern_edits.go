@@ -0,0 +1,66 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"market-watch-go/internal/models"
+)
+
+// InsertPatternEdit records a manual edit of a pattern in its audit trail
+func (db *DB) InsertPatternEdit(edit *models.PatternEdit) error {
+	changesJSON, err := json.Marshal(edit.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pattern edit changes: %w", err)
+	}
+
+	result, err := db.conn.Exec(`
+		INSERT INTO pattern_edits (pattern_type, pattern_id, changes, note, editor, edited_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, edit.PatternType, edit.PatternID, string(changesJSON), edit.Note, edit.Editor, edit.EditedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert pattern edit: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	edit.ID = id
+	return nil
+}
+
+// GetPatternEdits retrieves the audit trail of manual edits of a pattern, oldest first
+func (db *DB) GetPatternEdits(patternType string, patternID int64) ([]*models.PatternEdit, error) {
+	rows, err := db.conn.Query(`
+		SELECT id, pattern_type, pattern_id, changes, note, editor, edited_at
+		FROM pattern_edits WHERE pattern_type = ? AND pattern_id = ?
+		ORDER BY edited_at ASC, id ASC
+	`, patternType, patternID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pattern edits: %w", err)
+	}
+	defer rows.Close()
+
+	edits := make([]*models.PatternEdit, 0)
+	for rows.Next() {
+		edit := &models.PatternEdit{}
+		var changesJSON string
+
+		if err := rows.Scan(&edit.ID, &edit.PatternType, &edit.PatternID, &changesJSON, &edit.Note, &edit.Editor, &edit.EditedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan pattern edit: %w", err)
+		}
+		if err := json.Unmarshal([]byte(changesJSON), &edit.Changes); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal pattern edit changes: %w", err)
+		}
+
+		edits = append(edits, edit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pattern edits: %w", err)
+	}
+
+	return edits, nil
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"market-watch-go/internal/models"
+)
+
+// patternIDByFingerprint returns the ID of the oldest pattern in a pattern table with the symbol and
+// fingerprint, or 0 when there is none
+func (db *DB) patternIDByFingerprint(table, symbol, fingerprint string) (int64, error) {
+	var id int64
+	err := db.conn.QueryRow(
+		`SELECT id FROM `+table+` WHERE symbol = ? AND fingerprint = ? ORDER BY id LIMIT 1`, symbol, fingerprint,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up %s by fingerprint: %w", table, err)
+	}
+	return id, nil
+}
+
+// GetHeadShouldersPatternByFingerprint retrieves the stored pattern with the fingerprint, or nil if there is none
+func (db *DB) GetHeadShouldersPatternByFingerprint(symbol, fingerprint string) (*models.HeadShouldersPattern, error) {
+	id, err := db.patternIDByFingerprint("head_shoulders_patterns", symbol, fingerprint)
+	if err != nil || id == 0 {
+		return nil, err
+	}
+	return db.GetHeadShouldersPatternByID(id)
+}
+
+// GetFallingWedgePatternByFingerprint retrieves the stored wedge with the fingerprint, or nil if there is none
+func (db *DB) GetFallingWedgePatternByFingerprint(symbol, fingerprint string) (*models.FallingWedgePattern, error) {
+	id, err := db.patternIDByFingerprint("falling_wedge_patterns", symbol, fingerprint)
+	if err != nil || id == 0 {
+		return nil, err
+	}
+	return db.GetFallingWedgePatternByID(id)
+}
+
+// GetTrianglePatternByFingerprint retrieves the stored triangle with the fingerprint, or nil if there is none
+func (db *DB) GetTrianglePatternByFingerprint(symbol, fingerprint string) (*models.TrianglePattern, error) {
+	id, err := db.patternIDByFingerprint("triangle_patterns", symbol, fingerprint)
+	if err != nil || id == 0 {
+		return nil, err
+	}
+	return db.GetTrianglePatternByID(id)
+}
+
+// GetFlagPatternByFingerprint retrieves the stored flag with the fingerprint, or nil if there is none
+func (db *DB) GetFlagPatternByFingerprint(symbol, fingerprint string) (*models.FlagPattern, error) {
+	id, err := db.patternIDByFingerprint("flag_patterns", symbol, fingerprint)
+	if err != nil || id == 0 {
+		return nil, err
+	}
+	return db.GetFlagPatternByID(id)
+}
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// CreatePatternLinkTables creates the table of links between overlapping patterns
+func (db *DB) CreatePatternLinkTables() error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS pattern_links (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			symbol TEXT NOT NULL,
+			direction TEXT NOT NULL,
+			family TEXT NOT NULL,
+			pattern_id INTEGER NOT NULL,
+			linked_family TEXT NOT NULL,
+			linked_pattern_id INTEGER NOT NULL,
+			shared_pivots INTEGER NOT NULL DEFAULT 0,
+			kept_setup_id INTEGER NOT NULL DEFAULT 0,
+			superseded_setup_id INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL,
+			UNIQUE (family, pattern_id, linked_family, linked_pattern_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_pattern_links_symbol ON pattern_links(symbol)`,
+	}
+
+	for _, query := range queries {
+		if _, err := db.conn.Exec(query); err != nil {
+			return fmt.Errorf("failed to create pattern link tables: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// InsertPatternLink stores a link between two patterns; a link that is already stored is left as is
+func (db *DB) InsertPatternLink(link *models.PatternLink) error {
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = time.Now()
+	}
+
+	_, err := db.conn.Exec(`
+		INSERT OR IGNORE INTO pattern_links (symbol, direction, family, pattern_id, linked_family, linked_pattern_id,
+			shared_pivots, kept_setup_id, superseded_setup_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		link.Symbol, link.Direction, link.Family, link.PatternID, link.LinkedFamily, link.LinkedPatternID,
+		link.SharedPivots, link.KeptSetupID, link.SupersededSetupID, link.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert pattern link: %w", err)
+	}
+	return nil
+}
+
+// GetPatternLinks retrieves the pattern links of a symbol, or of all symbols for an empty symbol, newest first
+func (db *DB) GetPatternLinks(symbol string) ([]*models.PatternLink, error) {
+	query := `
+		SELECT id, symbol, direction, family, pattern_id, linked_family, linked_pattern_id,
+			shared_pivots, kept_setup_id, superseded_setup_id, created_at
+		FROM pattern_links`
+	var args []interface{}
+	if symbol != "" {
+		query += " WHERE symbol = ?"
+		args = append(args, symbol)
+	}
+	query += " ORDER BY created_at DESC, id DESC"
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pattern links: %w", err)
+	}
+	defer rows.Close()
+
+	links := make([]*models.PatternLink, 0)
+	for rows.Next() {
+		link := &models.PatternLink{}
+		if err := rows.Scan(
+			&link.ID, &link.Symbol, &link.Direction, &link.Family, &link.PatternID, &link.LinkedFamily,
+			&link.LinkedPatternID, &link.SharedPivots, &link.KeptSetupID, &link.SupersededSetupID, &link.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan pattern link: %w", err)
+		}
+		links = append(links, link)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pattern links: %w", err)
+	}
+
+	return links, nil
+}
@@ -0,0 +1,214 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// CreatePortfolioTables creates the portfolio positions table
+func (db *DB) CreatePortfolioTables() error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS portfolio_positions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			symbol TEXT NOT NULL,
+			side TEXT NOT NULL DEFAULT 'long' CHECK (side IN ('long', 'short')),
+			quantity REAL NOT NULL,
+			entry_price REAL NOT NULL,
+			entry_time DATETIME NOT NULL,
+			stop_loss REAL DEFAULT 0,
+			target_price REAL DEFAULT 0,
+			exit_price REAL,
+			exit_time DATETIME,
+			status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+			notes TEXT DEFAULT '',
+			setup_id INTEGER,
+			setup_type TEXT NOT NULL DEFAULT 'manual',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (setup_id) REFERENCES trading_setups(id) ON DELETE SET NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_portfolio_positions_status ON portfolio_positions(status)`,
+		`CREATE INDEX IF NOT EXISTS idx_portfolio_positions_symbol ON portfolio_positions(symbol)`,
+		`CREATE INDEX IF NOT EXISTS idx_portfolio_positions_setup ON portfolio_positions(setup_id)`,
+	}
+
+	for _, query := range queries {
+		if _, err := db.conn.Exec(query); err != nil {
+			return fmt.Errorf("failed to create portfolio tables: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// InsertPosition inserts a new portfolio position
+func (db *DB) InsertPosition(position *models.Position) error {
+	now := time.Now()
+	position.CreatedAt = now
+	position.UpdatedAt = now
+
+	query := `
+		INSERT INTO portfolio_positions (
+			symbol, side, quantity, entry_price, entry_time, stop_loss, target_price,
+			exit_price, exit_time, status, notes, setup_id, setup_type, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := db.conn.Exec(query,
+		position.Symbol, position.Side, position.Quantity, position.EntryPrice, position.EntryTime,
+		position.StopLoss, position.TargetPrice, position.ExitPrice, position.ExitTime, position.Status,
+		position.Notes, position.SetupID, position.SetupType, position.CreatedAt, position.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert position: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	position.ID = id
+	return nil
+}
+
+const positionColumns = `id, symbol, side, quantity, entry_price, entry_time, stop_loss, target_price,
+	exit_price, exit_time, status, notes, setup_id, setup_type, created_at, updated_at`
+
+// GetPositions retrieves portfolio positions, newest entries first
+func (db *DB) GetPositions(filter *models.PositionFilter) ([]*models.Position, error) {
+	query := "SELECT " + positionColumns + " FROM portfolio_positions WHERE 1=1"
+	args := []interface{}{}
+
+	if filter != nil {
+		if filter.Symbol != "" {
+			query += " AND symbol = ?"
+			args = append(args, filter.Symbol)
+		}
+		if filter.Status != "" {
+			query += " AND status = ?"
+			args = append(args, filter.Status)
+		}
+		if filter.SetupType != "" {
+			query += " AND setup_type = ?"
+			args = append(args, filter.SetupType)
+		}
+	}
+
+	query += " ORDER BY entry_time DESC"
+
+	if filter != nil && filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query positions: %w", err)
+	}
+	defer rows.Close()
+
+	positions := make([]*models.Position, 0)
+	for rows.Next() {
+		position, err := scanPosition(rows)
+		if err != nil {
+			return nil, err
+		}
+		positions = append(positions, position)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating positions: %w", err)
+	}
+
+	return positions, nil
+}
+
+// GetPositionByID retrieves a specific position by ID
+func (db *DB) GetPositionByID(id int64) (*models.Position, error) {
+	row := db.conn.QueryRow("SELECT "+positionColumns+" FROM portfolio_positions WHERE id = ?", id)
+
+	position, err := scanPosition(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return position, err
+}
+
+// UpdatePosition updates an existing position
+func (db *DB) UpdatePosition(position *models.Position) error {
+	position.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE portfolio_positions SET
+			side = ?, quantity = ?, entry_price = ?, entry_time = ?, stop_loss = ?, target_price = ?,
+			exit_price = ?, exit_time = ?, status = ?, notes = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	_, err := db.conn.Exec(query,
+		position.Side, position.Quantity, position.EntryPrice, position.EntryTime, position.StopLoss,
+		position.TargetPrice, position.ExitPrice, position.ExitTime, position.Status, position.Notes,
+		position.UpdatedAt, position.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update position: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePosition deletes a position
+func (db *DB) DeletePosition(id int64) error {
+	result, err := db.conn.Exec("DELETE FROM portfolio_positions WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete position: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("position %w", ErrNotFound)
+	}
+
+	return nil
+}
+
+// scanPosition scans a position from a database row
+func scanPosition(row interface{ Scan(...interface{}) error }) (*models.Position, error) {
+	position := &models.Position{}
+	var exitPrice sql.NullFloat64
+	var exitTime sql.NullTime
+	var setupID sql.NullInt64
+
+	err := row.Scan(
+		&position.ID, &position.Symbol, &position.Side, &position.Quantity, &position.EntryPrice,
+		&position.EntryTime, &position.StopLoss, &position.TargetPrice, &exitPrice, &exitTime,
+		&position.Status, &position.Notes, &setupID, &position.SetupType,
+		&position.CreatedAt, &position.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan position: %w", err)
+	}
+
+	if exitPrice.Valid {
+		position.ExitPrice = &exitPrice.Float64
+	}
+	if exitTime.Valid {
+		position.ExitTime = &exitTime.Time
+	}
+	if setupID.Valid {
+		position.SetupID = &setupID.Int64
+	}
+
+	return position, nil
+}
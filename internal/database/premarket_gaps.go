@@ -0,0 +1,69 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"market-watch-go/internal/models"
+)
+
+// premarketGapColumns are the premarket_gaps columns in scan order
+var premarketGapColumns = []string{
+	"trade_date", "symbol", "rank", "prev_close", "pre_market_price", "gap_percent", "pre_market_high",
+	"pre_market_low", "pre_market_volume", "rvol", "gapper", "scanned_at",
+}
+
+// ReplaceGapScan stores a trading day's gap scan, replacing the day's earlier scan
+func (db *DB) ReplaceGapScan(tradeDate string, entries []*models.GapScanEntry) error {
+	rows := make([][]interface{}, len(entries))
+	for i, e := range entries {
+		rows[i] = []interface{}{
+			e.TradeDate, e.Symbol, e.Rank, e.PrevClose, e.PreMarketPrice, e.GapPercent, e.PreMarketHigh,
+			e.PreMarketLow, e.PreMarketVolume, e.RVOL, e.Gapper, e.ScannedAt,
+		}
+	}
+
+	return db.WithTx(func(tx *DB) error {
+		if _, err := tx.conn.Exec("DELETE FROM premarket_gaps WHERE trade_date = ?", tradeDate); err != nil {
+			return fmt.Errorf("failed to delete gap scan of %s: %w", tradeDate, err)
+		}
+		if err := tx.upsertBatch("premarket_gaps", premarketGapColumns, premarketGapColumns[:2], premarketGapColumns[2:], rows); err != nil {
+			return fmt.Errorf("failed to store gap scan of %s: %w", tradeDate, err)
+		}
+		return nil
+	})
+}
+
+// GetGapScan retrieves a trading day's gap scan by rank, or the latest scan when tradeDate is empty
+func (db *DB) GetGapScan(tradeDate string) ([]*models.GapScanEntry, error) {
+	query := "SELECT " + strings.Join(premarketGapColumns, ", ") + " FROM premarket_gaps WHERE trade_date = "
+	args := []interface{}{}
+	if tradeDate == "" {
+		query += "(SELECT MAX(trade_date) FROM premarket_gaps)"
+	} else {
+		query += "?"
+		args = append(args, tradeDate)
+	}
+
+	rows, err := db.conn.Query(query+" ORDER BY rank", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query gap scan: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*models.GapScanEntry, 0)
+	for rows.Next() {
+		e := &models.GapScanEntry{}
+		if err := rows.Scan(&e.TradeDate, &e.Symbol, &e.Rank, &e.PrevClose, &e.PreMarketPrice, &e.GapPercent,
+			&e.PreMarketHigh, &e.PreMarketLow, &e.PreMarketVolume, &e.RVOL, &e.Gapper, &e.ScannedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan gap scan entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating gap scan: %w", err)
+	}
+
+	return entries, nil
+}
@@ -1,7 +1,10 @@
 package database
 
 import (
+	"database/sql"
 	"fmt"
+	"slices"
+	"strconv"
 	"time"
 
 	"market-watch-go/internal/models"
@@ -11,8 +14,8 @@ import (
 func (db *DB) InsertPriceData(data *models.PriceData) error {
 	query := `
 		INSERT OR REPLACE INTO price_data
-		(symbol, timestamp, open_price, high_price, low_price, close_price, volume, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		(symbol, timestamp, open_price, high_price, low_price, close_price, volume, session, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err := db.conn.Exec(query,
@@ -23,6 +26,7 @@ func (db *DB) InsertPriceData(data *models.PriceData) error {
 		data.Low,
 		data.Close,
 		data.Volume,
+		data.Session,
 		data.CreatedAt,
 	)
 
@@ -33,60 +37,97 @@ func (db *DB) InsertPriceData(data *models.PriceData) error {
 	return nil
 }
 
-// InsertPriceDataBatch inserts multiple price data records in a transaction
+// InsertPriceDataBatch upserts multiple price data records in a transaction; a bar already stored for the
+// same symbol and timestamp is updated in place. The last of repeated bars wins.
 func (db *DB) InsertPriceDataBatch(dataList []*models.PriceData) error {
-	if len(dataList) == 0 {
-		return nil
+	latest := make(map[string]int, len(dataList))
+	for i, data := range dataList {
+		latest[barKey(data.Symbol, data.Timestamp)] = i
 	}
 
-	tx, err := db.conn.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+	rows := make([][]interface{}, 0, len(latest))
+	for i, data := range dataList {
+		if latest[barKey(data.Symbol, data.Timestamp)] != i {
+			continue
+		}
+		rows = append(rows, []interface{}{
+			data.Symbol, data.Timestamp, data.Open, data.High, data.Low, data.Close, data.Volume, data.Session, data.CreatedAt,
+		})
 	}
-	defer tx.Rollback()
 
-	stmt, err := tx.Prepare(`
-		INSERT OR REPLACE INTO price_data
-		(symbol, timestamp, open_price, high_price, low_price, close_price, volume, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-	`)
-	if err != nil {
-		return fmt.Errorf("failed to prepare statement: %w", err)
-	}
-	defer stmt.Close()
-
-	for _, data := range dataList {
-		_, err := stmt.Exec(
-			data.Symbol,
-			data.Timestamp,
-			data.Open,
-			data.High,
-			data.Low,
-			data.Close,
-			data.Volume,
-			data.CreatedAt,
-		)
+	return db.upsertBatch("price_data",
+		[]string{"symbol", "timestamp", "open_price", "high_price", "low_price", "close_price", "volume", "session", "created_at"},
+		[]string{"symbol", "timestamp"},
+		[]string{"open_price", "high_price", "low_price", "close_price", "volume", "session"},
+		rows)
+}
+
+// barKey identifies a bar by symbol and timestamp
+func barKey(symbol string, timestamp time.Time) string {
+	return symbol + "|" + strconv.FormatInt(timestamp.UnixNano(), 10)
+}
+
+// priceDataRangeQuery selects a symbol's 1-minute bars within a time range, oldest first
+const priceDataRangeQuery = `
+	SELECT id, symbol, timestamp, open_price, high_price, low_price, close_price, volume, session, created_at
+	FROM price_data
+	WHERE symbol = ? AND timestamp BETWEEN ? AND ?
+	ORDER BY timestamp ASC
+`
+
+// GetPriceData retrieves price data for a symbol within a time range
+func (db *DB) GetPriceData(filter *models.PriceDataFilter) ([]*models.PriceData, error) {
+	// Larger timeframes are rolled up from every 1-minute bar in range, after any compacted candles, then paginated
+	if filter.Timeframe.IsAggregated() {
+		rows, err := db.conn.Query(priceDataRangeQuery, filter.Symbol, filter.From, filter.To)
 		if err != nil {
-			return fmt.Errorf("failed to execute batch insert: %w", err)
+			return nil, fmt.Errorf("failed to query price data: %w", err)
+		}
+		defer rows.Close()
+
+		data, err := scanPriceDataRows(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		rollups, err := db.getRollupCandles(filter.Symbol, filter.From, filter.To, filter.Timeframe)
+		if err != nil {
+			return nil, err
 		}
-	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		candles := prependRollups(rollups, AggregatePriceData(data, filter.Timeframe))
+		return PaginatePriceData(candles, filter.Limit, filter.Offset), nil
 	}
 
-	return nil
+	var data []*models.PriceData
+	err := db.EachPriceData(filter, func(pd *models.PriceData) error {
+		data = append(data, pd)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
 }
 
-// GetPriceData retrieves price data for a symbol within a time range
-func (db *DB) GetPriceData(filter *models.PriceDataFilter) ([]*models.PriceData, error) {
-	query := `
-		SELECT id, symbol, timestamp, open_price, high_price, low_price, close_price, volume, created_at
-		FROM price_data
-		WHERE symbol = ? AND timestamp BETWEEN ? AND ?
-		ORDER BY timestamp ASC
-	`
+// EachPriceData calls fn with each bar GetPriceData would return, oldest first, stopping at fn's first error.
+// 1-minute bars are read one row at a time so a long range is never held in memory; larger timeframes are
+// rolled up first.
+func (db *DB) EachPriceData(filter *models.PriceDataFilter, fn func(*models.PriceData) error) error {
+	if filter.Timeframe.IsAggregated() {
+		data, err := db.GetPriceData(filter)
+		if err != nil {
+			return err
+		}
+		for _, pd := range data {
+			if err := fn(pd); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 
+	query := priceDataRangeQuery
 	args := []interface{}{filter.Symbol, filter.From, filter.To}
 
 	if filter.Limit > 0 {
@@ -101,11 +142,28 @@ func (db *DB) GetPriceData(filter *models.PriceDataFilter) ([]*models.PriceData,
 
 	rows, err := db.conn.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query price data: %w", err)
+		return fmt.Errorf("failed to query price data: %w", err)
 	}
 	defer rows.Close()
 
+	return eachPriceDataRow(rows, fn)
+}
+
+// scanPriceDataRows scans price_data rows into PriceData structs
+func scanPriceDataRows(rows *sql.Rows) ([]*models.PriceData, error) {
 	var data []*models.PriceData
+	err := eachPriceDataRow(rows, func(pd *models.PriceData) error {
+		data = append(data, pd)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// eachPriceDataRow scans price_data rows, selected with their session, one at a time and calls fn with each
+func eachPriceDataRow(rows *sql.Rows, fn func(*models.PriceData) error) error {
 	for rows.Next() {
 		pd := &models.PriceData{}
 		err := rows.Scan(
@@ -117,19 +175,22 @@ func (db *DB) GetPriceData(filter *models.PriceDataFilter) ([]*models.PriceData,
 			&pd.Low,
 			&pd.Close,
 			&pd.Volume,
+			&pd.Session,
 			&pd.CreatedAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan price data: %w", err)
+			return fmt.Errorf("failed to scan price data: %w", err)
+		}
+		if err := fn(pd); err != nil {
+			return err
 		}
-		data = append(data, pd)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating price data rows: %w", err)
+		return fmt.Errorf("error iterating price data rows: %w", err)
 	}
 
-	return data, nil
+	return nil
 }
 
 // GetPriceDataRange retrieves price data for a symbol within a specific time range
@@ -142,10 +203,97 @@ func (db *DB) GetPriceDataRange(symbol string, startTime, endTime time.Time) ([]
 	return db.GetPriceData(filter)
 }
 
+// GetPriceDataRangeTimeframe retrieves price data for a symbol within a time range, rolled up to a timeframe
+func (db *DB) GetPriceDataRangeTimeframe(symbol string, startTime, endTime time.Time, timeframe models.Timeframe) ([]*models.PriceData, error) {
+	filter := &models.PriceDataFilter{
+		Symbol:    symbol,
+		From:      startTime,
+		To:        endTime,
+		Timeframe: timeframe,
+	}
+	return db.GetPriceData(filter)
+}
+
+// GetPriceDataBefore retrieves the last limit bars of a timeframe before a time, oldest first
+func (db *DB) GetPriceDataBefore(symbol string, before time.Time, limit int, timeframe models.Timeframe) ([]*models.PriceData, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	// Larger timeframes are rolled up from the 1-minute bars that could make up limit candles
+	if timeframe.IsAggregated() {
+		var from time.Time
+		err := db.conn.QueryRow(`SELECT timestamp FROM price_data WHERE symbol = ? AND timestamp < ?
+			ORDER BY timestamp DESC LIMIT 1 OFFSET ?`, symbol, before, limit*int(timeframe.Duration()/time.Minute)-1).Scan(&from)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, fmt.Errorf("failed to find price data before %s: %w", before.Format(time.RFC3339), err)
+		}
+
+		candles, err := db.GetPriceDataRangeTimeframe(symbol, from, before.Add(-time.Nanosecond), timeframe)
+		if err != nil {
+			return nil, err
+		}
+		if len(candles) > limit {
+			candles = candles[len(candles)-limit:]
+		}
+		return candles, nil
+	}
+
+	rows, err := db.conn.Query(`
+		SELECT id, symbol, timestamp, open_price, high_price, low_price, close_price, volume, session, created_at
+		FROM price_data
+		WHERE symbol = ? AND timestamp < ?
+		ORDER BY timestamp DESC
+		LIMIT ?`, symbol, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query price data: %w", err)
+	}
+	defer rows.Close()
+
+	data, err := scanPriceDataRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(data)
+	return data, nil
+}
+
+// GetPriceTimestamps retrieves the timestamps of a symbol's bars within a time range, oldest first
+func (db *DB) GetPriceTimestamps(symbol string, from, to time.Time) ([]time.Time, error) {
+	return db.getBarTimestamps("price_data", symbol, from, to)
+}
+
+// getBarTimestamps retrieves the timestamps of a symbol's bars in a bar table within a time range, oldest first
+func (db *DB) getBarTimestamps(table, symbol string, from, to time.Time) ([]time.Time, error) {
+	rows, err := db.conn.Query(fmt.Sprintf(`
+		SELECT timestamp FROM %s
+		WHERE symbol = ? AND timestamp BETWEEN ? AND ?
+		ORDER BY timestamp ASC`, table), symbol, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s timestamps: %w", table, err)
+	}
+	defer rows.Close()
+
+	var timestamps []time.Time
+	for rows.Next() {
+		var ts time.Time
+		if err := rows.Scan(&ts); err != nil {
+			return nil, fmt.Errorf("failed to scan %s timestamp: %w", table, err)
+		}
+		timestamps = append(timestamps, ts)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating %s timestamps: %w", table, err)
+	}
+
+	return timestamps, nil
+}
+
 // GetLatestPriceData retrieves the latest price data for a symbol
 func (db *DB) GetLatestPriceData(symbol string) (*models.PriceData, error) {
 	query := `
-		SELECT id, symbol, timestamp, open_price, high_price, low_price, close_price, volume, created_at
+		SELECT id, symbol, timestamp, open_price, high_price, low_price, close_price, volume, session, created_at
 		FROM price_data
 		WHERE symbol = ?
 		ORDER BY timestamp DESC
@@ -164,6 +312,7 @@ func (db *DB) GetLatestPriceData(symbol string) (*models.PriceData, error) {
 		&pd.Low,
 		&pd.Close,
 		&pd.Volume,
+		&pd.Session,
 		&pd.CreatedAt,
 	)
 
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// CreateReferenceDataTables creates the symbol reference data table
+func (db *DB) CreateReferenceDataTables() error {
+	query := `CREATE TABLE IF NOT EXISTS symbol_reference (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		symbol TEXT NOT NULL UNIQUE,
+		name TEXT DEFAULT '',
+		sector TEXT DEFAULT '',
+		industry TEXT DEFAULT '',
+		exchange TEXT DEFAULT '',
+		market_cap REAL DEFAULT 0,
+		shares_outstanding INTEGER DEFAULT 0,
+		float_shares INTEGER DEFAULT 0,
+		avg_volume INTEGER DEFAULT 0,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`
+
+	if _, err := db.conn.Exec(query); err != nil {
+		return fmt.Errorf("failed to create symbol reference table: %w", err)
+	}
+
+	return nil
+}
+
+// UpsertSymbolReference stores or replaces the reference data of a symbol
+func (db *DB) UpsertSymbolReference(ref *models.SymbolReference) error {
+	if ref.UpdatedAt.IsZero() {
+		ref.UpdatedAt = time.Now()
+	}
+
+	query := `
+		INSERT OR REPLACE INTO symbol_reference (
+			symbol, name, sector, industry, exchange, market_cap, shares_outstanding, float_shares, avg_volume, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := db.conn.Exec(query,
+		strings.ToUpper(ref.Symbol), ref.Name, ref.Sector, ref.Industry, ref.Exchange, ref.MarketCap,
+		ref.SharesOutstanding, ref.FloatShares, ref.AvgVolume, ref.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert symbol reference: %w", err)
+	}
+
+	return nil
+}
+
+// GetSymbolReference returns the reference data of a symbol, or nil if it hasn't been fetched
+func (db *DB) GetSymbolReference(symbol string) (*models.SymbolReference, error) {
+	query := `SELECT symbol, name, sector, industry, exchange, market_cap, shares_outstanding, float_shares, avg_volume, updated_at
+		FROM symbol_reference WHERE symbol = ?`
+
+	ref := &models.SymbolReference{}
+	err := db.conn.QueryRow(query, strings.ToUpper(symbol)).Scan(
+		&ref.Symbol, &ref.Name, &ref.Sector, &ref.Industry, &ref.Exchange, &ref.MarketCap,
+		&ref.SharesOutstanding, &ref.FloatShares, &ref.AvgVolume, &ref.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get symbol reference: %w", err)
+	}
+
+	return ref, nil
+}
+
+// GetSymbolReferences returns all stored reference data keyed by symbol
+func (db *DB) GetSymbolReferences() (map[string]*models.SymbolReference, error) {
+	query := `SELECT symbol, name, sector, industry, exchange, market_cap, shares_outstanding, float_shares, avg_volume, updated_at
+		FROM symbol_reference`
+
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query symbol references: %w", err)
+	}
+	defer rows.Close()
+
+	refs := make(map[string]*models.SymbolReference)
+	for rows.Next() {
+		ref := &models.SymbolReference{}
+		if err := rows.Scan(
+			&ref.Symbol, &ref.Name, &ref.Sector, &ref.Industry, &ref.Exchange, &ref.MarketCap,
+			&ref.SharesOutstanding, &ref.FloatShares, &ref.AvgVolume, &ref.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan symbol reference: %w", err)
+		}
+		refs[ref.Symbol] = ref
+	}
+
+	return refs, rows.Err()
+}
+
+// FillStockName sets a watchlist stock's company name if it doesn't have one yet
+func (db *DB) FillStockName(symbol, name string) error {
+	query := `UPDATE stocks SET name = ?, updated_at = CURRENT_TIMESTAMP WHERE symbol = ? AND (name IS NULL OR name = '')`
+
+	if _, err := db.conn.Exec(query, name, strings.ToUpper(symbol)); err != nil {
+		return fmt.Errorf("failed to update stock name: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// CreateScreenerTables creates the saved screens table
+func (db *DB) CreateScreenerTables() error {
+	query := `CREATE TABLE IF NOT EXISTS saved_screens (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL UNIQUE,
+		description TEXT DEFAULT '',
+		criteria TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`
+
+	if _, err := db.conn.Exec(query); err != nil {
+		return fmt.Errorf("failed to create screener tables: %w", err)
+	}
+
+	return nil
+}
+
+// InsertSavedScreen inserts a new saved screen
+func (db *DB) InsertSavedScreen(screen *models.SavedScreen) error {
+	criteria, err := json.Marshal(screen.Criteria)
+	if err != nil {
+		return fmt.Errorf("failed to marshal screen criteria: %w", err)
+	}
+
+	now := time.Now()
+	screen.CreatedAt = now
+	screen.UpdatedAt = now
+
+	result, err := db.conn.Exec(
+		`INSERT INTO saved_screens (name, description, criteria, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+		screen.Name, screen.Description, string(criteria), screen.CreatedAt, screen.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert saved screen: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	screen.ID = id
+	return nil
+}
+
+// UpdateSavedScreen updates a saved screen
+func (db *DB) UpdateSavedScreen(screen *models.SavedScreen) error {
+	criteria, err := json.Marshal(screen.Criteria)
+	if err != nil {
+		return fmt.Errorf("failed to marshal screen criteria: %w", err)
+	}
+
+	screen.UpdatedAt = time.Now()
+
+	result, err := db.conn.Exec(
+		`UPDATE saved_screens SET name = ?, description = ?, criteria = ?, updated_at = ? WHERE id = ?`,
+		screen.Name, screen.Description, string(criteria), screen.UpdatedAt, screen.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update saved screen: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("saved screen %w", ErrNotFound)
+	}
+
+	return nil
+}
+
+// GetSavedScreens retrieves all saved screens ordered by name
+func (db *DB) GetSavedScreens() ([]*models.SavedScreen, error) {
+	rows, err := db.conn.Query(`SELECT id, name, description, criteria, created_at, updated_at FROM saved_screens ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query saved screens: %w", err)
+	}
+	defer rows.Close()
+
+	screens := make([]*models.SavedScreen, 0)
+	for rows.Next() {
+		screen, err := scanSavedScreen(rows)
+		if err != nil {
+			return nil, err
+		}
+		screens = append(screens, screen)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating saved screens: %w", err)
+	}
+
+	return screens, nil
+}
+
+// GetSavedScreenByID retrieves a saved screen by ID
+func (db *DB) GetSavedScreenByID(id int64) (*models.SavedScreen, error) {
+	row := db.conn.QueryRow(`SELECT id, name, description, criteria, created_at, updated_at FROM saved_screens WHERE id = ?`, id)
+
+	screen, err := scanSavedScreen(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return screen, err
+}
+
+// DeleteSavedScreen deletes a saved screen
+func (db *DB) DeleteSavedScreen(id int64) error {
+	result, err := db.conn.Exec("DELETE FROM saved_screens WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete saved screen: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("saved screen %w", ErrNotFound)
+	}
+
+	return nil
+}
+
+// scanSavedScreen scans a saved screen from a database row
+func scanSavedScreen(row interface{ Scan(...interface{}) error }) (*models.SavedScreen, error) {
+	screen := &models.SavedScreen{}
+	var criteria string
+
+	err := row.Scan(&screen.ID, &screen.Name, &screen.Description, &criteria, &screen.CreatedAt, &screen.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan saved screen: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(criteria), &screen.Criteria); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal screen criteria: %w", err)
+	}
+
+	return screen, nil
+}
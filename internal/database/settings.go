@@ -0,0 +1,66 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CreateSettingsTables creates the table holding settings changed at runtime
+func (db *DB) CreateSettingsTables() error {
+	query := `CREATE TABLE IF NOT EXISTS settings (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		section TEXT NOT NULL UNIQUE,
+		value TEXT NOT NULL,
+		updated_at DATETIME NOT NULL
+	)`
+
+	if _, err := db.conn.Exec(query); err != nil {
+		return fmt.Errorf("failed to create settings table: %w", err)
+	}
+
+	return nil
+}
+
+// SaveSettings stores a settings section as JSON, replacing the previous value
+func (db *DB) SaveSettings(section string, value interface{}) error {
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s settings: %w", section, err)
+	}
+
+	query := `INSERT OR REPLACE INTO settings (section, value, updated_at) VALUES (?, ?, ?)`
+	if _, err := db.conn.Exec(query, section, string(valueJSON), time.Now()); err != nil {
+		return fmt.Errorf("failed to save %s settings: %w", section, err)
+	}
+
+	return nil
+}
+
+// GetSettings decodes a stored settings section into value. It reports false, leaving value
+// untouched, when the section has never been saved.
+func (db *DB) GetSettings(section string, value interface{}) (bool, error) {
+	var valueJSON string
+	err := db.conn.QueryRow(`SELECT value FROM settings WHERE section = ?`, section).Scan(&valueJSON)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get %s settings: %w", section, err)
+	}
+
+	if err := json.Unmarshal([]byte(valueJSON), value); err != nil {
+		return false, fmt.Errorf("failed to unmarshal %s settings: %w", section, err)
+	}
+
+	return true, nil
+}
+
+// DeleteSettings removes a stored settings section
+func (db *DB) DeleteSettings(section string) error {
+	if _, err := db.conn.Exec(`DELETE FROM settings WHERE section = ?`, section); err != nil {
+		return fmt.Errorf("failed to delete %s settings: %w", section, err)
+	}
+	return nil
+}
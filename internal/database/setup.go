@@ -1,7 +1,11 @@
 package database
 
 import (
+	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"market-watch-go/internal/models"
 )
@@ -17,7 +21,7 @@ func (db *Database) CreateSetupTables() error {
 			direction TEXT NOT NULL CHECK (direction IN ('bullish', 'bearish')),
 			quality_score REAL NOT NULL DEFAULT 0,
 			confidence TEXT DEFAULT 'low' CHECK (confidence IN ('high', 'medium', 'low')),
-			status TEXT DEFAULT 'active' CHECK (status IN ('active', 'triggered', 'expired', 'invalidated')),
+			status TEXT DEFAULT 'active' CHECK (status IN ('active', 'triggered', 'completed', 'stopped_out', 'expired', 'invalidated')),
 			detected_at DATETIME NOT NULL,
 			expires_at DATETIME NOT NULL,
 			last_updated DATETIME DEFAULT CURRENT_TIMESTAMP,
@@ -121,6 +125,20 @@ func (db *Database) CreateSetupTables() error {
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY (setup_id) REFERENCES trading_setups(id)
 		)`,
+
+		// Setup Stop Adjustments table
+		`CREATE TABLE IF NOT EXISTS setup_stop_adjustments (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			setup_id INTEGER NOT NULL,
+			reason TEXT NOT NULL,
+			previous_stop REAL NOT NULL,
+			suggested_stop REAL NOT NULL,
+			best_price REAL NOT NULL,
+			atr REAL DEFAULT 0,
+			price REAL NOT NULL,
+			suggested_at DATETIME NOT NULL,
+			FOREIGN KEY (setup_id) REFERENCES trading_setups(id)
+		)`,
 	}
 
 	// Create tables
@@ -155,6 +173,9 @@ func (db *Database) CreateSetupTables() error {
 		`CREATE INDEX IF NOT EXISTS idx_setup_alerts_type ON setup_alerts(alert_type)`,
 		`CREATE INDEX IF NOT EXISTS idx_setup_alerts_active ON setup_alerts(is_active)`,
 		`CREATE INDEX IF NOT EXISTS idx_setup_alerts_triggered ON setup_alerts(triggered_at)`,
+
+		// Stop adjustment indexes
+		`CREATE INDEX IF NOT EXISTS idx_setup_stop_adjustments_setup_id ON setup_stop_adjustments(setup_id)`,
 	}
 
 	for _, indexQuery := range indexes {
@@ -167,7 +188,7 @@ func (db *Database) CreateSetupTables() error {
 	return nil
 }
 
-// GetSetupSummary calculates summary statistics for setups
+// GetSetupSummary calculates summary statistics for a symbol's unarchived setups
 func (db *Database) GetSetupSummary(symbol string) (*models.SetupSummary, error) {
 	summary := &models.SetupSummary{}
 
@@ -185,7 +206,7 @@ func (db *Database) GetSetupSummary(symbol string) (*models.SetupSummary, error)
 			AVG(risk_reward_ratio) as avg_risk_reward,
 			MAX(detected_at) as last_detection
 		FROM trading_setups 
-		WHERE symbol = ?
+		WHERE symbol = ? AND archived = FALSE
 	`
 
 	err := db.conn.QueryRow(query, symbol).Scan(
@@ -222,49 +243,6 @@ func (db *Database) ExpireOldSetups() (int64, error) {
 	return rowsAffected, nil
 }
 
-// CleanupOldSetupData removes old setup data based on retention policy
-func (db *Database) CleanupOldSetupData(days int) (int64, error) {
-	var totalDeleted int64
-
-	// Cleanup old checklists
-	checklistResult, err := db.conn.Exec(
-		"DELETE FROM setup_checklists WHERE setup_id IN (SELECT id FROM trading_setups WHERE created_at < datetime('now', '-' || ? || ' days'))",
-		days,
-	)
-	if err != nil {
-		return 0, fmt.Errorf("failed to cleanup old setup checklists: %w", err)
-	}
-
-	checklistsDeleted, _ := checklistResult.RowsAffected()
-	totalDeleted += checklistsDeleted
-
-	// Cleanup old alerts
-	alertResult, err := db.conn.Exec(
-		"DELETE FROM setup_alerts WHERE setup_id IN (SELECT id FROM trading_setups WHERE created_at < datetime('now', '-' || ? || ' days'))",
-		days,
-	)
-	if err != nil {
-		return 0, fmt.Errorf("failed to cleanup old setup alerts: %w", err)
-	}
-
-	alertsDeleted, _ := alertResult.RowsAffected()
-	totalDeleted += alertsDeleted
-
-	// Cleanup old setups
-	setupResult, err := db.conn.Exec(
-		"DELETE FROM trading_setups WHERE created_at < datetime('now', '-' || ? || ' days')",
-		days,
-	)
-	if err != nil {
-		return 0, fmt.Errorf("failed to cleanup old trading setups: %w", err)
-	}
-
-	setupsDeleted, _ := setupResult.RowsAffected()
-	totalDeleted += setupsDeleted
-
-	return totalDeleted, nil
-}
-
 // InsertTradingSetup inserts a new trading setup into the database
 func (db *Database) InsertTradingSetup(setup *models.TradingSetup) error {
 	query := `
@@ -272,16 +250,27 @@ func (db *Database) InsertTradingSetup(setup *models.TradingSetup) error {
 			symbol, setup_type, direction, quality_score, confidence, status,
 			detected_at, expires_at, current_price, entry_price, stop_loss,
 			target1, target2, target3, risk_amount, reward_potential, risk_reward_ratio,
-			price_action_score, volume_score, technical_score, risk_reward_score, notes, is_manual
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			price_action_score, volume_score, technical_score, risk_reward_score, notes, is_manual, key_level_id,
+			confidence_score, confidence_factors, score_breakdown
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
+	var keyLevelID sql.NullInt64
+	if setup.KeyLevelID > 0 {
+		keyLevelID = sql.NullInt64{Int64: setup.KeyLevelID, Valid: true}
+	}
+	factorsJSON, breakdownJSON, err := setupJSONColumns(setup)
+	if err != nil {
+		return err
+	}
+
 	result, err := db.conn.Exec(
 		query,
 		setup.Symbol, setup.SetupType, setup.Direction, setup.QualityScore, setup.Confidence, setup.Status,
 		setup.DetectedAt, setup.ExpiresAt, setup.CurrentPrice, setup.EntryPrice, setup.StopLoss,
 		setup.Target1, setup.Target2, setup.Target3, setup.RiskAmount, setup.RewardPotential, setup.RiskRewardRatio,
 		setup.PriceActionScore, setup.VolumeScore, setup.TechnicalScore, setup.RiskRewardScore, setup.Notes, setup.IsManual,
+		keyLevelID, setup.ConfidenceScore, factorsJSON, breakdownJSON,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert trading setup: %w", err)
@@ -296,96 +285,269 @@ func (db *Database) InsertTradingSetup(setup *models.TradingSetup) error {
 	return nil
 }
 
+// InsertTradingSetupWithChecklist stores a trading setup together with its checklist, if any, in one transaction
+func (db *Database) InsertTradingSetupWithChecklist(setup *models.TradingSetup) error {
+	return db.WithTx(func(tx *Database) error {
+		if err := tx.InsertTradingSetup(setup); err != nil {
+			return err
+		}
+		if setup.Checklist == nil {
+			return nil
+		}
+		setup.Checklist.SetupID = setup.ID
+		return tx.InsertSetupChecklist(setup.Checklist)
+	})
+}
+
+// StoreDetectedSetup stores a detected setup with its checklist, unless an active setup of the same type at the
+// same key level exists: that one is refreshed with the new detection instead, keeping its ID and detection
+// time. It reports whether an existing setup was refreshed.
+func (db *Database) StoreDetectedSetup(setup *models.TradingSetup) (bool, error) {
+	if setup.KeyLevelID == 0 {
+		return false, db.InsertTradingSetupWithChecklist(setup)
+	}
+
+	refreshed := false
+	err := db.WithTx(func(tx *Database) error {
+		var id int64
+		var detectedAt, createdAt time.Time
+		err := tx.conn.QueryRow(`
+			SELECT id, detected_at, created_at FROM trading_setups
+			WHERE symbol = ? AND setup_type = ? AND key_level_id = ? AND status = 'active' AND archived = FALSE`,
+			setup.Symbol, setup.SetupType, setup.KeyLevelID,
+		).Scan(&id, &detectedAt, &createdAt)
+		if err == sql.ErrNoRows {
+			return tx.InsertTradingSetupWithChecklist(setup)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to find active setup at key level: %w", err)
+		}
+
+		setup.ID, setup.DetectedAt, setup.CreatedAt = id, detectedAt, createdAt
+		setup.LastUpdated = time.Now()
+		if err := tx.UpdateTradingSetup(setup); err != nil {
+			return err
+		}
+		if _, err := tx.conn.Exec(`UPDATE trading_setups SET last_updated = ? WHERE id = ?`, setup.LastUpdated, id); err != nil {
+			return fmt.Errorf("failed to refresh trading setup: %w", err)
+		}
+		refreshed = true
+
+		if setup.Checklist == nil {
+			return nil
+		}
+		setup.Checklist.SetupID = id
+		return tx.UpdateSetupChecklist(setup.Checklist)
+	})
+	if err != nil {
+		return false, err
+	}
+	return refreshed, nil
+}
+
 // GetTradingSetups retrieves trading setups based on filter criteria
 func (db *Database) GetTradingSetups(filter *models.SetupFilter) ([]*models.TradingSetup, error) {
 	var setups []*models.TradingSetup
-	var args []interface{}
 
 	// Build the query based on filter
-	query := `
-		SELECT 
-			id, symbol, setup_type, direction, quality_score, confidence, status,
-			detected_at, expires_at, last_updated, current_price, entry_price, stop_loss,
-			target1, target2, target3, risk_amount, reward_potential, risk_reward_ratio,
-			price_action_score, volume_score, technical_score, risk_reward_score,
-			notes, is_manual, created_at, updated_at
-		FROM trading_setups
-		WHERE 1=1
-	`
+	where, args := tradingSetupWhere(filter)
+	query := `SELECT ` + tradingSetupColumns + ` FROM trading_setups` + where
+
+	// Order by quality score descending unless another sort was requested
+	query += orderBy(filter.Sort, filter.Order, models.SetupSortFields, "quality_score DESC")
+	query, args = limitOffset(query, args, filter.Limit, filter.Offset)
+
+	// Execute query
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trading setups: %w", err)
+	}
+	defer rows.Close()
+
+	// Scan results
+	for rows.Next() {
+		setup, err := scanTradingSetup(rows)
+		if err != nil {
+			return nil, err
+		}
+		setups = append(setups, setup)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating trading setups: %w", err)
+	}
+
+	return setups, nil
+}
+
+// CountTradingSetups counts the setups matching a filter, ignoring its limit and offset
+func (db *Database) CountTradingSetups(filter *models.SetupFilter) (int, error) {
+	where, args := tradingSetupWhere(filter)
+	return db.countRows("trading_setups", where, args)
+}
+
+// tradingSetupWhere builds the WHERE clause for a SetupFilter
+func tradingSetupWhere(filter *models.SetupFilter) (string, []interface{}) {
+	where := " WHERE 1=1"
+	var args []interface{}
+
+	if filter.ID > 0 {
+		where += " AND id = ?"
+		args = append(args, filter.ID)
+	}
 
-	// Apply filters
 	if filter.Symbol != "" {
-		query += " AND symbol = ?"
+		where += " AND symbol = ?"
 		args = append(args, filter.Symbol)
 	}
 
+	if len(filter.Symbols) > 0 {
+		where += " AND symbol IN (?" + strings.Repeat(", ?", len(filter.Symbols)-1) + ")"
+		for _, symbol := range filter.Symbols {
+			args = append(args, symbol)
+		}
+	}
+
 	if filter.SetupType != "" {
-		query += " AND setup_type = ?"
+		where += " AND setup_type = ?"
 		args = append(args, filter.SetupType)
 	}
 
 	if filter.Direction != "" {
-		query += " AND direction = ?"
+		where += " AND direction = ?"
 		args = append(args, filter.Direction)
 	}
 
 	if filter.Status != "" {
-		query += " AND status = ?"
+		where += " AND status = ?"
 		args = append(args, filter.Status)
 	}
 
 	if filter.Confidence != "" {
-		query += " AND confidence = ?"
+		where += " AND confidence = ?"
 		args = append(args, filter.Confidence)
 	}
 
 	if filter.MinQualityScore > 0 {
-		query += " AND quality_score >= ?"
+		where += " AND quality_score >= ?"
 		args = append(args, filter.MinQualityScore)
 	}
 
+	if !filter.TimeRange.From.IsZero() {
+		where += " AND detected_at >= ?"
+		args = append(args, filter.TimeRange.From)
+	}
+
+	if !filter.TimeRange.To.IsZero() {
+		where += " AND detected_at <= ?"
+		args = append(args, filter.TimeRange.To)
+	}
+
 	if filter.IsActive != nil && *filter.IsActive {
-		query += " AND status = 'active' AND expires_at > datetime('now')"
+		where += " AND status = 'active' AND expires_at > datetime('now')"
 	}
 
-	// Order by quality score descending
-	query += " ORDER BY quality_score DESC"
+	// A setup looked up by ID is returned archived or not
+	if !filter.IncludeArchived && filter.ID == 0 {
+		where += " AND archived = FALSE"
+	}
+
+	return where, args
+}
 
-	// Apply limit
-	if filter.Limit > 0 {
-		query += " LIMIT ?"
-		args = append(args, filter.Limit)
+// GetTradingSetupByID retrieves a setup with its checklist and alert count, or nil if it doesn't exist
+func (db *Database) GetTradingSetupByID(id int64) (*models.TradingSetup, error) {
+	row := db.conn.QueryRow(`SELECT `+tradingSetupColumns+` FROM trading_setups WHERE id = ?`, id)
+
+	setup, err := scanTradingSetup(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	// Execute query
-	rows, err := db.conn.Query(query, args...)
+	checklist, err := db.GetSetupChecklist(id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query trading setups: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
+	setup.Checklist = checklist
 
-	// Scan results
-	for rows.Next() {
-		setup := &models.TradingSetup{}
-		err := rows.Scan(
-			&setup.ID, &setup.Symbol, &setup.SetupType, &setup.Direction, &setup.QualityScore,
-			&setup.Confidence, &setup.Status, &setup.DetectedAt, &setup.ExpiresAt, &setup.LastUpdated,
-			&setup.CurrentPrice, &setup.EntryPrice, &setup.StopLoss, &setup.Target1, &setup.Target2,
-			&setup.Target3, &setup.RiskAmount, &setup.RewardPotential, &setup.RiskRewardRatio,
-			&setup.PriceActionScore, &setup.VolumeScore, &setup.TechnicalScore, &setup.RiskRewardScore,
-			&setup.Notes, &setup.IsManual, &setup.CreatedAt, &setup.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan trading setup: %w", err)
-		}
-		setups = append(setups, setup)
+	history, err := db.GetSetupStopAdjustments(id)
+	if err != nil {
+		return nil, err
+	}
+	setup.StopHistory = history
+	if len(history) > 0 {
+		setup.SuggestedStop = history[len(history)-1].SuggestedStop
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating trading setups: %w", err)
+	return setup, nil
+}
+
+// tradingSetupColumns are the columns read by scanTradingSetup
+const tradingSetupColumns = `id, symbol, setup_type, direction, quality_score, confidence, status,
+	detected_at, expires_at, last_updated, current_price, entry_price, stop_loss,
+	target1, target2, target3, risk_amount, reward_potential, risk_reward_ratio,
+	price_action_score, volume_score, technical_score, risk_reward_score,
+	notes, is_manual, created_at, updated_at, archived, archived_at, COALESCE(key_level_id, 0),
+	COALESCE(confidence_score, 0), confidence_factors, score_breakdown,
+	(SELECT COUNT(*) FROM setup_alerts WHERE setup_alerts.setup_id = trading_setups.id)`
+
+// scanTradingSetup scans a trading setup from a database row
+func scanTradingSetup(row interface{ Scan(...interface{}) error }) (*models.TradingSetup, error) {
+	setup := &models.TradingSetup{}
+	var archivedAt sql.NullTime
+	var factorsJSON, breakdownJSON sql.NullString
+	err := row.Scan(
+		&setup.ID, &setup.Symbol, &setup.SetupType, &setup.Direction, &setup.QualityScore,
+		&setup.Confidence, &setup.Status, &setup.DetectedAt, &setup.ExpiresAt, &setup.LastUpdated,
+		&setup.CurrentPrice, &setup.EntryPrice, &setup.StopLoss, &setup.Target1, &setup.Target2,
+		&setup.Target3, &setup.RiskAmount, &setup.RewardPotential, &setup.RiskRewardRatio,
+		&setup.PriceActionScore, &setup.VolumeScore, &setup.TechnicalScore, &setup.RiskRewardScore,
+		&setup.Notes, &setup.IsManual, &setup.CreatedAt, &setup.UpdatedAt,
+		&setup.Archived, &archivedAt, &setup.KeyLevelID, &setup.ConfidenceScore, &factorsJSON, &breakdownJSON,
+		&setup.AlertCount,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan trading setup: %w", err)
+	}
+	if archivedAt.Valid {
+		setup.ArchivedAt = &archivedAt.Time
 	}
+	if factorsJSON.Valid && factorsJSON.String != "" {
+		if err := json.Unmarshal([]byte(factorsJSON.String), &setup.ConfidenceFactors); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal confidence factors: %w", err)
+		}
+	}
+	if breakdownJSON.Valid && breakdownJSON.String != "" {
+		if err := json.Unmarshal([]byte(breakdownJSON.String), &setup.ScoreBreakdown); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal score breakdown: %w", err)
+		}
+	}
+	return setup, nil
+}
 
-	return setups, nil
+// setupJSONColumns encodes a setup's confidence factors and score breakdown, storing NULL for the ones it lacks
+func setupJSONColumns(setup *models.TradingSetup) (factors, breakdown sql.NullString, err error) {
+	if len(setup.ConfidenceFactors) > 0 {
+		data, err := json.Marshal(setup.ConfidenceFactors)
+		if err != nil {
+			return factors, breakdown, fmt.Errorf("failed to marshal confidence factors: %w", err)
+		}
+		factors = sql.NullString{String: string(data), Valid: true}
+	}
+	if setup.ScoreBreakdown != nil {
+		data, err := json.Marshal(setup.ScoreBreakdown)
+		if err != nil {
+			return factors, breakdown, fmt.Errorf("failed to marshal score breakdown: %w", err)
+		}
+		breakdown = sql.NullString{String: string(data), Valid: true}
+	}
+	return factors, breakdown, nil
 }
 
 // UpdateTradingSetup updates an existing trading setup
@@ -415,17 +577,25 @@ func (db *Database) UpdateTradingSetup(setup *models.TradingSetup) error {
 			risk_reward_score = ?,
 			notes = ?,
 			is_manual = ?,
+			confidence_score = ?,
+			confidence_factors = ?,
+			score_breakdown = ?,
 			updated_at = CURRENT_TIMESTAMP
 		WHERE id = ?
 	`
 
-	_, err := db.conn.Exec(
+	factorsJSON, breakdownJSON, err := setupJSONColumns(setup)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.conn.Exec(
 		query,
 		setup.Symbol, setup.SetupType, setup.Direction, setup.QualityScore, setup.Confidence, setup.Status,
 		setup.DetectedAt, setup.ExpiresAt, setup.CurrentPrice, setup.EntryPrice, setup.StopLoss,
 		setup.Target1, setup.Target2, setup.Target3, setup.RiskAmount, setup.RewardPotential, setup.RiskRewardRatio,
 		setup.PriceActionScore, setup.VolumeScore, setup.TechnicalScore, setup.RiskRewardScore, setup.Notes, setup.IsManual,
-		setup.ID,
+		setup.ConfidenceScore, factorsJSON, breakdownJSON, setup.ID,
 	)
 
 	if err != nil {
@@ -435,7 +605,91 @@ func (db *Database) UpdateTradingSetup(setup *models.TradingSetup) error {
 	return nil
 }
 
-// GetSetupChecklist retrieves the checklist for a trading setup
+// setupChecklistColumns are the columns written by InsertSetupChecklist and UpdateSetupChecklist, in setupChecklistArgs order
+var setupChecklistColumns = []string{
+	"min_level_touches_completed", "min_level_touches_points",
+	"bounce_strength_completed", "bounce_strength_points",
+	"time_at_level_completed", "time_at_level_points",
+	"rejection_candle_completed", "rejection_candle_points",
+	"level_duration_completed", "level_duration_points",
+
+	"volume_spike_completed", "volume_spike_points",
+	"volume_confirmation_completed", "volume_confirmation_points",
+	"approach_volume_completed", "approach_volume_points",
+	"vwap_relationship_completed", "vwap_relationship_points",
+	"relative_volume_completed", "relative_volume_points",
+
+	"rsi_condition_completed", "rsi_condition_points",
+	"moving_average_completed", "moving_average_points",
+	"macd_signal_completed", "macd_signal_points",
+	"momentum_divergence_completed", "momentum_divergence_points",
+	"bollinger_bands_completed", "bollinger_bands_points",
+
+	"stop_loss_defined_completed", "stop_loss_defined_points",
+	"risk_reward_ratio_completed", "risk_reward_ratio_points",
+	"position_size_completed", "position_size_points",
+	"entry_precision_completed", "entry_precision_points",
+	"exit_strategy_completed", "exit_strategy_points",
+
+	"short_interest_completed", "short_interest_value",
+
+	"total_score", "completed_items", "total_items", "completion_percent",
+	"last_updated",
+}
+
+// setupChecklistArgs flattens a checklist into values matching setupChecklistColumns
+func setupChecklistArgs(checklist *models.SetupChecklist) []interface{} {
+	args := make([]interface{}, 0, len(setupChecklistColumns))
+	for _, item := range []models.ChecklistItem{
+		checklist.MinLevelTouches, checklist.BounceStrength, checklist.TimeAtLevel, checklist.RejectionCandle, checklist.LevelDuration,
+		checklist.VolumeSpike, checklist.VolumeConfirmation, checklist.ApproachVolume, checklist.VWAPRelationship, checklist.RelativeVolume,
+		checklist.RSICondition, checklist.MovingAverage, checklist.MACDSignal, checklist.MomentumDivergence, checklist.BollingerBands,
+		checklist.StopLossDefined, checklist.RiskRewardRatio, checklist.PositionSize, checklist.EntryPrecision, checklist.ExitStrategy,
+	} {
+		args = append(args, item.IsCompleted, item.Points)
+	}
+	args = append(args, checklist.ShortInterest.IsCompleted, checklist.ShortInterest.Value)
+	return append(args, checklist.TotalScore, checklist.CompletedItems, checklist.TotalItems, checklist.CompletionPercent, checklist.LastUpdated)
+}
+
+// InsertSetupChecklist stores the checklist for a trading setup
+func (db *Database) InsertSetupChecklist(checklist *models.SetupChecklist) error {
+	if checklist.LastUpdated.IsZero() {
+		checklist.LastUpdated = time.Now()
+	}
+
+	query := `INSERT INTO setup_checklists (setup_id, ` + strings.Join(setupChecklistColumns, ", ") + `)
+		VALUES (?` + strings.Repeat(", ?", len(setupChecklistColumns)) + `)`
+
+	args := append([]interface{}{checklist.SetupID}, setupChecklistArgs(checklist)...)
+	if _, err := db.conn.Exec(query, args...); err != nil {
+		return fmt.Errorf("failed to insert setup checklist: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateSetupChecklist overwrites the stored checklist for a trading setup, inserting it if none exists
+func (db *Database) UpdateSetupChecklist(checklist *models.SetupChecklist) error {
+	checklist.LastUpdated = time.Now()
+
+	query := `UPDATE setup_checklists SET ` + strings.Join(setupChecklistColumns, " = ?, ") + ` = ?
+		WHERE setup_id = ?`
+
+	args := append(setupChecklistArgs(checklist), checklist.SetupID)
+	result, err := db.conn.Exec(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update setup checklist: %w", err)
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return db.InsertSetupChecklist(checklist)
+	}
+
+	return nil
+}
+
+// GetSetupChecklist retrieves the checklist for a trading setup, or nil if none was stored
 func (db *Database) GetSetupChecklist(setupID int64) (*models.SetupChecklist, error) {
 	query := `
 		SELECT 
@@ -469,6 +723,9 @@ func (db *Database) GetSetupChecklist(setupID int64) (*models.SetupChecklist, er
 			entry_precision_completed, entry_precision_points,
 			exit_strategy_completed, exit_strategy_points,
 			
+			-- Market Context
+			short_interest_completed, short_interest_value,
+			
 			-- Summary
 			total_score, completed_items, total_items, completion_percent,
 			last_updated
@@ -517,10 +774,15 @@ func (db *Database) GetSetupChecklist(setupID int64) (*models.SetupChecklist, er
 		&entryPrecisionCompleted, &entryPrecisionPoints,
 		&exitStrategyCompleted, &exitStrategyPoints,
 
+		&checklist.ShortInterest.IsCompleted, &checklist.ShortInterest.Value,
+
 		&checklist.TotalScore, &checklist.CompletedItems, &checklist.TotalItems, &checklist.CompletionPercent,
 		&checklist.LastUpdated,
 	)
 
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get setup checklist: %w", err)
 	}
@@ -651,3 +913,54 @@ func (db *Database) GetSetupChecklist(setupID int64) (*models.SetupChecklist, er
 
 	return checklist, nil
 }
+
+// InsertSetupStopAdjustment records a stop adjustment suggested for a setup
+func (db *Database) InsertSetupStopAdjustment(adjustment *models.SetupStopAdjustment) error {
+	result, err := db.conn.Exec(`
+		INSERT INTO setup_stop_adjustments (setup_id, reason, previous_stop, suggested_stop, best_price, atr, price, suggested_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		adjustment.SetupID, adjustment.Reason, adjustment.PreviousStop, adjustment.SuggestedStop,
+		adjustment.BestPrice, adjustment.ATR, adjustment.Price, adjustment.SuggestedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert setup stop adjustment: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	adjustment.ID = id
+	return nil
+}
+
+// GetSetupStopAdjustments retrieves the stop adjustments suggested for a setup, oldest first
+func (db *Database) GetSetupStopAdjustments(setupID int64) ([]*models.SetupStopAdjustment, error) {
+	rows, err := db.conn.Query(`
+		SELECT id, setup_id, reason, previous_stop, suggested_stop, best_price, atr, price, suggested_at
+		FROM setup_stop_adjustments
+		WHERE setup_id = ?
+		ORDER BY suggested_at ASC, id ASC`, setupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query setup stop adjustments: %w", err)
+	}
+	defer rows.Close()
+
+	var adjustments []*models.SetupStopAdjustment
+	for rows.Next() {
+		adjustment := &models.SetupStopAdjustment{}
+		if err := rows.Scan(
+			&adjustment.ID, &adjustment.SetupID, &adjustment.Reason, &adjustment.PreviousStop, &adjustment.SuggestedStop,
+			&adjustment.BestPrice, &adjustment.ATR, &adjustment.Price, &adjustment.SuggestedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan setup stop adjustment: %w", err)
+		}
+		adjustments = append(adjustments, adjustment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating setup stop adjustments: %w", err)
+	}
+
+	return adjustments, nil
+}
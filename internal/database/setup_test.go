@@ -0,0 +1,157 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestSetupChecklistRoundTrip tests that a stored setup comes back with its checklist
+func TestSetupChecklistRoundTrip(t *testing.T) {
+	db := newTestDB(t)
+
+	now := time.Now()
+	setup := &models.TradingSetup{
+		Symbol: "TEST", SetupType: "support_bounce", Direction: "bullish", Confidence: "high", Status: "active",
+		QualityScore: 82, DetectedAt: now, ExpiresAt: now.Add(24 * time.Hour),
+		CurrentPrice: 100, EntryPrice: 100.5, StopLoss: 98, Target1: 105,
+	}
+	checklist := &models.SetupChecklist{
+		VolumeSpike:     models.ChecklistItem{IsCompleted: true, Points: 5},
+		StopLossDefined: models.ChecklistItem{IsCompleted: true, Points: 4.5},
+		ShortInterest:   models.ChecklistItem{IsCompleted: true, Value: "55.0% short volume"},
+		TotalScore:      9.5, CompletedItems: 2, TotalItems: 20, CompletionPercent: 10,
+	}
+
+	setup.Checklist = checklist
+	if err := db.InsertTradingSetupWithChecklist(setup); err != nil {
+		t.Fatalf("InsertTradingSetupWithChecklist failed: %v", err)
+	}
+	if checklist.SetupID != setup.ID {
+		t.Fatalf("expected the checklist to take the setup's ID %d, got %d", setup.ID, checklist.SetupID)
+	}
+
+	got, err := db.GetTradingSetupByID(setup.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetTradingSetupByID returned %v, %v", got, err)
+	}
+	if got.Checklist == nil || !got.Checklist.VolumeSpike.IsCompleted || got.Checklist.StopLossDefined.Points != 4.5 || got.Checklist.CompletedItems != 2 {
+		t.Fatalf("unexpected checklist: %+v", got.Checklist)
+	}
+	if short := got.Checklist.ShortInterest; !short.IsCompleted || short.Value != "55.0% short volume" {
+		t.Errorf("expected the short interest signal to be stored, got %+v", short)
+	}
+
+	checklist.RSICondition = models.ChecklistItem{IsCompleted: true, Points: 5}
+	checklist.CompletedItems = 3
+	if err := db.UpdateSetupChecklist(checklist); err != nil {
+		t.Fatalf("UpdateSetupChecklist failed: %v", err)
+	}
+	updated, err := db.GetSetupChecklist(setup.ID)
+	if err != nil || updated == nil || !updated.RSICondition.IsCompleted || updated.CompletedItems != 3 {
+		t.Fatalf("unexpected updated checklist: %+v, %v", updated, err)
+	}
+
+	if missing, err := db.GetSetupChecklist(setup.ID + 1); missing != nil || err != nil {
+		t.Errorf("expected no checklist for an unknown setup, got %+v, %v", missing, err)
+	}
+}
+
+// TestTradingSetupPagination tests sorting, paging and counting setups across symbols
+func TestTradingSetupPagination(t *testing.T) {
+	db := newTestDB(t)
+
+	now := time.Now()
+	for i, symbol := range []string{"AAA", "BBB", "AAA", "CCC", "BBB"} {
+		setup := &models.TradingSetup{
+			Symbol: symbol, SetupType: "breakout", Direction: "bullish", Confidence: "medium", Status: "active",
+			QualityScore: float64(60 + i), DetectedAt: now, ExpiresAt: now.Add(time.Hour),
+			CurrentPrice: 10, EntryPrice: 10, StopLoss: 9,
+		}
+		if err := db.InsertTradingSetup(setup); err != nil {
+			t.Fatalf("InsertTradingSetup failed: %v", err)
+		}
+	}
+
+	filter := &models.SetupFilter{Symbols: []string{"AAA", "BBB"}, Sort: "quality_score", Order: "asc", Limit: 2, Offset: 2}
+	setups, err := db.GetTradingSetups(filter)
+	if err != nil {
+		t.Fatalf("GetTradingSetups failed: %v", err)
+	}
+	if len(setups) != 2 || setups[0].QualityScore != 62 || setups[1].QualityScore != 64 {
+		t.Fatalf("unexpected second page: %+v", setups)
+	}
+
+	total, err := db.CountTradingSetups(filter)
+	if err != nil || total != 4 {
+		t.Fatalf("CountTradingSetups = %d, %v; want 4", total, err)
+	}
+
+	// Unknown sort fields fall back to the default ordering rather than reaching the query
+	filter = &models.SetupFilter{Sort: "quality_score; DROP TABLE trading_setups", Limit: 1}
+	if setups, err := db.GetTradingSetups(filter); err != nil || len(setups) != 1 || setups[0].QualityScore != 64 {
+		t.Fatalf("unexpected default ordering: %+v, %v", setups, err)
+	}
+}
+
+// TestStoreDetectedSetupRefreshesActiveSetup tests that a detection at the key level of an active setup of its
+// type refreshes it, and that the uniqueness constraint rejects a second active setup there
+func TestStoreDetectedSetupRefreshesActiveSetup(t *testing.T) {
+	db := newTestDB(t)
+
+	detected := time.Now().Add(-time.Hour).Truncate(time.Second)
+	setupAt := func(keyLevelID int64, score float64) *models.TradingSetup {
+		now := time.Now()
+		return &models.TradingSetup{
+			Symbol: "TEST", SetupType: "support_bounce", Direction: "bullish", Confidence: "medium", Status: "active",
+			QualityScore: score, DetectedAt: now, ExpiresAt: now.Add(24 * time.Hour), EntryPrice: 100, StopLoss: 98,
+			KeyLevelID: keyLevelID, Checklist: &models.SetupChecklist{CompletedItems: int(score / 10)},
+			ConfidenceScore:   score,
+			ConfidenceFactors: []*models.ConfidenceFactor{{Name: models.ConfidenceFactorQuality, Score: score, Weight: 100, Contribution: score}},
+			ScoreBreakdown:    &models.ScoreBreakdown{Score: score, MaxScore: 100, Items: []*models.ScoreBreakdownItem{{Key: "volume_spike", Points: score}}},
+		}
+	}
+
+	first := setupAt(7, 70)
+	first.DetectedAt = detected
+	if refreshed, err := db.StoreDetectedSetup(first); err != nil || refreshed {
+		t.Fatalf("expected the first setup to be inserted, got %v, %v", refreshed, err)
+	}
+
+	again := setupAt(7, 85)
+	refreshed, err := db.StoreDetectedSetup(again)
+	if err != nil || !refreshed || again.ID != first.ID {
+		t.Fatalf("expected the active setup %d to be refreshed, got %d, %v, %v", first.ID, again.ID, refreshed, err)
+	}
+
+	got, err := db.GetTradingSetupByID(first.ID)
+	if err != nil || got.QualityScore != 85 || got.KeyLevelID != 7 || !got.DetectedAt.Equal(detected) || got.Checklist.CompletedItems != 8 {
+		t.Fatalf("expected the refreshed setup to keep its detection time, got %+v, %v", got, err)
+	}
+	if got.ConfidenceScore != 85 || len(got.ConfidenceFactors) != 1 || got.ConfidenceFactors[0].Contribution != 85 {
+		t.Errorf("expected the refreshed confidence factors, got %.1f and %+v", got.ConfidenceScore, got.ConfidenceFactors)
+	}
+	if got.ScoreBreakdown == nil || got.ScoreBreakdown.Score != 85 || len(got.ScoreBreakdown.Items) != 1 {
+		t.Errorf("expected the refreshed score breakdown, got %+v", got.ScoreBreakdown)
+	}
+
+	// Other levels, and levels of setups that are no longer active, get setups of their own
+	if refreshed, err := db.StoreDetectedSetup(setupAt(8, 60)); err != nil || refreshed {
+		t.Errorf("expected a setup at another level to be inserted, got %v, %v", refreshed, err)
+	}
+	got.Status = "triggered"
+	if err := db.UpdateTradingSetup(got); err != nil {
+		t.Fatalf("UpdateTradingSetup failed: %v", err)
+	}
+	if refreshed, err := db.StoreDetectedSetup(setupAt(7, 75)); err != nil || refreshed {
+		t.Errorf("expected a new setup once the previous one triggered, got %v, %v", refreshed, err)
+	}
+	if count, _ := db.CountTradingSetups(&models.SetupFilter{Symbol: "TEST"}); count != 3 {
+		t.Errorf("expected 3 setups, got %d", count)
+	}
+
+	if err := db.InsertTradingSetup(setupAt(7, 50)); err == nil {
+		t.Error("expected a second active setup at the level to be rejected")
+	}
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"market-watch-go/internal/models"
+)
+
+// SetStrategyVWAPSetups stores a strategy's VWAP setup settings, removing them when neither setup type is on
+func (db *DB) SetStrategyVWAPSetups(settings *models.StrategyVWAPSetups) error {
+	if !settings.Reclaim && !settings.Rejection {
+		return db.DeleteStrategyVWAPSetups(settings.StrategyID)
+	}
+
+	columns := []string{"strategy_id", "reclaim", "rejection", "volume_multiplier", "updated_at"}
+	query := upsertQuery("strategy_vwap_setups", columns, columns[:1], columns[1:], 1)
+	_, err := db.conn.Exec(query, settings.StrategyID, settings.Reclaim, settings.Rejection, settings.VolumeMultiplier, settings.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to set VWAP setups of strategy %d: %w", settings.StrategyID, err)
+	}
+	return nil
+}
+
+// DeleteStrategyVWAPSetups turns a strategy's VWAP setups off
+func (db *DB) DeleteStrategyVWAPSetups(strategyID int) error {
+	if _, err := db.conn.Exec("DELETE FROM strategy_vwap_setups WHERE strategy_id = ?", strategyID); err != nil {
+		return fmt.Errorf("failed to delete VWAP setups of strategy %d: %w", strategyID, err)
+	}
+	return nil
+}
+
+// GetStrategyVWAPSetups retrieves a strategy's VWAP setup settings, or nil when they are off
+func (db *DB) GetStrategyVWAPSetups(strategyID int) (*models.StrategyVWAPSetups, error) {
+	row := db.conn.QueryRow(`
+		SELECT strategy_id, reclaim, rejection, volume_multiplier, updated_at
+		FROM strategy_vwap_setups
+		WHERE strategy_id = ?
+	`, strategyID)
+	settings, err := scanStrategyVWAPSetups(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return settings, err
+}
+
+// GetVWAPSetupsForSymbol retrieves the VWAP setup settings of the strategies the symbol's stock belongs to
+func (db *DB) GetVWAPSetupsForSymbol(symbol string) ([]*models.StrategyVWAPSetups, error) {
+	rows, err := db.conn.Query(`
+		SELECT vs.strategy_id, vs.reclaim, vs.rejection, vs.volume_multiplier, vs.updated_at
+		FROM strategy_vwap_setups vs
+		INNER JOIN stock_strategies ss ON ss.strategy_id = vs.strategy_id
+		INNER JOIN stocks s ON s.id = ss.stock_id
+		WHERE s.symbol = ?
+		ORDER BY vs.strategy_id
+	`, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query VWAP setups: %w", err)
+	}
+	defer rows.Close()
+
+	all := make([]*models.StrategyVWAPSetups, 0)
+	for rows.Next() {
+		settings, err := scanStrategyVWAPSetups(rows)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, settings)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating VWAP setups: %w", err)
+	}
+
+	return all, nil
+}
+
+// scanStrategyVWAPSetups scans a strategy's VWAP setup settings from a database row
+func scanStrategyVWAPSetups(row interface{ Scan(...interface{}) error }) (*models.StrategyVWAPSetups, error) {
+	settings := &models.StrategyVWAPSetups{}
+	err := row.Scan(&settings.StrategyID, &settings.Reclaim, &settings.Rejection, &settings.VolumeMultiplier, &settings.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan VWAP setups: %w", err)
+	}
+	return settings, nil
+}
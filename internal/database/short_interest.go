@@ -0,0 +1,134 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"market-watch-go/internal/models"
+)
+
+// shortVolumeColumns are the short_volume columns in scan order
+var shortVolumeColumns = []string{
+	"symbol", "trade_date", "short_volume", "short_exempt_volume", "total_volume", "short_volume_ratio",
+}
+
+// shortInterestColumns are the short_interest columns in scan order
+var shortInterestColumns = []string{
+	"symbol", "settlement_date", "short_interest", "avg_daily_volume", "days_to_cover",
+}
+
+// UpsertShortVolumes stores daily short volume, replacing the days stored before
+func (db *DB) UpsertShortVolumes(volumes []*models.ShortVolume) error {
+	rows := make([][]interface{}, len(volumes))
+	for i, v := range volumes {
+		rows[i] = []interface{}{v.Symbol, v.TradeDate, v.ShortVolume, v.ShortExemptVolume, v.TotalVolume, v.ShortVolumeRatio}
+	}
+	if err := db.upsertBatch("short_volume", shortVolumeColumns, shortVolumeColumns[:2], shortVolumeColumns[2:], rows); err != nil {
+		return fmt.Errorf("failed to upsert short volume: %w", err)
+	}
+	return nil
+}
+
+// GetShortVolumes retrieves a symbol's latest daily short volume, newest first
+func (db *DB) GetShortVolumes(symbol string, limit int) ([]*models.ShortVolume, error) {
+	rows, err := db.conn.Query("SELECT "+strings.Join(shortVolumeColumns, ", ")+
+		" FROM short_volume WHERE symbol = ? ORDER BY trade_date DESC LIMIT ?", symbol, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query short volume: %w", err)
+	}
+	defer rows.Close()
+
+	volumes := make([]*models.ShortVolume, 0)
+	for rows.Next() {
+		v := &models.ShortVolume{}
+		if err := rows.Scan(&v.Symbol, &v.TradeDate, &v.ShortVolume, &v.ShortExemptVolume, &v.TotalVolume, &v.ShortVolumeRatio); err != nil {
+			return nil, fmt.Errorf("failed to scan short volume: %w", err)
+		}
+		volumes = append(volumes, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating short volume: %w", err)
+	}
+
+	return volumes, nil
+}
+
+// GetShortVolumeDates returns the trading days since a date with short volume stored, for any symbol or for
+// one symbol when it is given
+func (db *DB) GetShortVolumeDates(symbol, since string) (map[string]bool, error) {
+	query := "SELECT DISTINCT trade_date FROM short_volume WHERE trade_date >= ?"
+	args := []interface{}{since}
+	if symbol != "" {
+		query += " AND symbol = ?"
+		args = append(args, symbol)
+	}
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query short volume dates: %w", err)
+	}
+	defer rows.Close()
+
+	dates := make(map[string]bool)
+	for rows.Next() {
+		var date string
+		if err := rows.Scan(&date); err != nil {
+			return nil, fmt.Errorf("failed to scan short volume date: %w", err)
+		}
+		dates[date] = true
+	}
+
+	return dates, rows.Err()
+}
+
+// DeleteShortVolumesBefore deletes daily short volume older than a date and returns how many rows were deleted
+func (db *DB) DeleteShortVolumesBefore(date string) (int64, error) {
+	result, err := db.conn.Exec("DELETE FROM short_volume WHERE trade_date < ?", date)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old short volume: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return deleted, nil
+}
+
+// UpsertShortInterest stores short interest reports, replacing the ones stored before for the same settlement dates
+func (db *DB) UpsertShortInterest(reports []*models.ShortInterest) error {
+	rows := make([][]interface{}, len(reports))
+	for i, r := range reports {
+		rows[i] = []interface{}{r.Symbol, r.SettlementDate, r.ShortInterest, r.AvgDailyVolume, r.DaysToCover}
+	}
+	if err := db.upsertBatch("short_interest", shortInterestColumns, shortInterestColumns[:2], shortInterestColumns[2:], rows); err != nil {
+		return fmt.Errorf("failed to upsert short interest: %w", err)
+	}
+	return nil
+}
+
+// GetShortInterest retrieves a symbol's latest short interest reports, newest first
+func (db *DB) GetShortInterest(symbol string, limit int) ([]*models.ShortInterest, error) {
+	rows, err := db.conn.Query("SELECT "+strings.Join(shortInterestColumns, ", ")+
+		" FROM short_interest WHERE symbol = ? ORDER BY settlement_date DESC LIMIT ?", symbol, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query short interest: %w", err)
+	}
+	defer rows.Close()
+
+	reports := make([]*models.ShortInterest, 0)
+	for rows.Next() {
+		r := &models.ShortInterest{}
+		if err := rows.Scan(&r.Symbol, &r.SettlementDate, &r.ShortInterest, &r.AvgDailyVolume, &r.DaysToCover); err != nil {
+			return nil, fmt.Errorf("failed to scan short interest: %w", err)
+		}
+		reports = append(reports, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating short interest: %w", err)
+	}
+
+	return reports, nil
+}
@@ -1,42 +1,67 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
 
+	_ "github.com/lib/pq"
 	_ "github.com/mattn/go-sqlite3"
 )
 
 // DB represents the database connection
 type DB struct {
-	conn *sql.DB
+	conn queryer // the pool, or the open transaction for a DB handed out by WithTx
+	pool *dbConn
+	ctx  context.Context // bounds every query of a DB handed out by WithContext
 }
 
 // New creates a new database connection
 func New(cfg *config.Config) (*DB, error) {
-	// Ensure data directory exists
-	dataDir := filepath.Dir(cfg.Database.Path)
-	if err := os.MkdirAll(dataDir, 0700); err != nil {
-		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	driver := cfg.Database.Driver
+	if driver == "" {
+		driver = DriverSQLite
 	}
 
-	conn, err := sql.Open("sqlite3", cfg.Database.Path)
+	var dataSource string
+	switch driver {
+	case DriverSQLite:
+		// Ensure data directory exists
+		dataDir := filepath.Dir(cfg.Database.Path)
+		if err := os.MkdirAll(dataDir, 0700); err != nil {
+			return nil, fmt.Errorf("failed to create data directory: %w", err)
+		}
+		dataSource = sqliteDataSource(cfg.Database)
+	case DriverPostgres:
+		dataSource = cfg.Database.DSN
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %s", driver)
+	}
+
+	sqlConn, err := sql.Open(driver, dataSource)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	conn := &dbConn{
+		DB:      sqlConn,
+		dialect: newDialect(driver),
+		writes:  &writeStats{tables: make(map[string]*models.InsertStats)},
+	}
 
 	// Set connection pool settings
 	conn.SetMaxOpenConns(cfg.Database.MaxOpenConns)
 	conn.SetMaxIdleConns(cfg.Database.MaxIdleConns)
 	conn.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
 
-	db := &DB{conn: conn}
+	db := &DB{conn: conn, pool: conn}
 
 	// Initialize database schema
 	if err := db.initSchema(); err != nil {
@@ -68,6 +93,78 @@ func New(cfg *config.Config) (*DB, error) {
 		return nil, fmt.Errorf("failed to initialize falling wedge pattern tables: %w", err)
 	}
 
+	// Initialize triangle pattern tables
+	if err := db.CreateTrianglePatternTables(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to initialize triangle pattern tables: %w", err)
+	}
+
+	// Initialize flag pattern tables
+	if err := db.CreateFlagPatternTables(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to initialize flag pattern tables: %w", err)
+	}
+
+	// Initialize links between overlapping patterns
+	if err := db.CreatePatternLinkTables(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to initialize pattern link tables: %w", err)
+	}
+
+	// Initialize alert rule tables
+	if err := db.CreateAlertRuleTables(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to initialize alert rule tables: %w", err)
+	}
+
+	// Initialize portfolio tables
+	if err := db.CreatePortfolioTables(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to initialize portfolio tables: %w", err)
+	}
+
+	// Initialize paper trading tables
+	if err := db.CreatePaperTradingTables(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to initialize paper trading tables: %w", err)
+	}
+
+	// Initialize calendar tables
+	if err := db.CreateCalendarTables(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to initialize calendar tables: %w", err)
+	}
+
+	// Initialize news tables
+	if err := db.CreateNewsTables(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to initialize news tables: %w", err)
+	}
+
+	// Initialize options tables
+	if err := db.CreateOptionsTables(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to initialize options tables: %w", err)
+	}
+
+	// Initialize screener tables
+	if err := db.CreateScreenerTables(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to initialize screener tables: %w", err)
+	}
+
+	// Initialize reference data tables
+	if err := db.CreateReferenceDataTables(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to initialize reference data tables: %w", err)
+	}
+
+	// Initialize volume profile tables
+	if err := db.CreateVolumeProfileTables(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to initialize volume profile tables: %w", err)
+	}
+
 	// Initialize watchlist tables
 	if err := db.CreateWatchlistTables(); err != nil {
 		conn.Close()
@@ -80,14 +177,79 @@ func New(cfg *config.Config) (*DB, error) {
 		return nil, fmt.Errorf("failed to initialize strategy tables: %w", err)
 	}
 
-	log.Printf("Database initialized at %s", cfg.Database.Path)
+	// Initialize stock tag and journal tables
+	if err := db.CreateJournalTables(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to initialize journal tables: %w", err)
+	}
+
+	// Initialize runtime settings tables
+	if err := db.CreateSettingsTables(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to initialize settings tables: %w", err)
+	}
+
+	// Initialize notification center tables
+	if err := db.CreateNotificationTables(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to initialize notification tables: %w", err)
+	}
+
+	// Initialize compacted hourly and daily candle tables
+	if err := db.CreateRollupTables(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to initialize rollup tables: %w", err)
+	}
+
+	// Record applied schema migrations, and apply pending ones unless an operator runs them with -migrate
+	if err := db.CreateMigrationTables(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to initialize migration tables: %w", err)
+	}
+	if !cfg.Database.ManualMigrations {
+		if _, err := db.Migrate(); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to apply schema migrations: %w", err)
+		}
+	}
+
+	if driver == DriverPostgres {
+		log.Printf("Database initialized (postgres)")
+	} else {
+		log.Printf("Database initialized at %s", cfg.Database.Path)
+	}
 	return db, nil
 }
 
+// sqliteDataSource adds the connection settings to the database path: write-ahead logging, so API reads don't
+// wait for collection writes, and a busy timeout, so concurrent writers wait for the lock instead of failing.
+// They are DSN parameters rather than PRAGMAs because the busy timeout applies per pooled connection.
+func sqliteDataSource(cfg config.DatabaseConfig) string {
+	journalMode := cfg.JournalMode
+	if journalMode == "" {
+		journalMode = "WAL"
+	}
+	busyTimeout := cfg.BusyTimeout
+	if busyTimeout <= 0 {
+		busyTimeout = 5 * time.Second
+	}
+
+	separator := "?"
+	if strings.Contains(cfg.Path, "?") {
+		separator = "&"
+	}
+	params := fmt.Sprintf("_journal_mode=%s&_busy_timeout=%d", journalMode, busyTimeout.Milliseconds())
+	if strings.EqualFold(journalMode, "WAL") {
+		// Safe with WAL: a power loss can drop the last commits but never corrupts the database
+		params += "&_synchronous=NORMAL"
+	}
+	return cfg.Path + separator + params
+}
+
 // Close closes the database connection
 func (db *DB) Close() error {
-	if db.conn != nil {
-		return db.conn.Close()
+	if db.pool != nil {
+		return db.pool.Close()
 	}
 	return nil
 }
@@ -139,6 +301,13 @@ func (db *DB) initSchema() error {
 		bb_upper REAL,
 		bb_middle REAL,
 		bb_lower REAL,
+		atr_14 REAL,
+		stoch_k REAL,
+		stoch_d REAL,
+		adx_14 REAL,
+		plus_di REAL,
+		minus_di REAL,
+		obv REAL,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		UNIQUE(symbol, timestamp)
 	);
@@ -158,31 +327,6 @@ func (db *DB) initSchema() error {
 		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);
 
-	-- Trading setups table
-	CREATE TABLE IF NOT EXISTS trading_setups (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		symbol TEXT NOT NULL,
-		setup_type TEXT NOT NULL,
-		direction TEXT NOT NULL CHECK (direction IN ('long', 'short')),
-		current_price REAL,
-		entry_price REAL,
-		stop_loss REAL,
-		target1 REAL,
-		target2 REAL,
-		target3 REAL,
-		risk_reward_ratio REAL,
-		confidence TEXT CHECK (confidence IN ('low', 'medium', 'high')),
-		quality_score REAL,
-		checklist_items TEXT,
-		checklist_score INTEGER,
-		status TEXT DEFAULT 'pending' CHECK (status IN ('pending', 'active', 'filled', 'stopped', 'completed', 'expired')),
-		notes TEXT,
-		detected_at DATETIME NOT NULL,
-		expires_at DATETIME,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-	);
-
 	-- Watched symbols table
 	CREATE TABLE IF NOT EXISTS watched_symbols (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -207,11 +351,8 @@ func (db *DB) initSchema() error {
 	);
 
 	-- Create indexes for better performance
-	CREATE INDEX IF NOT EXISTS idx_price_data_symbol_timestamp ON price_data(symbol, timestamp);
-	CREATE INDEX IF NOT EXISTS idx_volume_data_symbol_timestamp ON volume_data(symbol, timestamp);
 	CREATE INDEX IF NOT EXISTS idx_technical_indicators_symbol_timestamp ON technical_indicators(symbol, timestamp);
 	CREATE INDEX IF NOT EXISTS idx_support_resistance_symbol_active ON support_resistance_levels(symbol, is_active);
-	CREATE INDEX IF NOT EXISTS idx_trading_setups_symbol_status ON trading_setups(symbol, status, detected_at);
 	CREATE INDEX IF NOT EXISTS idx_watched_symbols_active ON watched_symbols(is_active);
 	CREATE INDEX IF NOT EXISTS idx_indicator_alerts_symbol ON indicator_alerts(symbol);
 	CREATE INDEX IF NOT EXISTS idx_indicator_alerts_active ON indicator_alerts(is_active);
@@ -249,7 +390,7 @@ func (db *DB) EnsureConfigSymbolsWatched(configSymbols []string) error {
 
 // GetWatchedSymbols returns all active watched symbols
 func (db *DB) GetWatchedSymbols() ([]string, error) {
-	query := `SELECT symbol FROM watched_symbols WHERE is_active = 1 ORDER BY symbol`
+	query := `SELECT symbol FROM watched_symbols WHERE is_active = TRUE ORDER BY symbol`
 
 	rows, err := db.conn.Query(query)
 	if err != nil {
@@ -275,7 +416,7 @@ func (db *DB) GetWatchedSymbols() ([]string, error) {
 
 // AddWatchedSymbol adds a new symbol to watch
 func (db *DB) AddWatchedSymbol(symbol, name string) error {
-	query := `INSERT OR REPLACE INTO watched_symbols (symbol, name, is_active) VALUES (?, ?, 1)`
+	query := `INSERT OR REPLACE INTO watched_symbols (symbol, name, is_active) VALUES (?, ?, TRUE)`
 
 	_, err := db.conn.Exec(query, symbol, name)
 	if err != nil {
@@ -287,7 +428,7 @@ func (db *DB) AddWatchedSymbol(symbol, name string) error {
 
 // RemoveWatchedSymbol removes a symbol from being watched
 func (db *DB) RemoveWatchedSymbol(symbol string) error {
-	query := `UPDATE watched_symbols SET is_active = 0 WHERE symbol = ?`
+	query := `UPDATE watched_symbols SET is_active = FALSE WHERE symbol = ?`
 
 	result, err := db.conn.Exec(query, symbol)
 	if err != nil {
@@ -300,7 +441,7 @@ func (db *DB) RemoveWatchedSymbol(symbol string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("symbol %s not found", symbol)
+		return fmt.Errorf("symbol %s %w", symbol, ErrNotFound)
 	}
 
 	return nil
@@ -312,6 +453,8 @@ func (db *DB) GetDatabaseStats() (map[string]int64, error) {
 
 	tables := []string{
 		"price_data",
+		"price_data_1h",
+		"price_data_1d",
 		"volume_data",
 		"technical_indicators",
 		"support_resistance_levels",
@@ -335,14 +478,24 @@ func (db *DB) GetDatabaseStats() (map[string]int64, error) {
 
 // runMigrations applies database migrations for schema updates
 func (db *DB) runMigrations() error {
-	// Add current_price column to trading_setups if it doesn't exist
-	alterQuery := `ALTER TABLE trading_setups ADD COLUMN current_price REAL`
+	// --- MIGRATION: Move the legacy trading_setups table out of the way ---
+	// Older databases created trading_setups with 'long'/'short' directions and a JSON checklist
+	// column, which CreateSetupTables' schema replaces. Keep the old rows in trading_setups_legacy.
+	var legacyChecklist sql.NullString
+	err := db.conn.QueryRow(`SELECT checklist_items FROM trading_setups LIMIT 1`).Scan(&legacyChecklist)
+	if err == nil || err == sql.ErrNoRows {
+		if _, err := db.conn.Exec(`ALTER TABLE trading_setups RENAME TO trading_setups_legacy`); err != nil {
+			log.Printf("Warning: Failed to rename legacy trading_setups table: %v", err)
+		} else {
+			log.Printf("Renamed legacy trading_setups table to trading_setups_legacy")
+		}
+	}
 
-	// Check if column already exists by trying to add it
-	// SQLite will return an error if column already exists, which we can ignore
-	_, err := db.conn.Exec(alterQuery)
-	if err != nil && !strings.Contains(err.Error(), "duplicate column name") {
-		log.Printf("Warning: Failed to add current_price column (might already exist): %v", err)
+	// --- MIGRATION: Deduplicate bars in tables created without UNIQUE(symbol, timestamp) ---
+	for _, table := range []string{"price_data", "volume_data"} {
+		if err := db.ensureUniqueBars(table); err != nil {
+			return err
+		}
 	}
 
 	// --- MIGRATION: Add missing columns to watchlist_stocks ---
@@ -395,10 +548,20 @@ func (db *DB) runMigrations() error {
 	}
 	// Document: SMA columns are deprecated and ignored in code.
 
+	// --- MIGRATION: Add ATR, Stochastic, ADX and OBV columns to technical_indicators ---
+	indicatorCols := []string{"atr_14", "stoch_k", "stoch_d", "adx_14", "plus_di", "minus_di", "obv"}
+	for _, col := range indicatorCols {
+		alter := "ALTER TABLE technical_indicators ADD COLUMN " + col + " REAL DEFAULT 0"
+		_, err := db.conn.Exec(alter)
+		if err != nil && !strings.Contains(err.Error(), "duplicate column name") && !strings.Contains(err.Error(), "already exists") {
+			log.Printf("Warning: Failed to add column '%s' to technical_indicators: %v", col, err)
+		}
+	}
+
 	return nil
 }
 
 // Ping checks if the database connection is alive
 func (db *DB) Ping() error {
-	return db.conn.Ping()
+	return db.pool.Ping()
 }
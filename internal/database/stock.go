@@ -13,10 +13,13 @@ func (db *Database) RefreshStock(symbol string, price, ema9, ema50, ema200 float
 
 // DeleteStock deletes a stock from the stocks table
 func (db *Database) DeleteStock(stockID int) error {
-	// First remove all strategy associations
+	// First remove all strategy associations and tags; journal entries are kept by symbol
 	if err := db.RemoveAllStockStrategies(stockID); err != nil {
 		return err
 	}
+	if err := db.RemoveAllStockTags(stockID); err != nil {
+		return err
+	}
 
 	// Then delete the stock
 	query := `DELETE FROM stocks WHERE id = ?`
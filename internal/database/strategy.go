@@ -149,6 +149,41 @@ func (db *Database) StrategyExists(id int) (bool, error) {
 	return count > 0, nil
 }
 
+// EnsureStrategy returns the ID of the strategy with the given name, creating it when there is none
+func (db *Database) EnsureStrategy(name, color string) (int, error) {
+	var id int
+	err := db.conn.QueryRow("SELECT id FROM strategies WHERE name = ?", name).Scan(&id)
+	if err == nil {
+		return id, nil
+	}
+	if err != sql.ErrNoRows {
+		return 0, fmt.Errorf("failed to get strategy %s: %w", name, err)
+	}
+
+	strategy, err := db.CreateStrategy(models.Strategy{Name: name, Color: color})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create strategy %s: %w", name, err)
+	}
+	return strategy.ID, nil
+}
+
+// SetStrategySymbols makes the stocks of the given symbols a strategy's only stocks, adding symbols that have
+// no watchlist stock yet
+func (db *Database) SetStrategySymbols(strategyID int, symbols []string) error {
+	return db.WithTx(func(tx *DB) error {
+		if _, err := tx.conn.Exec("DELETE FROM stock_strategies WHERE strategy_id = ?", strategyID); err != nil {
+			return fmt.Errorf("failed to clear strategy %d: %w", strategyID, err)
+		}
+		for _, symbol := range symbols {
+			stock := models.Stock{Symbol: symbol, Strategies: []models.Strategy{{ID: strategyID}}}
+			if _, err := tx.AddStock(stock); err != nil {
+				return fmt.Errorf("failed to add %s to strategy %d: %w", symbol, strategyID, err)
+			}
+		}
+		return nil
+	})
+}
+
 // DeleteStrategy deletes a strategy and removes all associations
 func (db *Database) DeleteStrategy(id int) error {
 	// First delete all associations in the stock_strategies table
@@ -158,6 +193,20 @@ func (db *Database) DeleteStrategy(id int) error {
 		return err
 	}
 
+	// Its automations, auto trading, VWAP setups and regimes go with it
+	if err := db.DeleteStrategyAutomations(id); err != nil {
+		return err
+	}
+	if err := db.SetStrategyAutoTrade(id, false); err != nil {
+		return err
+	}
+	if err := db.DeleteStrategyVWAPSetups(id); err != nil {
+		return err
+	}
+	if err := db.DeleteStrategyRegimes(id); err != nil {
+		return err
+	}
+
 	// Then delete the strategy
 	deleteStrategyQuery := `DELETE FROM strategies WHERE id = ?`
 	_, err = db.conn.Exec(deleteStrategyQuery, id)
@@ -200,6 +249,12 @@ func (db *Database) GetStocks() ([]models.Stock, error) {
 		}
 		stock.Strategies = strategies
 
+		tags, err := db.GetStockTags(stock.ID)
+		if err != nil {
+			return nil, err
+		}
+		stock.Tags = tags
+
 		stocks = append(stocks, stock)
 	}
 
@@ -234,6 +289,13 @@ func (db *Database) GetStocksByStrategy(strategyID int) ([]models.Stock, error)
 		if err != nil {
 			return nil, err
 		}
+
+		tags, err := db.GetStockTags(stock.ID)
+		if err != nil {
+			return nil, err
+		}
+		stock.Tags = tags
+
 		stocks = append(stocks, stock)
 	}
 
@@ -247,58 +309,54 @@ func (db *Database) AddStock(stock models.Stock) (*models.Stock, error) {
 		return nil, fmt.Errorf("failed to check for existing stock: %v", err)
 	}
 
-	// If stock exists, just add strategies to it
+	// If stock exists, just add strategies and tags to it
 	if existingStock != nil {
 		for _, strategy := range stock.Strategies {
 			err := db.AddStockToStrategy(existingStock.ID, strategy.ID)
 			if err != nil {
 				// Ignore unique constraint errors, as the association may already exist
-				if !strings.Contains(err.Error(), "UNIQUE constraint failed") {
+				if !isUniqueViolation(err) {
 					return nil, fmt.Errorf("failed to add stock to strategy: %v", err)
 				}
 			}
 		}
+		if err := db.AddStockTags(existingStock.ID, stock.Tags); err != nil {
+			return nil, err
+		}
 		return db.GetStockBySymbol(stock.Symbol) // Return the updated stock
 	}
 
 	// If stock doesn't exist, create it and then add strategies
-	tx, err := db.conn.Begin()
-	if err != nil {
-		return nil, err
-	}
-
-	query := `
-		INSERT INTO stocks (symbol, name, notes, price, change, change_percent, volume, market_cap)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-	`
-	result, err := tx.Exec(query,
-		strings.ToUpper(stock.Symbol), stock.Name, stock.Notes,
-		stock.Price, stock.Change, stock.ChangePercent,
-		stock.Volume, stock.MarketCap,
-	)
-	if err != nil {
-		tx.Rollback()
-		return nil, err
-	}
-
-	id, err := result.LastInsertId()
-	if err != nil {
-		tx.Rollback()
-		return nil, err
-	}
-
-	stock.ID = int(id)
+	err = db.WithTx(func(tx *DB) error {
+		query := `
+			INSERT INTO stocks (symbol, name, notes, price, change, change_percent, volume, market_cap)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`
+		result, err := tx.conn.Exec(query,
+			strings.ToUpper(stock.Symbol), stock.Name, stock.Notes,
+			stock.Price, stock.Change, stock.ChangePercent,
+			stock.Volume, stock.MarketCap,
+		)
+		if err != nil {
+			return err
+		}
 
-	// Add strategies
-	for _, strategy := range stock.Strategies {
-		_, err := tx.Exec("INSERT INTO stock_strategies (stock_id, strategy_id) VALUES (?, ?)", stock.ID, strategy.ID)
+		id, err := result.LastInsertId()
 		if err != nil {
-			tx.Rollback()
-			return nil, err
+			return err
 		}
-	}
 
-	if err := tx.Commit(); err != nil {
+		stock.ID = int(id)
+
+		// Add strategies
+		for _, strategy := range stock.Strategies {
+			if _, err := tx.conn.Exec("INSERT INTO stock_strategies (stock_id, strategy_id) VALUES (?, ?)", stock.ID, strategy.ID); err != nil {
+				return err
+			}
+		}
+		return tx.AddStockTags(stock.ID, stock.Tags)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -357,9 +415,22 @@ func (db *Database) GetStockBySymbol(symbol string) (*models.Stock, error) {
 	}
 	stock.Strategies = strategies
 
+	tags, err := db.GetStockTags(stock.ID)
+	if err != nil {
+		return nil, err
+	}
+	stock.Tags = tags
+
 	return &stock, nil
 }
 
+// GetStockSymbol returns the symbol of a watchlist stock, or sql.ErrNoRows if it doesn't exist
+func (db *Database) GetStockSymbol(id int) (string, error) {
+	var symbol string
+	err := db.conn.QueryRow("SELECT symbol FROM stocks WHERE id = ?", id).Scan(&symbol)
+	return symbol, err
+}
+
 // Stock-Strategy Relationship Operations
 
 func (db *Database) AddStockToStrategy(stockID, strategyID int) error {
@@ -0,0 +1,330 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// InsertStrategyAutomation inserts a new strategy automation
+func (db *DB) InsertStrategyAutomation(automation *models.StrategyAutomation) error {
+	patternsJSON, conditionsJSON, err := marshalAutomation(automation)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	automation.CreatedAt = now
+	automation.UpdatedAt = now
+
+	query := `
+		INSERT INTO strategy_automations (
+			strategy_id, name, action, patterns, conditions, logic, schedule, is_active,
+			cooldown_minutes, last_run_at, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := db.conn.Exec(query,
+		automation.StrategyID, automation.Name, automation.Action, patternsJSON, conditionsJSON, automation.Logic,
+		automation.Schedule, automation.IsActive, automation.CooldownMinutes, automation.LastRunAt,
+		automation.CreatedAt, automation.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert strategy automation: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	automation.ID = id
+	return nil
+}
+
+const strategyAutomationColumns = `id, strategy_id, name, action, patterns, conditions, logic, schedule, is_active,
+	cooldown_minutes, last_run_at, created_at, updated_at`
+
+// GetStrategyAutomations retrieves the automations of a strategy, or of every strategy when strategyID is 0,
+// optionally only the active ones
+func (db *DB) GetStrategyAutomations(strategyID int, activeOnly bool) ([]*models.StrategyAutomation, error) {
+	query := "SELECT " + strategyAutomationColumns + " FROM strategy_automations WHERE 1=1"
+	args := []interface{}{}
+	if strategyID != 0 {
+		query += " AND strategy_id = ?"
+		args = append(args, strategyID)
+	}
+	if activeOnly {
+		query += " AND is_active = ?"
+		args = append(args, true)
+	}
+	query += " ORDER BY id ASC"
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query strategy automations: %w", err)
+	}
+	defer rows.Close()
+
+	automations := make([]*models.StrategyAutomation, 0)
+	for rows.Next() {
+		automation, err := scanStrategyAutomation(rows)
+		if err != nil {
+			return nil, err
+		}
+		automations = append(automations, automation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating strategy automations: %w", err)
+	}
+
+	return automations, nil
+}
+
+// GetStrategyAutomationByID retrieves a specific strategy automation, or nil if there is none
+func (db *DB) GetStrategyAutomationByID(id int64) (*models.StrategyAutomation, error) {
+	row := db.conn.QueryRow("SELECT "+strategyAutomationColumns+" FROM strategy_automations WHERE id = ?", id)
+
+	automation, err := scanStrategyAutomation(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return automation, err
+}
+
+// UpdateStrategyAutomation updates an existing strategy automation
+func (db *DB) UpdateStrategyAutomation(automation *models.StrategyAutomation) error {
+	patternsJSON, conditionsJSON, err := marshalAutomation(automation)
+	if err != nil {
+		return err
+	}
+
+	automation.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE strategy_automations SET
+			name = ?, action = ?, patterns = ?, conditions = ?, logic = ?, schedule = ?, is_active = ?,
+			cooldown_minutes = ?, last_run_at = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	_, err = db.conn.Exec(query,
+		automation.Name, automation.Action, patternsJSON, conditionsJSON, automation.Logic, automation.Schedule,
+		automation.IsActive, automation.CooldownMinutes, automation.LastRunAt, automation.UpdatedAt, automation.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update strategy automation: %w", err)
+	}
+
+	return nil
+}
+
+// SetStrategyAutomationLastRun records when a strategy automation last ran
+func (db *DB) SetStrategyAutomationLastRun(id int64, lastRunAt time.Time) error {
+	if _, err := db.conn.Exec("UPDATE strategy_automations SET last_run_at = ? WHERE id = ?", lastRunAt, id); err != nil {
+		return fmt.Errorf("failed to update strategy automation last run: %w", err)
+	}
+	return nil
+}
+
+// DeleteStrategyAutomation deletes a strategy automation and its run history
+func (db *DB) DeleteStrategyAutomation(id int64) error {
+	if _, err := db.conn.Exec("DELETE FROM strategy_automation_runs WHERE automation_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete strategy automation runs: %w", err)
+	}
+
+	result, err := db.conn.Exec("DELETE FROM strategy_automations WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete strategy automation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("strategy automation %w", ErrNotFound)
+	}
+
+	return nil
+}
+
+// DeleteStrategyAutomations deletes every automation of a strategy and their run history
+func (db *DB) DeleteStrategyAutomations(strategyID int) error {
+	if _, err := db.conn.Exec("DELETE FROM strategy_automation_runs WHERE strategy_id = ?", strategyID); err != nil {
+		return fmt.Errorf("failed to delete strategy automation runs: %w", err)
+	}
+	if _, err := db.conn.Exec("DELETE FROM strategy_automations WHERE strategy_id = ?", strategyID); err != nil {
+		return fmt.Errorf("failed to delete strategy automations: %w", err)
+	}
+	return nil
+}
+
+// InsertStrategyAutomationRun records a run of a strategy automation
+func (db *DB) InsertStrategyAutomationRun(run *models.StrategyAutomationRun) error {
+	matchesJSON, err := json.Marshal(run.Matches)
+	if err != nil {
+		return fmt.Errorf("failed to marshal automation matches: %w", err)
+	}
+	errorsJSON, err := json.Marshal(run.Errors)
+	if err != nil {
+		return fmt.Errorf("failed to marshal automation errors: %w", err)
+	}
+
+	query := `
+		INSERT INTO strategy_automation_runs (
+			automation_id, automation_name, strategy_id, action, run_trigger, status, symbols,
+			matches, errors, error, started_at, duration_ms
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := db.conn.Exec(query,
+		run.AutomationID, run.AutomationName, run.StrategyID, run.Action, run.Trigger, run.Status, run.Symbols,
+		string(matchesJSON), string(errorsJSON), run.Error, run.StartedAt, run.DurationMillis,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert strategy automation run: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	run.ID = id
+	return nil
+}
+
+// GetStrategyAutomationRuns retrieves automation run history, newest first
+func (db *DB) GetStrategyAutomationRuns(filter *models.StrategyAutomationRunFilter) ([]*models.StrategyAutomationRun, error) {
+	query := `SELECT id, automation_id, automation_name, strategy_id, action, run_trigger, status, symbols,
+		matches, errors, error, started_at, duration_ms
+		FROM strategy_automation_runs WHERE 1=1`
+	args := []interface{}{}
+
+	limit := 100
+	if filter != nil {
+		if filter.StrategyID > 0 {
+			query += " AND strategy_id = ?"
+			args = append(args, filter.StrategyID)
+		}
+		if filter.AutomationID > 0 {
+			query += " AND automation_id = ?"
+			args = append(args, filter.AutomationID)
+		}
+		if !filter.Since.IsZero() {
+			query += " AND started_at >= ?"
+			args = append(args, filter.Since)
+		}
+		if filter.Limit > 0 {
+			limit = filter.Limit
+		}
+	}
+
+	query += " ORDER BY started_at DESC, id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query strategy automation runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := make([]*models.StrategyAutomationRun, 0)
+	for rows.Next() {
+		run := &models.StrategyAutomationRun{}
+		var matchesJSON, errorsJSON string
+
+		err := rows.Scan(
+			&run.ID, &run.AutomationID, &run.AutomationName, &run.StrategyID, &run.Action, &run.Trigger,
+			&run.Status, &run.Symbols, &matchesJSON, &errorsJSON, &run.Error, &run.StartedAt, &run.DurationMillis,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan strategy automation run: %w", err)
+		}
+
+		if err := json.Unmarshal([]byte(matchesJSON), &run.Matches); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal automation matches: %w", err)
+		}
+		if err := json.Unmarshal([]byte(errorsJSON), &run.Errors); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal automation errors: %w", err)
+		}
+		if run.Matches == nil {
+			run.Matches = []models.AutomationMatch{}
+		}
+
+		runs = append(runs, run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating strategy automation runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+// marshalAutomation encodes an automation's patterns and conditions for storage
+func marshalAutomation(automation *models.StrategyAutomation) (string, string, error) {
+	patterns := automation.Patterns
+	if patterns == nil {
+		patterns = []string{}
+	}
+	patternsJSON, err := json.Marshal(patterns)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal automation patterns: %w", err)
+	}
+
+	conditions := automation.Conditions
+	if conditions == nil {
+		conditions = []models.AlertCondition{}
+	}
+	conditionsJSON, err := json.Marshal(conditions)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal automation conditions: %w", err)
+	}
+
+	return string(patternsJSON), string(conditionsJSON), nil
+}
+
+// scanStrategyAutomation scans a strategy automation from a database row
+func scanStrategyAutomation(row interface{ Scan(...interface{}) error }) (*models.StrategyAutomation, error) {
+	automation := &models.StrategyAutomation{}
+	var patternsJSON, conditionsJSON string
+	var lastRunAt sql.NullTime
+
+	err := row.Scan(
+		&automation.ID, &automation.StrategyID, &automation.Name, &automation.Action, &patternsJSON,
+		&conditionsJSON, &automation.Logic, &automation.Schedule, &automation.IsActive,
+		&automation.CooldownMinutes, &lastRunAt, &automation.CreatedAt, &automation.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan strategy automation: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(patternsJSON), &automation.Patterns); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal automation patterns: %w", err)
+	}
+	if err := json.Unmarshal([]byte(conditionsJSON), &automation.Conditions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal automation conditions: %w", err)
+	}
+	if len(automation.Patterns) == 0 {
+		automation.Patterns = nil
+	}
+	if len(automation.Conditions) == 0 {
+		automation.Conditions = nil
+	}
+
+	if lastRunAt.Valid {
+		automation.LastRunAt = &lastRunAt.Time
+	}
+
+	return automation, nil
+}
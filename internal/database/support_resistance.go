@@ -112,14 +112,18 @@ func (db *DB) CreateSupportResistanceTables() error {
 func (db *DB) InsertSupportResistanceLevel(level *models.SupportResistanceLevel) error {
 	query := `
 		INSERT INTO support_resistance_levels 
-		(symbol, level, level_type, strength, touches, first_touch, last_touch, 
+		(symbol, level, level_type, level_class, fib_ratio, strength, touches, first_touch, last_touch, 
 		 volume_confirmed, avg_volume, max_bounce_percent, avg_bounce_percent, 
 		 timeframe_origin, is_active, last_validated, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
+	if level.LevelClass == "" {
+		level.LevelClass = models.SRClassPivot
+	}
+
 	result, err := db.conn.Exec(query,
-		level.Symbol, level.Level, level.LevelType, level.Strength, level.Touches,
+		level.Symbol, level.Level, level.LevelType, level.LevelClass, level.FibRatio, level.Strength, level.Touches,
 		level.FirstTouch, level.LastTouch, level.VolumeConfirmed, level.AvgVolume,
 		level.MaxBouncePercent, level.AvgBouncePercent, level.TimeframeOrigin,
 		level.IsActive, level.LastValidated, level.CreatedAt, level.UpdatedAt,
@@ -138,6 +142,24 @@ func (db *DB) InsertSupportResistanceLevel(level *models.SupportResistanceLevel)
 	return nil
 }
 
+// ReplaceFibonacciLevels replaces a symbol's Fibonacci levels with the levels of its latest swing
+func (db *DB) ReplaceFibonacciLevels(symbol string, levels []*models.SupportResistanceLevel) error {
+	return db.WithTx(func(tx *DB) error {
+		if _, err := tx.conn.Exec(
+			`DELETE FROM support_resistance_levels WHERE symbol = ? AND level_class = ?`, symbol, models.SRClassFibonacci,
+		); err != nil {
+			return fmt.Errorf("failed to delete Fibonacci levels: %w", err)
+		}
+		for _, level := range levels {
+			level.LevelClass = models.SRClassFibonacci
+			if err := tx.InsertSupportResistanceLevel(level); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // UpdateSupportResistanceLevel updates an existing S/R level
 func (db *DB) UpdateSupportResistanceLevel(level *models.SupportResistanceLevel) error {
 	query := `
@@ -165,109 +187,123 @@ func (db *DB) UpdateSupportResistanceLevel(level *models.SupportResistanceLevel)
 
 // GetSupportResistanceLevels retrieves S/R levels based on filter criteria
 func (db *DB) GetSupportResistanceLevels(filter *models.SRDetectionFilter) ([]*models.SupportResistanceLevel, error) {
+	where, args := srLevelWhere(filter)
 	query := `
-		SELECT id, symbol, level, level_type, strength, touches, first_touch, last_touch,
+		SELECT id, symbol, level, level_type, level_class, fib_ratio, strength, touches, first_touch, last_touch,
 		       volume_confirmed, avg_volume, max_bounce_percent, avg_bounce_percent,
 		       timeframe_origin, is_active, last_validated, created_at, updated_at
-		FROM support_resistance_levels 
-		WHERE symbol = ?
-	`
+		FROM support_resistance_levels` + where
+
+	// Order by strength descending unless another sort was requested
+	query += orderBy(filter.Sort, filter.Order, models.SRLevelSortFields, "strength DESC, touches DESC")
+	query, args = limitOffset(query, args, filter.Limit, filter.Offset)
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query S/R levels: %w", err)
+	}
+	defer rows.Close()
+
+	var levels []*models.SupportResistanceLevel
+	for rows.Next() {
+		level := &models.SupportResistanceLevel{}
+		err := rows.Scan(
+			&level.ID, &level.Symbol, &level.Level, &level.LevelType, &level.LevelClass, &level.FibRatio,
+			&level.Strength, &level.Touches, &level.FirstTouch, &level.LastTouch,
+			&level.VolumeConfirmed, &level.AvgVolume, &level.MaxBouncePercent,
+			&level.AvgBouncePercent, &level.TimeframeOrigin, &level.IsActive,
+			&level.LastValidated, &level.CreatedAt, &level.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan S/R level: %w", err)
+		}
+		levels = append(levels, level)
+	}
+
+	return levels, nil
+}
+
+// CountSupportResistanceLevels counts the levels matching a filter, ignoring its limit and offset
+func (db *DB) CountSupportResistanceLevels(filter *models.SRDetectionFilter) (int, error) {
+	where, args := srLevelWhere(filter)
+	return db.countRows("support_resistance_levels", where, args)
+}
+
+// srLevelWhere builds the WHERE clause for an SRDetectionFilter
+func srLevelWhere(filter *models.SRDetectionFilter) (string, []interface{}) {
+	where := " WHERE symbol = ?"
 	args := []interface{}{filter.Symbol}
 
+	// Detected pivot levels unless another class is asked for
+	switch filter.LevelClass {
+	case models.SRClassAll:
+	case "":
+		where += " AND level_class = ?"
+		args = append(args, models.SRClassPivot)
+	default:
+		where += " AND level_class = ?"
+		args = append(args, filter.LevelClass)
+	}
+
 	// Add optional filters
 	if filter.LevelType != "" && filter.LevelType != "both" {
-		query += " AND level_type = ?"
+		where += " AND level_type = ?"
 		args = append(args, filter.LevelType)
 	}
 
 	if filter.MinStrength > 0 {
-		query += " AND strength >= ?"
+		where += " AND strength >= ?"
 		args = append(args, filter.MinStrength)
 	}
 
 	if filter.MaxStrength > 0 {
-		query += " AND strength <= ?"
+		where += " AND strength <= ?"
 		args = append(args, filter.MaxStrength)
 	}
 
 	if filter.IsActive != nil {
-		query += " AND is_active = ?"
+		where += " AND is_active = ?"
 		args = append(args, *filter.IsActive)
 	}
 
 	if filter.MinTouches > 0 {
-		query += " AND touches >= ?"
+		where += " AND touches >= ?"
 		args = append(args, filter.MinTouches)
 	}
 
 	if filter.PriceRange.Min > 0 {
-		query += " AND level >= ?"
+		where += " AND level >= ?"
 		args = append(args, filter.PriceRange.Min)
 	}
 
 	if filter.PriceRange.Max > 0 {
-		query += " AND level <= ?"
+		where += " AND level <= ?"
 		args = append(args, filter.PriceRange.Max)
 	}
 
-	// Order by strength descending
-	query += " ORDER BY strength DESC, touches DESC"
-
-	if filter.Limit > 0 {
-		query += " LIMIT ?"
-		args = append(args, filter.Limit)
-	}
-
-	if filter.Offset > 0 {
-		query += " OFFSET ?"
-		args = append(args, filter.Offset)
-	}
-
-	rows, err := db.conn.Query(query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query S/R levels: %w", err)
-	}
-	defer rows.Close()
-
-	var levels []*models.SupportResistanceLevel
-	for rows.Next() {
-		level := &models.SupportResistanceLevel{}
-		err := rows.Scan(
-			&level.ID, &level.Symbol, &level.Level, &level.LevelType,
-			&level.Strength, &level.Touches, &level.FirstTouch, &level.LastTouch,
-			&level.VolumeConfirmed, &level.AvgVolume, &level.MaxBouncePercent,
-			&level.AvgBouncePercent, &level.TimeframeOrigin, &level.IsActive,
-			&level.LastValidated, &level.CreatedAt, &level.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan S/R level: %w", err)
-		}
-		levels = append(levels, level)
-	}
-
-	return levels, nil
+	return where, args
 }
 
 // GetNearestSupportResistance finds the nearest support and resistance levels to current price
 func (db *DB) GetNearestSupportResistance(symbol string, currentPrice float64) (*models.SupportResistanceLevel, *models.SupportResistanceLevel, error) {
 	// Find nearest support (below current price)
 	supportQuery := `
-		SELECT id, symbol, level, level_type, strength, touches, first_touch, last_touch,
+		SELECT id, symbol, level, level_type, level_class, fib_ratio, strength, touches, first_touch, last_touch,
 		       volume_confirmed, avg_volume, max_bounce_percent, avg_bounce_percent,
 		       timeframe_origin, is_active, last_validated, created_at, updated_at
 		FROM support_resistance_levels 
-		WHERE symbol = ? AND level_type = 'support' AND level < ? AND is_active = TRUE
+		WHERE symbol = ? AND level_type = 'support' AND level_class = 'pivot' AND level < ? AND is_active = TRUE
 		ORDER BY level DESC
 		LIMIT 1
 	`
 
 	// Find nearest resistance (above current price)
 	resistanceQuery := `
-		SELECT id, symbol, level, level_type, strength, touches, first_touch, last_touch,
+		SELECT id, symbol, level, level_type, level_class, fib_ratio, strength, touches, first_touch, last_touch,
 		       volume_confirmed, avg_volume, max_bounce_percent, avg_bounce_percent,
 		       timeframe_origin, is_active, last_validated, created_at, updated_at
 		FROM support_resistance_levels 
-		WHERE symbol = ? AND level_type = 'resistance' AND level > ? AND is_active = TRUE
+		WHERE symbol = ? AND level_type = 'resistance' AND level_class = 'pivot' AND level > ? AND is_active = TRUE
 		ORDER BY level ASC
 		LIMIT 1
 	`
@@ -278,7 +314,7 @@ func (db *DB) GetNearestSupportResistance(symbol string, currentPrice float64) (
 	row := db.conn.QueryRow(supportQuery, symbol, currentPrice)
 	support := &models.SupportResistanceLevel{}
 	err := row.Scan(
-		&support.ID, &support.Symbol, &support.Level, &support.LevelType,
+		&support.ID, &support.Symbol, &support.Level, &support.LevelType, &support.LevelClass, &support.FibRatio,
 		&support.Strength, &support.Touches, &support.FirstTouch, &support.LastTouch,
 		&support.VolumeConfirmed, &support.AvgVolume, &support.MaxBouncePercent,
 		&support.AvgBouncePercent, &support.TimeframeOrigin, &support.IsActive,
@@ -295,7 +331,7 @@ func (db *DB) GetNearestSupportResistance(symbol string, currentPrice float64) (
 	row = db.conn.QueryRow(resistanceQuery, symbol, currentPrice)
 	resistance := &models.SupportResistanceLevel{}
 	err = row.Scan(
-		&resistance.ID, &resistance.Symbol, &resistance.Level, &resistance.LevelType,
+		&resistance.ID, &resistance.Symbol, &resistance.Level, &resistance.LevelType, &resistance.LevelClass, &resistance.FibRatio,
 		&resistance.Strength, &resistance.Touches, &resistance.FirstTouch, &resistance.LastTouch,
 		&resistance.VolumeConfirmed, &resistance.AvgVolume, &resistance.MaxBouncePercent,
 		&resistance.AvgBouncePercent, &resistance.TimeframeOrigin, &resistance.IsActive,
@@ -406,18 +442,65 @@ func (db *DB) InsertSRLevelTouch(touch *models.SRLevelTouch) error {
 	return nil
 }
 
-// GetRecentSRLevelTouches retrieves recent touches for S/R levels
-func (db *DB) GetRecentSRLevelTouches(symbol string, hours int, limit int) ([]*models.SRLevelTouch, error) {
+// InsertSRLevelBreak records a break of an S/R level, reporting false when the level's break at that bar was
+// recorded before
+func (db *DB) InsertSRLevelBreak(touch *models.SRLevelTouch) (bool, error) {
+	touch.TouchType = models.SRTouchBreak
+	return db.InsertSRLevelTouchOnce(touch)
+}
+
+// InsertSRLevelTouchOnce records a touch of an S/R level, reporting false when a touch of the level at that bar
+// was recorded before: a break, or a test or bounce
+func (db *DB) InsertSRLevelTouchOnce(touch *models.SRLevelTouch) (bool, error) {
+	if touch.CreatedAt.IsZero() {
+		touch.CreatedAt = time.Now()
+	}
+
+	result, err := db.conn.Exec(`
+		INSERT OR IGNORE INTO sr_level_touches
+		(level_id, symbol, touch_time, touch_price, level, distance_percent,
+		 bounce_percent, volume_at_touch, volume_spike, bounce_confirmed,
+		 time_at_level, touch_type, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, touch.LevelID, touch.Symbol, touch.TouchTime, touch.TouchPrice,
+		touch.Level, touch.DistancePercent, touch.BouncePercent,
+		touch.VolumeAtTouch, touch.VolumeSpike, touch.BounceConfirmed,
+		touch.TimeAtLevel, touch.TouchType, touch.CreatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to insert S/R level %s: %w", touch.TouchType, err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return false, nil
+	}
+
+	if id, err := result.LastInsertId(); err == nil {
+		touch.ID = id
+	}
+	return true, nil
+}
+
+// GetRecentSRLevelTouches retrieves recent touches for S/R levels, of one type when touchType is set
+func (db *DB) GetRecentSRLevelTouches(symbol, touchType string, hours int, limit int) ([]*models.SRLevelTouch, error) {
 	query := `
 		SELECT id, level_id, symbol, touch_time, touch_price, level, distance_percent,
 		       bounce_percent, volume_at_touch, volume_spike, bounce_confirmed,
 		       time_at_level, touch_type, created_at
 		FROM sr_level_touches 
 		WHERE symbol = ? AND touch_time > datetime('now', '-' || ? || ' hours')
-		ORDER BY touch_time DESC
 	`
 
 	args := []interface{}{symbol, hours}
+	if touchType != "" {
+		query += " AND touch_type = ?"
+		args = append(args, touchType)
+	}
+	query += " ORDER BY touch_time DESC"
 	if limit > 0 {
 		query += " LIMIT ?"
 		args = append(args, limit)
@@ -479,11 +562,11 @@ func (db *DB) GetSRLevelSummary(symbol string) (*models.SRLevelSummary, error) {
 			COUNT(*) as total_levels,
 			COUNT(CASE WHEN level_type = 'support' AND is_active = TRUE THEN 1 END) as support_count,
 			COUNT(CASE WHEN level_type = 'resistance' AND is_active = TRUE THEN 1 END) as resistance_count,
-			AVG(CASE WHEN is_active = TRUE THEN strength END) as avg_strength,
-			MAX(CASE WHEN is_active = TRUE THEN strength END) as strongest_level,
-			MIN(CASE WHEN is_active = TRUE THEN strength END) as weakest_level
+			COALESCE(AVG(CASE WHEN is_active = TRUE THEN strength END), 0) as avg_strength,
+			COALESCE(MAX(CASE WHEN is_active = TRUE THEN strength END), 0) as strongest_level,
+			COALESCE(MIN(CASE WHEN is_active = TRUE THEN strength END), 0) as weakest_level
 		FROM support_resistance_levels 
-		WHERE symbol = ? AND is_active = TRUE
+		WHERE symbol = ? AND is_active = TRUE AND level_class = 'pivot'
 	`
 
 	err := db.conn.QueryRow(query, symbol).Scan(
@@ -494,20 +577,19 @@ func (db *DB) GetSRLevelSummary(symbol string) (*models.SRLevelSummary, error) {
 		return nil, fmt.Errorf("failed to get S/R level summary: %w", err)
 	}
 
-	// Get recent touch information
-	touchQuery := `
-		SELECT COUNT(*), MAX(touch_time)
-		FROM sr_level_touches 
-		WHERE symbol = ? AND touch_time > datetime('now', '-24 hours')
-	`
+	// Get recent touch information; the latest touch is read as a column, as an aggregate loses its time type
+	touchWhere := ` FROM sr_level_touches WHERE symbol = ? AND touch_time > datetime('now', '-24 hours')`
 
-	err = db.conn.QueryRow(touchQuery, symbol).Scan(
-		&summary.RecentTouchCount, &summary.LastTouchTime,
-	)
-	if err != nil && err != sql.ErrNoRows {
+	err = db.conn.QueryRow(`SELECT COUNT(*)`+touchWhere, symbol).Scan(&summary.RecentTouchCount)
+	if err != nil {
 		return nil, fmt.Errorf("failed to get recent touch info: %w", err)
 	}
 
+	err = db.conn.QueryRow(`SELECT touch_time`+touchWhere+` ORDER BY touch_time DESC LIMIT 1`, symbol).Scan(&summary.LastTouchTime)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get last touch time: %w", err)
+	}
+
 	return summary, nil
 }
 
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"market-watch-go/internal/models"
+)
+
+// symbolStatsColumns are the symbol_stats columns in scan order
+var symbolStatsColumns = []string{
+	"symbol", "trading_date", "days", "close", "atr_14", "atr_percent", "avg_range", "avg_range_percent",
+	"gap_percent", "gap_up_days", "gap_down_days", "gap_frequency", "avg_gap_percent",
+	"avg_pre_market_volume", "avg_regular_volume", "avg_post_market_volume", "computed_at",
+}
+
+// UpsertSymbolStats stores a symbol's stats, replacing the ones computed before
+func (db *DB) UpsertSymbolStats(stats *models.SymbolStats) error {
+	query := upsertQuery("symbol_stats", symbolStatsColumns, symbolStatsColumns[:1], symbolStatsColumns[1:], 1)
+	_, err := db.conn.Exec(query,
+		stats.Symbol, stats.TradingDate, stats.Days, stats.Close, stats.ATR14, stats.ATRPercent, stats.AvgRange,
+		stats.AvgRangePercent, stats.GapPercent, stats.GapUpDays, stats.GapDownDays, stats.GapFrequency,
+		stats.AvgGapPercent, stats.AvgPreMarketVolume, stats.AvgRegularVolume, stats.AvgPostMarketVolume,
+		stats.ComputedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert symbol stats: %w", err)
+	}
+	return nil
+}
+
+// GetSymbolStats retrieves a symbol's latest stats, or nil when they were never computed
+func (db *DB) GetSymbolStats(symbol string) (*models.SymbolStats, error) {
+	row := db.conn.QueryRow("SELECT "+strings.Join(symbolStatsColumns, ", ")+" FROM symbol_stats WHERE symbol = ?", symbol)
+	stats, err := scanSymbolStats(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return stats, err
+}
+
+// GetAllSymbolStats retrieves the latest stats of every symbol, ordered by symbol
+func (db *DB) GetAllSymbolStats() ([]*models.SymbolStats, error) {
+	rows, err := db.conn.Query("SELECT " + strings.Join(symbolStatsColumns, ", ") + " FROM symbol_stats ORDER BY symbol")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query symbol stats: %w", err)
+	}
+	defer rows.Close()
+
+	all := make([]*models.SymbolStats, 0)
+	for rows.Next() {
+		stats, err := scanSymbolStats(rows)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, stats)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating symbol stats: %w", err)
+	}
+
+	return all, nil
+}
+
+// scanSymbolStats scans a symbol's stats from a database row
+func scanSymbolStats(row interface{ Scan(...interface{}) error }) (*models.SymbolStats, error) {
+	stats := &models.SymbolStats{}
+	err := row.Scan(
+		&stats.Symbol, &stats.TradingDate, &stats.Days, &stats.Close, &stats.ATR14, &stats.ATRPercent, &stats.AvgRange,
+		&stats.AvgRangePercent, &stats.GapPercent, &stats.GapUpDays, &stats.GapDownDays, &stats.GapFrequency,
+		&stats.AvgGapPercent, &stats.AvgPreMarketVolume, &stats.AvgRegularVolume, &stats.AvgPostMarketVolume,
+		&stats.ComputedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan symbol stats: %w", err)
+	}
+	return stats, nil
+}
@@ -28,6 +28,13 @@ func (db *DB) CreateTechnicalIndicatorsTable() error {
 			bb_upper REAL,
 			bb_middle REAL,
 			bb_lower REAL,
+			atr_14 REAL,
+			stoch_k REAL,
+			stoch_d REAL,
+			adx_14 REAL,
+			plus_di REAL,
+			minus_di REAL,
+			obv REAL,
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			UNIQUE(symbol, timestamp)
 		);
@@ -61,8 +68,9 @@ func (db *DB) InsertTechnicalIndicators(indicators *models.TechnicalIndicators)
 		INSERT OR REPLACE INTO technical_indicators 
 		(symbol, timestamp, rsi_14, rsi_30, macd_line, macd_signal, macd_histogram,
 		 ema_20, ema_50, vwap, volume_ratio,
-		 bb_upper, bb_middle, bb_lower, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 bb_upper, bb_middle, bb_lower,
+		 atr_14, stoch_k, stoch_d, adx_14, plus_di, minus_di, obv, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err := db.conn.Exec(query,
@@ -80,6 +88,13 @@ func (db *DB) InsertTechnicalIndicators(indicators *models.TechnicalIndicators)
 		indicators.BBUpper,
 		indicators.BBMiddle,
 		indicators.BBLower,
+		indicators.ATR14,
+		indicators.StochK,
+		indicators.StochD,
+		indicators.ADX14,
+		indicators.PlusDI,
+		indicators.MinusDI,
+		indicators.OBV,
 		indicators.CreatedAt,
 	)
 
@@ -95,7 +110,10 @@ func (db *DB) GetLatestTechnicalIndicators(symbol string) (*models.TechnicalIndi
 	query := `
 		SELECT id, symbol, timestamp, rsi_14, rsi_30, macd_line, macd_signal, macd_histogram,
 		       ema_20, ema_50, vwap, volume_ratio,
-		       bb_upper, bb_middle, bb_lower, created_at
+		       bb_upper, bb_middle, bb_lower,
+		       COALESCE(atr_14, 0), COALESCE(stoch_k, 0), COALESCE(stoch_d, 0),
+		       COALESCE(adx_14, 0), COALESCE(plus_di, 0), COALESCE(minus_di, 0), COALESCE(obv, 0),
+		       created_at
 		FROM technical_indicators 
 		WHERE symbol = ?
 		ORDER BY timestamp DESC
@@ -122,6 +140,13 @@ func (db *DB) GetLatestTechnicalIndicators(symbol string) (*models.TechnicalIndi
 		&indicators.BBUpper,
 		&indicators.BBMiddle,
 		&indicators.BBLower,
+		&indicators.ATR14,
+		&indicators.StochK,
+		&indicators.StochD,
+		&indicators.ADX14,
+		&indicators.PlusDI,
+		&indicators.MinusDI,
+		&indicators.OBV,
 		&indicators.CreatedAt,
 	)
 
@@ -140,7 +165,10 @@ func (db *DB) GetTechnicalIndicators(filter *models.IndicatorFilter) ([]*models.
 	query := `
 		SELECT id, symbol, timestamp, rsi_14, rsi_30, macd_line, macd_signal, macd_histogram,
 		       ema_20, ema_50, vwap, volume_ratio,
-		       bb_upper, bb_middle, bb_lower, created_at
+		       bb_upper, bb_middle, bb_lower,
+		       COALESCE(atr_14, 0), COALESCE(stoch_k, 0), COALESCE(stoch_d, 0),
+		       COALESCE(adx_14, 0), COALESCE(plus_di, 0), COALESCE(minus_di, 0), COALESCE(obv, 0),
+		       created_at
 		FROM technical_indicators 
 		WHERE symbol = ? AND timestamp BETWEEN ? AND ?
 		ORDER BY timestamp ASC
@@ -184,6 +212,13 @@ func (db *DB) GetTechnicalIndicators(filter *models.IndicatorFilter) ([]*models.
 			&ind.BBUpper,
 			&ind.BBMiddle,
 			&ind.BBLower,
+			&ind.ATR14,
+			&ind.StochK,
+			&ind.StochD,
+			&ind.ADX14,
+			&ind.PlusDI,
+			&ind.MinusDI,
+			&ind.OBV,
 			&ind.CreatedAt,
 		)
 		if err != nil {
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// marketLocation is used to bucket daily candles by exchange trading date
+var marketLocation = loadMarketLocation()
+
+func loadMarketLocation() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// TradingDate returns the exchange trading date a timestamp falls on, as YYYY-MM-DD
+func TradingDate(ts time.Time) string {
+	return ts.In(marketLocation).Format("2006-01-02")
+}
+
+// timeframeBucket returns the start time of the candle a timestamp falls into
+func timeframeBucket(ts time.Time, timeframe models.Timeframe) time.Time {
+	if timeframe == models.Timeframe1d {
+		local := ts.In(marketLocation)
+		return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, marketLocation)
+	}
+	return ts.Truncate(timeframe.Duration())
+}
+
+// AggregatePriceData rolls time-ordered 1-minute bars up into candles of the given timeframe. Intraday
+// candles take the session of their first bar; daily candles span sessions and have none.
+func AggregatePriceData(data []*models.PriceData, timeframe models.Timeframe) []*models.PriceData {
+	if !timeframe.IsAggregated() || len(data) == 0 {
+		return data
+	}
+
+	var candles []*models.PriceData
+	var current *models.PriceData
+
+	for _, bar := range data {
+		bucket := timeframeBucket(bar.Timestamp, timeframe)
+
+		if current == nil || !current.Timestamp.Equal(bucket) {
+			current = &models.PriceData{
+				Symbol:    bar.Symbol,
+				Timestamp: bucket,
+				Open:      bar.Open,
+				High:      bar.High,
+				Low:       bar.Low,
+				Close:     bar.Close,
+				Volume:    bar.Volume,
+				CreatedAt: bar.CreatedAt,
+			}
+			if timeframe != models.Timeframe1d {
+				current.Session = bar.Session
+			}
+			candles = append(candles, current)
+			continue
+		}
+
+		if bar.High > current.High {
+			current.High = bar.High
+		}
+		if bar.Low < current.Low {
+			current.Low = bar.Low
+		}
+		current.Close = bar.Close
+		current.Volume += bar.Volume
+		current.CreatedAt = bar.CreatedAt
+	}
+
+	return candles
+}
+
+// PaginatePriceData applies limit and offset to an in-memory result set
+func PaginatePriceData(data []*models.PriceData, limit, offset int) []*models.PriceData {
+	if offset > 0 {
+		if offset >= len(data) {
+			return []*models.PriceData{}
+		}
+		data = data[offset:]
+	}
+	if limit > 0 && limit < len(data) {
+		data = data[:limit]
+	}
+	return data
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestAggregatePriceData tests rolling 1-minute bars up into 5-minute candles
+func TestAggregatePriceData(t *testing.T) {
+	start := time.Date(2024, 3, 4, 14, 30, 0, 0, time.UTC)
+	var bars []*models.PriceData
+	for i := 0; i < 12; i++ {
+		price := 100 + float64(i)
+		bars = append(bars, &models.PriceData{
+			Symbol:    "TEST",
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			Open:      price,
+			High:      price + 0.5,
+			Low:       price - 0.5,
+			Close:     price + 0.25,
+			Volume:    100,
+		})
+	}
+
+	candles := AggregatePriceData(bars, models.Timeframe5m)
+	if len(candles) != 3 {
+		t.Fatalf("expected 3 candles, got %d", len(candles))
+	}
+
+	first := candles[0]
+	if !first.Timestamp.Equal(start) {
+		t.Errorf("expected first candle at %s, got %s", start, first.Timestamp)
+	}
+	if first.Open != 100 || first.High != 104.5 || first.Low != 99.5 || first.Close != 104.25 {
+		t.Errorf("unexpected OHLC for first candle: %+v", first)
+	}
+	if first.Volume != 500 {
+		t.Errorf("expected volume 500, got %d", first.Volume)
+	}
+
+	// Trailing partial candle holds the remaining two bars
+	if last := candles[2]; last.Volume != 200 || last.Close != 111.25 {
+		t.Errorf("unexpected partial candle: %+v", last)
+	}
+
+	if page := PaginatePriceData(candles, 1, 1); len(page) != 1 || page[0] != candles[1] {
+		t.Errorf("expected second candle from pagination, got %+v", page)
+	}
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+	"fmt"
+)
+
+// WithTx runs fn inside a transaction, committing if it returns nil and rolling back otherwise.
+// The DB passed to fn runs every query on the transaction; calling WithTx on it joins the
+// enclosing transaction instead of starting a new one, so multi-table writes can be composed.
+func (db *DB) WithTx(fn func(tx *DB) error) error {
+	if db.InTx() {
+		return fn(db)
+	}
+
+	ctx := db.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	tx, err := db.pool.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	txDB := &DB{conn: tx, pool: db.pool}
+	if db.ctx != nil {
+		txDB = txDB.WithContext(db.ctx)
+	}
+	if err := fn(txDB); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// InTx reports whether queries on this DB run inside a transaction
+func (db *DB) InTx() bool {
+	conn := db.conn
+	if bound, ok := conn.(*boundConn); ok {
+		conn = bound.conn
+	}
+	_, ok := conn.(*Tx)
+	return ok
+}
+
+// WithContext returns a DB whose queries are cancelled once ctx is done, such as when the client of an
+// API request disconnects or the request times out. Inside WithTx the returned DB stays on the transaction.
+func (db *DB) WithContext(ctx context.Context) *DB {
+	if db == nil {
+		return nil
+	}
+	conn := db.conn
+	if bound, ok := conn.(*boundConn); ok {
+		conn = bound.conn
+	}
+	base, ok := conn.(translatingConn)
+	if !ok {
+		return db
+	}
+	return &DB{conn: &boundConn{ctx: ctx, conn: base}, pool: db.pool, ctx: ctx}
+}
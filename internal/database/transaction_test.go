@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestWithTxRollback tests that a failed unit of work leaves nothing behind and nested calls join it
+func TestWithTxRollback(t *testing.T) {
+	db := newTestDB(t)
+
+	bar := func(minute int) *models.PriceData {
+		return &models.PriceData{
+			Symbol:    "TEST",
+			Timestamp: time.Date(2024, 3, 4, 14, minute, 0, 0, time.UTC),
+			Open:      100, High: 101, Low: 99, Close: 100.5, Volume: 1000,
+			CreatedAt: time.Now(),
+		}
+	}
+	count := func() int {
+		var n int
+		if err := db.conn.QueryRow("SELECT COUNT(*) FROM price_data WHERE symbol = 'TEST'").Scan(&n); err != nil {
+			t.Fatalf("count failed: %v", err)
+		}
+		return n
+	}
+
+	errAbort := errors.New("abort")
+	err := db.WithTx(func(tx *DB) error {
+		if !tx.InTx() {
+			t.Fatal("expected the callback DB to run in a transaction")
+		}
+		// InsertPriceDataBatch opens its own transaction, which must join this one
+		if err := tx.InsertPriceDataBatch([]*models.PriceData{bar(30), bar(31)}); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected the callback error, got %v", err)
+	}
+	if n := count(); n != 0 {
+		t.Fatalf("expected rollback to discard both bars, found %d", n)
+	}
+
+	err = db.WithTx(func(tx *DB) error {
+		return tx.InsertPriceDataBatch([]*models.PriceData{bar(30), bar(31)})
+	})
+	if err != nil {
+		t.Fatalf("WithTx failed: %v", err)
+	}
+	if n := count(); n != 2 {
+		t.Fatalf("expected 2 committed bars, found %d", n)
+	}
+}
+
+// TestWithContextCancels tests that a DB bound to a cancelled context fails its queries and transactions
+// while the unbound DB keeps working
+func TestWithContextCancels(t *testing.T) {
+	db := newTestDB(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bound := db.WithContext(ctx)
+	if _, err := bound.GetWatchedSymbols(); err != nil {
+		t.Fatalf("expected queries to run before cancellation, got %v", err)
+	}
+
+	cancel()
+	if _, err := bound.GetWatchedSymbols(); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled query, got %v", err)
+	}
+	err := bound.WithTx(func(tx *DB) error { return nil })
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled transaction, got %v", err)
+	}
+	if _, err := db.GetWatchedSymbols(); err != nil {
+		t.Errorf("expected the unbound DB to be unaffected, got %v", err)
+	}
+
+	err = db.WithTx(func(tx *DB) error {
+		if !tx.WithContext(context.Background()).InTx() {
+			t.Error("expected a bound transaction DB to stay on the transaction")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTx failed: %v", err)
+	}
+}
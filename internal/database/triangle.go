@@ -0,0 +1,309 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// CreateTrianglePatternTables creates all triangle pattern related tables
+func (db *DB) CreateTrianglePatternTables() error {
+	query := `CREATE TABLE IF NOT EXISTS triangle_patterns (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		symbol TEXT NOT NULL,
+		pattern_type TEXT NOT NULL CHECK (pattern_type IN ('ascending_triangle', 'descending_triangle')),
+
+		-- Trend line points (JSON stored as TEXT)
+		upper_trend_line_1 TEXT NOT NULL,
+		upper_trend_line_2 TEXT NOT NULL,
+		lower_trend_line_1 TEXT NOT NULL,
+		lower_trend_line_2 TEXT NOT NULL,
+
+		-- Pattern metrics
+		upper_slope REAL NOT NULL,
+		lower_slope REAL NOT NULL,
+		breakout_level REAL NOT NULL,
+		pattern_width INTEGER NOT NULL,
+		pattern_height REAL NOT NULL,
+		touch_points INTEGER NOT NULL DEFAULT 0,
+
+		-- Volume analysis
+		volume_profile TEXT DEFAULT 'unknown',
+
+		-- Thesis components (JSON stored as TEXT)
+		thesis_components TEXT NOT NULL DEFAULT '{}',
+
+		-- Status
+		detected_at DATETIME NOT NULL,
+		last_updated DATETIME DEFAULT CURRENT_TIMESTAMP,
+		is_complete BOOLEAN DEFAULT FALSE,
+		current_phase TEXT DEFAULT 'formation' CHECK (current_phase IN ('formation', 'breakout', 'target_pursuit', 'completed'))
+	)`
+
+	if _, err := db.conn.Exec(query); err != nil {
+		return fmt.Errorf("failed to create triangle pattern table: %w", err)
+	}
+
+	indexes := []string{
+		`CREATE INDEX IF NOT EXISTS idx_triangle_symbol ON triangle_patterns(symbol)`,
+		`CREATE INDEX IF NOT EXISTS idx_triangle_type ON triangle_patterns(pattern_type)`,
+		`CREATE INDEX IF NOT EXISTS idx_triangle_complete ON triangle_patterns(is_complete)`,
+		`CREATE INDEX IF NOT EXISTS idx_triangle_detected ON triangle_patterns(detected_at)`,
+	}
+
+	for _, indexQuery := range indexes {
+		if _, err := db.conn.Exec(indexQuery); err != nil {
+			return fmt.Errorf("failed to create triangle pattern index: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// InsertTrianglePattern inserts a new triangle pattern
+func (db *DB) InsertTrianglePattern(pattern *models.TrianglePattern) error {
+	points, err := marshalPatternPoints(pattern.UpperTrendLine1, pattern.UpperTrendLine2, pattern.LowerTrendLine1, pattern.LowerTrendLine2)
+	if err != nil {
+		return err
+	}
+
+	thesisJSON, err := json.Marshal(pattern.ThesisComponents)
+	if err != nil {
+		return fmt.Errorf("failed to marshal thesis components: %w", err)
+	}
+
+	query := `
+		INSERT INTO triangle_patterns (
+			symbol, pattern_type,
+			upper_trend_line_1, upper_trend_line_2, lower_trend_line_1, lower_trend_line_2,
+			upper_slope, lower_slope, breakout_level, pattern_width, pattern_height, touch_points,
+			volume_profile, thesis_components,
+			detected_at, last_updated, is_complete, current_phase, fingerprint
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := db.conn.Exec(query,
+		pattern.Symbol, pattern.PatternType,
+		points[0], points[1], points[2], points[3],
+		pattern.UpperSlope, pattern.LowerSlope, pattern.BreakoutLevel,
+		pattern.PatternWidth, pattern.PatternHeight, pattern.TouchPoints,
+		pattern.VolumeProfile, string(thesisJSON),
+		pattern.DetectedAt, pattern.LastUpdated, pattern.IsComplete, pattern.CurrentPhase, pattern.Fingerprint(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert triangle pattern: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	pattern.ID = id
+	return nil
+}
+
+const triangleColumns = `id, symbol, pattern_type,
+	upper_trend_line_1, upper_trend_line_2, lower_trend_line_1, lower_trend_line_2,
+	upper_slope, lower_slope, breakout_level, pattern_width, pattern_height, touch_points,
+	volume_profile, thesis_components,
+	detected_at, last_updated, is_complete, current_phase, archived, archived_at`
+
+// GetTrianglePatterns retrieves triangle patterns with optional filtering
+func (db *DB) GetTrianglePatterns(filter *models.TriangleFilter) ([]*models.TrianglePattern, error) {
+	if filter == nil {
+		filter = &models.TriangleFilter{Limit: 100}
+	}
+
+	where, args := triangleWhere(filter)
+	query := "SELECT " + triangleColumns + " FROM triangle_patterns" + where
+	query += orderBy(filter.Sort, filter.Order, models.PatternSortFields, "detected_at DESC")
+	query, args = limitOffset(query, args, filter.Limit, filter.Offset)
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query triangle patterns: %w", err)
+	}
+	defer rows.Close()
+
+	patterns := make([]*models.TrianglePattern, 0)
+	for rows.Next() {
+		pattern, err := scanTrianglePattern(rows)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating triangle patterns: %w", err)
+	}
+
+	return patterns, nil
+}
+
+// CountTrianglePatterns counts the patterns matching a filter, ignoring its limit and offset
+func (db *DB) CountTrianglePatterns(filter *models.TriangleFilter) (int, error) {
+	where, args := triangleWhere(filter)
+	return db.countRows("triangle_patterns", where, args)
+}
+
+// triangleWhere builds the WHERE clause for a TriangleFilter
+func triangleWhere(filter *models.TriangleFilter) (string, []interface{}) {
+	where := " WHERE 1=1"
+	args := []interface{}{}
+	if filter == nil {
+		return where + " AND archived = FALSE", args
+	}
+
+	if filter.Symbol != "" {
+		where += " AND symbol = ?"
+		args = append(args, filter.Symbol)
+	}
+
+	if filter.PatternType != "" {
+		where += " AND pattern_type = ?"
+		args = append(args, filter.PatternType)
+	}
+
+	if filter.Phase != "" {
+		where += " AND current_phase = ?"
+		args = append(args, filter.Phase)
+	}
+
+	if filter.IsComplete != nil {
+		where += " AND is_complete = ?"
+		args = append(args, *filter.IsComplete)
+	}
+
+	if !filter.IncludeArchived {
+		where += " AND archived = FALSE"
+	}
+
+	return where, args
+}
+
+// GetTrianglePatternByID retrieves a specific triangle pattern by ID
+func (db *DB) GetTrianglePatternByID(id int64) (*models.TrianglePattern, error) {
+	row := db.conn.QueryRow("SELECT "+triangleColumns+" FROM triangle_patterns WHERE id = ?", id)
+
+	pattern, err := scanTrianglePattern(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return pattern, err
+}
+
+// UpdateTrianglePattern updates an existing triangle pattern
+func (db *DB) UpdateTrianglePattern(pattern *models.TrianglePattern) error {
+	thesisJSON, err := json.Marshal(pattern.ThesisComponents)
+	if err != nil {
+		return fmt.Errorf("failed to marshal thesis components: %w", err)
+	}
+
+	query := `
+		UPDATE triangle_patterns SET
+			breakout_level = ?, volume_profile = ?, thesis_components = ?,
+			last_updated = ?, is_complete = ?, current_phase = ?
+		WHERE id = ?
+	`
+
+	pattern.LastUpdated = time.Now()
+
+	_, err = db.conn.Exec(query,
+		pattern.BreakoutLevel, pattern.VolumeProfile, string(thesisJSON),
+		pattern.LastUpdated, pattern.IsComplete, pattern.CurrentPhase,
+		pattern.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update triangle pattern: %w", err)
+	}
+
+	return nil
+}
+
+// GetActiveTrianglePatterns retrieves all active triangle patterns
+func (db *DB) GetActiveTrianglePatterns() ([]*models.TrianglePattern, error) {
+	isComplete := false
+	return db.GetTrianglePatterns(&models.TriangleFilter{
+		IsComplete: &isComplete,
+		Limit:      1000,
+	})
+}
+
+// GetTrianglePatternsBySymbol retrieves all triangle patterns for a specific symbol
+func (db *DB) GetTrianglePatternsBySymbol(symbol string) ([]*models.TrianglePattern, error) {
+	return db.GetTrianglePatterns(&models.TriangleFilter{
+		Symbol: symbol,
+		Limit:  100,
+	})
+}
+
+// scanTrianglePattern scans a triangle pattern row and decodes its JSON columns
+func scanTrianglePattern(row interface{ Scan(...interface{}) error }) (*models.TrianglePattern, error) {
+	pattern := &models.TrianglePattern{}
+	var upperLine1JSON, upperLine2JSON, lowerLine1JSON, lowerLine2JSON string
+	var thesisJSON string
+	var archivedAt sql.NullTime
+
+	err := row.Scan(
+		&pattern.ID, &pattern.Symbol, &pattern.PatternType,
+		&upperLine1JSON, &upperLine2JSON, &lowerLine1JSON, &lowerLine2JSON,
+		&pattern.UpperSlope, &pattern.LowerSlope, &pattern.BreakoutLevel,
+		&pattern.PatternWidth, &pattern.PatternHeight, &pattern.TouchPoints,
+		&pattern.VolumeProfile, &thesisJSON,
+		&pattern.DetectedAt, &pattern.LastUpdated, &pattern.IsComplete, &pattern.CurrentPhase,
+		&pattern.Archived, &archivedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan triangle pattern: %w", err)
+	}
+
+	if archivedAt.Valid {
+		pattern.ArchivedAt = &archivedAt.Time
+	}
+
+	if err := unmarshalPatternPoints(
+		[]string{upperLine1JSON, upperLine2JSON, lowerLine1JSON, lowerLine2JSON},
+		&pattern.UpperTrendLine1, &pattern.UpperTrendLine2, &pattern.LowerTrendLine1, &pattern.LowerTrendLine2,
+	); err != nil {
+		return nil, err
+	}
+
+	if thesisJSON != "" {
+		if err := json.Unmarshal([]byte(thesisJSON), &pattern.ThesisComponents); err != nil {
+			fmt.Printf("Failed to unmarshal thesis components for triangle pattern %d: %v\n", pattern.ID, err)
+		}
+	}
+
+	return pattern, nil
+}
+
+// marshalPatternPoints encodes pattern points as JSON strings for TEXT columns
+func marshalPatternPoints(points ...models.PatternPoint) ([]string, error) {
+	encoded := make([]string, 0, len(points))
+	for i, point := range points {
+		data, err := json.Marshal(point)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal pattern point %d: %w", i+1, err)
+		}
+		encoded = append(encoded, string(data))
+	}
+	return encoded, nil
+}
+
+// unmarshalPatternPoints decodes JSON TEXT columns into pattern points
+func unmarshalPatternPoints(encoded []string, points ...*models.PatternPoint) error {
+	for i, data := range encoded {
+		if err := json.Unmarshal([]byte(data), points[i]); err != nil {
+			return fmt.Errorf("failed to unmarshal pattern point %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
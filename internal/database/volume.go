@@ -29,45 +29,26 @@ func (db *DB) InsertVolumeData(data *models.VolumeData) error {
 	return nil
 }
 
-// InsertVolumeDataBatch inserts multiple volume data records in a transaction
+// InsertVolumeDataBatch upserts multiple volume data records in a transaction; the last of repeated bars wins
 func (db *DB) InsertVolumeDataBatch(dataList []*models.VolumeData) error {
-	if len(dataList) == 0 {
-		return nil
+	latest := make(map[string]int, len(dataList))
+	for i, data := range dataList {
+		latest[barKey(data.Symbol, data.Timestamp)] = i
 	}
 
-	tx, err := db.conn.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback()
-
-	stmt, err := tx.Prepare(`
-		INSERT OR REPLACE INTO volume_data
-		(symbol, timestamp, volume, created_at)
-		VALUES (?, ?, ?, ?)
-	`)
-	if err != nil {
-		return fmt.Errorf("failed to prepare statement: %w", err)
-	}
-	defer stmt.Close()
-
-	for _, data := range dataList {
-		_, err := stmt.Exec(
-			data.Symbol,
-			data.Timestamp,
-			data.Volume,
-			data.CreatedAt,
-		)
-		if err != nil {
-			return fmt.Errorf("failed to execute batch insert: %w", err)
+	rows := make([][]interface{}, 0, len(latest))
+	for i, data := range dataList {
+		if latest[barKey(data.Symbol, data.Timestamp)] != i {
+			continue
 		}
+		rows = append(rows, []interface{}{data.Symbol, data.Timestamp, data.Volume, data.CreatedAt})
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	return nil
+	return db.upsertBatch("volume_data",
+		[]string{"symbol", "timestamp", "volume", "created_at"},
+		[]string{"symbol", "timestamp"},
+		[]string{"volume"},
+		rows)
 }
 
 // GetVolumeData retrieves volume data for a symbol within a time range
@@ -144,6 +125,11 @@ func (db *DB) GetLatestVolumeData(symbol string) (*models.VolumeData, error) {
 	return vd, nil
 }
 
+// GetVolumeTimestamps retrieves the timestamps of a symbol's volume bars within a time range, oldest first
+func (db *DB) GetVolumeTimestamps(symbol string, from, to time.Time) ([]time.Time, error) {
+	return db.getBarTimestamps("volume_data", symbol, from, to)
+}
+
 // GetVolumeStats calculates volume statistics for a symbol
 func (db *DB) GetVolumeStats(symbol string, days int) (*models.VolumeStats, error) {
 	// Get current volume (latest data point)
@@ -266,7 +252,7 @@ func (db *DB) GetAllSymbols() ([]string, error) {
 
 // GetWatchedSymbolsWithDetails returns watched symbols with details
 func (db *DB) GetWatchedSymbolsWithDetails() ([]models.WatchedSymbol, error) {
-	query := `SELECT id, symbol, name, added_at, is_active FROM watched_symbols WHERE is_active = 1 ORDER BY symbol`
+	query := `SELECT id, symbol, name, added_at, is_active FROM watched_symbols WHERE is_active = TRUE ORDER BY symbol`
 
 	rows, err := db.conn.Query(query)
 	if err != nil {
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"market-watch-go/internal/models"
+)
+
+// CreateVolumeProfileTables creates the per-symbol volume profile table
+func (db *DB) CreateVolumeProfileTables() error {
+	query := `CREATE TABLE IF NOT EXISTS volume_profiles (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		symbol TEXT NOT NULL UNIQUE,
+		window_days INTEGER NOT NULL,
+		from_time DATETIME NOT NULL,
+		to_time DATETIME NOT NULL,
+		bucket_size REAL NOT NULL,
+		total_volume REAL DEFAULT 0,
+		point_of_control REAL DEFAULT 0,
+		value_area_high REAL DEFAULT 0,
+		value_area_low REAL DEFAULT 0,
+		high_volume_nodes TEXT DEFAULT '[]',
+		buckets TEXT DEFAULT '[]',
+		computed_at DATETIME NOT NULL
+	)`
+
+	if _, err := db.conn.Exec(query); err != nil {
+		return fmt.Errorf("failed to create volume profile table: %w", err)
+	}
+
+	return nil
+}
+
+// UpsertVolumeProfile stores a symbol's volume profile, replacing the previous one
+func (db *DB) UpsertVolumeProfile(profile *models.VolumeProfile) error {
+	nodesJSON, err := json.Marshal(profile.HighVolumeNodes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal high volume nodes: %w", err)
+	}
+	bucketsJSON, err := json.Marshal(profile.Buckets)
+	if err != nil {
+		return fmt.Errorf("failed to marshal volume profile buckets: %w", err)
+	}
+
+	query := `
+		INSERT OR REPLACE INTO volume_profiles (
+			symbol, window_days, from_time, to_time, bucket_size, total_volume, point_of_control,
+			value_area_high, value_area_low, high_volume_nodes, buckets, computed_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = db.conn.Exec(query,
+		strings.ToUpper(profile.Symbol), profile.WindowDays, profile.From, profile.To, profile.BucketSize,
+		profile.TotalVolume, profile.PointOfControl, profile.ValueAreaHigh, profile.ValueAreaLow,
+		string(nodesJSON), string(bucketsJSON), profile.ComputedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert volume profile: %w", err)
+	}
+
+	return nil
+}
+
+// GetVolumeProfile returns a symbol's stored volume profile, or nil if none has been computed
+func (db *DB) GetVolumeProfile(symbol string) (*models.VolumeProfile, error) {
+	query := `SELECT symbol, window_days, from_time, to_time, bucket_size, total_volume, point_of_control,
+		value_area_high, value_area_low, high_volume_nodes, buckets, computed_at
+		FROM volume_profiles WHERE symbol = ?`
+
+	profile := &models.VolumeProfile{}
+	var nodesJSON, bucketsJSON string
+	err := db.conn.QueryRow(query, strings.ToUpper(symbol)).Scan(
+		&profile.Symbol, &profile.WindowDays, &profile.From, &profile.To, &profile.BucketSize,
+		&profile.TotalVolume, &profile.PointOfControl, &profile.ValueAreaHigh, &profile.ValueAreaLow,
+		&nodesJSON, &bucketsJSON, &profile.ComputedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get volume profile: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(nodesJSON), &profile.HighVolumeNodes); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal high volume nodes: %w", err)
+	}
+	if err := json.Unmarshal([]byte(bucketsJSON), &profile.Buckets); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal volume profile buckets: %w", err)
+	}
+
+	return profile, nil
+}
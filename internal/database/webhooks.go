@@ -0,0 +1,103 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"market-watch-go/internal/models"
+)
+
+// InsertWebhookDeadLetter records a webhook event that could not be delivered
+func (db *DB) InsertWebhookDeadLetter(letter *models.WebhookDeadLetter) error {
+	result, err := db.conn.Exec(`
+		INSERT INTO webhook_dead_letters (event_id, event_type, symbol, url, payload, attempts, last_error, failed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, letter.EventID, letter.EventType, letter.Symbol, letter.URL, letter.Payload, letter.Attempts, letter.LastError, letter.FailedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert webhook dead letter: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	letter.ID = id
+	return nil
+}
+
+const webhookDeadLetterColumns = `id, event_id, event_type, symbol, url, payload, attempts, last_error, failed_at`
+
+// GetWebhookDeadLetters retrieves undelivered webhook events, newest first
+func (db *DB) GetWebhookDeadLetters(limit int) ([]*models.WebhookDeadLetter, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := db.conn.Query("SELECT "+webhookDeadLetterColumns+" FROM webhook_dead_letters ORDER BY failed_at DESC, id DESC LIMIT ?", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	letters := make([]*models.WebhookDeadLetter, 0)
+	for rows.Next() {
+		letter, err := scanWebhookDeadLetter(rows)
+		if err != nil {
+			return nil, err
+		}
+		letters = append(letters, letter)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook dead letters: %w", err)
+	}
+
+	return letters, nil
+}
+
+// GetWebhookDeadLetterByID retrieves an undelivered webhook event, or nil if there is none
+func (db *DB) GetWebhookDeadLetterByID(id int64) (*models.WebhookDeadLetter, error) {
+	row := db.conn.QueryRow("SELECT "+webhookDeadLetterColumns+" FROM webhook_dead_letters WHERE id = ?", id)
+
+	letter, err := scanWebhookDeadLetter(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return letter, err
+}
+
+// DeleteWebhookDeadLetter deletes an undelivered webhook event, e.g. once it has been resent
+func (db *DB) DeleteWebhookDeadLetter(id int64) error {
+	result, err := db.conn.Exec("DELETE FROM webhook_dead_letters WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook dead letter: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook dead letter %w", ErrNotFound)
+	}
+
+	return nil
+}
+
+// scanWebhookDeadLetter scans an undelivered webhook event from a database row
+func scanWebhookDeadLetter(row interface{ Scan(...interface{}) error }) (*models.WebhookDeadLetter, error) {
+	letter := &models.WebhookDeadLetter{}
+	err := row.Scan(
+		&letter.ID, &letter.EventID, &letter.EventType, &letter.Symbol, &letter.URL, &letter.Payload,
+		&letter.Attempts, &letter.LastError, &letter.FailedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan webhook dead letter: %w", err)
+	}
+	return letter, nil
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"net/http"
+
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler handles administrative API endpoints
+type AdminHandler struct {
+	reloader  *services.ConfigReloader
+	schedules *services.ScheduleControl
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(reloader *services.ConfigReloader, schedules *services.ScheduleControl) *AdminHandler {
+	return &AdminHandler{
+		reloader:  reloader,
+		schedules: schedules,
+	}
+}
+
+// scheduleRequest enables or disables a schedule
+type scheduleRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// ReloadConfig godoc
+// @Summary Reload config file
+// @Description Re-read and validate the config file, then apply the collection interval, default watched symbols, email and Telegram notification settings and logging level without a restart. Other changed sections are listed as needing a restart; an invalid file changes nothing.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.ConfigReloadResult
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/admin/config/reload [post]
+func (h *AdminHandler) ReloadConfig(c *gin.Context) {
+	result, err := h.reloader.Reload()
+	if err != nil {
+		respondInvalid(c, "", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// PauseCollector godoc
+// @Summary Pause data collection
+// @Description Skip scheduled collections and refuse forced ones until resumed, to stop market data provider requests during incidents or maintenance. Collection stats are kept; a restart resumes collection.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.ScheduleStatus
+// @Router /api/v1/admin/collector/pause [post]
+func (h *AdminHandler) PauseCollector(c *gin.Context) {
+	h.setSchedule(c, models.ScheduleCollection, false)
+}
+
+// ResumeCollector godoc
+// @Summary Resume data collection
+// @Description Resume scheduled and forced collections paused with POST /api/v1/admin/collector/pause
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.ScheduleStatus
+// @Router /api/v1/admin/collector/resume [post]
+func (h *AdminHandler) ResumeCollector(c *gin.Context) {
+	h.setSchedule(c, models.ScheduleCollection, true)
+}
+
+// PauseJobs godoc
+// @Summary Pause background jobs
+// @Description Disable every schedule and job type except collection. Queued jobs wait and running items finish.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/admin/jobs/pause [post]
+func (h *AdminHandler) PauseJobs(c *gin.Context) {
+	schedules := h.schedules.SetJobsEnabled(false)
+	c.JSON(http.StatusOK, gin.H{"schedules": schedules})
+}
+
+// ResumeJobs godoc
+// @Summary Resume background jobs
+// @Description Enable every schedule and job type except collection
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/admin/jobs/resume [post]
+func (h *AdminHandler) ResumeJobs(c *gin.Context) {
+	schedules := h.schedules.SetJobsEnabled(true)
+	c.JSON(http.StatusOK, gin.H{"schedules": schedules})
+}
+
+// GetSchedules godoc
+// @Summary List background schedules
+// @Description List the background schedules and job types, whether each is enabled and the runs skipped while disabled
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/admin/schedules [get]
+func (h *AdminHandler) GetSchedules(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"schedules": h.schedules.List()})
+}
+
+// SetSchedule godoc
+// @Summary Enable or disable a background schedule
+// @Description Enable or disable one schedule or job type: collection, cleanup, pattern_scan, backfill, indicator_recompute, news, calendar, reference_data, symbol_stats, digest or automations. Disabled job types keep their queued jobs until enabled.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param name path string true "Schedule name"
+// @Param request body scheduleRequest true "Whether the schedule runs"
+// @Success 200 {object} models.ScheduleStatus
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/admin/schedules/{name} [put]
+func (h *AdminHandler) SetSchedule(c *gin.Context) {
+	var req scheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, "Invalid request body", err)
+		return
+	}
+	h.setSchedule(c, c.Param("name"), *req.Enabled)
+}
+
+// setSchedule enables or disables a schedule and responds with its status
+func (h *AdminHandler) setSchedule(c *gin.Context, name string, enabled bool) {
+	status, err := h.schedules.SetEnabled(name, enabled)
+	if err != nil {
+		respondError(c, "Failed to update schedule", err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
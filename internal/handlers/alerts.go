@@ -0,0 +1,349 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AlertsHandler handles alert rule API endpoints
+type AlertsHandler struct {
+	db               *database.Database
+	alertRuleService *services.AlertRuleService
+}
+
+// NewAlertsHandler creates a new alerts handler
+func NewAlertsHandler(db *database.Database, alertRuleService *services.AlertRuleService) *AlertsHandler {
+	return &AlertsHandler{
+		db:               db,
+		alertRuleService: alertRuleService,
+	}
+}
+
+// alertRuleRequest is the request body for creating or updating an alert rule
+type alertRuleRequest struct {
+	Name            string                  `json:"name"`
+	Symbol          string                  `json:"symbol"`
+	Conditions      []models.AlertCondition `json:"conditions"`
+	Logic           string                  `json:"logic"`
+	IsActive        *bool                   `json:"is_active"`
+	NotifyEmail     *bool                   `json:"notify_email"`
+	CooldownMinutes *int                    `json:"cooldown_minutes"`
+	Then            []models.AlertStep      `json:"then"`
+}
+
+// apply copies the request fields onto a rule, keeping existing values for omitted optional fields
+func (r *alertRuleRequest) apply(rule *models.AlertRule) {
+	rule.Name = r.Name
+	rule.Symbol = r.Symbol
+	rule.Conditions = r.Conditions
+	rule.Logic = r.Logic
+	rule.Then = r.Then
+	if r.IsActive != nil {
+		rule.IsActive = *r.IsActive
+	}
+	if r.NotifyEmail != nil {
+		rule.NotifyEmail = *r.NotifyEmail
+	}
+	if r.CooldownMinutes != nil {
+		rule.CooldownMinutes = *r.CooldownMinutes
+	}
+}
+
+// GetRules godoc
+// @Summary List alert rules
+// @Description Get all alert rules, optionally only active ones
+// @Tags alerts
+// @Produce json
+// @Param active query bool false "Only return active rules"
+// @Success 200 {array} models.AlertRule
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/alerts [get]
+func (h *AlertsHandler) GetRules(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	activeOnly := c.Query("active") == "true"
+
+	rules, err := db.GetAlertRules(activeOnly)
+	if err != nil {
+		respondError(c, "Failed to get alert rules", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rules":  rules,
+		"count":  len(rules),
+		"fields": models.AlertFields,
+	})
+}
+
+// CreateRule godoc
+// @Summary Create an alert rule
+// @Description Create a rule such as "rsi_14 < 30 AND support_distance_percent <= 1". Conditions compare a field with a value, or with another field's value via value_field (e.g. price > vwap). Legs in then are matched in order after the conditions, each within_minutes of the previous leg last matching, and the rule fires when the last leg matches.
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.AlertRule
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/alerts [post]
+func (h *AlertsHandler) CreateRule(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	var request alertRuleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalid(c, "Invalid request body", err)
+		return
+	}
+
+	rule := &models.AlertRule{
+		IsActive:        true,
+		NotifyEmail:     true,
+		CooldownMinutes: 60,
+	}
+	request.apply(rule)
+
+	if err := rule.Validate(); err != nil {
+		respondInvalid(c, "", err)
+		return
+	}
+
+	if err := db.InsertAlertRule(rule); err != nil {
+		respondError(c, "Failed to create alert rule", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// GetRule godoc
+// @Summary Get an alert rule
+// @Tags alerts
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Success 200 {object} models.AlertRule
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/alerts/{id} [get]
+func (h *AlertsHandler) GetRule(c *gin.Context) {
+	rule, ok := h.loadRule(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// UpdateRule godoc
+// @Summary Update an alert rule
+// @Description Update an alert rule; sequences in progress start over
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Success 200 {object} models.AlertRule
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/alerts/{id} [put]
+func (h *AlertsHandler) UpdateRule(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	rule, ok := h.loadRule(c)
+	if !ok {
+		return
+	}
+
+	var request alertRuleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalid(c, "Invalid request body", err)
+		return
+	}
+	request.apply(rule)
+
+	if err := rule.Validate(); err != nil {
+		respondInvalid(c, "", err)
+		return
+	}
+
+	if err := db.UpdateAlertRule(rule); err != nil {
+		respondError(c, "Failed to update alert rule", err)
+		return
+	}
+	if err := db.DeleteAlertRuleStates(rule.ID); err != nil {
+		respondError(c, "Failed to reset alert rule sequences", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRule godoc
+// @Summary Delete an alert rule
+// @Description Delete an alert rule and its trigger history
+// @Tags alerts
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/alerts/{id} [delete]
+func (h *AlertsHandler) DeleteRule(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	id, ok := parseRuleID(c)
+	if !ok {
+		return
+	}
+
+	if err := db.DeleteAlertRule(id); err != nil {
+		respondError(c, "Failed to delete alert rule", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Alert rule deleted",
+		"id":      id,
+	})
+}
+
+// GetRuleHistory godoc
+// @Summary Get trigger history for an alert rule
+// @Tags alerts
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Param limit query int false "Maximum number of triggers"
+// @Success 200 {array} models.AlertTrigger
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/alerts/{id}/history [get]
+func (h *AlertsHandler) GetRuleHistory(c *gin.Context) {
+	id, ok := parseRuleID(c)
+	if !ok {
+		return
+	}
+
+	h.respondWithTriggers(c, &models.AlertTriggerFilter{RuleID: id})
+}
+
+// GetRuleState godoc
+// @Summary Get the sequences in progress of an alert rule
+// @Description Get how many legs of a sequenced rule each symbol has matched, when the last one matched and when the next leg's window closes
+// @Tags alerts
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/alerts/{id}/state [get]
+func (h *AlertsHandler) GetRuleState(c *gin.Context) {
+	rule, ok := h.loadRule(c)
+	if !ok {
+		return
+	}
+
+	states, err := withRequestContext(c, h.db).GetAlertRuleStates(rule.ID)
+	if err != nil {
+		respondError(c, "Failed to get alert rule state", err)
+		return
+	}
+	for _, state := range states {
+		state.ExpiresAt = rule.Deadline(state)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rule_id": rule.ID,
+		"legs":    len(rule.Legs()),
+		"states":  states,
+	})
+}
+
+// GetHistory godoc
+// @Summary Get trigger history for all alert rules
+// @Tags alerts
+// @Produce json
+// @Param symbol query string false "Filter by symbol"
+// @Param limit query int false "Maximum number of triggers"
+// @Success 200 {array} models.AlertTrigger
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/alerts/history [get]
+func (h *AlertsHandler) GetHistory(c *gin.Context) {
+	h.respondWithTriggers(c, &models.AlertTriggerFilter{Symbol: c.Query("symbol")})
+}
+
+// EvaluateRules godoc
+// @Summary Evaluate alert rules now
+// @Description Evaluate all active alert rules against watched symbols without waiting for the next collection cycle
+// @Tags alerts
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/alerts/evaluate [post]
+func (h *AlertsHandler) EvaluateRules(c *gin.Context) {
+	triggers, err := h.alertRuleService.EvaluateAllRules()
+	if err != nil {
+		respondError(c, "Failed to evaluate alert rules", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"triggers": triggers,
+		"count":    len(triggers),
+	})
+}
+
+// respondWithTriggers writes the trigger history matching a filter, honoring the limit query parameter
+func (h *AlertsHandler) respondWithTriggers(c *gin.Context, filter *models.AlertTriggerFilter) {
+	db := withRequestContext(c, h.db)
+
+	if limit, err := strconv.Atoi(c.DefaultQuery("limit", "100")); err == nil && limit > 0 {
+		filter.Limit = limit
+	}
+
+	triggers, err := db.GetAlertTriggers(filter)
+	if err != nil {
+		respondError(c, "Failed to get alert history", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, triggers)
+}
+
+// loadRule loads the rule named by the id path parameter, writing an error response on failure
+func (h *AlertsHandler) loadRule(c *gin.Context) (*models.AlertRule, bool) {
+	db := withRequestContext(c, h.db)
+
+	id, ok := parseRuleID(c)
+	if !ok {
+		return nil, false
+	}
+
+	rule, err := db.GetAlertRuleByID(id)
+	if err != nil {
+		respondError(c, "Failed to get alert rule", err)
+		return nil, false
+	}
+
+	if rule == nil {
+		respondNotFound(c, "Alert rule not found", nil)
+		return nil, false
+	}
+
+	return rule, true
+}
+
+// parseRuleID parses the id path parameter, writing a bad request response on failure
+func parseRuleID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondInvalid(c, "Invalid alert rule ID", nil)
+		return 0, false
+	}
+	return id, true
+}
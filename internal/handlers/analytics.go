@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AnalyticsHandler handles watchlist analytics API endpoints
+type AnalyticsHandler struct {
+	sectorStrength      *services.SectorStrengthService
+	relativePerformance *services.RelativePerformanceService
+}
+
+// NewAnalyticsHandler creates a new analytics handler
+func NewAnalyticsHandler(sectorStrength *services.SectorStrengthService, relativePerformance *services.RelativePerformanceService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		sectorStrength:      sectorStrength,
+		relativePerformance: relativePerformance,
+	}
+}
+
+// GetSectorStrength godoc
+// @Summary Rank watchlist sectors by relative strength
+// @Description Group watchlist stocks by sector, compute each stock's return minus the benchmark return over each window of daily sessions, and rank sectors by their average relative strength
+// @Tags analytics
+// @Produce json
+// @Param windows query string false "Comma separated windows in trading days (default from config, e.g. 5,20,60)"
+// @Param benchmark query string false "Benchmark symbol (default from config, e.g. SPY)"
+// @Success 200 {object} models.SectorStrengthReport
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/analytics/sectors [get]
+func (h *AnalyticsHandler) GetSectorStrength(c *gin.Context) {
+	var windows []int
+	if value := c.Query("windows"); value != "" {
+		for _, part := range strings.Split(value, ",") {
+			window, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || window < 1 || window > models.MaxRelativeStrengthWindow {
+				respondInvalid(c, "Invalid windows parameter, expected comma separated trading day counts between 1 and "+strconv.Itoa(models.MaxRelativeStrengthWindow), nil)
+				return
+			}
+			windows = append(windows, window)
+		}
+	}
+
+	report, err := h.sectorStrength.Analyze(windows, c.Query("benchmark"))
+	if err != nil {
+		respondError(c, "", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetRelativePerformance godoc
+// @Summary Compare the relative performance of symbols
+// @Description Get each symbol's percent change over the range from its stored regular session bars, one value per timestamp shared by every series, so the series chart together without downloading candles. Series start at the first timestamp every symbol has a close at.
+// @Tags analytics
+// @Produce json
+// @Param symbols query string true "Comma separated symbols, up to 10"
+// @Param range query string false "Range: 1D, 1W, 2W, 1M, 3M, 6M or 1Y" default(1M)
+// @Param timeframe query string false "Candle timeframe: 1m, 5m, 15m, 1h or 1d (default picked from the range)"
+// @Success 200 {object} models.RelativePerformance
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/compare [get]
+func (h *AnalyticsHandler) GetRelativePerformance(c *gin.Context) {
+	var symbols []string
+	for _, part := range strings.Split(c.Query("symbols"), ",") {
+		symbol := strings.ToUpper(strings.TrimSpace(part))
+		if symbol != "" && !slices.Contains(symbols, symbol) {
+			symbols = append(symbols, symbol)
+		}
+	}
+	if len(symbols) == 0 || len(symbols) > models.MaxCompareSymbols {
+		respondInvalid(c, "symbols must list between 1 and "+strconv.Itoa(models.MaxCompareSymbols)+" comma separated symbols", nil)
+		return
+	}
+
+	var timeframe models.Timeframe
+	if value := c.Query("timeframe"); value != "" {
+		tf, err := models.ParseTimeframe(value)
+		if err != nil {
+			respondInvalid(c, "", err)
+			return
+		}
+		timeframe = tf
+	}
+
+	result, err := h.relativePerformance.Compare(symbols, c.DefaultQuery("range", "1M"), timeframe)
+	if err != nil {
+		respondError(c, "", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
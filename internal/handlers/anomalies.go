@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AnomaliesHandler handles the market anomaly endpoints
+type AnomaliesHandler struct {
+	db        *database.Database
+	anomalies *services.AnomalyService
+}
+
+// NewAnomaliesHandler creates a new anomalies handler
+func NewAnomaliesHandler(db *database.Database, anomalies *services.AnomalyService) *AnomaliesHandler {
+	return &AnomaliesHandler{
+		db:        db,
+		anomalies: anomalies,
+	}
+}
+
+// GetAnomalies godoc
+// @Summary List market anomalies
+// @Description Get flagged volume spikes, price gaps and potential halts, most recent bar first
+// @Tags anomalies
+// @Produce json
+// @Param symbol query string false "Filter by symbol"
+// @Param type query string false "Filter by type: volume_spike, price_gap, halt"
+// @Param hours query int false "Only anomalies on bars of the last N hours (default 24, max 720)"
+// @Param limit query int false "Maximum number of anomalies (default 50, max 500)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/anomalies [get]
+func (h *AnomaliesHandler) GetAnomalies(c *gin.Context) {
+	h.listAnomalies(c, strings.ToUpper(c.Query("symbol")))
+}
+
+// GetSymbolAnomalies godoc
+// @Summary List a symbol's market anomalies
+// @Description Get the volume spikes, price gaps and potential halts flagged on a symbol's bars, most recent bar first
+// @Tags anomalies
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Param type query string false "Filter by type: volume_spike, price_gap, halt"
+// @Param hours query int false "Only anomalies on bars of the last N hours (default 24, max 720)"
+// @Param limit query int false "Maximum number of anomalies (default 50, max 500)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/anomalies/{symbol} [get]
+func (h *AnomaliesHandler) GetSymbolAnomalies(c *gin.Context) {
+	h.listAnomalies(c, strings.ToUpper(c.Param("symbol")))
+}
+
+// listAnomalies responds with the anomalies matching the type, hours and limit query parameters
+func (h *AnomaliesHandler) listAnomalies(c *gin.Context, symbol string) {
+	db := withRequestContext(c, h.db)
+
+	anomalyType := c.Query("type")
+	if anomalyType != "" && !slices.Contains(models.AnomalyTypes, anomalyType) {
+		respondInvalid(c, "Type must be volume_spike, price_gap or halt", nil)
+		return
+	}
+
+	hours := 24
+	if n, err := strconv.Atoi(c.Query("hours")); err == nil && n > 0 && n <= 720 {
+		hours = n
+	}
+	limit := 50
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= 500 {
+		limit = l
+	}
+
+	anomalies, err := db.GetMarketAnomalies(&models.AnomalyFilter{
+		Symbol: symbol,
+		Type:   anomalyType,
+		Since:  time.Now().Add(-time.Duration(hours) * time.Hour),
+		Limit:  limit,
+	})
+	if err != nil {
+		respondError(c, "Failed to get market anomalies", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"anomalies": anomalies,
+		"count":     len(anomalies),
+		"hours":     hours,
+	})
+}
+
+// ScanAnomalies godoc
+// @Summary Scan for market anomalies now
+// @Description Check the latest bars of a symbol, or of every watched symbol, for volume spikes, price gaps and potential halts. Only anomalies not flagged before are returned and notified.
+// @Tags anomalies
+// @Produce json
+// @Param symbol query string false "Symbol to scan (default all watched symbols)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/anomalies/scan [post]
+func (h *AnomaliesHandler) ScanAnomalies(c *gin.Context) {
+	symbols := []string{strings.ToUpper(c.Query("symbol"))}
+	if symbols[0] == "" {
+		var err error
+		symbols, err = withRequestContext(c, h.db).GetWatchedSymbols()
+		if err != nil {
+			respondError(c, "Failed to get watched symbols", err)
+			return
+		}
+	}
+
+	anomalies, err := h.anomalies.ScanSymbols(symbols)
+	if err != nil {
+		respondError(c, "Failed to scan for market anomalies", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"anomalies": anomalies,
+		"count":     len(anomalies),
+		"symbols":   len(symbols),
+	})
+}
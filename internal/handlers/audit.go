@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// auditActorHeader names the caller of an audited request; the client IP is recorded without it
+const auditActorHeader = "X-Actor"
+
+// auditSecretKeys are the payload field name fragments whose values are redacted
+var auditSecretKeys = []string{"password", "token", "secret", "api_key", "apikey", "authorization"}
+
+// AuditMiddleware records every POST, PUT, PATCH and DELETE request with its actor, payload summary and
+// outcome in the audit log. It must run outside ErrorMiddleware so the final status is recorded.
+func AuditMiddleware(db *database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		payload := auditPayload(c.Request)
+
+		c.Next()
+
+		entry := &models.AuditEntry{
+			Timestamp:  start,
+			Actor:      c.GetHeader(auditActorHeader),
+			Method:     c.Request.Method,
+			Path:       c.Request.URL.Path,
+			Route:      c.FullPath(),
+			Query:      c.Request.URL.RawQuery,
+			Payload:    payload,
+			StatusCode: c.Writer.Status(),
+			DurationMS: time.Since(start).Milliseconds(),
+		}
+		if entry.Actor == "" {
+			entry.Actor = c.ClientIP()
+		}
+		entry.Success = entry.StatusCode < http.StatusBadRequest
+		if last := c.Errors.Last(); last != nil {
+			_, response := errorResponse(last.Err)
+			entry.Error = response.Message
+		}
+
+		if err := db.InsertAuditEntry(entry); err != nil {
+			log.Printf("Failed to record audit entry for %s %s: %v", entry.Method, entry.Path, err)
+		}
+	}
+}
+
+// auditPayload summarizes a request body: JSON with secrets redacted and truncated, or the size and type of
+// any other body. The body is restored for the handler.
+func auditPayload(req *http.Request) string {
+	if req.Body == nil || req.ContentLength == 0 {
+		return ""
+	}
+
+	contentType := req.Header.Get("Content-Type")
+	if !strings.Contains(contentType, "json") {
+		if req.ContentLength < 0 {
+			return fmt.Sprintf("<%s body>", contentType)
+		}
+		return fmt.Sprintf("<%d bytes of %s>", req.ContentLength, contentType)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil || len(body) == 0 {
+		return ""
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return truncateAuditPayload(string(body))
+	}
+	summary, err := json.Marshal(redactAuditValue(value))
+	if err != nil {
+		return ""
+	}
+	return truncateAuditPayload(string(summary))
+}
+
+// redactAuditValue replaces the values of secret fields anywhere in a decoded JSON value
+func redactAuditValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isAuditSecret(key) {
+				v[key] = models.AuditRedacted
+			} else {
+				v[key] = redactAuditValue(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactAuditValue(item)
+		}
+	}
+	return value
+}
+
+// isAuditSecret reports whether a payload field holds a secret
+func isAuditSecret(key string) bool {
+	key = strings.ToLower(key)
+	for _, secret := range auditSecretKeys {
+		if strings.Contains(key, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// truncateAuditPayload cuts a payload to the stored length
+func truncateAuditPayload(payload string) string {
+	if utf8.RuneCountInString(payload) <= models.MaxAuditPayloadLength {
+		return payload
+	}
+	runes := []rune(payload)
+	return string(runes[:models.MaxAuditPayloadLength]) + "…"
+}
+
+// AuditHandler handles the audit log API endpoints
+type AuditHandler struct {
+	db *database.Database
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(db *database.Database) *AuditHandler {
+	return &AuditHandler{
+		db: db,
+	}
+}
+
+// GetAuditLog godoc
+// @Summary List audit log entries
+// @Description Get the recorded POST, PUT, PATCH and DELETE requests, newest first, with their actor (the X-Actor header or client IP), payload with secrets redacted, status and error
+// @Tags audit
+// @Produce json
+// @Param actor query string false "Filter by actor"
+// @Param method query string false "Filter by HTTP method"
+// @Param path query string false "Filter by path prefix, e.g. /api/v1/watchlist"
+// @Param success query bool false "Only successful or only failed requests"
+// @Param from query string false "Start time (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End time (RFC3339 or YYYY-MM-DD, inclusive)"
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Entries per page (default 50, max 500)"
+// @Success 200 {object} models.AuditLogResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/audit [get]
+func (h *AuditHandler) GetAuditLog(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	page, err := parsePageRequest(c, models.DefaultPageLimit, nil)
+	if err != nil {
+		respondInvalid(c, "", err)
+		return
+	}
+
+	filter := &models.AuditFilter{
+		Actor:  c.Query("actor"),
+		Method: strings.ToUpper(c.Query("method")),
+		Path:   c.Query("path"),
+		Limit:  page.Limit,
+		Offset: page.Offset(),
+	}
+	if value := c.Query("success"); value != "" {
+		success, err := strconv.ParseBool(value)
+		if err != nil {
+			respondInvalid(c, "Invalid success parameter", err)
+			return
+		}
+		filter.Success = &success
+	}
+	filter.From, filter.To, err = services.ParseExportRange(c.Query("from"), c.Query("to"))
+	if err != nil {
+		respondInvalid(c, "", err)
+		return
+	}
+
+	entries, err := db.GetAuditEntries(filter)
+	if err != nil {
+		respondError(c, "Failed to get audit log", err)
+		return
+	}
+	total, err := db.CountAuditEntries(filter)
+	if err != nil {
+		respondError(c, "Failed to count audit log entries", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &models.AuditLogResponse{
+		Entries:    entries,
+		Pagination: models.NewPagination(page, total),
+	})
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"market-watch-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestAuditMiddleware tests that mutating requests are recorded with their actor, redacted payload and outcome
+func TestAuditMiddleware(t *testing.T) {
+	db := newTestDB(t)
+
+	router := gin.New()
+	router.Use(AuditMiddleware(db), ErrorMiddleware())
+	router.GET("/api/v1/settings", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.PUT("/api/v1/settings/:section", func(c *gin.Context) {
+		var body map[string]interface{}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			respondInvalid(c, "Invalid request body", err)
+			return
+		}
+		c.JSON(http.StatusOK, body)
+	})
+	router.DELETE("/api/v1/symbols/:symbol", func(c *gin.Context) {
+		respondNotFound(c, "Symbol not found", nil)
+	})
+
+	serve := func(method, path, body, actor string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if actor != "" {
+			req.Header.Set("X-Actor", actor)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	serve("GET", "/api/v1/settings", "", "alice")
+	w := serve("PUT", "/api/v1/settings/display?dry_run=true", `{"timezone":"UTC","api_key":"abc","nested":{"bot_token":"xyz"}}`, "alice")
+	if !strings.Contains(w.Body.String(), `"api_key":"abc"`) {
+		t.Errorf("expected the handler to read the whole body, got %s", w.Body.String())
+	}
+	serve("DELETE", "/api/v1/symbols/NONE", "", "")
+
+	entries, err := db.GetAuditEntries(&models.AuditFilter{})
+	if err != nil {
+		t.Fatalf("GetAuditEntries failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected the two mutating requests to be recorded, got %d", len(entries))
+	}
+
+	failed, put := entries[0], entries[1]
+	if put.Actor != "alice" || put.Route != "/api/v1/settings/:section" || put.Query != "dry_run=true" || !put.Success {
+		t.Errorf("unexpected entry: %+v", put)
+	}
+	if strings.Contains(put.Payload, "abc") || strings.Contains(put.Payload, "xyz") || !strings.Contains(put.Payload, `"timezone":"UTC"`) {
+		t.Errorf("expected secrets to be redacted from the payload, got %s", put.Payload)
+	}
+	if failed.Actor == "" || failed.StatusCode != http.StatusNotFound || failed.Success || failed.Error != "Symbol not found" {
+		t.Errorf("unexpected failed entry: %+v", failed)
+	}
+
+	success := false
+	filter := &models.AuditFilter{Success: &success, Path: "/api/v1/symbols"}
+	if count, err := db.CountAuditEntries(filter); err != nil || count != 1 {
+		t.Errorf("expected one failed symbols request, got %d, %v", count, err)
+	}
+
+	h := NewAuditHandler(db)
+	router.GET("/api/v1/audit", h.GetAuditLog)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/audit?method=put&actor=alice", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"total":1`) {
+		t.Errorf("expected one PUT by alice, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/audit?from=yesterday", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected an invalid from to be rejected, got %d", w.Code)
+	}
+}
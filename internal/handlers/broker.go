@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BrokerHandler handles the broker order and strategy auto trading endpoints
+type BrokerHandler struct {
+	db     *database.Database
+	broker *services.BrokerService
+}
+
+// autoTradeRequest is the request body for turning a strategy's auto trading on or off
+type autoTradeRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// NewBrokerHandler creates a new broker handler
+func NewBrokerHandler(db *database.Database, broker *services.BrokerService) *BrokerHandler {
+	return &BrokerHandler{
+		db:     db,
+		broker: broker,
+	}
+}
+
+// GetBrokerStatus godoc
+// @Summary Get the broker status
+// @Description Get whether orders are placed with the broker, on the paper or live account, which strategies trade automatically and the last order sync
+// @Tags broker
+// @Produce json
+// @Success 200 {object} models.BrokerStatus
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/broker/status [get]
+func (h *BrokerHandler) GetBrokerStatus(c *gin.Context) {
+	status, err := h.broker.GetStatus()
+	if err != nil {
+		respondError(c, "Failed to get broker status", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// GetBrokerOrders godoc
+// @Summary List broker orders
+// @Description Get the bracket orders placed for triggered setups, newest first
+// @Tags broker
+// @Produce json
+// @Param status query string false "Filter by status: submitted, filled, closed, canceled, failed"
+// @Param symbol query string false "Filter by symbol"
+// @Param open query bool false "Only orders with working legs at the broker"
+// @Param limit query int false "Maximum number of orders (default 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/broker/orders [get]
+func (h *BrokerHandler) GetBrokerOrders(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	filter := &models.BrokerOrderFilter{
+		Status: c.Query("status"),
+		Symbol: strings.ToUpper(c.Query("symbol")),
+		Open:   c.Query("open") == "true",
+		Limit:  100,
+	}
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 && limit <= 1000 {
+		filter.Limit = limit
+	}
+
+	orders, err := db.GetBrokerOrders(filter)
+	if err != nil {
+		respondError(c, "Failed to get broker orders", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"orders": orders,
+		"count":  len(orders),
+	})
+}
+
+// SyncBrokerOrders godoc
+// @Summary Sync broker orders now
+// @Description Refresh open orders from the broker without waiting for the next interval; a filled entry opens a portfolio position and a filled stop or target closes it
+// @Tags broker
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/broker/orders/sync [post]
+func (h *BrokerHandler) SyncBrokerOrders(c *gin.Context) {
+	changed, err := h.broker.SyncOrders()
+	if err != nil {
+		respondError(c, "Failed to sync broker orders", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Broker orders synced",
+		"changed": changed,
+	})
+}
+
+// CancelBrokerOrder godoc
+// @Summary Cancel a broker order
+// @Description Cancel a bracket order whose entry has not filled, along with its stop and target
+// @Tags broker
+// @Produce json
+// @Param id path int true "Broker order ID"
+// @Success 200 {object} models.BrokerOrder
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/broker/orders/{id}/cancel [post]
+func (h *BrokerHandler) CancelBrokerOrder(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		respondInvalid(c, "Invalid broker order ID", nil)
+		return
+	}
+
+	order, err := h.broker.CancelOrder(id)
+	if err != nil {
+		respondError(c, "Failed to cancel broker order", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// SetStrategyAutoTrade godoc
+// @Summary Turn a strategy's auto trading on or off
+// @Description When on, a setup that triggers on a stock of the strategy places a bracket order (entry, stop and first target) with the broker, sized by the risk settings. Orders are only placed while the broker is enabled in the config.
+// @Tags watchlist
+// @Accept json
+// @Produce json
+// @Param id path int true "Strategy ID"
+// @Param request body autoTradeRequest true "Auto trading"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/watchlist/strategies/{id}/auto-trade [put]
+func (h *BrokerHandler) SetStrategyAutoTrade(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	strategyID, ok := parseStrategyID(c)
+	if !ok {
+		return
+	}
+
+	var req autoTradeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, "Invalid request body", err)
+		return
+	}
+	if req.Enabled == nil {
+		respondInvalid(c, "enabled is required", nil)
+		return
+	}
+
+	exists, err := db.StrategyExists(strategyID)
+	if err != nil {
+		respondError(c, "Failed to check strategy", err)
+		return
+	}
+	if !exists {
+		respondNotFound(c, fmt.Sprintf("Strategy %d not found", strategyID), nil)
+		return
+	}
+
+	if err := db.SetStrategyAutoTrade(strategyID, *req.Enabled); err != nil {
+		respondError(c, "Failed to set strategy auto trading", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"strategy_id":    strategyID,
+		"auto_trade":     *req.Enabled,
+		"broker_enabled": h.broker.IsEnabled(),
+	})
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CalendarHandler handles earnings and economic calendar API endpoints
+type CalendarHandler struct {
+	db              *database.Database
+	calendarService *services.CalendarService
+}
+
+// NewCalendarHandler creates a new calendar handler
+func NewCalendarHandler(db *database.Database, calendarService *services.CalendarService) *CalendarHandler {
+	return &CalendarHandler{
+		db:              db,
+		calendarService: calendarService,
+	}
+}
+
+// addEventRequest is the request body for adding a calendar event manually
+type addEventRequest struct {
+	Symbol     string `json:"symbol"`
+	EventType  string `json:"event_type"`
+	Title      string `json:"title"`
+	EventDate  string `json:"event_date"` // YYYY-MM-DD
+	EventTime  string `json:"event_time"`
+	Importance int    `json:"importance"`
+	Details    string `json:"details"`
+}
+
+// GetEvents godoc
+// @Summary List upcoming calendar events
+// @Description Get earnings and economic events over the next number of days
+// @Tags calendar
+// @Produce json
+// @Param symbol query string false "Filter by symbol (market-wide events are included)"
+// @Param type query string false "Filter by event type: earnings, economic"
+// @Param days query int false "Number of days ahead (default 30)"
+// @Success 200 {array} models.CalendarEvent
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/calendar [get]
+func (h *CalendarHandler) GetEvents(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days <= 0 {
+		days = 30
+	}
+
+	now := time.Now()
+	filter := &models.CalendarEventFilter{
+		Symbol:        strings.ToUpper(c.Query("symbol")),
+		EventType:     c.Query("type"),
+		IncludeMarket: true,
+		From:          now.AddDate(0, 0, -1),
+		To:            now.AddDate(0, 0, days),
+	}
+
+	events, err := db.GetCalendarEvents(filter)
+	if err != nil {
+		respondError(c, "Failed to get calendar events", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+		"count":  len(events),
+	})
+}
+
+// GetSymbolEvents godoc
+// @Summary Get upcoming events for a symbol
+// @Tags calendar
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Param days query int false "Number of days ahead (default 30)"
+// @Success 200 {array} models.CalendarEvent
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/calendar/{symbol} [get]
+func (h *CalendarHandler) GetSymbolEvents(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days <= 0 {
+		days = 30
+	}
+
+	events, err := h.calendarService.GetUpcomingEvents(symbol, days)
+	if err != nil {
+		respondError(c, "Failed to get calendar events", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"symbol": symbol,
+		"events": events,
+		"count":  len(events),
+	})
+}
+
+// AddEvent godoc
+// @Summary Add a calendar event
+// @Description Add a manual earnings date or a market-wide economic event (leave symbol empty)
+// @Tags calendar
+// @Accept json
+// @Produce json
+// @Param event body addEventRequest true "Calendar event"
+// @Success 201 {object} models.CalendarEvent
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/calendar/events [post]
+func (h *CalendarHandler) AddEvent(c *gin.Context) {
+	var request addEventRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalid(c, "Invalid request body", err)
+		return
+	}
+
+	eventDate, err := time.ParseInLocation("2006-01-02", request.EventDate, time.Local)
+	if err != nil {
+		respondInvalid(c, "Invalid event_date, expected YYYY-MM-DD", nil)
+		return
+	}
+
+	event := &models.CalendarEvent{
+		Symbol:     request.Symbol,
+		EventType:  request.EventType,
+		Title:      request.Title,
+		EventDate:  eventDate,
+		EventTime:  request.EventTime,
+		Importance: request.Importance,
+		Details:    request.Details,
+	}
+
+	if err := h.calendarService.AddEvent(event); err != nil {
+		respondInvalid(c, "Failed to add calendar event", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, event)
+}
+
+// DeleteEvent godoc
+// @Summary Delete a calendar event
+// @Tags calendar
+// @Produce json
+// @Param id path int true "Event ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/calendar/events/{id} [delete]
+func (h *CalendarHandler) DeleteEvent(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondInvalid(c, "Invalid event ID", nil)
+		return
+	}
+
+	if err := db.DeleteCalendarEvent(id); err != nil {
+		respondError(c, "Failed to delete calendar event", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Calendar event deleted",
+	})
+}
+
+// RefreshEarnings godoc
+// @Summary Refresh earnings dates
+// @Description Fetch upcoming earnings dates for all watched symbols from Polygon
+// @Tags calendar
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/calendar/refresh [post]
+func (h *CalendarHandler) RefreshEarnings(c *gin.Context) {
+	if err := h.calendarService.RefreshEarnings(); err != nil {
+		respondError(c, "Failed to refresh earnings calendar", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Earnings calendar refreshed",
+	})
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CalibrationHandler handles the setup score calibration endpoints
+type CalibrationHandler struct {
+	calibration *services.CalibrationService
+}
+
+// NewCalibrationHandler creates a new calibration handler
+func NewCalibrationHandler(calibration *services.CalibrationService) *CalibrationHandler {
+	return &CalibrationHandler{
+		calibration: calibration,
+	}
+}
+
+// GetCalibration godoc
+// @Summary Get setup score calibration
+// @Description Get the scorer in use, the active weight version and the latest calibrated versions with each checklist item's win rates, lift and weight
+// @Tags setups
+// @Produce json
+// @Param limit query int false "Maximum number of versions (default 20)"
+// @Success 200 {object} models.CalibrationStatus
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/setups/calibration [get]
+func (h *CalibrationHandler) GetCalibration(c *gin.Context) {
+	limit := 20
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+
+	status, err := h.calibration.GetStatus(limit)
+	if err != nil {
+		respondError(c, "Failed to get setup score calibration", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// RunCalibration godoc
+// @Summary Calibrate setup scoring weights now
+// @Description Learn checklist item weights from every setup that completed or stopped out and store them as a new version. The version is not used for scoring until it is activated, unless auto_activate is set and a version is already active.
+// @Tags setups
+// @Produce json
+// @Success 200 {object} models.ScoringWeightVersion
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/setups/calibration/run [post]
+func (h *CalibrationHandler) RunCalibration(c *gin.Context) {
+	version, err := h.calibration.Calibrate()
+	if err != nil {
+		respondError(c, "Failed to calibrate setup scoring", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, version)
+}
+
+// ActivateCalibration godoc
+// @Summary Score setups with a calibrated weight version
+// @Description Opt into the calibrated scorer: setups detected from now on score the sum of the calibrated weights of their completed checklist items
+// @Tags setups
+// @Produce json
+// @Param id path int true "Weight version ID"
+// @Success 200 {object} models.ScoringWeightVersion
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/setups/calibration/versions/{id}/activate [post]
+func (h *CalibrationHandler) ActivateCalibration(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		respondInvalid(c, "Invalid weight version ID", nil)
+		return
+	}
+
+	version, err := h.calibration.Activate(id)
+	if err != nil {
+		respondError(c, "Failed to activate weight version", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, version)
+}
+
+// DeactivateCalibration godoc
+// @Summary Return to the default setup scorer
+// @Description Score setups detected from now on with the configured category weights
+// @Tags setups
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/setups/calibration/deactivate [post]
+func (h *CalibrationHandler) DeactivateCalibration(c *gin.Context) {
+	if err := h.calibration.Deactivate(); err != nil {
+		respondError(c, "Failed to deactivate calibrated scoring", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Scoring setups with the default weights",
+	})
+}
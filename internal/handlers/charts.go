@@ -0,0 +1,355 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChartsHandler handles the chart annotations and layouts users save per symbol
+type ChartsHandler struct {
+	db *database.Database
+}
+
+// chartAnnotationRequest is the body of the chart annotation create and update endpoints
+type chartAnnotationRequest struct {
+	Type      string              `json:"type"`
+	Timeframe string              `json:"timeframe"` // empty to show on every timeframe
+	Points    []models.ChartPoint `json:"points"`
+	Price     float64             `json:"price"`
+	Text      string              `json:"text"`
+	Color     string              `json:"color"`
+}
+
+// chartLayoutRequest is the body of the chart layout endpoint
+type chartLayoutRequest struct {
+	Layout json.RawMessage `json:"layout" swaggertype:"object"`
+}
+
+// NewChartsHandler creates a new charts handler
+func NewChartsHandler(db *database.Database) *ChartsHandler {
+	return &ChartsHandler{db: db}
+}
+
+// chartUser returns the user whose drawings a request is about, named by the X-Actor header
+func chartUser(c *gin.Context) string {
+	if user := strings.TrimSpace(c.GetHeader(auditActorHeader)); user != "" {
+		return user
+	}
+	return models.DefaultChartUser
+}
+
+// GetChart godoc
+// @Summary Get a user's saved chart of a symbol
+// @Description Get the annotations and layout a user saved for a symbol's chart, so the dashboard can restore them. The user is named by the X-Actor header.
+// @Tags charts
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Param X-Actor header string false "User, defaults to 'default'"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/charts/{symbol} [get]
+func (h *ChartsHandler) GetChart(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+	user, symbol := chartUser(c), strings.ToUpper(c.Param("symbol"))
+
+	annotations, err := db.GetChartAnnotations(user, symbol, "")
+	if err != nil {
+		respondError(c, "Failed to fetch chart annotations", err)
+		return
+	}
+	layout, err := db.GetChartLayout(user, symbol)
+	if err != nil {
+		respondError(c, "Failed to fetch chart layout", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user":        user,
+		"symbol":      symbol,
+		"annotations": annotations,
+		"layout":      layout,
+	})
+}
+
+// GetChartAnnotations godoc
+// @Summary List a user's annotations of a symbol's chart
+// @Description List the trend lines, levels and notes a user drew on a symbol's chart, in the order they were drawn
+// @Tags charts
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Param timeframe query string false "Only annotations drawn on this timeframe or on every timeframe"
+// @Param X-Actor header string false "User, defaults to 'default'"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/charts/{symbol}/annotations [get]
+func (h *ChartsHandler) GetChartAnnotations(c *gin.Context) {
+	timeframe := c.Query("timeframe")
+	if timeframe != "" {
+		if _, err := models.ParseTimeframe(timeframe); err != nil {
+			respondInvalid(c, "Invalid timeframe", err)
+			return
+		}
+	}
+
+	annotations, err := withRequestContext(c, h.db).GetChartAnnotations(chartUser(c), strings.ToUpper(c.Param("symbol")), timeframe)
+	if err != nil {
+		respondError(c, "Failed to fetch chart annotations", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"annotations": annotations,
+		"count":       len(annotations),
+	})
+}
+
+// CreateChartAnnotation godoc
+// @Summary Save an annotation on a symbol's chart
+// @Description Save a trend line (2 points), level (price) or note (text at 1 point) a user drew on a symbol's chart
+// @Tags charts
+// @Accept json
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Param X-Actor header string false "User, defaults to 'default'"
+// @Param annotation body chartAnnotationRequest true "Annotation"
+// @Success 201 {object} models.ChartAnnotation
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/charts/{symbol}/annotations [post]
+func (h *ChartsHandler) CreateChartAnnotation(c *gin.Context) {
+	now := time.Now()
+	annotation := &models.ChartAnnotation{
+		User:      chartUser(c),
+		Symbol:    strings.ToUpper(c.Param("symbol")),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if !bindChartAnnotation(c, annotation) {
+		return
+	}
+
+	if err := withRequestContext(c, h.db).InsertChartAnnotation(annotation); err != nil {
+		respondError(c, "Failed to save chart annotation", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, annotation)
+}
+
+// UpdateChartAnnotation godoc
+// @Summary Update an annotation on a symbol's chart
+// @Description Replace the drawing of one of a user's annotations on a symbol's chart, such as after it was moved
+// @Tags charts
+// @Accept json
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Param id path int true "Annotation ID"
+// @Param X-Actor header string false "User, defaults to 'default'"
+// @Param annotation body chartAnnotationRequest true "Annotation"
+// @Success 200 {object} models.ChartAnnotation
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/charts/{symbol}/annotations/{id} [put]
+func (h *ChartsHandler) UpdateChartAnnotation(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	id, ok := chartAnnotationID(c)
+	if !ok {
+		return
+	}
+	annotation, err := db.GetChartAnnotation(chartUser(c), strings.ToUpper(c.Param("symbol")), id)
+	if err != nil {
+		respondError(c, "Failed to fetch chart annotation", err)
+		return
+	}
+	if !bindChartAnnotation(c, annotation) {
+		return
+	}
+
+	annotation.UpdatedAt = time.Now()
+	if err := db.UpdateChartAnnotation(annotation); err != nil {
+		respondError(c, "Failed to update chart annotation", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, annotation)
+}
+
+// DeleteChartAnnotation godoc
+// @Summary Delete an annotation from a symbol's chart
+// @Description Delete one of a user's annotations on a symbol's chart
+// @Tags charts
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Param id path int true "Annotation ID"
+// @Param X-Actor header string false "User, defaults to 'default'"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/charts/{symbol}/annotations/{id} [delete]
+func (h *ChartsHandler) DeleteChartAnnotation(c *gin.Context) {
+	id, ok := chartAnnotationID(c)
+	if !ok {
+		return
+	}
+
+	if err := withRequestContext(c, h.db).DeleteChartAnnotation(chartUser(c), strings.ToUpper(c.Param("symbol")), id); err != nil {
+		respondError(c, "Failed to delete chart annotation", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Chart annotation deleted"})
+}
+
+// ClearChartAnnotations godoc
+// @Summary Delete every annotation from a symbol's chart
+// @Description Delete all of a user's annotations on a symbol's chart, keeping its layout
+// @Tags charts
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Param X-Actor header string false "User, defaults to 'default'"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/charts/{symbol}/annotations [delete]
+func (h *ChartsHandler) ClearChartAnnotations(c *gin.Context) {
+	deleted, err := withRequestContext(c, h.db).DeleteChartAnnotations(chartUser(c), strings.ToUpper(c.Param("symbol")))
+	if err != nil {
+		respondError(c, "Failed to delete chart annotations", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Chart annotations deleted",
+		"deleted": deleted,
+	})
+}
+
+// GetChartLayout godoc
+// @Summary Get a user's layout of a symbol's chart
+// @Description Get the layout preferences, such as timeframe, indicators and zoom, a user saved for a symbol's chart
+// @Tags charts
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Param X-Actor header string false "User, defaults to 'default'"
+// @Success 200 {object} models.ChartLayout
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/charts/{symbol}/layout [get]
+func (h *ChartsHandler) GetChartLayout(c *gin.Context) {
+	layout, err := withRequestContext(c, h.db).GetChartLayout(chartUser(c), strings.ToUpper(c.Param("symbol")))
+	if err != nil {
+		respondError(c, "Failed to fetch chart layout", err)
+		return
+	}
+	if layout == nil {
+		respondNotFound(c, "No chart layout saved", nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, layout)
+}
+
+// SaveChartLayout godoc
+// @Summary Save a user's layout of a symbol's chart
+// @Description Save the layout preferences of a symbol's chart as a JSON object, replacing the ones saved before
+// @Tags charts
+// @Accept json
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Param X-Actor header string false "User, defaults to 'default'"
+// @Param layout body chartLayoutRequest true "Layout"
+// @Success 200 {object} models.ChartLayout
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/charts/{symbol}/layout [put]
+func (h *ChartsHandler) SaveChartLayout(c *gin.Context) {
+	var request chartLayoutRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalid(c, "Invalid request body", err)
+		return
+	}
+	if len(request.Layout) == 0 || request.Layout[0] != '{' {
+		respondInvalid(c, "Invalid layout", fmt.Errorf("layout must be a JSON object"))
+		return
+	}
+	if len(request.Layout) > models.MaxChartLayoutSize {
+		respondInvalid(c, "Invalid layout", fmt.Errorf("layout must be at most %d bytes", models.MaxChartLayoutSize))
+		return
+	}
+
+	layout := &models.ChartLayout{
+		User:      chartUser(c),
+		Symbol:    strings.ToUpper(c.Param("symbol")),
+		Layout:    request.Layout,
+		UpdatedAt: time.Now(),
+	}
+	if err := withRequestContext(c, h.db).SaveChartLayout(layout); err != nil {
+		respondError(c, "Failed to save chart layout", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, layout)
+}
+
+// DeleteChartLayout godoc
+// @Summary Delete a user's layout of a symbol's chart
+// @Description Delete the layout preferences a user saved for a symbol's chart, so the dashboard falls back to its defaults
+// @Tags charts
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Param X-Actor header string false "User, defaults to 'default'"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/charts/{symbol}/layout [delete]
+func (h *ChartsHandler) DeleteChartLayout(c *gin.Context) {
+	if err := withRequestContext(c, h.db).DeleteChartLayout(chartUser(c), strings.ToUpper(c.Param("symbol"))); err != nil {
+		respondError(c, "Failed to delete chart layout", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Chart layout deleted"})
+}
+
+// bindChartAnnotation copies a request body onto an annotation, responding with an error if it isn't valid
+func bindChartAnnotation(c *gin.Context, annotation *models.ChartAnnotation) bool {
+	var request chartAnnotationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalid(c, "Invalid request body", err)
+		return false
+	}
+
+	annotation.Type = request.Type
+	annotation.Timeframe = request.Timeframe
+	annotation.Points = request.Points
+	annotation.Price = request.Price
+	annotation.Text = strings.TrimSpace(request.Text)
+	annotation.Color = request.Color
+	if err := annotation.Validate(); err != nil {
+		respondInvalid(c, "Invalid chart annotation", err)
+		return false
+	}
+	return true
+}
+
+// chartAnnotationID reads the :id annotation parameter, responding with an error if it isn't a number
+func chartAnnotationID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondInvalid(c, "Invalid annotation ID", nil)
+		return 0, false
+	}
+	return id, true
+}
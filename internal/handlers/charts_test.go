@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"market-watch-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestChartAnnotations tests that annotations and layouts are saved per user and symbol and restored together
+func TestChartAnnotations(t *testing.T) {
+	db := newTestDB(t)
+
+	h := NewChartsHandler(db)
+	router := gin.New()
+	router.Use(ErrorMiddleware())
+	router.GET("/charts/:symbol", h.GetChart)
+	router.GET("/charts/:symbol/annotations", h.GetChartAnnotations)
+	router.POST("/charts/:symbol/annotations", h.CreateChartAnnotation)
+	router.PUT("/charts/:symbol/annotations/:id", h.UpdateChartAnnotation)
+	router.DELETE("/charts/:symbol/annotations/:id", h.DeleteChartAnnotation)
+	router.PUT("/charts/:symbol/layout", h.SaveChartLayout)
+	router.GET("/charts/:symbol/layout", h.GetChartLayout)
+
+	request := func(method, target, actor, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if actor != "" {
+			req.Header.Set(auditActorHeader, actor)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	line := `{"type":"trend_line","timeframe":"1d","points":[{"time":"2025-03-03T00:00:00Z","price":100},{"time":"2025-03-10T00:00:00Z","price":110}]}`
+	w := request(http.MethodPost, "/charts/aapl/annotations", "alice", line)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	created := decode[models.ChartAnnotation](t, w)
+	if created.ID == 0 || created.User != "alice" || created.Symbol != "AAPL" || len(created.Points) != 2 {
+		t.Errorf("unexpected annotation: %+v", created)
+	}
+
+	request(http.MethodPost, "/charts/AAPL/annotations", "alice", `{"type":"level","price":95.5,"color":"#ff0000"}`)
+	request(http.MethodPost, "/charts/AAPL/annotations", "", `{"type":"level","price":120}`)
+	if w := request(http.MethodPost, "/charts/AAPL/annotations", "alice", `{"type":"trend_line","points":[]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected a trend line without points to be rejected, got %d", w.Code)
+	}
+
+	// Annotations of another timeframe are left out, the ones drawn on every timeframe are kept
+	listed := decode[struct{ Count int }](t, request(http.MethodGet, "/charts/AAPL/annotations?timeframe=1h", "alice", ""))
+	if listed.Count != 1 {
+		t.Errorf("expected only the level on the 1h chart, got %d annotations", listed.Count)
+	}
+
+	// Another user can neither see nor move alice's drawings
+	if w := request(http.MethodPut, "/charts/AAPL/annotations/1", "bob", `{"type":"level","price":90}`); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 updating another user's annotation, got %d", w.Code)
+	}
+	w = request(http.MethodPut, "/charts/AAPL/annotations/1", "alice", `{"type":"note","text":"breakout","points":[{"time":"2025-03-10T00:00:00Z","price":110}]}`)
+	if updated := decode[models.ChartAnnotation](t, w); w.Code != http.StatusOK || updated.Type != models.ChartAnnotationNote || updated.Text != "breakout" {
+		t.Errorf("expected the annotation to become a note, got %d: %+v", w.Code, updated)
+	}
+
+	if w := request(http.MethodGet, "/charts/AAPL/layout", "alice", ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 before a layout is saved, got %d", w.Code)
+	}
+	if w := request(http.MethodPut, "/charts/AAPL/layout", "alice", `{"layout":[1]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected a layout that isn't an object to be rejected, got %d", w.Code)
+	}
+	request(http.MethodPut, "/charts/AAPL/layout", "alice", `{"layout":{"timeframe":"1h"}}`)
+	request(http.MethodPut, "/charts/AAPL/layout", "alice", `{"layout":{"timeframe":"1d","indicators":["vwap"]}}`)
+
+	chart := decode[struct {
+		Annotations []models.ChartAnnotation
+		Layout      *models.ChartLayout
+	}](t, request(http.MethodGet, "/charts/AAPL", "alice", ""))
+	if len(chart.Annotations) != 2 || chart.Layout == nil || string(chart.Layout.Layout) != `{"timeframe":"1d","indicators":["vwap"]}` {
+		t.Errorf("expected alice's 2 annotations and latest layout, got %+v", chart)
+	}
+
+	if w := request(http.MethodDelete, "/charts/AAPL/annotations/1", "alice", ""); w.Code != http.StatusOK {
+		t.Errorf("expected the annotation to be deleted, got %d", w.Code)
+	}
+	defaults := decode[struct{ Annotations []models.ChartAnnotation }](t, request(http.MethodGet, "/charts/AAPL", "", ""))
+	if len(defaults.Annotations) != 1 || defaults.Annotations[0].User != models.DefaultChartUser {
+		t.Errorf("expected the default user's level only, got %+v", defaults.Annotations)
+	}
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// compressionMinSize is the smallest response body worth compressing; shorter bodies are sent as they are
+const compressionMinSize = 1024
+
+// brotliLevel trades some of Brotli's ratio for speed, still beating gzip's default on JSON
+const brotliLevel = 4
+
+// compressibleTypes are the content types that compress well; images, Parquet files and the like don't
+var compressibleTypes = []string{"application/json", "application/javascript", "image/svg+xml", "text/"}
+
+var (
+	gzipWriters   = sync.Pool{New: func() interface{} { return gzip.NewWriter(io.Discard) }}
+	brotliWriters = sync.Pool{New: func() interface{} { return brotli.NewWriterLevel(io.Discard, brotliLevel) }}
+)
+
+// resettableWriter is a pooled compressor
+type resettableWriter interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// CompressionMiddleware compresses responses with Brotli or gzip, whichever the client prefers, with Brotli
+// winning ties. Bodies are held back until compressionMinSize bytes are written, so small responses and
+// bodiless ones such as 304s go out unchanged, while streamed responses are compressed as they are flushed.
+// WebSocket upgrades, range requests and responses that are already encoded are left alone.
+func CompressionMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" || c.GetHeader("Upgrade") != "" || c.GetHeader("Range") != "" {
+			c.Next()
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: c.Writer, encoding: encoding}
+		c.Writer = cw
+		defer func() {
+			c.Writer = cw.ResponseWriter
+			cw.finish()
+		}()
+
+		c.Next()
+	}
+}
+
+// negotiateEncoding picks br or gzip from an Accept-Encoding header by quality, or "" for neither
+func negotiateEncoding(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		if name == "*" {
+			name = "br"
+		}
+		if (name != "br" && name != "gzip") || q <= 0 {
+			continue
+		}
+		if q > bestQ || (q == bestQ && name == "br") {
+			best, bestQ = name, q
+		}
+	}
+	return best
+}
+
+// compressWriter buffers the start of a response to decide whether to compress it, then streams the rest
+// through a pooled compressor
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding   string
+	buf        bytes.Buffer
+	compressor resettableWriter
+	decided    bool // whether the response is being compressed or passed through
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if w.decided {
+		if w.compressor != nil {
+			return w.compressor.Write(data)
+		}
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buf.Write(data)
+	if w.buf.Len() >= compressionMinSize {
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Written reports whether the response has started, counting a body still held back
+func (w *compressWriter) Written() bool {
+	return w.buf.Len() > 0 || w.ResponseWriter.Written()
+}
+
+// Flush sends everything written so far, compressing it when the response is being compressed
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		if err := w.decide(); err != nil {
+			return
+		}
+	}
+	if w.compressor != nil {
+		if err := w.compressor.Flush(); err != nil {
+			return
+		}
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide starts compressing when the response is large enough and of a compressible type and its headers
+// haven't been sent yet, then writes out the buffered start of the body
+func (w *compressWriter) decide() error {
+	w.decided = true
+	header := w.Header()
+
+	if w.buf.Len() >= compressionMinSize && !w.ResponseWriter.Written() && header.Get("Content-Encoding") == "" &&
+		compressible(header.Get("Content-Type")) && w.Status() != http.StatusPartialContent {
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		header.Add("Vary", "Accept-Encoding")
+
+		pool := w.pool()
+		w.compressor = pool.Get().(resettableWriter)
+		w.compressor.Reset(w.ResponseWriter)
+	}
+
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	var err error
+	if w.compressor != nil {
+		_, err = w.compressor.Write(w.buf.Bytes())
+	} else {
+		_, err = w.ResponseWriter.Write(w.buf.Bytes())
+	}
+	w.buf.Reset()
+	return err
+}
+
+// finish writes out a body too small to compress or completes the compressed stream
+func (w *compressWriter) finish() {
+	if !w.decided {
+		w.decide()
+	}
+	if w.compressor != nil {
+		w.compressor.Close()
+		w.compressor.Reset(io.Discard)
+		w.pool().Put(w.compressor)
+		w.compressor = nil
+	}
+}
+
+func (w *compressWriter) pool() *sync.Pool {
+	if w.encoding == "br" {
+		return &brotliWriters
+	}
+	return &gzipWriters
+}
+
+// compressible reports whether a content type is worth compressing
+func compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, prefix := range compressibleTypes {
+		if strings.HasPrefix(mediaType, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// compressedRequest serves one request through the compression middleware
+func compressedRequest(acceptEncoding string, handler gin.HandlerFunc) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Use(CompressionMiddleware(), ErrorMiddleware())
+	router.GET("/data", handler)
+
+	req := httptest.NewRequest("GET", "/data", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// decompressed returns a response body decoded according to its Content-Encoding
+func decompressed(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var r io.Reader = w.Body
+	switch w.Header().Get("Content-Encoding") {
+	case "gzip":
+		gz, err := gzip.NewReader(w.Body)
+		if err != nil {
+			t.Fatalf("invalid gzip body: %v", err)
+		}
+		r = gz
+	case "br":
+		r = brotli.NewReader(w.Body)
+	}
+	body, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("failed to decompress the body: %v", err)
+	}
+	return string(body)
+}
+
+// TestCompressionMiddleware tests that large JSON is compressed with the preferred encoding while small,
+// bodiless and binary responses are sent unchanged
+func TestCompressionMiddleware(t *testing.T) {
+	large := strings.Repeat("market-watch ", 500)
+	jsonHandler := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"text": large}) }
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		handler        gin.HandlerFunc
+		encoding       string
+	}{
+		{"brotli preferred", "gzip, deflate, br", jsonHandler, "br"},
+		{"gzip only", "gzip", jsonHandler, "gzip"},
+		{"gzip weighted higher", "br;q=0.5, gzip", jsonHandler, "gzip"},
+		{"no encoding accepted", "", jsonHandler, ""},
+		{"refused encodings", "br;q=0, gzip;q=0", jsonHandler, ""},
+		{"small body", "br", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) }, ""},
+		{"binary body", "br", func(c *gin.Context) { c.Data(http.StatusOK, "image/png", []byte(large)) }, ""},
+		{"not modified", "br", func(c *gin.Context) { c.AbortWithStatus(http.StatusNotModified) }, ""},
+	}
+	for _, tt := range tests {
+		w := compressedRequest(tt.acceptEncoding, tt.handler)
+		if got := w.Header().Get("Content-Encoding"); got != tt.encoding {
+			t.Errorf("%s: expected encoding %q, got %q", tt.name, tt.encoding, got)
+			continue
+		}
+		if w.Code == http.StatusNotModified {
+			continue
+		}
+		body := decompressed(t, w)
+		if tt.encoding != "" && (w.Body.Len() >= len(body) || !strings.Contains(body, "market-watch")) {
+			t.Errorf("%s: expected a smaller body that decompresses to the JSON, got %d bytes for %d", tt.name, w.Body.Len(), len(body))
+		}
+	}
+}
+
+// TestCompressionMiddlewareErrors tests that an error answered by the error middleware isn't written twice
+func TestCompressionMiddlewareErrors(t *testing.T) {
+	w := compressedRequest("br", func(c *gin.Context) { respondNotFound(c, "No such symbol", errors.New("missing")) })
+	if w.Code != http.StatusNotFound || strings.Count(decompressed(t, w), `"code"`) != 1 {
+		t.Errorf("expected a single not found error, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+// TestJSONStream tests that a streamed object is valid JSON through compression, and that a failure before
+// the first item can still be answered with an error status
+func TestJSONStream(t *testing.T) {
+	w := compressedRequest("gzip", func(c *gin.Context) {
+		stream := newJSONStream(c)
+		stream.Field("symbol", "AAPL")
+		stream.BeginArray("data")
+		for i := 0; i < 2*streamFlushItems+1; i++ {
+			stream.Item(gin.H{"time": i, "close": 100.5})
+		}
+		total, _ := stream.EndArray()
+		stream.Field("total_records", total)
+		stream.Close()
+	})
+
+	var response struct {
+		Symbol       string                   `json:"symbol"`
+		Data         []map[string]interface{} `json:"data"`
+		TotalRecords int                      `json:"total_records"`
+	}
+	if err := json.Unmarshal([]byte(decompressed(t, w)), &response); err != nil {
+		t.Fatalf("invalid streamed JSON: %v", err)
+	}
+	if w.Header().Get("Content-Encoding") != "gzip" || response.Symbol != "AAPL" ||
+		len(response.Data) != 2*streamFlushItems+1 || response.TotalRecords != len(response.Data) {
+		t.Errorf("unexpected streamed response %q: %+v", w.Header().Get("Content-Encoding"), response)
+	}
+
+	w = compressedRequest("", func(c *gin.Context) {
+		stream := newJSONStream(c)
+		stream.Field("symbol", "AAPL")
+		stream.BeginArray("data")
+		if !stream.Started() {
+			respondError(c, "Failed to retrieve price data", errors.New("database locked"))
+		}
+	})
+	if w.Code != http.StatusInternalServerError || strings.Contains(w.Body.String(), "AAPL") {
+		t.Errorf("expected only the error response, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+// TestNegotiateEncoding tests quality values and the wildcard
+func TestNegotiateEncoding(t *testing.T) {
+	tests := map[string]string{
+		"gzip, br":             "br",
+		"gzip;q=1.0, br;q=0.8": "gzip",
+		"*":                    "br",
+		"deflate":              "",
+		"identity, gzip;q=0":   "",
+	}
+	for header, want := range tests {
+		if got := negotiateEncoding(header); got != want {
+			t.Errorf("%q: expected %q, got %q", header, want, got)
+		}
+	}
+}
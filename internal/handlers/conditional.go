@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// historicalMaxAge is how long clients may reuse a response for a range that ended before the current trading
+// date. It is kept short since a late backfill can still fill gaps in past ranges.
+const historicalMaxAge = time.Hour
+
+// versionLookup returns the version of the stored data a response for a symbol is built from
+type versionLookup func(symbol string) (*models.DataVersion, error)
+
+// notModified sets the ETag, Last-Modified and Cache-Control headers of a response built from a symbol's
+// stored data and answers 304 Not Modified when the client's copy is still current. The ETag covers the
+// request URI and the data version, so it changes with the parameters and with every stored bar. Ranges that
+// ended before the current trading date may be cached for historicalMaxAge and revalidated by date; others
+// must be revalidated by ETag, since the newest bar changes while it is collected without a new timestamp.
+// When the version can't be read the response is built as usual.
+func notModified(c *gin.Context, lookup versionLookup, symbol string, historical bool) bool {
+	version, err := lookup(symbol)
+	if err != nil {
+		log.Printf("Failed to get the data version of %s for %s: %v", symbol, c.Request.URL.Path, err)
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%d|%s", c.Request.URL.RequestURI(),
+		version.Latest.UnixNano(), version.Rows, version.Content)))
+	etag := fmt.Sprintf(`W/"%x"`, sum[:12])
+
+	c.Header("ETag", etag)
+	if !version.Latest.IsZero() {
+		c.Header("Last-Modified", version.Latest.UTC().Format(http.TimeFormat))
+	}
+	if historical {
+		c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", int(historicalMaxAge.Seconds())))
+	} else {
+		c.Header("Cache-Control", "no-cache")
+	}
+
+	// If-None-Match takes precedence over If-Modified-Since
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		if !etagMatches(match, etag) {
+			return false
+		}
+	} else {
+		since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+		if !historical || err != nil || version.Latest.IsZero() || version.Latest.Truncate(time.Second).After(since) {
+			return false
+		}
+	}
+
+	c.AbortWithStatus(http.StatusNotModified)
+	return true
+}
+
+// etagMatches reports whether an If-None-Match header lists the ETag, comparing weakly as RFC 9110 requires
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// isHistorical reports whether a range ended before the current trading date, so its bars are settled
+func isHistorical(to time.Time) bool {
+	return database.TradingDate(to) < database.TradingDate(time.Now())
+}
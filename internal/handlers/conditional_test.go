@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestNotModified tests that a matching ETag is answered with 304 until the data changes, and that dates are
+// only trusted for historical ranges
+func TestNotModified(t *testing.T) {
+	version := &models.DataVersion{Latest: time.Date(2025, 3, 4, 15, 0, 0, 0, time.UTC), Content: "100 500", Rows: 10}
+	lookup := func(string) (*models.DataVersion, error) { return version, nil }
+
+	request := func(target string, historical bool, headers map[string]string) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.GET("/chart", func(c *gin.Context) {
+			if notModified(c, lookup, "AAPL", historical) {
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"rows": version.Rows})
+		})
+		req := httptest.NewRequest("GET", target, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := request("/chart?range=1D", false, nil)
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" || first.Header().Get("Cache-Control") != "no-cache" {
+		t.Fatalf("expected a revalidatable 200 with an ETag, got %d %v", first.Code, first.Header())
+	}
+
+	if w := request("/chart?range=1D", false, map[string]string{"If-None-Match": etag}); w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("expected 304 without a body for a current ETag, got %d %q", w.Code, w.Body.String())
+	}
+	if w := request("/chart?range=1W", false, map[string]string{"If-None-Match": etag}); w.Code != http.StatusOK {
+		t.Errorf("expected 200 for other parameters, got %d", w.Code)
+	}
+
+	// The newest bar updated in place changes the ETag
+	version.Content = "101 700"
+	if w := request("/chart?range=1D", false, map[string]string{"If-None-Match": etag}); w.Code != http.StatusOK {
+		t.Errorf("expected 200 once the newest bar changed, got %d", w.Code)
+	}
+
+	since := map[string]string{"If-Modified-Since": version.Latest.Format(http.TimeFormat)}
+	if w := request("/chart?to=2025-03-04", false, since); w.Code != http.StatusOK {
+		t.Errorf("expected If-Modified-Since to be ignored for a live range, got %d", w.Code)
+	}
+	w := request("/chart?to=2025-03-04", true, since)
+	if w.Code != http.StatusNotModified || w.Header().Get("Cache-Control") != "private, max-age=3600" {
+		t.Errorf("expected a cacheable 304 for a historical range, got %d %v", w.Code, w.Header())
+	}
+}
+
+// TestETagMatches tests weak comparison, lists and the wildcard
+func TestETagMatches(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{`W/"abc"`, true},
+		{`"abc"`, true},
+		{`"xyz", W/"abc"`, true},
+		{`*`, true},
+		{`"xyz"`, false},
+	}
+	for _, tt := range tests {
+		if got := etagMatches(tt.header, `W/"abc"`); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.header, tt.want, got)
+		}
+	}
+}
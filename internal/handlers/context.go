@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"context"
+
+	"market-watch-go/internal/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// contextBinder is implemented by *database.DB, whose WithContext ties its queries to a context
+type contextBinder interface {
+	WithContext(ctx context.Context) *database.DB
+}
+
+// withRequestContext binds a store to the request's context, so its queries are cancelled when the request
+// times out or the client disconnects. Stores that can't be bound, such as test mocks, are returned unchanged.
+func withRequestContext[S any](c *gin.Context, store S) S {
+	binder, ok := any(store).(contextBinder)
+	if !ok {
+		return store
+	}
+	if bound, ok := any(binder.WithContext(c.Request.Context())).(S); ok {
+		return bound
+	}
+	return store
+}
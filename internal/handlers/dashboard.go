@@ -1,19 +1,33 @@
 package handlers
 
 import (
+	"cmp"
 	"log"
 	"net/http"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
 
 	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Dashboard overview list sizes
+const (
+	overviewDefaultMovers = 5
+	overviewMaxMovers     = 20
+	overviewRecentAlerts  = 10
+	overviewAnomalyWindow = 24 * time.Hour
+)
+
 type DashboardHandler struct {
 	templatesPath string
 	staticPath    string
 	db            *database.DB
+	collector     CollectorMonitor
 }
 
 // NewDashboardHandler creates a new dashboard handler
@@ -25,10 +39,17 @@ func NewDashboardHandler(templatesPath, staticPath string, db *database.DB) *Das
 	}
 }
 
+// SetCollector sets the collector whose health the overview reports
+func (dh *DashboardHandler) SetCollector(collector CollectorMonitor) {
+	dh.collector = collector
+}
+
 // Index handles GET / - serves the main dashboard
 func (dh *DashboardHandler) Index(c *gin.Context) {
+	db := withRequestContext(c, dh.db)
+
 	// Get current watched symbols from database
-	symbols, err := dh.db.GetWatchedSymbols()
+	symbols, err := db.GetWatchedSymbols()
 	if err != nil {
 		log.Printf("Failed to load watched symbols for dashboard: %v", err)
 		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
@@ -60,3 +81,96 @@ func (dh *DashboardHandler) ServeStatic(c *gin.Context) {
 	// Serve the file
 	c.File(fullPath)
 }
+
+// GetOverview godoc
+// @Summary Dashboard overview
+// @Description Top gainers and losers among watched symbols on the latest trading date, volume leaders, open setups by status, active patterns by phase, recent alerts, market anomalies of the last day and collector health in one response
+// @Tags dashboard
+// @Produce json
+// @Param limit query int false "Symbols per mover list (default 5, max 20)"
+// @Success 200 {object} models.DashboardOverview
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/dashboard/overview [get]
+func (dh *DashboardHandler) GetOverview(c *gin.Context) {
+	db := withRequestContext(c, dh.db)
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(overviewDefaultMovers)))
+	if err != nil || limit < 1 || limit > overviewMaxMovers {
+		respondInvalid(c, "limit must be between 1 and 20", err)
+		return
+	}
+
+	latest, movers, err := db.GetSymbolMovers()
+	if err != nil {
+		respondError(c, "Failed to get symbol movers", err)
+		return
+	}
+	setups, err := db.GetOpenSetupCounts()
+	if err != nil {
+		respondError(c, "Failed to count setups", err)
+		return
+	}
+	patterns, err := db.GetActivePatternPhaseCounts()
+	if err != nil {
+		respondError(c, "Failed to count patterns", err)
+		return
+	}
+	alerts, err := db.GetNotifications(&models.NotificationFilter{Limit: overviewRecentAlerts})
+	if err != nil {
+		respondError(c, "Failed to get recent alerts", err)
+		return
+	}
+	anomalies, err := db.GetMarketAnomalies(&models.AnomalyFilter{Since: time.Now().Add(-overviewAnomalyWindow), Limit: overviewRecentAlerts})
+	if err != nil {
+		respondError(c, "Failed to get recent anomalies", err)
+		return
+	}
+
+	overview := &models.DashboardOverview{
+		WatchedSymbols:  len(movers),
+		TopGainers:      topMovers(movers, limit, func(m *models.SymbolMover) bool { return m.ChangePercent > 0 }, func(a, b *models.SymbolMover) int { return cmp.Compare(b.ChangePercent, a.ChangePercent) }),
+		TopLosers:       topMovers(movers, limit, func(m *models.SymbolMover) bool { return m.ChangePercent < 0 }, func(a, b *models.SymbolMover) int { return cmp.Compare(a.ChangePercent, b.ChangePercent) }),
+		VolumeLeaders:   topMovers(movers, limit, func(m *models.SymbolMover) bool { return m.Volume > 0 }, func(a, b *models.SymbolMover) int { return cmp.Compare(b.Volume, a.Volume) }),
+		SetupsByStatus:  setups,
+		PatternsByPhase: patterns,
+		RecentAlerts:    alerts,
+		RecentAnomalies: anomalies,
+		GeneratedAt:     time.Now(),
+	}
+	if !latest.IsZero() {
+		overview.TradingDate = database.TradingDate(latest)
+	}
+
+	if dh.collector != nil {
+		stats := dh.collector.GetStats()
+		overview.Collector = &models.CollectorHealth{
+			Healthy:        true,
+			IsRunning:      stats.IsRunning,
+			LastRun:        stats.LastRun,
+			NextRun:        stats.NextRun,
+			SuccessfulRuns: stats.SuccessfulRuns,
+			FailedRuns:     stats.FailedRuns,
+			LastError:      stats.LastError,
+			LatestBar:      latest,
+		}
+		if err := dh.collector.HealthCheck(); err != nil {
+			overview.Collector.Healthy = false
+			overview.Collector.Error = err.Error()
+		}
+	}
+
+	c.JSON(http.StatusOK, overview)
+}
+
+// topMovers returns up to limit movers that pass keep, in the order given by compare
+func topMovers(movers []*models.SymbolMover, limit int, keep func(*models.SymbolMover) bool, compare func(a, b *models.SymbolMover) int) []*models.SymbolMover {
+	top := make([]*models.SymbolMover, 0, limit)
+	for _, mover := range movers {
+		if keep(mover) {
+			top = append(top, mover)
+		}
+	}
+	slices.SortStableFunc(top, compare)
+	return top[:min(limit, len(top))]
+}
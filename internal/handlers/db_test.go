@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"path/filepath"
+	"testing"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+)
+
+// newTestDB opens a fresh database in the test's temporary directory, closed when the test ends
+func newTestDB(t *testing.T) *database.Database {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Database.Path = filepath.Join(t.TempDir(), "test.db")
+	db, err := database.New(cfg)
+	if err != nil {
+		t.Fatalf("database.New failed: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
@@ -2,52 +2,51 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 
 	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
 type DebugHandler struct {
-	db *database.DB
+	db        *database.DB
+	collector *services.CollectorService
 }
 
 // NewDebugHandler creates a new debug handler
-func NewDebugHandler(db *database.DB) *DebugHandler {
+func NewDebugHandler(db *database.DB, collector *services.CollectorService) *DebugHandler {
 	return &DebugHandler{
-		db: db,
+		db:        db,
+		collector: collector,
 	}
 }
 
 // GetDataCount handles GET /api/debug/count
 func (dh *DebugHandler) GetDataCount(c *gin.Context) {
+	db := withRequestContext(c, dh.db)
+
 	// Get total data count
-	totalCount, err := dh.db.GetDataCount()
+	totalCount, err := db.GetDataCount()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to get data count",
-			"details": err.Error(),
-		})
+		respondError(c, "Failed to get data count", err)
 		return
 	}
 
 	// Get data count by symbol
-	countBySymbol, err := dh.db.GetDataCountBySymbol()
+	countBySymbol, err := db.GetDataCountBySymbol()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to get data count by symbol",
-			"details": err.Error(),
-		})
+		respondError(c, "Failed to get data count by symbol", err)
 		return
 	}
 
 	// Get all symbols
-	symbols, err := dh.db.GetAllSymbols()
+	symbols, err := db.GetAllSymbols()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to get symbols",
-			"details": err.Error(),
-		})
+		respondError(c, "Failed to get symbols", err)
 		return
 	}
 
@@ -57,3 +56,54 @@ func (dh *DebugHandler) GetDataCount(c *gin.Context) {
 		"count_by_symbol": countBySymbol,
 	})
 }
+
+// GetCoverage godoc
+// @Summary Data coverage per symbol
+// @Description Report how much of each trading day's regular session the stored bars cover, with the missing ranges. Weekends and exchange holidays expect no bars.
+// @Tags debug
+// @Produce json
+// @Param days query int false "Trading history to check in days (default 5, max 60)"
+// @Param symbol query string false "Only check this symbol (default: all watched symbols)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/debug/coverage [get]
+func (dh *DebugHandler) GetCoverage(c *gin.Context) {
+	db := withRequestContext(c, dh.db)
+
+	days := models.DefaultCoverageDays
+	if value := c.Query("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > models.MaxCoverageDays {
+			respondInvalid(c, "Days must be between 1 and "+strconv.Itoa(models.MaxCoverageDays), nil)
+			return
+		}
+		days = parsed
+	}
+
+	symbols := []string{strings.ToUpper(c.Query("symbol"))}
+	if symbols[0] == "" {
+		watched, err := db.GetWatchedSymbols()
+		if err != nil {
+			respondError(c, "Failed to get watched symbols", err)
+			return
+		}
+		symbols = watched
+	}
+
+	coverage := make([]*models.SymbolCoverage, 0, len(symbols))
+	for _, symbol := range symbols {
+		result, err := dh.collector.ScanCoverage(symbol, days)
+		if err != nil {
+			respondError(c, "Failed to scan coverage for "+symbol, err)
+			return
+		}
+		coverage = append(coverage, result)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"days":     days,
+		"coverage": coverage,
+		"count":    len(coverage),
+	})
+}
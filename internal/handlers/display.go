@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"time"
+
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// responseLocation returns the time zone a response is localized to: the request's tz parameter, else the
+// display setting, else UTC. An unknown time zone is answered with 400 and returns false.
+func responseLocation(c *gin.Context, display *services.DisplayService) (*time.Location, bool) {
+	tz := c.Query("tz")
+	if display == nil && tz == "" {
+		return time.UTC, true
+	}
+
+	var loc *time.Location
+	var err error
+	if display != nil {
+		loc, err = display.Location(tz)
+	} else {
+		loc, err = time.LoadLocation(tz)
+	}
+	if err != nil {
+		respondInvalid(c, "Invalid tz parameter, expected an IANA time zone such as America/New_York", nil)
+		return nil, false
+	}
+	return loc, true
+}
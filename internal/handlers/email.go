@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"net/http"
+	"slices"
+	"strings"
 
 	"market-watch-go/internal/services"
 
@@ -51,29 +53,34 @@ func (eh *EmailHandler) GetEmailStatus(c *gin.Context) {
 	}
 }
 
-// SendTestEmail sends a test email to verify configuration
+// SendTestEmail sends a test email to verify configuration. alert_type renders one of the alert templates
+// with example data instead; without email it goes to the recipients of the example's severity.
 func (eh *EmailHandler) SendTestEmail(c *gin.Context) {
 	var request struct {
-		Email string `json:"email" binding:"required,email"`
+		Email     string `json:"email" binding:"omitempty,email"`
+		AlertType string `json:"alert_type"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request format",
-			"details": err.Error(),
-		})
+		respondInvalid(c, "Invalid request format", err)
 		return
 	}
 
 	if !eh.emailService.IsConfigured() {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Email service not configured",
-			"message": "Please update configs/config.yaml with your Gmail credentials",
-		})
+		respondUnavailable(c, "Email service not configured, please update configs/config.yaml with your Gmail credentials", nil)
+		return
+	}
+
+	if request.AlertType == "" {
+		request.AlertType = services.EmailAlertTest
+	}
+	if !slices.Contains(services.EmailAlertTypes, request.AlertType) {
+		respondInvalid(c, "Invalid alert type, alert_type must be one of "+strings.Join(services.EmailAlertTypes, ", "), nil)
 		return
 	}
 
-	if err := eh.emailService.SendTestEmail(request.Email); err != nil {
+	recipients, err := eh.emailService.SendTestAlert(request.AlertType, request.Email)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to send test email",
 			"details": err.Error(),
@@ -92,9 +99,10 @@ func (eh *EmailHandler) SendTestEmail(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message":   "Test email sent successfully",
-		"recipient": request.Email,
-		"status":    "Email notifications are working correctly",
+		"message":    "Test email sent successfully",
+		"alert_type": request.AlertType,
+		"recipients": recipients,
+		"status":     "Email notifications are working correctly",
 	})
 }
 
@@ -109,17 +117,12 @@ func (eh *EmailHandler) SendTradingAlert(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request format",
-			"details": err.Error(),
-		})
+		respondInvalid(c, "Invalid request format", err)
 		return
 	}
 
 	if !eh.emailService.IsConfigured() {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Email service not configured",
-		})
+		respondInvalid(c, "Email service not configured", nil)
 		return
 	}
 
@@ -130,10 +133,7 @@ func (eh *EmailHandler) SendTradingAlert(c *gin.Context) {
 		request.Score,
 		request.Details,
 	); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to send trading alert",
-			"details": err.Error(),
-		})
+		respondError(c, "Failed to send trading alert", err)
 		return
 	}
 
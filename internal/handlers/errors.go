@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Error codes returned in models.ErrorResponse.Code
+const (
+	CodeInvalidRequest      = "invalid_request"
+	CodeNotFound            = "not_found"
+	CodeUpstreamRateLimited = "upstream_rate_limited"
+	CodeUpstreamUnavailable = "upstream_unavailable"
+	CodeUnavailable         = "unavailable"
+	CodeTimeout             = "timeout"
+	CodeRequestCanceled     = "request_canceled"
+	CodeInternal            = "internal_error"
+)
+
+// statusClientClosedRequest is reported when the client went away before the response was written
+const statusClientClosedRequest = 499
+
+// errorKind maps an error sentinel to its response
+type errorKind struct {
+	target  error
+	status  int
+	code    string
+	message string // used when the handler gave no message
+}
+
+// errorKinds are checked in order; an error matching none of them is an internal error
+var errorKinds = []errorKind{
+	{context.DeadlineExceeded, http.StatusGatewayTimeout, CodeTimeout, "The request timed out"},
+	{context.Canceled, statusClientClosedRequest, CodeRequestCanceled, "The request was canceled"},
+	{services.ErrUpstreamRateLimited, http.StatusTooManyRequests, CodeUpstreamRateLimited, "The market data provider is rate limiting requests; try again later"},
+	{services.ErrUpstreamUnavailable, http.StatusBadGateway, CodeUpstreamUnavailable, "The market data provider is unavailable"},
+	{services.ErrUnavailable, http.StatusServiceUnavailable, CodeUnavailable, "The service is unavailable"},
+	{database.ErrNotFound, http.StatusNotFound, CodeNotFound, "Not found"},
+	{services.ErrValidation, http.StatusBadRequest, CodeInvalidRequest, "Invalid request"},
+}
+
+// apiError is an error with a message that is safe to show to API clients. Its kind, when set,
+// decides the response status; otherwise the cause's kind does.
+type apiError struct {
+	kind    error
+	message string
+	cause   error
+}
+
+func (e *apiError) Error() string {
+	switch {
+	case e.cause == nil:
+		return e.message
+	case e.message == "":
+		return e.cause.Error()
+	}
+	return e.message + ": " + e.cause.Error()
+}
+
+func (e *apiError) Unwrap() []error {
+	var errs []error
+	for _, err := range []error{e.kind, e.cause} {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// respondError records err for ErrorMiddleware to answer with, showing message to the client. The
+// status follows the error's kind and is 500 for errors of no known kind.
+func respondError(c *gin.Context, message string, err error) {
+	c.Error(&apiError{message: message, cause: err})
+	c.Abort()
+}
+
+// respondInvalid answers with 400. The cause, usually a parse or validation failure of the client's
+// input, is shown after the message.
+func respondInvalid(c *gin.Context, message string, err error) {
+	c.Error(&apiError{kind: services.ErrValidation, message: message, cause: err})
+	c.Abort()
+}
+
+// respondNotFound answers with 404
+func respondNotFound(c *gin.Context, message string, err error) {
+	c.Error(&apiError{kind: database.ErrNotFound, message: message, cause: err})
+	c.Abort()
+}
+
+// respondUnavailable answers with 503, for features whose service is disabled
+func respondUnavailable(c *gin.Context, message string, err error) {
+	c.Error(&apiError{kind: services.ErrUnavailable, message: message, cause: err})
+	c.Abort()
+}
+
+// respondUpstreamError answers with 502 unless the cause is a rate limit or timeout, for failed
+// calls to the market data provider
+func respondUpstreamError(c *gin.Context, message string, err error) {
+	c.Error(&apiError{kind: services.ErrUpstreamUnavailable, message: message, cause: err})
+	c.Abort()
+}
+
+// classifyError finds the response status and code for an error
+func classifyError(err error) errorKind {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.cause != nil {
+		// The cause's kind is more specific than the one the handler assumed, e.g. a timeout
+		// while fetching data the handler reports as upstream unavailable
+		for _, kind := range errorKinds {
+			if errors.Is(apiErr.cause, kind.target) {
+				return kind
+			}
+		}
+	}
+	for _, kind := range errorKinds {
+		if errors.Is(err, kind.target) {
+			return kind
+		}
+	}
+	return errorKind{status: http.StatusInternalServerError, code: CodeInternal, message: "An internal error occurred"}
+}
+
+// errorResponse builds the sanitized response for an error; causes of server-side failures are only logged
+func errorResponse(err error) (int, models.ErrorResponse) {
+	kind := classifyError(err)
+	message := kind.message
+
+	// Invalid input and missing records are described by errors of our own making, so those causes are
+	// shown after the handler's message
+	showCause := kind.code == CodeInvalidRequest || kind.code == CodeNotFound
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		if apiErr.message != "" {
+			message = apiErr.message
+		}
+		if showCause && apiErr.cause != nil {
+			message = apiErr.Error()
+		}
+	} else if showCause {
+		message = err.Error()
+	}
+
+	title := http.StatusText(kind.status)
+	if kind.status == statusClientClosedRequest {
+		title = "Client Closed Request"
+	}
+	return kind.status, models.ErrorResponse{
+		Error:   title,
+		Code:    kind.code,
+		Message: message,
+	}
+}
+
+// ErrorMiddleware logs the errors handlers attach to the context and, when the handler hasn't written a
+// response, answers with the last one mapped to its status code and a sanitized message
+func ErrorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 {
+			return
+		}
+		for _, ginErr := range c.Errors {
+			log.Printf("[GIN ERROR] %s %s | %v", c.Request.Method, c.Request.URL.Path, ginErr.Err)
+		}
+		if c.Writer.Written() {
+			return
+		}
+
+		status, response := errorResponse(c.Errors.Last().Err)
+		c.JSON(status, response)
+	}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestErrorMiddleware tests that handler errors are mapped to their status, code and a sanitized message
+func TestErrorMiddleware(t *testing.T) {
+	dbErr := errors.New("pq: relation \"stocks\" does not exist")
+
+	tests := []struct {
+		name    string
+		respond func(c *gin.Context)
+		status  int
+		code    string
+		message string
+	}{
+		{"internal", func(c *gin.Context) { respondError(c, "Failed to fetch stocks", dbErr) },
+			http.StatusInternalServerError, CodeInternal, "Failed to fetch stocks"},
+		{"invalid input shows cause", func(c *gin.Context) { respondInvalid(c, "Invalid request body", errors.New("unexpected EOF")) },
+			http.StatusBadRequest, CodeInvalidRequest, "Invalid request body: unexpected EOF"},
+		{"not found", func(c *gin.Context) { respondNotFound(c, "Setup not found", nil) },
+			http.StatusNotFound, CodeNotFound, "Setup not found"},
+		{"kind from cause", func(c *gin.Context) {
+			respondError(c, "Failed to delete alert rule", fmt.Errorf("alert rule %w", database.ErrNotFound))
+		}, http.StatusNotFound, CodeNotFound, "Failed to delete alert rule: alert rule not found"},
+		{"validation from service", func(c *gin.Context) { respondError(c, "Failed to update settings", services.ErrInvalidSettings) },
+			http.StatusBadRequest, CodeInvalidRequest, "Failed to update settings: validation failed: invalid settings"},
+		{"rate limited", func(c *gin.Context) { respondUpstreamError(c, "Failed to fetch EMA", services.ErrBudgetExhausted) },
+			http.StatusTooManyRequests, CodeUpstreamRateLimited, "Failed to fetch EMA"},
+		{"upstream", func(c *gin.Context) { respondUpstreamError(c, "Failed to fetch reference data", dbErr) },
+			http.StatusBadGateway, CodeUpstreamUnavailable, "Failed to fetch reference data"},
+		{"timeout", func(c *gin.Context) { respondError(c, "", fmt.Errorf("failed to query: %w", context.DeadlineExceeded)) },
+			http.StatusGatewayTimeout, CodeTimeout, "The request timed out"},
+		{"unavailable", func(c *gin.Context) { respondUnavailable(c, "Job queue is not available", nil) },
+			http.StatusServiceUnavailable, CodeUnavailable, "Job queue is not available"},
+		{"plain error", func(c *gin.Context) { c.Error(dbErr) },
+			http.StatusInternalServerError, CodeInternal, "An internal error occurred"},
+	}
+	for _, tt := range tests {
+		w := serve("GET", "/", "/", "", tt.respond)
+		if w.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.status, w.Code)
+			continue
+		}
+		response := decode[models.ErrorResponse](t, w)
+		if response.Code != tt.code || response.Message != tt.message {
+			t.Errorf("%s: expected %s %q, got %s %q", tt.name, tt.code, tt.message, response.Code, response.Message)
+		}
+		if response.Error != http.StatusText(tt.status) {
+			t.Errorf("%s: expected error %q, got %q", tt.name, http.StatusText(tt.status), response.Error)
+		}
+		if strings.Contains(w.Body.String(), "relation") {
+			t.Errorf("%s: expected the database error to stay out of the response, got %s", tt.name, w.Body.String())
+		}
+	}
+}
+
+// TestErrorMiddlewareWrittenResponse tests that a response the handler wrote itself is left alone
+func TestErrorMiddlewareWrittenResponse(t *testing.T) {
+	w := serve("GET", "/", "/", "", func(c *gin.Context) {
+		c.Error(errors.New("logged only"))
+		c.JSON(http.StatusOK, gin.H{"status": "partial"})
+	})
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "partial") {
+		t.Errorf("expected the handler's response, got %d: %s", w.Code, w.Body.String())
+	}
+}
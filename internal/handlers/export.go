@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportHandler handles data export and import endpoints
+type ExportHandler struct {
+	exportService *services.ExportService
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(exportService *services.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// Export godoc
+// @Summary Export a dataset
+// @Description Download price or volume bars, support/resistance levels, setups or patterns as CSV or Parquet
+// @Tags export
+// @Produce text/csv,application/octet-stream
+// @Param dataset path string true "Dataset: price, volume, levels, setups or patterns"
+// @Param symbol query string false "Stock symbol (default: all symbols)"
+// @Param from query string false "Start time, RFC 3339 or YYYY-MM-DD (default: all history)"
+// @Param to query string false "End time, RFC 3339 or YYYY-MM-DD (default: now)"
+// @Param format query string false "csv (default) or parquet"
+// @Success 200 {file} file
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/export/{dataset} [get]
+func (h *ExportHandler) Export(c *gin.Context) {
+	req := &models.ExportRequest{
+		Dataset: c.Param("dataset"),
+		Symbol:  strings.ToUpper(c.Query("symbol")),
+	}
+
+	var err error
+	if err = services.ValidateExportDataset(req.Dataset); err == nil {
+		req.Format, err = services.ValidateExportFormat(c.Query("format"))
+	}
+	if err == nil {
+		req.From, req.To, err = services.ParseExportRange(c.Query("from"), c.Query("to"))
+	}
+	if err != nil {
+		respondInvalid(c, "", err)
+		return
+	}
+
+	// Buffered so a failed export is reported as an error rather than a truncated file
+	var buf bytes.Buffer
+	if _, err := h.exportService.Export(&buf, req); err != nil {
+		respondError(c, "Failed to export data", err)
+		return
+	}
+
+	name := req.Dataset
+	if req.Symbol != "" {
+		name = req.Symbol + "_" + name
+	}
+	contentType := "text/csv"
+	if req.Format == models.FormatParquet {
+		contentType = "application/octet-stream"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+"."+req.Format))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// Import godoc
+// @Summary Import a dataset
+// @Description Load a CSV or Parquet file produced by the export endpoint or an external dataset. The file is sent as the request body or as the multipart field "file". Price and volume bars replace bars at the same timestamp; levels, setups and patterns are added as new rows.
+// @Tags export
+// @Accept text/csv,application/octet-stream,multipart/form-data
+// @Produce json
+// @Param dataset path string true "Dataset: price, volume, levels, setups or patterns"
+// @Param format query string false "csv (default) or parquet"
+// @Success 200 {object} models.ImportResult
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/import/{dataset} [post]
+func (h *ExportHandler) Import(c *gin.Context) {
+	dataset := c.Param("dataset")
+
+	body := c.Request.Body
+	if file, err := c.FormFile("file"); err == nil {
+		opened, err := file.Open()
+		if err != nil {
+			respondInvalid(c, "Failed to read uploaded file", err)
+			return
+		}
+		defer opened.Close()
+		body = opened
+	}
+
+	result, err := h.exportService.Import(body, dataset, c.Query("format"))
+	if err != nil {
+		respondInvalid(c, "Failed to import data", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
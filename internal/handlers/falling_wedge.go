@@ -30,24 +30,30 @@ func NewFallingWedgeHandler(db *database.DB, fallingWedgeService *services.Falli
 func (h *FallingWedgeHandler) DetectPattern(c *gin.Context) {
 	symbol := c.Param("symbol")
 
-	pattern, err := h.fallingWedgeService.DetectFallingWedge(symbol)
+	scan, err := parseScanParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to detect falling wedge pattern",
-			"details": err.Error(),
-		})
+		respondInvalid(c, err.Error(), nil)
+		return
+	}
+
+	pattern, err := h.fallingWedgeService.DetectFallingWedge(c.Request.Context(), symbol, scan)
+	if err != nil {
+		respondError(c, "Failed to detect falling wedge pattern", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"symbol":  symbol,
-		"pattern": pattern,
-		"message": "Falling wedge pattern detected successfully",
+		"symbol":      symbol,
+		"scan_params": scan,
+		"pattern":     pattern,
+		"message":     "Falling wedge pattern detected successfully",
 	})
 }
 
 // GetPatterns retrieves falling wedge patterns with optional filtering
 func (h *FallingWedgeHandler) GetPatterns(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	filter := &models.FallingWedgeFilter{}
 
 	// Parse query parameters
@@ -85,12 +91,9 @@ func (h *FallingWedgeHandler) GetPatterns(c *gin.Context) {
 		}
 	}
 
-	patterns, err := h.db.GetFallingWedgePatterns(filter)
+	patterns, err := db.GetFallingWedgePatterns(filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to get falling wedge patterns",
-			"details": err.Error(),
-		})
+		respondError(c, "Failed to get falling wedge patterns", err)
 		return
 	}
 
@@ -103,14 +106,13 @@ func (h *FallingWedgeHandler) GetPatterns(c *gin.Context) {
 
 // GetPatternsBySymbol retrieves falling wedge patterns for a specific symbol
 func (h *FallingWedgeHandler) GetPatternsBySymbol(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	symbol := c.Param("symbol")
 
-	patterns, err := h.db.GetFallingWedgePatternsBySymbol(symbol)
+	patterns, err := db.GetFallingWedgePatternsBySymbol(symbol)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to get falling wedge patterns for symbol",
-			"details": err.Error(),
-		})
+		respondError(c, "Failed to get falling wedge patterns for symbol", err)
 		return
 	}
 
@@ -123,28 +125,23 @@ func (h *FallingWedgeHandler) GetPatternsBySymbol(c *gin.Context) {
 
 // GetPatternDetails retrieves detailed information about a specific falling wedge pattern
 func (h *FallingWedgeHandler) GetPatternDetails(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid pattern ID",
-		})
+		respondInvalid(c, "Invalid pattern ID", nil)
 		return
 	}
 
-	pattern, err := h.db.GetFallingWedgePatternByID(id)
+	pattern, err := db.GetFallingWedgePatternByID(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to get falling wedge pattern",
-			"details": err.Error(),
-		})
+		respondError(c, "Failed to get falling wedge pattern", err)
 		return
 	}
 
 	if pattern == nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Pattern not found",
-		})
+		respondNotFound(c, "Pattern not found", nil)
 		return
 	}
 
@@ -155,12 +152,11 @@ func (h *FallingWedgeHandler) GetPatternDetails(c *gin.Context) {
 
 // GetActivePatterns retrieves all active falling wedge patterns
 func (h *FallingWedgeHandler) GetActivePatterns(c *gin.Context) {
-	patterns, err := h.db.GetActiveFallingWedgePatterns()
+	db := withRequestContext(c, h.db)
+
+	patterns, err := db.GetActiveFallingWedgePatterns()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to get active falling wedge patterns",
-			"details": err.Error(),
-		})
+		respondError(c, "Failed to get active falling wedge patterns", err)
 		return
 	}
 
@@ -172,13 +168,12 @@ func (h *FallingWedgeHandler) GetActivePatterns(c *gin.Context) {
 
 // GetPatternStatistics returns statistics about falling wedge patterns
 func (h *FallingWedgeHandler) GetPatternStatistics(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	// Get all patterns
-	allPatterns, err := h.db.GetFallingWedgePatterns(nil)
+	allPatterns, err := db.GetFallingWedgePatterns(nil)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to get patterns for statistics",
-			"details": err.Error(),
-		})
+		respondError(c, "Failed to get patterns for statistics", err)
 		return
 	}
 
@@ -233,13 +228,18 @@ func (h *FallingWedgeHandler) GetPatternStatistics(c *gin.Context) {
 
 // ScanPatterns scans all watched symbols for falling wedge patterns
 func (h *FallingWedgeHandler) ScanPatterns(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	scan, err := parseScanParams(c)
+	if err != nil {
+		respondInvalid(c, err.Error(), nil)
+		return
+	}
+
 	// Get all watched symbols
-	symbols, err := h.db.GetWatchedSymbols()
+	symbols, err := db.GetWatchedSymbols()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to get watched symbols",
-			"details": err.Error(),
-		})
+		respondError(c, "Failed to get watched symbols", err)
 		return
 	}
 
@@ -260,7 +260,7 @@ func (h *FallingWedgeHandler) ScanPatterns(c *gin.Context) {
 	// Scan each symbol for patterns
 	for _, symbol := range symbols {
 		scannedSymbols++
-		_, err := h.fallingWedgeService.DetectFallingWedge(symbol)
+		_, err := h.fallingWedgeService.DetectFallingWedge(c.Request.Context(), symbol, scan)
 		if err != nil {
 			// Log error but continue scanning other symbols
 			scanErrors = append(scanErrors, fmt.Sprintf("%s: %s", symbol, err.Error()))
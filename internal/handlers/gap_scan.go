@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GapScanHandler handles the pre-market gap report endpoints
+type GapScanHandler struct {
+	gaps *services.GapScanService
+}
+
+// NewGapScanHandler creates a new gap scan handler
+func NewGapScanHandler(gaps *services.GapScanService) *GapScanHandler {
+	return &GapScanHandler{gaps: gaps}
+}
+
+// GetGapReport godoc
+// @Summary Get the morning gap report
+// @Description Get watched symbols ranked by their pre-market gap from the prior regular session close, with pre-market volume and RVOL against prior sessions by the same time. Gappers meet the gap_scan thresholds.
+// @Tags market
+// @Produce json
+// @Param date query string false "Trading date (YYYY-MM-DD, default the latest scan)"
+// @Param sort query string false "Order by gap (default) or rvol"
+// @Success 200 {object} models.GapReport
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/market/gaps [get]
+func (h *GapScanHandler) GetGapReport(c *gin.Context) {
+	date := c.Query("date")
+	if date != "" {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			respondInvalid(c, "Invalid date parameter, expected YYYY-MM-DD", nil)
+			return
+		}
+	}
+	order := c.DefaultQuery("sort", "gap")
+	if order != "gap" && order != "rvol" {
+		respondInvalid(c, "Invalid sort parameter, expected gap or rvol", nil)
+		return
+	}
+
+	report, err := h.gaps.Report(date)
+	if err != nil {
+		respondError(c, "Failed to get gap report", err)
+		return
+	}
+	if order == "rvol" {
+		sort.SliceStable(report.Entries, func(i, j int) bool {
+			if report.Entries[i].RVOL != report.Entries[j].RVOL {
+				return report.Entries[i].RVOL > report.Entries[j].RVOL
+			}
+			return math.Abs(report.Entries[i].GapPercent) > math.Abs(report.Entries[j].GapPercent)
+		})
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ScanGaps godoc
+// @Summary Scan pre-market gaps now
+// @Description Rank today's pre-market gaps of every watched symbol and store the report, replacing the day's earlier scan, as the morning job does before the open. Tagging makes the gappers the only stocks of the gap_scan strategy (Today by default).
+// @Tags market
+// @Produce json
+// @Param tag query bool false "Tag the gappers into the strategy (default gap_scan.auto_tag)"
+// @Success 200 {object} models.GapReport
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/market/gaps/scan [post]
+func (h *GapScanHandler) ScanGaps(c *gin.Context) {
+	tag := h.gaps.AutoTag()
+	if value := c.Query("tag"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			respondInvalid(c, "Invalid tag parameter, expected true or false", nil)
+			return
+		}
+		tag = parsed
+	}
+
+	report, err := h.gaps.Scan(tag)
+	if err != nil {
+		respondError(c, "Failed to scan pre-market gaps", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -41,8 +41,10 @@ func NewHeadShouldersHandler(db *database.DB, hsService *services.HeadShouldersD
 // @Param limit query int false "Maximum number of patterns to return" default(50)
 // @Success 200 {array} models.HeadShouldersPattern
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/head-shoulders/patterns [get]
+// @Router /api/v1/head-shoulders/patterns [get]
 func (h *HeadShouldersHandler) GetAllPatterns(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	// Parse query parameters
 	symbol := c.Query("symbol")
 	patternType := c.Query("pattern_type")
@@ -80,12 +82,9 @@ func (h *HeadShouldersHandler) GetAllPatterns(c *gin.Context) {
 	}
 
 	// Get patterns from database
-	patterns, err := h.db.GetHeadShouldersPatterns(filter)
+	patterns, err := db.GetHeadShouldersPatterns(filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get patterns: " + err.Error(),
-		})
+		respondError(c, "Failed to get patterns", err)
 		return
 	}
 
@@ -109,14 +108,13 @@ func (h *HeadShouldersHandler) GetAllPatterns(c *gin.Context) {
 // @Success 200 {array} models.HeadShouldersPattern
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/head-shoulders/patterns/{symbol} [get]
+// @Router /api/v1/head-shoulders/patterns/{symbol} [get]
 func (h *HeadShouldersHandler) GetPatternsBySymbol(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
 		return
 	}
 
@@ -138,12 +136,9 @@ func (h *HeadShouldersHandler) GetPatternsBySymbol(c *gin.Context) {
 		Limit:      100,
 	}
 
-	patterns, err := h.db.GetHeadShouldersPatterns(filter)
+	patterns, err := db.GetHeadShouldersPatterns(filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get patterns: " + err.Error(),
-		})
+		respondError(c, "Failed to get patterns", err)
 		return
 	}
 
@@ -161,32 +156,25 @@ func (h *HeadShouldersHandler) GetPatternsBySymbol(c *gin.Context) {
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/head-shoulders/patterns/{id}/details [get]
+// @Router /api/v1/head-shoulders/patterns/{id}/details [get]
 func (h *HeadShouldersHandler) GetPatternDetails(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Invalid pattern ID",
-		})
+		respondInvalid(c, "Invalid pattern ID", nil)
 		return
 	}
 
-	pattern, err := h.db.GetHeadShouldersPatternByID(id)
+	pattern, err := db.GetHeadShouldersPatternByID(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get pattern: " + err.Error(),
-		})
+		respondError(c, "Failed to get pattern", err)
 		return
 	}
 
 	if pattern == nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Error:   "Not Found",
-			Message: "Pattern not found",
-		})
+		respondNotFound(c, "Pattern not found", nil)
 		return
 	}
 
@@ -204,32 +192,25 @@ func (h *HeadShouldersHandler) GetPatternDetails(c *gin.Context) {
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/head-shoulders/patterns/{id}/thesis [get]
+// @Router /api/v1/head-shoulders/patterns/{id}/thesis [get]
 func (h *HeadShouldersHandler) GetThesisComponents(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Invalid pattern ID",
-		})
+		respondInvalid(c, "Invalid pattern ID", nil)
 		return
 	}
 
-	pattern, err := h.db.GetHeadShouldersPatternByID(id)
+	pattern, err := db.GetHeadShouldersPatternByID(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get pattern: " + err.Error(),
-		})
+		respondError(c, "Failed to get pattern", err)
 		return
 	}
 
 	if pattern == nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Error:   "Not Found",
-			Message: "Pattern not found",
-		})
+		respondNotFound(c, "Pattern not found", nil)
 		return
 	}
 
@@ -243,28 +224,43 @@ func (h *HeadShouldersHandler) GetThesisComponents(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param symbol path string true "Stock symbol"
+// @Param pattern_type query string false "'inverse_head_shoulders' (default, bullish) or 'head_shoulders' (bearish)"
+// @Param timeframe query string false "Bar size: 1m, 5m, 15m, 1h or 1d (default: configured bar_interval)"
+// @Param lookback_days query int false "Days of bars to scan (default: configured lookback_days)"
+// @Param sensitivity query number false "0.25-4; above 1 finds smaller swings and shallower heads (default: configured sensitivity)"
 // @Success 200 {object} models.HeadShouldersPattern
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/head-shoulders/patterns/{symbol}/detect [post]
+// @Router /api/v1/head-shoulders/patterns/{symbol}/detect [post]
 func (h *HeadShouldersHandler) DetectPattern(c *gin.Context) {
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
 		return
 	}
 
-	pattern, err := h.hsService.DetectInverseHeadShoulders(symbol)
+	scan, err := parseScanParams(c)
+	if err != nil {
+		respondInvalid(c, "", err)
+		return
+	}
+
+	var pattern *models.HeadShouldersPattern
+	switch c.DefaultQuery("pattern_type", models.SetupTypeInverseHeadShoulders) {
+	case models.SetupTypeInverseHeadShoulders:
+		pattern, err = h.hsService.DetectInverseHeadShoulders(c.Request.Context(), symbol, scan)
+	case models.SetupTypeHeadShoulders:
+		pattern, err = h.hsService.DetectHeadShoulders(c.Request.Context(), symbol, scan)
+	default:
+		respondInvalid(c, "pattern_type must be 'inverse_head_shoulders' or 'head_shoulders'", nil)
+		return
+	}
 	if err != nil {
 		// Log the full error for debugging
 		log.Printf("Pattern detection failed for %s: %v", symbol, err)
 
 		// Check if it's a "no pattern found" error vs a system error
-		if err.Error() == "no valid inverse head and shoulders pattern found" ||
-			err.Error() == "insufficient price data for pattern detection" {
+		if isPatternNotFound(err) {
 			c.JSON(http.StatusOK, gin.H{
 				"status":  "no_pattern",
 				"message": err.Error(),
@@ -273,10 +269,7 @@ func (h *HeadShouldersHandler) DetectPattern(c *gin.Context) {
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to detect pattern: " + err.Error(),
-		})
+		respondError(c, "Failed to detect pattern", err)
 		return
 	}
 
@@ -300,24 +293,20 @@ func (h *HeadShouldersHandler) DetectPattern(c *gin.Context) {
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/head-shoulders/patterns/{id}/thesis/{component} [put]
+// @Router /api/v1/head-shoulders/patterns/{id}/thesis/{component} [put]
 func (h *HeadShouldersHandler) UpdateThesisComponent(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Invalid pattern ID",
-		})
+		respondInvalid(c, "Invalid pattern ID", nil)
 		return
 	}
 
 	componentName := c.Param("component")
 	if componentName == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Component name is required",
-		})
+		respondInvalid(c, "Component name is required", nil)
 		return
 	}
 
@@ -329,28 +318,19 @@ func (h *HeadShouldersHandler) UpdateThesisComponent(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Invalid request body: " + err.Error(),
-		})
+		respondInvalid(c, "Invalid request body", err)
 		return
 	}
 
 	// Get the pattern
-	pattern, err := h.db.GetHeadShouldersPatternByID(id)
+	pattern, err := db.GetHeadShouldersPatternByID(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get pattern: " + err.Error(),
-		})
+		respondError(c, "Failed to get pattern", err)
 		return
 	}
 
 	if pattern == nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Error:   "Not Found",
-			Message: "Pattern not found",
-		})
+		respondNotFound(c, "Pattern not found", nil)
 		return
 	}
 
@@ -366,10 +346,7 @@ func (h *HeadShouldersHandler) UpdateThesisComponent(c *gin.Context) {
 	}
 
 	if targetComponent == nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Error:   "Not Found",
-			Message: "Component not found",
-		})
+		respondNotFound(c, "Component not found", nil)
 		return
 	}
 
@@ -391,12 +368,9 @@ func (h *HeadShouldersHandler) UpdateThesisComponent(c *gin.Context) {
 	pattern.LastUpdated = now
 
 	// Save updated pattern
-	err = h.db.UpdateHeadShouldersPattern(pattern)
+	err = db.UpdateHeadShouldersPattern(pattern)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to update pattern: " + err.Error(),
-		})
+		respondError(c, "Failed to update pattern", err)
 		return
 	}
 
@@ -425,24 +399,20 @@ func (h *HeadShouldersHandler) UpdateThesisComponent(c *gin.Context) {
 // @Produce json
 // @Success 200 {object} map[string]interface{}
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/head-shoulders/patterns/monitor [post]
+// @Router /api/v1/head-shoulders/patterns/monitor [post]
 func (h *HeadShouldersHandler) MonitorAllPatterns(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	err := h.hsService.MonitorActivePatterns()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to monitor patterns: " + err.Error(),
-		})
+		respondError(c, "Failed to monitor patterns", err)
 		return
 	}
 
 	// Get updated pattern count
-	patterns, err := h.db.GetActiveHeadShouldersPatterns()
+	patterns, err := db.GetActiveHeadShouldersPatterns()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get pattern count: " + err.Error(),
-		})
+		respondError(c, "Failed to get pattern count", err)
 		return
 	}
 
@@ -466,24 +436,20 @@ func (h *HeadShouldersHandler) MonitorAllPatterns(c *gin.Context) {
 // @Success 200 {array} models.PatternAlert
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/head-shoulders/patterns/{id}/alerts [get]
+// @Router /api/v1/head-shoulders/patterns/{id}/alerts [get]
 func (h *HeadShouldersHandler) GetPatternAlerts(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Invalid pattern ID",
-		})
+		respondInvalid(c, "Invalid pattern ID", nil)
 		return
 	}
 
-	alerts, err := h.db.GetPatternAlerts(id)
+	alerts, err := db.GetPatternAlerts(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get alerts: " + err.Error(),
-		})
+		respondError(c, "Failed to get alerts", err)
 		return
 	}
 
@@ -498,15 +464,14 @@ func (h *HeadShouldersHandler) GetPatternAlerts(c *gin.Context) {
 // @Produce json
 // @Success 200 {object} map[string]interface{}
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/head-shoulders/patterns/stats [get]
+// @Router /api/v1/head-shoulders/patterns/stats [get]
 func (h *HeadShouldersHandler) GetPatternStatistics(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	// Get all patterns for statistics
-	allPatterns, err := h.db.GetHeadShouldersPatterns(&models.PatternFilter{Limit: 10000})
+	allPatterns, err := db.GetHeadShouldersPatterns(&models.PatternFilter{Limit: 10000})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get patterns for statistics: " + err.Error(),
-		})
+		respondError(c, "Failed to get patterns for statistics", err)
 		return
 	}
 
@@ -585,38 +550,31 @@ func (h *HeadShouldersHandler) GetPatternStatistics(c *gin.Context) {
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/head-shoulders/patterns/{id}/performance [get]
+// @Router /api/v1/head-shoulders/patterns/{id}/performance [get]
 func (h *HeadShouldersHandler) GetPatternPerformance(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Invalid pattern ID",
-		})
+		respondInvalid(c, "Invalid pattern ID", nil)
 		return
 	}
 
-	pattern, err := h.db.GetHeadShouldersPatternByID(id)
+	pattern, err := db.GetHeadShouldersPatternByID(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get pattern: " + err.Error(),
-		})
+		respondError(c, "Failed to get pattern", err)
 		return
 	}
 
 	if pattern == nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Error:   "Not Found",
-			Message: "Pattern not found",
-		})
+		respondNotFound(c, "Pattern not found", nil)
 		return
 	}
 
 	// Get current price for performance calculation
 	currentPrice := 0.0
-	latestPrice, err := h.db.GetLatestPriceData(pattern.Symbol)
+	latestPrice, err := db.GetLatestPriceData(pattern.Symbol)
 	if err == nil && latestPrice != nil {
 		currentPrice = latestPrice.Close
 	}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler handles the liveness and readiness probes
+type HealthHandler struct {
+	health *services.HealthService
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(health *services.HealthService) *HealthHandler {
+	return &HealthHandler{
+		health: health,
+	}
+}
+
+// Liveness godoc
+// @Summary Liveness probe
+// @Description Report that the server process is up, without checking any dependency
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.LivenessReport
+// @Router /healthz [get]
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, h.health.Liveness())
+}
+
+// Readiness godoc
+// @Summary Readiness probe
+// @Description Check the database, the market data provider's reachability and request budget, the collector's recent runs and the collection scheduler. Responds 503 while the database or the scheduler fails; a failing provider or collector reports the server as degraded but ready. Provider checks are reused for a minute to spare its quota.
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.ReadinessReport
+// @Failure 503 {object} models.ReadinessReport
+// @Router /readyz [get]
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	report := h.health.Readiness()
+	if !report.Ready {
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InsidersHandler handles the insider transaction endpoints
+type InsidersHandler struct {
+	insiders *services.InsiderService
+}
+
+// NewInsidersHandler creates a new insiders handler
+func NewInsidersHandler(insiders *services.InsiderService) *InsidersHandler {
+	return &InsidersHandler{insiders: insiders}
+}
+
+// GetInsiders godoc
+// @Summary Get a stock's insider transactions
+// @Description Get a stock's SEC Form 4 insider transactions, newest first, with open market purchase and sale totals and whether insiders are cluster buying
+// @Tags insiders
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Param days query int false "Days of transactions (default 90)"
+// @Param refresh query bool false "Fetch new Form 4 filings from EDGAR first"
+// @Success 200 {object} models.InsiderSummary
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/insiders/{symbol} [get]
+func (h *InsidersHandler) GetInsiders(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "90"))
+	if err != nil || days <= 0 {
+		days = 90
+	}
+
+	if c.Query("refresh") == "true" {
+		if _, err := h.insiders.RefreshSymbol(symbol); err != nil {
+			respondError(c, "Failed to fetch insider transactions", err)
+			return
+		}
+	}
+
+	summary, err := h.insiders.Summary(symbol, days)
+	if err != nil {
+		respondError(c, "Failed to get insider transactions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+)
+
+// Handlers depend on these narrow interfaces rather than *database.DB and the concrete services, so tests can
+// substitute in-memory implementations. Market data reaches handlers through services.MarketDataProvider.
+
+// SetupStore is the trading setup storage used by the setup handler
+type SetupStore interface {
+	GetWatchedSymbols() ([]string, error)
+	StoreDetectedSetup(setup *models.TradingSetup) (bool, error)
+	GetTradingSetups(filter *models.SetupFilter) ([]*models.TradingSetup, error)
+	CountTradingSetups(filter *models.SetupFilter) (int, error)
+	GetTradingSetupByID(id int64) (*models.TradingSetup, error)
+	UpdateTradingSetup(setup *models.TradingSetup) error
+	GetSetupChecklist(setupID int64) (*models.SetupChecklist, error)
+	GetSetupSummary(symbol string) (*models.SetupSummary, error)
+	ExpireOldSetups() (int64, error)
+	ArchiveOldSetups(days int) (int64, error)
+	SetTradingSetupArchived(id int64, archived bool) error
+}
+
+// SetupDetector finds trading setups for a symbol and sends alerts for the stored ones
+type SetupDetector interface {
+	DetectSetups(ctx context.Context, symbol string) (*models.SetupDetectionResult, error)
+	NotifySetups(setups []*models.TradingSetup)
+}
+
+// CollectorMonitor reports the data collector's run statistics and health
+type CollectorMonitor interface {
+	GetStats() *services.CollectionStats
+	HealthCheck() error
+}
+
+// RiskManager sizes setups and reports the open risk across them
+type RiskManager interface {
+	PortfolioHeat() (*models.PortfolioHeat, error)
+	ApplySizing(setups []*models.TradingSetup, heat *models.PortfolioHeat)
+}
+
+// PatternStore is the chart pattern storage used by the patterns handler
+type PatternStore interface {
+	GetWatchedSymbols() ([]string, error)
+	GetHeadShouldersPatterns(filter *models.PatternFilter) ([]*models.HeadShouldersPattern, error)
+	CountHeadShouldersPatterns(filter *models.PatternFilter) (int, error)
+	GetFallingWedgePatterns(filter *models.FallingWedgeFilter) ([]*models.FallingWedgePattern, error)
+	GetFallingWedgePatternsBySymbol(symbol string) ([]*models.FallingWedgePattern, error)
+	CountFallingWedgePatterns(filter *models.FallingWedgeFilter) (int, error)
+	GetTrianglePatterns(filter *models.TriangleFilter) ([]*models.TrianglePattern, error)
+	GetTrianglePatternsBySymbol(symbol string) ([]*models.TrianglePattern, error)
+	CountTrianglePatterns(filter *models.TriangleFilter) (int, error)
+	GetFlagPatterns(filter *models.FlagFilter) ([]*models.FlagPattern, error)
+	GetFlagPatternsBySymbol(symbol string) ([]*models.FlagPattern, error)
+	CountFlagPatterns(filter *models.FlagFilter) (int, error)
+	GetPatternLinks(symbol string) ([]*models.PatternLink, error)
+	ArchiveOldPatterns(days int) (int64, error)
+	SetPatternArchived(family string, id int64, archived bool) error
+}
+
+// PatternScheduler runs the periodic pattern scans and active pattern monitoring
+type PatternScheduler interface {
+	MonitorActivePatterns() error
+	GetSchedulerStatus() *services.PatternSchedulerStatus
+}
+
+// HeadShouldersDetector detects head and shoulders and inverse head and shoulders patterns
+type HeadShouldersDetector interface {
+	DetectHeadShoulders(ctx context.Context, symbol string, params *models.PatternScanParams) (*models.HeadShouldersPattern, error)
+	DetectInverseHeadShoulders(ctx context.Context, symbol string, params *models.PatternScanParams) (*models.HeadShouldersPattern, error)
+}
+
+// HeadShouldersEditor corrects the key points of stored head and shoulders patterns and keeps their edit history
+type HeadShouldersEditor interface {
+	EditKeyPoints(id int64, req *models.HeadShouldersEditRequest) (*models.HeadShouldersPattern, *models.PatternEdit, error)
+	GetEdits(id int64) ([]*models.PatternEdit, error)
+}
+
+// WedgeDetector detects falling and rising wedge patterns
+type WedgeDetector interface {
+	DetectFallingWedge(ctx context.Context, symbol string, params *models.PatternScanParams) (*models.FallingWedgePattern, error)
+	DetectRisingWedge(ctx context.Context, symbol string, params *models.PatternScanParams) (*models.FallingWedgePattern, error)
+}
+
+// TriangleDetector detects triangle patterns
+type TriangleDetector interface {
+	DetectTriangle(ctx context.Context, symbol string, timeframe models.Timeframe) (*models.TrianglePattern, error)
+}
+
+// FlagDetector detects bull and bear flag patterns
+type FlagDetector interface {
+	DetectFlag(ctx context.Context, symbol string, timeframe models.Timeframe) (*models.FlagPattern, error)
+}
+
+// JobQueue runs a task for each item of a background job
+type JobQueue interface {
+	Submit(jobType string, items []string, params map[string]interface{}, task services.JobTask) (*models.Job, error)
+}
+
+// ChartRenderer renders stored patterns as chart images
+type ChartRenderer interface {
+	PatternChart(family string, id int64) ([]byte, error)
+}
+
+// PatternTimelines assembles the thesis timeline of stored patterns
+type PatternTimelines interface {
+	Timeline(family string, id int64) (*models.PatternTimeline, error)
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JobsHandler handles background job API endpoints
+type JobsHandler struct {
+	db               *database.Database
+	jobService       *services.JobService
+	collectorService *services.CollectorService
+	taService        *services.TechnicalAnalysisService
+}
+
+// NewJobsHandler creates a new jobs handler
+func NewJobsHandler(db *database.Database, jobService *services.JobService, collectorService *services.CollectorService, taService *services.TechnicalAnalysisService) *JobsHandler {
+	return &JobsHandler{
+		db:               db,
+		jobService:       jobService,
+		collectorService: collectorService,
+		taService:        taService,
+	}
+}
+
+// backfillRequest is the request body for queuing a historical backfill
+type backfillRequest struct {
+	Days    int      `json:"days"`
+	Symbols []string `json:"symbols"`
+}
+
+// indicatorsRequest is the request body for queuing an indicator recomputation
+type indicatorsRequest struct {
+	Symbols []string `json:"symbols"`
+}
+
+// GetJobs godoc
+// @Summary List jobs
+// @Description List recent background jobs, newest first, without per-item results
+// @Tags jobs
+// @Produce json
+// @Param type query string false "Job type"
+// @Param status query string false "Job status"
+// @Param limit query int false "Maximum number of jobs" default(50)
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/jobs [get]
+func (h *JobsHandler) GetJobs(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+
+	jobs := h.jobService.List(&models.JobFilter{
+		Type:   c.Query("type"),
+		Status: c.Query("status"),
+		Limit:  limit,
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"jobs":  jobs,
+		"count": len(jobs),
+	})
+}
+
+// GetJob godoc
+// @Summary Get a job
+// @Description Get a job's progress and per-item results
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} models.Job
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/jobs/{id} [get]
+func (h *JobsHandler) GetJob(c *gin.Context) {
+	job, ok := h.jobService.Get(c.Param("id"))
+	if !ok {
+		respondNotFound(c, "Job not found", nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// CancelJob godoc
+// @Summary Cancel a job
+// @Description Stop dispatching a job's remaining items; items already running finish
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} models.Job
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/jobs/{id}/cancel [post]
+func (h *JobsHandler) CancelJob(c *gin.Context) {
+	job, err := h.jobService.Cancel(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, "", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// QueueBackfill godoc
+// @Summary Queue a historical backfill
+// @Description Backfill volume and price history for the given symbols, or all watched symbols and the market benchmarks
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Param request body backfillRequest false "Days and symbols"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/jobs/backfill [post]
+func (h *JobsHandler) QueueBackfill(c *gin.Context) {
+	var request backfillRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			respondInvalid(c, "Invalid request body", err)
+			return
+		}
+	}
+	if request.Days <= 0 {
+		request.Days = 30
+	}
+	if request.Days > 365 {
+		respondInvalid(c, "Days must be between 1 and 365", nil)
+		return
+	}
+
+	symbols, ok := h.resolveSymbols(c, request.Symbols, h.collectorService.CollectionSymbols)
+	if !ok {
+		return
+	}
+
+	params := map[string]interface{}{"days": request.Days}
+	h.submit(c, models.JobTypeBackfill, symbols, params, h.collectorService.BackfillTask(request.Days))
+}
+
+// QueueIndicatorRecompute godoc
+// @Summary Queue an indicator recomputation
+// @Description Recompute technical indicators for the given symbols, or all watched symbols
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Param request body indicatorsRequest false "Symbols"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/jobs/indicators [post]
+func (h *JobsHandler) QueueIndicatorRecompute(c *gin.Context) {
+	var request indicatorsRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			respondInvalid(c, "Invalid request body", err)
+			return
+		}
+	}
+
+	symbols, ok := h.resolveSymbols(c, request.Symbols, withRequestContext(c, h.db).GetWatchedSymbols)
+	if !ok {
+		return
+	}
+
+	h.submit(c, models.JobTypeIndicatorRecompute, symbols, nil, func(ctx context.Context, symbol string) (interface{}, error) {
+		return nil, h.taService.UpdateIndicatorsForSymbol(ctx, symbol)
+	})
+}
+
+// resolveSymbols normalizes the requested symbols, defaulting to the symbols all returns
+func (h *JobsHandler) resolveSymbols(c *gin.Context, requested []string, all func() ([]string, error)) ([]string, bool) {
+	symbols := make([]string, 0, len(requested))
+	for _, symbol := range requested {
+		if symbol = strings.ToUpper(strings.TrimSpace(symbol)); symbol != "" {
+			symbols = append(symbols, symbol)
+		}
+	}
+	if len(symbols) > 0 {
+		return symbols, true
+	}
+
+	watched, err := all()
+	if err != nil {
+		respondError(c, "Failed to get watched symbols", err)
+		return nil, false
+	}
+	return watched, true
+}
+
+// submit queues a job and writes the accepted response
+func (h *JobsHandler) submit(c *gin.Context, jobType string, symbols []string, params map[string]interface{}, task services.JobTask) {
+	job, err := h.jobService.Submit(jobType, symbols, params, task)
+	if err != nil {
+		respondUnavailable(c, "Failed to queue job", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"job_id":     job.ID,
+		"status_url": "/api/v1/jobs/" + job.ID,
+		"job":        job,
+	})
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamFlushItems is how many array items are written between flushes of a streamed response
+const streamFlushItems = 500
+
+// jsonStream writes a JSON object to the response a piece at a time, so a large array is encoded and sent
+// item by item instead of being marshalled whole in memory. Nothing is sent until the first array item or
+// Close, so a handler can still answer with an error while Started is false; after that a failure can only
+// be reported by cutting the body short. The first write error is kept and later calls do nothing.
+type jsonStream struct {
+	c       *gin.Context
+	pending bytes.Buffer
+	w       io.Writer
+	enc     *json.Encoder
+	fields  int
+	items   int
+	err     error
+}
+
+// newJSONStream opens a JSON object for the response
+func newJSONStream(c *gin.Context) *jsonStream {
+	s := &jsonStream{c: c}
+	s.w = &s.pending
+	s.enc = json.NewEncoder(s.w)
+	s.write("{")
+	return s
+}
+
+// Started reports whether the status and the start of the body have been sent
+func (s *jsonStream) Started() bool {
+	return s.w != &s.pending
+}
+
+// start sends a 200 status and whatever has been written so far, then writes straight to the response
+func (s *jsonStream) start() {
+	if s.Started() {
+		return
+	}
+	s.c.Header("Content-Type", "application/json; charset=utf-8")
+	s.c.Status(http.StatusOK)
+	s.w = s.c.Writer
+	s.enc = json.NewEncoder(s.w)
+	if s.err == nil {
+		_, s.err = s.w.Write(s.pending.Bytes())
+	}
+	s.pending.Reset()
+}
+
+func (s *jsonStream) write(text string) {
+	if s.err == nil {
+		_, s.err = io.WriteString(s.w, text)
+	}
+}
+
+func (s *jsonStream) encode(value interface{}) {
+	if s.err == nil {
+		s.err = s.enc.Encode(value)
+	}
+}
+
+// key starts the next field of the object
+func (s *jsonStream) key(name string) {
+	if s.fields > 0 {
+		s.write(",")
+	}
+	s.fields++
+	s.write(strconv.Quote(name) + ":")
+}
+
+// Field writes a field with an encoded value
+func (s *jsonStream) Field(name string, value interface{}) error {
+	s.key(name)
+	s.encode(value)
+	return s.err
+}
+
+// BeginArray starts an array field, to be filled with Item and closed with EndArray
+func (s *jsonStream) BeginArray(name string) error {
+	s.key(name)
+	s.write("[")
+	s.items = 0
+	return s.err
+}
+
+// Item writes the next item of the open array, flushing the response every streamFlushItems items
+func (s *jsonStream) Item(value interface{}) error {
+	s.start()
+	if s.items > 0 {
+		s.write(",")
+	}
+	s.encode(value)
+	s.items++
+	if s.err == nil && s.items%streamFlushItems == 0 {
+		s.c.Writer.Flush()
+	}
+	return s.err
+}
+
+// EndArray closes the open array and returns how many items it held
+func (s *jsonStream) EndArray() (int, error) {
+	s.write("]")
+	return s.items, s.err
+}
+
+// Close closes the object and sends the rest of the response
+func (s *jsonStream) Close() error {
+	s.start()
+	s.write("}")
+	return s.err
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MarketHandler handles exchange session, trading calendar and market context API endpoints
+type MarketHandler struct {
+	calendar      *services.MarketCalendar
+	marketContext *services.MarketContextService
+	regimes       *services.MarketRegimeService
+}
+
+// NewMarketHandler creates a new market handler
+func NewMarketHandler(calendar *services.MarketCalendar, marketContext *services.MarketContextService, regimes *services.MarketRegimeService) *MarketHandler {
+	return &MarketHandler{
+		calendar:      calendar,
+		marketContext: marketContext,
+		regimes:       regimes,
+	}
+}
+
+// GetStatus godoc
+// @Summary Get market status
+// @Description Get the exchange session (pre-market, regular, post-market or closed), holiday and early close information, and whether the collector is running
+// @Tags market
+// @Produce json
+// @Param at query string false "Time to evaluate instead of now (RFC3339)"
+// @Success 200 {object} models.MarketStatus
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/market/status [get]
+func (h *MarketHandler) GetStatus(c *gin.Context) {
+	at := time.Now()
+	if value := c.Query("at"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			respondInvalid(c, "Invalid at parameter, expected RFC3339", nil)
+			return
+		}
+		at = parsed
+	}
+
+	c.JSON(http.StatusOK, h.calendar.Status(at))
+}
+
+// GetHolidays godoc
+// @Summary List exchange holidays
+// @Description Get the full-day closures for a year
+// @Tags market
+// @Produce json
+// @Param year query int false "Year (default current year)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/market/holidays [get]
+func (h *MarketHandler) GetHolidays(c *gin.Context) {
+	year := time.Now().In(h.calendar.Location()).Year()
+	if value := c.Query("year"); value != "" {
+		parsed, err := time.Parse("2006", value)
+		if err != nil {
+			respondInvalid(c, "Invalid year parameter", nil)
+			return
+		}
+		year = parsed.Year()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"year":     year,
+		"holidays": h.calendar.Holidays(year),
+	})
+}
+
+// GetContext godoc
+// @Summary Get the market context
+// @Description Get each benchmark's daily close against its 20 and 50 day SMAs, the share of watched symbols above their SMAs, the advance/decline of watched symbols on the latest session, and the market regime setups are scored against
+// @Tags market
+// @Produce json
+// @Param refresh query bool false "Recompute instead of reusing the context from the last few minutes"
+// @Success 200 {object} models.MarketContext
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/market/context [get]
+func (h *MarketHandler) GetContext(c *gin.Context) {
+	mc, err := h.marketContext.Context(c.Query("refresh") == "true")
+	if err != nil {
+		respondError(c, "Failed to compute market context", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, mc)
+}
+
+// GetRegime godoc
+// @Summary Get the market regime
+// @Description Classify the regime index's latest trading day as trending_up, trending_down, choppy or high_volatility from its 50-day SMA, 14-day ADX and 14-day over 50-day ATR. Setups the regime rules out are not detected.
+// @Tags market
+// @Produce json
+// @Param refresh query bool false "Classify again instead of reusing the label from the last few minutes"
+// @Success 200 {object} models.MarketRegimeLabel
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/market/regime [get]
+func (h *MarketHandler) GetRegime(c *gin.Context) {
+	label, err := h.regimes.Current(c.Query("refresh") == "true")
+	if err != nil {
+		respondError(c, "Failed to classify market regime", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, label)
+}
+
+// GetRegimeHistory godoc
+// @Summary List daily market regime labels
+// @Description Get the stored daily regime labels of the regime index, newest first
+// @Tags market
+// @Produce json
+// @Param days query int false "Calendar days of history (default 90, max 365)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/market/regime/history [get]
+func (h *MarketHandler) GetRegimeHistory(c *gin.Context) {
+	days := 90
+	if value := c.Query("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 365 {
+			respondInvalid(c, "Invalid days parameter, expected 1 to 365", nil)
+			return
+		}
+		days = parsed
+	}
+
+	labels, err := h.regimes.History(days)
+	if err != nil {
+		respondError(c, "Failed to get market regimes", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"days":    days,
+		"regimes": labels,
+	})
+}
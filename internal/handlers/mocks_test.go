@@ -0,0 +1,358 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+)
+
+// mockSetupStore keeps trading setups in memory
+type mockSetupStore struct {
+	watched    []string
+	setups     []*models.TradingSetup
+	checklists map[int64]*models.SetupChecklist
+	insertErr  error
+	nextID     int64
+}
+
+func newMockSetupStore(setups ...*models.TradingSetup) *mockSetupStore {
+	store := &mockSetupStore{checklists: map[int64]*models.SetupChecklist{}}
+	for _, setup := range setups {
+		store.InsertTradingSetupWithChecklist(setup)
+	}
+	return store
+}
+
+func (m *mockSetupStore) GetWatchedSymbols() ([]string, error) { return m.watched, nil }
+
+func (m *mockSetupStore) InsertTradingSetupWithChecklist(setup *models.TradingSetup) error {
+	if m.insertErr != nil {
+		return m.insertErr
+	}
+	m.nextID++
+	setup.ID = m.nextID
+	m.setups = append(m.setups, setup)
+	if setup.Checklist != nil {
+		setup.Checklist.SetupID = setup.ID
+		m.checklists[setup.ID] = setup.Checklist
+	}
+	return nil
+}
+
+func (m *mockSetupStore) StoreDetectedSetup(setup *models.TradingSetup) (bool, error) {
+	for _, existing := range m.setups {
+		if setup.KeyLevelID > 0 && existing.KeyLevelID == setup.KeyLevelID && existing.Symbol == setup.Symbol &&
+			existing.SetupType == setup.SetupType && existing.Status == "active" && !existing.Archived {
+			setup.ID = existing.ID
+			*existing = *setup
+			return true, nil
+		}
+	}
+	return false, m.InsertTradingSetupWithChecklist(setup)
+}
+
+func (m *mockSetupStore) matching(filter *models.SetupFilter) []*models.TradingSetup {
+	var matched []*models.TradingSetup
+	for _, setup := range m.setups {
+		if filter.Symbol != "" && setup.Symbol != filter.Symbol {
+			continue
+		}
+		if filter.Status != "" && setup.Status != filter.Status {
+			continue
+		}
+		if setup.QualityScore < filter.MinQualityScore {
+			continue
+		}
+		if setup.Archived && !filter.IncludeArchived {
+			continue
+		}
+		matched = append(matched, setup)
+	}
+	return matched
+}
+
+func (m *mockSetupStore) GetTradingSetups(filter *models.SetupFilter) ([]*models.TradingSetup, error) {
+	matched := m.matching(filter)
+	if filter.Offset >= len(matched) {
+		return []*models.TradingSetup{}, nil
+	}
+	matched = matched[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(matched) {
+		matched = matched[:filter.Limit]
+	}
+	return matched, nil
+}
+
+func (m *mockSetupStore) CountTradingSetups(filter *models.SetupFilter) (int, error) {
+	return len(m.matching(filter)), nil
+}
+
+func (m *mockSetupStore) GetTradingSetupByID(id int64) (*models.TradingSetup, error) {
+	for _, setup := range m.setups {
+		if setup.ID == id {
+			return setup, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockSetupStore) UpdateTradingSetup(setup *models.TradingSetup) error {
+	for i, stored := range m.setups {
+		if stored.ID == setup.ID {
+			m.setups[i] = setup
+			return nil
+		}
+	}
+	return errors.New("setup not found")
+}
+
+func (m *mockSetupStore) GetSetupChecklist(setupID int64) (*models.SetupChecklist, error) {
+	return m.checklists[setupID], nil
+}
+
+func (m *mockSetupStore) GetSetupSummary(symbol string) (*models.SetupSummary, error) {
+	return &models.SetupSummary{TotalSetups: len(m.matching(&models.SetupFilter{Symbol: symbol}))}, nil
+}
+
+func (m *mockSetupStore) ExpireOldSetups() (int64, error) { return 0, nil }
+
+func (m *mockSetupStore) ArchiveOldSetups(days int) (int64, error) { return 0, nil }
+
+func (m *mockSetupStore) SetTradingSetupArchived(id int64, archived bool) error {
+	for _, setup := range m.setups {
+		if setup.ID == id {
+			setup.Archived = archived
+			return nil
+		}
+	}
+	return fmt.Errorf("trading setup %w", database.ErrNotFound)
+}
+
+// mockSetupDetector returns a fixed detection result and records the setups it is asked to notify about
+type mockSetupDetector struct {
+	result   *models.SetupDetectionResult
+	err      error
+	notified []*models.TradingSetup
+}
+
+func (m *mockSetupDetector) DetectSetups(ctx context.Context, symbol string) (*models.SetupDetectionResult, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.result, nil
+}
+
+func (m *mockSetupDetector) NotifySetups(setups []*models.TradingSetup) {
+	m.notified = append(m.notified, setups...)
+}
+
+// mockPatternStore keeps patterns of every family in memory
+type mockPatternStore struct {
+	watched   []string
+	hs        []*models.HeadShouldersPattern
+	wedges    []*models.FallingWedgePattern
+	triangles []*models.TrianglePattern
+	flags     []*models.FlagPattern
+	links     []*models.PatternLink
+	err       error
+}
+
+// bySymbol keeps the items whose symbol matches, or all items for an empty symbol, up to the limit
+func bySymbol[T any](items []T, symbolOf func(T) string, symbol string, limit int) []T {
+	matched := make([]T, 0, len(items))
+	for _, item := range items {
+		if symbol == "" || symbolOf(item) == symbol {
+			matched = append(matched, item)
+		}
+	}
+	if limit > 0 && limit < len(matched) {
+		matched = matched[:limit]
+	}
+	return matched
+}
+
+func (m *mockPatternStore) GetWatchedSymbols() ([]string, error) { return m.watched, m.err }
+
+func (m *mockPatternStore) GetHeadShouldersPatterns(filter *models.PatternFilter) ([]*models.HeadShouldersPattern, error) {
+	return bySymbol(m.hs, func(p *models.HeadShouldersPattern) string { return p.Symbol }, filter.Symbol, filter.Limit), m.err
+}
+
+func (m *mockPatternStore) CountHeadShouldersPatterns(filter *models.PatternFilter) (int, error) {
+	return len(bySymbol(m.hs, func(p *models.HeadShouldersPattern) string { return p.Symbol }, filter.Symbol, 0)), m.err
+}
+
+func (m *mockPatternStore) GetFallingWedgePatterns(filter *models.FallingWedgeFilter) ([]*models.FallingWedgePattern, error) {
+	return bySymbol(m.wedges, func(p *models.FallingWedgePattern) string { return p.Symbol }, filter.Symbol, filter.Limit), m.err
+}
+
+func (m *mockPatternStore) GetFallingWedgePatternsBySymbol(symbol string) ([]*models.FallingWedgePattern, error) {
+	return m.GetFallingWedgePatterns(&models.FallingWedgeFilter{Symbol: symbol})
+}
+
+func (m *mockPatternStore) CountFallingWedgePatterns(filter *models.FallingWedgeFilter) (int, error) {
+	return len(bySymbol(m.wedges, func(p *models.FallingWedgePattern) string { return p.Symbol }, filter.Symbol, 0)), m.err
+}
+
+func (m *mockPatternStore) GetTrianglePatterns(filter *models.TriangleFilter) ([]*models.TrianglePattern, error) {
+	return bySymbol(m.triangles, func(p *models.TrianglePattern) string { return p.Symbol }, filter.Symbol, filter.Limit), m.err
+}
+
+func (m *mockPatternStore) GetTrianglePatternsBySymbol(symbol string) ([]*models.TrianglePattern, error) {
+	return m.GetTrianglePatterns(&models.TriangleFilter{Symbol: symbol})
+}
+
+func (m *mockPatternStore) CountTrianglePatterns(filter *models.TriangleFilter) (int, error) {
+	return len(bySymbol(m.triangles, func(p *models.TrianglePattern) string { return p.Symbol }, filter.Symbol, 0)), m.err
+}
+
+func (m *mockPatternStore) GetFlagPatterns(filter *models.FlagFilter) ([]*models.FlagPattern, error) {
+	return bySymbol(m.flags, func(p *models.FlagPattern) string { return p.Symbol }, filter.Symbol, filter.Limit), m.err
+}
+
+func (m *mockPatternStore) GetFlagPatternsBySymbol(symbol string) ([]*models.FlagPattern, error) {
+	return m.GetFlagPatterns(&models.FlagFilter{Symbol: symbol})
+}
+
+func (m *mockPatternStore) CountFlagPatterns(filter *models.FlagFilter) (int, error) {
+	return len(bySymbol(m.flags, func(p *models.FlagPattern) string { return p.Symbol }, filter.Symbol, 0)), m.err
+}
+
+func (m *mockPatternStore) GetPatternLinks(symbol string) ([]*models.PatternLink, error) {
+	return bySymbol(m.links, func(l *models.PatternLink) string { return l.Symbol }, symbol, 0), m.err
+}
+
+func (m *mockPatternStore) ArchiveOldPatterns(days int) (int64, error) { return 0, m.err }
+
+func (m *mockPatternStore) SetPatternArchived(family string, id int64, archived bool) error {
+	return m.err
+}
+
+// errNoPattern is how the detectors report that no pattern is present
+var errNoPattern = fmt.Errorf("%w: no formation", services.ErrNoPattern)
+
+// mockDetectors implements every pattern detector, returning the configured pattern or errNoPattern, or a
+// failure for the detectors named in failing
+type mockDetectors struct {
+	inverseHS *models.HeadShouldersPattern
+	hs        *models.HeadShouldersPattern
+	falling   *models.FallingWedgePattern
+	rising    *models.FallingWedgePattern
+	triangle  *models.TrianglePattern
+	flag      *models.FlagPattern
+	failing   string
+}
+
+// detect returns the pattern when present, the detector's failure when it is listed, or errNoPattern
+func detect[T any](m *mockDetectors, name string, pattern *T) (*T, error) {
+	if strings.Contains(m.failing, name) {
+		return nil, errors.New(name + " detector failed")
+	}
+	if pattern == nil {
+		return nil, errNoPattern
+	}
+	return pattern, nil
+}
+
+func (m *mockDetectors) DetectInverseHeadShoulders(ctx context.Context, symbol string, params *models.PatternScanParams) (*models.HeadShouldersPattern, error) {
+	return detect(m, "inverse_hs", m.inverseHS)
+}
+
+func (m *mockDetectors) DetectHeadShoulders(ctx context.Context, symbol string, params *models.PatternScanParams) (*models.HeadShouldersPattern, error) {
+	return detect(m, "hs_top", m.hs)
+}
+
+func (m *mockDetectors) DetectFallingWedge(ctx context.Context, symbol string, params *models.PatternScanParams) (*models.FallingWedgePattern, error) {
+	return detect(m, "falling_wedge", m.falling)
+}
+
+func (m *mockDetectors) DetectRisingWedge(ctx context.Context, symbol string, params *models.PatternScanParams) (*models.FallingWedgePattern, error) {
+	return detect(m, "rising_wedge", m.rising)
+}
+
+func (m *mockDetectors) DetectTriangle(ctx context.Context, symbol string, timeframe models.Timeframe) (*models.TrianglePattern, error) {
+	return detect(m, "triangle", m.triangle)
+}
+
+func (m *mockDetectors) DetectFlag(ctx context.Context, symbol string, timeframe models.Timeframe) (*models.FlagPattern, error) {
+	return detect(m, "flag", m.flag)
+}
+
+// mockScheduler reports a fixed scheduler status and counts monitoring runs
+type mockScheduler struct {
+	status      services.PatternSchedulerStatus
+	monitorErr  error
+	monitorRuns int
+}
+
+func (m *mockScheduler) MonitorActivePatterns() error {
+	m.monitorRuns++
+	return m.monitorErr
+}
+
+func (m *mockScheduler) GetSchedulerStatus() *services.PatternSchedulerStatus { return &m.status }
+
+// mockJobQueue runs submitted tasks synchronously
+type mockJobQueue struct {
+	results map[string]interface{}
+}
+
+func (m *mockJobQueue) Submit(jobType string, items []string, params map[string]interface{}, task services.JobTask) (*models.Job, error) {
+	m.results = map[string]interface{}{}
+	for _, item := range items {
+		result, err := task(context.Background(), item)
+		if err != nil {
+			return nil, err
+		}
+		m.results[item] = result
+	}
+	return &models.Job{ID: "job-1", Type: jobType, Status: models.JobStatusCompleted}, nil
+}
+
+// mockChartRenderer returns a fixed image, or ErrPatternNotFound for other IDs
+type mockChartRenderer struct {
+	id    int64
+	chart []byte
+}
+
+func (m *mockChartRenderer) PatternChart(family string, id int64) ([]byte, error) {
+	if id != m.id {
+		return nil, services.ErrPatternNotFound
+	}
+	return m.chart, nil
+}
+
+// mockPatternTimelines returns a one event timeline for a single pattern ID
+type mockPatternTimelines struct {
+	id int64
+}
+
+func (m *mockPatternTimelines) Timeline(family string, id int64) (*models.PatternTimeline, error) {
+	if id != m.id {
+		return nil, services.ErrPatternNotFound
+	}
+	return &models.PatternTimeline{Family: family, PatternID: id, Events: []*models.PatternTimelineEvent{
+		{EventType: models.TimelineEventDetected, Message: "Pattern detected"},
+	}}, nil
+}
+
+// mockRiskManager reports a fixed heat and sizes every setup at a fixed number of shares
+type mockRiskManager struct {
+	heat   *models.PortfolioHeat
+	shares int
+}
+
+func (m *mockRiskManager) PortfolioHeat() (*models.PortfolioHeat, error) { return m.heat, nil }
+
+func (m *mockRiskManager) ApplySizing(setups []*models.TradingSetup, heat *models.PortfolioHeat) {
+	for _, setup := range setups {
+		setup.Sizing = &models.PositionSizing{Shares: m.shares}
+		if heat != nil && heat.OverLimit {
+			setup.Sizing.Warning = "over heat"
+		}
+	}
+}
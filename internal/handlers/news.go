@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NewsHandler handles news API endpoints
+type NewsHandler struct {
+	newsService *services.NewsService
+}
+
+// NewNewsHandler creates a new news handler
+func NewNewsHandler(newsService *services.NewsService) *NewsHandler {
+	return &NewsHandler{
+		newsService: newsService,
+	}
+}
+
+// GetSymbolNews godoc
+// @Summary Get recent news for a symbol
+// @Description Get stored headlines with keyword sentiment tags, optionally fetching the latest first
+// @Tags news
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Param limit query int false "Maximum number of articles (default 20)"
+// @Param refresh query bool false "Fetch the latest headlines before returning"
+// @Success 200 {array} models.NewsArticle
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/news/{symbol} [get]
+func (h *NewsHandler) GetSymbolNews(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	if c.Query("refresh") == "true" {
+		if _, err := h.newsService.RefreshSymbol(symbol); err != nil {
+			respondError(c, "Failed to fetch news", err)
+			return
+		}
+	}
+
+	articles, err := h.newsService.GetNews(symbol, limit)
+	if err != nil {
+		respondError(c, "Failed to get news", err)
+		return
+	}
+
+	summary, err := h.newsService.GetSentimentSummary(symbol, 7)
+	if err != nil {
+		respondError(c, "Failed to get news sentiment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"symbol":    symbol,
+		"articles":  articles,
+		"count":     len(articles),
+		"sentiment": summary,
+	})
+}
+
+// RefreshNews godoc
+// @Summary Refresh news for watched symbols
+// @Tags news
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/news/refresh [post]
+func (h *NewsHandler) RefreshNews(c *gin.Context) {
+	if err := h.newsService.RefreshAll(); err != nil {
+		respondError(c, "Failed to refresh news", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "News refreshed",
+	})
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationsHandler handles the notification center API endpoints
+type NotificationsHandler struct {
+	db *database.Database
+}
+
+// NewNotificationsHandler creates a new notifications handler
+func NewNotificationsHandler(db *database.Database) *NotificationsHandler {
+	return &NotificationsHandler{
+		db: db,
+	}
+}
+
+// validNotificationCategory reports whether a category filter is empty or a known category
+func validNotificationCategory(category string) bool {
+	return category == "" || slices.Contains(models.NotificationCategories, category)
+}
+
+// GetNotifications godoc
+// @Summary List notifications
+// @Description Get notification center entries, newest first, with the number of unread ones
+// @Tags notifications
+// @Produce json
+// @Param category query string false "Filter by category: pattern, setup, alert, anomaly, system"
+// @Param unread query bool false "Only return unread notifications"
+// @Param limit query int false "Maximum number of notifications (default 50)"
+// @Param offset query int false "Number of notifications to skip"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/notifications [get]
+func (h *NotificationsHandler) GetNotifications(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	filter := &models.NotificationFilter{
+		Category:   c.Query("category"),
+		UnreadOnly: c.Query("unread") == "true",
+	}
+	if !validNotificationCategory(filter.Category) {
+		respondInvalid(c, "Category must be pattern, setup, alert, anomaly or system", nil)
+		return
+	}
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 && limit <= 500 {
+		filter.Limit = limit
+	}
+	if offset, err := strconv.Atoi(c.Query("offset")); err == nil && offset > 0 {
+		filter.Offset = offset
+	}
+
+	notifications, err := db.GetNotifications(filter)
+	if err != nil {
+		respondError(c, "Failed to get notifications", err)
+		return
+	}
+
+	unread, err := db.CountUnreadNotifications(filter.Category)
+	if err != nil {
+		respondError(c, "Failed to count unread notifications", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+		"count":         len(notifications),
+		"unread":        unread,
+	})
+}
+
+// MarkNotificationRead godoc
+// @Summary Mark a notification read
+// @Description Mark one notification center entry as read
+// @Tags notifications
+// @Produce json
+// @Param id path int true "Notification ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/notifications/{id}/read [put]
+func (h *NotificationsHandler) MarkNotificationRead(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondInvalid(c, "Invalid notification ID", nil)
+		return
+	}
+
+	found, err := db.MarkNotificationRead(id)
+	if err != nil {
+		respondError(c, "Failed to mark notification read", err)
+		return
+	}
+	if !found {
+		respondNotFound(c, "Notification not found", nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked read"})
+}
+
+// MarkAllNotificationsRead godoc
+// @Summary Mark all notifications read
+// @Description Mark every unread notification, or every unread one of a category, as read
+// @Tags notifications
+// @Produce json
+// @Param category query string false "Only mark this category: pattern, setup, alert, anomaly, system"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/notifications/read [post]
+func (h *NotificationsHandler) MarkAllNotificationsRead(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	category := c.Query("category")
+	if !validNotificationCategory(category) {
+		respondInvalid(c, "Category must be pattern, setup, alert, anomaly or system", nil)
+		return
+	}
+
+	marked, err := db.MarkAllNotificationsRead(category)
+	if err != nil {
+		respondError(c, "Failed to mark notifications read", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"marked": marked})
+}
+
+// ClearNotifications godoc
+// @Summary Clear notifications
+// @Description Delete every notification, or only the read ones
+// @Tags notifications
+// @Produce json
+// @Param read query bool false "Only delete read notifications"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/notifications [delete]
+func (h *NotificationsHandler) ClearNotifications(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	deleted, err := db.DeleteNotifications(c.Query("read") == "true")
+	if err != nil {
+		respondError(c, "Failed to clear notifications", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deleted": deleted})
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OptionsHandler handles options flow API endpoints
+type OptionsHandler struct {
+	optionsService *services.OptionsService
+}
+
+// NewOptionsHandler creates a new options handler
+func NewOptionsHandler(optionsService *services.OptionsService) *OptionsHandler {
+	return &OptionsHandler{
+		optionsService: optionsService,
+	}
+}
+
+// GetSummary godoc
+// @Summary Get options flow summary for a symbol
+// @Description Get the latest options snapshot, IV rank, put/call ratios, unusual volume and snapshot history
+// @Tags options
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Param days query int false "Number of days of history (default 30)"
+// @Success 200 {object} models.OptionsSummary
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/options/{symbol}/summary [get]
+func (h *OptionsHandler) GetSummary(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days <= 0 {
+		days = 30
+	}
+
+	summary, err := h.optionsService.GetSummary(symbol, days)
+	if err != nil {
+		respondError(c, "Failed to get options summary", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// CollectSnapshot godoc
+// @Summary Collect an options snapshot now
+// @Description Fetch the options chain for a symbol and store an aggregated snapshot
+// @Tags options
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Success 200 {object} models.OptionsSnapshot
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/options/{symbol}/collect [post]
+func (h *OptionsHandler) CollectSnapshot(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+
+	snapshot, err := h.optionsService.CollectSnapshot(symbol)
+	if err != nil {
+		respondError(c, "Failed to collect options snapshot", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, snapshot)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"market-watch-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parsePageRequest reads the page, limit, sort and order query parameters shared by list
+// endpoints. Only the given sort fields are accepted; order defaults to descending.
+func parsePageRequest(c *gin.Context, defaultLimit int, sortFields []string) (models.PageRequest, error) {
+	req := models.PageRequest{Page: 1, Limit: defaultLimit, Order: models.SortDesc}
+
+	if pageStr := c.Query("page"); pageStr != "" {
+		page, err := strconv.Atoi(pageStr)
+		if err != nil || page < 1 {
+			return req, fmt.Errorf("page must be a positive integer")
+		}
+		req.Page = page
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			return req, fmt.Errorf("limit must be a positive integer")
+		}
+		req.Limit = limit
+	}
+	if req.Limit > models.MaxPageLimit {
+		req.Limit = models.MaxPageLimit
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		valid := false
+		for _, field := range sortFields {
+			if field == sort {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return req, fmt.Errorf("sort must be one of: %s", strings.Join(sortFields, ", "))
+		}
+		req.Sort = sort
+	}
+
+	switch order := strings.ToLower(c.Query("order")); order {
+	case "":
+	case models.SortAsc, models.SortDesc:
+		req.Order = order
+	default:
+		return req, fmt.Errorf("order must be 'asc' or 'desc'")
+	}
+
+	return req, nil
+}
+
+// includeArchived reads the include_archived query parameter of list endpoints; archived records are left out
+// unless it is true
+func includeArchived(c *gin.Context) bool {
+	include, _ := strconv.ParseBool(c.Query("include_archived"))
+	return include
+}
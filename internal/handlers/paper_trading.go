@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PaperTradingHandler handles paper trading API endpoints
+type PaperTradingHandler struct {
+	paperTradingService *services.PaperTradingService
+}
+
+// NewPaperTradingHandler creates a new paper trading handler
+func NewPaperTradingHandler(paperTradingService *services.PaperTradingService) *PaperTradingHandler {
+	return &PaperTradingHandler{
+		paperTradingService: paperTradingService,
+	}
+}
+
+// GetStatus godoc
+// @Summary Get paper trading account status
+// @Description Get simulated equity, cash, P&L and trade counts
+// @Tags paper-trading
+// @Produce json
+// @Success 200 {object} models.PaperTradingStatus
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/paper-trading/status [get]
+func (h *PaperTradingHandler) GetStatus(c *gin.Context) {
+	status, err := h.paperTradingService.GetStatus()
+	if err != nil {
+		respondError(c, "Failed to get paper trading status", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// GetTrades godoc
+// @Summary List paper trades
+// @Tags paper-trading
+// @Produce json
+// @Param status query string false "Filter by status: pending, open, closed, expired, cancelled"
+// @Param symbol query string false "Filter by symbol"
+// @Param limit query int false "Maximum number of trades (default 100)"
+// @Success 200 {array} models.PaperTrade
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/paper-trading/trades [get]
+func (h *PaperTradingHandler) GetTrades(c *gin.Context) {
+	filter := &models.PaperTradeFilter{
+		Status: c.Query("status"),
+		Symbol: strings.ToUpper(c.Query("symbol")),
+		Limit:  100,
+	}
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 {
+		filter.Limit = limit
+	}
+
+	trades, err := h.paperTradingService.GetTrades(filter)
+	if err != nil {
+		respondError(c, "Failed to get paper trades", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trades": trades,
+		"count":  len(trades),
+	})
+}
+
+// GetEquityCurve godoc
+// @Summary Get the paper trading equity curve
+// @Tags paper-trading
+// @Produce json
+// @Param days query int false "Number of days of history (default 30)"
+// @Success 200 {array} models.PaperEquityPoint
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/paper-trading/equity [get]
+func (h *PaperTradingHandler) GetEquityCurve(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days <= 0 {
+		days = 30
+	}
+
+	points, err := h.paperTradingService.GetEquityCurve(time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		respondError(c, "Failed to get equity curve", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"points": points,
+		"count":  len(points),
+	})
+}
+
+// RunCycle godoc
+// @Summary Run a paper trading cycle now
+// @Description Simulate fills and exits on new bars and take qualifying setups without waiting for the next interval
+// @Tags paper-trading
+// @Produce json
+// @Success 200 {object} models.PaperTradingCycleResult
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/paper-trading/run [post]
+func (h *PaperTradingHandler) RunCycle(c *gin.Context) {
+	result, err := h.paperTradingService.RunCycle()
+	if err != nil {
+		respondError(c, "Failed to run paper trading cycle", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CloseTrade godoc
+// @Summary Close a paper trade
+// @Description Cancel a pending trade or close an open trade at the latest price
+// @Tags paper-trading
+// @Produce json
+// @Param id path int true "Paper trade ID"
+// @Success 200 {object} models.PaperTrade
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/paper-trading/trades/{id}/close [post]
+func (h *PaperTradingHandler) CloseTrade(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondInvalid(c, "Invalid paper trade ID", nil)
+		return
+	}
+
+	trade, err := h.paperTradingService.CloseTrade(id)
+	if err != nil {
+		respondInvalid(c, "Failed to close paper trade", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, trade)
+}
+
+// Reset godoc
+// @Summary Reset paper trading
+// @Description Delete all paper trades and the equity curve
+// @Tags paper-trading
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/paper-trading/reset [post]
+func (h *PaperTradingHandler) Reset(c *gin.Context) {
+	if err := h.paperTradingService.Reset(); err != nil {
+		respondError(c, "Failed to reset paper trading", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Paper trading reset",
+	})
+}
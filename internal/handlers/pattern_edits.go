@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"market-watch-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetHeadShouldersEditor sets the service that corrects head and shoulders key points
+func (h *PatternsHandler) SetHeadShouldersEditor(editor HeadShouldersEditor) {
+	h.hsEditor = editor
+}
+
+// EditHeadShouldersKeyPoints godoc
+// @Summary Correct a head and shoulders pattern's key points
+// @Description Move the shoulders, head or reaction points of a detected pattern to the bars nearest the given timestamps, or pin the neckline. The neckline, targets, symmetry, thesis and linked setup are recalculated, the pattern is marked as human-edited so rescans keep the corrections, and the change is added to its audit trail.
+// @Tags patterns
+// @Accept json
+// @Produce json
+// @Param id path int true "Pattern ID"
+// @Param edit body models.HeadShouldersEditRequest true "Key points to move"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/patterns/head-shoulders/{id}/key-points [put]
+func (h *PatternsHandler) EditHeadShouldersKeyPoints(c *gin.Context) {
+	if h.hsEditor == nil {
+		respondUnavailable(c, "Pattern editing is not available", nil)
+		return
+	}
+
+	id, ok := parsePatternID(c)
+	if !ok {
+		return
+	}
+
+	var request models.HeadShouldersEditRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalid(c, "Invalid request body", err)
+		return
+	}
+
+	pattern, edit, err := h.hsEditor.EditKeyPoints(id, &request)
+	if err != nil {
+		respondError(c, "Failed to edit pattern", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pattern": pattern,
+		"edit":    edit,
+	})
+}
+
+// GetHeadShouldersEdits godoc
+// @Summary Get a head and shoulders pattern's edit history
+// @Description Get the audit trail of manual key point corrections of a pattern, oldest first
+// @Tags patterns
+// @Produce json
+// @Param id path int true "Pattern ID"
+// @Success 200 {array} models.PatternEdit
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/patterns/head-shoulders/{id}/edits [get]
+func (h *PatternsHandler) GetHeadShouldersEdits(c *gin.Context) {
+	if h.hsEditor == nil {
+		respondUnavailable(c, "Pattern editing is not available", nil)
+		return
+	}
+
+	id, ok := parsePatternID(c)
+	if !ok {
+		return
+	}
+
+	edits, err := h.hsEditor.GetEdits(id)
+	if err != nil {
+		respondError(c, "Failed to get pattern edits", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, edits)
+}
+
+// parsePatternID parses the id path parameter, writing a bad request response on failure
+func parsePatternID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		respondInvalid(c, "Invalid pattern ID", nil)
+		return 0, false
+	}
+	return id, true
+}
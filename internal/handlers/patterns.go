@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 
-	"market-watch-go/internal/database"
 	"market-watch-go/internal/models"
 	"market-watch-go/internal/services"
 
@@ -14,145 +18,246 @@ import (
 
 // PatternsHandler handles unified pattern detection for all pattern types
 type PatternsHandler struct {
-	db                  *database.DB
-	patternService      *services.PatternDetectionService
-	hsService           *services.HeadShouldersDetectionService
-	fallingWedgeService *services.FallingWedgeDetectionService
+	db                  PatternStore
+	patternService      PatternScheduler
+	hsService           HeadShouldersDetector
+	fallingWedgeService WedgeDetector
+	triangleService     TriangleDetector
+	flagService         FlagDetector
+	jobService          JobQueue
+	chartService        ChartRenderer
+	timelineService     PatternTimelines
+	hsEditor            HeadShouldersEditor
 }
 
 // NewPatternsHandler creates a new unified patterns handler
 func NewPatternsHandler(
-	db *database.DB,
-	patternService *services.PatternDetectionService,
-	hsService *services.HeadShouldersDetectionService,
-	fallingWedgeService *services.FallingWedgeDetectionService,
+	db PatternStore,
+	patternService PatternScheduler,
+	hsService HeadShouldersDetector,
+	fallingWedgeService WedgeDetector,
+	triangleService TriangleDetector,
+	flagService FlagDetector,
 ) *PatternsHandler {
 	return &PatternsHandler{
 		db:                  db,
 		patternService:      patternService,
 		hsService:           hsService,
 		fallingWedgeService: fallingWedgeService,
+		triangleService:     triangleService,
+		flagService:         flagService,
 	}
 }
 
-// ScanAllPatterns scans all watched symbols for all pattern types
+// SetJobService sets the job queue used for watchlist-wide scans
+func (h *PatternsHandler) SetJobService(jobService JobQueue) {
+	h.jobService = jobService
+}
+
+// SetChartService sets the renderer for pattern chart images
+func (h *PatternsHandler) SetChartService(chartService ChartRenderer) {
+	h.chartService = chartService
+}
+
+// SetTimelineService sets the service assembling pattern thesis timelines
+func (h *PatternsHandler) SetTimelineService(timelineService PatternTimelines) {
+	h.timelineService = timelineService
+}
+
+// ScanAllPatterns queues a pattern scan of all watched symbols and returns the job ID
 func (h *PatternsHandler) ScanAllPatterns(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	if h.jobService == nil {
+		respondUnavailable(c, "Job queue is not available", nil)
+		return
+	}
+
+	scan, err := parseScanParams(c)
+	if err != nil {
+		respondInvalid(c, err.Error(), nil)
+		return
+	}
+
 	// Get all watched symbols
-	symbols, err := h.db.GetWatchedSymbols()
+	symbols, err := db.GetWatchedSymbols()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to get watched symbols",
-			"details": err.Error(),
-		})
+		respondError(c, "Failed to get watched symbols", err)
 		return
 	}
 
-	if len(symbols) == 0 {
-		c.JSON(http.StatusOK, gin.H{
-			"message":              "No symbols to scan",
-			"symbols_scanned":      0,
-			"patterns_found":       0,
-			"head_shoulders_found": 0,
-			"falling_wedge_found":  0,
-		})
+	params := map[string]interface{}{"timeframe": scan.Timeframe, "lookback_days": scan.LookbackDays, "sensitivity": scan.Sensitivity}
+	job, err := h.jobService.Submit(models.JobTypePatternScan, symbols, params, func(ctx context.Context, symbol string) (interface{}, error) {
+		return h.scanSymbol(ctx, symbol, scan)
+	})
+	if err != nil {
+		respondUnavailable(c, "Failed to queue pattern scan", err)
 		return
 	}
 
-	// Track scan results
-	var headShouldersFound, fallingWedgeFound int
-	var scanErrors []string
-	scannedSymbols := 0
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":    fmt.Sprintf("Pattern scan queued for %d symbols", len(symbols)),
+		"job_id":     job.ID,
+		"status_url": "/api/v1/jobs/" + job.ID,
+		"job":        job,
+	})
+}
 
-	// Scan each symbol for all pattern types
-	for _, symbol := range symbols {
-		scannedSymbols++
+// symbolScanResult summarizes the patterns found for one symbol during a scan
+type symbolScanResult struct {
+	PatternsFound        int      `json:"patterns_found"`
+	HeadShoulders        bool     `json:"head_shoulders"`
+	BearishHeadShoulders bool     `json:"bearish_head_shoulders"`
+	FallingWedge         bool     `json:"falling_wedge"`
+	RisingWedge          bool     `json:"rising_wedge"`
+	Triangle             bool     `json:"triangle"`
+	Flag                 bool     `json:"flag"`
+	Errors               []string `json:"errors,omitempty"`
+}
 
-		// Scan for Head & Shoulders patterns
-		_, err := h.hsService.DetectInverseHeadShoulders(symbol)
-		if err == nil {
-			headShouldersFound++
-		}
+// parseScanParams reads the timeframe, lookback_days and sensitivity query parameters of a pattern scan
+func parseScanParams(c *gin.Context) (*models.PatternScanParams, error) {
+	return models.ParsePatternScanParams(c.Query("timeframe"), c.Query("lookback_days"), c.Query("sensitivity"))
+}
 
-		// Scan for Falling Wedge patterns
-		_, err = h.fallingWedgeService.DetectFallingWedge(symbol)
+// scanSymbol runs every pattern detector for a symbol; it fails only when every detector errored
+func (h *PatternsHandler) scanSymbol(ctx context.Context, symbol string, scan *models.PatternScanParams) (*symbolScanResult, error) {
+	result := &symbolScanResult{}
+	record := func(name string, found *bool, err error) {
 		if err == nil {
-			fallingWedgeFound++
-		} else {
-			// Only log non-pattern-not-found errors
-			if err.Error() != "no valid falling wedge pattern found" &&
-				err.Error() != "no valid inverse head and shoulders pattern found" &&
-				err.Error() != "insufficient price data for pattern detection" {
-				scanErrors = append(scanErrors, fmt.Sprintf("%s: %s", symbol, err.Error()))
-			}
+			*found = true
+			result.PatternsFound++
+		} else if !isPatternNotFound(err) {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %s", name, err.Error()))
 		}
 	}
 
-	totalPatternsFound := headShouldersFound + fallingWedgeFound
+	_, err := h.hsService.DetectInverseHeadShoulders(ctx, symbol, scan)
+	record("Head & Shoulders", &result.HeadShoulders, err)
 
-	// Prepare response
-	response := gin.H{
-		"message":              fmt.Sprintf("Pattern scan completed for %d symbols", scannedSymbols),
-		"symbols_scanned":      scannedSymbols,
-		"patterns_found":       totalPatternsFound,
-		"head_shoulders_found": headShouldersFound,
-		"falling_wedge_found":  fallingWedgeFound,
-	}
+	_, err = h.hsService.DetectHeadShoulders(ctx, symbol, scan)
+	record("Bearish Head & Shoulders", &result.BearishHeadShoulders, err)
 
-	// Include errors if any
-	if len(scanErrors) > 0 {
-		response["errors"] = scanErrors
-		response["message"] = fmt.Sprintf("Pattern scan completed for %d symbols with %d errors", scannedSymbols, len(scanErrors))
+	_, err = h.fallingWedgeService.DetectFallingWedge(ctx, symbol, scan)
+	record("Falling Wedge", &result.FallingWedge, err)
+
+	_, err = h.fallingWedgeService.DetectRisingWedge(ctx, symbol, scan)
+	record("Rising Wedge", &result.RisingWedge, err)
+
+	_, err = h.triangleService.DetectTriangle(ctx, symbol, scan.TimeframeOrDefault())
+	record("Triangle", &result.Triangle, err)
+
+	_, err = h.flagService.DetectFlag(ctx, symbol, scan.TimeframeOrDefault())
+	record("Flag", &result.Flag, err)
+
+	if len(result.Errors) == 6 {
+		return nil, fmt.Errorf("%s", strings.Join(result.Errors, "; "))
 	}
 
-	c.JSON(http.StatusOK, response)
+	return result, nil
 }
 
-// ScanSymbolPatterns scans a specific symbol for all pattern types
+// ScanSymbolPatterns scans a specific symbol for all pattern types. Optional timeframe, lookback_days and
+// sensitivity query parameters override the configured scan settings; triangles and flags only use the timeframe.
 func (h *PatternsHandler) ScanSymbolPatterns(c *gin.Context) {
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
+		return
+	}
+
+	scan, err := parseScanParams(c)
+	if err != nil {
+		respondInvalid(c, err.Error(), nil)
 		return
 	}
 
-	var headShouldersPattern *models.HeadShouldersPattern
-	var fallingWedgePattern *models.FallingWedgePattern
+	var headShouldersPattern, bearishHeadShouldersPattern *models.HeadShouldersPattern
+	var fallingWedgePattern, risingWedgePattern *models.FallingWedgePattern
+	var trianglePattern *models.TrianglePattern
+	var flagPattern *models.FlagPattern
 	var scanErrors []string
+	ctx := c.Request.Context()
 
 	// Detect Head & Shoulders pattern
-	hsPattern, err := h.hsService.DetectInverseHeadShoulders(symbol)
+	hsPattern, err := h.hsService.DetectInverseHeadShoulders(ctx, symbol, scan)
 	if err == nil {
 		headShouldersPattern = hsPattern
-	} else if err.Error() != "no valid inverse head and shoulders pattern found" &&
-		err.Error() != "insufficient price data for pattern detection" {
+	} else if !isPatternNotFound(err) {
 		scanErrors = append(scanErrors, fmt.Sprintf("Head & Shoulders: %s", err.Error()))
 	}
 
+	// Detect bearish Head & Shoulders top
+	topPattern, err := h.hsService.DetectHeadShoulders(ctx, symbol, scan)
+	if err == nil {
+		bearishHeadShouldersPattern = topPattern
+	} else if !isPatternNotFound(err) {
+		scanErrors = append(scanErrors, fmt.Sprintf("Bearish Head & Shoulders: %s", err.Error()))
+	}
+
 	// Detect Falling Wedge pattern
-	fwPattern, err := h.fallingWedgeService.DetectFallingWedge(symbol)
+	fwPattern, err := h.fallingWedgeService.DetectFallingWedge(ctx, symbol, scan)
 	if err == nil {
 		fallingWedgePattern = fwPattern
-	} else if err.Error() != "no valid falling wedge pattern found" &&
-		err.Error() != "insufficient price data for pattern detection" {
+	} else if !isPatternNotFound(err) {
 		scanErrors = append(scanErrors, fmt.Sprintf("Falling Wedge: %s", err.Error()))
 	}
 
+	// Detect Rising Wedge pattern
+	rwPattern, err := h.fallingWedgeService.DetectRisingWedge(ctx, symbol, scan)
+	if err == nil {
+		risingWedgePattern = rwPattern
+	} else if !isPatternNotFound(err) {
+		scanErrors = append(scanErrors, fmt.Sprintf("Rising Wedge: %s", err.Error()))
+	}
+
+	// Detect Triangle pattern
+	trPattern, err := h.triangleService.DetectTriangle(ctx, symbol, scan.TimeframeOrDefault())
+	if err == nil {
+		trianglePattern = trPattern
+	} else if !isPatternNotFound(err) {
+		scanErrors = append(scanErrors, fmt.Sprintf("Triangle: %s", err.Error()))
+	}
+
+	// Detect Flag pattern
+	flPattern, err := h.flagService.DetectFlag(ctx, symbol, scan.TimeframeOrDefault())
+	if err == nil {
+		flagPattern = flPattern
+	} else if !isPatternNotFound(err) {
+		scanErrors = append(scanErrors, fmt.Sprintf("Flag: %s", err.Error()))
+	}
+
 	patternsFound := 0
 	if headShouldersPattern != nil {
 		patternsFound++
 	}
+	if bearishHeadShouldersPattern != nil {
+		patternsFound++
+	}
 	if fallingWedgePattern != nil {
 		patternsFound++
 	}
+	if risingWedgePattern != nil {
+		patternsFound++
+	}
+	if trianglePattern != nil {
+		patternsFound++
+	}
+	if flagPattern != nil {
+		patternsFound++
+	}
 
 	response := gin.H{
-		"symbol":                 symbol,
-		"patterns_found":         patternsFound,
-		"head_shoulders_pattern": headShouldersPattern,
-		"falling_wedge_pattern":  fallingWedgePattern,
-		"message":                fmt.Sprintf("Pattern detection completed for %s", symbol),
+		"symbol":                         symbol,
+		"scan_params":                    scan,
+		"patterns_found":                 patternsFound,
+		"head_shoulders_pattern":         headShouldersPattern,
+		"bearish_head_shoulders_pattern": bearishHeadShouldersPattern,
+		"falling_wedge_pattern":          fallingWedgePattern,
+		"rising_wedge_pattern":           risingWedgePattern,
+		"triangle_pattern":               trianglePattern,
+		"flag_pattern":                   flagPattern,
+		"message":                        fmt.Sprintf("Pattern detection completed for %s", symbol),
 	}
 
 	if len(scanErrors) > 0 {
@@ -162,62 +267,174 @@ func (h *PatternsHandler) ScanSymbolPatterns(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-// GetAllPatterns returns all patterns of all types
+// GetAllPatterns returns a page of patterns of all types, merged and sorted across pattern families
 func (h *PatternsHandler) GetAllPatterns(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	// Parse query parameters
 	symbolFilter := c.Query("symbol")
-	patternType := c.Query("pattern_type") // "head_shoulders", "falling_wedge", or empty for all
-	limitStr := c.DefaultQuery("limit", "100")
+	patternType := c.Query("pattern_type") // "head_shoulders", "falling_wedge", "rising_wedge", "triangle", "flag", or empty for all
+	archived := includeArchived(c)
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 100
+	page, err := parsePageRequest(c, 100, models.PatternSortFields)
+	if err != nil {
+		respondInvalid(c, "", err)
+		return
 	}
-
-	response := gin.H{
-		"patterns": gin.H{
-			"head_shoulders": []interface{}{},
-			"falling_wedge":  []interface{}{},
-		},
-		"total_count": 0,
+	if page.Sort == "" {
+		page.Sort = "detected_at"
 	}
 
+	// Each family contributes up to offset+limit rows, which is enough to fill the page once merged
+	fetch := page.Offset() + page.Limit
+	items := make([]*models.PatternListItem, 0)
+	total := 0
+
 	// Get Head & Shoulders patterns
 	if patternType == "" || patternType == "head_shoulders" {
-		hsFilter := &models.PatternFilter{
-			Symbol: symbolFilter,
-			Limit:  limit,
+		filter := &models.PatternFilter{Symbol: symbolFilter, IncludeArchived: archived, Limit: fetch, Sort: page.Sort, Order: page.Order}
+		patterns, err := db.GetHeadShouldersPatterns(filter)
+		if err != nil {
+			h.patternListError(c, "head and shoulders", err)
+			return
+		}
+		count, err := db.CountHeadShouldersPatterns(filter)
+		if err != nil {
+			h.patternListError(c, "head and shoulders", err)
+			return
 		}
-		hsPatterns, err := h.db.GetHeadShouldersPatterns(hsFilter)
-		if err == nil && hsPatterns != nil {
-			response["patterns"].(gin.H)["head_shoulders"] = hsPatterns
-			response["total_count"] = response["total_count"].(int) + len(hsPatterns)
+		total += count
+		for _, p := range patterns {
+			items = append(items, &models.PatternListItem{PatternFamily: "head_shoulders", Direction: p.Direction(), ID: p.ID, Symbol: p.Symbol, DetectedAt: p.DetectedAt, LastUpdated: p.LastUpdated, Pattern: p})
 		}
 	}
 
-	// Get Falling Wedge patterns
-	if patternType == "" || patternType == "falling_wedge" {
-		fwFilter := &models.FallingWedgeFilter{
-			Symbol: symbolFilter,
-			Limit:  limit,
+	// Get Falling and Rising Wedge patterns
+	if patternType == "" || patternType == models.PatternFallingWedge || patternType == models.PatternRisingWedge {
+		filter := &models.FallingWedgeFilter{Symbol: symbolFilter, PatternType: patternType, IncludeArchived: archived, Limit: fetch, Sort: page.Sort, Order: page.Order}
+		patterns, err := db.GetFallingWedgePatterns(filter)
+		if err != nil {
+			h.patternListError(c, "wedge", err)
+			return
+		}
+		count, err := db.CountFallingWedgePatterns(filter)
+		if err != nil {
+			h.patternListError(c, "wedge", err)
+			return
 		}
-		fwPatterns, err := h.db.GetFallingWedgePatterns(fwFilter)
-		if err == nil && fwPatterns != nil {
-			response["patterns"].(gin.H)["falling_wedge"] = fwPatterns
-			response["total_count"] = response["total_count"].(int) + len(fwPatterns)
+		total += count
+		for _, p := range patterns {
+			items = append(items, &models.PatternListItem{PatternFamily: "falling_wedge", Direction: p.Direction(), ID: p.ID, Symbol: p.Symbol, DetectedAt: p.DetectedAt, LastUpdated: p.LastUpdated, Pattern: p})
 		}
 	}
 
-	c.JSON(http.StatusOK, response)
+	// Get Triangle patterns
+	if patternType == "" || patternType == "triangle" {
+		filter := &models.TriangleFilter{Symbol: symbolFilter, IncludeArchived: archived, Limit: fetch, Sort: page.Sort, Order: page.Order}
+		patterns, err := db.GetTrianglePatterns(filter)
+		if err != nil {
+			h.patternListError(c, "triangle", err)
+			return
+		}
+		count, err := db.CountTrianglePatterns(filter)
+		if err != nil {
+			h.patternListError(c, "triangle", err)
+			return
+		}
+		total += count
+		for _, p := range patterns {
+			items = append(items, &models.PatternListItem{PatternFamily: "triangle", Direction: directionOf(p.IsBullish()), ID: p.ID, Symbol: p.Symbol, DetectedAt: p.DetectedAt, LastUpdated: p.LastUpdated, Pattern: p})
+		}
+	}
+
+	// Get Flag patterns
+	if patternType == "" || patternType == "flag" {
+		filter := &models.FlagFilter{Symbol: symbolFilter, IncludeArchived: archived, Limit: fetch, Sort: page.Sort, Order: page.Order}
+		patterns, err := db.GetFlagPatterns(filter)
+		if err != nil {
+			h.patternListError(c, "flag", err)
+			return
+		}
+		count, err := db.CountFlagPatterns(filter)
+		if err != nil {
+			h.patternListError(c, "flag", err)
+			return
+		}
+		total += count
+		for _, p := range patterns {
+			items = append(items, &models.PatternListItem{PatternFamily: "flag", Direction: directionOf(p.IsBullish()), ID: p.ID, Symbol: p.Symbol, DetectedAt: p.DetectedAt, LastUpdated: p.LastUpdated, Pattern: p})
+		}
+	}
+
+	sortPatternItems(items, page.Sort, page.Order)
+
+	start := min(page.Offset(), len(items))
+	end := min(start+page.Limit, len(items))
+
+	links, err := db.GetPatternLinks(symbolFilter)
+	if err != nil {
+		respondError(c, "Failed to get pattern links", err)
+		return
+	}
+	for _, item := range items[start:end] {
+		for _, link := range links {
+			if link.Links(item.PatternFamily, item.ID) {
+				item.Links = append(item.Links, link)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, &models.PagedResponse{
+		Items:      items[start:end],
+		Pagination: models.NewPagination(page, total),
+	})
+}
+
+// directionOf returns the trading direction for a pattern's bullish flag
+func directionOf(bullish bool) string {
+	if bullish {
+		return "bullish"
+	}
+	return "bearish"
+}
+
+// patternListError reports a failure to load one pattern family
+func (h *PatternsHandler) patternListError(c *gin.Context, family string, err error) {
+	respondError(c, fmt.Sprintf("Failed to get %s patterns", family), err)
+}
+
+// sortPatternItems orders merged pattern items by one of models.PatternSortFields
+func sortPatternItems(items []*models.PatternListItem, field, order string) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if order == models.SortAsc {
+			a, b = b, a
+		}
+		switch field {
+		case "symbol":
+			if a.Symbol != b.Symbol {
+				return a.Symbol > b.Symbol
+			}
+		case "last_updated":
+			if !a.LastUpdated.Equal(b.LastUpdated) {
+				return a.LastUpdated.After(b.LastUpdated)
+			}
+		default:
+			if !a.DetectedAt.Equal(b.DetectedAt) {
+				return a.DetectedAt.After(b.DetectedAt)
+			}
+		}
+		return a.ID > b.ID
+	})
 }
 
 // GetPatternsBySymbol returns all patterns for a specific symbol
 func (h *PatternsHandler) GetPatternsBySymbol(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
 		return
 	}
 
@@ -226,6 +443,9 @@ func (h *PatternsHandler) GetPatternsBySymbol(c *gin.Context) {
 		"patterns": gin.H{
 			"head_shoulders": []interface{}{},
 			"falling_wedge":  []interface{}{},
+			"rising_wedge":   []interface{}{},
+			"triangle":       []interface{}{},
+			"flag":           []interface{}{},
 		},
 		"total_count": 0,
 	}
@@ -235,39 +455,105 @@ func (h *PatternsHandler) GetPatternsBySymbol(c *gin.Context) {
 		Symbol: symbol,
 		Limit:  100,
 	}
-	hsPatterns, err := h.db.GetHeadShouldersPatterns(hsFilter)
+	hsPatterns, err := db.GetHeadShouldersPatterns(hsFilter)
 	if err == nil && hsPatterns != nil {
 		response["patterns"].(gin.H)["head_shoulders"] = hsPatterns
 		response["total_count"] = response["total_count"].(int) + len(hsPatterns)
 	}
 
-	// Get Falling Wedge patterns for symbol
-	fwPatterns, err := h.db.GetFallingWedgePatternsBySymbol(symbol)
+	// Get Falling and Rising Wedge patterns for symbol
+	fwPatterns, err := db.GetFallingWedgePatternsBySymbol(symbol)
 	if err == nil && fwPatterns != nil {
-		response["patterns"].(gin.H)["falling_wedge"] = fwPatterns
+		var falling, rising []*models.FallingWedgePattern
+		for _, p := range fwPatterns {
+			if p.IsRising() {
+				rising = append(rising, p)
+			} else {
+				falling = append(falling, p)
+			}
+		}
+		if len(falling) > 0 {
+			response["patterns"].(gin.H)["falling_wedge"] = falling
+		}
+		if len(rising) > 0 {
+			response["patterns"].(gin.H)["rising_wedge"] = rising
+		}
 		response["total_count"] = response["total_count"].(int) + len(fwPatterns)
 	}
 
+	// Get Triangle patterns for symbol
+	trPatterns, err := db.GetTrianglePatternsBySymbol(symbol)
+	if err == nil && trPatterns != nil {
+		response["patterns"].(gin.H)["triangle"] = trPatterns
+		response["total_count"] = response["total_count"].(int) + len(trPatterns)
+	}
+
+	// Get Flag patterns for symbol
+	flPatterns, err := db.GetFlagPatternsBySymbol(symbol)
+	if err == nil && flPatterns != nil {
+		response["patterns"].(gin.H)["flag"] = flPatterns
+		response["total_count"] = response["total_count"].(int) + len(flPatterns)
+	}
+
+	// Get links between the symbol's overlapping patterns
+	if links, err := db.GetPatternLinks(symbol); err == nil {
+		response["links"] = links
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
+// GetPatternLinks returns the links between overlapping patterns of different families, for one symbol when
+// the symbol query parameter is set. A link records which pattern's setup was kept when both had one.
+func (h *PatternsHandler) GetPatternLinks(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	symbol := strings.ToUpper(c.Query("symbol"))
+	links, err := db.GetPatternLinks(symbol)
+	if err != nil {
+		respondError(c, "Failed to get pattern links", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"symbol": symbol,
+		"links":  links,
+		"count":  len(links),
+	})
+}
+
 // GetPatternStatistics returns statistics for all pattern types
 func (h *PatternsHandler) GetPatternStatistics(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	// Get Head & Shoulders patterns
-	hsPatterns, err := h.db.GetHeadShouldersPatterns(&models.PatternFilter{Limit: 10000})
+	hsPatterns, err := db.GetHeadShouldersPatterns(&models.PatternFilter{Limit: 10000})
 	if err != nil {
 		hsPatterns = []*models.HeadShouldersPattern{}
 	}
 
 	// Get Falling Wedge patterns
-	fwPatterns, err := h.db.GetFallingWedgePatterns(&models.FallingWedgeFilter{Limit: 10000})
+	fwPatterns, err := db.GetFallingWedgePatterns(&models.FallingWedgeFilter{Limit: 10000})
 	if err != nil {
 		fwPatterns = []*models.FallingWedgePattern{}
 	}
 
-	totalPatterns := len(hsPatterns) + len(fwPatterns)
+	// Get Triangle patterns
+	trPatterns, err := db.GetTrianglePatterns(&models.TriangleFilter{Limit: 10000})
+	if err != nil {
+		trPatterns = []*models.TrianglePattern{}
+	}
+
+	// Get Flag patterns
+	flPatterns, err := db.GetFlagPatterns(&models.FlagFilter{Limit: 10000})
+	if err != nil {
+		flPatterns = []*models.FlagPattern{}
+	}
+
+	totalPatterns := len(hsPatterns) + len(fwPatterns) + len(trPatterns) + len(flPatterns)
 	activePatterns := 0
 	completedPatterns := 0
+	directions := map[string]int{"bullish": 0, "bearish": 0}
 
 	// Count active/completed for Head & Shoulders
 	for _, pattern := range hsPatterns {
@@ -276,15 +562,41 @@ func (h *PatternsHandler) GetPatternStatistics(c *gin.Context) {
 		} else {
 			activePatterns++
 		}
+		directions[pattern.Direction()]++
 	}
 
-	// Count active/completed for Falling Wedge
+	// Count active/completed for Falling and Rising Wedges
+	risingWedges := 0
 	for _, pattern := range fwPatterns {
 		if pattern.IsComplete {
 			completedPatterns++
 		} else {
 			activePatterns++
 		}
+		if pattern.IsRising() {
+			risingWedges++
+		}
+		directions[pattern.Direction()]++
+	}
+
+	// Count active/completed for Triangles
+	for _, pattern := range trPatterns {
+		if pattern.IsComplete {
+			completedPatterns++
+		} else {
+			activePatterns++
+		}
+		directions[directionOf(pattern.IsBullish())]++
+	}
+
+	// Count active/completed for Flags
+	for _, pattern := range flPatterns {
+		if pattern.IsComplete {
+			completedPatterns++
+		} else {
+			activePatterns++
+		}
+		directions[directionOf(pattern.IsBullish())]++
 	}
 
 	stats := gin.H{
@@ -293,10 +605,170 @@ func (h *PatternsHandler) GetPatternStatistics(c *gin.Context) {
 		"completed_patterns": completedPatterns,
 		"pattern_types": gin.H{
 			"head_shoulders": len(hsPatterns),
-			"falling_wedge":  len(fwPatterns),
+			"falling_wedge":  len(fwPatterns) - risingWedges,
+			"rising_wedge":   risingWedges,
+			"triangle":       len(trPatterns),
+			"flag":           len(flPatterns),
 		},
+		"directions":   directions,
 		"last_updated": "now",
 	}
 
 	c.JSON(http.StatusOK, stats)
 }
+
+// MonitorPatterns updates the thesis components of all active patterns
+func (h *PatternsHandler) MonitorPatterns(c *gin.Context) {
+	response := gin.H{
+		"message": "Active pattern monitoring completed",
+	}
+
+	if err := h.patternService.MonitorActivePatterns(); err != nil {
+		response["errors"] = strings.Split(err.Error(), "\n")
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetPatternChart renders a stored pattern as a PNG candlestick chart with its pattern lines, neckline or
+// breakout level, target and nearby S/R levels. The route shares the :symbol wildcard, which holds the pattern
+// ID here; ?type= selects the pattern family since IDs are per family.
+func (h *PatternsHandler) GetPatternChart(c *gin.Context) {
+	if h.chartService == nil {
+		respondUnavailable(c, "Chart rendering is not available", nil)
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("symbol"), 10, 64)
+	if err != nil || id <= 0 {
+		respondInvalid(c, "Invalid pattern ID", nil)
+		return
+	}
+
+	family := c.DefaultQuery("type", services.ChartFamilyHeadShoulders)
+	valid := false
+	for _, f := range services.ChartFamilies {
+		valid = valid || f == family
+	}
+	if !valid {
+		respondInvalid(c, fmt.Sprintf("Invalid type %q: must be one of %s", family, strings.Join(services.ChartFamilies, ", ")), nil)
+		return
+	}
+
+	chart, err := h.chartService.PatternChart(family, id)
+	if errors.Is(err, services.ErrPatternNotFound) {
+		respondNotFound(c, fmt.Sprintf("No %s pattern with ID %d", family, id), nil)
+		return
+	}
+	if err != nil {
+		respondError(c, "Failed to render pattern chart", err)
+		return
+	}
+
+	c.Data(http.StatusOK, "image/png", chart)
+}
+
+// GetPatternTimeline returns the chronological thesis component completions, phase changes and alerts of a stored
+// pattern, with evidence, for rendering a progress timeline. Like the chart route it shares the :symbol wildcard,
+// which holds the pattern ID, and ?type= selects the pattern family.
+func (h *PatternsHandler) GetPatternTimeline(c *gin.Context) {
+	if h.timelineService == nil {
+		respondUnavailable(c, "Pattern timelines are not available", nil)
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("symbol"), 10, 64)
+	if err != nil || id <= 0 {
+		respondInvalid(c, "Invalid pattern ID", nil)
+		return
+	}
+
+	family := c.DefaultQuery("type", services.ChartFamilyHeadShoulders)
+	if !slices.Contains(services.ChartFamilies, family) {
+		respondInvalid(c, fmt.Sprintf("Invalid type %q: must be one of %s", family, strings.Join(services.ChartFamilies, ", ")), nil)
+		return
+	}
+
+	timeline, err := h.timelineService.Timeline(family, id)
+	if errors.Is(err, services.ErrPatternNotFound) {
+		respondNotFound(c, fmt.Sprintf("No %s pattern with ID %d", family, id), nil)
+		return
+	}
+	if err != nil {
+		respondError(c, "Failed to get pattern timeline", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, timeline)
+}
+
+// ArchiveOldPatterns archives the patterns of every family detected more than ?days= (default 90) ago. Archived
+// patterns are kept for analytics but left out of pattern lists until the purge job deletes them.
+func (h *PatternsHandler) ArchiveOldPatterns(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "90"))
+	if err != nil || days <= 0 {
+		respondInvalid(c, "Invalid days parameter", nil)
+		return
+	}
+
+	archived, err := db.ArchiveOldPatterns(days)
+	if err != nil {
+		respondError(c, "Failed to archive old patterns", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Old patterns archived successfully",
+		"archived_count": archived,
+		"retention_days": days,
+	})
+}
+
+// ArchivePattern archives a pattern of the :family in the path, e.g. /patterns/triangle/12/archive
+func (h *PatternsHandler) ArchivePattern(c *gin.Context) {
+	h.setArchived(c, true)
+}
+
+// UnarchivePattern restores an archived pattern to the pattern lists
+func (h *PatternsHandler) UnarchivePattern(c *gin.Context) {
+	h.setArchived(c, false)
+}
+
+// setArchived archives or restores the pattern in the path
+func (h *PatternsHandler) setArchived(c *gin.Context, archived bool) {
+	db := withRequestContext(c, h.db)
+
+	family := c.Param("family")
+	if !slices.Contains(services.ChartFamilies, family) {
+		respondInvalid(c, fmt.Sprintf("Invalid family %q: must be one of %s", family, strings.Join(services.ChartFamilies, ", ")), nil)
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		respondInvalid(c, "Invalid pattern ID", nil)
+		return
+	}
+
+	if err := db.SetPatternArchived(family, id, archived); err != nil {
+		respondError(c, "Failed to archive pattern", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pattern_family": family,
+		"id":             id,
+		"archived":       archived,
+	})
+}
+
+// GetSchedulerStatus returns the status of the background pattern scanning and monitoring loop
+func (h *PatternsHandler) GetSchedulerStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.patternService.GetSchedulerStatus())
+}
+
+// isPatternNotFound reports whether a detection error just means no pattern was present
+func isPatternNotFound(err error) bool {
+	return errors.Is(err, services.ErrNoPattern)
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// newTestPatternsHandler creates a patterns handler over in-memory mocks
+func newTestPatternsHandler(store *mockPatternStore, detectors *mockDetectors) (*PatternsHandler, *mockScheduler) {
+	scheduler := &mockScheduler{}
+	return NewPatternsHandler(store, scheduler, detectors, detectors, detectors, detectors), scheduler
+}
+
+// TestScanSymbolPatterns tests that found patterns are counted while "no pattern" results are not errors
+func TestScanSymbolPatterns(t *testing.T) {
+	detectors := &mockDetectors{
+		inverseHS: &models.HeadShouldersPattern{Symbol: "AAPL", PatternType: "inverse_head_shoulders"},
+		triangle:  &models.TrianglePattern{Symbol: "AAPL", PatternType: "ascending_triangle"},
+		failing:   "flag",
+	}
+	h, _ := newTestPatternsHandler(&mockPatternStore{}, detectors)
+
+	w := serve("POST", "/patterns/scan/:symbol", "/patterns/scan/AAPL", "", h.ScanSymbolPatterns)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	response := decode[map[string]interface{}](t, w)
+	if found := response["patterns_found"]; found != float64(2) {
+		t.Errorf("expected 2 patterns found, got %v", found)
+	}
+	scanErrors, _ := response["errors"].([]interface{})
+	if len(scanErrors) != 1 || !strings.HasPrefix(scanErrors[0].(string), "Flag:") {
+		t.Errorf("expected only the flag detector failure, got %v", response["errors"])
+	}
+}
+
+// TestScanSymbolPatternsInvalidParams tests that invalid scan parameters are rejected before detection
+func TestScanSymbolPatternsInvalidParams(t *testing.T) {
+	h, _ := newTestPatternsHandler(&mockPatternStore{}, &mockDetectors{})
+
+	w := serve("POST", "/patterns/scan/:symbol", "/patterns/scan/AAPL?lookback_days=-3", "", h.ScanSymbolPatterns)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+// TestScanAllPatternsQueuesWatchlist tests that a watchlist scan needs a job queue and runs every watched symbol
+func TestScanAllPatternsQueuesWatchlist(t *testing.T) {
+	store := &mockPatternStore{watched: []string{"AAPL", "MSFT"}}
+	h, _ := newTestPatternsHandler(store, &mockDetectors{flag: &models.FlagPattern{PatternType: "bull_flag"}})
+
+	if w := serve("POST", "/patterns/scan", "/patterns/scan", "", h.ScanAllPatterns); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without a job queue, got %d", w.Code)
+	}
+
+	jobs := &mockJobQueue{}
+	h.SetJobService(jobs)
+	w := serve("POST", "/patterns/scan", "/patterns/scan", "", h.ScanAllPatterns)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(jobs.results) != 2 {
+		t.Fatalf("expected both watched symbols scanned, got %v", jobs.results)
+	}
+	if result, ok := jobs.results["MSFT"].(*symbolScanResult); !ok || !result.Flag || result.PatternsFound != 1 {
+		t.Errorf("expected MSFT's flag to be found, got %+v", jobs.results["MSFT"])
+	}
+}
+
+// TestGetAllPatternsMergesFamilies tests that patterns of every family are merged newest first and paginated
+func TestGetAllPatternsMergesFamilies(t *testing.T) {
+	now := time.Now()
+	store := &mockPatternStore{
+		hs:        []*models.HeadShouldersPattern{{ID: 1, Symbol: "AAPL", DetectedAt: now.Add(-3 * time.Hour)}},
+		wedges:    []*models.FallingWedgePattern{{ID: 2, Symbol: "AAPL", PatternType: models.PatternFallingWedge, DetectedAt: now.Add(-time.Hour)}},
+		triangles: []*models.TrianglePattern{{ID: 3, Symbol: "MSFT", DetectedAt: now.Add(-2 * time.Hour)}},
+		flags:     []*models.FlagPattern{{ID: 4, Symbol: "AAPL", DetectedAt: now}},
+	}
+	h, _ := newTestPatternsHandler(store, &mockDetectors{})
+
+	w := serve("GET", "/patterns/", "/patterns/?symbol=AAPL&limit=2", "", h.GetAllPatterns)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	response := decode[struct {
+		Items      []models.PatternListItem `json:"items"`
+		Pagination models.Pagination        `json:"pagination"`
+	}](t, w)
+
+	if len(response.Items) != 2 {
+		t.Fatalf("expected a page of 2 patterns, got %+v", response.Items)
+	}
+	if response.Items[0].ID != 4 || response.Items[1].ID != 2 {
+		t.Errorf("expected the flag then the wedge, newest first, got %+v", response.Items)
+	}
+	if response.Pagination.Total != 3 || !response.Pagination.HasMore {
+		t.Errorf("expected 3 AAPL patterns with more to page through, got %+v", response.Pagination)
+	}
+}
+
+// TestPatternLinks tests that listed patterns carry their links and the links are listed per symbol
+func TestPatternLinks(t *testing.T) {
+	store := &mockPatternStore{
+		hs:     []*models.HeadShouldersPattern{{ID: 1, Symbol: "AAPL", SetupID: 5}},
+		wedges: []*models.FallingWedgePattern{{ID: 1, Symbol: "AAPL", SetupID: 6}},
+		flags:  []*models.FlagPattern{{ID: 2, Symbol: "AAPL"}},
+		links: []*models.PatternLink{
+			{Symbol: "AAPL", Family: "head_shoulders", PatternID: 1, LinkedFamily: "falling_wedge", LinkedPatternID: 1, KeptSetupID: 6, SupersededSetupID: 5},
+			{Symbol: "MSFT", Family: "triangle", PatternID: 3, LinkedFamily: "flag", LinkedPatternID: 4},
+		},
+	}
+	h, _ := newTestPatternsHandler(store, &mockDetectors{})
+
+	response := decode[struct {
+		Items []models.PatternListItem `json:"items"`
+	}](t, serve("GET", "/patterns/", "/patterns/?symbol=AAPL", "", h.GetAllPatterns))
+	for _, item := range response.Items {
+		if linked := item.PatternFamily != "flag"; linked != (len(item.Links) == 1) {
+			t.Errorf("%s #%d: unexpected links %+v", item.PatternFamily, item.ID, item.Links)
+		}
+	}
+
+	links := decode[struct {
+		Links []models.PatternLink `json:"links"`
+	}](t, serve("GET", "/patterns/links", "/patterns/links?symbol=msft", "", h.GetPatternLinks))
+	if len(links.Links) != 1 || links.Links[0].Family != "triangle" {
+		t.Errorf("expected the MSFT link, got %+v", links.Links)
+	}
+}
+
+// TestGetAllPatternsStoreError tests that a storage failure is a server error
+func TestGetAllPatternsStoreError(t *testing.T) {
+	h, _ := newTestPatternsHandler(&mockPatternStore{err: errors.New("database locked")}, &mockDetectors{})
+
+	if w := serve("GET", "/patterns/", "/patterns/", "", h.GetAllPatterns); w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d", w.Code)
+	}
+}
+
+// TestMonitorPatterns tests that monitoring runs through the scheduler and reports its errors
+func TestMonitorPatterns(t *testing.T) {
+	h, scheduler := newTestPatternsHandler(&mockPatternStore{}, &mockDetectors{})
+	scheduler.monitorErr = errors.New("flag monitoring failed")
+
+	w := serve("POST", "/patterns/monitor", "/patterns/monitor", "", h.MonitorPatterns)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if scheduler.monitorRuns != 1 {
+		t.Errorf("expected one monitoring run, got %d", scheduler.monitorRuns)
+	}
+	if !strings.Contains(w.Body.String(), "flag monitoring failed") {
+		t.Errorf("expected the monitoring error in the response, got %s", w.Body.String())
+	}
+}
+
+// TestGetPatternChart tests the chart image, unknown pattern and unavailable renderer responses
+func TestGetPatternChart(t *testing.T) {
+	h, _ := newTestPatternsHandler(&mockPatternStore{}, &mockDetectors{})
+
+	if w := serve("GET", "/patterns/:symbol/chart.png", "/patterns/7/chart.png", "", h.GetPatternChart); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without a renderer, got %d", w.Code)
+	}
+
+	h.SetChartService(&mockChartRenderer{id: 7, chart: []byte("\x89PNG")})
+	tests := []struct {
+		target string
+		status int
+	}{
+		{"/patterns/7/chart.png", http.StatusOK},
+		{"/patterns/8/chart.png", http.StatusNotFound},
+		{"/patterns/7/chart.png?type=pennant", http.StatusBadRequest},
+		{"/patterns/abc/chart.png", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := serve("GET", "/patterns/:symbol/chart.png", tt.target, "", h.GetPatternChart)
+		if w.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d", tt.target, tt.status, w.Code)
+		}
+		if tt.status == http.StatusOK && w.Header().Get("Content-Type") != "image/png" {
+			t.Errorf("%s: expected a PNG, got %q", tt.target, w.Header().Get("Content-Type"))
+		}
+	}
+}
+
+// TestGetPatternTimeline tests the timeline, unknown pattern and invalid family responses
+func TestGetPatternTimeline(t *testing.T) {
+	h, _ := newTestPatternsHandler(&mockPatternStore{}, &mockDetectors{})
+
+	if w := serve("GET", "/patterns/:symbol/timeline", "/patterns/7/timeline", "", h.GetPatternTimeline); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without a timeline service, got %d", w.Code)
+	}
+
+	h.SetTimelineService(&mockPatternTimelines{id: 7})
+	tests := []struct {
+		target string
+		status int
+	}{
+		{"/patterns/7/timeline?type=flag", http.StatusOK},
+		{"/patterns/8/timeline", http.StatusNotFound},
+		{"/patterns/7/timeline?type=pennant", http.StatusBadRequest},
+		{"/patterns/abc/timeline", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := serve("GET", "/patterns/:symbol/timeline", tt.target, "", h.GetPatternTimeline)
+		if w.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d", tt.target, tt.status, w.Code)
+		}
+	}
+
+	w := serve("GET", "/patterns/:symbol/timeline", "/patterns/7/timeline?type=flag", "", h.GetPatternTimeline)
+	if timeline := decode[models.PatternTimeline](t, w); timeline.Family != "flag" || len(timeline.Events) != 1 {
+		t.Errorf("expected the flag pattern's timeline, got %+v", timeline)
+	}
+}
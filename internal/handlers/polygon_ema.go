@@ -18,25 +18,25 @@ func PolygonEMAHandler(emaService *services.PolygonEMAService) gin.HandlerFunc {
 		limitStr := c.DefaultQuery("limit", "1")
 
 		if symbol == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "symbol is required"})
+			respondInvalid(c, "symbol is required", nil)
 			return
 		}
 
 		window, err := strconv.Atoi(windowStr)
 		if err != nil || window <= 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid window parameter"})
+			respondInvalid(c, "invalid window parameter", nil)
 			return
 		}
 
 		limit, err := strconv.Atoi(limitStr)
 		if err != nil || limit <= 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit parameter"})
+			respondInvalid(c, "invalid limit parameter", nil)
 			return
 		}
 
-		resp, err := emaService.GetEMA(symbol, window, timespan, limit)
+		resp, err := emaService.GetEMA(c.Request.Context(), symbol, window, timespan, limit)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch EMA", "details": err.Error()})
+			respondError(c, "failed to fetch EMA", err)
 			return
 		}
 
@@ -53,7 +53,7 @@ func PolygonEMABatchHandler(emaService *services.PolygonEMAService) gin.HandlerF
 		limitStr := c.DefaultQuery("limit", "1")
 
 		if symbol == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "symbol is required"})
+			respondInvalid(c, "symbol is required", nil)
 			return
 		}
 
@@ -62,7 +62,7 @@ func PolygonEMABatchHandler(emaService *services.PolygonEMAService) gin.HandlerF
 		for _, part := range windowParts {
 			w, err := strconv.Atoi(strings.TrimSpace(part))
 			if err != nil || w <= 0 {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid windows parameter"})
+				respondInvalid(c, "invalid windows parameter", nil)
 				return
 			}
 			windows = append(windows, w)
@@ -70,13 +70,13 @@ func PolygonEMABatchHandler(emaService *services.PolygonEMAService) gin.HandlerF
 
 		limit, err := strconv.Atoi(limitStr)
 		if err != nil || limit <= 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit parameter"})
+			respondInvalid(c, "invalid limit parameter", nil)
 			return
 		}
 
-		resp, err := emaService.GetEMABatch(symbol, windows, timespan, limit)
+		resp, err := emaService.GetEMABatch(c.Request.Context(), symbol, windows, timespan, limit)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch EMA batch", "details": err.Error()})
+			respondError(c, "failed to fetch EMA batch", err)
 			return
 		}
 
@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PortfolioHandler handles portfolio API endpoints
+type PortfolioHandler struct {
+	db               *database.Database
+	portfolioService *services.PortfolioService
+}
+
+// NewPortfolioHandler creates a new portfolio handler
+func NewPortfolioHandler(db *database.Database, portfolioService *services.PortfolioService) *PortfolioHandler {
+	return &PortfolioHandler{
+		db:               db,
+		portfolioService: portfolioService,
+	}
+}
+
+// openPositionRequest is the request body for recording a new position
+type openPositionRequest struct {
+	SetupID     *int64     `json:"setup_id"`
+	Symbol      string     `json:"symbol"`
+	Side        string     `json:"side"`
+	Quantity    float64    `json:"quantity"`
+	EntryPrice  float64    `json:"entry_price"`
+	EntryTime   *time.Time `json:"entry_time"`
+	StopLoss    float64    `json:"stop_loss"`
+	TargetPrice float64    `json:"target_price"`
+	Notes       string     `json:"notes"`
+}
+
+// closePositionRequest is the request body for closing a position
+type closePositionRequest struct {
+	Quantity  float64    `json:"quantity"`
+	ExitPrice float64    `json:"exit_price"`
+	ExitTime  *time.Time `json:"exit_time"`
+}
+
+// GetPortfolio godoc
+// @Summary Get portfolio overview
+// @Description Get the portfolio summary with open positions valued at the latest prices
+// @Tags portfolio
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/portfolio [get]
+func (h *PortfolioHandler) GetPortfolio(c *gin.Context) {
+	summary, err := h.portfolioService.GetSummary()
+	if err != nil {
+		respondError(c, "Failed to get portfolio summary", err)
+		return
+	}
+
+	positions, err := h.portfolioService.GetPositions(&models.PositionFilter{Status: models.PositionStatusOpen})
+	if err != nil {
+		respondError(c, "Failed to get open positions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"summary":        summary,
+		"open_positions": positions,
+	})
+}
+
+// GetPositions godoc
+// @Summary List positions
+// @Tags portfolio
+// @Produce json
+// @Param status query string false "Filter by status: 'open' or 'closed'"
+// @Param symbol query string false "Filter by symbol"
+// @Param setup_type query string false "Filter by originating setup type"
+// @Param limit query int false "Maximum number of positions"
+// @Success 200 {array} models.Position
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/portfolio/positions [get]
+func (h *PortfolioHandler) GetPositions(c *gin.Context) {
+	filter := &models.PositionFilter{
+		Status:    c.Query("status"),
+		Symbol:    strings.ToUpper(c.Query("symbol")),
+		SetupType: c.Query("setup_type"),
+	}
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 {
+		filter.Limit = limit
+	}
+
+	positions, err := h.portfolioService.GetPositions(filter)
+	if err != nil {
+		respondError(c, "Failed to get positions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"positions": positions,
+		"count":     len(positions),
+	})
+}
+
+// OpenPosition godoc
+// @Summary Record a new position
+// @Description Record an entry, optionally on a detected setup. Setup levels are used for omitted fields and the setup is marked triggered.
+// @Tags portfolio
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Position
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/portfolio/positions [post]
+func (h *PortfolioHandler) OpenPosition(c *gin.Context) {
+	var request openPositionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalid(c, "Invalid request body", err)
+		return
+	}
+
+	position := &models.Position{
+		SetupID:     request.SetupID,
+		Symbol:      request.Symbol,
+		Side:        request.Side,
+		Quantity:    request.Quantity,
+		EntryPrice:  request.EntryPrice,
+		StopLoss:    request.StopLoss,
+		TargetPrice: request.TargetPrice,
+		Notes:       request.Notes,
+	}
+	if request.EntryTime != nil {
+		position.EntryTime = *request.EntryTime
+	}
+
+	if err := h.portfolioService.OpenPosition(position); err != nil {
+		respondInvalid(c, "Failed to open position", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, position)
+}
+
+// GetPosition godoc
+// @Summary Get a position
+// @Tags portfolio
+// @Produce json
+// @Param id path int true "Position ID"
+// @Success 200 {object} models.Position
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/portfolio/positions/{id} [get]
+func (h *PortfolioHandler) GetPosition(c *gin.Context) {
+	id, ok := parsePositionID(c)
+	if !ok {
+		return
+	}
+
+	position, err := h.portfolioService.GetPosition(id)
+	if err != nil {
+		respondError(c, "Failed to get position", err)
+		return
+	}
+	if position == nil {
+		respondNotFound(c, "Position not found", nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, position)
+}
+
+// ClosePosition godoc
+// @Summary Close a position
+// @Description Close an open position. Without exit_price the latest collected price is used. A quantity below the open quantity sells part of it: the sold part is returned as its own closed position and the rest stays open.
+// @Tags portfolio
+// @Accept json
+// @Produce json
+// @Param id path int true "Position ID"
+// @Success 200 {object} models.Position
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/portfolio/positions/{id}/close [post]
+func (h *PortfolioHandler) ClosePosition(c *gin.Context) {
+	id, ok := parsePositionID(c)
+	if !ok {
+		return
+	}
+
+	var request closePositionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			respondInvalid(c, "Invalid request body", err)
+			return
+		}
+	}
+
+	var exitTime time.Time
+	if request.ExitTime != nil {
+		exitTime = *request.ExitTime
+	}
+
+	position, err := h.portfolioService.ClosePosition(id, request.Quantity, request.ExitPrice, exitTime)
+	if err != nil {
+		respondInvalid(c, "Failed to close position", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, position)
+}
+
+// DeletePosition godoc
+// @Summary Delete a position
+// @Tags portfolio
+// @Produce json
+// @Param id path int true "Position ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/portfolio/positions/{id} [delete]
+func (h *PortfolioHandler) DeletePosition(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	id, ok := parsePositionID(c)
+	if !ok {
+		return
+	}
+
+	if err := db.DeletePosition(id); err != nil {
+		respondError(c, "Failed to delete position", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Position deleted",
+		"id":      id,
+	})
+}
+
+// GetPerformance godoc
+// @Summary Get performance by setup type
+// @Description Attribute realized and unrealized P&L to the setup or pattern type that generated each trade
+// @Tags portfolio
+// @Produce json
+// @Success 200 {array} models.SetupPerformance
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/portfolio/performance [get]
+func (h *PortfolioHandler) GetPerformance(c *gin.Context) {
+	performance, err := h.portfolioService.GetPerformanceBySetupType()
+	if err != nil {
+		respondError(c, "Failed to get performance", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"by_setup_type": performance,
+	})
+}
+
+// parsePositionID parses the id path parameter, writing a bad request response on failure
+func parsePositionID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondInvalid(c, "Invalid position ID", nil)
+		return 0, false
+	}
+	return id, true
+}
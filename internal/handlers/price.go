@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"fmt"
+	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,32 +18,59 @@ import (
 type PriceHandler struct {
 	db        *database.DB
 	collector *services.CollectorService
-	polygon   *services.PolygonService
+	provider  services.MarketDataProvider
+	calendar  *services.MarketCalendar
+	display   *services.DisplayService
 }
 
 // NewPriceHandler creates a new price handler
-func NewPriceHandler(db *database.DB, collector *services.CollectorService, polygon *services.PolygonService) *PriceHandler {
+func NewPriceHandler(db *database.DB, collector *services.CollectorService, provider services.MarketDataProvider) *PriceHandler {
 	return &PriceHandler{
 		db:        db,
 		collector: collector,
-		polygon:   polygon,
+		provider:  provider,
 	}
 }
 
+// Chart data limits and styles
+const (
+	chartDefaultMaxPoints = 2000
+	chartMinPoints        = 100
+	chartMaxPoints        = 10000
+
+	chartStyleCandles = "candles"
+	chartStyleLine    = "line"
+)
+
+// SetMarketCalendar sets the trading calendar used to keep chart data to regular sessions
+func (ph *PriceHandler) SetMarketCalendar(calendar *services.MarketCalendar) {
+	ph.calendar = calendar
+}
+
+// SetDisplayService sets the time zone chart and stats responses are localized to by default
+func (ph *PriceHandler) SetDisplayService(display *services.DisplayService) {
+	ph.display = display
+}
+
 // GetPriceData handles GET /api/price/:symbol - returns OHLC price data for TradingView
 func (ph *PriceHandler) GetPriceData(c *gin.Context) {
+	db := withRequestContext(c, ph.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
 		return
 	}
 
 	// Parse query parameters
 	rangeStr := c.DefaultQuery("range", "1W")
 
+	timeframe, err := models.ParseTimeframe(c.Query("timeframe"))
+	if err != nil {
+		respondInvalid(c, "", err)
+		return
+	}
+
 	var from, to time.Time
 	now := time.Now()
 
@@ -62,51 +92,68 @@ func (ph *PriceHandler) GetPriceData(c *gin.Context) {
 		to = now
 	}
 
-	// Get data from database
-	filter := &models.PriceDataFilter{
-		Symbol: symbol,
-		From:   from,
-		To:     to,
-		Limit:  1000,
+	// An explicit from/to replaces the range, for exporting longer histories
+	if c.Query("from") != "" || c.Query("to") != "" {
+		from, to, err = services.ParseExportRange(c.Query("from"), c.Query("to"))
+		if err != nil {
+			respondInvalid(c, "", err)
+			return
+		}
+		rangeStr = "custom"
 	}
 
-	data, err := ph.db.GetPriceData(filter)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "database_error",
-			Message: "Failed to retrieve price data",
-		})
+	if notModified(c, db.GetPriceDataVersion, symbol, isHistorical(to)) {
 		return
 	}
 
-	// Convert to TradingView candlestick format
-	candleData := make([]models.TradingViewCandle, 0)
-	for _, pd := range data {
-		candle := pd.ToTradingViewCandle()
-		candleData = append(candleData, candle)
+	// Get data from database
+	filter := &models.PriceDataFilter{
+		Symbol:    symbol,
+		From:      from,
+		To:        to,
+		Timeframe: timeframe,
 	}
 
-	// Create response
-	response := models.PriceDataResponse{
-		Symbol:       symbol,
-		Data:         candleData,
-		TotalRecords: len(candleData),
-		From:         from,
-		To:           to,
-		Interval:     rangeStr,
+	// Candles are encoded as they are read, so months of bars don't have to be held in memory;
+	// the response has the fields of models.PriceDataResponse
+	stream := newJSONStream(c)
+	stream.Field("symbol", symbol)
+	stream.Field("from", from)
+	stream.Field("to", to)
+	stream.Field("interval", rangeStr)
+	stream.BeginArray("data")
+
+	err = db.EachPriceData(filter, func(pd *models.PriceData) error {
+		return stream.Item(pd.ToTradingViewCandle())
+	})
+	if err != nil && !stream.Started() {
+		respondError(c, "Failed to retrieve price data", err)
+		return
+	}
+	if err != nil {
+		log.Printf("Price data response for %s cut short: %v", symbol, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	total, _ := stream.EndArray()
+	stream.Field("total_records", total)
+	stream.Close()
 }
 
-// GetPriceChartData handles GET /api/price/:symbol/chart - returns TradingView compatible chart data
+// GetPriceChartData handles GET /api/price/:symbol/chart - returns TradingView compatible chart data. Times
+// are localized to the tz parameter or the display setting, and the regular sessions in the range are listed
+// with their exchange trading day so bars can be grouped by it.
 func (ph *PriceHandler) GetPriceChartData(c *gin.Context) {
+	db := withRequestContext(c, ph.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
+		return
+	}
+
+	loc, ok := responseLocation(c, ph.display)
+	if !ok {
 		return
 	}
 
@@ -134,121 +181,174 @@ func (ph *PriceHandler) GetPriceChartData(c *gin.Context) {
 		to = now
 	}
 
+	maxPoints, err := strconv.Atoi(c.DefaultQuery("max_points", strconv.Itoa(chartDefaultMaxPoints)))
+	if err != nil || maxPoints < chartMinPoints || maxPoints > chartMaxPoints {
+		respondInvalid(c, fmt.Sprintf("max_points must be between %d and %d", chartMinPoints, chartMaxPoints), err)
+		return
+	}
+
+	style := c.DefaultQuery("style", chartStyleCandles)
+	if style != chartStyleCandles && style != chartStyleLine {
+		respondInvalid(c, fmt.Sprintf("Invalid style %q: must be candles or line", style), nil)
+		return
+	}
+
+	// Candles are rolled up to fit max_points unless a timeframe is requested; lines are downsampled from
+	// 1-minute bars instead, which keeps their shape better than coarser candles
+	timeframe, err := models.ParseTimeframe(c.Query("timeframe"))
+	if err != nil {
+		respondInvalid(c, "", err)
+		return
+	}
+	if c.Query("timeframe") == "" && style == chartStyleCandles {
+		timeframe = services.ChartTimeframe(to.Sub(from), maxPoints)
+	}
+
+	if notModified(c, db.GetPriceDataVersion, symbol, false) {
+		return
+	}
+
 	// Get data from database
 	filter := &models.PriceDataFilter{
-		Symbol: symbol,
-		From:   from,
-		To:     to,
-		Limit:  1000,
+		Symbol:    symbol,
+		From:      from,
+		To:        to,
+		Timeframe: timeframe,
 	}
 
-	data, err := ph.db.GetPriceData(filter)
+	data, err := db.GetPriceData(filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "database_error",
-			Message: "Failed to retrieve price data",
-		})
+		respondError(c, "Failed to retrieve price data", nil)
 		return
 	}
 
 	// Just return whatever data we have in the database
 	// The collector service handles data collection in the background
 
+	resolution := &models.ChartResolution{Timeframe: timeframe, Method: models.DecimationNone, MaxPoints: maxPoints}
+	if timeframe.IsAggregated() {
+		resolution.Method = models.DecimationAggregate
+	}
+
 	// Convert to simple format suitable for TradingView Lightweight Charts
 	chartData := make([]models.TradingViewCandle, 0)
 	for _, pd := range data {
-		// Filter trading hours (9:30 AM to 4:00 PM ET)
-		hour := pd.Timestamp.Hour()
-		minute := pd.Timestamp.Minute()
-		dayOfWeek := pd.Timestamp.Weekday()
-
-		// Skip weekends
-		if dayOfWeek == time.Saturday || dayOfWeek == time.Sunday {
-			continue
-		}
-
-		// Skip non-trading hours
-		if hour < 9 || (hour == 9 && minute < 30) || hour > 16 {
+		// Keep regular session bars only, skipping weekends, holidays and extended hours
+		if !ph.inRegularSession(pd.Timestamp, timeframe) {
 			continue
 		}
 
 		candle := pd.ToTradingViewCandle()
 		chartData = append(chartData, candle)
 	}
+	resolution.SourcePoints = len(chartData)
+
+	var points interface{} = chartData
+	resolution.Points = len(chartData)
+	if style == chartStyleLine {
+		line := make([]models.TradingViewLinePoint, len(chartData))
+		for i, candle := range chartData {
+			line[i] = models.TradingViewLinePoint{Time: candle.Time, Value: candle.Close}
+		}
+		if len(line) > maxPoints {
+			line = services.DownsampleLTTB(line, maxPoints)
+			resolution.Method = models.DecimationLTTB
+		}
+		points = line
+		resolution.Points = len(line)
+	}
+
+	// Regular session boundaries, in the response's time zone
+	sessions := []models.TradingSession{}
+	if ph.calendar != nil {
+		sessions = ph.calendar.RegularSessions(from, to)
+		for i := range sessions {
+			sessions[i].Open = sessions[i].Open.In(loc)
+			sessions[i].Close = sessions[i].Close.In(loc)
+		}
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"symbol": symbol,
-		"data":   chartData,
-		"range":  rangeStr,
-		"from":   from,
-		"to":     to,
+		"symbol":     symbol,
+		"data":       points,
+		"style":      style,
+		"range":      rangeStr,
+		"from":       from.In(loc),
+		"to":         to.In(loc),
+		"timezone":   loc.String(),
+		"sessions":   sessions,
+		"resolution": resolution,
 	})
 }
 
+// inRegularSession reports whether a chart bar falls in the regular session. Rolled up candles are kept when
+// any of their minutes does, and daily candles on trading days. Without a calendar every bar is kept.
+func (ph *PriceHandler) inRegularSession(ts time.Time, timeframe models.Timeframe) bool {
+	if ph.calendar == nil {
+		return true
+	}
+	if timeframe == models.Timeframe1d {
+		return ph.calendar.IsTradingDay(ts)
+	}
+	return ph.calendar.IsMarketHours(ts) || ph.calendar.IsMarketHours(ts.Add(timeframe.Duration()-time.Minute))
+}
+
 // GetLatestPriceData handles GET /api/price/:symbol/latest
 func (ph *PriceHandler) GetLatestPriceData(c *gin.Context) {
+	db := withRequestContext(c, ph.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
 		return
 	}
 
 	symbol = strings.ToUpper(symbol)
 
 	// Get latest data from database
-	data, err := ph.db.GetLatestPriceData(symbol)
+	data, err := db.GetLatestPriceData(symbol)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "database_error",
-			Message: "Failed to retrieve latest price data",
-		})
+		respondError(c, "Failed to retrieve latest price data", nil)
 		return
 	}
 
 	if data == nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Error:   "not_found",
-			Message: "No price data found for symbol",
-		})
+		respondNotFound(c, "No price data found for symbol", nil)
 		return
 	}
 
 	c.JSON(http.StatusOK, data)
 }
 
-// GetPriceStats handles GET /api/price/:symbol/stats
+// GetPriceStats handles GET /api/price/:symbol/stats, with the last update localized like chart data
 func (ph *PriceHandler) GetPriceStats(c *gin.Context) {
+	db := withRequestContext(c, ph.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
+		return
+	}
+
+	loc, ok := responseLocation(c, ph.display)
+	if !ok {
 		return
 	}
 
 	symbol = strings.ToUpper(symbol)
 
 	// Get price statistics
-	stats, err := ph.db.GetPriceStats(symbol, 1) // Get stats for today
+	stats, err := db.GetPriceStats(symbol, 1) // Get stats for today
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "database_error",
-			Message: "Failed to retrieve price statistics",
-		})
+		respondError(c, "Failed to retrieve price statistics", nil)
 		return
 	}
 
 	if stats == nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Error:   "not_found",
-			Message: "No price statistics available for symbol",
-		})
+		respondNotFound(c, "No price statistics available for symbol", nil)
 		return
 	}
+	stats.LastUpdate = stats.LastUpdate.In(loc)
 
 	c.JSON(http.StatusOK, stats)
 }
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReportsHandler handles the digest report API endpoints
+type ReportsHandler struct {
+	digestService *services.DigestService
+}
+
+// NewReportsHandler creates a new reports handler
+func NewReportsHandler(digestService *services.DigestService) *ReportsHandler {
+	return &ReportsHandler{
+		digestService: digestService,
+	}
+}
+
+// GetReport godoc
+// @Summary Get a digest report
+// @Description Compile the digest emailed on a schedule: new high-quality setups, pattern activity, triggered alerts and collection health since the previous trading day (daily) or over the last seven days (weekly)
+// @Tags reports
+// @Produce json
+// @Param period path string true "Report period: daily, weekly"
+// @Success 200 {object} models.DigestReport
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/reports/{period} [get]
+func (h *ReportsHandler) GetReport(c *gin.Context) {
+	period := c.Param("period")
+	if !models.IsDigestPeriod(period) {
+		respondInvalid(c, "Period must be daily or weekly", nil)
+		return
+	}
+
+	report, err := h.digestService.Build(period)
+	if err != nil {
+		respondError(c, "Failed to build report", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScreenerHandler handles market screener API endpoints
+type ScreenerHandler struct {
+	db              *database.Database
+	screenerService *services.ScreenerService
+}
+
+// NewScreenerHandler creates a new screener handler
+func NewScreenerHandler(db *database.Database, screenerService *services.ScreenerService) *ScreenerHandler {
+	return &ScreenerHandler{
+		db:              db,
+		screenerService: screenerService,
+	}
+}
+
+// runScreenRequest is the request body for running a screen
+type runScreenRequest struct {
+	ScreenID *int64                 `json:"screen_id"`
+	Criteria *models.ScreenCriteria `json:"criteria"`
+}
+
+// watchRequest is the request body for adding a screener result to the watchlist
+type watchRequest struct {
+	Symbol     string `json:"symbol"`
+	StrategyID int    `json:"strategy_id"`
+}
+
+// RunScreen godoc
+// @Summary Run a screen
+// @Description Scan the market or the watchlist with a saved screen or inline criteria
+// @Tags screener
+// @Accept json
+// @Produce json
+// @Param request body runScreenRequest true "Saved screen ID or criteria"
+// @Success 200 {object} models.ScreenerRun
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/screener/run [post]
+func (h *ScreenerHandler) RunScreen(c *gin.Context) {
+	var request runScreenRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalid(c, "Invalid request body", err)
+		return
+	}
+
+	var run *models.ScreenerRun
+	var err error
+	switch {
+	case request.ScreenID != nil:
+		run, err = h.screenerService.RunSavedScreen(*request.ScreenID)
+	case request.Criteria != nil:
+		run, err = h.screenerService.Run(request.Criteria)
+	default:
+		respondInvalid(c, "Either screen_id or criteria is required", nil)
+		return
+	}
+
+	if err != nil {
+		respondInvalid(c, "Failed to run screen", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// GetScreens godoc
+// @Summary List saved screens
+// @Tags screener
+// @Produce json
+// @Success 200 {array} models.SavedScreen
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/screener/screens [get]
+func (h *ScreenerHandler) GetScreens(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	screens, err := db.GetSavedScreens()
+	if err != nil {
+		respondError(c, "Failed to get saved screens", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"screens": screens,
+		"count":   len(screens),
+	})
+}
+
+// CreateScreen godoc
+// @Summary Save a screen
+// @Tags screener
+// @Accept json
+// @Produce json
+// @Param screen body models.SavedScreen true "Screen definition"
+// @Success 201 {object} models.SavedScreen
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/screener/screens [post]
+func (h *ScreenerHandler) CreateScreen(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	var screen models.SavedScreen
+	if !bindScreen(c, &screen) {
+		return
+	}
+
+	if err := db.InsertSavedScreen(&screen); err != nil {
+		respondInvalid(c, "Failed to save screen", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, screen)
+}
+
+// UpdateScreen godoc
+// @Summary Update a saved screen
+// @Tags screener
+// @Accept json
+// @Produce json
+// @Param id path int true "Screen ID"
+// @Param screen body models.SavedScreen true "Screen definition"
+// @Success 200 {object} models.SavedScreen
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/screener/screens/{id} [put]
+func (h *ScreenerHandler) UpdateScreen(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	id, ok := parseScreenID(c)
+	if !ok {
+		return
+	}
+
+	var screen models.SavedScreen
+	if !bindScreen(c, &screen) {
+		return
+	}
+	screen.ID = id
+
+	if err := db.UpdateSavedScreen(&screen); err != nil {
+		respondInvalid(c, "Failed to update screen", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, screen)
+}
+
+// DeleteScreen godoc
+// @Summary Delete a saved screen
+// @Tags screener
+// @Produce json
+// @Param id path int true "Screen ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/screener/screens/{id} [delete]
+func (h *ScreenerHandler) DeleteScreen(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	id, ok := parseScreenID(c)
+	if !ok {
+		return
+	}
+
+	if err := db.DeleteSavedScreen(id); err != nil {
+		respondError(c, "Failed to delete saved screen", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Screen deleted",
+	})
+}
+
+// AddToWatchlist godoc
+// @Summary Add a screener result to the watchlist
+// @Description Add the symbol to a watchlist strategy and start collecting its data
+// @Tags screener
+// @Accept json
+// @Produce json
+// @Param request body watchRequest true "Symbol and strategy"
+// @Success 201 {object} models.Stock
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/screener/watch [post]
+func (h *ScreenerHandler) AddToWatchlist(c *gin.Context) {
+	var request watchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalid(c, "Invalid request body", err)
+		return
+	}
+
+	stock, err := h.screenerService.AddToWatchlist(request.Symbol, request.StrategyID)
+	if err != nil {
+		respondInvalid(c, "Failed to add to watchlist", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, stock)
+}
+
+// bindScreen binds and validates a screen definition, writing a bad request response on failure
+func bindScreen(c *gin.Context, screen *models.SavedScreen) bool {
+	if err := c.ShouldBindJSON(screen); err != nil {
+		respondInvalid(c, "Invalid request body", err)
+		return false
+	}
+
+	screen.Name = strings.TrimSpace(screen.Name)
+	if screen.Name == "" {
+		respondInvalid(c, "Screen name is required", nil)
+		return false
+	}
+
+	if err := screen.Criteria.Validate(); err != nil {
+		respondInvalid(c, "", err)
+		return false
+	}
+
+	return true
+}
+
+// parseScreenID parses the id path parameter, writing a bad request response on failure
+func parseScreenID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondInvalid(c, "Invalid screen ID", nil)
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SettingsHandler handles the runtime settings API endpoints
+type SettingsHandler struct {
+	settings *services.SettingsService
+}
+
+// NewSettingsHandler creates a new settings handler
+func NewSettingsHandler(settings *services.SettingsService) *SettingsHandler {
+	return &SettingsHandler{
+		settings: settings,
+	}
+}
+
+// GetAllSettings godoc
+// @Summary Get all settings
+// @Description Get the setup scoring, pattern detection, S/R detection and display settings currently in effect, keyed by section
+// @Tags settings
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/settings [get]
+func (h *SettingsHandler) GetAllSettings(c *gin.Context) {
+	all := make(map[string]interface{})
+	for _, section := range models.SettingsSections {
+		value, err := h.settings.Get(section)
+		if err != nil {
+			respondError(c, "", err)
+			return
+		}
+		all[section] = value
+	}
+
+	c.JSON(http.StatusOK, all)
+}
+
+// GetSettings godoc
+// @Summary Get settings section
+// @Description Get one settings section currently in effect: setups (models.SetupScoringConfig), patterns (models.PatternSettings), sr (models.SRDetectionConfig) or display (models.DisplaySettings)
+// @Tags settings
+// @Produce json
+// @Param section path string true "Settings section" Enums(setups, patterns, sr, display)
+// @Success 200 {object} object
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/settings/{section} [get]
+func (h *SettingsHandler) GetSettings(c *gin.Context) {
+	section, ok := h.section(c)
+	if !ok {
+		return
+	}
+
+	value, err := h.settings.Get(section)
+	if err != nil {
+		respondError(c, "", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, value)
+}
+
+// UpdateSettings godoc
+// @Summary Update settings section
+// @Description Merge a JSON object into a settings section, then validate, save and apply it without a restart. Fields left out keep their current values; saved settings override the config file on the next start. Setup scoring weights must add up to 100 and durations are in nanoseconds.
+// @Tags settings
+// @Accept json
+// @Produce json
+// @Param section path string true "Settings section" Enums(setups, patterns, sr, display)
+// @Param settings body object true "Fields to change"
+// @Success 200 {object} object
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/settings/{section} [put]
+func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
+	section, ok := h.section(c)
+	if !ok {
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		respondInvalid(c, "Failed to read request body", nil)
+		return
+	}
+
+	value, err := h.settings.Update(section, body)
+	if err != nil {
+		// Invalid settings wrap services.ErrValidation and are answered with 400
+		respondError(c, "Failed to update settings", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, value)
+}
+
+// ResetSettings godoc
+// @Summary Reset settings section
+// @Description Discard a section's saved settings and restore the config file and built-in defaults in effect at startup
+// @Tags settings
+// @Produce json
+// @Param section path string true "Settings section" Enums(setups, patterns, sr, display)
+// @Success 200 {object} object
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/settings/{section} [delete]
+func (h *SettingsHandler) ResetSettings(c *gin.Context) {
+	section, ok := h.section(c)
+	if !ok {
+		return
+	}
+
+	value, err := h.settings.Reset(section)
+	if err != nil {
+		respondError(c, "", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, value)
+}
+
+// section reads the settings section path parameter, responding 404 for unknown sections
+func (h *SettingsHandler) section(c *gin.Context) (string, bool) {
+	section := strings.ToLower(c.Param("section"))
+	if !models.IsSettingsSection(section) {
+		respondNotFound(c, "Unknown settings section, expected one of "+strings.Join(models.SettingsSections, ", "), nil)
+		return "", false
+	}
+	return section, true
+}
@@ -1,13 +1,14 @@
 package handlers
 
 import (
+	"log"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
-	"market-watch-go/internal/database"
 	"market-watch-go/internal/models"
-	"market-watch-go/internal/services"
 	"market-watch-go/internal/utils"
 
 	"github.com/gin-gonic/gin"
@@ -15,21 +16,41 @@ import (
 
 // SetupHandler handles setup detection API endpoints
 type SetupHandler struct {
-	db           *database.Database
-	setupService *services.SetupDetectionService
+	db           SetupStore
+	setupService SetupDetector
+	risk         RiskManager
 }
 
 // NewSetupHandler creates a new setup handler
-func NewSetupHandler(db *database.Database, setupService *services.SetupDetectionService) *SetupHandler {
+func NewSetupHandler(db SetupStore, setupService SetupDetector) *SetupHandler {
 	return &SetupHandler{
 		db:           db,
 		setupService: setupService,
 	}
 }
 
+// SetRiskService sets the risk manager that sizes setups in responses
+func (h *SetupHandler) SetRiskService(risk RiskManager) {
+	h.risk = risk
+}
+
+// applyRisk sizes the setups against the current portfolio heat and returns the heat, or nil without a risk
+// manager. Setups are still sized when the heat can't be computed.
+func (h *SetupHandler) applyRisk(setups []*models.TradingSetup) *models.PortfolioHeat {
+	if h.risk == nil {
+		return nil
+	}
+	heat, err := h.risk.PortfolioHeat()
+	if err != nil {
+		log.Printf("Failed to compute portfolio heat: %v", err)
+	}
+	h.risk.ApplySizing(setups, heat)
+	return heat
+}
+
 // DetectSetups godoc
 // @Summary Detect trading setups for a symbol
-// @Description Analyze market data and detect potential trading setups with scoring
+// @Description Analyze market data and detect potential trading setups with scoring. A setup at the key level of an active setup of the same type refreshes that setup instead of adding another.
 // @Tags setups
 // @Accept json
 // @Produce json
@@ -37,35 +58,42 @@ func NewSetupHandler(db *database.Database, setupService *services.SetupDetectio
 // @Success 200 {object} models.SetupDetectionResult
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/setups/{symbol}/detect [post]
+// @Router /api/v1/setups/{symbol}/detect [post]
 func (h *SetupHandler) DetectSetups(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
 		return
 	}
 
 	// Run setup detection
-	result, err := h.setupService.DetectSetups(symbol)
+	result, err := h.setupService.DetectSetups(c.Request.Context(), symbol)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to detect setups: " + err.Error(),
-		})
+		respondError(c, "Failed to detect setups", err)
 		return
 	}
 
-	// Store detected setups in database
+	// Store detected setups in database, each together with its checklist; setups at the key level of an
+	// active setup of their type refresh it and aren't alerted on again
+	stored := make([]*models.TradingSetup, 0, len(result.SetupsFound))
 	for _, setup := range result.SetupsFound {
-		err := h.db.InsertTradingSetup(setup)
+		refreshed, err := db.StoreDetectedSetup(setup)
 		if err != nil {
+			setup.ID = 0
 			result.Errors = append(result.Errors, "Failed to store setup: "+err.Error())
+			continue
+		}
+		if refreshed {
+			result.Refreshed++
+			continue
 		}
+		stored = append(stored, setup)
 	}
 
+	h.setupService.NotifySetups(stored)
+
 	c.JSON(http.StatusOK, result)
 }
 
@@ -78,22 +106,25 @@ func (h *SetupHandler) DetectSetups(c *gin.Context) {
 // @Param symbol path string true "Stock symbol"
 // @Param setup_type query string false "Setup type filter"
 // @Param direction query string false "Direction filter: 'bullish' or 'bearish'"
-// @Param status query string false "Status filter: 'active', 'triggered', 'expired', 'invalidated'"
+// @Param status query string false "Status filter: 'active', 'triggered', 'completed', 'stopped_out', 'invalidated', 'expired'"
 // @Param min_quality query number false "Minimum quality score"
 // @Param confidence query string false "Confidence filter: 'high', 'medium', 'low'"
 // @Param is_active query boolean false "Filter for active setups only"
-// @Param limit query int false "Maximum number of setups to return"
+// @Param include_archived query boolean false "Include archived setups" default(false)
+// @Param page query int false "Page number, starting at 1" default(1)
+// @Param limit query int false "Setups per page" default(50)
+// @Param sort query string false "Sort field: 'quality_score', 'detected_at', 'expires_at', 'symbol' or 'risk_reward_ratio'"
+// @Param order query string false "Sort order: 'asc' or 'desc'" default(desc)
 // @Success 200 {object} models.SetupResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/setups/{symbol} [get]
+// @Router /api/v1/setups/{symbol} [get]
 func (h *SetupHandler) GetSetups(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
 		return
 	}
 
@@ -103,7 +134,6 @@ func (h *SetupHandler) GetSetups(c *gin.Context) {
 	status := c.Query("status")
 	confidence := c.Query("confidence")
 	minQualityStr := c.DefaultQuery("min_quality", "0")
-	limitStr := c.DefaultQuery("limit", "50")
 	isActiveStr := c.Query("is_active")
 
 	minQuality, err := strconv.ParseFloat(minQualityStr, 64)
@@ -111,9 +141,10 @@ func (h *SetupHandler) GetSetups(c *gin.Context) {
 		minQuality = 0
 	}
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 50
+	page, err := parsePageRequest(c, models.DefaultPageLimit, models.SetupSortFields)
+	if err != nil {
+		respondInvalid(c, "", err)
+		return
 	}
 
 	var isActive *bool
@@ -133,30 +164,39 @@ func (h *SetupHandler) GetSetups(c *gin.Context) {
 		Confidence:      confidence,
 		MinQualityScore: minQuality,
 		IsActive:        isActive,
-		Limit:           limit,
+		IncludeArchived: includeArchived(c),
+		Limit:           page.Limit,
+		Offset:          page.Offset(),
+		Sort:            page.Sort,
+		Order:           page.Order,
 	}
 
 	// Get setups from database
-	setups, err := h.db.GetTradingSetups(filter)
+	setups, err := db.GetTradingSetups(filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get setups: " + err.Error(),
-		})
+		respondError(c, "Failed to get setups", err)
+		return
+	}
+
+	total, err := db.CountTradingSetups(filter)
+	if err != nil {
+		respondError(c, "Failed to count setups", err)
 		return
 	}
 
 	// Get summary
-	summary, err := h.db.GetSetupSummary(symbol)
+	summary, err := db.GetSetupSummary(symbol)
 	if err != nil {
 		summary = &models.SetupSummary{} // Return empty summary on error
 	}
 
 	response := &models.SetupResponse{
-		Symbol:  symbol,
-		Setups:  setups,
-		Summary: summary,
-		Status:  "success",
+		Symbol:     symbol,
+		Setups:     setups,
+		Summary:    summary,
+		Pagination: models.NewPagination(page, total),
+		Heat:       h.applyRisk(setups),
+		Status:     "success",
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -173,45 +213,29 @@ func (h *SetupHandler) GetSetups(c *gin.Context) {
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/setups/id/{id} [get]
+// @Router /api/v1/setups/id/{id} [get]
 func (h *SetupHandler) GetSetupByID(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Invalid setup ID",
-		})
+		respondInvalid(c, "Invalid setup ID", nil)
 		return
 	}
 
-	// Get setup by ID
-	setups, err := h.db.GetTradingSetups(&models.SetupFilter{Limit: 1})
+	targetSetup, err := db.GetTradingSetupByID(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get setup: " + err.Error(),
-		})
+		respondError(c, "Failed to get setup", err)
 		return
 	}
 
-	// Find the specific setup (this is a simplified approach)
-	var targetSetup *models.TradingSetup
-	for _, setup := range setups {
-		if setup.ID == id {
-			targetSetup = setup
-			break
-		}
-	}
-
 	if targetSetup == nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Error:   "Not Found",
-			Message: "Setup not found",
-		})
+		respondNotFound(c, "Setup not found", nil)
 		return
 	}
 
+	h.applyRisk([]*models.TradingSetup{targetSetup})
 	c.JSON(http.StatusOK, targetSetup)
 }
 
@@ -227,15 +251,14 @@ func (h *SetupHandler) GetSetupByID(c *gin.Context) {
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/setups/id/{id}/status [put]
+// @Router /api/v1/setups/id/{id}/status [put]
 func (h *SetupHandler) UpdateSetupStatus(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Invalid setup ID",
-		})
+		respondInvalid(c, "Invalid setup ID", nil)
 		return
 	}
 
@@ -245,52 +268,25 @@ func (h *SetupHandler) UpdateSetupStatus(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Invalid request body: " + err.Error(),
-		})
+		respondInvalid(c, "Invalid request body", err)
 		return
 	}
 
 	// Validate status
-	validStatuses := map[string]bool{
-		"active":      true,
-		"triggered":   true,
-		"expired":     true,
-		"invalidated": true,
-	}
-
-	if !validStatuses[request.Status] {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Invalid status. Must be one of: active, triggered, expired, invalidated",
-		})
+	if !slices.Contains(models.SetupStatuses, request.Status) {
+		respondInvalid(c, "Invalid status. Must be one of: "+strings.Join(models.SetupStatuses, ", "), nil)
 		return
 	}
 
-	// Get existing setup (simplified approach)
-	setups, err := h.db.GetTradingSetups(&models.SetupFilter{Limit: 1000})
+	// Get existing setup
+	targetSetup, err := db.GetTradingSetupByID(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get setup: " + err.Error(),
-		})
+		respondError(c, "Failed to get setup", err)
 		return
 	}
 
-	var targetSetup *models.TradingSetup
-	for _, setup := range setups {
-		if setup.ID == id {
-			targetSetup = setup
-			break
-		}
-	}
-
 	if targetSetup == nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Error:   "Not Found",
-			Message: "Setup not found",
-		})
+		respondNotFound(c, "Setup not found", nil)
 		return
 	}
 
@@ -300,12 +296,9 @@ func (h *SetupHandler) UpdateSetupStatus(c *gin.Context) {
 		targetSetup.Notes = request.Notes
 	}
 
-	err = h.db.UpdateTradingSetup(targetSetup)
+	err = db.UpdateTradingSetup(targetSetup)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to update setup: " + err.Error(),
-		})
+		respondError(c, "Failed to update setup", err)
 		return
 	}
 
@@ -322,7 +315,7 @@ func (h *SetupHandler) UpdateSetupStatus(c *gin.Context) {
 
 // GetMultipleSetups godoc
 // @Summary Get setups for multiple symbols
-// @Description Get trading setups for all watched symbols or a specific list
+// @Description Get a page of trading setups across all watched symbols or a specific list
 // @Tags setups
 // @Accept json
 // @Produce json
@@ -331,32 +324,49 @@ func (h *SetupHandler) UpdateSetupStatus(c *gin.Context) {
 // @Param direction query string false "Direction filter"
 // @Param min_quality query number false "Minimum quality score" default(60)
 // @Param is_active query boolean false "Filter for active setups only" default(true)
-// @Success 200 {object} map[string]models.SetupResponse
+// @Param include_archived query boolean false "Include archived setups" default(false)
+// @Param page query int false "Page number, starting at 1" default(1)
+// @Param limit query int false "Setups per page" default(50)
+// @Param sort query string false "Sort field: 'quality_score', 'detected_at', 'expires_at', 'symbol' or 'risk_reward_ratio'"
+// @Param order query string false "Sort order: 'asc' or 'desc'" default(desc)
+// @Success 200 {object} models.PagedResponse
+// @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/setups [get]
+// @Router /api/v1/setups [get]
 func (h *SetupHandler) GetMultipleSetups(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	symbolsParam := c.Query("symbols")
 	setupType := c.Query("setup_type")
 	direction := c.Query("direction")
 	minQualityStr := c.DefaultQuery("min_quality", "60")
 	isActiveStr := c.DefaultQuery("is_active", "true")
 
-	var symbols []string
-	var err error
+	page, err := parsePageRequest(c, models.DefaultPageLimit, models.SetupSortFields)
+	if err != nil {
+		respondInvalid(c, "", err)
+		return
+	}
 
+	var symbols []string
 	if symbolsParam != "" {
 		symbols = utils.ParseSymbols(symbolsParam)
 	} else {
-		symbols, err = h.db.GetWatchedSymbols()
+		symbols, err = db.GetWatchedSymbols()
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-				Error:   "Internal Server Error",
-				Message: "Failed to get watched symbols: " + err.Error(),
-			})
+			respondError(c, "Failed to get watched symbols", err)
 			return
 		}
 	}
 
+	if len(symbols) == 0 {
+		c.JSON(http.StatusOK, &models.PagedResponse{
+			Items:      []*models.TradingSetup{},
+			Pagination: models.NewPagination(page, 0),
+		})
+		return
+	}
+
 	minQuality, err := strconv.ParseFloat(minQualityStr, 64)
 	if err != nil {
 		minQuality = 60
@@ -370,42 +380,50 @@ func (h *SetupHandler) GetMultipleSetups(c *gin.Context) {
 		}
 	}
 
-	results := make(map[string]*models.SetupResponse)
+	filter := &models.SetupFilter{
+		Symbols:         symbols,
+		SetupType:       setupType,
+		Direction:       direction,
+		MinQualityScore: minQuality,
+		IsActive:        isActive,
+		IncludeArchived: includeArchived(c),
+		Limit:           page.Limit,
+		Offset:          page.Offset(),
+		Sort:            page.Sort,
+		Order:           page.Order,
+	}
 
-	for _, symbol := range symbols {
-		filter := &models.SetupFilter{
-			Symbol:          symbol,
-			SetupType:       setupType,
-			Direction:       direction,
-			MinQualityScore: minQuality,
-			IsActive:        isActive,
-			Limit:           20,
-		}
+	setups, err := db.GetTradingSetups(filter)
+	if err != nil {
+		respondError(c, "Failed to get setups", err)
+		return
+	}
+	if setups == nil {
+		setups = []*models.TradingSetup{}
+	}
 
-		setups, err := h.db.GetTradingSetups(filter)
-		if err != nil {
-			results[symbol] = &models.SetupResponse{
-				Symbol:  symbol,
-				Status:  "error",
-				Message: err.Error(),
-			}
-			continue
-		}
+	total, err := db.CountTradingSetups(filter)
+	if err != nil {
+		respondError(c, "Failed to count setups", err)
+		return
+	}
 
-		summary, _ := h.db.GetSetupSummary(symbol)
-		if summary == nil {
-			summary = &models.SetupSummary{}
-		}
+	h.applyRisk(setups)
 
-		results[symbol] = &models.SetupResponse{
-			Symbol:  symbol,
-			Setups:  setups,
-			Summary: summary,
-			Status:  "success",
+	// Setups are encoded one at a time rather than marshalled as a whole page; the response has the fields
+	// of models.PagedResponse
+	stream := newJSONStream(c)
+	stream.BeginArray("items")
+	for _, setup := range setups {
+		if stream.Item(setup) != nil {
+			break
 		}
 	}
-
-	c.JSON(http.StatusOK, results)
+	stream.EndArray()
+	stream.Field("pagination", models.NewPagination(page, total))
+	if err := stream.Close(); err != nil {
+		log.Printf("Setups response cut short: %v", err)
+	}
 }
 
 // GetSetupSummary godoc
@@ -418,23 +436,19 @@ func (h *SetupHandler) GetMultipleSetups(c *gin.Context) {
 // @Success 200 {object} models.SetupSummary
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/setups/{symbol}/summary [get]
+// @Router /api/v1/setups/{symbol}/summary [get]
 func (h *SetupHandler) GetSetupSummary(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
 		return
 	}
 
-	summary, err := h.db.GetSetupSummary(symbol)
+	summary, err := db.GetSetupSummary(symbol)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get setup summary: " + err.Error(),
-		})
+		respondError(c, "Failed to get setup summary", err)
 		return
 	}
 
@@ -450,8 +464,10 @@ func (h *SetupHandler) GetSetupSummary(c *gin.Context) {
 // @Param limit query int false "Maximum number of setups to return" default(20)
 // @Success 200 {array} models.TradingSetup
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/setups/high-quality [get]
+// @Router /api/v1/setups/high-quality [get]
 func (h *SetupHandler) GetHighQualitySetups(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	limitStr := c.DefaultQuery("limit", "20")
 
 	limit, err := strconv.Atoi(limitStr)
@@ -465,15 +481,13 @@ func (h *SetupHandler) GetHighQualitySetups(c *gin.Context) {
 		Limit:           limit,
 	}
 
-	setups, err := h.db.GetTradingSetups(filter)
+	setups, err := db.GetTradingSetups(filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get high quality setups: " + err.Error(),
-		})
+		respondError(c, "Failed to get high quality setups", err)
 		return
 	}
 
+	h.applyRisk(setups)
 	c.JSON(http.StatusOK, setups)
 }
 
@@ -485,14 +499,13 @@ func (h *SetupHandler) GetHighQualitySetups(c *gin.Context) {
 // @Produce json
 // @Success 200 {object} map[string]interface{}
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/setups/expire [post]
+// @Router /api/v1/setups/expire [post]
 func (h *SetupHandler) ExpireOldSetups(c *gin.Context) {
-	expiredCount, err := h.db.ExpireOldSetups()
+	db := withRequestContext(c, h.db)
+
+	expiredCount, err := db.ExpireOldSetups()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to expire old setups: " + err.Error(),
-		})
+		respondError(c, "Failed to expire old setups", err)
 		return
 	}
 
@@ -507,41 +520,37 @@ func (h *SetupHandler) ExpireOldSetups(c *gin.Context) {
 }
 
 // CleanupOldSetups godoc
-// @Summary Cleanup old setup data
-// @Description Remove old setup data based on retention policy
+// @Summary Archive old setups
+// @Description Archive the setups created more than the given number of days ago. Archived setups are kept for analytics but left out of setup lists; they are deleted by the purge job once data_retention.purge_archived_after_days have passed.
 // @Tags setups
 // @Accept json
 // @Produce json
-// @Param days query int false "Days to retain setup data" default(90)
+// @Param days query int false "Days to keep setups unarchived" default(90)
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/setups/cleanup [post]
+// @Router /api/v1/setups/cleanup [post]
 func (h *SetupHandler) CleanupOldSetups(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	daysStr := c.DefaultQuery("days", "90")
 
 	days, err := strconv.Atoi(daysStr)
 	if err != nil || days <= 0 {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Invalid days parameter",
-		})
+		respondInvalid(c, "Invalid days parameter", nil)
 		return
 	}
 
-	deletedCount, err := h.db.CleanupOldSetupData(days)
+	archivedCount, err := db.ArchiveOldSetups(days)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to cleanup old setup data: " + err.Error(),
-		})
+		respondError(c, "Failed to archive old setups", err)
 		return
 	}
 
 	response := map[string]interface{}{
 		"status":         "success",
-		"message":        "Old setup data cleaned up successfully",
-		"deleted_count":  deletedCount,
+		"message":        "Old setups archived successfully",
+		"archived_count": archivedCount,
 		"retention_days": days,
 		"cleanup_time":   time.Now(),
 	}
@@ -549,6 +558,64 @@ func (h *SetupHandler) CleanupOldSetups(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// ArchiveSetup godoc
+// @Summary Archive a trading setup
+// @Description Archive a setup so it is left out of setup lists while its history is kept
+// @Tags setups
+// @Produce json
+// @Param id path int true "Setup ID"
+// @Success 200 {object} models.TradingSetup
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/setups/id/{id}/archive [post]
+func (h *SetupHandler) ArchiveSetup(c *gin.Context) {
+	h.setArchived(c, true)
+}
+
+// UnarchiveSetup godoc
+// @Summary Restore an archived trading setup
+// @Description Restore an archived setup to the setup lists
+// @Tags setups
+// @Produce json
+// @Param id path int true "Setup ID"
+// @Success 200 {object} models.TradingSetup
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/setups/id/{id}/unarchive [post]
+func (h *SetupHandler) UnarchiveSetup(c *gin.Context) {
+	h.setArchived(c, false)
+}
+
+// setArchived archives or restores the setup in the path and responds with it
+func (h *SetupHandler) setArchived(c *gin.Context, archived bool) {
+	db := withRequestContext(c, h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondInvalid(c, "Invalid setup ID", nil)
+		return
+	}
+
+	if err := db.SetTradingSetupArchived(id, archived); err != nil {
+		respondError(c, "Failed to archive setup", err)
+		return
+	}
+
+	setup, err := db.GetTradingSetupByID(id)
+	if err != nil {
+		respondError(c, "Failed to get setup", err)
+		return
+	}
+	if setup == nil {
+		respondNotFound(c, "Setup not found", nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, setup)
+}
+
 // GetSetupChecklist godoc
 // @Summary Get setup checklist
 // @Description Get the detailed checklist for a specific setup
@@ -560,38 +627,56 @@ func (h *SetupHandler) CleanupOldSetups(c *gin.Context) {
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/setups/id/{id}/checklist [get]
+// @Router /api/v1/setups/id/{id}/checklist [get]
 func (h *SetupHandler) GetSetupChecklist(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Invalid setup ID",
-		})
+		respondInvalid(c, "Invalid setup ID", nil)
 		return
 	}
 
-	checklist, err := h.db.GetSetupChecklist(id)
+	checklist, err := db.GetSetupChecklist(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get setup checklist: " + err.Error(),
-		})
+		respondError(c, "Failed to get setup checklist", err)
 		return
 	}
 
 	if checklist == nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Error:   "Not Found",
-			Message: "Checklist not found for setup",
-		})
+		respondNotFound(c, "Checklist not found for setup", nil)
 		return
 	}
 
 	c.JSON(http.StatusOK, checklist)
 }
 
+// GetPortfolioHeat godoc
+// @Summary Get portfolio heat
+// @Description Get the open risk across active and triggered setups, each sized under the risk settings, against the heat limit
+// @Tags setups
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.PortfolioHeat
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/setups/risk [get]
+func (h *SetupHandler) GetPortfolioHeat(c *gin.Context) {
+	if h.risk == nil {
+		respondUnavailable(c, "Risk management is not available", nil)
+		return
+	}
+
+	heat, err := h.risk.PortfolioHeat()
+	if err != nil {
+		respondError(c, "Failed to compute portfolio heat", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, heat)
+}
+
 // GetSetupsStats godoc
 // @Summary Get setup statistics
 // @Description Get comprehensive statistics about all trading setups
@@ -600,15 +685,14 @@ func (h *SetupHandler) GetSetupChecklist(c *gin.Context) {
 // @Produce json
 // @Success 200 {object} map[string]interface{}
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/setups/stats [get]
+// @Router /api/v1/setups/stats [get]
 func (h *SetupHandler) GetSetupsStats(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	// Get all setups for stats calculation
-	allSetups, err := h.db.GetTradingSetups(&models.SetupFilter{Limit: 10000})
+	allSetups, err := db.GetTradingSetups(&models.SetupFilter{Limit: 10000})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get setups for statistics: " + err.Error(),
-		})
+		respondError(c, "Failed to get setups for statistics", err)
 		return
 	}
 
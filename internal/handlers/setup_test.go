@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"market-watch-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// serve runs one request through a router holding a single route
+func serve(method, route, target, body string, handler gin.HandlerFunc) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Use(ErrorMiddleware())
+	router.Handle(method, route, handler)
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// decode unmarshals a JSON response body, failing the test when it isn't valid JSON
+func decode[T any](t *testing.T, w *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal(w.Body.Bytes(), &v); err != nil {
+		t.Fatalf("invalid JSON response %q: %v", w.Body.String(), err)
+	}
+	return v
+}
+
+// TestDetectSetupsStoresAndNotifies tests that detected setups are stored with their checklists and only the
+// stored ones are sent as alerts
+func TestDetectSetupsStoresAndNotifies(t *testing.T) {
+	store := newMockSetupStore()
+	detector := &mockSetupDetector{result: &models.SetupDetectionResult{
+		Symbol: "AAPL",
+		SetupsFound: []*models.TradingSetup{
+			{Symbol: "AAPL", SetupType: "support_bounce", Status: "active", QualityScore: 82, Checklist: &models.SetupChecklist{}},
+			{Symbol: "AAPL", SetupType: "resistance_break", Status: "active", QualityScore: 65},
+		},
+	}}
+	h := NewSetupHandler(store, detector)
+
+	w := serve("POST", "/setups/:symbol/detect", "/setups/AAPL/detect", "", h.DetectSetups)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if len(store.setups) != 2 {
+		t.Fatalf("expected 2 stored setups, got %d", len(store.setups))
+	}
+	if checklist := store.checklists[store.setups[0].ID]; checklist == nil || checklist.SetupID != store.setups[0].ID {
+		t.Errorf("expected the checklist to be stored with the setup's ID, got %+v", checklist)
+	}
+	if len(detector.notified) != 2 {
+		t.Errorf("expected 2 notified setups, got %d", len(detector.notified))
+	}
+}
+
+// TestDetectSetupsRefreshesActiveSetup tests that a setup at the key level of an active setup of its type refreshes
+// it instead of being stored and alerted on again
+func TestDetectSetupsRefreshesActiveSetup(t *testing.T) {
+	store := newMockSetupStore(&models.TradingSetup{Symbol: "AAPL", SetupType: "support_bounce", Status: "active", QualityScore: 70, KeyLevelID: 7})
+	detector := &mockSetupDetector{result: &models.SetupDetectionResult{
+		Symbol: "AAPL",
+		SetupsFound: []*models.TradingSetup{
+			{Symbol: "AAPL", SetupType: "support_bounce", Status: "active", QualityScore: 85, KeyLevelID: 7},
+			{Symbol: "AAPL", SetupType: "support_bounce", Status: "active", QualityScore: 80, KeyLevelID: 8},
+		},
+	}}
+	h := NewSetupHandler(store, detector)
+
+	w := serve("POST", "/setups/:symbol/detect", "/setups/AAPL/detect", "", h.DetectSetups)
+	result := decode[models.SetupDetectionResult](t, w)
+
+	if len(store.setups) != 2 || store.setups[0].QualityScore != 85 {
+		t.Fatalf("expected the level 7 setup to be refreshed and one setup added, got %d setups", len(store.setups))
+	}
+	if result.Refreshed != 1 || result.SetupsFound[0].ID != store.setups[0].ID {
+		t.Errorf("expected 1 refreshed setup keeping its ID, got %d", result.Refreshed)
+	}
+	if len(detector.notified) != 1 || detector.notified[0].KeyLevelID != 8 {
+		t.Errorf("expected only the new setup to be alerted on, got %d", len(detector.notified))
+	}
+}
+
+// TestDetectSetupsStoreFailure tests that setups that fail to store are reported and not sent as alerts
+func TestDetectSetupsStoreFailure(t *testing.T) {
+	store := newMockSetupStore()
+	store.insertErr = errors.New("disk full")
+	detector := &mockSetupDetector{result: &models.SetupDetectionResult{
+		Symbol:      "AAPL",
+		SetupsFound: []*models.TradingSetup{{Symbol: "AAPL", Status: "active"}},
+	}}
+	h := NewSetupHandler(store, detector)
+
+	w := serve("POST", "/setups/:symbol/detect", "/setups/AAPL/detect", "", h.DetectSetups)
+	result := decode[models.SetupDetectionResult](t, w)
+
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "disk full") {
+		t.Errorf("expected the store error in the result, got %v", result.Errors)
+	}
+	if len(detector.notified) != 0 {
+		t.Errorf("expected no alerts for unstored setups, got %d", len(detector.notified))
+	}
+}
+
+// TestDetectSetupsDetectionError tests that a detection failure is a server error
+func TestDetectSetupsDetectionError(t *testing.T) {
+	h := NewSetupHandler(newMockSetupStore(), &mockSetupDetector{err: errors.New("no price data")})
+
+	w := serve("POST", "/setups/:symbol/detect", "/setups/AAPL/detect", "", h.DetectSetups)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d", w.Code)
+	}
+}
+
+// TestGetSetupsPaginates tests that a symbol's setups are filtered and paginated
+func TestGetSetupsPaginates(t *testing.T) {
+	store := newMockSetupStore(
+		&models.TradingSetup{Symbol: "AAPL", Status: "active", QualityScore: 90},
+		&models.TradingSetup{Symbol: "AAPL", Status: "active", QualityScore: 70},
+		&models.TradingSetup{Symbol: "AAPL", Status: "expired", QualityScore: 85},
+		&models.TradingSetup{Symbol: "MSFT", Status: "active", QualityScore: 95},
+	)
+	h := NewSetupHandler(store, &mockSetupDetector{})
+
+	w := serve("GET", "/setups/:symbol", "/setups/AAPL?status=active&limit=1", "", h.GetSetups)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	response := decode[models.SetupResponse](t, w)
+	if len(response.Setups) != 1 || response.Setups[0].Symbol != "AAPL" {
+		t.Fatalf("expected one AAPL setup, got %+v", response.Setups)
+	}
+	if response.Pagination == nil || response.Pagination.Total != 2 {
+		t.Errorf("expected 2 matching setups in total, got %+v", response.Pagination)
+	}
+}
+
+// TestGetSetupByID tests the found, missing and invalid ID responses
+func TestGetSetupByID(t *testing.T) {
+	store := newMockSetupStore(&models.TradingSetup{Symbol: "AAPL", Status: "active"})
+	h := NewSetupHandler(store, &mockSetupDetector{})
+
+	tests := []struct {
+		target string
+		status int
+	}{
+		{"/setups/id/1", http.StatusOK},
+		{"/setups/id/2", http.StatusNotFound},
+		{"/setups/id/abc", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if w := serve("GET", "/setups/id/:id", tt.target, "", h.GetSetupByID); w.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d", tt.target, tt.status, w.Code)
+		}
+	}
+}
+
+// TestUpdateSetupStatus tests that a valid status and notes are saved and an unknown status is rejected
+func TestUpdateSetupStatus(t *testing.T) {
+	store := newMockSetupStore(&models.TradingSetup{Symbol: "AAPL", Status: "active"})
+	h := NewSetupHandler(store, &mockSetupDetector{})
+
+	w := serve("PUT", "/setups/id/:id/status", "/setups/id/1/status", `{"status":"triggered","notes":"filled at open"}`, h.UpdateSetupStatus)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if setup := store.setups[0]; setup.Status != "triggered" || setup.Notes != "filled at open" {
+		t.Errorf("expected the status and notes to be saved, got %q %q", setup.Status, setup.Notes)
+	}
+
+	w = serve("PUT", "/setups/id/:id/status", "/setups/id/1/status", `{"status":"sold"}`, h.UpdateSetupStatus)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown status, got %d", w.Code)
+	}
+	if store.setups[0].Status != "triggered" {
+		t.Errorf("expected a rejected update to leave the setup unchanged, got %q", store.setups[0].Status)
+	}
+}
+
+// TestArchiveSetup tests that an archived setup leaves the setup list unless archived setups are asked for
+func TestArchiveSetup(t *testing.T) {
+	store := newMockSetupStore(
+		&models.TradingSetup{Symbol: "AAPL", Status: "completed"},
+		&models.TradingSetup{Symbol: "AAPL", Status: "active"},
+	)
+	h := NewSetupHandler(store, &mockSetupDetector{})
+
+	w := serve("POST", "/setups/id/:id/archive", "/setups/id/1/archive", "", h.ArchiveSetup)
+	if w.Code != http.StatusOK || !decode[models.TradingSetup](t, w).Archived {
+		t.Fatalf("expected the setup to be archived, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := serve("POST", "/setups/id/:id/archive", "/setups/id/9/archive", "", h.ArchiveSetup); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown setup, got %d", w.Code)
+	}
+
+	for target, want := range map[string]int{"/setups/AAPL": 1, "/setups/AAPL?include_archived=true": 2} {
+		w := serve("GET", "/setups/:symbol", target, "", h.GetSetups)
+		if response := decode[models.SetupResponse](t, w); len(response.Setups) != want {
+			t.Errorf("%s: expected %d setups, got %d", target, want, len(response.Setups))
+		}
+	}
+
+	w = serve("POST", "/setups/id/:id/unarchive", "/setups/id/1/unarchive", "", h.UnarchiveSetup)
+	if w.Code != http.StatusOK || store.setups[0].Archived {
+		t.Errorf("expected the setup to be restored, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+// TestSetupResponsesCarryRisk tests that setup responses are sized and carry the portfolio heat
+func TestSetupResponsesCarryRisk(t *testing.T) {
+	store := newMockSetupStore(&models.TradingSetup{Symbol: "AAPL", Status: "active", EntryPrice: 100, StopLoss: 95})
+	h := NewSetupHandler(store, &mockSetupDetector{})
+
+	if w := serve("GET", "/setups/risk", "/setups/risk", "", h.GetPortfolioHeat); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without a risk manager, got %d", w.Code)
+	}
+
+	h.SetRiskService(&mockRiskManager{heat: &models.PortfolioHeat{HeatPercent: 7.5, MaxHeatPercent: 6, OverLimit: true}, shares: 100})
+
+	setup := decode[models.TradingSetup](t, serve("GET", "/setups/id/:id", "/setups/id/1", "", h.GetSetupByID))
+	if setup.Sizing == nil || setup.Sizing.Shares != 100 || setup.Sizing.Warning == "" {
+		t.Errorf("expected the setup to be sized with a heat warning, got %+v", setup.Sizing)
+	}
+
+	response := decode[models.SetupResponse](t, serve("GET", "/setups/:symbol", "/setups/AAPL", "", h.GetSetups))
+	if response.Heat == nil || !response.Heat.OverLimit || len(response.Setups) != 1 || response.Setups[0].Sizing == nil {
+		t.Errorf("expected sized setups and the portfolio heat, got %+v", response)
+	}
+
+	heat := decode[models.PortfolioHeat](t, serve("GET", "/setups/risk", "/setups/risk", "", h.GetPortfolioHeat))
+	if heat.HeatPercent != 7.5 {
+		t.Errorf("expected the portfolio heat, got %+v", heat)
+	}
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VWAPSetupsHandler handles turning VWAP reclaim and rejection setups on for watchlist strategies
+type VWAPSetupsHandler struct {
+	db *database.Database
+}
+
+// vwapSetupsRequest is the request body for a strategy's VWAP setup settings
+type vwapSetupsRequest struct {
+	Reclaim          bool     `json:"reclaim"`
+	Rejection        bool     `json:"rejection"`
+	VolumeMultiplier *float64 `json:"volume_multiplier"` // defaults to 1.5
+}
+
+// NewVWAPSetupsHandler creates a new VWAP setups handler
+func NewVWAPSetupsHandler(db *database.Database) *VWAPSetupsHandler {
+	return &VWAPSetupsHandler{db: db}
+}
+
+// GetStrategyVWAPSetups godoc
+// @Summary Get a strategy's VWAP setup settings
+// @Description Get whether VWAP reclaim and rejection setups are detected for the strategy's stocks, and the volume they need
+// @Tags watchlist
+// @Produce json
+// @Param id path int true "Strategy ID"
+// @Success 200 {object} models.StrategyVWAPSetups
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/watchlist/strategies/{id}/vwap-setups [get]
+func (h *VWAPSetupsHandler) GetStrategyVWAPSetups(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	strategyID, ok := existingStrategyID(c, db)
+	if !ok {
+		return
+	}
+
+	settings, err := db.GetStrategyVWAPSetups(strategyID)
+	if err != nil {
+		respondError(c, "Failed to get strategy VWAP setups", err)
+		return
+	}
+	if settings == nil {
+		settings = &models.StrategyVWAPSetups{StrategyID: strategyID, VolumeMultiplier: models.DefaultVWAPVolumeMultiplier}
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// SetStrategyVWAPSetups godoc
+// @Summary Turn a strategy's VWAP setups on or off
+// @Description Detect VWAP reclaim (long) and rejection (short) setups for the strategy's stocks during the regular session. The signal bar needs volume_multiplier times the session's average bar volume. Turning both off removes the settings.
+// @Tags watchlist
+// @Accept json
+// @Produce json
+// @Param id path int true "Strategy ID"
+// @Param request body vwapSetupsRequest true "VWAP setup settings"
+// @Success 200 {object} models.StrategyVWAPSetups
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/watchlist/strategies/{id}/vwap-setups [put]
+func (h *VWAPSetupsHandler) SetStrategyVWAPSetups(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	strategyID, ok := existingStrategyID(c, db)
+	if !ok {
+		return
+	}
+
+	var req vwapSetupsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, "Invalid request body", err)
+		return
+	}
+
+	settings := &models.StrategyVWAPSetups{
+		StrategyID:       strategyID,
+		Reclaim:          req.Reclaim,
+		Rejection:        req.Rejection,
+		VolumeMultiplier: models.DefaultVWAPVolumeMultiplier,
+		UpdatedAt:        time.Now(),
+	}
+	if req.VolumeMultiplier != nil {
+		settings.VolumeMultiplier = *req.VolumeMultiplier
+	}
+	if err := settings.Validate(); err != nil {
+		respondInvalid(c, "Invalid VWAP setup settings", err)
+		return
+	}
+
+	if err := db.SetStrategyVWAPSetups(settings); err != nil {
+		respondError(c, "Failed to set strategy VWAP setups", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// existingStrategyID parses the strategy ID of the request and checks the strategy exists
+func existingStrategyID(c *gin.Context, db *database.Database) (int, bool) {
+	strategyID, ok := parseStrategyID(c)
+	if !ok {
+		return 0, false
+	}
+
+	exists, err := db.StrategyExists(strategyID)
+	if err != nil {
+		respondError(c, "Failed to check strategy", err)
+		return 0, false
+	}
+	if !exists {
+		respondNotFound(c, fmt.Sprintf("Strategy %d not found", strategyID), nil)
+		return 0, false
+	}
+	return strategyID, true
+}
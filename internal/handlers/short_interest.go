@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ShortInterestHandler handles the short volume and short interest endpoints
+type ShortInterestHandler struct {
+	shortInterest *services.ShortInterestService
+}
+
+// NewShortInterestHandler creates a new short interest handler
+func NewShortInterestHandler(shortInterest *services.ShortInterestService) *ShortInterestHandler {
+	return &ShortInterestHandler{shortInterest: shortInterest}
+}
+
+// GetShortInterest godoc
+// @Summary Get a stock's short volume and short interest
+// @Description Get a stock's FINRA daily short volume over the lookback days, its latest short interest reports with days to cover and percent of float, and whether any of them is elevated
+// @Tags short-interest
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Param refresh query bool false "Fetch the missing short volume days and the latest short interest first"
+// @Success 200 {object} models.ShortInterestSummary
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/short-interest/{symbol} [get]
+func (h *ShortInterestHandler) GetShortInterest(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+
+	if c.Query("refresh") == "true" {
+		if err := h.shortInterest.RefreshSymbol(symbol); err != nil {
+			respondError(c, "Failed to fetch short interest", err)
+			return
+		}
+	}
+
+	summary, err := h.shortInterest.Summary(symbol)
+	if err != nil {
+		respondError(c, "Failed to get short interest", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StrategyRegimesHandler handles declaring the market regimes watchlist strategies trade in
+type StrategyRegimesHandler struct {
+	db *database.Database
+}
+
+// strategyRegimesRequest is the request body for the regimes a strategy trades in
+type strategyRegimesRequest struct {
+	Long  []string `json:"long"`
+	Short []string `json:"short"`
+}
+
+// NewStrategyRegimesHandler creates a new strategy regimes handler
+func NewStrategyRegimesHandler(db *database.Database) *StrategyRegimesHandler {
+	return &StrategyRegimesHandler{db: db}
+}
+
+// GetStrategyRegimes godoc
+// @Summary Get the market regimes a strategy trades in
+// @Description Get the regimes the strategy's bullish (long) and bearish (short) setups are detected in; an empty list allows any regime
+// @Tags watchlist
+// @Produce json
+// @Param id path int true "Strategy ID"
+// @Success 200 {object} models.StrategyRegimes
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/watchlist/strategies/{id}/regimes [get]
+func (h *StrategyRegimesHandler) GetStrategyRegimes(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	strategyID, ok := existingStrategyID(c, db)
+	if !ok {
+		return
+	}
+
+	regimes, err := db.GetStrategyRegimes(strategyID)
+	if err != nil {
+		respondError(c, "Failed to get strategy regimes", err)
+		return
+	}
+	if regimes == nil {
+		regimes = &models.StrategyRegimes{StrategyID: strategyID, Long: []string{}, Short: []string{}}
+	}
+
+	c.JSON(http.StatusOK, regimes)
+}
+
+// SetStrategyRegimes godoc
+// @Summary Declare the market regimes a strategy trades in
+// @Description Detect the strategy's stocks' bullish setups only in the long regimes and bearish setups only in the short regimes (trending_up, trending_down, choppy, high_volatility). A stock in several strategies keeps a setup when any strategy trades it. Empty lists remove the declaration.
+// @Tags watchlist
+// @Accept json
+// @Produce json
+// @Param id path int true "Strategy ID"
+// @Param request body strategyRegimesRequest true "Regimes"
+// @Success 200 {object} models.StrategyRegimes
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/watchlist/strategies/{id}/regimes [put]
+func (h *StrategyRegimesHandler) SetStrategyRegimes(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	strategyID, ok := existingStrategyID(c, db)
+	if !ok {
+		return
+	}
+
+	var req strategyRegimesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, "Invalid request body", err)
+		return
+	}
+
+	regimes := &models.StrategyRegimes{
+		StrategyID: strategyID,
+		Long:       append([]string{}, req.Long...),
+		Short:      append([]string{}, req.Short...),
+		UpdatedAt:  time.Now(),
+	}
+	if err := regimes.Validate(); err != nil {
+		respondInvalid(c, "Invalid strategy regimes", err)
+		return
+	}
+
+	if err := db.SetStrategyRegimes(regimes); err != nil {
+		respondError(c, "Failed to set strategy regimes", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, regimes)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"market-watch-go/internal/services"
+	"market-watch-go/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+type StreamingHandler struct {
+	streamingService *services.StreamingService
+	upgrader         websocket.Upgrader
+}
+
+// NewStreamingHandler creates a new streaming handler
+func NewStreamingHandler(streamingService *services.StreamingService) *StreamingHandler {
+	return &StreamingHandler{
+		streamingService: streamingService,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			// Dashboard is served from the same host, API allows any origin (see CORS middleware)
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// Stream godoc
+// @Summary Stream real-time market data
+// @Description Upgrade to a WebSocket that pushes new price/volume bars, indicator updates and pattern alerts.
+// @Description Send {"action":"subscribe","symbols":["AAPL"]} or {"action":"unsubscribe","symbols":["AAPL"]} to manage subscriptions; "*" subscribes to all symbols.
+// @Tags streaming
+// @Param symbols query string false "Comma-separated symbols to subscribe to on connect"
+// @Success 101
+// @Router /ws/stream [get]
+func (h *StreamingHandler) Stream(c *gin.Context) {
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade already wrote an HTTP error response
+		log.Printf("Failed to upgrade streaming connection: %v", err)
+		return
+	}
+
+	symbols := utils.ParseSymbols(c.Query("symbols"))
+	h.streamingService.ServeClient(conn, symbols)
+}
+
+// GetStatus godoc
+// @Summary Get streaming status
+// @Description Get the number of connected streaming clients and their subscriptions
+// @Tags streaming
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/stream/status [get]
+func (h *StreamingHandler) GetStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.streamingService.GetStatus())
+}
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	"market-watch-go/internal/database"
@@ -17,6 +19,9 @@ import (
 type SupportResistanceHandler struct {
 	db        *database.DB
 	srService *services.SupportResistanceService
+	recalc    *services.SRRecalculationService
+	pivots    *services.FloorPivotService
+	workers   *services.WorkerPool
 }
 
 // NewSupportResistanceHandler creates a new S/R handler
@@ -24,39 +29,71 @@ func NewSupportResistanceHandler(db *database.DB, srService *services.SupportRes
 	return &SupportResistanceHandler{
 		db:        db,
 		srService: srService,
+		workers:   services.NewWorkerPool(1),
 	}
 }
 
+// SetWorkerPool sets the pool S/R detection for many symbols runs on
+func (h *SupportResistanceHandler) SetWorkerPool(workers *services.WorkerPool) {
+	h.workers = workers
+}
+
+// SetRecalculationService sets the service that diffs recalculated levels against the stored ones
+func (h *SupportResistanceHandler) SetRecalculationService(recalc *services.SRRecalculationService) {
+	h.recalc = recalc
+}
+
+// SetFloorPivotService lists the daily and weekly floor pivots alongside the detected levels
+func (h *SupportResistanceHandler) SetFloorPivotService(pivots *services.FloorPivotService) {
+	h.pivots = pivots
+}
+
 // GetSupportResistanceLevels godoc
 // @Summary Get support and resistance levels for a symbol
-// @Description Get all active support and resistance levels for a specific symbol
+// @Description Get all active support and resistance levels for a specific symbol, with the current session's daily and weekly floor pivots (PP, R1-R3, S1-S3) in floor_pivots
 // @Tags support-resistance
 // @Accept json
 // @Produce json
 // @Param symbol path string true "Stock symbol"
 // @Param level_type query string false "Level type: 'support', 'resistance', or 'both'" default(both)
 // @Param min_strength query number false "Minimum strength score"
-// @Param min_touches query int false "Minimum number of touches"
-// @Param limit query int false "Maximum number of levels to return"
+// @Param min_touches query int false "Minimum number of touches (default 2 for pivot levels, 0 otherwise)"
+// @Param class query string false "Level class: 'pivot', 'fibonacci' (retracements and extensions of the latest significant swing) or 'all'" default(pivot)
+// @Param page query int false "Page number, starting at 1" default(1)
+// @Param limit query int false "Levels per page" default(50)
+// @Param sort query string false "Sort field: 'strength', 'touches', 'level' or 'last_touch'"
+// @Param order query string false "Sort order: 'asc' or 'desc'" default(desc)
 // @Success 200 {object} models.SRResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/support-resistance/{symbol}/levels [get]
+// @Router /api/v1/support-resistance/{symbol}/levels [get]
 func (h *SupportResistanceHandler) GetSupportResistanceLevels(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
 		return
 	}
 
 	// Parse query parameters
+	levelClass := c.DefaultQuery("class", models.SRClassPivot)
+	switch levelClass {
+	case models.SRClassPivot, models.SRClassFibonacci, models.SRClassAll:
+	default:
+		respondInvalid(c, "Invalid class parameter: expected pivot, fibonacci or all", nil)
+		return
+	}
+
+	// Fibonacci levels have no touches, so only pivot levels are filtered by them unless asked
+	defaultTouches := 0
+	if levelClass == models.SRClassPivot {
+		defaultTouches = 2
+	}
+
 	levelType := c.DefaultQuery("level_type", "both")
 	minStrengthStr := c.DefaultQuery("min_strength", "0")
-	minTouchesStr := c.DefaultQuery("min_touches", "2")
-	limitStr := c.DefaultQuery("limit", "50")
+	minTouchesStr := c.DefaultQuery("min_touches", strconv.Itoa(defaultTouches))
 
 	minStrength, err := strconv.ParseFloat(minStrengthStr, 64)
 	if err != nil {
@@ -65,45 +102,61 @@ func (h *SupportResistanceHandler) GetSupportResistanceLevels(c *gin.Context) {
 
 	minTouches, err := strconv.Atoi(minTouchesStr)
 	if err != nil {
-		minTouches = 2
+		minTouches = defaultTouches
 	}
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 50
+	page, err := parsePageRequest(c, models.DefaultPageLimit, models.SRLevelSortFields)
+	if err != nil {
+		respondInvalid(c, "", err)
+		return
 	}
 
 	// Create filter
 	filter := &models.SRDetectionFilter{
 		Symbol:      symbol,
 		LevelType:   levelType,
+		LevelClass:  levelClass,
 		MinStrength: minStrength,
 		MinTouches:  minTouches,
 		IsActive:    utils.BoolPtr(true),
-		Limit:       limit,
+		Limit:       page.Limit,
+		Offset:      page.Offset(),
+		Sort:        page.Sort,
+		Order:       page.Order,
 	}
 
 	// Get levels from database
-	levels, err := h.db.GetSupportResistanceLevels(filter)
+	levels, err := db.GetSupportResistanceLevels(filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get S/R levels: " + err.Error(),
-		})
+		respondError(c, "Failed to get S/R levels", err)
+		return
+	}
+
+	total, err := db.CountSupportResistanceLevels(filter)
+	if err != nil {
+		respondError(c, "Failed to count S/R levels", err)
 		return
 	}
 
 	// Get summary
-	summary, err := h.db.GetSRLevelSummary(symbol)
+	summary, err := db.GetSRLevelSummary(symbol)
 	if err != nil {
 		summary = &models.SRLevelSummary{} // Return empty summary on error
 	}
 
 	response := &models.SRResponse{
-		Symbol:  symbol,
-		Levels:  levels,
-		Summary: summary,
-		Status:  "success",
+		Symbol:     symbol,
+		Levels:     levels,
+		Summary:    summary,
+		Pagination: models.NewPagination(page, total),
+		Status:     "success",
+	}
+
+	// Floor pivots are best effort; missing daily history leaves them out
+	if h.pivots != nil {
+		if pivots, err := h.pivots.Levels(symbol); err == nil {
+			response.FloorPivots = pivots
+		}
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -116,27 +169,120 @@ func (h *SupportResistanceHandler) GetSupportResistanceLevels(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param symbol path string true "Stock symbol"
+// @Param timeframe query string false "Candle timeframe (1m, 5m, 15m, 1h, 1d)"
 // @Success 200 {object} models.SRAnalysisResult
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/support-resistance/{symbol}/detect [post]
+// @Router /api/v1/support-resistance/{symbol}/detect [post]
 func (h *SupportResistanceHandler) DetectSupportResistance(c *gin.Context) {
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
+		return
+	}
+
+	timeframe, err := models.ParseTimeframe(c.Query("timeframe"))
+	if err != nil {
+		respondInvalid(c, "", err)
 		return
 	}
 
 	// Run S/R detection
-	result, err := h.srService.DetectSupportResistanceLevels(symbol)
+	result, err := h.srService.DetectSupportResistanceLevels(c.Request.Context(), symbol, timeframe)
+	if err != nil {
+		respondError(c, "Failed to detect S/R levels", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RecalculateSupportResistance godoc
+// @Summary Recalculate support and resistance levels for a symbol
+// @Description Detect a symbol's levels again on the sr_recalculation timeframe and diff them against the stored active levels. Levels detected for the first time are new; stored levels no longer detected are deactivated as invalidated. Significant changes are sent to the notification center.
+// @Tags support-resistance
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Success 200 {object} models.SRRecalculation
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/support-resistance/{symbol}/recalculate [post]
+func (h *SupportResistanceHandler) RecalculateSupportResistance(c *gin.Context) {
+	recalculation, err := h.recalc.Recalculate(c.Param("symbol"))
+	if err != nil {
+		respondError(c, "Failed to recalculate S/R levels", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, recalculation)
+}
+
+// DetectMultipleSupportResistance godoc
+// @Summary Detect support and resistance levels for multiple symbols
+// @Description Detect S/R levels for all watched symbols or a specific list on the shared scanner worker pool. A failing symbol is reported in the run's errors without stopping the others.
+// @Tags support-resistance
+// @Produce json
+// @Param symbols query string false "Comma-separated list of symbols (optional)"
+// @Param timeframe query string false "Candle timeframe (1m, 5m, 15m, 1h, 1d)"
+// @Success 200 {object} models.SRDetectionRun
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/support-resistance/detect [post]
+func (h *SupportResistanceHandler) DetectMultipleSupportResistance(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	timeframe, err := models.ParseTimeframe(c.Query("timeframe"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to detect S/R levels: " + err.Error(),
-		})
+		respondInvalid(c, "", err)
+		return
+	}
+
+	symbols := utils.ParseSymbols(c.Query("symbols"))
+	if len(symbols) == 0 {
+		symbols, err = db.GetWatchedSymbols()
+		if err != nil {
+			respondError(c, "Failed to get watched symbols", err)
+			return
+		}
+	}
+
+	results := make(map[string]*models.SRAnalysisResult)
+	var mutex sync.Mutex
+
+	run := h.workers.Run(c.Request.Context(), "S/R detection", symbols, func(ctx context.Context, symbol string) error {
+		result, err := h.srService.DetectSupportResistanceLevels(ctx, symbol, timeframe)
+		if err != nil {
+			return err
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		results[symbol] = result
+		return nil
+	})
+
+	c.JSON(http.StatusOK, &models.SRDetectionRun{Results: results, Run: run})
+}
+
+// GetZones godoc
+// @Summary Get support and resistance zones
+// @Description Merge the symbol's active levels into price bands and score each zone's confluence with round numbers, the prior day high/low and session VWAP
+// @Tags support-resistance
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Success 200 {object} models.SRZoneResult
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/support-resistance/{symbol}/zones [get]
+func (h *SupportResistanceHandler) GetZones(c *gin.Context) {
+	symbol := c.Param("symbol")
+	if symbol == "" {
+		respondInvalid(c, "Symbol parameter is required", nil)
+		return
+	}
+
+	result, err := h.srService.GetZones(symbol)
+	if err != nil {
+		respondError(c, "Failed to get S/R zones", err)
 		return
 	}
 
@@ -153,42 +299,32 @@ func (h *SupportResistanceHandler) DetectSupportResistance(c *gin.Context) {
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/support-resistance/{symbol}/nearest [get]
+// @Router /api/v1/support-resistance/{symbol}/nearest [get]
 func (h *SupportResistanceHandler) GetNearestLevels(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
 		return
 	}
 
 	// Get current price from latest price data
-	latestPrice, err := h.db.GetLatestPriceData(symbol)
+	latestPrice, err := db.GetLatestPriceData(symbol)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get current price: " + err.Error(),
-		})
+		respondError(c, "Failed to get current price", err)
 		return
 	}
 
 	if latestPrice == nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Error:   "Not Found",
-			Message: "No price data available for symbol",
-		})
+		respondNotFound(c, "No price data available for symbol", nil)
 		return
 	}
 
 	// Get nearest support and resistance levels
-	nearestSupport, nearestResistance, err := h.db.GetNearestSupportResistance(symbol, latestPrice.Close)
+	nearestSupport, nearestResistance, err := db.GetNearestSupportResistance(symbol, latestPrice.Close)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get nearest levels: " + err.Error(),
-		})
+		respondError(c, "Failed to get nearest levels", err)
 		return
 	}
 
@@ -223,17 +359,17 @@ func (h *SupportResistanceHandler) GetNearestLevels(c *gin.Context) {
 // @Param symbol path string true "Stock symbol"
 // @Param hours query int false "Hours to look back for touches" default(24)
 // @Param limit query int false "Maximum number of touches to return" default(20)
+// @Param type query string false "Touch type: 'test', 'break' or 'bounce', or empty for all"
 // @Success 200 {array} models.SRLevelTouch
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/support-resistance/{symbol}/touches [get]
+// @Router /api/v1/support-resistance/{symbol}/touches [get]
 func (h *SupportResistanceHandler) GetLevelTouches(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
 		return
 	}
 
@@ -250,12 +386,17 @@ func (h *SupportResistanceHandler) GetLevelTouches(c *gin.Context) {
 		limit = 20
 	}
 
-	touches, err := h.db.GetRecentSRLevelTouches(symbol, hours, limit)
+	touchType := c.Query("type")
+	switch touchType {
+	case "", models.SRTouchTest, models.SRTouchBreak, models.SRTouchBounce:
+	default:
+		respondInvalid(c, "Invalid type parameter: expected test, break or bounce", nil)
+		return
+	}
+
+	touches, err := db.GetRecentSRLevelTouches(symbol, touchType, hours, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get level touches: " + err.Error(),
-		})
+		respondError(c, "Failed to get level touches", err)
 		return
 	}
 
@@ -275,14 +416,13 @@ func (h *SupportResistanceHandler) GetLevelTouches(c *gin.Context) {
 // @Success 200 {array} models.PivotPoint
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/support-resistance/{symbol}/pivots [get]
+// @Router /api/v1/support-resistance/{symbol}/pivots [get]
 func (h *SupportResistanceHandler) GetPivotPoints(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
 		return
 	}
 
@@ -293,28 +433,19 @@ func (h *SupportResistanceHandler) GetPivotPoints(c *gin.Context) {
 
 	from, err := time.Parse(time.RFC3339, fromStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Invalid 'from' date format. Use RFC3339 format",
-		})
+		respondInvalid(c, "Invalid 'from' date format. Use RFC3339 format", nil)
 		return
 	}
 
 	to, err := time.Parse(time.RFC3339, toStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Invalid 'to' date format. Use RFC3339 format",
-		})
+		respondInvalid(c, "Invalid 'to' date format. Use RFC3339 format", nil)
 		return
 	}
 
-	pivots, err := h.db.GetPivotPoints(symbol, from, to, pivotType)
+	pivots, err := db.GetPivotPoints(symbol, from, to, pivotType)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get pivot points: " + err.Error(),
-		})
+		respondError(c, "Failed to get pivot points", err)
 		return
 	}
 
@@ -331,23 +462,19 @@ func (h *SupportResistanceHandler) GetPivotPoints(c *gin.Context) {
 // @Success 200 {object} models.SRLevelSummary
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/support-resistance/{symbol}/summary [get]
+// @Router /api/v1/support-resistance/{symbol}/summary [get]
 func (h *SupportResistanceHandler) GetLevelSummary(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
 		return
 	}
 
-	summary, err := h.db.GetSRLevelSummary(symbol)
+	summary, err := db.GetSRLevelSummary(symbol)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get level summary: " + err.Error(),
-		})
+		respondError(c, "Failed to get level summary", err)
 		return
 	}
 
@@ -365,8 +492,10 @@ func (h *SupportResistanceHandler) GetLevelSummary(c *gin.Context) {
 // @Param min_strength query number false "Minimum strength score" default(20)
 // @Success 200 {object} map[string]models.SRResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/support-resistance/levels [get]
+// @Router /api/v1/support-resistance/levels [get]
 func (h *SupportResistanceHandler) GetMultipleLevels(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	symbolsParam := c.Query("symbols")
 	levelType := c.DefaultQuery("level_type", "both")
 	minStrengthStr := c.DefaultQuery("min_strength", "20")
@@ -377,12 +506,9 @@ func (h *SupportResistanceHandler) GetMultipleLevels(c *gin.Context) {
 	if symbolsParam != "" {
 		symbols = utils.ParseSymbols(symbolsParam)
 	} else {
-		symbols, err = h.db.GetWatchedSymbols()
+		symbols, err = db.GetWatchedSymbols()
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-				Error:   "Internal Server Error",
-				Message: "Failed to get watched symbols: " + err.Error(),
-			})
+			respondError(c, "Failed to get watched symbols", err)
 			return
 		}
 	}
@@ -403,7 +529,7 @@ func (h *SupportResistanceHandler) GetMultipleLevels(c *gin.Context) {
 			Limit:       20,
 		}
 
-		levels, err := h.db.GetSupportResistanceLevels(filter)
+		levels, err := db.GetSupportResistanceLevels(filter)
 		if err != nil {
 			results[symbol] = &models.SRResponse{
 				Symbol:  symbol,
@@ -413,7 +539,7 @@ func (h *SupportResistanceHandler) GetMultipleLevels(c *gin.Context) {
 			continue
 		}
 
-		summary, _ := h.db.GetSRLevelSummary(symbol)
+		summary, _ := db.GetSRLevelSummary(symbol)
 		if summary == nil {
 			summary = &models.SRLevelSummary{}
 		}
@@ -439,25 +565,21 @@ func (h *SupportResistanceHandler) GetMultipleLevels(c *gin.Context) {
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/support-resistance/cleanup [post]
+// @Router /api/v1/support-resistance/cleanup [post]
 func (h *SupportResistanceHandler) CleanupOldData(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	daysStr := c.DefaultQuery("days", "90")
 
 	days, err := strconv.Atoi(daysStr)
 	if err != nil || days <= 0 {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Invalid days parameter",
-		})
+		respondInvalid(c, "Invalid days parameter", nil)
 		return
 	}
 
-	deletedCount, err := h.db.CleanupOldSRData(days)
+	deletedCount, err := db.CleanupOldSRData(days)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to cleanup old data: " + err.Error(),
-		})
+		respondError(c, "Failed to cleanup old data", err)
 		return
 	}
 
@@ -482,25 +604,21 @@ func (h *SupportResistanceHandler) CleanupOldData(c *gin.Context) {
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/support-resistance/deactivate [post]
+// @Router /api/v1/support-resistance/deactivate [post]
 func (h *SupportResistanceHandler) DeactivateOldLevels(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	maxAgeStr := c.DefaultQuery("max_age_hours", "720") // 30 days default
 
 	maxAgeHours, err := strconv.Atoi(maxAgeStr)
 	if err != nil || maxAgeHours <= 0 {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Invalid max_age_hours parameter",
-		})
+		respondInvalid(c, "Invalid max_age_hours parameter", nil)
 		return
 	}
 
-	deactivatedCount, err := h.db.DeactivateOldSRLevels(maxAgeHours)
+	deactivatedCount, err := db.DeactivateOldSRLevels(maxAgeHours)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to deactivate old levels: " + err.Error(),
-		})
+		respondError(c, "Failed to deactivate old levels", err)
 		return
 	}
 
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetSymbolSearch sets the service used for the symbol typeahead and to check symbols before they are watched
+func (vh *VolumeHandler) SetSymbolSearch(search *services.SymbolSearchService) {
+	vh.symbolSearch = search
+}
+
+// SearchSymbols godoc
+// @Summary Search tickers
+// @Description Search Polygon's active reference tickers by symbol or company name for the watchlist typeahead, best matches first
+// @Tags symbols
+// @Produce json
+// @Param q query string true "Symbol or company name, e.g. appl or Apple"
+// @Param limit query int false "Maximum number of results (default 10, max 50)"
+// @Success 200 {object} models.SymbolSearchResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/symbols/search [get]
+func (vh *VolumeHandler) SearchSymbols(c *gin.Context) {
+	if !vh.symbolSearch.IsEnabled() {
+		respondUnavailable(c, "Symbol search is disabled (set symbol_search.enabled)", nil)
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(services.DefaultSymbolSearchLimit)))
+	if err != nil || limit < 1 || limit > services.MaxSymbolSearchLimit {
+		respondInvalid(c, fmt.Sprintf("limit must be between 1 and %d", services.MaxSymbolSearchLimit), err)
+		return
+	}
+
+	query := c.Query("q")
+	matches, err := vh.symbolSearch.Search(query, limit)
+	if err != nil {
+		respondUpstreamError(c, "Failed to search symbols", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SymbolSearchResponse{
+		Query:   query,
+		Results: matches,
+		Count:   len(matches),
+	})
+}
+
+// checkNewSymbol checks a symbol about to be watched against the reference tickers when symbol search is
+// enabled, returning its company name. It responds with 400 and reports false for unknown, delisted or
+// mismatched tickers; when the provider can't be reached the symbol is let through unchecked.
+func checkNewSymbol(c *gin.Context, search *services.SymbolSearchService, symbol string) (string, bool) {
+	if !search.IsEnabled() {
+		return "", true
+	}
+
+	match, err := search.CheckSymbol(symbol)
+	if errors.Is(err, services.ErrValidation) {
+		respondInvalid(c, "Invalid symbol", err)
+		return "", false
+	}
+	if err != nil {
+		log.Printf("Could not check %s against the reference tickers, adding it unchecked: %v", symbol, err)
+		return "", true
+	}
+	return match.Name, true
+}
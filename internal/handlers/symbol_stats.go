@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SymbolStatsHandler handles the per-symbol trading statistics endpoints
+type SymbolStatsHandler struct {
+	db    *database.Database
+	stats *services.SymbolStatsService
+}
+
+// NewSymbolStatsHandler creates a new symbol stats handler
+func NewSymbolStatsHandler(db *database.Database, stats *services.SymbolStatsService) *SymbolStatsHandler {
+	return &SymbolStatsHandler{
+		db:    db,
+		stats: stats,
+	}
+}
+
+// GetSymbolStats godoc
+// @Summary Get a symbol's trading statistics
+// @Description Get a symbol's 14-day ATR, average regular session range, gap frequency and average volume by session, as computed after the latest close. Stats never computed before are computed on request.
+// @Tags symbols
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Param refresh query bool false "Recompute the stats from the stored bars first"
+// @Success 200 {object} models.SymbolStats
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/symbols/{symbol}/stats [get]
+func (h *SymbolStatsHandler) GetSymbolStats(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+
+	get := h.stats.Get
+	if c.Query("refresh") == "true" {
+		get = h.stats.Refresh
+	}
+	stats, err := get(symbol)
+	if err != nil {
+		respondError(c, "Failed to get symbol stats", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetAllSymbolStats godoc
+// @Summary List trading statistics of every symbol
+// @Description Get the stored trading statistics of every symbol they were computed for
+// @Tags symbols
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/symbols/stats [get]
+func (h *SymbolStatsHandler) GetAllSymbolStats(c *gin.Context) {
+	stats, err := withRequestContext(c, h.db).GetAllSymbolStats()
+	if err != nil {
+		respondError(c, "Failed to get symbol stats", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"stats": stats,
+		"count": len(stats),
+	})
+}
+
+// RefreshSymbolStats godoc
+// @Summary Recompute every symbol's trading statistics now
+// @Description Recompute and store the trading statistics of every watched symbol with enough daily history, as the daily job does after the close
+// @Tags symbols
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/symbols/stats/refresh [post]
+func (h *SymbolStatsHandler) RefreshSymbolStats(c *gin.Context) {
+	computed, err := h.stats.RefreshAll()
+	if err != nil {
+		respondError(c, "Failed to compute symbol stats", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Symbol stats computed",
+		"computed": computed,
+	})
+}
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	"market-watch-go/internal/database"
@@ -17,6 +19,7 @@ import (
 type TechnicalAnalysisHandler struct {
 	db        *database.DB
 	taService *services.TechnicalAnalysisService
+	workers   *services.WorkerPool
 }
 
 // NewTechnicalAnalysisHandler creates a new technical analysis handler
@@ -24,9 +27,15 @@ func NewTechnicalAnalysisHandler(db *database.DB, taService *services.TechnicalA
 	return &TechnicalAnalysisHandler{
 		db:        db,
 		taService: taService,
+		workers:   services.NewWorkerPool(1),
 	}
 }
 
+// SetWorkerPool sets the pool indicators for many symbols are computed on
+func (h *TechnicalAnalysisHandler) SetWorkerPool(workers *services.WorkerPool) {
+	h.workers = workers
+}
+
 // GetIndicators godoc
 // @Summary Get technical indicators for a symbol
 // @Description Get all technical indicators (RSI, MACD, MA, etc.) for a specific symbol
@@ -34,26 +43,33 @@ func NewTechnicalAnalysisHandler(db *database.DB, taService *services.TechnicalA
 // @Accept json
 // @Produce json
 // @Param symbol path string true "Stock symbol"
+// @Param timeframe query string false "Candle timeframe (1m, 5m, 15m, 1h, 1d)"
 // @Success 200 {object} models.TechnicalIndicatorsResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/technical-analysis/{symbol}/indicators [get]
+// @Router /api/v1/technical-analysis/{symbol}/indicators [get]
 func (h *TechnicalAnalysisHandler) GetIndicators(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
+		return
+	}
+
+	timeframe, err := models.ParseTimeframe(c.Query("timeframe"))
+	if err != nil {
+		respondInvalid(c, "", err)
+		return
+	}
+
+	if notModified(c, db.GetPriceDataVersion, symbol, false) {
 		return
 	}
 
-	indicators, err := h.taService.GetIndicators(symbol)
+	indicators, err := h.taService.GetIndicatorsForTimeframe(c.Request.Context(), symbol, timeframe)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to calculate technical indicators: " + err.Error(),
-		})
+		respondError(c, "Failed to calculate technical indicators", err)
 		return
 	}
 
@@ -73,26 +89,33 @@ func (h *TechnicalAnalysisHandler) GetIndicators(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param symbol path string true "Stock symbol"
+// @Param timeframe query string false "Candle timeframe (1m, 5m, 15m, 1h, 1d)"
 // @Success 200 {object} models.IndicatorSummary
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/technical-analysis/{symbol}/summary [get]
+// @Router /api/v1/technical-analysis/{symbol}/summary [get]
 func (h *TechnicalAnalysisHandler) GetIndicatorsSummary(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
+		return
+	}
+
+	timeframe, err := models.ParseTimeframe(c.Query("timeframe"))
+	if err != nil {
+		respondInvalid(c, "", err)
+		return
+	}
+
+	if notModified(c, db.GetPriceDataVersion, symbol, false) {
 		return
 	}
 
-	summary, err := h.taService.GetIndicatorsSummary(symbol)
+	summary, err := h.taService.GetIndicatorsSummary(c.Request.Context(), symbol, timeframe)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get indicators summary: " + err.Error(),
-		})
+		respondError(c, "Failed to get indicators summary", err)
 		return
 	}
 
@@ -108,8 +131,10 @@ func (h *TechnicalAnalysisHandler) GetIndicatorsSummary(c *gin.Context) {
 // @Param symbols query string false "Comma-separated list of symbols (optional)"
 // @Success 200 {object} map[string]models.TechnicalIndicatorsResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/technical-analysis/indicators [get]
+// @Router /api/v1/technical-analysis/indicators [get]
 func (h *TechnicalAnalysisHandler) GetMultipleIndicators(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	symbolsParam := c.Query("symbols")
 	var symbols []string
 	var err error
@@ -119,34 +144,39 @@ func (h *TechnicalAnalysisHandler) GetMultipleIndicators(c *gin.Context) {
 		symbols = utils.ParseSymbols(symbolsParam)
 	} else {
 		// Get all watched symbols
-		symbols, err = h.db.GetWatchedSymbols()
+		symbols, err = db.GetWatchedSymbols()
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-				Error:   "Internal Server Error",
-				Message: "Failed to get watched symbols: " + err.Error(),
-			})
+			respondError(c, "Failed to get watched symbols", err)
 			return
 		}
 	}
 
 	results := make(map[string]*models.TechnicalIndicatorsResponse)
+	var mutex sync.Mutex
 
-	for _, symbol := range symbols {
-		indicators, err := h.taService.GetIndicators(symbol)
+	run := h.workers.Run(c.Request.Context(), "Indicator refresh", symbols, func(ctx context.Context, symbol string) error {
+		indicators, err := h.taService.GetIndicators(ctx, symbol)
 		if err != nil {
-			results[symbol] = &models.TechnicalIndicatorsResponse{
-				Symbol:  symbol,
-				Status:  "error",
-				Message: err.Error(),
-			}
-			continue
+			return err
 		}
 
+		mutex.Lock()
+		defer mutex.Unlock()
 		results[symbol] = &models.TechnicalIndicatorsResponse{
 			Symbol:     symbol,
 			Indicators: indicators,
 			Status:     "success",
 		}
+		return nil
+	})
+
+	// Failed symbols, including ones the run never started, are reported rather than failing the request
+	for symbol, message := range run.Errors {
+		results[symbol] = &models.TechnicalIndicatorsResponse{
+			Symbol:  symbol,
+			Status:  "error",
+			Message: message,
+		}
 	}
 
 	c.JSON(http.StatusOK, results)
@@ -166,14 +196,13 @@ func (h *TechnicalAnalysisHandler) GetMultipleIndicators(c *gin.Context) {
 // @Success 200 {array} models.TechnicalIndicators
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/technical-analysis/{symbol}/historical [get]
+// @Router /api/v1/technical-analysis/{symbol}/historical [get]
 func (h *TechnicalAnalysisHandler) GetHistoricalIndicators(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
 		return
 	}
 
@@ -185,19 +214,13 @@ func (h *TechnicalAnalysisHandler) GetHistoricalIndicators(c *gin.Context) {
 
 	from, err := time.Parse(time.RFC3339, fromStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Invalid 'from' date format. Use RFC3339 format",
-		})
+		respondInvalid(c, "Invalid 'from' date format. Use RFC3339 format", nil)
 		return
 	}
 
 	to, err := time.Parse(time.RFC3339, toStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Invalid 'to' date format. Use RFC3339 format",
-		})
+		respondInvalid(c, "Invalid 'to' date format. Use RFC3339 format", nil)
 		return
 	}
 
@@ -219,18 +242,76 @@ func (h *TechnicalAnalysisHandler) GetHistoricalIndicators(c *gin.Context) {
 		Offset: offset,
 	}
 
-	indicators, err := h.db.GetTechnicalIndicators(filter)
+	if notModified(c, db.GetTechnicalIndicatorsVersion, symbol, isHistorical(to)) {
+		return
+	}
+
+	indicators, err := db.GetTechnicalIndicators(filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get historical indicators: " + err.Error(),
-		})
+		respondError(c, "Failed to get historical indicators", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, indicators)
 }
 
+// GetMACDSeries godoc
+// @Summary Get historical MACD series for a symbol
+// @Description Get the MACD line, signal line and histogram for every bar in a date range, for charting
+// @Tags technical-analysis
+// @Accept json
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Param from query string false "Start date (RFC3339 format)"
+// @Param to query string false "End date (RFC3339 format)"
+// @Param timeframe query string false "Candle timeframe (1m, 5m, 15m, 1h, 1d)"
+// @Success 200 {array} models.MACDPoint
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/indicators/{symbol}/macd [get]
+func (h *TechnicalAnalysisHandler) GetMACDSeries(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	symbol := c.Param("symbol")
+	if symbol == "" {
+		respondInvalid(c, "Symbol parameter is required", nil)
+		return
+	}
+
+	fromStr := c.DefaultQuery("from", time.Now().AddDate(0, 0, -7).Format(time.RFC3339))
+	toStr := c.DefaultQuery("to", time.Now().Format(time.RFC3339))
+
+	from, err := time.Parse(time.RFC3339, fromStr)
+	if err != nil {
+		respondInvalid(c, "Invalid 'from' date format. Use RFC3339 format", nil)
+		return
+	}
+
+	to, err := time.Parse(time.RFC3339, toStr)
+	if err != nil {
+		respondInvalid(c, "Invalid 'to' date format. Use RFC3339 format", nil)
+		return
+	}
+
+	timeframe, err := models.ParseTimeframe(c.Query("timeframe"))
+	if err != nil {
+		respondInvalid(c, "", err)
+		return
+	}
+
+	if notModified(c, db.GetPriceDataVersion, symbol, isHistorical(to)) {
+		return
+	}
+
+	series, err := h.taService.GetMACDSeries(c.Request.Context(), symbol, from, to, timeframe)
+	if err != nil {
+		respondError(c, "Failed to get MACD series", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
 // UpdateIndicators godoc
 // @Summary Update technical indicators for a symbol
 // @Description Force update of technical indicators for a specific symbol
@@ -241,39 +322,32 @@ func (h *TechnicalAnalysisHandler) GetHistoricalIndicators(c *gin.Context) {
 // @Success 200 {object} models.TechnicalIndicatorsResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/technical-analysis/{symbol}/update [post]
+// @Router /api/v1/technical-analysis/{symbol}/update [post]
 func (h *TechnicalAnalysisHandler) UpdateIndicators(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
 		return
 	}
 
 	// Force update by invalidating cache and recalculating
-	err := h.taService.UpdateIndicatorsForSymbol(symbol)
+	err := h.taService.UpdateIndicatorsForSymbol(c.Request.Context(), symbol)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to update indicators: " + err.Error(),
-		})
+		respondError(c, "Failed to update indicators", err)
 		return
 	}
 
 	// Get fresh indicators
-	indicators, err := h.taService.GetIndicators(symbol)
+	indicators, err := h.taService.GetIndicators(c.Request.Context(), symbol)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get updated indicators: " + err.Error(),
-		})
+		respondError(c, "Failed to get updated indicators", err)
 		return
 	}
 
 	// Store indicators in database
-	err = h.db.InsertTechnicalIndicators(indicators)
+	err = db.InsertTechnicalIndicators(indicators)
 	if err != nil {
 		// Log error but don't fail the request
 		c.Header("X-Warning", "Failed to store indicators in database: "+err.Error())
@@ -296,7 +370,7 @@ func (h *TechnicalAnalysisHandler) UpdateIndicators(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} map[string]interface{}
-// @Router /api/technical-analysis/cache/status [get]
+// @Router /api/v1/technical-analysis/cache/status [get]
 func (h *TechnicalAnalysisHandler) GetCacheStatus(c *gin.Context) {
 	status := h.taService.GetCacheStatus()
 	c.JSON(http.StatusOK, status)
@@ -309,7 +383,7 @@ func (h *TechnicalAnalysisHandler) GetCacheStatus(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} map[string]string
-// @Router /api/technical-analysis/cache/clear [post]
+// @Router /api/v1/technical-analysis/cache/clear [post]
 func (h *TechnicalAnalysisHandler) ClearCache(c *gin.Context) {
 	h.taService.ClearExpiredCache()
 	c.JSON(http.StatusOK, gin.H{
@@ -327,14 +401,11 @@ func (h *TechnicalAnalysisHandler) ClearCache(c *gin.Context) {
 // @Param symbol path string true "Stock symbol"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} models.ErrorResponse
-// @Router /api/technical-analysis/{symbol}/cache/invalidate [post]
+// @Router /api/v1/technical-analysis/{symbol}/cache/invalidate [post]
 func (h *TechnicalAnalysisHandler) InvalidateSymbolCache(c *gin.Context) {
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
 		return
 	}
 
@@ -355,14 +426,11 @@ func (h *TechnicalAnalysisHandler) InvalidateSymbolCache(c *gin.Context) {
 // @Success 200 {array} models.IndicatorAlert
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/technical-analysis/{symbol}/alerts [get]
+// @Router /api/v1/technical-analysis/{symbol}/alerts [get]
 func (h *TechnicalAnalysisHandler) CheckAlerts(c *gin.Context) {
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
 		return
 	}
 
@@ -377,12 +445,9 @@ func (h *TechnicalAnalysisHandler) CheckAlerts(c *gin.Context) {
 		BBOversold:    true,
 	}
 
-	alerts, err := h.taService.CheckIndicatorAlerts(symbol, thresholds)
+	alerts, err := h.taService.CheckIndicatorAlerts(c.Request.Context(), symbol, thresholds)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to check alerts: " + err.Error(),
-		})
+		respondError(c, "Failed to check alerts", err)
 		return
 	}
 
@@ -399,23 +464,19 @@ func (h *TechnicalAnalysisHandler) CheckAlerts(c *gin.Context) {
 // @Success 200 {array} models.IndicatorAlert
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/technical-analysis/{symbol}/alerts/active [get]
+// @Router /api/v1/technical-analysis/{symbol}/alerts/active [get]
 func (h *TechnicalAnalysisHandler) GetActiveAlerts(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
 		return
 	}
 
-	alerts, err := h.db.GetActiveIndicatorAlerts(symbol)
+	alerts, err := db.GetActiveIndicatorAlerts(symbol)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get active alerts: " + err.Error(),
-		})
+		respondError(c, "Failed to get active alerts", err)
 		return
 	}
 
@@ -430,19 +491,18 @@ func (h *TechnicalAnalysisHandler) GetActiveAlerts(c *gin.Context) {
 // @Produce json
 // @Success 200 {object} map[string]interface{}
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/technical-analysis/stats [get]
+// @Router /api/v1/technical-analysis/stats [get]
 func (h *TechnicalAnalysisHandler) GetStats(c *gin.Context) {
-	stats, err := h.db.GetTechnicalIndicatorsStats()
+	db := withRequestContext(c, h.db)
+
+	stats, err := db.GetTechnicalIndicatorsStats()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to get statistics: " + err.Error(),
-		})
+		respondError(c, "Failed to get statistics", err)
 		return
 	}
 
 	// Add alert statistics
-	alertStats, err := h.db.GetIndicatorAlertsStats()
+	alertStats, err := db.GetIndicatorAlertsStats()
 	if err != nil {
 		c.Header("X-Warning", "Failed to get alert statistics: "+err.Error())
 	} else {
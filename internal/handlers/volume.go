@@ -18,28 +18,40 @@ import (
 type VolumeHandler struct {
 	db             *database.DB
 	collector      *services.CollectorService
-	polygon        *services.PolygonService
+	provider       services.MarketDataProvider
 	patternService *services.PatternDetectionService
+	rvolService    *services.RVOLService
+	symbolSearch   *services.SymbolSearchService
+	display        *services.DisplayService
 }
 
 // NewVolumeHandler creates a new volume handler
-func NewVolumeHandler(db *database.DB, collector *services.CollectorService, polygon *services.PolygonService, patternService *services.PatternDetectionService) *VolumeHandler {
+func NewVolumeHandler(db *database.DB, collector *services.CollectorService, provider services.MarketDataProvider, patternService *services.PatternDetectionService) *VolumeHandler {
 	return &VolumeHandler{
 		db:             db,
 		collector:      collector,
-		polygon:        polygon,
+		provider:       provider,
 		patternService: patternService,
 	}
 }
 
+// SetRVOLService enables the intraday relative volume endpoint
+func (vh *VolumeHandler) SetRVOLService(rvolService *services.RVOLService) {
+	vh.rvolService = rvolService
+}
+
+// SetDisplayService sets the time zone chart and summary responses are localized to by default
+func (vh *VolumeHandler) SetDisplayService(display *services.DisplayService) {
+	vh.display = display
+}
+
 // GetVolumeData handles GET /api/volume/:symbol
 func (vh *VolumeHandler) GetVolumeData(c *gin.Context) {
+	db := withRequestContext(c, vh.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
 		return
 	}
 
@@ -60,10 +72,7 @@ func (vh *VolumeHandler) GetVolumeData(c *gin.Context) {
 		if err != nil {
 			parsedFrom, err = time.Parse("2006-01-02T15:04:05Z", fromStr)
 			if err != nil {
-				c.JSON(http.StatusBadRequest, models.ErrorResponse{
-					Error:   "invalid_from_date",
-					Message: "Invalid from date format. Use YYYY-MM-DD or RFC3339",
-				})
+				respondInvalid(c, "Invalid from date format. Use YYYY-MM-DD or RFC3339", nil)
 				return
 			}
 		}
@@ -76,10 +85,7 @@ func (vh *VolumeHandler) GetVolumeData(c *gin.Context) {
 		if err != nil {
 			parsedTo, err = time.Parse("2006-01-02T15:04:05Z", toStr)
 			if err != nil {
-				c.JSON(http.StatusBadRequest, models.ErrorResponse{
-					Error:   "invalid_to_date",
-					Message: "Invalid to date format. Use YYYY-MM-DD or RFC3339",
-				})
+				respondInvalid(c, "Invalid to date format. Use YYYY-MM-DD or RFC3339", nil)
 				return
 			}
 		}
@@ -112,12 +118,9 @@ func (vh *VolumeHandler) GetVolumeData(c *gin.Context) {
 	}
 
 	// Get data from database
-	data, err := vh.db.GetVolumeData(filter)
+	data, err := db.GetVolumeData(filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "database_error",
-			Message: "Failed to retrieve volume data",
-		})
+		respondError(c, "Failed to retrieve volume data", nil)
 		return
 	}
 
@@ -142,38 +145,39 @@ func (vh *VolumeHandler) GetVolumeData(c *gin.Context) {
 
 // GetLatestVolumeData handles GET /api/volume/:symbol/latest
 func (vh *VolumeHandler) GetLatestVolumeData(c *gin.Context) {
+	db := withRequestContext(c, vh.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
 		return
 	}
 
 	// Get latest data from database
-	data, err := vh.db.GetLatestVolumeData(symbol)
+	data, err := db.GetLatestVolumeData(symbol)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "database_error",
-			Message: "Failed to retrieve latest volume data",
-		})
+		respondError(c, "Failed to retrieve latest volume data", nil)
 		return
 	}
 
 	if data == nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Error:   "not_found",
-			Message: "No volume data found for symbol",
-		})
+		respondNotFound(c, "No volume data found for symbol", nil)
 		return
 	}
 
 	c.JSON(http.StatusOK, data)
 }
 
-// GetDashboardSummary handles GET /api/dashboard/summary
+// GetDashboardSummary handles GET /api/dashboard/summary, with times localized to the tz parameter or the
+// display setting
 func (vh *VolumeHandler) GetDashboardSummary(c *gin.Context) {
+	db := withRequestContext(c, vh.db)
+
+	loc, ok := responseLocation(c, vh.display)
+	if !ok {
+		return
+	}
+
 	daysStr := c.DefaultQuery("days", "7")
 	days, err := strconv.Atoi(daysStr)
 	if err != nil || days < 1 {
@@ -184,12 +188,9 @@ func (vh *VolumeHandler) GetDashboardSummary(c *gin.Context) {
 	}
 
 	// Get watched symbols from database
-	symbols, err := vh.db.GetWatchedSymbols()
+	symbols, err := db.GetWatchedSymbols()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "database_error",
-			Message: "Failed to retrieve watched symbols",
-		})
+		respondError(c, "Failed to retrieve watched symbols", nil)
 		return
 	}
 
@@ -198,11 +199,12 @@ func (vh *VolumeHandler) GetDashboardSummary(c *gin.Context) {
 
 	// Get stats for each symbol
 	for _, symbol := range symbols {
-		stats, err := vh.db.GetVolumeStats(symbol, days)
+		stats, err := db.GetVolumeStats(symbol, days)
 		if err != nil {
 			continue // Skip symbols with errors
 		}
 		if stats != nil {
+			stats.LastUpdate = stats.LastUpdate.In(loc)
 			volumeStats = append(volumeStats, *stats)
 			if stats.LastUpdate.After(lastUpdate) {
 				lastUpdate = stats.LastUpdate
@@ -215,19 +217,25 @@ func (vh *VolumeHandler) GetDashboardSummary(c *gin.Context) {
 		Symbols:        volumeStats,
 		LastUpdate:     lastUpdate,
 		CollectionMode: "24/7", // Indicates continuous data collection
+		Timezone:       loc.String(),
 	}
 
 	c.JSON(http.StatusOK, summary)
 }
 
-// GetChartData handles GET /api/volume/:symbol/chart
+// GetChartData handles GET /api/volume/:symbol/chart, with point times localized to the tz parameter or the
+// display setting
 func (vh *VolumeHandler) GetChartData(c *gin.Context) {
+	db := withRequestContext(c, vh.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
+		return
+	}
+
+	loc, ok := responseLocation(c, vh.display)
+	if !ok {
 		return
 	}
 
@@ -255,6 +263,10 @@ func (vh *VolumeHandler) GetChartData(c *gin.Context) {
 		to = now
 	}
 
+	if notModified(c, db.GetVolumeDataVersion, symbol, false) {
+		return
+	}
+
 	// Get data from database
 	filter := &models.VolumeDataFilter{
 		Symbol: symbol,
@@ -263,12 +275,9 @@ func (vh *VolumeHandler) GetChartData(c *gin.Context) {
 		Limit:  1000,
 	}
 
-	data, err := vh.db.GetVolumeData(filter)
+	data, err := db.GetVolumeData(filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "database_error",
-			Message: "Failed to retrieve chart data",
-		})
+		respondError(c, "Failed to retrieve chart data", nil)
 		return
 	}
 
@@ -276,7 +285,7 @@ func (vh *VolumeHandler) GetChartData(c *gin.Context) {
 	chartPoints := make([]models.ChartDataPoint, 0)
 	for _, vd := range data {
 		chartPoints = append(chartPoints, models.ChartDataPoint{
-			X: vd.Timestamp.Format(time.RFC3339),
+			X: vd.Timestamp.In(loc).Format(time.RFC3339),
 			Y: vd.Volume,
 		})
 	}
@@ -305,14 +314,43 @@ func (vh *VolumeHandler) GetChartData(c *gin.Context) {
 	c.JSON(http.StatusOK, chartData)
 }
 
+// GetRVOL handles GET /api/volume/:symbol/rvol
+func (vh *VolumeHandler) GetRVOL(c *gin.Context) {
+	if vh.rvolService == nil {
+		respondUnavailable(c, "Relative volume is not available", nil)
+		return
+	}
+
+	symbol := strings.ToUpper(c.Param("symbol"))
+	if symbol == "" {
+		respondInvalid(c, "Symbol parameter is required", nil)
+		return
+	}
+
+	days := 0
+	if daysStr := c.Query("days"); daysStr != "" {
+		parsed, err := strconv.Atoi(daysStr)
+		if err != nil || parsed < 1 || parsed > models.MaxRVOLLookbackDays {
+			respondInvalid(c, fmt.Sprintf("days must be between 1 and %d", models.MaxRVOLLookbackDays), nil)
+			return
+		}
+		days = parsed
+	}
+
+	curve, err := vh.rvolService.Compute(symbol, days)
+	if err != nil {
+		respondError(c, "Failed to compute relative volume", nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, curve)
+}
+
 // ForceCollection handles POST /api/collection/force
 func (vh *VolumeHandler) ForceCollection(c *gin.Context) {
 	err := vh.collector.ForceCollection()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "collection_error",
-			Message: "Failed to force collection",
-		})
+		respondError(c, "Failed to force collection", err)
 		return
 	}
 
@@ -330,6 +368,8 @@ func (vh *VolumeHandler) GetCollectionStatus(c *gin.Context) {
 
 // HealthCheck handles GET /api/health
 func (vh *VolumeHandler) HealthCheck(c *gin.Context) {
+	db := withRequestContext(c, vh.db)
+
 	health := models.HealthResponse{
 		Status:    "ok",
 		Timestamp: time.Now(),
@@ -337,7 +377,7 @@ func (vh *VolumeHandler) HealthCheck(c *gin.Context) {
 	}
 
 	// Check database health
-	if err := vh.db.HealthCheck(); err != nil {
+	if err := db.HealthCheck(); err != nil {
 		health.Services["database"] = models.ServiceHealth{
 			Status:  "error",
 			Message: err.Error(),
@@ -349,15 +389,15 @@ func (vh *VolumeHandler) HealthCheck(c *gin.Context) {
 		}
 	}
 
-	// Check Polygon service health
-	if err := vh.polygon.HealthCheck(); err != nil {
-		health.Services["polygon"] = models.ServiceHealth{
+	// Check market data provider health
+	if err := vh.provider.HealthCheck(); err != nil {
+		health.Services[vh.provider.Name()] = models.ServiceHealth{
 			Status:  "error",
 			Message: err.Error(),
 		}
 		health.Status = "degraded"
 	} else {
-		health.Services["polygon"] = models.ServiceHealth{
+		health.Services[vh.provider.Name()] = models.ServiceHealth{
 			Status: "ok",
 		}
 	}
@@ -385,12 +425,11 @@ func (vh *VolumeHandler) HealthCheck(c *gin.Context) {
 
 // GetWatchedSymbols handles GET /api/symbols
 func (vh *VolumeHandler) GetWatchedSymbols(c *gin.Context) {
-	symbols, err := vh.db.GetWatchedSymbolsWithDetails()
+	db := withRequestContext(c, vh.db)
+
+	symbols, err := db.GetWatchedSymbolsWithDetails()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "database_error",
-			Message: "Failed to retrieve watched symbols",
-		})
+		respondError(c, "Failed to retrieve watched symbols", nil)
 		return
 	}
 
@@ -405,33 +444,32 @@ func (vh *VolumeHandler) GetWatchedSymbols(c *gin.Context) {
 
 // AddWatchedSymbol handles POST /api/symbols
 func (vh *VolumeHandler) AddWatchedSymbol(c *gin.Context) {
+	db := withRequestContext(c, vh.db)
+
 	var req models.WatchedSymbolRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "invalid_request",
-			Message: "Invalid request format",
-		})
+		respondInvalid(c, "Invalid request format", nil)
 		return
 	}
 
-	// Validate symbol format (basic validation)
-	if len(req.Symbol) < 1 || len(req.Symbol) > 10 {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "invalid_symbol",
-			Message: "Symbol must be between 1 and 10 characters",
-		})
+	// Stocks, crypto (X:BTCUSD) and FX pairs (C:EURUSD) can be watched
+	symbol, err := models.ValidateSymbol(req.Symbol)
+	if err != nil {
+		respondInvalid(c, "Invalid symbol", err)
 		return
 	}
+	req.Symbol = symbol
 
-	// Convert to uppercase
-	req.Symbol = strings.ToUpper(req.Symbol)
+	name, ok := checkNewSymbol(c, vh.symbolSearch, req.Symbol)
+	if !ok {
+		return
+	}
+	if req.Name == "" {
+		req.Name = name
+	}
 
-	err := vh.db.AddWatchedSymbol(req.Symbol, req.Name)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "database_error",
-			Message: "Failed to add watched symbol",
-		})
+	if err := db.AddWatchedSymbol(req.Symbol, req.Name); err != nil {
+		respondError(c, "Failed to add watched symbol", nil)
 		return
 	}
 
@@ -450,36 +488,30 @@ func (vh *VolumeHandler) AddWatchedSymbol(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Symbol added successfully",
 		"symbol":  req.Symbol,
+		"name":    req.Name,
 	})
 }
 
 // RemoveWatchedSymbol handles DELETE /api/symbols/:symbol
 func (vh *VolumeHandler) RemoveWatchedSymbol(c *gin.Context) {
+	db := withRequestContext(c, vh.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
 		return
 	}
 
 	symbol = strings.ToUpper(symbol)
 
-	err := vh.db.RemoveWatchedSymbol(symbol)
+	err := db.RemoveWatchedSymbol(symbol)
 	if err != nil {
 		if err.Error() == fmt.Sprintf("symbol not found: %s", symbol) {
-			c.JSON(http.StatusNotFound, models.ErrorResponse{
-				Error:   "symbol_not_found",
-				Message: "Symbol not found in watched list",
-			})
+			respondNotFound(c, "Symbol not found in watched list", nil)
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "database_error",
-			Message: "Failed to remove watched symbol",
-		})
+		respondError(c, "Failed to remove watched symbol", nil)
 		return
 	}
 
@@ -491,24 +523,20 @@ func (vh *VolumeHandler) RemoveWatchedSymbol(c *gin.Context) {
 
 // CheckSymbolData handles GET /api/symbols/:symbol/check - checks if symbol has data
 func (vh *VolumeHandler) CheckSymbolData(c *gin.Context) {
+	db := withRequestContext(c, vh.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
 		return
 	}
 
 	symbol = strings.ToUpper(symbol)
 
 	// Check if we have any data for this symbol
-	latestData, err := vh.db.GetLatestVolumeData(symbol)
+	latestData, err := db.GetLatestVolumeData(symbol)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "database_error",
-			Message: "Failed to check symbol data",
-		})
+		respondError(c, "Failed to check symbol data", nil)
 		return
 	}
 
@@ -518,7 +546,7 @@ func (vh *VolumeHandler) CheckSymbolData(c *gin.Context) {
 
 	if hasData {
 		// Get data count for this symbol
-		counts, err := vh.db.GetDataCountBySymbol()
+		counts, err := db.GetDataCountBySymbol()
 		if err == nil {
 			dataCount = counts[symbol]
 		}
@@ -535,24 +563,20 @@ func (vh *VolumeHandler) CheckSymbolData(c *gin.Context) {
 
 // CollectSymbolData handles POST /api/symbols/:symbol/collect - manually trigger data collection
 func (vh *VolumeHandler) CollectSymbolData(c *gin.Context) {
+	db := withRequestContext(c, vh.db)
+
 	symbol := c.Param("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "bad_request",
-			Message: "Symbol parameter is required",
-		})
+		respondInvalid(c, "Symbol parameter is required", nil)
 		return
 	}
 
 	symbol = strings.ToUpper(symbol)
 
 	// Check if symbol is being watched
-	watchedSymbols, err := vh.db.GetWatchedSymbols()
+	watchedSymbols, err := db.GetWatchedSymbols()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "database_error",
-			Message: "Failed to check watched symbols",
-		})
+		respondError(c, "Failed to check watched symbols", nil)
 		return
 	}
 
@@ -565,10 +589,7 @@ func (vh *VolumeHandler) CollectSymbolData(c *gin.Context) {
 	}
 
 	if !isWatched {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "symbol_not_watched",
-			Message: "Symbol is not in the watchlist",
-		})
+		respondInvalid(c, "Symbol is not in the watchlist", nil)
 		return
 	}
 
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VolumeProfileHandler handles volume-by-price API endpoints
+type VolumeProfileHandler struct {
+	volumeProfile *services.VolumeProfileService
+}
+
+// NewVolumeProfileHandler creates a new volume profile handler
+func NewVolumeProfileHandler(volumeProfile *services.VolumeProfileService) *VolumeProfileHandler {
+	return &VolumeProfileHandler{
+		volumeProfile: volumeProfile,
+	}
+}
+
+// GetVolumeProfile godoc
+// @Summary Get volume profile
+// @Description Get a symbol's volume by price with the point of control, value area and high-volume nodes, for use as a chart overlay. Without days or buckets the stored profile for the configured window is returned, recomputed when stale; custom windows are computed on demand and not stored.
+// @Tags volume
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Param days query int false "Calendar days of bars in the profile (default from config)"
+// @Param buckets query int false "Number of price buckets (default from config)"
+// @Param refresh query bool false "Recompute and store the configured profile now"
+// @Success 200 {object} models.VolumeProfile
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/volume-profile/{symbol} [get]
+func (h *VolumeProfileHandler) GetVolumeProfile(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+
+	var days, buckets int
+	var err error
+	if value := c.Query("days"); value != "" {
+		if days, err = strconv.Atoi(value); err != nil || days < 1 {
+			respondInvalid(c, "Invalid days parameter", nil)
+			return
+		}
+	}
+	if value := c.Query("buckets"); value != "" {
+		if buckets, err = strconv.Atoi(value); err != nil || buckets < 1 || buckets > models.MaxVolumeProfileBuckets {
+			respondInvalid(c, "Invalid buckets parameter, expected 1 to "+strconv.Itoa(models.MaxVolumeProfileBuckets), nil)
+			return
+		}
+	}
+
+	var profile *models.VolumeProfile
+	switch {
+	case days > 0 || buckets > 0:
+		profile, err = h.volumeProfile.Compute(symbol, days, buckets)
+	case c.Query("refresh") == "true":
+		profile, err = h.volumeProfile.Refresh(symbol)
+	default:
+		profile, err = h.volumeProfile.Profile(symbol)
+	}
+	if err != nil {
+		respondError(c, "Failed to compute volume profile", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
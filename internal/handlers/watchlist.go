@@ -16,33 +16,56 @@ import (
 type WatchlistHandler struct {
 	db           *database.Database
 	stockService *services.StockService
+	transfer     *services.WatchlistTransferService
+	reference    *services.ReferenceDataService
+	symbolSearch *services.SymbolSearchService
+	bulk         *services.WatchlistBulkService
+	jobs         *services.JobService
+	collector    *services.CollectorService
+	automations  *services.StrategyAutomationService
 }
 
 // NewWatchlistHandler creates a new watchlist handler
 func NewWatchlistHandler(db *database.Database, stockService *services.StockService) *WatchlistHandler {
-	return &WatchlistHandler{db: db, stockService: stockService}
+	return &WatchlistHandler{
+		db:           db,
+		stockService: stockService,
+		transfer:     services.NewWatchlistTransferService(db),
+		bulk:         services.NewWatchlistBulkService(db),
+	}
+}
+
+// SetSymbolSearch sets the service used to check symbols before they are added
+func (h *WatchlistHandler) SetSymbolSearch(search *services.SymbolSearchService) {
+	h.symbolSearch = search
+	h.bulk.SetSymbolSearch(search)
+}
+
+// SetReferenceDataService sets the service that enriches newly added stocks with reference data
+func (h *WatchlistHandler) SetReferenceDataService(reference *services.ReferenceDataService) {
+	h.reference = reference
 }
 
 // Strategy Endpoints
 
 // GetStrategies returns all watchlist strategies
 func (h *WatchlistHandler) GetStrategies(c *gin.Context) {
-	strategies, err := h.db.GetStrategies()
+	db := withRequestContext(c, h.db)
+
+	strategies, err := db.GetStrategies()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to fetch strategies",
-			"details": err.Error(),
-		})
+		respondError(c, "Failed to fetch strategies", err)
 		return
 	}
 
 	for i, strategy := range strategies {
-		stocks, err := h.db.GetStocksByStrategy(strategy.ID)
+		stocks, err := db.GetStocksByStrategy(strategy.ID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to fetch stocks for strategy",
-				"details": err.Error(),
-			})
+			respondError(c, "Failed to fetch stocks for strategy", err)
+			return
+		}
+		if err := h.attachReferences(stocks); err != nil {
+			respondError(c, "Failed to fetch reference data", err)
 			return
 		}
 		strategies[i].Stocks = stocks
@@ -56,20 +79,17 @@ func (h *WatchlistHandler) GetStrategies(c *gin.Context) {
 
 // CreateStrategy creates a new watchlist strategy
 func (h *WatchlistHandler) CreateStrategy(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	var strategy models.Strategy
 	if err := c.ShouldBindJSON(&strategy); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request body",
-			"details": err.Error(),
-		})
+		respondInvalid(c, "Invalid request body", err)
 		return
 	}
 
 	// Validate required fields
 	if strategy.Name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Strategy name is required",
-		})
+		respondInvalid(c, "Strategy name is required", nil)
 		return
 	}
 
@@ -78,12 +98,9 @@ func (h *WatchlistHandler) CreateStrategy(c *gin.Context) {
 		strategy.Color = "#007bff"
 	}
 
-	createdStrategy, err := h.db.CreateStrategy(strategy)
+	createdStrategy, err := db.CreateStrategy(strategy)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to create strategy",
-			"details": err.Error(),
-		})
+		respondError(c, "Failed to create strategy", err)
 		return
 	}
 
@@ -95,28 +112,23 @@ func (h *WatchlistHandler) CreateStrategy(c *gin.Context) {
 
 // UpdateStrategy updates an existing strategy
 func (h *WatchlistHandler) UpdateStrategy(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid strategy ID",
-		})
+		respondInvalid(c, "Invalid strategy ID", nil)
 		return
 	}
 
 	var strategy models.Strategy
 	if err := c.ShouldBindJSON(&strategy); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request body",
-			"details": err.Error(),
-		})
+		respondInvalid(c, "Invalid request body", err)
 		return
 	}
 
 	// Validate required fields
 	if strategy.Name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Strategy name is required",
-		})
+		respondInvalid(c, "Strategy name is required", nil)
 		return
 	}
 
@@ -125,11 +137,8 @@ func (h *WatchlistHandler) UpdateStrategy(c *gin.Context) {
 		strategy.Color = "#007bff"
 	}
 
-	if err := h.db.UpdateStrategy(id, strategy); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to update strategy",
-			"details": err.Error(),
-		})
+	if err := db.UpdateStrategy(id, strategy); err != nil {
+		respondError(c, "Failed to update strategy", err)
 		return
 	}
 
@@ -140,19 +149,16 @@ func (h *WatchlistHandler) UpdateStrategy(c *gin.Context) {
 
 // DeleteStrategy deletes a strategy
 func (h *WatchlistHandler) DeleteStrategy(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid strategy ID",
-		})
+		respondInvalid(c, "Invalid strategy ID", nil)
 		return
 	}
 
-	if err := h.db.DeleteStrategy(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to delete strategy",
-			"details": err.Error(),
-		})
+	if err := db.DeleteStrategy(id); err != nil {
+		respondError(c, "Failed to delete strategy", err)
 		return
 	}
 
@@ -163,77 +169,109 @@ func (h *WatchlistHandler) DeleteStrategy(c *gin.Context) {
 
 // Stocks Endpoints
 
-// GetStocks returns watchlist stocks
+// GetStocks returns watchlist stocks with their reference data, optionally filtered by sector,
+// market cap, average volume or float and grouped by sector, industry or exchange
 func (h *WatchlistHandler) GetStocks(c *gin.Context) {
-	stocks, err := h.db.GetStocks()
+	db := withRequestContext(c, h.db)
+
+	filter, err := parseStockReferenceFilter(c)
 	if err != nil {
-		c.Error(err) // Attach error for middleware logging
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to fetch stocks",
-			"details": err.Error(),
-		})
+		respondInvalid(c, "Invalid filter", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	stocks, err := db.GetStocks()
+	if err == nil {
+		err = h.attachReferences(stocks)
+	}
+	if err != nil {
+		respondError(c, "Failed to fetch stocks", err)
+		return
+	}
+	stocks = services.FilterStocksByReference(stocks, filter)
+	if tag := c.Query("tag"); tag != "" {
+		stocks = filterStocksByTag(stocks, tag)
+	}
+
+	response := gin.H{
 		"stocks": stocks,
 		"count":  len(stocks),
-	})
+	}
+	if groupBy := c.Query("group_by"); groupBy != "" {
+		groups, err := services.GroupStocksByReference(stocks, groupBy)
+		if err != nil {
+			respondInvalid(c, "Invalid grouping", err)
+			return
+		}
+		response["groups"] = groups
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // AddStock adds a new stock to the watchlist
 func (h *WatchlistHandler) AddStock(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	var stock models.Stock
 	if err := c.ShouldBindJSON(&stock); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request body",
-			"details": err.Error(),
-		})
+		respondInvalid(c, "Invalid request body", err)
 		return
 	}
 
 	// Validate required fields
 	if stock.Symbol == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Stock symbol is required",
-		})
+		respondInvalid(c, "Stock symbol is required", nil)
+		return
+	}
+	symbol, err := models.ValidateSymbol(stock.Symbol)
+	if err != nil {
+		respondInvalid(c, "Invalid symbol", err)
+		return
+	}
+	stock.Symbol = symbol
+
+	name, ok := checkNewSymbol(c, h.symbolSearch, stock.Symbol)
+	if !ok {
+		return
+	}
+	if stock.Name == "" {
+		stock.Name = name
+	}
+
+	tags, err := models.NormalizeTags(stock.Tags)
+	if err != nil {
+		respondInvalid(c, "Invalid tags", err)
 		return
 	}
+	stock.Tags = tags
 
 	// Validate strategy existence
 	if len(stock.Strategies) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "At least one strategy ID must be provided",
-		})
+		respondInvalid(c, "At least one strategy ID must be provided", nil)
 		return
 	}
 
 	for _, strategy := range stock.Strategies {
-		exists, err := h.db.StrategyExists(strategy.ID)
+		exists, err := db.StrategyExists(strategy.ID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to validate strategy existence",
-				"details": err.Error(),
-			})
+			respondError(c, "Failed to validate strategy existence", err)
 			return
 		}
 		if !exists {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": fmt.Sprintf("Strategy with ID %d does not exist", strategy.ID),
-			})
+			respondInvalid(c, fmt.Sprintf("Strategy with ID %d does not exist", strategy.ID), nil)
 			return
 		}
 	}
 
-	addedStock, err := h.db.AddStock(stock)
+	addedStock, err := db.AddStock(stock)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to add stock",
-			"details": err.Error(),
-		})
+		respondError(c, "Failed to add stock", err)
 		return
 	}
 
+	h.reference.RefreshSymbolAsync(addedStock.Symbol)
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Stock added successfully",
 		"stock":   addedStock,
@@ -242,55 +280,57 @@ func (h *WatchlistHandler) AddStock(c *gin.Context) {
 
 // UpdateStock updates an existing stock
 func (h *WatchlistHandler) UpdateStock(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid stock ID",
-		})
+		respondInvalid(c, "Invalid stock ID", nil)
 		return
 	}
 
 	var stock models.Stock
 	if err := c.ShouldBindJSON(&stock); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request body",
-			"details": err.Error(),
-		})
+		respondInvalid(c, "Invalid request body", err)
+		return
+	}
+
+	tags, err := models.NormalizeTags(stock.Tags)
+	if err != nil {
+		respondInvalid(c, "Invalid tags", err)
 		return
 	}
 
 	// Update only the notes field - other stock data comes from Polygon API
-	if err := h.db.UpdateStockNotes(id, stock.Notes); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to update stock notes",
-			"details": err.Error(),
-		})
+	if err := db.UpdateStockNotes(id, stock.Notes); err != nil {
+		respondError(c, "Failed to update stock notes", err)
 		return
 	}
 
 	// Handle strategy assignments if provided
 	if stock.Strategies != nil && len(stock.Strategies) > 0 {
 		// First, remove all existing strategy associations
-		if err := h.db.RemoveAllStockStrategies(id); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to update stock strategies",
-				"details": err.Error(),
-			})
+		if err := db.RemoveAllStockStrategies(id); err != nil {
+			respondError(c, "Failed to update stock strategies", err)
 			return
 		}
 
 		// Then add the new ones
 		for _, strategy := range stock.Strategies {
-			if err := h.db.AddStockToStrategy(id, strategy.ID); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error":   "Failed to add stock to strategy",
-					"details": err.Error(),
-				})
+			if err := db.AddStockToStrategy(id, strategy.ID); err != nil {
+				respondError(c, "Failed to add stock to strategy", err)
 				return
 			}
 		}
 	}
 
+	// Replace the tags if provided
+	if stock.Tags != nil {
+		if err := db.SetStockTags(id, tags); err != nil {
+			respondError(c, "Failed to update stock tags", err)
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Stock updated successfully",
 	})
@@ -298,17 +338,19 @@ func (h *WatchlistHandler) UpdateStock(c *gin.Context) {
 
 // RemoveStock removes a stock from current strategy and deletes if no strategies remain
 func (h *WatchlistHandler) RemoveStock(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
 	stockID, _ := strconv.Atoi(c.Param("id"))
 	strategyID, _ := strconv.Atoi(c.Query("strategy_id"))
 
 	// Remove from specific strategy
-	if err := h.db.RemoveStockFromStrategy(stockID, strategyID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove from strategy"})
+	if err := db.RemoveStockFromStrategy(stockID, strategyID); err != nil {
+		respondError(c, "Failed to remove from strategy", nil)
 		return
 	}
 
 	// Check if stock has any remaining strategies
-	strategies, _ := h.db.GetStockStrategies(stockID)
+	strategies, _ := db.GetStockStrategies(stockID)
 	if len(strategies) == 0 {
 		// Use stock service to delete stock
 		h.stockService.DeleteStock(stockID)
@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// strategyAutomationRequest is the request body for creating or updating a strategy automation
+type strategyAutomationRequest struct {
+	Name            string                  `json:"name"`
+	Action          string                  `json:"action"`
+	Patterns        []string                `json:"patterns"`
+	Conditions      []models.AlertCondition `json:"conditions"`
+	Logic           string                  `json:"logic"`
+	Schedule        string                  `json:"schedule"`
+	IsActive        *bool                   `json:"is_active"`
+	CooldownMinutes *int                    `json:"cooldown_minutes"`
+}
+
+// apply copies the request fields onto an automation, keeping existing values for omitted optional fields
+func (r *strategyAutomationRequest) apply(automation *models.StrategyAutomation) {
+	automation.Name = r.Name
+	automation.Action = r.Action
+	automation.Patterns = r.Patterns
+	automation.Conditions = r.Conditions
+	automation.Logic = r.Logic
+	automation.Schedule = r.Schedule
+	if r.IsActive != nil {
+		automation.IsActive = *r.IsActive
+	}
+	if r.CooldownMinutes != nil {
+		automation.CooldownMinutes = *r.CooldownMinutes
+	}
+}
+
+// SetStrategyAutomation sets the service that validates and runs strategy automations
+func (h *WatchlistHandler) SetStrategyAutomation(automations *services.StrategyAutomationService) {
+	h.automations = automations
+}
+
+// GetStrategyAutomations godoc
+// @Summary List a strategy's automations
+// @Description Get the scans and alerts attached to a watchlist strategy
+// @Tags watchlist
+// @Produce json
+// @Param id path int true "Strategy ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/watchlist/strategies/{id}/automations [get]
+func (h *WatchlistHandler) GetStrategyAutomations(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	strategyID, ok := parseStrategyID(c)
+	if !ok {
+		return
+	}
+
+	automations, err := db.GetStrategyAutomations(strategyID, false)
+	if err != nil {
+		respondError(c, "Failed to get strategy automations", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"automations": automations,
+		"count":       len(automations),
+		"patterns":    models.AutomationPatterns,
+		"fields":      models.AlertFields,
+	})
+}
+
+// CreateStrategyAutomation godoc
+// @Summary Attach an automation to a strategy
+// @Description Attach a pattern scan, e.g. falling wedges every weekday after the close ("30 16 * * 1-5"), or an alert, e.g. any member with rsi_14 < 30, to a watchlist strategy. Schedules are cron expressions in exchange time.
+// @Tags watchlist
+// @Accept json
+// @Produce json
+// @Param id path int true "Strategy ID"
+// @Success 201 {object} models.StrategyAutomation
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/watchlist/strategies/{id}/automations [post]
+func (h *WatchlistHandler) CreateStrategyAutomation(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	strategyID, ok := parseStrategyID(c)
+	if !ok {
+		return
+	}
+
+	var request strategyAutomationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalid(c, "Invalid request body", err)
+		return
+	}
+
+	automation := &models.StrategyAutomation{
+		StrategyID:      strategyID,
+		IsActive:        true,
+		CooldownMinutes: 24 * 60,
+	}
+	request.apply(automation)
+
+	if err := h.automations.Validate(automation); err != nil {
+		respondError(c, "Invalid automation", err)
+		return
+	}
+
+	if err := db.InsertStrategyAutomation(automation); err != nil {
+		respondError(c, "Failed to create strategy automation", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, automation)
+}
+
+// UpdateStrategyAutomation godoc
+// @Summary Update a strategy automation
+// @Tags watchlist
+// @Accept json
+// @Produce json
+// @Param id path int true "Strategy ID"
+// @Param automationId path int true "Automation ID"
+// @Success 200 {object} models.StrategyAutomation
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/watchlist/strategies/{id}/automations/{automationId} [put]
+func (h *WatchlistHandler) UpdateStrategyAutomation(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	automation, ok := h.loadStrategyAutomation(c)
+	if !ok {
+		return
+	}
+
+	var request strategyAutomationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalid(c, "Invalid request body", err)
+		return
+	}
+	request.apply(automation)
+
+	if err := h.automations.Validate(automation); err != nil {
+		respondError(c, "Invalid automation", err)
+		return
+	}
+
+	if err := db.UpdateStrategyAutomation(automation); err != nil {
+		respondError(c, "Failed to update strategy automation", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, automation)
+}
+
+// DeleteStrategyAutomation godoc
+// @Summary Delete a strategy automation
+// @Description Delete a strategy automation and its run history
+// @Tags watchlist
+// @Produce json
+// @Param id path int true "Strategy ID"
+// @Param automationId path int true "Automation ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/watchlist/strategies/{id}/automations/{automationId} [delete]
+func (h *WatchlistHandler) DeleteStrategyAutomation(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	automation, ok := h.loadStrategyAutomation(c)
+	if !ok {
+		return
+	}
+
+	if err := db.DeleteStrategyAutomation(automation.ID); err != nil {
+		respondError(c, "Failed to delete strategy automation", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Strategy automation deleted",
+		"id":      automation.ID,
+	})
+}
+
+// RunStrategyAutomation godoc
+// @Summary Run a strategy automation now
+// @Description Run an automation against the strategy's member stocks without waiting for its schedule; the run is recorded in the strategy's history
+// @Tags watchlist
+// @Produce json
+// @Param id path int true "Strategy ID"
+// @Param automationId path int true "Automation ID"
+// @Success 200 {object} models.StrategyAutomationRun
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/watchlist/strategies/{id}/automations/{automationId}/run [post]
+func (h *WatchlistHandler) RunStrategyAutomation(c *gin.Context) {
+	automation, ok := h.loadStrategyAutomation(c)
+	if !ok {
+		return
+	}
+
+	run, err := h.automations.Run(automation, models.AutomationTriggerManual)
+	if err != nil {
+		respondError(c, "Failed to run strategy automation", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// GetStrategyAutomationRuns godoc
+// @Summary Get a strategy's automation run history
+// @Description Get the runs of a strategy's automations, newest first, optionally for one automation
+// @Tags watchlist
+// @Produce json
+// @Param id path int true "Strategy ID"
+// @Param automation_id query int false "Only runs of this automation"
+// @Param limit query int false "Maximum number of runs"
+// @Success 200 {array} models.StrategyAutomationRun
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/watchlist/strategies/{id}/automations/runs [get]
+func (h *WatchlistHandler) GetStrategyAutomationRuns(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	strategyID, ok := parseStrategyID(c)
+	if !ok {
+		return
+	}
+
+	filter := &models.StrategyAutomationRunFilter{StrategyID: strategyID}
+	if raw := c.Query("automation_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			respondInvalid(c, "Invalid automation ID", nil)
+			return
+		}
+		filter.AutomationID = id
+	}
+	if limit, err := strconv.Atoi(c.DefaultQuery("limit", "100")); err == nil && limit > 0 {
+		filter.Limit = limit
+	}
+
+	runs, err := db.GetStrategyAutomationRuns(filter)
+	if err != nil {
+		respondError(c, "Failed to get strategy automation runs", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, runs)
+}
+
+// loadStrategyAutomation loads the automation named by the automationId path parameter, writing an error
+// response on failure or when it belongs to another strategy
+func (h *WatchlistHandler) loadStrategyAutomation(c *gin.Context) (*models.StrategyAutomation, bool) {
+	db := withRequestContext(c, h.db)
+
+	strategyID, ok := parseStrategyID(c)
+	if !ok {
+		return nil, false
+	}
+	id, err := strconv.ParseInt(c.Param("automationId"), 10, 64)
+	if err != nil {
+		respondInvalid(c, "Invalid automation ID", nil)
+		return nil, false
+	}
+
+	automation, err := db.GetStrategyAutomationByID(id)
+	if err != nil {
+		respondError(c, "Failed to get strategy automation", err)
+		return nil, false
+	}
+
+	if automation == nil || automation.StrategyID != strategyID {
+		respondNotFound(c, "Strategy automation not found", nil)
+		return nil, false
+	}
+
+	return automation, true
+}
+
+// parseStrategyID parses the id path parameter, writing a bad request response on failure
+func parseStrategyID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, "Invalid strategy ID", nil)
+		return 0, false
+	}
+	return id, true
+}
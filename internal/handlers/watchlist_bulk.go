@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetBackfill sets the job queue and collector used to backfill the history of symbols added in bulk
+func (h *WatchlistHandler) SetBackfill(jobs *services.JobService, collector *services.CollectorService) {
+	h.jobs = jobs
+	h.collector = collector
+}
+
+// BulkAddStocks godoc
+// @Summary Add several stocks
+// @Description Add symbols to watchlist strategies in one request, reporting the outcome per symbol. Newly added symbols are collected from now on and their history is backfilled by a single job.
+// @Tags watchlist
+// @Accept json
+// @Produce json
+// @Param request body models.WatchlistBulkAddRequest true "Symbols, strategies, tags and backfill days"
+// @Success 200 {object} models.WatchlistBulkResult
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/watchlist/stocks/bulk/add [post]
+func (h *WatchlistHandler) BulkAddStocks(c *gin.Context) {
+	var req models.WatchlistBulkAddRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, "Invalid request body", err)
+		return
+	}
+
+	result, added, err := h.bulk.Add(&req)
+	if err != nil {
+		respondError(c, "Failed to add stocks", err)
+		return
+	}
+
+	for _, symbol := range added {
+		h.reference.RefreshSymbolAsync(symbol)
+	}
+
+	// One job backfills every new symbol instead of a backfill per symbol
+	if days := services.BackfillDays(&req); days > 0 && len(added) > 0 && h.jobs != nil && h.collector != nil {
+		job, err := h.jobs.Submit(models.JobTypeBackfill, added, map[string]interface{}{"days": days}, h.collector.BackfillTask(days))
+		if err != nil {
+			log.Printf("Failed to queue the backfill of %d added symbols: %v", len(added), err)
+		} else {
+			result.BackfillJob = job
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// BulkRemoveStocks godoc
+// @Summary Remove several stocks
+// @Description Remove symbols from the watchlist, or from one strategy, in one request, reporting the outcome per symbol
+// @Tags watchlist
+// @Accept json
+// @Produce json
+// @Param request body models.WatchlistBulkRemoveRequest true "Symbols, optional strategy and whether to stop collecting them"
+// @Success 200 {object} models.WatchlistBulkResult
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/watchlist/stocks/bulk/remove [post]
+func (h *WatchlistHandler) BulkRemoveStocks(c *gin.Context) {
+	var req models.WatchlistBulkRemoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, "Invalid request body", err)
+		return
+	}
+
+	result, err := h.bulk.Remove(&req)
+	if err != nil {
+		respondError(c, "Failed to remove stocks", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// BulkMoveStocks godoc
+// @Summary Move several stocks
+// @Description Move symbols out of one strategy, or all of their strategies, into another in one request, reporting the outcome per symbol
+// @Tags watchlist
+// @Accept json
+// @Produce json
+// @Param request body models.WatchlistBulkMoveRequest true "Symbols and strategies"
+// @Success 200 {object} models.WatchlistBulkResult
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/watchlist/stocks/bulk/move [post]
+func (h *WatchlistHandler) BulkMoveStocks(c *gin.Context) {
+	var req models.WatchlistBulkMoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, "Invalid request body", err)
+		return
+	}
+
+	result, err := h.bulk.Move(&req)
+	if err != nil {
+		respondError(c, "Failed to move stocks", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
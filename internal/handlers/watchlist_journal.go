@@ -0,0 +1,395 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"market-watch-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// stockTagsRequest is the body of the stock tag endpoints
+type stockTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// journalEntryRequest is the body of the journal create and update endpoints
+type journalEntryRequest struct {
+	Symbol    string   `json:"symbol"` // defaults to the setup's symbol
+	SetupID   *int64   `json:"setup_id"`
+	EntryType string   `json:"entry_type"`
+	EntryDate string   `json:"entry_date"` // RFC3339 or YYYY-MM-DD, defaults to now
+	Title     string   `json:"title"`
+	Body      string   `json:"body"`
+	Tags      []string `json:"tags"`
+}
+
+// Tag Endpoints
+
+// GetTags returns every tag in use with the number of stocks carrying it
+func (h *WatchlistHandler) GetTags(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	tags, err := db.GetTagCounts()
+	if err != nil {
+		respondError(c, "Failed to fetch tags", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tags":  tags,
+		"count": len(tags),
+	})
+}
+
+// AddStockTags adds tags to a stock, keeping the ones it has
+func (h *WatchlistHandler) AddStockTags(c *gin.Context) {
+	h.updateStockTags(c, false)
+}
+
+// SetStockTags replaces the tags of a stock
+func (h *WatchlistHandler) SetStockTags(c *gin.Context) {
+	h.updateStockTags(c, true)
+}
+
+// updateStockTags adds or replaces the tags of a stock and responds with its tags
+func (h *WatchlistHandler) updateStockTags(c *gin.Context, replace bool) {
+	db := withRequestContext(c, h.db)
+
+	id, ok := h.stockIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req stockTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, "Invalid request body", err)
+		return
+	}
+	tags, err := models.NormalizeTags(req.Tags)
+	if err != nil {
+		respondInvalid(c, "Invalid tags", err)
+		return
+	}
+
+	if replace {
+		err = db.SetStockTags(id, tags)
+	} else {
+		err = db.AddStockTags(id, tags)
+	}
+	if err != nil {
+		respondError(c, "Failed to update stock tags", err)
+		return
+	}
+
+	h.respondStockTags(c, id)
+}
+
+// RemoveStockTag removes a tag from a stock
+func (h *WatchlistHandler) RemoveStockTag(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	id, ok := h.stockIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := db.RemoveStockTag(id, strings.ToLower(c.Param("tag"))); err != nil {
+		respondError(c, "Failed to remove stock tag", err)
+		return
+	}
+
+	h.respondStockTags(c, id)
+}
+
+// stockIDParam reads the :id stock parameter, responding with an error if it isn't a watchlist stock
+func (h *WatchlistHandler) stockIDParam(c *gin.Context) (int, bool) {
+	db := withRequestContext(c, h.db)
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, "Invalid stock ID", nil)
+		return 0, false
+	}
+
+	if _, err := db.GetStockSymbol(id); err != nil {
+		if err == sql.ErrNoRows {
+			respondNotFound(c, "Stock not found", nil)
+		} else {
+			respondError(c, "Failed to fetch stock", err)
+		}
+		return 0, false
+	}
+
+	return id, true
+}
+
+// respondStockTags responds with the current tags of a stock
+func (h *WatchlistHandler) respondStockTags(c *gin.Context, id int) {
+	db := withRequestContext(c, h.db)
+
+	tags, err := db.GetStockTags(id)
+	if err != nil {
+		respondError(c, "Failed to fetch stock tags", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"stock_id": id,
+		"tags":     tags,
+	})
+}
+
+// filterStocksByTag keeps the stocks carrying a tag
+func filterStocksByTag(stocks []models.Stock, tag string) []models.Stock {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	filtered := make([]models.Stock, 0, len(stocks))
+	for _, stock := range stocks {
+		if slices.Contains(stock.Tags, tag) {
+			filtered = append(filtered, stock)
+		}
+	}
+	return filtered
+}
+
+// Journal Endpoints
+
+// GetJournalEntries returns journal entries filtered by symbol, setup_id, entry_type, tag, q and an entry date range
+func (h *WatchlistHandler) GetJournalEntries(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	filter := &models.JournalFilter{
+		Symbol:    c.Query("symbol"),
+		EntryType: strings.ToLower(c.Query("entry_type")),
+		Tag:       c.Query("tag"),
+		Query:     c.Query("q"),
+		Limit:     models.DefaultPageLimit,
+	}
+
+	var err error
+	if value := c.Query("setup_id"); value != "" {
+		if filter.SetupID, err = strconv.ParseInt(value, 10, 64); err != nil {
+			err = fmt.Errorf("invalid setup_id: %s", value)
+		}
+	}
+	if value := c.Query("limit"); value != "" && err == nil {
+		if filter.Limit, err = strconv.Atoi(value); err != nil || filter.Limit < 1 {
+			err = fmt.Errorf("invalid limit: %s", value)
+		}
+		filter.Limit = min(filter.Limit, models.MaxPageLimit)
+	}
+	if value := c.Query("from"); value != "" && err == nil {
+		filter.From, err = parseJournalDate(value)
+	}
+	if value := c.Query("to"); value != "" && err == nil {
+		filter.To, err = parseJournalDate(value)
+		if err == nil && len(value) == len("2006-01-02") {
+			// A bare date includes the whole day
+			filter.To = filter.To.Add(24*time.Hour - time.Nanosecond)
+		}
+	}
+	if err != nil {
+		respondInvalid(c, "Invalid filter", err)
+		return
+	}
+
+	entries, err := db.GetJournalEntries(filter)
+	if err != nil {
+		respondError(c, "Failed to fetch journal entries", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"count":   len(entries),
+	})
+}
+
+// GetJournalEntry returns a journal entry
+func (h *WatchlistHandler) GetJournalEntry(c *gin.Context) {
+	entry, ok := h.journalEntryParam(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// CreateJournalEntry adds a journal entry for a stock or setup
+func (h *WatchlistHandler) CreateJournalEntry(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	entry := &models.JournalEntry{}
+	if !h.bindJournalEntry(c, entry) {
+		return
+	}
+
+	if err := db.CreateJournalEntry(entry); err != nil {
+		respondError(c, "Failed to create journal entry", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// UpdateJournalEntry replaces the content of a journal entry
+func (h *WatchlistHandler) UpdateJournalEntry(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	entry, ok := h.journalEntryParam(c)
+	if !ok {
+		return
+	}
+	if !h.bindJournalEntry(c, entry) {
+		return
+	}
+
+	if err := db.UpdateJournalEntry(entry); err != nil {
+		respondError(c, "Failed to update journal entry", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// DeleteJournalEntry deletes a journal entry
+func (h *WatchlistHandler) DeleteJournalEntry(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	entry, ok := h.journalEntryParam(c)
+	if !ok {
+		return
+	}
+
+	if err := db.DeleteJournalEntry(entry.ID); err != nil {
+		respondError(c, "Failed to delete journal entry", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Journal entry deleted successfully",
+	})
+}
+
+// Search returns the stocks whose symbol, name, notes or tags and the journal entries whose
+// title or body contain q
+func (h *WatchlistHandler) Search(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		respondInvalid(c, "Search query q is required", nil)
+		return
+	}
+
+	stocks, err := db.SearchStocks(q)
+	if err != nil {
+		respondError(c, "Failed to search stocks", err)
+		return
+	}
+
+	entries, err := db.GetJournalEntries(&models.JournalFilter{Query: q, Limit: models.DefaultPageLimit})
+	if err != nil {
+		respondError(c, "Failed to search journal entries", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.WatchlistSearchResult{
+		Query:   q,
+		Stocks:  stocks,
+		Journal: entries,
+	})
+}
+
+// journalEntryParam loads the :id journal entry, responding with an error if it doesn't exist
+func (h *WatchlistHandler) journalEntryParam(c *gin.Context) (*models.JournalEntry, bool) {
+	db := withRequestContext(c, h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondInvalid(c, "Invalid journal entry ID", nil)
+		return nil, false
+	}
+
+	entry, err := db.GetJournalEntry(id)
+	if err != nil {
+		respondError(c, "Failed to fetch journal entry", err)
+		return nil, false
+	}
+	if entry == nil {
+		respondNotFound(c, "Journal entry not found", nil)
+		return nil, false
+	}
+
+	return entry, true
+}
+
+// bindJournalEntry applies the request body to entry and validates it, responding with an error if it is invalid
+func (h *WatchlistHandler) bindJournalEntry(c *gin.Context, entry *models.JournalEntry) bool {
+	db := withRequestContext(c, h.db)
+
+	var req journalEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, "Invalid request body", err)
+		return false
+	}
+
+	entry.Symbol = req.Symbol
+	entry.SetupID = req.SetupID
+	entry.EntryType = req.EntryType
+	entry.Title = req.Title
+	entry.Body = req.Body
+	entry.Tags = req.Tags
+	if req.EntryDate != "" {
+		date, err := parseJournalDate(req.EntryDate)
+		if err != nil {
+			respondInvalid(c, "Invalid entry_date", err)
+			return false
+		}
+		entry.EntryDate = date
+	}
+
+	// Entries about a setup belong to the setup's symbol
+	if entry.SetupID != nil {
+		setup, err := db.GetTradingSetupByID(*entry.SetupID)
+		if err != nil {
+			respondError(c, "Failed to fetch setup", err)
+			return false
+		}
+		if setup == nil {
+			respondInvalid(c, fmt.Sprintf("Setup with ID %d does not exist", *entry.SetupID), nil)
+			return false
+		}
+		if entry.Symbol == "" {
+			entry.Symbol = setup.Symbol
+		} else if !strings.EqualFold(entry.Symbol, setup.Symbol) {
+			respondInvalid(c, fmt.Sprintf("Setup %d is for %s, not %s", setup.ID, setup.Symbol, strings.ToUpper(entry.Symbol)), nil)
+			return false
+		}
+	}
+
+	if err := entry.Validate(); err != nil {
+		respondInvalid(c, "Invalid journal entry", err)
+		return false
+	}
+
+	return true
+}
+
+// parseJournalDate parses an RFC3339 timestamp or a YYYY-MM-DD date
+func parseJournalDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected RFC3339 or YYYY-MM-DD", value)
+	}
+	return t, nil
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"market-watch-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetReference returns the stored sector, market cap, float and average volume of a symbol
+func (h *WatchlistHandler) GetReference(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	symbol := strings.ToUpper(c.Param("symbol"))
+
+	ref, err := db.GetSymbolReference(symbol)
+	if err != nil {
+		respondError(c, "Failed to fetch reference data", err)
+		return
+	}
+	if ref == nil {
+		respondNotFound(c, fmt.Sprintf("No reference data for %s", symbol), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, ref)
+}
+
+// RefreshReference fetches a symbol's reference data from Polygon now
+func (h *WatchlistHandler) RefreshReference(c *gin.Context) {
+	if !h.reference.IsEnabled() {
+		respondUnavailable(c, "Reference data is disabled (set reference_data.enabled)", nil)
+		return
+	}
+
+	ref, err := h.reference.RefreshSymbol(c.Param("symbol"))
+	if err != nil {
+		respondUpstreamError(c, "Failed to fetch reference data", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ref)
+}
+
+// attachReferences sets the stored reference data on each stock
+func (h *WatchlistHandler) attachReferences(stocks []models.Stock) error {
+	refs, err := h.db.GetSymbolReferences()
+	if err != nil {
+		return err
+	}
+	for i := range stocks {
+		stocks[i].Reference = refs[stocks[i].Symbol]
+	}
+	return nil
+}
+
+// parseStockReferenceFilter reads the reference data filter from the query string
+func parseStockReferenceFilter(c *gin.Context) (*models.StockReferenceFilter, error) {
+	filter := &models.StockReferenceFilter{
+		Sector:   c.Query("sector"),
+		Industry: c.Query("industry"),
+	}
+
+	var err error
+	parseFloat := func(name string, dst *float64) {
+		if value := c.Query(name); value != "" && err == nil {
+			if *dst, err = strconv.ParseFloat(value, 64); err != nil {
+				err = fmt.Errorf("invalid %s: %s", name, value)
+			}
+		}
+	}
+	parseInt := func(name string, dst *int64) {
+		if value := c.Query(name); value != "" && err == nil {
+			if *dst, err = strconv.ParseInt(value, 10, 64); err != nil {
+				err = fmt.Errorf("invalid %s: %s", name, value)
+			}
+		}
+	}
+	parseFloat("min_market_cap", &filter.MinMarketCap)
+	parseFloat("max_market_cap", &filter.MaxMarketCap)
+	parseInt("min_avg_volume", &filter.MinAvgVolume)
+	parseInt("max_float", &filter.MaxFloat)
+
+	return filter, err
+}
@@ -14,13 +14,10 @@ func WatchlistRefreshHandler(db *database.Database, stockService *services.Stock
 	return func(c *gin.Context) {
 		log.Printf("[WATCHLIST] Triggering stock refresh...")
 		
-		err := stockService.RefreshAllStocks()
+		err := stockService.RefreshAllStocks(c.Request.Context())
 		if err != nil {
 			log.Printf("[WATCHLIST] Stock refresh failed: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Stock refresh failed",
-				"details": err.Error(),
-			})
+			respondError(c, "Stock refresh failed", err)
 			return
 		}
 
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportWatchlist adds the symbols of a CSV, TradingView or thinkorswim watchlist file, sent as the
+// request body or the multipart field "file", to the watchlist
+func (h *WatchlistHandler) ImportWatchlist(c *gin.Context) {
+	opts := &models.WatchlistImportOptions{
+		Format:     c.Query("format"),
+		Strategy:   c.Query("strategy"),
+		Duplicates: c.Query("duplicates"),
+	}
+
+	body := c.Request.Body
+	if file, err := c.FormFile("file"); err == nil {
+		opened, err := file.Open()
+		if err != nil {
+			respondInvalid(c, "Failed to read uploaded file", err)
+			return
+		}
+		defer opened.Close()
+		body = opened
+	}
+
+	result, err := h.transfer.Import(body, opts)
+	if err != nil {
+		respondInvalid(c, "Failed to import watchlist", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("Imported %d new and %d updated stocks", result.Added, result.Updated),
+		"result":  result,
+	})
+}
+
+// ExportWatchlist downloads the watchlist, or one strategy of it, as a CSV, TradingView or thinkorswim file
+func (h *WatchlistHandler) ExportWatchlist(c *gin.Context) {
+	format, err := services.ValidateWatchlistFormat(c.Query("format"))
+	if err != nil {
+		respondInvalid(c, "Invalid export format", err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if _, err := h.transfer.Export(&buf, format, c.Query("strategy")); err != nil {
+		respondError(c, "Failed to export watchlist", err)
+		return
+	}
+
+	filename, contentType := "watchlist.csv", "text/csv"
+	if format != models.WatchlistFormatCSV {
+		filename, contentType = "watchlist_"+format+".txt", "text/plain"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
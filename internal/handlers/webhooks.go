@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhooksHandler handles the webhook test and dead letter endpoints
+type WebhooksHandler struct {
+	db       *database.Database
+	webhooks *services.WebhookService
+}
+
+// NewWebhooksHandler creates a new webhooks handler
+func NewWebhooksHandler(db *database.Database, webhooks *services.WebhookService) *WebhooksHandler {
+	return &WebhooksHandler{
+		db:       db,
+		webhooks: webhooks,
+	}
+}
+
+// SendTestWebhook godoc
+// @Summary Send a test webhook
+// @Description Post a signed test event to every configured endpoint, whatever events it subscribes to, and report which received it. Test events are not retried or dead-lettered.
+// @Tags webhooks
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 503 {object} models.ErrorResponse
+// @Router /api/v1/webhooks/test [post]
+func (h *WebhooksHandler) SendTestWebhook(c *gin.Context) {
+	results, err := h.webhooks.SendTest()
+	if err != nil {
+		respondError(c, "Failed to send test webhook", err)
+		return
+	}
+
+	delivered := 0
+	for _, result := range results {
+		if result.Delivered {
+			delivered++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results":   results,
+		"delivered": delivered,
+		"count":     len(results),
+	})
+}
+
+// GetWebhookDeadLetters godoc
+// @Summary List undelivered webhook events
+// @Description Get the events that could not be delivered to an endpoint after every retry, newest first
+// @Tags webhooks
+// @Produce json
+// @Param limit query int false "Maximum number of dead letters (default 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/webhooks/dead-letters [get]
+func (h *WebhooksHandler) GetWebhookDeadLetters(c *gin.Context) {
+	db := withRequestContext(c, h.db)
+
+	limit := 100
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= 1000 {
+		limit = l
+	}
+
+	letters, err := db.GetWebhookDeadLetters(limit)
+	if err != nil {
+		respondError(c, "Failed to get webhook dead letters", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"dead_letters": letters,
+		"count":        len(letters),
+	})
+}
+
+// RetryWebhookDeadLetter godoc
+// @Summary Resend an undelivered webhook event
+// @Description Post a dead-lettered event once more to its endpoint with its original ID and a fresh signature. The dead letter is deleted when the endpoint accepts it.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Dead letter ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse
+// @Router /api/v1/webhooks/dead-letters/{id}/retry [post]
+func (h *WebhooksHandler) RetryWebhookDeadLetter(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		respondInvalid(c, "Invalid dead letter ID", nil)
+		return
+	}
+
+	if err := h.webhooks.Redeliver(id); err != nil {
+		respondUpstreamError(c, "Failed to redeliver webhook event", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook event delivered",
+		"id":      id,
+	})
+}
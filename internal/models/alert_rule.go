@@ -0,0 +1,320 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Alert rule condition fields
+const (
+	AlertFieldPrice                     = "price"
+	AlertFieldRSI14                     = "rsi_14"
+	AlertFieldRSI30                     = "rsi_30"
+	AlertFieldMACD                      = "macd"
+	AlertFieldMACDHistogram             = "macd_histogram"
+	AlertFieldVolumeRatio               = "volume_ratio"
+	AlertFieldRVOL                      = "rvol"
+	AlertFieldVWAP                      = "vwap"
+	AlertFieldATR14                     = "atr_14"
+	AlertFieldStochK                    = "stoch_k"
+	AlertFieldADX14                     = "adx_14"
+	AlertFieldSupportDistancePercent    = "support_distance_percent"
+	AlertFieldResistanceDistancePercent = "resistance_distance_percent"
+	AlertFieldVolumeZScore              = "volume_zscore"
+	AlertFieldRecentAnomalies           = "recent_anomalies"
+)
+
+// Alert rule logic operators
+const (
+	AlertLogicAnd = "AND"
+	AlertLogicOr  = "OR"
+)
+
+// AlertFields lists the fields an alert condition can reference
+var AlertFields = []string{
+	AlertFieldPrice,
+	AlertFieldRSI14,
+	AlertFieldRSI30,
+	AlertFieldMACD,
+	AlertFieldMACDHistogram,
+	AlertFieldVolumeRatio,
+	AlertFieldRVOL,
+	AlertFieldVWAP,
+	AlertFieldATR14,
+	AlertFieldStochK,
+	AlertFieldADX14,
+	AlertFieldSupportDistancePercent,
+	AlertFieldResistanceDistancePercent,
+	AlertFieldVolumeZScore,
+	AlertFieldRecentAnomalies,
+}
+
+// AlertRule represents a user-defined alert evaluated on each collection cycle
+type AlertRule struct {
+	ID              int64            `json:"id" db:"id"`
+	Name            string           `json:"name" db:"name"`
+	Symbol          string           `json:"symbol" db:"symbol"` // empty applies to all watched symbols
+	Conditions      []AlertCondition `json:"conditions" db:"conditions"`
+	Logic           string           `json:"logic" db:"logic"` // 'AND' or 'OR'
+	IsActive        bool             `json:"is_active" db:"is_active"`
+	NotifyEmail     bool             `json:"notify_email" db:"notify_email"`
+	CooldownMinutes int              `json:"cooldown_minutes" db:"cooldown_minutes"`
+	Then            []AlertStep      `json:"then,omitempty" db:"steps"` // legs matched in order after the conditions
+	LastTriggeredAt *time.Time       `json:"last_triggered_at,omitempty" db:"last_triggered_at"`
+	CreatedAt       time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// AlertCondition represents a single comparison, e.g. rsi_14 < 30 or price > vwap
+type AlertCondition struct {
+	Field      string  `json:"field"`
+	Operator   string  `json:"operator"` // '<', '<=', '>', '>=', '=='
+	Value      float64 `json:"value"`
+	ValueField string  `json:"value_field,omitempty"` // field compared against instead of value
+}
+
+// AlertStep is a leg of a sequenced rule, matched after the previous leg
+type AlertStep struct {
+	Conditions []AlertCondition `json:"conditions"`
+	Logic      string           `json:"logic"` // 'AND' or 'OR'
+	// WithinMinutes is how long after the previous leg last matched this leg may match, 0 for no limit
+	WithinMinutes int `json:"within_minutes"`
+}
+
+// AlertRuleState is the progress of a sequenced rule on one symbol, kept until the sequence fires or expires
+type AlertRuleState struct {
+	RuleID    int64              `json:"rule_id" db:"rule_id"`
+	Symbol    string             `json:"symbol" db:"symbol"`
+	Step      int                `json:"step" db:"step"`             // legs matched so far
+	StartedAt time.Time          `json:"started_at" db:"started_at"` // when the first leg matched
+	StepAt    time.Time          `json:"step_at" db:"step_at"`       // when the last matched leg last matched
+	ExpiresAt *time.Time         `json:"expires_at,omitempty"`       // when the next leg's window closes
+	Values    map[string]float64 `json:"values" db:"field_values"`   // condition values as each leg matched
+}
+
+// AlertTrigger represents a single firing of an alert rule
+type AlertTrigger struct {
+	ID          int64              `json:"id" db:"id"`
+	RuleID      int64              `json:"rule_id" db:"rule_id"`
+	RuleName    string             `json:"rule_name" db:"rule_name"`
+	Symbol      string             `json:"symbol" db:"symbol"`
+	Message     string             `json:"message" db:"message"`
+	Values      map[string]float64 `json:"values" db:"field_values"`
+	EmailSent   bool               `json:"email_sent" db:"email_sent"`
+	TriggeredAt time.Time          `json:"triggered_at" db:"triggered_at"`
+}
+
+// AlertTriggerFilter represents filter parameters for querying alert triggers
+type AlertTriggerFilter struct {
+	RuleID int64     `json:"rule_id"`
+	Symbol string    `json:"symbol"`
+	Since  time.Time `json:"since"`
+	Limit  int       `json:"limit"`
+}
+
+// Validate checks that a rule is well formed and normalizes its logic operator
+func (r *AlertRule) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("rule name is required")
+	}
+
+	if len(r.Conditions) == 0 {
+		return fmt.Errorf("at least one condition is required")
+	}
+
+	logic, err := normalizeLogic(r.Logic)
+	if err != nil {
+		return err
+	}
+	r.Logic = logic
+
+	if r.CooldownMinutes < 0 {
+		return fmt.Errorf("cooldown_minutes cannot be negative")
+	}
+
+	r.Symbol = strings.ToUpper(strings.TrimSpace(r.Symbol))
+
+	if err := validateConditions(r.Conditions, ""); err != nil {
+		return err
+	}
+
+	for i := range r.Then {
+		step := &r.Then[i]
+		if len(step.Conditions) == 0 {
+			return fmt.Errorf("then %d: at least one condition is required", i+1)
+		}
+		if step.Logic, err = normalizeLogic(step.Logic); err != nil {
+			return fmt.Errorf("then %d: %w", i+1, err)
+		}
+		if step.WithinMinutes < 0 {
+			return fmt.Errorf("then %d: within_minutes cannot be negative", i+1)
+		}
+		if err := validateConditions(step.Conditions, fmt.Sprintf("then %d ", i+1)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// normalizeLogic upper-cases a logic operator, defaulting to AND
+func normalizeLogic(logic string) (string, error) {
+	logic = strings.ToUpper(strings.TrimSpace(logic))
+	if logic == "" {
+		logic = AlertLogicAnd
+	}
+	if logic != AlertLogicAnd && logic != AlertLogicOr {
+		return "", fmt.Errorf("invalid logic %q: must be AND or OR", logic)
+	}
+	return logic, nil
+}
+
+// validateConditions checks each condition's fields and operator, prefixing errors to locate the leg
+func validateConditions(conditions []AlertCondition, prefix string) error {
+	for i, condition := range conditions {
+		if !isAlertField(condition.Field) {
+			return fmt.Errorf("%scondition %d: unknown field %q", prefix, i+1, condition.Field)
+		}
+		if condition.ValueField != "" && !isAlertField(condition.ValueField) {
+			return fmt.Errorf("%scondition %d: unknown value_field %q", prefix, i+1, condition.ValueField)
+		}
+		switch condition.Operator {
+		case "<", "<=", ">", ">=", "==":
+		default:
+			return fmt.Errorf("%scondition %d: invalid operator %q", prefix, i+1, condition.Operator)
+		}
+	}
+	return nil
+}
+
+// IsSequence reports whether the rule has legs to match in order
+func (r *AlertRule) IsSequence() bool {
+	return len(r.Then) > 0
+}
+
+// Legs returns the rule's conditions as the first leg followed by its sequenced legs
+func (r *AlertRule) Legs() []AlertStep {
+	legs := make([]AlertStep, 0, len(r.Then)+1)
+	legs = append(legs, AlertStep{Conditions: r.Conditions, Logic: r.Logic})
+	return append(legs, r.Then...)
+}
+
+// Deadline returns when the window for a sequence's next leg closes, or nil when it has no limit
+func (r *AlertRule) Deadline(state *AlertRuleState) *time.Time {
+	legs := r.Legs()
+	if state.Step < 1 || state.Step >= len(legs) || legs[state.Step].WithinMinutes == 0 {
+		return nil
+	}
+	deadline := state.StepAt.Add(time.Duration(legs[state.Step].WithinMinutes) * time.Minute)
+	return &deadline
+}
+
+// IsCoolingDown returns true if the rule last fired on a symbol, at lastTriggered, within its cooldown window.
+// The cooldown applies per symbol, so a rule covering every watched symbol still fires on the others.
+func (r *AlertRule) IsCoolingDown(lastTriggered *time.Time, now time.Time) bool {
+	if lastTriggered == nil || r.CooldownMinutes == 0 {
+		return false
+	}
+	return now.Sub(*lastTriggered) < time.Duration(r.CooldownMinutes)*time.Minute
+}
+
+// Evaluate checks the rule's conditions, its first leg, against a set of field values.
+// Conditions on fields missing from values never match.
+func (r *AlertRule) Evaluate(values map[string]float64) bool {
+	return evaluateConditions(r.Conditions, r.Logic, values)
+}
+
+// Evaluate checks the leg's conditions against a set of field values
+func (s AlertStep) Evaluate(values map[string]float64) bool {
+	return evaluateConditions(s.Conditions, s.Logic, values)
+}
+
+// evaluateConditions combines the conditions' matches with the logic operator
+func evaluateConditions(conditions []AlertCondition, logic string, values map[string]float64) bool {
+	if len(conditions) == 0 {
+		return false
+	}
+
+	for _, condition := range conditions {
+		matched := condition.Matches(values)
+		if logic == AlertLogicOr && matched {
+			return true
+		}
+		if logic != AlertLogicOr && !matched {
+			return false
+		}
+	}
+
+	return logic != AlertLogicOr
+}
+
+// Matches checks a single condition against a set of field values
+func (c AlertCondition) Matches(values map[string]float64) bool {
+	value, ok := values[c.Field]
+	if !ok {
+		return false
+	}
+
+	target := c.Value
+	if c.ValueField != "" {
+		if target, ok = values[c.ValueField]; !ok {
+			return false
+		}
+	}
+
+	switch c.Operator {
+	case "<":
+		return value < target
+	case "<=":
+		return value <= target
+	case ">":
+		return value > target
+	case ">=":
+		return value >= target
+	case "==":
+		return value == target
+	default:
+		return false
+	}
+}
+
+// String returns the condition in human readable form, e.g. "rsi_14 < 30.00" or "price > vwap"
+func (c AlertCondition) String() string {
+	if c.ValueField != "" {
+		return fmt.Sprintf("%s %s %s", c.Field, c.Operator, c.ValueField)
+	}
+	return fmt.Sprintf("%s %s %.2f", c.Field, c.Operator, c.Value)
+}
+
+// Describe returns the rule's conditions joined by its logic operator, followed by each sequenced leg, e.g.
+// "rsi_14 < 30.00, then within 120m: price > vwap"
+func (r *AlertRule) Describe() string {
+	description := describeConditions(r.Conditions, r.Logic)
+	for _, step := range r.Then {
+		description += ", then "
+		if step.WithinMinutes > 0 {
+			description += fmt.Sprintf("within %dm: ", step.WithinMinutes)
+		}
+		description += describeConditions(step.Conditions, step.Logic)
+	}
+	return description
+}
+
+// describeConditions joins conditions by their logic operator
+func describeConditions(conditions []AlertCondition, logic string) string {
+	parts := make([]string, len(conditions))
+	for i, condition := range conditions {
+		parts[i] = condition.String()
+	}
+	return strings.Join(parts, " "+logic+" ")
+}
+
+func isAlertField(field string) bool {
+	for _, f := range AlertFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
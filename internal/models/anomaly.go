@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Anomaly types
+const (
+	AnomalyVolumeSpike = "volume_spike" // a bar's volume is far above the volume of the bars before it
+	AnomalyPriceGap    = "price_gap"    // a bar opened far from the previous bar's close
+	AnomalyHalt        = "halt"         // no bars for a stretch of the regular session, as in a trading halt
+)
+
+// AnomalyTypes lists the anomaly types
+var AnomalyTypes = []string{AnomalyVolumeSpike, AnomalyPriceGap, AnomalyHalt}
+
+// MarketAnomaly is a statistically unusual bar flagged by the anomaly detector
+type MarketAnomaly struct {
+	ID         int64     `json:"id" db:"id"`
+	Symbol     string    `json:"symbol" db:"symbol"`
+	Type       string    `json:"type" db:"type"`         // one of AnomalyTypes
+	Severity   string    `json:"severity" db:"severity"` // 'high' or 'medium'
+	BarTime    time.Time `json:"bar_time" db:"bar_time"` // the unusual bar, or the first bar after a halt
+	Value      float64   `json:"value" db:"value"`       // volume z-score, gap percent or minutes without bars
+	Threshold  float64   `json:"threshold" db:"threshold"`
+	Price      float64   `json:"price" db:"price"` // the bar's close
+	Message    string    `json:"message" db:"message"`
+	DetectedAt time.Time `json:"detected_at" db:"detected_at"`
+}
+
+// AnomalyFilter represents filter parameters for anomaly queries
+type AnomalyFilter struct {
+	Symbol string    `json:"symbol"`
+	Type   string    `json:"type"`
+	Since  time.Time `json:"since"` // bars at or after this time
+	Limit  int       `json:"limit"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AssetClass identifies the market a symbol trades in
+type AssetClass string
+
+// Asset classes, told apart by Polygon's ticker prefixes
+const (
+	AssetClassEquity AssetClass = "equity"
+	AssetClassCrypto AssetClass = "crypto" // X:BTCUSD
+	AssetClassForex  AssetClass = "forex"  // C:EURUSD
+)
+
+// Polygon ticker prefixes of non-equity asset classes
+const (
+	CryptoSymbolPrefix = "X:"
+	ForexSymbolPrefix  = "C:"
+)
+
+var (
+	equitySymbolPattern = regexp.MustCompile(`^[A-Z][A-Z0-9.-]{0,9}$`)
+	cryptoSymbolPattern = regexp.MustCompile(`^X:[A-Z0-9]{4,16}$`)
+	forexSymbolPattern  = regexp.MustCompile(`^C:[A-Z]{6}$`)
+)
+
+// AssetClassOf returns the asset class of a symbol from its ticker prefix
+func AssetClassOf(symbol string) AssetClass {
+	switch {
+	case strings.HasPrefix(symbol, CryptoSymbolPrefix):
+		return AssetClassCrypto
+	case strings.HasPrefix(symbol, ForexSymbolPrefix):
+		return AssetClassForex
+	default:
+		return AssetClassEquity
+	}
+}
+
+// IsEquity reports whether a symbol is a stock or ETF ticker
+func IsEquity(symbol string) bool {
+	return AssetClassOf(symbol) == AssetClassEquity
+}
+
+// ValidateSymbol upper-cases a symbol and checks it is a valid ticker for its asset class
+func ValidateSymbol(symbol string) (string, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+
+	switch AssetClassOf(symbol) {
+	case AssetClassCrypto:
+		if !cryptoSymbolPattern.MatchString(symbol) {
+			return "", fmt.Errorf("crypto symbols look like X:BTCUSD")
+		}
+	case AssetClassForex:
+		if !forexSymbolPattern.MatchString(symbol) {
+			return "", fmt.Errorf("forex symbols look like C:EURUSD")
+		}
+	default:
+		if !equitySymbolPattern.MatchString(symbol) {
+			return "", fmt.Errorf("symbol must be 1 to 10 letters, digits, dots or dashes, starting with a letter")
+		}
+	}
+
+	return symbol, nil
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Audit log limits
+const (
+	MaxAuditPayloadLength = 2000 // characters of the request body kept in an entry
+	AuditRedacted         = "[redacted]"
+)
+
+// AuditEntry records one mutating API request and its outcome
+type AuditEntry struct {
+	ID         int64     `json:"id" db:"id"`
+	Timestamp  time.Time `json:"timestamp" db:"timestamp"`
+	Actor      string    `json:"actor" db:"actor"` // the X-Actor header, or the client IP without one
+	Method     string    `json:"method" db:"method"`
+	Path       string    `json:"path" db:"path"`
+	Route      string    `json:"route" db:"route"` // the matched route pattern, e.g. /api/v1/setups/:id/status
+	Query      string    `json:"query,omitempty" db:"query"`
+	Payload    string    `json:"payload,omitempty" db:"payload"` // the request body with secrets redacted, truncated
+	StatusCode int       `json:"status_code" db:"status_code"`
+	Success    bool      `json:"success" db:"success"`
+	Error      string    `json:"error,omitempty" db:"error"`
+	DurationMS int64     `json:"duration_ms" db:"duration_ms"`
+}
+
+// AuditFilter represents filter parameters for audit log queries
+type AuditFilter struct {
+	Actor   string    `json:"actor"`
+	Method  string    `json:"method"`
+	Path    string    `json:"path"` // path prefix
+	Success *bool     `json:"success"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Limit   int       `json:"limit"`
+	Offset  int       `json:"offset"`
+}
+
+// AuditLogResponse is a page of audit log entries, newest first
+type AuditLogResponse struct {
+	Entries    []*AuditEntry `json:"entries"`
+	Pagination *Pagination   `json:"pagination"`
+}
@@ -0,0 +1,84 @@
+package models
+
+import "time"
+
+// Broker order statuses
+const (
+	BrokerOrderSubmitted = "submitted" // accepted by the broker, waiting for the entry to fill
+	BrokerOrderFilled    = "filled"    // entry filled and a position opened; the stop and target legs are working
+	BrokerOrderClosed    = "closed"    // the stop or target leg filled and the position was closed
+	BrokerOrderCanceled  = "canceled"  // canceled, expired or rejected before the entry filled
+	BrokerOrderFailed    = "failed"    // the broker refused the order
+)
+
+// BrokerOrder is a bracket order placed with the broker for a triggered setup
+type BrokerOrder struct {
+	ID            int64      `json:"id" db:"id"`
+	SetupID       int64      `json:"setup_id" db:"setup_id"`
+	StrategyID    int        `json:"strategy_id" db:"strategy_id"`           // strategy whose auto trading placed the order
+	PositionID    *int64     `json:"position_id,omitempty" db:"position_id"` // portfolio position opened when the entry filled
+	Broker        string     `json:"broker" db:"broker"`
+	BrokerOrderID string     `json:"broker_order_id" db:"broker_order_id"`
+	ClientOrderID string     `json:"client_order_id" db:"client_order_id"`
+	Symbol        string     `json:"symbol" db:"symbol"`
+	Side          string     `json:"side" db:"side"` // 'long' or 'short'
+	Quantity      float64    `json:"quantity" db:"quantity"`
+	EntryPrice    float64    `json:"entry_price" db:"entry_price"` // limit price of the entry
+	StopLoss      float64    `json:"stop_loss" db:"stop_loss"`
+	TargetPrice   float64    `json:"target_price" db:"target_price"`
+	Status        string     `json:"status" db:"status"`
+	FilledPrice   float64    `json:"filled_price,omitempty" db:"filled_price"`
+	FilledAt      *time.Time `json:"filled_at,omitempty" db:"filled_at"`
+	ExitPrice     float64    `json:"exit_price,omitempty" db:"exit_price"`
+	ExitAt        *time.Time `json:"exit_at,omitempty" db:"exit_at"`
+	Error         string     `json:"error,omitempty" db:"error"` // why the order failed or was canceled
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IsOpen reports whether the order still has working legs at the broker
+func (o *BrokerOrder) IsOpen() bool {
+	return o.Status == BrokerOrderSubmitted || o.Status == BrokerOrderFilled
+}
+
+// BrokerOrderFilter represents filter parameters for broker order queries
+type BrokerOrderFilter struct {
+	Symbol string `json:"symbol"`
+	Status string `json:"status"`
+	Open   bool   `json:"open"` // only submitted and filled orders
+	Limit  int    `json:"limit"`
+}
+
+// BracketOrderRequest is an entry with an attached stop loss and take profit, sent to the broker
+type BracketOrderRequest struct {
+	ClientOrderID string  `json:"client_order_id"` // idempotency key, so a retried request can't place a second order
+	Symbol        string  `json:"symbol"`
+	Side          string  `json:"side"` // 'long' buys the entry, 'short' sells it
+	Quantity      float64 `json:"quantity"`
+	EntryPrice    float64 `json:"entry_price"`
+	StopLoss      float64 `json:"stop_loss"`
+	TargetPrice   float64 `json:"target_price"`
+	TimeInForce   string  `json:"time_in_force"` // 'day' or 'gtc'
+}
+
+// BrokerOrderState is a bracket order's state as reported by the broker
+type BrokerOrderState struct {
+	BrokerOrderID string     `json:"broker_order_id"`
+	Status        string     `json:"status"` // one of the broker order statuses
+	FilledPrice   float64    `json:"filled_price,omitempty"`
+	FilledAt      *time.Time `json:"filled_at,omitempty"`
+	ExitPrice     float64    `json:"exit_price,omitempty"`
+	ExitAt        *time.Time `json:"exit_at,omitempty"`
+	Reason        string     `json:"reason,omitempty"` // the broker's status, e.g. 'rejected' or 'expired'
+}
+
+// BrokerStatus describes the broker connection and the strategies that trade automatically
+type BrokerStatus struct {
+	Enabled             bool       `json:"enabled"`
+	Broker              string     `json:"broker,omitempty"`
+	Live                bool       `json:"live"` // orders go to the live account rather than the paper account
+	AutoTradeStrategies []int      `json:"auto_trade_strategies"`
+	OpenOrders          int        `json:"open_orders"`
+	LastSync            *time.Time `json:"last_sync,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+}
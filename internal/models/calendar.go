@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Calendar event types
+const (
+	CalendarEventEarnings = "earnings"
+	CalendarEventEconomic = "economic"
+)
+
+// Calendar event sources
+const (
+	CalendarSourcePolygon = "polygon"
+	CalendarSourceManual  = "manual"
+)
+
+// CalendarEvent represents a scheduled earnings report or economic release
+type CalendarEvent struct {
+	ID         int64     `json:"id" db:"id"`
+	Symbol     string    `json:"symbol" db:"symbol"` // empty for market-wide events such as FOMC or CPI
+	EventType  string    `json:"event_type" db:"event_type"`
+	Title      string    `json:"title" db:"title"`
+	EventDate  time.Time `json:"event_date" db:"event_date"`
+	EventTime  string    `json:"event_time" db:"event_time"` // 'bmo' (before market open), 'amc' (after market close) or a clock time
+	Importance int       `json:"importance" db:"importance"` // 0-5, higher is more market moving
+	Source     string    `json:"source" db:"source"`
+	Details    string    `json:"details" db:"details"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CalendarEventFilter represents filter parameters for calendar queries
+type CalendarEventFilter struct {
+	Symbol        string    `json:"symbol"`
+	EventType     string    `json:"event_type"`
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+	IncludeMarket bool      `json:"include_market"` // include market-wide events when filtering by symbol
+	Limit         int       `json:"limit"`
+}
+
+// IsMarketWide returns true for events that affect every symbol
+func (e *CalendarEvent) IsMarketWide() bool {
+	return e.Symbol == ""
+}
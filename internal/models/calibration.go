@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// ScoringWeightVersion is a set of checklist item weights calibrated from the outcomes of resolved setups. While
+// a version is active, a setup's quality score is the sum of the weights of its completed checklist items.
+type ScoringWeightVersion struct {
+	ID          int64                  `json:"id" db:"id"`
+	Samples     int                    `json:"samples" db:"samples"` // resolved setups the weights were learned from
+	Wins        int                    `json:"wins" db:"wins"`       // setups that completed at their target
+	Losses      int                    `json:"losses" db:"losses"`   // setups that stopped out
+	BaseWinRate float64                `json:"base_win_rate" db:"base_win_rate"`
+	Items       []*ChecklistItemWeight `json:"items" db:"items"`
+	IsActive    bool                   `json:"is_active" db:"is_active"`
+	CreatedAt   time.Time              `json:"created_at" db:"created_at"`
+	ActivatedAt *time.Time             `json:"activated_at,omitempty" db:"activated_at"`
+}
+
+// ChecklistItemWeight is a checklist item's calibrated weight with the outcomes it was learned from
+type ChecklistItemWeight struct {
+	Key              string  `json:"key"` // the checklist item's JSON key, e.g. 'volume_spike'
+	Completed        int     `json:"completed"`
+	CompletedWins    int     `json:"completed_wins"`
+	Missed           int     `json:"missed"`
+	MissedWins       int     `json:"missed_wins"`
+	CompletedWinRate float64 `json:"completed_win_rate"` // posterior win rate of setups completing the item
+	MissedWinRate    float64 `json:"missed_win_rate"`    // posterior win rate of setups missing it
+	Lift             float64 `json:"lift"`               // completed over missed win rate; above 1 the item predicts wins
+	Points           float64 `json:"points"`             // weight out of 100 across all items
+}
+
+// Points returns the calibrated weight of a checklist item, or 0 for an unknown key
+func (v *ScoringWeightVersion) Points(key string) float64 {
+	for _, item := range v.Items {
+		if item.Key == key {
+			return item.Points
+		}
+	}
+	return 0
+}
+
+// CalibrationStatus describes the scorer in use and the stored weight versions
+type CalibrationStatus struct {
+	Enabled        bool                    `json:"enabled"` // weights are recomputed in the background
+	Scorer         string                  `json:"scorer"`  // 'default' or 'calibrated'
+	ActiveVersion  *ScoringWeightVersion   `json:"active_version,omitempty"`
+	Versions       []*ScoringWeightVersion `json:"versions"`
+	MinSamples     int                     `json:"min_samples"`
+	ResolvedSetups int                     `json:"resolved_setups"`
+}
+
+// Setup scorers
+const (
+	ScorerDefault    = "default"
+	ScorerCalibrated = "calibrated"
+)
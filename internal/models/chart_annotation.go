@@ -0,0 +1,94 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Chart annotation types
+const (
+	ChartAnnotationTrendLine = "trend_line" // line between two chart points
+	ChartAnnotationLevel     = "level"      // horizontal line at a price
+	ChartAnnotationNote      = "note"       // text pinned to a chart point
+)
+
+// Chart annotation and layout limits
+const (
+	MaxChartAnnotationText = 2000
+	MaxChartLayoutSize     = 64 * 1024
+)
+
+// DefaultChartUser owns the drawings saved without naming a user
+const DefaultChartUser = "default"
+
+// ChartAnnotationTypes lists the accepted chart annotation types
+var ChartAnnotationTypes = []string{ChartAnnotationTrendLine, ChartAnnotationLevel, ChartAnnotationNote}
+
+// ChartPoint is a point on a chart, a bar time and a price
+type ChartPoint struct {
+	Time  time.Time `json:"time"`
+	Price float64   `json:"price"`
+}
+
+// ChartAnnotation is something a user drew on a symbol's chart
+type ChartAnnotation struct {
+	ID        int64        `json:"id" db:"id"`
+	User      string       `json:"user" db:"user_name"`
+	Symbol    string       `json:"symbol" db:"symbol"`
+	Type      string       `json:"type" db:"annotation_type"`          // 'trend_line', 'level' or 'note'
+	Timeframe string       `json:"timeframe,omitempty" db:"timeframe"` // chart timeframe it was drawn on, empty for all
+	Points    []ChartPoint `json:"points" db:"points"`                 // two for a trend line, one for a note
+	Price     float64      `json:"price,omitempty" db:"price"`         // price of a level
+	Text      string       `json:"text,omitempty" db:"text"`
+	Color     string       `json:"color,omitempty" db:"color"`
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// Validate checks that an annotation has what its type needs to be drawn
+func (a *ChartAnnotation) Validate() error {
+	if !slices.Contains(ChartAnnotationTypes, a.Type) {
+		return fmt.Errorf("invalid annotation type %q: must be one of %s", a.Type, strings.Join(ChartAnnotationTypes, ", "))
+	}
+	if a.Timeframe != "" {
+		if _, err := ParseTimeframe(a.Timeframe); err != nil {
+			return err
+		}
+	}
+	if len(a.Text) > MaxChartAnnotationText {
+		return fmt.Errorf("text must be at most %d characters", MaxChartAnnotationText)
+	}
+	for _, point := range a.Points {
+		if point.Time.IsZero() || point.Price <= 0 {
+			return fmt.Errorf("points need a time and a positive price")
+		}
+	}
+
+	switch a.Type {
+	case ChartAnnotationTrendLine:
+		if len(a.Points) != 2 {
+			return fmt.Errorf("a trend line needs 2 points, got %d", len(a.Points))
+		}
+	case ChartAnnotationLevel:
+		if a.Price <= 0 {
+			return fmt.Errorf("a level needs a positive price")
+		}
+	case ChartAnnotationNote:
+		if strings.TrimSpace(a.Text) == "" || len(a.Points) != 1 {
+			return fmt.Errorf("a note needs text and 1 point")
+		}
+	}
+	return nil
+}
+
+// ChartLayout is a user's saved chart preferences for a symbol, such as its timeframe, indicators and zoom, kept
+// as the JSON the dashboard sent
+type ChartLayout struct {
+	User      string          `json:"user" db:"user_name"`
+	Symbol    string          `json:"symbol" db:"symbol"`
+	Layout    json.RawMessage `json:"layout" db:"layout" swaggertype:"object"`
+	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"`
+}
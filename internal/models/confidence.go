@@ -0,0 +1,115 @@
+package models
+
+import (
+	"fmt"
+	"math"
+)
+
+// Confidence factors a setup's confidence is derived from
+const (
+	ConfidenceFactorQuality    = "quality"             // the setup's quality score
+	ConfidenceFactorIndicators = "indicator_agreement" // trend and momentum indicators agreeing with the direction
+	ConfidenceFactorVolume     = "volume_confirmation" // volume checklist items and a volume confirmed key level
+	ConfidenceFactorLevel      = "sr_strength"         // strength of the S/R zone or level the setup trades
+)
+
+// ConfidenceModel weighs the factors of a setup's confidence score and maps the score to a confidence level.
+// The weights of factors a setup lacks, such as S/R strength for pattern setups, are spread over the others.
+type ConfidenceModel struct {
+	QualityWeight   float64 `json:"quality_weight" yaml:"quality_weight"`
+	IndicatorWeight float64 `json:"indicator_weight" yaml:"indicator_weight"`
+	VolumeWeight    float64 `json:"volume_weight" yaml:"volume_weight"`
+	LevelWeight     float64 `json:"level_weight" yaml:"level_weight"`
+	HighThreshold   float64 `json:"high_threshold" yaml:"high_threshold"`     // Confidence score from which a setup is high confidence
+	MediumThreshold float64 `json:"medium_threshold" yaml:"medium_threshold"` // Confidence score from which a setup is medium confidence
+}
+
+// DefaultConfidenceModel returns the default confidence weights and thresholds
+func DefaultConfidenceModel() ConfidenceModel {
+	return ConfidenceModel{
+		QualityWeight:   55.0,
+		IndicatorWeight: 15.0,
+		VolumeWeight:    15.0,
+		LevelWeight:     15.0,
+		HighThreshold:   80.0,
+		MediumThreshold: 60.0,
+	}
+}
+
+// Validate checks that the weights add up to 100 and the thresholds are ordered
+func (m *ConfidenceModel) Validate() error {
+	weights := []float64{m.QualityWeight, m.IndicatorWeight, m.VolumeWeight, m.LevelWeight}
+	total := 0.0
+	for _, weight := range weights {
+		if weight < 0 {
+			return fmt.Errorf("confidence weights must not be negative")
+		}
+		total += weight
+	}
+	if math.Abs(total-100) > 0.01 {
+		return fmt.Errorf("confidence quality, indicator, volume and level weights must add up to 100, got %g", total)
+	}
+	if m.MediumThreshold < 0 || m.MediumThreshold > m.HighThreshold || m.HighThreshold > 100 {
+		return fmt.Errorf("confidence thresholds must satisfy 0 <= medium <= high <= 100")
+	}
+	return nil
+}
+
+// weight returns the configured weight of a factor
+func (m *ConfidenceModel) weight(name string) float64 {
+	switch name {
+	case ConfidenceFactorQuality:
+		return m.QualityWeight
+	case ConfidenceFactorIndicators:
+		return m.IndicatorWeight
+	case ConfidenceFactorVolume:
+		return m.VolumeWeight
+	case ConfidenceFactorLevel:
+		return m.LevelWeight
+	}
+	return 0
+}
+
+// Level maps a confidence score to 'high', 'medium' or 'low'
+func (m *ConfidenceModel) Level(score float64) string {
+	switch {
+	case score >= m.HighThreshold:
+		return "high"
+	case score >= m.MediumThreshold:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// Evaluate weighs the factors into a 0-100 confidence score, filling in each factor's effective weight and
+// contribution, and returns the score and its level
+func (m *ConfidenceModel) Evaluate(factors []*ConfidenceFactor) (float64, string) {
+	total := 0.0
+	for _, factor := range factors {
+		total += m.weight(factor.Name)
+	}
+
+	score := 0.0
+	for _, factor := range factors {
+		factor.Weight, factor.Contribution = 0, 0
+		if total > 0 {
+			factor.Weight = math.Round(m.weight(factor.Name)/total*10000) / 100
+			factor.Contribution = math.Round(factor.Score*m.weight(factor.Name)/total*100) / 100
+		}
+		score += factor.Contribution
+	}
+
+	score = math.Round(math.Min(100, score)*100) / 100
+	return score, m.Level(score)
+}
+
+// ConfidenceFactor is one factor's part of a setup's confidence: its 0-100 score, its share of the weights of
+// the factors the setup has, in percent, and the points it contributed
+type ConfidenceFactor struct {
+	Name         string  `json:"name"`
+	Score        float64 `json:"score"`
+	Weight       float64 `json:"weight"`
+	Contribution float64 `json:"contribution"`
+	Detail       string  `json:"detail,omitempty"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// ConfigReloadResult reports what a config file reload changed
+type ConfigReloadResult struct {
+	Path            string    `json:"path"`
+	ReloadedAt      time.Time `json:"reloaded_at"`
+	Applied         []string  `json:"applied"`          // settings now in effect, e.g. collection.interval
+	RestartRequired []string  `json:"restart_required"` // changed sections that only take effect after a restart
+}
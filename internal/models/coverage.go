@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Coverage scan limits
+const (
+	CoverageIntervalMinutes = 5 // bars are collected at 5-minute resolution; a covered interval holds at least one bar
+	DefaultCoverageDays     = 5
+	MaxCoverageDays         = 60
+)
+
+// DataGap is a run of regular session intervals without a stored bar
+type DataGap struct {
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"` // end of the last missing interval
+	Minutes int       `json:"minutes"`
+}
+
+// SymbolCoverage reports how much of each trading day's regular session a symbol's stored bars cover
+type SymbolCoverage struct {
+	Symbol            string    `json:"symbol"`
+	From              time.Time `json:"from"`
+	To                time.Time `json:"to"`
+	TradingDays       int       `json:"trading_days"`
+	ExpectedIntervals int       `json:"expected_intervals"`
+	CoveredIntervals  int       `json:"covered_intervals"`
+	CoveragePercent   float64   `json:"coverage_percent"`
+	MissingMinutes    int       `json:"missing_minutes"` // in the reported gaps
+	Gaps              []DataGap `json:"gaps"`
+}
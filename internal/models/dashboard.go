@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// SymbolMover is a watched symbol's move on its latest trading date
+type SymbolMover struct {
+	Symbol        string  `json:"symbol"`
+	Price         float64 `json:"price"`          // latest close
+	PreviousClose float64 `json:"previous_close"` // last close of the previous trading date, 0 when unknown
+	Change        float64 `json:"change"`
+	ChangePercent float64 `json:"change_percent"`
+	Volume        int64   `json:"volume"` // volume traded on the latest trading date
+}
+
+// CollectorHealth summarizes the data collector for the dashboard
+type CollectorHealth struct {
+	Healthy        bool      `json:"healthy"`
+	Error          string    `json:"error,omitempty"` // why the collector is unhealthy
+	IsRunning      bool      `json:"is_running"`
+	LastRun        time.Time `json:"last_run"`
+	NextRun        time.Time `json:"next_run"`
+	SuccessfulRuns int       `json:"successful_runs"`
+	FailedRuns     int       `json:"failed_runs"`
+	LastError      string    `json:"last_error,omitempty"`
+	LatestBar      time.Time `json:"latest_bar"` // newest stored bar of any watched symbol
+}
+
+// DashboardOverview is everything the dashboard's landing view shows, assembled in one request
+type DashboardOverview struct {
+	TradingDate     string           `json:"trading_date"` // US/Eastern date of the newest bar, YYYY-MM-DD
+	WatchedSymbols  int              `json:"watched_symbols"`
+	TopGainers      []*SymbolMover   `json:"top_gainers"`
+	TopLosers       []*SymbolMover   `json:"top_losers"`
+	VolumeLeaders   []*SymbolMover   `json:"volume_leaders"`
+	SetupsByStatus  map[string]int   `json:"setups_by_status"`  // active and triggered setups
+	PatternsByPhase map[string]int   `json:"patterns_by_phase"` // incomplete patterns of every family
+	RecentAlerts    []*Notification  `json:"recent_alerts"`
+	RecentAnomalies []*MarketAnomaly `json:"recent_anomalies"` // anomalies on bars of the last day
+	Collector       *CollectorHealth `json:"collector,omitempty"`
+	GeneratedAt     time.Time        `json:"generated_at"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// DataVersion identifies the state of a symbol's stored rows in one table. A new, backfilled or removed row
+// or a newest row updated in place changes it, so responses built from the rows can be revalidated against it.
+type DataVersion struct {
+	Latest  time.Time `json:"latest"`  // timestamp of the newest row
+	Content string    `json:"content"` // values of the newest row
+	Rows    int64     `json:"rows"`
+}
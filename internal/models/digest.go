@@ -0,0 +1,66 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Digest periods
+const (
+	DigestDaily  = "daily"  // since the previous trading day, sent on trading days
+	DigestWeekly = "weekly" // the last seven days
+)
+
+// DigestReport summarizes a period's setups, pattern activity, alerts and collection health
+type DigestReport struct {
+	Period          string            `json:"period"` // 'daily' or 'weekly'
+	From            time.Time         `json:"from"`
+	To              time.Time         `json:"to"`
+	MinQualityScore float64           `json:"min_quality_score"`
+	Setups          []*TradingSetup   `json:"setups"`   // new setups at or above the minimum quality score, best first
+	Patterns        []*DigestPattern  `json:"patterns"` // patterns detected or moved past formation, most recent first
+	Alerts          []*AlertTrigger   `json:"alerts"`   // alert rule triggers, newest first
+	Collection      *DigestCollection `json:"collection"`
+	GeneratedAt     time.Time         `json:"generated_at"`
+}
+
+// DigestPattern is a pattern that was detected or updated past its formation phase during the period
+type DigestPattern struct {
+	Family            string    `json:"family"` // 'head_shoulders', 'falling_wedge', 'triangle' or 'flag'
+	ID                int64     `json:"id"`
+	Symbol            string    `json:"symbol"`
+	PatternType       string    `json:"pattern_type"`
+	Phase             string    `json:"phase"`
+	New               bool      `json:"new"` // detected during the period
+	CompletionPercent float64   `json:"completion_percent"`
+	DetectedAt        time.Time `json:"detected_at"`
+	LastUpdated       time.Time `json:"last_updated"`
+}
+
+// DigestCollection reports data collection health for a digest
+type DigestCollection struct {
+	LastRun        time.Time `json:"last_run"`
+	SuccessfulRuns int       `json:"successful_runs"` // since the server started
+	FailedRuns     int       `json:"failed_runs"`
+	LastError      string    `json:"last_error,omitempty"`
+	WatchedSymbols int       `json:"watched_symbols"`
+	StaleSymbols   []string  `json:"stale_symbols"` // watched symbols without a bar during the period
+}
+
+// IsDigestPeriod reports whether a period is 'daily' or 'weekly'
+func IsDigestPeriod(period string) bool {
+	return period == DigestDaily || period == DigestWeekly
+}
+
+// ParseWeekday parses a full or three-letter English weekday name, case insensitive
+func ParseWeekday(s string) (time.Weekday, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if s == name || s == name[:3] {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid weekday %q", s)
+}
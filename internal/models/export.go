@@ -0,0 +1,95 @@
+package models
+
+import "time"
+
+// Export and import datasets
+const (
+	DatasetPrice    = "price"
+	DatasetVolume   = "volume"
+	DatasetLevels   = "levels"
+	DatasetSetups   = "setups"
+	DatasetPatterns = "patterns"
+)
+
+// Export and import file formats
+const (
+	FormatCSV     = "csv"
+	FormatParquet = "parquet"
+)
+
+// ExportDatasets lists the datasets that can be exported and imported
+var ExportDatasets = []string{DatasetPrice, DatasetVolume, DatasetLevels, DatasetSetups, DatasetPatterns}
+
+// ExportRequest selects the rows of a dataset to export
+type ExportRequest struct {
+	Dataset string    `json:"dataset"`
+	Format  string    `json:"format"`           // 'csv' (default) or 'parquet'
+	Symbol  string    `json:"symbol,omitempty"` // empty for all symbols
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+}
+
+// ImportResult summarizes an import
+type ImportResult struct {
+	Dataset  string `json:"dataset"`
+	Format   string `json:"format"`
+	Rows     int    `json:"rows"`
+	Imported int    `json:"imported"`
+}
+
+// PriceRecord is the exported form of a price bar
+type PriceRecord struct {
+	Symbol    string    `parquet:"symbol" csv:"symbol"`
+	Timestamp time.Time `parquet:"timestamp,timestamp" csv:"timestamp"`
+	Open      float64   `parquet:"open" csv:"open"`
+	High      float64   `parquet:"high" csv:"high"`
+	Low       float64   `parquet:"low" csv:"low"`
+	Close     float64   `parquet:"close" csv:"close"`
+	Volume    int64     `parquet:"volume" csv:"volume"`
+}
+
+// VolumeRecord is the exported form of a volume bar
+type VolumeRecord struct {
+	Symbol    string    `parquet:"symbol" csv:"symbol"`
+	Timestamp time.Time `parquet:"timestamp,timestamp" csv:"timestamp"`
+	Volume    int64     `parquet:"volume" csv:"volume"`
+}
+
+// LevelRecord is the exported form of a support/resistance level
+type LevelRecord struct {
+	Symbol           string    `parquet:"symbol" csv:"symbol"`
+	Level            float64   `parquet:"level" csv:"level"`
+	LevelType        string    `parquet:"level_type" csv:"level_type"`
+	Strength         float64   `parquet:"strength" csv:"strength"`
+	Touches          int64     `parquet:"touches" csv:"touches"`
+	FirstTouch       time.Time `parquet:"first_touch,timestamp" csv:"first_touch"`
+	LastTouch        time.Time `parquet:"last_touch,timestamp" csv:"last_touch"`
+	VolumeConfirmed  bool      `parquet:"volume_confirmed" csv:"volume_confirmed"`
+	AvgVolume        float64   `parquet:"avg_volume" csv:"avg_volume"`
+	MaxBouncePercent float64   `parquet:"max_bounce_percent" csv:"max_bounce_percent"`
+	AvgBouncePercent float64   `parquet:"avg_bounce_percent" csv:"avg_bounce_percent"`
+	TimeframeOrigin  string    `parquet:"timeframe_origin" csv:"timeframe_origin"`
+	IsActive         bool      `parquet:"is_active" csv:"is_active"`
+	LastValidated    time.Time `parquet:"last_validated,timestamp" csv:"last_validated"`
+}
+
+// SetupRecord is the exported form of a trading setup; Data holds the full setup and checklist as JSON
+type SetupRecord struct {
+	Symbol       string    `parquet:"symbol" csv:"symbol"`
+	SetupType    string    `parquet:"setup_type" csv:"setup_type"`
+	Direction    string    `parquet:"direction" csv:"direction"`
+	Status       string    `parquet:"status" csv:"status"`
+	QualityScore float64   `parquet:"quality_score" csv:"quality_score"`
+	DetectedAt   time.Time `parquet:"detected_at,timestamp" csv:"detected_at"`
+	ExpiresAt    time.Time `parquet:"expires_at,timestamp" csv:"expires_at"`
+	Data         string    `parquet:"data" csv:"data"`
+}
+
+// PatternRecord is the exported form of a chart pattern of any family; Data holds the full pattern as JSON
+type PatternRecord struct {
+	PatternFamily string    `parquet:"pattern_family" csv:"pattern_family"`
+	Symbol        string    `parquet:"symbol" csv:"symbol"`
+	DetectedAt    time.Time `parquet:"detected_at,timestamp" csv:"detected_at"`
+	LastUpdated   time.Time `parquet:"last_updated,timestamp" csv:"last_updated"`
+	Data          string    `parquet:"data" csv:"data"`
+}
@@ -4,14 +4,20 @@ import (
 	"time"
 )
 
-// FallingWedgePattern represents a falling wedge chart pattern
+// Wedge pattern types
+const (
+	PatternFallingWedge = "falling_wedge"
+	PatternRisingWedge  = "rising_wedge"
+)
+
+// FallingWedgePattern represents a falling (bullish) or rising (bearish) wedge chart pattern
 type FallingWedgePattern struct {
 	ID      int64  `json:"id" db:"id"`
 	SetupID int64  `json:"setup_id" db:"setup_id"`
 	Symbol  string `json:"symbol" db:"symbol"`
 
 	// Pattern identification
-	PatternType string `json:"pattern_type" db:"pattern_type"` // "falling_wedge"
+	PatternType string `json:"pattern_type" db:"pattern_type"` // "falling_wedge" or "rising_wedge"
 
 	// Trend line points
 	UpperTrendLine1 PatternPoint `json:"upper_trend_line_1" db:"upper_trend_line_1"`
@@ -22,7 +28,7 @@ type FallingWedgePattern struct {
 	// Pattern metrics
 	UpperSlope    float64 `json:"upper_slope" db:"upper_slope"`       // Slope of upper trend line
 	LowerSlope    float64 `json:"lower_slope" db:"lower_slope"`       // Slope of lower trend line
-	BreakoutLevel float64 `json:"breakout_level" db:"breakout_level"` // Current upper trend line level
+	BreakoutLevel float64 `json:"breakout_level" db:"breakout_level"` // Current upper (falling) or lower (rising) trend line level
 	PatternWidth  int64   `json:"pattern_width" db:"pattern_width"`   // Duration in minutes
 	PatternHeight float64 `json:"pattern_height" db:"pattern_height"` // Price range
 	Convergence   float64 `json:"convergence" db:"convergence"`       // How much lines converge (%)
@@ -38,15 +44,19 @@ type FallingWedgePattern struct {
 	LastUpdated  time.Time `json:"last_updated" db:"last_updated"`
 	IsComplete   bool      `json:"is_complete" db:"is_complete"`
 	CurrentPhase string    `json:"current_phase" db:"current_phase"` // "formation", "breakout", "target_pursuit", "completed"
+
+	// Archived records are kept for analytics but left out of lists unless asked for
+	Archived   bool       `json:"archived" db:"archived"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty" db:"archived_at"`
 }
 
 // FallingWedgeThesis represents the thesis components for falling wedge pattern
 type FallingWedgeThesis struct {
 	// Formation components (40 points)
-	DowntrendEstablished ThesisComponent `json:"downtrend_established"`  // 10 points
-	ConvergingTrendLines ThesisComponent `json:"converging_trend_lines"` // 10 points
-	MinimumTouchPoints   ThesisComponent `json:"minimum_touch_points"`   // 10 points
-	VolumeDecline        ThesisComponent `json:"volume_decline"`         // 10 points
+	PriorTrendEstablished ThesisComponent `json:"prior_trend_established"` // 10 points: a downtrend, or an uptrend for rising wedges
+	ConvergingTrendLines  ThesisComponent `json:"converging_trend_lines"`  // 10 points
+	MinimumTouchPoints    ThesisComponent `json:"minimum_touch_points"`    // 10 points
+	VolumeDecline         ThesisComponent `json:"volume_decline"`          // 10 points
 
 	// Breakout components (35 points)
 	UpperTrendLineBreak ThesisComponent `json:"upper_trend_line_break"` // 15 points
@@ -62,6 +72,9 @@ type FallingWedgeThesis struct {
 	TotalComponents     int     `json:"total_components"`
 	CompletionPercent   float64 `json:"completion_percent"`
 	CurrentPhase        string  `json:"current_phase"`
+
+	// Points each component contributed to the completion percent
+	ScoreBreakdown *ScoreBreakdown `json:"score_breakdown,omitempty"`
 }
 
 // FallingWedgeConfig holds configuration for falling wedge detection
@@ -75,23 +88,66 @@ type FallingWedgeConfig struct {
 	BreakoutVolumeRatio float64       `json:"breakout_volume_ratio" yaml:"breakout_volume_ratio"` // Breakout volume increase
 	MinWedgeHeight      float64       `json:"min_wedge_height" yaml:"min_wedge_height"`           // Minimum pattern height %
 	MaxWedgeSlope       float64       `json:"max_wedge_slope" yaml:"max_wedge_slope"`             // Maximum downward slope
+	BarInterval         Timeframe     `json:"bar_interval" yaml:"bar_interval"`                   // Bar size scanned when a request doesn't pick one
+	LookbackDays        int           `json:"lookback_days" yaml:"lookback_days"`                 // Days of bars read per scan
+	SwingWindow         int           `json:"swing_window" yaml:"swing_window"`                   // Bars on each side of a swing high/low
+	Sensitivity         float64       `json:"sensitivity" yaml:"sensitivity"`                     // Divides the swing window and minimum height
+}
+
+// DefaultFallingWedgeConfig returns the default falling wedge detection settings
+func DefaultFallingWedgeConfig() *FallingWedgeConfig {
+	return &FallingWedgeConfig{
+		MinPatternDuration:  48 * time.Hour,  // 2 days minimum
+		MaxPatternDuration:  480 * time.Hour, // 20 days maximum
+		MinConvergence:      0.005,           // 0.5% minimum convergence
+		MaxConvergence:      0.15,            // 15% maximum convergence
+		MinTouchPoints:      4,               // Minimum 4 touch points (2 per line)
+		VolumeDecreaseRatio: 0.8,             // Volume should decrease to 80% or less
+		BreakoutVolumeRatio: 1.5,             // Breakout volume should be 1.5x average
+		MinWedgeHeight:      0.03,            // 3% minimum height
+		MaxWedgeSlope:       -0.1,            // Maximum downward slope
+		BarInterval:         DefaultTimeframe,
+		LookbackDays:        90, // 3 months
+		SwingWindow:         5,
+		Sensitivity:         1.0,
+	}
 }
 
 // FallingWedgeFilter represents filter parameters for falling wedge queries
 type FallingWedgeFilter struct {
-	Symbol         string      `json:"symbol"`
-	Phase          string      `json:"phase"`
-	IsComplete     *bool       `json:"is_complete"`
-	MinConvergence float64     `json:"min_convergence"`
-	TimeRange      SRTimeRange `json:"time_range"`
-	Limit          int         `json:"limit"`
-	Offset         int         `json:"offset"`
+	Symbol          string      `json:"symbol"`
+	PatternType     string      `json:"pattern_type"` // "falling_wedge", "rising_wedge" or empty for both
+	Phase           string      `json:"phase"`
+	IsComplete      *bool       `json:"is_complete"`
+	MinConvergence  float64     `json:"min_convergence"`
+	TimeRange       SRTimeRange `json:"time_range"`
+	IncludeArchived bool        `json:"include_archived"`
+	Limit           int         `json:"limit"`
+	Offset          int         `json:"offset"`
+	Sort            string      `json:"sort"`  // one of PatternSortFields
+	Order           string      `json:"order"` // 'asc' or 'desc'
 }
 
 // Methods for FallingWedgePattern
 
+// IsRising reports whether this is a bearish rising wedge, which breaks down through its lower trend line
+func (fwp *FallingWedgePattern) IsRising() bool {
+	return fwp.PatternType == PatternRisingWedge
+}
+
+// Direction returns the trading direction of the pattern: 'bullish' or 'bearish'
+func (fwp *FallingWedgePattern) Direction() string {
+	if fwp.IsRising() {
+		return "bearish"
+	}
+	return "bullish"
+}
+
 // CalculateTargetPrice calculates the price target based on pattern height
 func (fwp *FallingWedgePattern) CalculateTargetPrice() float64 {
+	if fwp.IsRising() {
+		return fwp.BreakoutLevel - fwp.PatternHeight
+	}
 	return fwp.BreakoutLevel + fwp.PatternHeight
 }
 
@@ -103,6 +159,9 @@ func (fwp *FallingWedgePattern) GetConvergenceScore() float64 {
 
 // IsNearBreakout checks if price is near the breakout level
 func (fwp *FallingWedgePattern) IsNearBreakout(currentPrice float64) bool {
+	if fwp.IsRising() {
+		return currentPrice <= fwp.BreakoutLevel*1.02 // Within 2% of breakdown
+	}
 	threshold := fwp.BreakoutLevel * 0.98 // Within 2% of breakout
 	return currentPrice >= threshold
 }
@@ -114,12 +173,13 @@ func (fwp *FallingWedgePattern) GetPatternAge() float64 {
 
 // Methods for FallingWedgeThesis
 
-// InitializeWedgeThesis initializes the thesis with default values
-func (fwt *FallingWedgeThesis) InitializeWedgeThesis() {
+// InitializeWedgeThesis initializes the thesis with default values; a rising wedge reuses the
+// components mirrored, so the trend line break is a break below the lower line
+func (fwt *FallingWedgeThesis) InitializeWedgeThesis(patternType string) {
 	now := time.Now()
 
 	// Formation components
-	fwt.DowntrendEstablished = ThesisComponent{
+	fwt.PriorTrendEstablished = ThesisComponent{
 		Name:            "Downtrend Established",
 		Description:     "Clear downward price trend established",
 		IsCompleted:     false,
@@ -229,6 +289,17 @@ func (fwt *FallingWedgeThesis) InitializeWedgeThesis() {
 		AutoDetected:    true,
 	}
 
+	if patternType == PatternRisingWedge {
+		fwt.PriorTrendEstablished.Name = "Uptrend Established"
+		fwt.PriorTrendEstablished.Description = "Clear upward price trend established"
+		fwt.VolumeDecline.Description = "Volume decreases as the rally loses momentum"
+		fwt.UpperTrendLineBreak.Name = "Lower Trend Line Break"
+		fwt.UpperTrendLineBreak.Description = "Price breaks below lower trend line"
+		fwt.VolumeConfirmation.Description = "Breakdown confirmed with volume spike"
+		fwt.PriceCloseAboveLine.Name = "Price Close Below Line"
+		fwt.PriceCloseAboveLine.Description = "Price closes below trend line"
+	}
+
 	fwt.TotalComponents = 9
 	fwt.CalculateCompletion()
 	fwt.UpdatePhase()
@@ -237,7 +308,7 @@ func (fwt *FallingWedgeThesis) InitializeWedgeThesis() {
 // GetAllComponents returns all thesis components
 func (fwt *FallingWedgeThesis) GetAllComponents() []*ThesisComponent {
 	return []*ThesisComponent{
-		&fwt.DowntrendEstablished,
+		&fwt.PriorTrendEstablished,
 		&fwt.ConvergingTrendLines,
 		&fwt.MinimumTouchPoints,
 		&fwt.VolumeDecline,
@@ -270,6 +341,7 @@ func (fwt *FallingWedgeThesis) CalculateCompletion() {
 	if totalWeight > 0 {
 		fwt.CompletionPercent = (completedWeight / totalWeight) * 100
 	}
+	fwt.ScoreBreakdown = thesisScoreBreakdown(components, fwt.CompletionPercent, true)
 }
 
 // UpdatePhase updates the current phase based on completion
@@ -289,7 +361,7 @@ func (fwt *FallingWedgeThesis) UpdatePhase() {
 func (fwt *FallingWedgeThesis) GetFormationScore() float64 {
 	score := 0.0
 	components := []*ThesisComponent{
-		&fwt.DowntrendEstablished,
+		&fwt.PriorTrendEstablished,
 		&fwt.ConvergingTrendLines,
 		&fwt.MinimumTouchPoints,
 		&fwt.VolumeDecline,
@@ -0,0 +1,202 @@
+package models
+
+import (
+	"time"
+)
+
+// Flag pattern types
+const (
+	PatternBullFlag = "bull_flag"
+	PatternBearFlag = "bear_flag"
+)
+
+// FlagPattern represents a bull or bear flag chart pattern
+type FlagPattern struct {
+	ID     int64  `json:"id" db:"id"`
+	Symbol string `json:"symbol" db:"symbol"`
+
+	// Pattern identification
+	PatternType string `json:"pattern_type" db:"pattern_type"` // "bull_flag", "bear_flag"
+
+	// Flagpole and flag channel points
+	PoleStart PatternPoint `json:"pole_start" db:"pole_start"`
+	PoleEnd   PatternPoint `json:"pole_end" db:"pole_end"`
+	FlagHigh  PatternPoint `json:"flag_high" db:"flag_high"`
+	FlagLow   PatternPoint `json:"flag_low" db:"flag_low"`
+
+	// Pattern metrics
+	PoleHeight    float64 `json:"pole_height" db:"pole_height"`       // Absolute price move of the pole
+	PoleChange    float64 `json:"pole_change" db:"pole_change"`       // Pole move in percent
+	Retracement   float64 `json:"retracement" db:"retracement"`       // Flag retracement of the pole (%)
+	FlagSlope     float64 `json:"flag_slope" db:"flag_slope"`         // Slope of the flag channel
+	BreakoutLevel float64 `json:"breakout_level" db:"breakout_level"` // Flag boundary the price must break
+	PatternWidth  int64   `json:"pattern_width" db:"pattern_width"`   // Duration in minutes
+
+	// Volume analysis
+	VolumeProfile string `json:"volume_profile" db:"volume_profile"` // "decreasing", "stable", "increasing"
+
+	// Thesis tracking
+	ThesisComponents FlagThesis `json:"thesis_components" db:"thesis_components"`
+
+	// Status
+	DetectedAt   time.Time `json:"detected_at" db:"detected_at"`
+	LastUpdated  time.Time `json:"last_updated" db:"last_updated"`
+	IsComplete   bool      `json:"is_complete" db:"is_complete"`
+	CurrentPhase string    `json:"current_phase" db:"current_phase"` // "formation", "breakout", "target_pursuit", "completed"
+
+	// Archived records are kept for analytics but left out of lists unless asked for
+	Archived   bool       `json:"archived" db:"archived"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty" db:"archived_at"`
+}
+
+// FlagThesis represents the thesis components for flag patterns
+type FlagThesis struct {
+	// Formation components (40 points)
+	StrongPole         ThesisComponent `json:"strong_pole"`         // 15 points
+	OrderlyChannel     ThesisComponent `json:"orderly_channel"`     // 10 points
+	ShallowRetracement ThesisComponent `json:"shallow_retracement"` // 10 points
+	VolumeDecline      ThesisComponent `json:"volume_decline"`      // 5 points
+
+	// Breakout components (35 points)
+	ChannelBreak       ThesisComponent `json:"channel_break"`       // 15 points
+	VolumeConfirmation ThesisComponent `json:"volume_confirmation"` // 10 points
+	PriceCloseBeyond   ThesisComponent `json:"price_close_beyond"`  // 10 points
+
+	// Target components (25 points)
+	PartialTarget ThesisComponent `json:"partial_target"` // 10 points
+	FullTarget    ThesisComponent `json:"full_target"`    // 15 points
+
+	// Calculated metrics
+	CompletedComponents int     `json:"completed_components"`
+	TotalComponents     int     `json:"total_components"`
+	CompletionPercent   float64 `json:"completion_percent"`
+	CurrentPhase        string  `json:"current_phase"`
+
+	// Points each component contributed to the completion percent
+	ScoreBreakdown *ScoreBreakdown `json:"score_breakdown,omitempty"`
+}
+
+// FlagConfig holds configuration for flag detection
+type FlagConfig struct {
+	MinPoleChange       float64       `json:"min_pole_change" yaml:"min_pole_change"`     // Minimum pole move (%)
+	MaxPoleDuration     time.Duration `json:"max_pole_duration" yaml:"max_pole_duration"` // Pole must be a sharp move
+	MinFlagDuration     time.Duration `json:"min_flag_duration" yaml:"min_flag_duration"`
+	MaxFlagDuration     time.Duration `json:"max_flag_duration" yaml:"max_flag_duration"`
+	MaxRetracement      float64       `json:"max_retracement" yaml:"max_retracement"`             // Max flag retracement of the pole (%)
+	BreakoutVolumeRatio float64       `json:"breakout_volume_ratio" yaml:"breakout_volume_ratio"` // Breakout volume increase
+}
+
+// DefaultFlagConfig returns the default flag detection settings
+func DefaultFlagConfig() *FlagConfig {
+	return &FlagConfig{
+		MinPoleChange:       5.0,             // Pole must move at least 5%
+		MaxPoleDuration:     72 * time.Hour,  // Pole forms within 3 days
+		MinFlagDuration:     4 * time.Hour,   // Flag consolidates at least 4 hours
+		MaxFlagDuration:     120 * time.Hour, // Flag older than 5 days is no longer a flag
+		MaxRetracement:      50.0,            // Flag retraces at most half of the pole
+		BreakoutVolumeRatio: 1.5,             // Breakout volume should be 1.5x average
+	}
+}
+
+// FlagFilter represents filter parameters for flag queries
+type FlagFilter struct {
+	Symbol          string `json:"symbol"`
+	PatternType     string `json:"pattern_type"`
+	Phase           string `json:"phase"`
+	IsComplete      *bool  `json:"is_complete"`
+	IncludeArchived bool   `json:"include_archived"`
+	Limit           int    `json:"limit"`
+	Offset          int    `json:"offset"`
+	Sort            string `json:"sort"`  // one of PatternSortFields
+	Order           string `json:"order"` // 'asc' or 'desc'
+}
+
+// Methods for FlagPattern
+
+// IsBullish returns true for bull flags, which break out upward
+func (fp *FlagPattern) IsBullish() bool {
+	return fp.PatternType == PatternBullFlag
+}
+
+// CalculateTargetPrice projects the pole height from the breakout level
+func (fp *FlagPattern) CalculateTargetPrice() float64 {
+	if fp.IsBullish() {
+		return fp.BreakoutLevel + fp.PoleHeight
+	}
+	return fp.BreakoutLevel - fp.PoleHeight
+}
+
+// IsBrokenOut checks if price has moved beyond the breakout level
+func (fp *FlagPattern) IsBrokenOut(currentPrice float64) bool {
+	if fp.IsBullish() {
+		return currentPrice > fp.BreakoutLevel
+	}
+	return currentPrice < fp.BreakoutLevel
+}
+
+// GetPatternAge returns the age of the pattern in hours
+func (fp *FlagPattern) GetPatternAge() float64 {
+	return time.Since(fp.DetectedAt).Hours()
+}
+
+// Methods for FlagThesis
+
+// InitializeFlagThesis initializes the thesis with default values
+func (ft *FlagThesis) InitializeFlagThesis(bullish bool) {
+	move, direction := "rally", "above"
+	if !bullish {
+		move, direction = "decline", "below"
+	}
+
+	// Formation components
+	ft.StrongPole = newThesisComponent("Strong Flagpole", "Sharp "+move+" forms the flagpole", true, 15.0)
+	ft.OrderlyChannel = newThesisComponent("Orderly Channel", "Price consolidates in a tight channel against the pole", true, 10.0)
+	ft.ShallowRetracement = newThesisComponent("Shallow Retracement", "Flag retraces less than half of the pole", true, 10.0)
+	ft.VolumeDecline = newThesisComponent("Volume Decline", "Volume dries up during consolidation", false, 5.0)
+
+	// Breakout components
+	ft.ChannelBreak = newThesisComponent("Channel Break", "Price breaks "+direction+" the flag channel", true, 15.0)
+	ft.VolumeConfirmation = newThesisComponent("Volume Confirmation", "Breakout confirmed with volume spike", false, 10.0)
+	ft.PriceCloseBeyond = newThesisComponent("Price Close Beyond Channel", "Price closes "+direction+" the flag channel", true, 10.0)
+
+	// Target components
+	ft.PartialTarget = newThesisComponent("Partial Target", "50% of projected target reached", false, 10.0)
+	ft.FullTarget = newThesisComponent("Full Target", "Full flagpole projection reached", false, 15.0)
+
+	ft.CalculateCompletion()
+	ft.UpdatePhase()
+}
+
+// GetAllComponents returns all thesis components
+func (ft *FlagThesis) GetAllComponents() []*ThesisComponent {
+	return []*ThesisComponent{
+		&ft.StrongPole,
+		&ft.OrderlyChannel,
+		&ft.ShallowRetracement,
+		&ft.VolumeDecline,
+		&ft.ChannelBreak,
+		&ft.VolumeConfirmation,
+		&ft.PriceCloseBeyond,
+		&ft.PartialTarget,
+		&ft.FullTarget,
+	}
+}
+
+// CalculateCompletion calculates completion statistics
+func (ft *FlagThesis) CalculateCompletion() {
+	ft.CompletedComponents, ft.TotalComponents, ft.CompletionPercent = calculateThesisCompletion(ft.GetAllComponents())
+	ft.ScoreBreakdown = thesisScoreBreakdown(ft.GetAllComponents(), ft.CompletionPercent, true)
+}
+
+// UpdatePhase updates the current phase based on completion
+func (ft *FlagThesis) UpdatePhase() {
+	if ft.FullTarget.IsCompleted {
+		ft.CurrentPhase = PhaseCompleted
+	} else if ft.ChannelBreak.IsCompleted {
+		ft.CurrentPhase = PhaseTargetPursuit
+	} else if ft.StrongPole.IsCompleted && ft.OrderlyChannel.IsCompleted {
+		ft.CurrentPhase = PhaseBreakout
+	} else {
+		ft.CurrentPhase = PhaseFormation
+	}
+}
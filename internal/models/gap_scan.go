@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// GapScanEntry is a watched symbol's pre-market gap from the prior regular session close on one trading day
+type GapScanEntry struct {
+	TradeDate       string    `json:"trade_date" db:"trade_date"` // YYYY-MM-DD, exchange time
+	Symbol          string    `json:"symbol" db:"symbol"`
+	Rank            int       `json:"rank" db:"rank"` // 1 for the largest gap either way
+	PrevClose       float64   `json:"prev_close" db:"prev_close"`
+	PreMarketPrice  float64   `json:"pre_market_price" db:"pre_market_price"` // latest pre-market close
+	GapPercent      float64   `json:"gap_percent" db:"gap_percent"`
+	PreMarketHigh   float64   `json:"pre_market_high" db:"pre_market_high"`
+	PreMarketLow    float64   `json:"pre_market_low" db:"pre_market_low"`
+	PreMarketVolume int64     `json:"pre_market_volume" db:"pre_market_volume"`
+	RVOL            float64   `json:"rvol" db:"rvol"`     // pre-market volume against prior sessions by the same time, 0 when unknown
+	Gapper          bool      `json:"gapper" db:"gapper"` // meets the gap and RVOL thresholds
+	ScannedAt       time.Time `json:"scanned_at" db:"scanned_at"`
+}
+
+// GapReport ranks the watched symbols gapping before the open
+type GapReport struct {
+	TradeDate     string          `json:"trade_date"`
+	MinGapPercent float64         `json:"min_gap_percent"`
+	MinRVOL       float64         `json:"min_rvol"`
+	Gappers       int             `json:"gappers"`
+	Strategy      string          `json:"strategy,omitempty"` // watchlist strategy the gappers were tagged into
+	Entries       []*GapScanEntry `json:"entries"`
+	Skipped       []string        `json:"skipped,omitempty"` // symbols without a prior close or pre-market bars
+	ScannedAt     time.Time       `json:"scanned_at"`
+}
@@ -53,6 +53,15 @@ type HeadShouldersPattern struct {
 	LastUpdated  time.Time `json:"last_updated" db:"last_updated"`
 	IsComplete   bool      `json:"is_complete" db:"is_complete"`
 	CurrentPhase string    `json:"current_phase" db:"current_phase"`
+	AlertCount   int       `json:"alert_count"` // Pattern alerts triggered so far
+
+	// Manual corrections: an edited pattern keeps its key points when rescans find the formation again
+	HumanEdited bool   `json:"human_edited" db:"human_edited"`
+	Annotation  string `json:"annotation,omitempty" db:"annotation"`
+
+	// Archived records are kept for analytics but left out of lists unless asked for
+	Archived   bool       `json:"archived" db:"archived"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty" db:"archived_at"`
 }
 
 // PatternPoint represents a specific point in the pattern
@@ -72,7 +81,8 @@ type HeadShouldersThesis struct {
 	// Head Components
 	HeadFormed      ThesisComponent `json:"head_formed"`
 	HeadVolumeSpike ThesisComponent `json:"head_volume_spike"`
-	HeadLowerLow    ThesisComponent `json:"head_lower_low"` // For inverse H&S
+	HeadLowerLow    ThesisComponent `json:"head_lower_low"`   // For inverse H&S
+	HeadHigherHigh  ThesisComponent `json:"head_higher_high"` // For regular H&S
 
 	// Right Shoulder Components
 	RightShoulderFormed   ThesisComponent `json:"right_shoulder_formed"`
@@ -96,6 +106,9 @@ type HeadShouldersThesis struct {
 	TotalComponents     int     `json:"total_components"`
 	CompletionPercent   float64 `json:"completion_percent"`
 	CurrentPhase        string  `json:"current_phase"`
+
+	// Points each component contributed to the completion percent
+	ScoreBreakdown *ScoreBreakdown `json:"score_breakdown,omitempty"`
 }
 
 // ThesisComponent represents a single component of the thesis
@@ -123,18 +136,43 @@ type HeadShouldersConfig struct {
 	TargetMultiplier     float64       `json:"target_multiplier" yaml:"target_multiplier"`
 	MinHeadDepth         float64       `json:"min_head_depth" yaml:"min_head_depth"`
 	MaxShoulderAsymmetry float64       `json:"max_shoulder_asymmetry" yaml:"max_shoulder_asymmetry"`
+	BarInterval          Timeframe     `json:"bar_interval" yaml:"bar_interval"`   // Bar size scanned when a request doesn't pick one
+	LookbackDays         int           `json:"lookback_days" yaml:"lookback_days"` // Days of bars read per scan
+	SwingWindow          int           `json:"swing_window" yaml:"swing_window"`   // Bars on each side of a peak/trough
+	Sensitivity          float64       `json:"sensitivity" yaml:"sensitivity"`     // Divides the swing window and minimum head depth
+}
+
+// DefaultHeadShouldersConfig returns the default head and shoulders detection settings
+func DefaultHeadShouldersConfig() *HeadShouldersConfig {
+	return &HeadShouldersConfig{
+		MinPatternDuration:   72 * time.Hour,  // 3 days minimum
+		MaxPatternDuration:   720 * time.Hour, // 30 days maximum
+		MinSymmetryScore:     60.0,            // 60% symmetry minimum
+		MinVolumeIncrease:    1.2,             // 20% volume increase
+		NecklineDeviation:    0.02,            // 2% deviation allowed
+		TargetMultiplier:     1.0,             // 1:1 target projection
+		MinHeadDepth:         0.05,            // 5% minimum head depth
+		MaxShoulderAsymmetry: 0.3,             // 30% max asymmetry between shoulders
+		BarInterval:          DefaultTimeframe,
+		LookbackDays:         180, // 6 months
+		SwingWindow:          5,
+		Sensitivity:          1.0,
+	}
 }
 
 // PatternFilter represents filter parameters for pattern queries
 type PatternFilter struct {
-	Symbol      string      `json:"symbol"`
-	PatternType string      `json:"pattern_type"`
-	Phase       string      `json:"phase"`
-	IsComplete  *bool       `json:"is_complete"`
-	MinSymmetry float64     `json:"min_symmetry"`
-	TimeRange   SRTimeRange `json:"time_range"`
-	Limit       int         `json:"limit"`
-	Offset      int         `json:"offset"`
+	Symbol          string      `json:"symbol"`
+	PatternType     string      `json:"pattern_type"`
+	Phase           string      `json:"phase"`
+	IsComplete      *bool       `json:"is_complete"`
+	MinSymmetry     float64     `json:"min_symmetry"`
+	TimeRange       SRTimeRange `json:"time_range"`
+	IncludeArchived bool        `json:"include_archived"`
+	Limit           int         `json:"limit"`
+	Offset          int         `json:"offset"`
+	Sort            string      `json:"sort"`  // one of PatternSortFields
+	Order           string      `json:"order"` // 'asc' or 'desc'
 }
 
 // PatternAlert represents an alert for a pattern
@@ -172,6 +210,27 @@ func (hsp *HeadShouldersPattern) IsTargetPursuit() bool {
 	return hsp.CurrentPhase == PhaseTargetPursuit
 }
 
+// IsBearish reports whether this is a regular head and shoulders top, which projects a move below the neckline
+func (hsp *HeadShouldersPattern) IsBearish() bool {
+	return hsp.PatternType == SetupTypeHeadShoulders
+}
+
+// Direction returns the trading direction of the pattern: 'bullish' or 'bearish'
+func (hsp *HeadShouldersPattern) Direction() string {
+	if hsp.IsBearish() {
+		return "bearish"
+	}
+	return "bullish"
+}
+
+// DisplayName returns the human readable pattern name
+func (hsp *HeadShouldersPattern) DisplayName() string {
+	if hsp.IsBearish() {
+		return "Head and Shoulders"
+	}
+	return "Inverse Head and Shoulders"
+}
+
 // CalculatePatternHeight calculates the height of the pattern
 func (hsp *HeadShouldersPattern) CalculatePatternHeight() float64 {
 	if hsp.PatternType == SetupTypeInverseHeadShoulders {
@@ -288,6 +347,15 @@ func (hst *HeadShouldersThesis) InitializeThesis(patternType string) {
 			LastChecked:  now,
 			AutoDetected: true,
 		}
+	} else {
+		hst.HeadHigherHigh = ThesisComponent{
+			Name:         "Head Higher High",
+			Description:  "Head forms a higher high than both shoulders",
+			IsRequired:   true,
+			Weight:       12.0,
+			LastChecked:  now,
+			AutoDetected: true,
+		}
 	}
 
 	hst.RightShoulderFormed = ThesisComponent{
@@ -410,6 +478,7 @@ func (hst *HeadShouldersThesis) CalculateCompletion() {
 	if total > 0 {
 		hst.CompletionPercent = (float64(completed) / float64(total)) * 100
 	}
+	hst.ScoreBreakdown = thesisScoreBreakdown(components, hst.CompletionPercent, false)
 }
 
 // GetAllComponents returns all thesis components
@@ -419,7 +488,7 @@ func (hst *HeadShouldersThesis) GetAllComponents() []*ThesisComponent {
 		&hst.LeftShoulderVolume,
 		&hst.HeadFormed,
 		&hst.HeadVolumeSpike,
-		&hst.HeadLowerLow,
+		hst.headExtreme(),
 		&hst.RightShoulderFormed,
 		&hst.RightShoulderSymmetry,
 		&hst.RightShoulderVolume,
@@ -434,6 +503,14 @@ func (hst *HeadShouldersThesis) GetAllComponents() []*ThesisComponent {
 	}
 }
 
+// headExtreme returns the head higher high component of a regular pattern, or the head lower low of an inverse one
+func (hst *HeadShouldersThesis) headExtreme() *ThesisComponent {
+	if hst.HeadHigherHigh.Name != "" {
+		return &hst.HeadHigherHigh
+	}
+	return &hst.HeadLowerLow
+}
+
 // GetFormationComponents returns formation phase components
 func (hst *HeadShouldersThesis) GetFormationComponents() []*ThesisComponent {
 	return []*ThesisComponent{
@@ -441,7 +518,7 @@ func (hst *HeadShouldersThesis) GetFormationComponents() []*ThesisComponent {
 		&hst.LeftShoulderVolume,
 		&hst.HeadFormed,
 		&hst.HeadVolumeSpike,
-		&hst.HeadLowerLow,
+		hst.headExtreme(),
 		&hst.RightShoulderFormed,
 		&hst.RightShoulderSymmetry,
 		&hst.RightShoulderVolume,
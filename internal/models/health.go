@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Dependency check statuses
+const (
+	CheckStatusOK       = "ok"
+	CheckStatusDegraded = "degraded" // working, but limited; does not make the server unready
+	CheckStatusError    = "error"
+)
+
+// Readiness statuses
+const (
+	ReadinessReady    = "ready"
+	ReadinessDegraded = "degraded" // ready, with a non-critical dependency failing
+	ReadinessNotReady = "not_ready"
+)
+
+// DependencyCheck is the result of checking one dependency of the server
+type DependencyCheck struct {
+	Name      string      `json:"name"`
+	Status    string      `json:"status"`
+	Critical  bool        `json:"critical"` // the server is not ready while a critical dependency fails
+	Message   string      `json:"message,omitempty"`
+	LatencyMS int64       `json:"latency_ms"`
+	CheckedAt time.Time   `json:"checked_at"`
+	Cached    bool        `json:"cached,omitempty"` // the result of an earlier check, to spare the provider's quota
+	Details   interface{} `json:"details,omitempty"`
+}
+
+// ReadinessReport is the result of checking every dependency of the server
+type ReadinessReport struct {
+	Status    string             `json:"status"`
+	Ready     bool               `json:"ready"`
+	Timestamp time.Time          `json:"timestamp"`
+	Checks    []*DependencyCheck `json:"checks"`
+}
+
+// LivenessReport reports that the server process is up
+type LivenessReport struct {
+	Status        string    `json:"status"`
+	Timestamp     time.Time `json:"timestamp"`
+	StartedAt     time.Time `json:"started_at"`
+	UptimeSeconds int64     `json:"uptime_seconds"`
+}
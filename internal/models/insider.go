@@ -0,0 +1,53 @@
+package models
+
+// Form 4 transaction codes most relevant to trading
+const (
+	InsiderCodePurchase = "P" // open market or private purchase
+	InsiderCodeSale     = "S" // open market or private sale
+	InsiderCodeAward    = "A" // grant or award from the company
+	InsiderCodeExercise = "M" // exercise or conversion of a derivative
+	InsiderCodeTax      = "F" // shares withheld for the exercise price or taxes
+	InsiderCodeGift     = "G" // gift
+)
+
+// InsiderTransaction is one transaction reported in an SEC Form 4 filing
+type InsiderTransaction struct {
+	ID                int64   `json:"id" db:"id"`
+	Symbol            string  `json:"symbol" db:"symbol"`
+	AccessionNumber   string  `json:"accession_number" db:"accession_number"` // EDGAR filing the transaction was reported in
+	Line              int     `json:"line" db:"line"`                         // Position of the transaction in the filing
+	InsiderName       string  `json:"insider_name" db:"insider_name"`
+	InsiderTitle      string  `json:"insider_title" db:"insider_title"` // Officer title, Director or 10% Owner
+	IsDirector        bool    `json:"is_director" db:"is_director"`
+	IsOfficer         bool    `json:"is_officer" db:"is_officer"`
+	IsTenPercentOwner bool    `json:"is_ten_percent_owner" db:"is_ten_percent_owner"`
+	SecurityTitle     string  `json:"security_title" db:"security_title"`
+	IsDerivative      bool    `json:"is_derivative" db:"is_derivative"`         // Options, warrants and other derivatives
+	TransactionDate   string  `json:"transaction_date" db:"transaction_date"`   // YYYY-MM-DD
+	FiledDate         string  `json:"filed_date" db:"filed_date"`               // YYYY-MM-DD
+	TransactionCode   string  `json:"transaction_code" db:"transaction_code"`   // 'P', 'S', 'A', 'M', 'F', 'G' or another Form 4 code
+	AcquiredDisposed  string  `json:"acquired_disposed" db:"acquired_disposed"` // 'A' acquired or 'D' disposed
+	Shares            float64 `json:"shares" db:"shares"`
+	Price             float64 `json:"price" db:"price"`
+	Value             float64 `json:"value" db:"value"`
+	SharesOwnedAfter  float64 `json:"shares_owned_after" db:"shares_owned_after"`
+}
+
+// IsOpenMarketPurchase reports whether the transaction is an insider buying common stock with their own money
+func (t *InsiderTransaction) IsOpenMarketPurchase() bool {
+	return t.TransactionCode == InsiderCodePurchase && !t.IsDerivative
+}
+
+// InsiderSummary is a stock's recent insider activity
+type InsiderSummary struct {
+	Symbol        string                `json:"symbol"`
+	Days          int                   `json:"days"`
+	Purchases     int                   `json:"purchases"` // Open market purchases
+	Sales         int                   `json:"sales"`     // Open market sales
+	BuyValue      float64               `json:"buy_value"`
+	SellValue     float64               `json:"sell_value"`
+	NetValue      float64               `json:"net_value"`      // Buy value less sell value
+	ClusterBuyers []string              `json:"cluster_buyers"` // Insiders with open market purchases in the cluster window
+	ClusterBuying bool                  `json:"cluster_buying"`
+	Transactions  []*InsiderTransaction `json:"transactions"` // Newest first
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Job statuses
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusCompleted = "completed"
+	JobStatusFailed    = "failed"
+	JobStatusCancelled = "cancelled"
+)
+
+// Job types
+const (
+	JobTypePatternScan        = "pattern_scan"
+	JobTypeBackfill           = "backfill"
+	JobTypeIndicatorRecompute = "indicator_recompute"
+)
+
+// Job represents a background job that processes a list of items, usually symbols
+type Job struct {
+	ID         string                 `json:"id"`
+	Type       string                 `json:"type"`
+	Status     string                 `json:"status"`
+	Params     map[string]interface{} `json:"params,omitempty"`
+	Total      int                    `json:"total"`
+	Completed  int                    `json:"completed"` // Items processed, including failures
+	Failed     int                    `json:"failed"`
+	Progress   float64                `json:"progress"` // Percent of items processed
+	Results    map[string]interface{} `json:"results,omitempty"`
+	Errors     map[string]string      `json:"errors,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+	StartedAt  *time.Time             `json:"started_at,omitempty"`
+	FinishedAt *time.Time             `json:"finished_at,omitempty"`
+}
+
+// IsFinished returns true once the job will not process any more items
+func (j *Job) IsFinished() bool {
+	return j.Status == JobStatusCompleted || j.Status == JobStatusFailed || j.Status == JobStatusCancelled
+}
+
+// JobFilter represents filter parameters for listing jobs
+type JobFilter struct {
+	Type   string `json:"type"`
+	Status string `json:"status"`
+	Limit  int    `json:"limit"`
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Journal entry types
+const (
+	JournalTypeNote   = "note"   // research, thesis or general observation
+	JournalTypeEntry  = "entry"  // rationale for opening a trade
+	JournalTypeExit   = "exit"   // rationale for closing a trade
+	JournalTypeReview = "review" // post-trade review and lessons
+)
+
+// Tag and journal title limits
+const (
+	MaxTagLength        = 32
+	MaxJournalTitleSize = 200
+)
+
+// JournalTypes lists the accepted journal entry types
+var JournalTypes = []string{JournalTypeNote, JournalTypeEntry, JournalTypeExit, JournalTypeReview}
+
+// JournalEntry is a dated note about a stock, optionally tied to a trading setup
+type JournalEntry struct {
+	ID        int64     `json:"id" db:"id"`
+	Symbol    string    `json:"symbol" db:"symbol"`
+	SetupID   *int64    `json:"setup_id,omitempty" db:"setup_id"`
+	EntryType string    `json:"entry_type" db:"entry_type"` // 'note', 'entry', 'exit' or 'review'
+	EntryDate time.Time `json:"entry_date" db:"entry_date"` // the day the entry is about, defaults to now
+	Title     string    `json:"title" db:"title"`
+	Body      string    `json:"body" db:"body"`
+	Tags      []string  `json:"tags" db:"tags"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// JournalFilter represents filter parameters for journal entry queries
+type JournalFilter struct {
+	Symbol    string    `json:"symbol"`
+	SetupID   int64     `json:"setup_id"`
+	EntryType string    `json:"entry_type"`
+	Tag       string    `json:"tag"`
+	Query     string    `json:"q"` // matched against title and body, case insensitive
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Limit     int       `json:"limit"`
+}
+
+// TagCount is a user tag with the number of stocks carrying it
+type TagCount struct {
+	Tag    string `json:"tag"`
+	Stocks int    `json:"stocks"`
+}
+
+// WatchlistSearchResult holds the stocks and journal entries matching a search
+type WatchlistSearchResult struct {
+	Query   string          `json:"query"`
+	Stocks  []Stock         `json:"stocks"`  // symbol, name, notes or a tag match
+	Journal []*JournalEntry `json:"journal"` // title or body match, newest first
+}
+
+// Validate checks that a journal entry is well formed and normalizes its symbol, type and tags
+func (e *JournalEntry) Validate() error {
+	e.Symbol = strings.ToUpper(strings.TrimSpace(e.Symbol))
+	if e.Symbol == "" {
+		return fmt.Errorf("symbol is required")
+	}
+
+	e.EntryType = strings.ToLower(strings.TrimSpace(e.EntryType))
+	if e.EntryType == "" {
+		e.EntryType = JournalTypeNote
+	}
+	valid := false
+	for _, entryType := range JournalTypes {
+		valid = valid || entryType == e.EntryType
+	}
+	if !valid {
+		return fmt.Errorf("invalid entry_type %q: must be one of %s", e.EntryType, strings.Join(JournalTypes, ", "))
+	}
+
+	e.Title = strings.TrimSpace(e.Title)
+	if len(e.Title) > MaxJournalTitleSize {
+		return fmt.Errorf("title must be at most %d characters", MaxJournalTitleSize)
+	}
+	if e.Title == "" && strings.TrimSpace(e.Body) == "" {
+		return fmt.Errorf("title or body is required")
+	}
+
+	tags, err := NormalizeTags(e.Tags)
+	if err != nil {
+		return err
+	}
+	e.Tags = tags
+
+	return nil
+}
+
+// NormalizeTags lower-cases, trims, deduplicates and sorts tags. Tags may not contain commas.
+func NormalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool)
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if strings.Contains(tag, ",") {
+			return nil, fmt.Errorf("tag %q may not contain commas", tag)
+		}
+		if len(tag) > MaxTagLength {
+			return nil, fmt.Errorf("tag %q is longer than %d characters", tag, MaxTagLength)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Market regimes setups are scored against
+const (
+	MarketRegimeBullish = "bullish"
+	MarketRegimeBearish = "bearish"
+	MarketRegimeNeutral = "neutral"
+)
+
+// IndexTrend is a benchmark's latest daily close against its 20 and 50 day simple moving averages
+type IndexTrend struct {
+	Symbol        string    `json:"symbol"`
+	Close         float64   `json:"close"`
+	ChangePercent float64   `json:"change_percent"` // latest close against the prior session's
+	SMA20         float64   `json:"sma_20"`
+	SMA50         float64   `json:"sma_50,omitempty"` // 0 until 50 sessions are stored
+	AboveSMA20    bool      `json:"above_sma_20"`
+	AboveSMA50    bool      `json:"above_sma_50"`
+	Trend         string    `json:"trend"` // one of the market regimes
+	AsOf          time.Time `json:"as_of"` // session of the latest close
+}
+
+// MarketBreadth summarizes the watched symbols' daily closes
+type MarketBreadth struct {
+	Symbols           int     `json:"symbols"` // watched stocks and ETFs with at least 20 daily closes
+	AboveSMA20        int     `json:"above_sma_20"`
+	AboveSMA20Percent float64 `json:"above_sma_20_percent"`
+	WithSMA50         int     `json:"with_sma_50"` // symbols with 50 daily closes
+	AboveSMA50        int     `json:"above_sma_50"`
+	AboveSMA50Percent float64 `json:"above_sma_50_percent"` // of the symbols with 50 daily closes
+	Advancing         int     `json:"advancing"`
+	Declining         int     `json:"declining"`
+	Unchanged         int     `json:"unchanged"`
+	AdvanceDecline    int     `json:"advance_decline"` // advancing minus declining
+}
+
+// MarketContext is the benchmark trends and watchlist breadth behind the market regime
+type MarketContext struct {
+	Regime      string         `json:"regime"`
+	Index       *IndexTrend    `json:"index,omitempty"`   // the regime index
+	Benchmarks  []*IndexTrend  `json:"benchmarks"`        // every collected benchmark, in configured order
+	Breadth     *MarketBreadth `json:"breadth"`           // watched symbols, benchmarks not included
+	Skipped     []string       `json:"skipped,omitempty"` // symbols without 20 daily closes
+	GeneratedAt time.Time      `json:"generated_at"`
+}
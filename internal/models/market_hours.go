@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// MarketSession identifies which part of the trading day a time falls into
+type MarketSession string
+
+// Market sessions
+const (
+	SessionClosed     MarketSession = "closed"
+	SessionPreMarket  MarketSession = "pre_market"
+	SessionRegular    MarketSession = "regular"
+	SessionPostMarket MarketSession = "post_market"
+)
+
+// Collection schedules for market_hours.collect_sessions
+const (
+	CollectSessionsExtended = "extended" // pre-market, regular and post-market on trading days
+	CollectSessionsRegular  = "regular"  // regular session only
+	CollectSessionsAlways   = "always"   // around the clock, including weekends and holidays
+)
+
+// MarketStatus describes the exchange session at a point in time
+type MarketStatus struct {
+	Exchange     string        `json:"exchange"`
+	Time         time.Time     `json:"time"` // exchange local time
+	Session      MarketSession `json:"session"`
+	IsOpen       bool          `json:"is_open"` // regular session
+	IsTradingDay bool          `json:"is_trading_day"`
+	IsHalfDay    bool          `json:"is_half_day"`
+	Holiday      string        `json:"holiday,omitempty"`
+	Collecting   bool          `json:"collecting"`         // whether the collector runs at this time
+	OpensAt      *time.Time    `json:"opens_at,omitempty"` // regular session open, trading days only
+	ClosesAt     *time.Time    `json:"closes_at,omitempty"`
+	NextOpen     time.Time     `json:"next_open"`
+}
+
+// TradingSession is the regular session of one trading day
+type TradingSession struct {
+	TradingDay string    `json:"trading_day"` // exchange date, YYYY-MM-DD
+	Open       time.Time `json:"open"`
+	Close      time.Time `json:"close"`
+}
+
+// MarketHoliday is a full-day closure or early close on the exchange calendar
+type MarketHoliday struct {
+	Date       string `json:"date"` // YYYY-MM-DD
+	Name       string `json:"name"`
+	EarlyClose bool   `json:"early_close"` // closes at 1:00 PM instead of a full-day closure
+}
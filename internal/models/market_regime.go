@@ -0,0 +1,64 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Market regimes classified daily from the regime index
+const (
+	RegimeTrendingUp     = "trending_up"     // ADX trend with price above its 50-day SMA
+	RegimeTrendingDown   = "trending_down"   // ADX trend with price below its 50-day SMA
+	RegimeChoppy         = "choppy"          // no ADX trend
+	RegimeHighVolatility = "high_volatility" // short-term ATR well above its longer average, whatever the trend
+)
+
+// MarketRegimes lists the classified market regimes
+var MarketRegimes = []string{RegimeTrendingUp, RegimeTrendingDown, RegimeChoppy, RegimeHighVolatility}
+
+// IsMarketRegime reports whether regime is a classified market regime
+func IsMarketRegime(regime string) bool {
+	return slices.Contains(MarketRegimes, regime)
+}
+
+// MarketRegimeLabel is the regime index's classification for one trading day
+type MarketRegimeLabel struct {
+	Symbol          string  `json:"symbol" db:"symbol"`
+	TradeDate       string  `json:"trade_date" db:"trade_date"` // YYYY-MM-DD
+	Regime          string  `json:"regime" db:"regime"`
+	Close           float64 `json:"close" db:"close"`
+	SMA50           float64 `json:"sma_50" db:"sma_50"`
+	ADX             float64 `json:"adx" db:"adx"`
+	PlusDI          float64 `json:"plus_di" db:"plus_di"`
+	MinusDI         float64 `json:"minus_di" db:"minus_di"`
+	ATRPercent      float64 `json:"atr_percent" db:"atr_percent"`           // 14-day ATR as a percent of the close
+	VolatilityRatio float64 `json:"volatility_ratio" db:"volatility_ratio"` // 14-day ATR over the 50-day ATR
+}
+
+// StrategyRegimes declares the market regimes a watchlist strategy's long and short setups trade in
+type StrategyRegimes struct {
+	StrategyID int       `json:"strategy_id" db:"strategy_id"`
+	Long       []string  `json:"long" db:"long_regimes"`   // regimes bullish setups are detected in, empty for any
+	Short      []string  `json:"short" db:"short_regimes"` // regimes bearish setups are detected in, empty for any
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Validate checks the declared regimes are known
+func (s *StrategyRegimes) Validate() error {
+	for _, regime := range append(slices.Clone(s.Long), s.Short...) {
+		if !IsMarketRegime(regime) {
+			return fmt.Errorf("unknown market regime %q, expected one of %v", regime, MarketRegimes)
+		}
+	}
+	return nil
+}
+
+// Allows reports whether the strategy trades setups of a direction in a regime
+func (s *StrategyRegimes) Allows(direction, regime string) bool {
+	regimes := s.Long
+	if direction == "bearish" {
+		regimes = s.Short
+	}
+	return len(regimes) == 0 || slices.Contains(regimes, regime)
+}
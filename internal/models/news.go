@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// News sentiment tags
+const (
+	NewsSentimentPositive = "positive"
+	NewsSentimentNegative = "negative"
+	NewsSentimentNeutral  = "neutral"
+)
+
+// NewsArticle represents a headline stored for a watched symbol
+type NewsArticle struct {
+	ID             int64     `json:"id" db:"id"`
+	Symbol         string    `json:"symbol" db:"symbol"`
+	ArticleID      string    `json:"article_id" db:"article_id"` // Provider article ID
+	Title          string    `json:"title" db:"title"`
+	Author         string    `json:"author" db:"author"`
+	Publisher      string    `json:"publisher" db:"publisher"`
+	URL            string    `json:"url" db:"url"`
+	ImageURL       string    `json:"image_url" db:"image_url"`
+	Description    string    `json:"description" db:"description"`
+	Keywords       string    `json:"keywords" db:"keywords"` // Comma separated
+	PublishedAt    time.Time `json:"published_at" db:"published_at"`
+	Sentiment      string    `json:"sentiment" db:"sentiment"`             // 'positive', 'negative', 'neutral'
+	SentimentScore float64   `json:"sentiment_score" db:"sentiment_score"` // -1 (negative) to 1 (positive)
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// NewsFilter represents filter parameters for news queries
+type NewsFilter struct {
+	Symbol    string    `json:"symbol"`
+	Sentiment string    `json:"sentiment"`
+	Since     time.Time `json:"since"`
+	Limit     int       `json:"limit"`
+}
+
+// NewsSentimentSummary counts recent headlines by sentiment for a symbol
+type NewsSentimentSummary struct {
+	Symbol       string  `json:"symbol"`
+	Positive     int     `json:"positive"`
+	Negative     int     `json:"negative"`
+	Neutral      int     `json:"neutral"`
+	AverageScore float64 `json:"average_score"`
+}
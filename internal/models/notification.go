@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Notification categories
+const (
+	NotificationPattern = "pattern" // pattern detections and completed thesis components
+	NotificationSetup   = "setup"   // high quality trading setups
+	NotificationAlert   = "alert"   // alert rule triggers
+	NotificationAnomaly = "anomaly" // volume spikes, price gaps and potential halts
+	NotificationSystem  = "system"  // collection failures, rejected config reloads
+)
+
+// NotificationCategories lists the notification categories
+var NotificationCategories = []string{NotificationPattern, NotificationSetup, NotificationAlert, NotificationAnomaly, NotificationSystem}
+
+// Notification is an entry in the in-app notification center
+type Notification struct {
+	ID        int64      `json:"id" db:"id"`
+	Category  string     `json:"category" db:"category"` // one of NotificationCategories
+	Severity  string     `json:"severity" db:"severity"` // 'high', 'medium', 'low'
+	Symbol    string     `json:"symbol,omitempty" db:"symbol"`
+	Title     string     `json:"title" db:"title"`
+	Message   string     `json:"message" db:"message"`
+	Link      string     `json:"link,omitempty" db:"link"` // dashboard page or API path with details
+	IsRead    bool       `json:"is_read" db:"is_read"`
+	ReadAt    *time.Time `json:"read_at,omitempty" db:"read_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// NotificationFilter represents filter parameters for notification queries
+type NotificationFilter struct {
+	Category   string `json:"category"`
+	UnreadOnly bool   `json:"unread_only"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+}
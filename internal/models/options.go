@@ -0,0 +1,80 @@
+package models
+
+import "time"
+
+// Option contract types
+const (
+	OptionTypeCall = "call"
+	OptionTypePut  = "put"
+)
+
+// OptionContract represents a single contract from an options chain snapshot
+type OptionContract struct {
+	Ticker            string    `json:"ticker"`
+	ContractType      string    `json:"contract_type"` // 'call' or 'put'
+	StrikePrice       float64   `json:"strike_price"`
+	ExpirationDate    time.Time `json:"expiration_date"`
+	Volume            int64     `json:"volume"`
+	OpenInterest      int64     `json:"open_interest"`
+	ImpliedVolatility float64   `json:"implied_volatility"`
+	LastPrice         float64   `json:"last_price"`
+	Delta             float64   `json:"delta"`
+}
+
+// VolumeOIRatio returns the day's volume relative to open interest
+func (oc *OptionContract) VolumeOIRatio() float64 {
+	if oc.OpenInterest <= 0 {
+		return float64(oc.Volume)
+	}
+	return float64(oc.Volume) / float64(oc.OpenInterest)
+}
+
+// OptionsSnapshot represents aggregated options activity for a symbol at a point in time
+type OptionsSnapshot struct {
+	ID                   int64     `json:"id" db:"id"`
+	Symbol               string    `json:"symbol" db:"symbol"`
+	Timestamp            time.Time `json:"timestamp" db:"timestamp"`
+	UnderlyingPrice      float64   `json:"underlying_price" db:"underlying_price"`
+	CallVolume           int64     `json:"call_volume" db:"call_volume"`
+	PutVolume            int64     `json:"put_volume" db:"put_volume"`
+	CallOpenInterest     int64     `json:"call_open_interest" db:"call_open_interest"`
+	PutOpenInterest      int64     `json:"put_open_interest" db:"put_open_interest"`
+	PutCallVolumeRatio   float64   `json:"put_call_volume_ratio" db:"put_call_volume_ratio"`
+	PutCallOIRatio       float64   `json:"put_call_oi_ratio" db:"put_call_oi_ratio"`
+	AvgImpliedVolatility float64   `json:"avg_implied_volatility" db:"avg_implied_volatility"` // Open interest weighted
+	ATMImpliedVolatility float64   `json:"atm_implied_volatility" db:"atm_implied_volatility"` // Nearest expiration, nearest strike
+	ContractCount        int       `json:"contract_count" db:"contract_count"`
+	UnusualCount         int       `json:"unusual_count" db:"unusual_count"`
+
+	UnusualContracts []*UnusualOptionActivity `json:"unusual_contracts,omitempty"`
+}
+
+// UnusualOptionActivity represents a contract trading well above its open interest
+type UnusualOptionActivity struct {
+	ID                int64     `json:"id" db:"id"`
+	SnapshotID        int64     `json:"snapshot_id" db:"snapshot_id"`
+	Symbol            string    `json:"symbol" db:"symbol"`
+	ContractTicker    string    `json:"contract_ticker" db:"contract_ticker"`
+	ContractType      string    `json:"contract_type" db:"contract_type"`
+	StrikePrice       float64   `json:"strike_price" db:"strike_price"`
+	ExpirationDate    time.Time `json:"expiration_date" db:"expiration_date"`
+	Volume            int64     `json:"volume" db:"volume"`
+	OpenInterest      int64     `json:"open_interest" db:"open_interest"`
+	VolumeOIRatio     float64   `json:"volume_oi_ratio" db:"volume_oi_ratio"`
+	ImpliedVolatility float64   `json:"implied_volatility" db:"implied_volatility"`
+	Timestamp         time.Time `json:"timestamp" db:"timestamp"`
+}
+
+// OptionsSummary summarizes recent options activity for a symbol
+type OptionsSummary struct {
+	Symbol                string                   `json:"symbol"`
+	Latest                *OptionsSnapshot         `json:"latest"`
+	Days                  int                      `json:"days"`
+	SnapshotCount         int                      `json:"snapshot_count"`
+	AvgPutCallVolumeRatio float64                  `json:"avg_put_call_volume_ratio"`
+	IVRank                float64                  `json:"iv_rank"` // 0-100 position of latest IV within the period's range
+	IVLow                 float64                  `json:"iv_low"`
+	IVHigh                float64                  `json:"iv_high"`
+	RecentUnusual         []*UnusualOptionActivity `json:"recent_unusual"`
+	History               []*OptionsSnapshot       `json:"history"`
+}
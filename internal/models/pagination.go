@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+// Page size limits for list endpoints
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 500
+)
+
+// Sort orders
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// Sortable fields per list endpoint; the names match the underlying columns
+var (
+	PatternSortFields = []string{"detected_at", "last_updated", "symbol"}
+	SetupSortFields   = []string{"quality_score", "detected_at", "expires_at", "symbol", "risk_reward_ratio"}
+	SRLevelSortFields = []string{"strength", "touches", "level", "last_touch"}
+)
+
+// PageRequest is a parsed page/limit/sort request for a list endpoint
+type PageRequest struct {
+	Page  int    `json:"page"` // 1-based
+	Limit int    `json:"limit"`
+	Sort  string `json:"sort,omitempty"`
+	Order string `json:"order,omitempty"` // 'asc' or 'desc'
+}
+
+// Offset returns the number of rows to skip for the requested page
+func (pr PageRequest) Offset() int {
+	if pr.Page <= 1 {
+		return 0
+	}
+	return (pr.Page - 1) * pr.Limit
+}
+
+// Pagination describes the page returned by a list endpoint
+type Pagination struct {
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	Total      int    `json:"total"`
+	TotalPages int    `json:"total_pages"`
+	HasMore    bool   `json:"has_more"`
+	Sort       string `json:"sort,omitempty"`
+	Order      string `json:"order,omitempty"`
+}
+
+// NewPagination builds the pagination block for a page of a result set with total rows
+func NewPagination(req PageRequest, total int) *Pagination {
+	p := &Pagination{
+		Page:  req.Page,
+		Limit: req.Limit,
+		Total: total,
+		Sort:  req.Sort,
+		Order: req.Order,
+	}
+	if req.Limit > 0 {
+		p.TotalPages = (total + req.Limit - 1) / req.Limit
+	}
+	p.HasMore = req.Offset()+req.Limit < total
+	return p
+}
+
+// PagedResponse is the envelope returned by paginated list endpoints
+type PagedResponse struct {
+	Items      interface{} `json:"items"`
+	Pagination *Pagination `json:"pagination"`
+}
+
+// PatternListItem is one entry of the unified pattern list, wrapping a pattern of any family
+type PatternListItem struct {
+	PatternFamily string         `json:"pattern_family"` // 'head_shoulders', 'falling_wedge' (falling and rising wedges), 'triangle', 'flag'
+	Direction     string         `json:"direction"`      // 'bullish' or 'bearish'
+	ID            int64          `json:"id"`
+	Symbol        string         `json:"symbol"`
+	DetectedAt    time.Time      `json:"detected_at"`
+	LastUpdated   time.Time      `json:"last_updated"`
+	Pattern       interface{}    `json:"pattern"`
+	Links         []*PatternLink `json:"links,omitempty"` // overlapping patterns of other families
+}
@@ -0,0 +1,183 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// Paper trade statuses and exit reasons
+const (
+	PaperTradePending   = "pending" // waiting for price to touch the entry
+	PaperTradeOpen      = "open"
+	PaperTradeClosed    = "closed"
+	PaperTradeExpired   = "expired" // setup expired before the entry was touched
+	PaperTradeCancelled = "cancelled"
+
+	PaperExitStop   = "stop"
+	PaperExitTarget = "target"
+	PaperExitManual = "manual"
+)
+
+// PaperTrade represents a simulated trade taken on a detected setup
+type PaperTrade struct {
+	ID            int64      `json:"id" db:"id"`
+	Symbol        string     `json:"symbol" db:"symbol"`
+	SetupType     string     `json:"setup_type" db:"setup_type"`
+	Side          string     `json:"side" db:"side"` // 'long' or 'short'
+	QualityScore  float64    `json:"quality_score" db:"quality_score"`
+	EntryPrice    float64    `json:"entry_price" db:"entry_price"`
+	StopLoss      float64    `json:"stop_loss" db:"stop_loss"`
+	TargetPrice   float64    `json:"target_price" db:"target_price"`
+	Quantity      float64    `json:"quantity" db:"quantity"`
+	Status        string     `json:"status" db:"status"`
+	ExitPrice     *float64   `json:"exit_price,omitempty" db:"exit_price"`
+	ExitReason    string     `json:"exit_reason,omitempty" db:"exit_reason"`
+	RealizedPnL   float64    `json:"realized_pnl" db:"realized_pnl"`
+	ExpiresAt     time.Time  `json:"expires_at" db:"expires_at"`
+	FilledAt      *time.Time `json:"filled_at,omitempty" db:"filled_at"`
+	ClosedAt      *time.Time `json:"closed_at,omitempty" db:"closed_at"`
+	LastCheckedAt time.Time  `json:"last_checked_at" db:"last_checked_at"` // timestamp of the last bar simulated
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Populated for open trades
+	CurrentPrice  float64 `json:"current_price,omitempty"`
+	UnrealizedPnL float64 `json:"unrealized_pnl,omitempty"`
+}
+
+// PaperTradeFilter represents filter parameters for paper trade queries
+type PaperTradeFilter struct {
+	Symbol string `json:"symbol"`
+	Status string `json:"status"`
+	Limit  int    `json:"limit"`
+}
+
+// PaperEquityPoint is a point on the simulated equity curve
+type PaperEquityPoint struct {
+	Timestamp     time.Time `json:"timestamp" db:"timestamp"`
+	Equity        float64   `json:"equity" db:"equity"`
+	Cash          float64   `json:"cash" db:"cash"`
+	UnrealizedPnL float64   `json:"unrealized_pnl" db:"unrealized_pnl"`
+	OpenTrades    int       `json:"open_trades" db:"open_trades"`
+}
+
+// PaperTradingStatus summarizes the simulated account
+type PaperTradingStatus struct {
+	Enabled         bool       `json:"enabled"`
+	MinQualityScore float64    `json:"min_quality_score"`
+	StartingCapital float64    `json:"starting_capital"`
+	Equity          float64    `json:"equity"`
+	Cash            float64    `json:"cash"`
+	RealizedPnL     float64    `json:"realized_pnl"`
+	UnrealizedPnL   float64    `json:"unrealized_pnl"`
+	ReturnPercent   float64    `json:"return_percent"`
+	PendingTrades   int        `json:"pending_trades"`
+	OpenTrades      int        `json:"open_trades"`
+	ClosedTrades    int        `json:"closed_trades"`
+	Wins            int        `json:"wins"`
+	Losses          int        `json:"losses"`
+	WinRate         float64    `json:"win_rate"`
+	LastRun         *time.Time `json:"last_run,omitempty"`
+}
+
+// PaperTradingCycleResult reports what a single paper trading cycle did
+type PaperTradingCycleResult struct {
+	RunAt   time.Time     `json:"run_at"`
+	Taken   []*PaperTrade `json:"taken"`
+	Filled  int           `json:"filled"`
+	Closed  int           `json:"closed"`
+	Expired int           `json:"expired"`
+	Errors  []string      `json:"errors,omitempty"`
+}
+
+// IsLong returns true for long trades
+func (pt *PaperTrade) IsLong() bool {
+	return pt.Side != PositionSideShort
+}
+
+// IsActive returns true while the trade is waiting for a fill or open
+func (pt *PaperTrade) IsActive() bool {
+	return pt.Status == PaperTradePending || pt.Status == PaperTradeOpen
+}
+
+// PnLAt returns the profit or loss if the trade were closed at price
+func (pt *PaperTrade) PnLAt(price float64) float64 {
+	pnl := (price - pt.EntryPrice) * pt.Quantity
+	if !pt.IsLong() {
+		return -pnl
+	}
+	return pnl
+}
+
+// Simulate walks a bar through the trade lifecycle: a pending trade fills when the bar
+// touches the entry, and an open trade exits at the stop or target. When a bar spans both
+// the stop and the target the stop is assumed to have been hit first. Returns true if the
+// trade changed state.
+func (pt *PaperTrade) Simulate(bar *PriceData) bool {
+	filledThisBar := false
+
+	if pt.Status == PaperTradePending {
+		if bar.Timestamp.After(pt.ExpiresAt) {
+			pt.Status = PaperTradeExpired
+			return true
+		}
+		if bar.Low > pt.EntryPrice || bar.High < pt.EntryPrice {
+			return false
+		}
+
+		filledAt := bar.Timestamp
+		pt.Status = PaperTradeOpen
+		pt.FilledAt = &filledAt
+		filledThisBar = true
+	}
+
+	if pt.Status != PaperTradeOpen {
+		return false
+	}
+
+	if exit, hit := pt.stopExit(bar); hit {
+		// The open precedes the fill on the fill bar, so a gap through the stop cannot apply
+		if filledThisBar {
+			exit = pt.StopLoss
+		}
+		pt.Close(exit, PaperExitStop, bar.Timestamp)
+		return true
+	}
+
+	if pt.TargetPrice > 0 {
+		if (pt.IsLong() && bar.High >= pt.TargetPrice) || (!pt.IsLong() && bar.Low <= pt.TargetPrice) {
+			pt.Close(pt.TargetPrice, PaperExitTarget, bar.Timestamp)
+			return true
+		}
+	}
+
+	return filledThisBar
+}
+
+// stopExit returns the exit price if the bar hits the stop, filling at the open on a gap through it
+func (pt *PaperTrade) stopExit(bar *PriceData) (float64, bool) {
+	if pt.StopLoss <= 0 {
+		return 0, false
+	}
+
+	if pt.IsLong() {
+		if bar.Low > pt.StopLoss {
+			return 0, false
+		}
+		return math.Min(pt.StopLoss, bar.Open), true
+	}
+
+	if bar.High < pt.StopLoss {
+		return 0, false
+	}
+	return math.Max(pt.StopLoss, bar.Open), true
+}
+
+// Close closes the trade at the given price and records its realized P&L
+func (pt *PaperTrade) Close(exitPrice float64, reason string, at time.Time) {
+	pt.Status = PaperTradeClosed
+	pt.ExitPrice = &exitPrice
+	pt.ExitReason = reason
+	pt.ClosedAt = &at
+	pt.RealizedPnL = pt.PnLAt(exitPrice)
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Key points of a head and shoulders pattern that can be corrected by hand
+const (
+	KeyPointLeftShoulder  = "left_shoulder"
+	KeyPointHead          = "head"
+	KeyPointRightShoulder = "right_shoulder"
+	KeyPointLeftReaction  = "left_reaction"  // neckline touch between the left shoulder and the head
+	KeyPointRightReaction = "right_reaction" // neckline touch between the head and the right shoulder
+	KeyPointNeckline      = "neckline"
+	KeyPointAnnotation    = "annotation"
+)
+
+// PatternFamilyHeadShoulders names the head_shoulders_patterns table, regular and inverse, in the edit audit trail
+const PatternFamilyHeadShoulders = "head_shoulders"
+
+// HeadShouldersEditRequest corrects the key points of a detected head and shoulders pattern. Points are given by
+// the timestamp of their bar and take that bar's low or high; omitted points keep their detected values.
+type HeadShouldersEditRequest struct {
+	LeftShoulder  *time.Time `json:"left_shoulder,omitempty"`
+	Head          *time.Time `json:"head,omitempty"`
+	RightShoulder *time.Time `json:"right_shoulder,omitempty"`
+	LeftReaction  *time.Time `json:"left_reaction,omitempty"`
+	RightReaction *time.Time `json:"right_reaction,omitempty"`
+	// Timeframe is the bar size the points are read from, the configured bar interval when empty
+	Timeframe Timeframe `json:"timeframe,omitempty"`
+	// NecklineLevel pins the neckline instead of averaging the two reaction points
+	NecklineLevel *float64 `json:"neckline_level,omitempty"`
+	Annotation    *string  `json:"annotation,omitempty"`
+	Note          string   `json:"note"`   // why the pattern was edited, kept in the audit trail
+	Editor        string   `json:"editor"` // who edited it
+}
+
+// PatternEdit is an entry in a pattern's audit trail of manual edits
+type PatternEdit struct {
+	ID          int64                      `json:"id" db:"id"`
+	PatternType string                     `json:"pattern_type" db:"pattern_type"`
+	PatternID   int64                      `json:"pattern_id" db:"pattern_id"`
+	Changes     map[string]PatternEditDiff `json:"changes" db:"changes"` // by key point
+	Note        string                     `json:"note,omitempty" db:"note"`
+	Editor      string                     `json:"editor,omitempty" db:"editor"`
+	EditedAt    time.Time                  `json:"edited_at" db:"edited_at"`
+}
+
+// PatternEditDiff is a key point's value before and after an edit
+type PatternEditDiff struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
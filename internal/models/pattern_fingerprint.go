@@ -0,0 +1,77 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// patternFingerprint identifies a formation by its symbol, type and key points, so the same formation found
+// again by a later scan maps to the same value. Times are compared to the second and prices to the cent.
+func patternFingerprint(symbol, patternType string, points ...PatternPoint) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%s", symbol, patternType)
+	for _, p := range points {
+		fmt.Fprintf(h, "|%d:%.2f", p.Timestamp.Unix(), p.Price)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// Fingerprint identifies the pattern by its shoulders and head: the lows of an inverse pattern, the highs of a top
+func (hsp *HeadShouldersPattern) Fingerprint() string {
+	if hsp.IsBearish() {
+		return patternFingerprint(hsp.Symbol, hsp.PatternType, hsp.LeftShoulderHigh, hsp.HeadHigh, hsp.RightShoulderHigh)
+	}
+	return patternFingerprint(hsp.Symbol, hsp.PatternType, hsp.LeftShoulderLow, hsp.HeadLow, hsp.RightShoulderLow)
+}
+
+// Refresh copies the measurements of a later detection of the same formation, keeping the tracked thesis
+func (hsp *HeadShouldersPattern) Refresh(detected *HeadShouldersPattern) {
+	hsp.RightShoulderHigh = detected.RightShoulderHigh
+	hsp.RightShoulderLow = detected.RightShoulderLow
+	hsp.NecklineLevel = detected.NecklineLevel
+	hsp.NecklineSlope = detected.NecklineSlope
+	hsp.NecklineTouch1 = detected.NecklineTouch1
+	hsp.NecklineTouch2 = detected.NecklineTouch2
+	hsp.PatternWidth = detected.PatternWidth
+	hsp.PatternHeight = detected.PatternHeight
+	hsp.Symmetry = detected.Symmetry
+}
+
+// Fingerprint identifies the wedge by the points of its two trend lines
+func (fwp *FallingWedgePattern) Fingerprint() string {
+	return patternFingerprint(fwp.Symbol, fwp.PatternType, fwp.UpperTrendLine1, fwp.UpperTrendLine2, fwp.LowerTrendLine1, fwp.LowerTrendLine2)
+}
+
+// Refresh copies the measurements of a later detection of the same formation, keeping the tracked thesis
+func (fwp *FallingWedgePattern) Refresh(detected *FallingWedgePattern) {
+	fwp.UpperSlope = detected.UpperSlope
+	fwp.LowerSlope = detected.LowerSlope
+	fwp.BreakoutLevel = detected.BreakoutLevel
+	fwp.PatternWidth = detected.PatternWidth
+	fwp.PatternHeight = detected.PatternHeight
+	fwp.Convergence = detected.Convergence
+	fwp.VolumeProfile = detected.VolumeProfile
+}
+
+// Fingerprint identifies the triangle by the points of its two trend lines
+func (tp *TrianglePattern) Fingerprint() string {
+	return patternFingerprint(tp.Symbol, tp.PatternType, tp.UpperTrendLine1, tp.UpperTrendLine2, tp.LowerTrendLine1, tp.LowerTrendLine2)
+}
+
+// Refresh copies the measurements of a later detection of the same formation, keeping the tracked thesis
+func (tp *TrianglePattern) Refresh(detected *TrianglePattern) {
+	tp.BreakoutLevel = detected.BreakoutLevel
+	tp.VolumeProfile = detected.VolumeProfile
+}
+
+// Fingerprint identifies the flag by its pole; the flag itself keeps extending until it breaks out
+func (fp *FlagPattern) Fingerprint() string {
+	return patternFingerprint(fp.Symbol, fp.PatternType, fp.PoleStart, fp.PoleEnd)
+}
+
+// Refresh copies the measurements of a later detection of the same formation, keeping the tracked thesis
+func (fp *FlagPattern) Refresh(detected *FlagPattern) {
+	fp.BreakoutLevel = detected.BreakoutLevel
+	fp.VolumeProfile = detected.VolumeProfile
+}
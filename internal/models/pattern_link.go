@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// PatternLink records two active patterns of different families on a symbol that share pivots, and which of
+// their trading setups was kept when both had one
+type PatternLink struct {
+	ID                int64     `json:"id" db:"id"`
+	Symbol            string    `json:"symbol" db:"symbol"`
+	Direction         string    `json:"direction" db:"direction"` // 'bullish' or 'bearish'
+	Family            string    `json:"family" db:"family"`
+	PatternID         int64     `json:"pattern_id" db:"pattern_id"`
+	LinkedFamily      string    `json:"linked_family" db:"linked_family"`
+	LinkedPatternID   int64     `json:"linked_pattern_id" db:"linked_pattern_id"`
+	SharedPivots      int       `json:"shared_pivots" db:"shared_pivots"`
+	KeptSetupID       int64     `json:"kept_setup_id,omitempty" db:"kept_setup_id"`
+	SupersededSetupID int64     `json:"superseded_setup_id,omitempty" db:"superseded_setup_id"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+}
+
+// Links reports whether the link involves the pattern
+func (pl *PatternLink) Links(family string, id int64) bool {
+	return (pl.Family == family && pl.PatternID == id) || (pl.LinkedFamily == family && pl.LinkedPatternID == id)
+}
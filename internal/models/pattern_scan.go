@@ -0,0 +1,60 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Bounds of per-request pattern scan parameters
+const (
+	MaxPatternLookbackDays = 730
+	MinPatternSensitivity  = 0.25
+	MaxPatternSensitivity  = 4.0
+)
+
+// PatternScanParams overrides a detector's configured bar interval, lookback and sensitivity for one scan.
+// Zero values fall back to the detector's configuration.
+type PatternScanParams struct {
+	Timeframe    Timeframe `json:"timeframe,omitempty"`
+	LookbackDays int       `json:"lookback_days,omitempty"`
+	Sensitivity  float64   `json:"sensitivity,omitempty"` // >1 finds smaller swings and shallower patterns, <1 only pronounced ones
+}
+
+// ParsePatternScanParams parses scan parameters from query strings, leaving empty values unset
+func ParsePatternScanParams(timeframe, lookbackDays, sensitivity string) (*PatternScanParams, error) {
+	params := &PatternScanParams{}
+
+	if timeframe != "" {
+		tf, err := ParseTimeframe(timeframe)
+		if err != nil {
+			return nil, err
+		}
+		params.Timeframe = tf
+	}
+
+	if lookbackDays != "" {
+		days, err := strconv.Atoi(lookbackDays)
+		if err != nil || days < 1 || days > MaxPatternLookbackDays {
+			return nil, fmt.Errorf("invalid lookback_days %q: must be between 1 and %d", lookbackDays, MaxPatternLookbackDays)
+		}
+		params.LookbackDays = days
+	}
+
+	if sensitivity != "" {
+		value, err := strconv.ParseFloat(sensitivity, 64)
+		if err != nil || value < MinPatternSensitivity || value > MaxPatternSensitivity {
+			return nil, fmt.Errorf("invalid sensitivity %q: must be between %g and %g", sensitivity, MinPatternSensitivity, MaxPatternSensitivity)
+		}
+		params.Sensitivity = value
+	}
+
+	return params, nil
+}
+
+// TimeframeOrDefault returns the requested timeframe, or the collection timeframe when unset
+func (p *PatternScanParams) TimeframeOrDefault() Timeframe {
+	if p == nil || p.Timeframe == "" {
+		return DefaultTimeframe
+	}
+	return p.Timeframe
+}
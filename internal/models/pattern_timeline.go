@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Pattern timeline event types, in the order events at the same time are listed
+const (
+	TimelineEventDetected  = "detected"
+	TimelineEventComponent = "component_completed"
+	TimelineEventPhase     = "phase_change"
+	TimelineEventAlert     = "alert"
+)
+
+// PatternTimeline is the chronological progress of a pattern's thesis, for rendering as a timeline
+type PatternTimeline struct {
+	Family            string                  `json:"pattern_family"`
+	PatternID         int64                   `json:"pattern_id"`
+	Symbol            string                  `json:"symbol"`
+	PatternType       string                  `json:"pattern_type"`
+	CurrentPhase      string                  `json:"current_phase"`
+	CompletionPercent float64                 `json:"completion_percent"`
+	Events            []*PatternTimelineEvent `json:"events"`
+	Pending           []string                `json:"pending"` // Components not completed yet, in thesis order
+}
+
+// PatternTimelineEvent is one step of a pattern timeline: its detection, a completed thesis component, a move
+// to another phase or an alert sent about it
+type PatternTimelineEvent struct {
+	Timestamp     time.Time `json:"timestamp"`
+	EventType     string    `json:"event_type"`
+	Component     string    `json:"component,omitempty"`
+	Phase         string    `json:"phase,omitempty"`
+	PreviousPhase string    `json:"previous_phase,omitempty"`
+	Message       string    `json:"message"`
+	Evidence      []string  `json:"evidence,omitempty"`
+}
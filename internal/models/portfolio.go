@@ -0,0 +1,168 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Position sides and statuses
+const (
+	PositionSideLong  = "long"
+	PositionSideShort = "short"
+
+	PositionStatusOpen   = "open"
+	PositionStatusClosed = "closed"
+)
+
+// Position represents a trade taken in the portfolio, optionally on a detected setup
+type Position struct {
+	ID          int64      `json:"id" db:"id"`
+	Symbol      string     `json:"symbol" db:"symbol"`
+	Side        string     `json:"side" db:"side"` // 'long' or 'short'
+	Quantity    float64    `json:"quantity" db:"quantity"`
+	EntryPrice  float64    `json:"entry_price" db:"entry_price"`
+	EntryTime   time.Time  `json:"entry_time" db:"entry_time"`
+	StopLoss    float64    `json:"stop_loss" db:"stop_loss"`
+	TargetPrice float64    `json:"target_price" db:"target_price"`
+	ExitPrice   *float64   `json:"exit_price,omitempty" db:"exit_price"`
+	ExitTime    *time.Time `json:"exit_time,omitempty" db:"exit_time"`
+	Status      string     `json:"status" db:"status"` // 'open' or 'closed'
+	Notes       string     `json:"notes" db:"notes"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Attribution to the setup that generated the trade
+	SetupID   *int64 `json:"setup_id,omitempty" db:"setup_id"`
+	SetupType string `json:"setup_type" db:"setup_type"` // e.g. 'support_bounce', 'inverse_head_shoulders', 'manual'
+
+	// Live valuation, populated for open positions
+	CurrentPrice         float64 `json:"current_price,omitempty"`
+	MarketValue          float64 `json:"market_value,omitempty"`
+	UnrealizedPnL        float64 `json:"unrealized_pnl,omitempty"`
+	UnrealizedPnLPercent float64 `json:"unrealized_pnl_percent,omitempty"`
+
+	// Populated for closed positions
+	RealizedPnL        float64 `json:"realized_pnl,omitempty"`
+	RealizedPnLPercent float64 `json:"realized_pnl_percent,omitempty"`
+}
+
+// PositionFilter represents filter parameters for position queries
+type PositionFilter struct {
+	Symbol    string `json:"symbol"`
+	Status    string `json:"status"`
+	SetupType string `json:"setup_type"`
+	Limit     int    `json:"limit"`
+}
+
+// PortfolioSummary aggregates open exposure and trading results
+type PortfolioSummary struct {
+	OpenPositions        int       `json:"open_positions"`
+	ClosedPositions      int       `json:"closed_positions"`
+	CostBasis            float64   `json:"cost_basis"`
+	MarketValue          float64   `json:"market_value"`
+	UnrealizedPnL        float64   `json:"unrealized_pnl"`
+	UnrealizedPnLPercent float64   `json:"unrealized_pnl_percent"`
+	RealizedPnL          float64   `json:"realized_pnl"`
+	TotalPnL             float64   `json:"total_pnl"`
+	WinRate              float64   `json:"win_rate"` // Percent of closed positions with positive P&L
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// SetupPerformance attributes closed trade results to the setup type that generated them
+type SetupPerformance struct {
+	SetupType        string  `json:"setup_type"`
+	Trades           int     `json:"trades"`
+	Wins             int     `json:"wins"`
+	Losses           int     `json:"losses"`
+	WinRate          float64 `json:"win_rate"`
+	RealizedPnL      float64 `json:"realized_pnl"`
+	AvgReturnPercent float64 `json:"avg_return_percent"`
+	BestReturn       float64 `json:"best_return_percent"`
+	WorstReturn      float64 `json:"worst_return_percent"`
+	OpenPositions    int     `json:"open_positions"`
+	UnrealizedPnL    float64 `json:"unrealized_pnl"`
+}
+
+// Validate checks that a position is well formed and normalizes its symbol and side
+func (p *Position) Validate() error {
+	p.Symbol = strings.ToUpper(strings.TrimSpace(p.Symbol))
+	if p.Symbol == "" {
+		return fmt.Errorf("symbol is required")
+	}
+
+	p.Side = strings.ToLower(strings.TrimSpace(p.Side))
+	if p.Side == "" {
+		p.Side = PositionSideLong
+	}
+	if p.Side != PositionSideLong && p.Side != PositionSideShort {
+		return fmt.Errorf("invalid side %q: must be long or short", p.Side)
+	}
+
+	if p.Quantity <= 0 {
+		return fmt.Errorf("quantity must be positive")
+	}
+
+	if p.EntryPrice <= 0 {
+		return fmt.Errorf("entry_price must be positive")
+	}
+
+	return nil
+}
+
+// IsOpen returns true if the position has not been closed
+func (p *Position) IsOpen() bool {
+	return p.Status == PositionStatusOpen
+}
+
+// CostBasis returns the capital committed at entry
+func (p *Position) CostBasis() float64 {
+	return p.EntryPrice * p.Quantity
+}
+
+// PnLAt returns the profit or loss if the position were closed at price
+func (p *Position) PnLAt(price float64) float64 {
+	pnl := (price - p.EntryPrice) * p.Quantity
+	if p.Side == PositionSideShort {
+		return -pnl
+	}
+	return pnl
+}
+
+// ReturnPercentAt returns the percent return on cost basis if closed at price
+func (p *Position) ReturnPercentAt(price float64) float64 {
+	if p.CostBasis() == 0 {
+		return 0
+	}
+	return p.PnLAt(price) / p.CostBasis() * 100
+}
+
+// ApplyPrice populates the live valuation of an open position or the realized result of a closed one
+func (p *Position) ApplyPrice(currentPrice float64) {
+	if !p.IsOpen() {
+		if p.ExitPrice != nil {
+			p.RealizedPnL = p.PnLAt(*p.ExitPrice)
+			p.RealizedPnLPercent = p.ReturnPercentAt(*p.ExitPrice)
+		}
+		return
+	}
+
+	if currentPrice <= 0 {
+		return
+	}
+
+	p.CurrentPrice = currentPrice
+	p.MarketValue = currentPrice * p.Quantity
+	p.UnrealizedPnL = p.PnLAt(currentPrice)
+	p.UnrealizedPnLPercent = p.ReturnPercentAt(currentPrice)
+}
+
+// SideForDirection maps a setup direction to a position side
+func SideForDirection(direction string) string {
+	switch direction {
+	case "bearish", PositionSideShort:
+		return PositionSideShort
+	default:
+		return PositionSideLong
+	}
+}
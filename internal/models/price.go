@@ -14,17 +14,21 @@ type PriceData struct {
 	Low       float64   `json:"low" db:"low_price"`
 	Close     float64   `json:"close" db:"close_price"`
 	Volume    int64     `json:"volume" db:"volume"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	// Session is the market session the bar was traded in; empty for bars stored before sessions were
+	// recorded and for daily and compacted candles
+	Session   MarketSession `json:"session,omitempty" db:"session"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
 }
 
 // PriceDataFilter represents filter parameters for querying price data
 type PriceDataFilter struct {
-	Symbol   string    `json:"symbol"`
-	From     time.Time `json:"from"`
-	To       time.Time `json:"to"`
-	Interval string    `json:"interval"`
-	Limit    int       `json:"limit"`
-	Offset   int       `json:"offset"`
+	Symbol    string    `json:"symbol"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Interval  string    `json:"interval"`
+	Timeframe Timeframe `json:"timeframe"`
+	Limit     int       `json:"limit"`
+	Offset    int       `json:"offset"`
 }
 
 // PriceDataResponse represents the API response for price data
@@ -47,6 +51,28 @@ type TradingViewCandle struct {
 	Volume int64   `json:"volume"` // Volume
 }
 
+// TradingViewLinePoint represents a line chart data point for TradingView
+type TradingViewLinePoint struct {
+	Time  int64   `json:"time"`  // Unix timestamp
+	Value float64 `json:"value"` // Close price
+}
+
+// Chart decimation methods
+const (
+	DecimationNone      = "none"      // bars as collected
+	DecimationAggregate = "aggregate" // 1-minute bars rolled up into larger candles
+	DecimationLTTB      = "lttb"      // line points downsampled with Largest-Triangle-Three-Buckets
+)
+
+// ChartResolution describes how chart data was reduced to fit the requested number of points
+type ChartResolution struct {
+	Timeframe    Timeframe `json:"timeframe"`     // candle size of the returned points
+	Method       string    `json:"method"`        // one of the Decimation* methods
+	SourcePoints int       `json:"source_points"` // points before downsampling
+	Points       int       `json:"points"`
+	MaxPoints    int       `json:"max_points"`
+}
+
 // PriceStats represents price statistics for a symbol
 type PriceStats struct {
 	Symbol             string    `json:"symbol"`
@@ -84,3 +110,23 @@ func (pa *PolygonAggregate) ToPriceData(symbol string) *PriceData {
 		CreatedAt: time.Now(),
 	}
 }
+
+// InsertStats reports the throughput of batch inserts into a table since the server started
+type InsertStats struct {
+	Batches         int64     `json:"batches"`
+	Rows            int64     `json:"rows"`
+	TotalMillis     float64   `json:"total_ms"`
+	RowsPerSecond   float64   `json:"rows_per_second"`
+	LastBatchRows   int       `json:"last_batch_rows"`
+	LastBatchMillis float64   `json:"last_batch_ms"`
+	LastBatchAt     time.Time `json:"last_batch_at"`
+}
+
+// CompactionResult reports a run that rolled old 1-minute bars up into hourly and daily candles
+type CompactionResult struct {
+	Cutoff        time.Time `json:"cutoff"`
+	Symbols       int       `json:"symbols"`
+	BarsCompacted int64     `json:"bars_compacted"`
+	HourlyCandles int       `json:"hourly_candles"`
+	DailyCandles  int       `json:"daily_candles"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// SymbolReference holds company reference data used to filter and group the watchlist
+type SymbolReference struct {
+	Symbol            string    `json:"symbol" db:"symbol"`
+	Name              string    `json:"name" db:"name"`
+	Sector            string    `json:"sector" db:"sector"`
+	Industry          string    `json:"industry" db:"industry"`
+	Exchange          string    `json:"exchange" db:"exchange"`
+	MarketCap         float64   `json:"market_cap" db:"market_cap"`
+	SharesOutstanding int64     `json:"shares_outstanding" db:"shares_outstanding"`
+	FloatShares       int64     `json:"float_shares" db:"float_shares"` // 0 when the data plan has no float data
+	AvgVolume         int64     `json:"avg_volume" db:"avg_volume"`     // average daily volume over recent sessions
+	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// StockReferenceFilter narrows watchlist stocks by their reference data
+type StockReferenceFilter struct {
+	Sector       string  `json:"sector,omitempty"`
+	Industry     string  `json:"industry,omitempty"`
+	MinMarketCap float64 `json:"min_market_cap,omitempty"`
+	MaxMarketCap float64 `json:"max_market_cap,omitempty"`
+	MinAvgVolume int64   `json:"min_avg_volume,omitempty"`
+	MaxFloat     int64   `json:"max_float,omitempty"`
+}
+
+// IsEmpty reports whether the filter has no criteria
+func (f *StockReferenceFilter) IsEmpty() bool {
+	return *f == StockReferenceFilter{}
+}
+
+// SymbolMatch is a ticker from the provider's reference data, found by symbol search or a lookup
+type SymbolMatch struct {
+	Symbol     string     `json:"symbol"`
+	Name       string     `json:"name"`
+	AssetClass AssetClass `json:"asset_class"`
+	Type       string     `json:"type,omitempty"` // CS for common stock, ETF, ...
+	Exchange   string     `json:"exchange,omitempty"`
+	Active     bool       `json:"active"`
+}
+
+// SymbolSearchResponse lists the tickers matching a search, best matches first
+type SymbolSearchResponse struct {
+	Query   string        `json:"query"`
+	Results []SymbolMatch `json:"results"`
+	Count   int           `json:"count"`
+}
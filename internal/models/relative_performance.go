@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// MaxCompareSymbols is the most symbols one relative performance chart compares
+const MaxCompareSymbols = 10
+
+// RelativePerformance is the percent change of several symbols since a common start, aligned on shared
+// timestamps so the series chart together
+type RelativePerformance struct {
+	Symbols   []string                     `json:"symbols"`
+	Range     string                       `json:"range"`
+	Timeframe Timeframe                    `json:"timeframe"`
+	From      time.Time                    `json:"from"`
+	To        time.Time                    `json:"to"`
+	Times     []int64                      `json:"times"` // Unix seconds of each point, shared by every series
+	Series    []*RelativePerformanceSeries `json:"series"`
+	Missing   []string                     `json:"missing,omitempty"` // symbols without bars in the range
+}
+
+// RelativePerformanceSeries is one symbol's percent change from the first shared timestamp, one value per time
+type RelativePerformanceSeries struct {
+	Symbol    string    `json:"symbol"`
+	BaseClose float64   `json:"base_close"` // close at the first shared timestamp
+	Values    []float64 `json:"values"`
+	Change    float64   `json:"change"` // percent change at the last timestamp
+}
@@ -0,0 +1,24 @@
+package models
+
+// PositionSizing is the recommended position for a setup under the risk settings
+type PositionSizing struct {
+	Shares        int     `json:"shares"`
+	RiskPerShare  float64 `json:"risk_per_share"` // Distance from entry to stop
+	DollarRisk    float64 `json:"dollar_risk"`    // Loss if the stop is hit, shares times risk per share
+	PositionValue float64 `json:"position_value"`
+	Blocked       bool    `json:"blocked,omitempty"` // New entries are sized at zero while portfolio heat is over the limit
+	Warning       string  `json:"warning,omitempty"`
+}
+
+// PortfolioHeat is the open risk across active and triggered setups, each sized under the risk settings
+type PortfolioHeat struct {
+	AccountSize     float64 `json:"account_size"`
+	RiskPercent     float64 `json:"risk_percent"` // Percent of the account risked per setup
+	OpenRisk        float64 `json:"open_risk"`
+	HeatPercent     float64 `json:"heat_percent"` // Open risk as a percent of the account
+	MaxHeatPercent  float64 `json:"max_heat_percent"`
+	OverLimit       bool    `json:"over_limit"`
+	BlockOverHeat   bool    `json:"block_over_heat"`
+	ActiveSetups    int     `json:"active_setups"`
+	TriggeredSetups int     `json:"triggered_setups"`
+}
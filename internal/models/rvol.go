@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// MaxRVOLLookbackDays is the most prior trading days an RVOL curve may average over
+const MaxRVOLLookbackDays = 60
+
+// RVOLPoint compares today's cumulative volume at one bar with the average of prior days at the same time of day
+type RVOLPoint struct {
+	Time                time.Time `json:"time"`
+	Minute              int       `json:"minute"` // Minutes since midnight in the exchange time zone
+	CumulativeVolume    int64     `json:"cumulative_volume"`
+	AvgCumulativeVolume float64   `json:"avg_cumulative_volume"` // Average of prior days' cumulative volume by the same minute
+	RVOL                float64   `json:"rvol"`
+}
+
+// RVOLCurve is a symbol's intraday relative volume for one trading day
+type RVOLCurve struct {
+	Symbol       string       `json:"symbol"`
+	Date         string       `json:"date"` // Trading date in the exchange time zone, YYYY-MM-DD
+	LookbackDays int          `json:"lookback_days"`
+	DaysUsed     int          `json:"days_used"` // Prior trading days that had bars
+	RVOL         float64      `json:"rvol"`      // Latest point's relative volume, 0 when unknown
+	Points       []*RVOLPoint `json:"points"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// ScanRun summarizes one run of a task over many symbols, such as a pattern scan or S/R detection
+type ScanRun struct {
+	Task        string            `json:"task"`
+	Symbols     int               `json:"symbols"`
+	Succeeded   int               `json:"succeeded"`
+	Failed      int               `json:"failed"`
+	Errors      map[string]string `json:"errors,omitempty"` // failure per symbol
+	Workers     int               `json:"workers"`
+	StartedAt   time.Time         `json:"started_at"`
+	TotalMillis float64           `json:"total_ms"`
+}
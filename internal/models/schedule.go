@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Background schedules and job types that can be disabled at runtime
+const (
+	ScheduleCollection         = "collection"
+	ScheduleCleanup            = "cleanup"
+	SchedulePurge              = "purge"
+	SchedulePatternScan        = JobTypePatternScan
+	ScheduleBackfill           = JobTypeBackfill
+	ScheduleIndicatorRecompute = JobTypeIndicatorRecompute
+	ScheduleNews               = "news"
+	ScheduleShortInterest      = "short_interest"
+	ScheduleInsiders           = "insiders"
+	ScheduleCalendar           = "calendar"
+	ScheduleReferenceData      = "reference_data"
+	ScheduleSymbolStats        = "symbol_stats"
+	ScheduleGapScan            = "gap_scan"
+	ScheduleDigest             = "digest"
+	ScheduleAutomations        = "automations"
+	ScheduleSRRecalculation    = "sr_recalculation"
+)
+
+// Schedules describes each schedule, in listing order
+var Schedules = []struct {
+	Name        string
+	Description string
+}{
+	{ScheduleCollection, "Scheduled and forced bar collection from the market data provider"},
+	{ScheduleCleanup, "Daily compaction and retention cleanup of old bars"},
+	{SchedulePurge, "Daily purge of setups and patterns archived past the retention window"},
+	{SchedulePatternScan, "Periodic pattern scans, pattern monitoring and queued pattern scan jobs"},
+	{ScheduleBackfill, "Queued history backfill jobs"},
+	{ScheduleIndicatorRecompute, "Queued indicator recompute jobs"},
+	{ScheduleNews, "News ingestion"},
+	{ScheduleShortInterest, "Short volume and short interest refresh"},
+	{ScheduleInsiders, "SEC Form 4 insider transaction ingestion"},
+	{ScheduleCalendar, "Earnings calendar refresh"},
+	{ScheduleReferenceData, "Watchlist reference data enrichment"},
+	{ScheduleSymbolStats, "Daily symbol stats computation"},
+	{ScheduleGapScan, "Pre-market gap scan and gapper tagging"},
+	{ScheduleDigest, "Scheduled digest emails"},
+	{ScheduleAutomations, "Strategy automations"},
+	{ScheduleSRRecalculation, "S/R level recalculation during market hours"},
+}
+
+// IsSchedule reports whether name is a known schedule
+func IsSchedule(name string) bool {
+	for _, schedule := range Schedules {
+		if schedule.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ScheduleStatus reports whether a schedule runs and how many runs it skipped while disabled
+type ScheduleStatus struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Enabled     bool       `json:"enabled"`
+	DisabledAt  *time.Time `json:"disabled_at,omitempty"`
+	SkippedRuns int        `json:"skipped_runs"` // since the schedule was last disabled
+}
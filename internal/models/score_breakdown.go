@@ -0,0 +1,122 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Score breakdown categories besides the checklist categories
+const (
+	ScoreCategoryAdjustment = "adjustment"
+	ScoreCategoryThesis     = "thesis"
+)
+
+// ScoreBreakdown explains a 0-100 score: the points each item contributed or missed, and adjustments applied
+// on top of the items
+type ScoreBreakdown struct {
+	Score       float64               `json:"score"`
+	MaxScore    float64               `json:"max_score"` // Most points the items can add up to
+	Items       []*ScoreBreakdownItem `json:"items"`
+	Adjustments []*ScoreBreakdownItem `json:"adjustments,omitempty"`
+}
+
+// ScoreBreakdownItem is one scored item: the points it contributed out of its maximum, and for items that
+// failed the measured value against the threshold it had to meet
+type ScoreBreakdownItem struct {
+	Key       string  `json:"key,omitempty"`
+	Category  string  `json:"category"`
+	Name      string  `json:"name"`
+	Passed    bool    `json:"passed"`
+	Points    float64 `json:"points"`
+	MaxPoints float64 `json:"max_points"`
+	Value     string  `json:"value,omitempty"`
+	Threshold string  `json:"threshold,omitempty"`
+	Reason    string  `json:"reason,omitempty"` // Why a failed item scored nothing
+}
+
+// AddAdjustment records points added to, or with negative points taken from, the score outside the items
+func (sb *ScoreBreakdown) AddAdjustment(key, name string, points, maxPoints float64, reason string) {
+	sb.Adjustments = append(sb.Adjustments, &ScoreBreakdownItem{
+		Key:       key,
+		Category:  ScoreCategoryAdjustment,
+		Name:      name,
+		Passed:    points > 0,
+		Points:    math.Round(points*100) / 100,
+		MaxPoints: math.Round(maxPoints*100) / 100,
+		Reason:    reason,
+	})
+}
+
+// ChecklistBreakdownItem explains a checklist item worth points when completed and maxPoints at most
+func ChecklistBreakdownItem(category, key string, item *ChecklistItem, points, maxPoints float64) *ScoreBreakdownItem {
+	entry := &ScoreBreakdownItem{
+		Key:       key,
+		Category:  category,
+		Name:      item.Name,
+		Passed:    item.IsCompleted,
+		MaxPoints: math.Round(maxPoints*100) / 100,
+		Value:     item.Value,
+		Threshold: item.Threshold,
+	}
+	if item.IsCompleted {
+		entry.Points = math.Round(points*100) / 100
+		return entry
+	}
+
+	switch {
+	case item.Value != "" && item.Threshold != "":
+		entry.Reason = fmt.Sprintf("%s does not meet %s", item.Value, item.Threshold)
+	case item.Value != "":
+		entry.Reason = item.Value
+	case item.LastChecked.IsZero():
+		entry.Reason = "not checked automatically"
+	default:
+		entry.Reason = "not met"
+	}
+	return entry
+}
+
+// thesisScoreBreakdown explains a thesis completion score: each component is worth its share of the weights
+// when the completion is weighted, or of the component count. Evidence is reported as the component's value.
+func thesisScoreBreakdown(components []*ThesisComponent, completionPercent float64, weighted bool) *ScoreBreakdown {
+	totalWeight := 0.0
+	if weighted {
+		for _, component := range components {
+			totalWeight += component.Weight
+		}
+	}
+
+	breakdown := &ScoreBreakdown{
+		Score: math.Round(completionPercent*100) / 100,
+		Items: make([]*ScoreBreakdownItem, 0, len(components)),
+	}
+	for _, component := range components {
+		share := 0.0
+		switch {
+		case totalWeight > 0:
+			share = component.Weight / totalWeight * 100
+		case len(components) > 0:
+			share = 100 / float64(len(components))
+		}
+
+		item := &ScoreBreakdownItem{
+			Category:  ScoreCategoryThesis,
+			Name:      component.Name,
+			Passed:    component.IsCompleted,
+			MaxPoints: math.Round(share*100) / 100,
+			Value:     strings.Join(component.Evidence, "; "),
+		}
+		if component.IsCompleted {
+			item.Points = item.MaxPoints
+		} else if item.Value == "" {
+			item.Reason = "not detected yet"
+		} else {
+			item.Reason = "not confirmed"
+		}
+		breakdown.MaxScore += share
+		breakdown.Items = append(breakdown.Items, item)
+	}
+	breakdown.MaxScore = math.Round(breakdown.MaxScore*100) / 100
+	return breakdown
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Screener universes
+const (
+	ScreenUniverseMarket    = "market"    // Every US stock in Polygon's grouped daily bars
+	ScreenUniverseWatchlist = "watchlist" // Watched symbols only
+)
+
+// ScreenCriteria defines the filters a screen applies to each symbol in its universe.
+// Zero values disable a criterion.
+type ScreenCriteria struct {
+	Universe         string   `json:"universe"`           // 'market' (default) or 'watchlist'
+	Symbols          []string `json:"symbols,omitempty"`  // Restrict the universe to these symbols
+	MinPrice         float64  `json:"min_price"`          // Universe filter
+	MaxPrice         float64  `json:"max_price"`          // Universe filter
+	MinAvgVolume     int64    `json:"min_avg_volume"`     // Universe filter on 20-day average daily volume
+	RSIBelow         float64  `json:"rsi_below"`          // Match when daily RSI is below this (oversold)
+	RSIAbove         float64  `json:"rsi_above"`          // Match when daily RSI is above this (overbought)
+	MinVolumeRatio   float64  `json:"min_volume_ratio"`   // Match when today's volume is at least this multiple of average
+	NearLevelPercent float64  `json:"near_level_percent"` // Match when price is within this percent of a strong S/R level
+	MinLevelTouches  int      `json:"min_level_touches"`  // Touches required for a level to count as strong (default 2)
+	MatchAny         bool     `json:"match_any"`          // Match when any criterion passes instead of all
+	Limit            int      `json:"limit"`              // Maximum results (default 100)
+}
+
+// Validate checks that the criteria enable at least one condition and are consistent
+func (sc *ScreenCriteria) Validate() error {
+	if sc.Universe != "" && sc.Universe != ScreenUniverseMarket && sc.Universe != ScreenUniverseWatchlist {
+		return fmt.Errorf("universe must be '%s' or '%s'", ScreenUniverseMarket, ScreenUniverseWatchlist)
+	}
+	if sc.RSIBelow == 0 && sc.RSIAbove == 0 && sc.MinVolumeRatio == 0 && sc.NearLevelPercent == 0 {
+		return fmt.Errorf("at least one of rsi_below, rsi_above, min_volume_ratio or near_level_percent is required")
+	}
+	if sc.RSIBelow < 0 || sc.RSIBelow > 100 || sc.RSIAbove < 0 || sc.RSIAbove > 100 {
+		return fmt.Errorf("RSI thresholds must be between 0 and 100")
+	}
+	if sc.MaxPrice > 0 && sc.MinPrice > sc.MaxPrice {
+		return fmt.Errorf("min_price must not exceed max_price")
+	}
+	if sc.MinVolumeRatio < 0 || sc.NearLevelPercent < 0 {
+		return fmt.Errorf("min_volume_ratio and near_level_percent must not be negative")
+	}
+	return nil
+}
+
+// SavedScreen represents a named screen definition
+type SavedScreen struct {
+	ID          int64          `json:"id" db:"id"`
+	Name        string         `json:"name" db:"name"`
+	Description string         `json:"description" db:"description"`
+	Criteria    ScreenCriteria `json:"criteria" db:"criteria"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// ScreenerResult represents a symbol that passed a screen
+type ScreenerResult struct {
+	Symbol               string   `json:"symbol"`
+	Price                float64  `json:"price"`
+	ChangePercent        float64  `json:"change_percent"`
+	Volume               int64    `json:"volume"`
+	AvgVolume            int64    `json:"avg_volume"`
+	VolumeRatio          float64  `json:"volume_ratio"`
+	RSI                  float64  `json:"rsi"`
+	NearestLevel         float64  `json:"nearest_level,omitempty"`
+	LevelType            string   `json:"level_type,omitempty"` // 'support' or 'resistance'
+	LevelTouches         int      `json:"level_touches,omitempty"`
+	LevelDistancePercent float64  `json:"level_distance_percent,omitempty"`
+	Matches              []string `json:"matches"`
+	InWatchlist          bool     `json:"in_watchlist"`
+}
+
+// ScreenerRun represents the outcome of running a screen
+type ScreenerRun struct {
+	ScreenID *int64            `json:"screen_id,omitempty"`
+	Criteria ScreenCriteria    `json:"criteria"`
+	RunAt    time.Time         `json:"run_at"`
+	AsOf     time.Time         `json:"as_of"` // Date of the latest daily bar used
+	Scanned  int               `json:"scanned"`
+	Results  []*ScreenerResult `json:"results"`
+	Count    int               `json:"count"`
+}
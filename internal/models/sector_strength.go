@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// MaxRelativeStrengthWindow is the longest relative strength window in trading days
+const MaxRelativeStrengthWindow = 250
+
+// SymbolStrength is a stock's performance against the benchmark over each window
+type SymbolStrength struct {
+	Symbol           string          `json:"symbol"`
+	Sector           string          `json:"sector"`
+	Returns          map[int]float64 `json:"returns"`           // percent change over each window
+	RelativeStrength map[int]float64 `json:"relative_strength"` // return minus the benchmark return, in percentage points
+	Score            float64         `json:"score"`             // average relative strength across windows
+}
+
+// SectorStrength is the average relative strength of a sector's watchlist stocks
+type SectorStrength struct {
+	Rank             int               `json:"rank"`
+	Sector           string            `json:"sector"`
+	RelativeStrength map[int]float64   `json:"relative_strength"`
+	Score            float64           `json:"score"`
+	Stocks           []*SymbolStrength `json:"stocks"` // strongest first
+}
+
+// SectorStrengthReport ranks watchlist sectors by relative strength against a benchmark
+type SectorStrengthReport struct {
+	Benchmark        string            `json:"benchmark"`
+	Windows          []int             `json:"windows"`
+	BenchmarkReturns map[int]float64   `json:"benchmark_returns"`
+	Sectors          []*SectorStrength `json:"sectors"`           // strongest first
+	Skipped          []string          `json:"skipped,omitempty"` // symbols without enough daily history
+	GeneratedAt      time.Time         `json:"generated_at"`
+}
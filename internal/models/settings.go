@@ -0,0 +1,173 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Runtime settings sections
+const (
+	SettingsSetups   = "setups"   // setup scoring weights, quality thresholds and expiration
+	SettingsPatterns = "patterns" // chart pattern detection thresholds
+	SettingsSR       = "sr"       // support/resistance detection
+	SettingsDisplay  = "display"  // time zone of chart and summary responses and the dashboard
+)
+
+// SettingsSections lists the sections that can be read and updated through the settings API
+var SettingsSections = []string{SettingsSetups, SettingsPatterns, SettingsSR, SettingsDisplay}
+
+// IsSettingsSection reports whether section is a known settings section
+func IsSettingsSection(section string) bool {
+	for _, s := range SettingsSections {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
+
+// PatternSettings holds the detection settings of every chart pattern detector.
+// Durations are in nanoseconds, as with any time.Duration in JSON.
+type PatternSettings struct {
+	HeadShoulders *HeadShouldersConfig `json:"head_shoulders"`
+	FallingWedge  *FallingWedgeConfig  `json:"falling_wedge"`
+	Triangle      *TriangleConfig      `json:"triangle"`
+	Flag          *FlagConfig          `json:"flag"`
+}
+
+// ValidatePatternScan checks a detector's default bar interval, lookback and sensitivity
+func ValidatePatternScan(barInterval Timeframe, lookbackDays int, sensitivity float64) error {
+	if _, err := ParseTimeframe(string(barInterval)); err != nil {
+		return fmt.Errorf("bar_interval: %w", err)
+	}
+	if lookbackDays < 1 || lookbackDays > MaxPatternLookbackDays {
+		return fmt.Errorf("lookback_days must be between 1 and %d", MaxPatternLookbackDays)
+	}
+	if sensitivity < MinPatternSensitivity || sensitivity > MaxPatternSensitivity {
+		return fmt.Errorf("sensitivity must be between %g and %g", MinPatternSensitivity, MaxPatternSensitivity)
+	}
+	return nil
+}
+
+// Validate checks that the scoring settings are usable
+func (c *SetupScoringConfig) Validate() error {
+	if c.LowQualityThreshold < 0 || c.LowQualityThreshold > c.MediumQualityThreshold ||
+		c.MediumQualityThreshold > c.HighQualityThreshold || c.HighQualityThreshold > 100 {
+		return fmt.Errorf("quality thresholds must satisfy 0 <= low <= medium <= high <= 100")
+	}
+
+	weights := []float64{c.PriceActionWeight, c.VolumeWeight, c.TechnicalWeight, c.RiskRewardWeight}
+	total := 0.0
+	for _, weight := range weights {
+		if weight < 0 {
+			return fmt.Errorf("scoring weights must not be negative")
+		}
+		total += weight
+	}
+	// The quality score is a weighted average on a 0-100 scale
+	if math.Abs(total-100) > 0.01 {
+		return fmt.Errorf("price_action, volume, technical and risk_reward weights must add up to 100, got %g", total)
+	}
+
+	if c.ZoneStrengthWeight < 0 || c.PivotConfluenceWeight < 0 || c.EarningsPenalty < 0 || c.RegimePenalty < 0 || c.MinBouncePercent < 0 || c.MinRiskRewardRatio < 0 ||
+		c.MaxRiskPercent < 0 || c.ATRStopMultiplier < 0 || c.TrailATRMultiplier < 0 || c.MinADXTrend < 0 || c.ORBVolumeMultiplier < 0 {
+		return fmt.Errorf("weights, penalties and ratios must not be negative")
+	}
+	if c.MinTimeAtLevelMinutes < 0 || c.MaxLevelAgeDays < 0 || c.EarningsBufferDays < 0 {
+		return fmt.Errorf("minutes and day counts must not be negative")
+	}
+	if err := c.Confidence.Validate(); err != nil {
+		return err
+	}
+	if c.SetupExpirationHours < 1 {
+		return fmt.Errorf("setup_expiration_hours must be at least 1")
+	}
+	if c.ORBRangeMinutes < 1 || c.ORBRangeMinutes > MaxORBRangeMinutes {
+		return fmt.Errorf("orb_range_minutes must be between 1 and %d", MaxORBRangeMinutes)
+	}
+	return nil
+}
+
+// Validate checks that the S/R detection settings are usable
+func (c *SRDetectionConfig) Validate() error {
+	if c.MinTouches < 1 || c.PivotStrength < 1 {
+		return fmt.Errorf("min_touches and pivot_strength must be at least 1")
+	}
+	if c.LookbackDays < 1 || c.LookbackDays > MaxPatternLookbackDays {
+		return fmt.Errorf("lookback_days must be between 1 and %d", MaxPatternLookbackDays)
+	}
+	if c.MaxLevelAge < 0 || c.MinLevelDistancePercent < 0 || c.LevelPenetrationTolerance < 0 || c.VolumeConfirmationRatio < 0 ||
+		c.MinBouncePercent < 0 || c.ZoneWidthPercent < 0 || c.ConfluenceTolerancePercent < 0 || c.FibMinSwingPercent < 0 {
+		return fmt.Errorf("ages, percents and ratios must not be negative")
+	}
+	return nil
+}
+
+// DisplaySettings holds how times are shown in chart and summary responses and on the dashboard
+type DisplaySettings struct {
+	Timezone string `json:"timezone"` // IANA time zone, e.g. America/New_York
+}
+
+// Validate checks that the time zone is known
+func (s *DisplaySettings) Validate() error {
+	if _, err := time.LoadLocation(s.Timezone); err != nil || s.Timezone == "" {
+		return fmt.Errorf("unknown time zone %q: use an IANA name such as UTC or America/New_York", s.Timezone)
+	}
+	return nil
+}
+
+// Validate checks every detector's settings; all of them must be present
+func (s *PatternSettings) Validate() error {
+	if s.HeadShoulders == nil || s.FallingWedge == nil || s.Triangle == nil || s.Flag == nil {
+		return fmt.Errorf("head_shoulders, falling_wedge, triangle and flag settings are required")
+	}
+
+	hs := s.HeadShoulders
+	if err := validateDurations("head_shoulders", hs.MinPatternDuration, hs.MaxPatternDuration); err != nil {
+		return err
+	}
+	if err := ValidatePatternScan(hs.BarInterval, hs.LookbackDays, hs.Sensitivity); err != nil {
+		return fmt.Errorf("head_shoulders.%w", err)
+	}
+	if hs.SwingWindow < 1 || hs.MinHeadDepth < 0 || hs.MaxShoulderAsymmetry < 0 || hs.MinSymmetryScore < 0 || hs.MinSymmetryScore > 100 {
+		return fmt.Errorf("head_shoulders needs a positive swing_window, non-negative depth and asymmetry, and a min_symmetry_score of 0-100")
+	}
+
+	fw := s.FallingWedge
+	if err := validateDurations("falling_wedge", fw.MinPatternDuration, fw.MaxPatternDuration); err != nil {
+		return err
+	}
+	if err := ValidatePatternScan(fw.BarInterval, fw.LookbackDays, fw.Sensitivity); err != nil {
+		return fmt.Errorf("falling_wedge.%w", err)
+	}
+	if fw.SwingWindow < 1 || fw.MinConvergence < 0 || fw.MinConvergence > fw.MaxConvergence || fw.MinWedgeHeight < 0 {
+		return fmt.Errorf("falling_wedge needs a positive swing_window, 0 <= min_convergence <= max_convergence and a non-negative min_wedge_height")
+	}
+
+	tr := s.Triangle
+	if err := validateDurations("triangle", tr.MinPatternDuration, tr.MaxPatternDuration); err != nil {
+		return err
+	}
+	if tr.MinTouchPoints < 1 || tr.FlatTolerance < 0 || tr.MinSlope < 0 || tr.MinPatternHeight < 0 {
+		return fmt.Errorf("triangle needs at least 1 touch point and non-negative tolerances")
+	}
+
+	fl := s.Flag
+	if err := validateDurations("flag", fl.MinFlagDuration, fl.MaxFlagDuration); err != nil {
+		return err
+	}
+	if fl.MaxPoleDuration <= 0 || fl.MinPoleChange < 0 || fl.MaxRetracement < 0 || fl.MaxRetracement > 100 {
+		return fmt.Errorf("flag needs a positive max_pole_duration, a non-negative min_pole_change and a max_retracement of 0-100")
+	}
+
+	return nil
+}
+
+// validateDurations checks that a detector's duration range is positive and ordered
+func validateDurations(name string, minDuration, maxDuration time.Duration) error {
+	if minDuration <= 0 || maxDuration < minDuration {
+		return fmt.Errorf("%s durations must satisfy 0 < min <= max", name)
+	}
+	return nil
+}
@@ -4,18 +4,34 @@ import (
 	"time"
 )
 
+// MaxORBRangeMinutes is the longest opening range, in minutes after the open
+const MaxORBRangeMinutes = 120
+
+// Opening range breakout setup types
+const (
+	SetupTypeOpeningRangeBreakout  = "opening_range_breakout"  // closed above the opening range on rising volume
+	SetupTypeOpeningRangeBreakdown = "opening_range_breakdown" // closed below the opening range on rising volume
+)
+
 // TradingSetup represents a detected trading setup
 type TradingSetup struct {
-	ID           int64     `json:"id" db:"id"`
-	Symbol       string    `json:"symbol" db:"symbol"`
-	SetupType    string    `json:"setup_type" db:"setup_type"`       // 'support_bounce', 'resistance_bounce', 'breakout', etc.
-	Direction    string    `json:"direction" db:"direction"`         // 'bullish', 'bearish'
-	QualityScore float64   `json:"quality_score" db:"quality_score"` // 0-100 overall score
-	Confidence   string    `json:"confidence" db:"confidence"`       // 'high', 'medium', 'low'
-	Status       string    `json:"status" db:"status"`               // 'active', 'triggered', 'expired', 'invalidated'
-	DetectedAt   time.Time `json:"detected_at" db:"detected_at"`
-	ExpiresAt    time.Time `json:"expires_at" db:"expires_at"`
-	LastUpdated  time.Time `json:"last_updated" db:"last_updated"`
+	ID           int64   `json:"id" db:"id"`
+	Symbol       string  `json:"symbol" db:"symbol"`
+	SetupType    string  `json:"setup_type" db:"setup_type"`       // 'support_bounce', 'resistance_bounce', 'breakout', etc.
+	Direction    string  `json:"direction" db:"direction"`         // 'bullish', 'bearish'
+	QualityScore float64 `json:"quality_score" db:"quality_score"` // 0-100 overall score
+	Confidence   string  `json:"confidence" db:"confidence"`       // 'high', 'medium', 'low'
+
+	// Confidence score (0-100) the confidence level was derived from, and what each factor contributed to it
+	ConfidenceScore   float64             `json:"confidence_score" db:"confidence_score"`
+	ConfidenceFactors []*ConfidenceFactor `json:"confidence_factors,omitempty" db:"confidence_factors"`
+
+	// Points each checklist item and adjustment contributed to the quality score
+	ScoreBreakdown *ScoreBreakdown `json:"score_breakdown,omitempty" db:"score_breakdown"`
+	Status         string          `json:"status" db:"status"` // one of the SetupStatus values
+	DetectedAt     time.Time       `json:"detected_at" db:"detected_at"`
+	ExpiresAt      time.Time       `json:"expires_at" db:"expires_at"`
+	LastUpdated    time.Time       `json:"last_updated" db:"last_updated"`
 
 	// Price levels
 	CurrentPrice float64 `json:"current_price" db:"current_price"`
@@ -34,6 +50,8 @@ type TradingSetup struct {
 	SupportLevel    *SupportResistanceLevel `json:"support_level,omitempty"`
 	ResistanceLevel *SupportResistanceLevel `json:"resistance_level,omitempty"`
 	KeyLevel        *SupportResistanceLevel `json:"key_level,omitempty"`
+	KeyLevelID      int64                   `json:"key_level_id,omitempty" db:"key_level_id"` // Stored key level; detection refreshes the active setup of a type at it
+	KeyZone         *SRZone                 `json:"key_zone,omitempty"`
 
 	// Scoring breakdown
 	PriceActionScore float64 `json:"price_action_score" db:"price_action_score"`
@@ -44,11 +62,33 @@ type TradingSetup struct {
 	// Checklist items
 	Checklist *SetupChecklist `json:"checklist,omitempty"`
 
+	// Setup alerts triggered so far
+	AlertCount int `json:"alert_count"`
+
+	// Calendar events inside the setup window (earnings, economic releases)
+	UpcomingEvents []*CalendarEvent `json:"upcoming_events,omitempty"`
+	EventPenalty   float64          `json:"event_penalty,omitempty"`
+
+	// Market regime at detection and the points deducted for trading against it
+	MarketRegime  string  `json:"market_regime,omitempty"`
+	RegimePenalty float64 `json:"regime_penalty,omitempty"`
+
+	// Recommended position under the risk settings, filled in for API responses
+	Sizing *PositionSizing `json:"sizing,omitempty"`
+
+	// Stop adjustments suggested since entry, oldest first, and the latest suggested stop
+	StopHistory   []*SetupStopAdjustment `json:"stop_history,omitempty"`
+	SuggestedStop float64                `json:"suggested_stop,omitempty"`
+
 	// Metadata
 	Notes     string    `json:"notes" db:"notes"`
 	IsManual  bool      `json:"is_manual" db:"is_manual"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	// Archived records are kept for analytics but left out of lists unless asked for
+	Archived   bool       `json:"archived" db:"archived"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty" db:"archived_at"`
 }
 
 // SetupChecklist represents the criteria checklist for a setup
@@ -83,6 +123,9 @@ type SetupChecklist struct {
 	EntryPrecision  ChecklistItem `json:"entry_precision"`   // 5 points
 	ExitStrategy    ChecklistItem `json:"exit_strategy"`     // 5 points
 
+	// Market context, reported without points
+	ShortInterest ChecklistItem `json:"short_interest"` // Elevated short volume or short interest
+
 	// Calculated scores
 	TotalScore        float64 `json:"total_score"`
 	CompletedItems    int     `json:"completed_items"`
@@ -104,6 +147,8 @@ type ChecklistItem struct {
 	ManualOverride bool      `json:"manual_override"`
 	Notes          string    `json:"notes"`
 	LastChecked    time.Time `json:"last_checked"`
+	Value          string    `json:"value,omitempty"`     // Value measured when the item was last checked
+	Threshold      string    `json:"threshold,omitempty"` // What the value had to meet
 }
 
 // SetupScoringConfig holds configuration for setup scoring
@@ -114,10 +159,12 @@ type SetupScoringConfig struct {
 	LowQualityThreshold    float64 `json:"low_quality_threshold" yaml:"low_quality_threshold"`
 
 	// Scoring weights
-	PriceActionWeight float64 `json:"price_action_weight" yaml:"price_action_weight"`
-	VolumeWeight      float64 `json:"volume_weight" yaml:"volume_weight"`
-	TechnicalWeight   float64 `json:"technical_weight" yaml:"technical_weight"`
-	RiskRewardWeight  float64 `json:"risk_reward_weight" yaml:"risk_reward_weight"`
+	PriceActionWeight     float64 `json:"price_action_weight" yaml:"price_action_weight"`
+	VolumeWeight          float64 `json:"volume_weight" yaml:"volume_weight"`
+	TechnicalWeight       float64 `json:"technical_weight" yaml:"technical_weight"`
+	RiskRewardWeight      float64 `json:"risk_reward_weight" yaml:"risk_reward_weight"`
+	ZoneStrengthWeight    float64 `json:"zone_strength_weight" yaml:"zone_strength_weight"`       // Bonus points for a setup at a full-strength S/R zone
+	PivotConfluenceWeight float64 `json:"pivot_confluence_weight" yaml:"pivot_confluence_weight"` // Bonus points for an entry at a daily or weekly floor pivot, 0 to leave pivots out
 
 	// Bounce criteria
 	MinBouncePercent      float64 `json:"min_bounce_percent" yaml:"min_bounce_percent"`
@@ -127,14 +174,66 @@ type SetupScoringConfig struct {
 	// Risk management
 	MinRiskRewardRatio float64 `json:"min_risk_reward_ratio" yaml:"min_risk_reward_ratio"`
 	MaxRiskPercent     float64 `json:"max_risk_percent" yaml:"max_risk_percent"`
+	ATRStopMultiplier  float64 `json:"atr_stop_multiplier" yaml:"atr_stop_multiplier"`
+	TrailATRMultiplier float64 `json:"trail_atr_multiplier" yaml:"trail_atr_multiplier"` // ATRs the suggested trailing stop follows behind the best price after the first target, 0 to only suggest breakeven
+
+	// Trend filter
+	MinADXTrend float64 `json:"min_adx_trend" yaml:"min_adx_trend"`
+
+	// Event awareness
+	EarningsPenalty    float64 `json:"earnings_penalty" yaml:"earnings_penalty"`         // Points deducted when earnings fall inside the setup window
+	EarningsBufferDays int     `json:"earnings_buffer_days" yaml:"earnings_buffer_days"` // Days past expiration still treated as the holding window
+
+	// Market regime filter
+	RegimePenalty      float64 `json:"regime_penalty" yaml:"regime_penalty"`             // Points deducted from setups trading against the market regime, 0 to ignore the regime
+	BounceRegimeFilter bool    `json:"bounce_regime_filter" yaml:"bounce_regime_filter"` // Leave out support bounces in a trending down market and resistance bounces in a trending up one
 
 	// Setup expiration
 	SetupExpirationHours int `json:"setup_expiration_hours" yaml:"setup_expiration_hours"`
+
+	// Opening range breakouts
+	ORBRangeMinutes     int     `json:"orb_range_minutes" yaml:"orb_range_minutes"`         // Minutes after the open spanned by the opening range, e.g. 15 or 30
+	ORBVolumeMultiplier float64 `json:"orb_volume_multiplier" yaml:"orb_volume_multiplier"` // Breakout bar volume over the opening range's average bar volume
+
+	// Confidence model
+	Confidence ConfidenceModel `json:"confidence" yaml:"confidence"`
+}
+
+// DefaultSetupScoringConfig returns the default setup scoring settings
+func DefaultSetupScoringConfig() *SetupScoringConfig {
+	return &SetupScoringConfig{
+		HighQualityThreshold:   80.0,
+		MediumQualityThreshold: 60.0,
+		LowQualityThreshold:    40.0,
+		PriceActionWeight:      25.0,
+		VolumeWeight:           25.0,
+		TechnicalWeight:        25.0,
+		RiskRewardWeight:       25.0,
+		MinBouncePercent:       2.0,
+		MinTimeAtLevelMinutes:  30,
+		MaxLevelAgeDays:        60,
+		MinRiskRewardRatio:     1.5,
+		MaxRiskPercent:         2.0,
+		ATRStopMultiplier:      0.5,
+		TrailATRMultiplier:     2.0,
+		MinADXTrend:            25.0,
+		ZoneStrengthWeight:     10.0,
+		SetupExpirationHours:   24,
+		EarningsPenalty:        15.0,
+		EarningsBufferDays:     2,
+		RegimePenalty:          10.0,
+		BounceRegimeFilter:     true,
+		ORBRangeMinutes:        15,
+		ORBVolumeMultiplier:    1.5,
+		Confidence:             DefaultConfidenceModel(),
+	}
 }
 
 // SetupFilter represents filter parameters for setup queries
 type SetupFilter struct {
+	ID              int64       `json:"id"`
 	Symbol          string      `json:"symbol"`
+	Symbols         []string    `json:"symbols"`
 	SetupType       string      `json:"setup_type"`
 	Direction       string      `json:"direction"`
 	Status          string      `json:"status"`
@@ -143,8 +242,11 @@ type SetupFilter struct {
 	Confidence      string      `json:"confidence"`
 	TimeRange       SRTimeRange `json:"time_range"`
 	IsActive        *bool       `json:"is_active"`
+	IncludeArchived bool        `json:"include_archived"`
 	Limit           int         `json:"limit"`
 	Offset          int         `json:"offset"`
+	Sort            string      `json:"sort"`  // one of SetupSortFields
+	Order           string      `json:"order"` // 'asc' or 'desc'
 }
 
 // SetupDetectionResult represents the result of setup detection
@@ -154,32 +256,57 @@ type SetupDetectionResult struct {
 	SetupsFound   []*TradingSetup `json:"setups_found"`
 	ActiveSetups  []*TradingSetup `json:"active_setups"`
 	ExpiredSetups []*TradingSetup `json:"expired_setups"`
+	Refreshed     int             `json:"refreshed"`            // Found setups that refreshed the active setup of their type at their key level
+	Regime        string          `json:"regime,omitempty"`     // classified market regime setups were gated by
+	Suppressed    []string        `json:"suppressed,omitempty"` // setup types left out in the market regime
 	Summary       *SetupSummary   `json:"summary"`
 	Errors        []string        `json:"errors,omitempty"`
 }
 
 // SetupSummary provides summary statistics about setups
 type SetupSummary struct {
-	TotalSetups        int           `json:"total_setups"`
-	ActiveCount        int           `json:"active_count"`
-	HighQualityCount   int           `json:"high_quality_count"`
-	MediumQualityCount int           `json:"medium_quality_count"`
-	LowQualityCount    int           `json:"low_quality_count"`
-	BullishCount       int           `json:"bullish_count"`
-	BearishCount       int           `json:"bearish_count"`
-	AvgQualityScore    float64       `json:"avg_quality_score"`
-	AvgRiskReward      float64       `json:"avg_risk_reward"`
-	BestSetup          *TradingSetup `json:"best_setup"`
-	LastDetection      time.Time     `json:"last_detection"`
+	TotalSetups        int            `json:"total_setups"`
+	ActiveCount        int            `json:"active_count"`
+	HighQualityCount   int            `json:"high_quality_count"`
+	MediumQualityCount int            `json:"medium_quality_count"`
+	LowQualityCount    int            `json:"low_quality_count"`
+	BullishCount       int            `json:"bullish_count"`
+	BearishCount       int            `json:"bearish_count"`
+	AvgQualityScore    float64        `json:"avg_quality_score"`
+	AvgRiskReward      float64        `json:"avg_risk_reward"`
+	ByType             map[string]int `json:"by_type"`
+	BestSetup          *TradingSetup  `json:"best_setup"`
+	LastDetection      time.Time      `json:"last_detection"`
 }
 
 // SetupResponse represents API response for setup queries
 type SetupResponse struct {
-	Symbol  string          `json:"symbol"`
-	Setups  []*TradingSetup `json:"setups"`
-	Summary *SetupSummary   `json:"summary"`
-	Status  string          `json:"status"`
-	Message string          `json:"message,omitempty"`
+	Symbol     string          `json:"symbol"`
+	Setups     []*TradingSetup `json:"setups"`
+	Summary    *SetupSummary   `json:"summary"`
+	Pagination *Pagination     `json:"pagination,omitempty"`
+	Heat       *PortfolioHeat  `json:"heat,omitempty"`
+	Status     string          `json:"status"`
+	Message    string          `json:"message,omitempty"`
+}
+
+// Stop adjustment reasons
+const (
+	StopAdjustmentBreakeven = "breakeven" // First target reached, stop to the entry
+	StopAdjustmentATRTrail  = "atr_trail" // Stop trailing the best price since the first target by an ATR multiple
+)
+
+// SetupStopAdjustment is a stop move suggested for a triggered setup
+type SetupStopAdjustment struct {
+	ID            int64     `json:"id" db:"id"`
+	SetupID       int64     `json:"setup_id" db:"setup_id"`
+	Reason        string    `json:"reason" db:"reason"` // one of the StopAdjustment values
+	PreviousStop  float64   `json:"previous_stop" db:"previous_stop"`
+	SuggestedStop float64   `json:"suggested_stop" db:"suggested_stop"`
+	BestPrice     float64   `json:"best_price" db:"best_price"` // Highest high since entry, or lowest low for bearish setups
+	ATR           float64   `json:"atr" db:"atr"`
+	Price         float64   `json:"price" db:"price"` // Close of the bar the adjustment was suggested on
+	SuggestedAt   time.Time `json:"suggested_at" db:"suggested_at"`
 }
 
 // SetupAlert represents an alert for a trading setup
@@ -203,6 +330,22 @@ func (ts *TradingSetup) GetAge() float64 {
 	return time.Since(ts.DetectedAt).Hours()
 }
 
+// Trading setup statuses. An active setup is triggered when its entry prints, or invalidated when price
+// crosses its stop first; a triggered setup is completed at its first target or stopped out at its stop.
+const (
+	SetupStatusActive      = "active"
+	SetupStatusTriggered   = "triggered"
+	SetupStatusCompleted   = "completed"
+	SetupStatusStoppedOut  = "stopped_out"
+	SetupStatusInvalidated = "invalidated"
+	SetupStatusExpired     = "expired"
+)
+
+// SetupStatuses lists every setup status
+var SetupStatuses = []string{
+	SetupStatusActive, SetupStatusTriggered, SetupStatusCompleted, SetupStatusStoppedOut, SetupStatusInvalidated, SetupStatusExpired,
+}
+
 // IsExpired checks if the setup has expired
 func (ts *TradingSetup) IsExpired() bool {
 	return time.Now().After(ts.ExpiresAt)
@@ -213,16 +356,10 @@ func (ts *TradingSetup) IsActive() bool {
 	return ts.Status == "active" && !ts.IsExpired()
 }
 
-// GetConfidenceLevel returns confidence based on quality score
+// GetConfidenceLevel returns confidence based on the quality score alone, with the default confidence thresholds
 func (ts *TradingSetup) GetConfidenceLevel() string {
-	switch {
-	case ts.QualityScore >= 80:
-		return "high"
-	case ts.QualityScore >= 60:
-		return "medium"
-	default:
-		return "low"
-	}
+	model := DefaultConfidenceModel()
+	return model.Level(ts.QualityScore)
 }
 
 // GetRiskAmount calculates risk amount from entry to stop loss
@@ -269,6 +406,31 @@ func (ts *TradingSetup) IsMediumQuality() bool {
 
 // Methods for SetupChecklist
 
+// ChecklistItemKeys are the JSON keys of the checklist items, in checklist order
+var ChecklistItemKeys = []string{
+	"min_level_touches", "bounce_strength", "time_at_level", "rejection_candle", "level_duration",
+	"volume_spike", "volume_confirmation", "approach_volume", "vwap_relationship", "relative_volume",
+	"rsi_condition", "moving_average", "macd_signal", "momentum_divergence", "bollinger_bands",
+	"stop_loss_defined", "risk_reward_ratio", "position_size", "entry_precision", "exit_strategy",
+}
+
+// ChecklistCategories are the categories of ChecklistItemKeys, five items each
+var ChecklistCategories = []string{"price_action", "volume", "technical", "risk_management"}
+
+// Items returns the checklist items by their JSON key
+func (sc *SetupChecklist) Items() map[string]*ChecklistItem {
+	return map[string]*ChecklistItem{
+		"min_level_touches": &sc.MinLevelTouches, "bounce_strength": &sc.BounceStrength, "time_at_level": &sc.TimeAtLevel,
+		"rejection_candle": &sc.RejectionCandle, "level_duration": &sc.LevelDuration,
+		"volume_spike": &sc.VolumeSpike, "volume_confirmation": &sc.VolumeConfirmation, "approach_volume": &sc.ApproachVolume,
+		"vwap_relationship": &sc.VWAPRelationship, "relative_volume": &sc.RelativeVolume,
+		"rsi_condition": &sc.RSICondition, "moving_average": &sc.MovingAverage, "macd_signal": &sc.MACDSignal,
+		"momentum_divergence": &sc.MomentumDivergence, "bollinger_bands": &sc.BollingerBands,
+		"stop_loss_defined": &sc.StopLossDefined, "risk_reward_ratio": &sc.RiskRewardRatio, "position_size": &sc.PositionSize,
+		"entry_precision": &sc.EntryPrecision, "exit_strategy": &sc.ExitStrategy,
+	}
+}
+
 // CalculateScore calculates the total checklist score
 func (sc *SetupChecklist) CalculateScore() {
 	totalPoints := 0.0
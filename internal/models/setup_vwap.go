@@ -0,0 +1,32 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// VWAP setup types
+const (
+	SetupTypeVWAPReclaim   = "vwap_reclaim"   // closed back above the session VWAP on rising volume
+	SetupTypeVWAPRejection = "vwap_rejection" // rallied into the session VWAP and closed back below it on rising volume
+)
+
+// DefaultVWAPVolumeMultiplier is the signal bar volume over the session's average bar volume a VWAP setup needs
+const DefaultVWAPVolumeMultiplier = 1.5
+
+// StrategyVWAPSetups turns VWAP reclaim and rejection setups on for the stocks of a watchlist strategy
+type StrategyVWAPSetups struct {
+	StrategyID       int       `json:"strategy_id" db:"strategy_id"`
+	Reclaim          bool      `json:"reclaim" db:"reclaim"`                     // long setups on a reclaim from below
+	Rejection        bool      `json:"rejection" db:"rejection"`                 // short setups on a rejection from below
+	VolumeMultiplier float64   `json:"volume_multiplier" db:"volume_multiplier"` // signal bar volume over the session's average bar volume
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Validate checks the VWAP setup settings of a strategy
+func (s *StrategyVWAPSetups) Validate() error {
+	if s.VolumeMultiplier < 0 {
+		return fmt.Errorf("volume_multiplier must not be negative")
+	}
+	return nil
+}
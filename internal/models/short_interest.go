@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// ShortVolume is a symbol's FINRA reported short sale volume for a trading day
+type ShortVolume struct {
+	Symbol            string  `json:"symbol" db:"symbol"`
+	TradeDate         string  `json:"trade_date" db:"trade_date"` // YYYY-MM-DD
+	ShortVolume       int64   `json:"short_volume" db:"short_volume"`
+	ShortExemptVolume int64   `json:"short_exempt_volume" db:"short_exempt_volume"`
+	TotalVolume       int64   `json:"total_volume" db:"total_volume"`             // Volume reported to FINRA facilities, not the consolidated volume
+	ShortVolumeRatio  float64 `json:"short_volume_ratio" db:"short_volume_ratio"` // Short volume as a percent of the total
+}
+
+// ShortInterest is a symbol's short interest as of an exchange settlement date, reported twice a month
+type ShortInterest struct {
+	Symbol         string  `json:"symbol" db:"symbol"`
+	SettlementDate string  `json:"settlement_date" db:"settlement_date"` // YYYY-MM-DD
+	ShortInterest  int64   `json:"short_interest" db:"short_interest"`   // Shares sold short and not yet covered
+	AvgDailyVolume int64   `json:"avg_daily_volume" db:"avg_daily_volume"`
+	DaysToCover    float64 `json:"days_to_cover" db:"days_to_cover"`
+}
+
+// ShortInterestSummary is a symbol's recent short data and whether it is elevated
+type ShortInterestSummary struct {
+	Symbol              string           `json:"symbol"`
+	Latest              *ShortInterest   `json:"latest,omitempty"`              // Latest short interest report
+	ShortPercentFloat   float64          `json:"short_percent_float,omitempty"` // Latest short interest as a percent of the float, when the float is known
+	AvgShortVolumeRatio float64          `json:"avg_short_volume_ratio"`        // Short volume percent over the daily short volume below
+	Elevated            bool             `json:"elevated"`
+	Reasons             []string         `json:"reasons"`        // Measures past their elevated thresholds
+	ShortInterest       []*ShortInterest `json:"short_interest"` // Newest first
+	ShortVolume         []*ShortVolume   `json:"short_volume"`   // Newest first
+	UpdatedAt           time.Time        `json:"updated_at"`
+}
@@ -30,8 +30,14 @@ type Stock struct {
 	EMA50         float64   `json:"ema_50" db:"ema_50"`
 	EMA200        float64   `json:"ema_200" db:"ema_200"`
 
-	// Associated strategies (tags)
+	// Associated strategies
 	Strategies []Strategy `json:"strategies,omitempty"`
+
+	// Free-form user tags, lower case
+	Tags []string `json:"tags,omitempty"`
+
+	// Sector, market cap, float and average volume, once fetched
+	Reference *SymbolReference `json:"reference,omitempty"`
 }
 
 // StockStrategy represents the many-to-many relationship between stocks and strategies
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Strategy automation actions
+const (
+	AutomationActionPatternScan = "pattern_scan" // scan member stocks for chart patterns
+	AutomationActionAlert       = "alert"        // notify when member stocks meet alert conditions
+)
+
+// Pattern families a scan automation can run
+const (
+	AutomationPatternHeadShoulders        = "head_shoulders"
+	AutomationPatternInverseHeadShoulders = "inverse_head_shoulders"
+	AutomationPatternFallingWedge         = PatternFallingWedge
+	AutomationPatternRisingWedge          = PatternRisingWedge
+	AutomationPatternTriangle             = "triangle"
+	AutomationPatternFlag                 = "flag"
+)
+
+// AutomationPatterns lists the pattern families a scan automation can run
+var AutomationPatterns = []string{
+	AutomationPatternHeadShoulders,
+	AutomationPatternInverseHeadShoulders,
+	AutomationPatternFallingWedge,
+	AutomationPatternRisingWedge,
+	AutomationPatternTriangle,
+	AutomationPatternFlag,
+}
+
+// Strategy automation run triggers and statuses
+const (
+	AutomationTriggerScheduled = "scheduled"
+	AutomationTriggerManual    = "manual"
+
+	AutomationRunSucceeded = "succeeded"
+	AutomationRunFailed    = "failed"
+)
+
+// StrategyAutomation is a scan or alert attached to a watchlist strategy and run on a schedule against the
+// strategy's member stocks
+type StrategyAutomation struct {
+	ID         int64            `json:"id" db:"id"`
+	StrategyID int              `json:"strategy_id" db:"strategy_id"`
+	Name       string           `json:"name" db:"name"`
+	Action     string           `json:"action" db:"action"`                   // 'pattern_scan' or 'alert'
+	Patterns   []string         `json:"patterns,omitempty" db:"patterns"`     // pattern_scan: families to run, empty for all
+	Conditions []AlertCondition `json:"conditions,omitempty" db:"conditions"` // alert: conditions a member must meet
+	Logic      string           `json:"logic,omitempty" db:"logic"`           // alert: 'AND' or 'OR'
+	Schedule   string           `json:"schedule" db:"schedule"`               // cron expression in exchange time, e.g. "30 16 * * 1-5"
+	IsActive   bool             `json:"is_active" db:"is_active"`
+	// CooldownMinutes keeps an alert from notifying about the same stock again within the window
+	CooldownMinutes int        `json:"cooldown_minutes" db:"cooldown_minutes"`
+	LastRunAt       *time.Time `json:"last_run_at,omitempty" db:"last_run_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// StrategyAutomationRun records one run of a strategy automation
+type StrategyAutomationRun struct {
+	ID             int64             `json:"id" db:"id"`
+	AutomationID   int64             `json:"automation_id" db:"automation_id"`
+	AutomationName string            `json:"automation_name" db:"automation_name"`
+	StrategyID     int               `json:"strategy_id" db:"strategy_id"`
+	Action         string            `json:"action" db:"action"`
+	Trigger        string            `json:"trigger" db:"run_trigger"` // 'scheduled' or 'manual'
+	Status         string            `json:"status" db:"status"`       // 'succeeded' or 'failed'
+	Symbols        int               `json:"symbols" db:"symbols"`
+	Matches        []AutomationMatch `json:"matches" db:"matches"`
+	Errors         map[string]string `json:"errors,omitempty" db:"errors"` // failure per symbol
+	Error          string            `json:"error,omitempty" db:"error"`   // why the whole run failed
+	StartedAt      time.Time         `json:"started_at" db:"started_at"`
+	DurationMillis float64           `json:"duration_ms" db:"duration_ms"`
+}
+
+// AutomationMatch is a member stock an automation run found something on
+type AutomationMatch struct {
+	Symbol string             `json:"symbol"`
+	Detail string             `json:"detail"`           // the pattern found or the conditions met
+	Values map[string]float64 `json:"values,omitempty"` // alert: values of the fields in the conditions
+}
+
+// StrategyAutomationRunFilter represents filter parameters for querying automation runs
+type StrategyAutomationRunFilter struct {
+	StrategyID   int       `json:"strategy_id"`
+	AutomationID int64     `json:"automation_id"`
+	Since        time.Time `json:"since"`
+	Limit        int       `json:"limit"`
+}
+
+// Validate checks that an automation is well formed and normalizes its action, patterns and logic. The
+// schedule's syntax is checked by the scheduler.
+func (a *StrategyAutomation) Validate() error {
+	if strings.TrimSpace(a.Name) == "" {
+		return fmt.Errorf("automation name is required")
+	}
+	if strings.TrimSpace(a.Schedule) == "" {
+		return fmt.Errorf("schedule is required, e.g. \"30 16 * * 1-5\"")
+	}
+	a.Schedule = strings.TrimSpace(a.Schedule)
+	if a.CooldownMinutes < 0 {
+		return fmt.Errorf("cooldown_minutes cannot be negative")
+	}
+
+	a.Action = strings.ToLower(strings.TrimSpace(a.Action))
+	switch a.Action {
+	case AutomationActionPatternScan:
+		a.Conditions, a.Logic = nil, ""
+		for i, pattern := range a.Patterns {
+			a.Patterns[i] = strings.ToLower(strings.TrimSpace(pattern))
+			if !isAutomationPattern(a.Patterns[i]) {
+				return fmt.Errorf("unknown pattern %q: must be one of %s", pattern, strings.Join(AutomationPatterns, ", "))
+			}
+		}
+	case AutomationActionAlert:
+		a.Patterns = nil
+		rule := AlertRule{Name: a.Name, Conditions: a.Conditions, Logic: a.Logic}
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+		a.Logic = rule.Logic
+	default:
+		return fmt.Errorf("invalid action %q: must be %s or %s", a.Action, AutomationActionPatternScan, AutomationActionAlert)
+	}
+
+	return nil
+}
+
+// AlertRule returns an alert automation's conditions as a rule to evaluate against a member stock
+func (a *StrategyAutomation) AlertRule() *AlertRule {
+	return &AlertRule{Name: a.Name, Conditions: a.Conditions, Logic: a.Logic, CooldownMinutes: a.CooldownMinutes}
+}
+
+// ScanPatterns returns the pattern families a scan automation runs
+func (a *StrategyAutomation) ScanPatterns() []string {
+	if len(a.Patterns) == 0 {
+		return AutomationPatterns
+	}
+	return a.Patterns
+}
+
+func isAutomationPattern(pattern string) bool {
+	for _, p := range AutomationPatterns {
+		if p == pattern {
+			return true
+		}
+	}
+	return false
+}
@@ -9,7 +9,9 @@ type SupportResistanceLevel struct {
 	ID               int64     `json:"id" db:"id"`
 	Symbol           string    `json:"symbol" db:"symbol"`
 	Level            float64   `json:"level" db:"level"`
-	LevelType        string    `json:"level_type" db:"level_type"` // 'support' or 'resistance'
+	LevelType        string    `json:"level_type" db:"level_type"`   // 'support' or 'resistance'
+	LevelClass       string    `json:"level_class" db:"level_class"` // SRClassPivot or SRClassFibonacci
+	FibRatio         float64   `json:"fib_ratio,omitempty" db:"fib_ratio"`
 	Strength         float64   `json:"strength" db:"strength"`
 	Touches          int       `json:"touches" db:"touches"`
 	FirstTouch       time.Time `json:"first_touch" db:"first_touch"`
@@ -48,6 +50,95 @@ type PivotPoint struct {
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
 
+// S/R level classes. Pivot levels cluster touched pivots; Fibonacci levels are the retracements and extensions
+// of the latest significant swing, anchored at its first and last pivot (first_touch and last_touch).
+const (
+	SRClassPivot     = "pivot"
+	SRClassFibonacci = "fibonacci"
+	SRClassAll       = "all"
+)
+
+// Fibonacci ratios of the levels computed for a swing
+var (
+	FibRetracementRatios = []float64{0.236, 0.382, 0.5, 0.618, 0.786}
+	FibExtensionRatios   = []float64{1.272, 1.618, 2.618}
+)
+
+// FibonacciSwing is the swing Fibonacci levels are computed for, from its first to its last pivot
+type FibonacciSwing struct {
+	Direction  string    `json:"direction"` // 'up' or 'down'
+	StartTime  time.Time `json:"start_time"`
+	StartPrice float64   `json:"start_price"`
+	EndTime    time.Time `json:"end_time"`
+	EndPrice   float64   `json:"end_price"`
+}
+
+// Floor pivot periods: daily pivots come from the prior session, weekly pivots from the prior week
+const (
+	FloorPivotDaily  = "daily"
+	FloorPivotWeekly = "weekly"
+)
+
+// SRClassFloorPivot discriminates floor pivot levels from detected levels in S/R responses
+const SRClassFloorPivot = "floor_pivot"
+
+// FloorPivots are the classic floor trader pivot points of a session, computed from the prior period's high,
+// low and close
+type FloorPivots struct {
+	Symbol      string    `json:"symbol" db:"symbol"`
+	Period      string    `json:"period" db:"period"`             // FloorPivotDaily or FloorPivotWeekly
+	TradingDate string    `json:"trading_date" db:"trading_date"` // Session the pivots apply to
+	High        float64   `json:"high" db:"high"`
+	Low         float64   `json:"low" db:"low"`
+	Close       float64   `json:"close" db:"close"`
+	PP          float64   `json:"pp" db:"pp"`
+	R1          float64   `json:"r1" db:"r1"`
+	R2          float64   `json:"r2" db:"r2"`
+	R3          float64   `json:"r3" db:"r3"`
+	S1          float64   `json:"s1" db:"s1"`
+	S2          float64   `json:"s2" db:"s2"`
+	S3          float64   `json:"s3" db:"s3"`
+	ComputedAt  time.Time `json:"computed_at" db:"computed_at"`
+}
+
+// FloorPivotLevel is one floor pivot price, listed alongside detected S/R levels
+type FloorPivotLevel struct {
+	Symbol      string  `json:"symbol"`
+	LevelClass  string  `json:"level_class"` // Always SRClassFloorPivot
+	Period      string  `json:"period"`
+	TradingDate string  `json:"trading_date"`
+	Name        string  `json:"name"` // PP, R1-R3 or S1-S3
+	Level       float64 `json:"level"`
+	LevelType   string  `json:"level_type"` // 'support', 'resistance' or 'pivot'
+}
+
+// Levels lists the pivot point, resistances and supports, lowest first
+func (p *FloorPivots) Levels() []*FloorPivotLevel {
+	level := func(name string, price float64, levelType string) *FloorPivotLevel {
+		return &FloorPivotLevel{
+			Symbol:      p.Symbol,
+			LevelClass:  SRClassFloorPivot,
+			Period:      p.Period,
+			TradingDate: p.TradingDate,
+			Name:        name,
+			Level:       price,
+			LevelType:   levelType,
+		}
+	}
+	return []*FloorPivotLevel{
+		level("S3", p.S3, "support"), level("S2", p.S2, "support"), level("S1", p.S1, "support"),
+		level("PP", p.PP, "pivot"),
+		level("R1", p.R1, "resistance"), level("R2", p.R2, "resistance"), level("R3", p.R3, "resistance"),
+	}
+}
+
+// S/R level touch types
+const (
+	SRTouchTest   = "test"
+	SRTouchBreak  = "break" // a close beyond the level on high volume
+	SRTouchBounce = "bounce"
+)
+
 // SRLevelTouch represents a touch of a support/resistance level
 type SRLevelTouch struct {
 	ID              int64     `json:"id" db:"id"`
@@ -68,15 +159,106 @@ type SRLevelTouch struct {
 
 // SRDetectionConfig holds configuration for S/R detection
 type SRDetectionConfig struct {
-	MinTouches                int     `json:"min_touches" yaml:"min_touches"`
-	LookbackDays              int     `json:"lookback_days" yaml:"lookback_days"`
-	StrengthCalculation       string  `json:"strength_calculation" yaml:"strength_calculation"`
-	MinLevelDistancePercent   float64 `json:"min_level_distance_percent" yaml:"min_level_distance_percent"`
-	LevelPenetrationTolerance float64 `json:"level_penetration_tolerance" yaml:"level_penetration_tolerance"`
-	PivotStrength             int     `json:"pivot_strength" yaml:"pivot_strength"`
-	VolumeConfirmationRatio   float64 `json:"volume_confirmation_ratio" yaml:"volume_confirmation_ratio"`
-	MaxLevelAge               int     `json:"max_level_age" yaml:"max_level_age"`
-	MinBouncePercent          float64 `json:"min_bounce_percent" yaml:"min_bounce_percent"`
+	MinTouches                 int     `json:"min_touches" yaml:"min_touches"`
+	LookbackDays               int     `json:"lookback_days" yaml:"lookback_days"`
+	StrengthCalculation        string  `json:"strength_calculation" yaml:"strength_calculation"`
+	MinLevelDistancePercent    float64 `json:"min_level_distance_percent" yaml:"min_level_distance_percent"`
+	LevelPenetrationTolerance  float64 `json:"level_penetration_tolerance" yaml:"level_penetration_tolerance"`
+	PivotStrength              int     `json:"pivot_strength" yaml:"pivot_strength"`
+	VolumeConfirmationRatio    float64 `json:"volume_confirmation_ratio" yaml:"volume_confirmation_ratio"`
+	MaxLevelAge                int     `json:"max_level_age" yaml:"max_level_age"`
+	MinBouncePercent           float64 `json:"min_bounce_percent" yaml:"min_bounce_percent"`
+	ZoneWidthPercent           float64 `json:"zone_width_percent" yaml:"zone_width_percent"`                     // Band around each level; overlapping bands merge into one zone
+	ConfluenceTolerancePercent float64 `json:"confluence_tolerance_percent" yaml:"confluence_tolerance_percent"` // Distance outside a zone that still counts as confluence
+	FibMinSwingPercent         float64 `json:"fib_min_swing_percent" yaml:"fib_min_swing_percent"`               // Smallest swing, in percent of its start, Fibonacci levels are computed for
+}
+
+// DefaultSRDetectionConfig returns the default S/R detection settings
+func DefaultSRDetectionConfig() *SRDetectionConfig {
+	return &SRDetectionConfig{
+		MinTouches:                 3,
+		LookbackDays:               30,
+		StrengthCalculation:        "weighted",
+		MinLevelDistancePercent:    1.0,
+		LevelPenetrationTolerance:  0.5,
+		PivotStrength:              5,
+		VolumeConfirmationRatio:    1.5,
+		MaxLevelAge:                60,
+		MinBouncePercent:           2.0,
+		ZoneWidthPercent:           0.75,
+		ConfluenceTolerancePercent: 0.25,
+		FibMinSwingPercent:         3.0,
+	}
+}
+
+// Confluence sources that can reinforce an S/R zone
+const (
+	ConfluenceRoundNumber  = "round_number"
+	ConfluencePriorDayHigh = "prior_day_high"
+	ConfluencePriorDayLow  = "prior_day_low"
+	ConfluenceVWAP         = "vwap"
+	ConfluencePOC          = "point_of_control"
+	ConfluenceHVN          = "high_volume_node"
+)
+
+// SRZone is a price band formed by merging overlapping S/R levels of the same type
+type SRZone struct {
+	Symbol          string                    `json:"symbol"`
+	LevelType       string                    `json:"level_type"` // 'support' or 'resistance'
+	Low             float64                   `json:"low"`
+	High            float64                   `json:"high"`
+	Level           float64                   `json:"level"` // Strength-weighted center of the merged levels
+	Touches         int                       `json:"touches"`
+	Strength        float64                   `json:"strength"` // Strongest merged level
+	Confluence      []string                  `json:"confluence"`
+	ConfluenceScore float64                   `json:"confluence_score"`
+	ZoneStrength    float64                   `json:"zone_strength"` // 0-100: level strength plus merged touches and confluence
+	Levels          []*SupportResistanceLevel `json:"levels"`
+}
+
+// Contains reports whether a price lies inside the zone
+func (z *SRZone) Contains(price float64) bool {
+	return price >= z.Low && price <= z.High
+}
+
+// HasConfluence reports whether the zone lines up with the given confluence source
+func (z *SRZone) HasConfluence(source string) bool {
+	for _, c := range z.Confluence {
+		if c == source {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyLevel returns the strongest level in the zone
+func (z *SRZone) KeyLevel() *SupportResistanceLevel {
+	var key *SupportResistanceLevel
+	for _, level := range z.Levels {
+		if key == nil || level.Strength > key.Strength {
+			key = level
+		}
+	}
+	return key
+}
+
+// SRZoneResult lists a symbol's support and resistance zones
+type SRZoneResult struct {
+	Symbol          string            `json:"symbol"`
+	SupportZones    []*SRZone         `json:"support_zones"`
+	ResistanceZones []*SRZone         `json:"resistance_zones"`
+	ConfluenceRefs  *SRConfluenceRefs `json:"confluence_refs"`
+}
+
+// SRConfluenceRefs holds the reference prices zones are checked against; zero values are skipped
+type SRConfluenceRefs struct {
+	PriorDayHigh float64 `json:"prior_day_high"`
+	PriorDayLow  float64 `json:"prior_day_low"`
+	VWAP         float64 `json:"vwap"` // Current session VWAP
+
+	// From the symbol's volume profile
+	PointOfControl  float64   `json:"point_of_control"`
+	HighVolumeNodes []float64 `json:"high_volume_nodes,omitempty"`
 }
 
 // SRPriceRange represents a price range for filtering
@@ -94,10 +276,13 @@ type SRDetectionFilter struct {
 	MinTouches  int          `json:"min_touches"`
 	MaxTouches  int          `json:"max_touches"`
 	IsActive    *bool        `json:"is_active"`
+	LevelClass  string       `json:"level_class"` // SRClassPivot (the default), SRClassFibonacci or SRClassAll
 	TimeRange   SRTimeRange  `json:"time_range"`
 	PriceRange  SRPriceRange `json:"price_range"`
 	Limit       int          `json:"limit"`
 	Offset      int          `json:"offset"`
+	Sort        string       `json:"sort"`  // one of SRLevelSortFields
+	Order       string       `json:"order"` // 'asc' or 'desc'
 }
 
 // SRTimeRange represents a time range for filtering
@@ -116,10 +301,34 @@ type SRAnalysisResult struct {
 	NearestSupport    *SupportResistanceLevel   `json:"nearest_support"`
 	NearestResistance *SupportResistanceLevel   `json:"nearest_resistance"`
 	KeyLevels         []*SupportResistanceLevel `json:"key_levels"`
+	SupportZones      []*SRZone                 `json:"support_zones"`
+	ResistanceZones   []*SRZone                 `json:"resistance_zones"`
+	ConfluenceRefs    *SRConfluenceRefs         `json:"confluence_refs,omitempty"`
+	FibonacciSwing    *FibonacciSwing           `json:"fibonacci_swing,omitempty"`
+	FibonacciLevels   []*SupportResistanceLevel `json:"fibonacci_levels"`
 	RecentTouches     []*SRLevelTouch           `json:"recent_touches"`
 	LevelSummary      *SRLevelSummary           `json:"level_summary"`
 }
 
+// SRDetectionRun is the result of detecting S/R levels for many symbols; failed symbols are listed in the
+// run's errors instead of the results
+type SRDetectionRun struct {
+	Results map[string]*SRAnalysisResult `json:"results"`
+	Run     *ScanRun                     `json:"run"`
+}
+
+// SRRecalculation is the difference a recalculation made to a symbol's active levels: the levels it added and
+// the stored levels it no longer detected, which were deactivated
+type SRRecalculation struct {
+	Symbol            string                    `json:"symbol"`
+	Timeframe         Timeframe                 `json:"timeframe"`
+	RecalculatedAt    time.Time                 `json:"recalculated_at"`
+	ActiveLevels      int                       `json:"active_levels"`
+	NewLevels         []*SupportResistanceLevel `json:"new_levels"`
+	InvalidatedLevels []*SupportResistanceLevel `json:"invalidated_levels"`
+	Notified          int                       `json:"notified"` // new and invalidated levels strong enough to notify
+}
+
 // SRLevelSummary provides summary statistics about S/R levels
 type SRLevelSummary struct {
 	TotalLevels      int       `json:"total_levels"`
@@ -135,9 +344,11 @@ type SRLevelSummary struct {
 
 // SRResponse represents API response for S/R queries
 type SRResponse struct {
-	Symbol  string                    `json:"symbol"`
-	Levels  []*SupportResistanceLevel `json:"levels"`
-	Summary *SRLevelSummary           `json:"summary"`
-	Status  string                    `json:"status"`
-	Message string                    `json:"message,omitempty"`
+	Symbol      string                    `json:"symbol"`
+	Levels      []*SupportResistanceLevel `json:"levels"`
+	Summary     *SRLevelSummary           `json:"summary"`
+	Pagination  *Pagination               `json:"pagination,omitempty"`
+	FloorPivots []*FloorPivotLevel        `json:"floor_pivots,omitempty"` // Daily and weekly floor pivots of the current session
+	Status      string                    `json:"status"`
+	Message     string                    `json:"message,omitempty"`
 }
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// SymbolStats are a symbol's daily trading statistics, recomputed after each trading day to size stops and
+// targets against how far the symbol usually moves
+type SymbolStats struct {
+	Symbol              string    `json:"symbol" db:"symbol"`
+	TradingDate         string    `json:"trading_date" db:"trading_date"` // latest trading day included, YYYY-MM-DD
+	Days                int       `json:"days" db:"days"`                 // trading days averaged
+	Close               float64   `json:"close" db:"close"`               // latest regular session close
+	ATR14               float64   `json:"atr_14" db:"atr_14"`             // 14-day average true range of regular session candles
+	ATRPercent          float64   `json:"atr_percent" db:"atr_percent"`   // ATR14 as a percent of the close
+	AvgRange            float64   `json:"avg_range" db:"avg_range"`       // average regular session high minus low
+	AvgRangePercent     float64   `json:"avg_range_percent" db:"avg_range_percent"`
+	GapPercent          float64   `json:"gap_percent" db:"gap_percent"`         // move from the prior close counted as a gap
+	GapUpDays           int       `json:"gap_up_days" db:"gap_up_days"`         // days opening at least gap_percent above the prior close
+	GapDownDays         int       `json:"gap_down_days" db:"gap_down_days"`     // days opening at least gap_percent below it
+	GapFrequency        float64   `json:"gap_frequency" db:"gap_frequency"`     // share of days that gapped, 0 to 1
+	AvgGapPercent       float64   `json:"avg_gap_percent" db:"avg_gap_percent"` // average absolute open vs prior close move
+	AvgPreMarketVolume  int64     `json:"avg_pre_market_volume" db:"avg_pre_market_volume"`
+	AvgRegularVolume    int64     `json:"avg_regular_volume" db:"avg_regular_volume"`
+	AvgPostMarketVolume int64     `json:"avg_post_market_volume" db:"avg_post_market_volume"`
+	ComputedAt          time.Time `json:"computed_at" db:"computed_at"`
+}
@@ -22,6 +22,13 @@ type TechnicalIndicators struct {
 	BBUpper       float64   `json:"bb_upper" db:"bb_upper"`
 	BBMiddle      float64   `json:"bb_middle" db:"bb_middle"`
 	BBLower       float64   `json:"bb_lower" db:"bb_lower"`
+	ATR14         float64   `json:"atr_14" db:"atr_14"`
+	StochK        float64   `json:"stoch_k" db:"stoch_k"`
+	StochD        float64   `json:"stoch_d" db:"stoch_d"`
+	ADX14         float64   `json:"adx_14" db:"adx_14"`
+	PlusDI        float64   `json:"plus_di" db:"plus_di"`
+	MinusDI       float64   `json:"minus_di" db:"minus_di"`
+	OBV           float64   `json:"obv" db:"obv"`
 	CreatedAt     time.Time `json:"created_at" db:"created_at"`
 }
 
@@ -40,6 +47,12 @@ type MACDData struct {
 	Histogram float64 `json:"histogram"`
 }
 
+// MACDPoint represents MACD indicator data at a point in time
+type MACDPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	MACDData
+}
+
 // RSIData represents RSI indicator data
 type RSIData struct {
 	RSI14 float64 `json:"rsi_14"`
@@ -65,6 +78,26 @@ type VolumeAnalysisData struct {
 	VWAP        float64 `json:"vwap"`
 	VolumeRatio float64 `json:"volume_ratio"`
 	AvgVolume   float64 `json:"avg_volume"`
+	OBV         float64 `json:"obv"`
+}
+
+// VolatilityData represents Average True Range data
+type VolatilityData struct {
+	ATR        float64 `json:"atr"`
+	ATRPercent float64 `json:"atr_percent"`
+}
+
+// StochasticData represents Stochastic oscillator data
+type StochasticData struct {
+	K float64 `json:"k"`
+	D float64 `json:"d"`
+}
+
+// TrendStrengthData represents ADX trend strength data
+type TrendStrengthData struct {
+	ADX     float64 `json:"adx"`
+	PlusDI  float64 `json:"plus_di"`
+	MinusDI float64 `json:"minus_di"`
 }
 
 // IndicatorFilter represents filter parameters for querying indicators
@@ -111,6 +144,9 @@ type IndicatorSummary struct {
 	MovingAverages   *MovingAverageData  `json:"moving_averages"`
 	BollingerBands   *BollingerBandsData `json:"bollinger_bands"`
 	Volume           *VolumeAnalysisData `json:"volume"`
+	Stochastic       *StochasticData     `json:"stochastic"`
+	TrendStrength    *TrendStrengthData  `json:"trend_strength"`
+	Volatility       *VolatilityData     `json:"volatility"`
 	ActiveAlerts     []*IndicatorAlert   `json:"active_alerts"`
 	TrendDirection   string              `json:"trend_direction"`   // 'bullish', 'bearish', 'neutral'
 	OverallSentiment string              `json:"overall_sentiment"` // 'strong_buy', 'buy', 'neutral', 'sell', 'strong_sell'
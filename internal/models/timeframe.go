@@ -0,0 +1,56 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Timeframe represents the candle size used for analysis
+type Timeframe string
+
+const (
+	Timeframe1m  Timeframe = "1m"
+	Timeframe5m  Timeframe = "5m"
+	Timeframe15m Timeframe = "15m"
+	Timeframe1h  Timeframe = "1h"
+	Timeframe1d  Timeframe = "1d"
+
+	// DefaultTimeframe is the resolution data is collected at
+	DefaultTimeframe = Timeframe1m
+)
+
+// ParseTimeframe parses a timeframe string, returning the default timeframe when empty
+func ParseTimeframe(s string) (Timeframe, error) {
+	if s == "" {
+		return DefaultTimeframe, nil
+	}
+
+	tf := Timeframe(s)
+	if tf.Duration() == 0 {
+		return "", fmt.Errorf("invalid timeframe %q: must be one of 1m, 5m, 15m, 1h, 1d", s)
+	}
+	return tf, nil
+}
+
+// Duration returns the length of one candle, or 0 for an unknown timeframe
+func (tf Timeframe) Duration() time.Duration {
+	switch tf {
+	case Timeframe1m:
+		return time.Minute
+	case Timeframe5m:
+		return 5 * time.Minute
+	case Timeframe15m:
+		return 15 * time.Minute
+	case Timeframe1h:
+		return time.Hour
+	case Timeframe1d:
+		return 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// IsAggregated returns true if candles must be rolled up from 1-minute bars
+func (tf Timeframe) IsAggregated() bool {
+	return tf != "" && tf != DefaultTimeframe
+}
@@ -0,0 +1,241 @@
+package models
+
+import (
+	"time"
+)
+
+// Triangle pattern types
+const (
+	PatternAscendingTriangle  = "ascending_triangle"
+	PatternDescendingTriangle = "descending_triangle"
+)
+
+// TrianglePattern represents an ascending or descending triangle chart pattern
+type TrianglePattern struct {
+	ID     int64  `json:"id" db:"id"`
+	Symbol string `json:"symbol" db:"symbol"`
+
+	// Pattern identification
+	PatternType string `json:"pattern_type" db:"pattern_type"` // "ascending_triangle", "descending_triangle"
+
+	// Trend line points
+	UpperTrendLine1 PatternPoint `json:"upper_trend_line_1" db:"upper_trend_line_1"`
+	UpperTrendLine2 PatternPoint `json:"upper_trend_line_2" db:"upper_trend_line_2"`
+	LowerTrendLine1 PatternPoint `json:"lower_trend_line_1" db:"lower_trend_line_1"`
+	LowerTrendLine2 PatternPoint `json:"lower_trend_line_2" db:"lower_trend_line_2"`
+
+	// Pattern metrics
+	UpperSlope    float64 `json:"upper_slope" db:"upper_slope"`       // Slope of upper trend line
+	LowerSlope    float64 `json:"lower_slope" db:"lower_slope"`       // Slope of lower trend line
+	BreakoutLevel float64 `json:"breakout_level" db:"breakout_level"` // Flat boundary the price must break
+	PatternWidth  int64   `json:"pattern_width" db:"pattern_width"`   // Duration in minutes
+	PatternHeight float64 `json:"pattern_height" db:"pattern_height"` // Price range at the widest point
+	TouchPoints   int     `json:"touch_points" db:"touch_points"`     // Touches of the flat boundary
+
+	// Volume analysis
+	VolumeProfile string `json:"volume_profile" db:"volume_profile"` // "decreasing", "stable", "increasing"
+
+	// Thesis tracking
+	ThesisComponents TriangleThesis `json:"thesis_components" db:"thesis_components"`
+
+	// Status
+	DetectedAt   time.Time `json:"detected_at" db:"detected_at"`
+	LastUpdated  time.Time `json:"last_updated" db:"last_updated"`
+	IsComplete   bool      `json:"is_complete" db:"is_complete"`
+	CurrentPhase string    `json:"current_phase" db:"current_phase"` // "formation", "breakout", "target_pursuit", "completed"
+
+	// Archived records are kept for analytics but left out of lists unless asked for
+	Archived   bool       `json:"archived" db:"archived"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty" db:"archived_at"`
+}
+
+// TriangleThesis represents the thesis components for triangle patterns
+type TriangleThesis struct {
+	// Formation components (40 points)
+	FlatBoundary       ThesisComponent `json:"flat_boundary"`        // 10 points
+	SlopingBoundary    ThesisComponent `json:"sloping_boundary"`     // 10 points
+	MinimumTouchPoints ThesisComponent `json:"minimum_touch_points"` // 10 points
+	VolumeContraction  ThesisComponent `json:"volume_contraction"`   // 10 points
+
+	// Breakout components (35 points)
+	BoundaryBreak      ThesisComponent `json:"boundary_break"`      // 15 points
+	VolumeConfirmation ThesisComponent `json:"volume_confirmation"` // 10 points
+	PriceCloseBeyond   ThesisComponent `json:"price_close_beyond"`  // 10 points
+
+	// Target components (25 points)
+	PartialTarget ThesisComponent `json:"partial_target"` // 10 points
+	FullTarget    ThesisComponent `json:"full_target"`    // 15 points
+
+	// Calculated metrics
+	CompletedComponents int     `json:"completed_components"`
+	TotalComponents     int     `json:"total_components"`
+	CompletionPercent   float64 `json:"completion_percent"`
+	CurrentPhase        string  `json:"current_phase"`
+
+	// Points each component contributed to the completion percent
+	ScoreBreakdown *ScoreBreakdown `json:"score_breakdown,omitempty"`
+}
+
+// TriangleConfig holds configuration for triangle detection
+type TriangleConfig struct {
+	MinPatternDuration  time.Duration `json:"min_pattern_duration" yaml:"min_pattern_duration"`
+	MaxPatternDuration  time.Duration `json:"max_pattern_duration" yaml:"max_pattern_duration"`
+	FlatTolerance       float64       `json:"flat_tolerance" yaml:"flat_tolerance"`         // Max price deviation of the flat line (%)
+	MinSlope            float64       `json:"min_slope" yaml:"min_slope"`                   // Min slope of the sloping line (% per day)
+	MinTouchPoints      int           `json:"min_touch_points" yaml:"min_touch_points"`     // Minimum flat boundary touches
+	MinPatternHeight    float64       `json:"min_pattern_height" yaml:"min_pattern_height"` // Minimum pattern height %
+	BreakoutVolumeRatio float64       `json:"breakout_volume_ratio" yaml:"breakout_volume_ratio"`
+}
+
+// DefaultTriangleConfig returns the default triangle detection settings
+func DefaultTriangleConfig() *TriangleConfig {
+	return &TriangleConfig{
+		MinPatternDuration:  48 * time.Hour,  // 2 days minimum
+		MaxPatternDuration:  720 * time.Hour, // 30 days maximum
+		FlatTolerance:       1.5,             // Flat line touches within 1.5% of each other
+		MinSlope:            0.2,             // Sloping line moves at least 0.2% per day
+		MinTouchPoints:      2,               // At least 2 touches of the flat line
+		MinPatternHeight:    0.03,            // 3% minimum height
+		BreakoutVolumeRatio: 1.5,             // Breakout volume should be 1.5x average
+	}
+}
+
+// TriangleFilter represents filter parameters for triangle queries
+type TriangleFilter struct {
+	Symbol          string `json:"symbol"`
+	PatternType     string `json:"pattern_type"`
+	Phase           string `json:"phase"`
+	IsComplete      *bool  `json:"is_complete"`
+	IncludeArchived bool   `json:"include_archived"`
+	Limit           int    `json:"limit"`
+	Offset          int    `json:"offset"`
+	Sort            string `json:"sort"`  // one of PatternSortFields
+	Order           string `json:"order"` // 'asc' or 'desc'
+}
+
+// Methods for TrianglePattern
+
+// IsBullish returns true for ascending triangles, which break out upward
+func (tp *TrianglePattern) IsBullish() bool {
+	return tp.PatternType == PatternAscendingTriangle
+}
+
+// CalculateTargetPrice projects the pattern height from the breakout level
+func (tp *TrianglePattern) CalculateTargetPrice() float64 {
+	if tp.IsBullish() {
+		return tp.BreakoutLevel + tp.PatternHeight
+	}
+	return tp.BreakoutLevel - tp.PatternHeight
+}
+
+// IsBrokenOut checks if price has moved beyond the breakout level
+func (tp *TrianglePattern) IsBrokenOut(currentPrice float64) bool {
+	if tp.IsBullish() {
+		return currentPrice > tp.BreakoutLevel
+	}
+	return currentPrice < tp.BreakoutLevel
+}
+
+// GetPatternAge returns the age of the pattern in hours
+func (tp *TrianglePattern) GetPatternAge() float64 {
+	return time.Since(tp.DetectedAt).Hours()
+}
+
+// Methods for TriangleThesis
+
+// InitializeTriangleThesis initializes the thesis with default values
+func (tt *TriangleThesis) InitializeTriangleThesis(bullish bool) {
+	flat, sloping, direction := "resistance", "rising support", "above"
+	if !bullish {
+		flat, sloping, direction = "support", "falling resistance", "below"
+	}
+
+	// Formation components
+	tt.FlatBoundary = newThesisComponent("Flat Boundary", "Horizontal "+flat+" line established", true, 10.0)
+	tt.SlopingBoundary = newThesisComponent("Sloping Boundary", "Converging "+sloping+" line established", true, 10.0)
+	tt.MinimumTouchPoints = newThesisComponent("Minimum Touch Points", "At least 2 touches of the flat boundary", true, 10.0)
+	tt.VolumeContraction = newThesisComponent("Volume Contraction", "Volume contracts as the triangle narrows", false, 10.0)
+
+	// Breakout components
+	tt.BoundaryBreak = newThesisComponent("Boundary Break", "Price breaks "+direction+" the flat "+flat, true, 15.0)
+	tt.VolumeConfirmation = newThesisComponent("Volume Confirmation", "Breakout confirmed with volume spike", false, 10.0)
+	tt.PriceCloseBeyond = newThesisComponent("Price Close Beyond Line", "Price closes "+direction+" the flat "+flat, true, 10.0)
+
+	// Target components
+	tt.PartialTarget = newThesisComponent("Partial Target", "50% of projected target reached", false, 10.0)
+	tt.FullTarget = newThesisComponent("Full Target", "Full projected target reached", false, 15.0)
+
+	tt.CalculateCompletion()
+	tt.UpdatePhase()
+}
+
+// GetAllComponents returns all thesis components
+func (tt *TriangleThesis) GetAllComponents() []*ThesisComponent {
+	return []*ThesisComponent{
+		&tt.FlatBoundary,
+		&tt.SlopingBoundary,
+		&tt.MinimumTouchPoints,
+		&tt.VolumeContraction,
+		&tt.BoundaryBreak,
+		&tt.VolumeConfirmation,
+		&tt.PriceCloseBeyond,
+		&tt.PartialTarget,
+		&tt.FullTarget,
+	}
+}
+
+// CalculateCompletion calculates completion statistics
+func (tt *TriangleThesis) CalculateCompletion() {
+	tt.CompletedComponents, tt.TotalComponents, tt.CompletionPercent = calculateThesisCompletion(tt.GetAllComponents())
+	tt.ScoreBreakdown = thesisScoreBreakdown(tt.GetAllComponents(), tt.CompletionPercent, true)
+}
+
+// UpdatePhase updates the current phase based on completion
+func (tt *TriangleThesis) UpdatePhase() {
+	if tt.FullTarget.IsCompleted {
+		tt.CurrentPhase = PhaseCompleted
+	} else if tt.BoundaryBreak.IsCompleted {
+		tt.CurrentPhase = PhaseTargetPursuit
+	} else if tt.FlatBoundary.IsCompleted && tt.SlopingBoundary.IsCompleted && tt.MinimumTouchPoints.IsCompleted {
+		tt.CurrentPhase = PhaseBreakout
+	} else {
+		tt.CurrentPhase = PhaseFormation
+	}
+}
+
+// newThesisComponent creates an auto-detected thesis component that has not been completed yet
+func newThesisComponent(name, description string, required bool, weight float64) ThesisComponent {
+	return ThesisComponent{
+		Name:            name,
+		Description:     description,
+		IsCompleted:     false,
+		IsRequired:      required,
+		Weight:          weight,
+		ConfidenceLevel: 0.0,
+		Evidence:        []string{},
+		LastChecked:     time.Now(),
+		AutoDetected:    true,
+	}
+}
+
+// calculateThesisCompletion returns completed count, total count and weighted completion percent
+func calculateThesisCompletion(components []*ThesisComponent) (int, int, float64) {
+	completed := 0
+	totalWeight := 0.0
+	completedWeight := 0.0
+
+	for _, component := range components {
+		totalWeight += component.Weight
+		if component.IsCompleted {
+			completed++
+			completedWeight += component.Weight
+		}
+	}
+
+	percent := 0.0
+	if totalWeight > 0 {
+		percent = (completedWeight / totalWeight) * 100
+	}
+
+	return completed, len(components), percent
+}
@@ -38,6 +38,7 @@ type DashboardSummary struct {
 	Symbols        []VolumeStats `json:"symbols"`
 	LastUpdate     time.Time     `json:"last_update"`
 	CollectionMode string        `json:"collection_mode"` // "24/7" to indicate continuous collection
+	Timezone       string        `json:"timezone"`        // time zone the times are in
 }
 
 // PolygonAggregateResponse represents the response from Polygon.io aggregates API
@@ -87,6 +88,7 @@ type VolumeDataFilter struct {
 // ErrorResponse represents an API error response
 type ErrorResponse struct {
 	Error   string            `json:"error"`
+	Code    string            `json:"code,omitempty"` // Stable error code clients can branch on, e.g. not_found
 	Message string            `json:"message"`
 	Details map[string]string `json:"details,omitempty"`
 }
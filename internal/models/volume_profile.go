@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// MaxVolumeProfileBuckets is the most price buckets a volume profile may be split into
+const MaxVolumeProfileBuckets = 500
+
+// VolumeProfileBucket is the volume traded within one price band of a volume profile
+type VolumeProfileBucket struct {
+	PriceLow  float64 `json:"price_low"`
+	PriceHigh float64 `json:"price_high"`
+	Price     float64 `json:"price"` // Band midpoint
+	Volume    float64 `json:"volume"`
+	Percent   float64 `json:"percent"` // Share of the profile's total volume
+	IsHVN     bool    `json:"is_hvn"`
+	InValue   bool    `json:"in_value_area"`
+}
+
+// VolumeProfile buckets a symbol's traded volume by price over a window
+type VolumeProfile struct {
+	Symbol          string                 `json:"symbol"`
+	WindowDays      int                    `json:"window_days"`
+	From            time.Time              `json:"from"`
+	To              time.Time              `json:"to"`
+	BucketSize      float64                `json:"bucket_size"`
+	TotalVolume     float64                `json:"total_volume"`
+	PointOfControl  float64                `json:"point_of_control"` // Midpoint of the highest volume bucket
+	ValueAreaHigh   float64                `json:"value_area_high"`
+	ValueAreaLow    float64                `json:"value_area_low"`
+	HighVolumeNodes []float64              `json:"high_volume_nodes"` // Midpoints of local volume peaks, highest volume first
+	Buckets         []*VolumeProfileBucket `json:"buckets"`           // Lowest price first
+	ComputedAt      time.Time              `json:"computed_at"`
+}
@@ -35,3 +35,90 @@ type WatchlistStock struct {
 	CategoryName  string `json:"category_name,omitempty" db:"category_name"`
 	CategoryColor string `json:"category_color,omitempty" db:"category_color"`
 }
+
+// Watchlist import/export formats
+const (
+	WatchlistFormatCSV         = "csv"         // symbol,name,strategies,notes with a header row
+	WatchlistFormatTradingView = "tradingview" // comma-separated symbols, ###Section markers start a new section
+	WatchlistFormatThinkorswim = "thinkorswim" // one symbol per line under a Symbol header
+)
+
+// How imported symbols already on the watchlist are handled
+const (
+	DuplicateMerge   = "merge"   // add the imported strategies and fill empty notes (default)
+	DuplicateSkip    = "skip"    // leave existing stocks untouched
+	DuplicateReplace = "replace" // replace the stock's strategies and notes with the imported ones
+)
+
+// WatchlistImportOptions controls how a watchlist file is mapped onto strategies
+type WatchlistImportOptions struct {
+	Format     string `json:"format"`
+	Strategy   string `json:"strategy"`   // strategy for rows without one; thinkorswim files always use it (default "Imported")
+	Duplicates string `json:"duplicates"` // merge, skip or replace
+}
+
+// WatchlistImportEntry is one symbol read from a watchlist file
+type WatchlistImportEntry struct {
+	Symbol     string   `json:"symbol"`
+	Name       string   `json:"name,omitempty"`
+	Strategies []string `json:"strategies"`
+	Notes      string   `json:"notes,omitempty"`
+}
+
+// WatchlistImportResult summarizes a watchlist import
+type WatchlistImportResult struct {
+	Format            string   `json:"format"`
+	Rows              int      `json:"rows"`
+	Added             int      `json:"added"`
+	Updated           int      `json:"updated"`
+	Skipped           int      `json:"skipped"`
+	DuplicateRows     int      `json:"duplicate_rows"` // rows repeating a symbol earlier in the file, merged into it
+	StrategiesCreated []string `json:"strategies_created"`
+	Errors            []string `json:"errors,omitempty"`
+}
+
+// Outcomes of a bulk watchlist operation for one symbol
+const (
+	BulkStatusAdded   = "added"
+	BulkStatusUpdated = "updated" // already on the watchlist; the strategies and tags were merged
+	BulkStatusRemoved = "removed"
+	BulkStatusMoved   = "moved"
+	BulkStatusFailed  = "failed"
+)
+
+// WatchlistBulkAddRequest adds several symbols to the watchlist at once
+type WatchlistBulkAddRequest struct {
+	Symbols      []string `json:"symbols"`
+	StrategyIDs  []int    `json:"strategy_ids"`
+	Tags         []string `json:"tags,omitempty"`
+	BackfillDays int      `json:"backfill_days,omitempty"` // history backfilled for the newly added symbols (default 30, -1 for none)
+}
+
+// WatchlistBulkRemoveRequest removes several symbols from the watchlist, or from one strategy
+type WatchlistBulkRemoveRequest struct {
+	Symbols    []string `json:"symbols"`
+	StrategyID int      `json:"strategy_id,omitempty"` // only remove from this strategy; stocks left without one are deleted
+	Unwatch    bool     `json:"unwatch,omitempty"`     // also stop collecting data for the deleted stocks
+}
+
+// WatchlistBulkMoveRequest moves several symbols to another strategy
+type WatchlistBulkMoveRequest struct {
+	Symbols        []string `json:"symbols"`
+	FromStrategyID int      `json:"from_strategy_id,omitempty"` // strategy to move out of; all of the stock's strategies when 0
+	ToStrategyID   int      `json:"to_strategy_id"`
+}
+
+// WatchlistBulkItem is the outcome of a bulk operation for one symbol
+type WatchlistBulkItem struct {
+	Symbol string `json:"symbol"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// WatchlistBulkResult reports a bulk operation per symbol, in request order
+type WatchlistBulkResult struct {
+	Results     []WatchlistBulkItem `json:"results"`
+	Succeeded   int                 `json:"succeeded"`
+	Failed      int                 `json:"failed"`
+	BackfillJob *Job                `json:"backfill_job,omitempty"` // one job backfilling every newly added symbol
+}
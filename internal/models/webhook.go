@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// Webhook event types, posted to the configured endpoints as patterns and setups move through their lifecycle
+const (
+	WebhookPatternDetected   = "pattern_detected"   // a new pattern was stored
+	WebhookBreakoutConfirmed = "breakout_confirmed" // an active pattern broke out and is pursuing its target
+	WebhookTargetReached     = "target_reached"     // a pattern reached its full target
+	WebhookSetupTriggered    = "setup_triggered"    // price printed a setup's entry
+	WebhookSetupInvalidated  = "setup_invalidated"  // a setup was invalidated before entry
+	WebhookTest              = "test"               // sent on request to check an endpoint
+)
+
+// WebhookEvents lists the event types endpoints can subscribe to
+var WebhookEvents = []string{
+	WebhookPatternDetected,
+	WebhookBreakoutConfirmed,
+	WebhookTargetReached,
+	WebhookSetupTriggered,
+	WebhookSetupInvalidated,
+}
+
+// IsWebhookEvent reports whether an event type can be subscribed to
+func IsWebhookEvent(event string) bool {
+	for _, e := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEvent is the JSON body posted to webhook endpoints
+type WebhookEvent struct {
+	ID        string      `json:"id"` // unique per event; retries resend the same ID so receivers can deduplicate
+	Type      string      `json:"type"`
+	Symbol    string      `json:"symbol,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// WebhookPatternData is the data of pattern events
+type WebhookPatternData struct {
+	Family        string  `json:"family"` // head_shoulders, falling_wedge, triangle or flag
+	PatternID     int64   `json:"pattern_id"`
+	PatternType   string  `json:"pattern_type"`
+	Direction     string  `json:"direction"` // 'bullish' or 'bearish'
+	Phase         string  `json:"phase"`
+	SetupID       int64   `json:"setup_id,omitempty"`
+	BreakoutLevel float64 `json:"breakout_level"` // neckline of head and shoulders patterns
+	TargetPrice   float64 `json:"target_price"`
+	Price         float64 `json:"price,omitempty"` // price that confirmed the breakout or reached the target
+}
+
+// WebhookSetupData is the data of setup events
+type WebhookSetupData struct {
+	Setup  *TradingSetup `json:"setup"`
+	Price  float64       `json:"price,omitempty"`  // close of the bar that changed the setup's status
+	BarAt  *time.Time    `json:"bar_at,omitempty"` // time of that bar; unset when reconciliation superseded the setup
+	Reason string        `json:"reason"`           // why the status changed
+}
+
+// WebhookDeadLetter is an event that could not be delivered to an endpoint after every retry
+type WebhookDeadLetter struct {
+	ID        int64     `json:"id" db:"id"`
+	EventID   string    `json:"event_id" db:"event_id"`
+	EventType string    `json:"event_type" db:"event_type"`
+	Symbol    string    `json:"symbol,omitempty" db:"symbol"`
+	URL       string    `json:"url" db:"url"`
+	Payload   string    `json:"payload" db:"payload"` // the JSON body that was posted
+	Attempts  int       `json:"attempts" db:"attempts"`
+	LastError string    `json:"last_error" db:"last_error"`
+	FailedAt  time.Time `json:"failed_at" db:"failed_at"`
+}
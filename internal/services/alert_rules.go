@@ -0,0 +1,344 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// AlertRuleService evaluates user-defined alert rules against the latest market data
+type AlertRuleService struct {
+	db            *database.Database
+	taService     *TechnicalAnalysisService
+	emailService  *EmailService
+	rvolService   *RVOLService
+	anomalies     *AnomalyService
+	notifications *NotificationService
+	mutex         sync.Mutex // serializes evaluation cycles
+}
+
+// NewAlertRuleService creates a new alert rule service
+func NewAlertRuleService(db *database.Database, taService *TechnicalAnalysisService, emailService *EmailService) *AlertRuleService {
+	return &AlertRuleService{
+		db:           db,
+		taService:    taService,
+		emailService: emailService,
+	}
+}
+
+// SetRVOLService enables the intraday relative volume field
+func (ars *AlertRuleService) SetRVOLService(rvolService *RVOLService) {
+	ars.rvolService = rvolService
+}
+
+// SetAnomalyService enables the volume z-score and recent anomaly fields
+func (ars *AlertRuleService) SetAnomalyService(anomalies *AnomalyService) {
+	ars.anomalies = anomalies
+}
+
+// SetNotificationService records every trigger in the notification center, whether or not the rule emails
+func (ars *AlertRuleService) SetNotificationService(notifications *NotificationService) {
+	ars.notifications = notifications
+}
+
+// alertRuleInstance identifies a rule's evaluation on one symbol
+type alertRuleInstance struct {
+	ruleID int64
+	symbol string
+}
+
+// EvaluateRules checks every active rule against the given symbols and records triggers. Sequenced rules
+// advance their stored progress on each symbol and fire when their last leg matches.
+func (ars *AlertRuleService) EvaluateRules(symbols []string) ([]*models.AlertTrigger, error) {
+	ars.mutex.Lock()
+	defer ars.mutex.Unlock()
+
+	rules, err := ars.db.GetAlertRules(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert rules: %w", err)
+	}
+
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	stored, err := ars.db.GetAlertRuleStates(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert rule states: %w", err)
+	}
+	states := make(map[alertRuleInstance]*models.AlertRuleState, len(stored))
+	for _, state := range stored {
+		states[alertRuleInstance{state.RuleID, state.Symbol}] = state
+	}
+
+	triggers := make([]*models.AlertTrigger, 0)
+	valuesCache := make(map[string]map[string]float64)
+
+	for _, symbol := range symbols {
+		for _, rule := range rules {
+			if rule.Symbol != "" && rule.Symbol != symbol {
+				continue
+			}
+
+			now := clockNow()
+			if rule.CooldownMinutes > 0 {
+				lastTriggered, err := ars.db.GetLastAlertTriggerTime(rule.ID, symbol)
+				if err != nil {
+					log.Printf("Failed to get last trigger of alert rule %d (%s) on %s: %v", rule.ID, rule.Name, symbol, err)
+					continue
+				}
+				if rule.IsCoolingDown(lastTriggered, now) {
+					continue
+				}
+			}
+
+			values, ok := valuesCache[symbol]
+			if !ok {
+				values, err = ars.GetFieldValues(symbol)
+				if err != nil {
+					log.Printf("Failed to get alert field values for %s: %v", symbol, err)
+					values = nil
+				}
+				valuesCache[symbol] = values
+			}
+			if values == nil {
+				continue
+			}
+
+			fired := values
+			if rule.IsSequence() {
+				fired, err = ars.advanceSequence(rule, symbol, states[alertRuleInstance{rule.ID, symbol}], values, now)
+				if err != nil {
+					log.Printf("Failed to advance alert rule %d (%s) on %s: %v", rule.ID, rule.Name, symbol, err)
+					continue
+				}
+			} else if !rule.Evaluate(values) {
+				fired = nil
+			}
+			if fired == nil {
+				continue
+			}
+
+			trigger, err := ars.fireRule(rule, symbol, fired, now)
+			if err != nil {
+				log.Printf("Failed to record trigger for alert rule %d (%s): %v", rule.ID, rule.Name, err)
+				continue
+			}
+			triggers = append(triggers, trigger)
+		}
+	}
+
+	return triggers, nil
+}
+
+// EvaluateAllRules checks every active rule against all watched symbols
+func (ars *AlertRuleService) EvaluateAllRules() ([]*models.AlertTrigger, error) {
+	symbols, err := ars.db.GetWatchedSymbols()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watched symbols: %w", err)
+	}
+	return ars.EvaluateRules(symbols)
+}
+
+// GetFieldValues returns the current value of every alert field for a symbol
+func (ars *AlertRuleService) GetFieldValues(symbol string) (map[string]float64, error) {
+	latestPrice, err := ars.db.GetLatestPriceData(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest price: %w", err)
+	}
+	if latestPrice == nil {
+		return nil, fmt.Errorf("no price data available for symbol %s", symbol)
+	}
+
+	price := latestPrice.Close
+	values := map[string]float64{
+		models.AlertFieldPrice: price,
+	}
+
+	indicators, err := ars.taService.GetIndicators(context.Background(), symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get technical indicators: %w", err)
+	}
+
+	values[models.AlertFieldRSI14] = indicators.RSI14
+	values[models.AlertFieldRSI30] = indicators.RSI30
+	values[models.AlertFieldMACD] = indicators.MACD
+	values[models.AlertFieldMACDHistogram] = indicators.MACDHistogram
+	values[models.AlertFieldVolumeRatio] = indicators.VolumeRatio
+	values[models.AlertFieldVWAP] = indicators.VWAP
+	values[models.AlertFieldATR14] = indicators.ATR14
+	values[models.AlertFieldStochK] = indicators.StochK
+	values[models.AlertFieldADX14] = indicators.ADX14
+
+	// Relative volume is only defined once prior sessions cover the current time of day
+	if ars.rvolService != nil {
+		curve, err := ars.rvolService.Compute(symbol, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get relative volume: %w", err)
+		}
+		if curve.RVOL > 0 {
+			values[models.AlertFieldRVOL] = curve.RVOL
+		}
+	}
+
+	if ars.anomalies != nil {
+		anomalyValues, err := ars.anomalies.FieldValues(symbol)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get anomaly fields: %w", err)
+		}
+		for field, value := range anomalyValues {
+			values[field] = value
+		}
+	}
+
+	// Distance to the nearest levels is only defined when a level exists
+	support, resistance, err := ars.db.GetNearestSupportResistance(symbol, price)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nearest support/resistance: %w", err)
+	}
+	if support != nil && support.Level > 0 {
+		values[models.AlertFieldSupportDistancePercent] = (price - support.Level) / support.Level * 100
+	}
+	if resistance != nil && resistance.Level > 0 {
+		values[models.AlertFieldResistanceDistancePercent] = (resistance.Level - price) / resistance.Level * 100
+	}
+
+	return values, nil
+}
+
+// fireRule records a trigger, notifies by email and Telegram if requested and starts the rule's cooldown
+func (ars *AlertRuleService) fireRule(rule *models.AlertRule, symbol string, values map[string]float64, now time.Time) (*models.AlertTrigger, error) {
+	trigger := &models.AlertTrigger{
+		RuleID:      rule.ID,
+		RuleName:    rule.Name,
+		Symbol:      symbol,
+		Message:     fmt.Sprintf("%s: %s triggered (%s)", symbol, rule.Name, rule.Describe()),
+		Values:      ars.conditionValues(rule, values),
+		TriggeredAt: now,
+	}
+
+	log.Printf("Alert rule triggered: %s", trigger.Message)
+
+	ars.notifications.Notify(&models.Notification{
+		Category: models.NotificationAlert,
+		Severity: EmailSeverityMedium,
+		Symbol:   symbol,
+		Title:    fmt.Sprintf("🔔 %s: %s", symbol, rule.Name),
+		Message:  trigger.Message,
+	})
+
+	if rule.NotifyEmail && ars.emailService != nil && ars.emailService.CanNotify() {
+		err := ars.emailService.SendAlert(&EmailAlert{
+			Type:     EmailAlertRule,
+			Severity: EmailSeverityMedium,
+			Subject:  fmt.Sprintf("🔔 %s: %s", symbol, rule.Name),
+			Data:     trigger,
+			Telegram: true,
+		})
+		if err != nil {
+			log.Printf("Failed to send alert rule email: %v", err)
+		} else {
+			trigger.EmailSent = ars.emailService.IsConfigured()
+		}
+	}
+
+	if err := ars.db.InsertAlertTrigger(trigger); err != nil {
+		return nil, err
+	}
+
+	rule.LastTriggeredAt = &now
+	if err := ars.db.UpdateAlertRule(rule); err != nil {
+		return nil, err
+	}
+
+	return trigger, nil
+}
+
+// advanceSequence moves a sequenced rule's progress on a symbol on and stores it, returning the values of every
+// leg's conditions when the last leg matches, or nil
+func (ars *AlertRuleService) advanceSequence(rule *models.AlertRule, symbol string, state *models.AlertRuleState, values map[string]float64, now time.Time) (map[string]float64, error) {
+	next, fired := nextSequenceState(rule, symbol, state, values, now)
+	switch {
+	case fired || (next == nil && state != nil):
+		if err := ars.db.DeleteAlertRuleState(rule.ID, symbol); err != nil {
+			return nil, err
+		}
+	case next != nil && (state == nil || next.Step != state.Step || !next.StepAt.Equal(state.StepAt)):
+		if err := ars.db.UpsertAlertRuleState(next); err != nil {
+			return nil, err
+		}
+	}
+
+	if !fired {
+		return nil, nil
+	}
+	return next.Values, nil
+}
+
+// nextSequenceState steps a rule instance's sequence: a sequence whose next leg's window has passed starts over,
+// the first leg starts one, the next leg advances it and, while waiting, the previous leg matching again restarts
+// the window. It returns the new state, nil when no sequence is in progress, and whether the last leg matched.
+func nextSequenceState(rule *models.AlertRule, symbol string, state *models.AlertRuleState, values map[string]float64, now time.Time) (*models.AlertRuleState, bool) {
+	legs := rule.Legs()
+	if state != nil && (state.Step < 1 || state.Step >= len(legs)) {
+		state = nil
+	}
+	if state != nil {
+		if deadline := rule.Deadline(state); deadline != nil && now.After(*deadline) {
+			state = nil
+		}
+	}
+
+	if state == nil {
+		if !legs[0].Evaluate(values) {
+			return nil, false
+		}
+		started := &models.AlertRuleState{
+			RuleID: rule.ID, Symbol: symbol, Step: 1, StartedAt: now, StepAt: now,
+			Values: legValues(legs[0], values, nil),
+		}
+		return started, len(legs) == 1
+	}
+
+	next := *state
+	switch {
+	case legs[state.Step].Evaluate(values):
+		next.Values = legValues(legs[state.Step], values, state.Values)
+		next.Step++
+		next.StepAt = now
+	case legs[state.Step-1].Evaluate(values):
+		next.Values = legValues(legs[state.Step-1], values, state.Values)
+		next.StepAt = now
+	}
+	return &next, next.Step == len(legs)
+}
+
+// legValues adds the values of the fields a leg's conditions reference to a copy of earlier legs' values
+func legValues(leg models.AlertStep, values, earlier map[string]float64) map[string]float64 {
+	result := make(map[string]float64, len(earlier))
+	for field, value := range earlier {
+		result[field] = value
+	}
+	for _, condition := range leg.Conditions {
+		for _, field := range []string{condition.Field, condition.ValueField} {
+			if value, ok := values[field]; ok && field != "" {
+				result[field] = value
+			}
+		}
+	}
+	return result
+}
+
+// conditionValues returns the values of the fields referenced by a rule's conditions and sequenced legs
+func (ars *AlertRuleService) conditionValues(rule *models.AlertRule, values map[string]float64) map[string]float64 {
+	result := make(map[string]float64)
+	for _, leg := range rule.Legs() {
+		result = legValues(leg, values, result)
+	}
+	return result
+}
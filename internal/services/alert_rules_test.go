@@ -0,0 +1,239 @@
+package services
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// reclaimRule is "RSI < 30, then within 2 hours price reclaims VWAP"
+func reclaimRule() *models.AlertRule {
+	return &models.AlertRule{
+		ID: 1, Name: "Oversold reclaim", Logic: models.AlertLogicAnd,
+		Conditions: []models.AlertCondition{{Field: models.AlertFieldRSI14, Operator: "<", Value: 30}},
+		Then: []models.AlertStep{{
+			Conditions:    []models.AlertCondition{{Field: models.AlertFieldPrice, Operator: ">", ValueField: models.AlertFieldVWAP}},
+			Logic:         models.AlertLogicAnd,
+			WithinMinutes: 120,
+		}},
+	}
+}
+
+// TestNextSequenceState tests legs match in order within their windows
+func TestNextSequenceState(t *testing.T) {
+	rule := reclaimRule()
+	if err := rule.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	start := time.Date(2025, time.March, 4, 15, 0, 0, 0, time.UTC)
+	oversold := map[string]float64{models.AlertFieldRSI14: 25, models.AlertFieldPrice: 95, models.AlertFieldVWAP: 100}
+	waiting := map[string]float64{models.AlertFieldRSI14: 35, models.AlertFieldPrice: 98, models.AlertFieldVWAP: 100}
+	reclaimed := map[string]float64{models.AlertFieldRSI14: 40, models.AlertFieldPrice: 101, models.AlertFieldVWAP: 100}
+
+	// The reclaim alone doesn't fire
+	if state, fired := nextSequenceState(rule, "TEST", nil, reclaimed, start); state != nil || fired {
+		t.Fatalf("expected nothing before the first leg, got %+v fired %v", state, fired)
+	}
+
+	state, fired := nextSequenceState(rule, "TEST", nil, oversold, start)
+	if state == nil || fired || state.Step != 1 || state.Values[models.AlertFieldRSI14] != 25 {
+		t.Fatalf("expected the first leg to start a sequence, got %+v", state)
+	}
+
+	// Waiting keeps the window from the first leg
+	waited, fired := nextSequenceState(rule, "TEST", state, waiting, start.Add(time.Hour))
+	if fired || waited.Step != 1 || !waited.StepAt.Equal(start) {
+		t.Fatalf("expected the sequence to keep waiting, got %+v", waited)
+	}
+	if deadline := rule.Deadline(waited); deadline == nil || !deadline.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("expected the window to close two hours after the first leg, got %v", deadline)
+	}
+
+	done, fired := nextSequenceState(rule, "TEST", waited, reclaimed, start.Add(90*time.Minute))
+	if !fired || done.Step != 2 {
+		t.Fatalf("expected the reclaim within the window to fire, got %+v", done)
+	}
+	if done.Values[models.AlertFieldRSI14] != 25 || done.Values[models.AlertFieldPrice] != 101 || done.Values[models.AlertFieldVWAP] != 100 {
+		t.Errorf("expected each leg's values as it matched, got %v", done.Values)
+	}
+
+	// Too late: the sequence is dropped
+	if late, fired := nextSequenceState(rule, "TEST", state, reclaimed, start.Add(3*time.Hour)); late != nil || fired {
+		t.Errorf("expected an expired sequence to end, got %+v fired %v", late, fired)
+	}
+
+	// Still oversold restarts the window
+	renewed, _ := nextSequenceState(rule, "TEST", state, oversold, start.Add(110*time.Minute))
+	if renewed.Step != 1 || !renewed.StepAt.Equal(start.Add(110*time.Minute)) {
+		t.Fatalf("expected the first leg matching again to restart the window, got %+v", renewed)
+	}
+	if _, fired := nextSequenceState(rule, "TEST", renewed, reclaimed, start.Add(3*time.Hour)); !fired {
+		t.Error("expected the reclaim within the restarted window to fire")
+	}
+
+	if got, want := rule.Describe(), "rsi_14 < 30.00, then within 120m: price > vwap"; got != want {
+		t.Errorf("expected description %q, got %q", want, got)
+	}
+}
+
+// TestAlertSequencePersisted tests sequenced rules and their progress survive a restart
+func TestAlertSequencePersisted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.db")
+	db := openTestDB(t, path)
+
+	rule := reclaimRule()
+	if err := db.InsertAlertRule(rule); err != nil {
+		t.Fatalf("InsertAlertRule failed: %v", err)
+	}
+
+	start := time.Date(2025, time.March, 4, 15, 0, 0, 0, time.UTC)
+	oversold := map[string]float64{models.AlertFieldRSI14: 25, models.AlertFieldPrice: 95, models.AlertFieldVWAP: 100}
+	if fired, err := NewAlertRuleService(db, nil, nil).advanceSequence(rule, "TEST", nil, oversold, start); err != nil || fired != nil {
+		t.Fatalf("expected the first leg stored without firing, got %v (%v)", fired, err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	db = openTestDB(t, path)
+
+	stored, err := db.GetAlertRuleByID(rule.ID)
+	if err != nil || stored == nil || len(stored.Then) != 1 || stored.Then[0].Conditions[0].ValueField != models.AlertFieldVWAP {
+		t.Fatalf("expected the sequenced rule back, got %+v (%v)", stored, err)
+	}
+	states, err := db.GetAlertRuleStates(rule.ID)
+	if err != nil || len(states) != 1 || states[0].Step != 1 || states[0].Symbol != "TEST" {
+		t.Fatalf("expected the sequence in progress back, got %+v (%v)", states, err)
+	}
+
+	reclaimed := map[string]float64{models.AlertFieldRSI14: 40, models.AlertFieldPrice: 101, models.AlertFieldVWAP: 100}
+	fired, err := NewAlertRuleService(db, nil, nil).advanceSequence(stored, "TEST", states[0], reclaimed, start.Add(time.Hour))
+	if err != nil || fired[models.AlertFieldRSI14] != 25 || fired[models.AlertFieldPrice] != 101 {
+		t.Fatalf("expected the sequence to fire after the restart, got %v (%v)", fired, err)
+	}
+	if states, err := db.GetAlertRuleStates(rule.ID); err != nil || len(states) != 0 {
+		t.Errorf("expected the fired sequence removed, got %+v (%v)", states, err)
+	}
+}
+
+// TestAlertRuleValidate tests rules are checked and their logic normalized
+func TestAlertRuleValidate(t *testing.T) {
+	rsi := models.AlertCondition{Field: models.AlertFieldRSI14, Operator: "<", Value: 30}
+	tests := []struct {
+		name    string
+		rule    models.AlertRule
+		wantErr bool
+	}{
+		{"valid", models.AlertRule{Name: "Oversold", Conditions: []models.AlertCondition{rsi}}, false},
+		{"missing name", models.AlertRule{Name: " ", Conditions: []models.AlertCondition{rsi}}, true},
+		{"no conditions", models.AlertRule{Name: "Empty"}, true},
+		{"invalid logic", models.AlertRule{Name: "Xor", Logic: "XOR", Conditions: []models.AlertCondition{rsi}}, true},
+		{"negative cooldown", models.AlertRule{Name: "Neg", CooldownMinutes: -1, Conditions: []models.AlertCondition{rsi}}, true},
+		{"unknown field", models.AlertRule{Name: "Field", Conditions: []models.AlertCondition{{Field: "beta", Operator: "<", Value: 1}}}, true},
+		{"unknown value field", models.AlertRule{Name: "Ref", Conditions: []models.AlertCondition{{Field: models.AlertFieldPrice, Operator: ">", ValueField: "sma_7"}}}, true},
+		{"invalid operator", models.AlertRule{Name: "Op", Conditions: []models.AlertCondition{{Field: models.AlertFieldPrice, Operator: "!=", Value: 1}}}, true},
+		{"empty leg", models.AlertRule{Name: "Leg", Conditions: []models.AlertCondition{rsi}, Then: []models.AlertStep{{}}}, true},
+		{"negative window", models.AlertRule{Name: "Window", Conditions: []models.AlertCondition{rsi}, Then: []models.AlertStep{{Conditions: []models.AlertCondition{rsi}, WithinMinutes: -5}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	rule := models.AlertRule{Name: "Normalized", Symbol: " aapl ", Logic: "or", Conditions: []models.AlertCondition{rsi},
+		Then: []models.AlertStep{{Conditions: []models.AlertCondition{rsi}}}}
+	if err := rule.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if rule.Logic != models.AlertLogicOr || rule.Then[0].Logic != models.AlertLogicAnd || rule.Symbol != "AAPL" {
+		t.Errorf("expected OR logic, an AND leg and symbol AAPL, got %q, %q and %q", rule.Logic, rule.Then[0].Logic, rule.Symbol)
+	}
+}
+
+// TestAlertRuleEvaluate tests AND/OR logic, field comparisons and missing fields
+func TestAlertRuleEvaluate(t *testing.T) {
+	oversold := models.AlertCondition{Field: models.AlertFieldRSI14, Operator: "<", Value: 30}
+	aboveVWAP := models.AlertCondition{Field: models.AlertFieldPrice, Operator: ">", ValueField: models.AlertFieldVWAP}
+	tests := []struct {
+		name   string
+		logic  string
+		values map[string]float64
+		want   bool
+	}{
+		{"and both", models.AlertLogicAnd, map[string]float64{models.AlertFieldRSI14: 25, models.AlertFieldPrice: 101, models.AlertFieldVWAP: 100}, true},
+		{"and one", models.AlertLogicAnd, map[string]float64{models.AlertFieldRSI14: 25, models.AlertFieldPrice: 99, models.AlertFieldVWAP: 100}, false},
+		{"or one", models.AlertLogicOr, map[string]float64{models.AlertFieldRSI14: 45, models.AlertFieldPrice: 101, models.AlertFieldVWAP: 100}, true},
+		{"or none", models.AlertLogicOr, map[string]float64{models.AlertFieldRSI14: 45, models.AlertFieldPrice: 99, models.AlertFieldVWAP: 100}, false},
+		{"missing value field", models.AlertLogicOr, map[string]float64{models.AlertFieldRSI14: 45, models.AlertFieldPrice: 101}, false},
+		{"missing field", models.AlertLogicAnd, map[string]float64{models.AlertFieldPrice: 101, models.AlertFieldVWAP: 100}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := models.AlertRule{Logic: tt.logic, Conditions: []models.AlertCondition{oversold, aboveVWAP}}
+			if got := rule.Evaluate(tt.values); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestAlertRuleCooldownPerSymbol tests a rule on every watched symbol fires on each symbol in the same cycle and
+// cools down per symbol
+func TestAlertRuleCooldownPerSymbol(t *testing.T) {
+	db := newTestDB(t)
+
+	start := time.Date(2025, time.March, 4, 15, 0, 0, 0, time.UTC)
+	bars := make([]*models.PriceData, 0)
+	for _, symbol := range []string{"AAA", "BBB"} {
+		for i := 0; i < 40; i++ {
+			price := 100 + float64(i%5)
+			bars = append(bars, &models.PriceData{Symbol: symbol, Timestamp: start.Add(time.Duration(i-40) * time.Minute),
+				Open: price, High: price + 1, Low: price - 1, Close: price, Volume: 1000})
+		}
+	}
+	if err := db.InsertPriceDataBatch(bars); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+
+	rule := &models.AlertRule{Name: "Above 50", Logic: models.AlertLogicAnd, IsActive: true, CooldownMinutes: 60,
+		Conditions: []models.AlertCondition{{Field: models.AlertFieldPrice, Operator: ">", Value: 50}}}
+	if err := db.InsertAlertRule(rule); err != nil {
+		t.Fatalf("InsertAlertRule failed: %v", err)
+	}
+
+	clock := NewSimulatedClock(start)
+	SetClock(clock)
+	t.Cleanup(func() { SetClock(nil) })
+
+	service := NewAlertRuleService(db, NewTechnicalAnalysisService(db, nil), nil)
+	triggers, err := service.EvaluateRules([]string{"AAA", "BBB"})
+	if err != nil {
+		t.Fatalf("EvaluateRules failed: %v", err)
+	}
+	if len(triggers) != 2 || triggers[0].Symbol != "AAA" || triggers[1].Symbol != "BBB" {
+		t.Fatalf("expected the rule to fire on both symbols, got %+v", triggers)
+	}
+
+	clock.Set(start.Add(30 * time.Minute))
+	if triggers, err := service.EvaluateRules([]string{"AAA", "BBB"}); err != nil || len(triggers) != 0 {
+		t.Fatalf("expected both symbols cooling down, got %+v (%v)", triggers, err)
+	}
+
+	// A symbol fired later cools down on its own schedule
+	if err := db.InsertAlertTrigger(&models.AlertTrigger{RuleID: rule.ID, RuleName: rule.Name, Symbol: "BBB",
+		Values: map[string]float64{}, TriggeredAt: clockNow()}); err != nil {
+		t.Fatalf("InsertAlertTrigger failed: %v", err)
+	}
+	clock.Set(start.Add(75 * time.Minute))
+	triggers, err = service.EvaluateRules([]string{"AAA", "BBB"})
+	if err != nil || len(triggers) != 1 || triggers[0].Symbol != "AAA" {
+		t.Errorf("expected only AAA past its cooldown, got %+v (%v)", triggers, err)
+	}
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// Alpaca trading API endpoints
+const (
+	BrokerAlpaca   = "alpaca"
+	alpacaPaperURL = "https://paper-api.alpaca.markets"
+	alpacaLiveURL  = "https://api.alpaca.markets"
+)
+
+// AlpacaExecutor places bracket orders through the Alpaca trading API
+type AlpacaExecutor struct {
+	client    *http.Client
+	baseURL   string
+	apiKey    string
+	apiSecret string
+	live      bool
+}
+
+// alpacaOrder is an order as returned by the Alpaca API; a bracket order's stop and take profit are its legs
+type alpacaOrder struct {
+	ID             string        `json:"id"`
+	ClientOrderID  string        `json:"client_order_id"`
+	Status         string        `json:"status"`
+	Type           string        `json:"type"`
+	FilledAvgPrice string        `json:"filled_avg_price"`
+	FilledAt       *time.Time    `json:"filled_at"`
+	Legs           []alpacaOrder `json:"legs"`
+}
+
+// NewAlpacaExecutor creates an executor for the paper account, or the live account when configured
+func NewAlpacaExecutor(cfg *config.AlpacaConfig) *AlpacaExecutor {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = alpacaPaperURL
+		if cfg.Live {
+			baseURL = alpacaLiveURL
+		}
+	}
+
+	return &AlpacaExecutor{
+		client:    &http.Client{Timeout: timeout},
+		baseURL:   baseURL,
+		apiKey:    cfg.APIKey,
+		apiSecret: cfg.APISecret,
+		live:      cfg.Live,
+	}
+}
+
+// Name returns the broker identifier
+func (ae *AlpacaExecutor) Name() string {
+	return BrokerAlpaca
+}
+
+// IsLive reports whether orders go to the live account
+func (ae *AlpacaExecutor) IsLive() bool {
+	return ae.live
+}
+
+// PlaceBracketOrder submits a limit entry with a stop loss and take profit attached
+func (ae *AlpacaExecutor) PlaceBracketOrder(ctx context.Context, request *models.BracketOrderRequest) (*models.BrokerOrderState, error) {
+	side := "buy"
+	if request.Side == models.PositionSideShort {
+		side = "sell"
+	}
+
+	body := map[string]interface{}{
+		"symbol":          request.Symbol,
+		"qty":             strconv.FormatFloat(request.Quantity, 'f', -1, 64),
+		"side":            side,
+		"type":            "limit",
+		"time_in_force":   request.TimeInForce,
+		"limit_price":     alpacaPrice(request.EntryPrice),
+		"order_class":     "bracket",
+		"client_order_id": request.ClientOrderID,
+		"take_profit":     map[string]string{"limit_price": alpacaPrice(request.TargetPrice)},
+		"stop_loss":       map[string]string{"stop_price": alpacaPrice(request.StopLoss)},
+	}
+
+	var order alpacaOrder
+	if err := ae.do(ctx, http.MethodPost, "/v2/orders", body, &order); err != nil {
+		return nil, fmt.Errorf("failed to place %s bracket order: %w", request.Symbol, err)
+	}
+	return order.state(), nil
+}
+
+// GetOrder returns the state of a bracket order and its legs
+func (ae *AlpacaExecutor) GetOrder(ctx context.Context, brokerOrderID string) (*models.BrokerOrderState, error) {
+	var order alpacaOrder
+	if err := ae.do(ctx, http.MethodGet, "/v2/orders/"+url.PathEscape(brokerOrderID)+"?nested=true", nil, &order); err != nil {
+		return nil, fmt.Errorf("failed to get order %s: %w", brokerOrderID, err)
+	}
+	return order.state(), nil
+}
+
+// CancelOrder cancels an order; canceling a bracket's entry cancels its legs too
+func (ae *AlpacaExecutor) CancelOrder(ctx context.Context, brokerOrderID string) error {
+	if err := ae.do(ctx, http.MethodDelete, "/v2/orders/"+url.PathEscape(brokerOrderID), nil, nil); err != nil {
+		return fmt.Errorf("failed to cancel order %s: %w", brokerOrderID, err)
+	}
+	return nil
+}
+
+// do sends an authenticated request and decodes the response into out, when given
+func (ae *AlpacaExecutor) do(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, ae.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("APCA-API-KEY-ID", ae.apiKey)
+	req.Header.Set("APCA-API-SECRET-KEY", ae.apiSecret)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := ae.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: failed to make request: %w", ErrUpstreamUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		message := string(raw)
+		if json.Unmarshal(raw, &apiErr) == nil && apiErr.Message != "" {
+			message = apiErr.Message
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			return fmt.Errorf("%w: %s", ErrUpstreamRateLimited, message)
+		case resp.StatusCode == http.StatusUnprocessableEntity || resp.StatusCode == http.StatusForbidden:
+			// Invalid orders, insufficient buying power and orders that can no longer be canceled
+			return fmt.Errorf("%w: alpaca rejected the request: %s", ErrValidation, message)
+		default:
+			return fmt.Errorf("%w: alpaca request failed with status %d: %s", ErrUpstreamUnavailable, resp.StatusCode, message)
+		}
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// state maps an Alpaca bracket order to a broker order state: the entry's fill, and the fill of whichever
+// leg closed the position
+func (o *alpacaOrder) state() *models.BrokerOrderState {
+	state := &models.BrokerOrderState{BrokerOrderID: o.ID, Status: models.BrokerOrderSubmitted}
+
+	switch o.Status {
+	case "filled":
+		state.Status = models.BrokerOrderFilled
+		state.FilledPrice, _ = strconv.ParseFloat(o.FilledAvgPrice, 64)
+		state.FilledAt = o.FilledAt
+	case "canceled", "expired", "rejected", "suspended":
+		state.Status = models.BrokerOrderCanceled
+		state.Reason = o.Status
+		return state
+	default:
+		// new, accepted, pending_new, partially_filled and the like are still working
+		return state
+	}
+
+	for _, leg := range o.Legs {
+		if leg.Status != "filled" {
+			continue
+		}
+		state.Status = models.BrokerOrderClosed
+		state.ExitPrice, _ = strconv.ParseFloat(leg.FilledAvgPrice, 64)
+		state.ExitAt = leg.FilledAt
+		state.Reason = "take_profit"
+		if leg.Type == "stop" || leg.Type == "stop_limit" {
+			state.Reason = "stop_loss"
+		}
+	}
+	return state
+}
+
+// alpacaPrice formats a price with the decimals Alpaca accepts: cents from $1, sub-penny below
+func alpacaPrice(price float64) string {
+	if price >= 1 {
+		return strconv.FormatFloat(price, 'f', 2, 64)
+	}
+	return strconv.FormatFloat(price, 'f', 4, 64)
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// Anomaly detection defaults
+const (
+	DefaultAnomalyVolumeZScore = 4.0
+	DefaultAnomalyLookbackBars = 100
+	DefaultAnomalyGapPercent   = 3.0
+	DefaultAnomalyHaltMinutes  = 10
+	DefaultAnomalyMinAvgVolume = 1000
+
+	// anomalyScanBars is how many of the latest bars each scan checks; bars flagged before are skipped
+	anomalyScanBars = 300
+	// anomalyRecentWindow is the window of the recent_anomalies alert field
+	anomalyRecentWindow = time.Hour
+)
+
+// AnomalyService flags statistically unusual bars: volume far above the prior bars, opens far from the previous
+// close and stretches of the regular session without bars, as in a trading halt
+type AnomalyService struct {
+	db            *database.Database
+	calendar      *MarketCalendar
+	notifications *NotificationService
+	enabled       bool
+	volumeZScore  float64
+	lookbackBars  int
+	gapPercent    float64
+	haltMinutes   int
+	minAvgVolume  float64
+}
+
+// NewAnomalyService creates a new anomaly detector
+func NewAnomalyService(cfg *config.Config, db *database.Database, calendar *MarketCalendar) *AnomalyService {
+	anomaliesCfg := cfg.Anomalies
+
+	as := &AnomalyService{
+		db:           db,
+		calendar:     calendar,
+		enabled:      anomaliesCfg.Enabled,
+		volumeZScore: anomaliesCfg.VolumeZScore,
+		lookbackBars: anomaliesCfg.LookbackBars,
+		gapPercent:   anomaliesCfg.GapPercent,
+		haltMinutes:  anomaliesCfg.HaltMinutes,
+		minAvgVolume: float64(anomaliesCfg.MinAvgVolume),
+	}
+
+	if as.volumeZScore <= 0 {
+		as.volumeZScore = DefaultAnomalyVolumeZScore
+	}
+	if as.lookbackBars <= 0 {
+		as.lookbackBars = DefaultAnomalyLookbackBars
+	}
+	if as.gapPercent <= 0 {
+		as.gapPercent = DefaultAnomalyGapPercent
+	}
+	if as.haltMinutes <= 0 {
+		as.haltMinutes = DefaultAnomalyHaltMinutes
+	}
+	if as.minAvgVolume <= 0 {
+		as.minAvgVolume = DefaultAnomalyMinAvgVolume
+	}
+
+	return as
+}
+
+// SetNotificationService records newly flagged anomalies in the notification center
+func (as *AnomalyService) SetNotificationService(notifications *NotificationService) {
+	as.notifications = notifications
+}
+
+// IsEnabled checks if bars are scanned after each collection cycle
+func (as *AnomalyService) IsEnabled() bool {
+	return as != nil && as.enabled
+}
+
+// ScanSymbols scans the latest bars of each symbol and returns the anomalies flagged for the first time
+func (as *AnomalyService) ScanSymbols(symbols []string) ([]*models.MarketAnomaly, error) {
+	flagged := make([]*models.MarketAnomaly, 0)
+	var lastErr error
+	for _, symbol := range symbols {
+		anomalies, err := as.Scan(symbol)
+		if err != nil {
+			log.Printf("Failed to scan %s for anomalies: %v", symbol, err)
+			lastErr = err
+			continue
+		}
+		flagged = append(flagged, anomalies...)
+	}
+
+	if len(flagged) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return flagged, nil
+}
+
+// Scan checks a symbol's latest bars for anomalies, stores them and notifies about the ones not flagged before
+func (as *AnomalyService) Scan(symbol string) ([]*models.MarketAnomaly, error) {
+	bars, err := as.db.GetPriceDataForAnalysis(symbol, as.lookbackBars+anomalyScanBars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price data: %w", err)
+	}
+
+	start := max(len(bars)-anomalyScanBars, 1)
+	flagged := make([]*models.MarketAnomaly, 0)
+	for _, anomaly := range as.detect(symbol, bars, start) {
+		inserted, err := as.db.InsertMarketAnomaly(anomaly)
+		if err != nil {
+			return flagged, err
+		}
+		if !inserted {
+			continue
+		}
+
+		flagged = append(flagged, anomaly)
+		log.Printf("Anomaly: %s", anomaly.Message)
+		as.notifications.Notify(&models.Notification{
+			Category: models.NotificationAnomaly,
+			Severity: anomaly.Severity,
+			Symbol:   symbol,
+			Title:    fmt.Sprintf("⚡ %s: %s", symbol, strings.ReplaceAll(anomaly.Type, "_", " ")),
+			Message:  anomaly.Message,
+			Link:     "/api/anomalies?symbol=" + symbol,
+		})
+	}
+
+	return flagged, nil
+}
+
+// FieldValues returns the alert fields of a symbol: its latest bar's volume z-score, when the prior bars are
+// enough to take one, and the anomalies flagged on its bars of the last hour
+func (as *AnomalyService) FieldValues(symbol string) (map[string]float64, error) {
+	values := make(map[string]float64)
+
+	bars, err := as.db.GetPriceDataForAnalysis(symbol, as.lookbackBars+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price data: %w", err)
+	}
+	if z, _, ok := volumeZScore(bars, len(bars)-1, as.lookbackBars); ok {
+		values[models.AlertFieldVolumeZScore] = z
+	}
+
+	recent, err := as.db.CountMarketAnomalies(symbol, clockNow().Add(-anomalyRecentWindow))
+	if err != nil {
+		return nil, err
+	}
+	values[models.AlertFieldRecentAnomalies] = float64(recent)
+
+	return values, nil
+}
+
+// detect flags the anomalies of the bars from index start on, comparing each with the bars before it. Bars
+// must be oldest first.
+func (as *AnomalyService) detect(symbol string, bars []*models.PriceData, start int) []*models.MarketAnomaly {
+	var anomalies []*models.MarketAnomaly
+	newAnomaly := func(bar *models.PriceData, anomalyType string, value, threshold float64, message string) {
+		severity := EmailSeverityMedium
+		if math.Abs(value) >= 2*threshold {
+			severity = EmailSeverityHigh
+		}
+		anomalies = append(anomalies, &models.MarketAnomaly{
+			Symbol:    symbol,
+			Type:      anomalyType,
+			Severity:  severity,
+			BarTime:   bar.Timestamp,
+			Value:     math.Round(value*100) / 100,
+			Threshold: threshold,
+			Price:     bar.Close,
+			Message:   message,
+		})
+	}
+
+	for i := max(start, 1); i < len(bars); i++ {
+		bar, prev := bars[i], bars[i-1]
+
+		z, mean, ok := volumeZScore(bars, i, as.lookbackBars)
+		if ok && mean >= as.minAvgVolume && z >= as.volumeZScore {
+			newAnomaly(bar, models.AnomalyVolumeSpike, z, as.volumeZScore,
+				fmt.Sprintf("%s traded %d shares at %s, %.1f standard deviations above the %.0f share average",
+					symbol, bar.Volume, bar.Timestamp.In(as.calendar.Location()).Format("Jan 2 15:04"), z, mean))
+		}
+
+		if prev.Close > 0 {
+			gap := (bar.Open - prev.Close) / prev.Close * 100
+			if math.Abs(gap) >= as.gapPercent {
+				newAnomaly(bar, models.AnomalyPriceGap, gap, as.gapPercent,
+					fmt.Sprintf("%s gapped %+.1f%% from $%.2f to $%.2f at %s",
+						symbol, gap, prev.Close, bar.Open, bar.Timestamp.In(as.calendar.Location()).Format("Jan 2 15:04")))
+			}
+		}
+
+		// Only missing bars inside one regular session look like a halt; ok also rules out the
+		// overnight and weekend breaks. Thinly traded symbols routinely skip bars.
+		if missing := as.sessionGapMinutes(prev.Timestamp, bar.Timestamp); missing >= float64(as.haltMinutes) && ok && mean >= as.minAvgVolume {
+			newAnomaly(bar, models.AnomalyHalt, missing, float64(as.haltMinutes),
+				fmt.Sprintf("%s had no bars for %.0f minutes before %s, a potential trading halt",
+					symbol, missing, bar.Timestamp.In(as.calendar.Location()).Format("Jan 2 15:04")))
+		}
+	}
+
+	return anomalies
+}
+
+// sessionGapMinutes returns the minutes without bars between two bars of the same regular session, or 0 when
+// they fall in different sessions
+func (as *AnomalyService) sessionGapMinutes(prev, next time.Time) float64 {
+	open, closeAt, ok := as.calendar.RegularSession(prev)
+	if !ok || prev.Before(open) || !next.Before(closeAt) {
+		return 0
+	}
+	return next.Sub(prev).Minutes() - models.CoverageIntervalMinutes
+}
+
+// volumeZScore returns how many standard deviations bar i's volume is above the mean of up to lookback bars
+// before it, with that mean. ok is false when fewer than half the lookback bars precede it or their volume
+// never varies.
+func volumeZScore(bars []*models.PriceData, i, lookback int) (z, mean float64, ok bool) {
+	if i < 0 || i >= len(bars) {
+		return 0, 0, false
+	}
+	prior := bars[max(i-lookback, 0):i]
+	if len(prior) < lookback/2 || len(prior) < 2 {
+		return 0, 0, false
+	}
+
+	for _, bar := range prior {
+		mean += float64(bar.Volume)
+	}
+	mean /= float64(len(prior))
+
+	variance := 0.0
+	for _, bar := range prior {
+		variance += math.Pow(float64(bar.Volume)-mean, 2)
+	}
+	std := math.Sqrt(variance / float64(len(prior)))
+	if std == 0 {
+		return 0, mean, false
+	}
+
+	return (float64(bars[i].Volume) - mean) / std, mean, true
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// TestAnomalyScan tests that a volume spike, an opening gap and a stretch of missing bars are each flagged
+// once, and that scanning again flags nothing new
+func TestAnomalyScan(t *testing.T) {
+	cfg := &config.Config{}
+	db := newTestDB(t)
+
+	// 5-minute bars from the 9:30 ET open, with no bars for 20 minutes after the 60th
+	open := time.Date(2025, 3, 4, 14, 30, 0, 0, time.UTC)
+	var bars []*models.PriceData
+	price := 100.0
+	for i := 0; i < 70; i++ {
+		if i >= 60 && i < 63 {
+			continue
+		}
+		bar := &models.PriceData{
+			Symbol: "TEST", Timestamp: open.Add(time.Duration(i) * 5 * time.Minute),
+			Open: price, High: price, Low: price, Close: price, Volume: 1000 + int64(i%2)*200,
+		}
+		switch i {
+		case 40:
+			bar.Volume = 5000
+		case 50:
+			price = 103.5
+			bar.Open, bar.High, bar.Close = price, price, price
+		}
+		bars = append(bars, bar)
+	}
+	if err := db.InsertPriceDataBatch(bars); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+
+	cfg.Anomalies = config.AnomaliesConfig{Enabled: true, LookbackBars: 20}
+	anomalies := NewAnomalyService(cfg, db, NewMarketCalendar(cfg.MarketHours))
+	anomalies.SetNotificationService(NewNotificationService(db))
+
+	flagged, err := anomalies.ScanSymbols([]string{"TEST"})
+	if err != nil {
+		t.Fatalf("ScanSymbols failed: %v", err)
+	}
+	byType := make(map[string]*models.MarketAnomaly)
+	for _, anomaly := range flagged {
+		byType[anomaly.Type] = anomaly
+	}
+	if len(flagged) != 3 || len(byType) != 3 {
+		t.Fatalf("expected one anomaly of each type, got %d: %+v", len(flagged), byType)
+	}
+
+	if spike := byType[models.AnomalyVolumeSpike]; !spike.BarTime.Equal(bars[40].Timestamp) || spike.Value < 4 {
+		t.Errorf("unexpected volume spike: %+v", spike)
+	}
+	if gap := byType[models.AnomalyPriceGap]; !gap.BarTime.Equal(bars[50].Timestamp) || gap.Value != 3.5 {
+		t.Errorf("unexpected price gap: %+v", gap)
+	}
+	if halt := byType[models.AnomalyHalt]; !halt.BarTime.Equal(open.Add(63*5*time.Minute)) || halt.Value != 15 {
+		t.Errorf("unexpected halt: %+v", halt)
+	}
+
+	// Already flagged bars are neither stored nor notified again
+	if flagged, err := anomalies.Scan("TEST"); err != nil || len(flagged) != 0 {
+		t.Errorf("expected nothing new on a second scan, got %d, %v", len(flagged), err)
+	}
+	stored, err := db.GetMarketAnomalies(&models.AnomalyFilter{Symbol: "TEST"})
+	if err != nil || len(stored) != 3 || stored[0].Type != models.AnomalyHalt {
+		t.Errorf("expected 3 stored anomalies, the halt first, got %d, %v", len(stored), err)
+	}
+	notifications, err := db.GetNotifications(&models.NotificationFilter{Category: models.NotificationAnomaly})
+	if err != nil || len(notifications) != 3 {
+		t.Errorf("expected 3 anomaly notifications, got %d, %v", len(notifications), err)
+	}
+
+	values, err := anomalies.FieldValues("TEST")
+	if err != nil {
+		t.Fatalf("FieldValues failed: %v", err)
+	}
+	if _, ok := values[models.AlertFieldVolumeZScore]; !ok {
+		t.Errorf("expected a volume z-score, got %+v", values)
+	}
+}
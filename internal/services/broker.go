@@ -0,0 +1,451 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// Broker defaults
+const (
+	DefaultBrokerSyncInterval = time.Minute
+
+	// brokerMaxTriggerAge is how long after its entry bar a triggered setup may still place an order, so
+	// setups that triggered while collection was down don't chase a stale entry
+	brokerMaxTriggerAge = 30 * time.Minute
+)
+
+// OrderExecutor places and tracks bracket orders with a broker
+type OrderExecutor interface {
+	Name() string
+	PlaceBracketOrder(ctx context.Context, request *models.BracketOrderRequest) (*models.BrokerOrderState, error)
+	GetOrder(ctx context.Context, brokerOrderID string) (*models.BrokerOrderState, error)
+	CancelOrder(ctx context.Context, brokerOrderID string) error
+}
+
+// BrokerService places a bracket order when a setup of a strategy with auto trading on triggers, and syncs
+// order fills back into the portfolio: a filled entry opens a position, a filled stop or target closes it
+type BrokerService struct {
+	db          *database.Database
+	executor    OrderExecutor
+	risk        *RiskService
+	portfolio   *PortfolioService
+	enabled     bool
+	live        bool
+	timeInForce string
+	interval    time.Duration
+	orderMutex  sync.Mutex // serializes placing and syncing orders
+	statusMutex sync.RWMutex
+	lastSync    *time.Time
+	lastError   string
+	stop        chan struct{}
+	stopOnce    sync.Once
+	wg          sync.WaitGroup // tracks the background sync loop
+}
+
+// NewBrokerService creates a new broker service, connected to Alpaca when the broker is enabled
+func NewBrokerService(cfg *config.Config, db *database.Database, risk *RiskService, portfolio *PortfolioService) *BrokerService {
+	brokerCfg := cfg.Broker
+
+	bs := &BrokerService{
+		db:          db,
+		risk:        risk,
+		portfolio:   portfolio,
+		enabled:     brokerCfg.Enabled,
+		live:        brokerCfg.Alpaca.Live,
+		timeInForce: brokerCfg.TimeInForce,
+		interval:    brokerCfg.SyncInterval,
+		stop:        make(chan struct{}),
+	}
+
+	if bs.timeInForce == "" {
+		bs.timeInForce = "day"
+	}
+	if bs.interval <= 0 {
+		bs.interval = DefaultBrokerSyncInterval
+	}
+	if bs.enabled {
+		bs.executor = NewAlpacaExecutor(&brokerCfg.Alpaca)
+	}
+
+	return bs
+}
+
+// SetExecutor replaces the broker orders are placed with, e.g. with a stub in tests
+func (bs *BrokerService) SetExecutor(executor OrderExecutor) {
+	bs.executor = executor
+}
+
+// IsEnabled checks if orders are placed with a broker
+func (bs *BrokerService) IsEnabled() bool {
+	return bs != nil && bs.enabled && bs.executor != nil
+}
+
+// Start syncs open orders in the background when the broker is enabled
+func (bs *BrokerService) Start() {
+	if !bs.IsEnabled() {
+		log.Printf("Broker order placement disabled")
+		return
+	}
+
+	account := "paper"
+	if bs.live {
+		account = "LIVE"
+	}
+	log.Printf("Starting %s order sync on the %s account (every %v)...", bs.executor.Name(), account, bs.interval)
+
+	ticker := time.NewTicker(bs.interval)
+	bs.wg.Add(1)
+	go func() {
+		defer bs.wg.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := bs.SyncOrders(); err != nil {
+					log.Printf("Broker order sync failed: %v", err)
+				}
+			case <-bs.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background loop and waits for an in-flight sync to finish
+func (bs *BrokerService) Stop(ctx context.Context) error {
+	bs.stopOnce.Do(func() { close(bs.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		bs.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for broker order sync to finish: %w", ctx.Err())
+	}
+}
+
+// PlaceSetupOrder places a bracket order for a setup that triggered at triggeredAt when its stock belongs to a
+// strategy with auto trading on, sized by the risk settings with the setup's first target as take profit. It
+// returns nil without an order when the broker is disabled, no strategy trades the symbol, or the trigger is
+// too old. A setup gets at most one order; an order the broker refused is recorded as failed.
+func (bs *BrokerService) PlaceSetupOrder(setup *models.TradingSetup, triggeredAt time.Time) (*models.BrokerOrder, error) {
+	if !bs.IsEnabled() || setup.Status != models.SetupStatusTriggered {
+		return nil, nil
+	}
+	if clockNow().Sub(triggeredAt) > brokerMaxTriggerAge {
+		log.Printf("Not placing an order for setup %d for %s: it triggered at %s", setup.ID, setup.Symbol, triggeredAt.Format(time.RFC3339))
+		return nil, nil
+	}
+
+	strategies, err := bs.db.GetAutoTradeStrategiesForSymbol(setup.Symbol)
+	if err != nil || len(strategies) == 0 {
+		return nil, err
+	}
+
+	bs.orderMutex.Lock()
+	defer bs.orderMutex.Unlock()
+
+	existing, err := bs.db.GetBrokerOrderBySetupID(setup.ID)
+	if err != nil || existing != nil {
+		return existing, err
+	}
+
+	target := setup.Target1
+	if target <= 0 {
+		target = finalTarget(setup)
+	}
+	order := &models.BrokerOrder{
+		SetupID:       setup.ID,
+		StrategyID:    strategies[0],
+		Broker:        bs.executor.Name(),
+		ClientOrderID: fmt.Sprintf("market-watch-setup-%d", setup.ID),
+		Symbol:        setup.Symbol,
+		Side:          models.SideForDirection(setup.Direction),
+		EntryPrice:    setup.EntryPrice,
+		StopLoss:      setup.StopLoss,
+		TargetPrice:   target,
+		Status:        models.BrokerOrderSubmitted,
+	}
+
+	if reason := bs.sizeOrder(order, setup); reason != "" {
+		order.Status = models.BrokerOrderFailed
+		order.Error = reason
+		log.Printf("Not placing an order for setup %d for %s: %s", setup.ID, setup.Symbol, reason)
+		return order, bs.db.InsertBrokerOrder(order)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	state, err := bs.executor.PlaceBracketOrder(ctx, &models.BracketOrderRequest{
+		ClientOrderID: order.ClientOrderID,
+		Symbol:        order.Symbol,
+		Side:          order.Side,
+		Quantity:      order.Quantity,
+		EntryPrice:    order.EntryPrice,
+		StopLoss:      order.StopLoss,
+		TargetPrice:   order.TargetPrice,
+		TimeInForce:   bs.timeInForce,
+	})
+	if err != nil {
+		order.Status = models.BrokerOrderFailed
+		order.Error = err.Error()
+		if insertErr := bs.db.InsertBrokerOrder(order); insertErr != nil {
+			log.Printf("Failed to record failed order for setup %d: %v", setup.ID, insertErr)
+		}
+		return order, err
+	}
+
+	order.BrokerOrderID = state.BrokerOrderID
+	if err := bs.db.InsertBrokerOrder(order); err != nil {
+		return nil, err
+	}
+	log.Printf("Placed %s bracket order %s for setup %d: %s %.0f %s @ $%.2f, stop $%.2f, target $%.2f",
+		order.Broker, order.BrokerOrderID, setup.ID, order.Side, order.Quantity, order.Symbol, order.EntryPrice, order.StopLoss, order.TargetPrice)
+
+	// The entry may fill as it is placed
+	if err := bs.apply(order, state); err != nil {
+		return order, err
+	}
+	return order, nil
+}
+
+// SyncOrders refreshes every open order from the broker and applies its fills to the portfolio, returning
+// the number of orders whose status changed
+func (bs *BrokerService) SyncOrders() (int, error) {
+	if !bs.IsEnabled() {
+		return 0, fmt.Errorf("broker %w: enable it in the config", ErrUnavailable)
+	}
+
+	bs.orderMutex.Lock()
+	defer bs.orderMutex.Unlock()
+
+	orders, err := bs.db.GetBrokerOrders(&models.BrokerOrderFilter{Open: true})
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	var lastErr error
+	for _, order := range orders {
+		previous := order.Status
+		if err := bs.syncOrder(order); err != nil {
+			log.Printf("Failed to sync broker order %d for %s: %v", order.ID, order.Symbol, err)
+			lastErr = err
+			continue
+		}
+		if order.Status != previous {
+			changed++
+		}
+	}
+
+	now := time.Now()
+	bs.statusMutex.Lock()
+	bs.lastSync = &now
+	bs.lastError = ""
+	if lastErr != nil {
+		bs.lastError = lastErr.Error()
+	}
+	bs.statusMutex.Unlock()
+
+	return changed, nil
+}
+
+// CancelOrder cancels an order whose entry has not filled. Filled orders are managed at the broker, as
+// canceling their legs would leave the position unprotected.
+func (bs *BrokerService) CancelOrder(id int64) (*models.BrokerOrder, error) {
+	if !bs.IsEnabled() {
+		return nil, fmt.Errorf("broker %w: enable it in the config", ErrUnavailable)
+	}
+
+	bs.orderMutex.Lock()
+	defer bs.orderMutex.Unlock()
+
+	order, err := bs.db.GetBrokerOrderByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, fmt.Errorf("broker order %d %w", id, database.ErrNotFound)
+	}
+	if order.Status != models.BrokerOrderSubmitted {
+		return nil, fmt.Errorf("%w: order %d is %s; only orders waiting for their entry can be canceled", ErrValidation, id, order.Status)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := bs.executor.CancelOrder(ctx, order.BrokerOrderID); err != nil {
+		return nil, err
+	}
+
+	// The entry may have filled before the cancel arrived
+	if err := bs.syncOrder(order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// GetStatus describes the broker connection, the strategies that trade automatically and the last sync
+func (bs *BrokerService) GetStatus() (*models.BrokerStatus, error) {
+	strategies, err := bs.db.GetAutoTradeStrategyIDs()
+	if err != nil {
+		return nil, err
+	}
+	open, err := bs.db.GetBrokerOrders(&models.BrokerOrderFilter{Open: true})
+	if err != nil {
+		return nil, err
+	}
+
+	status := &models.BrokerStatus{
+		Enabled:             bs.IsEnabled(),
+		Live:                bs.live,
+		AutoTradeStrategies: strategies,
+		OpenOrders:          len(open),
+	}
+	if bs.executor != nil {
+		status.Broker = bs.executor.Name()
+	}
+
+	bs.statusMutex.RLock()
+	status.LastSync = bs.lastSync
+	status.LastError = bs.lastError
+	bs.statusMutex.RUnlock()
+
+	return status, nil
+}
+
+// sizeOrder sets the order's quantity from the risk settings, returning why it can't be placed if it can't
+func (bs *BrokerService) sizeOrder(order *models.BrokerOrder, setup *models.TradingSetup) string {
+	if order.EntryPrice <= 0 || order.StopLoss <= 0 || order.TargetPrice <= 0 {
+		return "the setup has no entry, stop and target"
+	}
+
+	heat, err := bs.risk.PortfolioHeat()
+	if err != nil {
+		return fmt.Sprintf("failed to get portfolio heat: %v", err)
+	}
+	if heat.OverLimit && heat.BlockOverHeat {
+		return fmt.Sprintf("portfolio heat %.1f%% is over the %.1f%% limit", heat.HeatPercent, heat.MaxHeatPercent)
+	}
+
+	sizing := bs.risk.PositionSize(setup)
+	if sizing == nil || sizing.Shares < 1 {
+		return "the risk settings size the position at zero shares"
+	}
+	order.Quantity = float64(sizing.Shares)
+	return ""
+}
+
+// syncOrder refreshes an order from the broker and applies its state
+func (bs *BrokerService) syncOrder(order *models.BrokerOrder) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	state, err := bs.executor.GetOrder(ctx, order.BrokerOrderID)
+	if err != nil {
+		return err
+	}
+	return bs.apply(order, state)
+}
+
+// apply moves an order to the broker's state: a filled entry opens a portfolio position for the setup and a
+// filled stop or target closes it. An order that fills and exits between syncs goes through both.
+func (bs *BrokerService) apply(order *models.BrokerOrder, state *models.BrokerOrderState) error {
+	if state.Status == order.Status {
+		return nil
+	}
+
+	switch state.Status {
+	case models.BrokerOrderCanceled:
+		if order.Status != models.BrokerOrderSubmitted {
+			// A filled order's position stays open at the broker until its legs fill
+			return nil
+		}
+		order.Status = models.BrokerOrderCanceled
+		order.Error = state.Reason
+
+	case models.BrokerOrderFilled, models.BrokerOrderClosed:
+		if order.Status == models.BrokerOrderSubmitted {
+			if err := bs.openPosition(order, state); err != nil {
+				return err
+			}
+		}
+		if state.Status == models.BrokerOrderClosed {
+			if err := bs.closePosition(order, state); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return nil
+	}
+
+	return bs.db.UpdateBrokerOrder(order)
+}
+
+// openPosition records the filled entry of an order as a portfolio position on its setup
+func (bs *BrokerService) openPosition(order *models.BrokerOrder, state *models.BrokerOrderState) error {
+	order.FilledPrice = state.FilledPrice
+	if order.FilledPrice <= 0 {
+		order.FilledPrice = order.EntryPrice
+	}
+	filledAt := time.Now()
+	if state.FilledAt != nil {
+		filledAt = *state.FilledAt
+	}
+	order.FilledAt = &filledAt
+
+	setupID := order.SetupID
+	position := &models.Position{
+		Symbol:      order.Symbol,
+		Side:        order.Side,
+		Quantity:    order.Quantity,
+		EntryPrice:  order.FilledPrice,
+		EntryTime:   filledAt,
+		StopLoss:    order.StopLoss,
+		TargetPrice: order.TargetPrice,
+		SetupID:     &setupID,
+		Notes:       fmt.Sprintf("%s order %s", order.Broker, order.BrokerOrderID),
+	}
+	if err := bs.portfolio.OpenPosition(position); err != nil {
+		return fmt.Errorf("failed to open position for order %d: %w", order.ID, err)
+	}
+
+	order.PositionID = &position.ID
+	order.Status = models.BrokerOrderFilled
+	log.Printf("Broker order %d for %s filled at $%.2f, opened position %d", order.ID, order.Symbol, order.FilledPrice, position.ID)
+	return nil
+}
+
+// closePosition closes the order's position at the price of the leg that filled
+func (bs *BrokerService) closePosition(order *models.BrokerOrder, state *models.BrokerOrderState) error {
+	order.ExitPrice = state.ExitPrice
+	exitAt := time.Now()
+	if state.ExitAt != nil {
+		exitAt = *state.ExitAt
+	}
+	order.ExitAt = &exitAt
+
+	if order.PositionID != nil {
+		if _, err := bs.portfolio.ClosePosition(*order.PositionID, 0, order.ExitPrice, exitAt); err != nil {
+			return fmt.Errorf("failed to close position for order %d: %w", order.ID, err)
+		}
+	}
+
+	order.Status = models.BrokerOrderClosed
+	log.Printf("Broker order %d for %s closed by its %s at $%.2f", order.ID, order.Symbol, state.Reason, order.ExitPrice)
+	return nil
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// fakeExecutor is a broker that accepts every order and reports the state it is given
+type fakeExecutor struct {
+	requests []*models.BracketOrderRequest
+	state    *models.BrokerOrderState
+}
+
+func (f *fakeExecutor) Name() string { return "fake" }
+
+func (f *fakeExecutor) PlaceBracketOrder(ctx context.Context, request *models.BracketOrderRequest) (*models.BrokerOrderState, error) {
+	f.requests = append(f.requests, request)
+	return &models.BrokerOrderState{BrokerOrderID: "order-1", Status: models.BrokerOrderSubmitted}, nil
+}
+
+func (f *fakeExecutor) GetOrder(ctx context.Context, brokerOrderID string) (*models.BrokerOrderState, error) {
+	return f.state, nil
+}
+
+func (f *fakeExecutor) CancelOrder(ctx context.Context, brokerOrderID string) error {
+	return nil
+}
+
+// TestBrokerSetupOrder tests that a triggered setup only places an order once its strategy trades automatically,
+// and that the entry and exit fills open and close a portfolio position
+func TestBrokerSetupOrder(t *testing.T) {
+	cfg := &config.Config{}
+	db := newTestDB(t)
+
+	strategy, err := db.CreateStrategy(models.Strategy{Name: "Breakouts"})
+	if err != nil {
+		t.Fatalf("CreateStrategy failed: %v", err)
+	}
+	stock, err := db.AddStock(models.Stock{Symbol: "TEST", Name: "Test Corp"})
+	if err != nil {
+		t.Fatalf("AddStock failed: %v", err)
+	}
+	if err := db.AddStockToStrategy(stock.ID, strategy.ID); err != nil {
+		t.Fatalf("AddStockToStrategy failed: %v", err)
+	}
+
+	now := time.Now()
+	setup := &models.TradingSetup{
+		Symbol: "TEST", SetupType: "support_bounce", Direction: "bullish", Status: models.SetupStatusTriggered,
+		DetectedAt: now.Add(-time.Hour), ExpiresAt: now.Add(48 * time.Hour),
+		EntryPrice: 100, StopLoss: 95, Target1: 110, CurrentPrice: 100, Confidence: "high",
+	}
+	if err := db.InsertTradingSetup(setup); err != nil {
+		t.Fatalf("InsertTradingSetup failed: %v", err)
+	}
+
+	cfg.Broker.Enabled = true
+	cfg.Risk = config.RiskConfig{AccountSize: 50000, RiskPercent: 1, MaxHeatPercent: 6}
+	portfolio := NewPortfolioService(db)
+	broker := NewBrokerService(cfg, db, NewRiskService(cfg, db), portfolio)
+	executor := &fakeExecutor{}
+	broker.SetExecutor(executor)
+
+	// No strategy of the stock trades automatically yet
+	if order, err := broker.PlaceSetupOrder(setup, now); err != nil || order != nil {
+		t.Fatalf("expected no order without auto trading, got %+v, %v", order, err)
+	}
+
+	if err := db.SetStrategyAutoTrade(strategy.ID, true); err != nil {
+		t.Fatalf("SetStrategyAutoTrade failed: %v", err)
+	}
+	if order, err := broker.PlaceSetupOrder(setup, now.Add(-2*time.Hour)); err != nil || order != nil {
+		t.Fatalf("expected no order for a stale trigger, got %+v, %v", order, err)
+	}
+
+	order, err := broker.PlaceSetupOrder(setup, now)
+	if err != nil || order == nil {
+		t.Fatalf("PlaceSetupOrder failed: %+v, %v", order, err)
+	}
+	// $500 at risk over a $5 stop distance
+	if len(executor.requests) != 1 || executor.requests[0].Quantity != 100 || executor.requests[0].TargetPrice != 110 || executor.requests[0].TimeInForce != "day" {
+		t.Fatalf("unexpected order requests: %+v", executor.requests)
+	}
+	if order.Status != models.BrokerOrderSubmitted || order.StrategyID != strategy.ID || order.Side != models.PositionSideLong {
+		t.Fatalf("unexpected order: %+v", order)
+	}
+
+	// A setup is only ever ordered once
+	if again, err := broker.PlaceSetupOrder(setup, now); err != nil || again == nil || again.ID != order.ID || len(executor.requests) != 1 {
+		t.Fatalf("expected the existing order, got %+v, %v", again, err)
+	}
+
+	// The entry fills: a position opens at the fill price
+	filledAt := now.Add(time.Minute)
+	executor.state = &models.BrokerOrderState{BrokerOrderID: "order-1", Status: models.BrokerOrderFilled, FilledPrice: 100.1, FilledAt: &filledAt}
+	if changed, err := broker.SyncOrders(); err != nil || changed != 1 {
+		t.Fatalf("expected one order to change, got %d, %v", changed, err)
+	}
+	order, _ = db.GetBrokerOrderByID(order.ID)
+	if order.Status != models.BrokerOrderFilled || order.PositionID == nil {
+		t.Fatalf("expected a filled order with a position, got %+v", order)
+	}
+	position, err := portfolio.GetPosition(*order.PositionID)
+	if err != nil || !position.IsOpen() || position.EntryPrice != 100.1 || position.Quantity != 100 || *position.SetupID != setup.ID {
+		t.Fatalf("unexpected position: %+v, %v", position, err)
+	}
+
+	// The target leg fills: the position closes at the exit price
+	exitAt := now.Add(time.Hour)
+	executor.state = &models.BrokerOrderState{
+		BrokerOrderID: "order-1", Status: models.BrokerOrderClosed, FilledPrice: 100.1, FilledAt: &filledAt,
+		ExitPrice: 110, ExitAt: &exitAt, Reason: "take_profit",
+	}
+	if changed, err := broker.SyncOrders(); err != nil || changed != 1 {
+		t.Fatalf("expected one order to change, got %d, %v", changed, err)
+	}
+	position, _ = portfolio.GetPosition(*order.PositionID)
+	if position.IsOpen() || position.ExitPrice == nil || *position.ExitPrice != 110 {
+		t.Fatalf("expected the position closed at the target, got %+v", position)
+	}
+	if open, _ := db.GetBrokerOrders(&models.BrokerOrderFilter{Open: true}); len(open) != 0 {
+		t.Errorf("expected no open orders, got %+v", open)
+	}
+}
@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// Calendar defaults
+const (
+	DefaultCalendarRefreshInterval = 12 * time.Hour
+	defaultCalendarLookaheadDays   = 45
+	calendarRetentionDays          = 30
+)
+
+// CalendarService keeps upcoming earnings and economic events used for event-aware setup scoring
+type CalendarService struct {
+	cfg       *config.Config
+	db        *database.Database
+	client    *http.Client
+	enabled   bool
+	interval  time.Duration
+	lookahead int
+	location  *time.Location
+	schedules *ScheduleControl
+	stop      chan struct{}
+	stopOnce  sync.Once
+	wg        sync.WaitGroup // tracks the background refresh loop
+}
+
+// polygonEarningsResponse represents the response from the Polygon earnings endpoint
+type polygonEarningsResponse struct {
+	Status  string                  `json:"status"`
+	Results []polygonEarningsResult `json:"results"`
+}
+
+// polygonEarningsResult represents a single scheduled earnings report
+type polygonEarningsResult struct {
+	Ticker       string `json:"ticker"`
+	Date         string `json:"date"` // YYYY-MM-DD
+	Time         string `json:"time"` // HH:MM:SS Eastern
+	FiscalPeriod string `json:"fiscal_period"`
+	FiscalYear   int    `json:"fiscal_year"`
+	Importance   int    `json:"importance"`
+	DateStatus   string `json:"date_status"` // 'confirmed' or 'projected'
+	CompanyName  string `json:"company_name"`
+}
+
+// NewCalendarService creates a new calendar service
+func NewCalendarService(cfg *config.Config, db *database.Database) *CalendarService {
+	interval := cfg.Calendar.RefreshInterval
+	if interval <= 0 {
+		interval = DefaultCalendarRefreshInterval
+	}
+
+	lookahead := cfg.Calendar.LookaheadDays
+	if lookahead <= 0 {
+		lookahead = defaultCalendarLookaheadDays
+	}
+
+	timeout := cfg.Polygon.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		location = time.UTC
+	}
+
+	return &CalendarService{
+		cfg:       cfg,
+		db:        db,
+		client:    &http.Client{Timeout: timeout},
+		enabled:   cfg.Calendar.Enabled,
+		interval:  interval,
+		lookahead: lookahead,
+		location:  location,
+		stop:      make(chan struct{}),
+	}
+}
+
+// SetScheduleControl sets the control that can disable the earnings calendar refresh
+func (cs *CalendarService) SetScheduleControl(schedules *ScheduleControl) {
+	cs.schedules = schedules
+}
+
+// Start refreshes earnings dates now and then periodically when the calendar is enabled
+func (cs *CalendarService) Start() {
+	if !cs.enabled {
+		log.Printf("Earnings calendar refresh disabled")
+		return
+	}
+
+	log.Printf("Starting earnings calendar refresh (every %v, %d days ahead)...", cs.interval, cs.lookahead)
+
+	cs.wg.Add(1)
+	go func() {
+		defer cs.wg.Done()
+
+		ticker := time.NewTicker(cs.interval)
+		defer ticker.Stop()
+
+		for {
+			cs.scheduledRefresh()
+
+			select {
+			case <-ticker.C:
+			case <-cs.stop:
+				return
+			}
+		}
+	}()
+}
+
+// scheduledRefresh refreshes earnings dates unless the calendar schedule is disabled
+func (cs *CalendarService) scheduledRefresh() {
+	if cs.schedules.Skip(models.ScheduleCalendar) {
+		return
+	}
+	if err := cs.RefreshEarnings(); err != nil {
+		log.Printf("Earnings calendar refresh failed: %v", err)
+	}
+}
+
+// Stop stops the refresh loop and waits for an in-flight refresh to finish
+func (cs *CalendarService) Stop(ctx context.Context) error {
+	cs.stopOnce.Do(func() { close(cs.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		cs.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Printf("Calendar service stopped")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for calendar refresh to finish: %w", ctx.Err())
+	}
+}
+
+// RefreshEarnings fetches upcoming earnings for every watched symbol and prunes old events
+func (cs *CalendarService) RefreshEarnings() error {
+	symbols, err := cs.db.GetWatchedSymbols()
+	if err != nil {
+		return fmt.Errorf("failed to get watched symbols: %w", err)
+	}
+
+	stored := 0
+	for _, symbol := range symbols {
+		events, err := cs.FetchEarnings(symbol)
+		if err != nil {
+			log.Printf("Failed to fetch earnings for %s: %v", symbol, err)
+			continue
+		}
+
+		for _, event := range events {
+			if err := cs.db.UpsertCalendarEvent(event); err != nil {
+				log.Printf("Failed to store earnings event for %s: %v", symbol, err)
+				continue
+			}
+			stored++
+		}
+	}
+
+	if _, err := cs.db.DeleteCalendarEventsBefore(time.Now().AddDate(0, 0, -calendarRetentionDays)); err != nil {
+		log.Printf("Failed to prune old calendar events: %v", err)
+	}
+
+	log.Printf("Earnings calendar refreshed: %d events for %d symbols", stored, len(symbols))
+	return nil
+}
+
+// FetchEarnings fetches scheduled earnings reports for a symbol from Polygon
+func (cs *CalendarService) FetchEarnings(symbol string) ([]*models.CalendarEvent, error) {
+	now := time.Now().In(cs.location)
+
+	params := url.Values{}
+	params.Set("ticker", symbol)
+	params.Set("date.gte", now.Format("2006-01-02"))
+	params.Set("date.lte", now.AddDate(0, 0, cs.lookahead).Format("2006-01-02"))
+	params.Set("order", "asc")
+	params.Set("limit", "10")
+	params.Set("apiKey", cs.cfg.Polygon.APIKey)
+
+	req, err := http.NewRequest("GET", cs.cfg.Polygon.BaseURL+"/benzinga/v1/earnings?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := cs.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var earningsResp polygonEarningsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&earningsResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return cs.convertEarnings(symbol, earningsResp.Results), nil
+}
+
+// convertEarnings converts Polygon earnings results to calendar events
+func (cs *CalendarService) convertEarnings(symbol string, results []polygonEarningsResult) []*models.CalendarEvent {
+	events := make([]*models.CalendarEvent, 0, len(results))
+
+	for _, result := range results {
+		date, err := time.ParseInLocation("2006-01-02", result.Date, cs.location)
+		if err != nil {
+			continue
+		}
+
+		title := "Earnings"
+		if result.FiscalPeriod != "" {
+			title = fmt.Sprintf("%s %d Earnings", result.FiscalPeriod, result.FiscalYear)
+		}
+
+		details := result.DateStatus
+		if result.CompanyName != "" {
+			details = strings.TrimSpace(result.CompanyName + " " + details)
+		}
+
+		events = append(events, &models.CalendarEvent{
+			Symbol:     symbol,
+			EventType:  models.CalendarEventEarnings,
+			Title:      title,
+			EventDate:  date,
+			EventTime:  earningsSession(result.Time),
+			Importance: result.Importance,
+			Source:     models.CalendarSourcePolygon,
+			Details:    details,
+		})
+	}
+
+	return events
+}
+
+// earningsSession classifies an Eastern report time as before the open or after the close
+func earningsSession(clock string) string {
+	if len(clock) < 5 {
+		return ""
+	}
+	hhmm := clock[:5]
+	switch {
+	case hhmm < "09:30":
+		return "bmo"
+	case hhmm >= "16:00":
+		return "amc"
+	default:
+		return hhmm
+	}
+}
+
+// AddEvent validates and stores a manually entered event, such as an FOMC meeting or CPI release
+func (cs *CalendarService) AddEvent(event *models.CalendarEvent) error {
+	event.Symbol = strings.ToUpper(strings.TrimSpace(event.Symbol))
+	event.EventType = strings.ToLower(strings.TrimSpace(event.EventType))
+	if event.EventType == "" {
+		return fmt.Errorf("event_type is required")
+	}
+	if event.EventType == models.CalendarEventEarnings && event.Symbol == "" {
+		return fmt.Errorf("symbol is required for earnings events")
+	}
+	if event.EventDate.IsZero() {
+		return fmt.Errorf("event_date is required")
+	}
+	if event.Title == "" {
+		event.Title = event.EventType
+	}
+	if event.Source == "" {
+		event.Source = models.CalendarSourceManual
+	}
+
+	return cs.db.UpsertCalendarEvent(event)
+}
+
+// GetUpcomingEvents returns events for a symbol (and market-wide events) over the next number of days
+func (cs *CalendarService) GetUpcomingEvents(symbol string, days int) ([]*models.CalendarEvent, error) {
+	now := time.Now()
+	return cs.EventsInWindow(symbol, now, now.AddDate(0, 0, days))
+}
+
+// EventsInWindow returns events for a symbol, including market-wide events, scheduled between
+// the start of from's trading day and to
+func (cs *CalendarService) EventsInWindow(symbol string, from, to time.Time) ([]*models.CalendarEvent, error) {
+	local := from.In(cs.location)
+	dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, cs.location)
+
+	return cs.db.GetCalendarEvents(&models.CalendarEventFilter{
+		Symbol:        symbol,
+		IncludeMarket: true,
+		From:          dayStart,
+		To:            to,
+	})
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestApplyEventRisk tests that earnings inside the setup window are penalized and market events only flagged
+func TestApplyEventRisk(t *testing.T) {
+	sds := NewSetupDetectionService(nil, nil, nil)
+	now := time.Date(2024, 4, 22, 15, 0, 0, 0, time.UTC)
+	newSetup := func() *models.TradingSetup {
+		return &models.TradingSetup{Symbol: "AAPL", QualityScore: 85, ExpiresAt: now.Add(24 * time.Hour)}
+	}
+
+	earnings := &models.CalendarEvent{Symbol: "AAPL", EventType: models.CalendarEventEarnings, EventDate: now.AddDate(0, 0, 2)}
+	fomc := &models.CalendarEvent{EventType: models.CalendarEventEconomic, Title: "FOMC", EventDate: now.AddDate(0, 0, 1)}
+	later := &models.CalendarEvent{Symbol: "AAPL", EventType: models.CalendarEventEarnings, EventDate: now.AddDate(0, 0, 10)}
+
+	// Earnings within expiration plus the buffer days costs the penalty and drops the confidence
+	setup := newSetup()
+	sds.applyEventRisk(setup, []*models.CalendarEvent{earnings, fomc})
+	if setup.EventPenalty != 15 || setup.QualityScore != 70 {
+		t.Errorf("expected 15 point penalty to 70, got %.1f to %.1f", setup.EventPenalty, setup.QualityScore)
+	}
+	if setup.Confidence != setup.GetConfidenceLevel() || len(setup.UpcomingEvents) != 2 {
+		t.Errorf("expected recomputed confidence and 2 flagged events, got %s and %d", setup.Confidence, len(setup.UpcomingEvents))
+	}
+
+	// Market-wide events are flagged without a penalty
+	setup = newSetup()
+	sds.applyEventRisk(setup, []*models.CalendarEvent{fomc})
+	if setup.EventPenalty != 0 || setup.QualityScore != 85 || len(setup.UpcomingEvents) != 1 {
+		t.Errorf("expected flag only, got penalty %.1f score %.1f events %d", setup.EventPenalty, setup.QualityScore, len(setup.UpcomingEvents))
+	}
+
+	// Earnings after the holding window are ignored
+	setup = newSetup()
+	sds.applyEventRisk(setup, []*models.CalendarEvent{later})
+	if setup.EventPenalty != 0 || len(setup.UpcomingEvents) != 0 {
+		t.Errorf("expected later earnings to be ignored, got penalty %.1f events %d", setup.EventPenalty, len(setup.UpcomingEvents))
+	}
+}
+
+// TestEarningsSession tests classification of report times
+func TestEarningsSession(t *testing.T) {
+	cases := map[string]string{"06:00:00": "bmo", "16:05:00": "amc", "12:00:00": "12:00", "": ""}
+	for clock, want := range cases {
+		if got := earningsSession(clock); got != want {
+			t.Errorf("earningsSession(%q) = %q, want %q", clock, got, want)
+		}
+	}
+}
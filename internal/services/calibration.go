@@ -0,0 +1,326 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// Calibration defaults
+const (
+	DefaultCalibrationInterval      = 24 * time.Hour
+	DefaultCalibrationMinSamples    = 30
+	DefaultCalibrationPriorStrength = 10.0
+
+	// calibrationMaxLift caps an item's lift, so one item can earn at most three times an even share of the score
+	calibrationMaxLift = 3.0
+)
+
+// calibrationSample is a resolved setup's checklist and whether it reached its target
+type calibrationSample struct {
+	checklist *models.SetupChecklist
+	won       bool
+}
+
+// CalibrationService recomputes checklist item weights from the outcomes of resolved setups and stores each
+// result as a weight version. Setups are only scored with calibrated weights once a version is activated.
+type CalibrationService struct {
+	db           *database.Database
+	setups       *SetupDetectionService
+	enabled      bool
+	interval     time.Duration
+	minSamples   int
+	prior        float64
+	autoActivate bool
+	mutex        sync.Mutex // serializes calibrating and activating
+	stop         chan struct{}
+	stopOnce     sync.Once
+	wg           sync.WaitGroup // tracks the background calibration loop
+}
+
+// NewCalibrationService creates a new calibration service for the setup scorer
+func NewCalibrationService(cfg *config.Config, db *database.Database, setups *SetupDetectionService) *CalibrationService {
+	calibrationCfg := cfg.Calibration
+
+	cs := &CalibrationService{
+		db:           db,
+		setups:       setups,
+		enabled:      calibrationCfg.Enabled,
+		interval:     calibrationCfg.Interval,
+		minSamples:   calibrationCfg.MinSamples,
+		prior:        calibrationCfg.PriorStrength,
+		autoActivate: calibrationCfg.AutoActivate,
+		stop:         make(chan struct{}),
+	}
+
+	if cs.interval <= 0 {
+		cs.interval = DefaultCalibrationInterval
+	}
+	if cs.minSamples <= 0 {
+		cs.minSamples = DefaultCalibrationMinSamples
+	}
+	if cs.prior <= 0 {
+		cs.prior = DefaultCalibrationPriorStrength
+	}
+
+	return cs
+}
+
+// Load scores setups with the active weight version, if one was activated
+func (cs *CalibrationService) Load() error {
+	version, err := cs.db.GetActiveScoringWeightVersion()
+	if err != nil {
+		return err
+	}
+
+	cs.setups.SetCalibratedWeights(version)
+	if version != nil {
+		log.Printf("Scoring setups with calibrated weights version %d (%d samples)", version.ID, version.Samples)
+	}
+	return nil
+}
+
+// Start recomputes weights in the background when calibration is enabled
+func (cs *CalibrationService) Start() {
+	if !cs.enabled {
+		log.Printf("Setup score calibration disabled")
+		return
+	}
+
+	log.Printf("Starting setup score calibration (every %v, min %d samples)...", cs.interval, cs.minSamples)
+
+	ticker := time.NewTicker(cs.interval)
+	cs.wg.Add(1)
+	go func() {
+		defer cs.wg.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := cs.Calibrate(); err != nil {
+					log.Printf("Setup score calibration failed: %v", err)
+				}
+			case <-cs.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background loop and waits for an in-flight calibration to finish
+func (cs *CalibrationService) Stop(ctx context.Context) error {
+	cs.stopOnce.Do(func() { close(cs.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		cs.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for setup score calibration to finish: %w", ctx.Err())
+	}
+}
+
+// Calibrate learns checklist item weights from every setup that completed or stopped out and stores them as a
+// new version. With auto_activate, the new version replaces an active one; otherwise scoring is unchanged
+// until it is activated.
+func (cs *CalibrationService) Calibrate() (*models.ScoringWeightVersion, error) {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	samples, err := cs.resolvedSamples()
+	if err != nil {
+		return nil, err
+	}
+	if len(samples) < cs.minSamples {
+		return nil, fmt.Errorf("%w: %d resolved setups with checklists, at least %d are needed to calibrate", ErrUnavailable, len(samples), cs.minSamples)
+	}
+
+	version := calibrateWeights(samples, cs.prior)
+	if err := cs.db.InsertScoringWeightVersion(version); err != nil {
+		return nil, err
+	}
+	log.Printf("Calibrated setup scoring weights version %d from %d setups (%.0f%% won)", version.ID, version.Samples, version.BaseWinRate*100)
+
+	if cs.autoActivate && cs.setups.CalibratedWeights() != nil {
+		if err := cs.activate(version.ID); err != nil {
+			return nil, err
+		}
+		return cs.db.GetScoringWeightVersionByID(version.ID)
+	}
+	return version, nil
+}
+
+// Activate scores setups with a weight version from now on
+func (cs *CalibrationService) Activate(id int64) (*models.ScoringWeightVersion, error) {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	if err := cs.activate(id); err != nil {
+		return nil, err
+	}
+	return cs.setups.CalibratedWeights(), nil
+}
+
+// Deactivate returns setup scoring to the default category weights
+func (cs *CalibrationService) Deactivate() error {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	if err := cs.db.DeactivateScoringWeightVersions(); err != nil {
+		return err
+	}
+	cs.setups.SetCalibratedWeights(nil)
+	log.Printf("Scoring setups with the default weights")
+	return nil
+}
+
+// GetStatus describes the scorer in use and the latest weight versions
+func (cs *CalibrationService) GetStatus(limit int) (*models.CalibrationStatus, error) {
+	versions, err := cs.db.GetScoringWeightVersions(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := 0
+	for _, status := range []string{models.SetupStatusCompleted, models.SetupStatusStoppedOut} {
+		count, err := cs.db.CountTradingSetups(&models.SetupFilter{Status: status, IncludeArchived: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to count %s setups: %w", status, err)
+		}
+		resolved += count
+	}
+
+	status := &models.CalibrationStatus{
+		Enabled:        cs.enabled,
+		Scorer:         models.ScorerDefault,
+		ActiveVersion:  cs.setups.CalibratedWeights(),
+		Versions:       versions,
+		MinSamples:     cs.minSamples,
+		ResolvedSetups: resolved,
+	}
+	if status.ActiveVersion != nil {
+		status.Scorer = models.ScorerCalibrated
+	}
+	return status, nil
+}
+
+// activate marks a version active and switches the scorer to it
+func (cs *CalibrationService) activate(id int64) error {
+	if err := cs.db.ActivateScoringWeightVersion(id); err != nil {
+		return err
+	}
+
+	version, err := cs.db.GetScoringWeightVersionByID(id)
+	if err != nil {
+		return err
+	}
+	cs.setups.SetCalibratedWeights(version)
+	log.Printf("Scoring setups with calibrated weights version %d", id)
+	return nil
+}
+
+// resolvedSamples loads the checklists of setups, archived or not, that completed at their target or stopped out
+func (cs *CalibrationService) resolvedSamples() ([]calibrationSample, error) {
+	var samples []calibrationSample
+	for _, status := range []string{models.SetupStatusCompleted, models.SetupStatusStoppedOut} {
+		setups, err := cs.db.GetTradingSetups(&models.SetupFilter{Status: status, IncludeArchived: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s setups: %w", status, err)
+		}
+
+		for _, setup := range setups {
+			checklist, err := cs.db.GetSetupChecklist(setup.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get checklist of setup %d: %w", setup.ID, err)
+			}
+			if checklist == nil {
+				continue
+			}
+			samples = append(samples, calibrationSample{checklist: checklist, won: status == models.SetupStatusCompleted})
+		}
+	}
+	return samples, nil
+}
+
+// calibrateWeights weighs each checklist item by its lift: the win rate of setups that completed it over the
+// win rate of setups that missed it. Both rates are Beta posteriors centered on the overall win rate with
+// prior pseudo-setups, so rarely seen items stay close to an even share. Weights are scaled to sum to 100.
+func calibrateWeights(samples []calibrationSample, prior float64) *models.ScoringWeightVersion {
+	version := &models.ScoringWeightVersion{Samples: len(samples)}
+	items := make([]map[string]*models.ChecklistItem, len(samples))
+	for i, sample := range samples {
+		items[i] = sample.checklist.Items()
+		if sample.won {
+			version.Wins++
+		} else {
+			version.Losses++
+		}
+	}
+	// Laplace smoothing keeps the prior off 0 and 1
+	base := (float64(version.Wins) + 1) / (float64(version.Samples) + 2)
+	version.BaseWinRate = roundTo(float64(version.Wins)/float64(max(version.Samples, 1)), 4)
+
+	totalLift := 0.0
+	for _, key := range models.ChecklistItemKeys {
+		weight := &models.ChecklistItemWeight{Key: key}
+		for i, sample := range samples {
+			completed := items[i][key].IsCompleted
+			switch {
+			case completed && sample.won:
+				weight.Completed++
+				weight.CompletedWins++
+			case completed:
+				weight.Completed++
+			case sample.won:
+				weight.Missed++
+				weight.MissedWins++
+			default:
+				weight.Missed++
+			}
+		}
+
+		completedRate := (float64(weight.CompletedWins) + prior*base) / (float64(weight.Completed) + prior)
+		missedRate := (float64(weight.MissedWins) + prior*base) / (float64(weight.Missed) + prior)
+		weight.CompletedWinRate = roundTo(completedRate, 4)
+		weight.MissedWinRate = roundTo(missedRate, 4)
+		weight.Lift = math.Min(completedRate/missedRate, calibrationMaxLift)
+
+		totalLift += weight.Lift
+		version.Items = append(version.Items, weight)
+	}
+
+	for _, weight := range version.Items {
+		weight.Points = roundTo(weight.Lift/totalLift*100, 2)
+		weight.Lift = roundTo(weight.Lift, 4)
+	}
+	return version
+}
+
+// roundTo rounds a value to the given number of decimals
+func roundTo(value float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(value*scale) / scale
+}
+
+// calibratedScore adds up the calibrated weights of a checklist's completed items
+func calibratedScore(checklist *models.SetupChecklist, version *models.ScoringWeightVersion) float64 {
+	score := 0.0
+	for key, item := range checklist.Items() {
+		if item.IsCompleted {
+			score += version.Points(key)
+		}
+	}
+	return math.Min(100, score)
+}
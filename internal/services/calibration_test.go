@@ -0,0 +1,107 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// TestCalibration tests that an item predicting wins earns more weight than items that don't, and that the
+// calibrated scorer is only used once a version is activated
+func TestCalibration(t *testing.T) {
+	cfg := &config.Config{}
+	db := newTestDB(t)
+
+	setups := NewSetupDetectionService(db, nil, nil)
+	cfg.Calibration = config.CalibrationConfig{MinSamples: 40, PriorStrength: 10}
+	calibration := NewCalibrationService(cfg, db, setups)
+
+	newSetup := func(won, volumeSpike bool) {
+		status := models.SetupStatusStoppedOut
+		if won {
+			status = models.SetupStatusCompleted
+		}
+
+		checklist := &models.SetupChecklist{}
+		setups.initializeChecklistItems(checklist)
+		checklist.StopLossDefined.IsCompleted, checklist.StopLossDefined.Points = true, 5
+		checklist.VolumeSpike.IsCompleted, checklist.VolumeSpike.Points = volumeSpike, 5
+
+		detected := time.Now().Add(-48 * time.Hour)
+		setup := &models.TradingSetup{
+			Symbol: "TEST", SetupType: "support_bounce", Direction: "bullish", Status: status,
+			DetectedAt: detected, ExpiresAt: detected.Add(24 * time.Hour),
+			EntryPrice: 100, StopLoss: 95, Target1: 110, Confidence: "medium", Checklist: checklist,
+		}
+		if err := db.InsertTradingSetupWithChecklist(setup); err != nil {
+			t.Fatalf("InsertTradingSetupWithChecklist failed: %v", err)
+		}
+	}
+
+	// 16 of the 20 setups with a volume spike won against 4 of the 20 without
+	for i := 0; i < 20; i++ {
+		newSetup(i < 16, true)
+		newSetup(i < 4, false)
+	}
+	newSetup(true, false)
+
+	version, err := calibration.Calibrate()
+	if err != nil {
+		t.Fatalf("Calibrate failed: %v", err)
+	}
+	if version.Samples != 41 || version.Wins != 21 || version.Losses != 20 {
+		t.Fatalf("unexpected samples: %+v", version)
+	}
+
+	total := 0.0
+	for _, item := range version.Items {
+		total += item.Points
+	}
+	if math.Abs(total-100) > 0.1 {
+		t.Errorf("expected weights summing to 100, got %.2f", total)
+	}
+	spike, even := version.Points("volume_spike"), version.Points("stop_loss_defined")
+	if spike <= 2*even || math.Abs(version.Points("macd_signal")-even) > 0.5 {
+		t.Errorf("expected the volume spike to outweigh items that predict nothing, got %.2f vs %.2f", spike, even)
+	}
+
+	// Stored versions are not used until activated
+	if setups.CalibratedWeights() != nil {
+		t.Fatal("expected the default scorer before activation")
+	}
+	if _, err := calibration.Activate(version.ID + 1); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("expected an unknown version to be not found, got %v", err)
+	}
+	if _, err := calibration.Activate(version.ID); err != nil {
+		t.Fatalf("Activate failed: %v", err)
+	}
+
+	// A reloaded service picks up the active version
+	reloaded := NewSetupDetectionService(db, nil, nil)
+	if err := NewCalibrationService(cfg, db, reloaded).Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	active := reloaded.CalibratedWeights()
+	if active == nil || active.ID != version.ID {
+		t.Fatalf("expected version %d to be active, got %+v", version.ID, active)
+	}
+
+	checklist := &models.SetupChecklist{}
+	checklist.VolumeSpike.IsCompleted = true
+	checklist.StopLossDefined.IsCompleted = true
+	if score := calibratedScore(checklist, active); math.Abs(score-(spike+even)) > 0.01 {
+		t.Errorf("expected a calibrated score of %.2f, got %.2f", spike+even, score)
+	}
+
+	if err := calibration.Deactivate(); err != nil {
+		t.Fatalf("Deactivate failed: %v", err)
+	}
+	if status, err := calibration.GetStatus(10); err != nil || status.Scorer != models.ScorerDefault || status.ResolvedSetups != 41 || len(status.Versions) != 1 {
+		t.Errorf("unexpected status after deactivating: %+v, %v", status, err)
+	}
+}
@@ -0,0 +1,560 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// Chart image defaults
+const (
+	defaultChartWidth  = 800
+	defaultChartHeight = 450
+	maxChartCandles    = 160
+	maxChartSRLevels   = 6
+)
+
+// Pattern families a chart can be rendered for, matching the pattern_family of the pattern list
+const (
+	ChartFamilyHeadShoulders = "head_shoulders"
+	ChartFamilyFallingWedge  = "falling_wedge" // falling and rising wedges
+	ChartFamilyTriangle      = "triangle"
+	ChartFamilyFlag          = "flag"
+)
+
+// ChartFamilies lists the accepted pattern families
+var ChartFamilies = []string{ChartFamilyHeadShoulders, ChartFamilyFallingWedge, ChartFamilyTriangle, ChartFamilyFlag}
+
+// ErrPatternNotFound is returned when the pattern to chart doesn't exist
+var ErrPatternNotFound = fmt.Errorf("pattern %w", database.ErrNotFound)
+
+// Chart colors
+var (
+	chartBackground = color.RGBA{255, 255, 255, 255}
+	chartGrid       = color.RGBA{235, 235, 235, 255}
+	chartAxis       = color.RGBA{90, 90, 90, 255}
+	chartUp         = color.RGBA{38, 166, 91, 255}
+	chartDown       = color.RGBA{220, 53, 69, 255}
+	chartPattern    = color.RGBA{25, 118, 210, 255}
+	chartNeckline   = color.RGBA{245, 124, 0, 255}
+	chartTarget     = color.RGBA{123, 31, 162, 255}
+	chartSupport    = color.RGBA{46, 125, 50, 255}
+	chartResistance = color.RGBA{198, 40, 40, 255}
+	chartFibonacci  = color.RGBA{191, 144, 0, 255}
+)
+
+// ChartLine is a straight pattern line between two points, optionally extended to the right edge
+type ChartLine struct {
+	From   models.PatternPoint
+	To     models.PatternPoint
+	Color  color.RGBA
+	Extend bool
+}
+
+// ChartLevel is a labeled horizontal price level
+type ChartLevel struct {
+	Price  float64
+	Label  string
+	Color  color.RGBA
+	Dashed bool
+}
+
+// ChartSpec describes a candlestick chart and the pattern drawn over it
+type ChartSpec struct {
+	Title   string
+	Bars    []*models.PriceData // oldest first
+	Lines   []ChartLine
+	Levels  []ChartLevel
+	Markers []models.PatternPoint // pattern pivots
+	Width   int
+	Height  int
+}
+
+// ChartService renders stored patterns as PNG candlestick charts for emails and the API
+type ChartService struct {
+	db *database.Database
+}
+
+// NewChartService creates a new chart service
+func NewChartService(db *database.Database) *ChartService {
+	return &ChartService{db: db}
+}
+
+// PatternChart renders the stored pattern of a family by ID
+func (cs *ChartService) PatternChart(family string, id int64) ([]byte, error) {
+	var pattern interface{}
+	var err error
+
+	switch family {
+	case ChartFamilyHeadShoulders:
+		var p *models.HeadShouldersPattern
+		if p, err = cs.db.GetHeadShouldersPatternByID(id); p != nil {
+			pattern = p
+		}
+	case ChartFamilyFallingWedge:
+		var p *models.FallingWedgePattern
+		if p, err = cs.db.GetFallingWedgePatternByID(id); p != nil {
+			pattern = p
+		}
+	case ChartFamilyTriangle:
+		var p *models.TrianglePattern
+		if p, err = cs.db.GetTrianglePatternByID(id); p != nil {
+			pattern = p
+		}
+	case ChartFamilyFlag:
+		var p *models.FlagPattern
+		if p, err = cs.db.GetFlagPatternByID(id); p != nil {
+			pattern = p
+		}
+	default:
+		return nil, fmt.Errorf("invalid pattern type %q: must be one of %s", family, strings.Join(ChartFamilies, ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if pattern == nil {
+		return nil, ErrPatternNotFound
+	}
+
+	return cs.RenderPattern(pattern)
+}
+
+// RenderPattern renders a head and shoulders, wedge, triangle or flag pattern over its recent candles,
+// together with the symbol's strongest active S/R levels and its Fibonacci levels in view
+func (cs *ChartService) RenderPattern(pattern interface{}) ([]byte, error) {
+	spec, symbol, lastUpdated := patternChartSpec(pattern)
+	if spec == nil {
+		return nil, fmt.Errorf("unsupported pattern %T", pattern)
+	}
+
+	start, end := chartSpan(spec, lastUpdated)
+	bars, err := cs.chartBars(symbol, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(bars) == 0 {
+		return nil, fmt.Errorf("no price data for %s between %s and %s", symbol, start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+	spec.Bars = bars
+
+	low, high := barRange(bars)
+	active := true
+	levels, err := cs.db.GetSupportResistanceLevels(&models.SRDetectionFilter{
+		Symbol:     symbol,
+		IsActive:   &active,
+		PriceRange: models.SRPriceRange{Min: low, Max: high},
+		Limit:      maxChartSRLevels,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get S/R levels: %w", err)
+	}
+	for _, level := range levels {
+		levelColor, label := chartSupport, "S"
+		if level.LevelType == "resistance" {
+			levelColor, label = chartResistance, "R"
+		}
+		spec.Levels = append(spec.Levels, ChartLevel{Price: level.Level, Label: label, Color: levelColor, Dashed: true})
+	}
+
+	fibLevels, err := cs.db.GetSupportResistanceLevels(&models.SRDetectionFilter{
+		Symbol:     symbol,
+		LevelClass: models.SRClassFibonacci,
+		IsActive:   &active,
+		PriceRange: models.SRPriceRange{Min: low, Max: high},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Fibonacci levels: %w", err)
+	}
+	for _, level := range fibLevels {
+		label := "FIB " + strconv.FormatFloat(level.FibRatio*100, 'f', -1, 64) + "%"
+		spec.Levels = append(spec.Levels, ChartLevel{Price: level.Level, Label: label, Color: chartFibonacci, Dashed: true})
+	}
+
+	return RenderChart(spec)
+}
+
+// chartBars returns the bars covering start to end at the finest timeframe that fits the chart,
+// trimming the oldest bars when even daily bars don't fit
+func (cs *ChartService) chartBars(symbol string, start, end time.Time) ([]*models.PriceData, error) {
+	timeframes := []models.Timeframe{models.Timeframe1m, models.Timeframe5m, models.Timeframe15m, models.Timeframe1h, models.Timeframe1d}
+
+	var bars []*models.PriceData
+	for _, tf := range timeframes {
+		// Skip timeframes that can't fit even if the market were only open a third of the time
+		if tf != models.Timeframe1d && end.Sub(start)/tf.Duration() > 3*maxChartCandles {
+			continue
+		}
+
+		var err error
+		bars, err = cs.db.GetPriceDataRangeTimeframe(symbol, start, end, tf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get price data: %w", err)
+		}
+		if len(bars) <= maxChartCandles {
+			return bars, nil
+		}
+	}
+
+	return bars[len(bars)-maxChartCandles:], nil
+}
+
+// patternChartSpec builds the overlays of a pattern, returning nil for an unsupported pattern
+func patternChartSpec(pattern interface{}) (*ChartSpec, string, time.Time) {
+	switch p := pattern.(type) {
+	case *models.HeadShouldersPattern:
+		spec := &ChartSpec{Title: p.Symbol + " " + p.DisplayName()}
+		pivots := []models.PatternPoint{p.LeftShoulderHigh, p.HeadHigh, p.RightShoulderHigh}
+		if p.PatternType == models.SetupTypeInverseHeadShoulders {
+			pivots = []models.PatternPoint{p.LeftShoulderLow, p.HeadLow, p.RightShoulderLow}
+		}
+		var previous *models.PatternPoint
+		for i := range pivots {
+			if !validChartPoint(pivots[i]) {
+				continue
+			}
+			if previous != nil {
+				spec.Lines = append(spec.Lines, ChartLine{From: *previous, To: pivots[i], Color: chartPattern})
+			}
+			spec.Markers = append(spec.Markers, pivots[i])
+			previous = &pivots[i]
+		}
+		if validChartPoint(p.NecklineTouch1) && validChartPoint(p.NecklineTouch2) && !p.NecklineTouch1.Timestamp.Equal(p.NecklineTouch2.Timestamp) {
+			spec.Lines = append(spec.Lines, ChartLine{From: p.NecklineTouch1, To: p.NecklineTouch2, Color: chartNeckline, Extend: true})
+		}
+		spec.Levels = append(spec.Levels,
+			ChartLevel{Price: p.NecklineLevel, Label: "NECKLINE", Color: chartNeckline},
+			ChartLevel{Price: p.CalculateTargetPrice(), Label: "TARGET", Color: chartTarget, Dashed: true},
+		)
+		return spec, p.Symbol, p.LastUpdated
+
+	case *models.FallingWedgePattern:
+		spec := trendLineChartSpec(p.Symbol, p.PatternType, p.UpperTrendLine1, p.UpperTrendLine2, p.LowerTrendLine1, p.LowerTrendLine2)
+		spec.Levels = append(spec.Levels,
+			ChartLevel{Price: p.BreakoutLevel, Label: "BREAKOUT", Color: chartNeckline},
+			ChartLevel{Price: p.CalculateTargetPrice(), Label: "TARGET", Color: chartTarget, Dashed: true},
+		)
+		return spec, p.Symbol, p.LastUpdated
+
+	case *models.TrianglePattern:
+		spec := trendLineChartSpec(p.Symbol, p.PatternType, p.UpperTrendLine1, p.UpperTrendLine2, p.LowerTrendLine1, p.LowerTrendLine2)
+		spec.Levels = append(spec.Levels,
+			ChartLevel{Price: p.BreakoutLevel, Label: "BREAKOUT", Color: chartNeckline},
+			ChartLevel{Price: p.CalculateTargetPrice(), Label: "TARGET", Color: chartTarget, Dashed: true},
+		)
+		return spec, p.Symbol, p.LastUpdated
+
+	case *models.FlagPattern:
+		spec := &ChartSpec{Title: p.Symbol + " " + chartTitle(p.PatternType)}
+		if validChartPoint(p.PoleStart) && validChartPoint(p.PoleEnd) {
+			spec.Lines = append(spec.Lines, ChartLine{From: p.PoleStart, To: p.PoleEnd, Color: chartPattern})
+		}
+		for _, point := range []models.PatternPoint{p.PoleStart, p.PoleEnd, p.FlagHigh, p.FlagLow} {
+			if validChartPoint(point) {
+				spec.Markers = append(spec.Markers, point)
+			}
+		}
+		spec.Levels = append(spec.Levels,
+			ChartLevel{Price: p.BreakoutLevel, Label: "BREAKOUT", Color: chartNeckline},
+			ChartLevel{Price: p.CalculateTargetPrice(), Label: "TARGET", Color: chartTarget, Dashed: true},
+		)
+		return spec, p.Symbol, p.LastUpdated
+	}
+
+	return nil, "", time.Time{}
+}
+
+// trendLineChartSpec draws the two converging trend lines of a wedge or triangle
+func trendLineChartSpec(symbol, patternType string, upper1, upper2, lower1, lower2 models.PatternPoint) *ChartSpec {
+	spec := &ChartSpec{Title: symbol + " " + chartTitle(patternType)}
+	for _, line := range [][2]models.PatternPoint{{upper1, upper2}, {lower1, lower2}} {
+		if !validChartPoint(line[0]) || !validChartPoint(line[1]) {
+			continue
+		}
+		spec.Lines = append(spec.Lines, ChartLine{From: line[0], To: line[1], Color: chartPattern, Extend: true})
+		spec.Markers = append(spec.Markers, line[0], line[1])
+	}
+	return spec
+}
+
+// chartTitle turns a pattern type such as "bull_flag" into "BULL FLAG"
+func chartTitle(patternType string) string {
+	return strings.ToUpper(strings.ReplaceAll(patternType, "_", " "))
+}
+
+// validChartPoint reports whether a pattern point has been set
+func validChartPoint(point models.PatternPoint) bool {
+	return !point.Timestamp.IsZero() && point.Price > 0
+}
+
+// chartSpan returns the time range to chart: the pattern with a quarter of its width of context
+// before it and after its last update, capped at now
+func chartSpan(spec *ChartSpec, lastUpdated time.Time) (time.Time, time.Time) {
+	var first, last time.Time
+	points := append([]models.PatternPoint{}, spec.Markers...)
+	for _, line := range spec.Lines {
+		points = append(points, line.From, line.To)
+	}
+	for _, point := range points {
+		if first.IsZero() || point.Timestamp.Before(first) {
+			first = point.Timestamp
+		}
+		if point.Timestamp.After(last) {
+			last = point.Timestamp
+		}
+	}
+
+	now := clockNow()
+	if first.IsZero() {
+		return now.AddDate(0, 0, -5), now
+	}
+	if lastUpdated.After(last) {
+		last = lastUpdated
+	}
+
+	pad := max(last.Sub(first)/4, time.Hour)
+	end := last.Add(pad)
+	if end.After(now) {
+		end = now
+	}
+	return first.Add(-pad), end
+}
+
+// barRange returns the lowest low and highest high of the bars
+func barRange(bars []*models.PriceData) (float64, float64) {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, bar := range bars {
+		low = math.Min(low, bar.Low)
+		high = math.Max(high, bar.High)
+	}
+	return low, high
+}
+
+// chartPlot maps prices and times to pixels inside the plot area
+type chartPlot struct {
+	area       image.Rectangle
+	bars       []*models.PriceData
+	low, high  float64
+	slot       float64
+	barSpacing time.Duration
+}
+
+// y returns the pixel row of a price
+func (p *chartPlot) y(price float64) float64 {
+	return float64(p.area.Max.Y) - (price-p.low)/(p.high-p.low)*float64(p.area.Dy())
+}
+
+// x returns the pixel column of a time, interpolating between bars and extrapolating past either end
+func (p *chartPlot) x(t time.Time) float64 {
+	n := len(p.bars)
+	i := sort.Search(n, func(i int) bool { return !p.bars[i].Timestamp.Before(t) })
+
+	var index float64
+	switch {
+	case i == 0:
+		index = -float64(p.bars[0].Timestamp.Sub(t)) / float64(p.barSpacing)
+	case i == n:
+		index = float64(n-1) + float64(t.Sub(p.bars[n-1].Timestamp))/float64(p.barSpacing)
+	default:
+		before, after := p.bars[i-1].Timestamp, p.bars[i].Timestamp
+		index = float64(i-1) + float64(t.Sub(before))/float64(after.Sub(before))
+	}
+	return float64(p.area.Min.X) + (index+0.5)*p.slot
+}
+
+// RenderChart draws the candles of a spec with its lines, levels and markers and encodes the image as PNG
+func RenderChart(spec *ChartSpec) ([]byte, error) {
+	if len(spec.Bars) == 0 {
+		return nil, fmt.Errorf("no bars to chart")
+	}
+	width, height := spec.Width, spec.Height
+	if width <= 0 {
+		width = defaultChartWidth
+	}
+	if height <= 0 {
+		height = defaultChartHeight
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{chartBackground}, image.Point{}, draw.Src)
+
+	// Title on top, price axis on the right, time axis below
+	area := image.Rect(10, 30, width-70, height-22)
+	if area.Dx() < 50 || area.Dy() < 50 {
+		return nil, fmt.Errorf("chart size %dx%d is too small", width, height)
+	}
+	drawText(img, 10, 8, spec.Title, chartAxis, 2)
+
+	plot := &chartPlot{area: area, bars: spec.Bars, slot: float64(area.Dx()) / float64(len(spec.Bars))}
+	plot.barSpacing = time.Minute
+	if n := len(spec.Bars); n > 1 {
+		plot.barSpacing = max(spec.Bars[n-1].Timestamp.Sub(spec.Bars[0].Timestamp)/time.Duration(n-1), time.Minute)
+	}
+
+	// Price range: the candles, pattern levels and line endpoints, padded so nothing touches the edge
+	plot.low, plot.high = barRange(spec.Bars)
+	for _, level := range spec.Levels {
+		if level.Price > 0 {
+			plot.low = math.Min(plot.low, level.Price)
+			plot.high = math.Max(plot.high, level.Price)
+		}
+	}
+	for _, line := range spec.Lines {
+		plot.low = math.Min(plot.low, math.Min(line.From.Price, line.To.Price))
+		plot.high = math.Max(plot.high, math.Max(line.From.Price, line.To.Price))
+	}
+	if plot.high <= plot.low {
+		plot.high = plot.low*1.01 + 0.01
+	}
+	pad := (plot.high - plot.low) * 0.05
+	plot.low -= pad
+	plot.high += pad
+
+	drawPriceAxis(img, plot)
+	drawTimeAxis(img, plot)
+
+	// Candles
+	bodyWidth := max(int(plot.slot*0.6), 1)
+	for i, bar := range spec.Bars {
+		candleColor := chartUp
+		if bar.Close < bar.Open {
+			candleColor = chartDown
+		}
+		x := int(float64(area.Min.X) + (float64(i)+0.5)*plot.slot)
+		drawLine(img, area, float64(x), plot.y(bar.High), float64(x), plot.y(bar.Low), candleColor, false)
+
+		top, bottom := int(plot.y(math.Max(bar.Open, bar.Close))), int(plot.y(math.Min(bar.Open, bar.Close)))
+		fillRect(img, image.Rect(x-bodyWidth/2, top, x-bodyWidth/2+bodyWidth, max(bottom, top+1)).Intersect(area), candleColor)
+	}
+
+	for _, level := range spec.Levels {
+		if level.Price <= 0 {
+			continue
+		}
+		y := plot.y(level.Price)
+		drawLine(img, area, float64(area.Min.X), y, float64(area.Max.X), y, level.Color, level.Dashed)
+
+		label := level.Label + " " + formatChartPrice(level.Price, plot.high-plot.low)
+		labelX := area.Max.X - textWidth(label, 1) - 4
+		labelY := int(y) - glyphHeight - 3
+		fillRect(img, image.Rect(labelX-2, labelY-1, area.Max.X-2, labelY+glyphHeight+1).Intersect(area), chartBackground)
+		drawText(img, labelX, labelY, label, level.Color, 1)
+	}
+
+	for _, line := range spec.Lines {
+		x1, y1 := plot.x(line.From.Timestamp), plot.y(line.From.Price)
+		x2, y2 := plot.x(line.To.Timestamp), plot.y(line.To.Price)
+		if line.Extend && x2 != x1 {
+			slope := (y2 - y1) / (x2 - x1)
+			y2 += slope * (float64(area.Max.X) - x2)
+			x2 = float64(area.Max.X)
+		}
+		drawLine(img, area, x1, y1, x2, y2, line.Color, false)
+		drawLine(img, area, x1, y1+1, x2, y2+1, line.Color, false)
+	}
+
+	for _, marker := range spec.Markers {
+		x, y := int(plot.x(marker.Timestamp)), int(plot.y(marker.Price))
+		fillRect(img, image.Rect(x-3, y-3, x+4, y+4).Intersect(area), chartPattern)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode chart: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// drawPriceAxis draws horizontal grid lines at round prices with their labels right of the plot
+func drawPriceAxis(img *image.RGBA, plot *chartPlot) {
+	step := niceStep((plot.high - plot.low) / 6)
+	for price := math.Ceil(plot.low/step) * step; price <= plot.high; price += step {
+		y := plot.y(price)
+		drawLine(img, plot.area, float64(plot.area.Min.X), y, float64(plot.area.Max.X), y, chartGrid, false)
+		drawText(img, plot.area.Max.X+6, int(y)-glyphHeight/2, formatChartPrice(price, plot.high-plot.low), chartAxis, 1)
+	}
+
+	fillRect(img, image.Rect(plot.area.Max.X, plot.area.Min.Y, plot.area.Max.X+1, plot.area.Max.Y+1), chartAxis)
+	fillRect(img, image.Rect(plot.area.Min.X, plot.area.Max.Y, plot.area.Max.X+1, plot.area.Max.Y+1), chartAxis)
+}
+
+// drawTimeAxis labels about five evenly spaced candles below the plot
+func drawTimeAxis(img *image.RGBA, plot *chartPlot) {
+	bars := plot.bars
+	layout := "01/02"
+	if bars[len(bars)-1].Timestamp.Sub(bars[0].Timestamp) < 72*time.Hour {
+		layout = "01/02 15:04"
+	}
+
+	labels := min(5, len(bars))
+	for i := 0; i < labels; i++ {
+		index := 0
+		if labels > 1 {
+			index = i * (len(bars) - 1) / (labels - 1)
+		}
+		label := bars[index].Timestamp.Format(layout)
+		x := int(float64(plot.area.Min.X)+(float64(index)+0.5)*plot.slot) - textWidth(label, 1)/2
+		x = min(max(x, plot.area.Min.X), plot.area.Max.X-textWidth(label, 1))
+		drawText(img, x, plot.area.Max.Y+6, label, chartAxis, 1)
+	}
+}
+
+// niceStep rounds a raw grid step up to 1, 2 or 5 times a power of ten
+func niceStep(raw float64) float64 {
+	if raw <= 0 {
+		return 1
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, factor := range []float64{1, 2, 5} {
+		if raw <= factor*magnitude {
+			return factor * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+// formatChartPrice formats a price with enough decimals to tell grid lines over the range apart
+func formatChartPrice(price, priceRange float64) string {
+	decimals := 2
+	if priceRange < 1 {
+		decimals = 3
+	}
+	if priceRange > 1000 {
+		decimals = 0
+	}
+	return strconv.FormatFloat(price, 'f', decimals, 64)
+}
+
+// fillRect fills a rectangle with a color
+func fillRect(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	draw.Draw(img, r.Intersect(img.Bounds()), &image.Uniform{c}, image.Point{}, draw.Src)
+}
+
+// drawLine draws a one pixel line, optionally dashed, clipped to the plot area
+func drawLine(img *image.RGBA, clip image.Rectangle, x1, y1, x2, y2 float64, c color.RGBA, dashed bool) {
+	steps := int(math.Max(math.Abs(x2-x1), math.Abs(y2-y1)))
+	if steps == 0 {
+		steps = 1
+	}
+	for i := 0; i <= steps; i++ {
+		if dashed && (i/4)%2 == 1 {
+			continue
+		}
+		t := float64(i) / float64(steps)
+		x := int(math.Round(x1 + (x2-x1)*t))
+		y := int(math.Round(y1 + (y2-y1)*t))
+		if (image.Point{X: x, Y: y}).In(clip.Inset(-1).Intersect(img.Bounds())) {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
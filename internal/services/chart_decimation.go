@@ -0,0 +1,78 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// chartSessionShare is the share of wall-clock time covered by regular sessions (6.5 hours on 5 of 7 days),
+// used to estimate how many bars a chart range holds
+const chartSessionShare = 6.5 / 24 * 5 / 7
+
+// chartTimeframes are the candle sizes a chart can be rolled up to, finest first
+var chartTimeframes = []models.Timeframe{models.Timeframe1m, models.Timeframe5m, models.Timeframe15m, models.Timeframe1h, models.Timeframe1d}
+
+// ChartTimeframe returns the finest candle size that keeps a chart range's regular session bars within maxPoints
+func ChartTimeframe(span time.Duration, maxPoints int) models.Timeframe {
+	for _, tf := range chartTimeframes {
+		if tf == models.Timeframe1d {
+			break
+		}
+		if float64(span)*chartSessionShare/float64(tf.Duration()) <= float64(maxPoints) {
+			return tf
+		}
+	}
+	return models.Timeframe1d
+}
+
+// DownsampleLTTB reduces line points to threshold points with the Largest-Triangle-Three-Buckets algorithm,
+// which keeps the first and last points and, from each bucket in between, the point forming the largest
+// triangle with the previously kept point and the next bucket's average. That preserves the peaks and troughs
+// a plain stride would drop. Points are returned unchanged when there are no more than threshold of them.
+func DownsampleLTTB(points []models.TradingViewLinePoint, threshold int) []models.TradingViewLinePoint {
+	if threshold < 3 || len(points) <= threshold {
+		return points
+	}
+
+	sampled := make([]models.TradingViewLinePoint, 0, threshold)
+	sampled = append(sampled, points[0])
+
+	// Buckets split the points between the first and last evenly
+	bucketSize := float64(len(points)-2) / float64(threshold-2)
+	kept := 0
+
+	for i := 0; i < threshold-2; i++ {
+		start := int(float64(i)*bucketSize) + 1
+		end := int(float64(i+1)*bucketSize) + 1
+
+		// Average of the next bucket, or the last point for the final bucket
+		nextStart, nextEnd := end, min(int(float64(i+2)*bucketSize)+1, len(points))
+		if i == threshold-3 {
+			nextStart, nextEnd = len(points)-1, len(points)
+		}
+		var avgTime, avgValue float64
+		for _, p := range points[nextStart:nextEnd] {
+			avgTime += float64(p.Time)
+			avgValue += p.Value
+		}
+		avgTime /= float64(nextEnd - nextStart)
+		avgValue /= float64(nextEnd - nextStart)
+
+		a := points[kept]
+		best, bestArea := start, -1.0
+		for j := start; j < end; j++ {
+			area := math.Abs((float64(a.Time)-avgTime)*(points[j].Value-a.Value) -
+				(float64(a.Time)-float64(points[j].Time))*(avgValue-a.Value))
+			if area > bestArea {
+				best, bestArea = j, area
+			}
+		}
+
+		sampled = append(sampled, points[best])
+		kept = best
+	}
+
+	return append(sampled, points[len(points)-1])
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestChartTimeframe tests that longer ranges are rolled up to coarser candles
+func TestChartTimeframe(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		span time.Duration
+		want models.Timeframe
+	}{
+		{7 * day, models.Timeframe1m},
+		{30 * day, models.Timeframe5m},
+		{60 * day, models.Timeframe15m},
+		{365 * day, models.Timeframe1h},
+		{10 * 365 * day, models.Timeframe1d},
+	}
+	for _, tt := range tests {
+		if got := ChartTimeframe(tt.span, 2000); got != tt.want {
+			t.Errorf("%v: expected %s, got %s", tt.span, tt.want, got)
+		}
+	}
+}
+
+// TestDownsampleLTTB tests that downsampling keeps the endpoints and the extremes within the threshold
+func TestDownsampleLTTB(t *testing.T) {
+	points := make([]models.TradingViewLinePoint, 1000)
+	for i := range points {
+		points[i] = models.TradingViewLinePoint{Time: int64(i * 60), Value: 100 + math.Sin(float64(i)/20)}
+	}
+	points[437].Value = 150
+	points[811].Value = 50
+
+	sampled := DownsampleLTTB(points, 100)
+	if len(sampled) != 100 {
+		t.Fatalf("expected 100 points, got %d", len(sampled))
+	}
+	if sampled[0] != points[0] || sampled[99] != points[999] {
+		t.Errorf("expected the first and last points to be kept")
+	}
+
+	var peak, trough bool
+	for i, p := range sampled {
+		peak = peak || p.Value == 150
+		trough = trough || p.Value == 50
+		if i > 0 && p.Time <= sampled[i-1].Time {
+			t.Fatalf("expected points in time order, got %d after %d", p.Time, sampled[i-1].Time)
+		}
+	}
+	if !peak || !trough {
+		t.Errorf("expected the spike and the dip to be kept, got peak %v trough %v", peak, trough)
+	}
+
+	if short := DownsampleLTTB(points[:50], 100); len(short) != 50 {
+		t.Errorf("expected short series unchanged, got %d points", len(short))
+	}
+}
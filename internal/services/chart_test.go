@@ -0,0 +1,82 @@
+package services
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestRenderChart tests that a pattern chart encodes as a PNG of the requested size with candles and overlays drawn
+func TestRenderChart(t *testing.T) {
+	start := time.Date(2024, time.March, 4, 14, 30, 0, 0, time.UTC)
+	var bars []*models.PriceData
+	for i := 0; i < 40; i++ {
+		open := 100 + float64(i%10)
+		bars = append(bars, &models.PriceData{
+			Timestamp: start.Add(time.Duration(i) * 5 * time.Minute),
+			Open:      open,
+			High:      open + 2,
+			Low:       open - 1,
+			Close:     open + 1,
+		})
+	}
+
+	point := func(i int, price float64) models.PatternPoint {
+		return models.PatternPoint{Timestamp: bars[i].Timestamp, Price: price}
+	}
+	spec := &ChartSpec{
+		Title:   "TEST RISING WEDGE",
+		Bars:    bars,
+		Lines:   []ChartLine{{From: point(5, 111), To: point(25, 108), Color: chartPattern, Extend: true}},
+		Levels:  []ChartLevel{{Price: 95, Label: "TARGET", Color: chartTarget, Dashed: true}},
+		Markers: []models.PatternPoint{point(5, 111)},
+		Width:   640,
+		Height:  360,
+	}
+
+	data, err := RenderChart(spec)
+	if err != nil {
+		t.Fatalf("RenderChart failed: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("chart is not a PNG: %v", err)
+	}
+	if img.Bounds() != image.Rect(0, 0, 640, 360) {
+		t.Fatalf("unexpected chart bounds %v", img.Bounds())
+	}
+
+	counts := make(map[[3]uint32]int)
+	for y := 0; y < 360; y++ {
+		for x := 0; x < 640; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			counts[[3]uint32{r >> 8, g >> 8, b >> 8}]++
+		}
+	}
+	for name, c := range map[string][3]uint32{
+		"up candle": {uint32(chartUp.R), uint32(chartUp.G), uint32(chartUp.B)},
+		"pattern":   {uint32(chartPattern.R), uint32(chartPattern.G), uint32(chartPattern.B)},
+		"target":    {uint32(chartTarget.R), uint32(chartTarget.G), uint32(chartTarget.B)},
+	} {
+		if counts[c] == 0 {
+			t.Errorf("no %s pixels drawn", name)
+		}
+	}
+
+	if _, err := RenderChart(&ChartSpec{}); err == nil {
+		t.Error("expected an error for a chart without bars")
+	}
+}
+
+// TestNiceStep tests rounding grid steps up to 1, 2 or 5 times a power of ten
+func TestNiceStep(t *testing.T) {
+	for raw, expected := range map[float64]float64{0.3: 0.5, 1: 1, 1.2: 2, 3: 5, 7: 10, 42: 50} {
+		if step := niceStep(raw); step < expected*0.999 || step > expected*1.001 {
+			t.Errorf("niceStep(%v) = %v, expected %v", raw, step, expected)
+		}
+	}
+}
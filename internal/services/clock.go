@@ -0,0 +1,70 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock supplies the current time to the analysis and detection services
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock reads the wall clock
+type systemClock struct{}
+
+// Now returns the current wall-clock time
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+var (
+	serviceClock      Clock = systemClock{}
+	serviceClockMutex sync.RWMutex
+)
+
+// SetClock replaces the clock used by detection and analysis, e.g. with a simulated clock in replay mode
+func SetClock(clock Clock) {
+	serviceClockMutex.Lock()
+	defer serviceClockMutex.Unlock()
+
+	if clock == nil {
+		clock = systemClock{}
+	}
+	serviceClock = clock
+}
+
+// clockNow returns the current time according to the service clock
+func clockNow() time.Time {
+	serviceClockMutex.RLock()
+	defer serviceClockMutex.RUnlock()
+
+	return serviceClock.Now()
+}
+
+// SimulatedClock is a clock that only moves when it is set, used to replay stored data
+type SimulatedClock struct {
+	now   time.Time
+	mutex sync.RWMutex
+}
+
+// NewSimulatedClock creates a simulated clock starting at the given time
+func NewSimulatedClock(start time.Time) *SimulatedClock {
+	return &SimulatedClock{now: start}
+}
+
+// Now returns the simulated time
+func (sc *SimulatedClock) Now() time.Time {
+	sc.mutex.RLock()
+	defer sc.mutex.RUnlock()
+
+	return sc.now
+}
+
+// Set moves the simulated clock to the given time
+func (sc *SimulatedClock) Set(now time.Time) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	sc.now = now
+}
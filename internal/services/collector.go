@@ -1,8 +1,12 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,12 +18,29 @@ import (
 )
 
 type CollectorService struct {
-	db      *database.Database
-	polygon *PolygonService
-	cfg     *config.Config
-	cron    *cron.Cron
-	stats   *CollectionStats
-	mutex   sync.RWMutex
+	db            *database.Database
+	provider      MarketDataProvider
+	cfg           *config.Config
+	cron          *cron.Cron
+	collectJob    cron.EntryID // scheduled collection, replaced when the interval changes
+	stats         *CollectionStats
+	streaming     *StreamingService
+	alertRules    *AlertRuleService
+	anomalies     *AnomalyService
+	srBreaks      *SRBreakService
+	srTouches     *SRTouchService
+	setups        *SetupDetectionService
+	options       *OptionsService
+	realtime      *PolygonStream
+	calendar      *MarketCalendar
+	notifications *NotificationService
+	prices        *PriceCache
+	schedules     *ScheduleControl
+	benchmarks    []string // collected along with the watched symbols
+	requeued      []string // symbols skipped by rate limiting, collected first on the next run
+	failing       bool     // the last run failed; only the first failure of a streak is notified
+	mutex         sync.RWMutex
+	wg            sync.WaitGroup // tracks collections started outside the cron scheduler
 }
 
 type CollectionStats struct {
@@ -27,21 +48,29 @@ type CollectionStats struct {
 	NextRun        time.Time `json:"next_run"`
 	SuccessfulRuns int       `json:"successful_runs"`
 	FailedRuns     int       `json:"failed_runs"`
+	SkippedRuns    int       `json:"skipped_runs"` // scheduled runs skipped outside the configured market sessions
 	LastError      string    `json:"last_error"`
 	CollectedToday int       `json:"collected_today"`
 	IsRunning      bool      `json:"is_running"`
+	Paused         bool      `json:"paused"` // scheduled and forced collections are skipped
 	TotalCollected int64     `json:"total_collected"`
+
+	MarketSession   models.MarketSession `json:"market_session,omitempty"`
+	RequeuedSymbols []string        `json:"requeued_symbols,omitempty"`
+	RateLimit       *RateLimitStats      `json:"rate_limit,omitempty"`
+	Realtime        *PolygonStreamStatus `json:"realtime,omitempty"`
+	Inserts         map[string]*models.InsertStats `json:"inserts,omitempty"` // batch insert throughput per table
 }
 
 // NewCollectorService creates a new data collector service
-func NewCollectorService(db *database.Database, polygon *PolygonService, cfg *config.Config) *CollectorService {
+func NewCollectorService(db *database.Database, provider MarketDataProvider, cfg *config.Config) *CollectorService {
 	cronInstance := cron.New(cron.WithLocation(time.UTC))
 
 	return &CollectorService{
-		db:      db,
-		polygon: polygon,
-		cfg:     cfg,
-		cron:    cronInstance,
+		db:       db,
+		provider: provider,
+		cfg:      cfg,
+		cron:     cronInstance,
 		stats: &CollectionStats{
 			LastRun:        time.Time{},
 			NextRun:        time.Time{},
@@ -55,6 +84,91 @@ func NewCollectorService(db *database.Database, polygon *PolygonService, cfg *co
 	}
 }
 
+// SetStreamingService sets the streaming service used to push newly collected bars
+func (cs *CollectorService) SetStreamingService(streaming *StreamingService) {
+	cs.streaming = streaming
+}
+
+// SetAlertRuleService sets the alert rule service evaluated after each collection cycle
+func (cs *CollectorService) SetAlertRuleService(alertRules *AlertRuleService) {
+	cs.alertRules = alertRules
+}
+
+// SetAnomalyService sets the anomaly detector run on collected bars before alert rules are evaluated
+func (cs *CollectorService) SetAnomalyService(anomalies *AnomalyService) {
+	cs.anomalies = anomalies
+}
+
+// SetSRTouchService sets the recorder of S/R level touches run on collected bars after breaks are alerted on
+func (cs *CollectorService) SetSRTouchService(srTouches *SRTouchService) {
+	cs.srTouches = srTouches
+}
+
+// SetSRBreakService sets the S/R break detector run on collected bars before alert rules are evaluated
+func (cs *CollectorService) SetSRBreakService(srBreaks *SRBreakService) {
+	cs.srBreaks = srBreaks
+}
+
+// SetSetupService sets the setup service whose setup statuses are advanced after each collection cycle
+func (cs *CollectorService) SetSetupService(setups *SetupDetectionService) {
+	cs.setups = setups
+}
+
+// SetOptionsService sets the options service used to collect chain snapshots during collection
+func (cs *CollectorService) SetOptionsService(options *OptionsService) {
+	cs.options = options
+}
+
+// SetRealtimeStream sets the WebSocket stream that replaces REST polling for symbols it covers
+func (cs *CollectorService) SetRealtimeStream(realtime *PolygonStream) {
+	cs.realtime = realtime
+}
+
+// SetMarketCalendar limits scheduled collection to the calendar's configured market sessions
+func (cs *CollectorService) SetMarketCalendar(calendar *MarketCalendar) {
+	cs.calendar = calendar
+}
+
+// SetNotificationService records collection failures in the notification center
+func (cs *CollectorService) SetNotificationService(notifications *NotificationService) {
+	cs.notifications = notifications
+}
+
+// SetPriceCache sets the price cache kept up to date with ingested bars and invalidated by backfills
+func (cs *CollectorService) SetPriceCache(prices *PriceCache) {
+	cs.prices = prices
+}
+
+// SetScheduleControl sets the control pausing collection and cleanup
+func (cs *CollectorService) SetScheduleControl(schedules *ScheduleControl) {
+	cs.schedules = schedules
+}
+
+// SetBenchmarks sets the index and sector ETF symbols collected whether or not they are watched
+func (cs *CollectorService) SetBenchmarks(benchmarks []string) {
+	cs.benchmarks = benchmarks
+}
+
+// CollectionSymbols returns the watched symbols followed by the benchmarks that aren't watched
+func (cs *CollectorService) CollectionSymbols() ([]string, error) {
+	watched, err := cs.db.GetWatchedSymbols()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watched symbols: %w", err)
+	}
+	return withBenchmarks(watched, cs.benchmarks), nil
+}
+
+// withBenchmarks appends the benchmarks missing from symbols
+func withBenchmarks(symbols, benchmarks []string) []string {
+	all := slices.Clone(symbols)
+	for _, benchmark := range benchmarks {
+		if !slices.Contains(all, benchmark) {
+			all = append(all, benchmark)
+		}
+	}
+	return all
+}
+
 // Start begins the scheduled data collection
 func (cs *CollectorService) Start() error {
 	// Convert interval to cron expression
@@ -64,17 +178,23 @@ func (cs *CollectorService) Start() error {
 	}
 
 	// Schedule the collection job
-	_, err = cs.cron.AddFunc(cronExpr, cs.collectData)
+	cs.collectJob, err = cs.cron.AddFunc(cronExpr, cs.scheduledCollect)
 	if err != nil {
 		return fmt.Errorf("failed to schedule collection job: %w", err)
 	}
 
 	// Schedule daily cleanup job (runs at 2 AM UTC)
-	_, err = cs.cron.AddFunc("0 2 * * *", cs.cleanupOldData)
+	_, err = cs.cron.AddFunc("0 2 * * *", cs.scheduledCleanup)
 	if err != nil {
 		return fmt.Errorf("failed to schedule cleanup job: %w", err)
 	}
 
+	// Schedule daily purge of archived setups and patterns (runs at 2:30 AM UTC)
+	_, err = cs.cron.AddFunc("30 2 * * *", cs.scheduledPurge)
+	if err != nil {
+		return fmt.Errorf("failed to schedule purge job: %w", err)
+	}
+
 	// Start the cron scheduler
 	cs.cron.Start()
 
@@ -83,12 +203,51 @@ func (cs *CollectorService) Start() error {
 
 	log.Printf("Data collector started with interval: %v", cs.cfg.Collection.Interval)
 
-	// Run initial collection immediately (24/7 data collection mode)
-	go cs.collectData()
+	// Run initial collection immediately, even outside market hours, so the latest bars are available
+	cs.goCollect()
 
 	return nil
 }
 
+// SetInterval reschedules collection at a new interval, e.g. after the config file is reloaded
+func (cs *CollectorService) SetInterval(interval time.Duration) error {
+	cronExpr, err := cs.intervalToCron(interval)
+	if err != nil {
+		return fmt.Errorf("failed to convert interval to cron expression: %w", err)
+	}
+
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	// Before Start only the interval needs to change
+	if cs.collectJob != 0 {
+		job, err := cs.cron.AddFunc(cronExpr, cs.scheduledCollect)
+		if err != nil {
+			return fmt.Errorf("failed to schedule collection job: %w", err)
+		}
+		cs.cron.Remove(cs.collectJob)
+		cs.collectJob = job
+	}
+
+	cs.cfg.Collection.Interval = interval
+	cs.updateNextRunTime()
+	log.Printf("Data collection interval changed to %v", interval)
+	return nil
+}
+
+// NextScheduledRun returns when the scheduler runs the next collection, zero before Start. The time stays in
+// the past once the scheduler is stopped.
+func (cs *CollectorService) NextScheduledRun() time.Time {
+	cs.mutex.RLock()
+	job := cs.collectJob
+	cs.mutex.RUnlock()
+
+	if job == 0 {
+		return time.Time{}
+	}
+	return cs.cron.Entry(job).Next
+}
+
 // Stop stops the scheduled data collection
 func (cs *CollectorService) Stop() {
 	if cs.cron != nil {
@@ -97,8 +256,76 @@ func (cs *CollectorService) Stop() {
 	}
 }
 
-// collectData performs the actual data collection
-func (cs *CollectorService) collectData() {
+// Shutdown stops the scheduler and waits for in-flight collections (and their batch inserts) to finish
+func (cs *CollectorService) Shutdown(ctx context.Context) error {
+	// cron.Stop returns a context that is done once running jobs complete
+	cronDone := cs.cron.Stop()
+
+	done := make(chan struct{})
+	go func() {
+		<-cronDone.Done()
+		cs.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Printf("Data collector stopped")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for data collection to finish: %w", ctx.Err())
+	}
+}
+
+// goCollect runs a collection in the background, tracked for shutdown
+func (cs *CollectorService) goCollect() {
+	cs.wg.Add(1)
+	go func() {
+		defer cs.wg.Done()
+		cs.collectData(nil)
+	}()
+}
+
+// scheduledCollect runs a cron collection. While the exchange is closed only the symbols whose markets are
+// open, crypto and FX, are collected, and the run is skipped when there are none.
+func (cs *CollectorService) scheduledCollect() {
+	if cs.schedules.Skip(models.ScheduleCollection) {
+		cs.mutex.Lock()
+		cs.updateNextRunTime()
+		cs.mutex.Unlock()
+		return
+	}
+
+	now := clockNow()
+	if cs.calendar == nil || cs.calendar.ShouldCollect(now) {
+		cs.collectData(nil)
+		return
+	}
+
+	symbols, err := cs.db.GetWatchedSymbols()
+	if err != nil {
+		log.Printf("Failed to get watched symbols: %v", err)
+		return
+	}
+	var due []string
+	for _, symbol := range symbols {
+		if cs.calendar.ShouldCollectSymbol(symbol, now) {
+			due = append(due, symbol)
+		}
+	}
+
+	if len(due) == 0 {
+		cs.mutex.Lock()
+		cs.stats.SkippedRuns++
+		cs.updateNextRunTime()
+		cs.mutex.Unlock()
+		return
+	}
+	cs.collectData(due)
+}
+
+// collectData performs the actual data collection for symbols, or every watched symbol when nil
+func (cs *CollectorService) collectData(symbols []string) {
 	cs.mutex.Lock()
 	if cs.stats.IsRunning {
 		cs.mutex.Unlock()
@@ -116,15 +343,23 @@ func (cs *CollectorService) collectData() {
 		cs.mutex.Unlock()
 	}()
 
-	// Note: We collect data 24/7 to capture pre-market, after-hours, and extended trading
-	// This ensures we don't miss important price movements that happen outside regular market hours
-	log.Printf("Collecting data (24/7 mode - captures pre-market, after-hours, and extended trading)")
+	if cs.calendar != nil {
+		log.Printf("Collecting data (%s session)", cs.calendar.Session(clockNow()))
+	} else {
+		log.Printf("Collecting data")
+	}
 
-	// Get watched symbols from database
-	symbols, err := cs.db.GetWatchedSymbols()
-	if err != nil {
-		log.Printf("Failed to get watched symbols: %v", err)
-		return
+	// Get watched symbols from database, with the benchmarks that aren't watched. Those only feed
+	// market context and relative strength, so alerts, scans and setups run on the watched symbols.
+	analyzed := symbols
+	if symbols == nil {
+		watched, err := cs.db.GetWatchedSymbols()
+		if err != nil {
+			log.Printf("Failed to get watched symbols: %v", err)
+			return
+		}
+		analyzed = watched
+		symbols = withBenchmarks(watched, cs.benchmarks)
 	}
 
 	if len(symbols) == 0 {
@@ -132,15 +367,37 @@ func (cs *CollectorService) collectData() {
 		return
 	}
 
+	// Symbols skipped by rate limiting last run go first
+	cs.mutex.Lock()
+	symbols = prioritizeSymbols(symbols, cs.requeued)
+	cs.mutex.Unlock()
+
 	log.Printf("Starting data collection for symbols: %v", symbols)
 
 	// Collect data for all symbols
 	collectedCount := 0
 	errorCount := 0
+	var skipped []string
+	paced := cs.providerPaced()
+
+	for i, symbol := range symbols {
+		// Streamed symbols are written as bars arrive; REST polling resumes if the socket drops
+		if cs.realtime.IsStreaming(symbol) {
+			continue
+		}
 
-	for _, symbol := range symbols {
 		log.Printf("Starting data collection for symbol: %s", symbol)
 		count, err := cs.collectSymbolData(symbol)
+		if errors.Is(err, ErrBudgetExhausted) {
+			skipped = append(skipped, symbols[i:]...)
+			log.Printf("Request budget exhausted, re-queuing %d symbols for the next run", len(symbols)-i)
+			break
+		}
+		if errors.Is(err, ErrUpstreamRateLimited) {
+			skipped = append(skipped, symbol)
+			log.Printf("Rate limited while collecting %s, re-queuing for the next run", symbol)
+			continue
+		}
 		if err != nil {
 			log.Printf("ERROR: Failed to collect data for %s: %v", symbol, err)
 			errorCount++
@@ -153,58 +410,238 @@ func (cs *CollectorService) collectData() {
 			log.Printf("WARNING: No new data points collected for %s (may already be up to date)", symbol)
 		}
 
-		// Small delay between symbol requests to respect rate limits
-		time.Sleep(100 * time.Millisecond)
+		// Providers without their own rate limiter get a small delay between symbols
+		if !paced {
+			time.Sleep(100 * time.Millisecond)
+		}
 	}
 
 	// Update statistics
 	cs.mutex.Lock()
-	if errorCount == 0 {
-		cs.stats.SuccessfulRuns++
-		cs.stats.LastError = ""
-	} else {
+	cs.requeued = skipped
+	startedFailing := errorCount > 0 && !cs.failing
+	cs.failing = errorCount > 0
+	switch {
+	case errorCount > 0:
 		cs.stats.FailedRuns++
 		cs.stats.LastError = fmt.Sprintf("Failed to collect data for %d symbols", errorCount)
+	case len(skipped) > 0:
+		cs.stats.SuccessfulRuns++
+		cs.stats.LastError = fmt.Sprintf("Rate limited, %d symbols re-queued", len(skipped))
+	default:
+		cs.stats.SuccessfulRuns++
+		cs.stats.LastError = ""
 	}
 	cs.stats.CollectedToday += collectedCount
 	cs.stats.TotalCollected += int64(collectedCount)
 	cs.mutex.Unlock()
 
-	log.Printf("Data collection completed. Collected: %d, Errors: %d", collectedCount, errorCount)
+	if startedFailing {
+		cs.notifications.Notify(&models.Notification{
+			Category: models.NotificationSystem,
+			Severity: EmailSeverityHigh,
+			Title:    "⚠️ Data collection failing",
+			Message:  fmt.Sprintf("Failed to collect data for %d of %d symbols; see the collection status for details", errorCount, len(symbols)),
+			Link:     "/api/collection/status",
+		})
+	}
+
+	log.Printf("Data collection completed. Collected: %d, Errors: %d, Re-queued: %d", collectedCount, errorCount, len(skipped))
+
+	if cs.anomalies.IsEnabled() {
+		anomalies, err := cs.anomalies.ScanSymbols(analyzed)
+		if err != nil {
+			log.Printf("Failed to scan for anomalies: %v", err)
+		} else if len(anomalies) > 0 {
+			log.Printf("Anomalies flagged: %d", len(anomalies))
+		}
+	}
+
+	if cs.srBreaks.IsEnabled() {
+		breaks, err := cs.srBreaks.ScanSymbols(analyzed)
+		if err != nil {
+			log.Printf("Failed to scan for S/R breaks: %v", err)
+		} else if len(breaks) > 0 {
+			log.Printf("S/R breaks recorded: %d", len(breaks))
+		}
+	}
+
+	// Runs after the break alerts, which skip breaks recorded before
+	if cs.srTouches.IsEnabled() {
+		touches, err := cs.srTouches.RecordSymbols(analyzed)
+		if err != nil {
+			log.Printf("Failed to record S/R touches: %v", err)
+		} else if len(touches) > 0 {
+			log.Printf("S/R touches recorded: %d", len(touches))
+		}
+	}
+
+	if cs.alertRules != nil {
+		triggers, err := cs.alertRules.EvaluateRules(analyzed)
+		if err != nil {
+			log.Printf("Failed to evaluate alert rules: %v", err)
+		} else if len(triggers) > 0 {
+			log.Printf("Alert rules triggered: %d", len(triggers))
+		}
+	}
+
+	if cs.setups != nil {
+		for _, symbol := range analyzed {
+			if err := cs.setups.UpdateSetupStatus(symbol); err != nil {
+				log.Printf("Failed to update setup statuses for %s: %v", symbol, err)
+			}
+		}
+	}
+
+	if cs.options.IsEnabled() {
+		cs.options.CollectDue(equitySymbols(analyzed))
+	}
+}
+
+// IngestRealtimeBars stores 1-minute bars from the WebSocket stream through the batch insert path
+func (cs *CollectorService) IngestRealtimeBars(bars []*models.PriceData) {
+	bySymbol := make(map[string][]*models.PriceData)
+	for _, bar := range bars {
+		bySymbol[bar.Symbol] = append(bySymbol[bar.Symbol], bar)
+	}
+
+	stored := 0
+	for symbol, symbolBars := range bySymbol {
+		newBars, err := cs.filterNewPriceData(cs.prepareBars(symbolBars))
+		if err != nil {
+			log.Printf("Failed to filter streamed bars for %s: %v", symbol, err)
+			continue
+		}
+		if len(newBars) == 0 {
+			continue
+		}
+
+		if err := cs.db.InsertPriceDataBatch(newBars); err != nil {
+			log.Printf("Failed to insert streamed price bars for %s: %v", symbol, err)
+			continue
+		}
+		if cs.prices != nil {
+			cs.prices.Append(newBars)
+		}
+
+		volumeBars := make([]*models.VolumeData, 0, len(newBars))
+		for _, bar := range newBars {
+			volumeBars = append(volumeBars, &models.VolumeData{
+				Symbol:    bar.Symbol,
+				Timestamp: bar.Timestamp,
+				Volume:    bar.Volume,
+				CreatedAt: bar.CreatedAt,
+			})
+		}
+		newVolume, err := cs.filterNewVolumeData(volumeBars)
+		if err != nil {
+			log.Printf("Failed to filter streamed volume bars for %s: %v", symbol, err)
+		} else if len(newVolume) > 0 {
+			if err := cs.db.InsertVolumeDataBatch(newVolume); err != nil {
+				log.Printf("Failed to insert streamed volume bars for %s: %v", symbol, err)
+			}
+		}
+
+		stored += len(newBars)
+		if cs.streaming != nil {
+			cs.streaming.PublishPriceBars(symbol, newBars)
+			if len(newVolume) > 0 {
+				cs.streaming.PublishVolumeBars(symbol, newVolume)
+			}
+		}
+	}
+
+	if stored > 0 {
+		cs.mutex.Lock()
+		cs.stats.CollectedToday += stored
+		cs.stats.TotalCollected += int64(stored)
+		cs.mutex.Unlock()
+	}
 }
 
 // collectSymbolData collects data for a single symbol
 func (cs *CollectorService) collectSymbolData(symbol string) (int, error) {
 	// Collect volume data
-	volumeCount, err := cs.collectVolumeData(symbol)
-	if err != nil {
-		log.Printf("Failed to collect volume data for %s: %v", symbol, err)
+	volumeCount, volumeErr := cs.collectVolumeData(symbol)
+	if volumeErr != nil {
+		log.Printf("Failed to collect volume data for %s: %v", symbol, volumeErr)
+		if errors.Is(volumeErr, ErrBudgetExhausted) {
+			return 0, volumeErr
+		}
 	}
 
 	// Collect price data
-	priceCount, err := cs.collectPriceData(symbol)
-	if err != nil {
-		log.Printf("Failed to collect price data for %s: %v", symbol, err)
+	priceCount, priceErr := cs.collectPriceData(symbol)
+	if priceErr != nil {
+		log.Printf("Failed to collect price data for %s: %v", symbol, priceErr)
+	}
+
+	// Report rate limiting so the symbol is re-queued; other failures are only logged
+	for _, err := range []error{volumeErr, priceErr} {
+		if errors.Is(err, ErrUpstreamRateLimited) {
+			return volumeCount + priceCount, err
+		}
 	}
 
 	return volumeCount + priceCount, nil
 }
 
+// equitySymbols returns the stock and ETF symbols, leaving out crypto and FX pairs that have no options,
+// reference data or stocks stream
+func equitySymbols(symbols []string) []string {
+	equities := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		if models.IsEquity(symbol) {
+			equities = append(equities, symbol)
+		}
+	}
+	return equities
+}
+
+// prioritizeSymbols moves the still-watched requeued symbols to the front
+func prioritizeSymbols(symbols, requeued []string) []string {
+	if len(requeued) == 0 {
+		return symbols
+	}
+
+	watched := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		watched[symbol] = true
+	}
+
+	ordered := make([]string, 0, len(symbols))
+	seen := make(map[string]bool, len(symbols))
+	for _, symbol := range requeued {
+		if watched[symbol] && !seen[symbol] {
+			ordered = append(ordered, symbol)
+			seen[symbol] = true
+		}
+	}
+	for _, symbol := range symbols {
+		if !seen[symbol] {
+			ordered = append(ordered, symbol)
+		}
+	}
+
+	return ordered
+}
+
 // collectVolumeData collects volume data for a single symbol
 func (cs *CollectorService) collectVolumeData(symbol string) (int, error) {
 	log.Printf("Fetching latest volume aggregates for %s (last 120 minutes)", symbol)
 
 	// Get recent data (last 2 hours to ensure we don't miss anything)
-	data, err := cs.polygon.GetLatestAggregates(symbol, 120)
+	data, err := cs.provider.GetLatestAggregates(symbol, 120)
 	if err != nil {
 		log.Printf("ERROR: Failed to get latest volume aggregates for %s: %v", symbol, err)
 		return 0, fmt.Errorf("failed to get latest volume aggregates: %w", err)
 	}
 
-	log.Printf("Received %d raw volume data points from Polygon for %s", len(data), symbol)
+	data = cs.prepareVolumeBars(data)
+	log.Printf("Received %d raw volume data points from %s for %s", len(data), cs.provider.Name(), symbol)
 
 	if len(data) == 0 {
-		log.Printf("WARNING: No volume data available from Polygon API for %s", symbol)
+		log.Printf("WARNING: No volume data available from %s API for %s", cs.provider.Name(), symbol)
 		return 0, nil
 	}
 
@@ -231,6 +668,11 @@ func (cs *CollectorService) collectVolumeData(symbol string) (int, error) {
 	}
 
 	log.Printf("Successfully inserted %d volume data points for %s", len(newData), symbol)
+
+	if cs.streaming != nil {
+		cs.streaming.PublishVolumeBars(symbol, newData)
+	}
+
 	return len(newData), nil
 }
 
@@ -239,16 +681,17 @@ func (cs *CollectorService) collectPriceData(symbol string) (int, error) {
 	log.Printf("Fetching latest price aggregates for %s (last 120 minutes)", symbol)
 
 	// Get recent data (last 2 hours to ensure we don't miss anything)
-	data, err := cs.polygon.GetLatestPriceAggregates(symbol, 120)
+	data, err := cs.provider.GetLatestPriceAggregates(symbol, 120)
 	if err != nil {
 		log.Printf("ERROR: Failed to get latest price aggregates for %s: %v", symbol, err)
 		return 0, fmt.Errorf("failed to get latest price aggregates: %w", err)
 	}
 
-	log.Printf("Received %d raw price data points from Polygon for %s", len(data), symbol)
+	data = cs.prepareBars(data)
+	log.Printf("Received %d raw price data points from %s for %s", len(data), cs.provider.Name(), symbol)
 
 	if len(data) == 0 {
-		log.Printf("WARNING: No price data available from Polygon API for %s", symbol)
+		log.Printf("WARNING: No price data available from %s API for %s", cs.provider.Name(), symbol)
 		return 0, nil
 	}
 
@@ -275,82 +718,194 @@ func (cs *CollectorService) collectPriceData(symbol string) (int, error) {
 	}
 
 	log.Printf("Successfully inserted %d price data points for %s", len(newData), symbol)
+
+	if cs.prices != nil {
+		cs.prices.Append(newData)
+	}
+
+	if cs.streaming != nil {
+		cs.streaming.PublishPriceBars(symbol, newData)
+	}
+
 	return len(newData), nil
 }
 
-// filterNewVolumeData filters out volume data points that already exist in the database
-func (cs *CollectorService) filterNewVolumeData(data []*models.VolumeData) ([]*models.VolumeData, error) {
-	if len(data) == 0 {
-		return data, nil
+// prepareBars marks bars with the market session they were traded in, dropping pre- and post-market bars
+// when only regular session bars are collected
+func (cs *CollectorService) prepareBars(bars []*models.PriceData) []*models.PriceData {
+	calendar := cs.marketCalendar()
+	calendar.TagSessions(bars)
+	if !cs.cfg.Collection.RegularHoursOnly {
+		return bars
 	}
+	return calendar.RegularSessionBars(bars)
+}
 
-	symbol := data[0].Symbol
-
-	// Get the latest timestamp we have for this symbol
-	latest, err := cs.db.GetLatestVolumeData(symbol)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get latest volume data: %w", err)
+// prepareVolumeBars drops pre- and post-market volume bars when only regular session bars are collected
+func (cs *CollectorService) prepareVolumeBars(bars []*models.VolumeData) []*models.VolumeData {
+	if !cs.cfg.Collection.RegularHoursOnly {
+		return bars
 	}
+	calendar := cs.marketCalendar()
+	regular := make([]*models.VolumeData, 0, len(bars))
+	for _, bar := range bars {
+		if calendar.SymbolSession(bar.Symbol, bar.Timestamp) == models.SessionRegular {
+			regular = append(regular, bar)
+		}
+	}
+	return regular
+}
 
-	// If we have no data, return all new data
-	if latest == nil {
+// filterNewVolumeData filters out volume data points that are already stored or repeated within the batch
+func (cs *CollectorService) filterNewVolumeData(data []*models.VolumeData) ([]*models.VolumeData, error) {
+	if len(data) == 0 {
 		return data, nil
 	}
 
-	// Filter out data points that are older or equal to our latest timestamp
-	var newData []*models.VolumeData
-	for _, vd := range data {
-		if vd.Timestamp.After(latest.Timestamp) {
-			newData = append(newData, vd)
-		}
+	newData, err := filterUnstoredBars(data, func(vd *models.VolumeData) time.Time { return vd.Timestamp },
+		func(from, to time.Time) ([]time.Time, error) { return cs.db.GetVolumeTimestamps(data[0].Symbol, from, to) })
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stored volume timestamps: %w", err)
 	}
-
 	return newData, nil
 }
 
-// filterNewPriceData filters out price data points that already exist in the database
+// filterNewPriceData filters out price data points that are already stored or repeated within the batch
 func (cs *CollectorService) filterNewPriceData(data []*models.PriceData) ([]*models.PriceData, error) {
 	if len(data) == 0 {
 		return data, nil
 	}
 
-	symbol := data[0].Symbol
+	newData, err := filterUnstoredBars(data, func(pd *models.PriceData) time.Time { return pd.Timestamp },
+		func(from, to time.Time) ([]time.Time, error) { return cs.db.GetPriceTimestamps(data[0].Symbol, from, to) })
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stored price timestamps: %w", err)
+	}
+	return newData, nil
+}
+
+// filterUnstoredBars keeps the bars of one symbol whose timestamps aren't stored yet, looking up every stored
+// timestamp in the batch's range rather than only the latest, so bars backfilled into an earlier gap aren't dropped
+// and bars restated by a restart or overlapping backfill aren't counted or streamed twice
+func filterUnstoredBars[T any](data []T, timestamp func(T) time.Time, stored func(from, to time.Time) ([]time.Time, error)) ([]T, error) {
+	from, to := timestamp(data[0]), timestamp(data[0])
+	for _, bar := range data {
+		if ts := timestamp(bar); ts.Before(from) {
+			from = ts
+		} else if ts.After(to) {
+			to = ts
+		}
+	}
 
-	// Get the latest timestamp we have for this symbol
-	latest, err := cs.db.GetLatestPriceData(symbol)
+	timestamps, err := stored(from, to)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get latest price data: %w", err)
+		return nil, err
 	}
 
-	// If we have no data, return all new data
-	if latest == nil {
-		return data, nil
+	seen := make(map[int64]bool, len(timestamps)+len(data))
+	for _, ts := range timestamps {
+		seen[ts.UnixNano()] = true
 	}
 
-	// Filter out data points that are older or equal to our latest timestamp
-	var newData []*models.PriceData
-	for _, pd := range data {
-		if pd.Timestamp.After(latest.Timestamp) {
-			newData = append(newData, pd)
+	var newData []T
+	for _, bar := range data {
+		key := timestamp(bar).UnixNano()
+		if seen[key] {
+			continue
 		}
+		seen[key] = true
+		newData = append(newData, bar)
 	}
 
 	return newData, nil
 }
 
-// cleanupOldData removes old data based on retention policy
-func (cs *CollectorService) cleanupOldData() {
+// scheduledCleanup runs the daily cleanup unless it is disabled
+func (cs *CollectorService) scheduledCleanup() {
+	if cs.schedules.Skip(models.ScheduleCleanup) {
+		return
+	}
+	cs.CleanupOldData()
+}
+
+// CleanupOldData removes old data based on retention policy, compacting old bars first when enabled
+func (cs *CollectorService) CleanupOldData() {
 	log.Printf("Starting data cleanup...")
 
+	if days := cs.cfg.DataRetention.CompactAfterDays; days > 0 {
+		result, err := cs.db.CompactPriceData(time.Now().AddDate(0, 0, -days))
+		if err != nil {
+			log.Printf("Failed to compact old price data: %v", err)
+		} else {
+			log.Printf("Compacted %d bars of %d symbols before %s into %d hourly and %d daily candles",
+				result.BarsCompacted, result.Symbols, result.Cutoff.Format("2006-01-02"), result.HourlyCandles, result.DailyCandles)
+		}
+	}
+
 	rowsDeleted, err := cs.db.CleanupOldData(cs.cfg.DataRetention.Days)
 	if err != nil {
 		log.Printf("Failed to cleanup old data: %v", err)
 		return
 	}
 
+	// Cached bars may have been deleted or compacted
+	if cs.prices != nil {
+		cs.prices.InvalidateAll()
+	}
+
+	if cs.options.IsEnabled() {
+		optionsDeleted, err := cs.db.DeleteOptionsDataBefore(time.Now().AddDate(0, 0, -cs.cfg.DataRetention.Days))
+		if err != nil {
+			log.Printf("Failed to cleanup old options data: %v", err)
+		}
+		rowsDeleted += optionsDeleted
+	}
+
+	notificationsDeleted, err := cs.db.DeleteNotificationsBefore(time.Now().AddDate(0, 0, -cs.cfg.DataRetention.Days))
+	if err != nil {
+		log.Printf("Failed to cleanup old notifications: %v", err)
+	}
+	rowsDeleted += notificationsDeleted
+
+	anomaliesDeleted, err := cs.db.DeleteMarketAnomaliesBefore(time.Now().AddDate(0, 0, -cs.cfg.DataRetention.Days))
+	if err != nil {
+		log.Printf("Failed to cleanup old market anomalies: %v", err)
+	}
+	rowsDeleted += anomaliesDeleted
+
 	log.Printf("Data cleanup completed. Deleted %d old records", rowsDeleted)
 }
 
+// scheduledPurge runs the daily purge of archived records unless it is disabled
+func (cs *CollectorService) scheduledPurge() {
+	if cs.schedules.Skip(models.SchedulePurge) {
+		return
+	}
+	cs.PurgeArchived()
+}
+
+// PurgeArchived deletes the setups and patterns archived longer ago than the retention config allows
+func (cs *CollectorService) PurgeArchived() {
+	days := cs.cfg.DataRetention.PurgeArchivedAfterDays
+	if days <= 0 {
+		return
+	}
+	cutoff := time.Now().AddDate(0, 0, -days)
+
+	setupsPurged, err := cs.db.PurgeArchivedSetups(cutoff)
+	if err != nil {
+		log.Printf("Failed to purge archived setups: %v", err)
+	}
+
+	patternsPurged, err := cs.db.PurgeArchivedPatterns(cutoff)
+	if err != nil {
+		log.Printf("Failed to purge archived patterns: %v", err)
+	}
+
+	log.Printf("Purge completed. Deleted %d setups and %d patterns archived before %s",
+		setupsPurged, patternsPurged, cutoff.Format("2006-01-02"))
+}
+
 // intervalToCron converts a time.Duration to a cron expression
 func (cs *CollectorService) intervalToCron(interval time.Duration) (string, error) {
 	switch {
@@ -392,13 +947,43 @@ func (cs *CollectorService) GetStats() *CollectionStats {
 
 	// Create a copy to avoid race conditions
 	statsCopy := *cs.stats
+	statsCopy.RequeuedSymbols = append([]string(nil), cs.requeued...)
+	statsCopy.Paused = !cs.schedules.Enabled(models.ScheduleCollection)
+	if cs.calendar != nil {
+		statsCopy.MarketSession = cs.calendar.Session(clockNow())
+	}
+	if cs.realtime != nil {
+		statsCopy.Realtime = cs.realtime.Status()
+	}
+	if limited, ok := cs.provider.(RateLimitedProvider); ok {
+		statsCopy.RateLimit = limited.RateLimitStats()
+	}
+	statsCopy.Inserts = cs.db.InsertStats()
 	return &statsCopy
 }
 
+// providerPaced reports whether the provider needs no delay between symbols, either because it
+// paces its own requests or because it reads stored data in replay mode
+func (cs *CollectorService) providerPaced() bool {
+	if _, rateLimited := cs.provider.(RateLimitedProvider); rateLimited {
+		return true
+	}
+	_, replay := cs.provider.(*ReplayProvider)
+	return replay
+}
+
+// CollectNow runs a collection cycle synchronously, used by replay mode to step through stored data
+func (cs *CollectorService) CollectNow() {
+	cs.collectData(nil)
+}
+
 // ForceCollection triggers an immediate data collection
 func (cs *CollectorService) ForceCollection() error {
+	if !cs.schedules.Enabled(models.ScheduleCollection) {
+		return fmt.Errorf("%w: collection is paused", ErrUnavailable)
+	}
 	log.Printf("Forcing immediate data collection...")
-	go cs.collectData()
+	cs.goCollect()
 	return nil
 }
 
@@ -406,10 +991,10 @@ func (cs *CollectorService) ForceCollection() error {
 func (cs *CollectorService) CollectHistoricalData(days int) error {
 	log.Printf("Starting historical data collection for %d days", days)
 
-	// Get watched symbols from database
-	symbols, err := cs.db.GetWatchedSymbols()
+	// Get watched symbols from database, with the benchmarks
+	symbols, err := cs.CollectionSymbols()
 	if err != nil {
-		return fmt.Errorf("failed to get watched symbols: %w", err)
+		return err
 	}
 
 	if len(symbols) == 0 {
@@ -417,41 +1002,100 @@ func (cs *CollectorService) CollectHistoricalData(days int) error {
 		return nil
 	}
 
+	paced := cs.providerPaced()
 	for _, symbol := range symbols {
-		log.Printf("Collecting historical data for %s", symbol)
+		if _, err := cs.CollectHistoricalSymbol(symbol, days); err != nil {
+			log.Printf("Historical data collection failed for %s: %v", symbol, err)
+			if errors.Is(err, ErrBudgetExhausted) {
+				break
+			}
+		}
 
+		// Providers without their own rate limiter get a delay between symbols
+		if !paced {
+			time.Sleep(1 * time.Second)
+		}
+	}
+
+	log.Printf("Historical data collection completed")
+	return nil
+}
+
+// BackfillTask returns a job task backfilling the last N days of each symbol's history
+func (cs *CollectorService) BackfillTask(days int) JobTask {
+	return func(ctx context.Context, symbol string) (interface{}, error) {
+		stored, err := cs.CollectHistoricalSymbol(symbol, days)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]int{"points_stored": stored}, nil
+	}
+}
+
+// CollectHistoricalSymbol backfills the gaps in the last N days of a symbol's volume and price history and returns
+// the number of points stored; days that are already fully collected are not fetched again
+func (cs *CollectorService) CollectHistoricalSymbol(symbol string, days int) (int, error) {
+	coverage, err := cs.ScanCoverage(symbol, days)
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan data coverage: %w", err)
+	}
+	if len(coverage.Gaps) == 0 {
+		log.Printf("No gaps in the last %d days of historical data for %s", days, symbol)
+		return 0, nil
+	}
+
+	log.Printf("Collecting historical data for %s: %d gaps, %d missing minutes", symbol, len(coverage.Gaps), coverage.MissingMinutes)
+
+	stored := 0
+	var failures []string
+
+	for _, gap := range gapFetchRanges(cs.marketCalendar(), coverage.Gaps) {
 		// Collect volume data
-		volumeData, err := cs.polygon.GetHistoricalData(symbol, days)
+		volumeData, err := cs.provider.GetAggregates(symbol, gap.From, gap.To)
+		volumeData = cs.prepareVolumeBars(volumeData)
+		if errors.Is(err, ErrBudgetExhausted) {
+			return stored, fmt.Errorf("failed to collect historical data: %w", err)
+		}
 		if err != nil {
-			log.Printf("Failed to get historical volume data for %s: %v", symbol, err)
+			failures = append(failures, fmt.Sprintf("volume: %v", err))
 		} else if len(volumeData) > 0 {
-			err = cs.db.InsertVolumeDataBatch(volumeData)
-			if err != nil {
-				log.Printf("Failed to insert historical volume data for %s: %v", symbol, err)
+			if err := cs.db.InsertVolumeDataBatch(volumeData); err != nil {
+				failures = append(failures, fmt.Sprintf("volume insert: %v", err))
 			} else {
+				stored += len(volumeData)
 				log.Printf("Inserted %d historical volume data points for %s", len(volumeData), symbol)
 			}
 		}
 
 		// Collect price data
-		priceData, err := cs.polygon.GetHistoricalPriceData(symbol, days)
+		priceData, err := cs.provider.GetPriceAggregates(symbol, gap.From, gap.To)
+		priceData = cs.prepareBars(priceData)
+		if errors.Is(err, ErrBudgetExhausted) {
+			return stored, fmt.Errorf("failed to collect historical data: %w", err)
+		}
 		if err != nil {
-			log.Printf("Failed to get historical price data for %s: %v", symbol, err)
+			failures = append(failures, fmt.Sprintf("price: %v", err))
 		} else if len(priceData) > 0 {
-			err = cs.db.InsertPriceDataBatch(priceData)
-			if err != nil {
-				log.Printf("Failed to insert historical price data for %s: %v", symbol, err)
+			if err := cs.db.InsertPriceDataBatch(priceData); err != nil {
+				failures = append(failures, fmt.Sprintf("price insert: %v", err))
 			} else {
+				stored += len(priceData)
 				log.Printf("Inserted %d historical price data points for %s", len(priceData), symbol)
+				if cs.prices != nil {
+					cs.prices.Invalidate(symbol)
+				}
 			}
 		}
+	}
 
-		// Delay between symbols to respect rate limits
-		time.Sleep(1 * time.Second)
+	if len(failures) > 0 {
+		log.Printf("Historical data collection for %s had errors: %s", symbol, strings.Join(failures, "; "))
+		if stored == 0 {
+			return 0, fmt.Errorf("failed to collect historical data: %s", strings.Join(failures, "; "))
+		}
 	}
 
-	log.Printf("Historical data collection completed")
-	return nil
+	return stored, nil
 }
 
 // CollectSymbolDataNow immediately collects data for a specific symbol
@@ -466,7 +1110,8 @@ func (cs *CollectorService) CollectSymbolDataNow(symbol string) error {
 	log.Printf("Collecting historical data for new symbol %s (7 days)", symbol)
 
 	// Collect volume data
-	historicalVolumeData, err := cs.polygon.GetHistoricalData(symbol, 7)
+	historicalVolumeData, err := cs.provider.GetHistoricalData(symbol, 7)
+	historicalVolumeData = cs.prepareVolumeBars(historicalVolumeData)
 	if err != nil {
 		log.Printf("Failed to get historical volume data for %s: %v", symbol, err)
 	} else if len(historicalVolumeData) > 0 {
@@ -479,7 +1124,8 @@ func (cs *CollectorService) CollectSymbolDataNow(symbol string) error {
 	}
 
 	// Collect price data
-	historicalPriceData, err := cs.polygon.GetHistoricalPriceData(symbol, 7)
+	historicalPriceData, err := cs.provider.GetHistoricalPriceData(symbol, 7)
+	historicalPriceData = cs.prepareBars(historicalPriceData)
 	if err != nil {
 		log.Printf("Failed to get historical price data for %s: %v", symbol, err)
 	} else if len(historicalPriceData) > 0 {
@@ -495,7 +1141,8 @@ func (cs *CollectorService) CollectSymbolDataNow(symbol string) error {
 	log.Printf("Collecting recent data for new symbol %s (24 hours)", symbol)
 
 	// Collect recent volume data
-	recentVolumeData, err := cs.polygon.GetLatestAggregates(symbol, 1440) // 1440 minutes = 24 hours
+	recentVolumeData, err := cs.provider.GetLatestAggregates(symbol, 1440) // 1440 minutes = 24 hours
+	recentVolumeData = cs.prepareVolumeBars(recentVolumeData)
 	if err != nil {
 		log.Printf("Failed to get recent volume data for %s: %v", symbol, err)
 	} else if len(recentVolumeData) > 0 {
@@ -514,7 +1161,8 @@ func (cs *CollectorService) CollectSymbolDataNow(symbol string) error {
 	}
 
 	// Collect recent price data
-	recentPriceData, err := cs.polygon.GetLatestPriceAggregates(symbol, 1440) // 1440 minutes = 24 hours
+	recentPriceData, err := cs.provider.GetLatestPriceAggregates(symbol, 1440) // 1440 minutes = 24 hours
+	recentPriceData = cs.prepareBars(recentPriceData)
 	if err != nil {
 		log.Printf("Failed to get recent price data for %s: %v", symbol, err)
 	} else if len(recentPriceData) > 0 {
@@ -532,6 +1180,11 @@ func (cs *CollectorService) CollectSymbolDataNow(symbol string) error {
 		}
 	}
 
+	// The backfilled bars land before any cached ones
+	if cs.prices != nil {
+		cs.prices.Invalidate(symbol)
+	}
+
 	// Check final status
 	finalVolumeData, err := cs.db.GetLatestVolumeData(symbol)
 	if err != nil {
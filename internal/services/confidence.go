@@ -0,0 +1,114 @@
+package services
+
+import (
+	"fmt"
+	"math"
+
+	"market-watch-go/internal/models"
+)
+
+// checklistCategoryPoints is the most points a checklist category such as volume scores
+const checklistCategoryPoints = 25.0
+
+// applyConfidence derives a setup's confidence score and level from its quality, indicator agreement, volume
+// confirmation and S/R strength with the configured confidence model
+func (sds *SetupDetectionService) applyConfidence(setup *models.TradingSetup, indicators *models.TechnicalIndicators) {
+	setup.ConfidenceFactors = confidenceFactors(setup, indicators)
+	setup.ConfidenceScore, setup.Confidence = sds.config.Confidence.Evaluate(setup.ConfidenceFactors)
+}
+
+// refreshConfidence re-evaluates a setup's confidence after its quality score changed
+func (sds *SetupDetectionService) refreshConfidence(setup *models.TradingSetup) {
+	found := false
+	for _, factor := range setup.ConfidenceFactors {
+		if factor.Name == models.ConfidenceFactorQuality {
+			factor.Score = setup.QualityScore
+			found = true
+		}
+	}
+	if !found {
+		setup.ConfidenceFactors = append(setup.ConfidenceFactors, &models.ConfidenceFactor{
+			Name:  models.ConfidenceFactorQuality,
+			Score: setup.QualityScore,
+		})
+	}
+	setup.ConfidenceScore, setup.Confidence = sds.config.Confidence.Evaluate(setup.ConfidenceFactors)
+}
+
+// confidenceFactors scores the factors a setup has, each 0-100. Indicator agreement needs indicators and S/R
+// strength a key zone or level; factors a setup lacks are left out rather than scored zero.
+func confidenceFactors(setup *models.TradingSetup, indicators *models.TechnicalIndicators) []*models.ConfidenceFactor {
+	factors := []*models.ConfidenceFactor{{
+		Name:  models.ConfidenceFactorQuality,
+		Score: setup.QualityScore,
+	}}
+
+	if agreement := indicatorAgreement(setup, indicators); agreement != nil {
+		factors = append(factors, agreement)
+	}
+
+	volume := &models.ConfidenceFactor{
+		Name:   models.ConfidenceFactorVolume,
+		Score:  math.Min(100, setup.VolumeScore/checklistCategoryPoints*100),
+		Detail: fmt.Sprintf("volume checklist %.0f of %.0f points", setup.VolumeScore, checklistCategoryPoints),
+	}
+	if setup.KeyLevel != nil && setup.KeyLevel.VolumeConfirmed {
+		volume.Score = 0.7*volume.Score + 30
+		volume.Detail += ", key level volume confirmed"
+	}
+	volume.Score = math.Round(volume.Score*100) / 100
+	factors = append(factors, volume)
+
+	switch {
+	case setup.KeyZone != nil:
+		factors = append(factors, &models.ConfidenceFactor{
+			Name:   models.ConfidenceFactorLevel,
+			Score:  setup.KeyZone.ZoneStrength,
+			Detail: fmt.Sprintf("zone strength %.0f", setup.KeyZone.ZoneStrength),
+		})
+	case setup.KeyLevel != nil:
+		factors = append(factors, &models.ConfidenceFactor{
+			Name:   models.ConfidenceFactorLevel,
+			Score:  setup.KeyLevel.Strength,
+			Detail: fmt.Sprintf("level strength %.0f, %d touches", setup.KeyLevel.Strength, setup.KeyLevel.Touches),
+		})
+	}
+
+	return factors
+}
+
+// indicatorAgreement scores the share of available trend and momentum indicators pointing in the setup's
+// direction: MACD histogram, EMA20 over EMA50, price over VWAP, +DI over -DI and %K over %D. It returns nil
+// when no indicator is available.
+func indicatorAgreement(setup *models.TradingSetup, indicators *models.TechnicalIndicators) *models.ConfidenceFactor {
+	if indicators == nil {
+		return nil
+	}
+
+	bullish := setup.Direction != "bearish"
+	available, agreeing := 0, 0
+	check := func(ok, up bool) {
+		if !ok {
+			return
+		}
+		available++
+		if up == bullish {
+			agreeing++
+		}
+	}
+
+	check(indicators.MACDHistogram != 0, indicators.MACDHistogram > 0)
+	check(indicators.EMA20 > 0 && indicators.EMA50 > 0, indicators.EMA20 > indicators.EMA50)
+	check(indicators.VWAP > 0 && setup.CurrentPrice > 0, setup.CurrentPrice > indicators.VWAP)
+	check(indicators.PlusDI > 0 || indicators.MinusDI > 0, indicators.PlusDI > indicators.MinusDI)
+	check(indicators.StochK > 0 || indicators.StochD > 0, indicators.StochK > indicators.StochD)
+
+	if available == 0 {
+		return nil
+	}
+	return &models.ConfidenceFactor{
+		Name:   models.ConfidenceFactorIndicators,
+		Score:  math.Round(float64(agreeing)/float64(available)*10000) / 100,
+		Detail: fmt.Sprintf("%d of %d indicators agree", agreeing, available),
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"testing"
+
+	"market-watch-go/internal/models"
+)
+
+// TestConfidenceModel tests that factors are weighed into the confidence score with the weights of missing
+// factors spread over the others
+func TestConfidenceModel(t *testing.T) {
+	model := models.DefaultConfidenceModel()
+	if err := model.Validate(); err != nil {
+		t.Fatalf("expected the default model to be valid, got %v", err)
+	}
+
+	// Quality alone carries the whole weight, so the level follows the quality buckets
+	score, level := model.Evaluate([]*models.ConfidenceFactor{{Name: models.ConfidenceFactorQuality, Score: 82}})
+	if score != 82 || level != "high" {
+		t.Errorf("expected 82 high, got %.2f %s", score, level)
+	}
+
+	factors := []*models.ConfidenceFactor{
+		{Name: models.ConfidenceFactorQuality, Score: 80},
+		{Name: models.ConfidenceFactorIndicators, Score: 40},
+		{Name: models.ConfidenceFactorVolume, Score: 60},
+		{Name: models.ConfidenceFactorLevel, Score: 100},
+	}
+	score, level = model.Evaluate(factors)
+	if score != 74 || level != "medium" {
+		t.Errorf("expected 74 medium, got %.2f %s", score, level)
+	}
+	if factors[0].Weight != 55 || factors[0].Contribution != 44 || factors[3].Contribution != 15 {
+		t.Errorf("unexpected contributions: %+v, %+v", factors[0], factors[3])
+	}
+
+	model.LevelWeight = 20
+	if err := model.Validate(); err == nil {
+		t.Error("expected weights over 100 to be rejected")
+	}
+	model = models.DefaultConfidenceModel()
+	model.MediumThreshold = 90
+	if err := model.Validate(); err == nil {
+		t.Error("expected a medium threshold above the high one to be rejected")
+	}
+}
+
+// TestConfidenceFactors tests the indicator agreement, volume confirmation and S/R strength factors of a setup
+func TestConfidenceFactors(t *testing.T) {
+	sds := NewSetupDetectionService(nil, nil, nil)
+	setup := &models.TradingSetup{
+		Direction: "bullish", QualityScore: 70, VolumeScore: 15, CurrentPrice: 101,
+		KeyLevel: &models.SupportResistanceLevel{Strength: 80, Touches: 4, VolumeConfirmed: true},
+	}
+	indicators := &models.TechnicalIndicators{MACDHistogram: 0.2, EMA20: 100, EMA50: 98, VWAP: 102, PlusDI: 25, MinusDI: 20}
+
+	sds.applyConfidence(setup, indicators)
+	byName := make(map[string]*models.ConfidenceFactor)
+	for _, factor := range setup.ConfidenceFactors {
+		byName[factor.Name] = factor
+	}
+	if factor := byName[models.ConfidenceFactorIndicators]; factor == nil || factor.Score != 75 || factor.Detail != "3 of 4 indicators agree" {
+		t.Errorf("unexpected indicator agreement: %+v", factor)
+	}
+	if factor := byName[models.ConfidenceFactorVolume]; factor == nil || factor.Score != 72 {
+		t.Errorf("expected 60%% of the volume points lifted by the confirmed level, got %+v", factor)
+	}
+	if factor := byName[models.ConfidenceFactorLevel]; factor == nil || factor.Score != 80 {
+		t.Errorf("unexpected S/R strength: %+v", factor)
+	}
+	// 70*0.55 + 75*0.15 + 72*0.15 + 80*0.15
+	if setup.ConfidenceScore != 72.55 || setup.Confidence != "medium" {
+		t.Errorf("expected 72.55 medium, got %.2f %s", setup.ConfidenceScore, setup.Confidence)
+	}
+
+	// A bearish setup disagrees with the same indicators, and one without indicators or a level has neither factor
+	bearish := &models.TradingSetup{Direction: "bearish", QualityScore: 70, CurrentPrice: 101}
+	if factor := indicatorAgreement(bearish, indicators); factor == nil || factor.Score != 25 {
+		t.Errorf("expected 1 of 4 indicators to agree with a bearish setup, got %+v", factor)
+	}
+	if factors := confidenceFactors(bearish, nil); len(factors) != 2 {
+		t.Errorf("expected only the quality and volume factors, got %d", len(factors))
+	}
+
+	// A lower quality score re-evaluates the confidence with the other factors kept
+	setup.QualityScore = 40
+	sds.refreshConfidence(setup)
+	if len(setup.ConfidenceFactors) != 4 || setup.ConfidenceScore != 56.05 || setup.Confidence != "low" {
+		t.Errorf("expected 56.05 low, got %.2f %s", setup.ConfidenceScore, setup.Confidence)
+	}
+}
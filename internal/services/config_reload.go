@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// How often the config file is checked for changes
+const configWatchInterval = 5 * time.Second
+
+// ConfigReloader re-reads the config file and applies the settings that can change safely while running:
+// the collection interval, default watched symbols, email and Telegram notifications and the logging level.
+// Other changed sections are reported as needing a restart.
+type ConfigReloader struct {
+	path          string
+	replay        bool
+	db            *database.Database
+	collector     *CollectorService
+	email         *EmailService
+	telegram      *TelegramService
+	logLevel      func(level string)
+	notifications *NotificationService
+
+	current  *config.Config // settings in effect
+	mutex    sync.Mutex     // serializes reloads
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup // tracks the watch loop
+}
+
+// NewConfigReloader creates a config reloader for the file cfg was loaded from
+func NewConfigReloader(path string, cfg *config.Config, db *database.Database, collector *CollectorService, email *EmailService, telegram *TelegramService) *ConfigReloader {
+	current := *cfg
+	return &ConfigReloader{
+		path:      path,
+		replay:    cfg.Replay.Enabled,
+		db:        db,
+		collector: collector,
+		email:     email,
+		telegram:  telegram,
+		current:   &current,
+		stop:      make(chan struct{}),
+	}
+}
+
+// SetLogLevelFunc sets the function applying a changed logging level
+func (cr *ConfigReloader) SetLogLevelFunc(logLevel func(level string)) {
+	cr.logLevel = logLevel
+}
+
+// SetNotificationService records rejected file changes and changes needing a restart in the notification center
+func (cr *ConfigReloader) SetNotificationService(notifications *NotificationService) {
+	cr.notifications = notifications
+}
+
+// Start watches the config file and reloads it whenever it changes
+func (cr *ConfigReloader) Start() {
+	if cr.replay {
+		return
+	}
+
+	info, err := os.Stat(cr.path)
+	if err != nil {
+		log.Printf("Not watching config file: %v", err)
+		return
+	}
+
+	log.Printf("Watching %s for changes", cr.path)
+
+	cr.wg.Add(1)
+	go func() {
+		defer cr.wg.Done()
+
+		ticker := time.NewTicker(configWatchInterval)
+		defer ticker.Stop()
+
+		modTime, size := info.ModTime(), info.Size()
+		for {
+			select {
+			case <-ticker.C:
+			case <-cr.stop:
+				return
+			}
+
+			info, err := os.Stat(cr.path)
+			if err != nil || (info.ModTime().Equal(modTime) && info.Size() == size) {
+				continue
+			}
+			modTime, size = info.ModTime(), info.Size()
+
+			result, err := cr.Reload()
+			if err != nil {
+				log.Printf("Config reload failed, keeping current settings: %v", err)
+				cr.notifications.Notify(&models.Notification{
+					Category: models.NotificationSystem,
+					Severity: EmailSeverityMedium,
+					Title:    "⚠️ Config change rejected",
+					Message:  fmt.Sprintf("%s was not reloaded, the current settings stay in effect: %v", cr.path, err),
+				})
+			} else if len(result.RestartRequired) > 0 {
+				cr.notifications.Notify(&models.Notification{
+					Category: models.NotificationSystem,
+					Title:    "Config change needs a restart",
+					Message:  fmt.Sprintf("Changed sections of %s take effect on the next start: %s", cr.path, strings.Join(result.RestartRequired, ", ")),
+				})
+			}
+		}
+	}()
+}
+
+// Stop stops watching the config file
+func (cr *ConfigReloader) Stop(ctx context.Context) error {
+	cr.stopOnce.Do(func() { close(cr.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		cr.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for the config watcher to stop: %w", ctx.Err())
+	}
+}
+
+// Reload reads and validates the config file and applies what changed. An invalid file changes nothing.
+func (cr *ConfigReloader) Reload() (*models.ConfigReloadResult, error) {
+	if cr.replay {
+		return nil, fmt.Errorf("config reload is disabled during replays")
+	}
+
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	next, err := config.Load(cr.path)
+	if err != nil {
+		return nil, err
+	}
+
+	prev := cr.current
+	applied := *prev
+	result := &models.ConfigReloadResult{
+		Path:            cr.path,
+		ReloadedAt:      time.Now(),
+		Applied:         []string{},
+		RestartRequired: []string{},
+	}
+
+	if next.Collection.Interval != prev.Collection.Interval {
+		if err := cr.collector.SetInterval(next.Collection.Interval); err != nil {
+			return nil, err
+		}
+		applied.Collection.Interval = next.Collection.Interval
+		result.Applied = append(result.Applied, "collection.interval")
+	}
+
+	if !reflect.DeepEqual(next.Collection.DefaultWatchedSymbols, prev.Collection.DefaultWatchedSymbols) {
+		if added := addedSymbols(prev.Collection.DefaultWatchedSymbols, next.Collection.DefaultWatchedSymbols); len(added) > 0 {
+			if err := cr.db.EnsureConfigSymbolsWatched(added); err != nil {
+				return nil, fmt.Errorf("failed to watch new default symbols: %w", err)
+			}
+		}
+		applied.Collection.DefaultWatchedSymbols = next.Collection.DefaultWatchedSymbols
+		result.Applied = append(result.Applied, "collection.default_watched_symbols")
+	}
+
+	if !reflect.DeepEqual(next.Email, prev.Email) {
+		email := next.Email
+		cr.email.SetConfig(&email)
+		applied.Email = email
+		result.Applied = append(result.Applied, "email")
+	}
+
+	telegram := prev.Telegram
+	telegram.Enabled, telegram.BotToken, telegram.ChatID = next.Telegram.Enabled, next.Telegram.BotToken, next.Telegram.ChatID
+	if telegram != prev.Telegram {
+		cr.telegram.SetConfig(&telegram)
+		applied.Telegram = telegram
+		result.Applied = append(result.Applied, "telegram")
+	}
+
+	if next.Logging.Level != prev.Logging.Level {
+		if cr.logLevel != nil {
+			cr.logLevel(next.Logging.Level)
+		}
+		applied.Logging.Level = next.Logging.Level
+		result.Applied = append(result.Applied, "logging.level")
+	}
+
+	result.RestartRequired = changedSections(&applied, next)
+	cr.current = &applied
+
+	if len(result.Applied) > 0 || len(result.RestartRequired) > 0 {
+		log.Printf("Reloaded %s: applied [%s], restart required for [%s]", cr.path,
+			strings.Join(result.Applied, ", "), strings.Join(result.RestartRequired, ", "))
+	}
+	return result, nil
+}
+
+// changedSections returns the YAML names of the top-level config sections that differ
+func changedSections(a, b *config.Config) []string {
+	changed := []string{}
+	va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+	for i := 0; i < va.NumField(); i++ {
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			name := strings.Split(va.Type().Field(i).Tag.Get("yaml"), ",")[0]
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
+
+// addedSymbols returns the symbols in next that aren't in prev
+func addedSymbols(prev, next []string) []string {
+	seen := make(map[string]bool, len(prev))
+	for _, symbol := range prev {
+		seen[strings.ToUpper(symbol)] = true
+	}
+
+	var added []string
+	for _, symbol := range next {
+		if !seen[strings.ToUpper(symbol)] {
+			added = append(added, symbol)
+		}
+	}
+	return added
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+)
+
+// TestConfigReloader tests which changed settings are applied and which are reported as needing a restart
+func TestConfigReloader(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	write := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+	}
+
+	base := `
+server:
+  port: 8080
+database:
+  path: ` + filepath.Join(dir, "reload.db") + `
+market_data:
+  provider: yahoo
+collection:
+  interval: 5m
+  default_watched_symbols: [AAPL]
+logging:
+  level: info
+`
+	write(base)
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("config.Load failed: %v", err)
+	}
+	db, err := database.New(cfg)
+	if err != nil {
+		t.Fatalf("database.New failed: %v", err)
+	}
+	defer db.Close()
+
+	collector := NewCollectorService(db, nil, cfg)
+	reloader := NewConfigReloader(path, cfg, db, collector, NewEmailService(cfg), NewTelegramService(cfg, db, nil))
+	var level string
+	reloader.SetLogLevelFunc(func(l string) { level = l })
+
+	write(`
+server:
+  port: 9090
+database:
+  path: ` + filepath.Join(dir, "reload.db") + `
+market_data:
+  provider: yahoo
+collection:
+  interval: 15m
+  default_watched_symbols: [AAPL, MSFT]
+email:
+  enabled: true
+  smtp_host: smtp.example.com
+logging:
+  level: debug
+`)
+	result, err := reloader.Reload()
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+
+	wantApplied := []string{"collection.interval", "collection.default_watched_symbols", "email", "logging.level"}
+	if !reflect.DeepEqual(result.Applied, wantApplied) {
+		t.Errorf("expected applied %v, got %v", wantApplied, result.Applied)
+	}
+	if !reflect.DeepEqual(result.RestartRequired, []string{"server"}) {
+		t.Errorf("expected only server to need a restart, got %v", result.RestartRequired)
+	}
+	if cfg.Collection.Interval != 15*time.Minute || level != "debug" {
+		t.Errorf("expected the new interval and level to be in effect, got %v and %q", cfg.Collection.Interval, level)
+	}
+	if watched, _ := db.GetWatchedSymbols(); !reflect.DeepEqual(watched, []string{"MSFT"}) {
+		t.Errorf("expected the new default symbol to be watched, got %v", watched)
+	}
+
+	// An invalid file changes nothing
+	write("server:\n  port: -1\n")
+	if _, err := reloader.Reload(); err == nil {
+		t.Error("expected an invalid config to be rejected")
+	}
+	if cfg.Collection.Interval != 15*time.Minute {
+		t.Errorf("expected the interval to be unchanged, got %v", cfg.Collection.Interval)
+	}
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// Gap detection tuning
+const (
+	minGapMinutes   = 10 // thinly traded symbols routinely go an interval without a trade
+	maxGapFetchDays = 5  // longest span fetched in one request when nearby gaps are merged
+)
+
+// marketCalendar returns the collector's calendar, or one built from the config when none is set
+func (cs *CollectorService) marketCalendar() *MarketCalendar {
+	if cs.calendar != nil {
+		return cs.calendar
+	}
+	return NewMarketCalendar(cs.cfg.MarketHours)
+}
+
+// ScanCoverage finds the regular session intervals of the last N days with no stored bar for a symbol
+func (cs *CollectorService) ScanCoverage(symbol string, days int) (*models.SymbolCoverage, error) {
+	to := clockNow()
+	from := to.AddDate(0, 0, -days)
+
+	timestamps, err := cs.db.GetPriceTimestamps(symbol, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bar timestamps: %w", err)
+	}
+
+	return scanCoverage(cs.marketCalendar(), symbol, from, to, timestamps), nil
+}
+
+// scanCoverage checks every complete interval of each trading day's regular session between from and to for a
+// bar, reporting runs of at least minGapMinutes without one as gaps. Weekends and holidays expect no bars.
+func scanCoverage(calendar *MarketCalendar, symbol string, from, to time.Time, timestamps []time.Time) *models.SymbolCoverage {
+	interval := models.CoverageIntervalMinutes * time.Minute
+	covered := make(map[int64]bool, len(timestamps))
+	for _, ts := range timestamps {
+		covered[ts.Truncate(interval).Unix()] = true
+	}
+
+	coverage := &models.SymbolCoverage{
+		Symbol: symbol,
+		From:   from,
+		To:     to,
+		Gaps:   []models.DataGap{},
+	}
+
+	var gap *models.DataGap
+	closeGap := func() {
+		if gap != nil && gap.Minutes >= minGapMinutes {
+			coverage.Gaps = append(coverage.Gaps, *gap)
+			coverage.MissingMinutes += gap.Minutes
+		}
+		gap = nil
+	}
+
+	local := from.In(calendar.Location())
+	for day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location()); !day.After(to); day = day.AddDate(0, 0, 1) {
+		open, closeAt, ok := calendar.RegularSession(day)
+		if !ok {
+			continue
+		}
+
+		counted := false
+		for start := open; !start.Add(interval).After(closeAt) && !start.Add(interval).After(to); start = start.Add(interval) {
+			if start.Before(from) {
+				continue
+			}
+			counted = true
+			coverage.ExpectedIntervals++
+
+			if covered[start.Unix()] {
+				coverage.CoveredIntervals++
+				closeGap()
+				continue
+			}
+			if gap == nil {
+				gap = &models.DataGap{From: start}
+			}
+			gap.To = start.Add(interval)
+			gap.Minutes += models.CoverageIntervalMinutes
+		}
+		closeGap()
+
+		if counted {
+			coverage.TradingDays++
+		}
+	}
+
+	if coverage.ExpectedIntervals > 0 {
+		coverage.CoveragePercent = float64(coverage.CoveredIntervals) / float64(coverage.ExpectedIntervals) * 100
+	}
+
+	return coverage
+}
+
+// gapFetchRanges widens each gap to its days' full extended sessions, so pre and post market bars are restored too,
+// and merges gaps within maxGapFetchDays of each other into one request
+func gapFetchRanges(calendar *MarketCalendar, gaps []models.DataGap) []models.DataGap {
+	var ranges []models.DataGap
+	for _, gap := range gaps {
+		preOpen, _, _, _ := calendar.sessionBounds(gap.From)
+		_, _, _, postClose := calendar.sessionBounds(gap.To.Add(-time.Minute))
+
+		if n := len(ranges); n > 0 && !postClose.After(ranges[n-1].From.AddDate(0, 0, maxGapFetchDays)) {
+			ranges[n-1].To = postClose
+			ranges[n-1].Minutes += gap.Minutes
+			continue
+		}
+		ranges = append(ranges, models.DataGap{From: preOpen, To: postClose, Minutes: gap.Minutes})
+	}
+	return ranges
+}
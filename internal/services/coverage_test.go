@@ -0,0 +1,50 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+)
+
+// TestScanCoverage tests gap detection across a weekend and holiday, and merging gaps into fetch ranges
+func TestScanCoverage(t *testing.T) {
+	mc := NewMarketCalendar(config.MarketHoursConfig{})
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, time.May, day, hour, minute, 0, 0, mc.Location())
+	}
+
+	// Friday's session is stored except 10:00-10:10 and the 11:00 interval; Memorial Day Monday is closed
+	var timestamps []time.Time
+	for ts := at(24, 9, 30); ts.Before(at(24, 16, 0)); ts = ts.Add(5 * time.Minute) {
+		if (!ts.Before(at(24, 10, 0)) && ts.Before(at(24, 10, 10))) || ts.Equal(at(24, 11, 0)) {
+			continue
+		}
+		timestamps = append(timestamps, ts)
+	}
+
+	// Tuesday has no bars by 10:02; the interval still forming isn't expected
+	coverage := scanCoverage(mc, "TEST", at(24, 9, 0), at(28, 10, 2), timestamps)
+
+	if coverage.TradingDays != 2 || coverage.ExpectedIntervals != 84 || coverage.CoveredIntervals != 75 {
+		t.Errorf("unexpected coverage counts: %+v", coverage)
+	}
+	if len(coverage.Gaps) != 2 {
+		t.Fatalf("expected 2 gaps, got %+v", coverage.Gaps)
+	}
+	if gap := coverage.Gaps[0]; !gap.From.Equal(at(24, 10, 0)) || !gap.To.Equal(at(24, 10, 10)) || gap.Minutes != 10 {
+		t.Errorf("unexpected Friday gap: %+v", gap)
+	}
+	if gap := coverage.Gaps[1]; !gap.From.Equal(at(28, 9, 30)) || !gap.To.Equal(at(28, 10, 0)) || gap.Minutes != 30 {
+		t.Errorf("unexpected Tuesday gap: %+v", gap)
+	}
+	if coverage.MissingMinutes != 40 {
+		t.Errorf("expected 40 missing minutes, got %d", coverage.MissingMinutes)
+	}
+
+	// Both gaps are fetched in one request spanning their extended sessions
+	ranges := gapFetchRanges(mc, coverage.Gaps)
+	if len(ranges) != 1 || !ranges[0].From.Equal(at(24, 4, 0)) || !ranges[0].To.Equal(at(28, 20, 0)) {
+		t.Errorf("unexpected fetch ranges: %+v", ranges)
+	}
+}
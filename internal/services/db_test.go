@@ -0,0 +1,28 @@
+package services
+
+import (
+	"path/filepath"
+	"testing"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+)
+
+// newTestDB opens a fresh database in the test's temporary directory, closed when the test ends
+func newTestDB(t *testing.T) *database.Database {
+	t.Helper()
+	return openTestDB(t, filepath.Join(t.TempDir(), "test.db"))
+}
+
+// openTestDB opens the database at path, closed when the test ends, e.g. to reopen one after a restart
+func openTestDB(t *testing.T, path string) *database.Database {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Database.Path = path
+	db, err := database.New(cfg)
+	if err != nil {
+		t.Fatalf("database.New failed: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
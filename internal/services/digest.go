@@ -0,0 +1,313 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Digest defaults
+const (
+	defaultDigestTime    = "08:00"
+	defaultDigestWeekday = "monday"
+	maxDigestItems       = 500
+)
+
+// DigestService compiles daily and weekly summaries of setups, pattern activity, alerts and collection
+// health, and emails them on each configured schedule
+type DigestService struct {
+	db              *database.Database
+	collector       *CollectorService
+	email           *EmailService
+	calendar        *MarketCalendar
+	enabled         bool
+	minQualityScore float64
+	control         *ScheduleControl
+	schedules       []config.DigestSchedule
+	cron            *cron.Cron
+}
+
+// NewDigestService creates a new digest service
+func NewDigestService(cfg *config.Config, db *database.Database, collector *CollectorService, email *EmailService, calendar *MarketCalendar) *DigestService {
+	ds := &DigestService{
+		db:              db,
+		collector:       collector,
+		email:           email,
+		calendar:        calendar,
+		enabled:         cfg.Digest.Enabled,
+		minQualityScore: cfg.Digest.MinQualityScore,
+		schedules:       cfg.Digest.Schedules,
+		cron:            cron.New(cron.WithLocation(calendar.Location())),
+	}
+	if ds.minQualityScore <= 0 {
+		ds.minQualityScore = models.DefaultSetupScoringConfig().HighQualityThreshold
+	}
+
+	return ds
+}
+
+// SetScheduleControl sets the control that can disable the scheduled digests
+func (ds *DigestService) SetScheduleControl(control *ScheduleControl) {
+	ds.control = control
+}
+
+// Start schedules the configured digests
+func (ds *DigestService) Start() error {
+	if !ds.enabled || len(ds.schedules) == 0 {
+		log.Printf("Digest emails disabled")
+		return nil
+	}
+
+	for _, schedule := range ds.schedules {
+		spec, err := digestCronSpec(schedule)
+		if err != nil {
+			return err
+		}
+
+		schedule := schedule
+		if _, err := ds.cron.AddFunc(spec, func() { ds.scheduledSend(schedule) }); err != nil {
+			return fmt.Errorf("failed to schedule digest %s: %w", schedule.Name, err)
+		}
+		log.Printf("Scheduled %s digest %q (%s)", schedule.Frequency, schedule.Name, spec)
+	}
+
+	ds.cron.Start()
+	return nil
+}
+
+// Stop stops the schedule and waits for a digest being sent to finish
+func (ds *DigestService) Stop(ctx context.Context) error {
+	select {
+	case <-ds.cron.Stop().Done():
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for digest to finish: %w", ctx.Err())
+	}
+}
+
+// scheduledSend sends a scheduled digest; daily digests skip exchange holidays
+func (ds *DigestService) scheduledSend(schedule config.DigestSchedule) {
+	if schedule.Frequency == models.DigestDaily && !ds.calendar.IsTradingDay(clockNow()) {
+		return
+	}
+	if ds.control.Skip(models.ScheduleDigest) {
+		return
+	}
+
+	if err := ds.Send(schedule); err != nil {
+		log.Printf("Failed to send %s digest %q: %v", schedule.Frequency, schedule.Name, err)
+	}
+}
+
+// Send compiles the schedule's digest and emails it to its recipients
+func (ds *DigestService) Send(schedule config.DigestSchedule) error {
+	report, err := ds.Build(schedule.Frequency)
+	if err != nil {
+		return err
+	}
+
+	return ds.email.SendAlert(&EmailAlert{
+		Type:     EmailAlertDigest,
+		Severity: EmailSeverityLow,
+		Subject:  digestSubject(report),
+		Data:     report,
+		To:       schedule.Recipients,
+	})
+}
+
+// Build compiles the digest of a period ending now
+func (ds *DigestService) Build(period string) (*models.DigestReport, error) {
+	if !models.IsDigestPeriod(period) {
+		return nil, fmt.Errorf("invalid period %q: must be %s or %s", period, models.DigestDaily, models.DigestWeekly)
+	}
+
+	now := clockNow()
+	from := ds.periodStart(period, now)
+	report := &models.DigestReport{
+		Period:          period,
+		From:            from,
+		To:              now,
+		MinQualityScore: ds.minQualityScore,
+		GeneratedAt:     now,
+	}
+
+	setups, err := ds.db.GetTradingSetups(&models.SetupFilter{
+		MinQualityScore: ds.minQualityScore,
+		TimeRange:       models.SRTimeRange{From: from, To: now},
+		Sort:            "quality_score",
+		Order:           models.SortDesc,
+		Limit:           maxDigestItems,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get setups: %w", err)
+	}
+	report.Setups = setups
+	if report.Setups == nil {
+		report.Setups = []*models.TradingSetup{}
+	}
+
+	if report.Patterns, err = ds.patternActivity(from); err != nil {
+		return nil, err
+	}
+
+	if report.Alerts, err = ds.db.GetAlertTriggers(&models.AlertTriggerFilter{Since: from, Limit: maxDigestItems}); err != nil {
+		return nil, err
+	}
+
+	if report.Collection, err = ds.collectionHealth(from); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// periodStart returns the start of a digest period: a week back, or for daily digests the same time on
+// the previous trading day, so Monday's digest covers the weekend
+func (ds *DigestService) periodStart(period string, now time.Time) time.Time {
+	if period == models.DigestWeekly {
+		return now.AddDate(0, 0, -7)
+	}
+
+	from := now.AddDate(0, 0, -1)
+	for i := 0; i < 10 && !ds.calendar.IsTradingDay(from); i++ {
+		from = from.AddDate(0, 0, -1)
+	}
+	return from
+}
+
+// patternActivity returns the patterns of every family detected, or updated past formation, since from
+func (ds *DigestService) patternActivity(from time.Time) ([]*models.DigestPattern, error) {
+	var patterns []*models.DigestPattern
+	add := func(family string, id int64, symbol, patternType, phase string, completion float64, detectedAt, lastUpdated time.Time) {
+		isNew := !detectedAt.Before(from)
+		if !isNew && (lastUpdated.Before(from) || phase == models.PhaseFormation) {
+			return
+		}
+		patterns = append(patterns, &models.DigestPattern{
+			Family:            family,
+			ID:                id,
+			Symbol:            symbol,
+			PatternType:       patternType,
+			Phase:             phase,
+			New:               isNew,
+			CompletionPercent: completion,
+			DetectedAt:        detectedAt,
+			LastUpdated:       lastUpdated,
+		})
+	}
+
+	hs, err := ds.db.GetHeadShouldersPatterns(&models.PatternFilter{Sort: "last_updated", Order: models.SortDesc, Limit: maxDigestItems})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get head and shoulders patterns: %w", err)
+	}
+	for _, p := range hs {
+		add(ChartFamilyHeadShoulders, p.ID, p.Symbol, p.PatternType, p.CurrentPhase, p.ThesisComponents.CompletionPercent, p.DetectedAt, p.LastUpdated)
+	}
+
+	wedges, err := ds.db.GetFallingWedgePatterns(&models.FallingWedgeFilter{Sort: "last_updated", Order: models.SortDesc, Limit: maxDigestItems})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wedge patterns: %w", err)
+	}
+	for _, p := range wedges {
+		add(ChartFamilyFallingWedge, p.ID, p.Symbol, p.PatternType, p.CurrentPhase, p.ThesisComponents.CompletionPercent, p.DetectedAt, p.LastUpdated)
+	}
+
+	triangles, err := ds.db.GetTrianglePatterns(&models.TriangleFilter{Sort: "last_updated", Order: models.SortDesc, Limit: maxDigestItems})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get triangle patterns: %w", err)
+	}
+	for _, p := range triangles {
+		add(ChartFamilyTriangle, p.ID, p.Symbol, p.PatternType, p.CurrentPhase, p.ThesisComponents.CompletionPercent, p.DetectedAt, p.LastUpdated)
+	}
+
+	flags, err := ds.db.GetFlagPatterns(&models.FlagFilter{Sort: "last_updated", Order: models.SortDesc, Limit: maxDigestItems})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flag patterns: %w", err)
+	}
+	for _, p := range flags {
+		add(ChartFamilyFlag, p.ID, p.Symbol, p.PatternType, p.CurrentPhase, p.ThesisComponents.CompletionPercent, p.DetectedAt, p.LastUpdated)
+	}
+
+	sort.SliceStable(patterns, func(i, j int) bool { return patterns[i].LastUpdated.After(patterns[j].LastUpdated) })
+	if patterns == nil {
+		patterns = []*models.DigestPattern{}
+	}
+	return patterns, nil
+}
+
+// collectionHealth reports the collector's run counts and the watched symbols without a bar since from
+func (ds *DigestService) collectionHealth(from time.Time) (*models.DigestCollection, error) {
+	health := &models.DigestCollection{StaleSymbols: []string{}}
+	if ds.collector != nil {
+		stats := ds.collector.GetStats()
+		health.LastRun = stats.LastRun
+		health.SuccessfulRuns = stats.SuccessfulRuns
+		health.FailedRuns = stats.FailedRuns
+		health.LastError = stats.LastError
+	}
+
+	symbols, err := ds.db.GetWatchedSymbols()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watched symbols: %w", err)
+	}
+	health.WatchedSymbols = len(symbols)
+
+	for _, symbol := range symbols {
+		latest, err := ds.db.GetLatestPriceData(symbol)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get latest %s bar: %w", symbol, err)
+		}
+		if latest == nil || latest.Timestamp.Before(from) {
+			health.StaleSymbols = append(health.StaleSymbols, symbol)
+		}
+	}
+
+	return health, nil
+}
+
+// digestCronSpec returns the cron schedule of a digest: trading day mornings for daily digests
+// (holidays are skipped when the job runs) and one day a week for weekly ones
+func digestCronSpec(schedule config.DigestSchedule) (string, error) {
+	at := schedule.Time
+	if at == "" {
+		at = defaultDigestTime
+	}
+	t, err := time.Parse("15:04", at)
+	if err != nil {
+		return "", fmt.Errorf("invalid digest time %q: must be HH:MM", at)
+	}
+
+	switch schedule.Frequency {
+	case models.DigestDaily:
+		return fmt.Sprintf("%d %d * * 1-5", t.Minute(), t.Hour()), nil
+	case models.DigestWeekly:
+		name := schedule.Weekday
+		if name == "" {
+			name = defaultDigestWeekday
+		}
+		weekday, err := models.ParseWeekday(name)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d %d * * %d", t.Minute(), t.Hour(), weekday), nil
+	}
+
+	return "", fmt.Errorf("invalid digest frequency %q: must be %s or %s", schedule.Frequency, models.DigestDaily, models.DigestWeekly)
+}
+
+// digestSubject summarizes a digest in its email subject
+func digestSubject(report *models.DigestReport) string {
+	return fmt.Sprintf("📰 Market Watch %s digest: %d setups, %d patterns, %d alerts",
+		strings.ToUpper(report.Period[:1])+report.Period[1:], len(report.Setups), len(report.Patterns), len(report.Alerts))
+}
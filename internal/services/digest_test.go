@@ -0,0 +1,82 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// TestDigestCronSpec tests daily digests on weekday mornings and weekly digests on their weekday
+func TestDigestCronSpec(t *testing.T) {
+	for _, tc := range []struct {
+		schedule config.DigestSchedule
+		expected string
+	}{
+		{config.DigestSchedule{Frequency: models.DigestDaily}, "0 8 * * 1-5"},
+		{config.DigestSchedule{Frequency: models.DigestDaily, Time: "07:30"}, "30 7 * * 1-5"},
+		{config.DigestSchedule{Frequency: models.DigestWeekly}, "0 8 * * 1"},
+		{config.DigestSchedule{Frequency: models.DigestWeekly, Time: "18:15", Weekday: "Sat"}, "15 18 * * 6"},
+	} {
+		spec, err := digestCronSpec(tc.schedule)
+		if err != nil || spec != tc.expected {
+			t.Errorf("digestCronSpec(%+v) = %q, %v; expected %q", tc.schedule, spec, err, tc.expected)
+		}
+	}
+
+	for _, schedule := range []config.DigestSchedule{
+		{Frequency: "monthly"},
+		{Frequency: models.DigestDaily, Time: "8am"},
+		{Frequency: models.DigestWeekly, Weekday: "someday"},
+	} {
+		if _, err := digestCronSpec(schedule); err == nil {
+			t.Errorf("expected an error for %+v", schedule)
+		}
+	}
+}
+
+// TestBuildDigest tests the period of a digest, its alerts and the stale symbols of its collection health
+func TestBuildDigest(t *testing.T) {
+	cfg := &config.Config{}
+	db := newTestDB(t)
+
+	ds := NewDigestService(cfg, db, nil, nil, NewMarketCalendar(cfg.MarketHours))
+
+	// Monday's daily digest covers the weekend back to Friday morning
+	monday := time.Date(2025, time.June, 9, 8, 0, 0, 0, ds.calendar.Location())
+	if from := ds.periodStart(models.DigestDaily, monday); !from.Equal(monday.AddDate(0, 0, -3)) {
+		t.Errorf("daily digest on Monday starts %v, expected the previous Friday", from)
+	}
+	if from := ds.periodStart(models.DigestWeekly, monday); !from.Equal(monday.AddDate(0, 0, -7)) {
+		t.Errorf("weekly digest starts %v, expected a week back", from)
+	}
+
+	now := time.Now()
+	if err := db.EnsureConfigSymbolsWatched([]string{"TEST", "OLD"}); err != nil {
+		t.Fatalf("EnsureConfigSymbolsWatched failed: %v", err)
+	}
+	if err := db.InsertPriceData(&models.PriceData{Symbol: "TEST", Timestamp: now.Add(-time.Hour), Open: 1, High: 1, Low: 1, Close: 1}); err != nil {
+		t.Fatalf("InsertPriceData failed: %v", err)
+	}
+	for _, triggeredAt := range []time.Time{now.Add(-time.Hour), now.AddDate(0, 0, -30)} {
+		if err := db.InsertAlertTrigger(&models.AlertTrigger{RuleID: 1, RuleName: "rsi", Symbol: "TEST", Message: "RSI oversold", TriggeredAt: triggeredAt}); err != nil {
+			t.Fatalf("InsertAlertTrigger failed: %v", err)
+		}
+	}
+
+	report, err := ds.Build(models.DigestWeekly)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if len(report.Alerts) != 1 || len(report.Setups) != 0 || len(report.Patterns) != 0 {
+		t.Errorf("expected only the recent alert, got %d alerts, %d setups, %d patterns", len(report.Alerts), len(report.Setups), len(report.Patterns))
+	}
+	if report.Collection.WatchedSymbols != 2 || len(report.Collection.StaleSymbols) != 1 || report.Collection.StaleSymbols[0] != "OLD" {
+		t.Errorf("expected OLD to be stale, got %+v", report.Collection)
+	}
+
+	if _, err := ds.Build("monthly"); err == nil {
+		t.Error("expected an error for an invalid period")
+	}
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// defaultDisplayTimezone keeps responses in UTC unless another time zone is configured
+const defaultDisplayTimezone = "UTC"
+
+// displayZone is a display setting with its loaded time zone
+type displayZone struct {
+	settings models.DisplaySettings
+	location *time.Location
+}
+
+// DisplayService holds the time zone chart and summary responses and the dashboard show times in
+type DisplayService struct {
+	zone atomic.Pointer[displayZone]
+}
+
+// NewDisplayService creates a new display service with the configured time zone, UTC when unset or unknown
+func NewDisplayService(cfg *config.Config) *DisplayService {
+	ds := &DisplayService{}
+	settings := &models.DisplaySettings{Timezone: cfg.Display.Timezone}
+	if settings.Validate() != nil {
+		settings.Timezone = defaultDisplayTimezone
+	}
+	ds.SetConfig(settings)
+	return ds
+}
+
+// Config returns a copy of the display settings
+func (ds *DisplayService) Config() *models.DisplaySettings {
+	settings := ds.zone.Load().settings
+	return &settings
+}
+
+// SetConfig replaces the display settings, which must be valid
+func (ds *DisplayService) SetConfig(settings *models.DisplaySettings) {
+	location, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	ds.zone.Store(&displayZone{settings: *settings, location: location})
+}
+
+// Location returns the time zone named by tz, or the configured one when tz is empty
+func (ds *DisplayService) Location(tz string) (*time.Location, error) {
+	if tz == "" {
+		return ds.zone.Load().location, nil
+	}
+	location, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown time zone %q", ErrValidation, tz)
+	}
+	return location, nil
+}
@@ -1,15 +1,24 @@
 package services
 
 import (
+	"bytes"
+	"encoding/base64"
 	"fmt"
+	"log"
+	"mime/multipart"
 	"net/smtp"
+	"net/textproto"
 	"strings"
 
 	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
 )
 
 type EmailService struct {
-	config *config.EmailConfig
+	config        *config.EmailConfig
+	telegram      *TelegramService
+	charts        *ChartService
+	notifications *NotificationService
 }
 
 type EmailMessage struct {
@@ -17,6 +26,13 @@ type EmailMessage struct {
 	Subject string
 	Body    string
 	IsHTML  bool
+	Images  []EmailImage // PNG images, referenced by HTML bodies as cid:image1... and shown after plain ones
+}
+
+// EmailImage is a PNG image embedded in an email
+type EmailImage struct {
+	Name string
+	Data []byte
 }
 
 // NewEmailService creates a new email service
@@ -26,6 +42,26 @@ func NewEmailService(cfg *config.Config) *EmailService {
 	}
 }
 
+// SetConfig replaces the SMTP settings, e.g. after the config file is reloaded
+func (e *EmailService) SetConfig(cfg *config.EmailConfig) {
+	e.config = cfg
+}
+
+// SetTelegramService forwards pattern alerts to Telegram in addition to email
+func (e *EmailService) SetTelegramService(telegram *TelegramService) {
+	e.telegram = telegram
+}
+
+// SetChartService embeds a chart of the pattern in pattern alert emails
+func (e *EmailService) SetChartService(charts *ChartService) {
+	e.charts = charts
+}
+
+// SetNotificationService records alerts in the notification center in addition to email
+func (e *EmailService) SetNotificationService(notifications *NotificationService) {
+	e.notifications = notifications
+}
+
 // SendEmail sends an email using Gmail SMTP
 func (e *EmailService) SendEmail(message *EmailMessage) error {
 	if !e.config.Enabled {
@@ -43,26 +79,12 @@ func (e *EmailService) SendEmail(message *EmailMessage) error {
 	// and send the email all in one step
 	to := strings.Join(message.To, ",")
 
-	// Construct email headers
-	contentType := "text/plain"
-	if message.IsHTML {
-		contentType = "text/html"
+	msg, err := buildEmail(to, fmt.Sprintf("%s <%s>", e.config.FromName, e.config.FromAddress), message)
+	if err != nil {
+		return err
 	}
 
-	msg := []byte(fmt.Sprintf("To: %s\r\n"+
-		"From: %s <%s>\r\n"+
-		"Subject: %s\r\n"+
-		"Content-Type: %s; charset=UTF-8\r\n"+
-		"\r\n"+
-		"%s\r\n",
-		to,
-		e.config.FromName,
-		e.config.FromAddress,
-		message.Subject,
-		contentType,
-		message.Body))
-
-	err := smtp.SendMail(
+	err = smtp.SendMail(
 		fmt.Sprintf("%s:%d", e.config.SMTPHost, e.config.SMTPPort),
 		auth,
 		e.config.FromAddress,
@@ -77,87 +99,203 @@ func (e *EmailService) SendEmail(message *EmailMessage) error {
 	return nil
 }
 
-// SendTestEmail sends a test email to verify configuration
-func (e *EmailService) SendTestEmail(recipient string) error {
-	message := &EmailMessage{
-		To:      []string{recipient},
-		Subject: "Market Watch - Email Configuration Test",
-		Body: `This is a test email from your Market Watch application.
+// buildEmail constructs the raw message: a single part, or with images multipart/related for HTML bodies,
+// which reference them as cid:image1, cid:image2..., and multipart/mixed for plain text
+func buildEmail(to, from string, message *EmailMessage) ([]byte, error) {
+	// Construct email headers
+	contentType := "text/plain"
+	if message.IsHTML {
+		contentType = "text/html"
+	}
 
-If you received this email, your email notifications are configured correctly!
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "To: %s\r\nFrom: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\n", to, from, message.Subject)
 
-Configuration details:
-- SMTP Host: ` + e.config.SMTPHost + `
-- SMTP Port: ` + fmt.Sprintf("%d", e.config.SMTPPort) + `
-- From: ` + e.config.FromName + ` <` + e.config.FromAddress + `>
+	if len(message.Images) == 0 {
+		fmt.Fprintf(&buf, "Content-Type: %s; charset=UTF-8\r\n\r\n%s\r\n", contentType, message.Body)
+		return buf.Bytes(), nil
+	}
 
-You can now receive email notifications for:
-- Trading setup alerts
-- Price alerts
-- System notifications
-- Data collection status updates
+	kind := "mixed"
+	if message.IsHTML {
+		kind = "related"
+	}
+	parts := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/%s; boundary=%s\r\n\r\n", kind, parts.Boundary())
 
-Best regards,
-Market Watch System`,
-		IsHTML: false,
+	body, err := parts.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType + "; charset=UTF-8"}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create email body: %w", err)
+	}
+	fmt.Fprintf(body, "%s\r\n", message.Body)
+
+	for i, image := range message.Images {
+		part, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {fmt.Sprintf("image/png; name=%q", image.Name)},
+			"Content-Disposition":       {fmt.Sprintf("inline; filename=%q", image.Name)},
+			"Content-Id":                {fmt.Sprintf("<image%d>", i+1)},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create email image: %w", err)
+		}
+
+		// Base64 lines may be at most 76 characters
+		encoded := base64.StdEncoding.EncodeToString(image.Data)
+		for len(encoded) > 76 {
+			fmt.Fprintf(part, "%s\r\n", encoded[:76])
+			encoded = encoded[76:]
+		}
+		fmt.Fprintf(part, "%s\r\n", encoded)
 	}
 
-	return e.SendEmail(message)
+	if err := parts.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close email: %w", err)
+	}
+	return buf.Bytes(), nil
 }
 
-// SendThesisComponentAlert sends an email for a completed thesis component
-func (e *EmailService) SendThesisComponentAlert(pattern interface{}, component interface{}, emailData interface{}) error {
-	// For now, we'll use a simplified approach
-	// This method signature is kept for compatibility but internally uses the existing email structure
-
-	subject := "Thesis Component Alert"
-	body := "A thesis component has been completed. Please check the application for details."
+// SendAlert renders an alert from the templates of its type and emails it to the recipients of its severity.
+// Alerts marked for Telegram also go there as text, alerts with a notification category are recorded in the
+// notification center, and a pattern is embedded as a chart when it can be rendered.
+func (e *EmailService) SendAlert(alert *EmailAlert) error {
+	telegram := alert.Telegram && e.telegram.IsEnabled()
+	notify := alert.Notification != "" && e.notifications != nil
+	if telegram || notify {
+		_, text, err := renderEmail(alert, "", e.config.DashboardURL)
+		if err != nil {
+			return err
+		}
+		if notify {
+			e.notifications.Notify(&models.Notification{
+				Category: alert.Notification,
+				Severity: alert.Severity,
+				Symbol:   alert.Symbol,
+				Title:    alert.Subject,
+				Message:  text,
+			})
+		}
+		if telegram {
+			if err := e.telegram.SendMessage(alert.Subject + "\n" + text); err != nil {
+				log.Printf("Failed to send telegram alert: %v", err)
+			}
+		}
+
+		if !e.IsConfigured() {
+			return nil
+		}
+	}
 
-	message := &EmailMessage{
-		To:      []string{"admin@example.com"}, // TODO: Get from config
-		Subject: subject,
-		Body:    body,
-		IsHTML:  false,
+	var images []EmailImage
+	if alert.Pattern != nil && e.charts != nil && e.IsConfigured() {
+		chart, err := e.charts.RenderPattern(alert.Pattern)
+		if err != nil {
+			log.Printf("Failed to render pattern chart: %v", err)
+		} else {
+			images = append(images, EmailImage{Name: "chart.png", Data: chart})
+		}
 	}
 
-	return e.SendEmail(message)
-}
+	chartURL := ""
+	if len(images) > 0 {
+		chartURL = "cid:image1"
+	}
+	html, _, err := renderEmail(alert, chartURL, e.config.DashboardURL)
+	if err != nil {
+		return err
+	}
 
-// SendTradingAlert sends a trading setup alert email
-func (e *EmailService) SendTradingAlert(recipient, symbol, setupType string, score float64, details string) error {
-	subject := fmt.Sprintf("🚨 Trading Alert: %s - %s Setup (Score: %.1f)", symbol, setupType, score)
+	to := alert.To
+	if len(to) == 0 {
+		to = e.Recipients(alert.Severity)
+	}
 
-	body := fmt.Sprintf(`Trading Setup Alert
+	return e.SendEmail(&EmailMessage{
+		To:      to,
+		Subject: alert.Subject,
+		Body:    html,
+		IsHTML:  true,
+		Images:  images,
+	})
+}
 
-Symbol: %s
-Setup Type: %s
-Quality Score: %.1f/100
+// Recipients returns who receives alerts of a severity, falling back to the sender address
+func (e *EmailService) Recipients(severity string) []string {
+	var recipients []string
+	switch severity {
+	case EmailSeverityHigh:
+		recipients = e.config.Recipients.High
+	case EmailSeverityMedium:
+		recipients = e.config.Recipients.Medium
+	case EmailSeverityLow:
+		recipients = e.config.Recipients.Low
+	}
+	if len(recipients) == 0 {
+		return []string{e.config.FromAddress}
+	}
+	return recipients
+}
 
-Details:
-%s
+// SendTestAlert sends an alert type's template filled with example data to a recipient, or to the
+// recipients of the example's severity when recipient is empty, and returns who it was sent to
+func (e *EmailService) SendTestAlert(alertType, recipient string) ([]string, error) {
+	alert, err := sampleEmailAlert(alertType, &TestEmail{
+		SMTPHost: e.config.SMTPHost,
+		SMTPPort: e.config.SMTPPort,
+		From:     fmt.Sprintf("%s <%s>", e.config.FromName, e.config.FromAddress),
+	})
+	if err != nil {
+		return nil, err
+	}
 
-This is an automated alert from your Market Watch system.
-Please verify all information before making any trading decisions.
+	alert.To = e.Recipients(alert.Severity)
+	if recipient != "" {
+		alert.To = []string{recipient}
+	}
 
-Timestamp: %s
-Dashboard: http://localhost:8080/
+	return alert.To, e.SendAlert(alert)
+}
 
-Best regards,
-Market Watch System`,
-		symbol,
-		setupType,
-		score,
-		details,
-		fmt.Sprintf("%v", "now")) // You can replace with actual timestamp
+// SendThesisComponentAlert sends an alert for a completed thesis component with a chart of its pattern.
+// Breakout components are high severity, target components medium and formation components low.
+func (e *EmailService) SendThesisComponentAlert(data *ComponentCompletedEmail, pattern interface{}) error {
+	return e.SendAlert(&EmailAlert{
+		Type:     EmailAlertComponentCompleted,
+		Severity: componentSeverity(data.Phase),
+		Subject:  fmt.Sprintf("📊 %s: %s Component Completed", data.Symbol, data.Component.Name),
+		Data:     data,
+		Pattern:  pattern,
+		Telegram: true,
+
+		Notification: models.NotificationPattern,
+		Symbol:       data.Symbol,
+	})
+}
 
-	message := &EmailMessage{
-		To:      []string{recipient},
-		Subject: subject,
-		Body:    body,
-		IsHTML:  false,
-	}
+// SendPatternDetectedAlert sends a medium severity alert for a newly detected pattern with a chart of it
+func (e *EmailService) SendPatternDetectedAlert(data *PatternDetectedEmail, pattern interface{}) error {
+	return e.SendAlert(&EmailAlert{
+		Type:     EmailAlertPatternDetected,
+		Severity: EmailSeverityMedium,
+		Subject:  fmt.Sprintf("🔍 %s: %s Pattern Detected", data.Symbol, data.PatternName),
+		Data:     data,
+		Pattern:  pattern,
+		Telegram: true,
+
+		Notification: models.NotificationPattern,
+		Symbol:       data.Symbol,
+	})
+}
 
-	return e.SendEmail(message)
+// SendTradingAlert sends a high severity trading setup alert email to a recipient
+func (e *EmailService) SendTradingAlert(recipient, symbol, setupType string, score float64, details string) error {
+	return e.SendAlert(&EmailAlert{
+		Type:     EmailAlertTradingSetup,
+		Severity: EmailSeverityHigh,
+		Subject:  fmt.Sprintf("🚨 Trading Alert: %s - %s Setup (Score: %.1f)", symbol, setupType, score),
+		Data:     &TradingSetupEmail{Symbol: symbol, SetupType: setupType, Score: score, Details: details},
+		To:       []string{recipient},
+	})
 }
 
 // IsConfigured checks if email service is properly configured
@@ -169,6 +307,11 @@ func (e *EmailService) IsConfigured() bool {
 		e.config.SMTPPort > 0
 }
 
+// CanNotify checks if alerts can be delivered by email, Telegram or the notification center
+func (e *EmailService) CanNotify() bool {
+	return e.IsConfigured() || e.telegram.IsEnabled() || e.notifications != nil
+}
+
 // GetConfigStatus returns the configuration status for debugging
 func (e *EmailService) GetConfigStatus() map[string]interface{} {
 	return map[string]interface{}{
@@ -180,5 +323,10 @@ func (e *EmailService) GetConfigStatus() map[string]interface{} {
 		"configured":   e.IsConfigured(),
 		"username_set": e.config.Username != "",
 		"password_set": e.config.Password != "",
+		"recipients": map[string][]string{
+			EmailSeverityHigh:   e.Recipients(EmailSeverityHigh),
+			EmailSeverityMedium: e.Recipients(EmailSeverityMedium),
+			EmailSeverityLow:    e.Recipients(EmailSeverityLow),
+		},
 	}
 }
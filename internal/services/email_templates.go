@@ -0,0 +1,238 @@
+package services
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// Email alert types, each rendered by templates/email/<type>.html and <type>.txt
+const (
+	EmailAlertTest               = "test"
+	EmailAlertPatternDetected    = "pattern_detected"
+	EmailAlertComponentCompleted = "component_completed"
+	EmailAlertRule               = "alert_rule"
+	EmailAlertTradingSetup       = "trading_setup"
+	EmailAlertDigest             = "digest"
+)
+
+// EmailAlertTypes lists the email alert types
+var EmailAlertTypes = []string{EmailAlertTest, EmailAlertPatternDetected, EmailAlertComponentCompleted, EmailAlertRule, EmailAlertTradingSetup, EmailAlertDigest}
+
+// Email alert severities, matching the setup alert severities; each has its own recipients
+const (
+	EmailSeverityHigh   = "high"
+	EmailSeverityMedium = "medium"
+	EmailSeverityLow    = "low"
+)
+
+const defaultDashboardURL = "http://localhost:8080/"
+
+//go:embed templates/email
+var emailTemplateFS embed.FS
+
+// emailTemplates holds the parsed HTML (inside the shared layout) and text templates of every alert type
+var emailTemplates = mustParseEmailTemplates()
+
+// EmailAlert is an alert email rendered from the templates of its type
+type EmailAlert struct {
+	Type     string // one of EmailAlertTypes
+	Severity string // 'high', 'medium' or 'low'; selects the recipients
+	Subject  string
+	Data     interface{} // the template data of the type, e.g. *PatternDetectedEmail
+	Pattern  interface{} // embedded as a chart when set
+	To       []string    // overrides the recipients of the severity
+	Telegram bool        // also forward the text version to Telegram
+
+	Notification string // notification center category of the alert, if it is recorded there
+	Symbol       string // symbol of the notification
+}
+
+// PatternDetectedEmail is the data of a pattern_detected email
+type PatternDetectedEmail struct {
+	Symbol        string
+	PatternName   string
+	BreakoutLevel float64
+	TargetPrice   float64
+	Completion    float64
+	DetectedAt    time.Time
+}
+
+// ComponentCompletedEmail is the data of a component_completed email
+type ComponentCompletedEmail struct {
+	Symbol              string
+	PatternName         string
+	Phase               string
+	Component           *models.ThesisComponent
+	CompletedComponents int // 0 when the pattern doesn't count components
+	TotalComponents     int
+	TargetPrice         float64
+	LevelName           string // the level the pattern breaks, e.g. "Neckline Level"
+	Level               float64
+}
+
+// TradingSetupEmail is the data of a trading_setup email
+type TradingSetupEmail struct {
+	Symbol    string
+	SetupType string
+	Score     float64
+	Details   string
+}
+
+// TestEmail is the data of a test email
+type TestEmail struct {
+	SMTPHost string
+	SMTPPort int
+	From     string
+}
+
+type emailTemplateSet struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+// emailLayout is the data of the shared HTML layout
+type emailLayout struct {
+	Subject      string
+	Severity     string
+	Data         interface{}
+	Chart        htmltemplate.URL // cid: reference of the embedded chart, if any
+	DashboardURL string
+	SentAt       time.Time
+}
+
+// mustParseEmailTemplates parses the embedded templates; they ship with the binary, so a parse error is a bug
+func mustParseEmailTemplates() map[string]*emailTemplateSet {
+	funcs := htmltemplate.FuncMap{"severityColor": severityColor, "join": strings.Join}
+	layout := htmltemplate.Must(htmltemplate.New("layout.html").Funcs(funcs).ParseFS(emailTemplateFS, "templates/email/layout.html"))
+
+	templates := make(map[string]*emailTemplateSet)
+	for _, alertType := range EmailAlertTypes {
+		html := htmltemplate.Must(htmltemplate.Must(layout.Clone()).ParseFS(emailTemplateFS, "templates/email/"+alertType+".html"))
+		text := texttemplate.Must(texttemplate.New(alertType+".txt").Funcs(texttemplate.FuncMap{"join": strings.Join}).ParseFS(emailTemplateFS, "templates/email/"+alertType+".txt"))
+		templates[alertType] = &emailTemplateSet{html: html, text: text}
+	}
+	return templates
+}
+
+// renderEmail renders an alert's HTML body, referencing chart (a cid: URL) when not empty, and its text version
+func renderEmail(alert *EmailAlert, chart, dashboardURL string) (string, string, error) {
+	templates, ok := emailTemplates[alert.Type]
+	if !ok {
+		return "", "", fmt.Errorf("unknown email alert type %q: must be one of %s", alert.Type, strings.Join(EmailAlertTypes, ", "))
+	}
+	if dashboardURL == "" {
+		dashboardURL = defaultDashboardURL
+	}
+
+	var html bytes.Buffer
+	err := templates.html.Execute(&html, &emailLayout{
+		Subject:      alert.Subject,
+		Severity:     alert.Severity,
+		Data:         alert.Data,
+		Chart:        htmltemplate.URL(chart),
+		DashboardURL: dashboardURL,
+		SentAt:       clockNow(),
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to render %s email: %w", alert.Type, err)
+	}
+
+	var text bytes.Buffer
+	if err := templates.text.Execute(&text, alert.Data); err != nil {
+		return "", "", fmt.Errorf("failed to render %s text: %w", alert.Type, err)
+	}
+
+	return html.String(), strings.TrimSpace(text.String()), nil
+}
+
+// severityColor returns the accent color of a severity in the email layout
+func severityColor(severity string) string {
+	switch severity {
+	case EmailSeverityHigh:
+		return "#c62828"
+	case EmailSeverityMedium:
+		return "#f57c00"
+	default:
+		return "#1976d2"
+	}
+}
+
+// componentSeverity rates a completed thesis component by the phase it completes: breakout
+// components call for action, target components for follow-up, formation components are informational
+func componentSeverity(phase string) string {
+	switch phase {
+	case models.PhaseBreakout:
+		return EmailSeverityHigh
+	case models.PhaseTargetPursuit, models.PhaseCompleted:
+		return EmailSeverityMedium
+	default:
+		return EmailSeverityLow
+	}
+}
+
+// sampleEmailAlert returns an alert of the given type filled with example data, for test sends
+func sampleEmailAlert(alertType string, cfg *TestEmail) (*EmailAlert, error) {
+	now := clockNow()
+	alert := &EmailAlert{Type: alertType, Severity: EmailSeverityLow}
+
+	switch alertType {
+	case EmailAlertTest:
+		alert.Subject = "Market Watch - Email Configuration Test"
+		alert.Data = cfg
+	case EmailAlertPatternDetected:
+		alert.Severity = EmailSeverityMedium
+		alert.Subject = "🔍 TEST: Inverse Head & Shoulders Pattern Detected"
+		alert.Data = &PatternDetectedEmail{Symbol: "TEST", PatternName: "Inverse Head & Shoulders", BreakoutLevel: 102.5, TargetPrice: 110, Completion: 45, DetectedAt: now}
+	case EmailAlertComponentCompleted:
+		alert.Severity = EmailSeverityHigh
+		alert.Subject = "📊 TEST: Neckline Breakout Component Completed"
+		alert.Data = &ComponentCompletedEmail{
+			Symbol:      "TEST",
+			PatternName: "Inverse Head & Shoulders",
+			Phase:       models.PhaseBreakout,
+			Component: &models.ThesisComponent{
+				Name:        "Neckline Breakout",
+				Description: "Price closes above the neckline",
+				CompletedAt: &now,
+				Evidence:    []string{"Close $103.10 above neckline $102.50", "Volume 1.8x average"},
+			},
+			CompletedComponents: 6,
+			TotalComponents:     12,
+			TargetPrice:         110,
+			LevelName:           "Neckline Level",
+			Level:               102.5,
+		}
+	case EmailAlertRule:
+		alert.Severity = EmailSeverityMedium
+		alert.Subject = "🔔 TEST: RSI oversold"
+		alert.Data = &models.AlertTrigger{RuleName: "RSI oversold", Symbol: "TEST", Message: "TEST matched RSI oversold", Values: map[string]float64{"price": 98.4, "rsi": 28.6}, TriggeredAt: now}
+	case EmailAlertTradingSetup:
+		alert.Severity = EmailSeverityHigh
+		alert.Subject = "🚨 Trading Alert: TEST - bullish Setup (Score: 85.0)"
+		alert.Data = &TradingSetupEmail{Symbol: "TEST", SetupType: "bullish", Score: 85, Details: "Entry $102.60, stop $99.80, target $110.00"}
+	case EmailAlertDigest:
+		alert.Subject = "📰 Market Watch Daily digest: 1 setups, 1 patterns, 1 alerts"
+		alert.Data = &models.DigestReport{
+			Period:          models.DigestDaily,
+			From:            now.AddDate(0, 0, -1),
+			To:              now,
+			MinQualityScore: 80,
+			Setups:          []*models.TradingSetup{{Symbol: "TEST", SetupType: "support_bounce", Direction: "bullish", QualityScore: 86.5, EntryPrice: 102.6, StopLoss: 99.8}},
+			Patterns:        []*models.DigestPattern{{Family: ChartFamilyHeadShoulders, Symbol: "TEST", PatternType: models.SetupTypeInverseHeadShoulders, Phase: models.PhaseBreakout, CompletionPercent: 58}},
+			Alerts:          []*models.AlertTrigger{{Message: "TEST: RSI oversold triggered (rsi < 30)", TriggeredAt: now}},
+			Collection:      &models.DigestCollection{LastRun: now, SuccessfulRuns: 96, WatchedSymbols: 12, StaleSymbols: []string{"OLD"}},
+			GeneratedAt:     now,
+		}
+	default:
+		return nil, fmt.Errorf("unknown email alert type %q: must be one of %s", alertType, strings.Join(EmailAlertTypes, ", "))
+	}
+
+	return alert, nil
+}
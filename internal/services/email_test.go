@@ -0,0 +1,79 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRenderEmailTemplates tests that every alert type renders its example in the layout and as text
+func TestRenderEmailTemplates(t *testing.T) {
+	for _, alertType := range EmailAlertTypes {
+		alert, err := sampleEmailAlert(alertType, &TestEmail{SMTPHost: "smtp.example.com", SMTPPort: 587, From: "Market Watch <mw@example.com>"})
+		if err != nil {
+			t.Fatalf("%s: %v", alertType, err)
+		}
+
+		html, text, err := renderEmail(alert, "cid:image1", "")
+		if err != nil {
+			t.Fatalf("%s: render failed: %v", alertType, err)
+		}
+		if !strings.Contains(html, `src="cid:image1"`) || !strings.Contains(html, defaultDashboardURL) {
+			t.Errorf("%s: layout is missing the chart or dashboard link", alertType)
+		}
+		if strings.Contains(html, "<no value>") || strings.Contains(text, "<no value>") {
+			t.Errorf("%s: template references a missing field", alertType)
+		}
+		if text == "" || strings.Contains(text, "<td") || strings.Contains(text, "&#") {
+			t.Errorf("%s: unexpected text version %q", alertType, text)
+		}
+	}
+
+	// Data is escaped in the HTML version only
+	alert := &EmailAlert{Type: EmailAlertTradingSetup, Severity: EmailSeverityHigh, Subject: "Setup",
+		Data: &TradingSetupEmail{Symbol: "AAPL", SetupType: "bullish", Score: 80, Details: "<b>entry</b> & stop"}}
+	html, text, err := renderEmail(alert, "", "")
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	if !strings.Contains(html, "&lt;b&gt;entry&lt;/b&gt; &amp; stop") || strings.Contains(html, "cid:") {
+		t.Error("expected escaped details and no chart in the HTML version")
+	}
+	if !strings.Contains(text, "<b>entry</b> & stop") {
+		t.Errorf("expected raw details in the text version, got %q", text)
+	}
+
+	if _, _, err := renderEmail(&EmailAlert{Type: "unknown"}, "", ""); err == nil {
+		t.Error("expected an error for an unknown alert type")
+	}
+}
+
+// TestBuildEmail tests single part messages and multipart messages with inline images
+func TestBuildEmail(t *testing.T) {
+	msg, err := buildEmail("a@example.com", "MW <mw@example.com>", &EmailMessage{Subject: "Plain", Body: "hello"})
+	if err != nil {
+		t.Fatalf("buildEmail failed: %v", err)
+	}
+	if !strings.Contains(string(msg), "Content-Type: text/plain; charset=UTF-8\r\n\r\nhello") {
+		t.Errorf("unexpected plain message:\n%s", msg)
+	}
+
+	msg, err = buildEmail("a@example.com", "MW <mw@example.com>", &EmailMessage{
+		Subject: "Chart",
+		Body:    `<img src="cid:image1">`,
+		IsHTML:  true,
+		Images:  []EmailImage{{Name: "chart.png", Data: make([]byte, 100)}},
+	})
+	if err != nil {
+		t.Fatalf("buildEmail failed: %v", err)
+	}
+	for _, want := range []string{"Content-Type: multipart/related; boundary=", "Content-Id: <image1>", "Content-Transfer-Encoding: base64", "Content-Type: text/html; charset=UTF-8"} {
+		if !strings.Contains(string(msg), want) {
+			t.Errorf("expected %q in message:\n%s", want, msg)
+		}
+	}
+	for _, line := range strings.Split(string(msg), "\r\n") {
+		if len(line) > 76 && !strings.HasPrefix(line, "Content-") {
+			t.Errorf("line longer than 76 characters: %q", line)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package services
+
+import "errors"
+
+// Error kinds shared by the services. Errors returned by services wrap one of these, or
+// database.ErrNotFound, so API handlers can map them to a status code without matching on text.
+var (
+	// ErrValidation is returned, wrapped with the reason, when input fails validation
+	ErrValidation = errors.New("validation failed")
+
+	// ErrUpstreamRateLimited is returned when a request was not made, or was rejected, because of API rate limits
+	ErrUpstreamRateLimited = errors.New("rate limit exceeded")
+
+	// ErrUpstreamUnavailable is returned when a market data provider can't be reached or fails a request
+	ErrUpstreamUnavailable = errors.New("market data provider unavailable")
+
+	// ErrUnavailable is returned when a feature's service is disabled or not configured
+	ErrUnavailable = errors.New("service unavailable")
+
+	// ErrNoPattern is returned by the pattern detectors when the price data holds no pattern, or too few bars to find one
+	ErrNoPattern = errors.New("no valid pattern found")
+)
@@ -0,0 +1,772 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// Pattern families in pattern exports, matching the unified pattern list
+const (
+	patternFamilyHeadShoulders = "head_shoulders"
+	patternFamilyFallingWedge  = "falling_wedge"
+	patternFamilyTriangle      = "triangle"
+	patternFamilyFlag          = "flag"
+)
+
+// ExportService dumps stored data as CSV or Parquet and loads it back, so data can be moved
+// between instances or seeded from external datasets
+type ExportService struct {
+	db     *database.Database
+	prices *PriceCache
+}
+
+// NewExportService creates a new export service
+func NewExportService(db *database.Database) *ExportService {
+	return &ExportService{db: db}
+}
+
+// SetPriceCache sets the price cache invalidated for symbols whose bars are imported
+func (es *ExportService) SetPriceCache(prices *PriceCache) {
+	es.prices = prices
+}
+
+// ValidateExportFormat normalizes a format name, defaulting to CSV
+func ValidateExportFormat(format string) (string, error) {
+	switch format = strings.ToLower(format); format {
+	case "":
+		return models.FormatCSV, nil
+	case models.FormatCSV, models.FormatParquet:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q: must be csv or parquet", format)
+	}
+}
+
+// ParseExportRange parses optional from and to times; a bare end date includes the whole day
+func ParseExportRange(fromStr, toStr string) (time.Time, time.Time, error) {
+	var from, to time.Time
+	var err error
+
+	if fromStr != "" {
+		if from, err = ParseExportTime(fromStr); err != nil {
+			return from, to, fmt.Errorf("invalid from: %w", err)
+		}
+	}
+	if toStr != "" {
+		if to, err = ParseExportTime(toStr); err != nil {
+			return from, to, fmt.Errorf("invalid to: %w", err)
+		}
+		if len(toStr) == len("2006-01-02") {
+			to = to.Add(24*time.Hour - time.Nanosecond)
+		}
+	}
+	if !to.IsZero() && to.Before(from) {
+		return from, to, fmt.Errorf("to must not be before from")
+	}
+
+	return from, to, nil
+}
+
+// ValidateExportDataset checks a dataset name
+func ValidateExportDataset(dataset string) error {
+	for _, known := range models.ExportDatasets {
+		if dataset == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported dataset %q: must be one of %s", dataset, strings.Join(models.ExportDatasets, ", "))
+}
+
+// Export writes the requested dataset to w and returns the number of rows written
+func (es *ExportService) Export(w io.Writer, req *models.ExportRequest) (int, error) {
+	if err := ValidateExportDataset(req.Dataset); err != nil {
+		return 0, err
+	}
+	format, err := ValidateExportFormat(req.Format)
+	if err != nil {
+		return 0, err
+	}
+	if req.To.IsZero() {
+		req.To = time.Now()
+	}
+
+	switch req.Dataset {
+	case models.DatasetPrice:
+		records, err := es.priceRecords(req)
+		if err != nil {
+			return 0, err
+		}
+		return len(records), writeRecords(w, format, records)
+	case models.DatasetVolume:
+		records, err := es.volumeRecords(req)
+		if err != nil {
+			return 0, err
+		}
+		return len(records), writeRecords(w, format, records)
+	case models.DatasetLevels:
+		records, err := es.levelRecords(req)
+		if err != nil {
+			return 0, err
+		}
+		return len(records), writeRecords(w, format, records)
+	case models.DatasetSetups:
+		records, err := es.setupRecords(req)
+		if err != nil {
+			return 0, err
+		}
+		return len(records), writeRecords(w, format, records)
+	default:
+		records, err := es.patternRecords(req)
+		if err != nil {
+			return 0, err
+		}
+		return len(records), writeRecords(w, format, records)
+	}
+}
+
+// Import reads a dataset from r and stores it. Price and volume bars replace bars at the same
+// timestamp; levels, setups and patterns are added as new rows.
+func (es *ExportService) Import(r io.Reader, dataset, format string) (*models.ImportResult, error) {
+	if err := ValidateExportDataset(dataset); err != nil {
+		return nil, err
+	}
+	format, err := ValidateExportFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ImportResult{Dataset: dataset, Format: format}
+
+	switch dataset {
+	case models.DatasetPrice:
+		records, err := readRecords[models.PriceRecord](r, format)
+		if err != nil {
+			return nil, err
+		}
+		result.Rows = len(records)
+		result.Imported, err = es.importPrice(records)
+		return result, err
+	case models.DatasetVolume:
+		records, err := readRecords[models.VolumeRecord](r, format)
+		if err != nil {
+			return nil, err
+		}
+		result.Rows = len(records)
+		result.Imported, err = es.importVolume(records)
+		return result, err
+	case models.DatasetLevels:
+		records, err := readRecords[models.LevelRecord](r, format)
+		if err != nil {
+			return nil, err
+		}
+		result.Rows = len(records)
+		result.Imported, err = es.importLevels(records)
+		return result, err
+	case models.DatasetSetups:
+		records, err := readRecords[models.SetupRecord](r, format)
+		if err != nil {
+			return nil, err
+		}
+		result.Rows = len(records)
+		result.Imported, err = es.importSetups(records)
+		return result, err
+	default:
+		records, err := readRecords[models.PatternRecord](r, format)
+		if err != nil {
+			return nil, err
+		}
+		result.Rows = len(records)
+		result.Imported, err = es.importPatterns(records)
+		return result, err
+	}
+}
+
+// exportSymbols returns the requested symbol, or every symbol with stored bars
+func (es *ExportService) exportSymbols(req *models.ExportRequest) ([]string, error) {
+	if req.Symbol != "" {
+		return []string{strings.ToUpper(req.Symbol)}, nil
+	}
+	symbols, err := es.db.GetSymbolsWithPriceData()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get symbols: %w", err)
+	}
+	return symbols, nil
+}
+
+// inRange reports whether t falls within the export range
+func inRange(t time.Time, req *models.ExportRequest) bool {
+	return !t.Before(req.From) && !t.After(req.To)
+}
+
+func (es *ExportService) priceRecords(req *models.ExportRequest) ([]models.PriceRecord, error) {
+	symbols, err := es.exportSymbols(req)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []models.PriceRecord{}
+	for _, symbol := range symbols {
+		bars, err := es.db.GetPriceDataRange(symbol, req.From, req.To)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get price data for %s: %w", symbol, err)
+		}
+		for _, bar := range bars {
+			records = append(records, models.PriceRecord{
+				Symbol:    bar.Symbol,
+				Timestamp: bar.Timestamp,
+				Open:      bar.Open,
+				High:      bar.High,
+				Low:       bar.Low,
+				Close:     bar.Close,
+				Volume:    bar.Volume,
+			})
+		}
+	}
+	return records, nil
+}
+
+func (es *ExportService) volumeRecords(req *models.ExportRequest) ([]models.VolumeRecord, error) {
+	symbols, err := es.exportSymbols(req)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []models.VolumeRecord{}
+	for _, symbol := range symbols {
+		bars, err := es.db.GetVolumeData(&models.VolumeDataFilter{Symbol: symbol, From: req.From, To: req.To})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get volume data for %s: %w", symbol, err)
+		}
+		for _, bar := range bars {
+			records = append(records, models.VolumeRecord{
+				Symbol:    bar.Symbol,
+				Timestamp: bar.Timestamp,
+				Volume:    bar.Volume,
+			})
+		}
+	}
+	return records, nil
+}
+
+func (es *ExportService) levelRecords(req *models.ExportRequest) ([]models.LevelRecord, error) {
+	levels, err := es.db.GetSupportResistanceLevels(&models.SRDetectionFilter{Symbol: strings.ToUpper(req.Symbol)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get support/resistance levels: %w", err)
+	}
+
+	records := []models.LevelRecord{}
+	for _, level := range levels {
+		// Levels are exported when they were touched at any point in the range
+		if level.LastTouch.Before(req.From) || level.FirstTouch.After(req.To) {
+			continue
+		}
+		records = append(records, models.LevelRecord{
+			Symbol:           level.Symbol,
+			Level:            level.Level,
+			LevelType:        level.LevelType,
+			Strength:         level.Strength,
+			Touches:          int64(level.Touches),
+			FirstTouch:       level.FirstTouch,
+			LastTouch:        level.LastTouch,
+			VolumeConfirmed:  level.VolumeConfirmed,
+			AvgVolume:        level.AvgVolume,
+			MaxBouncePercent: level.MaxBouncePercent,
+			AvgBouncePercent: level.AvgBouncePercent,
+			TimeframeOrigin:  level.TimeframeOrigin,
+			IsActive:         level.IsActive,
+			LastValidated:    level.LastValidated,
+		})
+	}
+	return records, nil
+}
+
+func (es *ExportService) setupRecords(req *models.ExportRequest) ([]models.SetupRecord, error) {
+	setups, err := es.db.GetTradingSetups(&models.SetupFilter{Symbol: strings.ToUpper(req.Symbol), IncludeArchived: true, Sort: "detected_at", Order: models.SortAsc})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trading setups: %w", err)
+	}
+
+	records := []models.SetupRecord{}
+	for _, setup := range setups {
+		if !inRange(setup.DetectedAt, req) {
+			continue
+		}
+
+		checklist, err := es.db.GetSetupChecklist(setup.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get checklist for setup %d: %w", setup.ID, err)
+		}
+		setup.Checklist = checklist
+
+		data, err := json.Marshal(setup)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode setup %d: %w", setup.ID, err)
+		}
+		records = append(records, models.SetupRecord{
+			Symbol:       setup.Symbol,
+			SetupType:    setup.SetupType,
+			Direction:    setup.Direction,
+			Status:       setup.Status,
+			QualityScore: setup.QualityScore,
+			DetectedAt:   setup.DetectedAt,
+			ExpiresAt:    setup.ExpiresAt,
+			Data:         string(data),
+		})
+	}
+	return records, nil
+}
+
+func (es *ExportService) patternRecords(req *models.ExportRequest) ([]models.PatternRecord, error) {
+	symbol := strings.ToUpper(req.Symbol)
+	records := []models.PatternRecord{}
+	add := func(family, symbol string, detectedAt, lastUpdated time.Time, pattern interface{}) error {
+		if !inRange(detectedAt, req) {
+			return nil
+		}
+		data, err := json.Marshal(pattern)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s pattern: %w", family, err)
+		}
+		records = append(records, models.PatternRecord{
+			PatternFamily: family,
+			Symbol:        symbol,
+			DetectedAt:    detectedAt,
+			LastUpdated:   lastUpdated,
+			Data:          string(data),
+		})
+		return nil
+	}
+
+	hsPatterns, err := es.db.GetHeadShouldersPatterns(&models.PatternFilter{Symbol: symbol, IncludeArchived: true, Sort: "detected_at", Order: models.SortAsc})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get head and shoulders patterns: %w", err)
+	}
+	for _, p := range hsPatterns {
+		if err := add(patternFamilyHeadShoulders, p.Symbol, p.DetectedAt, p.LastUpdated, p); err != nil {
+			return nil, err
+		}
+	}
+
+	wedges, err := es.db.GetFallingWedgePatterns(&models.FallingWedgeFilter{Symbol: symbol, IncludeArchived: true, Sort: "detected_at", Order: models.SortAsc})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get falling wedge patterns: %w", err)
+	}
+	for _, p := range wedges {
+		if err := add(patternFamilyFallingWedge, p.Symbol, p.DetectedAt, p.LastUpdated, p); err != nil {
+			return nil, err
+		}
+	}
+
+	triangles, err := es.db.GetTrianglePatterns(&models.TriangleFilter{Symbol: symbol, IncludeArchived: true, Sort: "detected_at", Order: models.SortAsc})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get triangle patterns: %w", err)
+	}
+	for _, p := range triangles {
+		if err := add(patternFamilyTriangle, p.Symbol, p.DetectedAt, p.LastUpdated, p); err != nil {
+			return nil, err
+		}
+	}
+
+	flags, err := es.db.GetFlagPatterns(&models.FlagFilter{Symbol: symbol, IncludeArchived: true, Sort: "detected_at", Order: models.SortAsc})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flag patterns: %w", err)
+	}
+	for _, p := range flags {
+		if err := add(patternFamilyFlag, p.Symbol, p.DetectedAt, p.LastUpdated, p); err != nil {
+			return nil, err
+		}
+	}
+
+	return records, nil
+}
+
+func (es *ExportService) importPrice(records []models.PriceRecord) (int, error) {
+	now := time.Now()
+	bars := make([]*models.PriceData, 0, len(records))
+	for i, record := range records {
+		if record.Symbol == "" || record.Timestamp.IsZero() {
+			return 0, fmt.Errorf("row %d: symbol and timestamp are required", i+1)
+		}
+		bars = append(bars, &models.PriceData{
+			Symbol:    strings.ToUpper(record.Symbol),
+			Timestamp: record.Timestamp.UTC(),
+			Open:      record.Open,
+			High:      record.High,
+			Low:       record.Low,
+			Close:     record.Close,
+			Volume:    record.Volume,
+			CreatedAt: now,
+		})
+	}
+	if len(bars) == 0 {
+		return 0, nil
+	}
+
+	if err := es.db.InsertPriceDataBatch(bars); err != nil {
+		return 0, fmt.Errorf("failed to import price data: %w", err)
+	}
+	if es.prices != nil {
+		for _, bar := range bars {
+			es.prices.Invalidate(bar.Symbol)
+		}
+	}
+	return len(bars), nil
+}
+
+func (es *ExportService) importVolume(records []models.VolumeRecord) (int, error) {
+	now := time.Now()
+	bars := make([]*models.VolumeData, 0, len(records))
+	for i, record := range records {
+		if record.Symbol == "" || record.Timestamp.IsZero() {
+			return 0, fmt.Errorf("row %d: symbol and timestamp are required", i+1)
+		}
+		bars = append(bars, &models.VolumeData{
+			Symbol:    strings.ToUpper(record.Symbol),
+			Timestamp: record.Timestamp.UTC(),
+			Volume:    record.Volume,
+			CreatedAt: now,
+		})
+	}
+	if len(bars) == 0 {
+		return 0, nil
+	}
+
+	if err := es.db.InsertVolumeDataBatch(bars); err != nil {
+		return 0, fmt.Errorf("failed to import volume data: %w", err)
+	}
+	return len(bars), nil
+}
+
+func (es *ExportService) importLevels(records []models.LevelRecord) (int, error) {
+	now := time.Now()
+	err := es.db.WithTx(func(tx *database.DB) error {
+		for i, record := range records {
+			if record.Symbol == "" || record.Level <= 0 {
+				return fmt.Errorf("row %d: symbol and level are required", i+1)
+			}
+			level := &models.SupportResistanceLevel{
+				Symbol:           strings.ToUpper(record.Symbol),
+				Level:            record.Level,
+				LevelType:        record.LevelType,
+				Strength:         record.Strength,
+				Touches:          int(record.Touches),
+				FirstTouch:       record.FirstTouch,
+				LastTouch:        record.LastTouch,
+				VolumeConfirmed:  record.VolumeConfirmed,
+				AvgVolume:        record.AvgVolume,
+				MaxBouncePercent: record.MaxBouncePercent,
+				AvgBouncePercent: record.AvgBouncePercent,
+				TimeframeOrigin:  record.TimeframeOrigin,
+				IsActive:         record.IsActive,
+				LastValidated:    record.LastValidated,
+				CreatedAt:        now,
+				UpdatedAt:        now,
+			}
+			if err := tx.InsertSupportResistanceLevel(level); err != nil {
+				return fmt.Errorf("row %d: %w", i+1, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to import support/resistance levels: %w", err)
+	}
+	return len(records), nil
+}
+
+func (es *ExportService) importSetups(records []models.SetupRecord) (int, error) {
+	err := es.db.WithTx(func(tx *database.DB) error {
+		for i, record := range records {
+			setup := &models.TradingSetup{}
+			if record.Data != "" {
+				if err := json.Unmarshal([]byte(record.Data), setup); err != nil {
+					return fmt.Errorf("row %d: invalid setup data: %w", i+1, err)
+				}
+			} else {
+				setup.Symbol = record.Symbol
+				setup.SetupType = record.SetupType
+				setup.Direction = record.Direction
+				setup.Status = record.Status
+				setup.QualityScore = record.QualityScore
+				setup.DetectedAt = record.DetectedAt
+				setup.ExpiresAt = record.ExpiresAt
+			}
+			if setup.Symbol == "" {
+				return fmt.Errorf("row %d: symbol is required", i+1)
+			}
+			setup.ID = 0
+			setup.Symbol = strings.ToUpper(setup.Symbol)
+
+			if err := tx.InsertTradingSetup(setup); err != nil {
+				return fmt.Errorf("row %d: %w", i+1, err)
+			}
+			if setup.Checklist != nil {
+				setup.Checklist.SetupID = setup.ID
+				if err := tx.InsertSetupChecklist(setup.Checklist); err != nil {
+					return fmt.Errorf("row %d: %w", i+1, err)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to import trading setups: %w", err)
+	}
+	return len(records), nil
+}
+
+func (es *ExportService) importPatterns(records []models.PatternRecord) (int, error) {
+	err := es.db.WithTx(func(tx *database.DB) error {
+		for i, record := range records {
+			var err error
+			switch record.PatternFamily {
+			case patternFamilyHeadShoulders:
+				pattern := &models.HeadShouldersPattern{}
+				if err = json.Unmarshal([]byte(record.Data), pattern); err == nil {
+					pattern.ID = 0
+					err = tx.InsertHeadShouldersPattern(pattern)
+				}
+			case patternFamilyFallingWedge:
+				pattern := &models.FallingWedgePattern{}
+				if err = json.Unmarshal([]byte(record.Data), pattern); err == nil {
+					pattern.ID = 0
+					err = tx.InsertFallingWedgePattern(pattern)
+				}
+			case patternFamilyTriangle:
+				pattern := &models.TrianglePattern{}
+				if err = json.Unmarshal([]byte(record.Data), pattern); err == nil {
+					pattern.ID = 0
+					err = tx.InsertTrianglePattern(pattern)
+				}
+			case patternFamilyFlag:
+				pattern := &models.FlagPattern{}
+				if err = json.Unmarshal([]byte(record.Data), pattern); err == nil {
+					pattern.ID = 0
+					err = tx.InsertFlagPattern(pattern)
+				}
+			default:
+				err = fmt.Errorf("unknown pattern family %q", record.PatternFamily)
+			}
+			if err != nil {
+				return fmt.Errorf("row %d: %w", i+1, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to import patterns: %w", err)
+	}
+	return len(records), nil
+}
+
+// writeRecords encodes records as CSV with a header row, or as a Parquet file
+func writeRecords[T any](w io.Writer, format string, records []T) error {
+	if format == models.FormatParquet {
+		if err := parquet.Write(w, records); err != nil {
+			return fmt.Errorf("failed to write parquet: %w", err)
+		}
+		return nil
+	}
+
+	fields := csvFields(reflect.TypeOf((*T)(nil)).Elem())
+	writer := csv.NewWriter(w)
+
+	header := make([]string, len(fields))
+	for i, field := range fields {
+		header[i] = field.name
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+
+	row := make([]string, len(fields))
+	for _, record := range records {
+		value := reflect.ValueOf(record)
+		for i, field := range fields {
+			row[i] = formatCSVValue(value.Field(field.index))
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write csv row: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
+
+// readRecords decodes CSV (columns matched by header name, in any order) or Parquet into records
+func readRecords[T any](r io.Reader, format string) ([]T, error) {
+	if format == models.FormatParquet {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read parquet: %w", err)
+		}
+		records, err := parquet.Read[T](bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read parquet: %w", err)
+		}
+		return records, nil
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	byName := make(map[string]csvField)
+	for _, field := range csvFields(reflect.TypeOf((*T)(nil)).Elem()) {
+		byName[field.name] = field
+	}
+	columns := make([]*csvField, len(header))
+	for i, name := range header {
+		if field, ok := byName[strings.ToLower(strings.TrimSpace(name))]; ok {
+			columns[i] = &field
+		}
+	}
+
+	var records []T
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv line %d: %w", line, err)
+		}
+
+		var record T
+		value := reflect.ValueOf(&record).Elem()
+		for i, cell := range row {
+			if i >= len(columns) || columns[i] == nil {
+				continue
+			}
+			if err := parseCSVValue(value.Field(columns[i].index), strings.TrimSpace(cell)); err != nil {
+				return nil, fmt.Errorf("csv line %d, column %s: %w", line, columns[i].name, err)
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// csvField is a record field and its CSV column name
+type csvField struct {
+	name  string
+	index int
+}
+
+// csvFields lists the record fields with a csv tag, in declaration order
+func csvFields(t reflect.Type) []csvField {
+	var fields []csvField
+	for i := 0; i < t.NumField(); i++ {
+		if name := t.Field(i).Tag.Get("csv"); name != "" {
+			fields = append(fields, csvField{name: name, index: i})
+		}
+	}
+	return fields
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// formatCSVValue formats a record field for CSV; times are written as RFC 3339 in UTC
+func formatCSVValue(v reflect.Value) string {
+	if v.Type() == timeType {
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Int, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// parseCSVValue parses a CSV cell into a record field; empty cells leave the zero value
+func parseCSVValue(v reflect.Value, cell string) error {
+	if cell == "" {
+		return nil
+	}
+
+	if v.Type() == timeType {
+		t, err := ParseExportTime(cell)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(cell)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", cell)
+		}
+		v.SetFloat(f)
+	case reflect.Int, reflect.Int64:
+		// Volumes in external datasets are sometimes written as floats
+		f, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", cell)
+		}
+		v.SetInt(int64(f))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", cell)
+		}
+		v.SetBool(b)
+	}
+	return nil
+}
+
+// ParseExportTime accepts RFC 3339, "2006-01-02 15:04:05", "2006-01-02" and Unix epoch seconds or milliseconds
+func ParseExportTime(value string) (time.Time, error) {
+	if epoch, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if epoch > 1e12 {
+			return time.UnixMilli(epoch).UTC(), nil
+		}
+		return time.Unix(epoch, 0).UTC(), nil
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", value)
+}
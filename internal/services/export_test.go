@@ -0,0 +1,116 @@
+package services
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestExportImportRoundTrip tests that exported bars, setups and patterns load into another database unchanged
+func TestExportImportRoundTrip(t *testing.T) {
+	source := newTestDB(t)
+
+	start := time.Date(2024, 3, 4, 14, 30, 0, 0, time.UTC)
+	var bars []*models.PriceData
+	for i := 0; i < 10; i++ {
+		ts := start.Add(time.Duration(i) * time.Minute)
+		bars = append(bars, &models.PriceData{Symbol: "TEST", Timestamp: ts, Open: 100, High: 101.25, Low: 99.5, Close: 100.75, Volume: int64(1000 + i), CreatedAt: ts})
+	}
+	if err := source.InsertPriceDataBatch(bars); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+
+	setup := &models.TradingSetup{Symbol: "TEST", SetupType: "support_bounce", Direction: "bullish", Confidence: "high", Status: "active", QualityScore: 82, DetectedAt: start, ExpiresAt: start.Add(24 * time.Hour), EntryPrice: 100.5}
+	if err := source.InsertTradingSetup(setup); err != nil {
+		t.Fatalf("InsertTradingSetup failed: %v", err)
+	}
+	checklist := &models.SetupChecklist{SetupID: setup.ID, TotalScore: 82}
+	if err := source.InsertSetupChecklist(checklist); err != nil {
+		t.Fatalf("InsertSetupChecklist failed: %v", err)
+	}
+
+	triangle := &models.TrianglePattern{Symbol: "TEST", PatternType: models.PatternAscendingTriangle, BreakoutLevel: 105, CurrentPhase: "formation", DetectedAt: start, LastUpdated: start}
+	if err := source.InsertTrianglePattern(triangle); err != nil {
+		t.Fatalf("InsertTrianglePattern failed: %v", err)
+	}
+
+	exporter := NewExportService(source)
+	for _, format := range []string{models.FormatCSV, models.FormatParquet} {
+		t.Run(format, func(t *testing.T) {
+			target := NewExportService(newTestDB(t))
+
+			for _, dataset := range []string{models.DatasetPrice, models.DatasetSetups, models.DatasetPatterns} {
+				var buf bytes.Buffer
+				count, err := exporter.Export(&buf, &models.ExportRequest{Dataset: dataset, Format: format, Symbol: "test", From: start, To: start.Add(time.Hour)})
+				if err != nil {
+					t.Fatalf("Export %s failed: %v", dataset, err)
+				}
+				if format == models.FormatCSV && dataset == models.DatasetPrice && !strings.HasPrefix(buf.String(), "symbol,timestamp,open,high,low,close,volume\n") {
+					t.Errorf("unexpected CSV header: %q", strings.SplitN(buf.String(), "\n", 2)[0])
+				}
+
+				result, err := target.Import(&buf, dataset, format)
+				if err != nil {
+					t.Fatalf("Import %s failed: %v", dataset, err)
+				}
+				if result.Imported != count || count == 0 {
+					t.Errorf("%s: exported %d rows, imported %d", dataset, count, result.Imported)
+				}
+			}
+
+			imported, err := target.db.GetPriceDataRange("TEST", start, start.Add(time.Hour))
+			if err != nil {
+				t.Fatalf("GetPriceDataRange failed: %v", err)
+			}
+			if len(imported) != len(bars) || !imported[3].Timestamp.Equal(bars[3].Timestamp) || imported[3].High != 101.25 || imported[3].Volume != 1003 {
+				t.Errorf("unexpected imported bars: %d, %+v", len(imported), imported[3])
+			}
+
+			setups, err := target.db.GetTradingSetups(&models.SetupFilter{Symbol: "TEST"})
+			if err != nil || len(setups) != 1 {
+				t.Fatalf("GetTradingSetups = %d, %v", len(setups), err)
+			}
+			gotChecklist, err := target.db.GetSetupChecklist(setups[0].ID)
+			if err != nil || gotChecklist == nil || gotChecklist.TotalScore != 82 {
+				t.Errorf("checklist not imported: %+v, %v", gotChecklist, err)
+			}
+
+			triangles, err := target.db.GetTrianglePatterns(&models.TriangleFilter{Symbol: "TEST"})
+			if err != nil || len(triangles) != 1 || triangles[0].BreakoutLevel != 105 {
+				t.Errorf("unexpected imported triangles: %+v, %v", triangles, err)
+			}
+		})
+	}
+}
+
+// TestImportExternalCSV tests that CSV columns are matched by name and epoch timestamps are accepted
+func TestImportExternalCSV(t *testing.T) {
+	db := newTestDB(t)
+
+	csvData := "timestamp,symbol,close,volume,vwap\n" +
+		"1709562600000,aapl,180.5,12000.0,180.1\n" +
+		"2024-03-04T14:31:00Z,aapl,181,9000,180.4\n"
+
+	result, err := NewExportService(db).Import(strings.NewReader(csvData), models.DatasetPrice, "")
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if result.Rows != 2 || result.Imported != 2 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	latest, err := db.GetLatestPriceData("AAPL")
+	if err != nil {
+		t.Fatalf("GetLatestPriceData failed: %v", err)
+	}
+	if !latest.Timestamp.Equal(time.Date(2024, 3, 4, 14, 31, 0, 0, time.UTC)) || latest.Close != 181 || latest.Volume != 9000 {
+		t.Errorf("unexpected latest bar: %+v", latest)
+	}
+
+	if _, err := NewExportService(db).Import(strings.NewReader("symbol,close\nAAPL,1\n"), models.DatasetPrice, models.FormatCSV); err == nil {
+		t.Error("expected an error for rows without a timestamp")
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"math"
@@ -14,55 +15,69 @@ import (
 // FallingWedgeDetectionService handles falling wedge pattern detection and monitoring
 type FallingWedgeDetectionService struct {
 	db           *database.Database
+	prices       PriceReader
 	taService    *TechnicalAnalysisService
 	emailService *EmailService
+	webhooks     *WebhookService
 	config       *models.FallingWedgeConfig
 }
 
 // NewFallingWedgeDetectionService creates a new falling wedge detection service
 func NewFallingWedgeDetectionService(db *database.Database, taService *TechnicalAnalysisService, emailService *EmailService) *FallingWedgeDetectionService {
-	// FIXED: Adjusted configuration for falling wedge patterns
-	config := &models.FallingWedgeConfig{
-		MinPatternDuration:  48 * time.Hour,  // 2 days minimum
-		MaxPatternDuration:  480 * time.Hour, // 20 days maximum
-		MinConvergence:      0.005,           // FIXED: 0.5% minimum convergence (was 2%)
-		MaxConvergence:      0.15,            // 15% maximum convergence
-		MinTouchPoints:      4,               // Minimum 4 touch points (2 per line)
-		VolumeDecreaseRatio: 0.8,             // Volume should decrease to 80% or less
-		BreakoutVolumeRatio: 1.5,             // Breakout volume should be 1.5x average
-		MinWedgeHeight:      0.03,            // 3% minimum height
-		MaxWedgeSlope:       -0.1,            // Maximum downward slope
-	}
-
 	return &FallingWedgeDetectionService{
 		db:           db,
+		prices:       db,
 		taService:    taService,
 		emailService: emailService,
-		config:       config,
+		config:       models.DefaultFallingWedgeConfig(),
 	}
 }
 
-// DetectFallingWedge detects falling wedge patterns for a symbol
-func (fwds *FallingWedgeDetectionService) DetectFallingWedge(symbol string) (*models.FallingWedgePattern, error) {
-	log.Printf("Detecting falling wedge pattern for %s", symbol)
+// SetPriceReader sets where detection reads bars from, e.g. the price cache or only regular session bars
+func (fwds *FallingWedgeDetectionService) SetPriceReader(prices PriceReader) {
+	fwds.prices = prices
+}
 
-	// Get price data for analysis (last 3 months)
-	endTime := time.Now()
-	startTime := endTime.Add(-90 * 24 * time.Hour) // 3 months
+// withContext returns a copy of the service whose queries run under ctx
+func (fwds *FallingWedgeDetectionService) withContext(ctx context.Context) *FallingWedgeDetectionService {
+	bound := *fwds
+	bound.db = fwds.db.WithContext(ctx)
+	bound.prices = bindPriceReader(ctx, fwds.prices)
+	return &bound
+}
 
-	priceData, err := fwds.db.GetPriceDataRange(symbol, startTime, endTime)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get price data: %w", err)
-	}
+// Config returns a copy of the detection settings
+func (fwds *FallingWedgeDetectionService) Config() *models.FallingWedgeConfig {
+	config := *fwds.config
+	return &config
+}
 
-	if len(priceData) < 30 {
-		return nil, fmt.Errorf("insufficient price data for pattern detection")
+// SetConfig replaces the detection settings, e.g. with overrides from the config file
+func (fwds *FallingWedgeDetectionService) SetConfig(config *models.FallingWedgeConfig) {
+	fwds.config = config
+}
+
+// SetWebhookService sets the service that posts pattern lifecycle webhooks
+func (fwds *FallingWedgeDetectionService) SetWebhookService(webhooks *WebhookService) {
+	fwds.webhooks = webhooks
+}
+
+// DetectFallingWedge detects falling wedge patterns for a symbol; nil params use the configured bar interval, lookback and sensitivity
+func (fwds *FallingWedgeDetectionService) DetectFallingWedge(ctx context.Context, symbol string, params *models.PatternScanParams) (*models.FallingWedgePattern, error) {
+	fwds = fwds.withContext(ctx)
+	scan, priceData, err := fwds.loadPriceData(symbol, params, "falling wedge")
+	if err != nil {
+		return nil, err
 	}
 
 	// Find potential wedge patterns
-	pattern := fwds.analyzeFallingWedgePattern(symbol, priceData)
+	pattern := fwds.analyzeWedgePattern(symbol, priceData, scan, models.PatternFallingWedge)
 	if pattern == nil {
-		return nil, fmt.Errorf("no valid falling wedge pattern found")
+		return nil, fmt.Errorf("%w: no falling wedge formation", ErrNoPattern)
+	}
+
+	if existing, err := fwds.storedPattern(pattern); err != nil || existing != nil {
+		return existing, err
 	}
 
 	// Skip trading setup creation for now to avoid database schema issues
@@ -77,21 +92,159 @@ func (fwds *FallingWedgeDetectionService) DetectFallingWedge(symbol string) (*mo
 		log.Printf("Pattern detected successfully but not stored: %+v", pattern)
 	} else {
 		log.Printf("Successfully detected and stored falling wedge pattern for %s (ID: %d)", symbol, pattern.ID)
+		emitPatternDetected(fwds.webhooks, symbol, pattern)
 	}
 
 	return pattern, nil
 }
 
-// analyzeFallingWedgePattern analyzes price data for falling wedge pattern
-func (fwds *FallingWedgeDetectionService) analyzeFallingWedgePattern(symbol string, priceData []*models.PriceData) *models.FallingWedgePattern {
+// DetectRisingWedge detects bearish rising wedge patterns for a symbol and stores them with a short trading setup
+func (fwds *FallingWedgeDetectionService) DetectRisingWedge(ctx context.Context, symbol string, params *models.PatternScanParams) (*models.FallingWedgePattern, error) {
+	fwds = fwds.withContext(ctx)
+	scan, priceData, err := fwds.loadPriceData(symbol, params, "rising wedge")
+	if err != nil {
+		return nil, err
+	}
+
+	pattern := fwds.analyzeWedgePattern(symbol, priceData, scan, models.PatternRisingWedge)
+	if pattern == nil {
+		return nil, fmt.Errorf("%w: no rising wedge formation", ErrNoPattern)
+	}
+
+	if existing, err := fwds.storedPattern(pattern); err != nil || existing != nil {
+		return existing, err
+	}
+
+	// Avoid storing the same active pattern and setup on every scan
+	existing, err := fwds.db.GetFallingWedgePatterns(&models.FallingWedgeFilter{
+		Symbol:      symbol,
+		PatternType: models.PatternRisingWedge,
+		IsComplete:  boolPtr(false),
+		Limit:       1,
+	})
+	if err == nil && len(existing) > 0 {
+		log.Printf("Active rising wedge pattern already tracked for %s (ID: %d)", symbol, existing[0].ID)
+		return existing[0], nil
+	}
+
+	setup := fwds.createShortSetup(pattern, priceData[len(priceData)-1].Close)
+
+	// Store the setup and the pattern together so a failure can't leave an orphaned setup
+	err = fwds.db.WithTx(func(tx *database.DB) error {
+		if err := tx.InsertTradingSetup(setup); err != nil {
+			return fmt.Errorf("failed to store trading setup: %w", err)
+		}
+
+		pattern.SetupID = setup.ID
+
+		if err := tx.InsertFallingWedgePattern(pattern); err != nil {
+			return fmt.Errorf("failed to store pattern: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Successfully detected and stored rising wedge pattern for %s (ID: %d)", symbol, pattern.ID)
+	emitPatternDetected(fwds.webhooks, symbol, pattern)
+	notifyPatternDetected(fwds.emailService, symbol, "Rising Wedge", pattern.BreakoutLevel, pattern.CalculateTargetPrice(), pattern.ThesisComponents.CompletionPercent, pattern)
+
+	return pattern, nil
+}
+
+// storedPattern returns the stored wedge with the detected pattern's fingerprint, refreshed with its
+// measurements while it is active, or nil when the formation is new
+func (fwds *FallingWedgeDetectionService) storedPattern(pattern *models.FallingWedgePattern) (*models.FallingWedgePattern, error) {
+	existing, err := fwds.db.GetFallingWedgePatternByFingerprint(pattern.Symbol, pattern.Fingerprint())
+	if err != nil || existing == nil {
+		return nil, err
+	}
+
+	if !existing.IsComplete {
+		existing.Refresh(pattern)
+		if err := fwds.db.UpdateFallingWedgePattern(existing); err != nil {
+			return nil, err
+		}
+	}
+	log.Printf("%s pattern for %s already stored (ID: %d)", existing.PatternType, existing.Symbol, existing.ID)
+	return existing, nil
+}
+
+// loadPriceData resolves the scan settings and loads the bars a wedge scan analyzes
+func (fwds *FallingWedgeDetectionService) loadPriceData(symbol string, params *models.PatternScanParams, name string) (patternScan, []*models.PriceData, error) {
+	scan := fwds.scanSettings(params)
+	log.Printf("Detecting %s pattern for %s on %s from %s", name, symbol, scan.timeframe, scan.start.Format("2006-01-02"))
+
+	priceData, err := fwds.prices.GetPriceDataRangeTimeframe(symbol, scan.start, scan.end, scan.timeframe)
+	if err != nil {
+		return scan, nil, fmt.Errorf("failed to get price data: %w", err)
+	}
+
+	if len(priceData) < 30 {
+		return scan, nil, fmt.Errorf("%w: insufficient price data for pattern detection", ErrNoPattern)
+	}
+
+	return scan, priceData, nil
+}
+
+// createShortSetup creates a bearish trading setup from a rising wedge: entry just under the lower
+// trend line, stop above the last upper trend line touch and targets projected below the breakdown
+func (fwds *FallingWedgeDetectionService) createShortSetup(pattern *models.FallingWedgePattern, currentPrice float64) *models.TradingSetup {
+	targetPrice := pattern.CalculateTargetPrice()
+	projection := pattern.BreakoutLevel - targetPrice
+
+	quality := math.Min(40.0, pattern.GetConvergenceScore()*0.4) // Convergence (0-40 points)
+	// Fading volume into the apex (0-25 points)
+	switch pattern.VolumeProfile {
+	case "decreasing":
+		quality += 25.0
+	case "stable":
+		quality += 10.0
+	}
+	quality += pattern.ThesisComponents.CompletionPercent / 100.0 * 35.0 // Thesis completion (0-35 points)
+
+	setup := &models.TradingSetup{
+		Symbol:       pattern.Symbol,
+		SetupType:    pattern.PatternType,
+		Direction:    pattern.Direction(),
+		QualityScore: math.Min(100.0, quality),
+		Status:       "active",
+		DetectedAt:   pattern.DetectedAt,
+		ExpiresAt:    pattern.DetectedAt.Add(24 * time.Hour), // 24 hours to enter
+		CurrentPrice: currentPrice,
+		EntryPrice:   pattern.BreakoutLevel * 0.998,          // Slight discount below the lower trend line
+		StopLoss:     pattern.UpperTrendLine2.Price * 1.01,   // Above the last upper trend line touch
+		Target1:      pattern.BreakoutLevel - projection*0.5, // 50% of projection
+		Target2:      pattern.BreakoutLevel - projection*0.75,
+		Target3:      targetPrice,
+		CreatedAt:    clockNow(),
+		UpdatedAt:    clockNow(),
+	}
+
+	setup.Confidence = setup.GetConfidenceLevel()
+	setup.RiskAmount = setup.GetRiskAmount()
+	setup.RewardPotential = setup.GetRewardPotential()
+	setup.RiskRewardRatio = setup.CalculateRiskRewardRatio()
+
+	return setup
+}
+
+// scanSettings resolves per-request scan parameters against the detector's configuration
+func (fwds *FallingWedgeDetectionService) scanSettings(params *models.PatternScanParams) patternScan {
+	return resolvePatternScan(params, fwds.config.BarInterval, fwds.config.LookbackDays, fwds.config.SwingWindow, fwds.config.Sensitivity)
+}
+
+// analyzeWedgePattern analyzes price data for a falling or rising wedge pattern
+func (fwds *FallingWedgeDetectionService) analyzeWedgePattern(symbol string, priceData []*models.PriceData, scan patternScan, patternType string) *models.FallingWedgePattern {
 	// Find significant highs and lows
-	highs, lows := fwds.findSignificantLevels(priceData)
+	highs, lows := fwds.findSignificantLevels(priceData, scan.swingWindow)
 
 	if len(highs) < 2 || len(lows) < 2 {
 		return nil
 	}
 
-	// Look for converging downward trending lines
+	// Look for converging trend lines sloping the same way
 	for i := 0; i < len(highs)-1; i++ {
 		for j := i + 1; j < len(highs); j++ {
 			upperLine := []models.PatternPoint{highs[i], highs[j]}
@@ -100,8 +253,8 @@ func (fwds *FallingWedgeDetectionService) analyzeFallingWedgePattern(symbol stri
 				for l := k + 1; l < len(lows); l++ {
 					lowerLine := []models.PatternPoint{lows[k], lows[l]}
 
-					if fwds.isValidFallingWedge(upperLine, lowerLine) {
-						pattern := fwds.buildFallingWedgePattern(symbol, upperLine, lowerLine, priceData)
+					if fwds.isValidWedge(upperLine, lowerLine, scan.sensitivity, patternType) {
+						pattern := fwds.buildWedgePattern(symbol, upperLine, lowerLine, priceData, patternType)
 						if pattern != nil {
 							return pattern
 						}
@@ -115,13 +268,11 @@ func (fwds *FallingWedgeDetectionService) analyzeFallingWedgePattern(symbol stri
 }
 
 // findSignificantLevels identifies significant highs and lows
-func (fwds *FallingWedgeDetectionService) findSignificantLevels(priceData []*models.PriceData) (highs, lows []models.PatternPoint) {
-	if len(priceData) < 10 {
+func (fwds *FallingWedgeDetectionService) findSignificantLevels(priceData []*models.PriceData, windowSize int) (highs, lows []models.PatternPoint) {
+	if len(priceData) < 2*windowSize {
 		return
 	}
 
-	windowSize := 5 // Look for highs/lows over 5-period window
-
 	for i := windowSize; i < len(priceData)-windowSize; i++ {
 		current := priceData[i]
 		isHigh := true
@@ -191,8 +342,8 @@ func (fwds *FallingWedgeDetectionService) calculateVolumeRatio(priceData []*mode
 	return float64(priceData[index].Volume) / avgVolume
 }
 
-// isValidFallingWedge checks if the given lines form a valid falling wedge
-func (fwds *FallingWedgeDetectionService) isValidFallingWedge(upperLine, lowerLine []models.PatternPoint) bool {
+// isValidWedge checks if the given lines form a valid falling or rising wedge
+func (fwds *FallingWedgeDetectionService) isValidWedge(upperLine, lowerLine []models.PatternPoint, sensitivity float64, patternType string) bool {
 	if len(upperLine) != 2 || len(lowerLine) != 2 {
 		return false
 	}
@@ -201,20 +352,27 @@ func (fwds *FallingWedgeDetectionService) isValidFallingWedge(upperLine, lowerLi
 	upperSlope := (upperLine[1].Price - upperLine[0].Price) / float64(upperLine[1].Timestamp.Sub(upperLine[0].Timestamp).Hours())
 	lowerSlope := (lowerLine[1].Price - lowerLine[0].Price) / float64(lowerLine[1].Timestamp.Sub(lowerLine[0].Timestamp).Hours())
 
-	// FIXED: Falling wedge pattern validation
-	// Upper line must trend downward (negative slope)
-	if upperSlope >= 0 {
-		return false
-	}
+	if patternType == models.PatternRisingWedge {
+		// Both lines trend upward, the lower line rising faster so they converge
+		if upperSlope <= 0 || lowerSlope <= upperSlope {
+			return false
+		}
+	} else {
+		// FIXED: Falling wedge pattern validation
+		// Upper line must trend downward (negative slope)
+		if upperSlope >= 0 {
+			return false
+		}
 
-	// Lower line must trend upward (positive slope) for convergence
-	if lowerSlope <= 0 {
-		return false
-	}
+		// Lower line must trend upward (positive slope) for convergence
+		if lowerSlope <= 0 {
+			return false
+		}
 
-	// Lines must converge: upper line falling faster than lower line rising
-	if math.Abs(upperSlope) <= math.Abs(lowerSlope) {
-		return false
+		// Lines must converge: upper line falling faster than lower line rising
+		if math.Abs(upperSlope) <= math.Abs(lowerSlope) {
+			return false
+		}
 	}
 
 	// Check pattern duration
@@ -247,15 +405,15 @@ func (fwds *FallingWedgeDetectionService) isValidFallingWedge(upperLine, lowerLi
 	minLow := math.Min(lowerLine[0].Price, lowerLine[1].Price)
 	height := (maxHigh - minLow) / maxHigh
 
-	if height < fwds.config.MinWedgeHeight {
+	if height < fwds.config.MinWedgeHeight/sensitivity {
 		return false
 	}
 
 	return true
 }
 
-// buildFallingWedgePattern constructs the complete pattern structure
-func (fwds *FallingWedgeDetectionService) buildFallingWedgePattern(symbol string, upperLine, lowerLine []models.PatternPoint, priceData []*models.PriceData) *models.FallingWedgePattern {
+// buildWedgePattern constructs the complete pattern structure
+func (fwds *FallingWedgeDetectionService) buildWedgePattern(symbol string, upperLine, lowerLine []models.PatternPoint, priceData []*models.PriceData, patternType string) *models.FallingWedgePattern {
 	// Calculate pattern metrics
 	upperSlope := (upperLine[1].Price - upperLine[0].Price) / float64(upperLine[1].Timestamp.Sub(upperLine[0].Timestamp).Hours())
 	lowerSlope := (lowerLine[1].Price - lowerLine[0].Price) / float64(lowerLine[1].Timestamp.Sub(lowerLine[0].Timestamp).Hours())
@@ -270,15 +428,18 @@ func (fwds *FallingWedgeDetectionService) buildFallingWedgePattern(symbol string
 		patternEnd = lowerLine[1].Timestamp
 	}
 
-	// Calculate breakout level (upper trend line at current time)
-	now := time.Now()
+	// Calculate breakout level (upper trend line at current time, lower trend line for a rising wedge)
+	now := clockNow()
 	hoursFromStart := float64(now.Sub(upperLine[0].Timestamp).Hours())
 	breakoutLevel := upperLine[0].Price + (upperSlope * hoursFromStart)
+	if patternType == models.PatternRisingWedge {
+		breakoutLevel = lowerLine[0].Price + (lowerSlope * float64(now.Sub(lowerLine[0].Timestamp).Hours()))
+	}
 
 	// Create pattern
 	pattern := &models.FallingWedgePattern{
 		Symbol:          symbol,
-		PatternType:     "falling_wedge",
+		PatternType:     patternType,
 		UpperTrendLine1: upperLine[0],
 		UpperTrendLine2: upperLine[1],
 		LowerTrendLine1: lowerLine[0],
@@ -290,14 +451,14 @@ func (fwds *FallingWedgeDetectionService) buildFallingWedgePattern(symbol string
 		PatternHeight:   math.Max(upperLine[0].Price, upperLine[1].Price) - math.Min(lowerLine[0].Price, lowerLine[1].Price),
 		Convergence:     fwds.calculateConvergence(upperLine, lowerLine),
 		VolumeProfile:   fwds.calculateVolumeProfile(priceData, patternStart, patternEnd),
-		DetectedAt:      time.Now(),
-		LastUpdated:     time.Now(),
+		DetectedAt:      clockNow(),
+		LastUpdated:     clockNow(),
 		CurrentPhase:    models.PhaseFormation,
 		IsComplete:      false,
 	}
 
 	// Initialize thesis components
-	pattern.ThesisComponents.InitializeWedgeThesis()
+	pattern.ThesisComponents.InitializeWedgeThesis(patternType)
 
 	// Evaluate initial thesis components
 	fwds.evaluateInitialThesis(pattern)
@@ -352,5 +513,5 @@ func (fwds *FallingWedgeDetectionService) calculateVolumeProfile(priceData []*mo
 func (fwds *FallingWedgeDetectionService) evaluateInitialThesis(pattern *models.FallingWedgePattern) {
 	// This would be implemented based on the thesis structure
 	// For now, mark pattern formation as complete
-	log.Printf("Initial thesis evaluation completed for falling wedge pattern %s", pattern.Symbol)
+	log.Printf("Initial thesis evaluation completed for %s pattern %s", pattern.PatternType, pattern.Symbol)
 }
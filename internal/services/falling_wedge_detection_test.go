@@ -1,6 +1,8 @@
 package services
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
@@ -125,7 +127,7 @@ func TestFallingWedgeDetection_LTBR(t *testing.T) {
 	}
 
 	t.Run("AnalyzeFallingWedgePattern", func(t *testing.T) {
-		pattern := service.analyzeFallingWedgePattern("LTBR", testData)
+		pattern := service.analyzeWedgePattern("LTBR", testData, service.scanSettings(nil), models.PatternFallingWedge)
 
 		if pattern == nil {
 			t.Error("Expected falling wedge pattern to be detected for LTBR, but got nil")
@@ -159,6 +161,78 @@ func TestFallingWedgeDetection_LTBR(t *testing.T) {
 	})
 }
 
+// TestFallingWedgeScanSettings tests that per-request scan parameters override the configured lookback, bar interval and sensitivity
+func TestFallingWedgeScanSettings(t *testing.T) {
+	service := &FallingWedgeDetectionService{config: models.DefaultFallingWedgeConfig()}
+
+	defaults := service.scanSettings(nil)
+	if defaults.timeframe != models.Timeframe1m || defaults.swingWindow != 5 || defaults.sensitivity != 1 {
+		t.Errorf("unexpected default scan: %+v", defaults)
+	}
+	if days := defaults.end.Sub(defaults.start).Hours() / 24; days != 90 {
+		t.Errorf("expected a 90 day lookback, got %.1f", days)
+	}
+
+	scan := service.scanSettings(&models.PatternScanParams{Timeframe: models.Timeframe1h, LookbackDays: 30, Sensitivity: 2})
+	if scan.timeframe != models.Timeframe1h || scan.swingWindow != 3 || scan.sensitivity != 2 {
+		t.Errorf("unexpected overridden scan: %+v", scan)
+	}
+	if days := scan.end.Sub(scan.start).Hours() / 24; days != 30 {
+		t.Errorf("expected a 30 day lookback, got %.1f", days)
+	}
+
+	// Low sensitivity widens the swing window
+	if coarse := service.scanSettings(&models.PatternScanParams{Sensitivity: 0.5}); coarse.swingWindow != 10 {
+		t.Errorf("expected a 10 bar swing window, got %d", coarse.swingWindow)
+	}
+
+	if _, err := models.ParsePatternScanParams("2h", "", ""); err == nil {
+		t.Error("expected an error for an unknown timeframe")
+	}
+	if _, err := models.ParsePatternScanParams("", "0", ""); err == nil {
+		t.Error("expected an error for a zero lookback")
+	}
+	if _, err := models.ParsePatternScanParams("", "", "10"); err == nil {
+		t.Error("expected an error for an out of range sensitivity")
+	}
+}
+
+// TestRisingWedgeShortSetup tests that rising trend lines converging from below form a rising wedge with a short setup
+func TestRisingWedgeShortSetup(t *testing.T) {
+	service := &FallingWedgeDetectionService{config: models.DefaultFallingWedgeConfig()}
+
+	start := time.Now().Add(-10 * 24 * time.Hour).Truncate(time.Hour)
+	end := start.Add(9 * 24 * time.Hour)
+	upperLine := []models.PatternPoint{{Timestamp: start, Price: 110}, {Timestamp: end, Price: 116}}
+	lowerLine := []models.PatternPoint{{Timestamp: start, Price: 100}, {Timestamp: end, Price: 107}}
+
+	if service.isValidWedge(upperLine, lowerLine, 1, models.PatternFallingWedge) {
+		t.Error("rising trend lines should not form a falling wedge")
+	}
+	if !service.isValidWedge(upperLine, lowerLine, 1, models.PatternRisingWedge) {
+		t.Fatal("expected a valid rising wedge")
+	}
+
+	pattern := service.buildWedgePattern("TEST", upperLine, lowerLine, nil, models.PatternRisingWedge)
+	if !pattern.IsRising() || pattern.Direction() != "bearish" || pattern.ThesisComponents.PriorTrendEstablished.Name != "Uptrend Established" {
+		t.Errorf("unexpected rising wedge: type %s, direction %s, first component %q", pattern.PatternType, pattern.Direction(), pattern.ThesisComponents.PriorTrendEstablished.Name)
+	}
+	if thesis, err := json.Marshal(pattern.ThesisComponents); err != nil || !strings.Contains(string(thesis), `"prior_trend_established":{"name":"Uptrend Established"`) {
+		t.Errorf("expected the uptrend under the direction-neutral prior_trend_established key, got %s (%v)", thesis, err)
+	}
+	if pattern.CalculateTargetPrice() >= pattern.BreakoutLevel {
+		t.Errorf("expected a target below the breakdown level %.2f, got %.2f", pattern.BreakoutLevel, pattern.CalculateTargetPrice())
+	}
+
+	setup := service.createShortSetup(pattern, 108)
+	if setup.Direction != "bearish" || setup.StopLoss <= setup.EntryPrice || setup.Target1 >= setup.EntryPrice || setup.Target3 >= setup.Target2 {
+		t.Errorf("unexpected short setup: entry %.2f, stop %.2f, targets %.2f/%.2f/%.2f", setup.EntryPrice, setup.StopLoss, setup.Target1, setup.Target2, setup.Target3)
+	}
+	if setup.RiskRewardRatio <= 0 {
+		t.Errorf("expected a positive risk/reward ratio, got %.2f", setup.RiskRewardRatio)
+	}
+}
+
 // Helper function to parse time strings
 func parseTime(timeStr string) time.Time {
 	t, err := time.Parse("2006-01-02T15:04:05Z", timeStr)
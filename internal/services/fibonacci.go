@@ -0,0 +1,123 @@
+package services
+
+import (
+	"fmt"
+	"math"
+
+	"market-watch-go/internal/models"
+)
+
+// defaultFibMinSwingPercent is the smallest swing Fibonacci levels are computed for when detection settings
+// stored before the setting existed leave it unset
+const defaultFibMinSwingPercent = 3.0
+
+// updateFibonacciLevels computes the Fibonacci retracements and extensions of the latest significant swing
+// among the pivots and stores them in place of the symbol's previous ones. Without a significant swing the
+// previous levels are removed.
+func (srs *SupportResistanceService) updateFibonacciLevels(symbol string, timeframe models.Timeframe, pivots []*models.PivotPoint, priceData []*models.PriceData) (*models.FibonacciSwing, []*models.SupportResistanceLevel, error) {
+	minSwing := srs.config.FibMinSwingPercent
+	if minSwing <= 0 {
+		minSwing = defaultFibMinSwingPercent
+	}
+
+	levels := make([]*models.SupportResistanceLevel, 0)
+	swing := latestFibonacciSwing(pivots, minSwing)
+	if swing != nil && len(priceData) > 0 {
+		levels = fibonacciLevels(symbol, swing, priceData[len(priceData)-1].Close)
+		for _, level := range levels {
+			level.TimeframeOrigin = string(timeframe)
+		}
+	}
+
+	if err := srs.db.ReplaceFibonacciLevels(symbol, levels); err != nil {
+		return nil, nil, fmt.Errorf("failed to store Fibonacci levels: %w", err)
+	}
+	return swing, levels, nil
+}
+
+// latestFibonacciSwing returns the most recent swing that moves at least minPercent: from the most extreme
+// opposite pivot before a pivot to that pivot, stopping at an earlier pivot beyond it. Pivots must be oldest
+// first; nil means no swing is significant.
+func latestFibonacciSwing(pivots []*models.PivotPoint, minPercent float64) *models.FibonacciSwing {
+	for last := len(pivots) - 1; last > 0; last-- {
+		end := pivots[last]
+
+		var start *models.PivotPoint
+		for j := last - 1; j >= 0; j-- {
+			p := pivots[j]
+			if p.PivotType == end.PivotType {
+				if pivotBeyond(p, end) {
+					break
+				}
+				continue
+			}
+			if start == nil || pivotBeyond(p, start) {
+				start = p
+			}
+		}
+
+		if start == nil || start.Price <= 0 || math.Abs(end.Price-start.Price)/start.Price*100 < minPercent {
+			continue
+		}
+
+		direction := "up"
+		if end.PivotType == "low" {
+			direction = "down"
+		}
+		return &models.FibonacciSwing{
+			Direction:  direction,
+			StartTime:  start.Timestamp,
+			StartPrice: start.Price,
+			EndTime:    end.Timestamp,
+			EndPrice:   end.Price,
+		}
+	}
+	return nil
+}
+
+// pivotBeyond reports whether p is more extreme than ref in p's direction: a higher high or a lower low
+func pivotBeyond(p, ref *models.PivotPoint) bool {
+	if p.PivotType == "high" {
+		return p.Price > ref.Price
+	}
+	return p.Price < ref.Price
+}
+
+// fibonacciLevels returns a swing's retracements, back toward its start, and extensions, beyond its end.
+// Levels below the current price are support and the others resistance.
+func fibonacciLevels(symbol string, swing *models.FibonacciSwing, currentPrice float64) []*models.SupportResistanceLevel {
+	move := swing.EndPrice - swing.StartPrice
+	now := clockNow()
+
+	levels := make([]*models.SupportResistanceLevel, 0, len(models.FibRetracementRatios)+len(models.FibExtensionRatios))
+	add := func(price, ratio float64) {
+		if price <= 0 {
+			return
+		}
+		levelType := "resistance"
+		if price < currentPrice {
+			levelType = "support"
+		}
+		levels = append(levels, &models.SupportResistanceLevel{
+			Symbol:        symbol,
+			Level:         math.Round(price*100) / 100,
+			LevelType:     levelType,
+			LevelClass:    models.SRClassFibonacci,
+			FibRatio:      ratio,
+			FirstTouch:    swing.StartTime,
+			LastTouch:     swing.EndTime,
+			IsActive:      true,
+			LastValidated: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+
+	for _, ratio := range models.FibRetracementRatios {
+		add(swing.EndPrice-ratio*move, ratio)
+	}
+	for _, ratio := range models.FibExtensionRatios {
+		add(swing.StartPrice+ratio*move, ratio)
+	}
+	return levels
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestLatestFibonacciSwing tests that the swing runs from the most extreme opposite pivot to the latest pivot
+// and that insignificant swings are skipped
+func TestLatestFibonacciSwing(t *testing.T) {
+	start := time.Date(2025, 3, 3, 15, 0, 0, 0, time.UTC)
+	pivot := func(i int, pivotType string, price float64) *models.PivotPoint {
+		return &models.PivotPoint{Timestamp: start.Add(time.Duration(i) * time.Hour), PivotType: pivotType, Price: price}
+	}
+
+	// A rally from 90 to 110 with a pullback to 100 on the way, then a small dip to 108
+	pivots := []*models.PivotPoint{
+		pivot(0, "high", 115), pivot(1, "low", 90), pivot(2, "high", 104),
+		pivot(3, "low", 100), pivot(4, "high", 110), pivot(5, "low", 108),
+	}
+
+	swing := latestFibonacciSwing(pivots, 3)
+	if swing == nil || swing.Direction != "up" || swing.StartPrice != 90 || swing.EndPrice != 110 {
+		t.Fatalf("expected the 90 to 110 rally, got %+v", swing)
+	}
+	if !swing.StartTime.Equal(pivots[1].Timestamp) || !swing.EndTime.Equal(pivots[4].Timestamp) {
+		t.Errorf("expected the swing to be anchored at its pivots, got %+v", swing)
+	}
+
+	// The decline from 115 is the latest swing once the low undercuts 90
+	pivots = append(pivots, pivot(6, "low", 85))
+	if swing := latestFibonacciSwing(pivots, 3); swing == nil || swing.Direction != "down" || swing.StartPrice != 115 || swing.EndPrice != 85 {
+		t.Errorf("expected the 115 to 85 decline, got %+v", swing)
+	}
+
+	if swing := latestFibonacciSwing(pivots[:2], 50); swing != nil {
+		t.Errorf("expected no significant swing, got %+v", swing)
+	}
+}
+
+// TestFibonacciLevels tests the retracement and extension prices of a swing and that they are stored as their
+// own level class
+func TestFibonacciLevels(t *testing.T) {
+	swing := &models.FibonacciSwing{Direction: "up", StartPrice: 100, EndPrice: 200}
+	levels := fibonacciLevels("TEST", swing, 150)
+
+	byRatio := make(map[float64]*models.SupportResistanceLevel)
+	for _, level := range levels {
+		byRatio[level.FibRatio] = level
+	}
+	if len(levels) != len(models.FibRetracementRatios)+len(models.FibExtensionRatios) {
+		t.Fatalf("expected every ratio, got %d levels", len(levels))
+	}
+	if level := byRatio[0.618]; level.Level != 138.2 || level.LevelType != "support" {
+		t.Errorf("unexpected 61.8%% retracement: %+v", level)
+	}
+	if level := byRatio[0.382]; level.Level != 161.8 || level.LevelType != "resistance" {
+		t.Errorf("unexpected 38.2%% retracement: %+v", level)
+	}
+	if level := byRatio[1.618]; level.Level != 261.8 || level.LevelType != "resistance" {
+		t.Errorf("unexpected 161.8%% extension: %+v", level)
+	}
+
+	// A decline retraces upward and extends below its low
+	down := fibonacciLevels("TEST", &models.FibonacciSwing{Direction: "down", StartPrice: 200, EndPrice: 100}, 150)
+	if down[0].Level != 123.6 || down[len(down)-1].Level <= 0 {
+		t.Errorf("unexpected decline levels: %+v, %+v", down[0], down[len(down)-1])
+	}
+
+	db := newTestDB(t)
+
+	pivotLevel := &models.SupportResistanceLevel{Symbol: "TEST", Level: 140, LevelType: "support", Strength: 60, Touches: 3, IsActive: true}
+	if err := db.InsertSupportResistanceLevel(pivotLevel); err != nil {
+		t.Fatalf("InsertSupportResistanceLevel failed: %v", err)
+	}
+	// Replacing twice keeps only the latest swing's levels
+	for i := 0; i < 2; i++ {
+		if err := db.ReplaceFibonacciLevels("TEST", fibonacciLevels("TEST", swing, 150)); err != nil {
+			t.Fatalf("ReplaceFibonacciLevels failed: %v", err)
+		}
+	}
+
+	if stored, _ := db.GetSupportResistanceLevels(&models.SRDetectionFilter{Symbol: "TEST"}); len(stored) != 1 || stored[0].LevelClass != models.SRClassPivot {
+		t.Errorf("expected only the pivot level by default, got %d", len(stored))
+	}
+	stored, err := db.GetSupportResistanceLevels(&models.SRDetectionFilter{Symbol: "TEST", LevelClass: models.SRClassFibonacci})
+	if err != nil || len(stored) != len(levels) {
+		t.Errorf("expected %d Fibonacci levels, got %d, %v", len(levels), len(stored), err)
+	}
+	if count, _ := db.CountSupportResistanceLevels(&models.SRDetectionFilter{Symbol: "TEST", LevelClass: models.SRClassAll}); count != len(levels)+1 {
+		t.Errorf("expected every level of all classes, got %d", count)
+	}
+}
@@ -0,0 +1,409 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// FlagDetectionService handles bull and bear flag detection and monitoring
+type FlagDetectionService struct {
+	db           *database.Database
+	prices       PriceReader
+	taService    *TechnicalAnalysisService
+	emailService *EmailService
+	webhooks     *WebhookService
+	config       *models.FlagConfig
+}
+
+// NewFlagDetectionService creates a new flag detection service
+func NewFlagDetectionService(db *database.Database, taService *TechnicalAnalysisService, emailService *EmailService) *FlagDetectionService {
+	return &FlagDetectionService{
+		db:           db,
+		prices:       db,
+		taService:    taService,
+		emailService: emailService,
+		config:       models.DefaultFlagConfig(),
+	}
+}
+
+// SetPriceReader sets where detection reads bars from, e.g. the price cache or only regular session bars
+func (fds *FlagDetectionService) SetPriceReader(prices PriceReader) {
+	fds.prices = prices
+}
+
+// withContext returns a copy of the service whose queries run under ctx
+func (fds *FlagDetectionService) withContext(ctx context.Context) *FlagDetectionService {
+	bound := *fds
+	bound.db = fds.db.WithContext(ctx)
+	bound.prices = bindPriceReader(ctx, fds.prices)
+	return &bound
+}
+
+// Config returns a copy of the detection settings
+func (fds *FlagDetectionService) Config() *models.FlagConfig {
+	config := *fds.config
+	return &config
+}
+
+// SetConfig replaces the detection settings, e.g. with overrides from the config file
+func (fds *FlagDetectionService) SetConfig(config *models.FlagConfig) {
+	fds.config = config
+}
+
+// SetWebhookService sets the service that posts pattern lifecycle webhooks
+func (fds *FlagDetectionService) SetWebhookService(webhooks *WebhookService) {
+	fds.webhooks = webhooks
+}
+
+// DetectFlag detects bull or bear flag patterns for a symbol on the given timeframe
+func (fds *FlagDetectionService) DetectFlag(ctx context.Context, symbol string, timeframe models.Timeframe) (*models.FlagPattern, error) {
+	fds = fds.withContext(ctx)
+	log.Printf("Detecting flag pattern for %s on %s", symbol, timeframe)
+
+	endTime := clockNow()
+	startTime := endTime.Add(-(fds.config.MaxPoleDuration + fds.config.MaxFlagDuration))
+
+	priceData, err := fds.prices.GetPriceDataRangeTimeframe(symbol, startTime, endTime, timeframe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price data: %w", err)
+	}
+
+	if len(priceData) < 30 {
+		return nil, fmt.Errorf("%w: insufficient price data for pattern detection", ErrNoPattern)
+	}
+
+	pattern := fds.analyzeFlag(symbol, priceData, true)
+	if pattern == nil {
+		pattern = fds.analyzeFlag(symbol, priceData, false)
+	}
+	if pattern == nil {
+		return nil, fmt.Errorf("%w: no flag formation", ErrNoPattern)
+	}
+
+	// A formation found again by a later scan refreshes the stored pattern instead of adding a duplicate
+	stored, err := fds.db.GetFlagPatternByFingerprint(symbol, pattern.Fingerprint())
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil {
+		if !stored.IsComplete {
+			stored.Refresh(pattern)
+			if err := fds.db.UpdateFlagPattern(stored); err != nil {
+				return nil, err
+			}
+		}
+		log.Printf("%s pattern for %s already stored (ID: %d)", stored.PatternType, symbol, stored.ID)
+		return stored, nil
+	}
+
+	// Avoid storing the same active pattern on every scan
+	existing, err := fds.db.GetFlagPatterns(&models.FlagFilter{
+		Symbol:      symbol,
+		PatternType: pattern.PatternType,
+		IsComplete:  boolPtr(false),
+		Limit:       1,
+	})
+	if err == nil && len(existing) > 0 {
+		log.Printf("Active %s pattern already tracked for %s (ID: %d)", pattern.PatternType, symbol, existing[0].ID)
+		return existing[0], nil
+	}
+
+	if err := fds.db.InsertFlagPattern(pattern); err != nil {
+		log.Printf("Warning: Failed to store pattern in database: %v", err)
+		return pattern, nil
+	}
+
+	log.Printf("Successfully detected and stored %s pattern for %s (ID: %d)", pattern.PatternType, symbol, pattern.ID)
+	emitPatternDetected(fds.webhooks, symbol, pattern)
+	notifyPatternDetected(fds.emailService, symbol, fds.patternName(pattern), pattern.BreakoutLevel, pattern.CalculateTargetPrice(), pattern.ThesisComponents.CompletionPercent, pattern)
+
+	return pattern, nil
+}
+
+// analyzeFlag looks for a sharp pole followed by a shallow consolidation that runs up to the latest bar
+func (fds *FlagDetectionService) analyzeFlag(symbol string, priceData []*models.PriceData, bullish bool) *models.FlagPattern {
+	latest := priceData[len(priceData)-1].Timestamp
+
+	// Walk back from the latest bar looking for the pole end, so the most recent flag wins
+	for j := len(priceData) - 2; j > 0; j-- {
+		flagDuration := latest.Sub(priceData[j].Timestamp)
+		if flagDuration < fds.config.MinFlagDuration {
+			continue
+		}
+		if flagDuration > fds.config.MaxFlagDuration {
+			break
+		}
+
+		poleEnd := fds.extreme(priceData[j], bullish)
+		flagHigh, flagLow := fds.flagRange(priceData[j+1:])
+
+		// The flag must stay inside the pole's extreme
+		if (bullish && flagHigh.Price > poleEnd) || (!bullish && flagLow.Price < poleEnd) {
+			continue
+		}
+
+		// Pole start is the opposite extreme within the pole window
+		startIdx := -1
+		poleStart := 0.0
+		for i := j - 1; i >= 0 && priceData[j].Timestamp.Sub(priceData[i].Timestamp) <= fds.config.MaxPoleDuration; i-- {
+			candidate := fds.extreme(priceData[i], !bullish)
+			if startIdx == -1 || (bullish && candidate < poleStart) || (!bullish && candidate > poleStart) {
+				startIdx, poleStart = i, candidate
+			}
+		}
+		if startIdx == -1 {
+			continue
+		}
+
+		poleHeight := math.Abs(poleEnd - poleStart)
+		poleChange := poleHeight / poleStart * 100
+		if poleChange < fds.config.MinPoleChange {
+			continue
+		}
+
+		retracement := (poleEnd - flagLow.Price) / poleHeight * 100
+		if !bullish {
+			retracement = (flagHigh.Price - poleEnd) / poleHeight * 100
+		}
+		if retracement > fds.config.MaxRetracement {
+			continue
+		}
+
+		return fds.buildFlagPattern(symbol, priceData, startIdx, j, flagHigh, flagLow, poleHeight, poleChange, retracement, bullish)
+	}
+
+	return nil
+}
+
+// extreme returns the bar's high for bullish extremes and its low otherwise
+func (fds *FlagDetectionService) extreme(bar *models.PriceData, high bool) float64 {
+	if high {
+		return bar.High
+	}
+	return bar.Low
+}
+
+// flagRange returns the highest high and lowest low of the consolidation bars
+func (fds *FlagDetectionService) flagRange(bars []*models.PriceData) (models.PatternPoint, models.PatternPoint) {
+	high := models.PatternPoint{Timestamp: bars[0].Timestamp, Price: bars[0].High, Volume: bars[0].Volume}
+	low := models.PatternPoint{Timestamp: bars[0].Timestamp, Price: bars[0].Low, Volume: bars[0].Volume}
+
+	for _, bar := range bars[1:] {
+		if bar.High > high.Price {
+			high = models.PatternPoint{Timestamp: bar.Timestamp, Price: bar.High, Volume: bar.Volume}
+		}
+		if bar.Low < low.Price {
+			low = models.PatternPoint{Timestamp: bar.Timestamp, Price: bar.Low, Volume: bar.Volume}
+		}
+	}
+
+	return high, low
+}
+
+// buildFlagPattern constructs the complete pattern structure
+func (fds *FlagDetectionService) buildFlagPattern(symbol string, priceData []*models.PriceData, startIdx, endIdx int, flagHigh, flagLow models.PatternPoint, poleHeight, poleChange, retracement float64, bullish bool) *models.FlagPattern {
+	start, end := priceData[startIdx], priceData[endIdx]
+	flagBars := priceData[endIdx+1:]
+
+	pattern := &models.FlagPattern{
+		Symbol: symbol,
+		PoleStart: models.PatternPoint{
+			Timestamp:   start.Timestamp,
+			Price:       fds.extreme(start, !bullish),
+			Volume:      start.Volume,
+			VolumeRatio: volumeRatioAt(priceData, startIdx),
+		},
+		PoleEnd: models.PatternPoint{
+			Timestamp:   end.Timestamp,
+			Price:       fds.extreme(end, bullish),
+			Volume:      end.Volume,
+			VolumeRatio: volumeRatioAt(priceData, endIdx),
+		},
+		FlagHigh:      flagHigh,
+		FlagLow:       flagLow,
+		PoleHeight:    poleHeight,
+		PoleChange:    poleChange,
+		Retracement:   retracement,
+		FlagSlope:     fds.channelSlope(flagBars),
+		PatternWidth:  int64(flagBars[len(flagBars)-1].Timestamp.Sub(start.Timestamp).Minutes()),
+		VolumeProfile: volumeProfileBetween(priceData, start.Timestamp, flagBars[len(flagBars)-1].Timestamp),
+		DetectedAt:    clockNow(),
+		LastUpdated:   clockNow(),
+		CurrentPhase:  models.PhaseFormation,
+		IsComplete:    false,
+	}
+
+	if bullish {
+		pattern.PatternType = models.PatternBullFlag
+		pattern.BreakoutLevel = flagHigh.Price
+	} else {
+		pattern.PatternType = models.PatternBearFlag
+		pattern.BreakoutLevel = flagLow.Price
+	}
+
+	pattern.ThesisComponents.InitializeFlagThesis(bullish)
+	fds.evaluateInitialThesis(pattern, fds.averageVolume(priceData[startIdx:endIdx+1]), fds.averageVolume(flagBars))
+
+	return pattern
+}
+
+// channelSlope returns the least-squares slope of closing prices in price per hour
+func (fds *FlagDetectionService) channelSlope(bars []*models.PriceData) float64 {
+	if len(bars) < 2 {
+		return 0
+	}
+
+	origin := bars[0].Timestamp
+	var sumX, sumY, sumXY, sumXX float64
+	for _, bar := range bars {
+		x := bar.Timestamp.Sub(origin).Hours()
+		sumX += x
+		sumY += bar.Close
+		sumXY += x * bar.Close
+		sumXX += x * x
+	}
+
+	n := float64(len(bars))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+
+	return (n*sumXY - sumX*sumY) / denominator
+}
+
+// averageVolume returns the mean volume of the bars
+func (fds *FlagDetectionService) averageVolume(bars []*models.PriceData) float64 {
+	if len(bars) == 0 {
+		return 0
+	}
+
+	var total int64
+	for _, bar := range bars {
+		total += bar.Volume
+	}
+	return float64(total) / float64(len(bars))
+}
+
+// evaluateInitialThesis completes the formation components observed at detection time
+func (fds *FlagDetectionService) evaluateInitialThesis(pattern *models.FlagPattern, poleVolume, flagVolume float64) {
+	thesis := &pattern.ThesisComponents
+
+	completeComponent(&thesis.StrongPole, math.Min(100.0, 60.0+pattern.PoleChange*4),
+		fmt.Sprintf("Flagpole moved %.1f%% from $%.2f to $%.2f", pattern.PoleChange, pattern.PoleStart.Price, pattern.PoleEnd.Price))
+
+	// A flag drifts against the pole (or sideways), never with it
+	counterTrend := (pattern.IsBullish() && pattern.FlagSlope <= 0) || (!pattern.IsBullish() && pattern.FlagSlope >= 0)
+	if counterTrend || math.Abs(pattern.FlagSlope) < pattern.PoleHeight*0.01 {
+		completeComponent(&thesis.OrderlyChannel, 75.0,
+			fmt.Sprintf("Flag channel $%.2f - $%.2f, slope %.4f per hour", pattern.FlagLow.Price, pattern.FlagHigh.Price, pattern.FlagSlope))
+	}
+
+	if pattern.Retracement <= fds.config.MaxRetracement {
+		completeComponent(&thesis.ShallowRetracement, 100.0-pattern.Retracement,
+			fmt.Sprintf("Flag retraced %.1f%% of the pole", pattern.Retracement))
+	}
+
+	if poleVolume > 0 && flagVolume < poleVolume {
+		completeComponent(&thesis.VolumeDecline, 70.0,
+			fmt.Sprintf("Flag volume %.0f%% of pole volume", flagVolume/poleVolume*100))
+	}
+
+	// Formation alerts are covered by the detection email
+	for _, component := range thesis.GetAllComponents() {
+		if component.IsCompleted {
+			component.NotificationSent = true
+		}
+	}
+
+	thesis.CalculateCompletion()
+	thesis.UpdatePhase()
+	pattern.CurrentPhase = thesis.CurrentPhase
+}
+
+// MonitorActivePatterns updates the thesis of all active flag patterns
+func (fds *FlagDetectionService) MonitorActivePatterns() error {
+	patterns, err := fds.db.GetActiveFlagPatterns()
+	if err != nil {
+		return fmt.Errorf("failed to get active flag patterns: %w", err)
+	}
+
+	log.Printf("Monitoring %d active flag patterns", len(patterns))
+
+	for _, pattern := range patterns {
+		if err := fds.updatePatternThesis(pattern); err != nil {
+			log.Printf("Failed to update flag thesis for %s: %v", pattern.Symbol, err)
+			continue
+		}
+
+		if err := fds.db.UpdateFlagPattern(pattern); err != nil {
+			log.Printf("Failed to save updated flag pattern for %s: %v", pattern.Symbol, err)
+		}
+	}
+
+	return nil
+}
+
+// updatePatternThesis updates breakout and target components based on the latest price
+func (fds *FlagDetectionService) updatePatternThesis(pattern *models.FlagPattern) error {
+	latest, err := fds.db.GetLatestPriceData(pattern.Symbol)
+	if err != nil {
+		return fmt.Errorf("failed to get latest price data: %w", err)
+	}
+	if latest == nil {
+		return fmt.Errorf("no price data available for symbol %s", pattern.Symbol)
+	}
+
+	thesis := &pattern.ThesisComponents
+	price := latest.Close
+	previousPhase := pattern.CurrentPhase
+
+	if pattern.IsBrokenOut(price) {
+		completeComponent(&thesis.ChannelBreak, 85.0,
+			fmt.Sprintf("Price broke the flag channel: $%.2f vs $%.2f", price, pattern.BreakoutLevel))
+		completeComponent(&thesis.PriceCloseBeyond, 85.0,
+			fmt.Sprintf("Close of $%.2f beyond $%.2f at %s", price, pattern.BreakoutLevel, latest.Timestamp.Format("2006-01-02 15:04")))
+
+		if ratio, err := latestVolumeRatio(fds.db, pattern.Symbol); err == nil && ratio >= fds.config.BreakoutVolumeRatio {
+			completeComponent(&thesis.VolumeConfirmation, 80.0,
+				fmt.Sprintf("Breakout volume %.1fx average", ratio))
+		}
+	}
+
+	if thesis.ChannelBreak.IsCompleted {
+		target := pattern.CalculateTargetPrice()
+		partial := pattern.BreakoutLevel + (target-pattern.BreakoutLevel)*0.5
+
+		if (pattern.IsBullish() && price >= partial) || (!pattern.IsBullish() && price <= partial) {
+			completeComponent(&thesis.PartialTarget, 90.0, fmt.Sprintf("50%% target reached: $%.2f", partial))
+		}
+
+		if (pattern.IsBullish() && price >= target) || (!pattern.IsBullish() && price <= target) {
+			completeComponent(&thesis.FullTarget, 100.0, fmt.Sprintf("Full target reached: $%.2f", target))
+			pattern.IsComplete = true
+		}
+	}
+
+	thesis.CalculateCompletion()
+	thesis.UpdatePhase()
+	pattern.CurrentPhase = thesis.CurrentPhase
+	emitPhaseChange(fds.webhooks, pattern.Symbol, previousPhase, pattern.CurrentPhase, price, pattern)
+
+	notifyCompletedComponents(fds.emailService, pattern.Symbol, fds.patternName(pattern), pattern.CurrentPhase,
+		thesis.GetAllComponents(), pattern.CalculateTargetPrice(), pattern.BreakoutLevel, pattern)
+
+	return nil
+}
+
+// patternName returns the display name of the flag type
+func (fds *FlagDetectionService) patternName(pattern *models.FlagPattern) string {
+	if pattern.IsBullish() {
+		return "Bull Flag"
+	}
+	return "Bear Flag"
+}
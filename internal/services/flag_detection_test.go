@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestFlagDetection_BullFlag tests bull flag detection on a sharp rally followed by a shallow pullback
+func TestFlagDetection_BullFlag(t *testing.T) {
+	service := NewFlagDetectionService(nil, nil, nil)
+
+	start := time.Now().Add(-60 * time.Hour)
+	var testData []*models.PriceData
+	addBar := func(high, low, close float64, volume int64) {
+		testData = append(testData, &models.PriceData{
+			Timestamp: start.Add(time.Duration(len(testData)) * time.Hour),
+			High:      high,
+			Low:       low,
+			Close:     close,
+			Volume:    volume,
+		})
+	}
+
+	// Base around $100
+	for i := 0; i < 10; i++ {
+		addBar(100.5, 99.5, 100, 1000)
+	}
+	// Flagpole: $100 -> $112 on heavy volume
+	for i := 1; i <= 6; i++ {
+		price := 100 + float64(i)*2
+		addBar(price+0.2, price-1.8, price, 5000)
+	}
+	// Flag: tight drift lower on light volume
+	for i := 0; i < 20; i++ {
+		price := 111 - float64(i)*0.1
+		addBar(price+0.4, price-0.4, price, 800)
+	}
+
+	pattern := service.analyzeFlag("TEST", testData, true)
+	if pattern == nil {
+		t.Fatal("expected bull flag to be detected")
+	}
+
+	if pattern.PatternType != models.PatternBullFlag {
+		t.Errorf("expected pattern type %s, got %s", models.PatternBullFlag, pattern.PatternType)
+	}
+
+	if pattern.PoleChange < 10 {
+		t.Errorf("expected pole change of at least 10%%, got %.2f%%", pattern.PoleChange)
+	}
+
+	if pattern.Retracement > 50 {
+		t.Errorf("expected shallow retracement, got %.2f%%", pattern.Retracement)
+	}
+
+	if !pattern.ThesisComponents.StrongPole.IsCompleted || !pattern.ThesisComponents.OrderlyChannel.IsCompleted {
+		t.Errorf("expected formation components to be completed: %+v", pattern.ThesisComponents)
+	}
+
+	if pattern.CurrentPhase != models.PhaseBreakout {
+		t.Errorf("expected phase %s, got %s", models.PhaseBreakout, pattern.CurrentPhase)
+	}
+
+	if target := pattern.CalculateTargetPrice(); target <= pattern.BreakoutLevel {
+		t.Errorf("expected target above breakout level, got target %.2f breakout %.2f", target, pattern.BreakoutLevel)
+	}
+
+	// The same data must not produce a bear flag
+	if bear := service.analyzeFlag("TEST", testData, false); bear != nil {
+		t.Errorf("expected no bear flag, got %+v", bear)
+	}
+}
+
+// TestDetectFlagRescan tests that scanning the same bars again returns the stored flag instead of adding a
+// duplicate, even when a newer active flag of the same type is stored
+func TestDetectFlagRescan(t *testing.T) {
+	db := newTestDB(t)
+
+	start := time.Now().Add(-60 * time.Hour).Truncate(time.Hour)
+	var bars []*models.PriceData
+	addBar := func(high, low, close float64, volume int64) {
+		bars = append(bars, &models.PriceData{
+			Symbol: "TEST", Timestamp: start.Add(time.Duration(len(bars)) * time.Hour),
+			Open: close, High: high, Low: low, Close: close, Volume: volume,
+		})
+	}
+	for i := 0; i < 10; i++ {
+		addBar(100.5, 99.5, 100, 1000)
+	}
+	for i := 1; i <= 6; i++ {
+		price := 100 + float64(i)*2
+		addBar(price+0.2, price-1.8, price, 5000)
+	}
+	for i := 0; i < 20; i++ {
+		price := 111 - float64(i)*0.1
+		addBar(price+0.4, price-0.4, price, 800)
+	}
+	if err := db.InsertPriceDataBatch(bars); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+
+	service := NewFlagDetectionService(db, nil, nil)
+	first, err := service.DetectFlag(context.Background(), "TEST", models.DefaultTimeframe)
+	if err != nil {
+		t.Fatalf("DetectFlag failed: %v", err)
+	}
+
+	// Another active flag would otherwise hide the stored one from the active pattern check
+	other := &models.FlagPattern{Symbol: "TEST", PatternType: first.PatternType, CurrentPhase: models.PhaseFormation, DetectedAt: time.Now(), LastUpdated: time.Now()}
+	if err := db.InsertFlagPattern(other); err != nil {
+		t.Fatalf("InsertFlagPattern failed: %v", err)
+	}
+
+	second, err := service.DetectFlag(context.Background(), "TEST", models.DefaultTimeframe)
+	if err != nil {
+		t.Fatalf("DetectFlag failed: %v", err)
+	}
+	if second.ID != first.ID {
+		t.Errorf("expected the rescan to return stored flag %d, got %d", first.ID, second.ID)
+	}
+
+	flags, err := db.GetFlagPatternsBySymbol("TEST")
+	if err != nil {
+		t.Fatalf("GetFlagPatternsBySymbol failed: %v", err)
+	}
+	if len(flags) != 2 {
+		t.Errorf("expected no duplicate flag, got %d flags", len(flags))
+	}
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+const (
+	// floorPivotLookbackDays are the calendar days of daily candles read to find the prior session and week
+	floorPivotLookbackDays = 21
+	// pivotConfluencePercent is how close to a floor pivot a setup's entry counts as confluent with it
+	pivotConfluencePercent = 0.5
+)
+
+// FloorPivotService computes the classic floor trader pivot points of a session from the prior session's and
+// prior week's regular session high, low and close, storing them per symbol per session
+type FloorPivotService struct {
+	db       *database.Database
+	calendar *MarketCalendar
+}
+
+// NewFloorPivotService creates a new floor pivot service
+func NewFloorPivotService(db *database.Database, calendar *MarketCalendar) *FloorPivotService {
+	return &FloorPivotService{
+		db:       db,
+		calendar: calendar,
+	}
+}
+
+// Get returns a symbol's daily and weekly pivots for the current session, computing and storing them the first
+// time they are asked for. A period without prior candles is left out.
+func (fps *FloorPivotService) Get(symbol string) ([]*models.FloorPivots, error) {
+	symbol = strings.ToUpper(symbol)
+	now := clockNow()
+	tradingDate := database.TradingDate(now)
+
+	stored, err := fps.db.GetFloorPivots(symbol, tradingDate)
+	if err != nil {
+		return nil, err
+	}
+	if len(stored) == 2 {
+		return stored, nil
+	}
+
+	candles, err := NewRegularSessionReader(fps.db, fps.calendar).GetPriceData(&models.PriceDataFilter{
+		Symbol: symbol, From: now.AddDate(0, 0, -floorPivotLookbackDays), To: now, Timeframe: models.Timeframe1d,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s daily bars: %w", symbol, err)
+	}
+
+	all := floorPivotsFromCandles(symbol, tradingDate, candles)
+	for _, pivots := range all {
+		pivots.ComputedAt = now
+		if err := fps.db.UpsertFloorPivots(pivots); err != nil {
+			return nil, err
+		}
+	}
+	return all, nil
+}
+
+// Levels returns the prices of a symbol's daily and weekly pivots for the current session
+func (fps *FloorPivotService) Levels(symbol string) ([]*models.FloorPivotLevel, error) {
+	all, err := fps.Get(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	levels := make([]*models.FloorPivotLevel, 0, 7*len(all))
+	for _, pivots := range all {
+		levels = append(levels, pivots.Levels()...)
+	}
+	return levels, nil
+}
+
+// floorPivotsFromCandles computes the daily pivots from the last daily candle before the trading date and the
+// weekly pivots from the candles of the last week before the trading date's week. Candles must be oldest first.
+func floorPivotsFromCandles(symbol, tradingDate string, candles []*models.PriceData) []*models.FloorPivots {
+	date, err := time.Parse("2006-01-02", tradingDate)
+	if err != nil {
+		return nil
+	}
+	year, week := date.ISOWeek()
+
+	var prior []*models.PriceData
+	for _, candle := range candles {
+		if database.TradingDate(candle.Timestamp) < tradingDate {
+			prior = append(prior, candle)
+		}
+	}
+
+	all := make([]*models.FloorPivots, 0, 2)
+	if len(prior) == 0 {
+		return all
+	}
+	last := prior[len(prior)-1]
+	all = append(all, floorPivots(symbol, models.FloorPivotDaily, tradingDate, last.High, last.Low, last.Close))
+
+	// The prior week is the latest week with candles before the trading date's week
+	var high, low, closePrice float64
+	var priorYear, priorWeek int
+	for i := len(prior) - 1; i >= 0; i-- {
+		candle := prior[i]
+		day, _ := time.Parse("2006-01-02", database.TradingDate(candle.Timestamp))
+		y, w := day.ISOWeek()
+		if y == year && w == week {
+			continue
+		}
+		if priorWeek == 0 {
+			priorYear, priorWeek = y, w
+			high, low, closePrice = candle.High, candle.Low, candle.Close
+		}
+		if y != priorYear || w != priorWeek {
+			break
+		}
+		high = math.Max(high, candle.High)
+		low = math.Min(low, candle.Low)
+	}
+	if priorWeek != 0 {
+		all = append(all, floorPivots(symbol, models.FloorPivotWeekly, tradingDate, high, low, closePrice))
+	}
+	return all
+}
+
+// floorPivots computes the pivot point and three resistances and supports of a period's high, low and close
+func floorPivots(symbol, period, tradingDate string, high, low, closePrice float64) *models.FloorPivots {
+	pp := (high + low + closePrice) / 3
+	round := func(price float64) float64 {
+		return math.Round(price*100) / 100
+	}
+
+	return &models.FloorPivots{
+		Symbol:      symbol,
+		Period:      period,
+		TradingDate: tradingDate,
+		High:        high,
+		Low:         low,
+		Close:       closePrice,
+		PP:          round(pp),
+		R1:          round(2*pp - low),
+		R2:          round(pp + (high - low)),
+		R3:          round(high + 2*(pp-low)),
+		S1:          round(2*pp - high),
+		S2:          round(pp - (high - low)),
+		S3:          round(low - 2*(high-pp)),
+	}
+}
+
+// nearFloorPivot reports whether a price lies within pivotConfluencePercent of any of the pivot levels
+func nearFloorPivot(price float64, levels []*models.FloorPivotLevel) bool {
+	if price <= 0 {
+		return false
+	}
+	for _, level := range levels {
+		if math.Abs(level.Level-price)/price*100 <= pivotConfluencePercent {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// TestFloorPivots tests the daily pivots from the prior session, the weekly pivots from the prior week, their
+// storage per session and the setup confluence bonus
+func TestFloorPivots(t *testing.T) {
+	cfg := &config.Config{}
+	db := newTestDB(t)
+
+	calendar := NewMarketCalendar(cfg.MarketHours)
+	loc := calendar.Location()
+	bar := func(day int, high, low, close float64) *models.PriceData {
+		ts := time.Date(2025, 3, day, 10, 0, 0, 0, loc)
+		return &models.PriceData{Symbol: "TEST", Timestamp: ts, Open: close, High: high, Low: low, Close: close, Volume: 100}
+	}
+	// The week of March 3 spans 95 to 110 and closes at 108; Monday March 10 spans 106 to 112 and closes at 109
+	bars := []*models.PriceData{
+		bar(3, 105, 95, 100), bar(5, 107, 99, 104), bar(7, 110, 98, 108), bar(10, 112, 106, 109), bar(11, 120, 90, 95),
+	}
+	if err := db.InsertPriceDataBatch(bars); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+
+	SetClock(NewSimulatedClock(time.Date(2025, 3, 11, 11, 0, 0, 0, loc)))
+	t.Cleanup(func() { SetClock(nil) })
+
+	pivots := NewFloorPivotService(db, calendar)
+	all, err := pivots.Get("test")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("expected daily and weekly pivots, got %d", len(all))
+	}
+
+	daily, weekly := all[0], all[1]
+	if daily.Period != models.FloorPivotDaily || daily.TradingDate != "2025-03-11" || daily.PP != 109 ||
+		daily.R1 != 112 || daily.R2 != 115 || daily.R3 != 118 || daily.S1 != 106 || daily.S2 != 103 || daily.S3 != 100 {
+		t.Errorf("unexpected daily pivots: %+v", daily)
+	}
+	if weekly.Period != models.FloorPivotWeekly || weekly.High != 110 || weekly.Low != 95 || weekly.Close != 108 ||
+		weekly.PP != 104.33 || weekly.R1 != 113.67 || weekly.S1 != 98.67 {
+		t.Errorf("unexpected weekly pivots: %+v", weekly)
+	}
+
+	stored, err := db.GetFloorPivots("TEST", "2025-03-11")
+	if err != nil || len(stored) != 2 {
+		t.Fatalf("expected the pivots to be stored for the session, got %d, %v", len(stored), err)
+	}
+
+	levels, err := pivots.Levels("TEST")
+	if err != nil || len(levels) != 14 {
+		t.Fatalf("expected 14 levels, got %d, %v", len(levels), err)
+	}
+	if level := levels[3]; level.Name != "PP" || level.Level != 109 || level.LevelClass != models.SRClassFloorPivot {
+		t.Errorf("unexpected daily pivot point level: %+v", level)
+	}
+
+	// An entry at the daily R1 earns the bonus only once a weight is set
+	sds := NewSetupDetectionService(db, nil, nil)
+	sds.SetFloorPivotService(pivots)
+	score := func(entry float64) float64 {
+		setup := &models.TradingSetup{Symbol: "TEST", EntryPrice: entry}
+		if err := sds.scoreSetup(setup, &models.TechnicalIndicators{}); err != nil {
+			t.Fatalf("scoreSetup failed: %v", err)
+		}
+		return setup.QualityScore
+	}
+	base := score(112.2)
+	config := sds.Config()
+	config.PivotConfluenceWeight = 5
+	sds.SetConfig(config)
+	if bonus := score(112.2) - base; bonus != 5 {
+		t.Errorf("expected a 5 point pivot bonus, got %v", bonus)
+	}
+	if bonus := score(125) - base; bonus != 0 {
+		t.Errorf("expected no bonus away from the pivots, got %v", bonus)
+	}
+}
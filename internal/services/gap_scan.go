@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Gap scan defaults
+const (
+	DefaultGapScanMinGapPercent = 2.0
+	defaultGapScanTime          = "09:00"
+	defaultGapScanStrategy      = "Today"
+
+	gapScanLookbackDays = 10 // calendar days searched for the prior regular session close
+)
+
+// GapScanService ranks watched symbols by their pre-market gap from the prior close and pre-market relative
+// volume before the open, storing the day's report and optionally tagging the gappers into a watchlist strategy
+type GapScanService struct {
+	db            *database.Database
+	calendar      *MarketCalendar
+	rvol          *RVOLService
+	at            string
+	minGapPercent float64
+	minRVOL       float64
+	autoTag       bool
+	strategy      string
+	schedules     *ScheduleControl
+	cron          *cron.Cron
+}
+
+// NewGapScanService creates a new pre-market gap scan service
+func NewGapScanService(cfg *config.Config, db *database.Database, calendar *MarketCalendar, rvol *RVOLService) *GapScanService {
+	gs := &GapScanService{
+		db:            db,
+		calendar:      calendar,
+		rvol:          rvol,
+		at:            cfg.GapScan.Time,
+		minGapPercent: cfg.GapScan.MinGapPercent,
+		minRVOL:       cfg.GapScan.MinRVOL,
+		autoTag:       cfg.GapScan.AutoTag,
+		strategy:      cfg.GapScan.Strategy,
+		cron:          cron.New(cron.WithLocation(calendar.Location())),
+	}
+	if gs.at == "" {
+		gs.at = defaultGapScanTime
+	}
+	if gs.minGapPercent <= 0 {
+		gs.minGapPercent = DefaultGapScanMinGapPercent
+	}
+	if gs.strategy == "" {
+		gs.strategy = defaultGapScanStrategy
+	}
+
+	return gs
+}
+
+// SetScheduleControl sets the control that can disable the morning scan
+func (gs *GapScanService) SetScheduleControl(schedules *ScheduleControl) {
+	gs.schedules = schedules
+}
+
+// AutoTag reports whether scans tag the gappers into the strategy by default
+func (gs *GapScanService) AutoTag() bool {
+	return gs.autoTag
+}
+
+// Start schedules the scan on weekdays at the configured exchange time. Without extended hours collection
+// there are no pre-market bars to scan, so nothing is scheduled.
+func (gs *GapScanService) Start() error {
+	if !gs.calendar.CollectsExtendedHours() {
+		log.Printf("Pre-market gap scan not scheduled: market_hours.collect_sessions is regular")
+		return nil
+	}
+
+	t, err := time.Parse("15:04", gs.at)
+	if err != nil {
+		return fmt.Errorf("invalid gap scan time %q: must be HH:MM", gs.at)
+	}
+
+	spec := fmt.Sprintf("%d %d * * 1-5", t.Minute(), t.Hour())
+	if _, err := gs.cron.AddFunc(spec, gs.scheduledScan); err != nil {
+		return fmt.Errorf("failed to schedule gap scan: %w", err)
+	}
+	log.Printf("Scheduled pre-market gap scan (%s)", spec)
+
+	gs.cron.Start()
+	return nil
+}
+
+// Stop stops the schedule and waits for a running scan to finish
+func (gs *GapScanService) Stop(ctx context.Context) error {
+	select {
+	case <-gs.cron.Stop().Done():
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for the gap scan to finish: %w", ctx.Err())
+	}
+}
+
+// scheduledScan runs the morning scan, skipping exchange holidays
+func (gs *GapScanService) scheduledScan() {
+	if !gs.calendar.IsTradingDay(clockNow()) || gs.schedules.Skip(models.ScheduleGapScan) {
+		return
+	}
+
+	report, err := gs.Scan(gs.autoTag)
+	if err != nil {
+		log.Printf("Failed to scan pre-market gaps: %v", err)
+		return
+	}
+	log.Printf("Scanned pre-market gaps of %d symbols: %d gappers", len(report.Entries), report.Gappers)
+}
+
+// Scan ranks every watched symbol's gap on the current trading day and stores the report, replacing the day's
+// earlier scan. With tag set the gappers become the only stocks of the tag strategy.
+func (gs *GapScanService) Scan(tag bool) (*models.GapReport, error) {
+	now := clockNow()
+	if !gs.calendar.IsTradingDay(now) {
+		return nil, fmt.Errorf("%w: %s is not a trading day", ErrValidation, now.In(gs.calendar.Location()).Format("2006-01-02"))
+	}
+	open, _, _ := gs.calendar.RegularSession(now)
+
+	symbols, err := gs.db.GetWatchedSymbols()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watched symbols: %w", err)
+	}
+
+	report := &models.GapReport{
+		TradeDate:     database.TradingDate(now),
+		MinGapPercent: gs.minGapPercent,
+		MinRVOL:       gs.minRVOL,
+		Entries:       make([]*models.GapScanEntry, 0, len(symbols)),
+		ScannedAt:     now,
+	}
+	for _, symbol := range symbols {
+		entry, err := gs.gap(symbol, now, open)
+		if err != nil {
+			log.Printf("Failed to scan %s pre-market gap: %v", symbol, err)
+		}
+		if entry == nil {
+			report.Skipped = append(report.Skipped, symbol)
+			continue
+		}
+		entry.TradeDate = report.TradeDate
+		entry.ScannedAt = now
+		report.Entries = append(report.Entries, entry)
+	}
+
+	rankGaps(report.Entries, gs.minGapPercent, gs.minRVOL)
+	if err := gs.db.ReplaceGapScan(report.TradeDate, report.Entries); err != nil {
+		return nil, err
+	}
+
+	gappers := make([]string, 0)
+	for _, entry := range report.Entries {
+		if entry.Gapper {
+			gappers = append(gappers, entry.Symbol)
+		}
+	}
+	report.Gappers = len(gappers)
+
+	if tag {
+		strategyID, err := gs.db.EnsureStrategy(gs.strategy, defaultStrategyColor)
+		if err != nil {
+			return nil, err
+		}
+		if err := gs.db.SetStrategySymbols(strategyID, gappers); err != nil {
+			return nil, err
+		}
+		report.Strategy = gs.strategy
+	}
+
+	return report, nil
+}
+
+// Report returns the stored scan of a trading day (YYYY-MM-DD), or the latest scan when date is empty
+func (gs *GapScanService) Report(date string) (*models.GapReport, error) {
+	entries, err := gs.db.GetGapScan(date)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("gap scan %w; run one with POST /api/market/gaps/scan", database.ErrNotFound)
+	}
+
+	report := &models.GapReport{
+		TradeDate:     entries[0].TradeDate,
+		MinGapPercent: gs.minGapPercent,
+		MinRVOL:       gs.minRVOL,
+		Entries:       entries,
+	}
+	for _, entry := range entries {
+		if entry.Gapper {
+			report.Gappers++
+		}
+		if entry.ScannedAt.After(report.ScannedAt) {
+			report.ScannedAt = entry.ScannedAt
+		}
+	}
+	return report, nil
+}
+
+// gap measures a symbol's latest pre-market price against the prior regular session close and its pre-market
+// RVOL, or returns nil when it has no prior close or no pre-market bars yet
+func (gs *GapScanService) gap(symbol string, now, open time.Time) (*models.GapScanEntry, error) {
+	to := now
+	if open.Before(to) {
+		to = open
+	}
+	bars, err := gs.db.GetPriceDataRange(symbol, now.AddDate(0, 0, -gapScanLookbackDays), to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s bars: %w", symbol, err)
+	}
+
+	today := database.TradingDate(now)
+	entry := &models.GapScanEntry{Symbol: symbol}
+	for _, bar := range bars {
+		switch session := gs.calendar.BarSession(bar); {
+		case database.TradingDate(bar.Timestamp) != today:
+			if session == models.SessionRegular {
+				entry.PrevClose = bar.Close
+			}
+		case session == models.SessionPreMarket:
+			if entry.PreMarketPrice == 0 {
+				entry.PreMarketHigh, entry.PreMarketLow = bar.High, bar.Low
+			}
+			entry.PreMarketHigh = math.Max(entry.PreMarketHigh, bar.High)
+			entry.PreMarketLow = math.Min(entry.PreMarketLow, bar.Low)
+			entry.PreMarketPrice = bar.Close
+			entry.PreMarketVolume += bar.Volume
+		}
+	}
+	if entry.PrevClose <= 0 || entry.PreMarketPrice <= 0 {
+		return nil, nil
+	}
+	entry.GapPercent = roundTo((entry.PreMarketPrice-entry.PrevClose)/entry.PrevClose*100, 2)
+
+	// RVOL by the last pre-market bar, as the regular session volume would swamp it
+	curve, err := gs.rvol.Compute(symbol, 0)
+	if err != nil {
+		return entry, fmt.Errorf("failed to compute %s RVOL: %w", symbol, err)
+	}
+	for _, point := range curve.Points {
+		if point.Minute < regularOpenMinute {
+			entry.RVOL = roundTo(point.RVOL, 2)
+		}
+	}
+
+	return entry, nil
+}
+
+// rankGaps flags the gappers and orders the entries by the size of their gap either way, then by RVOL
+func rankGaps(entries []*models.GapScanEntry, minGapPercent, minRVOL float64) {
+	for _, entry := range entries {
+		entry.Gapper = math.Abs(entry.GapPercent) >= minGapPercent && entry.RVOL >= minRVOL
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		gi, gj := math.Abs(entries[i].GapPercent), math.Abs(entries[j].GapPercent)
+		if gi != gj {
+			return gi > gj
+		}
+		if entries[i].RVOL != entries[j].RVOL {
+			return entries[i].RVOL > entries[j].RVOL
+		}
+		return entries[i].Symbol < entries[j].Symbol
+	})
+	for i, entry := range entries {
+		entry.Rank = i + 1
+	}
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// TestRankGaps tests gappers are flagged and ranked by gap size either way, then RVOL
+func TestRankGaps(t *testing.T) {
+	entries := []*models.GapScanEntry{
+		{Symbol: "UP", GapPercent: 3, RVOL: 1},
+		{Symbol: "DOWN", GapPercent: -5, RVOL: 4},
+		{Symbol: "FLAT", GapPercent: 0.5, RVOL: 6},
+		{Symbol: "TIE", GapPercent: -3, RVOL: 2},
+	}
+	rankGaps(entries, 2, 1.5)
+
+	order := []string{"DOWN", "TIE", "UP", "FLAT"}
+	gappers := map[string]bool{"DOWN": true, "TIE": true}
+	for i, entry := range entries {
+		if entry.Symbol != order[i] || entry.Rank != i+1 {
+			t.Errorf("rank %d: expected %s, got %s (rank %d)", i+1, order[i], entry.Symbol, entry.Rank)
+		}
+		if entry.Gapper != gappers[entry.Symbol] {
+			t.Errorf("%s: expected gapper %v", entry.Symbol, gappers[entry.Symbol])
+		}
+	}
+}
+
+// TestGapScan tests the morning scan measures gaps and RVOL from stored bars, stores the report and tags the
+// gappers into the Today strategy
+func TestGapScan(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	cfg := &config.Config{}
+	cfg.GapScan.MinRVOL = 2
+	db := newTestDB(t)
+
+	// Tuesday morning, after Friday and Monday sessions
+	today := time.Date(2025, time.March, 4, 0, 0, 0, 0, loc)
+	priorDays := []time.Time{today.AddDate(0, 0, -4), today.AddDate(0, 0, -1)}
+	at := func(day time.Time, hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	bar := func(symbol string, ts time.Time, close float64, volume int64) *models.PriceData {
+		return &models.PriceData{Symbol: symbol, Timestamp: ts, Open: close, High: close + 0.5, Low: close - 0.5, Close: close, Volume: volume}
+	}
+
+	var bars []*models.PriceData
+	for _, symbol := range []string{"GAPR", "LOWV", "FLAT", "NOPM"} {
+		if err := db.AddWatchedSymbol(symbol, ""); err != nil {
+			t.Fatalf("AddWatchedSymbol failed: %v", err)
+		}
+		for _, day := range priorDays {
+			bars = append(bars, bar(symbol, at(day, 8, 0), 100, 1000), bar(symbol, at(day, 15, 55), 100, 5000))
+		}
+	}
+	bars = append(bars,
+		bar("GAPR", at(today, 7, 0), 104, 1000), bar("GAPR", at(today, 8, 0), 105, 2000),
+		bar("LOWV", at(today, 8, 0), 97, 1000),
+		bar("FLAT", at(today, 8, 0), 100.5, 4000),
+	)
+	if err := db.InsertPriceDataBatch(bars); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+
+	// A stock tagged by yesterday's scan that isn't gapping today
+	strategyID, err := db.EnsureStrategy(defaultGapScanStrategy, defaultStrategyColor)
+	if err != nil {
+		t.Fatalf("EnsureStrategy failed: %v", err)
+	}
+	if err := db.SetStrategySymbols(strategyID, []string{"FLAT"}); err != nil {
+		t.Fatalf("SetStrategySymbols failed: %v", err)
+	}
+
+	SetClock(NewSimulatedClock(at(today, 9, 0)))
+	t.Cleanup(func() { SetClock(nil) })
+
+	calendar := NewMarketCalendar(cfg.MarketHours)
+	service := NewGapScanService(cfg, db, calendar, NewRVOLService(cfg, db, calendar))
+	report, err := service.Scan(true)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if report.TradeDate != "2025-03-04" || len(report.Entries) != 3 || report.Gappers != 1 || report.Strategy != defaultGapScanStrategy {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if len(report.Skipped) != 1 || report.Skipped[0] != "NOPM" {
+		t.Errorf("expected NOPM skipped without pre-market bars, got %v", report.Skipped)
+	}
+
+	top := report.Entries[0]
+	if top.Symbol != "GAPR" || top.Rank != 1 || !top.Gapper || top.PrevClose != 100 || top.GapPercent != 5 {
+		t.Errorf("unexpected top gapper %+v", top)
+	}
+	if top.PreMarketVolume != 3000 || math.Abs(top.RVOL-3) > 1e-9 || top.PreMarketHigh != 105.5 || top.PreMarketLow != 103.5 {
+		t.Errorf("unexpected pre-market measures %+v", top)
+	}
+	if second := report.Entries[1]; second.Symbol != "LOWV" || second.Gapper {
+		t.Errorf("expected LOWV second and not a gapper below the RVOL threshold, got %+v", second)
+	}
+
+	stored, err := service.Report("")
+	if err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	if len(stored.Entries) != 3 || stored.Gappers != 1 || stored.Entries[0].Symbol != "GAPR" {
+		t.Errorf("unexpected stored report %+v", stored)
+	}
+	if _, err := service.Report("2025-03-03"); err == nil {
+		t.Error("expected no report for a day without a scan")
+	}
+
+	tagged, err := db.GetStocksByStrategy(strategyID)
+	if err != nil {
+		t.Fatalf("GetStocksByStrategy failed: %v", err)
+	}
+	if len(tagged) != 1 || tagged[0].Symbol != "GAPR" {
+		t.Errorf("expected only GAPR tagged into Today, got %+v", tagged)
+	}
+}
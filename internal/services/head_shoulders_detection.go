@@ -1,10 +1,12 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"math"
 	"sort"
+	"strings"
 	"time"
 
 	"market-watch-go/internal/database"
@@ -14,59 +16,118 @@ import (
 // HeadShouldersDetectionService handles head and shoulders pattern detection and monitoring
 type HeadShouldersDetectionService struct {
 	db           *database.Database
+	prices       PriceReader
 	setupService *SetupDetectionService
 	taService    *TechnicalAnalysisService
 	emailService *EmailService
+	streaming    *StreamingService
+	webhooks     *WebhookService
 	config       *models.HeadShouldersConfig
 }
 
 // NewHeadShouldersDetectionService creates a new head and shoulders detection service
 func NewHeadShouldersDetectionService(db *database.Database, setupService *SetupDetectionService, taService *TechnicalAnalysisService, emailService *EmailService) *HeadShouldersDetectionService {
-	// Default configuration
-	config := &models.HeadShouldersConfig{
-		MinPatternDuration:   72 * time.Hour,  // 3 days minimum
-		MaxPatternDuration:   720 * time.Hour, // 30 days maximum
-		MinSymmetryScore:     60.0,            // 60% symmetry minimum
-		MinVolumeIncrease:    1.2,             // 20% volume increase
-		NecklineDeviation:    0.02,            // 2% deviation allowed
-		TargetMultiplier:     1.0,             // 1:1 target projection
-		MinHeadDepth:         0.05,            // 5% minimum head depth
-		MaxShoulderAsymmetry: 0.3,             // 30% max asymmetry between shoulders
-	}
-
 	return &HeadShouldersDetectionService{
 		db:           db,
+		prices:       db,
 		setupService: setupService,
 		taService:    taService,
 		emailService: emailService,
-		config:       config,
+		config:       models.DefaultHeadShouldersConfig(),
 	}
 }
 
-// DetectInverseHeadShoulders detects inverse head and shoulders patterns for a symbol
-func (hsds *HeadShouldersDetectionService) DetectInverseHeadShoulders(symbol string) (*models.HeadShouldersPattern, error) {
-	log.Printf("Detecting inverse head and shoulders pattern for %s", symbol)
+// SetPriceReader sets where detection reads bars from, e.g. the price cache or only regular session bars
+func (hsds *HeadShouldersDetectionService) SetPriceReader(prices PriceReader) {
+	hsds.prices = prices
+}
+
+// withContext returns a copy of the service whose queries run under ctx
+func (hsds *HeadShouldersDetectionService) withContext(ctx context.Context) *HeadShouldersDetectionService {
+	bound := *hsds
+	bound.db = hsds.db.WithContext(ctx)
+	bound.prices = bindPriceReader(ctx, hsds.prices)
+	return &bound
+}
+
+// Config returns a copy of the detection settings
+func (hsds *HeadShouldersDetectionService) Config() *models.HeadShouldersConfig {
+	config := *hsds.config
+	return &config
+}
+
+// SetConfig replaces the detection settings, e.g. with overrides from the config file
+func (hsds *HeadShouldersDetectionService) SetConfig(config *models.HeadShouldersConfig) {
+	hsds.config = config
+}
+
+// SetStreamingService sets the streaming service used to push pattern alerts
+func (hsds *HeadShouldersDetectionService) SetStreamingService(streaming *StreamingService) {
+	hsds.streaming = streaming
+}
 
-	// Get price data for analysis (last 6 months)
-	endTime := time.Now()
-	startTime := endTime.Add(-180 * 24 * time.Hour) // 6 months
+// SetWebhookService sets the service that posts pattern lifecycle webhooks
+func (hsds *HeadShouldersDetectionService) SetWebhookService(webhooks *WebhookService) {
+	hsds.webhooks = webhooks
+}
+
+// DetectInverseHeadShoulders detects inverse head and shoulders patterns for a symbol; nil params use the configured bar interval, lookback and sensitivity
+func (hsds *HeadShouldersDetectionService) DetectInverseHeadShoulders(ctx context.Context, symbol string, params *models.PatternScanParams) (*models.HeadShouldersPattern, error) {
+	return hsds.withContext(ctx).detectPattern(symbol, params, models.SetupTypeInverseHeadShoulders)
+}
+
+// DetectHeadShoulders detects bearish head and shoulders tops for a symbol and stores them with a short trading setup
+func (hsds *HeadShouldersDetectionService) DetectHeadShoulders(ctx context.Context, symbol string, params *models.PatternScanParams) (*models.HeadShouldersPattern, error) {
+	return hsds.withContext(ctx).detectPattern(symbol, params, models.SetupTypeHeadShoulders)
+}
+
+// detectPattern scans a symbol for a regular or inverse pattern and stores it together with its trading setup
+func (hsds *HeadShouldersDetectionService) detectPattern(symbol string, params *models.PatternScanParams, patternType string) (*models.HeadShouldersPattern, error) {
+	name := "inverse head and shoulders"
+	if patternType == models.SetupTypeHeadShoulders {
+		name = "head and shoulders"
+	}
 
-	priceData, err := hsds.db.GetPriceDataRange(symbol, startTime, endTime)
+	scan := resolvePatternScan(params, hsds.config.BarInterval, hsds.config.LookbackDays, hsds.config.SwingWindow, hsds.config.Sensitivity)
+	log.Printf("Detecting %s pattern for %s on %s from %s", name, symbol, scan.timeframe, scan.start.Format("2006-01-02"))
+
+	priceData, err := hsds.prices.GetPriceDataRangeTimeframe(symbol, scan.start, scan.end, scan.timeframe)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get price data: %w", err)
 	}
 
 	if len(priceData) < 50 {
-		return nil, fmt.Errorf("insufficient price data for pattern detection")
+		return nil, fmt.Errorf("%w: insufficient price data for pattern detection", ErrNoPattern)
 	}
 
 	// Find potential pattern points
-	peaks, troughs := hsds.findPeaksAndTroughs(priceData)
+	peaks, troughs := hsds.findPeaksAndTroughs(priceData, scan.swingWindow)
 
-	// Analyze for inverse head and shoulders pattern
-	pattern := hsds.analyzeInverseHeadShouldersPattern(symbol, peaks, troughs)
+	var pattern *models.HeadShouldersPattern
+	if patternType == models.SetupTypeHeadShoulders {
+		pattern = hsds.analyzeHeadShouldersTopPattern(symbol, peaks, troughs, scan.sensitivity)
+	} else {
+		pattern = hsds.analyzeInverseHeadShouldersPattern(symbol, peaks, troughs, scan.sensitivity)
+	}
 	if pattern == nil {
-		return nil, fmt.Errorf("no valid inverse head and shoulders pattern found")
+		return nil, fmt.Errorf("%w: no %s formation", ErrNoPattern, name)
+	}
+
+	// A formation found again by a later scan refreshes the stored pattern instead of adding a duplicate
+	existing, err := hsds.db.GetHeadShouldersPatternByFingerprint(symbol, pattern.Fingerprint())
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		// Key points corrected by hand are kept over the detector's
+		if !existing.IsComplete && !existing.HumanEdited {
+			existing.Refresh(pattern)
+			if err := hsds.db.UpdateHeadShouldersPattern(existing); err != nil {
+				return nil, err
+			}
+		}
+		log.Printf("%s pattern for %s already stored (ID: %d)", name, symbol, existing.ID)
+		return existing, nil
 	}
 
 	// Create associated trading setup
@@ -75,32 +136,34 @@ func (hsds *HeadShouldersDetectionService) DetectInverseHeadShoulders(symbol str
 		return nil, fmt.Errorf("failed to create trading setup: %w", err)
 	}
 
-	// Store the setup first to get ID
-	err = hsds.db.InsertTradingSetup(setup)
-	if err != nil {
-		return nil, fmt.Errorf("failed to store trading setup: %w", err)
-	}
+	// Store the setup and the pattern together so a failure can't leave an orphaned setup
+	err = hsds.db.WithTx(func(tx *database.DB) error {
+		if err := tx.InsertTradingSetup(setup); err != nil {
+			return fmt.Errorf("failed to store trading setup: %w", err)
+		}
 
-	pattern.SetupID = setup.ID
+		pattern.SetupID = setup.ID
 
-	// Store the pattern
-	err = hsds.db.InsertHeadShouldersPattern(pattern)
+		if err := tx.InsertHeadShouldersPattern(pattern); err != nil {
+			return fmt.Errorf("failed to store pattern: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to store pattern: %w", err)
+		return nil, err
 	}
 
-	log.Printf("Successfully detected and stored inverse head and shoulders pattern for %s (ID: %d)", symbol, pattern.ID)
+	log.Printf("Successfully detected and stored %s pattern for %s (ID: %d)", name, symbol, pattern.ID)
+	emitPatternDetected(hsds.webhooks, symbol, pattern)
 	return pattern, nil
 }
 
 // findPeaksAndTroughs identifies local peaks and troughs in price data
-func (hsds *HeadShouldersDetectionService) findPeaksAndTroughs(priceData []*models.PriceData) (peaks, troughs []models.PatternPoint) {
-	if len(priceData) < 5 {
+func (hsds *HeadShouldersDetectionService) findPeaksAndTroughs(priceData []*models.PriceData, windowSize int) (peaks, troughs []models.PatternPoint) {
+	if len(priceData) < windowSize {
 		return
 	}
 
-	windowSize := 5 // Look for peaks/troughs over 5-period window
-
 	for i := windowSize; i < len(priceData)-windowSize; i++ {
 		current := priceData[i]
 		isPeak := true
@@ -163,7 +226,7 @@ func (hsds *HeadShouldersDetectionService) calculateVolumeRatio(priceData []*mod
 }
 
 // analyzeInverseHeadShouldersPattern analyzes price data for inverse head and shoulders pattern
-func (hsds *HeadShouldersDetectionService) analyzeInverseHeadShouldersPattern(symbol string, peaks, troughs []models.PatternPoint) *models.HeadShouldersPattern {
+func (hsds *HeadShouldersDetectionService) analyzeInverseHeadShouldersPattern(symbol string, peaks, troughs []models.PatternPoint, sensitivity float64) *models.HeadShouldersPattern {
 	if len(troughs) < 3 || len(peaks) < 2 {
 		return nil
 	}
@@ -187,7 +250,7 @@ func (hsds *HeadShouldersDetectionService) analyzeInverseHeadShouldersPattern(sy
 				rightShoulder := troughs[k]
 
 				// Check if this could be a valid inverse H&S pattern
-				if hsds.isValidInverseHeadShouldersPattern(leftShoulder, head, rightShoulder, peaks) {
+				if hsds.isValidInverseHeadShouldersPattern(leftShoulder, head, rightShoulder, peaks, sensitivity) {
 					pattern := hsds.buildInverseHeadShouldersPattern(symbol, leftShoulder, head, rightShoulder, peaks)
 					if pattern != nil {
 						return pattern
@@ -201,7 +264,7 @@ func (hsds *HeadShouldersDetectionService) analyzeInverseHeadShouldersPattern(sy
 }
 
 // isValidInverseHeadShouldersPattern checks if the given points form a valid inverse H&S pattern
-func (hsds *HeadShouldersDetectionService) isValidInverseHeadShouldersPattern(leftShoulder, head, rightShoulder models.PatternPoint, peaks []models.PatternPoint) bool {
+func (hsds *HeadShouldersDetectionService) isValidInverseHeadShouldersPattern(leftShoulder, head, rightShoulder models.PatternPoint, peaks []models.PatternPoint, sensitivity float64) bool {
 	// Head must be lower than both shoulders (inverse pattern)
 	if head.Price >= leftShoulder.Price || head.Price >= rightShoulder.Price {
 		return false
@@ -210,7 +273,7 @@ func (hsds *HeadShouldersDetectionService) isValidInverseHeadShouldersPattern(le
 	// Check minimum depth requirement
 	avgShoulderPrice := (leftShoulder.Price + rightShoulder.Price) / 2
 	headDepth := (avgShoulderPrice - head.Price) / avgShoulderPrice
-	if headDepth < hsds.config.MinHeadDepth {
+	if headDepth < hsds.config.MinHeadDepth/sensitivity {
 		return false
 	}
 
@@ -291,8 +354,8 @@ func (hsds *HeadShouldersDetectionService) buildInverseHeadShouldersPattern(symb
 		NecklineTouch2:    *rightShoulderHigh,
 		PatternWidth:      int64(rightShoulder.Timestamp.Sub(leftShoulder.Timestamp).Minutes()),
 		PatternHeight:     necklineLevel - head.Price,
-		DetectedAt:        time.Now(),
-		LastUpdated:       time.Now(),
+		DetectedAt:        clockNow(),
+		LastUpdated:       clockNow(),
 		CurrentPhase:      models.PhaseFormation,
 		IsComplete:        false,
 	}
@@ -309,53 +372,212 @@ func (hsds *HeadShouldersDetectionService) buildInverseHeadShouldersPattern(symb
 	return pattern
 }
 
+// analyzeHeadShouldersTopPattern analyzes price data for a regular (bearish) head and shoulders pattern
+func (hsds *HeadShouldersDetectionService) analyzeHeadShouldersTopPattern(symbol string, peaks, troughs []models.PatternPoint, sensitivity float64) *models.HeadShouldersPattern {
+	if len(peaks) < 3 || len(troughs) < 2 {
+		return nil
+	}
+
+	sort.Slice(peaks, func(i, j int) bool {
+		return peaks[i].Timestamp.Before(peaks[j].Timestamp)
+	})
+
+	sort.Slice(troughs, func(i, j int) bool {
+		return troughs[i].Timestamp.Before(troughs[j].Timestamp)
+	})
+
+	for i := 0; i < len(peaks)-2; i++ {
+		for j := i + 1; j < len(peaks)-1; j++ {
+			for k := j + 1; k < len(peaks); k++ {
+				leftShoulder := peaks[i]
+				head := peaks[j]
+				rightShoulder := peaks[k]
+
+				if hsds.isValidHeadShouldersTopPattern(leftShoulder, head, rightShoulder, troughs, sensitivity) {
+					pattern := hsds.buildHeadShouldersTopPattern(symbol, leftShoulder, head, rightShoulder, troughs)
+					if pattern != nil {
+						return pattern
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// isValidHeadShouldersTopPattern checks if the given peaks form a valid regular H&S pattern
+func (hsds *HeadShouldersDetectionService) isValidHeadShouldersTopPattern(leftShoulder, head, rightShoulder models.PatternPoint, troughs []models.PatternPoint, sensitivity float64) bool {
+	// Head must be higher than both shoulders
+	if head.Price <= leftShoulder.Price || head.Price <= rightShoulder.Price {
+		return false
+	}
+
+	// Check minimum height requirement
+	avgShoulderPrice := (leftShoulder.Price + rightShoulder.Price) / 2
+	headHeight := (head.Price - avgShoulderPrice) / avgShoulderPrice
+	if headHeight < hsds.config.MinHeadDepth/sensitivity {
+		return false
+	}
+
+	// Check shoulder symmetry
+	shoulderAsymmetry := math.Abs(leftShoulder.Price-rightShoulder.Price) / math.Min(leftShoulder.Price, rightShoulder.Price)
+	if shoulderAsymmetry > hsds.config.MaxShoulderAsymmetry {
+		return false
+	}
+
+	// Check pattern duration
+	patternDuration := rightShoulder.Timestamp.Sub(leftShoulder.Timestamp)
+	if patternDuration < hsds.config.MinPatternDuration || patternDuration > hsds.config.MaxPatternDuration {
+		return false
+	}
+
+	// Find troughs between shoulders for neckline calculation
+	leftTrough := hsds.findTroughBetween(troughs, leftShoulder.Timestamp, head.Timestamp)
+	rightTrough := hsds.findTroughBetween(troughs, head.Timestamp, rightShoulder.Timestamp)
+
+	return leftTrough != nil && rightTrough != nil
+}
+
+// findTroughBetween finds the lowest trough between two timestamps
+func (hsds *HeadShouldersDetectionService) findTroughBetween(troughs []models.PatternPoint, start, end time.Time) *models.PatternPoint {
+	var bestTrough *models.PatternPoint
+
+	for i := range troughs {
+		trough := &troughs[i]
+		if trough.Timestamp.After(start) && trough.Timestamp.Before(end) {
+			if bestTrough == nil || trough.Price < bestTrough.Price {
+				bestTrough = trough
+			}
+		}
+	}
+
+	return bestTrough
+}
+
+// buildHeadShouldersTopPattern constructs the complete regular H&S pattern structure
+func (hsds *HeadShouldersDetectionService) buildHeadShouldersTopPattern(symbol string, leftShoulder, head, rightShoulder models.PatternPoint, troughs []models.PatternPoint) *models.HeadShouldersPattern {
+	// Find shoulder troughs
+	leftShoulderLow := hsds.findTroughBetween(troughs, leftShoulder.Timestamp.Add(-24*time.Hour), leftShoulder.Timestamp.Add(24*time.Hour))
+	rightShoulderLow := hsds.findTroughBetween(troughs, rightShoulder.Timestamp.Add(-24*time.Hour), rightShoulder.Timestamp.Add(24*time.Hour))
+
+	if leftShoulderLow == nil || rightShoulderLow == nil {
+		return nil
+	}
+
+	// Find head trough (lowest point around the head peak)
+	headLow := hsds.findTroughBetween(troughs, head.Timestamp.Add(-48*time.Hour), head.Timestamp.Add(48*time.Hour))
+	if headLow == nil {
+		return nil
+	}
+
+	// Calculate neckline from the reaction lows
+	necklineLevel := (leftShoulderLow.Price + rightShoulderLow.Price) / 2
+	necklineSlope := (rightShoulderLow.Price - leftShoulderLow.Price) / float64(rightShoulderLow.Timestamp.Sub(leftShoulderLow.Timestamp).Hours())
+
+	pattern := &models.HeadShouldersPattern{
+		Symbol:            symbol,
+		PatternType:       models.SetupTypeHeadShoulders,
+		LeftShoulderHigh:  leftShoulder,
+		LeftShoulderLow:   *leftShoulderLow,
+		HeadHigh:          head,
+		HeadLow:           *headLow,
+		RightShoulderHigh: rightShoulder,
+		RightShoulderLow:  *rightShoulderLow,
+		NecklineLevel:     necklineLevel,
+		NecklineSlope:     necklineSlope,
+		NecklineTouch1:    *leftShoulderLow,
+		NecklineTouch2:    *rightShoulderLow,
+		PatternWidth:      int64(rightShoulder.Timestamp.Sub(leftShoulder.Timestamp).Minutes()),
+		PatternHeight:     head.Price - necklineLevel,
+		DetectedAt:        clockNow(),
+		LastUpdated:       clockNow(),
+		CurrentPhase:      models.PhaseFormation,
+		IsComplete:        false,
+	}
+
+	pattern.Symmetry = pattern.GetSymmetryScore()
+	pattern.ThesisComponents.InitializeThesis(models.SetupTypeHeadShoulders)
+	hsds.evaluateInitialThesis(pattern)
+
+	return pattern
+}
+
 // evaluateInitialThesis evaluates the initial state of thesis components
 func (hsds *HeadShouldersDetectionService) evaluateInitialThesis(pattern *models.HeadShouldersPattern) {
-	now := time.Now()
+	now := clockNow()
+
+	// The shoulders and head are the swing lows of an inverse pattern and the swing highs of a regular one
+	leftShoulder, head, rightShoulder := &pattern.LeftShoulderLow, &pattern.HeadLow, &pattern.RightShoulderLow
+	leftReaction, rightReaction := &pattern.LeftShoulderHigh, &pattern.RightShoulderHigh
+	extremeLabel, reactionLabel := "low", "high"
+	if pattern.IsBearish() {
+		leftShoulder, head, rightShoulder = &pattern.LeftShoulderHigh, &pattern.HeadHigh, &pattern.RightShoulderHigh
+		leftReaction, rightReaction = &pattern.LeftShoulderLow, &pattern.RightShoulderLow
+		extremeLabel, reactionLabel = "high", "low"
+	}
 
 	// Left shoulder formed - always completed for detected patterns
 	pattern.ThesisComponents.LeftShoulderFormed.IsCompleted = true
-	pattern.ThesisComponents.LeftShoulderFormed.CompletedAt = &pattern.LeftShoulderLow.Timestamp
+	pattern.ThesisComponents.LeftShoulderFormed.CompletedAt = &leftShoulder.Timestamp
 	pattern.ThesisComponents.LeftShoulderFormed.ConfidenceLevel = 95.0
 	pattern.ThesisComponents.LeftShoulderFormed.Evidence = []string{
-		fmt.Sprintf("Left shoulder low at $%.2f on %s", pattern.LeftShoulderLow.Price, pattern.LeftShoulderLow.Timestamp.Format("2006-01-02")),
-		fmt.Sprintf("Left shoulder high at $%.2f", pattern.LeftShoulderHigh.Price),
+		fmt.Sprintf("Left shoulder %s at $%.2f on %s", extremeLabel, leftShoulder.Price, leftShoulder.Timestamp.Format("2006-01-02")),
+		fmt.Sprintf("Left shoulder %s at $%.2f", reactionLabel, leftReaction.Price),
 	}
 
 	// Head formed - always completed for detected patterns
 	pattern.ThesisComponents.HeadFormed.IsCompleted = true
-	pattern.ThesisComponents.HeadFormed.CompletedAt = &pattern.HeadLow.Timestamp
+	pattern.ThesisComponents.HeadFormed.CompletedAt = &head.Timestamp
 	pattern.ThesisComponents.HeadFormed.ConfidenceLevel = 95.0
-	pattern.ThesisComponents.HeadFormed.Evidence = []string{
-		fmt.Sprintf("Head low at $%.2f on %s", pattern.HeadLow.Price, pattern.HeadLow.Timestamp.Format("2006-01-02")),
-		fmt.Sprintf("Head forms lower low than shoulders"),
-	}
-
-	// Head lower low - check if head is significantly lower
-	leftToHeadDepth := (pattern.LeftShoulderLow.Price - pattern.HeadLow.Price) / pattern.LeftShoulderLow.Price * 100
-	if leftToHeadDepth >= 3.0 { // 3% minimum depth
-		pattern.ThesisComponents.HeadLowerLow.IsCompleted = true
-		pattern.ThesisComponents.HeadLowerLow.CompletedAt = &pattern.HeadLow.Timestamp
-		pattern.ThesisComponents.HeadLowerLow.ConfidenceLevel = 90.0
-		pattern.ThesisComponents.HeadLowerLow.Evidence = []string{
-			fmt.Sprintf("Head is %.1f%% lower than left shoulder", leftToHeadDepth),
+	if pattern.IsBearish() {
+		pattern.ThesisComponents.HeadFormed.Evidence = []string{
+			fmt.Sprintf("Head high at $%.2f on %s", head.Price, head.Timestamp.Format("2006-01-02")),
+			"Head forms higher high than shoulders",
+		}
+
+		// Head higher high - check if head is significantly higher
+		leftToHeadHeight := (head.Price - leftShoulder.Price) / leftShoulder.Price * 100
+		if leftToHeadHeight >= 3.0 { // 3% minimum height
+			pattern.ThesisComponents.HeadHigherHigh.IsCompleted = true
+			pattern.ThesisComponents.HeadHigherHigh.CompletedAt = &head.Timestamp
+			pattern.ThesisComponents.HeadHigherHigh.ConfidenceLevel = 90.0
+			pattern.ThesisComponents.HeadHigherHigh.Evidence = []string{
+				fmt.Sprintf("Head is %.1f%% higher than left shoulder", leftToHeadHeight),
+			}
+		}
+	} else {
+		pattern.ThesisComponents.HeadFormed.Evidence = []string{
+			fmt.Sprintf("Head low at $%.2f on %s", head.Price, head.Timestamp.Format("2006-01-02")),
+			fmt.Sprintf("Head forms lower low than shoulders"),
+		}
+
+		// Head lower low - check if head is significantly lower
+		leftToHeadDepth := (leftShoulder.Price - head.Price) / leftShoulder.Price * 100
+		if leftToHeadDepth >= 3.0 { // 3% minimum depth
+			pattern.ThesisComponents.HeadLowerLow.IsCompleted = true
+			pattern.ThesisComponents.HeadLowerLow.CompletedAt = &head.Timestamp
+			pattern.ThesisComponents.HeadLowerLow.ConfidenceLevel = 90.0
+			pattern.ThesisComponents.HeadLowerLow.Evidence = []string{
+				fmt.Sprintf("Head is %.1f%% lower than left shoulder", leftToHeadDepth),
+			}
 		}
 	}
 
 	// Right shoulder formed - completed if we have right shoulder data
-	if pattern.RightShoulderLow.Price > 0 {
+	if rightShoulder.Price > 0 {
 		pattern.ThesisComponents.RightShoulderFormed.IsCompleted = true
-		pattern.ThesisComponents.RightShoulderFormed.CompletedAt = &pattern.RightShoulderLow.Timestamp
+		pattern.ThesisComponents.RightShoulderFormed.CompletedAt = &rightShoulder.Timestamp
 		pattern.ThesisComponents.RightShoulderFormed.ConfidenceLevel = 95.0
 		pattern.ThesisComponents.RightShoulderFormed.Evidence = []string{
-			fmt.Sprintf("Right shoulder low at $%.2f on %s", pattern.RightShoulderLow.Price, pattern.RightShoulderLow.Timestamp.Format("2006-01-02")),
-			fmt.Sprintf("Right shoulder high at $%.2f", pattern.RightShoulderHigh.Price),
+			fmt.Sprintf("Right shoulder %s at $%.2f on %s", extremeLabel, rightShoulder.Price, rightShoulder.Timestamp.Format("2006-01-02")),
+			fmt.Sprintf("Right shoulder %s at $%.2f", reactionLabel, rightReaction.Price),
 		}
 
 		// Check symmetry
 		if pattern.Symmetry >= hsds.config.MinSymmetryScore {
 			pattern.ThesisComponents.RightShoulderSymmetry.IsCompleted = true
-			pattern.ThesisComponents.RightShoulderSymmetry.CompletedAt = &pattern.RightShoulderLow.Timestamp
+			pattern.ThesisComponents.RightShoulderSymmetry.CompletedAt = &rightShoulder.Timestamp
 			pattern.ThesisComponents.RightShoulderSymmetry.ConfidenceLevel = pattern.Symmetry
 			pattern.ThesisComponents.RightShoulderSymmetry.Evidence = []string{
 				fmt.Sprintf("Shoulder symmetry score: %.1f%%", pattern.Symmetry),
@@ -391,10 +613,14 @@ func (hsds *HeadShouldersDetectionService) evaluateInitialThesis(pattern *models
 func (hsds *HeadShouldersDetectionService) createTradingSetup(pattern *models.HeadShouldersPattern) (*models.TradingSetup, error) {
 	targetPrice := pattern.CalculateTargetPrice()
 
+	if pattern.IsBearish() {
+		return hsds.createShortSetup(pattern, targetPrice), nil
+	}
+
 	setup := &models.TradingSetup{
 		Symbol:       pattern.Symbol,
 		SetupType:    pattern.PatternType,
-		Direction:    "long", // Inverse H&S is bullish (use 'long' to match schema)
+		Direction:    "bullish", // Inverse H&S is bullish
 		QualityScore: hsds.calculatePatternQuality(pattern),
 		Status:       "active",
 		DetectedAt:   pattern.DetectedAt,
@@ -405,22 +631,49 @@ func (hsds *HeadShouldersDetectionService) createTradingSetup(pattern *models.He
 		Target1:      targetPrice * 0.5,             // 50% target
 		Target2:      targetPrice * 0.75,            // 75% target
 		Target3:      targetPrice,                   // Full target
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+		CreatedAt:    clockNow(),
+		UpdatedAt:    clockNow(),
 	}
 
-	// Calculate risk/reward ratio manually since methods don't exist
-	if setup.EntryPrice > 0 && setup.StopLoss > 0 && setup.Target1 > 0 {
-		risk := setup.EntryPrice - setup.StopLoss
-		reward := setup.Target1 - setup.EntryPrice
-		if risk > 0 {
-			setup.RiskRewardRatio = reward / risk
-		}
-	}
+	setup.Confidence = setup.GetConfidenceLevel()
+	setup.RiskAmount = setup.GetRiskAmount()
+	setup.RewardPotential = setup.GetRewardPotential()
+	setup.RiskRewardRatio = setup.CalculateRiskRewardRatio()
 
 	return setup, nil
 }
 
+// createShortSetup creates a bearish trading setup from a regular H&S: entry just under the neckline,
+// stop above the head and targets projected below the neckline
+func (hsds *HeadShouldersDetectionService) createShortSetup(pattern *models.HeadShouldersPattern, targetPrice float64) *models.TradingSetup {
+	projection := pattern.NecklineLevel - targetPrice
+
+	setup := &models.TradingSetup{
+		Symbol:       pattern.Symbol,
+		SetupType:    pattern.PatternType,
+		Direction:    pattern.Direction(),
+		QualityScore: hsds.calculatePatternQuality(pattern),
+		Status:       "active",
+		DetectedAt:   pattern.DetectedAt,
+		ExpiresAt:    pattern.DetectedAt.Add(24 * time.Hour), // 24 hours to enter
+		CurrentPrice: pattern.RightShoulderHigh.Price,
+		EntryPrice:   pattern.NecklineLevel * 0.998,           // Slight discount below neckline
+		StopLoss:     pattern.HeadHigh.Price * 1.02,           // Above head high
+		Target1:      pattern.NecklineLevel - projection*0.5,  // 50% target
+		Target2:      pattern.NecklineLevel - projection*0.75, // 75% target
+		Target3:      targetPrice,                             // Full target
+		CreatedAt:    clockNow(),
+		UpdatedAt:    clockNow(),
+	}
+
+	setup.Confidence = setup.GetConfidenceLevel()
+	setup.RiskAmount = setup.GetRiskAmount()
+	setup.RewardPotential = setup.GetRewardPotential()
+	setup.RiskRewardRatio = setup.CalculateRiskRewardRatio()
+
+	return setup
+}
+
 // calculatePatternQuality calculates the overall quality score for the pattern
 func (hsds *HeadShouldersDetectionService) calculatePatternQuality(pattern *models.HeadShouldersPattern) float64 {
 	score := 0.0
@@ -441,13 +694,17 @@ func (hsds *HeadShouldersDetectionService) calculatePatternQuality(pattern *mode
 
 	// Volume analysis score (0-15 points)
 	volumeScore := 0.0
-	if pattern.HeadLow.VolumeRatio > 1.2 {
+	head, leftShoulder, rightShoulder := pattern.HeadLow, pattern.LeftShoulderLow, pattern.RightShoulderLow
+	if pattern.IsBearish() {
+		head, leftShoulder, rightShoulder = pattern.HeadHigh, pattern.LeftShoulderHigh, pattern.RightShoulderHigh
+	}
+	if head.VolumeRatio > 1.2 {
 		volumeScore += 8.0
 	}
-	if pattern.LeftShoulderLow.VolumeRatio > 1.0 {
+	if leftShoulder.VolumeRatio > 1.0 {
 		volumeScore += 3.5
 	}
-	if pattern.RightShoulderLow.VolumeRatio > 1.0 {
+	if rightShoulder.VolumeRatio > 1.0 {
 		volumeScore += 3.5
 	}
 	score += volumeScore
@@ -493,6 +750,7 @@ func (hsds *HeadShouldersDetectionService) updatePatternThesis(pattern *models.H
 	}
 
 	previousState := pattern.ThesisComponents
+	previousPhase := pattern.CurrentPhase
 
 	// Check breakout conditions
 	if pattern.CurrentPhase == models.PhaseFormation || pattern.CurrentPhase == models.PhaseBreakout {
@@ -507,25 +765,33 @@ func (hsds *HeadShouldersDetectionService) updatePatternThesis(pattern *models.H
 	// Update completion statistics
 	pattern.ThesisComponents.CalculateCompletion()
 	pattern.ThesisComponents.UpdatePhase()
-	pattern.LastUpdated = time.Now()
+	pattern.LastUpdated = clockNow()
 
 	// Send notifications for newly completed components
 	hsds.sendNotificationsForNewCompletions(pattern, &previousState)
+	emitPhaseChange(hsds.webhooks, pattern.Symbol, previousPhase, pattern.CurrentPhase, currentPrice, pattern)
 
 	return nil
 }
 
 // checkBreakoutConditions checks if neckline breakout has occurred
 func (hsds *HeadShouldersDetectionService) checkBreakoutConditions(pattern *models.HeadShouldersPattern, currentPrice float64) {
-	now := time.Now()
+	now := clockNow()
+
+	// Check neckline breakout (for inverse H&S, need price above neckline; for regular H&S, below it)
+	brokeOut := currentPrice > pattern.NecklineLevel
+	evidence := fmt.Sprintf("Price broke above neckline: $%.2f > $%.2f", currentPrice, pattern.NecklineLevel)
+	if pattern.IsBearish() {
+		brokeOut = currentPrice < pattern.NecklineLevel
+		evidence = fmt.Sprintf("Price broke below neckline: $%.2f < $%.2f", currentPrice, pattern.NecklineLevel)
+	}
 
-	// Check neckline breakout (for inverse H&S, need price above neckline)
-	if currentPrice > pattern.NecklineLevel && !pattern.ThesisComponents.NecklineBreakout.IsCompleted {
+	if brokeOut && !pattern.ThesisComponents.NecklineBreakout.IsCompleted {
 		pattern.ThesisComponents.NecklineBreakout.IsCompleted = true
 		pattern.ThesisComponents.NecklineBreakout.CompletedAt = &now
 		pattern.ThesisComponents.NecklineBreakout.ConfidenceLevel = 85.0
 		pattern.ThesisComponents.NecklineBreakout.Evidence = []string{
-			fmt.Sprintf("Price broke above neckline: $%.2f > $%.2f", currentPrice, pattern.NecklineLevel),
+			evidence,
 			fmt.Sprintf("Breakout confirmed at %s", now.Format("2006-01-02 15:04")),
 		}
 
@@ -540,11 +806,19 @@ func (hsds *HeadShouldersDetectionService) checkBreakoutConditions(pattern *mode
 // checkTargetAchievement checks if price targets have been reached
 func (hsds *HeadShouldersDetectionService) checkTargetAchievement(pattern *models.HeadShouldersPattern, currentPrice float64) {
 	targetPrice := pattern.CalculateTargetPrice()
-	now := time.Now()
+	now := clockNow()
+
+	// Targets lie above the neckline for an inverse pattern and below it for a regular one
+	reached := func(target float64) bool {
+		if pattern.IsBearish() {
+			return currentPrice <= target
+		}
+		return currentPrice >= target
+	}
 
 	// Check partial target 1 (50% of projection)
 	target1 := pattern.NecklineLevel + (targetPrice-pattern.NecklineLevel)*0.5
-	if currentPrice >= target1 && !pattern.ThesisComponents.PartialFillT1.IsCompleted {
+	if reached(target1) && !pattern.ThesisComponents.PartialFillT1.IsCompleted {
 		pattern.ThesisComponents.PartialFillT1.IsCompleted = true
 		pattern.ThesisComponents.PartialFillT1.CompletedAt = &now
 		pattern.ThesisComponents.PartialFillT1.ConfidenceLevel = 90.0
@@ -555,7 +829,7 @@ func (hsds *HeadShouldersDetectionService) checkTargetAchievement(pattern *model
 
 	// Check partial target 2 (75% of projection)
 	target2 := pattern.NecklineLevel + (targetPrice-pattern.NecklineLevel)*0.75
-	if currentPrice >= target2 && !pattern.ThesisComponents.PartialFillT2.IsCompleted {
+	if reached(target2) && !pattern.ThesisComponents.PartialFillT2.IsCompleted {
 		pattern.ThesisComponents.PartialFillT2.IsCompleted = true
 		pattern.ThesisComponents.PartialFillT2.CompletedAt = &now
 		pattern.ThesisComponents.PartialFillT2.ConfidenceLevel = 95.0
@@ -565,7 +839,7 @@ func (hsds *HeadShouldersDetectionService) checkTargetAchievement(pattern *model
 	}
 
 	// Check full target
-	if currentPrice >= targetPrice && !pattern.ThesisComponents.FullTarget.IsCompleted {
+	if reached(targetPrice) && !pattern.ThesisComponents.FullTarget.IsCompleted {
 		pattern.ThesisComponents.FullTarget.IsCompleted = true
 		pattern.ThesisComponents.FullTarget.CompletedAt = &now
 		pattern.ThesisComponents.FullTarget.ConfidenceLevel = 100.0
@@ -610,8 +884,8 @@ func (hsds *HeadShouldersDetectionService) sendNotificationsForNewCompletions(pa
 					Symbol:        pattern.Symbol,
 					ComponentName: component.Name,
 					AlertType:     "component_completed",
-					Message:       fmt.Sprintf("%s completed for %s inverse head and shoulders pattern", component.Name, pattern.Symbol),
-					TriggeredAt:   time.Now(),
+					Message:       fmt.Sprintf("%s completed for %s %s pattern", component.Name, pattern.Symbol, strings.ToLower(pattern.DisplayName())),
+					TriggeredAt:   clockNow(),
 				}
 
 				err := hsds.db.InsertPatternAlert(alert)
@@ -619,6 +893,10 @@ func (hsds *HeadShouldersDetectionService) sendNotificationsForNewCompletions(pa
 					log.Printf("Failed to insert pattern alert: %v", err)
 				}
 
+				if hsds.streaming != nil {
+					hsds.streaming.PublishPatternAlert(alert)
+				}
+
 				// Send email notification if email service is available
 				if hsds.emailService != nil {
 					err = hsds.sendThesisComponentEmail(pattern, component)
@@ -647,42 +925,15 @@ func (hsds *HeadShouldersDetectionService) sendThesisComponentEmail(pattern *mod
 		return fmt.Errorf("email service not available")
 	}
 
-	subject := fmt.Sprintf("📊 %s: %s Component Completed", pattern.Symbol, component.Name)
-
-	// Create email content
-	message := fmt.Sprintf(`
-Pattern: %s - Inverse Head and Shoulders
-Component: %s
-Description: %s
-Current Phase: %s
-Completion: %d/%d components
-
-Evidence:
-%s
-
-Target Price: $%.2f
-Neckline Level: $%.2f
-Completed At: %s
-`,
-		pattern.Symbol,
-		component.Name,
-		component.Description,
-		pattern.CurrentPhase,
-		pattern.ThesisComponents.CompletedComponents,
-		pattern.ThesisComponents.TotalComponents,
-		fmt.Sprintf("- %s", component.Evidence),
-		pattern.CalculateTargetPrice(),
-		pattern.NecklineLevel,
-		component.CompletedAt.Format("2006-01-02 15:04:05"),
-	)
-
-	// Use the existing email service method
-	emailMessage := &EmailMessage{
-		To:      []string{"admin@example.com"}, // TODO: Get from config
-		Subject: subject,
-		Body:    message,
-		IsHTML:  false,
-	}
-
-	return hsds.emailService.SendEmail(emailMessage)
+	return hsds.emailService.SendThesisComponentAlert(&ComponentCompletedEmail{
+		Symbol:              pattern.Symbol,
+		PatternName:         pattern.DisplayName(),
+		Phase:               pattern.CurrentPhase,
+		Component:           component,
+		CompletedComponents: pattern.ThesisComponents.CompletedComponents,
+		TotalComponents:     pattern.ThesisComponents.TotalComponents,
+		TargetPrice:         pattern.CalculateTargetPrice(),
+		LevelName:           "Neckline Level",
+		Level:               pattern.NecklineLevel,
+	}, pattern)
 }
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestHeadShouldersTopDetection tests that a higher peak between two shoulders forms a bearish pattern with a short setup
+func TestHeadShouldersTopDetection(t *testing.T) {
+	service := &HeadShouldersDetectionService{config: models.DefaultHeadShouldersConfig()}
+
+	start := time.Date(2025, 3, 3, 15, 0, 0, 0, time.UTC)
+	day := func(d float64) time.Time { return start.Add(time.Duration(d * 24 * float64(time.Hour))) }
+
+	peaks := []models.PatternPoint{
+		{Timestamp: day(0), Price: 105},    // Left shoulder
+		{Timestamp: day(5), Price: 112},    // Head
+		{Timestamp: day(10), Price: 104.5}, // Right shoulder
+	}
+	troughs := []models.PatternPoint{
+		{Timestamp: day(0.5), Price: 100},
+		{Timestamp: day(2.5), Price: 98},
+		{Timestamp: day(5.5), Price: 99},
+		{Timestamp: day(7.5), Price: 98.5},
+		{Timestamp: day(10.5), Price: 100.5},
+	}
+
+	if service.analyzeInverseHeadShouldersPattern("TEST", peaks, troughs, 1) != nil {
+		t.Error("a head above the shoulders should not form an inverse pattern")
+	}
+
+	pattern := service.analyzeHeadShouldersTopPattern("TEST", peaks, troughs, 1)
+	if pattern == nil {
+		t.Fatal("expected a head and shoulders top")
+	}
+	if !pattern.IsBearish() || pattern.HeadHigh.Price != 112 || pattern.NecklineLevel != 100.25 {
+		t.Errorf("unexpected pattern: type %s, head %.2f, neckline %.2f", pattern.PatternType, pattern.HeadHigh.Price, pattern.NecklineLevel)
+	}
+	if target := pattern.CalculateTargetPrice(); target != 88.5 {
+		t.Errorf("expected a target of 88.50 below the neckline, got %.2f", target)
+	}
+	if !pattern.ThesisComponents.HeadHigherHigh.IsCompleted || pattern.ThesisComponents.TotalComponents != 16 {
+		t.Errorf("unexpected thesis: head higher high %v, %d components", pattern.ThesisComponents.HeadHigherHigh.IsCompleted, pattern.ThesisComponents.TotalComponents)
+	}
+
+	setup, err := service.createTradingSetup(pattern)
+	if err != nil {
+		t.Fatalf("createTradingSetup failed: %v", err)
+	}
+	if setup.Direction != "bearish" || setup.StopLoss <= setup.EntryPrice || setup.Target1 >= setup.EntryPrice || setup.Target3 != 88.5 {
+		t.Errorf("unexpected short setup: entry %.2f, stop %.2f, targets %.2f/%.2f/%.2f", setup.EntryPrice, setup.StopLoss, setup.Target1, setup.Target2, setup.Target3)
+	}
+
+	// Breaking below the neckline starts target pursuit, and falling through the targets completes the pattern
+	service.checkBreakoutConditions(pattern, 100)
+	if pattern.CurrentPhase != models.PhaseTargetPursuit {
+		t.Errorf("expected target pursuit after the neckline break, got %s", pattern.CurrentPhase)
+	}
+	service.checkTargetAchievement(pattern, 88)
+	if !pattern.ThesisComponents.PartialFillT1.IsCompleted || !pattern.IsComplete {
+		t.Error("expected the targets below the neckline to be reached")
+	}
+}
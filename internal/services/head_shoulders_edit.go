@@ -0,0 +1,336 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// editLookbackBars is how many bars before the earliest edited point are read, enough for the volume ratio
+const editLookbackBars = 40
+
+// EditKeyPoints corrects the key points of a stored head and shoulders pattern by hand. Each point moves to
+// the bar nearest its timestamp; the neckline, measurements, thesis and the linked setup's entry, stop and
+// targets are recalculated and the pattern is marked as human-edited, so later scans keep the corrections.
+// The change is recorded in the pattern's audit trail.
+func (hsds *HeadShouldersDetectionService) EditKeyPoints(id int64, req *models.HeadShouldersEditRequest) (*models.HeadShouldersPattern, *models.PatternEdit, error) {
+	pattern, err := hsds.db.GetHeadShouldersPatternByID(id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get pattern: %w", err)
+	}
+	if pattern == nil {
+		return nil, nil, fmt.Errorf("pattern %d %w", id, database.ErrNotFound)
+	}
+	before := *pattern
+
+	points := headShouldersKeyPoints(pattern)
+	requested := map[string]*time.Time{
+		models.KeyPointLeftShoulder:  req.LeftShoulder,
+		models.KeyPointHead:          req.Head,
+		models.KeyPointRightShoulder: req.RightShoulder,
+		models.KeyPointLeftReaction:  req.LeftReaction,
+		models.KeyPointRightReaction: req.RightReaction,
+	}
+	if err := hsds.movePoints(pattern, points, requested, req.Timeframe); err != nil {
+		return nil, nil, err
+	}
+
+	// The neckline follows the reaction points unless it is pinned
+	reactionsMoved := requested[models.KeyPointLeftReaction] != nil || requested[models.KeyPointRightReaction] != nil
+	leftReaction, rightReaction := points[models.KeyPointLeftReaction], points[models.KeyPointRightReaction]
+	switch {
+	case req.NecklineLevel != nil:
+		if *req.NecklineLevel <= 0 {
+			return nil, nil, fmt.Errorf("%w: neckline_level must be positive", ErrValidation)
+		}
+		pattern.NecklineLevel = *req.NecklineLevel
+		pattern.NecklineSlope = 0
+	case reactionsMoved:
+		pattern.NecklineLevel = (leftReaction.Price + rightReaction.Price) / 2
+		pattern.NecklineSlope = 0
+		if hours := rightReaction.Timestamp.Sub(leftReaction.Timestamp).Hours(); hours > 0 {
+			pattern.NecklineSlope = (rightReaction.Price - leftReaction.Price) / hours
+		}
+	}
+	pattern.NecklineTouch1 = *leftReaction
+	pattern.NecklineTouch2 = *rightReaction
+
+	if err := validateKeyPoints(pattern, points, requested); err != nil {
+		return nil, nil, err
+	}
+
+	if req.Annotation != nil {
+		pattern.Annotation = strings.TrimSpace(*req.Annotation)
+	}
+
+	edit := &models.PatternEdit{
+		PatternType: models.PatternFamilyHeadShoulders,
+		PatternID:   pattern.ID,
+		Changes:     diffKeyPoints(&before, pattern),
+		Note:        strings.TrimSpace(req.Note),
+		Editor:      strings.TrimSpace(req.Editor),
+		EditedAt:    clockNow(),
+	}
+	if len(edit.Changes) == 0 {
+		return nil, nil, fmt.Errorf("%w: the edit changes nothing", ErrValidation)
+	}
+
+	pattern.PatternWidth = int64(points[models.KeyPointRightShoulder].Timestamp.Sub(points[models.KeyPointLeftShoulder].Timestamp).Minutes())
+	pattern.PatternHeight = pattern.CalculatePatternHeight()
+	pattern.Symmetry = pattern.GetSymmetryScore()
+	hsds.rebuildThesis(pattern)
+	pattern.HumanEdited = true
+
+	err = hsds.db.WithTx(func(tx *database.DB) error {
+		if err := tx.UpdateHeadShouldersPattern(pattern); err != nil {
+			return err
+		}
+		if err := hsds.updateEditedSetup(tx, pattern); err != nil {
+			return err
+		}
+		return tx.InsertPatternEdit(edit)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pattern, edit, nil
+}
+
+// GetEdits returns the audit trail of manual edits of a pattern, oldest first
+func (hsds *HeadShouldersDetectionService) GetEdits(id int64) ([]*models.PatternEdit, error) {
+	pattern, err := hsds.db.GetHeadShouldersPatternByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pattern: %w", err)
+	}
+	if pattern == nil {
+		return nil, fmt.Errorf("pattern %d %w", id, database.ErrNotFound)
+	}
+	return hsds.db.GetPatternEdits(models.PatternFamilyHeadShoulders, id)
+}
+
+// movePoints moves the requested key points to the bar nearest their timestamps. Shoulders and head take the
+// bar's low on an inverse pattern and its high on a regular one; reactions take the opposite.
+func (hsds *HeadShouldersDetectionService) movePoints(pattern *models.HeadShouldersPattern, points map[string]*models.PatternPoint, requested map[string]*time.Time, timeframe models.Timeframe) error {
+	var earliest, latest time.Time
+	for _, at := range requested {
+		if at == nil {
+			continue
+		}
+		if earliest.IsZero() || at.Before(earliest) {
+			earliest = *at
+		}
+		if at.After(latest) {
+			latest = *at
+		}
+	}
+	if earliest.IsZero() {
+		return nil
+	}
+
+	if timeframe == "" {
+		timeframe = hsds.config.BarInterval
+	}
+	if _, err := models.ParseTimeframe(string(timeframe)); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+	barLength := timeframe.Duration()
+
+	bars, err := hsds.prices.GetPriceDataRangeTimeframe(pattern.Symbol, earliest.Add(-editLookbackBars*barLength), latest.Add(barLength), timeframe)
+	if err != nil {
+		return fmt.Errorf("failed to get price data: %w", err)
+	}
+
+	for key, at := range requested {
+		if at == nil {
+			continue
+		}
+		index := nearestBar(bars, *at, barLength)
+		if index < 0 {
+			return fmt.Errorf("%w: no %s bar of %s near %s for the %s", ErrValidation, timeframe, pattern.Symbol, at.Format(time.RFC3339), strings.ReplaceAll(key, "_", " "))
+		}
+
+		bar := bars[index]
+		useLow := !pattern.IsBearish()
+		if key == models.KeyPointLeftReaction || key == models.KeyPointRightReaction {
+			useLow = !useLow
+		}
+		price := bar.High
+		if useLow {
+			price = bar.Low
+		}
+
+		*points[key] = models.PatternPoint{
+			Timestamp:   bar.Timestamp,
+			Price:       price,
+			Volume:      bar.Volume,
+			VolumeRatio: hsds.calculateVolumeRatio(bars, index),
+		}
+	}
+	return nil
+}
+
+// rebuildThesis re-evaluates the formation components after an edit, keeping the breakout and target progress
+// monitoring has already recorded
+func (hsds *HeadShouldersDetectionService) rebuildThesis(pattern *models.HeadShouldersPattern) {
+	previous := pattern.ThesisComponents
+
+	pattern.ThesisComponents.InitializeThesis(pattern.PatternType)
+	hsds.evaluateInitialThesis(pattern)
+
+	thesis := &pattern.ThesisComponents
+	thesis.NecklineRetest = previous.NecklineRetest
+	thesis.NecklineBreakout = previous.NecklineBreakout
+	thesis.BreakoutVolume = previous.BreakoutVolume
+	thesis.PartialFillT1 = previous.PartialFillT1
+	thesis.PartialFillT2 = previous.PartialFillT2
+	thesis.FullTarget = previous.FullTarget
+	thesis.CalculateCompletion()
+	thesis.UpdatePhase()
+}
+
+// updateEditedSetup recalculates the entry, stop and targets of the pattern's trading setup
+func (hsds *HeadShouldersDetectionService) updateEditedSetup(tx *database.DB, pattern *models.HeadShouldersPattern) error {
+	if pattern.SetupID == 0 {
+		return nil
+	}
+	setup, err := tx.GetTradingSetupByID(pattern.SetupID)
+	if err != nil {
+		return fmt.Errorf("failed to get trading setup: %w", err)
+	}
+	if setup == nil {
+		return nil
+	}
+
+	recalculated, err := hsds.createTradingSetup(pattern)
+	if err != nil {
+		return err
+	}
+	setup.QualityScore = recalculated.QualityScore
+	setup.Confidence = recalculated.Confidence
+	setup.EntryPrice = recalculated.EntryPrice
+	setup.StopLoss = recalculated.StopLoss
+	setup.Target1 = recalculated.Target1
+	setup.Target2 = recalculated.Target2
+	setup.Target3 = recalculated.Target3
+	setup.RiskAmount = recalculated.RiskAmount
+	setup.RewardPotential = recalculated.RewardPotential
+	setup.RiskRewardRatio = recalculated.RiskRewardRatio
+
+	return tx.UpdateTradingSetup(setup)
+}
+
+// headShouldersKeyPoints maps the editable key points to the pattern's fields: the shoulders and head are the
+// swing lows of an inverse pattern and the swing highs of a regular one
+func headShouldersKeyPoints(pattern *models.HeadShouldersPattern) map[string]*models.PatternPoint {
+	if pattern.IsBearish() {
+		return map[string]*models.PatternPoint{
+			models.KeyPointLeftShoulder:  &pattern.LeftShoulderHigh,
+			models.KeyPointHead:          &pattern.HeadHigh,
+			models.KeyPointRightShoulder: &pattern.RightShoulderHigh,
+			models.KeyPointLeftReaction:  &pattern.LeftShoulderLow,
+			models.KeyPointRightReaction: &pattern.RightShoulderLow,
+		}
+	}
+	return map[string]*models.PatternPoint{
+		models.KeyPointLeftShoulder:  &pattern.LeftShoulderLow,
+		models.KeyPointHead:          &pattern.HeadLow,
+		models.KeyPointRightShoulder: &pattern.RightShoulderLow,
+		models.KeyPointLeftReaction:  &pattern.LeftShoulderHigh,
+		models.KeyPointRightReaction: &pattern.RightShoulderHigh,
+	}
+}
+
+// validateKeyPoints checks that the edited points still form the pattern: shoulders either side of the head,
+// moved reactions between a shoulder and the head, and the head beyond both shoulders and the neckline
+func validateKeyPoints(pattern *models.HeadShouldersPattern, points map[string]*models.PatternPoint, requested map[string]*time.Time) error {
+	leftShoulder, head, rightShoulder := points[models.KeyPointLeftShoulder], points[models.KeyPointHead], points[models.KeyPointRightShoulder]
+
+	if !leftShoulder.Timestamp.Before(head.Timestamp) || !head.Timestamp.Before(rightShoulder.Timestamp) {
+		return fmt.Errorf("%w: the head must come after the left shoulder and before the right shoulder", ErrValidation)
+	}
+	if requested[models.KeyPointLeftReaction] != nil {
+		if at := points[models.KeyPointLeftReaction].Timestamp; !at.After(leftShoulder.Timestamp) || !at.Before(head.Timestamp) {
+			return fmt.Errorf("%w: the left reaction must come between the left shoulder and the head", ErrValidation)
+		}
+	}
+	if requested[models.KeyPointRightReaction] != nil {
+		if at := points[models.KeyPointRightReaction].Timestamp; !at.After(head.Timestamp) || !at.Before(rightShoulder.Timestamp) {
+			return fmt.Errorf("%w: the right reaction must come between the head and the right shoulder", ErrValidation)
+		}
+	}
+
+	if pattern.IsBearish() {
+		if head.Price <= leftShoulder.Price || head.Price <= rightShoulder.Price {
+			return fmt.Errorf("%w: the head high ($%.2f) must be above both shoulders", ErrValidation, head.Price)
+		}
+		if pattern.NecklineLevel >= head.Price {
+			return fmt.Errorf("%w: the neckline ($%.2f) must be below the head", ErrValidation, pattern.NecklineLevel)
+		}
+		return nil
+	}
+
+	if head.Price >= leftShoulder.Price || head.Price >= rightShoulder.Price {
+		return fmt.Errorf("%w: the head low ($%.2f) must be below both shoulders", ErrValidation, head.Price)
+	}
+	if pattern.NecklineLevel <= head.Price {
+		return fmt.Errorf("%w: the neckline ($%.2f) must be above the head", ErrValidation, pattern.NecklineLevel)
+	}
+	return nil
+}
+
+// diffKeyPoints describes the key points, neckline and annotation that differ between two versions of a pattern
+func diffKeyPoints(before, after *models.HeadShouldersPattern) map[string]models.PatternEditDiff {
+	changes := make(map[string]models.PatternEditDiff)
+
+	beforePoints, afterPoints := headShouldersKeyPoints(before), headShouldersKeyPoints(after)
+	for key, from := range beforePoints {
+		to := afterPoints[key]
+		if from.Timestamp.Equal(to.Timestamp) && from.Price == to.Price {
+			continue
+		}
+		changes[key] = models.PatternEditDiff{From: formatKeyPoint(*from), To: formatKeyPoint(*to)}
+	}
+
+	if before.NecklineLevel != after.NecklineLevel || before.NecklineSlope != after.NecklineSlope {
+		changes[models.KeyPointNeckline] = models.PatternEditDiff{
+			From: fmt.Sprintf("$%.2f (slope %.4f)", before.NecklineLevel, before.NecklineSlope),
+			To:   fmt.Sprintf("$%.2f (slope %.4f)", after.NecklineLevel, after.NecklineSlope),
+		}
+	}
+	if before.Annotation != after.Annotation {
+		changes[models.KeyPointAnnotation] = models.PatternEditDiff{From: before.Annotation, To: after.Annotation}
+	}
+
+	return changes
+}
+
+// formatKeyPoint formats a key point for the audit trail, e.g. "2025-03-04 10:00 @ $101.20"
+func formatKeyPoint(point models.PatternPoint) string {
+	if point.Timestamp.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%s @ $%.2f", point.Timestamp.Format("2006-01-02 15:04"), point.Price)
+}
+
+// nearestBar returns the index of the bar closest to a timestamp, or -1 when none is within one bar length
+func nearestBar(bars []*models.PriceData, at time.Time, barLength time.Duration) int {
+	best := -1
+	var bestDistance time.Duration
+	for i, bar := range bars {
+		distance := bar.Timestamp.Sub(at)
+		if distance < 0 {
+			distance = -distance
+		}
+		if distance > barLength {
+			continue
+		}
+		if best < 0 || distance < bestDistance {
+			best, bestDistance = i, distance
+		}
+	}
+	return best
+}
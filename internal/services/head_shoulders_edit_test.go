@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// TestEditKeyPoints tests that a corrected right shoulder and a pinned neckline recalculate the pattern and its
+// setup, mark it as human-edited and are kept in its audit trail
+func TestEditKeyPoints(t *testing.T) {
+	db := newTestDB(t)
+
+	start := time.Date(2025, 3, 4, 15, 0, 0, 0, time.UTC)
+	minute := func(i int) time.Time { return start.Add(time.Duration(i) * time.Minute) }
+
+	// A head and shoulders top on one-minute bars; the detector took the high at 90 for the right shoulder
+	highs := map[int]float64{20: 105, 50: 112, 80: 106, 90: 104.5}
+	lows := map[int]float64{30: 98, 70: 98.5}
+	bars := make([]*models.PriceData, 0, 120)
+	for i := 0; i < 120; i++ {
+		bar := &models.PriceData{Symbol: "TEST", Timestamp: minute(i), Open: 100, High: 101, Low: 99, Close: 100, Volume: 1000}
+		if high, ok := highs[i]; ok {
+			bar.High = high
+		}
+		if low, ok := lows[i]; ok {
+			bar.Low = low
+		}
+		bars = append(bars, bar)
+	}
+	if err := db.InsertPriceDataBatch(bars); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+
+	service := NewHeadShouldersDetectionService(db, nil, nil, nil)
+	pattern := &models.HeadShouldersPattern{
+		Symbol:            "TEST",
+		PatternType:       models.SetupTypeHeadShoulders,
+		LeftShoulderHigh:  models.PatternPoint{Timestamp: minute(20), Price: 105},
+		LeftShoulderLow:   models.PatternPoint{Timestamp: minute(30), Price: 98},
+		HeadHigh:          models.PatternPoint{Timestamp: minute(50), Price: 112},
+		RightShoulderLow:  models.PatternPoint{Timestamp: minute(70), Price: 98.5},
+		RightShoulderHigh: models.PatternPoint{Timestamp: minute(90), Price: 104.5},
+		NecklineLevel:     98.25,
+		DetectedAt:        minute(100),
+		CurrentPhase:      models.PhaseFormation,
+	}
+	pattern.PatternHeight = pattern.CalculatePatternHeight()
+	pattern.ThesisComponents.InitializeThesis(pattern.PatternType)
+	setup, err := service.createTradingSetup(pattern)
+	if err != nil {
+		t.Fatalf("createTradingSetup failed: %v", err)
+	}
+	if err := db.InsertTradingSetup(setup); err != nil {
+		t.Fatalf("InsertTradingSetup failed: %v", err)
+	}
+	pattern.SetupID = setup.ID
+	if err := db.InsertHeadShouldersPattern(pattern); err != nil {
+		t.Fatalf("InsertHeadShouldersPattern failed: %v", err)
+	}
+
+	// The right shoulder snaps to the nearest bar and takes its high
+	rightShoulder := minute(80).Add(20 * time.Second)
+	edited, edit, err := service.EditKeyPoints(pattern.ID, &models.HeadShouldersEditRequest{RightShoulder: &rightShoulder, Note: "wrong shoulder", Editor: "alice"})
+	if err != nil {
+		t.Fatalf("EditKeyPoints failed: %v", err)
+	}
+	if !edited.HumanEdited || edited.RightShoulderHigh.Price != 106 || !edited.RightShoulderHigh.Timestamp.Equal(minute(80)) || edited.PatternWidth != 60 {
+		t.Errorf("unexpected edited pattern: edited %v, right shoulder %+v, width %d", edited.HumanEdited, edited.RightShoulderHigh, edited.PatternWidth)
+	}
+	if len(edit.Changes) != 1 || edit.Changes[models.KeyPointRightShoulder].To != "2025-03-04 16:20 @ $106.00" {
+		t.Errorf("unexpected edit changes: %+v", edit.Changes)
+	}
+
+	// Pinning the neckline moves the setup's entry and targets
+	neckline, annotation := 99.0, "neckline from the daily chart"
+	if _, _, err := service.EditKeyPoints(pattern.ID, &models.HeadShouldersEditRequest{NecklineLevel: &neckline, Annotation: &annotation}); err != nil {
+		t.Fatalf("EditKeyPoints failed: %v", err)
+	}
+	stored, err := db.GetHeadShouldersPatternByID(pattern.ID)
+	if err != nil || stored == nil {
+		t.Fatalf("failed to reload the pattern: %v", err)
+	}
+	if !stored.HumanEdited || stored.NecklineLevel != 99 || stored.Annotation != annotation || stored.CalculateTargetPrice() != 86 {
+		t.Errorf("unexpected stored pattern: edited %v, neckline %.2f, annotation %q, target %.2f", stored.HumanEdited, stored.NecklineLevel, stored.Annotation, stored.CalculateTargetPrice())
+	}
+	updatedSetup, err := db.GetTradingSetupByID(setup.ID)
+	if err != nil || updatedSetup == nil {
+		t.Fatalf("failed to reload the setup: %v", err)
+	}
+	if updatedSetup.EntryPrice != 99*0.998 || updatedSetup.Target3 != 86 {
+		t.Errorf("expected the setup to follow the neckline, got entry %.2f and target %.2f", updatedSetup.EntryPrice, updatedSetup.Target3)
+	}
+
+	// A head below a shoulder no longer forms the pattern
+	head := minute(80)
+	if _, _, err := service.EditKeyPoints(pattern.ID, &models.HeadShouldersEditRequest{Head: &head}); !errors.Is(err, ErrValidation) {
+		t.Errorf("expected a validation error for a head below the shoulders, got %v", err)
+	}
+
+	edits, err := service.GetEdits(pattern.ID)
+	if err != nil {
+		t.Fatalf("GetEdits failed: %v", err)
+	}
+	if len(edits) != 2 || edits[0].Editor != "alice" || edits[1].Changes[models.KeyPointAnnotation].To != annotation {
+		t.Errorf("unexpected audit trail: %+v", edits)
+	}
+
+	if _, err := service.GetEdits(pattern.ID + 1); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("expected not found for a missing pattern, got %v", err)
+	}
+}
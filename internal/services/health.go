@@ -0,0 +1,177 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// providerCheckInterval is how long a provider check is reused; each check is a request against the quota
+const providerCheckInterval = time.Minute
+
+// HealthService checks the server's dependencies for the liveness and readiness probes
+type HealthService struct {
+	db        *database.Database
+	provider  MarketDataProvider
+	collector *CollectorService
+	startedAt time.Time
+
+	providerCheck *models.DependencyCheck // last provider check, reused for providerCheckInterval
+	mutex         sync.Mutex
+}
+
+// NewHealthService creates a new health service
+func NewHealthService(db *database.Database, provider MarketDataProvider, collector *CollectorService) *HealthService {
+	return &HealthService{
+		db:        db,
+		provider:  provider,
+		collector: collector,
+		startedAt: time.Now(),
+	}
+}
+
+// Liveness reports that the process is up; it checks no dependencies
+func (hs *HealthService) Liveness() *models.LivenessReport {
+	now := time.Now()
+	return &models.LivenessReport{
+		Status:        models.CheckStatusOK,
+		Timestamp:     now,
+		StartedAt:     hs.startedAt,
+		UptimeSeconds: int64(now.Sub(hs.startedAt).Seconds()),
+	}
+}
+
+// Readiness checks the database, the market data provider and its quota, the collector's recent runs and the
+// collection scheduler. The server is not ready while the database or the scheduler fails; a failing provider
+// or collector only degrades it, as stored data can still be served.
+func (hs *HealthService) Readiness() *models.ReadinessReport {
+	report := &models.ReadinessReport{
+		Status:    models.ReadinessReady,
+		Ready:     true,
+		Timestamp: time.Now(),
+		Checks: []*models.DependencyCheck{
+			hs.checkDatabase(),
+			hs.checkProvider(),
+			hs.checkCollector(),
+			hs.checkScheduler(),
+		},
+	}
+
+	for _, check := range report.Checks {
+		switch {
+		case check.Status == models.CheckStatusOK:
+		case check.Critical && check.Status == models.CheckStatusError:
+			report.Status = models.ReadinessNotReady
+			report.Ready = false
+		case report.Ready:
+			report.Status = models.ReadinessDegraded
+		}
+	}
+
+	return report
+}
+
+// runCheck times a dependency check, recording its error as the check's message
+func runCheck(name string, critical bool, check func() error) *models.DependencyCheck {
+	start := time.Now()
+	result := &models.DependencyCheck{
+		Name:      name,
+		Status:    models.CheckStatusOK,
+		Critical:  critical,
+		CheckedAt: start,
+	}
+	if err := check(); err != nil {
+		result.Status = models.CheckStatusError
+		result.Message = err.Error()
+	}
+	result.LatencyMS = time.Since(start).Milliseconds()
+	return result
+}
+
+// checkDatabase pings the database
+func (hs *HealthService) checkDatabase() *models.DependencyCheck {
+	return runCheck("database", true, hs.db.HealthCheck)
+}
+
+// checkProvider checks the provider is reachable, reusing a recent result, and that its request budget is not
+// used up or backing off
+func (hs *HealthService) checkProvider() *models.DependencyCheck {
+	hs.mutex.Lock()
+	cached := hs.providerCheck
+	if cached == nil || time.Since(cached.CheckedAt) >= providerCheckInterval {
+		hs.providerCheck = runCheck("provider", false, hs.provider.HealthCheck)
+		cached = nil
+	}
+	check := *hs.providerCheck
+	hs.mutex.Unlock()
+
+	check.Cached = cached != nil
+	check.Details = map[string]interface{}{"name": hs.provider.Name()}
+
+	limited, ok := hs.provider.(RateLimitedProvider)
+	if !ok {
+		return &check
+	}
+	stats := limited.RateLimitStats()
+	check.Details = map[string]interface{}{"name": hs.provider.Name(), "rate_limit": stats}
+	if check.Status != models.CheckStatusOK {
+		return &check
+	}
+	switch {
+	case stats.DailyBudget > 0 && stats.BudgetRemaining == 0:
+		check.Status = models.CheckStatusDegraded
+		check.Message = fmt.Sprintf("daily request budget of %d exhausted", stats.DailyBudget)
+	case stats.BackoffUntil != nil:
+		check.Status = models.CheckStatusDegraded
+		check.Message = fmt.Sprintf("rate limited, backing off until %s", stats.BackoffUntil.Format(time.RFC3339))
+	}
+	return &check
+}
+
+// checkCollector checks the collector ran recently and mostly succeeded
+func (hs *HealthService) checkCollector() *models.DependencyCheck {
+	check := runCheck("collector", false, hs.collector.HealthCheck)
+	if check.Status == models.CheckStatusError {
+		check.Status = models.CheckStatusDegraded
+	}
+
+	stats := hs.collector.GetStats()
+	details := map[string]interface{}{
+		"successful_runs": stats.SuccessfulRuns,
+		"failed_runs":     stats.FailedRuns,
+		"last_error":      stats.LastError,
+	}
+	if !stats.LastRun.IsZero() {
+		details["last_run"] = stats.LastRun
+	}
+	check.Details = details
+	return check
+}
+
+// checkScheduler checks the collection schedule is running; replays step the collector themselves
+func (hs *HealthService) checkScheduler() *models.DependencyCheck {
+	if _, replay := hs.provider.(*ReplayProvider); replay {
+		check := runCheck("scheduler", true, func() error { return nil })
+		check.Message = "replay steps the collector"
+		return check
+	}
+
+	var next time.Time
+	check := runCheck("scheduler", true, func() error {
+		next = hs.collector.NextScheduledRun()
+		switch {
+		case next.IsZero():
+			return fmt.Errorf("collection is not scheduled")
+		case next.Before(time.Now().Add(-time.Minute)):
+			return fmt.Errorf("scheduler stopped; the next collection was due at %s", next.Format(time.RFC3339))
+		}
+		return nil
+	})
+	if !next.IsZero() {
+		check.Details = map[string]interface{}{"next_run": next}
+	}
+	return check
+}
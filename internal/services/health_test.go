@@ -0,0 +1,68 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// healthProvider counts health checks and reports fixed rate limit stats; it serves no bars
+type healthProvider struct {
+	MarketDataProvider
+	checks int
+	err    error
+	stats  *RateLimitStats
+}
+
+func (p *healthProvider) Name() string                    { return "test" }
+func (p *healthProvider) HealthCheck() error              { p.checks++; return p.err }
+func (p *healthProvider) RateLimitStats() *RateLimitStats { return p.stats }
+
+// TestHealthReadiness tests the critical and degrading checks, and that provider checks are reused
+func TestHealthReadiness(t *testing.T) {
+	cfg := &config.Config{}
+	db := newTestDB(t)
+
+	provider := &healthProvider{stats: &RateLimitStats{DailyBudget: 100, BudgetRemaining: 0}}
+	hs := NewHealthService(db, provider, NewCollectorService(db, provider, cfg))
+
+	checks := func(report *models.ReadinessReport) map[string]*models.DependencyCheck {
+		byName := make(map[string]*models.DependencyCheck)
+		for _, check := range report.Checks {
+			byName[check.Name] = check
+		}
+		return byName
+	}
+
+	// Without a running collection schedule the server is not ready
+	report := hs.Readiness()
+	if report.Ready || report.Status != models.ReadinessNotReady {
+		t.Errorf("expected not ready without a schedule, got %s", report.Status)
+	}
+	byName := checks(report)
+	if byName["database"].Status != models.CheckStatusOK || byName["scheduler"].Status != models.CheckStatusError {
+		t.Errorf("unexpected checks: database %+v, scheduler %+v", byName["database"], byName["scheduler"])
+	}
+	if byName["provider"].Status != models.CheckStatusDegraded || byName["provider"].Critical {
+		t.Errorf("expected an exhausted budget to degrade the provider, got %+v", byName["provider"])
+	}
+
+	// The provider is checked once a minute at most
+	provider.err = errors.New("unreachable")
+	byName = checks(hs.Readiness())
+	if provider.checks != 1 || !byName["provider"].Cached || byName["provider"].Status != models.CheckStatusDegraded {
+		t.Errorf("expected the earlier provider check to be reused, got %d checks and %+v", provider.checks, byName["provider"])
+	}
+
+	hs.providerCheck = nil
+	byName = checks(hs.Readiness())
+	if provider.checks != 2 || byName["provider"].Status != models.CheckStatusError || byName["provider"].Message != "unreachable" {
+		t.Errorf("expected an unreachable provider, got %d checks and %+v", provider.checks, byName["provider"])
+	}
+
+	if live := hs.Liveness(); live.Status != models.CheckStatusOK {
+		t.Errorf("expected the server to be live, got %s", live.Status)
+	}
+}
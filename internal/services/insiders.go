@@ -0,0 +1,585 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// Insider defaults
+const (
+	DefaultInsidersRefreshInterval = 6 * time.Hour
+	DefaultSECURL                  = "https://www.sec.gov"
+	DefaultSECDataURL              = "https://data.sec.gov"
+	defaultInsiderLookbackDays     = 90
+	defaultInsiderClusterBuyers    = 3
+	defaultInsiderClusterDays      = 14
+)
+
+// secRequestsPerMinute keeps EDGAR requests under the SEC's fair access limit of 10 per second
+const secRequestsPerMinute = 480
+
+// InsiderService ingests SEC Form 4 insider transactions of watched stocks and flags cluster buying
+type InsiderService struct {
+	cfg           *config.Config
+	db            *database.Database
+	client        *http.Client
+	limiter       *RateLimiter
+	notifications *NotificationService
+	schedules     *ScheduleControl
+	enabled       bool
+	interval      time.Duration
+	lookback      int
+	clusterBuyers int
+	clusterDays   int
+	alertClusters bool
+	userAgent     string
+	secURL        string
+	secDataURL    string
+
+	ciksMutex sync.Mutex
+	ciks      map[string]string // Ticker to zero padded CIK, loaded once
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup // tracks the background refresh loop
+}
+
+// secSubmissions represents the recent filings of an EDGAR submissions response, as parallel arrays
+type secSubmissions struct {
+	Filings struct {
+		Recent struct {
+			AccessionNumber []string `json:"accessionNumber"`
+			FilingDate      []string `json:"filingDate"`
+			Form            []string `json:"form"`
+			PrimaryDocument []string `json:"primaryDocument"`
+		} `json:"recent"`
+	} `json:"filings"`
+}
+
+// form4Filing is a Form 4 filing listed in a company's submissions
+type form4Filing struct {
+	AccessionNumber string
+	FilingDate      string
+	PrimaryDocument string
+}
+
+// form4Value is a Form 4 element holding its value in a value child
+type form4Value struct {
+	Value string `xml:"value"`
+}
+
+// form4Document represents the parts of a Form 4 ownership document that are stored
+type form4Document struct {
+	Owners []struct {
+		Name         string `xml:"reportingOwnerId>rptOwnerName"`
+		Relationship struct {
+			IsDirector        string `xml:"isDirector"`
+			IsOfficer         string `xml:"isOfficer"`
+			IsTenPercentOwner string `xml:"isTenPercentOwner"`
+			OfficerTitle      string `xml:"officerTitle"`
+		} `xml:"reportingOwnerRelationship"`
+	} `xml:"reportingOwner"`
+	NonDerivative []form4Transaction `xml:"nonDerivativeTable>nonDerivativeTransaction"`
+	Derivative    []form4Transaction `xml:"derivativeTable>derivativeTransaction"`
+}
+
+// form4Transaction represents a transaction row of a Form 4 table
+type form4Transaction struct {
+	SecurityTitle    form4Value `xml:"securityTitle"`
+	Date             form4Value `xml:"transactionDate"`
+	Code             string     `xml:"transactionCoding>transactionCode"`
+	Shares           form4Value `xml:"transactionAmounts>transactionShares"`
+	Price            form4Value `xml:"transactionAmounts>transactionPricePerShare"`
+	AcquiredDisposed form4Value `xml:"transactionAmounts>transactionAcquiredDisposedCode"`
+	SharesOwnedAfter form4Value `xml:"postTransactionAmounts>sharesOwnedFollowingTransaction"`
+}
+
+// NewInsiderService creates a new insider transaction service
+func NewInsiderService(cfg *config.Config, db *database.Database) *InsiderService {
+	settings := cfg.Insiders
+
+	interval := settings.RefreshInterval
+	if interval <= 0 {
+		interval = DefaultInsidersRefreshInterval
+	}
+
+	lookback := settings.LookbackDays
+	if lookback <= 0 {
+		lookback = defaultInsiderLookbackDays
+	}
+
+	clusterBuyers := settings.ClusterBuyers
+	if clusterBuyers <= 0 {
+		clusterBuyers = defaultInsiderClusterBuyers
+	}
+
+	clusterDays := settings.ClusterDays
+	if clusterDays <= 0 {
+		clusterDays = defaultInsiderClusterDays
+	}
+
+	secURL := strings.TrimSuffix(settings.SECURL, "/")
+	if secURL == "" {
+		secURL = DefaultSECURL
+	}
+
+	secDataURL := strings.TrimSuffix(settings.SECDataURL, "/")
+	if secDataURL == "" {
+		secDataURL = DefaultSECDataURL
+	}
+
+	return &InsiderService{
+		cfg:           cfg,
+		db:            db,
+		client:        &http.Client{Timeout: 30 * time.Second},
+		limiter:       NewRateLimiter(secRequestsPerMinute, secRequestsPerMinute/60, 0),
+		enabled:       settings.Enabled,
+		interval:      interval,
+		lookback:      lookback,
+		clusterBuyers: clusterBuyers,
+		clusterDays:   clusterDays,
+		alertClusters: settings.AlertClusterBuying,
+		userAgent:     settings.UserAgent,
+		secURL:        secURL,
+		secDataURL:    secDataURL,
+		stop:          make(chan struct{}),
+	}
+}
+
+// SetNotificationService sets the notification center cluster buying alerts are recorded in
+func (is *InsiderService) SetNotificationService(notifications *NotificationService) {
+	is.notifications = notifications
+}
+
+// SetScheduleControl sets the control that can disable the insider ingestion
+func (is *InsiderService) SetScheduleControl(schedules *ScheduleControl) {
+	is.schedules = schedules
+}
+
+// Start fetches filings now and then periodically when insider ingestion is enabled
+func (is *InsiderService) Start() {
+	if !is.enabled {
+		log.Printf("Insider transaction ingestion disabled")
+		return
+	}
+	if is.userAgent == "" {
+		log.Printf("Insider transaction ingestion disabled: insiders.user_agent must name a contact for SEC EDGAR")
+		return
+	}
+
+	log.Printf("Starting insider transaction ingestion (every %v)...", is.interval)
+
+	is.wg.Add(1)
+	go func() {
+		defer is.wg.Done()
+
+		ticker := time.NewTicker(is.interval)
+		defer ticker.Stop()
+
+		for {
+			is.scheduledRefresh()
+
+			select {
+			case <-ticker.C:
+			case <-is.stop:
+				return
+			}
+		}
+	}()
+}
+
+// scheduledRefresh ingests new filings unless the insiders schedule is disabled
+func (is *InsiderService) scheduledRefresh() {
+	if is.schedules.Skip(models.ScheduleInsiders) {
+		return
+	}
+	if err := is.RefreshAll(); err != nil {
+		log.Printf("Insider transaction refresh failed: %v", err)
+	}
+}
+
+// Stop stops the refresh loop and waits for an in-flight refresh to finish
+func (is *InsiderService) Stop(ctx context.Context) error {
+	is.stopOnce.Do(func() { close(is.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		is.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Printf("Insider service stopped")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for insider refresh to finish: %w", ctx.Err())
+	}
+}
+
+// RefreshAll ingests the new Form 4 filings of every watched stock
+func (is *InsiderService) RefreshAll() error {
+	symbols, err := is.db.GetWatchedSymbols()
+	if err != nil {
+		return fmt.Errorf("failed to get watched symbols: %w", err)
+	}
+
+	stored, refreshed := 0, 0
+	for _, symbol := range symbols {
+		if !models.IsEquity(symbol) {
+			continue
+		}
+		count, err := is.RefreshSymbol(symbol)
+		if err != nil {
+			log.Printf("Failed to refresh insider transactions for %s: %v", symbol, err)
+			continue
+		}
+		stored += count
+		refreshed++
+	}
+
+	log.Printf("Insider transactions refreshed: %d transactions for %d symbols", stored, refreshed)
+	return nil
+}
+
+// RefreshSymbol fetches and stores a stock's Form 4 filings within the lookback days not fetched yet, then
+// alerts when they complete cluster buying. It returns the number of transactions stored.
+func (is *InsiderService) RefreshSymbol(symbol string) (int, error) {
+	if is.userAgent == "" {
+		return 0, fmt.Errorf("%w: insiders.user_agent must name a contact for SEC EDGAR", ErrUnavailable)
+	}
+
+	cik, err := is.lookupCIK(symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	filings, err := is.fetchForm4Filings(cik, clockNow().AddDate(0, 0, -is.lookback).Format("2006-01-02"))
+	if err != nil {
+		return 0, err
+	}
+	fetched, err := is.db.GetInsiderFilings(symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	stored, newPurchases := 0, false
+	for _, filing := range filings {
+		if fetched[filing.AccessionNumber] {
+			continue
+		}
+
+		transactions, err := is.fetchForm4(symbol, cik, filing)
+		if err != nil {
+			log.Printf("Failed to fetch Form 4 %s for %s: %v", filing.AccessionNumber, symbol, err)
+			continue
+		}
+		if err := is.db.UpsertInsiderTransactions(symbol, filing.AccessionNumber, filing.FilingDate, transactions); err != nil {
+			return stored, err
+		}
+
+		stored += len(transactions)
+		for _, t := range transactions {
+			newPurchases = newPurchases || t.IsOpenMarketPurchase()
+		}
+	}
+
+	if newPurchases {
+		is.checkClusterBuying(symbol)
+	}
+	return stored, nil
+}
+
+// lookupCIK returns the zero padded SEC CIK of a ticker, loading EDGAR's ticker list the first time
+func (is *InsiderService) lookupCIK(symbol string) (string, error) {
+	is.ciksMutex.Lock()
+	defer is.ciksMutex.Unlock()
+
+	if is.ciks == nil {
+		var tickers map[string]struct {
+			CIK    int64  `json:"cik_str"`
+			Ticker string `json:"ticker"`
+		}
+		if err := is.getJSON(is.secURL+"/files/company_tickers.json", &tickers); err != nil {
+			return "", fmt.Errorf("failed to load SEC tickers: %w", err)
+		}
+
+		is.ciks = make(map[string]string, len(tickers))
+		for _, ticker := range tickers {
+			// EDGAR lists share classes with a dash, BRK-B rather than BRK.B
+			is.ciks[strings.ReplaceAll(strings.ToUpper(ticker.Ticker), "-", ".")] = fmt.Sprintf("%010d", ticker.CIK)
+		}
+	}
+
+	cik, ok := is.ciks[symbol]
+	if !ok {
+		return "", fmt.Errorf("SEC registrant for %s %w", symbol, database.ErrNotFound)
+	}
+	return cik, nil
+}
+
+// fetchForm4Filings lists a company's Form 4 filings since a date from its recent EDGAR submissions
+func (is *InsiderService) fetchForm4Filings(cik, since string) ([]form4Filing, error) {
+	var submissions secSubmissions
+	if err := is.getJSON(fmt.Sprintf("%s/submissions/CIK%s.json", is.secDataURL, cik), &submissions); err != nil {
+		return nil, fmt.Errorf("failed to fetch SEC submissions: %w", err)
+	}
+
+	recent := submissions.Filings.Recent
+	filings := make([]form4Filing, 0)
+	for i, form := range recent.Form {
+		if form != "4" || i >= len(recent.AccessionNumber) || i >= len(recent.FilingDate) || i >= len(recent.PrimaryDocument) {
+			continue
+		}
+		if recent.FilingDate[i] < since {
+			continue
+		}
+		filings = append(filings, form4Filing{
+			AccessionNumber: recent.AccessionNumber[i],
+			FilingDate:      recent.FilingDate[i],
+			PrimaryDocument: recent.PrimaryDocument[i],
+		})
+	}
+	return filings, nil
+}
+
+// fetchForm4 fetches a Form 4 filing's ownership document and returns its transactions
+func (is *InsiderService) fetchForm4(symbol, cik string, filing form4Filing) ([]*models.InsiderTransaction, error) {
+	// The listed primary document is rendered by an XSL stylesheet directory; the raw XML sits beside it
+	cikNumber, _ := strconv.ParseInt(cik, 10, 64)
+	url := fmt.Sprintf("%s/Archives/edgar/data/%d/%s/%s", is.secURL, cikNumber,
+		strings.ReplaceAll(filing.AccessionNumber, "-", ""), path.Base(filing.PrimaryDocument))
+
+	body, err := is.get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return parseForm4(body, symbol, filing)
+}
+
+// parseForm4 parses the transactions of a Form 4 ownership document, attributed to its reporting owners
+func parseForm4(r io.Reader, symbol string, filing form4Filing) ([]*models.InsiderTransaction, error) {
+	var doc form4Document
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode Form 4: %w", err)
+	}
+
+	flag := func(value string) bool {
+		value = strings.TrimSpace(value)
+		return value == "1" || strings.EqualFold(value, "true")
+	}
+
+	owner := &models.InsiderTransaction{}
+	names, titles := make([]string, 0, len(doc.Owners)), make([]string, 0, 3)
+	for _, o := range doc.Owners {
+		names = append(names, strings.TrimSpace(o.Name))
+		owner.IsDirector = owner.IsDirector || flag(o.Relationship.IsDirector)
+		owner.IsOfficer = owner.IsOfficer || flag(o.Relationship.IsOfficer)
+		owner.IsTenPercentOwner = owner.IsTenPercentOwner || flag(o.Relationship.IsTenPercentOwner)
+		if title := strings.TrimSpace(o.Relationship.OfficerTitle); title != "" && !slices.Contains(titles, title) {
+			titles = append(titles, title)
+		}
+	}
+	if owner.IsDirector {
+		titles = append(titles, "Director")
+	}
+	if owner.IsTenPercentOwner {
+		titles = append(titles, "10% Owner")
+	}
+
+	transactions := make([]*models.InsiderTransaction, 0, len(doc.NonDerivative)+len(doc.Derivative))
+	add := func(row form4Transaction, derivative bool) {
+		shares, _ := strconv.ParseFloat(strings.TrimSpace(row.Shares.Value), 64)
+		price, _ := strconv.ParseFloat(strings.TrimSpace(row.Price.Value), 64)
+		owned, _ := strconv.ParseFloat(strings.TrimSpace(row.SharesOwnedAfter.Value), 64)
+
+		date := strings.TrimSpace(row.Date.Value)
+		if len(date) > 10 {
+			date = date[:10] // Dates may carry a time zone offset
+		}
+
+		transactions = append(transactions, &models.InsiderTransaction{
+			Symbol:            symbol,
+			AccessionNumber:   filing.AccessionNumber,
+			Line:              len(transactions) + 1,
+			InsiderName:       strings.Join(names, "; "),
+			InsiderTitle:      strings.Join(titles, ", "),
+			IsDirector:        owner.IsDirector,
+			IsOfficer:         owner.IsOfficer,
+			IsTenPercentOwner: owner.IsTenPercentOwner,
+			SecurityTitle:     strings.TrimSpace(row.SecurityTitle.Value),
+			IsDerivative:      derivative,
+			TransactionDate:   date,
+			FiledDate:         filing.FilingDate,
+			TransactionCode:   strings.TrimSpace(row.Code),
+			AcquiredDisposed:  strings.TrimSpace(row.AcquiredDisposed.Value),
+			Shares:            shares,
+			Price:             price,
+			Value:             shares * price,
+			SharesOwnedAfter:  owned,
+		})
+	}
+	for _, row := range doc.NonDerivative {
+		add(row, false)
+	}
+	for _, row := range doc.Derivative {
+		add(row, true)
+	}
+	return transactions, nil
+}
+
+// Summary returns a stock's insider transactions over the given number of days, with its open market purchase
+// and sale totals and whether insiders are cluster buying
+func (is *InsiderService) Summary(symbol string, days int) (*models.InsiderSummary, error) {
+	window := max(days, is.clusterDays)
+	transactions, err := is.db.GetInsiderTransactions(symbol, clockNow().AddDate(0, 0, -window).Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+
+	since := clockNow().AddDate(0, 0, -days).Format("2006-01-02")
+	summary := &models.InsiderSummary{
+		Symbol:        symbol,
+		Days:          days,
+		ClusterBuyers: is.clusterBuyersOf(transactions),
+		Transactions:  make([]*models.InsiderTransaction, 0, len(transactions)),
+	}
+	summary.ClusterBuying = len(summary.ClusterBuyers) >= is.clusterBuyers
+
+	for _, t := range transactions {
+		if t.TransactionDate < since {
+			continue
+		}
+		summary.Transactions = append(summary.Transactions, t)
+
+		if t.IsDerivative {
+			continue
+		}
+		switch t.TransactionCode {
+		case models.InsiderCodePurchase:
+			summary.Purchases++
+			summary.BuyValue += t.Value
+		case models.InsiderCodeSale:
+			summary.Sales++
+			summary.SellValue += t.Value
+		}
+	}
+	summary.NetValue = summary.BuyValue - summary.SellValue
+
+	return summary, nil
+}
+
+// clusterBuyersOf returns the insiders with open market purchases in the cluster window, in name order
+func (is *InsiderService) clusterBuyersOf(transactions []*models.InsiderTransaction) []string {
+	since := clockNow().AddDate(0, 0, -is.clusterDays).Format("2006-01-02")
+
+	buyers := make([]string, 0)
+	for _, t := range transactions {
+		if t.IsOpenMarketPurchase() && t.TransactionDate >= since && !slices.Contains(buyers, t.InsiderName) {
+			buyers = append(buyers, t.InsiderName)
+		}
+	}
+	sort.Strings(buyers)
+	return buyers
+}
+
+// checkClusterBuying notifies when a stock's insiders are cluster buying while it has an active bullish setup
+func (is *InsiderService) checkClusterBuying(symbol string) {
+	if !is.alertClusters {
+		return
+	}
+
+	summary, err := is.Summary(symbol, is.clusterDays)
+	if err != nil {
+		log.Printf("Failed to check cluster buying for %s: %v", symbol, err)
+		return
+	}
+	if !summary.ClusterBuying {
+		return
+	}
+
+	active := true
+	setups, err := is.db.GetTradingSetups(&models.SetupFilter{Symbol: symbol, Direction: "bullish", IsActive: &active})
+	if err != nil {
+		log.Printf("Failed to get active setups for %s: %v", symbol, err)
+		return
+	}
+	var setup *models.TradingSetup
+	for _, s := range setups {
+		if s.IsActive() {
+			setup = s
+			break
+		}
+	}
+	if setup == nil {
+		return
+	}
+
+	is.notifications.Notify(&models.Notification{
+		Category: models.NotificationSetup,
+		Severity: EmailSeverityHigh,
+		Symbol:   symbol,
+		Title:    fmt.Sprintf("🏦 %s: insider cluster buying with an active %s", symbol, setupTitle(setup)),
+		Message: fmt.Sprintf("%d insiders bought $%.0f on the open market in the last %d days: %s\n%s",
+			len(summary.ClusterBuyers), summary.BuyValue, is.clusterDays, strings.Join(summary.ClusterBuyers, ", "),
+			formatSetupLevels(setup)),
+		Link: "/api/v1/insiders/" + symbol,
+	})
+}
+
+// getJSON fetches and decodes a JSON document from EDGAR
+func (is *InsiderService) getJSON(url string, v interface{}) error {
+	body, err := is.get(url)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// get makes a rate limited EDGAR request with the contact user agent the SEC requires
+func (is *InsiderService) get(url string) (io.ReadCloser, error) {
+	if err := is.limiter.Wait(context.Background()); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", is.userAgent)
+
+	resp, err := is.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("SEC request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// form4XML renders a minimal Form 4 ownership document with one non-derivative transaction
+func form4XML(owner, title, date, code string, shares, price float64) string {
+	return fmt.Sprintf(`<?xml version="1.0"?><ownershipDocument>
+<reportingOwner><reportingOwnerId><rptOwnerName>%s</rptOwnerName></reportingOwnerId>
+<reportingOwnerRelationship><isDirector>0</isDirector><isOfficer>1</isOfficer><officerTitle>%s</officerTitle></reportingOwnerRelationship></reportingOwner>
+<nonDerivativeTable><nonDerivativeTransaction><securityTitle><value>Common Stock</value></securityTitle>
+<transactionDate><value>%s</value></transactionDate><transactionCoding><transactionCode>%s</transactionCode></transactionCoding>
+<transactionAmounts><transactionShares><value>%.0f</value></transactionShares><transactionPricePerShare><value>%.2f</value></transactionPricePerShare>
+<transactionAcquiredDisposedCode><value>A</value></transactionAcquiredDisposedCode></transactionAmounts>
+<postTransactionAmounts><sharesOwnedFollowingTransaction><value>10000</value></sharesOwnedFollowingTransaction></postTransactionAmounts>
+</nonDerivativeTransaction></nonDerivativeTable></ownershipDocument>`, owner, title, date, code, shares, price)
+}
+
+// TestInsiderClusterBuying tests that new Form 4 filings are stored once and that cluster buying on a stock with
+// an active bullish setup is notified
+func TestInsiderClusterBuying(t *testing.T) {
+	SetClock(NewSimulatedClock(time.Date(2025, 3, 12, 15, 0, 0, 0, time.UTC)))
+	t.Cleanup(func() { SetClock(nil) })
+
+	requested := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested[r.URL.Path]++
+		if r.Header.Get("User-Agent") != "Test test@example.com" {
+			http.Error(w, "Undeclared Automated Tool", http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/files/company_tickers.json":
+			fmt.Fprint(w, `{"0":{"cik_str":1234,"ticker":"TEST","title":"Test Corp"},"1":{"cik_str":99,"ticker":"BRK-B","title":"Berkshire"}}`)
+		case "/submissions/CIK0000001234.json":
+			fmt.Fprint(w, `{"filings":{"recent":{
+				"accessionNumber":["0001-25-000003","0001-25-000002","0001-25-000001","0001-24-000009","0001-25-000004"],
+				"filingDate":["2025-03-11","2025-03-10","2025-03-07","2024-10-01","2025-03-06"],
+				"form":["4","4","4","4","10-K"],
+				"primaryDocument":["xslF345X05/a3.xml","xslF345X05/a2.xml","a1.xml","old.xml","10k.htm"]}}}`)
+		case "/Archives/edgar/data/1234/000125000003/a3.xml":
+			fmt.Fprint(w, form4XML("Doe Jane", "CFO", "2025-03-10", "P", 1000, 50))
+		case "/Archives/edgar/data/1234/000125000002/a2.xml":
+			fmt.Fprint(w, form4XML("Roe Richard", "CEO", "2025-03-07-05:00", "P", 2000, 48.5))
+		case "/Archives/edgar/data/1234/000125000001/a1.xml":
+			fmt.Fprint(w, form4XML("Doe Jane", "CFO", "2025-03-05", "S", 500, 55))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.Insiders = config.InsidersConfig{
+		UserAgent: "Test test@example.com", ClusterBuyers: 2, AlertClusterBuying: true,
+		SECURL: server.URL, SECDataURL: server.URL,
+	}
+	db := newTestDB(t)
+
+	setup := &models.TradingSetup{
+		Symbol: "TEST", SetupType: "support_bounce", Direction: "bullish", Confidence: "high", Status: "active", QualityScore: 80,
+		DetectedAt: time.Now(), ExpiresAt: time.Now().Add(24 * time.Hour), EntryPrice: 50, StopLoss: 48, Target1: 55,
+	}
+	if err := db.InsertTradingSetup(setup); err != nil {
+		t.Fatalf("InsertTradingSetup failed: %v", err)
+	}
+
+	is := NewInsiderService(cfg, db)
+	is.SetNotificationService(NewNotificationService(db))
+	stored, err := is.RefreshSymbol("TEST")
+	if err != nil || stored != 3 {
+		t.Fatalf("expected the 3 Form 4 filings in the lookback to be stored, got %d, %v", stored, err)
+	}
+	if stored, err := is.RefreshSymbol("TEST"); err != nil || stored != 0 || requested["/Archives/edgar/data/1234/000125000003/a3.xml"] != 1 {
+		t.Errorf("expected fetched filings to be skipped, got %d, %v, %v", stored, err, requested)
+	}
+
+	summary, err := is.Summary("TEST", 30)
+	if err != nil {
+		t.Fatalf("Summary failed: %v", err)
+	}
+	if len(summary.Transactions) != 3 || summary.Transactions[0].InsiderName != "Doe Jane" || summary.Transactions[1].TransactionDate != "2025-03-07" {
+		t.Errorf("expected the transactions newest first, got %+v", summary.Transactions)
+	}
+	if summary.Purchases != 2 || summary.Sales != 1 || summary.BuyValue != 147000 || summary.NetValue != 119500 {
+		t.Errorf("unexpected totals: %+v", summary)
+	}
+	if !summary.ClusterBuying || len(summary.ClusterBuyers) != 2 || summary.ClusterBuyers[0] != "Doe Jane" {
+		t.Errorf("expected 2 insiders cluster buying, got %v", summary.ClusterBuyers)
+	}
+
+	notifications, err := db.GetNotifications(&models.NotificationFilter{Category: models.NotificationSetup})
+	if err != nil || len(notifications) != 1 || notifications[0].Symbol != "TEST" {
+		t.Fatalf("expected one cluster buying notification, got %+v, %v", notifications, err)
+	}
+
+	if cik, err := is.lookupCIK("BRK.B"); err != nil || cik != "0000000099" {
+		t.Errorf("expected share classes to match EDGAR's dashed tickers, got %q, %v", cik, err)
+	}
+}
@@ -0,0 +1,347 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// Job queue defaults
+const (
+	DefaultJobWorkers = 4
+	maxRetainedJobs   = 200
+)
+
+// JobTask processes one item of a job and returns its result
+type JobTask func(ctx context.Context, item string) (interface{}, error)
+
+// jobState tracks dispatch of a job's items
+type jobState struct {
+	job      *models.Job
+	items    []string
+	next     int // index of the next item to dispatch
+	inFlight int
+	task     JobTask
+}
+
+// JobService runs background jobs item by item on a bounded pool of workers
+type JobService struct {
+	workers   int
+	jobs      map[string]*jobState
+	order     []string    // job IDs in submission order
+	queue     []*jobState // jobs with items left to dispatch, oldest first
+	seq       int64
+	stopped   bool
+	schedules *ScheduleControl // items of disabled job types stay queued
+	mutex     sync.Mutex
+	cond      *sync.Cond
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup // tracks the workers
+}
+
+// NewJobService creates a new job service with the given number of workers
+func NewJobService(workers int) *JobService {
+	if workers <= 0 {
+		workers = DefaultJobWorkers
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	js := &JobService{
+		workers: workers,
+		jobs:    make(map[string]*jobState),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	js.cond = sync.NewCond(&js.mutex)
+	return js
+}
+
+// SetScheduleControl sets the control disabling job types; queued items of a disabled type wait until it is
+// enabled again, while items already running finish
+func (js *JobService) SetScheduleControl(schedules *ScheduleControl) {
+	js.schedules = schedules
+	schedules.Watch(func() {
+		js.mutex.Lock()
+		js.cond.Broadcast()
+		js.mutex.Unlock()
+	})
+}
+
+// Start launches the worker pool
+func (js *JobService) Start() {
+	log.Printf("Starting job queue with %d workers...", js.workers)
+
+	for i := 0; i < js.workers; i++ {
+		js.wg.Add(1)
+		go func() {
+			defer js.wg.Done()
+			js.work()
+		}()
+	}
+}
+
+// Stop cancels queued items and waits for running items to finish
+func (js *JobService) Stop(ctx context.Context) error {
+	js.mutex.Lock()
+	js.stopped = true
+	now := time.Now()
+	for _, state := range js.queue {
+		state.job.Status = models.JobStatusCancelled
+		if state.inFlight == 0 {
+			state.job.FinishedAt = &now
+		}
+	}
+	js.queue = nil
+	js.cond.Broadcast()
+	js.mutex.Unlock()
+
+	js.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		js.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Printf("Job queue stopped")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for jobs to finish: %w", ctx.Err())
+	}
+}
+
+// Submit queues a job that runs task for every item and returns a snapshot of the job
+func (js *JobService) Submit(jobType string, items []string, params map[string]interface{}, task JobTask) (*models.Job, error) {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+
+	if js.stopped {
+		return nil, fmt.Errorf("job queue is stopped")
+	}
+
+	js.seq++
+	job := &models.Job{
+		ID:        fmt.Sprintf("job-%d-%d", time.Now().Unix(), js.seq),
+		Type:      jobType,
+		Status:    models.JobStatusQueued,
+		Params:    params,
+		Total:     len(items),
+		Results:   make(map[string]interface{}),
+		Errors:    make(map[string]string),
+		CreatedAt: time.Now(),
+	}
+	state := &jobState{job: job, items: items, task: task}
+
+	js.jobs[job.ID] = state
+	js.order = append(js.order, job.ID)
+	js.pruneLocked()
+
+	if len(items) == 0 {
+		js.finishLocked(state)
+	} else {
+		js.queue = append(js.queue, state)
+		js.cond.Signal()
+	}
+
+	log.Printf("Queued %s job %s with %d items", jobType, job.ID, len(items))
+	return snapshotJob(job), nil
+}
+
+// Get returns a snapshot of a job
+func (js *JobService) Get(id string) (*models.Job, bool) {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+
+	state, ok := js.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	return snapshotJob(state.job), true
+}
+
+// List returns job summaries, newest first, without per-item results
+func (js *JobService) List(filter *models.JobFilter) []*models.Job {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+
+	jobs := make([]*models.Job, 0)
+	for i := len(js.order) - 1; i >= 0; i-- {
+		job := js.jobs[js.order[i]].job
+		if filter != nil && filter.Type != "" && job.Type != filter.Type {
+			continue
+		}
+		if filter != nil && filter.Status != "" && job.Status != filter.Status {
+			continue
+		}
+
+		summary := *job
+		summary.Results = nil
+		summary.Errors = nil
+		jobs = append(jobs, &summary)
+
+		if filter != nil && filter.Limit > 0 && len(jobs) >= filter.Limit {
+			break
+		}
+	}
+
+	return jobs
+}
+
+// Cancel stops dispatching a job's remaining items; items already running finish normally
+func (js *JobService) Cancel(id string) (*models.Job, error) {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+
+	state, ok := js.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("job %w", database.ErrNotFound)
+	}
+	if state.job.IsFinished() {
+		return nil, fmt.Errorf("job is already %s", state.job.Status)
+	}
+
+	for i, queued := range js.queue {
+		if queued == state {
+			js.queue = append(js.queue[:i], js.queue[i+1:]...)
+			break
+		}
+	}
+	state.next = len(state.items)
+	state.job.Status = models.JobStatusCancelled
+	if state.inFlight == 0 {
+		now := time.Now()
+		state.job.FinishedAt = &now
+	}
+
+	return snapshotJob(state.job), nil
+}
+
+// work runs queued items until the service stops
+func (js *JobService) work() {
+	for {
+		js.mutex.Lock()
+		next := js.nextLocked()
+		for next < 0 && !js.stopped {
+			js.cond.Wait()
+			next = js.nextLocked()
+		}
+		if js.stopped {
+			js.mutex.Unlock()
+			return
+		}
+
+		state := js.queue[next]
+		item := state.items[state.next]
+		state.next++
+		state.inFlight++
+		if state.next >= len(state.items) {
+			js.queue = append(js.queue[:next], js.queue[next+1:]...)
+		}
+		if state.job.Status == models.JobStatusQueued {
+			now := time.Now()
+			state.job.Status = models.JobStatusRunning
+			state.job.StartedAt = &now
+		}
+		js.mutex.Unlock()
+
+		result, err := runJobTask(js.ctx, state.task, item)
+
+		js.mutex.Lock()
+		state.inFlight--
+		job := state.job
+		job.Completed++
+		if err != nil {
+			job.Failed++
+			job.Errors[item] = err.Error()
+		} else if result != nil {
+			job.Results[item] = result
+		}
+		if job.Total > 0 {
+			job.Progress = float64(job.Completed) / float64(job.Total) * 100
+		}
+		if state.next >= len(state.items) && state.inFlight == 0 {
+			js.finishLocked(state)
+		}
+		js.mutex.Unlock()
+	}
+}
+
+// nextLocked returns the index of the oldest queued job of an enabled type, -1 when there is none; the caller
+// must hold the mutex
+func (js *JobService) nextLocked() int {
+	for i, state := range js.queue {
+		if js.schedules.Enabled(state.job.Type) {
+			return i
+		}
+	}
+	return -1
+}
+
+// finishLocked marks a job finished; the caller must hold the mutex
+func (js *JobService) finishLocked(state *jobState) {
+	job := state.job
+	now := time.Now()
+	job.FinishedAt = &now
+
+	switch {
+	case job.Status == models.JobStatusCancelled:
+	case job.Total > 0 && job.Failed == job.Total:
+		job.Status = models.JobStatusFailed
+	default:
+		job.Status = models.JobStatusCompleted
+		job.Progress = 100
+	}
+
+	log.Printf("Job %s %s: %d/%d items, %d failed", job.ID, job.Status, job.Completed, job.Total, job.Failed)
+}
+
+// pruneLocked drops the oldest finished jobs beyond the retention limit; the caller must hold the mutex
+func (js *JobService) pruneLocked() {
+	excess := len(js.order) - maxRetainedJobs
+	if excess <= 0 {
+		return
+	}
+
+	kept := js.order[:0]
+	for _, id := range js.order {
+		if excess > 0 && js.jobs[id].job.IsFinished() {
+			delete(js.jobs, id)
+			excess--
+			continue
+		}
+		kept = append(kept, id)
+	}
+	js.order = kept
+}
+
+// runJobTask runs a task, converting a panic into an item error
+func runJobTask(ctx context.Context, task JobTask, item string) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return task(ctx, item)
+}
+
+// snapshotJob copies a job so callers can read it without holding the lock
+func snapshotJob(job *models.Job) *models.Job {
+	snapshot := *job
+	snapshot.Results = make(map[string]interface{}, len(job.Results))
+	for k, v := range job.Results {
+		snapshot.Results[k] = v
+	}
+	snapshot.Errors = make(map[string]string, len(job.Errors))
+	for k, v := range job.Errors {
+		snapshot.Errors[k] = v
+	}
+	return &snapshot
+}
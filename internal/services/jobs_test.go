@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// waitForJob polls a job until it finishes or the test times out
+func waitForJob(t *testing.T, js *JobService, id string) *models.Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, ok := js.Get(id)
+		if !ok {
+			t.Fatalf("job %s not found", id)
+		}
+		if job.IsFinished() {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", id)
+	return nil
+}
+
+// TestJobServiceRunsItemsWithBoundedConcurrency tests results, errors and the worker limit
+func TestJobServiceRunsItemsWithBoundedConcurrency(t *testing.T) {
+	js := NewJobService(2)
+	js.Start()
+	defer js.Stop(context.Background())
+
+	var running, peak int32
+	task := func(ctx context.Context, item string) (interface{}, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		if item == "BAD" {
+			return nil, fmt.Errorf("no data")
+		}
+		return item + " ok", nil
+	}
+
+	queued, err := js.Submit(models.JobTypePatternScan, []string{"AAPL", "MSFT", "BAD", "NVDA", "TSLA"}, nil, task)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	job := waitForJob(t, js, queued.ID)
+	if job.Status != models.JobStatusCompleted {
+		t.Errorf("status = %s, want %s", job.Status, models.JobStatusCompleted)
+	}
+	if job.Completed != 5 || job.Failed != 1 || job.Progress != 100 {
+		t.Errorf("completed/failed/progress = %d/%d/%.0f, want 5/1/100", job.Completed, job.Failed, job.Progress)
+	}
+	if job.Results["AAPL"] != "AAPL ok" || job.Errors["BAD"] != "no data" {
+		t.Errorf("unexpected results %v / errors %v", job.Results, job.Errors)
+	}
+	if peak > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", peak)
+	}
+}
+
+// TestJobServiceCancel tests that cancelling a queued job stops it before any item runs
+func TestJobServiceCancel(t *testing.T) {
+	js := NewJobService(1)
+
+	job, err := js.Submit(models.JobTypeBackfill, []string{"AAPL", "MSFT"}, nil, func(ctx context.Context, item string) (interface{}, error) {
+		t.Errorf("cancelled job ran item %s", item)
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	if _, err := js.Cancel(job.ID); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+
+	js.Start()
+	defer js.Stop(context.Background())
+
+	job = waitForJob(t, js, job.ID)
+	if job.Status != models.JobStatusCancelled || job.Completed != 0 {
+		t.Errorf("status/completed = %s/%d, want cancelled/0", job.Status, job.Completed)
+	}
+	if _, err := js.Cancel(job.ID); err == nil {
+		t.Errorf("expected error cancelling a finished job")
+	}
+}
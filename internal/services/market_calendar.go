@@ -0,0 +1,365 @@
+package services
+
+import (
+	"time"
+	_ "time/tzdata" // exchange hours must follow US daylight saving time even on hosts without zoneinfo
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// Session times in minutes after midnight, exchange time
+const (
+	regularOpenMinute  = 9*60 + 30
+	regularCloseMinute = 16 * 60
+	earlyCloseMinute   = 13 * 60
+	defaultPreMarket   = 4 * 60
+	defaultPostMarket  = 20 * 60
+)
+
+// MarketCalendar knows the exchange's trading days, holidays, early closes and session windows
+type MarketCalendar struct {
+	exchange        string
+	location        *time.Location
+	collectSessions string
+	preMarketOpen   int // minutes after midnight
+	postMarketClose int
+	extraHolidays   map[string]bool
+}
+
+// NewMarketCalendar creates a trading calendar from the market_hours config
+func NewMarketCalendar(cfg config.MarketHoursConfig) *MarketCalendar {
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		location = time.UTC
+	}
+
+	mc := &MarketCalendar{
+		exchange:        cfg.Exchange,
+		location:        location,
+		collectSessions: cfg.CollectSessions,
+		preMarketOpen:   parseSessionMinute(cfg.PreMarketOpen, defaultPreMarket),
+		postMarketClose: parseSessionMinute(cfg.PostMarketClose, defaultPostMarket),
+		extraHolidays:   make(map[string]bool),
+	}
+	if mc.exchange == "" {
+		mc.exchange = "NYSE"
+	}
+	if mc.collectSessions == "" {
+		mc.collectSessions = models.CollectSessionsExtended
+	}
+	for _, day := range cfg.ExtraHolidays {
+		mc.extraHolidays[day] = true
+	}
+
+	return mc
+}
+
+// parseSessionMinute converts an HH:MM clock time to minutes after midnight
+func parseSessionMinute(value string, fallback int) int {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return fallback
+	}
+	return parsed.Hour()*60 + parsed.Minute()
+}
+
+// Location returns the exchange time zone
+func (mc *MarketCalendar) Location() *time.Location {
+	return mc.location
+}
+
+// Holiday returns the name of the exchange holiday on the trading date of t, if any
+func (mc *MarketCalendar) Holiday(t time.Time) (string, bool) {
+	local := t.In(mc.location)
+	date := local.Format("2006-01-02")
+	if mc.extraHolidays[date] {
+		return "Unscheduled closure", true
+	}
+	name, ok := exchangeHolidays(local.Year())[date]
+	return name, ok
+}
+
+// IsTradingDay reports whether the exchange is open at all on the date of t
+func (mc *MarketCalendar) IsTradingDay(t time.Time) bool {
+	local := t.In(mc.location)
+	if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+		return false
+	}
+	_, holiday := mc.Holiday(local)
+	return !holiday
+}
+
+// IsHalfDay reports whether the regular session closes early at 1:00 PM on the date of t
+func (mc *MarketCalendar) IsHalfDay(t time.Time) bool {
+	if !mc.IsTradingDay(t) {
+		return false
+	}
+
+	local := t.In(mc.location)
+	switch local.Month() {
+	case time.July:
+		// Independence Day eve, unless July 4 falls on a weekend and July 3 is the observed holiday
+		return local.Day() == 3
+	case time.November:
+		// Day after Thanksgiving
+		thanksgiving := nthWeekday(local.Year(), time.November, time.Thursday, 4)
+		return local.Day() == thanksgiving.Day()+1
+	case time.December:
+		return local.Day() == 24
+	}
+	return false
+}
+
+// sessionBounds returns the pre-market open, regular open, regular close and post-market close on the date of t
+func (mc *MarketCalendar) sessionBounds(t time.Time) (preOpen, open, closeAt, postClose time.Time) {
+	local := t.In(mc.location)
+	at := func(minute int) time.Time {
+		return time.Date(local.Year(), local.Month(), local.Day(), 0, minute, 0, 0, mc.location)
+	}
+
+	closeMinute := regularCloseMinute
+	postMinute := mc.postMarketClose
+	if mc.IsHalfDay(local) {
+		// Extended trading ends early by as much as the regular session does
+		postMinute -= regularCloseMinute - earlyCloseMinute
+		closeMinute = earlyCloseMinute
+	}
+
+	return at(mc.preMarketOpen), at(regularOpenMinute), at(closeMinute), at(postMinute)
+}
+
+// RegularSession returns the regular session open and close on the date of t; ok is false when the exchange is closed
+func (mc *MarketCalendar) RegularSession(t time.Time) (open, closeAt time.Time, ok bool) {
+	if !mc.IsTradingDay(t) {
+		return time.Time{}, time.Time{}, false
+	}
+	_, open, closeAt, _ = mc.sessionBounds(t)
+	return open, closeAt, true
+}
+
+// Session returns the trading session t falls into
+func (mc *MarketCalendar) Session(t time.Time) models.MarketSession {
+	if !mc.IsTradingDay(t) {
+		return models.SessionClosed
+	}
+
+	preOpen, open, closeAt, postClose := mc.sessionBounds(t)
+	switch {
+	case t.Before(preOpen):
+		return models.SessionClosed
+	case t.Before(open):
+		return models.SessionPreMarket
+	case t.Before(closeAt):
+		return models.SessionRegular
+	case t.Before(postClose):
+		return models.SessionPostMarket
+	default:
+		return models.SessionClosed
+	}
+}
+
+// IsMarketHours reports whether t falls in the regular trading session
+func (mc *MarketCalendar) IsMarketHours(t time.Time) bool {
+	return mc.Session(t) == models.SessionRegular
+}
+
+// ShouldCollect reports whether the collector should fetch data at t under the configured schedule
+func (mc *MarketCalendar) ShouldCollect(t time.Time) bool {
+	switch mc.collectSessions {
+	case models.CollectSessionsAlways:
+		return true
+	case models.CollectSessionsRegular:
+		return mc.IsMarketHours(t)
+	default:
+		return mc.Session(t) != models.SessionClosed
+	}
+}
+
+// CollectsExtendedHours reports whether pre- and post-market bars are collected
+func (mc *MarketCalendar) CollectsExtendedHours() bool {
+	return mc.collectSessions != models.CollectSessionsRegular
+}
+
+// forexOpen reports whether the FX market, open from Sunday 5:00 PM to Friday 5:00 PM New York time, trades at t
+func (mc *MarketCalendar) forexOpen(t time.Time) bool {
+	local := t.In(mc.location)
+	switch local.Weekday() {
+	case time.Saturday:
+		return false
+	case time.Sunday:
+		return local.Hour() >= 17
+	case time.Friday:
+		return local.Hour() < 17
+	default:
+		return true
+	}
+}
+
+// SymbolSession returns the session t falls into for a symbol's asset class. Crypto trades around the clock
+// and FX through the week, so their bars are either regular or closed.
+func (mc *MarketCalendar) SymbolSession(symbol string, t time.Time) models.MarketSession {
+	switch models.AssetClassOf(symbol) {
+	case models.AssetClassCrypto:
+		return models.SessionRegular
+	case models.AssetClassForex:
+		if mc.forexOpen(t) {
+			return models.SessionRegular
+		}
+		return models.SessionClosed
+	default:
+		return mc.Session(t)
+	}
+}
+
+// ShouldCollectSymbol reports whether the collector should fetch a symbol's data at t. Equities follow the
+// configured schedule; crypto is always collected and FX while the market is open.
+func (mc *MarketCalendar) ShouldCollectSymbol(symbol string, t time.Time) bool {
+	switch models.AssetClassOf(symbol) {
+	case models.AssetClassCrypto:
+		return true
+	case models.AssetClassForex:
+		return mc.collectSessions == models.CollectSessionsAlways || mc.forexOpen(t)
+	default:
+		return mc.ShouldCollect(t)
+	}
+}
+
+// NextOpen returns the start of the next regular session after t
+func (mc *MarketCalendar) NextOpen(t time.Time) time.Time {
+	local := t.In(mc.location)
+	for i := 0; i < 15; i++ {
+		day := local.AddDate(0, 0, i)
+		if !mc.IsTradingDay(day) {
+			continue
+		}
+		_, open, _, _ := mc.sessionBounds(day)
+		if open.After(t) {
+			return open
+		}
+	}
+	return time.Time{}
+}
+
+// Status describes the exchange session at t
+func (mc *MarketCalendar) Status(t time.Time) *models.MarketStatus {
+	local := t.In(mc.location)
+	session := mc.Session(local)
+
+	status := &models.MarketStatus{
+		Exchange:     mc.exchange,
+		Time:         local,
+		Session:      session,
+		IsOpen:       session == models.SessionRegular,
+		IsTradingDay: mc.IsTradingDay(local),
+		IsHalfDay:    mc.IsHalfDay(local),
+		Collecting:   mc.ShouldCollect(local),
+		NextOpen:     mc.NextOpen(local),
+	}
+	if name, ok := mc.Holiday(local); ok {
+		status.Holiday = name
+	}
+	if status.IsTradingDay {
+		_, open, closeAt, _ := mc.sessionBounds(local)
+		status.OpensAt = &open
+		status.ClosesAt = &closeAt
+	}
+
+	return status
+}
+
+// Holidays lists the year's full-day closures and early closes in date order
+func (mc *MarketCalendar) Holidays(year int) []models.MarketHoliday {
+	holidays := []models.MarketHoliday{}
+	for day := time.Date(year, time.January, 1, 12, 0, 0, 0, mc.location); day.Year() == year; day = day.AddDate(0, 0, 1) {
+		if name, ok := mc.Holiday(day); ok {
+			holidays = append(holidays, models.MarketHoliday{Date: day.Format("2006-01-02"), Name: name})
+		} else if mc.IsHalfDay(day) {
+			holidays = append(holidays, models.MarketHoliday{Date: day.Format("2006-01-02"), Name: "Early close", EarlyClose: true})
+		}
+	}
+	return holidays
+}
+
+// RegularSessions returns the regular sessions of the trading days from the date of from through the date of
+// to, oldest first
+func (mc *MarketCalendar) RegularSessions(from, to time.Time) []models.TradingSession {
+	sessions := []models.TradingSession{}
+	start, end := from.In(mc.location), to.In(mc.location)
+	last := time.Date(end.Year(), end.Month(), end.Day(), 12, 0, 0, 0, mc.location)
+	for day := time.Date(start.Year(), start.Month(), start.Day(), 12, 0, 0, 0, mc.location); !day.After(last); day = day.AddDate(0, 0, 1) {
+		if open, closeAt, ok := mc.RegularSession(day); ok {
+			sessions = append(sessions, models.TradingSession{TradingDay: day.Format("2006-01-02"), Open: open, Close: closeAt})
+		}
+	}
+	return sessions
+}
+
+// exchangeHolidays returns the NYSE/NASDAQ full-day closures for a year keyed by YYYY-MM-DD
+func exchangeHolidays(year int) map[string]string {
+	holidays := make(map[string]string)
+	add := func(date time.Time, name string) {
+		holidays[date.Format("2006-01-02")] = name
+	}
+
+	// New Year's Day moves to Monday when it falls on Sunday, but is not observed on the prior Friday
+	newYear := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	switch newYear.Weekday() {
+	case time.Saturday:
+	case time.Sunday:
+		add(newYear.AddDate(0, 0, 1), "New Year's Day")
+	default:
+		add(newYear, "New Year's Day")
+	}
+
+	add(nthWeekday(year, time.January, time.Monday, 3), "Martin Luther King Jr. Day")
+	add(nthWeekday(year, time.February, time.Monday, 3), "Washington's Birthday")
+	add(easterSunday(year).AddDate(0, 0, -2), "Good Friday")
+	add(nthWeekday(year, time.June, time.Monday, 1).AddDate(0, 0, -7), "Memorial Day")
+	if year >= 2022 {
+		add(observed(time.Date(year, time.June, 19, 0, 0, 0, 0, time.UTC)), "Juneteenth")
+	}
+	add(observed(time.Date(year, time.July, 4, 0, 0, 0, 0, time.UTC)), "Independence Day")
+	add(nthWeekday(year, time.September, time.Monday, 1), "Labor Day")
+	add(nthWeekday(year, time.November, time.Thursday, 4), "Thanksgiving Day")
+	add(observed(time.Date(year, time.December, 25, 0, 0, 0, 0, time.UTC)), "Christmas Day")
+
+	return holidays
+}
+
+// observed moves a Saturday holiday to Friday and a Sunday holiday to Monday
+func observed(date time.Time) time.Time {
+	switch date.Weekday() {
+	case time.Saturday:
+		return date.AddDate(0, 0, -1)
+	case time.Sunday:
+		return date.AddDate(0, 0, 1)
+	}
+	return date
+}
+
+// nthWeekday returns the nth occurrence of a weekday in a month
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+	return first.AddDate(0, 0, offset+(n-1)*7)
+}
+
+// easterSunday computes the Gregorian Easter date (anonymous Gregorian algorithm)
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
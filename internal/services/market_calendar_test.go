@@ -0,0 +1,160 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// TestExchangeHolidays tests rule-based holidays, weekend observance and early closes
+func TestExchangeHolidays(t *testing.T) {
+	mc := NewMarketCalendar(config.MarketHoursConfig{ExtraHolidays: []string{"2025-01-09"}})
+	noon := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 12, 0, 0, 0, mc.Location())
+	}
+
+	holidays := []time.Time{
+		noon(2025, time.January, 1),   // New Year's Day
+		noon(2025, time.January, 9),   // Configured closure
+		noon(2025, time.January, 20),  // Martin Luther King Jr. Day
+		noon(2025, time.April, 18),    // Good Friday
+		noon(2025, time.May, 26),      // Memorial Day
+		noon(2022, time.June, 20),     // Juneteenth observed on Monday
+		noon(2026, time.July, 3),      // Independence Day observed on Friday
+		noon(2025, time.November, 27), // Thanksgiving Day
+		noon(2022, time.December, 26), // Christmas observed on Monday
+	}
+	for _, day := range holidays {
+		if mc.IsTradingDay(day) {
+			t.Errorf("%s should be a holiday", day.Format("2006-01-02"))
+		}
+	}
+
+	// New Year's Day on a Saturday is not observed on the prior Friday
+	if !mc.IsTradingDay(noon(2021, time.December, 31)) {
+		t.Error("2021-12-31 should be a trading day")
+	}
+	if mc.IsTradingDay(noon(2025, time.June, 7)) {
+		t.Error("weekends should not be trading days")
+	}
+
+	halfDays := []time.Time{noon(2025, time.July, 3), noon(2025, time.November, 28), noon(2025, time.December, 24)}
+	for _, day := range halfDays {
+		if !mc.IsHalfDay(day) {
+			t.Errorf("%s should close early", day.Format("2006-01-02"))
+		}
+	}
+	if mc.IsHalfDay(noon(2026, time.July, 3)) || mc.IsHalfDay(noon(2025, time.July, 2)) {
+		t.Error("only the trading day before Independence Day closes early")
+	}
+	if got := len(mc.Holidays(2025)); got != 14 {
+		t.Errorf("expected 10 holidays, 1 closure and 3 early closes in 2025, got %d", got)
+	}
+}
+
+// TestMarketSessions tests session windows across daylight saving time and early closes
+func TestMarketSessions(t *testing.T) {
+	mc := NewMarketCalendar(config.MarketHoursConfig{})
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want models.MarketSession
+	}{
+		{"standard time pre-market", time.Date(2025, 3, 7, 13, 35, 0, 0, time.UTC), models.SessionPreMarket},
+		{"standard time open", time.Date(2025, 3, 7, 14, 35, 0, 0, time.UTC), models.SessionRegular},
+		{"daylight time open", time.Date(2025, 3, 10, 13, 35, 0, 0, time.UTC), models.SessionRegular},
+		{"daylight time post-market", time.Date(2025, 3, 10, 20, 30, 0, 0, time.UTC), models.SessionPostMarket},
+		{"overnight", time.Date(2025, 3, 10, 2, 0, 0, 0, time.UTC), models.SessionClosed},
+		{"early close post-market", time.Date(2025, 11, 28, 18, 30, 0, 0, time.UTC), models.SessionPostMarket},
+		{"early close after hours", time.Date(2025, 11, 28, 22, 30, 0, 0, time.UTC), models.SessionClosed},
+		{"holiday", time.Date(2025, 7, 4, 15, 0, 0, 0, time.UTC), models.SessionClosed},
+	}
+	for _, tt := range tests {
+		if got := mc.Session(tt.at); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+
+	// Friday after the close opens next on Monday, skipping the weekend
+	next := mc.NextOpen(time.Date(2025, 3, 7, 22, 0, 0, 0, time.UTC))
+	if want := time.Date(2025, 3, 10, 13, 30, 0, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("expected next open %v, got %v", want, next)
+	}
+
+	regular := NewMarketCalendar(config.MarketHoursConfig{CollectSessions: models.CollectSessionsRegular})
+	preMarket := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	if !mc.ShouldCollect(preMarket) || regular.ShouldCollect(preMarket) {
+		t.Error("pre-market should only be collected on the extended schedule")
+	}
+
+	// Sessions from a Friday evening through Tuesday, across the weekend and the DST change, by exchange date
+	sessions := mc.RegularSessions(time.Date(2025, 3, 7, 22, 0, 0, 0, time.UTC), time.Date(2025, 3, 11, 3, 0, 0, 0, time.UTC))
+	if len(sessions) != 2 || sessions[0].TradingDay != "2025-03-07" || sessions[1].TradingDay != "2025-03-10" {
+		t.Fatalf("expected the Friday and Monday sessions, got %+v", sessions)
+	}
+	if !sessions[1].Open.Equal(time.Date(2025, 3, 10, 13, 30, 0, 0, time.UTC)) || !sessions[1].Close.Equal(time.Date(2025, 3, 10, 20, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected Monday session: %+v", sessions[1])
+	}
+}
+
+// TestAssetClassHours tests that crypto trades around the clock, FX through the week and equities on the
+// exchange calendar
+func TestAssetClassHours(t *testing.T) {
+	mc := NewMarketCalendar(config.MarketHoursConfig{CollectSessions: models.CollectSessionsRegular})
+	at := func(day, hour int) time.Time {
+		return time.Date(2025, time.March, day, hour, 0, 0, 0, mc.Location())
+	}
+
+	tests := []struct {
+		symbol  string
+		t       time.Time
+		session models.MarketSession
+		collect bool
+	}{
+		{"AAPL", at(4, 11), models.SessionRegular, true},     // Tuesday morning
+		{"AAPL", at(4, 18), models.SessionPostMarket, false}, // only the regular session is collected
+		{"X:BTCUSD", at(8, 3), models.SessionRegular, true},  // Saturday night
+		{"C:EURUSD", at(4, 22), models.SessionRegular, true}, // Tuesday evening
+		{"C:EURUSD", at(7, 18), models.SessionClosed, false}, // Friday after the close
+		{"C:EURUSD", at(9, 12), models.SessionClosed, false}, // Sunday before the open
+		{"C:EURUSD", at(9, 18), models.SessionRegular, true}, // Sunday after the open
+	}
+	for _, tt := range tests {
+		if session := mc.SymbolSession(tt.symbol, tt.t); session != tt.session {
+			t.Errorf("%s at %s: expected %s, got %s", tt.symbol, tt.t.Format("Mon 15:04"), tt.session, session)
+		}
+		if collect := mc.ShouldCollectSymbol(tt.symbol, tt.t); collect != tt.collect {
+			t.Errorf("%s at %s: expected collect %v, got %v", tt.symbol, tt.t.Format("Mon 15:04"), tt.collect, collect)
+		}
+	}
+
+	bars := []*models.PriceData{{Symbol: "X:BTCUSD", Timestamp: at(8, 3)}, {Symbol: "AAPL", Timestamp: at(8, 3)}}
+	if regular := mc.RegularSessionBars(bars); len(regular) != 1 || regular[0].Symbol != "X:BTCUSD" {
+		t.Errorf("expected only the weekend crypto bar to be regular, got %+v", regular)
+	}
+}
+
+// TestValidateSymbol tests ticker validation per asset class and the Yahoo ticker of each
+func TestValidateSymbol(t *testing.T) {
+	valid := map[string]string{"brk.b": "BRK.B", "x:ethusd": "X:ETHUSD", "C:GBPJPY": "C:GBPJPY"}
+	for input, expected := range valid {
+		if symbol, err := models.ValidateSymbol(input); err != nil || symbol != expected {
+			t.Errorf("%s: expected %s, got %q (%v)", input, expected, symbol, err)
+		}
+	}
+	for _, input := range []string{"", "TOOLONGSYMBOL", "1ABC", "X:", "C:EUR", "BTC/USD"} {
+		if _, err := models.ValidateSymbol(input); err == nil {
+			t.Errorf("%q: expected a validation error", input)
+		}
+	}
+
+	yahoo := map[string]string{"AAPL": "AAPL", "X:BTCUSD": "BTC-USD", "C:EURUSD": "EURUSD=X"}
+	for symbol, expected := range yahoo {
+		if got := yahooSymbol(symbol); got != expected {
+			t.Errorf("%s: expected Yahoo ticker %s, got %s", symbol, expected, got)
+		}
+	}
+}
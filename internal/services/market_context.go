@@ -0,0 +1,273 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// Market context defaults
+var defaultBenchmarks = []string{"SPY", "QQQ", "IWM", "XLB", "XLC", "XLE", "XLF", "XLI", "XLK", "XLP", "XLRE", "XLU", "XLV", "XLY"}
+
+const (
+	marketContextTTL = 5 * time.Minute // how long a computed context is reused, setup detection runs per symbol
+
+	// Breadth that overrides the regime index: a rising index with fewer watched symbols above their
+	// 50-day SMA than breadthWeakPercent, or a falling one with more than breadthStrongPercent, is neutral
+	breadthWeakPercent   = 40.0
+	breadthStrongPercent = 60.0
+)
+
+// MarketContextService measures benchmark trends and watchlist breadth and derives the market regime
+type MarketContextService struct {
+	db          *database.Database
+	benchmarks  []string
+	regimeIndex string
+
+	mutex    sync.Mutex
+	cached   *models.MarketContext
+	cachedAt time.Time
+}
+
+// NewMarketContextService creates a new market context service
+func NewMarketContextService(cfg *config.Config, db *database.Database) *MarketContextService {
+	benchmarks := make([]string, 0, len(defaultBenchmarks)+1)
+	configured := cfg.Analytics.Benchmarks
+	if len(configured) == 0 {
+		configured = defaultBenchmarks
+	}
+	for _, symbol := range configured {
+		if symbol = strings.ToUpper(strings.TrimSpace(symbol)); symbol != "" && !slices.Contains(benchmarks, symbol) {
+			benchmarks = append(benchmarks, symbol)
+		}
+	}
+
+	// The relative strength benchmark is always collected
+	strengthBenchmark := strings.ToUpper(cfg.Analytics.Benchmark)
+	if strengthBenchmark == "" {
+		strengthBenchmark = defaultBenchmark
+	}
+	if !slices.Contains(benchmarks, strengthBenchmark) {
+		benchmarks = append(benchmarks, strengthBenchmark)
+	}
+
+	regimeIndex := strings.ToUpper(cfg.Analytics.RegimeIndex)
+	if regimeIndex == "" {
+		regimeIndex = strengthBenchmark
+	}
+	if !slices.Contains(benchmarks, regimeIndex) {
+		benchmarks = append(benchmarks, regimeIndex)
+	}
+
+	return &MarketContextService{
+		db:          db,
+		benchmarks:  benchmarks,
+		regimeIndex: regimeIndex,
+	}
+}
+
+// Benchmarks returns the symbols collected whether or not they are watched
+func (mcs *MarketContextService) Benchmarks() []string {
+	return slices.Clone(mcs.benchmarks)
+}
+
+// RegimeIndex returns the benchmark whose trend sets the market regime
+func (mcs *MarketContextService) RegimeIndex() string {
+	return mcs.regimeIndex
+}
+
+// Context returns the benchmark trends, watchlist breadth and market regime, reusing a context computed
+// in the last few minutes unless refresh is set
+func (mcs *MarketContextService) Context(refresh bool) (*models.MarketContext, error) {
+	mcs.mutex.Lock()
+	defer mcs.mutex.Unlock()
+
+	now := clockNow()
+	if !refresh && mcs.cached != nil && now.Sub(mcs.cachedAt) < marketContextTTL {
+		return mcs.cached, nil
+	}
+
+	mc, err := mcs.compute(now)
+	if err != nil {
+		return nil, err
+	}
+	mcs.cached = mc
+	mcs.cachedAt = now
+	return mc, nil
+}
+
+// Regime returns the current market regime, or an empty string when it can't be determined
+func (mcs *MarketContextService) Regime() string {
+	if mcs == nil {
+		return ""
+	}
+
+	mc, err := mcs.Context(false)
+	if err != nil {
+		log.Printf("Failed to compute market context: %v", err)
+		return ""
+	}
+	return mc.Regime
+}
+
+// compute builds the market context from stored daily bars
+func (mcs *MarketContextService) compute(now time.Time) (*models.MarketContext, error) {
+	// Calendar days covering 50 sessions plus the prior close, allowing for weekends and holidays
+	from := now.AddDate(0, 0, -(51*7/5 + 10))
+
+	mc := &models.MarketContext{
+		Regime:      models.MarketRegimeNeutral,
+		Benchmarks:  []*models.IndexTrend{},
+		Breadth:     &models.MarketBreadth{},
+		GeneratedAt: now,
+	}
+
+	for _, symbol := range mcs.benchmarks {
+		bars, err := mcs.db.GetPriceDataRangeTimeframe(symbol, from, now, models.Timeframe1d)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s daily bars: %w", symbol, err)
+		}
+		trend := indexTrend(symbol, bars)
+		if trend == nil {
+			mc.Skipped = append(mc.Skipped, symbol)
+			continue
+		}
+		mc.Benchmarks = append(mc.Benchmarks, trend)
+		if symbol == mcs.regimeIndex {
+			mc.Index = trend
+		}
+	}
+
+	watched, err := mcs.db.GetWatchedSymbols()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watched symbols: %w", err)
+	}
+	for _, symbol := range watched {
+		if slices.Contains(mcs.benchmarks, symbol) {
+			continue
+		}
+		bars, err := mcs.db.GetPriceDataRangeTimeframe(symbol, from, now, models.Timeframe1d)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s daily bars: %w", symbol, err)
+		}
+		trend := indexTrend(symbol, bars)
+		if trend == nil {
+			mc.Skipped = append(mc.Skipped, symbol)
+			continue
+		}
+		addBreadth(mc.Breadth, trend)
+	}
+	finishBreadth(mc.Breadth)
+
+	mc.Regime = marketRegime(mc.Index, mc.Breadth)
+	return mc, nil
+}
+
+// indexTrend measures the latest daily close against the 20 and 50 day SMAs, or nil with fewer than 20 closes
+func indexTrend(symbol string, bars []*models.PriceData) *models.IndexTrend {
+	if len(bars) < 20 {
+		return nil
+	}
+
+	closes := make([]float64, len(bars))
+	for i, bar := range bars {
+		closes[i] = bar.Close
+	}
+	last := bars[len(bars)-1]
+
+	trend := &models.IndexTrend{
+		Symbol: symbol,
+		Close:  last.Close,
+		SMA20:  trailingMean(closes, 20),
+		AsOf:   last.Timestamp,
+	}
+	if prior := closes[len(closes)-2]; prior > 0 {
+		trend.ChangePercent = (last.Close/prior - 1) * 100
+	}
+	trend.AboveSMA20 = last.Close > trend.SMA20
+	if len(closes) >= 50 {
+		trend.SMA50 = trailingMean(closes, 50)
+		trend.AboveSMA50 = last.Close > trend.SMA50
+	}
+
+	switch {
+	case trend.SMA50 == 0 && trend.AboveSMA20:
+		trend.Trend = models.MarketRegimeBullish
+	case trend.SMA50 == 0:
+		trend.Trend = models.MarketRegimeBearish
+	case trend.AboveSMA50 && trend.SMA20 > trend.SMA50:
+		trend.Trend = models.MarketRegimeBullish
+	case !trend.AboveSMA50 && trend.SMA20 < trend.SMA50:
+		trend.Trend = models.MarketRegimeBearish
+	default:
+		trend.Trend = models.MarketRegimeNeutral
+	}
+	return trend
+}
+
+// trailingMean averages the last period values
+func trailingMean(values []float64, period int) float64 {
+	sum := 0.0
+	for _, value := range values[len(values)-period:] {
+		sum += value
+	}
+	return sum / float64(period)
+}
+
+// addBreadth counts a watched symbol's trend into the breadth
+func addBreadth(breadth *models.MarketBreadth, trend *models.IndexTrend) {
+	breadth.Symbols++
+	if trend.AboveSMA20 {
+		breadth.AboveSMA20++
+	}
+	if trend.SMA50 > 0 {
+		breadth.WithSMA50++
+	}
+	if trend.AboveSMA50 {
+		breadth.AboveSMA50++
+	}
+	switch {
+	case trend.ChangePercent > 0:
+		breadth.Advancing++
+	case trend.ChangePercent < 0:
+		breadth.Declining++
+	default:
+		breadth.Unchanged++
+	}
+}
+
+// finishBreadth computes the breadth percentages and advance/decline line
+func finishBreadth(breadth *models.MarketBreadth) {
+	breadth.AdvanceDecline = breadth.Advancing - breadth.Declining
+	if breadth.Symbols > 0 {
+		breadth.AboveSMA20Percent = float64(breadth.AboveSMA20) / float64(breadth.Symbols) * 100
+	}
+	if breadth.WithSMA50 > 0 {
+		breadth.AboveSMA50Percent = float64(breadth.AboveSMA50) / float64(breadth.WithSMA50) * 100
+	}
+}
+
+// marketRegime takes the regime index's trend, turned neutral when the watchlist breadth disagrees with it
+func marketRegime(index *models.IndexTrend, breadth *models.MarketBreadth) string {
+	if index == nil {
+		return models.MarketRegimeNeutral
+	}
+	if breadth == nil || breadth.WithSMA50 == 0 {
+		return index.Trend
+	}
+
+	switch {
+	case index.Trend == models.MarketRegimeBullish && breadth.AboveSMA50Percent < breadthWeakPercent:
+		return models.MarketRegimeNeutral
+	case index.Trend == models.MarketRegimeBearish && breadth.AboveSMA50Percent > breadthStrongPercent:
+		return models.MarketRegimeNeutral
+	}
+	return index.Trend
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// rampCloses returns n closes starting at start and moving step per session
+func rampCloses(n int, start, step float64) []float64 {
+	closes := make([]float64, n)
+	for i := range closes {
+		closes[i] = start + float64(i)*step
+	}
+	return closes
+}
+
+// TestIndexTrend tests SMA placement and trend classification from daily closes
+func TestIndexTrend(t *testing.T) {
+	if trend := indexTrend("SPY", dailyBars(rampCloses(19, 100, 1))); trend != nil {
+		t.Fatalf("expected no trend with fewer than 20 closes, got %+v", trend)
+	}
+
+	rising := indexTrend("SPY", dailyBars(rampCloses(60, 100, 1)))
+	if rising == nil || rising.Trend != models.MarketRegimeBullish || !rising.AboveSMA20 || !rising.AboveSMA50 {
+		t.Fatalf("expected a bullish trend above both SMAs, got %+v", rising)
+	}
+	// Last 20 closes are 140..159
+	if math.Abs(rising.SMA20-149.5) > 1e-9 || math.Abs(rising.ChangePercent-(159.0/158-1)*100) > 1e-9 {
+		t.Errorf("unexpected SMA20 %.4f or change %.4f", rising.SMA20, rising.ChangePercent)
+	}
+
+	falling := indexTrend("QQQ", dailyBars(rampCloses(60, 200, -1)))
+	if falling == nil || falling.Trend != models.MarketRegimeBearish || falling.AboveSMA50 {
+		t.Fatalf("expected a bearish trend below the SMA50, got %+v", falling)
+	}
+
+	// A pullback under the 20-day SMA holding the 50-day stays bullish; a drop under the 50-day is neutral
+	pullback := append(rampCloses(59, 100, 1), 140)
+	if trend := indexTrend("IWM", dailyBars(pullback)); trend == nil || trend.Trend != models.MarketRegimeBullish || trend.AboveSMA20 {
+		t.Errorf("expected a bullish trend after a pullback under the SMA20, got %+v", trend)
+	}
+	drop := append(rampCloses(59, 100, 1), 120)
+	if trend := indexTrend("IWM", dailyBars(drop)); trend == nil || trend.Trend != models.MarketRegimeNeutral {
+		t.Errorf("expected a neutral trend after a drop under the SMA50, got %+v", trend)
+	}
+
+	// Without 50 closes the SMA20 alone decides
+	short := indexTrend("XLK", dailyBars(rampCloses(30, 100, 1)))
+	if short == nil || short.SMA50 != 0 || short.Trend != models.MarketRegimeBullish {
+		t.Errorf("expected a bullish trend from the SMA20 alone, got %+v", short)
+	}
+}
+
+// TestMarketRegimeBreadth tests breadth counts and breadth overriding the regime index
+func TestMarketRegimeBreadth(t *testing.T) {
+	breadth := &models.MarketBreadth{}
+	addBreadth(breadth, indexTrend("AAPL", dailyBars(rampCloses(60, 100, -1))))
+	addBreadth(breadth, indexTrend("MSFT", dailyBars(rampCloses(60, 100, -1))))
+	addBreadth(breadth, indexTrend("NVDA", dailyBars(rampCloses(60, 100, 1))))
+	addBreadth(breadth, indexTrend("ARM", dailyBars(rampCloses(30, 100, 0))))
+	finishBreadth(breadth)
+
+	if breadth.Symbols != 4 || breadth.WithSMA50 != 3 || breadth.AboveSMA50 != 1 {
+		t.Fatalf("unexpected breadth counts: %+v", breadth)
+	}
+	if breadth.Advancing != 1 || breadth.Declining != 2 || breadth.Unchanged != 1 || breadth.AdvanceDecline != -1 {
+		t.Errorf("unexpected advance/decline: %+v", breadth)
+	}
+	if math.Abs(breadth.AboveSMA50Percent-100.0/3) > 1e-9 || breadth.AboveSMA20Percent != 25 {
+		t.Errorf("unexpected breadth percentages: %+v", breadth)
+	}
+
+	rising := indexTrend("SPY", dailyBars(rampCloses(60, 100, 1)))
+	if regime := marketRegime(rising, breadth); regime != models.MarketRegimeNeutral {
+		t.Errorf("expected weak breadth to turn a rising index neutral, got %s", regime)
+	}
+	if regime := marketRegime(rising, &models.MarketBreadth{}); regime != models.MarketRegimeBullish {
+		t.Errorf("expected the index trend without breadth, got %s", regime)
+	}
+	if regime := marketRegime(nil, breadth); regime != models.MarketRegimeNeutral {
+		t.Errorf("expected a neutral regime without index history, got %s", regime)
+	}
+}
+
+// TestMarketContextBenchmarks tests the benchmark set always includes the strength and regime indexes
+func TestMarketContextBenchmarks(t *testing.T) {
+	cfg := &config.Config{}
+	if got := NewMarketContextService(cfg, nil).Benchmarks(); len(got) != len(defaultBenchmarks) || got[0] != "SPY" {
+		t.Errorf("expected the default benchmarks, got %v", got)
+	}
+
+	cfg.Analytics.Benchmarks = []string{"qqq", "QQQ", " xlk "}
+	cfg.Analytics.Benchmark = "spy"
+	cfg.Analytics.RegimeIndex = "dia"
+	service := NewMarketContextService(cfg, nil)
+	want := []string{"QQQ", "XLK", "SPY", "DIA"}
+	got := service.Benchmarks()
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+	if service.regimeIndex != "DIA" {
+		t.Errorf("expected regime index DIA, got %s", service.regimeIndex)
+	}
+}
+
+// TestApplyMarketRegime tests setups trading against the regime lose the regime penalty
+func TestApplyMarketRegime(t *testing.T) {
+	sds := NewSetupDetectionService(nil, nil, nil)
+
+	against := &models.TradingSetup{Direction: "bearish", QualityScore: 70, ScoreBreakdown: &models.ScoreBreakdown{}}
+	sds.applyMarketRegime(against, models.MarketRegimeBullish)
+	if against.MarketRegime != models.MarketRegimeBullish || against.RegimePenalty != 10 || against.QualityScore != 60 {
+		t.Fatalf("expected a 10 point penalty against a bullish market, got %+v", against)
+	}
+	if len(against.ScoreBreakdown.Adjustments) != 1 || against.ScoreBreakdown.Adjustments[0].Key != "market_regime" {
+		t.Errorf("expected a market regime adjustment, got %+v", against.ScoreBreakdown.Adjustments)
+	}
+
+	with := &models.TradingSetup{Direction: "bullish", QualityScore: 70}
+	sds.applyMarketRegime(with, models.MarketRegimeBullish)
+	if with.RegimePenalty != 0 || with.QualityScore != 70 || with.MarketRegime != models.MarketRegimeBullish {
+		t.Errorf("expected no penalty trading with the regime, got %+v", with)
+	}
+
+	neutral := &models.TradingSetup{Direction: "bullish", QualityScore: 70}
+	sds.applyMarketRegime(neutral, models.MarketRegimeNeutral)
+	if neutral.RegimePenalty != 0 || neutral.QualityScore != 70 {
+		t.Errorf("expected no penalty in a neutral market, got %+v", neutral)
+	}
+}
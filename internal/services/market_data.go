@@ -0,0 +1,61 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// Supported market data providers
+const (
+	ProviderPolygon = "polygon"
+	ProviderYahoo   = "yahoo"
+	ProviderReplay  = "replay" // Stored bars served on a simulated clock, selected by replay mode
+)
+
+// MarketDataProvider is the source of price and volume bars used by the collector
+type MarketDataProvider interface {
+	// Name returns the provider identifier (e.g. "polygon", "yahoo")
+	Name() string
+
+	// GetLatestAggregates fetches volume bars for the last N minutes
+	GetLatestAggregates(symbol string, minutes int) ([]*models.VolumeData, error)
+
+	// GetLatestPriceAggregates fetches OHLC bars for the last N minutes
+	GetLatestPriceAggregates(symbol string, minutes int) ([]*models.PriceData, error)
+
+	// GetHistoricalData fetches volume bars for the last N days
+	GetHistoricalData(symbol string, days int) ([]*models.VolumeData, error)
+
+	// GetHistoricalPriceData fetches OHLC bars for the last N days
+	GetHistoricalPriceData(symbol string, days int) ([]*models.PriceData, error)
+
+	// GetAggregates fetches volume bars between two times
+	GetAggregates(symbol string, from, to time.Time) ([]*models.VolumeData, error)
+
+	// GetPriceAggregates fetches OHLC bars between two times
+	GetPriceAggregates(symbol string, from, to time.Time) ([]*models.PriceData, error)
+
+	// HealthCheck verifies the provider is reachable and credentials are valid
+	HealthCheck() error
+}
+
+// NewMarketDataProvider creates the market data provider selected in the configuration
+func NewMarketDataProvider(cfg *config.Config) (MarketDataProvider, error) {
+	switch strings.ToLower(cfg.MarketData.Provider) {
+	case "", ProviderPolygon:
+		return NewPolygonService(cfg), nil
+	case ProviderYahoo:
+		return NewYahooService(cfg), nil
+	default:
+		return nil, fmt.Errorf("unsupported market data provider: %s", cfg.MarketData.Provider)
+	}
+}
+
+// Name returns the provider identifier
+func (ps *PolygonService) Name() string {
+	return ProviderPolygon
+}
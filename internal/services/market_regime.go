@@ -0,0 +1,170 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// Market regime classification defaults
+const (
+	defaultRegimeTrendADX        = 25.0
+	defaultRegimeVolatilityRatio = 1.5
+
+	regimeLookbackDays = 365 // calendar days of daily bars classified on each refresh
+	regimeMinBars      = 51  // daily bars a label needs, for the 50-day ATR
+	regimeTTL          = 5 * time.Minute
+)
+
+// MarketRegimeService classifies the regime index's trading days as trending up or down, choppy or highly
+// volatile from its 50-day SMA, ADX and ATR, and stores the daily labels
+type MarketRegimeService struct {
+	db              *database.Database
+	index           string
+	trendADX        float64
+	volatilityRatio float64
+
+	mutex     sync.Mutex
+	current   *models.MarketRegimeLabel
+	checkedAt time.Time
+}
+
+// NewMarketRegimeService creates a new market regime service classifying the given index
+func NewMarketRegimeService(cfg *config.Config, db *database.Database, index string) *MarketRegimeService {
+	trendADX := cfg.Analytics.RegimeTrendADX
+	if trendADX == 0 {
+		trendADX = defaultRegimeTrendADX
+	}
+	volatilityRatio := cfg.Analytics.RegimeVolatilityRatio
+	if volatilityRatio == 0 {
+		volatilityRatio = defaultRegimeVolatilityRatio
+	}
+
+	return &MarketRegimeService{
+		db:              db,
+		index:           index,
+		trendADX:        trendADX,
+		volatilityRatio: volatilityRatio,
+	}
+}
+
+// Current returns the latest regime label, classifying the stored daily bars again when the last check is
+// a few minutes old or refresh is set
+func (mrs *MarketRegimeService) Current(refresh bool) (*models.MarketRegimeLabel, error) {
+	mrs.mutex.Lock()
+	defer mrs.mutex.Unlock()
+
+	now := clockNow()
+	if refresh || mrs.checkedAt.IsZero() || now.Sub(mrs.checkedAt) >= regimeTTL {
+		label, err := mrs.refresh(now)
+		if err != nil {
+			return nil, err
+		}
+		mrs.current = label
+		mrs.checkedAt = now
+	}
+
+	if mrs.current == nil {
+		return nil, fmt.Errorf("regime of %s needs %d daily bars; backfill it with POST /api/jobs/backfill: %w", mrs.index, regimeMinBars, database.ErrNotFound)
+	}
+	return mrs.current, nil
+}
+
+// Regime returns the current regime, or an empty string when it can't be classified
+func (mrs *MarketRegimeService) Regime() string {
+	if mrs == nil {
+		return ""
+	}
+
+	label, err := mrs.Current(false)
+	if err != nil {
+		if !errors.Is(err, database.ErrNotFound) {
+			log.Printf("Failed to classify market regime: %v", err)
+		}
+		return ""
+	}
+	return label.Regime
+}
+
+// History returns the stored regime labels of the last days, newest first
+func (mrs *MarketRegimeService) History(days int) ([]*models.MarketRegimeLabel, error) {
+	since := database.TradingDate(clockNow().AddDate(0, 0, -days))
+	return mrs.db.GetMarketRegimes(mrs.index, since)
+}
+
+// refresh classifies every trading day in the lookback with enough history, stores the labels and returns the
+// latest, or nil without enough history
+func (mrs *MarketRegimeService) refresh(now time.Time) (*models.MarketRegimeLabel, error) {
+	bars, err := mrs.db.GetPriceDataRangeTimeframe(mrs.index, now.AddDate(0, 0, -regimeLookbackDays), now, models.Timeframe1d)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s daily bars: %w", mrs.index, err)
+	}
+
+	labels := classifyRegimes(mrs.index, bars, mrs.trendADX, mrs.volatilityRatio)
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	if err := mrs.db.UpsertMarketRegimes(labels); err != nil {
+		return nil, err
+	}
+	return labels[len(labels)-1], nil
+}
+
+// classifyRegimes labels each daily bar that has regimeMinBars bars of history up to it, oldest first
+func classifyRegimes(symbol string, bars []*models.PriceData, trendADX, volatilityRatio float64) []*models.MarketRegimeLabel {
+	if len(bars) < regimeMinBars {
+		return nil
+	}
+
+	highs := make([]float64, len(bars))
+	lows := make([]float64, len(bars))
+	closes := make([]float64, len(bars))
+	for i, bar := range bars {
+		highs[i], lows[i], closes[i] = bar.High, bar.Low, bar.Close
+	}
+
+	labels := make([]*models.MarketRegimeLabel, 0, len(bars)-regimeMinBars+1)
+	for end := regimeMinBars; end <= len(bars); end++ {
+		h, l, c := highs[:end], lows[:end], closes[:end]
+		label := &models.MarketRegimeLabel{
+			Symbol:    symbol,
+			TradeDate: database.TradingDate(bars[end-1].Timestamp),
+			Close:     c[end-1],
+			SMA50:     trailingMean(c, 50),
+		}
+		label.ADX, label.PlusDI, label.MinusDI = averageDirectionalIndex(h, l, c, 14)
+
+		atr14 := averageTrueRange(h, l, c, 14)
+		if label.Close > 0 {
+			label.ATRPercent = atr14 / label.Close * 100
+		}
+		if atr50 := averageTrueRange(h, l, c, 50); atr50 > 0 {
+			label.VolatilityRatio = atr14 / atr50
+		}
+
+		label.Regime = classifyRegime(label, trendADX, volatilityRatio)
+		labels = append(labels, label)
+	}
+	return labels
+}
+
+// classifyRegime picks a day's regime: high volatility first, then an ADX trend agreeing with price against
+// the 50-day SMA and the directional indicators, otherwise choppy
+func classifyRegime(label *models.MarketRegimeLabel, trendADX, volatilityRatio float64) string {
+	switch {
+	case label.VolatilityRatio >= volatilityRatio:
+		return models.RegimeHighVolatility
+	case label.ADX >= trendADX && label.Close > label.SMA50 && label.PlusDI > label.MinusDI:
+		return models.RegimeTrendingUp
+	case label.ADX >= trendADX && label.Close < label.SMA50 && label.MinusDI > label.PlusDI:
+		return models.RegimeTrendingDown
+	default:
+		return models.RegimeChoppy
+	}
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// regimeBars builds daily bars mid-session around the closes, each spanning ranges[i] either side, or 1 when
+// no range is given
+func regimeBars(symbol string, closes []float64, ranges map[int]float64) []*models.PriceData {
+	start := time.Date(2025, 1, 6, 15, 0, 0, 0, time.UTC)
+	bars := make([]*models.PriceData, len(closes))
+	for i, close := range closes {
+		spread := 1.0
+		if r, ok := ranges[i]; ok {
+			spread = r
+		}
+		bars[i] = &models.PriceData{
+			Symbol: symbol, Timestamp: start.AddDate(0, 0, i),
+			Open: close, High: close + spread, Low: close - spread, Close: close, Volume: 1000,
+		}
+	}
+	return bars
+}
+
+// TestClassifyRegimes tests trend, chop and volatility labels from daily bars
+func TestClassifyRegimes(t *testing.T) {
+	choppy := make([]float64, 60)
+	for i := range choppy {
+		choppy[i] = 100 + float64(i%2)
+	}
+	volatile := map[int]float64{}
+	for i := 50; i < 60; i++ {
+		volatile[i] = 5
+	}
+
+	tests := []struct {
+		name   string
+		bars   []*models.PriceData
+		regime string
+	}{
+		{"uptrend", regimeBars("SPY", rampCloses(60, 100, 1), nil), models.RegimeTrendingUp},
+		{"downtrend", regimeBars("SPY", rampCloses(60, 200, -1), nil), models.RegimeTrendingDown},
+		{"chop", regimeBars("SPY", choppy, nil), models.RegimeChoppy},
+		{"volatility spike", regimeBars("SPY", choppy, volatile), models.RegimeHighVolatility},
+	}
+	for _, tt := range tests {
+		labels := classifyRegimes("SPY", tt.bars, defaultRegimeTrendADX, defaultRegimeVolatilityRatio)
+		if len(labels) != 60-regimeMinBars+1 {
+			t.Fatalf("%s: expected %d labels, got %d", tt.name, 60-regimeMinBars+1, len(labels))
+		}
+		if last := labels[len(labels)-1]; last.Regime != tt.regime {
+			t.Errorf("%s: expected %s, got %+v", tt.name, tt.regime, last)
+		}
+	}
+
+	if labels := classifyRegimes("SPY", regimeBars("SPY", rampCloses(regimeMinBars-1, 100, 1), nil), 25, 1.5); labels != nil {
+		t.Errorf("expected no labels without %d bars, got %d", regimeMinBars, len(labels))
+	}
+}
+
+// TestRegimeGating tests counter-trend bounces and strategies declaring regimes leave setups out
+func TestRegimeGating(t *testing.T) {
+	db := newTestDB(t)
+
+	strategy, err := db.CreateStrategy(models.Strategy{Name: "Swing"})
+	if err != nil {
+		t.Fatalf("CreateStrategy failed: %v", err)
+	}
+	stock, err := db.AddStock(models.Stock{Symbol: "TEST", Name: "Test Corp"})
+	if err != nil {
+		t.Fatalf("AddStock failed: %v", err)
+	}
+	if err := db.AddStockToStrategy(stock.ID, strategy.ID); err != nil {
+		t.Fatalf("AddStockToStrategy failed: %v", err)
+	}
+
+	newSetups := func() []*models.TradingSetup {
+		return []*models.TradingSetup{
+			{SetupType: "support_bounce", Direction: "bullish"},
+			{SetupType: "resistance_breakout", Direction: "bullish"},
+			{SetupType: "support_breakdown", Direction: "bearish"},
+		}
+	}
+
+	sds := NewSetupDetectionService(db, nil, nil)
+	result := &models.SetupDetectionResult{}
+	kept := sds.gateByRegime(context.Background(), "TEST", newSetups(), models.RegimeTrendingDown, result)
+	if len(kept) != 2 || len(result.Suppressed) != 1 || result.Suppressed[0] != "support_bounce" {
+		t.Fatalf("expected only the support bounce left out in a downtrend, kept %d, suppressed %v", len(kept), result.Suppressed)
+	}
+
+	regimes := &models.StrategyRegimes{StrategyID: strategy.ID, Long: []string{models.RegimeTrendingUp}, UpdatedAt: time.Now()}
+	if err := db.SetStrategyRegimes(regimes); err != nil {
+		t.Fatalf("SetStrategyRegimes failed: %v", err)
+	}
+	result = &models.SetupDetectionResult{}
+	kept = sds.gateByRegime(context.Background(), "TEST", newSetups(), models.RegimeTrendingDown, result)
+	if len(kept) != 1 || kept[0].Direction != "bearish" || len(result.Suppressed) != 2 {
+		t.Fatalf("expected only the bearish setup kept for a long-only uptrend strategy, kept %d, suppressed %v", len(kept), result.Suppressed)
+	}
+	if kept = sds.gateByRegime(context.Background(), "TEST", newSetups(), models.RegimeTrendingUp, &models.SetupDetectionResult{}); len(kept) != 3 {
+		t.Errorf("expected every setup kept in an uptrend, got %d", len(kept))
+	}
+
+	stored, err := db.GetStrategyRegimes(strategy.ID)
+	if err != nil || stored == nil || len(stored.Long) != 1 || len(stored.Short) != 0 {
+		t.Fatalf("expected the stored declaration back, got %+v (%v)", stored, err)
+	}
+	if err := db.SetStrategyRegimes(&models.StrategyRegimes{StrategyID: strategy.ID, UpdatedAt: time.Now()}); err != nil {
+		t.Fatalf("SetStrategyRegimes failed: %v", err)
+	}
+	if stored, err := db.GetStrategyRegimes(strategy.ID); err != nil || stored != nil {
+		t.Errorf("expected empty lists to remove the declaration, got %+v (%v)", stored, err)
+	}
+}
+
+// TestMarketRegimeLabelsStored tests the current label is classified from stored bars and kept daily
+func TestMarketRegimeLabelsStored(t *testing.T) {
+	cfg := &config.Config{}
+	db := newTestDB(t)
+
+	bars := regimeBars("SPY", rampCloses(60, 100, 1), nil)
+	SetClock(NewSimulatedClock(bars[len(bars)-1].Timestamp.Add(time.Hour)))
+	t.Cleanup(func() { SetClock(nil) })
+
+	service := NewMarketRegimeService(cfg, db, "SPY")
+	if _, err := service.Current(true); err == nil {
+		t.Fatal("expected an error without daily history")
+	}
+	if regime := service.Regime(); regime != "" {
+		t.Errorf("expected no regime without daily history, got %s", regime)
+	}
+
+	if err := db.InsertPriceDataBatch(bars); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+	label, err := service.Current(true)
+	if err != nil {
+		t.Fatalf("Current failed: %v", err)
+	}
+	if label.Regime != models.RegimeTrendingUp || label.TradeDate != database.TradingDate(bars[len(bars)-1].Timestamp) {
+		t.Errorf("unexpected current label %+v", label)
+	}
+
+	history, err := service.History(30)
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(history) != 60-regimeMinBars+1 || history[0].TradeDate != label.TradeDate {
+		t.Errorf("expected %d stored labels, newest first, got %d", 60-regimeMinBars+1, len(history))
+	}
+}
@@ -0,0 +1,345 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// News defaults
+const (
+	DefaultNewsRefreshInterval = 30 * time.Minute
+	defaultNewsArticleLimit    = 20
+	defaultNewsRetentionDays   = 30
+)
+
+// sentimentThreshold is the score beyond which a headline is tagged positive or negative
+const sentimentThreshold = 0.2
+
+// positiveNewsWords are headline words that usually signal good news for the stock
+var positiveNewsWords = map[string]bool{
+	"beat": true, "beats": true, "surge": true, "surges": true, "soar": true, "soars": true,
+	"jump": true, "jumps": true, "rally": true, "rallies": true, "gain": true, "gains": true,
+	"record": true, "upgrade": true, "upgraded": true, "upgrades": true, "outperform": true,
+	"bullish": true, "growth": true, "profit": true, "profits": true, "raise": true, "raises": true,
+	"raised": true, "strong": true, "approval": true, "approved": true, "buyback": true,
+	"partnership": true, "expands": true, "tops": true, "exceeds": true, "boost": true, "boosts": true,
+}
+
+// negativeNewsWords are headline words that usually signal bad news for the stock
+var negativeNewsWords = map[string]bool{
+	"miss": true, "misses": true, "missed": true, "plunge": true, "plunges": true, "slump": true,
+	"slumps": true, "fall": true, "falls": true, "drop": true, "drops": true, "tumble": true,
+	"tumbles": true, "downgrade": true, "downgraded": true, "downgrades": true, "underperform": true,
+	"bearish": true, "loss": true, "losses": true, "cut": true, "cuts": true, "lawsuit": true,
+	"probe": true, "investigation": true, "recall": true, "weak": true, "layoffs": true,
+	"warns": true, "warning": true, "fraud": true, "bankruptcy": true, "decline": true, "declines": true,
+}
+
+// NewsService fetches and stores recent headlines for watched symbols
+type NewsService struct {
+	cfg       *config.Config
+	db        *database.Database
+	client    *http.Client
+	enabled   bool
+	interval  time.Duration
+	limit     int
+	retention int
+	schedules *ScheduleControl
+	stop      chan struct{}
+	stopOnce  sync.Once
+	wg        sync.WaitGroup // tracks the background refresh loop
+}
+
+// polygonNewsResponse represents the response from the Polygon news endpoint
+type polygonNewsResponse struct {
+	Status  string              `json:"status"`
+	Results []polygonNewsResult `json:"results"`
+}
+
+// polygonNewsResult represents a single news article
+type polygonNewsResult struct {
+	ID        string `json:"id"`
+	Publisher struct {
+		Name string `json:"name"`
+	} `json:"publisher"`
+	Title        string    `json:"title"`
+	Author       string    `json:"author"`
+	PublishedUTC time.Time `json:"published_utc"`
+	ArticleURL   string    `json:"article_url"`
+	ImageURL     string    `json:"image_url"`
+	Description  string    `json:"description"`
+	Keywords     []string  `json:"keywords"`
+}
+
+// NewNewsService creates a new news service
+func NewNewsService(cfg *config.Config, db *database.Database) *NewsService {
+	interval := cfg.News.RefreshInterval
+	if interval <= 0 {
+		interval = DefaultNewsRefreshInterval
+	}
+
+	limit := cfg.News.ArticlesPerSymbol
+	if limit <= 0 {
+		limit = defaultNewsArticleLimit
+	}
+
+	retention := cfg.News.RetentionDays
+	if retention <= 0 {
+		retention = defaultNewsRetentionDays
+	}
+
+	timeout := cfg.Polygon.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	return &NewsService{
+		cfg:       cfg,
+		db:        db,
+		client:    &http.Client{Timeout: timeout},
+		enabled:   cfg.News.Enabled,
+		interval:  interval,
+		limit:     limit,
+		retention: retention,
+		stop:      make(chan struct{}),
+	}
+}
+
+// SetScheduleControl sets the control that can disable the news refresh
+func (ns *NewsService) SetScheduleControl(schedules *ScheduleControl) {
+	ns.schedules = schedules
+}
+
+// Start fetches headlines now and then periodically when news ingestion is enabled
+func (ns *NewsService) Start() {
+	if !ns.enabled {
+		log.Printf("News ingestion disabled")
+		return
+	}
+
+	log.Printf("Starting news ingestion (every %v)...", ns.interval)
+
+	ns.wg.Add(1)
+	go func() {
+		defer ns.wg.Done()
+
+		ticker := time.NewTicker(ns.interval)
+		defer ticker.Stop()
+
+		for {
+			ns.scheduledRefresh()
+
+			select {
+			case <-ticker.C:
+			case <-ns.stop:
+				return
+			}
+		}
+	}()
+}
+
+// scheduledRefresh refreshes the headlines unless the news schedule is disabled
+func (ns *NewsService) scheduledRefresh() {
+	if ns.schedules.Skip(models.ScheduleNews) {
+		return
+	}
+	if err := ns.RefreshAll(); err != nil {
+		log.Printf("News refresh failed: %v", err)
+	}
+}
+
+// Stop stops the refresh loop and waits for an in-flight refresh to finish
+func (ns *NewsService) Stop(ctx context.Context) error {
+	ns.stopOnce.Do(func() { close(ns.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		ns.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Printf("News service stopped")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for news refresh to finish: %w", ctx.Err())
+	}
+}
+
+// RefreshAll fetches headlines for every watched symbol and prunes old articles
+func (ns *NewsService) RefreshAll() error {
+	symbols, err := ns.db.GetWatchedSymbols()
+	if err != nil {
+		return fmt.Errorf("failed to get watched symbols: %w", err)
+	}
+
+	stored := 0
+	for _, symbol := range symbols {
+		count, err := ns.RefreshSymbol(symbol)
+		if err != nil {
+			log.Printf("Failed to refresh news for %s: %v", symbol, err)
+			continue
+		}
+		stored += count
+	}
+
+	if _, err := ns.db.DeleteNewsArticlesBefore(time.Now().AddDate(0, 0, -ns.retention)); err != nil {
+		log.Printf("Failed to prune old news articles: %v", err)
+	}
+
+	log.Printf("News refreshed: %d articles for %d symbols", stored, len(symbols))
+	return nil
+}
+
+// RefreshSymbol fetches, tags and stores recent headlines for a symbol
+func (ns *NewsService) RefreshSymbol(symbol string) (int, error) {
+	articles, err := ns.FetchNews(symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	stored := 0
+	for _, article := range articles {
+		if err := ns.db.UpsertNewsArticle(article); err != nil {
+			log.Printf("Failed to store news article for %s: %v", symbol, err)
+			continue
+		}
+		stored++
+	}
+
+	return stored, nil
+}
+
+// FetchNews fetches recent headlines for a symbol from Polygon
+func (ns *NewsService) FetchNews(symbol string) ([]*models.NewsArticle, error) {
+	params := url.Values{}
+	params.Set("ticker", symbol)
+	params.Set("order", "desc")
+	params.Set("sort", "published_utc")
+	params.Set("limit", strconv.Itoa(ns.limit))
+	params.Set("apiKey", ns.cfg.Polygon.APIKey)
+
+	req, err := http.NewRequest("GET", ns.cfg.Polygon.BaseURL+"/v2/reference/news?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := ns.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var newsResp polygonNewsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&newsResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	articles := make([]*models.NewsArticle, 0, len(newsResp.Results))
+	for _, result := range newsResp.Results {
+		sentiment, score := ScoreSentiment(result.Title + " " + result.Description)
+		articles = append(articles, &models.NewsArticle{
+			Symbol:         symbol,
+			ArticleID:      result.ID,
+			Title:          result.Title,
+			Author:         result.Author,
+			Publisher:      result.Publisher.Name,
+			URL:            result.ArticleURL,
+			ImageURL:       result.ImageURL,
+			Description:    result.Description,
+			Keywords:       strings.Join(result.Keywords, ","),
+			PublishedAt:    result.PublishedUTC,
+			Sentiment:      sentiment,
+			SentimentScore: score,
+		})
+	}
+
+	return articles, nil
+}
+
+// GetNews returns stored headlines for a symbol, newest first
+func (ns *NewsService) GetNews(symbol string, limit int) ([]*models.NewsArticle, error) {
+	return ns.db.GetNewsArticles(&models.NewsFilter{Symbol: symbol, Limit: limit})
+}
+
+// GetSentimentSummary counts a symbol's headlines by sentiment over the given number of days
+func (ns *NewsService) GetSentimentSummary(symbol string, days int) (*models.NewsSentimentSummary, error) {
+	articles, err := ns.db.GetNewsArticles(&models.NewsFilter{
+		Symbol: symbol,
+		Since:  time.Now().AddDate(0, 0, -days),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.NewsSentimentSummary{Symbol: symbol}
+	total := 0.0
+	for _, article := range articles {
+		switch article.Sentiment {
+		case models.NewsSentimentPositive:
+			summary.Positive++
+		case models.NewsSentimentNegative:
+			summary.Negative++
+		default:
+			summary.Neutral++
+		}
+		total += article.SentimentScore
+	}
+	if len(articles) > 0 {
+		summary.AverageScore = total / float64(len(articles))
+	}
+
+	return summary, nil
+}
+
+// ScoreSentiment tags text by counting positive and negative keywords, returning the tag
+// and a score from -1 (all negative) to 1 (all positive)
+func ScoreSentiment(text string) (string, float64) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	positive, negative := 0, 0
+	for _, word := range words {
+		if positiveNewsWords[word] {
+			positive++
+		}
+		if negativeNewsWords[word] {
+			negative++
+		}
+	}
+
+	if positive+negative == 0 {
+		return models.NewsSentimentNeutral, 0
+	}
+
+	score := float64(positive-negative) / float64(positive+negative)
+	switch {
+	case score > sentimentThreshold:
+		return models.NewsSentimentPositive, score
+	case score < -sentimentThreshold:
+		return models.NewsSentimentNegative, score
+	default:
+		return models.NewsSentimentNeutral, score
+	}
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"testing"
+
+	"market-watch-go/internal/models"
+)
+
+// TestScoreSentiment tests keyword-based headline sentiment tagging
+func TestScoreSentiment(t *testing.T) {
+	cases := []struct {
+		text      string
+		sentiment string
+	}{
+		{"Apple beats estimates as iPhone sales surge to record", models.NewsSentimentPositive},
+		{"Tesla shares plunge after deliveries miss; analysts downgrade", models.NewsSentimentNegative},
+		{"Nvidia beats estimates but warns of weak guidance", models.NewsSentimentNegative},
+		{"Microsoft gains on upgrade despite lawsuit", models.NewsSentimentPositive},
+		{"Amazon to hold annual shareholder meeting", models.NewsSentimentNeutral},
+		{"Profit falls", models.NewsSentimentNeutral},
+	}
+
+	for _, tc := range cases {
+		if got, score := ScoreSentiment(tc.text); got != tc.sentiment {
+			t.Errorf("ScoreSentiment(%q) = %s (%.2f), want %s", tc.text, got, score, tc.sentiment)
+		}
+	}
+}
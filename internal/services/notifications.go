@@ -0,0 +1,61 @@
+package services
+
+import (
+	"log"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// NotificationService records pattern, setup, alert rule, anomaly and system events in the in-app notification
+// center, so alerts are visible on the dashboard without email or Telegram configured
+type NotificationService struct {
+	db        *database.Database
+	streaming *StreamingService
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(db *database.Database) *NotificationService {
+	return &NotificationService{db: db}
+}
+
+// SetStreamingService pushes new notifications to connected dashboards
+func (ns *NotificationService) SetStreamingService(streaming *StreamingService) {
+	ns.streaming = streaming
+}
+
+// notificationLinks are the dashboard pages notifications of each category link to by default
+var notificationLinks = map[string]string{
+	models.NotificationPattern: "/pattern-watcher",
+	models.NotificationSetup:   "/watchlist",
+	models.NotificationAlert:   "/",
+	models.NotificationAnomaly: "/",
+	models.NotificationSystem:  "/",
+}
+
+// Notify stores a notification; failures are logged, as a missed notification must not fail detection
+func (ns *NotificationService) Notify(notification *models.Notification) {
+	if ns == nil {
+		return
+	}
+	if notification.Severity == "" {
+		notification.Severity = EmailSeverityLow
+	}
+	if notification.Link == "" {
+		notification.Link = notificationLinks[notification.Category]
+	}
+	notification.CreatedAt = clockNow()
+
+	if err := ns.db.InsertNotification(notification); err != nil {
+		log.Printf("Failed to store %s notification %q: %v", notification.Category, notification.Title, err)
+		return
+	}
+
+	if ns.streaming != nil {
+		ns.streaming.Broadcast(&StreamMessage{
+			Type:      StreamTypeNotification,
+			Timestamp: notification.CreatedAt,
+			Data:      notification,
+		})
+	}
+}
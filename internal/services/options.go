@@ -0,0 +1,380 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// Options collection defaults
+const (
+	DefaultOptionsInterval         = time.Hour
+	defaultOptionsMaxContracts     = 1000
+	defaultUnusualVolumeRatio      = 2.0
+	defaultMinUnusualVolume        = 500
+	maxUnusualContractsPerSnapshot = 20
+)
+
+// OptionsService collects options chain snapshots and summarizes options flow
+type OptionsService struct {
+	cfg          *config.Config
+	db           *database.Database
+	client       *http.Client
+	enabled      bool
+	interval     time.Duration
+	maxContracts int
+	unusualRatio float64
+	minUnusual   int64
+	lastRun      map[string]time.Time
+	mutex        sync.Mutex
+}
+
+// polygonOptionsSnapshotResponse represents the response from the Polygon options chain snapshot endpoint
+type polygonOptionsSnapshotResponse struct {
+	Status  string                        `json:"status"`
+	Results []polygonOptionsContractEntry `json:"results"`
+	NextURL string                        `json:"next_url"`
+}
+
+// polygonOptionsContractEntry represents a single contract in an options chain snapshot
+type polygonOptionsContractEntry struct {
+	Day struct {
+		Close  float64 `json:"close"`
+		Volume float64 `json:"volume"`
+	} `json:"day"`
+	Details struct {
+		ContractType   string  `json:"contract_type"`
+		ExpirationDate string  `json:"expiration_date"`
+		StrikePrice    float64 `json:"strike_price"`
+		Ticker         string  `json:"ticker"`
+	} `json:"details"`
+	Greeks struct {
+		Delta float64 `json:"delta"`
+	} `json:"greeks"`
+	ImpliedVolatility float64 `json:"implied_volatility"`
+	OpenInterest      float64 `json:"open_interest"`
+	UnderlyingAsset   struct {
+		Price float64 `json:"price"`
+	} `json:"underlying_asset"`
+}
+
+// NewOptionsService creates a new options service
+func NewOptionsService(cfg *config.Config, db *database.Database) *OptionsService {
+	optionsCfg := cfg.Collection.Options
+
+	interval := optionsCfg.Interval
+	if interval <= 0 {
+		interval = DefaultOptionsInterval
+	}
+
+	maxContracts := optionsCfg.MaxContracts
+	if maxContracts <= 0 {
+		maxContracts = defaultOptionsMaxContracts
+	}
+
+	unusualRatio := optionsCfg.UnusualVolumeRatio
+	if unusualRatio <= 0 {
+		unusualRatio = defaultUnusualVolumeRatio
+	}
+
+	minUnusual := optionsCfg.MinUnusualVolume
+	if minUnusual <= 0 {
+		minUnusual = defaultMinUnusualVolume
+	}
+
+	timeout := cfg.Polygon.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	return &OptionsService{
+		cfg:          cfg,
+		db:           db,
+		client:       &http.Client{Timeout: timeout},
+		enabled:      optionsCfg.Enabled,
+		interval:     interval,
+		maxContracts: maxContracts,
+		unusualRatio: unusualRatio,
+		minUnusual:   minUnusual,
+		lastRun:      make(map[string]time.Time),
+	}
+}
+
+// IsEnabled returns true when options snapshots should be collected
+func (ops *OptionsService) IsEnabled() bool {
+	return ops != nil && ops.enabled
+}
+
+// CollectDue collects a snapshot for each symbol whose last snapshot is older than the interval
+func (ops *OptionsService) CollectDue(symbols []string) {
+	now := time.Now()
+
+	for _, symbol := range symbols {
+		ops.mutex.Lock()
+		last := ops.lastRun[symbol]
+		ops.mutex.Unlock()
+		if now.Sub(last) < ops.interval {
+			continue
+		}
+
+		snapshot, err := ops.CollectSnapshot(symbol)
+		if err != nil {
+			log.Printf("Failed to collect options snapshot for %s: %v", symbol, err)
+			continue
+		}
+
+		ops.mutex.Lock()
+		ops.lastRun[symbol] = now
+		ops.mutex.Unlock()
+
+		log.Printf("Options snapshot for %s: %d contracts, put/call volume %.2f, %d unusual",
+			symbol, snapshot.ContractCount, snapshot.PutCallVolumeRatio, snapshot.UnusualCount)
+	}
+}
+
+// CollectSnapshot fetches the options chain for a symbol and stores the aggregated snapshot
+func (ops *OptionsService) CollectSnapshot(symbol string) (*models.OptionsSnapshot, error) {
+	contracts, underlyingPrice, err := ops.FetchChain(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if len(contracts) == 0 {
+		return nil, fmt.Errorf("no option contracts returned for %s", symbol)
+	}
+
+	snapshot := AggregateOptionsChain(symbol, contracts, underlyingPrice, time.Now(), ops.unusualRatio, ops.minUnusual)
+	if err := ops.db.InsertOptionsSnapshot(snapshot); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// FetchChain fetches an options chain snapshot for a symbol from Polygon, following pagination
+func (ops *OptionsService) FetchChain(symbol string) ([]*models.OptionContract, float64, error) {
+	params := url.Values{}
+	params.Set("limit", "250")
+	params.Set("apiKey", ops.cfg.Polygon.APIKey)
+	requestURL := fmt.Sprintf("%s/v3/snapshot/options/%s?%s", ops.cfg.Polygon.BaseURL, url.PathEscape(symbol), params.Encode())
+
+	contracts := make([]*models.OptionContract, 0)
+	underlyingPrice := 0.0
+
+	for requestURL != "" && len(contracts) < ops.maxContracts {
+		page, err := ops.fetchChainPage(requestURL)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		for _, entry := range page.Results {
+			expiration, err := time.Parse("2006-01-02", entry.Details.ExpirationDate)
+			if err != nil {
+				continue
+			}
+			if entry.UnderlyingAsset.Price > 0 {
+				underlyingPrice = entry.UnderlyingAsset.Price
+			}
+			contracts = append(contracts, &models.OptionContract{
+				Ticker:            entry.Details.Ticker,
+				ContractType:      entry.Details.ContractType,
+				StrikePrice:       entry.Details.StrikePrice,
+				ExpirationDate:    expiration,
+				Volume:            int64(entry.Day.Volume),
+				OpenInterest:      int64(entry.OpenInterest),
+				ImpliedVolatility: entry.ImpliedVolatility,
+				LastPrice:         entry.Day.Close,
+				Delta:             entry.Greeks.Delta,
+			})
+		}
+
+		requestURL = ""
+		if page.NextURL != "" {
+			requestURL = page.NextURL + "&apiKey=" + url.QueryEscape(ops.cfg.Polygon.APIKey)
+		}
+	}
+
+	return contracts, underlyingPrice, nil
+}
+
+// fetchChainPage fetches a single page of an options chain snapshot
+func (ops *OptionsService) fetchChainPage(requestURL string) (*polygonOptionsSnapshotResponse, error) {
+	req, err := http.NewRequest("GET", requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := ops.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var page polygonOptionsSnapshotResponse
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &page, nil
+}
+
+// GetSummary summarizes options activity for a symbol over the given number of days
+func (ops *OptionsService) GetSummary(symbol string, days int) (*models.OptionsSummary, error) {
+	since := time.Now().AddDate(0, 0, -days)
+
+	history, err := ops.db.GetOptionsSnapshots(symbol, since)
+	if err != nil {
+		return nil, err
+	}
+
+	unusual, err := ops.db.GetUnusualOptionActivity(symbol, since, 50)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.OptionsSummary{
+		Symbol:        symbol,
+		Days:          days,
+		SnapshotCount: len(history),
+		RecentUnusual: unusual,
+		History:       history,
+	}
+	if len(history) == 0 {
+		return summary, nil
+	}
+
+	summary.Latest = history[len(history)-1]
+	summary.IVLow = math.Inf(1)
+	summary.IVHigh = math.Inf(-1)
+	ratioTotal := 0.0
+	for _, snapshot := range history {
+		ratioTotal += snapshot.PutCallVolumeRatio
+		summary.IVLow = math.Min(summary.IVLow, snapshot.AvgImpliedVolatility)
+		summary.IVHigh = math.Max(summary.IVHigh, snapshot.AvgImpliedVolatility)
+	}
+	summary.AvgPutCallVolumeRatio = ratioTotal / float64(len(history))
+	if summary.IVHigh > summary.IVLow {
+		summary.IVRank = (summary.Latest.AvgImpliedVolatility - summary.IVLow) / (summary.IVHigh - summary.IVLow) * 100
+	}
+
+	return summary, nil
+}
+
+// AggregateOptionsChain builds a snapshot from an options chain: call/put volume and open interest,
+// open interest weighted IV, at-the-money IV of the nearest expiration and unusual volume contracts
+func AggregateOptionsChain(symbol string, contracts []*models.OptionContract, underlyingPrice float64, now time.Time, unusualRatio float64, minUnusualVolume int64) *models.OptionsSnapshot {
+	snapshot := &models.OptionsSnapshot{
+		Symbol:          symbol,
+		Timestamp:       now,
+		UnderlyingPrice: underlyingPrice,
+		ContractCount:   len(contracts),
+	}
+
+	ivWeighted, ivWeight := 0.0, 0.0
+	var nearestExpiration time.Time
+	for _, contract := range contracts {
+		switch contract.ContractType {
+		case models.OptionTypeCall:
+			snapshot.CallVolume += contract.Volume
+			snapshot.CallOpenInterest += contract.OpenInterest
+		case models.OptionTypePut:
+			snapshot.PutVolume += contract.Volume
+			snapshot.PutOpenInterest += contract.OpenInterest
+		default:
+			continue
+		}
+
+		if contract.ImpliedVolatility > 0 && contract.OpenInterest > 0 {
+			ivWeighted += contract.ImpliedVolatility * float64(contract.OpenInterest)
+			ivWeight += float64(contract.OpenInterest)
+		}
+
+		if !contract.ExpirationDate.Before(now.Truncate(24*time.Hour)) &&
+			(nearestExpiration.IsZero() || contract.ExpirationDate.Before(nearestExpiration)) {
+			nearestExpiration = contract.ExpirationDate
+		}
+
+		if contract.Volume >= minUnusualVolume && contract.VolumeOIRatio() >= unusualRatio {
+			snapshot.UnusualContracts = append(snapshot.UnusualContracts, &models.UnusualOptionActivity{
+				Symbol:            symbol,
+				ContractTicker:    contract.Ticker,
+				ContractType:      contract.ContractType,
+				StrikePrice:       contract.StrikePrice,
+				ExpirationDate:    contract.ExpirationDate,
+				Volume:            contract.Volume,
+				OpenInterest:      contract.OpenInterest,
+				VolumeOIRatio:     contract.VolumeOIRatio(),
+				ImpliedVolatility: contract.ImpliedVolatility,
+				Timestamp:         now,
+			})
+		}
+	}
+
+	if snapshot.CallVolume > 0 {
+		snapshot.PutCallVolumeRatio = float64(snapshot.PutVolume) / float64(snapshot.CallVolume)
+	}
+	if snapshot.CallOpenInterest > 0 {
+		snapshot.PutCallOIRatio = float64(snapshot.PutOpenInterest) / float64(snapshot.CallOpenInterest)
+	}
+	if ivWeight > 0 {
+		snapshot.AvgImpliedVolatility = ivWeighted / ivWeight
+	}
+	snapshot.ATMImpliedVolatility = atmImpliedVolatility(contracts, underlyingPrice, nearestExpiration)
+
+	// Keep the heaviest unusual contracts
+	sort.Slice(snapshot.UnusualContracts, func(i, j int) bool {
+		return snapshot.UnusualContracts[i].Volume > snapshot.UnusualContracts[j].Volume
+	})
+	if len(snapshot.UnusualContracts) > maxUnusualContractsPerSnapshot {
+		snapshot.UnusualContracts = snapshot.UnusualContracts[:maxUnusualContractsPerSnapshot]
+	}
+	snapshot.UnusualCount = len(snapshot.UnusualContracts)
+
+	return snapshot
+}
+
+// atmImpliedVolatility averages the call and put IV at the strike closest to the underlying price
+func atmImpliedVolatility(contracts []*models.OptionContract, underlyingPrice float64, expiration time.Time) float64 {
+	if underlyingPrice <= 0 || expiration.IsZero() {
+		return 0
+	}
+
+	bestDistance := math.Inf(1)
+	for _, contract := range contracts {
+		if !contract.ExpirationDate.Equal(expiration) || contract.ImpliedVolatility <= 0 {
+			continue
+		}
+		bestDistance = math.Min(bestDistance, math.Abs(contract.StrikePrice-underlyingPrice))
+	}
+
+	total, count := 0.0, 0
+	for _, contract := range contracts {
+		if !contract.ExpirationDate.Equal(expiration) || contract.ImpliedVolatility <= 0 {
+			continue
+		}
+		if math.Abs(contract.StrikePrice-underlyingPrice) == bestDistance {
+			total += contract.ImpliedVolatility
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 0
+	}
+	return total / float64(count)
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestAggregateOptionsChain tests put/call ratios, weighted IV, ATM IV and unusual volume detection
+func TestAggregateOptionsChain(t *testing.T) {
+	now := time.Date(2024, 5, 6, 15, 0, 0, 0, time.UTC)
+	near := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	far := time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC)
+
+	contracts := []*models.OptionContract{
+		{Ticker: "C100", ContractType: models.OptionTypeCall, StrikePrice: 100, ExpirationDate: near, Volume: 4000, OpenInterest: 1000, ImpliedVolatility: 0.30},
+		{Ticker: "P100", ContractType: models.OptionTypePut, StrikePrice: 100, ExpirationDate: near, Volume: 1000, OpenInterest: 1000, ImpliedVolatility: 0.34},
+		{Ticker: "C110", ContractType: models.OptionTypeCall, StrikePrice: 110, ExpirationDate: near, Volume: 1000, OpenInterest: 2000, ImpliedVolatility: 0.40},
+		{Ticker: "P90", ContractType: models.OptionTypePut, StrikePrice: 90, ExpirationDate: far, Volume: 100, OpenInterest: 10, ImpliedVolatility: 0.50},
+	}
+
+	snapshot := AggregateOptionsChain("AAPL", contracts, 101, now, 2.0, 500)
+
+	if snapshot.CallVolume != 5000 || snapshot.PutVolume != 1100 {
+		t.Fatalf("expected call/put volume 5000/1100, got %d/%d", snapshot.CallVolume, snapshot.PutVolume)
+	}
+	if math.Abs(snapshot.PutCallVolumeRatio-0.22) > 1e-9 {
+		t.Errorf("expected put/call volume ratio 0.22, got %.4f", snapshot.PutCallVolumeRatio)
+	}
+	wantIV := (0.30*1000 + 0.34*1000 + 0.40*2000 + 0.50*10) / 4010
+	if math.Abs(snapshot.AvgImpliedVolatility-wantIV) > 1e-9 {
+		t.Errorf("expected OI weighted IV %.4f, got %.4f", wantIV, snapshot.AvgImpliedVolatility)
+	}
+	if math.Abs(snapshot.ATMImpliedVolatility-0.32) > 1e-9 {
+		t.Errorf("expected ATM IV 0.32 from the nearest expiration 100 strike, got %.4f", snapshot.ATMImpliedVolatility)
+	}
+
+	// Only the 100 call trades at least 500 contracts and twice its open interest
+	if snapshot.UnusualCount != 1 || snapshot.UnusualContracts[0].ContractTicker != "C100" {
+		t.Errorf("expected C100 as the only unusual contract, got %d", snapshot.UnusualCount)
+	}
+}
@@ -0,0 +1,504 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// Paper trading defaults
+const (
+	DefaultPaperTradingInterval = 5 * time.Minute
+	defaultPaperMinQuality      = 80.0
+	defaultPaperStartingCapital = 100000.0
+	defaultPaperRiskPercent     = 1.0
+	defaultPaperMaxOpenTrades   = 5
+	defaultPaperSetupExpiry     = 24 * time.Hour
+)
+
+// PaperTradingService simulates trading high-quality setups against collected prices
+type PaperTradingService struct {
+	db           *database.Database
+	setupService *SetupDetectionService
+	enabled      bool
+	minQuality   float64
+	capital      float64
+	riskPercent  float64
+	maxOpen      int
+	interval     time.Duration
+	cycleMutex   sync.Mutex // serializes simulation cycles
+	statusMutex  sync.RWMutex
+	lastRun      *time.Time
+	stop         chan struct{}
+	stopOnce     sync.Once
+	wg           sync.WaitGroup // tracks the background simulation loop
+}
+
+// NewPaperTradingService creates a new paper trading service
+func NewPaperTradingService(cfg *config.Config, db *database.Database, setupService *SetupDetectionService) *PaperTradingService {
+	ptCfg := cfg.PaperTrading
+
+	pts := &PaperTradingService{
+		db:           db,
+		setupService: setupService,
+		enabled:      ptCfg.Enabled,
+		minQuality:   ptCfg.MinQualityScore,
+		capital:      ptCfg.StartingCapital,
+		riskPercent:  ptCfg.RiskPercent,
+		maxOpen:      ptCfg.MaxOpenTrades,
+		interval:     ptCfg.Interval,
+		stop:         make(chan struct{}),
+	}
+
+	if pts.minQuality <= 0 {
+		pts.minQuality = defaultPaperMinQuality
+	}
+	if pts.capital <= 0 {
+		pts.capital = defaultPaperStartingCapital
+	}
+	if pts.riskPercent <= 0 {
+		pts.riskPercent = defaultPaperRiskPercent
+	}
+	if pts.maxOpen <= 0 {
+		pts.maxOpen = defaultPaperMaxOpenTrades
+	}
+	if pts.interval <= 0 {
+		pts.interval = DefaultPaperTradingInterval
+	}
+
+	return pts
+}
+
+// Start runs simulation cycles in the background when paper trading is enabled
+func (pts *PaperTradingService) Start() {
+	if !pts.enabled {
+		log.Printf("Paper trading disabled")
+		return
+	}
+
+	log.Printf("Starting paper trading (every %v, min quality %.0f)...", pts.interval, pts.minQuality)
+
+	ticker := time.NewTicker(pts.interval)
+	pts.wg.Add(1)
+	go func() {
+		defer pts.wg.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := pts.RunCycle(); err != nil {
+					log.Printf("Paper trading cycle failed: %v", err)
+				}
+			case <-pts.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background loop and waits for an in-flight cycle to finish
+func (pts *PaperTradingService) Stop(ctx context.Context) error {
+	pts.stopOnce.Do(func() { close(pts.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		pts.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Printf("Paper trading service stopped")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for paper trading to finish: %w", ctx.Err())
+	}
+}
+
+// RunCycle simulates fills and exits on new bars, takes new qualifying setups and
+// records a point on the equity curve
+func (pts *PaperTradingService) RunCycle() (*models.PaperTradingCycleResult, error) {
+	pts.cycleMutex.Lock()
+	defer pts.cycleMutex.Unlock()
+
+	now := time.Now()
+	result := &models.PaperTradingCycleResult{
+		RunAt: now,
+		Taken: []*models.PaperTrade{},
+	}
+
+	active, err := pts.activeTrades()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, trade := range active {
+		previous := trade.Status
+		if err := pts.simulateTrade(trade, now); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", trade.Symbol, err))
+			continue
+		}
+
+		if previous == models.PaperTradePending && trade.FilledAt != nil {
+			result.Filled++
+		}
+		switch trade.Status {
+		case models.PaperTradeClosed:
+			result.Closed++
+		case models.PaperTradeExpired:
+			result.Expired++
+		}
+	}
+
+	if err := pts.takeSetups(now, result); err != nil {
+		return nil, err
+	}
+
+	status, err := pts.GetStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	err = pts.db.InsertPaperEquityPoint(&models.PaperEquityPoint{
+		Timestamp:     now,
+		Equity:        status.Equity,
+		Cash:          status.Cash,
+		UnrealizedPnL: status.UnrealizedPnL,
+		OpenTrades:    status.OpenTrades,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pts.statusMutex.Lock()
+	pts.lastRun = &now
+	pts.statusMutex.Unlock()
+
+	if len(result.Taken) > 0 || result.Filled > 0 || result.Closed > 0 {
+		log.Printf("Paper trading: took %d, filled %d, closed %d, expired %d (equity $%.2f)",
+			len(result.Taken), result.Filled, result.Closed, result.Expired, status.Equity)
+	}
+
+	return result, nil
+}
+
+// simulateTrade replays bars collected since the trade was last checked
+func (pts *PaperTradingService) simulateTrade(trade *models.PaperTrade, now time.Time) error {
+	bars, err := pts.db.GetPriceDataRangeTimeframe(trade.Symbol, trade.LastCheckedAt, now, models.DefaultTimeframe)
+	if err != nil {
+		return fmt.Errorf("failed to get price data: %w", err)
+	}
+
+	changed := false
+	for _, bar := range bars {
+		if !bar.Timestamp.After(trade.LastCheckedAt) {
+			continue
+		}
+
+		trade.LastCheckedAt = bar.Timestamp
+		changed = true
+
+		trade.Simulate(bar)
+		if !trade.IsActive() {
+			break
+		}
+	}
+
+	// Expire untouched entries even when no new bars arrived
+	if trade.Status == models.PaperTradePending && now.After(trade.ExpiresAt) {
+		trade.Status = models.PaperTradeExpired
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return pts.db.UpdatePaperTrade(trade)
+}
+
+// takeSetups opens pending trades on the best qualifying setup of each watched symbol
+// without an active trade, up to the open trade limit
+func (pts *PaperTradingService) takeSetups(now time.Time, result *models.PaperTradingCycleResult) error {
+	active, err := pts.activeTrades()
+	if err != nil {
+		return err
+	}
+	if len(active) >= pts.maxOpen {
+		return nil
+	}
+
+	busy := make(map[string]bool)
+	for _, trade := range active {
+		busy[trade.Symbol] = true
+	}
+
+	symbols, err := pts.db.GetWatchedSymbols()
+	if err != nil {
+		return fmt.Errorf("failed to get watched symbols: %w", err)
+	}
+
+	status, err := pts.GetStatus()
+	if err != nil {
+		return err
+	}
+	available := status.Cash - pts.reservedCash(active)
+
+	for _, symbol := range symbols {
+		if len(active)+len(result.Taken) >= pts.maxOpen {
+			break
+		}
+		if busy[symbol] {
+			continue
+		}
+
+		setup, err := pts.bestSetup(symbol)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", symbol, err))
+			continue
+		}
+		if setup == nil {
+			continue
+		}
+
+		trade := pts.tradeForSetup(setup, status.Equity, available, now)
+		if trade == nil {
+			continue
+		}
+
+		if err := pts.db.InsertPaperTrade(trade); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", symbol, err))
+			continue
+		}
+
+		available -= trade.EntryPrice * trade.Quantity
+		result.Taken = append(result.Taken, trade)
+	}
+
+	return nil
+}
+
+// bestSetup returns the highest scoring active setup for a symbol above the quality threshold
+func (pts *PaperTradingService) bestSetup(symbol string) (*models.TradingSetup, error) {
+	detection, err := pts.setupService.DetectSetups(context.Background(), symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	var best *models.TradingSetup
+	for _, setup := range detection.ActiveSetups {
+		if setup.QualityScore < pts.minQuality {
+			continue
+		}
+		if best == nil || setup.QualityScore > best.QualityScore {
+			best = setup
+		}
+	}
+
+	return best, nil
+}
+
+// tradeForSetup sizes a pending trade so that hitting the stop loses the configured
+// percent of equity, limited by available cash. Returns nil if the setup cannot be traded.
+func (pts *PaperTradingService) tradeForSetup(setup *models.TradingSetup, equity, available float64, now time.Time) *models.PaperTrade {
+	side := models.SideForDirection(setup.Direction)
+
+	riskPerShare := setup.EntryPrice - setup.StopLoss
+	if side == models.PositionSideShort {
+		riskPerShare = -riskPerShare
+	}
+	if setup.EntryPrice <= 0 || riskPerShare <= 0 {
+		return nil
+	}
+
+	quantity := math.Floor(equity * pts.riskPercent / 100 / riskPerShare)
+	quantity = math.Min(quantity, math.Floor(available/setup.EntryPrice))
+	if quantity < 1 {
+		return nil
+	}
+
+	expiresAt := setup.ExpiresAt
+	if expiresAt.IsZero() || expiresAt.Before(now) {
+		expiresAt = now.Add(defaultPaperSetupExpiry)
+	}
+
+	return &models.PaperTrade{
+		Symbol:        setup.Symbol,
+		SetupType:     setup.SetupType,
+		Side:          side,
+		QualityScore:  setup.QualityScore,
+		EntryPrice:    setup.EntryPrice,
+		StopLoss:      setup.StopLoss,
+		TargetPrice:   setup.Target1,
+		Quantity:      quantity,
+		Status:        models.PaperTradePending,
+		ExpiresAt:     expiresAt,
+		LastCheckedAt: now, // only bars after the setup was taken can fill it
+	}
+}
+
+// CloseTrade cancels a pending trade or closes an open one at the latest price
+func (pts *PaperTradingService) CloseTrade(id int64) (*models.PaperTrade, error) {
+	pts.cycleMutex.Lock()
+	defer pts.cycleMutex.Unlock()
+
+	trade, err := pts.db.GetPaperTradeByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if trade == nil {
+		return nil, fmt.Errorf("paper trade %d %w", id, database.ErrNotFound)
+	}
+
+	switch trade.Status {
+	case models.PaperTradePending:
+		trade.Status = models.PaperTradeCancelled
+	case models.PaperTradeOpen:
+		latest, err := pts.db.GetLatestPriceData(trade.Symbol)
+		if err != nil || latest == nil {
+			return nil, fmt.Errorf("no current price available for %s", trade.Symbol)
+		}
+		trade.Close(latest.Close, models.PaperExitManual, time.Now())
+	default:
+		return nil, fmt.Errorf("paper trade %d is already %s", id, trade.Status)
+	}
+
+	if err := pts.db.UpdatePaperTrade(trade); err != nil {
+		return nil, err
+	}
+
+	return trade, nil
+}
+
+// Reset deletes all paper trades and the equity curve
+func (pts *PaperTradingService) Reset() error {
+	pts.cycleMutex.Lock()
+	defer pts.cycleMutex.Unlock()
+
+	return pts.db.ResetPaperTrading()
+}
+
+// GetTrades returns paper trades with open trades valued at the latest price
+func (pts *PaperTradingService) GetTrades(filter *models.PaperTradeFilter) ([]*models.PaperTrade, error) {
+	trades, err := pts.db.GetPaperTrades(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	pts.applyPrices(trades)
+	return trades, nil
+}
+
+// GetEquityCurve returns the simulated equity curve since a point in time
+func (pts *PaperTradingService) GetEquityCurve(since time.Time) ([]*models.PaperEquityPoint, error) {
+	return pts.db.GetPaperEquityCurve(since)
+}
+
+// GetStatus summarizes the simulated account from all trades
+func (pts *PaperTradingService) GetStatus() (*models.PaperTradingStatus, error) {
+	trades, err := pts.GetTrades(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &models.PaperTradingStatus{
+		Enabled:         pts.enabled,
+		MinQualityScore: pts.minQuality,
+		StartingCapital: pts.capital,
+	}
+
+	invested := 0.0
+	for _, trade := range trades {
+		switch trade.Status {
+		case models.PaperTradePending:
+			status.PendingTrades++
+		case models.PaperTradeOpen:
+			status.OpenTrades++
+			invested += trade.EntryPrice * trade.Quantity
+			status.UnrealizedPnL += trade.UnrealizedPnL
+		case models.PaperTradeClosed:
+			status.ClosedTrades++
+			status.RealizedPnL += trade.RealizedPnL
+			if trade.RealizedPnL > 0 {
+				status.Wins++
+			} else {
+				status.Losses++
+			}
+		}
+	}
+
+	status.Cash = pts.capital + status.RealizedPnL - invested
+	status.Equity = pts.capital + status.RealizedPnL + status.UnrealizedPnL
+	status.ReturnPercent = (status.Equity - pts.capital) / pts.capital * 100
+	if status.ClosedTrades > 0 {
+		status.WinRate = float64(status.Wins) / float64(status.ClosedTrades) * 100
+	}
+
+	pts.statusMutex.RLock()
+	status.LastRun = pts.lastRun
+	pts.statusMutex.RUnlock()
+
+	return status, nil
+}
+
+// activeTrades returns pending and open trades, oldest first
+func (pts *PaperTradingService) activeTrades() ([]*models.PaperTrade, error) {
+	pending, err := pts.db.GetPaperTrades(&models.PaperTradeFilter{Status: models.PaperTradePending})
+	if err != nil {
+		return nil, err
+	}
+	open, err := pts.db.GetPaperTrades(&models.PaperTradeFilter{Status: models.PaperTradeOpen})
+	if err != nil {
+		return nil, err
+	}
+
+	trades := append(open, pending...)
+	sort.Slice(trades, func(i, j int) bool {
+		return trades[i].CreatedAt.Before(trades[j].CreatedAt)
+	})
+	return trades, nil
+}
+
+// reservedCash returns the capital pending entries would commit when filled
+func (pts *PaperTradingService) reservedCash(trades []*models.PaperTrade) float64 {
+	reserved := 0.0
+	for _, trade := range trades {
+		if trade.Status == models.PaperTradePending {
+			reserved += trade.EntryPrice * trade.Quantity
+		}
+	}
+	return reserved
+}
+
+// applyPrices values open trades at the latest price of each symbol
+func (pts *PaperTradingService) applyPrices(trades []*models.PaperTrade) {
+	prices := make(map[string]float64)
+
+	for _, trade := range trades {
+		if trade.Status != models.PaperTradeOpen {
+			continue
+		}
+
+		price, ok := prices[trade.Symbol]
+		if !ok {
+			if latest, err := pts.db.GetLatestPriceData(trade.Symbol); err == nil && latest != nil {
+				price = latest.Close
+			}
+			prices[trade.Symbol] = price
+		}
+		if price <= 0 {
+			continue
+		}
+
+		trade.CurrentPrice = price
+		trade.UnrealizedPnL = trade.PnLAt(price)
+	}
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// TestPaperTradeSimulate tests entry fills, stop priority and gap fills on simulated bars
+func TestPaperTradeSimulate(t *testing.T) {
+	start := time.Date(2024, 3, 4, 14, 30, 0, 0, time.UTC)
+	bar := func(i int, open, high, low, close float64) *models.PriceData {
+		return &models.PriceData{Timestamp: start.Add(time.Duration(i) * time.Minute), Open: open, High: high, Low: low, Close: close}
+	}
+	newTrade := func() *models.PaperTrade {
+		return &models.PaperTrade{
+			Side: models.PositionSideLong, EntryPrice: 100, StopLoss: 98, TargetPrice: 104,
+			Quantity: 10, Status: models.PaperTradePending, ExpiresAt: start.Add(time.Hour),
+		}
+	}
+
+	// Fills when the entry is touched, then exits at the target
+	trade := newTrade()
+	trade.Simulate(bar(0, 101, 102, 100.5, 101))
+	if trade.Status != models.PaperTradePending {
+		t.Fatalf("expected trade to stay pending, got %s", trade.Status)
+	}
+	trade.Simulate(bar(1, 101, 101, 99.5, 100.5))
+	if trade.Status != models.PaperTradeOpen {
+		t.Fatalf("expected trade to fill, got %s", trade.Status)
+	}
+	trade.Simulate(bar(2, 101, 104.5, 100.5, 104))
+	if trade.Status != models.PaperTradeClosed || trade.ExitReason != models.PaperExitTarget || trade.RealizedPnL != 40 {
+		t.Errorf("expected target exit with $40 profit, got %s/%s/%.2f", trade.Status, trade.ExitReason, trade.RealizedPnL)
+	}
+
+	// A bar spanning both stop and target exits at the stop
+	trade = newTrade()
+	trade.Simulate(bar(0, 100, 105, 97, 101))
+	if trade.ExitReason != models.PaperExitStop || *trade.ExitPrice != 98 {
+		t.Errorf("expected stop exit at 98 on wide fill bar, got %s at %v", trade.ExitReason, trade.ExitPrice)
+	}
+
+	// A gap through the stop fills at the open
+	trade = newTrade()
+	trade.Simulate(bar(0, 100, 100.5, 99.5, 100))
+	trade.Simulate(bar(1, 96, 97, 95, 96))
+	if trade.ExitPrice == nil || *trade.ExitPrice != 96 || trade.RealizedPnL != -40 {
+		t.Errorf("expected gap stop fill at 96, got %v (pnl %.2f)", trade.ExitPrice, trade.RealizedPnL)
+	}
+
+	// Untouched entries expire
+	trade = newTrade()
+	trade.Simulate(bar(61, 110, 111, 109, 110))
+	if trade.Status != models.PaperTradeExpired {
+		t.Errorf("expected trade to expire, got %s", trade.Status)
+	}
+}
+
+// TestPaperTradeSizing tests risk based position sizing capped by available cash
+func TestPaperTradeSizing(t *testing.T) {
+	pts := NewPaperTradingService(&config.Config{}, nil, nil)
+	now := time.Now()
+
+	setup := &models.TradingSetup{Symbol: "AAPL", Direction: "bullish", EntryPrice: 100, StopLoss: 98, Target1: 106}
+	trade := pts.tradeForSetup(setup, 100000, 100000, now)
+	if trade == nil || trade.Quantity != 500 {
+		t.Fatalf("expected 500 shares risking 1%% of equity, got %+v", trade)
+	}
+
+	trade = pts.tradeForSetup(setup, 100000, 20000, now)
+	if trade == nil || trade.Quantity != 200 {
+		t.Errorf("expected quantity capped at 200 by cash, got %+v", trade)
+	}
+
+	short := &models.TradingSetup{Symbol: "AAPL", Direction: "bearish", EntryPrice: 100, StopLoss: 98}
+	if trade := pts.tradeForSetup(short, 100000, 100000, now); trade != nil {
+		t.Errorf("expected short setup with stop below entry to be rejected, got %+v", trade)
+	}
+}
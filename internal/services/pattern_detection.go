@@ -1,54 +1,214 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"market-watch-go/internal/database"
 	"market-watch-go/internal/models"
 )
 
+// Default intervals for the background pattern scheduler
+const (
+	DefaultPatternScanInterval    = 30 * time.Minute
+	DefaultPatternMonitorInterval = 5 * time.Minute
+)
+
 // PatternDetectionService handles automatic pattern detection for multiple pattern types
 type PatternDetectionService struct {
-	db           *database.Database
-	taService    *TechnicalAnalysisService
-	hsService    *HeadShouldersDetectionService
-	emailService *EmailService
+	db                  *database.Database
+	taService           *TechnicalAnalysisService
+	hsService           *HeadShouldersDetectionService
+	fallingWedgeService *FallingWedgeDetectionService
+	triangleService     *TriangleDetectionService
+	flagService         *FlagDetectionService
+	emailService        *EmailService
+	workers             *WorkerPool
+	webhooks            *WebhookService
+	schedules           *ScheduleControl
+	scheduler           *PatternSchedulerStatus
+	statusMutex         sync.RWMutex
+	scanMutex           sync.Mutex // prevents overlapping scans
+	monitorMutex        sync.Mutex // prevents overlapping monitoring runs
+	stop                chan struct{}
+	stopOnce            sync.Once
+	wg                  sync.WaitGroup // tracks background detection runs
+}
+
+// PatternSchedulerStatus describes the background scanning and monitoring loop
+type PatternSchedulerStatus struct {
+	IsRunning       bool      `json:"is_running"`
+	ScanInterval    string    `json:"scan_interval"`
+	MonitorInterval string    `json:"monitor_interval"`
+	LastScan        time.Time `json:"last_scan"`
+	LastMonitor     time.Time `json:"last_monitor"`
+	NextScan        time.Time `json:"next_scan"`
+	NextMonitor     time.Time `json:"next_monitor"`
+	ScanRuns        int       `json:"scan_runs"`
+	MonitorRuns     int       `json:"monitor_runs"`
+	LastError       string    `json:"last_error"`
+	// LastScanRun reports the duration and per-symbol failures of the last scan of all watched symbols
+	LastScanRun *models.ScanRun `json:"last_scan_run,omitempty"`
 }
 
 // NewPatternDetectionService creates a new pattern detection service
-func NewPatternDetectionService(db *database.Database, taService *TechnicalAnalysisService, hsService *HeadShouldersDetectionService, emailService *EmailService) *PatternDetectionService {
+func NewPatternDetectionService(
+	db *database.Database,
+	taService *TechnicalAnalysisService,
+	hsService *HeadShouldersDetectionService,
+	fallingWedgeService *FallingWedgeDetectionService,
+	triangleService *TriangleDetectionService,
+	flagService *FlagDetectionService,
+	emailService *EmailService,
+) *PatternDetectionService {
 	return &PatternDetectionService{
-		db:           db,
-		taService:    taService,
-		hsService:    hsService,
-		emailService: emailService,
+		db:                  db,
+		taService:           taService,
+		hsService:           hsService,
+		fallingWedgeService: fallingWedgeService,
+		triangleService:     triangleService,
+		flagService:         flagService,
+		emailService:        emailService,
+		workers:             NewWorkerPool(1),
+		scheduler:           &PatternSchedulerStatus{},
+		stop:                make(chan struct{}),
 	}
 }
 
+// SetWorkerPool sets the pool scans of many symbols run on; without one symbols are scanned one at a time
+func (pds *PatternDetectionService) SetWorkerPool(workers *WorkerPool) {
+	pds.workers = workers
+}
+
+// SetWebhookService sets the service that posts setup_invalidated webhooks for setups superseded by reconciliation
+func (pds *PatternDetectionService) SetWebhookService(webhooks *WebhookService) {
+	pds.webhooks = webhooks
+}
+
 // AutoDetectPatternsForSymbol automatically detects all pattern types for a given symbol
-func (pds *PatternDetectionService) AutoDetectPatternsForSymbol(symbol string) error {
+func (pds *PatternDetectionService) AutoDetectPatternsForSymbol(ctx context.Context, symbol string) error {
 	log.Printf("Starting automatic pattern detection for %s", symbol)
 
 	// Detect Head & Shoulders patterns (both regular and inverse)
-	if err := pds.detectHeadShouldersPatterns(symbol); err != nil {
+	if err := pds.detectHeadShouldersPatterns(ctx, symbol); err != nil {
 		log.Printf("Failed to detect H&S patterns for %s: %v", symbol, err)
 	}
 
-	// TODO: Add other pattern detection methods
-	// if err := pds.detectCupAndHandlePatterns(symbol); err != nil {
-	//     log.Printf("Failed to detect Cup & Handle patterns for %s: %v", symbol, err)
-	// }
+	if pds.fallingWedgeService != nil {
+		_, err := pds.fallingWedgeService.DetectFallingWedge(ctx, symbol, nil)
+		logDetection("falling wedge", symbol, err)
+		_, err = pds.fallingWedgeService.DetectRisingWedge(ctx, symbol, nil)
+		logDetection("rising wedge", symbol, err)
+	}
+
+	if pds.triangleService != nil {
+		_, err := pds.triangleService.DetectTriangle(ctx, symbol, models.DefaultTimeframe)
+		logDetection("triangle", symbol, err)
+	}
+
+	if pds.flagService != nil {
+		_, err := pds.flagService.DetectFlag(ctx, symbol, models.DefaultTimeframe)
+		logDetection("flag", symbol, err)
+	}
 
-	// if err := pds.detectTrianglePatterns(symbol); err != nil {
-	//     log.Printf("Failed to detect Triangle patterns for %s: %v", symbol, err)
-	// }
+	// TODO: Add Cup & Handle detection
+
+	// A cancelled scan leaves the symbol for the next one
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("pattern detection for %s stopped: %w", symbol, err)
+	}
+
+	// Link patterns the detectors found on the same pivots so they yield a single setup
+	if _, err := pds.ReconcilePatterns(symbol); err != nil {
+		log.Printf("Failed to reconcile patterns for %s: %v", symbol, err)
+	}
 
 	log.Printf("Completed automatic pattern detection for %s", symbol)
 	return nil
 }
 
+// DetectPatterns runs the detectors of the given pattern families for a symbol and returns the pattern types
+// found, e.g. falling_wedge or bull_flag. A detector finding nothing is not an error.
+func (pds *PatternDetectionService) DetectPatterns(ctx context.Context, symbol string, patterns []string) ([]string, error) {
+	found := make([]string, 0)
+	for _, pattern := range patterns {
+		if err := ctx.Err(); err != nil {
+			return found, fmt.Errorf("pattern detection for %s stopped: %w", symbol, err)
+		}
+
+		var patternType string
+		var err error
+
+		switch pattern {
+		case models.AutomationPatternHeadShoulders, models.AutomationPatternInverseHeadShoulders:
+			if pds.hsService == nil {
+				continue
+			}
+			var hs *models.HeadShouldersPattern
+			if pattern == models.AutomationPatternInverseHeadShoulders {
+				hs, err = pds.hsService.DetectInverseHeadShoulders(ctx, symbol, nil)
+			} else {
+				hs, err = pds.hsService.DetectHeadShoulders(ctx, symbol, nil)
+			}
+			if err == nil && hs != nil {
+				patternType = hs.PatternType
+			}
+		case models.AutomationPatternFallingWedge, models.AutomationPatternRisingWedge:
+			if pds.fallingWedgeService == nil {
+				continue
+			}
+			var wedge *models.FallingWedgePattern
+			if pattern == models.AutomationPatternRisingWedge {
+				wedge, err = pds.fallingWedgeService.DetectRisingWedge(ctx, symbol, nil)
+			} else {
+				wedge, err = pds.fallingWedgeService.DetectFallingWedge(ctx, symbol, nil)
+			}
+			if err == nil && wedge != nil {
+				patternType = wedge.PatternType
+			}
+		case models.AutomationPatternTriangle:
+			if pds.triangleService == nil {
+				continue
+			}
+			var triangle *models.TrianglePattern
+			triangle, err = pds.triangleService.DetectTriangle(ctx, symbol, models.DefaultTimeframe)
+			if err == nil && triangle != nil {
+				patternType = triangle.PatternType
+			}
+		case models.AutomationPatternFlag:
+			if pds.flagService == nil {
+				continue
+			}
+			var flag *models.FlagPattern
+			flag, err = pds.flagService.DetectFlag(ctx, symbol, models.DefaultTimeframe)
+			if err == nil && flag != nil {
+				patternType = flag.PatternType
+			}
+		default:
+			return found, fmt.Errorf("unknown pattern %q", pattern)
+		}
+
+		if err != nil {
+			logDetection(pattern, symbol, err)
+			continue
+		}
+		if patternType != "" {
+			found = append(found, patternType)
+		}
+	}
+
+	if len(found) > 0 {
+		if _, err := pds.ReconcilePatterns(symbol); err != nil {
+			log.Printf("Failed to reconcile patterns for %s: %v", symbol, err)
+		}
+	}
+	return found, nil
+}
+
 // AutoDetectPatternsForAllSymbols runs pattern detection for all watched symbols
 func (pds *PatternDetectionService) AutoDetectPatternsForAllSymbols() error {
 	symbols, err := pds.db.GetWatchedSymbols()
@@ -58,61 +218,202 @@ func (pds *PatternDetectionService) AutoDetectPatternsForAllSymbols() error {
 
 	log.Printf("Starting automatic pattern detection for %d symbols", len(symbols))
 
-	for _, symbol := range symbols {
-		if err := pds.AutoDetectPatternsForSymbol(symbol); err != nil {
-			log.Printf("Failed pattern detection for %s: %v", symbol, err)
-			continue
-		}
-	}
+	run := pds.workers.Run(context.Background(), "Pattern scan", symbols, func(ctx context.Context, symbol string) error {
+		return pds.AutoDetectPatternsForSymbol(ctx, symbol)
+	})
 
-	log.Printf("Completed automatic pattern detection for all symbols")
+	pds.statusMutex.Lock()
+	pds.scheduler.LastScanRun = run
+	pds.statusMutex.Unlock()
 	return nil
 }
 
 // detectHeadShouldersPatterns detects both regular and inverse head & shoulders patterns
-func (pds *PatternDetectionService) detectHeadShouldersPatterns(symbol string) error {
+func (pds *PatternDetectionService) detectHeadShouldersPatterns(ctx context.Context, symbol string) error {
 	// Detect Inverse Head & Shoulders (bullish)
-	_, err := pds.hsService.DetectInverseHeadShoulders(symbol)
-	if err != nil {
-		log.Printf("No inverse H&S pattern found for %s: %v", symbol, err)
-	}
+	_, err := pds.hsService.DetectInverseHeadShoulders(ctx, symbol, nil)
+	logDetection("inverse H&S", symbol, err)
 
-	// TODO: Add regular Head & Shoulders detection (bearish)
-	// _, err = pds.hsService.DetectHeadShoulders(symbol)
-	// if err != nil {
-	//     log.Printf("No H&S pattern found for %s: %v", symbol, err)
-	// }
+	// Detect regular Head & Shoulders (bearish)
+	_, err = pds.hsService.DetectHeadShoulders(ctx, symbol, nil)
+	logDetection("H&S", symbol, err)
 
 	return nil
 }
 
-// StartPeriodicPatternDetection starts a background goroutine that periodically scans for patterns
-func (pds *PatternDetectionService) StartPeriodicPatternDetection() {
-	log.Printf("Starting periodic pattern detection service...")
+// logDetection logs a detector's error, telling a scan that found no pattern apart from one that failed
+func logDetection(pattern, symbol string, err error) {
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrNoPattern):
+		log.Printf("No %s pattern found for %s: %v", pattern, symbol, err)
+	default:
+		log.Printf("Failed to detect %s pattern for %s: %v", pattern, symbol, err)
+	}
+}
 
-	// Run pattern detection every 30 minutes
-	ticker := time.NewTicker(30 * time.Minute)
+// MonitorActivePatterns updates the thesis components of all active patterns
+func (pds *PatternDetectionService) MonitorActivePatterns() error {
+	var errs []error
 
+	if err := pds.hsService.MonitorActivePatterns(); err != nil {
+		errs = append(errs, fmt.Errorf("head and shoulders: %w", err))
+	}
+
+	if pds.triangleService != nil {
+		if err := pds.triangleService.MonitorActivePatterns(); err != nil {
+			errs = append(errs, fmt.Errorf("triangle: %w", err))
+		}
+	}
+
+	if pds.flagService != nil {
+		if err := pds.flagService.MonitorActivePatterns(); err != nil {
+			errs = append(errs, fmt.Errorf("flag: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// SetScheduleControl sets the control that can disable the periodic scans and monitoring
+func (pds *PatternDetectionService) SetScheduleControl(schedules *ScheduleControl) {
+	pds.schedules = schedules
+}
+
+// StartPeriodicPatternDetection starts a background goroutine that periodically scans all watched
+// symbols for new patterns and updates the thesis of active patterns
+func (pds *PatternDetectionService) StartPeriodicPatternDetection(scanInterval, monitorInterval time.Duration) {
+	if scanInterval <= 0 {
+		scanInterval = DefaultPatternScanInterval
+	}
+	if monitorInterval <= 0 {
+		monitorInterval = DefaultPatternMonitorInterval
+	}
+
+	log.Printf("Starting periodic pattern detection service (scan every %v, monitor every %v)...", scanInterval, monitorInterval)
+
+	scanTicker := time.NewTicker(scanInterval)
+	monitorTicker := time.NewTicker(monitorInterval)
+
+	pds.statusMutex.Lock()
+	pds.scheduler.IsRunning = true
+	pds.scheduler.ScanInterval = scanInterval.String()
+	pds.scheduler.MonitorInterval = monitorInterval.String()
+	pds.scheduler.NextScan = time.Now().Add(scanInterval)
+	pds.scheduler.NextMonitor = time.Now().Add(monitorInterval)
+	pds.statusMutex.Unlock()
+
+	pds.wg.Add(1)
 	go func() {
+		defer pds.wg.Done()
+		defer scanTicker.Stop()
+		defer monitorTicker.Stop()
 		for {
 			select {
-			case <-ticker.C:
-				log.Printf("Running periodic pattern detection...")
-				if err := pds.AutoDetectPatternsForAllSymbols(); err != nil {
-					log.Printf("Periodic pattern detection failed: %v", err)
-				}
+			case <-scanTicker.C:
+				pds.runScheduledScan(scanInterval)
+			case <-monitorTicker.C:
+				pds.runScheduledMonitor(monitorInterval)
+			case <-pds.stop:
+				pds.statusMutex.Lock()
+				pds.scheduler.IsRunning = false
+				pds.statusMutex.Unlock()
+				return
 			}
 		}
 	}()
 }
 
+// runScheduledScan scans all watched symbols, skipping the run if the previous scan is still going
+func (pds *PatternDetectionService) runScheduledScan(interval time.Duration) {
+	if pds.schedules.Skip(models.SchedulePatternScan) {
+		return
+	}
+	if !pds.scanMutex.TryLock() {
+		log.Printf("Pattern scan already running, skipping...")
+		return
+	}
+	defer pds.scanMutex.Unlock()
+
+	log.Printf("Running periodic pattern detection...")
+	err := pds.AutoDetectPatternsForAllSymbols()
+	if err != nil {
+		log.Printf("Periodic pattern detection failed: %v", err)
+	}
+
+	pds.statusMutex.Lock()
+	pds.scheduler.LastScan = time.Now()
+	pds.scheduler.NextScan = time.Now().Add(interval)
+	pds.scheduler.ScanRuns++
+	if err != nil {
+		pds.scheduler.LastError = err.Error()
+	}
+	pds.statusMutex.Unlock()
+}
+
+// runScheduledMonitor updates active patterns, skipping the run if the previous one is still going
+func (pds *PatternDetectionService) runScheduledMonitor(interval time.Duration) {
+	if !pds.schedules.Enabled(models.SchedulePatternScan) {
+		return
+	}
+	if !pds.monitorMutex.TryLock() {
+		log.Printf("Pattern monitoring already running, skipping...")
+		return
+	}
+	defer pds.monitorMutex.Unlock()
+
+	err := pds.MonitorActivePatterns()
+	if err != nil {
+		log.Printf("Periodic pattern monitoring failed: %v", err)
+	}
+
+	pds.statusMutex.Lock()
+	pds.scheduler.LastMonitor = time.Now()
+	pds.scheduler.NextMonitor = time.Now().Add(interval)
+	pds.scheduler.MonitorRuns++
+	if err != nil {
+		pds.scheduler.LastError = err.Error()
+	}
+	pds.statusMutex.Unlock()
+}
+
+// GetSchedulerStatus returns a snapshot of the background scheduler state
+func (pds *PatternDetectionService) GetSchedulerStatus() *PatternSchedulerStatus {
+	pds.statusMutex.RLock()
+	defer pds.statusMutex.RUnlock()
+
+	statusCopy := *pds.scheduler
+	return &statusCopy
+}
+
+// Stop stops periodic detection and waits for in-flight detection runs to finish
+func (pds *PatternDetectionService) Stop(ctx context.Context) error {
+	pds.stopOnce.Do(func() { close(pds.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		pds.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Printf("Pattern detection service stopped")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for pattern detection to finish: %w", ctx.Err())
+	}
+}
+
 // OnSymbolAdded is called when a new symbol is added to the watchlist
 func (pds *PatternDetectionService) OnSymbolAdded(symbol string) {
 	log.Printf("New symbol %s added to watchlist, triggering pattern detection", symbol)
 
 	// Run pattern detection in a goroutine to avoid blocking
+	pds.wg.Add(1)
 	go func() {
-		if err := pds.AutoDetectPatternsForSymbol(symbol); err != nil {
+		defer pds.wg.Done()
+		if err := pds.AutoDetectPatternsForSymbol(context.Background(), symbol); err != nil {
 			log.Printf("Failed to detect patterns for newly added symbol %s: %v", symbol, err)
 		}
 	}()
@@ -122,15 +423,19 @@ func (pds *PatternDetectionService) OnSymbolAdded(symbol string) {
 func (pds *PatternDetectionService) OnDataUpdated(symbols []string) {
 	log.Printf("Price data updated for %d symbols, checking for pattern updates", len(symbols))
 
+	pds.wg.Add(1)
 	go func() {
+		defer pds.wg.Done()
+		// Only run pattern detection if we haven't run it recently for a symbol
+		var due []string
 		for _, symbol := range symbols {
-			// Only run pattern detection if we haven't run it recently for this symbol
 			if pds.shouldRunPatternDetection(symbol) {
-				if err := pds.AutoDetectPatternsForSymbol(symbol); err != nil {
-					log.Printf("Failed to update patterns for %s: %v", symbol, err)
-				}
+				due = append(due, symbol)
 			}
 		}
+		pds.workers.Run(context.Background(), "Pattern update", due, func(ctx context.Context, symbol string) error {
+			return pds.AutoDetectPatternsForSymbol(ctx, symbol)
+		})
 	}()
 }
 
@@ -160,7 +465,7 @@ func (pds *PatternDetectionService) GetPatternSummaryForSymbol(symbol string) (*
 		ActivePatterns:        0,
 		CompletedPatterns:     0,
 		HeadShouldersPatterns: hsPatterns,
-		LastDetectionRun:      time.Now(),
+		LastDetectionRun:      clockNow(),
 	}
 
 	for _, pattern := range hsPatterns {
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// failingPriceReader fails every price data read
+type failingPriceReader struct{}
+
+func (failingPriceReader) GetPriceData(filter *models.PriceDataFilter) ([]*models.PriceData, error) {
+	return nil, errors.New("database is locked")
+}
+
+func (failingPriceReader) GetPriceDataRangeTimeframe(symbol string, startTime, endTime time.Time, timeframe models.Timeframe) ([]*models.PriceData, error) {
+	return nil, errors.New("database is locked")
+}
+
+// detectorScans returns a scan of TEST by every pattern detector, each reading bars from prices
+func detectorScans(db *database.DB, prices PriceReader) map[string]func(ctx context.Context) error {
+	hs := NewHeadShouldersDetectionService(db, nil, nil, nil)
+	wedge := NewFallingWedgeDetectionService(db, nil, nil)
+	triangle := NewTriangleDetectionService(db, nil, nil)
+	flag := NewFlagDetectionService(db, nil, nil)
+	hs.SetPriceReader(prices)
+	wedge.SetPriceReader(prices)
+	triangle.SetPriceReader(prices)
+	flag.SetPriceReader(prices)
+
+	return map[string]func(ctx context.Context) error{
+		"head and shoulders": func(ctx context.Context) error {
+			_, err := hs.DetectHeadShoulders(ctx, "TEST", nil)
+			return err
+		},
+		"falling wedge": func(ctx context.Context) error {
+			_, err := wedge.DetectFallingWedge(ctx, "TEST", nil)
+			return err
+		},
+		"rising wedge": func(ctx context.Context) error {
+			_, err := wedge.DetectRisingWedge(ctx, "TEST", nil)
+			return err
+		},
+		"triangle": func(ctx context.Context) error {
+			_, err := triangle.DetectTriangle(ctx, "TEST", models.DefaultTimeframe)
+			return err
+		},
+		"flag": func(ctx context.Context) error {
+			_, err := flag.DetectFlag(ctx, "TEST", models.DefaultTimeframe)
+			return err
+		},
+	}
+}
+
+// TestDetectorsNoPattern tests the detectors report missing data as ErrNoPattern, and a failed read as an error
+// that isn't
+func TestDetectorsNoPattern(t *testing.T) {
+	db := newTestDB(t)
+
+	for name, detect := range detectorScans(db, db) {
+		if err := detect(context.Background()); !errors.Is(err, ErrNoPattern) {
+			t.Errorf("%s: expected ErrNoPattern without price data, got %v", name, err)
+		}
+	}
+
+	for name, detect := range detectorScans(db, failingPriceReader{}) {
+		if err := detect(context.Background()); err == nil || errors.Is(err, ErrNoPattern) {
+			t.Errorf("%s: expected a failed read to be a real error, got %v", name, err)
+		}
+	}
+}
+
+// TestDetectorsCancelled tests a cancelled context stops the detectors' reads, from the database and through
+// the price cache and regular session reader
+func TestDetectorsCancelled(t *testing.T) {
+	db := newTestDB(t)
+	cache := NewPriceCache(&config.Config{}, db)
+	readers := map[string]PriceReader{
+		"database":        db,
+		"price cache":     cache,
+		"regular session": NewRegularSessionReader(cache, NewMarketCalendar(config.MarketHoursConfig{})),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for readerName, prices := range readers {
+		for name, detect := range detectorScans(db, prices) {
+			if err := detect(ctx); !errors.Is(err, context.Canceled) {
+				t.Errorf("%s over the %s: expected the scan cancelled, got %v", name, readerName, err)
+			}
+		}
+	}
+
+	// Scans of every family stop before reconciling what they found
+	pds := NewPatternDetectionService(db, nil, NewHeadShouldersDetectionService(db, nil, nil, nil), nil, nil, nil, nil)
+	if err := pds.AutoDetectPatternsForSymbol(ctx, "TEST"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the symbol scan cancelled, got %v", err)
+	}
+	if found, err := pds.DetectPatterns(ctx, "TEST", []string{models.AutomationPatternHeadShoulders}); !errors.Is(err, context.Canceled) || len(found) != 0 {
+		t.Errorf("expected the pattern scan cancelled, got %v (%v)", found, err)
+	}
+}
+
+// TestPatternScheduler tests the periodic loop runs scans and monitoring on their intervals and stops on Stop
+func TestPatternScheduler(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AddWatchedSymbol("TEST", "Test Inc"); err != nil {
+		t.Fatalf("AddWatchedSymbol failed: %v", err)
+	}
+
+	pds := NewPatternDetectionService(db, nil, NewHeadShouldersDetectionService(db, nil, nil, nil), nil, nil, nil, nil)
+	pds.StartPeriodicPatternDetection(20*time.Millisecond, 10*time.Millisecond)
+
+	status := pds.GetSchedulerStatus()
+	if !status.IsRunning || status.ScanInterval != "20ms" || status.MonitorInterval != "10ms" {
+		t.Errorf("expected the scheduler running on the given intervals, got %+v", status)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for status.ScanRuns < 2 || status.MonitorRuns < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for scheduled runs, got %+v", status)
+		}
+		time.Sleep(10 * time.Millisecond)
+		status = pds.GetSchedulerStatus()
+	}
+	if status.LastScanRun == nil || status.LastScanRun.Symbols != 1 || status.LastError != "" {
+		t.Errorf("expected the watched symbol scanned without errors, got %+v", status)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := pds.Stop(ctx); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	stopped := pds.GetSchedulerStatus()
+	if stopped.IsRunning {
+		t.Error("expected the scheduler stopped")
+	}
+	time.Sleep(60 * time.Millisecond)
+	if after := pds.GetSchedulerStatus(); after.ScanRuns != stopped.ScanRuns || after.MonitorRuns != stopped.MonitorRuns {
+		t.Errorf("expected no runs after Stop, went from %d/%d to %d/%d scans/monitors", stopped.ScanRuns,
+			stopped.MonitorRuns, after.ScanRuns, after.MonitorRuns)
+	}
+}
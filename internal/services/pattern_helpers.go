@@ -0,0 +1,278 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// findSwingPoints returns swing highs and lows that are the extreme of a +/- window bar range, in time order
+func findSwingPoints(priceData []*models.PriceData, window int) (highs, lows []models.PatternPoint) {
+	for i := window; i < len(priceData)-window; i++ {
+		current := priceData[i]
+		isHigh := true
+		isLow := true
+
+		for j := i - window; j <= i+window; j++ {
+			if j == i {
+				continue
+			}
+			if priceData[j].High >= current.High {
+				isHigh = false
+			}
+			if priceData[j].Low <= current.Low {
+				isLow = false
+			}
+		}
+
+		if isHigh {
+			highs = append(highs, models.PatternPoint{
+				Timestamp:   current.Timestamp,
+				Price:       current.High,
+				Volume:      current.Volume,
+				VolumeRatio: volumeRatioAt(priceData, i),
+			})
+		}
+
+		if isLow {
+			lows = append(lows, models.PatternPoint{
+				Timestamp:   current.Timestamp,
+				Price:       current.Low,
+				Volume:      current.Volume,
+				VolumeRatio: volumeRatioAt(priceData, i),
+			})
+		}
+	}
+
+	return highs, lows
+}
+
+// patternScan holds the resolved bar interval, range and swing settings of one detector run
+type patternScan struct {
+	timeframe   models.Timeframe
+	start, end  time.Time
+	swingWindow int
+	sensitivity float64
+}
+
+// resolvePatternScan applies per-request scan parameters over a detector's configured bar interval, lookback and sensitivity
+func resolvePatternScan(params *models.PatternScanParams, barInterval models.Timeframe, lookbackDays, swingWindow int, sensitivity float64) patternScan {
+	if params != nil {
+		if params.Timeframe != "" {
+			barInterval = params.Timeframe
+		}
+		if params.LookbackDays > 0 {
+			lookbackDays = params.LookbackDays
+		}
+		if params.Sensitivity > 0 {
+			sensitivity = params.Sensitivity
+		}
+	}
+	if barInterval == "" {
+		barInterval = models.DefaultTimeframe
+	}
+	if swingWindow <= 0 {
+		swingWindow = 5
+	}
+	if sensitivity <= 0 {
+		sensitivity = 1
+	}
+
+	end := clockNow()
+	return patternScan{
+		timeframe: barInterval,
+		start:     end.Add(-time.Duration(lookbackDays) * 24 * time.Hour),
+		end:       end,
+		// Higher sensitivity compares each bar with fewer neighbours, so smaller swings qualify
+		swingWindow: max(2, int(math.Round(float64(swingWindow)/sensitivity))),
+		sensitivity: sensitivity,
+	}
+}
+
+// volumeRatioAt compares a bar's volume to the average of the previous 20 bars
+func volumeRatioAt(priceData []*models.PriceData, index int) float64 {
+	if index < 20 {
+		return 1.0
+	}
+
+	var totalVolume int64
+	for i := index - 20; i < index; i++ {
+		totalVolume += priceData[i].Volume
+	}
+	avgVolume := float64(totalVolume) / 20.0
+
+	if avgVolume == 0 {
+		return 1.0
+	}
+
+	return float64(priceData[index].Volume) / avgVolume
+}
+
+// volumeProfileBetween compares average volume in the first and second half of a time range
+func volumeProfileBetween(priceData []*models.PriceData, start, end time.Time) string {
+	var earlyVolume, lateVolume int64
+	var earlyCount, lateCount int
+
+	midPoint := start.Add(end.Sub(start) / 2)
+
+	for _, data := range priceData {
+		if data.Timestamp.Before(start) || data.Timestamp.After(end) {
+			continue
+		}
+		if data.Timestamp.Before(midPoint) {
+			earlyVolume += data.Volume
+			earlyCount++
+		} else {
+			lateVolume += data.Volume
+			lateCount++
+		}
+	}
+
+	if earlyCount == 0 || lateCount == 0 || earlyVolume == 0 {
+		return "insufficient_data"
+	}
+
+	ratio := (float64(lateVolume) / float64(lateCount)) / (float64(earlyVolume) / float64(earlyCount))
+
+	if ratio < 0.8 {
+		return "decreasing"
+	} else if ratio > 1.2 {
+		return "increasing"
+	}
+	return "stable"
+}
+
+// latestVolumeRatio compares the most recent bar's volume to the average of the preceding two days
+func latestVolumeRatio(db *database.Database, symbol string) (float64, error) {
+	now := clockNow()
+	priceData, err := db.GetPriceDataRange(symbol, now.Add(-48*time.Hour), now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get recent price data: %w", err)
+	}
+
+	if len(priceData) < 2 {
+		return 1.0, nil
+	}
+
+	return volumeRatioAt(priceData, len(priceData)-1), nil
+}
+
+// completeComponent marks a thesis component as completed with supporting evidence
+func completeComponent(component *models.ThesisComponent, confidence float64, evidence ...string) {
+	if component.IsCompleted {
+		return
+	}
+
+	now := clockNow()
+	component.IsCompleted = true
+	component.CompletedAt = &now
+	component.ConfidenceLevel = confidence
+	component.Evidence = evidence
+	component.LastChecked = now
+}
+
+// notifyCompletedComponents sends an alert with a chart of the pattern for every completed component that
+// has not been notified yet
+func notifyCompletedComponents(emailService *EmailService, symbol, patternName, phase string, components []*models.ThesisComponent, targetPrice, breakoutLevel float64, pattern interface{}) {
+	for _, component := range components {
+		if !component.IsCompleted || component.NotificationSent {
+			continue
+		}
+
+		log.Printf("%s completed for %s %s pattern", component.Name, symbol, patternName)
+
+		if emailService != nil && emailService.CanNotify() {
+			err := emailService.SendThesisComponentAlert(&ComponentCompletedEmail{
+				Symbol:      symbol,
+				PatternName: patternName,
+				Phase:       phase,
+				Component:   component,
+				TargetPrice: targetPrice,
+				LevelName:   "Breakout Level",
+				Level:       breakoutLevel,
+			}, pattern)
+			if err != nil {
+				log.Printf("Failed to send email notification: %v", err)
+			}
+		}
+
+		component.NotificationSent = true
+	}
+}
+
+// notifyPatternDetected sends an alert with a chart of the pattern for a newly detected pattern
+func notifyPatternDetected(emailService *EmailService, symbol, patternName string, breakoutLevel, targetPrice, completion float64, pattern interface{}) {
+	if emailService == nil || !emailService.CanNotify() {
+		return
+	}
+
+	err := emailService.SendPatternDetectedAlert(&PatternDetectedEmail{
+		Symbol:        symbol,
+		PatternName:   patternName,
+		BreakoutLevel: breakoutLevel,
+		TargetPrice:   targetPrice,
+		Completion:    completion,
+		DetectedAt:    clockNow(),
+	}, pattern)
+	if err != nil {
+		log.Printf("Failed to send pattern detection email: %v", err)
+	}
+}
+
+// patternWebhookData returns the webhook event data of a stored pattern
+func patternWebhookData(pattern interface{}) *models.WebhookPatternData {
+	direction := func(bullish bool) string {
+		if bullish {
+			return "bullish"
+		}
+		return "bearish"
+	}
+
+	switch p := pattern.(type) {
+	case *models.HeadShouldersPattern:
+		return &models.WebhookPatternData{Family: ChartFamilyHeadShoulders, PatternID: p.ID, PatternType: p.PatternType, Direction: p.Direction(),
+			Phase: p.CurrentPhase, SetupID: p.SetupID, BreakoutLevel: p.NecklineLevel, TargetPrice: p.CalculateTargetPrice()}
+	case *models.FallingWedgePattern:
+		return &models.WebhookPatternData{Family: ChartFamilyFallingWedge, PatternID: p.ID, PatternType: p.PatternType, Direction: p.Direction(),
+			Phase: p.CurrentPhase, SetupID: p.SetupID, BreakoutLevel: p.BreakoutLevel, TargetPrice: p.CalculateTargetPrice()}
+	case *models.TrianglePattern:
+		return &models.WebhookPatternData{Family: ChartFamilyTriangle, PatternID: p.ID, PatternType: p.PatternType, Direction: direction(p.IsBullish()),
+			Phase: p.CurrentPhase, BreakoutLevel: p.BreakoutLevel, TargetPrice: p.CalculateTargetPrice()}
+	case *models.FlagPattern:
+		return &models.WebhookPatternData{Family: ChartFamilyFlag, PatternID: p.ID, PatternType: p.PatternType, Direction: direction(p.IsBullish()),
+			Phase: p.CurrentPhase, BreakoutLevel: p.BreakoutLevel, TargetPrice: p.CalculateTargetPrice()}
+	}
+	return nil
+}
+
+// emitPatternDetected posts a pattern_detected webhook for a newly stored pattern
+func emitPatternDetected(webhooks *WebhookService, symbol string, pattern interface{}) {
+	if !webhooks.IsEnabled() {
+		return
+	}
+	webhooks.Emit(models.WebhookPatternDetected, symbol, patternWebhookData(pattern))
+}
+
+// emitPhaseChange posts breakout_confirmed when a pattern moves past its breakout and target_reached when it
+// completes, given its phase before the latest price was checked
+func emitPhaseChange(webhooks *WebhookService, symbol, previousPhase, phase string, price float64, pattern interface{}) {
+	if !webhooks.IsEnabled() || phase == previousPhase {
+		return
+	}
+
+	beforeBreakout := previousPhase == models.PhaseFormation || previousPhase == models.PhaseBreakout
+	if beforeBreakout && (phase == models.PhaseTargetPursuit || phase == models.PhaseCompleted) {
+		data := patternWebhookData(pattern)
+		data.Price = price
+		webhooks.Emit(models.WebhookBreakoutConfirmed, symbol, data)
+	}
+	if phase == models.PhaseCompleted {
+		data := patternWebhookData(pattern)
+		data.Price = price
+		webhooks.Emit(models.WebhookTargetReached, symbol, data)
+	}
+}
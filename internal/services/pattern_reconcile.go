@@ -0,0 +1,227 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+const (
+	// Pivots of two patterns are the same swing point when their prices are within this percentage and their
+	// times within reconcileTimeTolerance of the shorter pattern's span (but at least reconcileMinTimeTolerance)
+	reconcilePriceTolerance   = 0.5
+	reconcileTimeTolerance    = 0.05
+	reconcileMinTimeTolerance = time.Hour
+
+	// reconcileMinSharedPivots is how many pivots two patterns must share to be the same structure
+	reconcileMinSharedPivots = 2
+)
+
+// reconcilePattern is the part of an active pattern of any family that reconciliation compares
+type reconcilePattern struct {
+	family    string
+	id        int64
+	direction string
+	setupID   int64
+	pivots    []models.PatternPoint
+}
+
+// ReconcilePatterns links a symbol's active patterns of different families that are built on the same pivots.
+// When both linked patterns produced an active trading setup, the higher quality one is kept and the other is
+// invalidated, so one price structure yields one setup. Returns the links made in this run.
+func (pds *PatternDetectionService) ReconcilePatterns(symbol string) ([]*models.PatternLink, error) {
+	patterns, err := pds.activePatterns(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := pds.db.GetPatternLinks(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pattern links: %w", err)
+	}
+
+	var links []*models.PatternLink
+	for i, a := range patterns {
+		for _, b := range patterns[i+1:] {
+			if a.family == b.family || a.direction != b.direction || linked(existing, a, b) {
+				continue
+			}
+			shared := sharedPivots(a.pivots, b.pivots)
+			if shared < reconcileMinSharedPivots {
+				continue
+			}
+
+			link := &models.PatternLink{
+				Symbol:          symbol,
+				Direction:       a.direction,
+				Family:          a.family,
+				PatternID:       a.id,
+				LinkedFamily:    b.family,
+				LinkedPatternID: b.id,
+				SharedPivots:    shared,
+			}
+			if err := pds.resolveSetups(link, a.setupID, b.setupID); err != nil {
+				return links, err
+			}
+			if err := pds.db.InsertPatternLink(link); err != nil {
+				return links, err
+			}
+
+			log.Printf("Linked %s #%d and %s #%d for %s (%d shared pivots)", a.family, a.id, b.family, b.id, symbol, shared)
+			existing = append(existing, link)
+			links = append(links, link)
+		}
+	}
+
+	return links, nil
+}
+
+// activePatterns loads the symbol's incomplete patterns of every family
+func (pds *PatternDetectionService) activePatterns(symbol string) ([]*reconcilePattern, error) {
+	incomplete := false
+	var patterns []*reconcilePattern
+
+	hs, err := pds.db.GetHeadShouldersPatterns(&models.PatternFilter{Symbol: symbol, IsComplete: &incomplete})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get head and shoulders patterns: %w", err)
+	}
+	for _, p := range hs {
+		patterns = append(patterns, &reconcilePattern{
+			family: ChartFamilyHeadShoulders, id: p.ID, direction: p.Direction(), setupID: p.SetupID,
+			pivots: []models.PatternPoint{p.LeftShoulderHigh, p.LeftShoulderLow, p.HeadHigh, p.HeadLow,
+				p.RightShoulderHigh, p.RightShoulderLow, p.NecklineTouch1, p.NecklineTouch2},
+		})
+	}
+
+	wedges, err := pds.db.GetFallingWedgePatterns(&models.FallingWedgeFilter{Symbol: symbol, IsComplete: &incomplete})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wedge patterns: %w", err)
+	}
+	for _, p := range wedges {
+		patterns = append(patterns, &reconcilePattern{
+			family: ChartFamilyFallingWedge, id: p.ID, direction: p.Direction(), setupID: p.SetupID,
+			pivots: []models.PatternPoint{p.UpperTrendLine1, p.UpperTrendLine2, p.LowerTrendLine1, p.LowerTrendLine2},
+		})
+	}
+
+	triangles, err := pds.db.GetTrianglePatterns(&models.TriangleFilter{Symbol: symbol, IsComplete: &incomplete})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get triangle patterns: %w", err)
+	}
+	for _, p := range triangles {
+		patterns = append(patterns, &reconcilePattern{
+			family: ChartFamilyTriangle, id: p.ID, direction: bullishDirection(p.IsBullish()),
+			pivots: []models.PatternPoint{p.UpperTrendLine1, p.UpperTrendLine2, p.LowerTrendLine1, p.LowerTrendLine2},
+		})
+	}
+
+	flags, err := pds.db.GetFlagPatterns(&models.FlagFilter{Symbol: symbol, IsComplete: &incomplete})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flag patterns: %w", err)
+	}
+	for _, p := range flags {
+		patterns = append(patterns, &reconcilePattern{
+			family: ChartFamilyFlag, id: p.ID, direction: bullishDirection(p.IsBullish()),
+			pivots: []models.PatternPoint{p.PoleStart, p.PoleEnd, p.FlagHigh, p.FlagLow},
+		})
+	}
+
+	return patterns, nil
+}
+
+// resolveSetups keeps the higher quality of two active setups and invalidates the other, recording both on
+// the link. Nothing changes unless both patterns have an active setup.
+func (pds *PatternDetectionService) resolveSetups(link *models.PatternLink, setupA, setupB int64) error {
+	if setupA == 0 || setupB == 0 || setupA == setupB {
+		return nil
+	}
+
+	a, err := pds.db.GetTradingSetupByID(setupA)
+	if err != nil {
+		return fmt.Errorf("failed to get setup %d: %w", setupA, err)
+	}
+	b, err := pds.db.GetTradingSetupByID(setupB)
+	if err != nil {
+		return fmt.Errorf("failed to get setup %d: %w", setupB, err)
+	}
+	if a == nil || b == nil || a.Status != models.SetupStatusActive || b.Status != models.SetupStatusActive {
+		return nil
+	}
+
+	kept, superseded := a, b
+	if b.QualityScore > a.QualityScore {
+		kept, superseded = b, a
+	}
+
+	superseded.Status = models.SetupStatusInvalidated
+	superseded.Notes = fmt.Sprintf("Superseded by setup %d, which shares its pivots with a higher quality score", kept.ID)
+	if err := pds.db.UpdateTradingSetup(superseded); err != nil {
+		return fmt.Errorf("failed to invalidate setup %d: %w", superseded.ID, err)
+	}
+	pds.webhooks.Emit(models.WebhookSetupInvalidated, superseded.Symbol, &models.WebhookSetupData{Setup: superseded, Reason: superseded.Notes})
+
+	link.KeptSetupID = kept.ID
+	link.SupersededSetupID = superseded.ID
+	return nil
+}
+
+// linked reports whether two patterns already have a link, in either order
+func linked(links []*models.PatternLink, a, b *reconcilePattern) bool {
+	for _, link := range links {
+		if link.Links(a.family, a.id) && link.Links(b.family, b.id) {
+			return true
+		}
+	}
+	return false
+}
+
+// sharedPivots counts the pivots of a that match a pivot of b. Unset pivots never match.
+func sharedPivots(a, b []models.PatternPoint) int {
+	tolerance := max(min(pivotSpan(a), pivotSpan(b))*reconcileTimeTolerance, float64(reconcileMinTimeTolerance))
+
+	shared := 0
+	for _, p := range a {
+		if p.Timestamp.IsZero() || p.Price <= 0 {
+			continue
+		}
+		for _, q := range b {
+			if q.Timestamp.IsZero() || q.Price <= 0 {
+				continue
+			}
+			if math.Abs(float64(p.Timestamp.Sub(q.Timestamp))) <= tolerance &&
+				math.Abs(p.Price-q.Price)/q.Price*100 <= reconcilePriceTolerance {
+				shared++
+				break
+			}
+		}
+	}
+	return shared
+}
+
+// pivotSpan returns the time between a pattern's first and last set pivots
+func pivotSpan(points []models.PatternPoint) float64 {
+	var first, last time.Time
+	for _, p := range points {
+		if p.Timestamp.IsZero() {
+			continue
+		}
+		if first.IsZero() || p.Timestamp.Before(first) {
+			first = p.Timestamp
+		}
+		if p.Timestamp.After(last) {
+			last = p.Timestamp
+		}
+	}
+	return float64(last.Sub(first))
+}
+
+// bullishDirection returns the trading direction for a pattern's bullish flag
+func bullishDirection(bullish bool) string {
+	if bullish {
+		return "bullish"
+	}
+	return "bearish"
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestSharedPivots tests that pivots match within the price and time tolerances and unset pivots never match
+func TestSharedPivots(t *testing.T) {
+	start := time.Date(2025, 3, 3, 15, 0, 0, 0, time.UTC)
+	point := func(days float64, price float64) models.PatternPoint {
+		return models.PatternPoint{Timestamp: start.Add(time.Duration(days * float64(24*time.Hour))), Price: price}
+	}
+	pattern := []models.PatternPoint{point(0, 110), point(5, 100), point(10, 115), point(15, 101), {}}
+
+	tests := []struct {
+		name   string
+		other  []models.PatternPoint
+		shared int
+	}{
+		{"same pivots", []models.PatternPoint{point(0, 110.2), point(10, 115), point(15, 101)}, 3},
+		{"close in time", []models.PatternPoint{point(0.04, 110), point(5.04, 100.3)}, 2},
+		{"price too far", []models.PatternPoint{point(0, 112), point(5, 102)}, 0},
+		{"time too far", []models.PatternPoint{point(2, 110), point(7, 100)}, 0},
+		{"unset pivots", []models.PatternPoint{{}, {}}, 0},
+	}
+	for _, tt := range tests {
+		if shared := sharedPivots(pattern, tt.other); shared != tt.shared {
+			t.Errorf("%s: expected %d shared pivots, got %d", tt.name, tt.shared, shared)
+		}
+	}
+}
+
+// TestReconcilePatterns tests that patterns of different families on the same pivots are linked once and only
+// the higher quality setup stays active
+func TestReconcilePatterns(t *testing.T) {
+	db := newTestDB(t)
+
+	pds := NewPatternDetectionService(db, nil, nil, nil, nil, nil, nil)
+
+	detected := time.Now().UTC().Truncate(time.Minute)
+	day := func(n int) time.Time { return detected.Add(time.Duration(n-20) * 24 * time.Hour) }
+	newSetup := func(quality float64) *models.TradingSetup {
+		setup := &models.TradingSetup{
+			Symbol: "TEST", SetupType: "pattern", Direction: "bearish", Status: models.SetupStatusActive,
+			Confidence: "medium", QualityScore: quality, DetectedAt: detected, ExpiresAt: detected.Add(48 * time.Hour),
+			EntryPrice: 100, StopLoss: 106, Target1: 94,
+		}
+		if err := db.InsertTradingSetup(setup); err != nil {
+			t.Fatalf("InsertTradingSetup failed: %v", err)
+		}
+		return setup
+	}
+	hsSetup, wedgeSetup := newSetup(70), newSetup(85)
+
+	hs := &models.HeadShouldersPattern{
+		SetupID: hsSetup.ID, Symbol: "TEST", PatternType: "head_shoulders", CurrentPhase: "formation",
+		LeftShoulderHigh:  models.PatternPoint{Timestamp: day(0), Price: 108},
+		HeadHigh:          models.PatternPoint{Timestamp: day(8), Price: 115},
+		RightShoulderHigh: models.PatternPoint{Timestamp: day(16), Price: 109},
+		NecklineTouch1:    models.PatternPoint{Timestamp: day(4), Price: 100},
+		NecklineTouch2:    models.PatternPoint{Timestamp: day(12), Price: 101},
+		DetectedAt:        detected, LastUpdated: detected,
+	}
+	if err := db.InsertHeadShouldersPattern(hs); err != nil {
+		t.Fatalf("InsertHeadShouldersPattern failed: %v", err)
+	}
+	wedge := &models.FallingWedgePattern{
+		SetupID: wedgeSetup.ID, Symbol: "TEST", PatternType: models.PatternRisingWedge, CurrentPhase: "formation",
+		UpperTrendLine1: models.PatternPoint{Timestamp: day(8), Price: 115.2},
+		UpperTrendLine2: models.PatternPoint{Timestamp: day(16), Price: 109},
+		LowerTrendLine1: models.PatternPoint{Timestamp: day(4), Price: 100.1},
+		LowerTrendLine2: models.PatternPoint{Timestamp: day(18), Price: 104},
+		DetectedAt:      detected, LastUpdated: detected,
+	}
+	if err := db.InsertFallingWedgePattern(wedge); err != nil {
+		t.Fatalf("InsertFallingWedgePattern failed: %v", err)
+	}
+
+	for range 2 {
+		if _, err := pds.ReconcilePatterns("TEST"); err != nil {
+			t.Fatalf("ReconcilePatterns failed: %v", err)
+		}
+	}
+
+	links, err := db.GetPatternLinks("TEST")
+	if err != nil {
+		t.Fatalf("GetPatternLinks failed: %v", err)
+	}
+	if len(links) != 1 {
+		t.Fatalf("expected one link, got %+v", links)
+	}
+	if link := links[0]; link.SharedPivots != 3 || link.KeptSetupID != wedgeSetup.ID || link.SupersededSetupID != hsSetup.ID {
+		t.Errorf("expected the wedge's setup kept over the H&S setup on 3 shared pivots, got %+v", link)
+	}
+
+	kept, _ := db.GetTradingSetupByID(wedgeSetup.ID)
+	superseded, _ := db.GetTradingSetupByID(hsSetup.ID)
+	if kept.Status != models.SetupStatusActive || superseded.Status != models.SetupStatusInvalidated {
+		t.Errorf("expected only the higher quality setup active, got %s and %s", kept.Status, superseded.Status)
+	}
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// PatternTimelineService assembles the thesis timeline of stored patterns
+type PatternTimelineService struct {
+	db *database.Database
+}
+
+// NewPatternTimelineService creates a new pattern timeline service
+func NewPatternTimelineService(db *database.Database) *PatternTimelineService {
+	return &PatternTimelineService{db: db}
+}
+
+// timelineThesis is a copy of a pattern's thesis whose phase can be replayed component by component
+type timelineThesis struct {
+	components []*models.ThesisComponent
+	update     func() string // Updates the phase from the components and returns it
+}
+
+// Timeline returns the chronological thesis component completions, phase changes and alerts of a pattern of a
+// chart family
+func (pts *PatternTimelineService) Timeline(family string, id int64) (*models.PatternTimeline, error) {
+	timeline := &models.PatternTimeline{Family: family, PatternID: id}
+	var components []*models.ThesisComponent
+	var replay timelineThesis
+	var detectedAt, lastUpdated time.Time
+	var alerts []*models.PatternAlert
+
+	switch family {
+	case ChartFamilyHeadShoulders:
+		p, err := pts.db.GetHeadShouldersPatternByID(id)
+		if err != nil || p == nil {
+			return nil, patternLookupError(err)
+		}
+		timeline.Symbol, timeline.PatternType, timeline.CurrentPhase = p.Symbol, p.PatternType, p.CurrentPhase
+		timeline.CompletionPercent = p.ThesisComponents.CompletionPercent
+		components, detectedAt, lastUpdated = p.ThesisComponents.GetAllComponents(), p.DetectedAt, p.LastUpdated
+		thesis := p.ThesisComponents
+		replay = timelineThesis{thesis.GetAllComponents(), func() string { thesis.UpdatePhase(); return thesis.CurrentPhase }}
+
+		if alerts, err = pts.db.GetPatternAlerts(id); err != nil {
+			return nil, fmt.Errorf("failed to get pattern alerts: %w", err)
+		}
+		if alerts == nil {
+			alerts = make([]*models.PatternAlert, 0) // Head and shoulders alerts are stored, even when there are none
+		}
+	case ChartFamilyFallingWedge:
+		p, err := pts.db.GetFallingWedgePatternByID(id)
+		if err != nil || p == nil {
+			return nil, patternLookupError(err)
+		}
+		timeline.Symbol, timeline.PatternType, timeline.CurrentPhase = p.Symbol, p.PatternType, p.CurrentPhase
+		timeline.CompletionPercent = p.ThesisComponents.CompletionPercent
+		components, detectedAt, lastUpdated = p.ThesisComponents.GetAllComponents(), p.DetectedAt, p.LastUpdated
+		thesis := p.ThesisComponents
+		replay = timelineThesis{thesis.GetAllComponents(), func() string { thesis.UpdatePhase(); return thesis.CurrentPhase }}
+	case ChartFamilyTriangle:
+		p, err := pts.db.GetTrianglePatternByID(id)
+		if err != nil || p == nil {
+			return nil, patternLookupError(err)
+		}
+		timeline.Symbol, timeline.PatternType, timeline.CurrentPhase = p.Symbol, p.PatternType, p.CurrentPhase
+		timeline.CompletionPercent = p.ThesisComponents.CompletionPercent
+		components, detectedAt, lastUpdated = p.ThesisComponents.GetAllComponents(), p.DetectedAt, p.LastUpdated
+		thesis := p.ThesisComponents
+		replay = timelineThesis{thesis.GetAllComponents(), func() string { thesis.UpdatePhase(); return thesis.CurrentPhase }}
+	case ChartFamilyFlag:
+		p, err := pts.db.GetFlagPatternByID(id)
+		if err != nil || p == nil {
+			return nil, patternLookupError(err)
+		}
+		timeline.Symbol, timeline.PatternType, timeline.CurrentPhase = p.Symbol, p.PatternType, p.CurrentPhase
+		timeline.CompletionPercent = p.ThesisComponents.CompletionPercent
+		components, detectedAt, lastUpdated = p.ThesisComponents.GetAllComponents(), p.DetectedAt, p.LastUpdated
+		thesis := p.ThesisComponents
+		replay = timelineThesis{thesis.GetAllComponents(), func() string { thesis.UpdatePhase(); return thesis.CurrentPhase }}
+	default:
+		return nil, fmt.Errorf("invalid pattern type %q: must be one of %s", family, strings.Join(ChartFamilies, ", "))
+	}
+
+	timeline.Events, timeline.Pending = buildPatternTimeline(components, replay, detectedAt, lastUpdated, timeline.CurrentPhase, alerts)
+	return timeline, nil
+}
+
+// patternLookupError returns the error of a pattern lookup, or ErrPatternNotFound when it found nothing
+func patternLookupError(err error) error {
+	if err != nil {
+		return err
+	}
+	return ErrPatternNotFound
+}
+
+// buildPatternTimeline orders a pattern's detection, completed components, phase changes and alerts by time.
+// Phase changes are replayed on a copy of the thesis by completing its components in order; when the stored
+// phase differs from the replayed one, the change is placed at the pattern's last update. Stored alerts are
+// used when the family records them, otherwise a notified component counts as alerted when it completed.
+func buildPatternTimeline(components []*models.ThesisComponent, replay timelineThesis, detectedAt, lastUpdated time.Time, currentPhase string, alerts []*models.PatternAlert) ([]*models.PatternTimelineEvent, []string) {
+	events := []*models.PatternTimelineEvent{{
+		Timestamp: detectedAt,
+		EventType: models.TimelineEventDetected,
+		Phase:     models.PhaseFormation,
+		Message:   "Pattern detected",
+	}}
+	pending := make([]string, 0)
+
+	// Completion order, with components lacking a completion time counted as completed at detection
+	completedAt := func(component *models.ThesisComponent) time.Time {
+		if component.CompletedAt != nil {
+			return *component.CompletedAt
+		}
+		return detectedAt
+	}
+	order := make([]int, 0, len(components))
+	for i, component := range components {
+		if component.IsCompleted {
+			order = append(order, i)
+		} else {
+			pending = append(pending, component.Name)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return completedAt(components[order[a]]).Before(completedAt(components[order[b]]))
+	})
+
+	for _, component := range replay.components {
+		component.IsCompleted = false
+	}
+	phase := models.PhaseFormation
+	for _, i := range order {
+		component := components[i]
+		at := completedAt(component)
+		events = append(events, &models.PatternTimelineEvent{
+			Timestamp: at,
+			EventType: models.TimelineEventComponent,
+			Component: component.Name,
+			Message:   fmt.Sprintf("%s completed", component.Name),
+			Evidence:  component.Evidence,
+		})
+		if alerts == nil && component.NotificationSent {
+			events = append(events, &models.PatternTimelineEvent{
+				Timestamp: at,
+				EventType: models.TimelineEventAlert,
+				Component: component.Name,
+				Message:   fmt.Sprintf("Alert sent: %s completed", component.Name),
+			})
+		}
+
+		replay.components[i].IsCompleted = true
+		if next := replay.update(); next != phase {
+			events = append(events, phaseEvent(at, phase, next))
+			phase = next
+		}
+	}
+	if currentPhase != "" && currentPhase != phase {
+		events = append(events, phaseEvent(lastUpdated, phase, currentPhase))
+	}
+
+	for _, alert := range alerts {
+		events = append(events, &models.PatternTimelineEvent{
+			Timestamp: alert.TriggeredAt,
+			EventType: models.TimelineEventAlert,
+			Component: alert.ComponentName,
+			Message:   alert.Message,
+		})
+	}
+
+	rank := map[string]int{
+		models.TimelineEventDetected: 0, models.TimelineEventComponent: 1,
+		models.TimelineEventPhase: 2, models.TimelineEventAlert: 3,
+	}
+	sort.SliceStable(events, func(a, b int) bool {
+		if !events[a].Timestamp.Equal(events[b].Timestamp) {
+			return events[a].Timestamp.Before(events[b].Timestamp)
+		}
+		return rank[events[a].EventType] < rank[events[b].EventType]
+	})
+	return events, pending
+}
+
+// phaseEvent records a move from one phase to another
+func phaseEvent(at time.Time, from, to string) *models.PatternTimelineEvent {
+	return &models.PatternTimelineEvent{
+		Timestamp:     at,
+		EventType:     models.TimelineEventPhase,
+		Phase:         to,
+		PreviousPhase: from,
+		Message:       fmt.Sprintf("Phase changed from %s to %s", from, to),
+	}
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestPatternTimeline tests that completions are ordered by time with the phase changes they caused and the
+// alerts sent about them
+func TestPatternTimeline(t *testing.T) {
+	db := newTestDB(t)
+
+	detected := time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)
+	at := func(hours int) *time.Time {
+		ts := detected.Add(time.Duration(hours) * time.Hour)
+		return &ts
+	}
+
+	pattern := &models.FlagPattern{Symbol: "TEST", PatternType: "bull_flag", DetectedAt: detected, LastUpdated: *at(5)}
+	pattern.ThesisComponents.InitializeFlagThesis(true)
+	thesis := &pattern.ThesisComponents
+	thesis.OrderlyChannel.IsCompleted, thesis.OrderlyChannel.CompletedAt = true, at(2)
+	thesis.StrongPole.IsCompleted, thesis.StrongPole.CompletedAt = true, at(0)
+	thesis.StrongPole.Evidence = []string{"pole up 12%"}
+	thesis.ChannelBreak.IsCompleted, thesis.ChannelBreak.CompletedAt, thesis.ChannelBreak.NotificationSent = true, at(4), true
+	thesis.CalculateCompletion()
+	thesis.UpdatePhase()
+	pattern.CurrentPhase = thesis.CurrentPhase
+	if err := db.InsertFlagPattern(pattern); err != nil {
+		t.Fatalf("InsertFlagPattern failed: %v", err)
+	}
+
+	timeline, err := NewPatternTimelineService(db).Timeline(ChartFamilyFlag, pattern.ID)
+	if err != nil {
+		t.Fatalf("Timeline failed: %v", err)
+	}
+
+	expected := []struct {
+		eventType, detail string
+		hours             int
+	}{
+		{models.TimelineEventDetected, "", 0},
+		{models.TimelineEventComponent, "Strong Flagpole", 0},
+		{models.TimelineEventComponent, "Orderly Channel", 2},
+		{models.TimelineEventPhase, models.PhaseBreakout, 2},
+		{models.TimelineEventComponent, "Channel Break", 4},
+		{models.TimelineEventPhase, models.PhaseTargetPursuit, 4},
+		{models.TimelineEventAlert, "Channel Break", 4},
+	}
+	if len(timeline.Events) != len(expected) {
+		t.Fatalf("expected %d events, got %d: %+v", len(expected), len(timeline.Events), timeline.Events)
+	}
+	for i, want := range expected {
+		event := timeline.Events[i]
+		detail := event.Component
+		if event.EventType == models.TimelineEventPhase {
+			detail = event.Phase
+		}
+		if event.EventType != want.eventType || detail != want.detail || !event.Timestamp.Equal(*at(want.hours)) {
+			t.Errorf("event %d: expected %s %q at +%dh, got %+v", i, want.eventType, want.detail, want.hours, event)
+		}
+	}
+	if len(timeline.Events[1].Evidence) != 1 || timeline.CurrentPhase != models.PhaseTargetPursuit {
+		t.Errorf("expected the pole evidence and the stored phase, got %+v", timeline)
+	}
+	if len(timeline.Pending) != 6 || timeline.Pending[0] != "Shallow Retracement" {
+		t.Errorf("expected the 6 components not completed yet, got %v", timeline.Pending)
+	}
+
+	if _, err := NewPatternTimelineService(db).Timeline(ChartFamilyTriangle, 99); !errors.Is(err, ErrPatternNotFound) {
+		t.Errorf("expected a missing pattern to be not found, got %v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"strconv"
 	"time"
 
 	"market-watch-go/internal/config"
@@ -14,8 +15,9 @@ import (
 )
 
 type PolygonService struct {
-	client *http.Client
-	cfg    *config.Config
+	client  *http.Client
+	cfg     *config.Config
+	limiter *RateLimiter
 }
 
 // PolygonResponse represents the response from Polygon.io aggregates API
@@ -49,15 +51,86 @@ func NewPolygonService(cfg *config.Config) *PolygonService {
 	}
 
 	return &PolygonService{
-		client: client,
-		cfg:    cfg,
+		client:  client,
+		cfg:     cfg,
+		limiter: NewRateLimiter(cfg.Polygon.RequestsPerMinute, cfg.Polygon.Burst, cfg.Polygon.DailyBudget),
+	}
+}
+
+// RateLimitStats returns request pacing and budget usage for the Polygon API key
+func (ps *PolygonService) RateLimitStats() *RateLimitStats {
+	return ps.limiter.Stats()
+}
+
+// doRequest performs a GET request within the rate limit, backing off and retrying on 429 and 5xx responses
+func (ps *PolygonService) doRequest(ctx context.Context, url string) (*http.Response, error) {
+	maxBackoff := ps.cfg.Polygon.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = time.Minute
+	}
+	attempts := ps.cfg.Polygon.RetryAttempts
+	if attempts < 0 {
+		attempts = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			ps.limiter.RecordRetry()
+		}
+		if err := ps.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		resp, err := ps.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to make request: %w", ErrUpstreamUnavailable, err)
+		}
+
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < http.StatusInternalServerError {
+			return resp, nil
+		}
+
+		delay := backoffDelay(attempt, maxBackoff)
+		if retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && retryAfter > 0 {
+			delay = time.Duration(retryAfter) * time.Second
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			ps.limiter.Backoff(delay)
+			if attempt >= attempts {
+				resp.Body.Close()
+				return nil, fmt.Errorf("%w: polygon returned 429 after %d attempts", ErrUpstreamRateLimited, attempt+1)
+			}
+			log.Printf("Polygon rate limit hit, backing off for %s (attempt %d/%d)", delay, attempt+1, attempts+1)
+			resp.Body.Close()
+			continue
+		}
+
+		if attempt >= attempts {
+			return resp, nil
+		}
+		resp.Body.Close()
+		log.Printf("Polygon returned status %d, retrying in %s (attempt %d/%d)", resp.StatusCode, delay, attempt+1, attempts+1)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("failed to make request: %w", ctx.Err())
+		case <-timer.C:
+		}
 	}
 }
 
 // GetAggregates fetches aggregated data for a symbol
 func (ps *PolygonService) GetAggregates(symbol string, from, to time.Time) ([]*localmodels.VolumeData, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), ps.cfg.Polygon.Timeout)
-	defer cancel()
+	// The client timeout bounds each attempt so time spent waiting on the rate limiter doesn't count against it
+	ctx := context.Background()
 
 	// Format dates for Polygon API
 	fromStr := from.Format("2006-01-02")
@@ -68,21 +141,16 @@ func (ps *PolygonService) GetAggregates(symbol string, from, to time.Time) ([]*l
 		ps.cfg.Polygon.BaseURL, symbol, fromStr, toStr, ps.cfg.Polygon.APIKey)
 
 	// Make the HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	resp, err := ps.doRequest(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := ps.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	// Check status code
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("%w: API request failed with status %d: %s", ErrUpstreamUnavailable, resp.StatusCode, string(body))
 	}
 
 	// Parse the response
@@ -158,9 +226,6 @@ func (ps *PolygonService) GetMultipleSymbolAggregates(symbols []string, from, to
 			continue
 		}
 		result[symbol] = data
-
-		// Add a small delay to avoid hitting rate limits
-		time.Sleep(200 * time.Millisecond)
 	}
 
 	return result, nil
@@ -225,12 +290,7 @@ func (ps *PolygonService) ValidateAPIKey() error {
 	url := fmt.Sprintf("%s/v2/aggs/ticker/AAPL/range/1/day/%s/%s?adjusted=true&sort=desc&limit=1&apikey=%s",
 		ps.cfg.Polygon.BaseURL, fromStr, toStr, ps.cfg.Polygon.APIKey)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := ps.client.Do(req)
+	resp, err := ps.doRequest(ctx, url)
 	if err != nil {
 		return fmt.Errorf("API key validation failed: %w", err)
 	}
@@ -314,8 +374,8 @@ func (ps *PolygonService) GetHistoricalData(symbol string, days int) ([]*localmo
 
 // GetPriceAggregates fetches price aggregated data for a symbol
 func (ps *PolygonService) GetPriceAggregates(symbol string, from, to time.Time) ([]*localmodels.PriceData, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), ps.cfg.Polygon.Timeout)
-	defer cancel()
+	// The client timeout bounds each attempt so time spent waiting on the rate limiter doesn't count against it
+	ctx := context.Background()
 
 	// Format dates for Polygon API
 	fromStr := from.Format("2006-01-02")
@@ -326,21 +386,16 @@ func (ps *PolygonService) GetPriceAggregates(symbol string, from, to time.Time)
 		ps.cfg.Polygon.BaseURL, symbol, fromStr, toStr, ps.cfg.Polygon.APIKey)
 
 	// Make the HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := ps.client.Do(req)
+	resp, err := ps.doRequest(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	// Check status code
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("%w: API request failed with status %d: %s", ErrUpstreamUnavailable, resp.StatusCode, string(body))
 	}
 
 	// Parse the response
@@ -14,20 +14,30 @@ import (
 // You must set the API key in config.yaml
 
 type PolygonEMAService struct {
-	client *polygon.Client
+	client  *polygon.Client
+	timeout time.Duration // bounds each Polygon call, on top of the caller's context
 }
 
-func NewPolygonEMAService(apiKey string) *PolygonEMAService {
+func NewPolygonEMAService(apiKey string, timeout time.Duration) *PolygonEMAService {
 	if apiKey == "" {
 		log.Fatal("Polygon API key is required for PolygonEMAService. Please set it in config.yaml.")
 	}
 	return &PolygonEMAService{
-		client: polygon.New(apiKey),
+		client:  polygon.New(apiKey),
+		timeout: timeout,
 	}
 }
 
+// callContext bounds a single Polygon call by the configured timeout
+func (s *PolygonEMAService) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, s.timeout)
+}
+
 // GetEMA queries Polygon for the EMA for a symbol, window, and timespan (e.g. 9, 50, 200, "day")
-func (s *PolygonEMAService) GetEMA(symbol string, window int, timespan string, limit int) (*models.GetEMAResponse, error) {
+func (s *PolygonEMAService) GetEMA(ctx context.Context, symbol string, window int, timespan string, limit int) (*models.GetEMAResponse, error) {
 	params := models.GetEMAParams{
 		Ticker: symbol,
 	}.
@@ -40,20 +50,22 @@ func (s *PolygonEMAService) GetEMA(symbol string, window int, timespan string, l
 
 	log.Printf("[PolygonEMAService] GetEMA request for %s (window %d): %+v", symbol, window, params)
 
-	resp, err := s.client.GetEMA(context.Background(), params)
+	ctx, cancel := s.callContext(ctx)
+	defer cancel()
+	resp, err := s.client.GetEMA(ctx, params)
 	if err != nil {
 		log.Printf("[PolygonEMAService] GetEMA error for %s (window %d): %v", symbol, window, err)
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", ErrUpstreamUnavailable, err)
 	}
 	log.Printf("[PolygonEMAService] GetEMA response for %s (window %d): %+v", symbol, window, resp)
 	return resp, nil
 }
 
 // GetEMABatch queries Polygon for multiple EMAs (e.g. 9, 50, 200) for a symbol
-func (s *PolygonEMAService) GetEMABatch(symbol string, windows []int, timespan string, limit int) (map[int]*models.GetEMAResponse, error) {
+func (s *PolygonEMAService) GetEMABatch(ctx context.Context, symbol string, windows []int, timespan string, limit int) (map[int]*models.GetEMAResponse, error) {
 	results := make(map[int]*models.GetEMAResponse)
 	for _, w := range windows {
-		resp, err := s.GetEMA(symbol, w, timespan, limit)
+		resp, err := s.GetEMA(ctx, symbol, w, timespan, limit)
 		if err != nil {
 			return nil, err
 		}
@@ -63,7 +75,7 @@ func (s *PolygonEMAService) GetEMABatch(symbol string, windows []int, timespan s
 }
 
 // GetLastPrice fetches the last close price for a symbol from Polygon (free-tier compatible)
-func (s *PolygonEMAService) GetLastPrice(symbol string) (float64, error) {
+func (s *PolygonEMAService) GetLastPrice(ctx context.Context, symbol string) (float64, error) {
 	from := models.Millis(time.Now().AddDate(0, 0, -7))
 	to := models.Millis(time.Now())
 	limit := 1
@@ -77,10 +89,12 @@ func (s *PolygonEMAService) GetLastPrice(symbol string) (float64, error) {
 	}
 
 	log.Printf("[PolygonEMAService] GetLastPrice (daily agg) request for %s: %+v", symbol, params)
-	resp, err := s.client.GetAggs(context.Background(), params)
+	ctx, cancel := s.callContext(ctx)
+	defer cancel()
+	resp, err := s.client.GetAggs(ctx, params)
 	if err != nil {
 		log.Printf("[PolygonEMAService] GetLastPrice (daily agg) error for %s: %v", symbol, err)
-		return 0, err
+		return 0, fmt.Errorf("%w: %w", ErrUpstreamUnavailable, err)
 	}
 	log.Printf("[PolygonEMAService] GetLastPrice (daily agg) response for %s: %+v", symbol, resp)
 	if resp == nil || len(resp.Results) == 0 {
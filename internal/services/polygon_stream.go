@@ -0,0 +1,510 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+
+	"github.com/gorilla/websocket"
+)
+
+// Polygon WebSocket channels
+const (
+	PolygonChannelMinuteAggs = "AM"
+	PolygonChannelSecondAggs = "A"
+	PolygonChannelTrades     = "T"
+)
+
+const (
+	defaultPolygonStreamURL = "wss://delayed.polygon.io/stocks"
+	maxStreamReconnectDelay = time.Minute
+	streamFlushInterval     = 5 * time.Second
+	streamResubscribeEvery  = time.Minute
+	streamLateTradeGrace    = 2 * time.Second
+)
+
+// PolygonStreamStatus describes the WebSocket ingestion state
+type PolygonStreamStatus struct {
+	Enabled       bool       `json:"enabled"`
+	Connected     bool       `json:"connected"`
+	Channel       string     `json:"channel"`
+	Symbols       []string   `json:"symbols"`
+	LastMessageAt *time.Time `json:"last_message_at,omitempty"`
+	BarsWritten   int64      `json:"bars_written"`
+	Reconnects    int        `json:"reconnects"`
+	LastError     string     `json:"last_error,omitempty"`
+}
+
+// polygonStreamEvent is a single message from the Polygon stocks socket
+type polygonStreamEvent struct {
+	Event     string  `json:"ev"`
+	Status    string  `json:"status"`
+	Message   string  `json:"message"`
+	Symbol    string  `json:"sym"`
+	Open      float64 `json:"o"`
+	High      float64 `json:"h"`
+	Low       float64 `json:"l"`
+	Close     float64 `json:"c"`
+	Volume    float64 `json:"v"`
+	S         float64 `json:"s"` // Aggregate start time (ms) or trade size
+	Price     float64 `json:"p"`
+	Timestamp int64   `json:"t"` // Trade time (ms)
+}
+
+// PolygonStream subscribes to Polygon's WebSocket feed and buffers ticks into 1-minute bars
+type PolygonStream struct {
+	enabled        bool
+	url            string
+	apiKey         string
+	channel        string
+	reconnectDelay time.Duration
+	flushInterval  time.Duration
+	symbols        func() ([]string, error)
+	onBars         func(bars []*models.PriceData)
+	aggregator     *BarAggregator
+
+	conn          *websocket.Conn
+	connected     bool
+	subscribed    map[string]bool
+	lastMessageAt time.Time
+	barsWritten   int64
+	reconnects    int
+	lastError     string
+	mutex         sync.RWMutex
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup // tracks the connection loop
+}
+
+// NewPolygonStream creates a WebSocket ingester for the symbols returned by symbols; completed bars are passed to onBars
+func NewPolygonStream(cfg *config.Config, symbols func() ([]string, error), onBars func(bars []*models.PriceData)) *PolygonStream {
+	rt := cfg.Collection.Realtime
+
+	url := rt.URL
+	if url == "" {
+		url = defaultPolygonStreamURL
+	}
+	channel := strings.ToUpper(rt.Channel)
+	switch channel {
+	case PolygonChannelMinuteAggs, PolygonChannelSecondAggs, PolygonChannelTrades:
+	default:
+		channel = PolygonChannelMinuteAggs
+	}
+	reconnectDelay := rt.ReconnectDelay
+	if reconnectDelay <= 0 {
+		reconnectDelay = 5 * time.Second
+	}
+
+	// The socket only replaces polling when Polygon is also the REST provider
+	provider := strings.ToLower(cfg.MarketData.Provider)
+	enabled := rt.Enabled && (provider == "" || provider == ProviderPolygon)
+
+	return &PolygonStream{
+		enabled:        enabled,
+		url:            url,
+		apiKey:         cfg.Polygon.APIKey,
+		channel:        channel,
+		reconnectDelay: reconnectDelay,
+		flushInterval:  streamFlushInterval,
+		symbols:        symbols,
+		onBars:         onBars,
+		aggregator:     NewBarAggregator(),
+		subscribed:     make(map[string]bool),
+		stop:           make(chan struct{}),
+	}
+}
+
+// Start connects to the socket in the background, reconnecting until stopped
+func (ps *PolygonStream) Start() {
+	if !ps.enabled {
+		log.Printf("Polygon WebSocket ingestion disabled")
+		return
+	}
+
+	log.Printf("Starting Polygon WebSocket ingestion (%s channel)...", ps.channel)
+
+	ps.wg.Add(1)
+	go func() {
+		defer ps.wg.Done()
+		ps.run()
+	}()
+}
+
+// Stop closes the socket, flushes buffered bars and waits for the connection loop to exit
+func (ps *PolygonStream) Stop(ctx context.Context) error {
+	ps.stopOnce.Do(func() {
+		close(ps.stop)
+		ps.mutex.Lock()
+		if ps.conn != nil {
+			ps.conn.Close()
+		}
+		ps.mutex.Unlock()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		ps.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		if bars := ps.aggregator.Flush(time.Now().Add(time.Minute)); len(bars) > 0 {
+			ps.emit(bars)
+		}
+		log.Printf("Polygon WebSocket ingestion stopped")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for WebSocket ingestion to stop: %w", ctx.Err())
+	}
+}
+
+// IsStreaming reports whether the socket is connected and subscribed to the symbol
+func (ps *PolygonStream) IsStreaming(symbol string) bool {
+	if ps == nil {
+		return false
+	}
+
+	ps.mutex.RLock()
+	defer ps.mutex.RUnlock()
+	return ps.connected && ps.subscribed[symbol]
+}
+
+// Status returns the current ingestion state
+func (ps *PolygonStream) Status() *PolygonStreamStatus {
+	ps.mutex.RLock()
+	defer ps.mutex.RUnlock()
+
+	status := &PolygonStreamStatus{
+		Enabled:     ps.enabled,
+		Connected:   ps.connected,
+		Channel:     ps.channel,
+		Symbols:     make([]string, 0, len(ps.subscribed)),
+		BarsWritten: ps.barsWritten,
+		Reconnects:  ps.reconnects,
+		LastError:   ps.lastError,
+	}
+	for symbol := range ps.subscribed {
+		status.Symbols = append(status.Symbols, symbol)
+	}
+	sort.Strings(status.Symbols)
+	if !ps.lastMessageAt.IsZero() {
+		lastMessageAt := ps.lastMessageAt
+		status.LastMessageAt = &lastMessageAt
+	}
+
+	return status
+}
+
+// run keeps a session open, backing off between reconnects
+func (ps *PolygonStream) run() {
+	delay := ps.reconnectDelay
+	for {
+		authenticated, err := ps.session()
+
+		ps.mutex.Lock()
+		ps.connected = false
+		ps.subscribed = make(map[string]bool)
+		ps.conn = nil
+		if err != nil {
+			ps.lastError = err.Error()
+		}
+		ps.mutex.Unlock()
+
+		select {
+		case <-ps.stop:
+			return
+		default:
+		}
+
+		if authenticated {
+			delay = ps.reconnectDelay
+		}
+		log.Printf("Polygon WebSocket disconnected (%v), falling back to REST polling; reconnecting in %v", err, delay)
+
+		select {
+		case <-time.After(delay):
+		case <-ps.stop:
+			return
+		}
+
+		ps.mutex.Lock()
+		ps.reconnects++
+		ps.mutex.Unlock()
+
+		delay *= 2
+		if delay > maxStreamReconnectDelay {
+			delay = maxStreamReconnectDelay
+		}
+	}
+}
+
+// session runs one connection until it fails or the stream is stopped; it reports whether authentication succeeded
+func (ps *PolygonStream) session() (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, ps.url, nil)
+	cancel()
+	if err != nil {
+		return false, fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+
+	ps.mutex.Lock()
+	ps.conn = conn
+	ps.mutex.Unlock()
+
+	// Stop may have run before the connection was registered
+	select {
+	case <-ps.stop:
+		return false, nil
+	default:
+	}
+
+	if err := ps.authenticate(conn); err != nil {
+		return false, err
+	}
+
+	if err := ps.syncSubscriptions(conn); err != nil {
+		return true, err
+	}
+
+	ps.mutex.Lock()
+	ps.connected = true
+	ps.lastError = ""
+	ps.mutex.Unlock()
+	log.Printf("Polygon WebSocket connected to %s", ps.url)
+
+	readErr := make(chan error, 1)
+	go func() {
+		for {
+			var events []polygonStreamEvent
+			if err := conn.ReadJSON(&events); err != nil {
+				readErr <- err
+				return
+			}
+			ps.handleEvents(events)
+		}
+	}()
+
+	flushTicker := time.NewTicker(ps.flushInterval)
+	defer flushTicker.Stop()
+	resubscribeTicker := time.NewTicker(streamResubscribeEvery)
+	defer resubscribeTicker.Stop()
+
+	for {
+		select {
+		case err := <-readErr:
+			ps.flush()
+			return true, fmt.Errorf("read failed: %w", err)
+		case <-flushTicker.C:
+			ps.flush()
+		case <-resubscribeTicker.C:
+			if err := ps.syncSubscriptions(conn); err != nil {
+				return true, err
+			}
+		case <-ps.stop:
+			return true, nil
+		}
+	}
+}
+
+// authenticate waits for the connected status and sends the API key
+func (ps *PolygonStream) authenticate(conn *websocket.Conn) error {
+	conn.SetReadDeadline(time.Now().Add(30 * time.Second))
+	defer conn.SetReadDeadline(time.Time{})
+
+	if err := ps.expectStatus(conn, "connected"); err != nil {
+		return err
+	}
+
+	if err := conn.WriteJSON(map[string]string{"action": "auth", "params": ps.apiKey}); err != nil {
+		return fmt.Errorf("failed to send auth: %w", err)
+	}
+
+	return ps.expectStatus(conn, "auth_success")
+}
+
+// expectStatus reads messages until the given status arrives
+func (ps *PolygonStream) expectStatus(conn *websocket.Conn, status string) error {
+	for {
+		var events []polygonStreamEvent
+		if err := conn.ReadJSON(&events); err != nil {
+			return fmt.Errorf("failed waiting for %s: %w", status, err)
+		}
+		for _, event := range events {
+			if event.Event != "status" {
+				continue
+			}
+			if event.Status == status {
+				return nil
+			}
+			if event.Status == "auth_failed" || event.Status == "error" {
+				return fmt.Errorf("polygon socket %s: %s", event.Status, event.Message)
+			}
+		}
+	}
+}
+
+// syncSubscriptions subscribes to newly watched symbols and drops removed ones
+func (ps *PolygonStream) syncSubscriptions(conn *websocket.Conn) error {
+	symbols, err := ps.symbols()
+	if err != nil {
+		return fmt.Errorf("failed to get watched symbols: %w", err)
+	}
+
+	// The stocks socket only carries equities; crypto and FX keep being polled
+	watched := make(map[string]bool, len(symbols))
+	for _, symbol := range equitySymbols(symbols) {
+		watched[symbol] = true
+	}
+
+	ps.mutex.RLock()
+	var add, remove []string
+	for symbol := range watched {
+		if !ps.subscribed[symbol] {
+			add = append(add, ps.channel+"."+symbol)
+		}
+	}
+	for symbol := range ps.subscribed {
+		if !watched[symbol] {
+			remove = append(remove, ps.channel+"."+symbol)
+		}
+	}
+	ps.mutex.RUnlock()
+
+	if len(add) > 0 {
+		if err := conn.WriteJSON(map[string]string{"action": "subscribe", "params": strings.Join(add, ",")}); err != nil {
+			return fmt.Errorf("failed to subscribe: %w", err)
+		}
+	}
+	if len(remove) > 0 {
+		if err := conn.WriteJSON(map[string]string{"action": "unsubscribe", "params": strings.Join(remove, ",")}); err != nil {
+			return fmt.Errorf("failed to unsubscribe: %w", err)
+		}
+	}
+
+	ps.mutex.Lock()
+	ps.subscribed = watched
+	ps.mutex.Unlock()
+	return nil
+}
+
+// handleEvents feeds aggregate and trade events into the bar buffer
+func (ps *PolygonStream) handleEvents(events []polygonStreamEvent) {
+	ps.mutex.Lock()
+	ps.lastMessageAt = time.Now()
+	ps.mutex.Unlock()
+
+	for _, event := range events {
+		switch event.Event {
+		case PolygonChannelMinuteAggs, PolygonChannelSecondAggs:
+			start := time.UnixMilli(int64(event.S))
+			ps.aggregator.AddBar(event.Symbol, start, event.Open, event.High, event.Low, event.Close, int64(event.Volume))
+		case PolygonChannelTrades:
+			ps.aggregator.AddTrade(event.Symbol, time.UnixMilli(event.Timestamp), event.Price, int64(event.S))
+		case "status":
+			if event.Status != "success" {
+				log.Printf("Polygon WebSocket status %s: %s", event.Status, event.Message)
+			}
+		}
+	}
+}
+
+// flush emits bars whose minute has closed
+func (ps *PolygonStream) flush() {
+	if bars := ps.aggregator.Flush(time.Now().Add(-streamLateTradeGrace)); len(bars) > 0 {
+		ps.emit(bars)
+	}
+}
+
+// emit hands completed bars to the callback
+func (ps *PolygonStream) emit(bars []*models.PriceData) {
+	ps.onBars(bars)
+
+	ps.mutex.Lock()
+	ps.barsWritten += int64(len(bars))
+	ps.mutex.Unlock()
+}
+
+// BarAggregator buffers ticks and sub-minute aggregates into 1-minute OHLCV bars
+type BarAggregator struct {
+	bars  map[string]*models.PriceData // keyed by symbol and minute
+	mutex sync.Mutex
+}
+
+// NewBarAggregator creates an empty bar aggregator
+func NewBarAggregator() *BarAggregator {
+	return &BarAggregator{bars: make(map[string]*models.PriceData)}
+}
+
+// AddTrade adds a trade to its minute bar
+func (ba *BarAggregator) AddTrade(symbol string, at time.Time, price float64, size int64) {
+	ba.AddBar(symbol, at, price, price, price, price, size)
+}
+
+// AddBar merges an aggregate starting at start into its minute bar
+func (ba *BarAggregator) AddBar(symbol string, start time.Time, open, high, low, close float64, volume int64) {
+	if symbol == "" || close <= 0 {
+		return
+	}
+
+	minute := start.Truncate(time.Minute)
+	key := fmt.Sprintf("%s|%d", symbol, minute.Unix())
+
+	ba.mutex.Lock()
+	defer ba.mutex.Unlock()
+
+	bar, ok := ba.bars[key]
+	if !ok {
+		ba.bars[key] = &models.PriceData{
+			Symbol:    symbol,
+			Timestamp: minute,
+			Open:      open,
+			High:      high,
+			Low:       low,
+			Close:     close,
+			Volume:    volume,
+		}
+		return
+	}
+
+	if high > bar.High {
+		bar.High = high
+	}
+	if low < bar.Low {
+		bar.Low = low
+	}
+	bar.Close = close
+	bar.Volume += volume
+}
+
+// Flush removes and returns the bars whose minute ended at or before cutoff, oldest first
+func (ba *BarAggregator) Flush(cutoff time.Time) []*models.PriceData {
+	ba.mutex.Lock()
+	defer ba.mutex.Unlock()
+
+	var bars []*models.PriceData
+	for key, bar := range ba.bars {
+		if !bar.Timestamp.Add(time.Minute).After(cutoff) {
+			bar.CreatedAt = time.Now()
+			bars = append(bars, bar)
+			delete(ba.bars, key)
+		}
+	}
+
+	sort.Slice(bars, func(i, j int) bool {
+		if bars[i].Timestamp.Equal(bars[j].Timestamp) {
+			return bars[i].Symbol < bars[j].Symbol
+		}
+		return bars[i].Timestamp.Before(bars[j].Timestamp)
+	})
+	return bars
+}
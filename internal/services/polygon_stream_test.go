@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+
+	"github.com/gorilla/websocket"
+)
+
+// TestBarAggregator tests that trades are bucketed into minute bars and only closed minutes are flushed
+func TestBarAggregator(t *testing.T) {
+	ba := NewBarAggregator()
+	minute := time.Date(2024, 3, 1, 14, 30, 0, 0, time.UTC)
+
+	ba.AddTrade("AAPL", minute.Add(5*time.Second), 180.00, 100)
+	ba.AddTrade("AAPL", minute.Add(20*time.Second), 181.50, 50)
+	ba.AddTrade("AAPL", minute.Add(40*time.Second), 179.25, 200)
+	ba.AddTrade("AAPL", minute.Add(59*time.Second), 180.75, 25)
+	ba.AddTrade("AAPL", minute.Add(65*time.Second), 181.00, 10)
+
+	if bars := ba.Flush(minute.Add(30 * time.Second)); len(bars) != 0 {
+		t.Fatalf("flushed %d bars before the minute closed", len(bars))
+	}
+
+	bars := ba.Flush(minute.Add(time.Minute))
+	if len(bars) != 1 {
+		t.Fatalf("expected 1 closed bar, got %d", len(bars))
+	}
+	bar := bars[0]
+	if !bar.Timestamp.Equal(minute) || bar.Open != 180.00 || bar.High != 181.50 || bar.Low != 179.25 || bar.Close != 180.75 || bar.Volume != 375 {
+		t.Errorf("unexpected bar: %+v", bar)
+	}
+
+	if bars := ba.Flush(minute.Add(2 * time.Minute)); len(bars) != 1 || bars[0].Volume != 10 {
+		t.Errorf("expected the next minute's bar, got %+v", bars)
+	}
+}
+
+// TestPolygonStreamSession tests authentication, subscription and bar delivery against a fake socket
+func TestPolygonStreamSession(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	subscribed := make(chan string, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.WriteJSON([]map[string]string{{"ev": "status", "status": "connected"}})
+
+		var auth map[string]string
+		if err := conn.ReadJSON(&auth); err != nil || auth["params"] != "test-key" {
+			conn.WriteJSON([]map[string]string{{"ev": "status", "status": "auth_failed", "message": "bad key"}})
+			return
+		}
+		conn.WriteJSON([]map[string]string{{"ev": "status", "status": "auth_success"}})
+
+		var sub map[string]string
+		if err := conn.ReadJSON(&sub); err != nil {
+			return
+		}
+		subscribed <- sub["params"]
+
+		start := time.Now().Add(-2 * time.Minute).Truncate(time.Minute)
+		conn.WriteJSON([]map[string]interface{}{
+			{"ev": "AM", "sym": "AAPL", "o": 180.0, "h": 181.0, "l": 179.5, "c": 180.5, "v": 12000, "s": start.UnixMilli()},
+		})
+
+		// Keep the connection open until the client goes away
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.Polygon.APIKey = "test-key"
+	cfg.Collection.Realtime = config.RealtimeCollectionConfig{
+		Enabled: true,
+		URL:     "ws" + strings.TrimPrefix(server.URL, "http"),
+	}
+
+	var mutex sync.Mutex
+	var received []*models.PriceData
+	stream := NewPolygonStream(cfg, func() ([]string, error) {
+		return []string{"AAPL"}, nil
+	}, func(bars []*models.PriceData) {
+		mutex.Lock()
+		received = append(received, bars...)
+		mutex.Unlock()
+	})
+	stream.flushInterval = 10 * time.Millisecond
+	stream.Start()
+
+	select {
+	case params := <-subscribed:
+		if params != "AM.AAPL" {
+			t.Errorf("subscribe params = %q, want AM.AAPL", params)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream never subscribed")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		mutex.Lock()
+		n := len(received)
+		mutex.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if !stream.IsStreaming("AAPL") || stream.IsStreaming("MSFT") {
+		t.Errorf("unexpected streaming state: %+v", stream.Status())
+	}
+
+	if err := stream.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(received) != 1 || received[0].Symbol != "AAPL" || received[0].Volume != 12000 || received[0].Close != 180.5 {
+		t.Errorf("unexpected bars: %+v", received)
+	}
+	if stream.IsStreaming("AAPL") {
+		t.Errorf("stream still reports AAPL after stop")
+	}
+}
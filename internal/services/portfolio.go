@@ -0,0 +1,299 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// setupTypeManual attributes positions that were not taken on a detected setup
+const setupTypeManual = "manual"
+
+// PortfolioService tracks positions taken on setups and values them against live prices
+type PortfolioService struct {
+	db *database.Database
+}
+
+// NewPortfolioService creates a new portfolio service
+func NewPortfolioService(db *database.Database) *PortfolioService {
+	return &PortfolioService{db: db}
+}
+
+// OpenPosition records a new position. When the position references a setup, the
+// setup's symbol, direction and levels are used as defaults and the setup is marked triggered.
+func (ps *PortfolioService) OpenPosition(position *models.Position) error {
+	position.Status = models.PositionStatusOpen
+	position.ExitPrice = nil
+	position.ExitTime = nil
+	if position.EntryTime.IsZero() {
+		position.EntryTime = time.Now()
+	}
+
+	var setup *models.TradingSetup
+	if position.SetupID != nil {
+		found, err := ps.db.GetTradingSetupByID(*position.SetupID)
+		if err != nil {
+			return fmt.Errorf("failed to get setup: %w", err)
+		}
+		if found == nil {
+			return fmt.Errorf("setup %d %w", *position.SetupID, database.ErrNotFound)
+		}
+		setup = found
+
+		if position.Symbol == "" {
+			position.Symbol = setup.Symbol
+		}
+		if position.Side == "" {
+			position.Side = models.SideForDirection(setup.Direction)
+		}
+		if position.EntryPrice == 0 {
+			position.EntryPrice = setup.EntryPrice
+		}
+		if position.StopLoss == 0 {
+			position.StopLoss = setup.StopLoss
+		}
+		if position.TargetPrice == 0 {
+			position.TargetPrice = setup.Target1
+		}
+		position.SetupType = setup.SetupType
+	}
+
+	if position.SetupType == "" {
+		position.SetupType = setupTypeManual
+	}
+
+	if err := position.Validate(); err != nil {
+		return err
+	}
+
+	if setup != nil && setup.Symbol != position.Symbol {
+		return fmt.Errorf("setup %d is for %s, not %s", setup.ID, setup.Symbol, position.Symbol)
+	}
+
+	// Opening the position and triggering its setup succeed or fail together
+	err := ps.db.WithTx(func(tx *database.DB) error {
+		if err := tx.InsertPosition(position); err != nil {
+			return err
+		}
+
+		if setup != nil && setup.Status == "active" {
+			setup.UpdateStatus("triggered")
+			if err := tx.UpdateTradingSetup(setup); err != nil {
+				return fmt.Errorf("failed to mark setup as triggered: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	ps.applyPrices([]*models.Position{position})
+	return nil
+}
+
+// ClosePosition closes quantity of an open position at the given exit price, or all of it when quantity is 0.
+// A partial close splits the closed quantity off into its own closed position at the same entry, returning it,
+// and leaves the rest open.
+func (ps *PortfolioService) ClosePosition(id int64, quantity, exitPrice float64, exitTime time.Time) (*models.Position, error) {
+	position, err := ps.db.GetPositionByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if position == nil {
+		return nil, fmt.Errorf("position %d %w", id, database.ErrNotFound)
+	}
+	if !position.IsOpen() {
+		return nil, fmt.Errorf("position %d is already closed", id)
+	}
+	if quantity < 0 || quantity > position.Quantity {
+		return nil, fmt.Errorf("quantity must be between 0 and the open %g", position.Quantity)
+	}
+
+	if exitPrice <= 0 {
+		latest, err := ps.db.GetLatestPriceData(position.Symbol)
+		if err != nil || latest == nil {
+			return nil, fmt.Errorf("exit_price is required: no current price available for %s", position.Symbol)
+		}
+		exitPrice = latest.Close
+	}
+	if exitTime.IsZero() {
+		exitTime = time.Now()
+	}
+
+	if quantity > 0 && quantity < position.Quantity {
+		closed := *position
+		closed.Quantity = quantity
+		closed.ExitPrice = &exitPrice
+		closed.ExitTime = &exitTime
+		closed.Status = models.PositionStatusClosed
+		position.Quantity -= quantity
+
+		err := ps.db.WithTx(func(tx *database.DB) error {
+			if err := tx.InsertPosition(&closed); err != nil {
+				return err
+			}
+			return tx.UpdatePosition(position)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		closed.ApplyPrice(exitPrice)
+		return &closed, nil
+	}
+
+	position.ExitPrice = &exitPrice
+	position.ExitTime = &exitTime
+	position.Status = models.PositionStatusClosed
+
+	if err := ps.db.UpdatePosition(position); err != nil {
+		return nil, err
+	}
+
+	position.ApplyPrice(exitPrice)
+	return position, nil
+}
+
+// GetPositions returns positions matching the filter, valued at the latest prices
+func (ps *PortfolioService) GetPositions(filter *models.PositionFilter) ([]*models.Position, error) {
+	positions, err := ps.db.GetPositions(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ps.applyPrices(positions)
+	return positions, nil
+}
+
+// GetPosition returns a single position valued at the latest price
+func (ps *PortfolioService) GetPosition(id int64) (*models.Position, error) {
+	position, err := ps.db.GetPositionByID(id)
+	if err != nil || position == nil {
+		return position, err
+	}
+
+	ps.applyPrices([]*models.Position{position})
+	return position, nil
+}
+
+// GetSummary returns open exposure and realized/unrealized P&L across all positions
+func (ps *PortfolioService) GetSummary() (*models.PortfolioSummary, error) {
+	positions, err := ps.GetPositions(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.PortfolioSummary{UpdatedAt: time.Now()}
+	wins := 0
+
+	for _, position := range positions {
+		if position.IsOpen() {
+			summary.OpenPositions++
+			summary.CostBasis += position.CostBasis()
+			summary.UnrealizedPnL += position.UnrealizedPnL
+			if position.MarketValue > 0 {
+				summary.MarketValue += position.MarketValue
+			} else {
+				// No live price yet, carry the position at cost
+				summary.MarketValue += position.CostBasis()
+			}
+			continue
+		}
+
+		summary.ClosedPositions++
+		summary.RealizedPnL += position.RealizedPnL
+		if position.RealizedPnL > 0 {
+			wins++
+		}
+	}
+
+	if summary.CostBasis > 0 {
+		summary.UnrealizedPnLPercent = summary.UnrealizedPnL / summary.CostBasis * 100
+	}
+	if summary.ClosedPositions > 0 {
+		summary.WinRate = float64(wins) / float64(summary.ClosedPositions) * 100
+	}
+	summary.TotalPnL = summary.RealizedPnL + summary.UnrealizedPnL
+
+	return summary, nil
+}
+
+// GetPerformanceBySetupType attributes trade results to the setup or pattern type that generated them
+func (ps *PortfolioService) GetPerformanceBySetupType() ([]*models.SetupPerformance, error) {
+	positions, err := ps.GetPositions(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	bySetup := make(map[string]*models.SetupPerformance)
+	for _, position := range positions {
+		perf, ok := bySetup[position.SetupType]
+		if !ok {
+			perf = &models.SetupPerformance{SetupType: position.SetupType}
+			bySetup[position.SetupType] = perf
+		}
+
+		if position.IsOpen() {
+			perf.OpenPositions++
+			perf.UnrealizedPnL += position.UnrealizedPnL
+			continue
+		}
+
+		returnPercent := position.RealizedPnLPercent
+		if perf.Trades == 0 || returnPercent > perf.BestReturn {
+			perf.BestReturn = returnPercent
+		}
+		if perf.Trades == 0 || returnPercent < perf.WorstReturn {
+			perf.WorstReturn = returnPercent
+		}
+
+		perf.Trades++
+		perf.RealizedPnL += position.RealizedPnL
+		perf.AvgReturnPercent += returnPercent
+		if position.RealizedPnL > 0 {
+			perf.Wins++
+		} else {
+			perf.Losses++
+		}
+	}
+
+	result := make([]*models.SetupPerformance, 0, len(bySetup))
+	for _, perf := range bySetup {
+		if perf.Trades > 0 {
+			perf.AvgReturnPercent /= float64(perf.Trades)
+			perf.WinRate = float64(perf.Wins) / float64(perf.Trades) * 100
+		}
+		result = append(result, perf)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].RealizedPnL > result[j].RealizedPnL
+	})
+
+	return result, nil
+}
+
+// applyPrices values open positions at the latest price of each symbol
+func (ps *PortfolioService) applyPrices(positions []*models.Position) {
+	prices := make(map[string]float64)
+
+	for _, position := range positions {
+		if position.IsOpen() {
+			price, ok := prices[position.Symbol]
+			if !ok {
+				if latest, err := ps.db.GetLatestPriceData(position.Symbol); err == nil && latest != nil {
+					price = latest.Close
+				}
+				prices[position.Symbol] = price
+			}
+			position.ApplyPrice(price)
+			continue
+		}
+
+		position.ApplyPrice(0)
+	}
+}
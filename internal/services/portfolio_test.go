@@ -0,0 +1,161 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestPortfolioPnL tests cost basis, realized and unrealized P&L and attribution through buys and partial sells
+func TestPortfolioPnL(t *testing.T) {
+	type buy struct {
+		side            string
+		quantity, price float64
+	}
+	type sell struct {
+		buy             int
+		quantity, price float64
+	}
+
+	tests := []struct {
+		name          string
+		buys          []buy
+		sells         []sell
+		lastPrice     float64
+		openQuantity  float64
+		costBasis     float64
+		unrealized    float64
+		unrealizedPct float64
+		realized      float64
+		trades        int
+		winRate       float64
+	}{
+		{
+			name:         "partial sell",
+			buys:         []buy{{"long", 10, 100}},
+			sells:        []sell{{0, 4, 110}},
+			lastPrice:    105,
+			openQuantity: 6, costBasis: 600, unrealized: 30, unrealizedPct: 5,
+			realized: 40, trades: 1, winRate: 100,
+		},
+		{
+			name:         "cost averaged across buys",
+			buys:         []buy{{"long", 10, 100}, {"long", 10, 110}},
+			lastPrice:    120,
+			openQuantity: 20, costBasis: 2100, unrealized: 300, unrealizedPct: 300.0 / 2100 * 100,
+		},
+		{
+			name:         "averaged buys partly sold",
+			buys:         []buy{{"long", 10, 100}, {"long", 30, 120}},
+			sells:        []sell{{1, 10, 125}},
+			lastPrice:    110,
+			openQuantity: 30, costBasis: 3400, unrealized: -100, unrealizedPct: -100.0 / 3400 * 100,
+			realized: 50, trades: 1, winRate: 100,
+		},
+		{
+			name:      "sold down to zero",
+			buys:      []buy{{"long", 10, 100}},
+			sells:     []sell{{0, 4, 110}, {0, 6, 95}},
+			lastPrice: 120,
+			realized:  10, trades: 2, winRate: 50,
+		},
+		{
+			name:         "short partly covered",
+			buys:         []buy{{"short", 10, 50}},
+			sells:        []sell{{0, 5, 45}},
+			lastPrice:    40,
+			openQuantity: 5, costBasis: 250, unrealized: 50, unrealizedPct: 20,
+			realized: 25, trades: 1, winRate: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+
+			start := time.Date(2025, time.March, 4, 15, 0, 0, 0, time.UTC)
+			service := NewPortfolioService(db)
+			ids := make([]int64, len(tt.buys))
+			for i, b := range tt.buys {
+				position := &models.Position{Symbol: "TEST", Side: b.side, Quantity: b.quantity, EntryPrice: b.price, EntryTime: start}
+				if err := service.OpenPosition(position); err != nil {
+					t.Fatalf("OpenPosition failed: %v", err)
+				}
+				ids[i] = position.ID
+			}
+			for _, s := range tt.sells {
+				closed, err := service.ClosePosition(ids[s.buy], s.quantity, s.price, start.Add(time.Hour))
+				if err != nil {
+					t.Fatalf("ClosePosition failed: %v", err)
+				}
+				if closed.IsOpen() || closed.Quantity != s.quantity {
+					t.Errorf("expected %g closed, got %+v", s.quantity, closed)
+				}
+			}
+
+			if err := db.InsertPriceData(&models.PriceData{Symbol: "TEST", Timestamp: start.Add(2 * time.Hour),
+				Open: tt.lastPrice, High: tt.lastPrice, Low: tt.lastPrice, Close: tt.lastPrice, Volume: 1000}); err != nil {
+				t.Fatalf("InsertPriceData failed: %v", err)
+			}
+
+			open, err := service.GetPositions(&models.PositionFilter{Status: models.PositionStatusOpen})
+			if err != nil {
+				t.Fatalf("GetPositions failed: %v", err)
+			}
+			openQuantity := 0.0
+			for _, position := range open {
+				openQuantity += position.Quantity
+			}
+			if openQuantity != tt.openQuantity {
+				t.Errorf("expected %g open, got %g", tt.openQuantity, openQuantity)
+			}
+
+			summary, err := service.GetSummary()
+			if err != nil {
+				t.Fatalf("GetSummary failed: %v", err)
+			}
+			near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+			if !near(summary.CostBasis, tt.costBasis) || !near(summary.UnrealizedPnL, tt.unrealized) ||
+				!near(summary.UnrealizedPnLPercent, tt.unrealizedPct) {
+				t.Errorf("expected cost basis %g and unrealized %g (%.4f%%), got %g and %g (%.4f%%)", tt.costBasis, tt.unrealized,
+					tt.unrealizedPct, summary.CostBasis, summary.UnrealizedPnL, summary.UnrealizedPnLPercent)
+			}
+			if !near(summary.RealizedPnL, tt.realized) || !near(summary.TotalPnL, tt.realized+tt.unrealized) ||
+				summary.ClosedPositions != tt.trades || !near(summary.WinRate, tt.winRate) {
+				t.Errorf("expected realized %g over %d trades (win rate %g), got %g over %d (%g)", tt.realized, tt.trades,
+					tt.winRate, summary.RealizedPnL, summary.ClosedPositions, summary.WinRate)
+			}
+
+			performance, err := service.GetPerformanceBySetupType()
+			if err != nil {
+				t.Fatalf("GetPerformanceBySetupType failed: %v", err)
+			}
+			if len(performance) != 1 || performance[0].SetupType != setupTypeManual || performance[0].Trades != tt.trades ||
+				!near(performance[0].RealizedPnL, tt.realized) || !near(performance[0].UnrealizedPnL, tt.unrealized) {
+				t.Errorf("expected manual trades attributed, got %+v", performance[0])
+			}
+		})
+	}
+}
+
+// TestClosePositionQuantity tests a close can't sell more than is open
+func TestClosePositionQuantity(t *testing.T) {
+	db := newTestDB(t)
+
+	service := NewPortfolioService(db)
+	position := &models.Position{Symbol: "TEST", Quantity: 10, EntryPrice: 100}
+	if err := service.OpenPosition(position); err != nil {
+		t.Fatalf("OpenPosition failed: %v", err)
+	}
+	if _, err := service.ClosePosition(position.ID, 11, 105, time.Time{}); err == nil {
+		t.Error("expected selling more than the open quantity to fail")
+	}
+
+	// Selling exactly what's open closes the position itself
+	closed, err := service.ClosePosition(position.ID, 10, 105, time.Time{})
+	if err != nil || closed.ID != position.ID || closed.IsOpen() || closed.RealizedPnL != 50 {
+		t.Errorf("expected the position closed with 50 realized, got %+v (%v)", closed, err)
+	}
+}
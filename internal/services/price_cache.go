@@ -0,0 +1,323 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// Defaults for the in-memory price series cache
+const (
+	DefaultPriceCacheDays    = 60
+	DefaultPriceCacheMaxBars = 40000
+)
+
+// PriceReader reads stored bars; both the database and PriceCache implement it
+type PriceReader interface {
+	GetPriceData(filter *models.PriceDataFilter) ([]*models.PriceData, error)
+	GetPriceDataRangeTimeframe(symbol string, startTime, endTime time.Time, timeframe models.Timeframe) ([]*models.PriceData, error)
+}
+
+// bindPriceReader ties the database queries of a price reader to a context, so a scan stops reading bars once
+// its request is cancelled. Readers that can't be bound are returned unchanged.
+func bindPriceReader(ctx context.Context, prices PriceReader) PriceReader {
+	switch reader := prices.(type) {
+	case *database.DB:
+		return reader.WithContext(ctx)
+	case *PriceCache:
+		return reader.WithContext(ctx)
+	case *regularSessionReader:
+		return &regularSessionReader{prices: bindPriceReader(ctx, reader.prices), calendar: reader.calendar}
+	}
+	return prices
+}
+
+// PriceCache keeps each symbol's recent 1-minute bars in a ring buffer, so indicator and pattern scans don't
+// re-read weeks of bars from the database on every call. A symbol is loaded from the database on first read;
+// the collector then appends ingested bars and invalidates the symbol after a backfill. Reads reaching
+// further back than a symbol's buffer fall back to the database.
+type PriceCache struct {
+	db      *database.Database
+	window  time.Duration
+	maxBars int
+	mutex   sync.Mutex
+	series  map[string]*priceSeries
+}
+
+// priceSeries is a ring buffer of a symbol's 1-minute bars, oldest first. Every stored bar at or after from
+// is held, so reads starting at or after from can be answered without the database.
+type priceSeries struct {
+	mutex  sync.RWMutex
+	loaded bool
+	bars   []*models.PriceData
+	head   int
+	count  int
+	from   time.Time
+}
+
+// NewPriceCache creates a price cache over the database. The window is capped at compaction's cutoff, since
+// older 1-minute bars only survive as rolled-up candles.
+func NewPriceCache(cfg *config.Config, db *database.Database) *PriceCache {
+	days := cfg.PriceCache.Days
+	if days == 0 {
+		days = DefaultPriceCacheDays
+	}
+	if compact := cfg.DataRetention.CompactAfterDays; compact > 0 && compact < days {
+		days = compact
+	}
+
+	maxBars := cfg.PriceCache.MaxBars
+	if maxBars <= 0 {
+		maxBars = DefaultPriceCacheMaxBars
+	}
+
+	return &PriceCache{
+		db:      db,
+		window:  time.Duration(days) * 24 * time.Hour,
+		maxBars: maxBars,
+		series:  make(map[string]*priceSeries),
+	}
+}
+
+// GetPriceData returns what the database would for the filter, from memory when the range is cached
+func (pc *PriceCache) GetPriceData(filter *models.PriceDataFilter) ([]*models.PriceData, error) {
+	return pc.getPriceData(pc.db, filter)
+}
+
+// GetPriceDataRangeTimeframe returns a symbol's bars within a time range, rolled up to a timeframe
+func (pc *PriceCache) GetPriceDataRangeTimeframe(symbol string, startTime, endTime time.Time, timeframe models.Timeframe) ([]*models.PriceData, error) {
+	return pc.GetPriceData(rangeFilter(symbol, startTime, endTime, timeframe))
+}
+
+// WithContext returns a reader of the cache whose loads and database fallbacks run under ctx
+func (pc *PriceCache) WithContext(ctx context.Context) PriceReader {
+	return &boundPriceCache{cache: pc, db: pc.db.WithContext(ctx)}
+}
+
+// getPriceData answers the filter from memory when the range is cached, else from db
+func (pc *PriceCache) getPriceData(db *database.Database, filter *models.PriceDataFilter) ([]*models.PriceData, error) {
+	bars, ok, err := pc.read(db, filter.Symbol, filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return db.GetPriceData(filter)
+	}
+	return database.PaginatePriceData(database.AggregatePriceData(bars, filter.Timeframe), filter.Limit, filter.Offset), nil
+}
+
+// rangeFilter returns the filter for a symbol's bars within a time range, rolled up to a timeframe
+func rangeFilter(symbol string, startTime, endTime time.Time, timeframe models.Timeframe) *models.PriceDataFilter {
+	return &models.PriceDataFilter{
+		Symbol:    symbol,
+		From:      startTime,
+		To:        endTime,
+		Timeframe: timeframe,
+	}
+}
+
+// boundPriceCache reads through a price cache, with the queries it makes bound to a context
+type boundPriceCache struct {
+	cache *PriceCache
+	db    *database.Database
+}
+
+// GetPriceData returns the filter's bars, from the cache when the range is in memory
+func (b *boundPriceCache) GetPriceData(filter *models.PriceDataFilter) ([]*models.PriceData, error) {
+	return b.cache.getPriceData(b.db, filter)
+}
+
+// GetPriceDataRangeTimeframe returns a symbol's bars within a time range, rolled up to a timeframe
+func (b *boundPriceCache) GetPriceDataRangeTimeframe(symbol string, startTime, endTime time.Time, timeframe models.Timeframe) ([]*models.PriceData, error) {
+	return b.GetPriceData(rangeFilter(symbol, startTime, endTime, timeframe))
+}
+
+// Append adds newly ingested bars to the buffers of symbols already loaded. A bar replacing a cached one
+// updates it in place; one arriving out of order invalidates its symbol, to be reloaded on the next read.
+func (pc *PriceCache) Append(bars []*models.PriceData) {
+	cutoff := clockNow().Add(-pc.window)
+
+	bySymbol := make(map[string][]*models.PriceData)
+	for _, bar := range bars {
+		bySymbol[bar.Symbol] = append(bySymbol[bar.Symbol], bar)
+	}
+
+	for symbol, symbolBars := range bySymbol {
+		series := pc.lookup(symbol, false)
+		if series == nil {
+			continue
+		}
+
+		sort.SliceStable(symbolBars, func(i, j int) bool { return symbolBars[i].Timestamp.Before(symbolBars[j].Timestamp) })
+
+		series.mutex.Lock()
+		if series.loaded {
+			for _, bar := range symbolBars {
+				if !series.add(copyBar(bar)) {
+					series.reset()
+					break
+				}
+			}
+			series.dropBefore(cutoff)
+		}
+		series.mutex.Unlock()
+	}
+}
+
+// Invalidate drops a symbol's buffer, e.g. after a backfill wrote bars into its cached range
+func (pc *PriceCache) Invalidate(symbol string) {
+	if series := pc.lookup(symbol, false); series != nil {
+		series.mutex.Lock()
+		series.reset()
+		series.mutex.Unlock()
+	}
+}
+
+// InvalidateAll drops every symbol's buffer, e.g. after old bars were deleted or compacted
+func (pc *PriceCache) InvalidateAll() {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	pc.series = make(map[string]*priceSeries)
+}
+
+// lookup returns a symbol's series, creating an unloaded one when create is set
+func (pc *PriceCache) lookup(symbol string, create bool) *priceSeries {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	series, ok := pc.series[symbol]
+	if !ok && create {
+		series = &priceSeries{}
+		pc.series[symbol] = series
+	}
+	return series
+}
+
+// read copies the cached bars within [from, to], loading the symbol first if needed. ok is false when the
+// range starts before the symbol's buffer.
+func (pc *PriceCache) read(db *database.Database, symbol string, from, to time.Time) ([]*models.PriceData, bool, error) {
+	series := pc.lookup(symbol, true)
+
+	series.mutex.RLock()
+	loaded := series.loaded
+	series.mutex.RUnlock()
+	if !loaded {
+		if err := pc.load(db, symbol, series); err != nil {
+			return nil, false, err
+		}
+	}
+
+	series.mutex.RLock()
+	defer series.mutex.RUnlock()
+	if !series.loaded || from.Before(series.from) {
+		return nil, false, nil
+	}
+
+	var bars []*models.PriceData
+	for i := series.search(from); i < series.count; i++ {
+		bar := series.at(i)
+		if bar.Timestamp.After(to) {
+			break
+		}
+		bars = append(bars, copyBar(bar))
+	}
+	return bars, true, nil
+}
+
+// load fills a symbol's buffer with its bars within the cache window, read from db
+func (pc *PriceCache) load(db *database.Database, symbol string, series *priceSeries) error {
+	series.mutex.Lock()
+	defer series.mutex.Unlock()
+	if series.loaded {
+		return nil
+	}
+
+	now := clockNow()
+	from := now.Add(-pc.window)
+	bars, err := db.GetPriceDataRange(symbol, from, now)
+	if err != nil {
+		return fmt.Errorf("failed to load price cache for %s: %w", symbol, err)
+	}
+
+	series.bars = make([]*models.PriceData, pc.maxBars)
+	series.head, series.count = 0, 0
+	series.from = from
+	series.loaded = true
+	for _, bar := range bars {
+		series.add(bar)
+	}
+
+	log.Printf("Loaded %d %s bars into the price cache", series.count, symbol)
+	return nil
+}
+
+// at returns the i-th oldest cached bar
+func (s *priceSeries) at(i int) *models.PriceData {
+	return s.bars[(s.head+i)%len(s.bars)]
+}
+
+// search returns the index of the first cached bar at or after t
+func (s *priceSeries) search(t time.Time) int {
+	return sort.Search(s.count, func(i int) bool { return !s.at(i).Timestamp.Before(t) })
+}
+
+// add appends a bar, replacing a cached bar with the same timestamp and overwriting the oldest bar once the
+// buffer is full. It reports false for a bar older than the newest one that isn't already cached.
+func (s *priceSeries) add(bar *models.PriceData) bool {
+	if bar.Timestamp.Before(s.from) {
+		return true
+	}
+
+	if s.count > 0 {
+		if last := s.at(s.count - 1); !bar.Timestamp.After(last.Timestamp) {
+			i := s.search(bar.Timestamp)
+			if i < s.count && s.at(i).Timestamp.Equal(bar.Timestamp) {
+				s.bars[(s.head+i)%len(s.bars)] = bar
+				return true
+			}
+			return false
+		}
+	}
+
+	if s.count == len(s.bars) {
+		s.bars[s.head] = bar
+		s.head = (s.head + 1) % len(s.bars)
+		s.from = s.at(0).Timestamp
+		return true
+	}
+	s.bars[(s.head+s.count)%len(s.bars)] = bar
+	s.count++
+	return true
+}
+
+// dropBefore removes the bars older than cutoff
+func (s *priceSeries) dropBefore(cutoff time.Time) {
+	for s.count > 0 && s.at(0).Timestamp.Before(cutoff) {
+		s.bars[s.head] = nil
+		s.head = (s.head + 1) % len(s.bars)
+		s.count--
+	}
+	if s.from.Before(cutoff) {
+		s.from = cutoff
+	}
+}
+
+// reset empties the buffer so it is reloaded on the next read
+func (s *priceSeries) reset() {
+	s.loaded = false
+	s.bars = nil
+	s.head, s.count = 0, 0
+}
+
+// copyBar copies a bar so callers can't modify the cached one
+func copyBar(bar *models.PriceData) *models.PriceData {
+	copied := *bar
+	return &copied
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// newTestPriceCache creates a price cache over a temporary database holding count 1-minute AAPL bars ending
+// a minute ago, closing at 100, 101, ...
+func newTestPriceCache(t *testing.T, count int, cacheCfg config.PriceCacheConfig) (*PriceCache, *database.DB, []*models.PriceData) {
+	t.Helper()
+
+	cfg := &config.Config{}
+	cfg.PriceCache = cacheCfg
+	db := newTestDB(t)
+
+	start := time.Now().UTC().Truncate(time.Minute).Add(-time.Duration(count) * time.Minute)
+	bars := make([]*models.PriceData, count)
+	for i := range bars {
+		bars[i] = testPriceBar(start.Add(time.Duration(i)*time.Minute), 100+float64(i))
+	}
+	if err := db.InsertPriceDataBatch(bars); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+
+	return NewPriceCache(cfg, db), db, bars
+}
+
+func testPriceBar(timestamp time.Time, close float64) *models.PriceData {
+	return &models.PriceData{Symbol: "AAPL", Timestamp: timestamp, Open: close, High: close + 1, Low: close - 1, Close: close, Volume: 100}
+}
+
+// closes returns the closes of bars
+func closes(bars []*models.PriceData) []float64 {
+	values := make([]float64, len(bars))
+	for i, bar := range bars {
+		values[i] = bar.Close
+	}
+	return values
+}
+
+// TestPriceCacheMatchesDatabase tests that cached reads, rolled up and paginated, match the database's
+func TestPriceCacheMatchesDatabase(t *testing.T) {
+	cache, db, bars := newTestPriceCache(t, 30, config.PriceCacheConfig{})
+
+	filters := []*models.PriceDataFilter{
+		{Symbol: "AAPL", From: bars[0].Timestamp, To: bars[29].Timestamp},
+		{Symbol: "AAPL", From: bars[3].Timestamp, To: bars[20].Timestamp, Limit: 5, Offset: 2},
+		{Symbol: "AAPL", From: bars[0].Timestamp, To: time.Now(), Timeframe: models.Timeframe5m},
+		{Symbol: "MSFT", From: bars[0].Timestamp, To: time.Now()},
+	}
+	for _, filter := range filters {
+		want, err := db.GetPriceData(filter)
+		if err != nil {
+			t.Fatalf("GetPriceData failed: %v", err)
+		}
+		got, err := cache.GetPriceData(filter)
+		if err != nil {
+			t.Fatalf("cached GetPriceData failed: %v", err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("%+v: expected %v, got %v", filter, closes(want), closes(got))
+		}
+		for i := range want {
+			if !got[i].Timestamp.Equal(want[i].Timestamp) || got[i].Close != want[i].Close || got[i].Volume != want[i].Volume {
+				t.Errorf("%+v: bar %d: expected %+v, got %+v", filter, i, want[i], got[i])
+			}
+		}
+	}
+}
+
+// TestPriceCacheAppend tests that appended bars are served without re-reading the database, replacing
+// cached bars with the same timestamp
+func TestPriceCacheAppend(t *testing.T) {
+	cache, db, bars := newTestPriceCache(t, 10, config.PriceCacheConfig{})
+	from := bars[0].Timestamp
+
+	if _, err := cache.GetPriceDataRangeTimeframe("AAPL", from, time.Now(), models.Timeframe1m); err != nil {
+		t.Fatalf("failed to load the cache: %v", err)
+	}
+
+	// A bar written only to the database isn't seen until it is appended
+	next := testPriceBar(bars[9].Timestamp.Add(time.Minute), 200)
+	if err := db.InsertPriceDataBatch([]*models.PriceData{next}); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+	got, _ := cache.GetPriceDataRangeTimeframe("AAPL", from, time.Now().Add(time.Hour), models.Timeframe1m)
+	if len(got) != 10 {
+		t.Fatalf("expected the cached 10 bars, got %d", len(got))
+	}
+
+	cache.Append([]*models.PriceData{next, testPriceBar(bars[9].Timestamp, 150)})
+	got, _ = cache.GetPriceDataRangeTimeframe("AAPL", from, time.Now().Add(time.Hour), models.Timeframe1m)
+	if len(got) != 11 || got[9].Close != 150 || got[10].Close != 200 {
+		t.Errorf("expected the updated last bar and the new one, got %v", closes(got))
+	}
+
+	// Returned bars are copies
+	got[0].Close = -1
+	again, _ := cache.GetPriceDataRangeTimeframe("AAPL", from, time.Now(), models.Timeframe1m)
+	if again[0].Close != 100 {
+		t.Errorf("expected the cached bar to be unchanged, got %v", again[0].Close)
+	}
+}
+
+// TestPriceCacheInvalidate tests that backfilled and out-of-order bars are read after the symbol reloads
+func TestPriceCacheInvalidate(t *testing.T) {
+	cache, db, bars := newTestPriceCache(t, 10, config.PriceCacheConfig{})
+	from := bars[0].Timestamp.Add(-5 * time.Minute)
+
+	if got, _ := cache.GetPriceDataRangeTimeframe("AAPL", from, time.Now(), models.Timeframe1m); len(got) != 10 {
+		t.Fatalf("expected 10 bars, got %d", len(got))
+	}
+
+	backfill := testPriceBar(bars[0].Timestamp.Add(-2*time.Minute), 90)
+	if err := db.InsertPriceDataBatch([]*models.PriceData{backfill}); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+	cache.Invalidate("AAPL")
+	if got, _ := cache.GetPriceDataRangeTimeframe("AAPL", from, time.Now(), models.Timeframe1m); len(got) != 11 || got[0].Close != 90 {
+		t.Errorf("expected the backfilled bar after invalidation, got %v", closes(got))
+	}
+
+	// A bar older than the newest one that isn't cached can't be placed in the ring, so the symbol reloads
+	late := testPriceBar(bars[0].Timestamp.Add(-time.Minute), 95)
+	if err := db.InsertPriceDataBatch([]*models.PriceData{late}); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+	cache.Append([]*models.PriceData{late})
+	if got, _ := cache.GetPriceDataRangeTimeframe("AAPL", from, time.Now(), models.Timeframe1m); len(got) != 12 || got[1].Close != 95 {
+		t.Errorf("expected the late bar after the reload, got %v", closes(got))
+	}
+}
+
+// TestPriceCacheFallsBackForOlderRanges tests that ranges before the window or evicted from a full ring are
+// read from the database
+func TestPriceCacheFallsBackForOlderRanges(t *testing.T) {
+	cache, db, bars := newTestPriceCache(t, 10, config.PriceCacheConfig{MaxBars: 4})
+
+	// Only the newest 4 bars fit, so a read from the first bar goes to the database
+	if got, _ := cache.GetPriceDataRangeTimeframe("AAPL", bars[0].Timestamp, time.Now(), models.Timeframe1m); len(got) != 10 {
+		t.Errorf("expected all 10 bars from the database, got %d", len(got))
+	}
+	if err := db.InsertPriceDataBatch([]*models.PriceData{testPriceBar(bars[9].Timestamp.Add(time.Minute), 300)}); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+	if got, _ := cache.GetPriceDataRangeTimeframe("AAPL", bars[6].Timestamp, time.Now().Add(time.Hour), models.Timeframe1m); len(got) != 4 {
+		t.Errorf("expected the 4 cached bars, got %v", closes(got))
+	}
+
+	// Bars older than the window are never cached
+	old := testPriceBar(time.Now().AddDate(0, 0, -3), 50)
+	if err := db.InsertPriceDataBatch([]*models.PriceData{old}); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+	short, _, _ := newTestPriceCache(t, 1, config.PriceCacheConfig{Days: 1})
+	short.db = db
+	if got, _ := short.GetPriceDataRangeTimeframe("AAPL", old.Timestamp, time.Now().Add(time.Hour), models.Timeframe1m); len(got) != 12 || got[0].Close != 50 {
+		t.Errorf("expected the old bar from the database, got %v", closes(got))
+	}
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExhausted is returned when the daily request budget has been used up
+var ErrBudgetExhausted = fmt.Errorf("%w: daily request budget exhausted", ErrUpstreamRateLimited)
+
+// RateLimitStats summarizes request pacing and budget usage
+type RateLimitStats struct {
+	RequestsPerMinute int        `json:"requests_per_minute"`
+	TokensAvailable   float64    `json:"tokens_available"`
+	RequestsToday     int        `json:"requests_today"`
+	DailyBudget       int        `json:"daily_budget"`     // 0 means unlimited
+	BudgetRemaining   int        `json:"budget_remaining"` // -1 when unlimited
+	TotalRequests     int64      `json:"total_requests"`
+	Throttled         int64      `json:"throttled"` // 429 responses received
+	Retries           int64      `json:"retries"`
+	BackoffUntil      *time.Time `json:"backoff_until,omitempty"`
+}
+
+// RateLimitedProvider is implemented by market data providers that pace their requests
+type RateLimitedProvider interface {
+	RateLimitStats() *RateLimitStats
+}
+
+// RateLimiter is a token bucket with a daily request budget and a backoff window
+type RateLimiter struct {
+	perMinute    int
+	rate         float64 // tokens per second
+	burst        float64
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+	dailyBudget  int
+	day          string
+	usedToday    int
+	total        int64
+	throttled    int64
+	retries      int64
+	now          func() time.Time
+	mutex        sync.Mutex
+}
+
+// NewRateLimiter creates a rate limiter allowing perMinute requests with the given burst
+func NewRateLimiter(perMinute, burst, dailyBudget int) *RateLimiter {
+	if perMinute <= 0 {
+		perMinute = 5
+	}
+	if burst <= 0 {
+		burst = perMinute
+	}
+
+	return &RateLimiter{
+		perMinute:   perMinute,
+		rate:        float64(perMinute) / 60,
+		burst:       float64(burst),
+		tokens:      float64(burst),
+		last:        time.Now(),
+		dailyBudget: dailyBudget,
+		now:         time.Now,
+	}
+}
+
+// Wait blocks until a request may be made and counts it against the budget
+func (rl *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		rl.mutex.Lock()
+		now := rl.now()
+		rl.refill(now)
+
+		if rl.dailyBudget > 0 && rl.usedToday >= rl.dailyBudget {
+			rl.mutex.Unlock()
+			return ErrBudgetExhausted
+		}
+
+		var wait time.Duration
+		switch {
+		case now.Before(rl.blockedUntil):
+			wait = rl.blockedUntil.Sub(now)
+		case rl.tokens >= 1:
+			rl.tokens--
+			rl.usedToday++
+			rl.total++
+			rl.mutex.Unlock()
+			return nil
+		default:
+			wait = time.Duration((1 - rl.tokens) / rl.rate * float64(time.Second))
+		}
+		rl.mutex.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w: %v", ErrUpstreamRateLimited, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// Backoff blocks all requests for the given duration after a 429 response
+func (rl *RateLimiter) Backoff(d time.Duration) {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	rl.throttled++
+	if until := rl.now().Add(d); until.After(rl.blockedUntil) {
+		rl.blockedUntil = until
+	}
+	// The server says the window is used up, so don't burst as soon as the backoff ends
+	rl.tokens = 0
+}
+
+// RecordRetry counts a retried request
+func (rl *RateLimiter) RecordRetry() {
+	rl.mutex.Lock()
+	rl.retries++
+	rl.mutex.Unlock()
+}
+
+// Stats returns a snapshot of the limiter state
+func (rl *RateLimiter) Stats() *RateLimitStats {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	now := rl.now()
+	rl.refill(now)
+
+	stats := &RateLimitStats{
+		RequestsPerMinute: rl.perMinute,
+		TokensAvailable:   rl.tokens,
+		RequestsToday:     rl.usedToday,
+		DailyBudget:       rl.dailyBudget,
+		BudgetRemaining:   -1,
+		TotalRequests:     rl.total,
+		Throttled:         rl.throttled,
+		Retries:           rl.retries,
+	}
+	if rl.dailyBudget > 0 {
+		stats.BudgetRemaining = rl.dailyBudget - rl.usedToday
+		if stats.BudgetRemaining < 0 {
+			stats.BudgetRemaining = 0
+		}
+	}
+	if now.Before(rl.blockedUntil) {
+		until := rl.blockedUntil
+		stats.BackoffUntil = &until
+	}
+
+	return stats
+}
+
+// refill adds tokens for the elapsed time and resets the budget at UTC midnight; the caller must hold the mutex
+func (rl *RateLimiter) refill(now time.Time) {
+	if elapsed := now.Sub(rl.last).Seconds(); elapsed > 0 {
+		rl.tokens += elapsed * rl.rate
+		if rl.tokens > rl.burst {
+			rl.tokens = rl.burst
+		}
+		rl.last = now
+	}
+
+	if day := now.UTC().Format("2006-01-02"); day != rl.day {
+		rl.day = day
+		rl.usedToday = 0
+	}
+}
+
+// backoffDelay returns the exponential backoff for a retry attempt, capped at max
+func backoffDelay(attempt int, max time.Duration) time.Duration {
+	delay := time.Second << uint(attempt)
+	if delay <= 0 || delay > max {
+		return max
+	}
+	return delay
+}
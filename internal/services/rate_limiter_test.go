@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+)
+
+// TestRateLimiterBudget tests burst pacing, the daily budget and the backoff window
+func TestRateLimiterBudget(t *testing.T) {
+	now := time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC)
+	rl := NewRateLimiter(60, 2, 3)
+	rl.now = func() time.Time { return now }
+	rl.last = now
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := rl.Wait(ctx); err != nil {
+			t.Fatalf("burst request %d failed: %v", i, err)
+		}
+	}
+
+	// The bucket is empty, so the next request has to wait for a refill
+	shortCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := rl.Wait(shortCtx); !errors.Is(err, ErrUpstreamRateLimited) {
+		t.Fatalf("expected rate limit wait to time out, got %v", err)
+	}
+
+	now = now.Add(time.Second)
+	if err := rl.Wait(ctx); err != nil {
+		t.Fatalf("request after refill failed: %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	if err := rl.Wait(ctx); !errors.Is(err, ErrBudgetExhausted) {
+		t.Fatalf("expected budget exhausted, got %v", err)
+	}
+
+	rl.Backoff(30 * time.Second)
+	stats := rl.Stats()
+	if stats.RequestsToday != 3 || stats.BudgetRemaining != 0 || stats.Throttled != 1 || stats.BackoffUntil == nil {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	// The budget resets at UTC midnight
+	now = time.Date(2024, 3, 2, 0, 1, 0, 0, time.UTC)
+	if err := rl.Wait(ctx); err != nil {
+		t.Fatalf("request on the next day failed: %v", err)
+	}
+}
+
+// TestPolygonRetriesOn429 tests that throttled requests are retried with backoff
+func TestPolygonRetriesOn429(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"status":"OK","results":[{"v":1200,"t":1709305200000}]}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.Polygon.BaseURL = server.URL
+	cfg.Polygon.Timeout = 5 * time.Second
+	cfg.Polygon.RetryAttempts = 3
+	cfg.Polygon.RequestsPerMinute = 6000
+	cfg.Polygon.MaxBackoff = 10 * time.Millisecond
+	ps := NewPolygonService(cfg)
+
+	data, err := ps.GetHistoricalData("AAPL", 1)
+	if err != nil {
+		t.Fatalf("GetHistoricalData failed: %v", err)
+	}
+	if len(data) != 1 || data[0].Volume != 1200 {
+		t.Errorf("unexpected data: %+v", data)
+	}
+
+	stats := ps.RateLimitStats()
+	if stats.Throttled != 2 || stats.Retries != 2 || stats.TotalRequests != 3 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	// With no retries left the error is reported as rate limiting so the collector re-queues the symbol
+	atomic.StoreInt32(&calls, 0)
+	cfg.Polygon.RetryAttempts = 1
+	if _, err := ps.GetHistoricalData("AAPL", 1); !errors.Is(err, ErrUpstreamRateLimited) {
+		t.Errorf("expected ErrUpstreamRateLimited, got %v", err)
+	}
+}
+
+// TestPrioritizeSymbols tests that re-queued symbols are collected first
+func TestPrioritizeSymbols(t *testing.T) {
+	got := prioritizeSymbols([]string{"AAPL", "MSFT", "NVDA", "TSLA"}, []string{"TSLA", "GONE", "NVDA"})
+	want := []string{"TSLA", "NVDA", "AAPL", "MSFT"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,485 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// Reference data defaults
+const (
+	DefaultReferenceRefreshInterval = 7 * 24 * time.Hour
+	defaultAvgVolumeDays            = 30
+	referenceCheckInterval          = time.Hour // how often stale or missing reference data is looked for
+)
+
+// ReferenceDataService fetches and stores company reference data for watchlist symbols
+type ReferenceDataService struct {
+	cfg        *config.Config
+	db         *database.Database
+	client     *http.Client
+	enabled    bool
+	interval   time.Duration
+	volumeDays int
+	schedules  *ScheduleControl
+	stop       chan struct{}
+	stopOnce   sync.Once
+	wg         sync.WaitGroup // tracks the background refresh loop and on-add fetches
+}
+
+// polygonTickerDetailsResponse represents the response from the Polygon ticker details endpoint
+type polygonTickerDetailsResponse struct {
+	Status  string `json:"status"`
+	Results struct {
+		Ticker                      string  `json:"ticker"`
+		Name                        string  `json:"name"`
+		MarketCap                   float64 `json:"market_cap"`
+		PrimaryExchange             string  `json:"primary_exchange"`
+		SICCode                     string  `json:"sic_code"`
+		SICDescription              string  `json:"sic_description"`
+		ShareClassSharesOutstanding int64   `json:"share_class_shares_outstanding"`
+		WeightedSharesOutstanding   int64   `json:"weighted_shares_outstanding"`
+	} `json:"results"`
+}
+
+// polygonFloatResponse represents the response from the Polygon float endpoint
+type polygonFloatResponse struct {
+	Results []struct {
+		Ticker    string  `json:"ticker"`
+		FreeFloat float64 `json:"free_float"`
+	} `json:"results"`
+}
+
+// polygonDailyBarsResponse represents daily aggregates, of which only the volume is used
+type polygonDailyBarsResponse struct {
+	Results []struct {
+		Volume float64 `json:"v"`
+	} `json:"results"`
+}
+
+// NewReferenceDataService creates a new reference data service
+func NewReferenceDataService(cfg *config.Config, db *database.Database) *ReferenceDataService {
+	interval := cfg.ReferenceData.RefreshInterval
+	if interval <= 0 {
+		interval = DefaultReferenceRefreshInterval
+	}
+
+	volumeDays := cfg.ReferenceData.AvgVolumeDays
+	if volumeDays <= 0 {
+		volumeDays = defaultAvgVolumeDays
+	}
+
+	timeout := cfg.Polygon.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	return &ReferenceDataService{
+		cfg:        cfg,
+		db:         db,
+		client:     &http.Client{Timeout: timeout},
+		enabled:    cfg.ReferenceData.Enabled,
+		interval:   interval,
+		volumeDays: volumeDays,
+		stop:       make(chan struct{}),
+	}
+}
+
+// IsEnabled reports whether reference data is fetched
+func (rs *ReferenceDataService) IsEnabled() bool {
+	return rs != nil && rs.enabled
+}
+
+// SetScheduleControl sets the control that can disable the reference data refresh
+func (rs *ReferenceDataService) SetScheduleControl(schedules *ScheduleControl) {
+	rs.schedules = schedules
+}
+
+// Start fetches missing and stale reference data now and then hourly when enabled
+func (rs *ReferenceDataService) Start() {
+	if !rs.enabled {
+		log.Printf("Reference data enrichment disabled")
+		return
+	}
+
+	log.Printf("Starting reference data enrichment (refetched every %v)...", rs.interval)
+
+	rs.wg.Add(1)
+	go func() {
+		defer rs.wg.Done()
+
+		ticker := time.NewTicker(referenceCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			rs.scheduledRefresh()
+
+			select {
+			case <-ticker.C:
+			case <-rs.stop:
+				return
+			}
+		}
+	}()
+}
+
+// scheduledRefresh fetches missing and stale reference data unless the schedule is disabled
+func (rs *ReferenceDataService) scheduledRefresh() {
+	if rs.schedules.Skip(models.ScheduleReferenceData) {
+		return
+	}
+	if err := rs.RefreshStale(); err != nil {
+		log.Printf("Reference data refresh failed: %v", err)
+	}
+}
+
+// Stop stops the refresh loop and waits for in-flight fetches to finish
+func (rs *ReferenceDataService) Stop(ctx context.Context) error {
+	rs.stopOnce.Do(func() { close(rs.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		rs.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Printf("Reference data service stopped")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for reference data refresh to finish: %w", ctx.Err())
+	}
+}
+
+// RefreshStale fetches reference data for watchlist and watched symbols that have none or
+// whose data is older than the refresh interval
+func (rs *ReferenceDataService) RefreshStale() error {
+	symbols, err := rs.trackedSymbols()
+	if err != nil {
+		return err
+	}
+
+	refs, err := rs.db.GetSymbolReferences()
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().Add(-rs.interval)
+	refreshed := 0
+	for _, symbol := range symbols {
+		if ref, ok := refs[symbol]; ok && ref.UpdatedAt.After(cutoff) {
+			continue
+		}
+		if _, err := rs.RefreshSymbol(symbol); err != nil {
+			log.Printf("Failed to refresh reference data for %s: %v", symbol, err)
+			continue
+		}
+		refreshed++
+	}
+
+	if refreshed > 0 {
+		log.Printf("Reference data refreshed for %d symbols", refreshed)
+	}
+	return nil
+}
+
+// RefreshSymbolAsync fetches a newly added symbol's reference data in the background
+func (rs *ReferenceDataService) RefreshSymbolAsync(symbol string) {
+	if !rs.IsEnabled() {
+		return
+	}
+
+	rs.wg.Add(1)
+	go func() {
+		defer rs.wg.Done()
+		if _, err := rs.RefreshSymbol(symbol); err != nil {
+			log.Printf("Failed to fetch reference data for %s: %v", symbol, err)
+		}
+	}()
+}
+
+// RefreshSymbol fetches and stores a symbol's reference data, filling in the watchlist company name
+func (rs *ReferenceDataService) RefreshSymbol(symbol string) (*models.SymbolReference, error) {
+	symbol = strings.ToUpper(symbol)
+
+	ref, err := rs.fetchTickerDetails(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	// Float and volume are best effort; the ticker details alone are worth keeping
+	if float, err := rs.fetchFloat(symbol); err != nil {
+		log.Printf("Float not available for %s: %v", symbol, err)
+	} else {
+		ref.FloatShares = float
+	}
+	if avgVolume, err := rs.fetchAvgVolume(symbol); err != nil {
+		log.Printf("Average volume not available for %s: %v", symbol, err)
+	} else {
+		ref.AvgVolume = avgVolume
+	}
+
+	ref.UpdatedAt = time.Now()
+	if err := rs.db.UpsertSymbolReference(ref); err != nil {
+		return nil, err
+	}
+	if ref.Name != "" {
+		if err := rs.db.FillStockName(symbol, ref.Name); err != nil {
+			log.Printf("Failed to set company name for %s: %v", symbol, err)
+		}
+	}
+
+	return ref, nil
+}
+
+// GetReference returns the stored reference data of a symbol, or nil if it hasn't been fetched
+func (rs *ReferenceDataService) GetReference(symbol string) (*models.SymbolReference, error) {
+	return rs.db.GetSymbolReference(symbol)
+}
+
+// trackedSymbols returns the watchlist stocks and watched symbols, deduplicated and sorted
+func (rs *ReferenceDataService) trackedSymbols() ([]string, error) {
+	watched, err := rs.db.GetWatchedSymbols()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watched symbols: %w", err)
+	}
+	stocks, err := rs.db.GetStocks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watchlist stocks: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, symbol := range equitySymbols(watched) {
+		seen[strings.ToUpper(symbol)] = true
+	}
+	for _, stock := range stocks {
+		seen[strings.ToUpper(stock.Symbol)] = true
+	}
+
+	symbols := make([]string, 0, len(seen))
+	for symbol := range seen {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols, nil
+}
+
+// fetchTickerDetails reads the company name, exchange, market cap, shares and SIC classification
+func (rs *ReferenceDataService) fetchTickerDetails(symbol string) (*models.SymbolReference, error) {
+	var details polygonTickerDetailsResponse
+	if err := rs.getJSON("/v3/reference/tickers/"+url.PathEscape(symbol), url.Values{}, &details); err != nil {
+		return nil, err
+	}
+
+	result := details.Results
+	shares := result.ShareClassSharesOutstanding
+	if shares == 0 {
+		shares = result.WeightedSharesOutstanding
+	}
+
+	return &models.SymbolReference{
+		Symbol:            symbol,
+		Name:              result.Name,
+		Sector:            sicSector(result.SICCode),
+		Industry:          titleCase(result.SICDescription),
+		Exchange:          result.PrimaryExchange,
+		MarketCap:         result.MarketCap,
+		SharesOutstanding: shares,
+	}, nil
+}
+
+// fetchFloat reads the latest free float, which not every Polygon plan includes
+func (rs *ReferenceDataService) fetchFloat(symbol string) (int64, error) {
+	params := url.Values{}
+	params.Set("ticker", symbol)
+
+	var float polygonFloatResponse
+	if err := rs.getJSON("/stocks/vX/float", params, &float); err != nil {
+		return 0, err
+	}
+	if len(float.Results) == 0 {
+		return 0, fmt.Errorf("no float data")
+	}
+	return int64(float.Results[0].FreeFloat), nil
+}
+
+// fetchAvgVolume averages the daily volume of the last completed sessions
+func (rs *ReferenceDataService) fetchAvgVolume(symbol string) (int64, error) {
+	to := time.Now().AddDate(0, 0, -1)
+	// Calendar days covering the requested sessions, allowing for weekends and holidays
+	from := to.AddDate(0, 0, -(rs.volumeDays*7/5 + 7))
+
+	params := url.Values{}
+	params.Set("adjusted", "true")
+	params.Set("sort", "desc")
+	params.Set("limit", strconv.Itoa(rs.volumeDays))
+
+	var bars polygonDailyBarsResponse
+	path := fmt.Sprintf("/v2/aggs/ticker/%s/range/1/day/%s/%s", url.PathEscape(symbol), from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err := rs.getJSON(path, params, &bars); err != nil {
+		return 0, err
+	}
+	if len(bars.Results) == 0 {
+		return 0, fmt.Errorf("no daily bars")
+	}
+
+	var total float64
+	for _, bar := range bars.Results {
+		total += bar.Volume
+	}
+	return int64(total / float64(len(bars.Results))), nil
+}
+
+// getJSON performs a GET request against the Polygon API and decodes the JSON response
+func (rs *ReferenceDataService) getJSON(path string, params url.Values, out interface{}) error {
+	params.Set("apiKey", rs.cfg.Polygon.APIKey)
+
+	req, err := http.NewRequest("GET", rs.cfg.Polygon.BaseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := rs.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// sicSectors maps SIC code ranges to market sectors, checked in order so narrower ranges win
+var sicSectors = []struct {
+	from, to int
+	sector   string
+}{
+	{2830, 2836, "Health Care"},
+	{3841, 3851, "Health Care"},
+	{8000, 8099, "Health Care"},
+	{3570, 3579, "Technology"},
+	{3600, 3699, "Technology"},
+	{3810, 3829, "Technology"},
+	{7370, 7379, "Technology"},
+	{1300, 1399, "Energy"},
+	{2900, 2999, "Energy"},
+	{4900, 4999, "Utilities"},
+	{4800, 4899, "Communication Services"},
+	{2700, 2799, "Communication Services"},
+	{7800, 7899, "Communication Services"},
+	{6500, 6553, "Real Estate"},
+	{6798, 6798, "Real Estate"},
+	{6000, 6799, "Financials"},
+	{2000, 2199, "Consumer Staples"},
+	{2840, 2844, "Consumer Staples"},
+	{5400, 5499, "Consumer Staples"},
+	{3710, 3716, "Consumer Discretionary"},
+	{5000, 5999, "Consumer Discretionary"},
+	{7000, 7299, "Consumer Discretionary"},
+	{1000, 1499, "Materials"},
+	{2800, 2899, "Materials"},
+	{3300, 3399, "Materials"},
+	{1500, 1799, "Industrials"},
+	{3400, 3599, "Industrials"},
+	{3700, 3799, "Industrials"},
+	{4000, 4799, "Industrials"},
+	{7300, 7399, "Industrials"},
+	{8700, 8799, "Industrials"},
+	{100, 999, "Consumer Staples"},
+	{2200, 3999, "Industrials"},
+}
+
+// sicSector returns the market sector of a SIC industry code
+func sicSector(code string) string {
+	sic, err := strconv.Atoi(code)
+	if err != nil {
+		return ""
+	}
+	for _, r := range sicSectors {
+		if sic >= r.from && sic <= r.to {
+			return r.sector
+		}
+	}
+	return "Other"
+}
+
+// titleCase converts Polygon's upper case SIC descriptions to title case
+func titleCase(s string) string {
+	words := strings.Fields(strings.ToLower(s))
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// FilterStocksByReference keeps the stocks whose reference data matches the filter. Stocks without
+// reference data only pass an empty filter.
+func FilterStocksByReference(stocks []models.Stock, filter *models.StockReferenceFilter) []models.Stock {
+	if filter == nil || filter.IsEmpty() {
+		return stocks
+	}
+
+	filtered := make([]models.Stock, 0, len(stocks))
+	for _, stock := range stocks {
+		ref := stock.Reference
+		switch {
+		case ref == nil:
+		case filter.Sector != "" && !strings.EqualFold(ref.Sector, filter.Sector):
+		case filter.Industry != "" && !strings.EqualFold(ref.Industry, filter.Industry):
+		case filter.MinMarketCap > 0 && ref.MarketCap < filter.MinMarketCap:
+		case filter.MaxMarketCap > 0 && ref.MarketCap > filter.MaxMarketCap:
+		case filter.MinAvgVolume > 0 && ref.AvgVolume < filter.MinAvgVolume:
+		case filter.MaxFloat > 0 && (ref.FloatShares == 0 || ref.FloatShares > filter.MaxFloat):
+		default:
+			filtered = append(filtered, stock)
+		}
+	}
+	return filtered
+}
+
+// GroupStocksByReference groups stocks by sector, industry or exchange; stocks without reference
+// data are grouped under "Unknown"
+func GroupStocksByReference(stocks []models.Stock, by string) (map[string][]models.Stock, error) {
+	field := map[string]func(*models.SymbolReference) string{
+		"sector":   func(r *models.SymbolReference) string { return r.Sector },
+		"industry": func(r *models.SymbolReference) string { return r.Industry },
+		"exchange": func(r *models.SymbolReference) string { return r.Exchange },
+	}[by]
+	if field == nil {
+		return nil, fmt.Errorf("unsupported group_by: %s (use sector, industry or exchange)", by)
+	}
+
+	groups := make(map[string][]models.Stock)
+	for _, stock := range stocks {
+		key := ""
+		if stock.Reference != nil {
+			key = field(stock.Reference)
+		}
+		if key == "" {
+			key = "Unknown"
+		}
+		groups[key] = append(groups[key], stock)
+	}
+	return groups, nil
+}
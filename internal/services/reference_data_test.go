@@ -0,0 +1,93 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// TestRefreshSymbolReference tests that ticker details, float and average volume are stored and fill the stock name
+func TestRefreshSymbolReference(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v3/reference/tickers/AAPL":
+			fmt.Fprint(w, `{"status":"OK","results":{"ticker":"AAPL","name":"Apple Inc.","market_cap":3.1e12,"primary_exchange":"XNAS","sic_code":"3571","sic_description":"ELECTRONIC COMPUTERS","share_class_shares_outstanding":15000000000}}`)
+		case r.URL.Path == "/stocks/vX/float":
+			// Plans without float data are rejected; the rest of the reference data is still stored
+			http.Error(w, `{"status":"NOT_AUTHORIZED"}`, http.StatusForbidden)
+		case strings.HasPrefix(r.URL.Path, "/v2/aggs/ticker/AAPL/range/1/day/"):
+			fmt.Fprint(w, `{"results":[{"v":50000000},{"v":70000000}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.Polygon.BaseURL = server.URL
+	cfg.ReferenceData.Enabled = true
+	db := newTestDB(t)
+
+	if _, err := db.AddStock(models.Stock{Symbol: "AAPL"}); err != nil {
+		t.Fatalf("AddStock failed: %v", err)
+	}
+
+	service := NewReferenceDataService(cfg, db)
+	if _, err := service.RefreshSymbol("aapl"); err != nil {
+		t.Fatalf("RefreshSymbol failed: %v", err)
+	}
+
+	ref, err := db.GetSymbolReference("AAPL")
+	if err != nil || ref == nil {
+		t.Fatalf("expected stored reference data, got %v (%v)", ref, err)
+	}
+	if ref.Sector != "Technology" || ref.Industry != "Electronic Computers" || ref.MarketCap != 3.1e12 {
+		t.Errorf("unexpected classification: %+v", ref)
+	}
+	if ref.AvgVolume != 60000000 || ref.FloatShares != 0 || ref.SharesOutstanding != 15000000000 {
+		t.Errorf("unexpected volume and shares: %+v", ref)
+	}
+
+	stock, err := db.GetStockBySymbol("AAPL")
+	if err != nil || stock.Name != "Apple Inc." {
+		t.Errorf("expected the company name to be filled in, got %+v (%v)", stock, err)
+	}
+}
+
+// TestFilterStocksByReference tests reference data filters and sector grouping
+func TestFilterStocksByReference(t *testing.T) {
+	stocks := []models.Stock{
+		{Symbol: "AAPL", Reference: &models.SymbolReference{Sector: "Technology", MarketCap: 3e12, AvgVolume: 60e6, FloatShares: 15e9}},
+		{Symbol: "BBAI", Reference: &models.SymbolReference{Sector: "Technology", MarketCap: 8e8, AvgVolume: 30e6, FloatShares: 2e8}},
+		{Symbol: "XOM", Reference: &models.SymbolReference{Sector: "Energy", MarketCap: 4.5e11, AvgVolume: 15e6}},
+		{Symbol: "NEW"},
+	}
+
+	small := FilterStocksByReference(stocks, &models.StockReferenceFilter{Sector: "technology", MaxMarketCap: 1e9})
+	if len(small) != 1 || small[0].Symbol != "BBAI" {
+		t.Errorf("expected only BBAI, got %v", small)
+	}
+	// Stocks without float data never pass a float filter
+	if lowFloat := FilterStocksByReference(stocks, &models.StockReferenceFilter{MaxFloat: 1e10}); len(lowFloat) != 1 {
+		t.Errorf("expected one low float stock, got %d", len(lowFloat))
+	}
+	if all := FilterStocksByReference(stocks, &models.StockReferenceFilter{}); len(all) != 4 {
+		t.Errorf("an empty filter should keep every stock, got %d", len(all))
+	}
+
+	groups, err := GroupStocksByReference(stocks, "sector")
+	if err != nil {
+		t.Fatalf("GroupStocksByReference failed: %v", err)
+	}
+	if len(groups["Technology"]) != 2 || len(groups["Energy"]) != 1 || len(groups["Unknown"]) != 1 {
+		t.Errorf("unexpected groups: %v", groups)
+	}
+	if _, err := GroupStocksByReference(stocks, "color"); err == nil {
+		t.Error("expected an error for an unsupported grouping")
+	}
+}
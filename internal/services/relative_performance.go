@@ -0,0 +1,124 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// comparePoints is about how many points a relative performance series has when no timeframe is asked for
+const comparePoints = 500
+
+// CompareRanges lists the relative performance ranges, shortest first
+var CompareRanges = []string{"1D", "1W", "2W", "1M", "3M", "6M", "1Y"}
+
+// compareLookbacks are the years, months and days each relative performance range looks back
+var compareLookbacks = map[string][3]int{
+	"1D": {0, 0, -1},
+	"1W": {0, 0, -7},
+	"2W": {0, 0, -14},
+	"1M": {0, -1, 0},
+	"3M": {0, -3, 0},
+	"6M": {0, -6, 0},
+	"1Y": {-1, 0, 0},
+}
+
+// RelativePerformanceService compares how symbols performed over a range from their stored regular session bars
+type RelativePerformanceService struct {
+	prices PriceReader
+}
+
+// NewRelativePerformanceService creates a new relative performance service
+func NewRelativePerformanceService(db *database.Database, calendar *MarketCalendar) *RelativePerformanceService {
+	return &RelativePerformanceService{
+		prices: NewRegularSessionReader(db, calendar),
+	}
+}
+
+// Compare returns each symbol's percent change over the range, one value per timestamp shared by every series.
+// The series start at the first timestamp every symbol has a close at; later gaps in a symbol's bars carry its
+// previous close forward. An empty timeframe picks one giving about comparePoints points.
+func (rp *RelativePerformanceService) Compare(symbols []string, rangeStr string, timeframe models.Timeframe) (*models.RelativePerformance, error) {
+	lookback, ok := compareLookbacks[rangeStr]
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid range %q: must be one of %s", ErrValidation, rangeStr, strings.Join(CompareRanges, ", "))
+	}
+	to := clockNow()
+	from := to.AddDate(lookback[0], lookback[1], lookback[2])
+	if timeframe == "" {
+		timeframe = ChartTimeframe(to.Sub(from), comparePoints)
+	}
+
+	result := &models.RelativePerformance{
+		Symbols:   symbols,
+		Range:     rangeStr,
+		Timeframe: timeframe,
+		From:      from,
+		To:        to,
+		Times:     []int64{},
+		Series:    []*models.RelativePerformanceSeries{},
+	}
+
+	var found []string
+	var closes []map[int64]float64
+	seen := make(map[int64]bool)
+	start := int64(math.MinInt64)
+	for _, symbol := range symbols {
+		candles, err := rp.prices.GetPriceData(&models.PriceDataFilter{Symbol: symbol, From: from, To: to, Timeframe: timeframe})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s price data: %w", symbol, err)
+		}
+		if len(candles) == 0 || candles[0].Close <= 0 {
+			result.Missing = append(result.Missing, symbol)
+			continue
+		}
+
+		byTime := make(map[int64]float64, len(candles))
+		for _, candle := range candles {
+			byTime[candle.Timestamp.Unix()] = candle.Close
+			seen[candle.Timestamp.Unix()] = true
+		}
+		start = max(start, candles[0].Timestamp.Unix())
+		found = append(found, symbol)
+		closes = append(closes, byTime)
+	}
+	if len(found) == 0 {
+		return nil, fmt.Errorf("price data for %s in the last %s %w", strings.Join(symbols, ", "), rangeStr, database.ErrNotFound)
+	}
+
+	times := make([]int64, 0, len(seen))
+	for ts := range seen {
+		times = append(times, ts)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	for _, ts := range times {
+		if ts >= start {
+			result.Times = append(result.Times, ts)
+		}
+	}
+
+	for k, symbol := range found {
+		series := &models.RelativePerformanceSeries{Symbol: symbol, Values: make([]float64, 0, len(result.Times))}
+		last := 0.0
+		for _, ts := range times {
+			if close, ok := closes[k][ts]; ok {
+				last = close
+			}
+			if ts < start {
+				continue
+			}
+			if series.BaseClose == 0 {
+				series.BaseClose = last
+			}
+			series.Values = append(series.Values, roundTo((last/series.BaseClose-1)*100, 4))
+		}
+		series.Change = series.Values[len(series.Values)-1]
+		result.Series = append(result.Series, series)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// TestRelativePerformance tests percent change series aligned from the first timestamp shared by every symbol
+func TestRelativePerformance(t *testing.T) {
+	cfg := &config.Config{}
+	db := newTestDB(t)
+
+	calendar := NewMarketCalendar(cfg.MarketHours)
+	loc := calendar.Location()
+	bar := func(symbol string, day int, close float64) *models.PriceData {
+		ts := time.Date(2025, 3, day, 10, 0, 0, 0, loc)
+		return &models.PriceData{Symbol: symbol, Timestamp: ts, Open: close, High: close, Low: close, Close: close, Volume: 100}
+	}
+	// B starts a day later and has no bar on Thursday
+	bars := []*models.PriceData{
+		bar("A", 3, 100), bar("A", 4, 102), bar("A", 5, 104), bar("A", 6, 106), bar("A", 7, 112.2),
+		bar("B", 4, 50), bar("B", 5, 55), bar("B", 7, 60),
+	}
+	if err := db.InsertPriceDataBatch(bars); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+
+	SetClock(NewSimulatedClock(time.Date(2025, 3, 7, 17, 0, 0, 0, loc)))
+	t.Cleanup(func() { SetClock(nil) })
+
+	rp := NewRelativePerformanceService(db, calendar)
+	result, err := rp.Compare([]string{"A", "B", "NONE"}, "1W", models.Timeframe1d)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if len(result.Times) != 4 || len(result.Series) != 2 {
+		t.Fatalf("expected 2 series of 4 points, got %d series of %d", len(result.Series), len(result.Times))
+	}
+	if len(result.Missing) != 1 || result.Missing[0] != "NONE" {
+		t.Errorf("expected NONE to be missing, got %v", result.Missing)
+	}
+
+	expected := map[string][]float64{
+		"A": {0, 1.9608, 3.9216, 10},
+		"B": {0, 10, 10, 20},
+	}
+	for _, series := range result.Series {
+		for i, value := range expected[series.Symbol] {
+			if series.Values[i] != value {
+				t.Errorf("%s: expected %v, got %v", series.Symbol, expected[series.Symbol], series.Values)
+				break
+			}
+		}
+		if series.Change != series.Values[len(series.Values)-1] {
+			t.Errorf("%s: expected the change to be the last value, got %.4f", series.Symbol, series.Change)
+		}
+	}
+
+	if _, err := rp.Compare([]string{"A"}, "5Y", ""); !errors.Is(err, ErrValidation) {
+		t.Errorf("expected an invalid range to fail validation, got %v", err)
+	}
+	if _, err := rp.Compare([]string{"NONE"}, "1W", ""); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("expected symbols without bars to be not found, got %v", err)
+	}
+}
@@ -0,0 +1,378 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// Replay defaults
+const (
+	DefaultReplayStep       = 5 * time.Minute
+	MaxReplayStep           = 2 * time.Hour
+	DefaultReplayWindow     = 5 * 24 * time.Hour
+	DefaultReplayWarmupDays = 30
+)
+
+// ReplayProvider serves stored bars up to the simulated clock, standing in for a live provider in replay mode
+type ReplayProvider struct {
+	source *database.Database
+	clock  Clock
+}
+
+// NewReplayProvider creates a provider that reads bars from a stored database
+func NewReplayProvider(source *database.Database, clock Clock) *ReplayProvider {
+	return &ReplayProvider{
+		source: source,
+		clock:  clock,
+	}
+}
+
+// Name returns the provider identifier
+func (rp *ReplayProvider) Name() string {
+	return ProviderReplay
+}
+
+// GetLatestAggregates returns stored volume bars for the last N simulated minutes
+func (rp *ReplayProvider) GetLatestAggregates(symbol string, minutes int) ([]*models.VolumeData, error) {
+	now := rp.clock.Now()
+	return rp.volumeBars(symbol, now.Add(-time.Duration(minutes)*time.Minute), now)
+}
+
+// GetLatestPriceAggregates returns stored OHLC bars for the last N simulated minutes
+func (rp *ReplayProvider) GetLatestPriceAggregates(symbol string, minutes int) ([]*models.PriceData, error) {
+	now := rp.clock.Now()
+	return rp.priceBars(symbol, now.Add(-time.Duration(minutes)*time.Minute), now)
+}
+
+// GetHistoricalData returns stored volume bars for the last N simulated days
+func (rp *ReplayProvider) GetHistoricalData(symbol string, days int) ([]*models.VolumeData, error) {
+	now := rp.clock.Now()
+	return rp.volumeBars(symbol, now.AddDate(0, 0, -days), now)
+}
+
+// GetHistoricalPriceData returns stored OHLC bars for the last N simulated days
+func (rp *ReplayProvider) GetHistoricalPriceData(symbol string, days int) ([]*models.PriceData, error) {
+	now := rp.clock.Now()
+	return rp.priceBars(symbol, now.AddDate(0, 0, -days), now)
+}
+
+// GetAggregates returns stored volume bars in a time range, up to the simulated time
+func (rp *ReplayProvider) GetAggregates(symbol string, from, to time.Time) ([]*models.VolumeData, error) {
+	if now := rp.clock.Now(); to.After(now) {
+		to = now
+	}
+	return rp.volumeBars(symbol, from, to)
+}
+
+// GetPriceAggregates returns stored OHLC bars in a time range, up to the simulated time
+func (rp *ReplayProvider) GetPriceAggregates(symbol string, from, to time.Time) ([]*models.PriceData, error) {
+	if now := rp.clock.Now(); to.After(now) {
+		to = now
+	}
+	return rp.priceBars(symbol, from, to)
+}
+
+// HealthCheck verifies the stored database has bars to replay
+func (rp *ReplayProvider) HealthCheck() error {
+	count, err := rp.source.GetPriceDataCount()
+	if err != nil {
+		return fmt.Errorf("failed to read stored price data: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("no stored price data to replay")
+	}
+	return nil
+}
+
+// volumeBars returns fresh copies of the stored volume bars in a time range
+func (rp *ReplayProvider) volumeBars(symbol string, from, to time.Time) ([]*models.VolumeData, error) {
+	data, err := rp.source.GetVolumeData(&models.VolumeDataFilter{Symbol: symbol, From: from, To: to})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stored volume data: %w", err)
+	}
+	for _, vd := range data {
+		vd.ID = 0
+	}
+	return data, nil
+}
+
+// priceBars returns fresh copies of the stored OHLC bars in a time range
+func (rp *ReplayProvider) priceBars(symbol string, from, to time.Time) ([]*models.PriceData, error) {
+	data, err := rp.source.GetPriceDataRange(symbol, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stored price data: %w", err)
+	}
+	for _, pd := range data {
+		pd.ID = 0
+	}
+	return data, nil
+}
+
+// ReplayStatus describes the progress of a replay run
+type ReplayStatus struct {
+	IsRunning     bool      `json:"is_running"`
+	Completed     bool      `json:"completed"`
+	Start         time.Time `json:"start"`
+	End           time.Time `json:"end"`
+	SimulatedTime time.Time `json:"simulated_time"`
+	Step          string    `json:"step"`
+	Speed         float64   `json:"speed"`
+	Steps         int       `json:"steps"`
+	ScanRuns      int       `json:"scan_runs"`
+	MonitorRuns   int       `json:"monitor_runs"`
+	LastError     string    `json:"last_error"`
+}
+
+// ReplayService steps a simulated clock through stored data, running the collector and the
+// pattern detectors at each step so detection changes can be tested deterministically
+type ReplayService struct {
+	cfg             config.ReplayConfig
+	db              *database.Database
+	source          *database.Database
+	clock           *SimulatedClock
+	collector       *CollectorService
+	patterns        *PatternDetectionService
+	scanInterval    time.Duration
+	monitorInterval time.Duration
+	status          *ReplayStatus
+	mutex           sync.RWMutex
+	stop            chan struct{}
+	stopOnce        sync.Once
+	wg              sync.WaitGroup
+}
+
+// NewReplayService creates a replay driver for the collector and pattern detection services
+func NewReplayService(cfg *config.Config, db, source *database.Database, clock *SimulatedClock, collector *CollectorService, patterns *PatternDetectionService) *ReplayService {
+	replayCfg := cfg.Replay
+	if replayCfg.Step <= 0 {
+		replayCfg.Step = DefaultReplayStep
+	}
+	// The collector only looks back two hours, so larger steps would skip bars
+	if replayCfg.Step > MaxReplayStep {
+		replayCfg.Step = MaxReplayStep
+	}
+	if replayCfg.WarmupDays <= 0 {
+		replayCfg.WarmupDays = DefaultReplayWarmupDays
+	}
+
+	scanInterval := cfg.PatternDetection.ScanInterval
+	if scanInterval <= 0 {
+		scanInterval = DefaultPatternScanInterval
+	}
+	monitorInterval := cfg.PatternDetection.MonitorInterval
+	if monitorInterval <= 0 {
+		monitorInterval = DefaultPatternMonitorInterval
+	}
+
+	return &ReplayService{
+		cfg:             replayCfg,
+		db:              db,
+		source:          source,
+		clock:           clock,
+		collector:       collector,
+		patterns:        patterns,
+		scanInterval:    scanInterval,
+		monitorInterval: monitorInterval,
+		status: &ReplayStatus{
+			Step:  replayCfg.Step.String(),
+			Speed: replayCfg.Speed,
+		},
+		stop: make(chan struct{}),
+	}
+}
+
+// Symbols returns the symbols to replay, defaulting to the stored database's watched symbols
+func (rs *ReplayService) Symbols() ([]string, error) {
+	if len(rs.cfg.Symbols) > 0 {
+		return rs.cfg.Symbols, nil
+	}
+
+	symbols, err := rs.source.GetWatchedSymbols()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stored watched symbols: %w", err)
+	}
+	return symbols, nil
+}
+
+// Window resolves the simulated time range, defaulting to the last days before the latest stored bar
+func (rs *ReplayService) Window(symbols []string) (time.Time, time.Time, error) {
+	start, end := rs.cfg.Start, rs.cfg.End
+
+	if end.IsZero() {
+		for _, symbol := range symbols {
+			latest, err := rs.source.GetLatestPriceData(symbol)
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			if err != nil {
+				return time.Time{}, time.Time{}, fmt.Errorf("failed to get latest stored bar for %s: %w", symbol, err)
+			}
+			if latest != nil && latest.Timestamp.After(end) {
+				end = latest.Timestamp
+			}
+		}
+		if end.IsZero() {
+			return time.Time{}, time.Time{}, fmt.Errorf("no stored price data for symbols %v", symbols)
+		}
+	}
+
+	if start.IsZero() {
+		start = end.Add(-DefaultReplayWindow)
+	}
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("replay start %s must be before end %s", start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+
+	return start, end, nil
+}
+
+// Start loads the warm-up history and begins stepping through the replay window in the background
+func (rs *ReplayService) Start() error {
+	symbols, err := rs.Symbols()
+	if err != nil {
+		return err
+	}
+	start, end, err := rs.Window(symbols)
+	if err != nil {
+		return err
+	}
+
+	if err := rs.db.EnsureConfigSymbolsWatched(symbols); err != nil {
+		return fmt.Errorf("failed to watch replay symbols: %w", err)
+	}
+
+	rs.mutex.Lock()
+	rs.status.IsRunning = true
+	rs.status.Start = start
+	rs.status.End = end
+	rs.status.SimulatedTime = start
+	rs.mutex.Unlock()
+
+	log.Printf("Replaying %v from %s to %s (step %v, speed %gx)", symbols, start.Format(time.RFC3339), end.Format(time.RFC3339), rs.cfg.Step, rs.cfg.Speed)
+
+	// Detectors need a lookback, so history up to the start is loaded in one pass
+	rs.clock.Set(start)
+	if err := rs.collector.CollectHistoricalData(rs.cfg.WarmupDays); err != nil {
+		return fmt.Errorf("failed to load replay warm-up data: %w", err)
+	}
+
+	rs.wg.Add(1)
+	go func() {
+		defer rs.wg.Done()
+		rs.run(start, end)
+	}()
+
+	return nil
+}
+
+// run advances the simulated clock one step at a time, collecting bars and running due detections
+func (rs *ReplayService) run(start, end time.Time) {
+	defer func() {
+		rs.mutex.Lock()
+		rs.status.IsRunning = false
+		rs.mutex.Unlock()
+	}()
+
+	var lastScan, lastMonitor time.Time
+	for now := start.Add(rs.cfg.Step); !now.After(end); now = now.Add(rs.cfg.Step) {
+		select {
+		case <-rs.stop:
+			log.Printf("Replay stopped at %s", rs.clock.Now().Format(time.RFC3339))
+			return
+		default:
+		}
+
+		rs.clock.Set(now)
+		rs.collector.CollectNow()
+
+		scanned, monitored := false, false
+		var stepErr error
+		if now.Sub(lastScan) >= rs.scanInterval {
+			if err := rs.patterns.AutoDetectPatternsForAllSymbols(); err != nil {
+				stepErr = fmt.Errorf("pattern scan at %s failed: %w", now.Format(time.RFC3339), err)
+			}
+			lastScan, scanned = now, true
+		}
+		if now.Sub(lastMonitor) >= rs.monitorInterval {
+			if err := rs.patterns.MonitorActivePatterns(); err != nil {
+				stepErr = fmt.Errorf("pattern monitoring at %s failed: %w", now.Format(time.RFC3339), err)
+			}
+			lastMonitor, monitored = now, true
+		}
+
+		rs.mutex.Lock()
+		rs.status.SimulatedTime = now
+		rs.status.Steps++
+		if scanned {
+			rs.status.ScanRuns++
+		}
+		if monitored {
+			rs.status.MonitorRuns++
+		}
+		if stepErr != nil {
+			rs.status.LastError = stepErr.Error()
+			log.Printf("Replay: %v", stepErr)
+		}
+		rs.mutex.Unlock()
+
+		if delay := rs.stepDelay(); delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-rs.stop:
+				log.Printf("Replay stopped at %s", now.Format(time.RFC3339))
+				return
+			}
+		}
+	}
+
+	rs.mutex.Lock()
+	rs.status.Completed = true
+	steps := rs.status.Steps
+	rs.mutex.Unlock()
+
+	log.Printf("Replay completed after %d steps", steps)
+}
+
+// stepDelay returns the wall-clock pause between steps for the configured speed
+func (rs *ReplayService) stepDelay() time.Duration {
+	if rs.cfg.Speed <= 0 {
+		return 0
+	}
+	return time.Duration(float64(rs.cfg.Step) / rs.cfg.Speed)
+}
+
+// Status returns a snapshot of the replay progress
+func (rs *ReplayService) Status() *ReplayStatus {
+	rs.mutex.RLock()
+	defer rs.mutex.RUnlock()
+
+	statusCopy := *rs.status
+	return &statusCopy
+}
+
+// Stop ends the replay and waits for the current step to finish
+func (rs *ReplayService) Stop(ctx context.Context) error {
+	rs.stopOnce.Do(func() { close(rs.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		rs.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for replay to stop: %w", ctx.Err())
+	}
+}
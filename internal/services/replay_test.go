@@ -0,0 +1,73 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// TestReplayCollection tests that collecting through the replay provider only stores bars up to the simulated clock
+func TestReplayCollection(t *testing.T) {
+	source := newTestDB(t)
+	target := newTestDB(t)
+
+	start := time.Date(2024, 3, 4, 14, 30, 0, 0, time.UTC)
+	var bars []*models.PriceData
+	var volume []*models.VolumeData
+	for i := 0; i < 60; i++ {
+		ts := start.Add(time.Duration(i) * time.Minute)
+		bars = append(bars, &models.PriceData{Symbol: "TEST", Timestamp: ts, Open: 100, High: 101, Low: 99, Close: 100.5, Volume: int64(1000 + i), CreatedAt: ts})
+		volume = append(volume, &models.VolumeData{Symbol: "TEST", Timestamp: ts, Volume: int64(1000 + i), CreatedAt: ts})
+	}
+	if err := source.InsertPriceDataBatch(bars); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+	if err := source.InsertVolumeDataBatch(volume); err != nil {
+		t.Fatalf("InsertVolumeDataBatch failed: %v", err)
+	}
+	if err := target.EnsureConfigSymbolsWatched([]string{"TEST"}); err != nil {
+		t.Fatalf("EnsureConfigSymbolsWatched failed: %v", err)
+	}
+
+	// Replay mode runs the services on the simulated clock too, so backfills look for gaps up to it
+	clock := NewSimulatedClock(start.Add(10 * time.Minute))
+	SetClock(clock)
+	t.Cleanup(func() { SetClock(nil) })
+	provider := NewReplayProvider(source, clock)
+	if err := provider.HealthCheck(); err != nil {
+		t.Fatalf("HealthCheck failed: %v", err)
+	}
+	collector := NewCollectorService(target, provider, &config.Config{})
+
+	latest := func() time.Time {
+		pd, err := target.GetLatestPriceData("TEST")
+		if err != nil {
+			t.Fatalf("GetLatestPriceData failed: %v", err)
+		}
+		return pd.Timestamp
+	}
+
+	// Warm-up history stops at the simulated start
+	if err := collector.CollectHistoricalData(1); err != nil {
+		t.Fatalf("CollectHistoricalData failed: %v", err)
+	}
+	if got := latest(); !got.Equal(start.Add(10 * time.Minute)) {
+		t.Errorf("latest bar after warm-up = %s, want %s", got, start.Add(10*time.Minute))
+	}
+
+	clock.Set(start.Add(25*time.Minute + 30*time.Second))
+	collector.CollectNow()
+	if got := latest(); !got.Equal(start.Add(25 * time.Minute)) {
+		t.Errorf("latest bar after step = %s, want %s", got, start.Add(25*time.Minute))
+	}
+
+	stored, err := target.GetPriceDataRange("TEST", start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetPriceDataRange failed: %v", err)
+	}
+	if len(stored) != 26 {
+		t.Errorf("stored %d bars, want 26", len(stored))
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"fmt"
+	"math"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+	"market-watch-go/internal/utils"
+)
+
+// Risk management defaults
+const (
+	defaultRiskAccountSize    = 100000.0
+	defaultRiskPercent        = 1.0
+	defaultRiskMaxHeatPercent = 6.0
+)
+
+// RiskService sizes setups from their entry to stop distance and tracks the open risk across them
+type RiskService struct {
+	db             *database.Database
+	accountSize    float64
+	riskPercent    float64
+	maxHeatPercent float64
+	blockOverHeat  bool
+}
+
+// NewRiskService creates a new risk service
+func NewRiskService(cfg *config.Config, db *database.Database) *RiskService {
+	riskCfg := cfg.Risk
+
+	rs := &RiskService{
+		db:             db,
+		accountSize:    riskCfg.AccountSize,
+		riskPercent:    riskCfg.RiskPercent,
+		maxHeatPercent: riskCfg.MaxHeatPercent,
+		blockOverHeat:  riskCfg.BlockOverHeat,
+	}
+
+	if rs.accountSize <= 0 {
+		rs.accountSize = defaultRiskAccountSize
+	}
+	if rs.riskPercent <= 0 {
+		rs.riskPercent = defaultRiskPercent
+	}
+	if rs.maxHeatPercent <= 0 {
+		rs.maxHeatPercent = defaultRiskMaxHeatPercent
+	}
+
+	return rs
+}
+
+// PositionSize returns the shares that risk the configured percent of the account between the setup's entry
+// and stop, capped at what the account can buy, or nil when the setup has no usable entry and stop
+func (rs *RiskService) PositionSize(setup *models.TradingSetup) *models.PositionSizing {
+	riskPerShare := math.Abs(setup.EntryPrice - setup.StopLoss)
+	if setup.EntryPrice <= 0 || setup.StopLoss <= 0 || riskPerShare == 0 {
+		return nil
+	}
+
+	shares := math.Floor(rs.accountSize * rs.riskPercent / 100 / riskPerShare)
+	shares = math.Min(shares, math.Floor(rs.accountSize/setup.EntryPrice))
+
+	return &models.PositionSizing{
+		Shares:        int(shares),
+		RiskPerShare:  riskPerShare,
+		DollarRisk:    shares * riskPerShare,
+		PositionValue: shares * setup.EntryPrice,
+	}
+}
+
+// PortfolioHeat adds up the risk of every unexpired active setup and every triggered setup
+func (rs *RiskService) PortfolioHeat() (*models.PortfolioHeat, error) {
+	active, err := rs.db.GetTradingSetups(&models.SetupFilter{IsActive: utils.BoolPtr(true)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active setups: %w", err)
+	}
+	triggered, err := rs.db.GetTradingSetups(&models.SetupFilter{Status: models.SetupStatusTriggered})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get triggered setups: %w", err)
+	}
+
+	return rs.heat(active, triggered), nil
+}
+
+// heat builds the portfolio heat of the given open setups
+func (rs *RiskService) heat(active, triggered []*models.TradingSetup) *models.PortfolioHeat {
+	heat := &models.PortfolioHeat{
+		AccountSize:     rs.accountSize,
+		RiskPercent:     rs.riskPercent,
+		MaxHeatPercent:  rs.maxHeatPercent,
+		BlockOverHeat:   rs.blockOverHeat,
+		ActiveSetups:    len(active),
+		TriggeredSetups: len(triggered),
+	}
+
+	for _, setup := range append(append([]*models.TradingSetup{}, active...), triggered...) {
+		if sizing := rs.PositionSize(setup); sizing != nil {
+			heat.OpenRisk += sizing.DollarRisk
+		}
+	}
+
+	heat.HeatPercent = heat.OpenRisk / rs.accountSize * 100
+	heat.OverLimit = heat.HeatPercent > rs.maxHeatPercent
+	return heat
+}
+
+// ApplySizing fills in each setup's recommended position. While the heat is over the limit, setups not yet
+// entered carry a warning, and are sized at zero shares when over-heat entries are blocked.
+func (rs *RiskService) ApplySizing(setups []*models.TradingSetup, heat *models.PortfolioHeat) {
+	for _, setup := range setups {
+		sizing := rs.PositionSize(setup)
+		if sizing == nil {
+			continue
+		}
+
+		if heat != nil && heat.OverLimit && setup.Status == models.SetupStatusActive {
+			sizing.Warning = fmt.Sprintf("Portfolio heat %.1f%% is over the %.1f%% limit", heat.HeatPercent, heat.MaxHeatPercent)
+			if rs.blockOverHeat {
+				sizing.Blocked = true
+				sizing.Shares = 0
+				sizing.DollarRisk = 0
+				sizing.PositionValue = 0
+			}
+		}
+		setup.Sizing = sizing
+	}
+}
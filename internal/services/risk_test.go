@@ -0,0 +1,83 @@
+package services
+
+import (
+	"testing"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// newTestRiskService creates a risk service over a $50,000 account risking 1% per setup with a 2% heat limit
+func newTestRiskService(block bool) *RiskService {
+	cfg := &config.Config{}
+	cfg.Risk = config.RiskConfig{AccountSize: 50000, RiskPercent: 1, MaxHeatPercent: 2, BlockOverHeat: block}
+	return NewRiskService(cfg, nil)
+}
+
+// TestPositionSize tests sizing from the entry to stop distance, capped at what the account can buy
+func TestPositionSize(t *testing.T) {
+	rs := newTestRiskService(false)
+
+	tests := []struct {
+		name   string
+		setup  *models.TradingSetup
+		shares int
+	}{
+		{"bullish", &models.TradingSetup{EntryPrice: 100, StopLoss: 97.5}, 200},
+		{"bearish", &models.TradingSetup{EntryPrice: 50, StopLoss: 53}, 166},
+		{"capped by the account", &models.TradingSetup{EntryPrice: 400, StopLoss: 399.9}, 125},
+	}
+	for _, tt := range tests {
+		sizing := rs.PositionSize(tt.setup)
+		if sizing == nil || sizing.Shares != tt.shares {
+			t.Errorf("%s: expected %d shares, got %+v", tt.name, tt.shares, sizing)
+			continue
+		}
+		if sizing.DollarRisk > 500 || sizing.PositionValue > 50000 {
+			t.Errorf("%s: expected at most $500 at risk within the account, got %+v", tt.name, sizing)
+		}
+	}
+
+	if sizing := rs.PositionSize(&models.TradingSetup{EntryPrice: 100, StopLoss: 100}); sizing != nil {
+		t.Errorf("expected no sizing without a stop distance, got %+v", sizing)
+	}
+}
+
+// TestApplySizingOverHeat tests that only setups not yet entered are warned, or blocked, while over the heat limit
+func TestApplySizingOverHeat(t *testing.T) {
+	newSetups := func() (active, triggered []*models.TradingSetup) {
+		active = []*models.TradingSetup{
+			{Status: models.SetupStatusActive, EntryPrice: 100, StopLoss: 95},
+			{Status: models.SetupStatusActive, EntryPrice: 20, StopLoss: 19},
+		}
+		triggered = []*models.TradingSetup{{Status: models.SetupStatusTriggered, EntryPrice: 60, StopLoss: 58}}
+		return active, triggered
+	}
+
+	rs := newTestRiskService(false)
+	active, triggered := newSetups()
+	heat := rs.heat(active, triggered)
+	if heat.OpenRisk != 1500 || heat.HeatPercent != 3 || !heat.OverLimit {
+		t.Fatalf("expected $1,500 of open risk, 3%% heat over the limit, got %+v", heat)
+	}
+
+	all := append(active, triggered...)
+	rs.ApplySizing(all, heat)
+	if all[0].Sizing.Warning == "" || all[0].Sizing.Blocked || all[0].Sizing.Shares != 100 {
+		t.Errorf("expected an active setup to be warned but still sized, got %+v", all[0].Sizing)
+	}
+	if all[2].Sizing.Warning != "" {
+		t.Errorf("expected no warning on a triggered setup, got %q", all[2].Sizing.Warning)
+	}
+
+	rs = newTestRiskService(true)
+	active, triggered = newSetups()
+	all = append(active, triggered...)
+	rs.ApplySizing(all, rs.heat(active, triggered))
+	if !all[1].Sizing.Blocked || all[1].Sizing.Shares != 0 {
+		t.Errorf("expected an active setup to be blocked, got %+v", all[1].Sizing)
+	}
+	if all[2].Sizing.Blocked || all[2].Sizing.Shares != 250 {
+		t.Errorf("expected a triggered setup to keep its size, got %+v", all[2].Sizing)
+	}
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// Relative volume defaults
+const defaultRVOLLookbackDays = 10
+
+// RVOLService compares a symbol's cumulative intraday volume with the same time of day on prior sessions
+type RVOLService struct {
+	db           *database.Database
+	calendar     *MarketCalendar
+	lookbackDays int
+}
+
+// NewRVOLService creates a new relative volume service
+func NewRVOLService(cfg *config.Config, db *database.Database, calendar *MarketCalendar) *RVOLService {
+	lookbackDays := cfg.RVOL.LookbackDays
+	if lookbackDays <= 0 {
+		lookbackDays = defaultRVOLLookbackDays
+	}
+
+	return &RVOLService{
+		db:           db,
+		calendar:     calendar,
+		lookbackDays: lookbackDays,
+	}
+}
+
+// Compute builds the symbol's RVOL curve for the current trading day (the last one when the market is
+// closed today) against the prior lookbackDays trading days. Zero uses the configured lookback.
+func (rs *RVOLService) Compute(symbol string, lookbackDays int) (*models.RVOLCurve, error) {
+	if lookbackDays <= 0 {
+		lookbackDays = rs.lookbackDays
+	}
+	if lookbackDays > models.MaxRVOLLookbackDays {
+		return nil, fmt.Errorf("days must be at most %d", models.MaxRVOLLookbackDays)
+	}
+
+	symbol = strings.ToUpper(symbol)
+	loc := rs.calendar.Location()
+	now := clockNow().In(loc)
+
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	for !rs.calendar.IsTradingDay(day) {
+		day = day.AddDate(0, 0, -1)
+	}
+
+	// Prior sessions, allowing for weekends and holidays
+	var priorDays []time.Time
+	for prev := day.AddDate(0, 0, -1); len(priorDays) < lookbackDays && day.Sub(prev) < time.Duration(lookbackDays*3+10)*24*time.Hour; prev = prev.AddDate(0, 0, -1) {
+		if rs.calendar.IsTradingDay(prev) {
+			priorDays = append(priorDays, prev)
+		}
+	}
+	from := day
+	if len(priorDays) > 0 {
+		from = priorDays[len(priorDays)-1]
+	}
+
+	bars, err := rs.db.GetPriceDataRange(symbol, from, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price data: %w", err)
+	}
+
+	curve := buildRVOLCurve(bars, day, priorDays, loc)
+	curve.Symbol = symbol
+	curve.LookbackDays = lookbackDays
+	return curve, nil
+}
+
+// buildRVOLCurve accumulates the day's volume bar by bar and divides it by the average cumulative volume
+// the prior days had reached by the same minute of the day. Prior days without any bars are left out of
+// the average; points the average can't cover yet are skipped.
+func buildRVOLCurve(bars []*models.PriceData, day time.Time, priorDays []time.Time, loc *time.Location) *models.RVOLCurve {
+	type cumulative struct {
+		minute int
+		volume int64
+	}
+
+	dateKey := func(t time.Time) string { return t.In(loc).Format("2006-01-02") }
+	minuteOf := func(t time.Time) int {
+		local := t.In(loc)
+		return local.Hour()*60 + local.Minute()
+	}
+
+	sorted := make([]*models.PriceData, len(bars))
+	copy(sorted, bars)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	wanted := make(map[string]bool, len(priorDays))
+	for _, prior := range priorDays {
+		wanted[dateKey(prior)] = true
+	}
+
+	today := dateKey(day)
+	var todayBars []*models.PriceData
+	priorSeries := make(map[string][]cumulative)
+	for _, bar := range sorted {
+		key := dateKey(bar.Timestamp)
+		switch {
+		case key == today:
+			todayBars = append(todayBars, bar)
+		case wanted[key]:
+			series := priorSeries[key]
+			total := bar.Volume
+			if len(series) > 0 {
+				total += series[len(series)-1].volume
+			}
+			priorSeries[key] = append(series, cumulative{minute: minuteOf(bar.Timestamp), volume: total})
+		}
+	}
+
+	curve := &models.RVOLCurve{
+		Date:     today,
+		DaysUsed: len(priorSeries),
+		Points:   []*models.RVOLPoint{},
+	}
+
+	var running int64
+	for _, bar := range todayBars {
+		running += bar.Volume
+		minute := minuteOf(bar.Timestamp)
+
+		// Each prior day contributes what it had traded by this minute
+		sum := 0.0
+		for _, series := range priorSeries {
+			i := sort.Search(len(series), func(i int) bool { return series[i].minute > minute }) - 1
+			if i >= 0 {
+				sum += float64(series[i].volume)
+			}
+		}
+		if curve.DaysUsed == 0 || sum <= 0 {
+			continue
+		}
+
+		avg := sum / float64(curve.DaysUsed)
+		curve.Points = append(curve.Points, &models.RVOLPoint{
+			Time:                bar.Timestamp,
+			Minute:              minute,
+			CumulativeVolume:    running,
+			AvgCumulativeVolume: avg,
+			RVOL:                float64(running) / avg,
+		})
+	}
+
+	if len(curve.Points) > 0 {
+		curve.RVOL = curve.Points[len(curve.Points)-1].RVOL
+	}
+	return curve
+}
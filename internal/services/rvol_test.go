@@ -0,0 +1,60 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestBuildRVOLCurve tests cumulative volume against the prior days' average at the same minute of day
+func TestBuildRVOLCurve(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, time.March, day, hour, minute, 0, 0, loc)
+	}
+	bar := func(t time.Time, volume int64) *models.PriceData {
+		return &models.PriceData{Timestamp: t, Volume: volume}
+	}
+
+	bars := []*models.PriceData{
+		// Two prior sessions; the second is missing its 9:35 bar
+		bar(at(4, 9, 30), 100), bar(at(4, 9, 35), 100), bar(at(4, 9, 40), 100),
+		bar(at(5, 9, 30), 300), bar(at(5, 9, 40), 100),
+		// Today, out of order
+		bar(at(6, 9, 35), 400), bar(at(6, 9, 30), 400), bar(at(6, 9, 40), 200),
+	}
+	priorDays := []time.Time{at(5, 0, 0), at(4, 0, 0), at(1, 0, 0)}
+
+	curve := buildRVOLCurve(bars, at(6, 0, 0), priorDays, loc)
+	if curve.Date != "2024-03-06" || curve.DaysUsed != 2 {
+		t.Fatalf("unexpected date %s or days used %d", curve.Date, curve.DaysUsed)
+	}
+	if len(curve.Points) != 3 {
+		t.Fatalf("expected 3 points, got %d", len(curve.Points))
+	}
+
+	// 9:30: 400 vs avg(100, 300); 9:35: 800 vs avg(200, 300); 9:40: 1000 vs avg(300, 400)
+	expected := []float64{2, 3.2, 1000.0 / 350}
+	for i, point := range curve.Points {
+		if math.Abs(point.RVOL-expected[i]) > 1e-9 {
+			t.Errorf("point %d: expected RVOL %.3f, got %.3f", i, expected[i], point.RVOL)
+		}
+	}
+	if curve.Points[1].Minute != 9*60+35 || curve.Points[1].CumulativeVolume != 800 {
+		t.Errorf("unexpected second point: %+v", curve.Points[1])
+	}
+	if curve.RVOL != curve.Points[2].RVOL {
+		t.Errorf("expected the curve RVOL to be the latest point's, got %.3f", curve.RVOL)
+	}
+
+	// Without prior sessions there is nothing to compare against
+	if empty := buildRVOLCurve(bars[5:], at(6, 0, 0), nil, loc); len(empty.Points) != 0 || empty.RVOL != 0 {
+		t.Errorf("expected an empty curve, got %d points", len(empty.Points))
+	}
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// disabledSchedule is when a schedule was disabled and how many runs it skipped since
+type disabledSchedule struct {
+	at      time.Time
+	skipped int
+}
+
+// ScheduleControl enables and disables the background schedules and job types at runtime, so provider requests
+// can be stopped during incidents or maintenance without a restart. Disabled schedules are kept in memory and
+// run again after a restart. A nil control disables nothing.
+type ScheduleControl struct {
+	disabled map[string]*disabledSchedule
+	watchers []func()
+	mutex    sync.Mutex
+}
+
+// NewScheduleControl creates a new schedule control with every schedule enabled
+func NewScheduleControl() *ScheduleControl {
+	return &ScheduleControl{
+		disabled: make(map[string]*disabledSchedule),
+	}
+}
+
+// Enabled reports whether the named schedule runs
+func (sc *ScheduleControl) Enabled(name string) bool {
+	if sc == nil {
+		return true
+	}
+
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	return sc.disabled[name] == nil
+}
+
+// Skip reports whether the named schedule is disabled, counting the run it skips
+func (sc *ScheduleControl) Skip(name string) bool {
+	if sc == nil {
+		return false
+	}
+
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	disabled := sc.disabled[name]
+	if disabled == nil {
+		return false
+	}
+	disabled.skipped++
+	return true
+}
+
+// SetEnabled enables or disables the named schedule
+func (sc *ScheduleControl) SetEnabled(name string, enabled bool) (*models.ScheduleStatus, error) {
+	if !models.IsSchedule(name) {
+		return nil, fmt.Errorf("%w: unknown schedule %q", ErrValidation, name)
+	}
+
+	sc.mutex.Lock()
+	switch {
+	case enabled && sc.disabled[name] != nil:
+		delete(sc.disabled, name)
+		log.Printf("Enabled %s schedule", name)
+	case !enabled && sc.disabled[name] == nil:
+		sc.disabled[name] = &disabledSchedule{at: time.Now()}
+		log.Printf("Disabled %s schedule", name)
+	}
+	watchers := sc.watchers
+	sc.mutex.Unlock()
+
+	for _, watch := range watchers {
+		watch()
+	}
+	return sc.Status(name), nil
+}
+
+// SetJobsEnabled enables or disables every schedule and job type except collection
+func (sc *ScheduleControl) SetJobsEnabled(enabled bool) []*models.ScheduleStatus {
+	for _, schedule := range models.Schedules {
+		if schedule.Name != models.ScheduleCollection {
+			// Known schedules cannot fail
+			_, _ = sc.SetEnabled(schedule.Name, enabled)
+		}
+	}
+	return sc.List()
+}
+
+// Watch registers fn to be called after any schedule is enabled or disabled
+func (sc *ScheduleControl) Watch(fn func()) {
+	sc.mutex.Lock()
+	sc.watchers = append(sc.watchers, fn)
+	sc.mutex.Unlock()
+}
+
+// Status returns the named schedule's status, nil when it is unknown
+func (sc *ScheduleControl) Status(name string) *models.ScheduleStatus {
+	for _, status := range sc.List() {
+		if status.Name == name {
+			return status
+		}
+	}
+	return nil
+}
+
+// List returns the status of every schedule
+func (sc *ScheduleControl) List() []*models.ScheduleStatus {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	statuses := make([]*models.ScheduleStatus, len(models.Schedules))
+	for i, schedule := range models.Schedules {
+		status := &models.ScheduleStatus{
+			Name:        schedule.Name,
+			Description: schedule.Description,
+			Enabled:     true,
+		}
+		if disabled := sc.disabled[schedule.Name]; disabled != nil {
+			at := disabled.at
+			status.Enabled = false
+			status.DisabledAt = &at
+			status.SkippedRuns = disabled.skipped
+		}
+		statuses[i] = status
+	}
+	return statuses
+}
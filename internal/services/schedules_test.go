@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestScheduleControl tests disabling schedules, counting their skipped runs and rejecting unknown ones
+func TestScheduleControl(t *testing.T) {
+	var none *ScheduleControl
+	if !none.Enabled(models.ScheduleCollection) || none.Skip(models.ScheduleCollection) {
+		t.Error("expected a nil control to disable nothing")
+	}
+
+	sc := NewScheduleControl()
+	if _, err := sc.SetEnabled("unknown", false); !errors.Is(err, ErrValidation) {
+		t.Errorf("expected an unknown schedule to be invalid, got %v", err)
+	}
+
+	status, err := sc.SetEnabled(models.ScheduleNews, false)
+	if err != nil || status.Enabled || status.DisabledAt == nil {
+		t.Fatalf("expected news to be disabled, got %+v, %v", status, err)
+	}
+	if !sc.Skip(models.ScheduleNews) || !sc.Skip(models.ScheduleNews) || sc.Skip(models.ScheduleDigest) {
+		t.Error("expected only the disabled schedule to be skipped")
+	}
+	if status := sc.Status(models.ScheduleNews); status.SkippedRuns != 2 {
+		t.Errorf("expected 2 skipped runs, got %d", status.SkippedRuns)
+	}
+
+	// Pausing jobs leaves collection running
+	for _, status := range sc.SetJobsEnabled(false) {
+		if status.Enabled != (status.Name == models.ScheduleCollection) {
+			t.Errorf("unexpected %s enabled %v after pausing jobs", status.Name, status.Enabled)
+		}
+	}
+	for _, status := range sc.SetJobsEnabled(true) {
+		if !status.Enabled || status.SkippedRuns != 0 {
+			t.Errorf("expected %s to be enabled afresh, got %+v", status.Name, status)
+		}
+	}
+}
+
+// TestJobServiceDisabledJobType tests that jobs of a disabled type wait while other types run
+func TestJobServiceDisabledJobType(t *testing.T) {
+	sc := NewScheduleControl()
+	js := NewJobService(1)
+	js.SetScheduleControl(sc)
+	js.Start()
+	defer js.Stop(context.Background())
+
+	if _, err := sc.SetEnabled(models.ScheduleBackfill, false); err != nil {
+		t.Fatalf("SetEnabled failed: %v", err)
+	}
+
+	task := func(ctx context.Context, item string) (interface{}, error) { return item, nil }
+	backfill, err := js.Submit(models.JobTypeBackfill, []string{"AAPL"}, nil, task)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	scan, err := js.Submit(models.JobTypePatternScan, []string{"MSFT"}, nil, task)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	if job := waitForJob(t, js, scan.ID); job.Status != models.JobStatusCompleted {
+		t.Errorf("expected the pattern scan to complete, got %s", job.Status)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if job, _ := js.Get(backfill.ID); job.Status != models.JobStatusQueued {
+		t.Errorf("expected the disabled backfill to stay queued, got %s", job.Status)
+	}
+
+	if _, err := sc.SetEnabled(models.ScheduleBackfill, true); err != nil {
+		t.Fatalf("SetEnabled failed: %v", err)
+	}
+	if job := waitForJob(t, js, backfill.ID); job.Status != models.JobStatusCompleted {
+		t.Errorf("expected the backfill to complete once enabled, got %s", job.Status)
+	}
+}
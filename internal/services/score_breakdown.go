@@ -0,0 +1,31 @@
+package services
+
+import (
+	"market-watch-go/internal/models"
+)
+
+// checklistBreakdown explains the quality score a checklist adds up to: each item is worth its points scaled by
+// its category weight, or its calibrated points when a calibrated version is active
+func (sds *SetupDetectionService) checklistBreakdown(checklist *models.SetupChecklist, version *models.ScoringWeightVersion) *models.ScoreBreakdown {
+	weights := []float64{sds.config.PriceActionWeight, sds.config.VolumeWeight, sds.config.TechnicalWeight, sds.config.RiskRewardWeight}
+	items := checklist.Items()
+
+	breakdown := &models.ScoreBreakdown{Items: make([]*models.ScoreBreakdownItem, 0, len(models.ChecklistItemKeys))}
+	for i, key := range models.ChecklistItemKeys {
+		category := models.ChecklistCategories[i/5]
+		item := items[key]
+
+		points, maxPoints := item.Points*weights[i/5]/100, item.MaxPoints*weights[i/5]/100
+		if version != nil {
+			points, maxPoints = version.Points(key), version.Points(key)
+		}
+
+		entry := models.ChecklistBreakdownItem(category, key, item, points, maxPoints)
+		breakdown.MaxScore += entry.MaxPoints
+		if entry.Passed {
+			breakdown.Score += entry.Points
+		}
+		breakdown.Items = append(breakdown.Items, entry)
+	}
+	return breakdown
+}
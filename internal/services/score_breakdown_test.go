@@ -0,0 +1,91 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestSetupScoreBreakdown tests that the breakdown items add up to the quality score and explain failed items
+func TestSetupScoreBreakdown(t *testing.T) {
+	SetClock(NewSimulatedClock(time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)))
+	t.Cleanup(func() { SetClock(nil) })
+
+	sds := NewSetupDetectionService(nil, nil, nil)
+	setup := &models.TradingSetup{
+		Symbol: "TEST", Direction: "bullish", CurrentPrice: 101, EntryPrice: 101, StopLoss: 99, Target1: 105,
+		KeyLevel: &models.SupportResistanceLevel{Level: 100, Touches: 4, AvgBouncePercent: 1.2, FirstTouch: clockNow().AddDate(0, 0, -10)},
+		KeyZone:  &models.SRZone{ZoneStrength: 50, Touches: 4},
+	}
+	setup.RiskRewardRatio = setup.CalculateRiskRewardRatio()
+	indicators := &models.TechnicalIndicators{VolumeRatio: 1.3, VWAP: 100, RSI14: 55, MACDHistogram: 0.1, ATR14: 2}
+
+	if err := sds.scoreSetup(setup, indicators); err != nil {
+		t.Fatalf("scoreSetup failed: %v", err)
+	}
+	breakdown := setup.ScoreBreakdown
+	if breakdown == nil || len(breakdown.Items) != len(models.ChecklistItemKeys) || breakdown.MaxScore != 25 {
+		t.Fatalf("expected every checklist item out of 25 points, got %+v", breakdown)
+	}
+
+	byKey := make(map[string]*models.ScoreBreakdownItem)
+	total := 0.0
+	for _, item := range breakdown.Items {
+		byKey[item.Key] = item
+		total += item.Points
+	}
+	for _, adjustment := range breakdown.Adjustments {
+		total += adjustment.Points
+	}
+	if breakdown.Score != setup.QualityScore || total != setup.QualityScore {
+		t.Errorf("expected the items and adjustments to add up to %.2f, got %.2f of %.2f", setup.QualityScore, total, breakdown.Score)
+	}
+
+	if item := byKey["min_level_touches"]; !item.Passed || item.Points != 1.25 || item.Category != "price_action" {
+		t.Errorf("unexpected level touches item: %+v", item)
+	}
+	if item := byKey["bounce_strength"]; item.Passed || item.Points != 0 || item.Reason != "1.20% average bounce does not meet at least 2.00%" {
+		t.Errorf("unexpected bounce strength item: %+v", item)
+	}
+	if item := byKey["volume_spike"]; item.Passed || item.Value != "1.30x average volume" || item.Threshold != "at least 1.50x" {
+		t.Errorf("unexpected volume spike item: %+v", item)
+	}
+	if item := byKey["rejection_candle"]; item.Passed || item.Reason != "not checked automatically" {
+		t.Errorf("unexpected rejection candle item: %+v", item)
+	}
+	if len(breakdown.Adjustments) != 1 || breakdown.Adjustments[0].Key != "zone_strength" || breakdown.Adjustments[0].Points != 5 {
+		t.Errorf("expected a 5 point zone strength adjustment, got %+v", breakdown.Adjustments)
+	}
+
+	// An earnings penalty is listed as a negative adjustment
+	earnings := &models.CalendarEvent{Symbol: "TEST", EventType: models.CalendarEventEarnings, EventDate: clockNow()}
+	setup.ExpiresAt = clockNow().Add(24 * time.Hour)
+	sds.applyEventRisk(setup, []*models.CalendarEvent{earnings})
+	last := breakdown.Adjustments[len(breakdown.Adjustments)-1]
+	if last.Key != "earnings_penalty" || last.Points != -setup.EventPenalty || breakdown.Score != setup.QualityScore {
+		t.Errorf("expected the earnings penalty adjustment, got %+v and score %.2f", last, breakdown.Score)
+	}
+}
+
+// TestThesisScoreBreakdown tests that thesis components are worth their share of the completion percent
+func TestThesisScoreBreakdown(t *testing.T) {
+	thesis := &models.FlagThesis{}
+	thesis.InitializeFlagThesis(true)
+	thesis.StrongPole.IsCompleted = true
+	thesis.OrderlyChannel.Evidence = []string{"channel width 4.2%"}
+	thesis.CalculateCompletion()
+
+	breakdown := thesis.ScoreBreakdown
+	if breakdown == nil || breakdown.Score != thesis.CompletionPercent || breakdown.MaxScore != 100 {
+		t.Fatalf("expected the breakdown of the completion percent, got %+v", breakdown)
+	}
+	pole, channel := breakdown.Items[0], breakdown.Items[1]
+	if !pole.Passed || pole.Points != 15 || pole.Points != breakdown.Score {
+		t.Errorf("expected the pole to earn its 15 points, got %+v", pole)
+	}
+	if channel.Passed || channel.Points != 0 || channel.MaxPoints != 10 || !strings.Contains(channel.Value, "4.2%") {
+		t.Errorf("unexpected channel item: %+v", channel)
+	}
+}
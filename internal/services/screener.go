@@ -0,0 +1,481 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// Screener defaults
+const (
+	screenerLookbackDays    = 60 // Calendar days of daily bars loaded for RSI, volume and S/R levels
+	screenerMinBars         = 20
+	screenerRSIPeriod       = 14
+	screenerVolumePeriod    = 21 // Today plus 20 days of average volume
+	screenerPivotStrength   = 2  // Bars on each side of a swing high/low
+	screenerLevelTolerance  = 1.5
+	defaultScreenerLimit    = 100
+	defaultMinLevelTouches  = 2
+	screenerRequestInterval = 250 * time.Millisecond
+)
+
+// ScreenerService scans a broad universe of stocks using Polygon grouped daily bars
+type ScreenerService struct {
+	cfg       *config.Config
+	db        *database.Database
+	taService *TechnicalAnalysisService
+	client    *http.Client
+	dayCache  map[string][]*models.PriceData // Completed trading days keyed by date, bars carry the ticker in Symbol
+	mutex     sync.Mutex
+}
+
+// polygonGroupedResponse represents the response from the Polygon grouped daily endpoint
+type polygonGroupedResponse struct {
+	Status       string                `json:"status"`
+	ResultsCount int                   `json:"resultsCount"`
+	Results      []polygonGroupedDaily `json:"results"`
+}
+
+// polygonGroupedDaily represents one ticker's daily bar
+type polygonGroupedDaily struct {
+	Ticker    string  `json:"T"`
+	Volume    float64 `json:"v"`
+	Open      float64 `json:"o"`
+	Close     float64 `json:"c"`
+	High      float64 `json:"h"`
+	Low       float64 `json:"l"`
+	Timestamp int64   `json:"t"`
+}
+
+// screenerLevel represents a support/resistance level derived from daily swing points
+type screenerLevel struct {
+	price   float64
+	touches int
+}
+
+// NewScreenerService creates a new screener service
+func NewScreenerService(cfg *config.Config, db *database.Database, taService *TechnicalAnalysisService) *ScreenerService {
+	timeout := cfg.Polygon.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	return &ScreenerService{
+		cfg:       cfg,
+		db:        db,
+		taService: taService,
+		client:    &http.Client{Timeout: timeout},
+		dayCache:  make(map[string][]*models.PriceData),
+	}
+}
+
+// RunSavedScreen runs a saved screen by ID
+func (ss *ScreenerService) RunSavedScreen(id int64) (*models.ScreenerRun, error) {
+	screen, err := ss.db.GetSavedScreenByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if screen == nil {
+		return nil, fmt.Errorf("saved screen %w", database.ErrNotFound)
+	}
+
+	run, err := ss.Run(&screen.Criteria)
+	if err != nil {
+		return nil, err
+	}
+	run.ScreenID = &screen.ID
+	return run, nil
+}
+
+// Run scans the criteria's universe and returns the matching symbols, strongest volume first
+func (ss *ScreenerService) Run(criteria *models.ScreenCriteria) (*models.ScreenerRun, error) {
+	if err := criteria.Validate(); err != nil {
+		return nil, err
+	}
+
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+
+	series, asOf, err := ss.loadDailySeries(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	watchlist, err := ss.watchlistSymbols()
+	if err != nil {
+		return nil, err
+	}
+
+	universe := ss.universe(criteria, series, watchlist)
+
+	run := &models.ScreenerRun{
+		Criteria: *criteria,
+		RunAt:    time.Now(),
+		AsOf:     asOf,
+		Scanned:  len(universe),
+		Results:  make([]*models.ScreenerResult, 0),
+	}
+
+	for _, symbol := range universe {
+		result, ok := ss.evaluate(symbol, series[symbol], criteria)
+		if !ok {
+			continue
+		}
+		result.InWatchlist = watchlist[symbol]
+		run.Results = append(run.Results, result)
+	}
+
+	sort.Slice(run.Results, func(i, j int) bool {
+		return run.Results[i].VolumeRatio > run.Results[j].VolumeRatio
+	})
+
+	limit := criteria.Limit
+	if limit <= 0 {
+		limit = defaultScreenerLimit
+	}
+	if len(run.Results) > limit {
+		run.Results = run.Results[:limit]
+	}
+	run.Count = len(run.Results)
+
+	log.Printf("Screener scanned %d symbols, %d matches", run.Scanned, run.Count)
+	return run, nil
+}
+
+// AddToWatchlist adds a screener result to a watchlist strategy and starts collecting its data
+func (ss *ScreenerService) AddToWatchlist(symbol string, strategyID int) (*models.Stock, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is required")
+	}
+
+	exists, err := ss.db.StrategyExists(strategyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate strategy: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("strategy with ID %d does not exist", strategyID)
+	}
+
+	stock, err := ss.db.AddStock(models.Stock{
+		Symbol:     symbol,
+		Strategies: []models.Strategy{{ID: strategyID}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add stock: %w", err)
+	}
+
+	if err := ss.db.AddWatchedSymbol(symbol, stock.Name); err != nil {
+		return nil, err
+	}
+
+	return stock, nil
+}
+
+// universe returns the symbols a screen covers after the price and liquidity filters
+func (ss *ScreenerService) universe(criteria *models.ScreenCriteria, series map[string][]*models.PriceData, watchlist map[string]bool) []string {
+	var candidates []string
+	switch {
+	case len(criteria.Symbols) > 0:
+		for _, symbol := range criteria.Symbols {
+			candidates = append(candidates, strings.ToUpper(symbol))
+		}
+	case criteria.Universe == models.ScreenUniverseWatchlist:
+		for symbol := range watchlist {
+			candidates = append(candidates, symbol)
+		}
+	default:
+		for symbol := range series {
+			candidates = append(candidates, symbol)
+		}
+	}
+	sort.Strings(candidates)
+
+	universe := make([]string, 0, len(candidates))
+	for _, symbol := range candidates {
+		bars := series[symbol]
+		if len(bars) < screenerMinBars {
+			continue
+		}
+		price := bars[len(bars)-1].Close
+		if criteria.MinPrice > 0 && price < criteria.MinPrice {
+			continue
+		}
+		if criteria.MaxPrice > 0 && price > criteria.MaxPrice {
+			continue
+		}
+		if criteria.MinAvgVolume > 0 && averageVolume(bars, screenerVolumePeriod-1) < criteria.MinAvgVolume {
+			continue
+		}
+		universe = append(universe, symbol)
+	}
+
+	return universe
+}
+
+// evaluate applies the screen criteria to one symbol's daily bars
+func (ss *ScreenerService) evaluate(symbol string, bars []*models.PriceData, criteria *models.ScreenCriteria) (*models.ScreenerResult, bool) {
+	if len(bars) < screenerMinBars {
+		return nil, false
+	}
+
+	closes := make([]float64, len(bars))
+	volumes := make([]int64, len(bars))
+	for i, bar := range bars {
+		closes[i] = bar.Close
+		volumes[i] = bar.Volume
+	}
+
+	last := bars[len(bars)-1]
+	prev := bars[len(bars)-2]
+	result := &models.ScreenerResult{
+		Symbol:      symbol,
+		Price:       last.Close,
+		Volume:      last.Volume,
+		AvgVolume:   averageVolume(bars, screenerVolumePeriod-1),
+		VolumeRatio: ss.taService.calculateVolumeRatio(volumes, screenerVolumePeriod),
+		RSI:         ss.taService.calculateRSI(closes, screenerRSIPeriod),
+		Matches:     []string{},
+	}
+	if prev.Close > 0 {
+		result.ChangePercent = (last.Close - prev.Close) / prev.Close * 100
+	}
+
+	checks, passed := 0, 0
+	check := func(ok bool, match string) {
+		checks++
+		if ok {
+			passed++
+			result.Matches = append(result.Matches, match)
+		}
+	}
+
+	if criteria.RSIBelow > 0 {
+		check(result.RSI < criteria.RSIBelow, fmt.Sprintf("RSI %.1f below %.0f", result.RSI, criteria.RSIBelow))
+	}
+	if criteria.RSIAbove > 0 {
+		check(result.RSI > criteria.RSIAbove, fmt.Sprintf("RSI %.1f above %.0f", result.RSI, criteria.RSIAbove))
+	}
+	if criteria.MinVolumeRatio > 0 {
+		check(result.VolumeRatio >= criteria.MinVolumeRatio, fmt.Sprintf("Volume %.1fx average", result.VolumeRatio))
+	}
+	if criteria.NearLevelPercent > 0 {
+		minTouches := criteria.MinLevelTouches
+		if minTouches <= 0 {
+			minTouches = defaultMinLevelTouches
+		}
+
+		near := false
+		if level, ok := nearestStrongLevel(bars[:len(bars)-1], last.Close, minTouches); ok {
+			result.NearestLevel = level.price
+			result.LevelTouches = level.touches
+			result.LevelDistancePercent = math.Abs(last.Close-level.price) / level.price * 100
+			result.LevelType = "support"
+			if level.price > last.Close {
+				result.LevelType = "resistance"
+			}
+			near = result.LevelDistancePercent <= criteria.NearLevelPercent
+		}
+		check(near, fmt.Sprintf("%.1f%% from %s %.2f (%d touches)", result.LevelDistancePercent, result.LevelType, result.NearestLevel, result.LevelTouches))
+	}
+
+	if criteria.MatchAny {
+		return result, passed > 0
+	}
+	return result, passed == checks
+}
+
+// nearestStrongLevel finds the level closest to price among swing highs/lows touched at least minTouches times
+func nearestStrongLevel(bars []*models.PriceData, price float64, minTouches int) (screenerLevel, bool) {
+	var pivots []float64
+	for i := screenerPivotStrength; i < len(bars)-screenerPivotStrength; i++ {
+		isHigh, isLow := true, true
+		for j := i - screenerPivotStrength; j <= i+screenerPivotStrength; j++ {
+			if j == i {
+				continue
+			}
+			if bars[j].High >= bars[i].High {
+				isHigh = false
+			}
+			if bars[j].Low <= bars[i].Low {
+				isLow = false
+			}
+		}
+		if isHigh {
+			pivots = append(pivots, bars[i].High)
+		}
+		if isLow {
+			pivots = append(pivots, bars[i].Low)
+		}
+	}
+
+	// Cluster pivots within the tolerance into levels
+	sort.Float64s(pivots)
+	var levels []screenerLevel
+	for _, pivot := range pivots {
+		if n := len(levels); n > 0 {
+			level := &levels[n-1]
+			if (pivot-level.price)/level.price*100 <= screenerLevelTolerance {
+				level.price = (level.price*float64(level.touches) + pivot) / float64(level.touches+1)
+				level.touches++
+				continue
+			}
+		}
+		levels = append(levels, screenerLevel{price: pivot, touches: 1})
+	}
+
+	best, found := screenerLevel{}, false
+	for _, level := range levels {
+		if level.touches < minTouches {
+			continue
+		}
+		if !found || math.Abs(level.price-price) < math.Abs(best.price-price) {
+			best, found = level, true
+		}
+	}
+
+	return best, found
+}
+
+// averageVolume returns the average volume of the period bars before the latest bar
+func averageVolume(bars []*models.PriceData, period int) int64 {
+	end := len(bars) - 1
+	start := end - period
+	if start < 0 {
+		start = 0
+	}
+	if end <= start {
+		return 0
+	}
+
+	total := int64(0)
+	for _, bar := range bars[start:end] {
+		total += bar.Volume
+	}
+	return total / int64(end-start)
+}
+
+// watchlistSymbols returns the symbols on the watchlist or being collected
+func (ss *ScreenerService) watchlistSymbols() (map[string]bool, error) {
+	symbols := make(map[string]bool)
+
+	watched, err := ss.db.GetWatchedSymbols()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watched symbols: %w", err)
+	}
+	for _, symbol := range watched {
+		symbols[symbol] = true
+	}
+
+	stocks, err := ss.db.GetStocks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watchlist stocks: %w", err)
+	}
+	for _, stock := range stocks {
+		symbols[strings.ToUpper(stock.Symbol)] = true
+	}
+
+	return symbols, nil
+}
+
+// loadDailySeries builds per-symbol daily bars, oldest first, over the lookback window.
+// Completed days are cached since past grouped bars never change.
+func (ss *ScreenerService) loadDailySeries(now time.Time) (map[string][]*models.PriceData, time.Time, error) {
+	series := make(map[string][]*models.PriceData)
+	today := now.Format("2006-01-02")
+	var asOf time.Time
+
+	for day := now.AddDate(0, 0, -screenerLookbackDays); !day.After(now); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+
+		date := day.Format("2006-01-02")
+		bars, cached := ss.dayCache[date]
+		if !cached {
+			fetched, err := ss.fetchGroupedDaily(date)
+			if err != nil {
+				if len(series) == 0 && date != today {
+					return nil, time.Time{}, err
+				}
+				log.Printf("Screener skipped %s: %v", date, err)
+				continue
+			}
+			bars = fetched
+			if date != today {
+				ss.dayCache[date] = bars
+			}
+			time.Sleep(screenerRequestInterval)
+		}
+
+		for _, bar := range bars {
+			series[bar.Symbol] = append(series[bar.Symbol], bar)
+		}
+		if len(bars) > 0 {
+			asOf = day
+		}
+	}
+
+	// Drop cached days that have left the window
+	oldest := now.AddDate(0, 0, -screenerLookbackDays).Format("2006-01-02")
+	for date := range ss.dayCache {
+		if date < oldest {
+			delete(ss.dayCache, date)
+		}
+	}
+
+	return series, asOf, nil
+}
+
+// fetchGroupedDaily fetches every US stock's daily bar for a date (empty on market holidays)
+func (ss *ScreenerService) fetchGroupedDaily(date string) ([]*models.PriceData, error) {
+	requestURL := fmt.Sprintf("%s/v2/aggs/grouped/locale/us/market/stocks/%s?adjusted=true&apiKey=%s",
+		ss.cfg.Polygon.BaseURL, date, ss.cfg.Polygon.APIKey)
+
+	req, err := http.NewRequest("GET", requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := ss.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var grouped polygonGroupedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&grouped); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	bars := make([]*models.PriceData, 0, len(grouped.Results))
+	for _, result := range grouped.Results {
+		bars = append(bars, &models.PriceData{
+			Symbol:    result.Ticker,
+			Timestamp: time.UnixMilli(result.Timestamp),
+			Open:      result.Open,
+			High:      result.High,
+			Low:       result.Low,
+			Close:     result.Close,
+			Volume:    int64(result.Volume),
+		})
+	}
+
+	return bars, nil
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestScreenerEvaluate tests the RSI, volume spike and S/R proximity criteria on daily bars
+func TestScreenerEvaluate(t *testing.T) {
+	ss := &ScreenerService{taService: NewTechnicalAnalysisService(nil, nil)}
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	// Price oscillates between support near 100 and resistance near 110, then sells off to support on heavy volume
+	closes := []float64{104, 107, 110, 107, 104, 101, 100, 103, 106, 109, 110, 108, 105, 102, 100, 102, 105, 108, 110, 108, 106, 104, 102, 100.5}
+	bars := make([]*models.PriceData, len(closes))
+	for i, close := range closes {
+		bars[i] = &models.PriceData{Timestamp: start.AddDate(0, 0, i), High: close + 0.5, Low: close - 0.5, Close: close, Volume: 1000}
+	}
+	bars[len(bars)-1].Volume = 3500
+
+	criteria := &models.ScreenCriteria{RSIBelow: 40, MinVolumeRatio: 3, NearLevelPercent: 1.5}
+	result, ok := ss.evaluate("TEST", bars, criteria)
+	if !ok {
+		t.Fatalf("expected all criteria to match, got %v (RSI %.1f, volume %.1fx, level %.2f)", result.Matches, result.RSI, result.VolumeRatio, result.NearestLevel)
+	}
+	if result.LevelType != "support" || result.LevelTouches < 2 {
+		t.Errorf("expected a support level with at least 2 touches, got %s with %d", result.LevelType, result.LevelTouches)
+	}
+
+	// Overbought fails, so all-match rejects while any-match accepts
+	criteria.RSIAbove = 70
+	if _, ok := ss.evaluate("TEST", bars, criteria); ok {
+		t.Error("expected match_all screen with a failing criterion to reject")
+	}
+	criteria.MatchAny = true
+	if result, ok := ss.evaluate("TEST", bars, criteria); !ok || len(result.Matches) != 3 {
+		t.Errorf("expected match_any screen to accept with 3 matches, got %v", result.Matches)
+	}
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// Relative strength defaults
+const defaultBenchmark = "SPY"
+
+var defaultStrengthWindows = []int{5, 20, 60}
+
+// SectorStrengthService ranks watchlist sectors by relative strength against a benchmark
+type SectorStrengthService struct {
+	db        *database.Database
+	benchmark string
+	windows   []int
+}
+
+// NewSectorStrengthService creates a new sector strength service
+func NewSectorStrengthService(cfg *config.Config, db *database.Database) *SectorStrengthService {
+	benchmark := strings.ToUpper(cfg.Analytics.Benchmark)
+	if benchmark == "" {
+		benchmark = defaultBenchmark
+	}
+
+	windows := cfg.Analytics.Windows
+	if len(windows) == 0 {
+		windows = defaultStrengthWindows
+	}
+
+	return &SectorStrengthService{
+		db:        db,
+		benchmark: benchmark,
+		windows:   windows,
+	}
+}
+
+// Analyze measures each watchlist stock against the benchmark over the windows (in trading days) and
+// ranks sectors by the average relative strength of their stocks. Empty arguments use the configured defaults.
+func (ss *SectorStrengthService) Analyze(windows []int, benchmark string) (*models.SectorStrengthReport, error) {
+	if len(windows) == 0 {
+		windows = ss.windows
+	}
+	benchmark = strings.ToUpper(benchmark)
+	if benchmark == "" {
+		benchmark = ss.benchmark
+	}
+
+	maxWindow := 0
+	for _, window := range windows {
+		if window < 1 || window > models.MaxRelativeStrengthWindow {
+			return nil, fmt.Errorf("windows must be between 1 and %d trading days", models.MaxRelativeStrengthWindow)
+		}
+		maxWindow = max(maxWindow, window)
+	}
+
+	now := clockNow()
+	// Calendar days covering the longest window, allowing for weekends and holidays
+	from := now.AddDate(0, 0, -(maxWindow*7/5 + 10))
+
+	benchmarkBars, err := ss.db.GetPriceDataRangeTimeframe(benchmark, from, now, models.Timeframe1d)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s daily bars: %w", benchmark, err)
+	}
+	if len(benchmarkBars) < 2 {
+		return nil, fmt.Errorf("no daily history for benchmark %s yet; backfill it with POST /api/jobs/backfill", benchmark)
+	}
+
+	stocks, err := ss.db.GetStocks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watchlist stocks: %w", err)
+	}
+	refs, err := ss.db.GetSymbolReferences()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.SectorStrengthReport{
+		Benchmark:        benchmark,
+		Windows:          windows,
+		BenchmarkReturns: make(map[int]float64),
+		GeneratedAt:      now,
+	}
+	for _, window := range windows {
+		if change, ok := windowReturn(benchmarkBars, benchmarkBars, window); ok {
+			report.BenchmarkReturns[window] = change
+		}
+	}
+
+	var strengths []*models.SymbolStrength
+	for _, stock := range stocks {
+		if strings.EqualFold(stock.Symbol, benchmark) {
+			continue
+		}
+
+		bars, err := ss.db.GetPriceDataRangeTimeframe(stock.Symbol, from, now, models.Timeframe1d)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s daily bars: %w", stock.Symbol, err)
+		}
+
+		sector := ""
+		if ref := refs[stock.Symbol]; ref != nil {
+			sector = ref.Sector
+		}
+		strength := symbolStrength(stock.Symbol, sector, bars, benchmarkBars, report.BenchmarkReturns, windows)
+		if strength == nil {
+			report.Skipped = append(report.Skipped, stock.Symbol)
+			continue
+		}
+		strengths = append(strengths, strength)
+	}
+
+	report.Sectors = rankSectorStrength(strengths, windows)
+	return report, nil
+}
+
+// symbolStrength computes a stock's returns and relative strength over the windows the benchmark
+// covers, or nil if the stock has no window with enough history
+func symbolStrength(symbol, sector string, bars, benchmarkBars []*models.PriceData, benchmarkReturns map[int]float64, windows []int) *models.SymbolStrength {
+	if sector == "" {
+		sector = "Unknown"
+	}
+	strength := &models.SymbolStrength{
+		Symbol:           symbol,
+		Sector:           sector,
+		Returns:          make(map[int]float64),
+		RelativeStrength: make(map[int]float64),
+	}
+
+	total := 0.0
+	for _, window := range windows {
+		benchmarkReturn, ok := benchmarkReturns[window]
+		if !ok {
+			continue
+		}
+		change, ok := windowReturn(bars, benchmarkBars, window)
+		if !ok {
+			continue
+		}
+		strength.Returns[window] = change
+		strength.RelativeStrength[window] = change - benchmarkReturn
+		total += change - benchmarkReturn
+	}
+
+	if len(strength.RelativeStrength) == 0 {
+		return nil
+	}
+	strength.Score = total / float64(len(strength.RelativeStrength))
+	return strength
+}
+
+// windowReturn returns the percent change of bars from the benchmark session window days ago to the
+// latest bar. Anchoring on benchmark sessions keeps symbols with missing days aligned with the benchmark.
+func windowReturn(bars, benchmarkBars []*models.PriceData, window int) (float64, bool) {
+	if len(bars) == 0 || window >= len(benchmarkBars) {
+		return 0, false
+	}
+	start := benchmarkBars[len(benchmarkBars)-1-window].Timestamp
+
+	// Latest close at or before the start session
+	i := sort.Search(len(bars), func(i int) bool { return bars[i].Timestamp.After(start) }) - 1
+	if i < 0 || i == len(bars)-1 || bars[i].Close <= 0 {
+		return 0, false
+	}
+	return (bars[len(bars)-1].Close/bars[i].Close - 1) * 100, true
+}
+
+// rankSectorStrength groups stock strengths by sector, averages them per window and ranks the
+// sectors by their average across windows
+func rankSectorStrength(strengths []*models.SymbolStrength, windows []int) []*models.SectorStrength {
+	bySector := make(map[string]*models.SectorStrength)
+	var sectors []*models.SectorStrength
+	for _, strength := range strengths {
+		sector, ok := bySector[strength.Sector]
+		if !ok {
+			sector = &models.SectorStrength{Sector: strength.Sector, RelativeStrength: make(map[int]float64)}
+			bySector[strength.Sector] = sector
+			sectors = append(sectors, sector)
+		}
+		sector.Stocks = append(sector.Stocks, strength)
+	}
+
+	for _, sector := range sectors {
+		sort.SliceStable(sector.Stocks, func(i, j int) bool { return sector.Stocks[i].Score > sector.Stocks[j].Score })
+
+		total := 0.0
+		for _, window := range windows {
+			sum, count := 0.0, 0
+			for _, stock := range sector.Stocks {
+				if rs, ok := stock.RelativeStrength[window]; ok {
+					sum += rs
+					count++
+				}
+			}
+			if count > 0 {
+				sector.RelativeStrength[window] = sum / float64(count)
+				total += sector.RelativeStrength[window]
+			}
+		}
+		if len(sector.RelativeStrength) > 0 {
+			sector.Score = total / float64(len(sector.RelativeStrength))
+		}
+	}
+
+	sort.SliceStable(sectors, func(i, j int) bool { return sectors[i].Score > sectors[j].Score })
+	for i, sector := range sectors {
+		sector.Rank = i + 1
+	}
+	return sectors
+}
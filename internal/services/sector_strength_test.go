@@ -0,0 +1,71 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// dailyBars builds consecutive daily bars for the given closes, skipping the given day offsets
+func dailyBars(closes []float64, skip ...int) []*models.PriceData {
+	start := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	var bars []*models.PriceData
+	for i, close := range closes {
+		skipped := false
+		for _, s := range skip {
+			skipped = skipped || s == i
+		}
+		if !skipped {
+			bars = append(bars, &models.PriceData{Timestamp: start.AddDate(0, 0, i), Close: close})
+		}
+	}
+	return bars
+}
+
+// TestSectorStrengthRanking tests window returns anchored on benchmark sessions and sector ranking
+func TestSectorStrengthRanking(t *testing.T) {
+	benchmark := dailyBars([]float64{100, 100, 100, 101, 102, 105})
+	windows := []int{2, 5}
+	benchmarkReturns := map[int]float64{}
+	for _, window := range windows {
+		change, _ := windowReturn(benchmark, benchmark, window)
+		benchmarkReturns[window] = change
+	}
+	if math.Abs(benchmarkReturns[5]-5) > 1e-9 {
+		t.Fatalf("expected a 5%% benchmark return over 5 sessions, got %.4f", benchmarkReturns[5])
+	}
+
+	// The missing window start session falls back to the prior close: 48 -> 60 is +25%
+	gappy := symbolStrength("AMD", "Technology", dailyBars([]float64{40, 40, 48, 50, 55, 60}, 3), benchmark, benchmarkReturns, windows)
+	if gappy == nil || math.Abs(gappy.Returns[2]-25) > 1e-9 {
+		t.Fatalf("expected a 25%% two session return, got %+v", gappy)
+	}
+
+	strong := symbolStrength("NVDA", "Technology", dailyBars([]float64{100, 100, 100, 110, 120, 130}), benchmark, benchmarkReturns, windows)
+	weak := symbolStrength("XOM", "Energy", dailyBars([]float64{100, 100, 100, 99, 98, 97}), benchmark, benchmarkReturns, windows)
+	// A listing without a bar at or before a window start only counts with the windows it covers
+	newIPO := symbolStrength("ARM", "", dailyBars([]float64{0, 0, 0, 0, 50, 55}, 0, 1, 2, 3), benchmark, benchmarkReturns, windows)
+	if newIPO != nil {
+		t.Errorf("expected no strength without a bar at or before the window start, got %+v", newIPO)
+	}
+	recent := symbolStrength("ARM", "", dailyBars([]float64{0, 0, 0, 50, 50, 55}, 0, 1, 2), benchmark, benchmarkReturns, windows)
+	if recent == nil || recent.Sector != "Unknown" || len(recent.RelativeStrength) != 1 {
+		t.Fatalf("expected a single window for a recent listing, got %+v", recent)
+	}
+
+	sectors := rankSectorStrength([]*models.SymbolStrength{weak, gappy, recent, strong}, windows)
+	if len(sectors) != 3 {
+		t.Fatalf("expected 3 sectors, got %d", len(sectors))
+	}
+	if sectors[0].Sector != "Technology" || sectors[0].Rank != 1 || sectors[2].Sector != "Energy" {
+		t.Errorf("unexpected sector order: %s, %s, %s", sectors[0].Sector, sectors[1].Sector, sectors[2].Sector)
+	}
+	if sectors[0].Stocks[0].Symbol != "AMD" {
+		t.Errorf("expected AMD to lead Technology, got %s", sectors[0].Stocks[0].Symbol)
+	}
+	if want := (gappy.RelativeStrength[5] + strong.RelativeStrength[5]) / 2; math.Abs(sectors[0].RelativeStrength[5]-want) > 1e-9 {
+		t.Errorf("expected sector RS %.4f, got %.4f", want, sectors[0].RelativeStrength[5])
+	}
+}
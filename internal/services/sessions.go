@@ -0,0 +1,82 @@
+package services
+
+import (
+	"time"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// TagSessions marks each bar with the market session it was traded in, keeping sessions already set
+func (mc *MarketCalendar) TagSessions(bars []*models.PriceData) {
+	for _, bar := range bars {
+		if bar.Session == "" {
+			bar.Session = mc.SymbolSession(bar.Symbol, bar.Timestamp)
+		}
+	}
+}
+
+// BarSession returns the session a bar was traded in, classifying bars stored before sessions were recorded
+func (mc *MarketCalendar) BarSession(bar *models.PriceData) models.MarketSession {
+	if bar.Session != "" {
+		return bar.Session
+	}
+	return mc.SymbolSession(bar.Symbol, bar.Timestamp)
+}
+
+// RegularSessionBars returns the bars traded in the regular session
+func (mc *MarketCalendar) RegularSessionBars(bars []*models.PriceData) []*models.PriceData {
+	regular := make([]*models.PriceData, 0, len(bars))
+	for _, bar := range bars {
+		if mc.BarSession(bar) == models.SessionRegular {
+			regular = append(regular, bar)
+		}
+	}
+	return regular
+}
+
+// regularSessionReader reads bars through another reader, leaving out pre-market, post-market and overnight
+// 1-minute bars before they are rolled up. Compacted candles only exist whole, so they are kept as they are.
+type regularSessionReader struct {
+	prices   PriceReader
+	calendar *MarketCalendar
+}
+
+// NewRegularSessionReader wraps a price reader so only regular session bars are read
+func NewRegularSessionReader(prices PriceReader, calendar *MarketCalendar) PriceReader {
+	return &regularSessionReader{prices: prices, calendar: calendar}
+}
+
+// GetPriceData returns the filter's candles rolled up from regular session bars only
+func (r *regularSessionReader) GetPriceData(filter *models.PriceDataFilter) ([]*models.PriceData, error) {
+	bars, err := r.prices.GetPriceData(&models.PriceDataFilter{Symbol: filter.Symbol, From: filter.From, To: filter.To})
+	if err != nil {
+		return nil, err
+	}
+	candles := database.AggregatePriceData(r.calendar.RegularSessionBars(bars), filter.Timeframe)
+
+	// Compacted candles, all older than the first 1-minute bar, come from the wrapped reader
+	if filter.Timeframe.IsAggregated() {
+		to := filter.To
+		if len(bars) > 0 {
+			to = bars[0].Timestamp.Add(-time.Nanosecond)
+		}
+		compacted, err := r.prices.GetPriceData(&models.PriceDataFilter{Symbol: filter.Symbol, From: filter.From, To: to, Timeframe: filter.Timeframe})
+		if err != nil {
+			return nil, err
+		}
+		candles = append(compacted, candles...)
+	}
+
+	return database.PaginatePriceData(candles, filter.Limit, filter.Offset), nil
+}
+
+// GetPriceDataRangeTimeframe returns a symbol's regular session candles within a time range
+func (r *regularSessionReader) GetPriceDataRangeTimeframe(symbol string, startTime, endTime time.Time, timeframe models.Timeframe) ([]*models.PriceData, error) {
+	return r.GetPriceData(&models.PriceDataFilter{
+		Symbol:    symbol,
+		From:      startTime,
+		To:        endTime,
+		Timeframe: timeframe,
+	})
+}
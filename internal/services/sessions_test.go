@@ -0,0 +1,88 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// sessionTestBars returns one AAPL bar every 30 minutes from 08:00 to 17:30 exchange time on a trading day,
+// closing at 100, 101, ...
+func sessionTestBars(mc *MarketCalendar) []*models.PriceData {
+	start := time.Date(2025, time.March, 4, 8, 0, 0, 0, mc.Location())
+	var bars []*models.PriceData
+	for i := 0; i < 20; i++ {
+		close := 100 + float64(i)
+		bars = append(bars, &models.PriceData{Symbol: "AAPL", Timestamp: start.Add(time.Duration(i) * 30 * time.Minute),
+			Open: close, High: close, Low: close, Close: close, Volume: 10})
+	}
+	return bars
+}
+
+// TestCollectorPrepareBars tests that collected bars are tagged with their session and extended-hours bars are
+// only dropped when collection is limited to the regular session
+func TestCollectorPrepareBars(t *testing.T) {
+	cfg := &config.Config{}
+	cs := NewCollectorService(nil, nil, cfg)
+	cs.SetMarketCalendar(NewMarketCalendar(cfg.MarketHours))
+
+	bars := cs.prepareBars(sessionTestBars(cs.calendar))
+	if len(bars) != 20 {
+		t.Fatalf("expected every bar to be kept, got %d", len(bars))
+	}
+	expected := map[int]models.MarketSession{0: models.SessionPreMarket, 3: models.SessionRegular, 15: models.SessionRegular, 16: models.SessionPostMarket}
+	for i, session := range expected {
+		if bars[i].Session != session {
+			t.Errorf("bar %d at %s: expected %s, got %s", i, bars[i].Timestamp.Format("15:04"), session, bars[i].Session)
+		}
+	}
+
+	cfg.Collection.RegularHoursOnly = true
+	regular := cs.prepareBars(sessionTestBars(cs.calendar))
+	if len(regular) != 13 || regular[0].Close != 103 || regular[12].Close != 115 {
+		t.Errorf("expected the 13 bars from 09:30 to 15:30, got %d", len(regular))
+	}
+
+	volume := []*models.VolumeData{{Timestamp: bars[0].Timestamp}, {Timestamp: bars[3].Timestamp}}
+	if kept := cs.prepareVolumeBars(volume); len(kept) != 1 || !kept[0].Timestamp.Equal(bars[3].Timestamp) {
+		t.Errorf("expected only the regular session volume bar, got %+v", kept)
+	}
+}
+
+// TestRegularSessionReader tests that extended-hours bars, stored with or without a session, are left out
+// before candles are rolled up
+func TestRegularSessionReader(t *testing.T) {
+	cfg := &config.Config{}
+	db := newTestDB(t)
+
+	mc := NewMarketCalendar(cfg.MarketHours)
+	bars := sessionTestBars(mc)
+	mc.TagSessions(bars[:10])
+	if err := db.InsertPriceDataBatch(bars); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+
+	stored, err := db.GetPriceDataRange("AAPL", bars[0].Timestamp, bars[19].Timestamp)
+	if err != nil || len(stored) != 20 {
+		t.Fatalf("expected 20 stored bars, got %d (%v)", len(stored), err)
+	}
+	if stored[0].Session != models.SessionPreMarket || stored[19].Session != "" {
+		t.Errorf("expected the recorded sessions to round trip, got %q and %q", stored[0].Session, stored[19].Session)
+	}
+
+	reader := NewRegularSessionReader(db, mc)
+	minutes, err := reader.GetPriceData(&models.PriceDataFilter{Symbol: "AAPL", From: bars[0].Timestamp, To: bars[19].Timestamp})
+	if err != nil || len(minutes) != 13 {
+		t.Fatalf("expected the 13 regular session bars, got %d (%v)", len(minutes), err)
+	}
+
+	hourly, err := reader.GetPriceDataRangeTimeframe("AAPL", bars[0].Timestamp, bars[19].Timestamp, models.Timeframe1h)
+	if err != nil {
+		t.Fatalf("GetPriceDataRangeTimeframe failed: %v", err)
+	}
+	if len(hourly) != 7 || hourly[0].Open != 103 || hourly[0].Session != models.SessionRegular || hourly[6].Close != 115 {
+		t.Errorf("expected 7 hourly candles from 09:30 to 15:30, got %d: %+v", len(hourly), hourly)
+	}
+}
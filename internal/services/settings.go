@@ -0,0 +1,202 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// ErrInvalidSettings is returned when an update is malformed or fails validation
+var ErrInvalidSettings = fmt.Errorf("%w: invalid settings", ErrValidation)
+
+// SettingsService lets setup scoring, pattern detection, S/R detection and display settings be tuned at runtime.
+// Saved sections override the built-in defaults and config file on every start.
+type SettingsService struct {
+	db              *database.Database
+	setupService    *SetupDetectionService
+	srService       *SupportResistanceService
+	hsService       *HeadShouldersDetectionService
+	fwService       *FallingWedgeDetectionService
+	triangleService *TriangleDetectionService
+	flagService     *FlagDetectionService
+	display         *DisplayService
+
+	// Settings in effect before any saved section was applied, restored by Reset
+	startup map[string]interface{}
+	mutex   sync.Mutex // serializes updates
+}
+
+// NewSettingsService creates a new settings service, remembering the services' current settings as the startup values
+func NewSettingsService(db *database.Database, setupService *SetupDetectionService, srService *SupportResistanceService,
+	hsService *HeadShouldersDetectionService, fwService *FallingWedgeDetectionService,
+	triangleService *TriangleDetectionService, flagService *FlagDetectionService, display *DisplayService) *SettingsService {
+	ss := &SettingsService{
+		db:              db,
+		setupService:    setupService,
+		srService:       srService,
+		hsService:       hsService,
+		fwService:       fwService,
+		triangleService: triangleService,
+		flagService:     flagService,
+		display:         display,
+		startup:         make(map[string]interface{}),
+	}
+	for _, section := range models.SettingsSections {
+		ss.startup[section] = ss.current(section)
+	}
+	return ss
+}
+
+// Load applies every saved section. A saved section that no longer validates is skipped.
+func (ss *SettingsService) Load() error {
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+
+	for _, section := range models.SettingsSections {
+		value := ss.current(section)
+		found, err := ss.db.GetSettings(section, value)
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+		if err := validateSettings(value); err != nil {
+			log.Printf("Ignoring saved %s settings: %v", section, err)
+			continue
+		}
+		ss.apply(section, value)
+		log.Printf("Applied saved %s settings", section)
+	}
+	return nil
+}
+
+// Get returns the settings of a section currently in effect
+func (ss *SettingsService) Get(section string) (interface{}, error) {
+	if !models.IsSettingsSection(section) {
+		return nil, fmt.Errorf("unknown settings section %q", section)
+	}
+	return ss.current(section), nil
+}
+
+// Update merges a JSON object into a section's current settings, then validates, saves and applies the
+// result. Fields left out of the object keep their current values.
+func (ss *SettingsService) Update(section string, body []byte) (interface{}, error) {
+	if !models.IsSettingsSection(section) {
+		return nil, fmt.Errorf("unknown settings section %q", section)
+	}
+
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+
+	value := ss.current(section)
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(value); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidSettings, section, err)
+	}
+	if err := validateSettings(value); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidSettings, section, err)
+	}
+
+	if err := ss.db.SaveSettings(section, value); err != nil {
+		return nil, err
+	}
+	ss.apply(section, value)
+	log.Printf("Updated %s settings", section)
+
+	return ss.current(section), nil
+}
+
+// Reset discards a section's saved settings and restores the values in effect at startup
+func (ss *SettingsService) Reset(section string) (interface{}, error) {
+	if !models.IsSettingsSection(section) {
+		return nil, fmt.Errorf("unknown settings section %q", section)
+	}
+
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+
+	if err := ss.db.DeleteSettings(section); err != nil {
+		return nil, err
+	}
+	ss.apply(section, copySettings(ss.startup[section]))
+	log.Printf("Reset %s settings", section)
+
+	return ss.current(section), nil
+}
+
+// current returns a copy of a section's settings in effect, safe to modify
+func (ss *SettingsService) current(section string) interface{} {
+	switch section {
+	case models.SettingsSetups:
+		return ss.setupService.Config()
+	case models.SettingsSR:
+		return ss.srService.Config()
+	case models.SettingsDisplay:
+		return ss.display.Config()
+	default:
+		return &models.PatternSettings{
+			HeadShoulders: ss.hsService.Config(),
+			FallingWedge:  ss.fwService.Config(),
+			Triangle:      ss.triangleService.Config(),
+			Flag:          ss.flagService.Config(),
+		}
+	}
+}
+
+// apply hands a section's settings to the services using them
+func (ss *SettingsService) apply(section string, value interface{}) {
+	switch v := value.(type) {
+	case *models.SetupScoringConfig:
+		ss.setupService.SetConfig(v)
+	case *models.SRDetectionConfig:
+		ss.srService.SetConfig(v)
+	case *models.DisplaySettings:
+		ss.display.SetConfig(v)
+	case *models.PatternSettings:
+		ss.hsService.SetConfig(v.HeadShoulders)
+		ss.fwService.SetConfig(v.FallingWedge)
+		ss.triangleService.SetConfig(v.Triangle)
+		ss.flagService.SetConfig(v.Flag)
+	}
+}
+
+// validateSettings validates a section's settings
+func validateSettings(value interface{}) error {
+	switch v := value.(type) {
+	case *models.SetupScoringConfig:
+		return v.Validate()
+	case *models.SRDetectionConfig:
+		return v.Validate()
+	case *models.DisplaySettings:
+		return v.Validate()
+	case *models.PatternSettings:
+		return v.Validate()
+	}
+	return fmt.Errorf("unsupported settings type %T", value)
+}
+
+// copySettings returns a copy of a section's settings so later changes don't alias the original
+func copySettings(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *models.SetupScoringConfig:
+		c := *v
+		return &c
+	case *models.SRDetectionConfig:
+		c := *v
+		return &c
+	case *models.DisplaySettings:
+		c := *v
+		return &c
+	case *models.PatternSettings:
+		hs, fw, tr, fl := *v.HeadShoulders, *v.FallingWedge, *v.Triangle, *v.Flag
+		return &models.PatternSettings{HeadShoulders: &hs, FallingWedge: &fw, Triangle: &tr, Flag: &fl}
+	}
+	return value
+}
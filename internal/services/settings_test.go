@@ -0,0 +1,77 @@
+package services
+
+import (
+	"testing"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// TestSettingsService tests partial updates, validation, persistence across restarts and reset
+func TestSettingsService(t *testing.T) {
+	cfg := &config.Config{}
+	db := newTestDB(t)
+
+	var display *DisplayService
+	newSettings := func() (*SettingsService, *SetupDetectionService, *HeadShouldersDetectionService) {
+		setupService := NewSetupDetectionService(db, nil, nil)
+		hsService := NewHeadShouldersDetectionService(db, setupService, nil, nil)
+		display = NewDisplayService(cfg)
+		ss := NewSettingsService(db, setupService, NewSupportResistanceService(db, nil), hsService,
+			NewFallingWedgeDetectionService(db, nil, nil), NewTriangleDetectionService(db, nil, nil), NewFlagDetectionService(db, nil, nil),
+			display)
+		if err := ss.Load(); err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		return ss, setupService, hsService
+	}
+
+	ss, setupService, _ := newSettings()
+
+	// Weights must keep adding up to 100
+	if _, err := ss.Update(models.SettingsSetups, []byte(`{"volume_weight": 40}`)); err == nil {
+		t.Error("expected unbalanced weights to be rejected")
+	}
+	if _, err := ss.Update(models.SettingsSetups, []byte(`{"volume_wieght": 25}`)); err == nil {
+		t.Error("expected an unknown field to be rejected")
+	}
+	if setupService.Config().VolumeWeight != 25 {
+		t.Fatalf("rejected updates must not change the settings in effect")
+	}
+
+	if _, err := ss.Update(models.SettingsSetups, []byte(`{"volume_weight": 40, "technical_weight": 10, "setup_expiration_hours": 48}`)); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if _, err := ss.Update(models.SettingsPatterns, []byte(`{"head_shoulders": {"min_symmetry_score": 75}}`)); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if _, err := ss.Update(models.SettingsDisplay, []byte(`{"timezone": "Mars/Olympus"}`)); err == nil {
+		t.Error("expected an unknown time zone to be rejected")
+	}
+	if _, err := ss.Update(models.SettingsDisplay, []byte(`{"timezone": "America/New_York"}`)); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	// A restart picks the saved sections back up; untouched fields keep their defaults
+	ss, setupService, hsService := newSettings()
+	scoring := setupService.Config()
+	if scoring.VolumeWeight != 40 || scoring.TechnicalWeight != 10 || scoring.SetupExpirationHours != 48 || scoring.PriceActionWeight != 25 {
+		t.Errorf("unexpected scoring settings after restart: %+v", scoring)
+	}
+	if hs := hsService.Config(); hs.MinSymmetryScore != 75 || hs.SwingWindow != 5 {
+		t.Errorf("unexpected head and shoulders settings after restart: %+v", hs)
+	}
+	if loc, err := display.Location(""); err != nil || loc.String() != "America/New_York" {
+		t.Errorf("expected the saved time zone after restart, got %v, %v", loc, err)
+	}
+
+	if _, err := ss.Reset(models.SettingsSetups); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if setupService.Config().VolumeWeight != 25 {
+		t.Error("expected reset to restore the default weights")
+	}
+	if _, err := ss.Get("unknown"); err == nil {
+		t.Error("expected an unknown section to be rejected")
+	}
+}
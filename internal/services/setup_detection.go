@@ -1,8 +1,13 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"math"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"market-watch-go/internal/database"
@@ -11,42 +16,131 @@ import (
 
 // SetupDetectionService handles trading setup detection and scoring
 type SetupDetectionService struct {
-	db        *database.Database
-	taService *TechnicalAnalysisService
-	srService *SupportResistanceService
-	config    *models.SetupScoringConfig
+	db            *database.Database
+	taService     *TechnicalAnalysisService
+	srService     *SupportResistanceService
+	telegram      *TelegramService
+	calendar      *CalendarService
+	notifications *NotificationService
+	webhooks      *WebhookService
+	broker        *BrokerService
+	sessions      *MarketCalendar // regular sessions opening ranges and VWAPs are measured in
+	pivots        *FloorPivotService
+	shortInterest *ShortInterestService
+	marketContext *MarketContextService
+	regimes       *MarketRegimeService
+	config        *models.SetupScoringConfig
+	calibrated    atomic.Pointer[models.ScoringWeightVersion] // checklist item weights replacing the category weights when set
 }
 
 // NewSetupDetectionService creates a new setup detection service
 func NewSetupDetectionService(db *database.Database, taService *TechnicalAnalysisService, srService *SupportResistanceService) *SetupDetectionService {
-	// Default configuration
-	config := &models.SetupScoringConfig{
-		HighQualityThreshold:   80.0,
-		MediumQualityThreshold: 60.0,
-		LowQualityThreshold:    40.0,
-		PriceActionWeight:      25.0,
-		VolumeWeight:           25.0,
-		TechnicalWeight:        25.0,
-		RiskRewardWeight:       25.0,
-		MinBouncePercent:       2.0,
-		MinTimeAtLevelMinutes:  30,
-		MaxLevelAgeDays:        60,
-		MinRiskRewardRatio:     1.5,
-		MaxRiskPercent:         2.0,
-		SetupExpirationHours:   24,
-	}
-
 	return &SetupDetectionService{
 		db:        db,
 		taService: taService,
 		srService: srService,
-		config:    config,
+		config:    models.DefaultSetupScoringConfig(),
+	}
+}
+
+// Config returns a copy of the scoring settings
+func (sds *SetupDetectionService) Config() *models.SetupScoringConfig {
+	config := *sds.config
+	return &config
+}
+
+// SetConfig replaces the scoring settings, e.g. with ones tuned through the settings API
+func (sds *SetupDetectionService) SetConfig(config *models.SetupScoringConfig) {
+	sds.config = config
+}
+
+// SetTelegramService sets the Telegram service used for setup alerts
+func (sds *SetupDetectionService) SetTelegramService(telegram *TelegramService) {
+	sds.telegram = telegram
+}
+
+// SetNotificationService records high quality setups in the notification center
+func (sds *SetupDetectionService) SetNotificationService(notifications *NotificationService) {
+	sds.notifications = notifications
+}
+
+// SetCalendarService sets the calendar service used to flag setups spanning earnings and economic events
+func (sds *SetupDetectionService) SetCalendarService(calendar *CalendarService) {
+	sds.calendar = calendar
+}
+
+// SetWebhookService sets the service that posts setup_triggered and setup_invalidated webhooks
+func (sds *SetupDetectionService) SetWebhookService(webhooks *WebhookService) {
+	sds.webhooks = webhooks
+}
+
+// SetCalibratedWeights scores setups with calibrated checklist item weights, or with the configured category
+// weights when version is nil
+func (sds *SetupDetectionService) SetCalibratedWeights(version *models.ScoringWeightVersion) {
+	sds.calibrated.Store(version)
+}
+
+// CalibratedWeights returns the calibrated weights setups are scored with, or nil for the default scorer
+func (sds *SetupDetectionService) CalibratedWeights() *models.ScoringWeightVersion {
+	return sds.calibrated.Load()
+}
+
+// SetBrokerService sets the broker that places orders for triggered setups of auto trading strategies
+func (sds *SetupDetectionService) SetBrokerService(broker *BrokerService) {
+	sds.broker = broker
+}
+
+// SetMarketCalendar enables opening range breakout and VWAP setups, measured from each regular session's open
+func (sds *SetupDetectionService) SetMarketCalendar(calendar *MarketCalendar) {
+	sds.sessions = calendar
+}
+
+// SetFloorPivotService lets entries at a daily or weekly floor pivot earn the pivot confluence bonus
+func (sds *SetupDetectionService) SetFloorPivotService(pivots *FloorPivotService) {
+	sds.pivots = pivots
+}
+
+// SetShortInterestService flags setups of stocks with elevated short data in their checklist
+func (sds *SetupDetectionService) SetShortInterestService(shortInterest *ShortInterestService) {
+	sds.shortInterest = shortInterest
+}
+
+// SetMarketContextService sets the service whose market regime penalizes setups trading against it
+func (sds *SetupDetectionService) SetMarketContextService(marketContext *MarketContextService) {
+	sds.marketContext = marketContext
+}
+
+// SetMarketRegimeService sets the service whose classified regime gates which setups are detected
+func (sds *SetupDetectionService) SetMarketRegimeService(regimes *MarketRegimeService) {
+	sds.regimes = regimes
+}
+
+// NotifySetups records every high quality setup in the notification center and sends it to Telegram
+func (sds *SetupDetectionService) NotifySetups(setups []*models.TradingSetup) {
+	for _, setup := range setups {
+		if setup.QualityScore < sds.config.HighQualityThreshold {
+			continue
+		}
+
+		sds.notifications.Notify(&models.Notification{
+			Category: models.NotificationSetup,
+			Severity: EmailSeverityHigh,
+			Symbol:   setup.Symbol,
+			Title:    fmt.Sprintf("🚨 %s: %s", setup.Symbol, setupTitle(setup)),
+			Message:  formatSetupLevels(setup),
+		})
+
+		if sds.telegram.IsEnabled() {
+			if err := sds.telegram.SendSetupAlert(setup); err != nil {
+				log.Printf("Failed to send setup alert for %s: %v", setup.Symbol, err)
+			}
+		}
 	}
 }
 
-// DetectSetups performs comprehensive setup detection for a symbol
-func (sds *SetupDetectionService) DetectSetups(symbol string) (*models.SetupDetectionResult, error) {
-	now := time.Now()
+// DetectSetups performs comprehensive setup detection for a symbol, reading prices, levels and indicators under ctx
+func (sds *SetupDetectionService) DetectSetups(ctx context.Context, symbol string) (*models.SetupDetectionResult, error) {
+	now := clockNow()
 
 	result := &models.SetupDetectionResult{
 		Symbol:        symbol,
@@ -58,43 +152,66 @@ func (sds *SetupDetectionService) DetectSetups(symbol string) (*models.SetupDete
 	}
 
 	// Get current market data
-	currentPrice, err := sds.getCurrentPrice(symbol)
+	currentPrice, err := sds.getCurrentPrice(ctx, symbol)
 	if err != nil {
-		result.Errors = append(result.Errors, "Failed to get current price: "+err.Error())
-		return result, nil
+		return detectionFailed(ctx, result, "Failed to get current price", err)
 	}
 
 	// Get S/R analysis
-	srAnalysis, err := sds.srService.DetectSupportResistanceLevels(symbol)
+	srAnalysis, err := sds.srService.DetectSupportResistanceLevels(ctx, symbol, models.DefaultTimeframe)
 	if err != nil {
-		result.Errors = append(result.Errors, "Failed to get S/R analysis: "+err.Error())
-		return result, nil
+		return detectionFailed(ctx, result, "Failed to get S/R analysis", err)
 	}
 
 	// Get technical indicators
-	indicators, err := sds.taService.GetIndicators(symbol)
+	indicators, err := sds.taService.GetIndicators(ctx, symbol)
 	if err != nil {
-		result.Errors = append(result.Errors, "Failed to get technical indicators: "+err.Error())
-		return result, nil
+		return detectionFailed(ctx, result, "Failed to get technical indicators", err)
 	}
 
 	// Detect different types of setups
-	supportBounceSetups := sds.detectSupportBounceSetups(symbol, currentPrice, srAnalysis)
-	resistanceBounceSetups := sds.detectResistanceBounceSetups(symbol, currentPrice, srAnalysis)
-	breakoutSetups := sds.detectBreakoutSetups(symbol, currentPrice, srAnalysis)
+	supportBounceSetups := sds.detectSupportBounceSetups(symbol, currentPrice, srAnalysis, indicators)
+	resistanceBounceSetups := sds.detectResistanceBounceSetups(symbol, currentPrice, srAnalysis, indicators)
+	breakoutSetups := sds.detectBreakoutSetups(symbol, currentPrice, srAnalysis, indicators)
+	openingRangeSetups := sds.detectOpeningRangeBreakoutSetups(ctx, symbol, currentPrice)
+	vwapSetups := sds.detectVWAPSetups(ctx, symbol, currentPrice)
 
 	// Combine all detected setups
 	allSetups := append(supportBounceSetups, resistanceBounceSetups...)
 	allSetups = append(allSetups, breakoutSetups...)
+	allSetups = append(allSetups, openingRangeSetups...)
+	allSetups = append(allSetups, vwapSetups...)
+
+	// Leave out setups the market regime rules out
+	if regime := sds.regimes.Regime(); regime != "" {
+		result.Regime = regime
+		allSetups = sds.gateByRegime(ctx, symbol, allSetups, regime, result)
+	}
+
+	// Load scheduled events that could fall inside a setup's holding window
+	events := sds.upcomingEvents(symbol, now)
+	shortData := sds.shortInterestSummary(symbol)
+	regime := sds.marketRegime()
 
 	// Score and validate setups
 	for _, setup := range allSetups {
+		if !sds.passesTrendFilter(setup, indicators) {
+			continue
+		}
+		if setup.KeyLevel != nil {
+			setup.KeyLevelID = setup.KeyLevel.ID
+		}
+
 		err := sds.scoreSetup(setup, indicators)
 		if err != nil {
 			result.Errors = append(result.Errors, fmt.Sprintf("Failed to score setup %s: %v", setup.SetupType, err))
 			continue
 		}
 
+		sds.applyEventRisk(setup, events)
+		sds.applyShortInterest(setup, shortData)
+		sds.applyMarketRegime(setup, regime)
+
 		// Only include setups that meet minimum criteria
 		if setup.QualityScore >= sds.config.LowQualityThreshold {
 			result.SetupsFound = append(result.SetupsFound, setup)
@@ -111,14 +228,24 @@ func (sds *SetupDetectionService) DetectSetups(symbol string) (*models.SetupDete
 	return result, nil
 }
 
+// detectionFailed ends a detection run whose market data couldn't be loaded. A cancelled run returns the
+// context's error; any other failure is reported in the result.
+func detectionFailed(ctx context.Context, result *models.SetupDetectionResult, message string, err error) (*models.SetupDetectionResult, error) {
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("setup detection for %s stopped: %w", result.Symbol, ctx.Err())
+	}
+	result.Errors = append(result.Errors, message+": "+err.Error())
+	return result, nil
+}
+
 // detectSupportBounceSetups identifies potential support bounce setups
-func (sds *SetupDetectionService) detectSupportBounceSetups(symbol string, currentPrice float64, srAnalysis *models.SRAnalysisResult) []*models.TradingSetup {
+func (sds *SetupDetectionService) detectSupportBounceSetups(symbol string, currentPrice float64, srAnalysis *models.SRAnalysisResult, indicators *models.TechnicalIndicators) []*models.TradingSetup {
 	var setups []*models.TradingSetup
 
-	for _, supportLevel := range srAnalysis.SupportLevels {
-		// Check if price is near support level (within 2%)
-		distancePercent := math.Abs(currentPrice-supportLevel.Level) / supportLevel.Level * 100
-		if distancePercent <= 2.0 && currentPrice >= supportLevel.Level*0.99 {
+	for _, zone := range srAnalysis.SupportZones {
+		// Check if price is near the support zone (within 2%)
+		distancePercent := math.Abs(currentPrice-zone.Level) / zone.Level * 100
+		if distancePercent <= 2.0 && currentPrice >= zone.Low*0.99 {
 
 			// Create support bounce setup
 			setup := &models.TradingSetup{
@@ -126,22 +253,23 @@ func (sds *SetupDetectionService) detectSupportBounceSetups(symbol string, curre
 				SetupType:    "support_bounce",
 				Direction:    "bullish",
 				Status:       "active",
-				DetectedAt:   time.Now(),
-				ExpiresAt:    time.Now().Add(time.Duration(sds.config.SetupExpirationHours) * time.Hour),
+				DetectedAt:   clockNow(),
+				ExpiresAt:    clockNow().Add(time.Duration(sds.config.SetupExpirationHours) * time.Hour),
 				CurrentPrice: currentPrice,
-				EntryPrice:   supportLevel.Level * 1.002, // Slight premium above support
-				StopLoss:     supportLevel.Level * 0.995, // Just below support
+				EntryPrice:   zone.Level * 1.002,                         // Slight premium above support
+				StopLoss:     sds.stopBelow(zone.Low, 0.995, indicators), // Just below the zone
 				IsManual:     false,
-				CreatedAt:    time.Now(),
-				UpdatedAt:    time.Now(),
+				CreatedAt:    clockNow(),
+				UpdatedAt:    clockNow(),
 			}
 
 			// Set targets based on resistance levels
 			sds.setTargetLevels(setup, srAnalysis)
 
-			// Associate the support level
-			setup.SupportLevel = supportLevel
-			setup.KeyLevel = supportLevel
+			// Associate the support zone and its strongest level
+			setup.SupportLevel = zone.KeyLevel()
+			setup.KeyLevel = setup.SupportLevel
+			setup.KeyZone = zone
 
 			setups = append(setups, setup)
 		}
@@ -151,13 +279,13 @@ func (sds *SetupDetectionService) detectSupportBounceSetups(symbol string, curre
 }
 
 // detectResistanceBounceSetups identifies potential resistance bounce setups
-func (sds *SetupDetectionService) detectResistanceBounceSetups(symbol string, currentPrice float64, srAnalysis *models.SRAnalysisResult) []*models.TradingSetup {
+func (sds *SetupDetectionService) detectResistanceBounceSetups(symbol string, currentPrice float64, srAnalysis *models.SRAnalysisResult, indicators *models.TechnicalIndicators) []*models.TradingSetup {
 	var setups []*models.TradingSetup
 
-	for _, resistanceLevel := range srAnalysis.ResistanceLevels {
-		// Check if price is near resistance level (within 2%)
-		distancePercent := math.Abs(currentPrice-resistanceLevel.Level) / resistanceLevel.Level * 100
-		if distancePercent <= 2.0 && currentPrice <= resistanceLevel.Level*1.01 {
+	for _, zone := range srAnalysis.ResistanceZones {
+		// Check if price is near the resistance zone (within 2%)
+		distancePercent := math.Abs(currentPrice-zone.Level) / zone.Level * 100
+		if distancePercent <= 2.0 && currentPrice <= zone.High*1.01 {
 
 			// Create resistance bounce setup
 			setup := &models.TradingSetup{
@@ -165,22 +293,23 @@ func (sds *SetupDetectionService) detectResistanceBounceSetups(symbol string, cu
 				SetupType:    "resistance_bounce",
 				Direction:    "bearish",
 				Status:       "active",
-				DetectedAt:   time.Now(),
-				ExpiresAt:    time.Now().Add(time.Duration(sds.config.SetupExpirationHours) * time.Hour),
+				DetectedAt:   clockNow(),
+				ExpiresAt:    clockNow().Add(time.Duration(sds.config.SetupExpirationHours) * time.Hour),
 				CurrentPrice: currentPrice,
-				EntryPrice:   resistanceLevel.Level * 0.998, // Slight discount below resistance
-				StopLoss:     resistanceLevel.Level * 1.005, // Just above resistance
+				EntryPrice:   zone.Level * 0.998,                          // Slight discount below resistance
+				StopLoss:     sds.stopAbove(zone.High, 1.005, indicators), // Just above the zone
 				IsManual:     false,
-				CreatedAt:    time.Now(),
-				UpdatedAt:    time.Now(),
+				CreatedAt:    clockNow(),
+				UpdatedAt:    clockNow(),
 			}
 
 			// Set targets based on support levels
 			sds.setTargetLevels(setup, srAnalysis)
 
-			// Associate the resistance level
-			setup.ResistanceLevel = resistanceLevel
-			setup.KeyLevel = resistanceLevel
+			// Associate the resistance zone and its strongest level
+			setup.ResistanceLevel = zone.KeyLevel()
+			setup.KeyLevel = setup.ResistanceLevel
+			setup.KeyZone = zone
 
 			setups = append(setups, setup)
 		}
@@ -190,56 +319,58 @@ func (sds *SetupDetectionService) detectResistanceBounceSetups(symbol string, cu
 }
 
 // detectBreakoutSetups identifies potential breakout setups
-func (sds *SetupDetectionService) detectBreakoutSetups(symbol string, currentPrice float64, srAnalysis *models.SRAnalysisResult) []*models.TradingSetup {
+func (sds *SetupDetectionService) detectBreakoutSetups(symbol string, currentPrice float64, srAnalysis *models.SRAnalysisResult, indicators *models.TechnicalIndicators) []*models.TradingSetup {
 	var setups []*models.TradingSetup
 
 	// Check for resistance breakouts
-	for _, resistanceLevel := range srAnalysis.ResistanceLevels {
-		if currentPrice > resistanceLevel.Level*1.002 { // Price broke above resistance
+	for _, zone := range srAnalysis.ResistanceZones {
+		if currentPrice > zone.High*1.002 { // Price broke above the resistance zone
 			setup := &models.TradingSetup{
 				Symbol:       symbol,
 				SetupType:    "resistance_breakout",
 				Direction:    "bullish",
 				Status:       "active",
-				DetectedAt:   time.Now(),
-				ExpiresAt:    time.Now().Add(time.Duration(sds.config.SetupExpirationHours) * time.Hour),
+				DetectedAt:   clockNow(),
+				ExpiresAt:    clockNow().Add(time.Duration(sds.config.SetupExpirationHours) * time.Hour),
 				CurrentPrice: currentPrice,
 				EntryPrice:   currentPrice,
-				StopLoss:     resistanceLevel.Level * 0.998, // Below broken resistance
+				StopLoss:     sds.stopBelow(zone.Level, 0.998, indicators), // Below broken resistance
 				IsManual:     false,
-				CreatedAt:    time.Now(),
-				UpdatedAt:    time.Now(),
+				CreatedAt:    clockNow(),
+				UpdatedAt:    clockNow(),
 			}
 
 			sds.setTargetLevels(setup, srAnalysis)
-			setup.ResistanceLevel = resistanceLevel
-			setup.KeyLevel = resistanceLevel
+			setup.ResistanceLevel = zone.KeyLevel()
+			setup.KeyLevel = setup.ResistanceLevel
+			setup.KeyZone = zone
 
 			setups = append(setups, setup)
 		}
 	}
 
 	// Check for support breakdowns
-	for _, supportLevel := range srAnalysis.SupportLevels {
-		if currentPrice < supportLevel.Level*0.998 { // Price broke below support
+	for _, zone := range srAnalysis.SupportZones {
+		if currentPrice < zone.Low*0.998 { // Price broke below the support zone
 			setup := &models.TradingSetup{
 				Symbol:       symbol,
 				SetupType:    "support_breakdown",
 				Direction:    "bearish",
 				Status:       "active",
-				DetectedAt:   time.Now(),
-				ExpiresAt:    time.Now().Add(time.Duration(sds.config.SetupExpirationHours) * time.Hour),
+				DetectedAt:   clockNow(),
+				ExpiresAt:    clockNow().Add(time.Duration(sds.config.SetupExpirationHours) * time.Hour),
 				CurrentPrice: currentPrice,
 				EntryPrice:   currentPrice,
-				StopLoss:     supportLevel.Level * 1.002, // Above broken support
+				StopLoss:     sds.stopAbove(zone.Level, 1.002, indicators), // Above broken support
 				IsManual:     false,
-				CreatedAt:    time.Now(),
-				UpdatedAt:    time.Now(),
+				CreatedAt:    clockNow(),
+				UpdatedAt:    clockNow(),
 			}
 
 			sds.setTargetLevels(setup, srAnalysis)
-			setup.SupportLevel = supportLevel
-			setup.KeyLevel = supportLevel
+			setup.SupportLevel = zone.KeyLevel()
+			setup.KeyLevel = setup.SupportLevel
+			setup.KeyZone = zone
 
 			setups = append(setups, setup)
 		}
@@ -248,14 +379,48 @@ func (sds *SetupDetectionService) detectBreakoutSetups(symbol string, currentPri
 	return setups
 }
 
+// stopBelow places a stop under a level, using ATR when available and a fixed ratio otherwise
+func (sds *SetupDetectionService) stopBelow(level, fallbackRatio float64, indicators *models.TechnicalIndicators) float64 {
+	if indicators != nil && indicators.ATR14 > 0 {
+		return level - indicators.ATR14*sds.config.ATRStopMultiplier
+	}
+	return level * fallbackRatio
+}
+
+// stopAbove places a stop over a level, using ATR when available and a fixed ratio otherwise
+func (sds *SetupDetectionService) stopAbove(level, fallbackRatio float64, indicators *models.TechnicalIndicators) float64 {
+	if indicators != nil && indicators.ATR14 > 0 {
+		return level + indicators.ATR14*sds.config.ATRStopMultiplier
+	}
+	return level * fallbackRatio
+}
+
+// passesTrendFilter uses ADX to require a trend for breakouts and reject bounces against a strong trend
+func (sds *SetupDetectionService) passesTrendFilter(setup *models.TradingSetup, indicators *models.TechnicalIndicators) bool {
+	if indicators == nil || indicators.ADX14 == 0 {
+		return true // ADX not available, don't filter
+	}
+
+	trending := indicators.ADX14 >= sds.config.MinADXTrend
+	trendAligned := (setup.Direction == "bullish" && indicators.PlusDI > indicators.MinusDI) ||
+		(setup.Direction == "bearish" && indicators.MinusDI > indicators.PlusDI)
+
+	switch setup.SetupType {
+	case "resistance_breakout", "support_breakdown":
+		return trending && trendAligned
+	default:
+		return !trending || trendAligned
+	}
+}
+
 // setTargetLevels sets target levels for a setup based on S/R analysis
 func (sds *SetupDetectionService) setTargetLevels(setup *models.TradingSetup, srAnalysis *models.SRAnalysisResult) {
 	if setup.Direction == "bullish" {
-		// Find resistance levels above current price for targets
+		// Find resistance zones above current price for targets
 		var targets []float64
-		for _, level := range srAnalysis.ResistanceLevels {
-			if level.Level > setup.EntryPrice {
-				targets = append(targets, level.Level)
+		for _, zone := range srAnalysis.ResistanceZones {
+			if zone.Level > setup.EntryPrice {
+				targets = append(targets, zone.Level)
 			}
 		}
 
@@ -287,11 +452,11 @@ func (sds *SetupDetectionService) setTargetLevels(setup *models.TradingSetup, sr
 		}
 
 	} else { // bearish
-		// Find support levels below current price for targets
+		// Find support zones below current price for targets
 		var targets []float64
-		for _, level := range srAnalysis.SupportLevels {
-			if level.Level < setup.EntryPrice {
-				targets = append(targets, level.Level)
+		for _, zone := range srAnalysis.SupportZones {
+			if zone.Level < setup.EntryPrice {
+				targets = append(targets, zone.Level)
 			}
 		}
 
@@ -341,18 +506,215 @@ func (sds *SetupDetectionService) scoreSetup(setup *models.TradingSetup, indicat
 	setup.TechnicalScore = checklist.GetTechnicalScore()
 	setup.RiskRewardScore = checklist.GetRiskManagementScore()
 
-	// Calculate weighted overall score
-	setup.QualityScore = (setup.PriceActionScore*sds.config.PriceActionWeight +
-		setup.VolumeScore*sds.config.VolumeWeight +
-		setup.TechnicalScore*sds.config.TechnicalWeight +
-		setup.RiskRewardScore*sds.config.RiskRewardWeight) / 100.0
+	// Calculate weighted overall score, or add up the calibrated weights of the completed items
+	version := sds.calibrated.Load()
+	if version != nil {
+		setup.QualityScore = calibratedScore(checklist, version)
+	} else {
+		setup.QualityScore = (setup.PriceActionScore*sds.config.PriceActionWeight +
+			setup.VolumeScore*sds.config.VolumeWeight +
+			setup.TechnicalScore*sds.config.TechnicalWeight +
+			setup.RiskRewardScore*sds.config.RiskRewardWeight) / 100.0
+	}
+	setup.ScoreBreakdown = sds.checklistBreakdown(checklist, version)
+
+	// Strong, confluent zones add up to ZoneStrengthWeight points
+	if setup.KeyZone != nil {
+		before := setup.QualityScore
+		setup.QualityScore = math.Min(100, setup.QualityScore+setup.KeyZone.ZoneStrength/100.0*sds.config.ZoneStrengthWeight)
+		setup.ScoreBreakdown.AddAdjustment("zone_strength", "Zone Strength", setup.QualityScore-before,
+			sds.config.ZoneStrengthWeight, fmt.Sprintf("zone strength %.0f of 100", setup.KeyZone.ZoneStrength))
+	}
+
+	// Entries at a daily or weekly floor pivot add PivotConfluenceWeight points
+	if sds.pivots != nil && sds.config.PivotConfluenceWeight > 0 {
+		levels, err := sds.pivots.Levels(setup.Symbol)
+		if err != nil {
+			log.Printf("Failed to get floor pivots for %s: %v", setup.Symbol, err)
+		} else if nearFloorPivot(setup.EntryPrice, levels) {
+			before := setup.QualityScore
+			setup.QualityScore = math.Min(100, setup.QualityScore+sds.config.PivotConfluenceWeight)
+			setup.ScoreBreakdown.AddAdjustment("pivot_confluence", "Pivot Confluence", setup.QualityScore-before,
+				sds.config.PivotConfluenceWeight, "entry at a daily or weekly floor pivot")
+		}
+	}
+	setup.ScoreBreakdown.Score = math.Round(setup.QualityScore*100) / 100
 
 	// Set confidence level
-	setup.Confidence = setup.GetConfidenceLevel()
+	sds.applyConfidence(setup, indicators)
 
 	return nil
 }
 
+// upcomingEvents loads calendar events that could overlap any setup detected now
+func (sds *SetupDetectionService) upcomingEvents(symbol string, now time.Time) []*models.CalendarEvent {
+	if sds.calendar == nil {
+		return nil
+	}
+
+	to := now.Add(time.Duration(sds.config.SetupExpirationHours)*time.Hour).AddDate(0, 0, sds.config.EarningsBufferDays)
+	events, err := sds.calendar.EventsInWindow(symbol, now, to)
+	if err != nil {
+		log.Printf("Failed to load calendar events for %s: %v", symbol, err)
+		return nil
+	}
+	return events
+}
+
+// applyEventRisk flags events inside the setup window and penalizes setups that would be held through earnings
+func (sds *SetupDetectionService) applyEventRisk(setup *models.TradingSetup, events []*models.CalendarEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	windowEnd := setup.ExpiresAt.AddDate(0, 0, sds.config.EarningsBufferDays)
+	earnings := false
+	for _, event := range events {
+		if event.EventDate.After(windowEnd) {
+			continue
+		}
+		setup.UpcomingEvents = append(setup.UpcomingEvents, event)
+		if event.EventType == models.CalendarEventEarnings && !event.IsMarketWide() {
+			earnings = true
+		}
+	}
+
+	// Market-wide events are flagged only; earnings gaps can jump straight through the stop
+	if earnings && sds.config.EarningsPenalty > 0 {
+		setup.EventPenalty = math.Min(sds.config.EarningsPenalty, setup.QualityScore)
+		setup.QualityScore -= setup.EventPenalty
+		if setup.ScoreBreakdown != nil {
+			setup.ScoreBreakdown.AddAdjustment("earnings_penalty", "Earnings Penalty", -setup.EventPenalty, 0,
+				"earnings before the setup expires or within the buffer days after")
+			setup.ScoreBreakdown.Score = math.Round(setup.QualityScore*100) / 100
+		}
+		sds.refreshConfidence(setup)
+	}
+}
+
+// shortInterestSummary loads a stock's short data when short interest is tracked
+func (sds *SetupDetectionService) shortInterestSummary(symbol string) *models.ShortInterestSummary {
+	if sds.shortInterest == nil || !models.IsEquity(symbol) {
+		return nil
+	}
+
+	summary, err := sds.shortInterest.Summary(symbol)
+	if err != nil {
+		log.Printf("Failed to load short interest for %s: %v", symbol, err)
+		return nil
+	}
+	return summary
+}
+
+// applyShortInterest checks the short interest item of a setup's checklist, which is reported without points:
+// heavy shorting can fuel a squeeze through a bullish setup's target or show a crowded bearish one
+func (sds *SetupDetectionService) applyShortInterest(setup *models.TradingSetup, summary *models.ShortInterestSummary) {
+	if setup.Checklist == nil || summary == nil || (summary.Latest == nil && len(summary.ShortVolume) == 0) {
+		return
+	}
+
+	item := &setup.Checklist.ShortInterest
+	item.AutoDetected = true
+	item.LastChecked = clockNow()
+	item.IsCompleted = summary.Elevated
+	item.Threshold = sds.shortInterest.thresholds()
+
+	values := make([]string, 0, 3)
+	if len(summary.ShortVolume) > 0 {
+		values = append(values, fmt.Sprintf("%.1f%% short volume", summary.AvgShortVolumeRatio))
+	}
+	if summary.Latest != nil {
+		values = append(values, fmt.Sprintf("%.1f days to cover", summary.Latest.DaysToCover))
+	}
+	if summary.ShortPercentFloat > 0 {
+		values = append(values, fmt.Sprintf("%.1f%% of float short", summary.ShortPercentFloat))
+	}
+	item.Value = strings.Join(values, ", ")
+
+	switch {
+	case !summary.Elevated:
+		item.Notes = "Short data is not elevated"
+	case setup.Direction == "bullish":
+		item.Notes = "Short covering can fuel a squeeze through the targets"
+	default:
+		item.Notes = "Crowded short; a squeeze can run through the stop"
+	}
+}
+
+// gateByRegime drops setups the classified regime rules out: counter-trend bounces when the bounce filter is on,
+// and setups whose direction none of the symbol's strategies declaring regimes trades in the regime. The dropped
+// setup types are listed in the result.
+func (sds *SetupDetectionService) gateByRegime(ctx context.Context, symbol string, setups []*models.TradingSetup, regime string, result *models.SetupDetectionResult) []*models.TradingSetup {
+	strategies, err := sds.db.WithContext(ctx).GetRegimesForSymbol(symbol)
+	if err != nil {
+		log.Printf("Failed to get strategy regimes for %s: %v", symbol, err)
+	}
+
+	kept := setups[:0]
+	for _, setup := range setups {
+		if (sds.config.BounceRegimeFilter && counterTrendBounce(setup, regime)) || !strategiesTrade(strategies, setup.Direction, regime) {
+			if !slices.Contains(result.Suppressed, setup.SetupType) {
+				result.Suppressed = append(result.Suppressed, setup.SetupType)
+			}
+			continue
+		}
+		kept = append(kept, setup)
+	}
+	return kept
+}
+
+// counterTrendBounce reports whether a setup bounces against a trending regime: off support in a downtrend or
+// off resistance in an uptrend
+func counterTrendBounce(setup *models.TradingSetup, regime string) bool {
+	return (setup.SetupType == "support_bounce" && regime == models.RegimeTrendingDown) ||
+		(setup.SetupType == "resistance_bounce" && regime == models.RegimeTrendingUp)
+}
+
+// strategiesTrade reports whether any of a symbol's strategies declaring regimes trades the direction in the
+// regime, or true when none declares regimes
+func strategiesTrade(strategies []*models.StrategyRegimes, direction, regime string) bool {
+	if len(strategies) == 0 {
+		return true
+	}
+	for _, s := range strategies {
+		if s.Allows(direction, regime) {
+			return true
+		}
+	}
+	return false
+}
+
+// marketRegime returns the market regime when the regime filter is on
+func (sds *SetupDetectionService) marketRegime() string {
+	if sds.config.RegimePenalty <= 0 {
+		return ""
+	}
+	return sds.marketContext.Regime()
+}
+
+// applyMarketRegime records the market regime and penalizes setups trading against it
+func (sds *SetupDetectionService) applyMarketRegime(setup *models.TradingSetup, regime string) {
+	if regime == "" {
+		return
+	}
+	setup.MarketRegime = regime
+
+	against := (regime == models.MarketRegimeBullish && setup.Direction == "bearish") ||
+		(regime == models.MarketRegimeBearish && setup.Direction == "bullish")
+	if !against {
+		return
+	}
+
+	setup.RegimePenalty = math.Min(sds.config.RegimePenalty, setup.QualityScore)
+	setup.QualityScore -= setup.RegimePenalty
+	if setup.ScoreBreakdown != nil {
+		setup.ScoreBreakdown.AddAdjustment("market_regime", "Market Regime", -setup.RegimePenalty, 0,
+			fmt.Sprintf("%s setup in a %s market", setup.Direction, regime))
+		setup.ScoreBreakdown.Score = math.Round(setup.QualityScore*100) / 100
+	}
+	sds.refreshConfidence(setup)
+}
+
 // createSetupChecklist creates and evaluates a checklist for a setup
 func (sds *SetupDetectionService) createSetupChecklist(setup *models.TradingSetup, indicators *models.TechnicalIndicators) *models.SetupChecklist {
 	checklist := &models.SetupChecklist{
@@ -366,7 +728,7 @@ func (sds *SetupDetectionService) createSetupChecklist(setup *models.TradingSetu
 	sds.evaluatePriceActionCriteria(checklist, setup)
 	sds.evaluateVolumeCriteria(checklist, setup, indicators)
 	sds.evaluateTechnicalCriteria(checklist, setup, indicators)
-	sds.evaluateRiskManagementCriteria(checklist, setup)
+	sds.evaluateRiskManagementCriteria(checklist, setup, indicators)
 
 	// Calculate final scores
 	checklist.CalculateScore()
@@ -403,23 +765,36 @@ func (sds *SetupDetectionService) initializeChecklistItems(checklist *models.Set
 	checklist.PositionSize = models.ChecklistItem{Name: "Position Size", MaxPoints: 5, IsRequired: false}
 	checklist.EntryPrecision = models.ChecklistItem{Name: "Entry Precision", MaxPoints: 5, IsRequired: false}
 	checklist.ExitStrategy = models.ChecklistItem{Name: "Exit Strategy", MaxPoints: 5, IsRequired: false}
+
+	// Market Context
+	checklist.ShortInterest = models.ChecklistItem{Name: "Elevated Short Interest", Description: "Reported without points"}
 }
 
 // evaluatePriceActionCriteria evaluates price action related criteria
 func (sds *SetupDetectionService) evaluatePriceActionCriteria(checklist *models.SetupChecklist, setup *models.TradingSetup) {
 	keyLevel := setup.KeyLevel
 	if keyLevel == nil {
+		for _, item := range []*models.ChecklistItem{&checklist.MinLevelTouches, &checklist.BounceStrength, &checklist.LevelDuration} {
+			item.Value = "no key level"
+		}
 		return
 	}
 
-	// Minimum Level Touches
-	if keyLevel.Touches >= 3 {
+	// Minimum Level Touches, counting every level merged into the zone
+	touches := keyLevel.Touches
+	if setup.KeyZone != nil {
+		touches = setup.KeyZone.Touches
+	}
+	checklist.MinLevelTouches.Value, checklist.MinLevelTouches.Threshold = fmt.Sprintf("%d touches", touches), "at least 3 touches"
+	if touches >= 3 {
 		checklist.MinLevelTouches.IsCompleted = true
 		checklist.MinLevelTouches.Points = 5
 		checklist.MinLevelTouches.AutoDetected = true
 	}
 
 	// Bounce Strength
+	checklist.BounceStrength.Value = fmt.Sprintf("%.2f%% average bounce", keyLevel.AvgBouncePercent)
+	checklist.BounceStrength.Threshold = fmt.Sprintf("at least %.2f%%", sds.config.MinBouncePercent)
 	if keyLevel.AvgBouncePercent >= sds.config.MinBouncePercent {
 		checklist.BounceStrength.IsCompleted = true
 		checklist.BounceStrength.Points = 5
@@ -428,27 +803,52 @@ func (sds *SetupDetectionService) evaluatePriceActionCriteria(checklist *models.
 
 	// Level Duration - fix type conversion
 	levelAge := keyLevel.GetAge()
+	checklist.LevelDuration.Value = fmt.Sprintf("%.0f days old", levelAge)
+	checklist.LevelDuration.Threshold = fmt.Sprintf("5 to %d days", sds.config.MaxLevelAgeDays)
 	if levelAge >= 5 && levelAge <= float64(sds.config.MaxLevelAgeDays) {
 		checklist.LevelDuration.IsCompleted = true
 		checklist.LevelDuration.Points = 5
 		checklist.LevelDuration.AutoDetected = true
 	}
 
-	checklist.MinLevelTouches.LastChecked = time.Now()
-	checklist.BounceStrength.LastChecked = time.Now()
-	checklist.LevelDuration.LastChecked = time.Now()
+	checklist.MinLevelTouches.LastChecked = clockNow()
+	checklist.BounceStrength.LastChecked = clockNow()
+	checklist.LevelDuration.LastChecked = clockNow()
 }
 
 // evaluateVolumeCriteria evaluates volume related criteria
 func (sds *SetupDetectionService) evaluateVolumeCriteria(checklist *models.SetupChecklist, setup *models.TradingSetup, indicators *models.TechnicalIndicators) {
 	// Volume Spike
+	volumeRatio := fmt.Sprintf("%.2fx average volume", indicators.VolumeRatio)
+	checklist.VolumeSpike.Value, checklist.VolumeSpike.Threshold = volumeRatio, "at least 1.50x"
 	if indicators.VolumeRatio >= 1.5 {
 		checklist.VolumeSpike.IsCompleted = true
 		checklist.VolumeSpike.Points = 5
 		checklist.VolumeSpike.AutoDetected = true
 	}
 
+	// Volume Confirmation: an opening range broken, or a VWAP reclaimed or rejected, on the configured volume
+	checklist.VolumeConfirmation.Threshold = "an opening range or VWAP level confirmed on volume"
+	switch {
+	case !isOpeningRangeSetup(setup) && !isVWAPSetup(setup):
+		checklist.VolumeConfirmation.Value = "not an opening range or VWAP setup"
+	case setup.KeyLevel != nil && setup.KeyLevel.VolumeConfirmed:
+		checklist.VolumeConfirmation.Value = "level confirmed on volume"
+	default:
+		checklist.VolumeConfirmation.Value = "level not confirmed on volume"
+	}
+	if (isOpeningRangeSetup(setup) || isVWAPSetup(setup)) && setup.KeyLevel != nil && setup.KeyLevel.VolumeConfirmed {
+		checklist.VolumeConfirmation.IsCompleted = true
+		checklist.VolumeConfirmation.Points = 5
+		checklist.VolumeConfirmation.AutoDetected = true
+	}
+
 	// VWAP Relationship
+	checklist.VWAPRelationship.Value = fmt.Sprintf("price $%.2f, VWAP $%.2f", setup.CurrentPrice, indicators.VWAP)
+	checklist.VWAPRelationship.Threshold = "price above VWAP"
+	if setup.Direction == "bearish" {
+		checklist.VWAPRelationship.Threshold = "price below VWAP"
+	}
 	if setup.Direction == "bullish" && setup.CurrentPrice > indicators.VWAP {
 		checklist.VWAPRelationship.IsCompleted = true
 		checklist.VWAPRelationship.Points = 5
@@ -460,20 +860,26 @@ func (sds *SetupDetectionService) evaluateVolumeCriteria(checklist *models.Setup
 	}
 
 	// Relative Volume
+	checklist.RelativeVolume.Value, checklist.RelativeVolume.Threshold = volumeRatio, "at least 1.20x"
 	if indicators.VolumeRatio >= 1.2 {
 		checklist.RelativeVolume.IsCompleted = true
 		checklist.RelativeVolume.Points = 5
 		checklist.RelativeVolume.AutoDetected = true
 	}
 
-	checklist.VolumeSpike.LastChecked = time.Now()
-	checklist.VWAPRelationship.LastChecked = time.Now()
-	checklist.RelativeVolume.LastChecked = time.Now()
+	checklist.VolumeSpike.LastChecked = clockNow()
+	checklist.VolumeConfirmation.LastChecked = clockNow()
+	checklist.VWAPRelationship.LastChecked = clockNow()
+	checklist.RelativeVolume.LastChecked = clockNow()
 }
 
 // evaluateTechnicalCriteria evaluates technical indicator criteria
 func (sds *SetupDetectionService) evaluateTechnicalCriteria(checklist *models.SetupChecklist, setup *models.TradingSetup, indicators *models.TechnicalIndicators) {
 	// RSI Condition
+	checklist.RSICondition.Value, checklist.RSICondition.Threshold = fmt.Sprintf("RSI %.1f", indicators.RSI14), "RSI at most 40"
+	if setup.Direction == "bearish" {
+		checklist.RSICondition.Threshold = "RSI at least 60"
+	}
 	if setup.Direction == "bullish" && indicators.RSI14 <= 40 {
 		checklist.RSICondition.IsCompleted = true
 		checklist.RSICondition.Points = 5
@@ -485,6 +891,10 @@ func (sds *SetupDetectionService) evaluateTechnicalCriteria(checklist *models.Se
 	}
 
 	// MACD Signal
+	checklist.MACDSignal.Value, checklist.MACDSignal.Threshold = fmt.Sprintf("histogram %.4f", indicators.MACDHistogram), "positive histogram"
+	if setup.Direction == "bearish" {
+		checklist.MACDSignal.Threshold = "negative histogram"
+	}
 	if setup.Direction == "bullish" && indicators.MACDHistogram > 0 {
 		checklist.MACDSignal.IsCompleted = true
 		checklist.MACDSignal.Points = 5
@@ -495,14 +905,32 @@ func (sds *SetupDetectionService) evaluateTechnicalCriteria(checklist *models.Se
 		checklist.MACDSignal.AutoDetected = true
 	}
 
-	checklist.RSICondition.LastChecked = time.Now()
-	checklist.MovingAverage.LastChecked = time.Now()
-	checklist.MACDSignal.LastChecked = time.Now()
+	// Stochastic momentum: %K crossing %D out of oversold/overbought territory
+	checklist.MomentumDivergence.Value = fmt.Sprintf("%%K %.1f, %%D %.1f", indicators.StochK, indicators.StochD)
+	checklist.MomentumDivergence.Threshold = "%K at most 30 and above %D"
+	if setup.Direction == "bearish" {
+		checklist.MomentumDivergence.Threshold = "%K at least 70 and below %D"
+	}
+	if setup.Direction == "bullish" && indicators.StochK <= 30 && indicators.StochK > indicators.StochD {
+		checklist.MomentumDivergence.IsCompleted = true
+		checklist.MomentumDivergence.Points = 5
+		checklist.MomentumDivergence.AutoDetected = true
+	} else if setup.Direction == "bearish" && indicators.StochK >= 70 && indicators.StochK < indicators.StochD {
+		checklist.MomentumDivergence.IsCompleted = true
+		checklist.MomentumDivergence.Points = 5
+		checklist.MomentumDivergence.AutoDetected = true
+	}
+
+	checklist.RSICondition.LastChecked = clockNow()
+	checklist.MovingAverage.LastChecked = clockNow()
+	checklist.MACDSignal.LastChecked = clockNow()
+	checklist.MomentumDivergence.LastChecked = clockNow()
 }
 
 // evaluateRiskManagementCriteria evaluates risk management criteria
-func (sds *SetupDetectionService) evaluateRiskManagementCriteria(checklist *models.SetupChecklist, setup *models.TradingSetup) {
+func (sds *SetupDetectionService) evaluateRiskManagementCriteria(checklist *models.SetupChecklist, setup *models.TradingSetup, indicators *models.TechnicalIndicators) {
 	// Stop Loss Defined
+	checklist.StopLossDefined.Value, checklist.StopLossDefined.Threshold = fmt.Sprintf("stop $%.2f", setup.StopLoss), "a stop loss"
 	if setup.StopLoss > 0 {
 		checklist.StopLossDefined.IsCompleted = true
 		checklist.StopLossDefined.Points = 5
@@ -510,29 +938,57 @@ func (sds *SetupDetectionService) evaluateRiskManagementCriteria(checklist *mode
 	}
 
 	// Risk/Reward Ratio
+	checklist.RiskRewardRatio.Value = fmt.Sprintf("%.2f:1", setup.RiskRewardRatio)
+	checklist.RiskRewardRatio.Threshold = fmt.Sprintf("at least %.2f:1", sds.config.MinRiskRewardRatio)
 	if setup.RiskRewardRatio >= sds.config.MinRiskRewardRatio {
 		checklist.RiskRewardRatio.IsCompleted = true
 		checklist.RiskRewardRatio.Points = 5
 		checklist.RiskRewardRatio.AutoDetected = true
 	}
 
+	// Position Size: stop distance keeps risk within the per-trade limit
+	if setup.EntryPrice > 0 {
+		checklist.PositionSize.Value = fmt.Sprintf("%.2f%% risk to stop", setup.GetRiskAmount()/setup.EntryPrice*100)
+	}
+	checklist.PositionSize.Threshold = fmt.Sprintf("at most %.2f%%", sds.config.MaxRiskPercent)
+	if setup.EntryPrice > 0 && setup.GetRiskAmount()/setup.EntryPrice*100 <= sds.config.MaxRiskPercent {
+		checklist.PositionSize.IsCompleted = true
+		checklist.PositionSize.Points = 5
+		checklist.PositionSize.AutoDetected = true
+	}
+
+	// Entry Precision: entry within one ATR of the key level
+	if setup.KeyLevel != nil {
+		checklist.EntryPrecision.Value = fmt.Sprintf("entry $%.2f from the key level", math.Abs(setup.EntryPrice-setup.KeyLevel.Level))
+		checklist.EntryPrecision.Threshold = fmt.Sprintf("within 1 ATR ($%.2f)", indicators.ATR14)
+	}
+	if setup.KeyLevel != nil && indicators.ATR14 > 0 && math.Abs(setup.EntryPrice-setup.KeyLevel.Level) <= indicators.ATR14 {
+		checklist.EntryPrecision.IsCompleted = true
+		checklist.EntryPrecision.Points = 5
+		checklist.EntryPrecision.AutoDetected = true
+	}
+
 	// Exit Strategy
+	checklist.ExitStrategy.Value, checklist.ExitStrategy.Threshold = fmt.Sprintf("target $%.2f", setup.Target1), "a profit target"
 	if setup.Target1 > 0 {
 		checklist.ExitStrategy.IsCompleted = true
 		checklist.ExitStrategy.Points = 5
 		checklist.ExitStrategy.AutoDetected = true
 	}
 
-	checklist.StopLossDefined.LastChecked = time.Now()
-	checklist.RiskRewardRatio.LastChecked = time.Now()
-	checklist.ExitStrategy.LastChecked = time.Now()
+	checklist.StopLossDefined.LastChecked = clockNow()
+	checklist.RiskRewardRatio.LastChecked = clockNow()
+	checklist.PositionSize.LastChecked = clockNow()
+	checklist.EntryPrecision.LastChecked = clockNow()
+	checklist.ExitStrategy.LastChecked = clockNow()
 }
 
 // buildSetupSummary creates a summary of detected setups
 func (sds *SetupDetectionService) buildSetupSummary(setups []*models.TradingSetup) *models.SetupSummary {
 	summary := &models.SetupSummary{
 		TotalSetups:   len(setups),
-		LastDetection: time.Now(),
+		ByType:        make(map[string]int),
+		LastDetection: clockNow(),
 	}
 
 	if len(setups) == 0 {
@@ -557,12 +1013,13 @@ func (sds *SetupDetectionService) buildSetupSummary(setups []*models.TradingSetu
 			summary.LowQualityCount++
 		}
 
-		// Count by direction
+		// Count by direction and type
 		if setup.Direction == "bullish" {
 			summary.BullishCount++
 		} else {
 			summary.BearishCount++
 		}
+		summary.ByType[setup.SetupType]++
 
 		// Track averages
 		totalScore += setup.QualityScore
@@ -582,8 +1039,8 @@ func (sds *SetupDetectionService) buildSetupSummary(setups []*models.TradingSetu
 }
 
 // getCurrentPrice gets the current price for a symbol
-func (sds *SetupDetectionService) getCurrentPrice(symbol string) (float64, error) {
-	latestPrice, err := sds.db.GetLatestPriceData(symbol)
+func (sds *SetupDetectionService) getCurrentPrice(ctx context.Context, symbol string) (float64, error) {
+	latestPrice, err := sds.db.WithContext(ctx).GetLatestPriceData(symbol)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get latest price data: %w", err)
 	}
@@ -595,13 +1052,6 @@ func (sds *SetupDetectionService) getCurrentPrice(symbol string) (float64, error
 	return latestPrice.Close, nil
 }
 
-// UpdateSetupStatus updates the status of setups based on current market conditions
-func (sds *SetupDetectionService) UpdateSetupStatus(symbol string) error {
-	// This would be implemented to check existing setups and update their status
-	// based on current price movements, trigger conditions, etc.
-	return nil
-}
-
 // GetActiveSetups retrieves all active setups for a symbol
 func (sds *SetupDetectionService) GetActiveSetups(symbol string) ([]*models.TradingSetup, error) {
 	// This would query the database for active setups
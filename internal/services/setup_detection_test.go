@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestDetectSetupsCancelled tests a cancelled context stops a setup scan with an error, rather than a result
+// reporting the failed loads
+func TestDetectSetupsCancelled(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.InsertPriceData(&models.PriceData{Symbol: "TEST", Timestamp: time.Now().Add(-time.Minute),
+		Open: 100, High: 101, Low: 99, Close: 100, Volume: 1000}); err != nil {
+		t.Fatalf("InsertPriceData failed: %v", err)
+	}
+	taService := NewTechnicalAnalysisService(db, nil)
+	sds := NewSetupDetectionService(db, taService, NewSupportResistanceService(db, taService))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if result, err := sds.DetectSetups(ctx, "TEST"); !errors.Is(err, context.Canceled) || result != nil {
+		t.Errorf("expected the scan cancelled, got %+v (%v)", result, err)
+	}
+
+	// The same scan runs under a live context
+	result, err := sds.DetectSetups(context.Background(), "TEST")
+	if err != nil || result == nil {
+		t.Fatalf("DetectSetups failed: %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// openingRangeOrigin marks the key level of an opening range breakout setup
+const openingRangeOrigin = "opening_range"
+
+// openingRange is a session's opening range and the first bars after it closing beyond it on confirmed volume
+type openingRange struct {
+	High      float64
+	Low       float64
+	AvgVolume float64 // average volume of the bars in the range
+	Breakout  *models.PriceData
+	Breakdown *models.PriceData
+}
+
+// measureOpeningRange measures the range of the bars before rangeEnd and finds the first later bars closing
+// above and below it on at least volumeMultiplier times the range's average bar volume. Bars must be one
+// session's, oldest first. It returns nil without bars both in and after the range.
+func measureOpeningRange(bars []*models.PriceData, rangeEnd time.Time, volumeMultiplier float64) *openingRange {
+	var or *openingRange
+	totalVolume, count := 0.0, 0
+	for _, bar := range bars {
+		if !bar.Timestamp.Before(rangeEnd) {
+			break
+		}
+		if or == nil {
+			or = &openingRange{High: bar.High, Low: bar.Low}
+		}
+		or.High = max(or.High, bar.High)
+		or.Low = min(or.Low, bar.Low)
+		totalVolume += float64(bar.Volume)
+		count++
+	}
+	if or == nil || count == len(bars) {
+		return nil
+	}
+	or.AvgVolume = totalVolume / float64(count)
+
+	for _, bar := range bars[count:] {
+		if float64(bar.Volume) < or.AvgVolume*volumeMultiplier {
+			continue
+		}
+		if or.Breakout == nil && bar.Close > or.High {
+			or.Breakout = bar
+		}
+		if or.Breakdown == nil && bar.Close < or.Low {
+			or.Breakdown = bar
+		}
+	}
+
+	return or
+}
+
+// isOpeningRangeSetup reports whether a setup is an opening range breakout or breakdown
+func isOpeningRangeSetup(setup *models.TradingSetup) bool {
+	return setup.SetupType == models.SetupTypeOpeningRangeBreakout || setup.SetupType == models.SetupTypeOpeningRangeBreakdown
+}
+
+// detectOpeningRangeBreakoutSetups identifies breakouts from today's opening range that price still holds.
+// Stops go at the middle of the range, targets at one, two and three range heights past the broken edge, and
+// the setups expire at the close.
+func (sds *SetupDetectionService) detectOpeningRangeBreakoutSetups(ctx context.Context, symbol string, currentPrice float64) []*models.TradingSetup {
+	if sds.sessions == nil {
+		return nil
+	}
+
+	now := clockNow()
+	open, closeAt, ok := sds.sessions.RegularSession(now)
+	rangeEnd := open.Add(time.Duration(sds.config.ORBRangeMinutes) * time.Minute)
+	if !ok || now.Before(rangeEnd) || !now.Before(closeAt) {
+		return nil
+	}
+
+	bars, err := sds.db.WithContext(ctx).GetPriceDataRange(symbol, open, now)
+	if err != nil {
+		log.Printf("Failed to get %s bars for the opening range: %v", symbol, err)
+		return nil
+	}
+	or := measureOpeningRange(sds.sessions.RegularSessionBars(bars), rangeEnd, sds.config.ORBVolumeMultiplier)
+	if or == nil || or.High <= or.Low {
+		return nil
+	}
+
+	height := or.High - or.Low
+	newSetup := func(setupType, direction string, edge, sign float64, breakout *models.PriceData) *models.TradingSetup {
+		level := &models.SupportResistanceLevel{
+			Symbol:          symbol,
+			Level:           edge,
+			FirstTouch:      open,
+			LastTouch:       breakout.Timestamp,
+			VolumeConfirmed: true,
+			AvgVolume:       or.AvgVolume,
+			TimeframeOrigin: openingRangeOrigin,
+			IsActive:        true,
+		}
+		setup := &models.TradingSetup{
+			Symbol:       symbol,
+			SetupType:    setupType,
+			Direction:    direction,
+			Status:       "active",
+			DetectedAt:   now,
+			ExpiresAt:    closeAt,
+			CurrentPrice: currentPrice,
+			EntryPrice:   currentPrice,
+			StopLoss:     (or.High + or.Low) / 2,
+			Target1:      edge + sign*height,
+			Target2:      edge + sign*2*height,
+			Target3:      edge + sign*3*height,
+			KeyLevel:     level,
+			Notes:        fmt.Sprintf("%d-minute opening range $%.2f-$%.2f broken at %s", sds.config.ORBRangeMinutes, or.Low, or.High, breakout.Timestamp.In(sds.sessions.Location()).Format("15:04")),
+			IsManual:     false,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+		if direction == "bullish" {
+			level.LevelType = "resistance"
+			setup.ResistanceLevel = level
+		} else {
+			level.LevelType = "support"
+			setup.SupportLevel = level
+		}
+
+		setup.RiskAmount = setup.GetRiskAmount()
+		setup.RewardPotential = setup.GetRewardPotential()
+		setup.RiskRewardRatio = setup.CalculateRiskRewardRatio()
+		return setup
+	}
+
+	// Breakouts that price fell back from, or that already ran to the first target, are skipped
+	var setups []*models.TradingSetup
+	if or.Breakout != nil && currentPrice > or.High && currentPrice < or.High+height {
+		setups = append(setups, newSetup(models.SetupTypeOpeningRangeBreakout, "bullish", or.High, 1, or.Breakout))
+	}
+	if or.Breakdown != nil && currentPrice < or.Low && currentPrice > or.Low-height {
+		setups = append(setups, newSetup(models.SetupTypeOpeningRangeBreakdown, "bearish", or.Low, -1, or.Breakdown))
+	}
+
+	return setups
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// orbBars builds consecutive 5-minute bars from low/high/close/volume quadruples
+func orbBars(start time.Time, bars ...[4]float64) []*models.PriceData {
+	data := make([]*models.PriceData, len(bars))
+	for i, b := range bars {
+		data[i] = &models.PriceData{
+			Symbol:    "TEST",
+			Timestamp: start.Add(time.Duration(i) * 5 * time.Minute),
+			Open:      b[2], Low: b[0], High: b[1], Close: b[2], Volume: int64(b[3]),
+		}
+	}
+	return data
+}
+
+// TestMeasureOpeningRange tests the range of the first bars and the first volume confirmed closes beyond it
+func TestMeasureOpeningRange(t *testing.T) {
+	open := time.Date(2025, 3, 4, 14, 30, 0, 0, time.UTC)
+	rangeEnd := open.Add(15 * time.Minute)
+
+	bars := orbBars(open,
+		[4]float64{99, 101, 100, 1000}, [4]float64{98, 100.5, 99, 2000}, [4]float64{99, 102, 101, 3000},
+		[4]float64{101, 103, 102.5, 2000}, // above the range on thin volume
+		[4]float64{102, 104, 103.5, 4000}, // the confirmed breakout
+		[4]float64{96, 99, 97, 5000},      // the confirmed breakdown
+	)
+	or := measureOpeningRange(bars, rangeEnd, 1.5)
+	if or == nil {
+		t.Fatal("expected an opening range")
+	}
+	if or.High != 102 || or.Low != 98 || or.AvgVolume != 2000 {
+		t.Errorf("unexpected range: %+v", or)
+	}
+	if or.Breakout != bars[4] {
+		t.Errorf("expected the breakout at the confirmed bar, got %+v", or.Breakout)
+	}
+	if or.Breakdown != bars[5] {
+		t.Errorf("expected the breakdown at the confirmed bar, got %+v", or.Breakdown)
+	}
+
+	if measureOpeningRange(bars[:3], rangeEnd, 1.5) != nil {
+		t.Error("expected no range without bars after it")
+	}
+	if measureOpeningRange(bars[3:], rangeEnd, 1.5) != nil {
+		t.Error("expected no range without bars in it")
+	}
+}
+
+// TestDetectOpeningRangeBreakoutSetups tests the stop, targets and expiry of a setup from a held breakout
+func TestDetectOpeningRangeBreakoutSetups(t *testing.T) {
+	cfg := &config.Config{}
+	db := newTestDB(t)
+
+	open := time.Date(2025, 3, 4, 14, 30, 0, 0, time.UTC)
+	bars := orbBars(open,
+		[4]float64{99, 101, 100, 1000}, [4]float64{98, 100.5, 99, 1000}, [4]float64{99, 102, 101, 1000},
+		[4]float64{101, 104, 103.5, 2000},
+	)
+	if err := db.InsertPriceDataBatch(bars); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+
+	SetClock(NewSimulatedClock(open.Add(30 * time.Minute)))
+	t.Cleanup(func() { SetClock(nil) })
+
+	sds := NewSetupDetectionService(db, nil, nil)
+	if setups := sds.detectOpeningRangeBreakoutSetups(context.Background(), "TEST", 103.5); setups != nil {
+		t.Errorf("expected no setups without a market calendar, got %d", len(setups))
+	}
+
+	sds.SetMarketCalendar(NewMarketCalendar(cfg.MarketHours))
+	setups := sds.detectOpeningRangeBreakoutSetups(context.Background(), "TEST", 103.5)
+	if len(setups) != 1 {
+		t.Fatalf("expected one setup, got %d", len(setups))
+	}
+	setup := setups[0]
+	if setup.SetupType != models.SetupTypeOpeningRangeBreakout || setup.Direction != "bullish" {
+		t.Errorf("unexpected setup: %s %s", setup.SetupType, setup.Direction)
+	}
+	if setup.StopLoss != 100 || setup.Target1 != 106 || setup.Target2 != 110 || setup.Target3 != 114 {
+		t.Errorf("unexpected levels: stop %.2f, targets %.2f/%.2f/%.2f", setup.StopLoss, setup.Target1, setup.Target2, setup.Target3)
+	}
+	if !setup.ExpiresAt.Equal(time.Date(2025, 3, 4, 21, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the setup to expire at the close, got %v", setup.ExpiresAt)
+	}
+	if setup.KeyLevel == nil || !setup.KeyLevel.VolumeConfirmed || setup.ResistanceLevel != setup.KeyLevel {
+		t.Errorf("expected a volume confirmed resistance key level, got %+v", setup.KeyLevel)
+	}
+
+	// Price back inside the range holds no breakout
+	if setups := sds.detectOpeningRangeBreakoutSetups(context.Background(), "TEST", 101); len(setups) != 0 {
+		t.Errorf("expected no setups back inside the range, got %d", len(setups))
+	}
+}
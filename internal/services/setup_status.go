@@ -0,0 +1,197 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// setupTransitions lists the statuses each tracked status may advance to. A setup can complete or stop out
+// within a single collection cycle of being triggered, so active setups may skip straight to those.
+var setupTransitions = map[string][]string{
+	models.SetupStatusActive: {
+		models.SetupStatusTriggered, models.SetupStatusInvalidated, models.SetupStatusCompleted, models.SetupStatusStoppedOut,
+	},
+	models.SetupStatusTriggered: {models.SetupStatusCompleted, models.SetupStatusStoppedOut},
+}
+
+// UpdateSetupStatus advances the symbol's active and triggered setups through the price bars collected
+// since they were detected, saving each status change and sending an alert for it. Setups still triggered
+// afterwards get their suggested stop updated.
+func (sds *SetupDetectionService) UpdateSetupStatus(symbol string) error {
+	var setups []*models.TradingSetup
+	for status := range setupTransitions {
+		found, err := sds.db.GetTradingSetups(&models.SetupFilter{Symbol: symbol, Status: status})
+		if err != nil {
+			return fmt.Errorf("failed to get %s setups: %w", status, err)
+		}
+		setups = append(setups, found...)
+	}
+	if len(setups) == 0 {
+		return nil
+	}
+
+	from := setups[0].DetectedAt
+	for _, setup := range setups {
+		if setup.DetectedAt.Before(from) {
+			from = setup.DetectedAt
+		}
+	}
+	bars, err := sds.db.GetPriceDataRange(symbol, from, clockNow())
+	if err != nil {
+		return fmt.Errorf("failed to get price data: %w", err)
+	}
+
+	atr := sync.OnceValue(func() float64 { return sds.trailingATR(symbol) })
+	for _, setup := range setups {
+		status, bar := replaySetupStatus(setup, bars)
+		if bar == nil {
+			continue
+		}
+
+		if slices.Contains(setupTransitions[setup.Status], status) {
+			previous := setup.Status
+			setup.CurrentPrice = bar.Close
+			setup.UpdateStatus(status)
+			if err := sds.db.UpdateTradingSetup(setup); err != nil {
+				log.Printf("Failed to update status of setup %d for %s: %v", setup.ID, symbol, err)
+				continue
+			}
+
+			log.Printf("Setup %d for %s %s -> %s at %s", setup.ID, symbol, previous, status, bar.Timestamp.Format(time.RFC3339))
+			sds.notifySetupTransition(setup, bar)
+
+			if status == models.SetupStatusTriggered && sds.broker.IsEnabled() {
+				if _, err := sds.broker.PlaceSetupOrder(setup, bar.Timestamp); err != nil {
+					log.Printf("Failed to place broker order for setup %d for %s: %v", setup.ID, symbol, err)
+				}
+			}
+		}
+
+		// The bar returned for a triggered setup is its entry bar
+		if setup.Status == models.SetupStatusTriggered && status == models.SetupStatusTriggered {
+			if err := sds.updateSuggestedStop(setup, barsAfter(bars, bar), atr); err != nil {
+				log.Printf("Failed to update suggested stop of setup %d for %s: %v", setup.ID, symbol, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// replaySetupStatus walks a setup from active through the bars since its detection and returns the status
+// it ends in with the bar of its last change, or nil if it never changed. A triggered setup completes at its
+// last target. Within a bar the stop is checked first, so a bar spanning both the stop and another level
+// counts against the setup.
+func replaySetupStatus(setup *models.TradingSetup, bars []*models.PriceData) (string, *models.PriceData) {
+	status := models.SetupStatusActive
+	if setup.EntryPrice <= 0 || setup.StopLoss <= 0 {
+		return status, nil
+	}
+	bearish := setup.Direction == "bearish"
+	target := finalTarget(setup)
+
+	var changed *models.PriceData
+	var prevClose float64
+	for _, bar := range bars {
+		if bar.Timestamp.Before(setup.DetectedAt) {
+			continue
+		}
+
+		stopHit := bar.Low <= setup.StopLoss
+		targetHit := target > 0 && bar.High >= target
+		if bearish {
+			stopHit = bar.High >= setup.StopLoss
+			targetHit = target > 0 && bar.Low <= target
+		}
+
+		switch status {
+		case models.SetupStatusActive:
+			if !setup.ExpiresAt.IsZero() && bar.Timestamp.After(setup.ExpiresAt) {
+				// Expiry is left to ExpireOldSetups
+				return status, changed
+			}
+			if stopHit {
+				return models.SetupStatusInvalidated, bar
+			}
+			// Extending the bar to the previous close catches gaps across the entry
+			low, high := bar.Low, bar.High
+			if prevClose > 0 {
+				low, high = min(low, prevClose), max(high, prevClose)
+			}
+			if low <= setup.EntryPrice && setup.EntryPrice <= high {
+				status, changed = models.SetupStatusTriggered, bar
+			}
+		case models.SetupStatusTriggered:
+			if stopHit {
+				return models.SetupStatusStoppedOut, bar
+			}
+			if targetHit {
+				return models.SetupStatusCompleted, bar
+			}
+		}
+		prevClose = bar.Close
+	}
+
+	return status, changed
+}
+
+// finalTarget returns the setup's last set target, which completes it
+func finalTarget(setup *models.TradingSetup) float64 {
+	for _, target := range []float64{setup.Target3, setup.Target2} {
+		if target > 0 {
+			return target
+		}
+	}
+	return setup.Target1
+}
+
+// notifySetupTransition records a setup's new status in the notification center, sends it to Telegram and
+// posts triggered and invalidated setups to webhooks
+func (sds *SetupDetectionService) notifySetupTransition(setup *models.TradingSetup, bar *models.PriceData) {
+	var icon, message string
+	severity := EmailSeverityMedium
+	switch setup.Status {
+	case models.SetupStatusTriggered:
+		icon, severity = "▶️", EmailSeverityHigh
+		message = fmt.Sprintf("Entry $%.2f printed", setup.EntryPrice)
+	case models.SetupStatusInvalidated:
+		icon = "❌"
+		message = fmt.Sprintf("Price crossed the $%.2f stop before the $%.2f entry", setup.StopLoss, setup.EntryPrice)
+	case models.SetupStatusCompleted:
+		icon, severity = "🎯", EmailSeverityHigh
+		message = fmt.Sprintf("Target $%.2f reached", finalTarget(setup))
+	case models.SetupStatusStoppedOut:
+		icon = "🛑"
+		message = fmt.Sprintf("Stopped out at $%.2f after entry", setup.StopLoss)
+	}
+
+	title := fmt.Sprintf("%s %s: %s %s", icon, setup.Symbol, setupTitle(setup), patternDisplayName(setup.Status))
+	message = fmt.Sprintf("%s at %s (last $%.2f)\n%s", message, bar.Timestamp.Format("Jan 2 15:04 MST"), bar.Close, formatSetupLevels(setup))
+
+	sds.notifications.Notify(&models.Notification{
+		Category: models.NotificationSetup,
+		Severity: severity,
+		Symbol:   setup.Symbol,
+		Title:    title,
+		Message:  message,
+	})
+
+	if sds.telegram.IsEnabled() {
+		if err := sds.telegram.SendMessage(title + "\n" + message); err != nil {
+			log.Printf("Failed to send setup status alert for %s: %v", setup.Symbol, err)
+		}
+	}
+
+	event := models.WebhookSetupTriggered
+	if setup.Status == models.SetupStatusInvalidated {
+		event = models.WebhookSetupInvalidated
+	} else if setup.Status != models.SetupStatusTriggered {
+		return
+	}
+	sds.webhooks.Emit(event, setup.Symbol, &models.WebhookSetupData{Setup: setup, Price: bar.Close, BarAt: &bar.Timestamp, Reason: message})
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// statusBars builds consecutive 5-minute bars from low/high/close triples
+func statusBars(start time.Time, ranges ...[3]float64) []*models.PriceData {
+	bars := make([]*models.PriceData, len(ranges))
+	for i, r := range ranges {
+		bars[i] = &models.PriceData{
+			Symbol:    "TEST",
+			Timestamp: start.Add(time.Duration(i) * 5 * time.Minute),
+			Open:      r[2], Low: r[0], High: r[1], Close: r[2], Volume: 1000,
+		}
+	}
+	return bars
+}
+
+// TestReplaySetupStatus tests the status a setup ends in after a series of bars
+func TestReplaySetupStatus(t *testing.T) {
+	detected := time.Date(2025, 3, 3, 15, 0, 0, 0, time.UTC)
+	bullish := &models.TradingSetup{Direction: "bullish", DetectedAt: detected, EntryPrice: 100, StopLoss: 95, Target1: 110}
+	bearish := &models.TradingSetup{Direction: "bearish", DetectedAt: detected, EntryPrice: 100, StopLoss: 105, Target1: 90}
+
+	tests := []struct {
+		name   string
+		setup  *models.TradingSetup
+		bars   [][3]float64
+		status string
+		bar    int // index of the bar of the last change, or -1
+	}{
+		{"waiting", bullish, [][3]float64{{101, 103, 102}, {101.5, 104, 103}}, models.SetupStatusActive, -1},
+		{"entry prints", bullish, [][3]float64{{101, 103, 102}, {99.5, 102, 100.5}}, models.SetupStatusTriggered, 1},
+		{"stop before entry", bullish, [][3]float64{{96, 99, 97}, {94, 97, 94.5}}, models.SetupStatusInvalidated, 1},
+		{"target after entry", bullish, [][3]float64{{99, 101, 100}, {100, 106, 105}, {105, 111, 110}}, models.SetupStatusCompleted, 2},
+		{"stopped out", bullish, [][3]float64{{99, 101, 100}, {94.5, 100, 95}}, models.SetupStatusStoppedOut, 1},
+		{"target in entry bar waits", bullish, [][3]float64{{99, 111, 108}}, models.SetupStatusTriggered, 0},
+		{"gap across entry", bullish, [][3]float64{{97, 98, 98}, {101, 102, 101.5}}, models.SetupStatusTriggered, 1},
+		{"bearish target", bearish, [][3]float64{{99, 101, 100}, {89, 95, 90}}, models.SetupStatusCompleted, 1},
+		{"bearish stop before entry", bearish, [][3]float64{{101, 104, 103}, {102, 105.5, 105}}, models.SetupStatusInvalidated, 1},
+	}
+	for _, tt := range tests {
+		bars := statusBars(detected.Add(5*time.Minute), tt.bars...)
+		status, bar := replaySetupStatus(tt.setup, bars)
+		if status != tt.status {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.status, status)
+		}
+		if (tt.bar < 0) != (bar == nil) || (bar != nil && bar != bars[tt.bar]) {
+			t.Errorf("%s: expected the change at bar %d, got %+v", tt.name, tt.bar, bar)
+		}
+	}
+
+	// Bars before detection and after expiry don't count for an active setup
+	expiring := *bullish
+	expiring.ExpiresAt = detected.Add(5 * time.Minute)
+	bars := statusBars(detected.Add(-5*time.Minute), [3]float64{99, 101, 100}, [3]float64{101, 102, 101}, [3]float64{101, 102, 101}, [3]float64{99, 101, 100})
+	if status, _ := replaySetupStatus(&expiring, bars); status != models.SetupStatusActive {
+		t.Errorf("expected bars outside the setup window to be ignored, got %s", status)
+	}
+}
+
+// TestUpdateSetupStatus tests that status changes are saved and notified while unchanged setups are left alone
+func TestUpdateSetupStatus(t *testing.T) {
+	db := newTestDB(t)
+
+	sds := NewSetupDetectionService(db, nil, nil)
+	sds.SetNotificationService(NewNotificationService(db))
+
+	detected := time.Now().UTC().Add(-time.Hour).Truncate(time.Minute)
+	newSetup := func(status string, entry float64) *models.TradingSetup {
+		setup := &models.TradingSetup{
+			Symbol: "TEST", SetupType: "support_bounce", Direction: "bullish", Status: status,
+			DetectedAt: detected, ExpiresAt: detected.Add(48 * time.Hour),
+			EntryPrice: entry, StopLoss: 95, Target1: 110, CurrentPrice: 102, Confidence: "medium",
+		}
+		if err := db.InsertTradingSetup(setup); err != nil {
+			t.Fatalf("InsertTradingSetup failed: %v", err)
+		}
+		return setup
+	}
+	triggers := newSetup(models.SetupStatusActive, 100)
+	waits := newSetup(models.SetupStatusActive, 98)
+	completes := newSetup(models.SetupStatusTriggered, 102.5)
+
+	if err := db.InsertPriceDataBatch(statusBars(detected.Add(5*time.Minute),
+		[3]float64{101, 103, 102}, [3]float64{99.5, 102, 100}, [3]float64{100, 110.5, 110})); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+
+	if err := sds.UpdateSetupStatus("TEST"); err != nil {
+		t.Fatalf("UpdateSetupStatus failed: %v", err)
+	}
+
+	for _, tc := range []struct {
+		setup  *models.TradingSetup
+		status string
+	}{
+		{triggers, models.SetupStatusCompleted},
+		{waits, models.SetupStatusActive},
+		{completes, models.SetupStatusCompleted},
+	} {
+		stored, err := db.GetTradingSetupByID(tc.setup.ID)
+		if err != nil {
+			t.Fatalf("GetTradingSetupByID failed: %v", err)
+		}
+		if stored.Status != tc.status {
+			t.Errorf("setup with entry %.1f: expected %s, got %s", tc.setup.EntryPrice, tc.status, stored.Status)
+		}
+	}
+
+	notifications, err := db.GetNotifications(&models.NotificationFilter{Category: models.NotificationSetup})
+	if err != nil {
+		t.Fatalf("GetNotifications failed: %v", err)
+	}
+	if len(notifications) != 2 {
+		t.Fatalf("expected an alert for each of the 2 changed setups, got %d", len(notifications))
+	}
+
+	// A second cycle over the same bars changes nothing
+	if err := sds.UpdateSetupStatus("TEST"); err != nil {
+		t.Fatalf("UpdateSetupStatus failed: %v", err)
+	}
+	if notifications, _ = db.GetNotifications(&models.NotificationFilter{Category: models.NotificationSetup}); len(notifications) != 2 {
+		t.Errorf("expected no new alerts, got %d", len(notifications))
+	}
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"market-watch-go/internal/models"
+)
+
+// Trailing stop suggestions follow the ATR of hourly candles
+const (
+	stopTrailATRPeriod    = 14
+	stopTrailLookbackDays = 10
+	stopTrailMinStepATR   = 0.25 // Trailing moves smaller than this many ATRs aren't suggested
+)
+
+// updateSuggestedStop records and alerts on the stop move a triggered setup calls for after the bars
+// following its entry bar. The ATR is only loaded when the setup has reached its first target.
+func (sds *SetupDetectionService) updateSuggestedStop(setup *models.TradingSetup, after []*models.PriceData, atr func() float64) error {
+	if !firstTargetReached(setup, after) {
+		return nil
+	}
+
+	history, err := sds.db.GetSetupStopAdjustments(setup.ID)
+	if err != nil {
+		return err
+	}
+	current := setup.StopLoss
+	if len(history) > 0 {
+		current = history[len(history)-1].SuggestedStop
+	}
+
+	adjustment := suggestStopAdjustment(setup, after, current, atr(), sds.config.TrailATRMultiplier)
+	if adjustment == nil {
+		return nil
+	}
+	if err := sds.db.InsertSetupStopAdjustment(adjustment); err != nil {
+		return err
+	}
+
+	log.Printf("Setup %d for %s stop %.2f -> %.2f (%s)", setup.ID, setup.Symbol, adjustment.PreviousStop, adjustment.SuggestedStop, adjustment.Reason)
+	sds.notifyStopAdjustment(setup, adjustment)
+	return nil
+}
+
+// trailingATR returns the symbol's ATR on recent hourly candles, or 0 without enough history
+func (sds *SetupDetectionService) trailingATR(symbol string) float64 {
+	now := clockNow()
+	candles, err := sds.db.GetPriceDataRangeTimeframe(symbol, now.AddDate(0, 0, -stopTrailLookbackDays), now, models.Timeframe1h)
+	if err != nil {
+		log.Printf("Failed to get hourly candles for %s trailing stops: %v", symbol, err)
+		return 0
+	}
+
+	highs := make([]float64, len(candles))
+	lows := make([]float64, len(candles))
+	closes := make([]float64, len(candles))
+	for i, candle := range candles {
+		highs[i], lows[i], closes[i] = candle.High, candle.Low, candle.Close
+	}
+	return averageTrueRange(highs, lows, closes, stopTrailATRPeriod)
+}
+
+// barsAfter returns the bars following a bar, which must be one of them
+func barsAfter(bars []*models.PriceData, bar *models.PriceData) []*models.PriceData {
+	for i, candidate := range bars {
+		if candidate == bar {
+			return bars[i+1:]
+		}
+	}
+	return nil
+}
+
+// bestPrice returns the highest high of the bars, or the lowest low for a bearish setup
+func bestPrice(setup *models.TradingSetup, bars []*models.PriceData) float64 {
+	if setup.Direction == "bearish" {
+		best := bars[0].Low
+		for _, bar := range bars[1:] {
+			best = math.Min(best, bar.Low)
+		}
+		return best
+	}
+
+	best := bars[0].High
+	for _, bar := range bars[1:] {
+		best = math.Max(best, bar.High)
+	}
+	return best
+}
+
+// firstTargetReached reports whether any of the bars printed the setup's first target
+func firstTargetReached(setup *models.TradingSetup, bars []*models.PriceData) bool {
+	if setup.Target1 <= 0 || len(bars) == 0 {
+		return false
+	}
+	best := bestPrice(setup, bars)
+	if setup.Direction == "bearish" {
+		return best <= setup.Target1
+	}
+	return best >= setup.Target1
+}
+
+// suggestStopAdjustment returns the stop move a triggered setup calls for given the bars after its entry bar,
+// or nil when the current stop stands. Once the first target prints the stop goes to breakeven; from there it
+// trails the best price since entry by multiple ATRs, skipping moves under stopTrailMinStepATR ATRs.
+func suggestStopAdjustment(setup *models.TradingSetup, after []*models.PriceData, current, atr, multiple float64) *models.SetupStopAdjustment {
+	if !firstTargetReached(setup, after) {
+		return nil
+	}
+
+	// dir turns the bearish comparisons into bullish ones: a better stop is always a larger dir*stop
+	dir := 1.0
+	if setup.Direction == "bearish" {
+		dir = -1
+	}
+
+	best := bestPrice(setup, after)
+	suggested, reason := setup.EntryPrice, models.StopAdjustmentBreakeven
+	if dir*(current-setup.EntryPrice) >= 0 {
+		if atr <= 0 || multiple <= 0 {
+			return nil
+		}
+		suggested, reason = math.Round((best-dir*multiple*atr)*100)/100, models.StopAdjustmentATRTrail
+		if dir*(suggested-current) < stopTrailMinStepATR*atr {
+			return nil
+		}
+	}
+
+	last := after[len(after)-1]
+	return &models.SetupStopAdjustment{
+		SetupID:       setup.ID,
+		Reason:        reason,
+		PreviousStop:  current,
+		SuggestedStop: suggested,
+		BestPrice:     best,
+		ATR:           atr,
+		Price:         last.Close,
+		SuggestedAt:   last.Timestamp,
+	}
+}
+
+// notifyStopAdjustment records a suggested stop move in the notification center and sends it to Telegram
+func (sds *SetupDetectionService) notifyStopAdjustment(setup *models.TradingSetup, adjustment *models.SetupStopAdjustment) {
+	title := fmt.Sprintf("🔒 %s: %s stop to breakeven", setup.Symbol, setupTitle(setup))
+	message := fmt.Sprintf("First target $%.2f reached; move the stop from $%.2f to the $%.2f entry",
+		setup.Target1, adjustment.PreviousStop, adjustment.SuggestedStop)
+	if adjustment.Reason == models.StopAdjustmentATRTrail {
+		title = fmt.Sprintf("📈 %s: %s trail stop", setup.Symbol, setupTitle(setup))
+		message = fmt.Sprintf("Trail the stop from $%.2f to $%.2f, %.1f ATR ($%.2f) from the $%.2f best price",
+			adjustment.PreviousStop, adjustment.SuggestedStop, sds.config.TrailATRMultiplier, adjustment.ATR, adjustment.BestPrice)
+	}
+	message = fmt.Sprintf("%s (last $%.2f)\n%s", message, adjustment.Price, formatSetupLevels(setup))
+
+	sds.notifications.Notify(&models.Notification{
+		Category: models.NotificationSetup,
+		Severity: EmailSeverityMedium,
+		Symbol:   setup.Symbol,
+		Title:    title,
+		Message:  message,
+	})
+
+	if sds.telegram.IsEnabled() {
+		if err := sds.telegram.SendMessage(title + "\n" + message); err != nil {
+			log.Printf("Failed to send stop adjustment alert for %s: %v", setup.Symbol, err)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestSuggestStopAdjustment tests breakeven at the first target, ATR trailing afterwards and skipped small moves
+func TestSuggestStopAdjustment(t *testing.T) {
+	start := time.Date(2025, 3, 3, 15, 0, 0, 0, time.UTC)
+	bullish := &models.TradingSetup{Direction: "bullish", EntryPrice: 100, StopLoss: 95, Target1: 105, Target2: 120}
+	bearish := &models.TradingSetup{Direction: "bearish", EntryPrice: 100, StopLoss: 105, Target1: 95, Target2: 80}
+
+	tests := []struct {
+		name      string
+		setup     *models.TradingSetup
+		bars      [][3]float64
+		current   float64
+		atr       float64
+		reason    string
+		suggested float64
+	}{
+		{"before the first target", bullish, [][3]float64{{100, 104, 103}}, 95, 1, "", 0},
+		{"no trailing without an ATR", bullish, [][3]float64{{100, 110, 109}}, 100, 0, "", 0},
+		{"breakeven", bullish, [][3]float64{{100, 105.5, 105}}, 95, 1, models.StopAdjustmentBreakeven, 100},
+		{"breakeven without an ATR", bullish, [][3]float64{{100, 110, 109}}, 95, 0, models.StopAdjustmentBreakeven, 100},
+		{"trail", bullish, [][3]float64{{100, 106, 105}, {105, 110, 109}}, 100, 1, models.StopAdjustmentATRTrail, 108},
+		{"breakeven before trailing", bullish, [][3]float64{{100, 110, 109}}, 95, 1, models.StopAdjustmentBreakeven, 100},
+		{"trail step too small", bullish, [][3]float64{{100, 110.2, 109}}, 108, 1, "", 0},
+		{"trail behind breakeven", bullish, [][3]float64{{100, 105.5, 104}}, 100, 3, "", 0},
+		{"bearish breakeven", bearish, [][3]float64{{94.5, 99, 95}}, 105, 1, models.StopAdjustmentBreakeven, 100},
+		{"bearish trail", bearish, [][3]float64{{90, 95, 91}}, 100, 1, models.StopAdjustmentATRTrail, 92},
+	}
+	for _, tt := range tests {
+		bars := statusBars(start, tt.bars...)
+		adjustment := suggestStopAdjustment(tt.setup, bars, tt.current, tt.atr, 2)
+		if tt.reason == "" {
+			if adjustment != nil {
+				t.Errorf("%s: expected no adjustment, got %+v", tt.name, adjustment)
+			}
+			continue
+		}
+		if adjustment == nil || adjustment.Reason != tt.reason || adjustment.SuggestedStop != tt.suggested || adjustment.PreviousStop != tt.current {
+			t.Errorf("%s: expected %s from %.2f to %.2f, got %+v", tt.name, tt.reason, tt.current, tt.suggested, adjustment)
+		}
+	}
+}
+
+// TestUpdateSetupStatusSuggestsStops tests that a triggered setup past its first target gets one breakeven
+// suggestion, stored in its stop history, and keeps running to its last target
+func TestUpdateSetupStatusSuggestsStops(t *testing.T) {
+	db := newTestDB(t)
+
+	sds := NewSetupDetectionService(db, nil, nil)
+	sds.SetNotificationService(NewNotificationService(db))
+
+	detected := time.Now().UTC().Add(-time.Hour).Truncate(time.Minute)
+	setup := &models.TradingSetup{
+		Symbol: "TEST", SetupType: "support_bounce", Direction: "bullish", Status: models.SetupStatusTriggered,
+		Confidence: "medium", DetectedAt: detected, ExpiresAt: detected.Add(48 * time.Hour),
+		EntryPrice: 100, StopLoss: 95, Target1: 105, Target2: 115, CurrentPrice: 100,
+	}
+	if err := db.InsertTradingSetup(setup); err != nil {
+		t.Fatalf("InsertTradingSetup failed: %v", err)
+	}
+	if err := db.InsertPriceDataBatch(statusBars(detected.Add(5*time.Minute),
+		[3]float64{99.5, 101, 100.5}, [3]float64{100, 105.5, 105}, [3]float64{103, 106, 104})); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+
+	for range 2 {
+		if err := sds.UpdateSetupStatus("TEST"); err != nil {
+			t.Fatalf("UpdateSetupStatus failed: %v", err)
+		}
+	}
+
+	stored, err := db.GetTradingSetupByID(setup.ID)
+	if err != nil {
+		t.Fatalf("GetTradingSetupByID failed: %v", err)
+	}
+	if stored.Status != models.SetupStatusTriggered {
+		t.Errorf("expected the setup to keep running past its first target, got %s", stored.Status)
+	}
+	if len(stored.StopHistory) != 1 || stored.StopHistory[0].Reason != models.StopAdjustmentBreakeven || stored.SuggestedStop != 100 {
+		t.Errorf("expected a single breakeven suggestion, got %+v (suggested %.2f)", stored.StopHistory, stored.SuggestedStop)
+	}
+
+	notifications, err := db.GetNotifications(&models.NotificationFilter{Category: models.NotificationSetup})
+	if err != nil || len(notifications) != 1 {
+		t.Errorf("expected one stop alert, got %d (%v)", len(notifications), err)
+	}
+}
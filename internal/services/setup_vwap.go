@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// VWAP setup detection
+const (
+	sessionVWAPOrigin = "session_vwap"   // marks the key level of a VWAP setup
+	vwapSignalWindow  = 30 * time.Minute // how long after its signal bar a VWAP setup is still detected
+)
+
+// runningVWAP returns the session VWAP as of each bar: the volume weighted average typical price of the bar
+// and every earlier one. Bars must be one session's, oldest first.
+func runningVWAP(bars []*models.PriceData) []float64 {
+	vwaps := make([]float64, len(bars))
+	priceVolume, volume := 0.0, 0.0
+	for i, bar := range bars {
+		typical := (bar.High + bar.Low + bar.Close) / 3
+		priceVolume += typical * float64(bar.Volume)
+		volume += float64(bar.Volume)
+		if volume > 0 {
+			vwaps[i] = priceVolume / volume
+		} else {
+			vwaps[i] = typical
+		}
+	}
+	return vwaps
+}
+
+// lastVWAPSignal returns the index of the latest bar that reclaimed the VWAP from below, or for a rejection
+// traded up into it from below and closed back under it, on at least volumeMultiplier times the average volume
+// of the session's earlier bars. Every later bar must have closed on the signal's side of the VWAP. It returns
+// -1 without such a bar.
+func lastVWAPSignal(bars []*models.PriceData, vwaps []float64, volumeMultiplier float64, reclaim bool) int {
+	signal := -1
+	totalVolume := 0.0
+	for i, bar := range bars {
+		if i > 0 {
+			above := bar.Close > vwaps[i]
+			fromBelow := bars[i-1].Close < vwaps[i-1]
+			confirmed := float64(bar.Volume) >= totalVolume/float64(i)*volumeMultiplier
+
+			switch {
+			case reclaim && fromBelow && above && confirmed:
+				signal = i
+			case !reclaim && fromBelow && bar.High >= vwaps[i] && !above && confirmed:
+				signal = i
+			case signal >= 0 && above != reclaim:
+				signal = -1
+			}
+		}
+		totalVolume += float64(bar.Volume)
+	}
+	return signal
+}
+
+// isVWAPSetup reports whether a setup is a VWAP reclaim or rejection
+func isVWAPSetup(setup *models.TradingSetup) bool {
+	return setup.SetupType == models.SetupTypeVWAPReclaim || setup.SetupType == models.SetupTypeVWAPRejection
+}
+
+// detectVWAPSetups identifies recent reclaims and rejections of today's session VWAP that price still holds,
+// for symbols in a strategy with VWAP setups on. When several strategies turn a setup type on, the lowest
+// volume multiplier applies. Stops go past the signal bar, targets at one, two and three times the risk, and
+// the setups expire at the close.
+func (sds *SetupDetectionService) detectVWAPSetups(ctx context.Context, symbol string, currentPrice float64) []*models.TradingSetup {
+	if sds.sessions == nil {
+		return nil
+	}
+
+	db := sds.db.WithContext(ctx)
+	strategies, err := db.GetVWAPSetupsForSymbol(symbol)
+	if err != nil {
+		log.Printf("Failed to get VWAP setup settings for %s: %v", symbol, err)
+		return nil
+	}
+	reclaimMultiplier, rejectionMultiplier := -1.0, -1.0
+	for _, s := range strategies {
+		if s.Reclaim && (reclaimMultiplier < 0 || s.VolumeMultiplier < reclaimMultiplier) {
+			reclaimMultiplier = s.VolumeMultiplier
+		}
+		if s.Rejection && (rejectionMultiplier < 0 || s.VolumeMultiplier < rejectionMultiplier) {
+			rejectionMultiplier = s.VolumeMultiplier
+		}
+	}
+	if reclaimMultiplier < 0 && rejectionMultiplier < 0 {
+		return nil
+	}
+
+	now := clockNow()
+	open, closeAt, ok := sds.sessions.RegularSession(now)
+	if !ok || now.Before(open) || !now.Before(closeAt) {
+		return nil
+	}
+
+	bars, err := db.GetPriceDataRange(symbol, open, now)
+	if err != nil {
+		log.Printf("Failed to get %s bars for the session VWAP: %v", symbol, err)
+		return nil
+	}
+	bars = sds.sessions.RegularSessionBars(bars)
+	if len(bars) < 2 {
+		return nil
+	}
+	vwaps := runningVWAP(bars)
+	vwap := vwaps[len(vwaps)-1]
+
+	newSetup := func(setupType, direction string, signal int, stop float64) *models.TradingSetup {
+		bar := bars[signal]
+		avgVolume := 0.0
+		for _, earlier := range bars[:signal] {
+			avgVolume += float64(earlier.Volume)
+		}
+		avgVolume /= float64(signal)
+
+		level := &models.SupportResistanceLevel{
+			Symbol:          symbol,
+			Level:           vwap,
+			FirstTouch:      open,
+			LastTouch:       bar.Timestamp,
+			VolumeConfirmed: true,
+			AvgVolume:       avgVolume,
+			TimeframeOrigin: sessionVWAPOrigin,
+			IsActive:        true,
+		}
+		risk := currentPrice - stop
+		setup := &models.TradingSetup{
+			Symbol:       symbol,
+			SetupType:    setupType,
+			Direction:    direction,
+			Status:       "active",
+			DetectedAt:   now,
+			ExpiresAt:    closeAt,
+			CurrentPrice: currentPrice,
+			EntryPrice:   currentPrice,
+			StopLoss:     stop,
+			Target1:      currentPrice + risk,
+			Target2:      currentPrice + 2*risk,
+			Target3:      currentPrice + 3*risk,
+			KeyLevel:     level,
+			IsManual:     false,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+
+		verb := "Reclaimed"
+		if direction == "bullish" {
+			level.LevelType = "support"
+			setup.SupportLevel = level
+		} else {
+			verb = "Rejected at"
+			level.LevelType = "resistance"
+			setup.ResistanceLevel = level
+		}
+		setup.Notes = fmt.Sprintf("%s session VWAP $%.2f at %s on %.1fx average volume", verb, vwaps[signal],
+			bar.Timestamp.In(sds.sessions.Location()).Format("15:04"), float64(bar.Volume)/avgVolume)
+
+		setup.RiskAmount = setup.GetRiskAmount()
+		setup.RewardPotential = setup.GetRewardPotential()
+		setup.RiskRewardRatio = setup.CalculateRiskRewardRatio()
+		return setup
+	}
+
+	// Signals older than the window, or with the stop already beyond the price, are skipped
+	recent := func(signal int) bool {
+		return signal > 0 && now.Sub(bars[signal].Timestamp) <= vwapSignalWindow
+	}
+	var setups []*models.TradingSetup
+	if reclaimMultiplier >= 0 && currentPrice > vwap {
+		if signal := lastVWAPSignal(bars, vwaps, reclaimMultiplier, true); recent(signal) && bars[signal].Low < currentPrice {
+			setups = append(setups, newSetup(models.SetupTypeVWAPReclaim, "bullish", signal, bars[signal].Low))
+		}
+	}
+	if rejectionMultiplier >= 0 && currentPrice < vwap {
+		if signal := lastVWAPSignal(bars, vwaps, rejectionMultiplier, false); recent(signal) && bars[signal].High > currentPrice {
+			setups = append(setups, newSetup(models.SetupTypeVWAPRejection, "bearish", signal, bars[signal].High))
+		}
+	}
+
+	return setups
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// vwapOpen is the regular session open of the VWAP test bars
+var vwapOpen = time.Date(2025, 3, 4, 14, 30, 0, 0, time.UTC)
+
+// vwapBars builds a session that dips below its VWAP and then trades the given bar
+func vwapBars(last ...[4]float64) []*models.PriceData {
+	return orbBars(vwapOpen, append([][4]float64{
+		{100, 102, 101, 1000}, {99, 101, 99.5, 1000}, {98.5, 100, 99, 1000},
+	}, last...)...)
+}
+
+// TestLastVWAPSignal tests reclaims and rejections of the VWAP on volume and signals undone by later closes
+func TestLastVWAPSignal(t *testing.T) {
+	tests := []struct {
+		name    string
+		bars    []*models.PriceData
+		reclaim bool
+		signal  int
+	}{
+		{"reclaim", vwapBars([4]float64{99, 102, 101.5, 2000}), true, 3},
+		{"reclaim on thin volume", vwapBars([4]float64{99, 102, 101.5, 1000}), true, -1},
+		{"reclaim lost", vwapBars([4]float64{99, 102, 101.5, 2000}, [4]float64{98, 100, 98.5, 1000}), true, -1},
+		{"rejection", vwapBars([4]float64{99, 100.5, 99.2, 2000}), false, 3},
+		{"rejection is no reclaim", vwapBars([4]float64{99, 100.5, 99.2, 2000}), true, -1},
+		{"reclaim is no rejection", vwapBars([4]float64{99, 102, 101.5, 2000}), false, -1},
+	}
+	for _, tt := range tests {
+		vwaps := runningVWAP(tt.bars)
+		if signal := lastVWAPSignal(tt.bars, vwaps, 1.5, tt.reclaim); signal != tt.signal {
+			t.Errorf("%s: expected signal %d, got %d", tt.name, tt.signal, signal)
+		}
+	}
+}
+
+// TestDetectVWAPSetups tests that VWAP setups are only detected for strategies turning them on
+func TestDetectVWAPSetups(t *testing.T) {
+	cfg := &config.Config{}
+	db := newTestDB(t)
+
+	strategy, err := db.CreateStrategy(models.Strategy{Name: "Intraday"})
+	if err != nil {
+		t.Fatalf("CreateStrategy failed: %v", err)
+	}
+	stock, err := db.AddStock(models.Stock{Symbol: "TEST", Name: "Test Corp"})
+	if err != nil {
+		t.Fatalf("AddStock failed: %v", err)
+	}
+	if err := db.AddStockToStrategy(stock.ID, strategy.ID); err != nil {
+		t.Fatalf("AddStockToStrategy failed: %v", err)
+	}
+	if err := db.InsertPriceDataBatch(vwapBars([4]float64{99, 102, 101.5, 2000})); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+
+	SetClock(NewSimulatedClock(vwapOpen.Add(25 * time.Minute)))
+	t.Cleanup(func() { SetClock(nil) })
+
+	sds := NewSetupDetectionService(db, nil, nil)
+	sds.SetMarketCalendar(NewMarketCalendar(cfg.MarketHours))
+	if setups := sds.detectVWAPSetups(context.Background(), "TEST", 101.5); len(setups) != 0 {
+		t.Errorf("expected no setups before the strategy turns them on, got %d", len(setups))
+	}
+
+	settings := &models.StrategyVWAPSetups{StrategyID: strategy.ID, Reclaim: true, VolumeMultiplier: 1.5, UpdatedAt: time.Now()}
+	if err := db.SetStrategyVWAPSetups(settings); err != nil {
+		t.Fatalf("SetStrategyVWAPSetups failed: %v", err)
+	}
+	setups := sds.detectVWAPSetups(context.Background(), "TEST", 101.5)
+	if len(setups) != 1 {
+		t.Fatalf("expected one setup, got %d", len(setups))
+	}
+	setup := setups[0]
+	if setup.SetupType != models.SetupTypeVWAPReclaim || setup.Direction != "bullish" {
+		t.Errorf("unexpected setup: %s %s", setup.SetupType, setup.Direction)
+	}
+	if setup.StopLoss != 99 || setup.Target1 != 104 || setup.Target2 != 106.5 || setup.Target3 != 109 {
+		t.Errorf("unexpected levels: stop %.2f, targets %.2f/%.2f/%.2f", setup.StopLoss, setup.Target1, setup.Target2, setup.Target3)
+	}
+	if setup.SupportLevel == nil || setup.SupportLevel.TimeframeOrigin != sessionVWAPOrigin || !setup.SupportLevel.VolumeConfirmed {
+		t.Errorf("expected a volume confirmed session VWAP support level, got %+v", setup.SupportLevel)
+	}
+
+	// A strategy needing more volume doesn't hide the setup, and deleting the strategy turns it off
+	other, err := db.CreateStrategy(models.Strategy{Name: "Strict"})
+	if err != nil {
+		t.Fatalf("CreateStrategy failed: %v", err)
+	}
+	if err := db.AddStockToStrategy(stock.ID, other.ID); err != nil {
+		t.Fatalf("AddStockToStrategy failed: %v", err)
+	}
+	strict := &models.StrategyVWAPSetups{StrategyID: other.ID, Reclaim: true, Rejection: true, VolumeMultiplier: 3, UpdatedAt: time.Now()}
+	if err := db.SetStrategyVWAPSetups(strict); err != nil {
+		t.Fatalf("SetStrategyVWAPSetups failed: %v", err)
+	}
+	if setups := sds.detectVWAPSetups(context.Background(), "TEST", 101.5); len(setups) != 1 {
+		t.Errorf("expected the lowest volume multiplier to apply, got %d setups", len(setups))
+	}
+	if err := db.DeleteStrategy(strategy.ID); err != nil {
+		t.Fatalf("DeleteStrategy failed: %v", err)
+	}
+	if setups := sds.detectVWAPSetups(context.Background(), "TEST", 101.5); len(setups) != 0 {
+		t.Errorf("expected no setups on 3x volume, got %d", len(setups))
+	}
+}
@@ -0,0 +1,478 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// Short interest defaults
+const (
+	DefaultShortInterestRefreshInterval = 6 * time.Hour
+	DefaultShortVolumeURL               = "https://cdn.finra.org/equity/regsho/daily"
+	defaultShortVolumeLookbackDays      = 20
+	defaultShortVolumeRetentionDays     = 365
+	defaultElevatedShortVolumeRatio     = 50
+	defaultElevatedDaysToCover          = 5
+	defaultElevatedShortFloatPercent    = 20
+)
+
+// shortInterestReports is the number of short interest reports, about three months, kept in a summary
+const shortInterestReports = 6
+
+// shortVolumePublishHour is the exchange hour after which FINRA has published the day's short volume file
+const shortVolumePublishHour = 18
+
+// ShortInterestService fetches and stores FINRA daily short volume and short interest for watched symbols
+type ShortInterestService struct {
+	cfg                  *config.Config
+	db                   *database.Database
+	calendar             *MarketCalendar
+	client               *http.Client
+	enabled              bool
+	interval             time.Duration
+	lookback             int
+	retention            int
+	elevatedVolumeRatio  float64
+	elevatedDaysToCover  float64
+	elevatedFloatPercent float64
+	shortVolumeURL       string
+	schedules            *ScheduleControl
+	stop                 chan struct{}
+	stopOnce             sync.Once
+	wg                   sync.WaitGroup // tracks the background refresh loop
+}
+
+// polygonShortInterestResponse represents the response from the Polygon short interest endpoint
+type polygonShortInterestResponse struct {
+	Status  string `json:"status"`
+	Results []struct {
+		Ticker         string  `json:"ticker"`
+		SettlementDate string  `json:"settlement_date"`
+		ShortInterest  int64   `json:"short_interest"`
+		AvgDailyVolume int64   `json:"avg_daily_volume"`
+		DaysToCover    float64 `json:"days_to_cover"`
+	} `json:"results"`
+}
+
+// NewShortInterestService creates a new short interest service
+func NewShortInterestService(cfg *config.Config, db *database.Database, calendar *MarketCalendar) *ShortInterestService {
+	settings := cfg.ShortInterest
+
+	interval := settings.RefreshInterval
+	if interval <= 0 {
+		interval = DefaultShortInterestRefreshInterval
+	}
+
+	lookback := settings.LookbackDays
+	if lookback <= 0 {
+		lookback = defaultShortVolumeLookbackDays
+	}
+
+	retention := settings.RetentionDays
+	if retention <= 0 {
+		retention = defaultShortVolumeRetentionDays
+	}
+
+	volumeRatio := settings.ElevatedVolumeRatio
+	if volumeRatio <= 0 {
+		volumeRatio = defaultElevatedShortVolumeRatio
+	}
+
+	daysToCover := settings.ElevatedDaysToCover
+	if daysToCover <= 0 {
+		daysToCover = defaultElevatedDaysToCover
+	}
+
+	floatPercent := settings.ElevatedFloatPercent
+	if floatPercent <= 0 {
+		floatPercent = defaultElevatedShortFloatPercent
+	}
+
+	shortVolumeURL := strings.TrimSuffix(settings.ShortVolumeURL, "/")
+	if shortVolumeURL == "" {
+		shortVolumeURL = DefaultShortVolumeURL
+	}
+
+	timeout := cfg.Polygon.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	return &ShortInterestService{
+		cfg:                  cfg,
+		db:                   db,
+		calendar:             calendar,
+		client:               &http.Client{Timeout: timeout},
+		enabled:              settings.Enabled,
+		interval:             interval,
+		lookback:             lookback,
+		retention:            retention,
+		elevatedVolumeRatio:  volumeRatio,
+		elevatedDaysToCover:  daysToCover,
+		elevatedFloatPercent: floatPercent,
+		shortVolumeURL:       shortVolumeURL,
+		stop:                 make(chan struct{}),
+	}
+}
+
+// SetScheduleControl sets the control that can disable the short data refresh
+func (sis *ShortInterestService) SetScheduleControl(schedules *ScheduleControl) {
+	sis.schedules = schedules
+}
+
+// Start fetches short data now and then periodically when short interest ingestion is enabled
+func (sis *ShortInterestService) Start() {
+	if !sis.enabled {
+		log.Printf("Short interest ingestion disabled")
+		return
+	}
+
+	log.Printf("Starting short interest ingestion (every %v)...", sis.interval)
+
+	sis.wg.Add(1)
+	go func() {
+		defer sis.wg.Done()
+
+		ticker := time.NewTicker(sis.interval)
+		defer ticker.Stop()
+
+		for {
+			sis.scheduledRefresh()
+
+			select {
+			case <-ticker.C:
+			case <-sis.stop:
+				return
+			}
+		}
+	}()
+}
+
+// scheduledRefresh refreshes the short data unless the short interest schedule is disabled
+func (sis *ShortInterestService) scheduledRefresh() {
+	if sis.schedules.Skip(models.ScheduleShortInterest) {
+		return
+	}
+	if err := sis.RefreshAll(); err != nil {
+		log.Printf("Short interest refresh failed: %v", err)
+	}
+}
+
+// Stop stops the refresh loop and waits for an in-flight refresh to finish
+func (sis *ShortInterestService) Stop(ctx context.Context) error {
+	sis.stopOnce.Do(func() { close(sis.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		sis.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Printf("Short interest service stopped")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for short interest refresh to finish: %w", ctx.Err())
+	}
+}
+
+// RefreshAll fetches the short volume files of the lookback days not stored yet and the latest short interest
+// of every watched stock, then prunes old short volume
+func (sis *ShortInterestService) RefreshAll() error {
+	watched, err := sis.db.GetWatchedSymbols()
+	if err != nil {
+		return fmt.Errorf("failed to get watched symbols: %w", err)
+	}
+
+	symbols := make(map[string]bool)
+	for _, symbol := range watched {
+		if models.IsEquity(symbol) {
+			symbols[symbol] = true
+		}
+	}
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	stored, err := sis.refreshShortVolume("", symbols)
+	if err != nil {
+		return err
+	}
+
+	reports := 0
+	for symbol := range symbols {
+		count, err := sis.RefreshShortInterest(symbol)
+		if err != nil {
+			log.Printf("Failed to refresh short interest for %s: %v", symbol, err)
+			continue
+		}
+		reports += count
+	}
+
+	cutoff := clockNow().In(sis.calendar.Location()).AddDate(0, 0, -sis.retention).Format("2006-01-02")
+	if _, err := sis.db.DeleteShortVolumesBefore(cutoff); err != nil {
+		log.Printf("Failed to prune old short volume: %v", err)
+	}
+
+	log.Printf("Short interest refreshed: %d short volume days and %d short interest reports for %d symbols", stored, reports, len(symbols))
+	return nil
+}
+
+// RefreshSymbol fetches the short volume of the lookback days missing for a stock and its latest short interest
+func (sis *ShortInterestService) RefreshSymbol(symbol string) error {
+	if !models.IsEquity(symbol) {
+		return fmt.Errorf("%w: short data is only reported for stocks", ErrValidation)
+	}
+
+	if _, err := sis.refreshShortVolume(symbol, map[string]bool{symbol: true}); err != nil {
+		return err
+	}
+	if _, err := sis.RefreshShortInterest(symbol); err != nil {
+		return fmt.Errorf("failed to refresh short interest: %w", err)
+	}
+	return nil
+}
+
+// refreshShortVolume fetches the short volume files of the lookback days with nothing stored, for every symbol
+// or for the given one, keeping the rows of the symbols asked for. It returns the number of rows stored.
+func (sis *ShortInterestService) refreshShortVolume(symbol string, symbols map[string]bool) (int, error) {
+	dates := sis.tradingDates(clockNow())
+	if len(dates) == 0 {
+		return 0, nil
+	}
+
+	stored, err := sis.db.GetShortVolumeDates(symbol, dates[len(dates)-1].Format("2006-01-02"))
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, date := range dates {
+		if stored[date.Format("2006-01-02")] {
+			continue
+		}
+
+		volumes, err := sis.FetchShortVolume(date, symbols)
+		if err != nil {
+			log.Printf("Failed to fetch short volume for %s: %v", date.Format("2006-01-02"), err)
+			continue
+		}
+		if err := sis.db.UpsertShortVolumes(volumes); err != nil {
+			return count, err
+		}
+		count += len(volumes)
+	}
+
+	return count, nil
+}
+
+// tradingDates returns the lookback trading days with a published short volume file, newest first
+func (sis *ShortInterestService) tradingDates(now time.Time) []time.Time {
+	local := now.In(sis.calendar.Location())
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	if local.Hour() < shortVolumePublishHour {
+		day = day.AddDate(0, 0, -1)
+	}
+
+	dates := make([]time.Time, 0, sis.lookback)
+	for len(dates) < sis.lookback {
+		if sis.calendar.IsTradingDay(day) {
+			dates = append(dates, day)
+		}
+		day = day.AddDate(0, 0, -1)
+	}
+	return dates
+}
+
+// FetchShortVolume fetches FINRA's consolidated daily short volume file for a trading day and returns the rows
+// of the given symbols
+func (sis *ShortInterestService) FetchShortVolume(date time.Time, symbols map[string]bool) ([]*models.ShortVolume, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/CNMSshvol%s.txt", sis.shortVolumeURL, date.Format("20060102")), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := sis.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("short volume file request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return parseShortVolume(resp.Body, date.Format("2006-01-02"), symbols)
+}
+
+// parseShortVolume parses the rows of the given symbols from a pipe delimited FINRA short volume file:
+// Date|Symbol|ShortVolume|ShortExemptVolume|TotalVolume|Market
+func parseShortVolume(r io.Reader, tradeDate string, symbols map[string]bool) ([]*models.ShortVolume, error) {
+	volumes := make([]*models.ShortVolume, 0, len(symbols))
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Split(strings.TrimSpace(scanner.Text()), "|")
+		if len(fields) < 5 || !symbols[fields[1]] {
+			continue
+		}
+
+		short, err1 := strconv.ParseFloat(fields[2], 64)
+		exempt, err2 := strconv.ParseFloat(fields[3], 64)
+		total, err3 := strconv.ParseFloat(fields[4], 64)
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+
+		volume := &models.ShortVolume{
+			Symbol:            fields[1],
+			TradeDate:         tradeDate,
+			ShortVolume:       int64(short),
+			ShortExemptVolume: int64(exempt),
+			TotalVolume:       int64(total),
+		}
+		if total > 0 {
+			volume.ShortVolumeRatio = math.Round(short/total*10000) / 100
+		}
+		volumes = append(volumes, volume)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read short volume file: %w", err)
+	}
+	return volumes, nil
+}
+
+// RefreshShortInterest fetches and stores a stock's latest short interest reports
+func (sis *ShortInterestService) RefreshShortInterest(symbol string) (int, error) {
+	reports, err := sis.FetchShortInterest(symbol)
+	if err != nil {
+		return 0, err
+	}
+	if err := sis.db.UpsertShortInterest(reports); err != nil {
+		return 0, err
+	}
+	return len(reports), nil
+}
+
+// FetchShortInterest fetches a stock's latest short interest reports from Polygon
+func (sis *ShortInterestService) FetchShortInterest(symbol string) ([]*models.ShortInterest, error) {
+	params := url.Values{}
+	params.Set("ticker", symbol)
+	params.Set("sort", "settlement_date.desc")
+	params.Set("limit", strconv.Itoa(shortInterestReports))
+	params.Set("apiKey", sis.cfg.Polygon.APIKey)
+
+	req, err := http.NewRequest("GET", sis.cfg.Polygon.BaseURL+"/stocks/v1/short-interest?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := sis.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var shortResp polygonShortInterestResponse
+	if err := json.NewDecoder(resp.Body).Decode(&shortResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	reports := make([]*models.ShortInterest, 0, len(shortResp.Results))
+	for _, result := range shortResp.Results {
+		reports = append(reports, &models.ShortInterest{
+			Symbol:         symbol,
+			SettlementDate: result.SettlementDate,
+			ShortInterest:  result.ShortInterest,
+			AvgDailyVolume: result.AvgDailyVolume,
+			DaysToCover:    result.DaysToCover,
+		})
+	}
+
+	return reports, nil
+}
+
+// Summary returns a stock's stored short data with its average short volume ratio over the lookback days and
+// whether any measure is elevated
+func (sis *ShortInterestService) Summary(symbol string) (*models.ShortInterestSummary, error) {
+	volumes, err := sis.db.GetShortVolumes(symbol, sis.lookback)
+	if err != nil {
+		return nil, err
+	}
+	reports, err := sis.db.GetShortInterest(symbol, shortInterestReports)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.ShortInterestSummary{
+		Symbol:        symbol,
+		Reasons:       make([]string, 0),
+		ShortInterest: reports,
+		ShortVolume:   volumes,
+		UpdatedAt:     clockNow(),
+	}
+
+	var short, total int64
+	for _, volume := range volumes {
+		short += volume.ShortVolume
+		total += volume.TotalVolume
+	}
+	if total > 0 {
+		summary.AvgShortVolumeRatio = math.Round(float64(short)/float64(total)*10000) / 100
+		if summary.AvgShortVolumeRatio >= sis.elevatedVolumeRatio {
+			summary.Reasons = append(summary.Reasons, fmt.Sprintf("%.1f%% of the last %d days' volume sold short (at least %.0f%%)",
+				summary.AvgShortVolumeRatio, len(volumes), sis.elevatedVolumeRatio))
+		}
+	}
+
+	if len(reports) > 0 {
+		summary.Latest = reports[0]
+		if summary.Latest.DaysToCover >= sis.elevatedDaysToCover {
+			summary.Reasons = append(summary.Reasons, fmt.Sprintf("%.1f days to cover (at least %.0f)",
+				summary.Latest.DaysToCover, sis.elevatedDaysToCover))
+		}
+
+		ref, err := sis.db.GetSymbolReference(symbol)
+		if err != nil {
+			log.Printf("Failed to get reference data for %s: %v", symbol, err)
+		}
+		if ref != nil && ref.FloatShares > 0 {
+			summary.ShortPercentFloat = math.Round(float64(summary.Latest.ShortInterest)/float64(ref.FloatShares)*10000) / 100
+			if summary.ShortPercentFloat >= sis.elevatedFloatPercent {
+				summary.Reasons = append(summary.Reasons, fmt.Sprintf("%.1f%% of the float sold short (at least %.0f%%)",
+					summary.ShortPercentFloat, sis.elevatedFloatPercent))
+			}
+		}
+	}
+
+	summary.Elevated = len(summary.Reasons) > 0
+	return summary, nil
+}
+
+// thresholds describes what counts as elevated short data
+func (sis *ShortInterestService) thresholds() string {
+	return fmt.Sprintf("short volume at least %.0f%%, at least %.0f days to cover or at least %.0f%% of the float short",
+		sis.elevatedVolumeRatio, sis.elevatedDaysToCover, sis.elevatedFloatPercent)
+}
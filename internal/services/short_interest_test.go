@@ -0,0 +1,88 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// TestShortInterestRefresh tests that the missing short volume days and the latest short interest of watched
+// stocks are stored and summarized, and that elevated short data checks the setup checklist signal
+func TestShortInterestRefresh(t *testing.T) {
+	location, _ := time.LoadLocation("America/New_York")
+	SetClock(NewSimulatedClock(time.Date(2025, 3, 12, 19, 0, 0, 0, location)))
+	t.Cleanup(func() { SetClock(nil) })
+
+	requested := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested[r.URL.Path]++
+		switch r.URL.Path {
+		case "/finra/CNMSshvol20250312.txt":
+			fmt.Fprint(w, "Date|Symbol|ShortVolume|ShortExemptVolume|TotalVolume|Market\n"+
+				"20250312|AAPL|600000|1000|1000000|B,Q,N\n20250312|MSFT|100|0|1000|B,Q,N\n")
+		case "/finra/CNMSshvol20250310.txt":
+			fmt.Fprint(w, "Date|Symbol|ShortVolume|ShortExemptVolume|TotalVolume|Market\n20250310|AAPL|400000.5|0|1000000|B,Q,N\n")
+		case "/stocks/v1/short-interest":
+			fmt.Fprint(w, `{"status":"OK","results":[
+				{"ticker":"AAPL","settlement_date":"2025-02-28","short_interest":5000000,"avg_daily_volume":1000000,"days_to_cover":5.0},
+				{"ticker":"AAPL","settlement_date":"2025-02-14","short_interest":3000000,"avg_daily_volume":1000000,"days_to_cover":3.0}]}`)
+		default:
+			http.Error(w, "Access Denied", http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.Polygon.BaseURL = server.URL
+	cfg.ShortInterest.LookbackDays = 3
+	cfg.ShortInterest.ShortVolumeURL = server.URL + "/finra/"
+	db := newTestDB(t)
+	for _, symbol := range []string{"AAPL", "X:BTCUSD"} {
+		if err := db.AddWatchedSymbol(symbol, symbol); err != nil {
+			t.Fatalf("AddWatchedSymbol failed: %v", err)
+		}
+	}
+
+	sis := NewShortInterestService(cfg, db, NewMarketCalendar(cfg.MarketHours))
+	if err := sis.RefreshAll(); err != nil {
+		t.Fatalf("RefreshAll failed: %v", err)
+	}
+	// A second refresh only asks again for the day FINRA had no file for
+	if err := sis.RefreshAll(); err != nil {
+		t.Fatalf("RefreshAll failed: %v", err)
+	}
+	if requested["/finra/CNMSshvol20250312.txt"] != 1 || requested["/finra/CNMSshvol20250311.txt"] != 2 {
+		t.Errorf("expected stored days not to be fetched again, got %v", requested)
+	}
+
+	summary, err := sis.Summary("AAPL")
+	if err != nil {
+		t.Fatalf("Summary failed: %v", err)
+	}
+	if len(summary.ShortVolume) != 2 || summary.ShortVolume[0].TradeDate != "2025-03-12" || summary.ShortVolume[0].ShortVolumeRatio != 60 {
+		t.Errorf("expected AAPL's 2 short volume days, newest first, got %+v", summary.ShortVolume)
+	}
+	if summary.AvgShortVolumeRatio != 50 || summary.Latest == nil || summary.Latest.SettlementDate != "2025-02-28" {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+	if !summary.Elevated || len(summary.Reasons) != 2 {
+		t.Errorf("expected the short volume and days to cover to be elevated, got %v", summary.Reasons)
+	}
+
+	sds := NewSetupDetectionService(db, nil, nil)
+	sds.SetShortInterestService(sis)
+	setup := &models.TradingSetup{Symbol: "AAPL", Direction: "bullish", Checklist: &models.SetupChecklist{}}
+	sds.applyShortInterest(setup, sds.shortInterestSummary("AAPL"))
+	item := setup.Checklist.ShortInterest
+	if !item.IsCompleted || item.Points != 0 || item.Value != "50.0% short volume, 5.0 days to cover" {
+		t.Errorf("expected the checklist signal without points, got %+v", item)
+	}
+	if sds.shortInterestSummary("X:BTCUSD") != nil {
+		t.Error("expected no short data for crypto")
+	}
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// S/R break alert defaults
+const (
+	DefaultSRBreakMinStrength  = 60.0
+	DefaultSRBreakPercent      = 0.25
+	DefaultSRBreakVolumeRatio  = 1.5
+	DefaultSRBreakLookbackBars = 20
+
+	// srBreakScanBars is how many of the latest bars each scan checks; breaks recorded before are skipped
+	srBreakScanBars = 60
+	// srBreakHighStrength is the level strength from which a break is a high severity notification
+	srBreakHighStrength = 80.0
+)
+
+// srBreakSettings are the break thresholds of one symbol
+type srBreakSettings struct {
+	enabled      bool
+	minStrength  float64
+	breakPercent float64
+	volumeRatio  float64
+}
+
+// SRBreakService alerts when a bar closes beyond a strong support or resistance level on above average volume,
+// recording the break as a touch of the level
+type SRBreakService struct {
+	db            *database.Database
+	calendar      *MarketCalendar
+	notifications *NotificationService
+	defaults      srBreakSettings
+	symbols       map[string]srBreakSettings
+	lookbackBars  int
+}
+
+// NewSRBreakService creates a new S/R break detector
+func NewSRBreakService(cfg *config.Config, db *database.Database, calendar *MarketCalendar) *SRBreakService {
+	breaksCfg := cfg.SRBreaks
+
+	sb := &SRBreakService{
+		db:       db,
+		calendar: calendar,
+		defaults: srBreakSettings{
+			enabled:      breaksCfg.Enabled,
+			minStrength:  breaksCfg.MinStrength,
+			breakPercent: breaksCfg.BreakPercent,
+			volumeRatio:  breaksCfg.VolumeRatio,
+		},
+		symbols:      make(map[string]srBreakSettings),
+		lookbackBars: breaksCfg.LookbackBars,
+	}
+
+	if sb.defaults.minStrength <= 0 {
+		sb.defaults.minStrength = DefaultSRBreakMinStrength
+	}
+	if sb.defaults.breakPercent <= 0 {
+		sb.defaults.breakPercent = DefaultSRBreakPercent
+	}
+	if sb.defaults.volumeRatio <= 0 {
+		sb.defaults.volumeRatio = DefaultSRBreakVolumeRatio
+	}
+	if sb.lookbackBars <= 0 {
+		sb.lookbackBars = DefaultSRBreakLookbackBars
+	}
+
+	for symbol, override := range breaksCfg.Symbols {
+		settings := sb.defaults
+		if override.Enabled != nil {
+			settings.enabled = *override.Enabled
+		}
+		if override.MinStrength > 0 {
+			settings.minStrength = override.MinStrength
+		}
+		if override.BreakPercent > 0 {
+			settings.breakPercent = override.BreakPercent
+		}
+		if override.VolumeRatio > 0 {
+			settings.volumeRatio = override.VolumeRatio
+		}
+		sb.symbols[strings.ToUpper(symbol)] = settings
+	}
+
+	return sb
+}
+
+// SetNotificationService dispatches new breaks to the notification center
+func (sb *SRBreakService) SetNotificationService(notifications *NotificationService) {
+	sb.notifications = notifications
+}
+
+// IsEnabled checks if breaks are checked after each collection cycle, globally or for any symbol
+func (sb *SRBreakService) IsEnabled() bool {
+	if sb == nil {
+		return false
+	}
+	if sb.defaults.enabled {
+		return true
+	}
+	for _, settings := range sb.symbols {
+		if settings.enabled {
+			return true
+		}
+	}
+	return false
+}
+
+// settings returns a symbol's thresholds, with its overrides applied
+func (sb *SRBreakService) settings(symbol string) srBreakSettings {
+	if settings, ok := sb.symbols[strings.ToUpper(symbol)]; ok {
+		return settings
+	}
+	return sb.defaults
+}
+
+// ScanSymbols checks each symbol for breaks and returns the ones recorded for the first time
+func (sb *SRBreakService) ScanSymbols(symbols []string) ([]*models.SRLevelTouch, error) {
+	recorded := make([]*models.SRLevelTouch, 0)
+	var lastErr error
+	for _, symbol := range symbols {
+		breaks, err := sb.Scan(symbol)
+		if err != nil {
+			log.Printf("Failed to scan %s for S/R breaks: %v", symbol, err)
+			lastErr = err
+			continue
+		}
+		recorded = append(recorded, breaks...)
+	}
+
+	if len(recorded) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return recorded, nil
+}
+
+// Scan checks a symbol's latest bars against its strong active levels, records the breaks and notifies about
+// the ones not recorded before. Symbols with breaks disabled are skipped.
+func (sb *SRBreakService) Scan(symbol string) ([]*models.SRLevelTouch, error) {
+	settings := sb.settings(symbol)
+	if !settings.enabled {
+		return nil, nil
+	}
+
+	active := true
+	levels, err := sb.db.GetSupportResistanceLevels(&models.SRDetectionFilter{
+		Symbol:      symbol,
+		MinStrength: settings.minStrength,
+		IsActive:    &active,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get S/R levels: %w", err)
+	}
+	if len(levels) == 0 {
+		return nil, nil
+	}
+
+	bars, err := sb.db.GetPriceDataForAnalysis(symbol, sb.lookbackBars+srBreakScanBars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price data: %w", err)
+	}
+
+	recorded := make([]*models.SRLevelTouch, 0)
+	for _, level := range levels {
+		for _, touch := range detectSRBreaks(level, bars, settings, sb.lookbackBars) {
+			inserted, err := sb.db.InsertSRLevelBreak(touch)
+			if err != nil {
+				return recorded, err
+			}
+			if !inserted {
+				continue
+			}
+
+			recorded = append(recorded, touch)
+			sb.notify(level, touch)
+		}
+	}
+
+	return recorded, nil
+}
+
+// notify dispatches a new break, as a high severity notification for the strongest levels
+func (sb *SRBreakService) notify(level *models.SupportResistanceLevel, touch *models.SRLevelTouch) {
+	direction := "above resistance"
+	if level.LevelType == "support" {
+		direction = "below support"
+	}
+	severity := EmailSeverityMedium
+	if level.Strength >= srBreakHighStrength {
+		severity = EmailSeverityHigh
+	}
+
+	message := fmt.Sprintf("%s closed at $%.2f at %s, %.2f%% %s at $%.2f (strength %.0f, %d touches)",
+		touch.Symbol, touch.TouchPrice, touch.TouchTime.In(sb.calendar.Location()).Format("Jan 2 15:04"),
+		math.Abs(touch.DistancePercent), direction, level.Level, level.Strength, level.Touches)
+	log.Printf("S/R break: %s", message)
+
+	sb.notifications.Notify(&models.Notification{
+		Category: models.NotificationAlert,
+		Severity: severity,
+		Symbol:   touch.Symbol,
+		Title:    fmt.Sprintf("🧱 %s broke %s $%.2f", touch.Symbol, level.LevelType, level.Level),
+		Message:  message,
+		Link:     "/api/support-resistance/" + touch.Symbol + "/touches?type=" + models.SRTouchBreak,
+	})
+}
+
+// detectSRBreaks returns a break for each bar after the level's last touch that closes beyond it by the break
+// percent, coming from the other side, on at least the volume ratio times the prior bars' average. Bars must
+// be oldest first.
+func detectSRBreaks(level *models.SupportResistanceLevel, bars []*models.PriceData, settings srBreakSettings, lookback int) []*models.SRLevelTouch {
+	if level.Level <= 0 {
+		return nil
+	}
+
+	var breaks []*models.SRLevelTouch
+	for i := max(len(bars)-srBreakScanBars, 1); i < len(bars); i++ {
+		bar, prev := bars[i], bars[i-1]
+		if !bar.Timestamp.After(level.LastTouch) {
+			continue
+		}
+
+		var broke bool
+		switch level.LevelType {
+		case "resistance":
+			broke = prev.Close <= level.Level && bar.Close >= level.Level*(1+settings.breakPercent/100)
+		case "support":
+			broke = prev.Close >= level.Level && bar.Close <= level.Level*(1-settings.breakPercent/100)
+		}
+		if !broke {
+			continue
+		}
+
+		avg := averageVolume(bars[:i+1], lookback)
+		if avg <= 0 || float64(bar.Volume) < settings.volumeRatio*float64(avg) {
+			continue
+		}
+
+		breaks = append(breaks, &models.SRLevelTouch{
+			LevelID:         level.ID,
+			Symbol:          level.Symbol,
+			TouchTime:       bar.Timestamp,
+			TouchPrice:      bar.Close,
+			Level:           level.Level,
+			DistancePercent: math.Round((bar.Close-level.Level)/level.Level*10000) / 100,
+			VolumeAtTouch:   bar.Volume,
+			VolumeSpike:     true,
+			TouchType:       models.SRTouchBreak,
+		})
+	}
+
+	return breaks
+}
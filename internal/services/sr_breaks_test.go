@@ -0,0 +1,101 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// TestSRBreakScan tests that only a close beyond a strong level on high volume is recorded and notified, once,
+// and that per-symbol settings override the global ones
+func TestSRBreakScan(t *testing.T) {
+	cfg := &config.Config{}
+	db := newTestDB(t)
+
+	// Bars under a $100 resistance; bar 40 closes above it on average volume and falls back, bar 50 closes
+	// above it on triple volume
+	start := time.Now().UTC().Truncate(time.Minute).Add(-60 * 5 * time.Minute)
+	for _, symbol := range []string{"TEST", "OFF"} {
+		var bars []*models.PriceData
+		for i := 0; i < 60; i++ {
+			price, volume := 99.0, int64(1000)
+			switch {
+			case i == 40:
+				price = 101
+			case i >= 50:
+				price = 101
+				if i == 50 {
+					volume = 3000
+				}
+			}
+			bars = append(bars, &models.PriceData{
+				Symbol: symbol, Timestamp: start.Add(time.Duration(i) * 5 * time.Minute),
+				Open: price, High: price, Low: price, Close: price, Volume: volume,
+			})
+		}
+		if err := db.InsertPriceDataBatch(bars); err != nil {
+			t.Fatalf("InsertPriceDataBatch failed: %v", err)
+		}
+
+		for _, level := range []*models.SupportResistanceLevel{
+			{Symbol: symbol, Level: 100, LevelType: "resistance", Strength: 75, Touches: 3},
+			{Symbol: symbol, Level: 98.9, LevelType: "support", Strength: 40, Touches: 2},
+		} {
+			level.FirstTouch, level.LastTouch, level.IsActive = start, start, true
+			if err := db.InsertSupportResistanceLevel(level); err != nil {
+				t.Fatalf("InsertSupportResistanceLevel failed: %v", err)
+			}
+		}
+	}
+
+	disabled := false
+	cfg.SRBreaks = config.SRBreaksConfig{
+		Enabled: true,
+		Symbols: map[string]config.SRBreakSymbolConfig{"off": {Enabled: &disabled}},
+	}
+	breaks := NewSRBreakService(cfg, db, NewMarketCalendar(cfg.MarketHours))
+	breaks.SetNotificationService(NewNotificationService(db))
+	if !breaks.IsEnabled() {
+		t.Fatal("expected breaks to be enabled")
+	}
+
+	recorded, err := breaks.ScanSymbols([]string{"TEST", "OFF"})
+	if err != nil {
+		t.Fatalf("ScanSymbols failed: %v", err)
+	}
+	if len(recorded) != 1 {
+		t.Fatalf("expected one break, got %d: %+v", len(recorded), recorded)
+	}
+	if touch := recorded[0]; touch.Symbol != "TEST" || !touch.TouchTime.Equal(start.Add(50*5*time.Minute)) ||
+		touch.TouchType != models.SRTouchBreak || touch.DistancePercent != 1 {
+		t.Errorf("unexpected break: %+v", touch)
+	}
+
+	// Already recorded breaks are neither stored nor notified again
+	if recorded, err := breaks.Scan("TEST"); err != nil || len(recorded) != 0 {
+		t.Errorf("expected nothing new on a second scan, got %d, %v", len(recorded), err)
+	}
+	stored, err := db.GetRecentSRLevelTouches("TEST", models.SRTouchBreak, 24, 0)
+	if err != nil || len(stored) != 1 {
+		t.Errorf("expected 1 stored break, got %d, %v", len(stored), err)
+	}
+	notifications, err := db.GetNotifications(&models.NotificationFilter{Category: models.NotificationAlert})
+	if err != nil || len(notifications) != 1 || notifications[0].Severity != EmailSeverityMedium {
+		t.Errorf("expected 1 medium severity break notification, got %+v, %v", notifications, err)
+	}
+
+	// A per-symbol override can enable a symbol while breaks are disabled globally
+	enabled := true
+	cfg.SRBreaks = config.SRBreaksConfig{
+		Symbols: map[string]config.SRBreakSymbolConfig{"OFF": {Enabled: &enabled, VolumeRatio: 4}},
+	}
+	breaks = NewSRBreakService(cfg, db, NewMarketCalendar(cfg.MarketHours))
+	if !breaks.IsEnabled() {
+		t.Fatal("expected breaks to be enabled by the override")
+	}
+	if recorded, err := breaks.ScanSymbols([]string{"TEST", "OFF"}); err != nil || len(recorded) != 0 {
+		t.Errorf("expected the override's volume ratio to filter the break, got %d, %v", len(recorded), err)
+	}
+}
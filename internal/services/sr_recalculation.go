@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+
+	"github.com/robfig/cron/v3"
+)
+
+// S/R recalculation defaults
+const (
+	DefaultSRRecalculationInterval    = 30 * time.Minute
+	DefaultSRRecalculationMinStrength = 50.0
+	defaultSRRecalculationTimeframe   = models.Timeframe5m
+)
+
+// SRRecalculationService recalculates the S/R levels of watched symbols on a schedule during market hours,
+// deactivating stored levels that are no longer detected and notifying about significant new and invalidated
+// levels
+type SRRecalculationService struct {
+	db            *database.Database
+	sr            *SupportResistanceService
+	calendar      *MarketCalendar
+	notifications *NotificationService
+	schedules     *ScheduleControl
+	cron          *cron.Cron
+	enabled       bool
+	interval      time.Duration
+	intervals     map[string]time.Duration
+	timeframe     models.Timeframe
+	minStrength   float64
+	lastRun       map[string]time.Time
+	mutex         sync.Mutex // serializes recalculations, which diff the stored levels
+}
+
+// NewSRRecalculationService creates a new S/R recalculation scheduler
+func NewSRRecalculationService(cfg *config.Config, db *database.Database, sr *SupportResistanceService, calendar *MarketCalendar) *SRRecalculationService {
+	recalcCfg := cfg.SRRecalculation
+
+	rs := &SRRecalculationService{
+		db:          db,
+		sr:          sr,
+		calendar:    calendar,
+		cron:        cron.New(cron.WithLocation(calendar.Location())),
+		enabled:     recalcCfg.Enabled,
+		interval:    recalcCfg.Interval,
+		intervals:   make(map[string]time.Duration),
+		timeframe:   models.Timeframe(recalcCfg.Timeframe),
+		minStrength: recalcCfg.MinStrength,
+		lastRun:     make(map[string]time.Time),
+	}
+
+	if rs.interval <= 0 {
+		rs.interval = DefaultSRRecalculationInterval
+	}
+	if rs.timeframe == "" {
+		rs.timeframe = defaultSRRecalculationTimeframe
+	}
+	if rs.minStrength <= 0 {
+		rs.minStrength = DefaultSRRecalculationMinStrength
+	}
+	for symbol, interval := range recalcCfg.Intervals {
+		rs.intervals[strings.ToUpper(symbol)] = interval
+	}
+
+	return rs
+}
+
+// SetNotificationService records significant new and invalidated levels in the notification center
+func (rs *SRRecalculationService) SetNotificationService(notifications *NotificationService) {
+	rs.notifications = notifications
+}
+
+// SetScheduleControl sets the control that can disable the scheduled recalculation
+func (rs *SRRecalculationService) SetScheduleControl(schedules *ScheduleControl) {
+	rs.schedules = schedules
+}
+
+// Start checks every minute on weekdays for symbols due a recalculation when the schedule is enabled
+func (rs *SRRecalculationService) Start() error {
+	if !rs.enabled {
+		log.Printf("S/R recalculation disabled")
+		return nil
+	}
+
+	if _, err := rs.cron.AddFunc("* * * * 1-5", rs.scheduledRecalculate); err != nil {
+		return fmt.Errorf("failed to schedule S/R recalculation: %w", err)
+	}
+	log.Printf("Scheduled S/R recalculation during market hours (every %v)", rs.interval)
+
+	rs.cron.Start()
+	return nil
+}
+
+// Stop stops the schedule and waits for a running recalculation to finish
+func (rs *SRRecalculationService) Stop(ctx context.Context) error {
+	select {
+	case <-rs.cron.Stop().Done():
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for S/R recalculation to finish: %w", ctx.Err())
+	}
+}
+
+// scheduledRecalculate recalculates the symbols that are due while the regular session is open
+func (rs *SRRecalculationService) scheduledRecalculate() {
+	now := clockNow()
+	if !rs.calendar.IsMarketHours(now) || rs.schedules.Skip(models.ScheduleSRRecalculation) {
+		return
+	}
+
+	if _, err := rs.RecalculateDue(now); err != nil {
+		log.Printf("S/R recalculation failed: %v", err)
+	}
+}
+
+// symbolInterval returns how often a symbol is recalculated
+func (rs *SRRecalculationService) symbolInterval(symbol string) time.Duration {
+	if interval, ok := rs.intervals[symbol]; ok {
+		return interval
+	}
+	return rs.interval
+}
+
+// RecalculateDue recalculates every watched symbol whose interval has passed since its last recalculation,
+// returning the changes of each
+func (rs *SRRecalculationService) RecalculateDue(now time.Time) ([]*models.SRRecalculation, error) {
+	symbols, err := rs.db.GetWatchedSymbols()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watched symbols: %w", err)
+	}
+
+	recalculations := make([]*models.SRRecalculation, 0)
+	for _, symbol := range symbols {
+		rs.mutex.Lock()
+		last, ran := rs.lastRun[symbol]
+		rs.mutex.Unlock()
+		if ran && now.Sub(last) < rs.symbolInterval(symbol) {
+			continue
+		}
+
+		recalculation, err := rs.Recalculate(symbol)
+		if err != nil {
+			log.Printf("Failed to recalculate S/R levels for %s: %v", symbol, err)
+			continue
+		}
+		recalculations = append(recalculations, recalculation)
+	}
+	return recalculations, nil
+}
+
+// Recalculate detects a symbol's levels again and diffs them against the stored active levels: levels
+// detected for the first time are new, and stored levels the detection no longer confirms are deactivated
+// as invalidated. Without bars to detect on, no level is invalidated.
+func (rs *SRRecalculationService) Recalculate(symbol string) (*models.SRRecalculation, error) {
+	symbol = strings.ToUpper(symbol)
+
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+
+	start := clockNow()
+	active := true
+	filter := &models.SRDetectionFilter{Symbol: symbol, IsActive: &active, Limit: 1000}
+
+	before, err := rs.db.GetSupportResistanceLevels(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stored S/R levels: %w", err)
+	}
+	result, err := rs.sr.DetectSupportResistanceLevels(context.Background(), symbol, rs.timeframe)
+	if err != nil {
+		return nil, err
+	}
+	after, err := rs.db.GetSupportResistanceLevels(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recalculated S/R levels: %w", err)
+	}
+	rs.lastRun[symbol] = start
+
+	recalculation := &models.SRRecalculation{
+		Symbol:            symbol,
+		Timeframe:         rs.timeframe,
+		RecalculatedAt:    start,
+		NewLevels:         make([]*models.SupportResistanceLevel, 0),
+		InvalidatedLevels: make([]*models.SupportResistanceLevel, 0),
+	}
+
+	stored := make(map[int64]bool, len(before))
+	for _, level := range before {
+		stored[level.ID] = true
+	}
+	for _, level := range after {
+		switch {
+		case !stored[level.ID]:
+			recalculation.NewLevels = append(recalculation.NewLevels, level)
+		case result.CurrentPrice > 0 && level.LastValidated.Before(start):
+			level.IsActive = false
+			if err := rs.db.UpdateSupportResistanceLevel(level); err != nil {
+				return nil, fmt.Errorf("failed to deactivate S/R level: %w", err)
+			}
+			recalculation.InvalidatedLevels = append(recalculation.InvalidatedLevels, level)
+			continue
+		}
+		recalculation.ActiveLevels++
+	}
+
+	for _, level := range recalculation.NewLevels {
+		if rs.notify(level, "new") {
+			recalculation.Notified++
+		}
+	}
+	for _, level := range recalculation.InvalidatedLevels {
+		if rs.notify(level, "invalidated") {
+			recalculation.Notified++
+		}
+	}
+
+	if len(recalculation.NewLevels) > 0 || len(recalculation.InvalidatedLevels) > 0 {
+		log.Printf("Recalculated S/R levels for %s: %d new, %d invalidated", symbol,
+			len(recalculation.NewLevels), len(recalculation.InvalidatedLevels))
+	}
+	return recalculation, nil
+}
+
+// notify records a new or invalidated level when it is strong enough, reporting whether it was
+func (rs *SRRecalculationService) notify(level *models.SupportResistanceLevel, change string) bool {
+	if level.Strength < rs.minStrength {
+		return false
+	}
+
+	message := fmt.Sprintf("New %s at $%.2f for %s (strength %.0f, %d touches)",
+		level.LevelType, level.Level, level.Symbol, level.Strength, level.Touches)
+	if change == "invalidated" {
+		message = fmt.Sprintf("%s %s at $%.2f is no longer detected and was deactivated (strength %.0f, %d touches)",
+			level.Symbol, level.LevelType, level.Level, level.Strength, level.Touches)
+	}
+
+	rs.notifications.Notify(&models.Notification{
+		Category: models.NotificationAlert,
+		Severity: EmailSeverityMedium,
+		Symbol:   level.Symbol,
+		Title:    fmt.Sprintf("📐 %s %s %s $%.2f", level.Symbol, change, level.LevelType, level.Level),
+		Message:  message,
+		Link:     "/api/support-resistance/" + level.Symbol + "/levels",
+	})
+	return true
+}
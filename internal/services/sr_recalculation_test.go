@@ -0,0 +1,83 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// TestSRRecalculation tests that a recalculation reports the levels it detects for the first time, deactivates
+// stored levels it no longer detects, and only recalculates symbols whose interval has passed
+func TestSRRecalculation(t *testing.T) {
+	cfg := &config.Config{}
+	db := newTestDB(t)
+
+	// 5-minute bars swinging between $95 and $105, so the swing highs and lows form levels
+	start := time.Now().UTC().Truncate(5 * time.Minute).Add(-400 * 5 * time.Minute)
+	var bars []*models.PriceData
+	for i := 0; i < 400; i++ {
+		price := 100 + 5*math.Sin(float64(i)*2*math.Pi/40)
+		bars = append(bars, &models.PriceData{
+			Symbol: "TEST", Timestamp: start.Add(time.Duration(i) * 5 * time.Minute),
+			Open: price, High: price + 0.1, Low: price - 0.1, Close: price, Volume: 1000,
+		})
+	}
+	if err := db.InsertPriceDataBatch(bars); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+	if err := db.AddWatchedSymbol("TEST", "Test"); err != nil {
+		t.Fatalf("AddWatchedSymbol failed: %v", err)
+	}
+
+	// A stored level far from every bar, which detection no longer confirms
+	stale := &models.SupportResistanceLevel{
+		Symbol: "TEST", Level: 150, LevelType: "resistance", Strength: 80, Touches: 4,
+		FirstTouch: start, LastTouch: start, IsActive: true, LastValidated: start,
+	}
+	if err := db.InsertSupportResistanceLevel(stale); err != nil {
+		t.Fatalf("InsertSupportResistanceLevel failed: %v", err)
+	}
+
+	cfg.SRRecalculation = config.SRRecalculationConfig{MinStrength: 1}
+	sr := NewSupportResistanceService(db, nil)
+	recalc := NewSRRecalculationService(cfg, db, sr, NewMarketCalendar(cfg.MarketHours))
+	recalc.SetNotificationService(NewNotificationService(db))
+
+	recalculation, err := recalc.Recalculate("test")
+	if err != nil {
+		t.Fatalf("Recalculate failed: %v", err)
+	}
+	if len(recalculation.NewLevels) == 0 || recalculation.ActiveLevels != len(recalculation.NewLevels) {
+		t.Fatalf("expected the detected levels to be new, got %+v", recalculation)
+	}
+	if len(recalculation.InvalidatedLevels) != 1 || recalculation.InvalidatedLevels[0].ID != stale.ID {
+		t.Fatalf("expected the stale level to be invalidated, got %+v", recalculation.InvalidatedLevels)
+	}
+	if recalculation.Notified != len(recalculation.NewLevels)+1 {
+		t.Errorf("expected every change to be notified, got %d", recalculation.Notified)
+	}
+	active := true
+	if levels, _ := db.GetSupportResistanceLevels(&models.SRDetectionFilter{Symbol: "TEST", IsActive: &active, Limit: 1000}); len(levels) != recalculation.ActiveLevels {
+		t.Errorf("expected %d active levels, got %d", recalculation.ActiveLevels, len(levels))
+	}
+
+	// The same bars confirm the same levels
+	again, err := recalc.Recalculate("TEST")
+	if err != nil {
+		t.Fatalf("Recalculate failed: %v", err)
+	}
+	if len(again.NewLevels) != 0 || len(again.InvalidatedLevels) != 0 || again.ActiveLevels != recalculation.ActiveLevels {
+		t.Errorf("expected no changes on the same bars, got %+v", again)
+	}
+
+	// Not due again until the interval has passed
+	if due, err := recalc.RecalculateDue(time.Now()); err != nil || len(due) != 0 {
+		t.Errorf("expected no symbol to be due, got %d, %v", len(due), err)
+	}
+	if due, err := recalc.RecalculateDue(time.Now().Add(DefaultSRRecalculationInterval)); err != nil || len(due) != 1 {
+		t.Errorf("expected the symbol to be due after the interval, got %d, %v", len(due), err)
+	}
+}
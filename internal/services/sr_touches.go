@@ -0,0 +1,287 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// S/R touch recording defaults
+const (
+	DefaultSRTouchBounceBars   = 12
+	DefaultSRTouchLookbackBars = 20
+
+	// srTouchScanBars is how many of the latest bars each scan checks; touches recorded before are skipped
+	srTouchScanBars = 60
+)
+
+// srTouchSettings are the thresholds touches are resolved with
+type srTouchSettings struct {
+	tolerance     float64 // band around the level, in percent of it, a bar must reach to touch it
+	bouncePercent float64
+	bounceBars    int
+	breakPercent  float64
+	volumeRatio   float64 // touch volume over the prior bars' average that counts as a spike
+	lookbackBars  int
+}
+
+// SRTouchService records touches, bounces and breaks of active S/R levels as bars are collected and updates
+// the levels' touch count, volume and bounce statistics incrementally between full detections
+type SRTouchService struct {
+	db      *database.Database
+	sr      *SupportResistanceService
+	enabled bool
+	cfg     config.SRTouchesConfig
+}
+
+// NewSRTouchService creates a new S/R touch recorder
+func NewSRTouchService(cfg *config.Config, db *database.Database, sr *SupportResistanceService) *SRTouchService {
+	return &SRTouchService{
+		db:      db,
+		sr:      sr,
+		enabled: cfg.SRTouches.Enabled,
+		cfg:     cfg.SRTouches,
+	}
+}
+
+// IsEnabled checks if touches are recorded after each collection cycle
+func (ts *SRTouchService) IsEnabled() bool {
+	return ts != nil && ts.enabled
+}
+
+// settings returns the touch thresholds, taking the ones not configured from the S/R detection settings
+func (ts *SRTouchService) settings() srTouchSettings {
+	detection := ts.sr.Config()
+	settings := srTouchSettings{
+		tolerance:     detection.LevelPenetrationTolerance,
+		bouncePercent: ts.cfg.BouncePercent,
+		bounceBars:    ts.cfg.BounceBars,
+		breakPercent:  ts.cfg.BreakPercent,
+		volumeRatio:   detection.VolumeConfirmationRatio,
+		lookbackBars:  ts.cfg.LookbackBars,
+	}
+
+	if settings.bouncePercent <= 0 {
+		settings.bouncePercent = detection.MinBouncePercent
+	}
+	if settings.bounceBars <= 0 {
+		settings.bounceBars = DefaultSRTouchBounceBars
+	}
+	if settings.breakPercent <= 0 {
+		settings.breakPercent = DefaultSRBreakPercent
+	}
+	if settings.lookbackBars <= 0 {
+		settings.lookbackBars = DefaultSRTouchLookbackBars
+	}
+	return settings
+}
+
+// RecordSymbols records the touches of each symbol's levels and returns the ones recorded for the first time
+func (ts *SRTouchService) RecordSymbols(symbols []string) ([]*models.SRLevelTouch, error) {
+	recorded := make([]*models.SRLevelTouch, 0)
+	var lastErr error
+	for _, symbol := range symbols {
+		touches, err := ts.Record(symbol)
+		if err != nil {
+			log.Printf("Failed to record S/R touches for %s: %v", symbol, err)
+			lastErr = err
+			continue
+		}
+		recorded = append(recorded, touches...)
+	}
+
+	if len(recorded) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return recorded, nil
+}
+
+// Record checks a symbol's latest bars against its active pivot levels, records the touches not recorded before
+// and updates the touched levels' statistics
+func (ts *SRTouchService) Record(symbol string) ([]*models.SRLevelTouch, error) {
+	symbol = strings.ToUpper(symbol)
+	settings := ts.settings()
+
+	active := true
+	levels, err := ts.db.GetSupportResistanceLevels(&models.SRDetectionFilter{Symbol: symbol, IsActive: &active, Limit: 1000})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get S/R levels: %w", err)
+	}
+	if len(levels) == 0 {
+		return nil, nil
+	}
+
+	bars, err := ts.db.GetPriceDataForAnalysis(symbol, settings.lookbackBars+srTouchScanBars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price data: %w", err)
+	}
+
+	recorded := make([]*models.SRLevelTouch, 0)
+	for _, level := range levels {
+		touches := detectSRTouches(level, bars, settings)
+		inserted := make([]*models.SRLevelTouch, 0, len(touches))
+		for _, touch := range touches {
+			ok, err := ts.db.InsertSRLevelTouchOnce(touch)
+			if err != nil {
+				return recorded, err
+			}
+			if ok {
+				inserted = append(inserted, touch)
+			}
+		}
+		if len(inserted) == 0 {
+			continue
+		}
+
+		ts.updateLevelStats(level, inserted)
+		if err := ts.db.UpdateSupportResistanceLevel(level); err != nil {
+			return recorded, fmt.Errorf("failed to update S/R level stats: %w", err)
+		}
+		recorded = append(recorded, inserted...)
+	}
+
+	return recorded, nil
+}
+
+// updateLevelStats folds newly recorded touches into a level's statistics: tests and bounces add a touch and
+// move the running touch volume and bounce averages, and a touch on spiking volume confirms the level
+func (ts *SRTouchService) updateLevelStats(level *models.SupportResistanceLevel, touches []*models.SRLevelTouch) {
+	for _, touch := range touches {
+		if touch.TouchTime.After(level.LastTouch) {
+			level.LastTouch = touch.TouchTime
+		}
+		if touch.TouchType == models.SRTouchBreak {
+			continue
+		}
+
+		level.Touches++
+		n := float64(level.Touches)
+		level.AvgVolume += (float64(touch.VolumeAtTouch) - level.AvgVolume) / n
+		level.AvgBouncePercent += (touch.BouncePercent - level.AvgBouncePercent) / n
+		level.MaxBouncePercent = math.Max(level.MaxBouncePercent, touch.BouncePercent)
+		if touch.VolumeSpike {
+			level.VolumeConfirmed = true
+		}
+	}
+
+	level.Strength = ts.sr.calculateStrengthScore(level)
+	level.UpdatedAt = clockNow()
+}
+
+// detectSRTouches returns the touches of a level among bars after its last full detection, which must be oldest
+// first. A bar reaching the level's band from away from it is a touch, resolved as a bounce once price moves
+// the bounce percent away within the bounce bars, or as a test when it doesn't; touches still waiting for
+// their bounce window are left for a later scan. A close beyond the level from the other side is a break.
+func detectSRTouches(level *models.SupportResistanceLevel, bars []*models.PriceData, settings srTouchSettings) []*models.SRLevelTouch {
+	if level.Level <= 0 {
+		return nil
+	}
+
+	since := level.LastValidated
+	if since.IsZero() {
+		since = level.LastTouch
+	}
+
+	// dir turns prices into distances from the level on its holding side: above support, below resistance
+	dir := 1.0
+	if level.LevelType == "resistance" {
+		dir = -1.0
+	}
+	band := level.Level * settings.tolerance / 100
+	breakDistance := level.Level * settings.breakPercent / 100
+	// reach is how close a bar's extreme toward the level came to it
+	reach := func(bar *models.PriceData) float64 {
+		if dir > 0 {
+			return bar.Low - level.Level
+		}
+		return level.Level - bar.High
+	}
+	// excursion is how far a bar's extreme away from the level moved from it
+	excursion := func(bar *models.PriceData) float64 {
+		if dir > 0 {
+			return bar.High - level.Level
+		}
+		return level.Level - bar.Low
+	}
+	closeDistance := func(bar *models.PriceData) float64 {
+		return dir * (bar.Close - level.Level)
+	}
+
+	var touches []*models.SRLevelTouch
+	for i := max(len(bars)-srTouchScanBars, 1); i < len(bars); i++ {
+		bar, prev := bars[i], bars[i-1]
+		if !bar.Timestamp.After(since) {
+			continue
+		}
+
+		touch := &models.SRLevelTouch{
+			LevelID:       level.ID,
+			Symbol:        level.Symbol,
+			TouchTime:     bar.Timestamp,
+			Level:         level.Level,
+			VolumeAtTouch: bar.Volume,
+		}
+		avg := averageVolume(bars[:i+1], settings.lookbackBars)
+		touch.VolumeSpike = avg > 0 && float64(bar.Volume) >= settings.volumeRatio*float64(avg)
+
+		if closeDistance(prev) >= 0 && closeDistance(bar) <= -breakDistance {
+			touch.TouchType = models.SRTouchBreak
+			touch.TouchPrice = bar.Close
+			touch.DistancePercent = math.Round((bar.Close-level.Level)/level.Level*10000) / 100
+			touches = append(touches, touch)
+			continue
+		}
+
+		if reach(bar) > band || reach(prev) <= band || closeDistance(prev) < 0 || closeDistance(bar) <= -breakDistance {
+			continue
+		}
+
+		// Resolve the touch over the bars after it
+		resolved := false
+		bestMove := 0.0
+		for j := i + 1; j < len(bars) && j <= i+settings.bounceBars; j++ {
+			if closeDistance(bars[j]) <= -breakDistance {
+				resolved = true
+				break
+			}
+			bestMove = math.Max(bestMove, excursion(bars[j]))
+			if bestMove/level.Level*100 >= settings.bouncePercent {
+				resolved = true
+				break
+			}
+			if j == i+settings.bounceBars {
+				resolved = true
+			}
+		}
+		if !resolved {
+			continue
+		}
+
+		timeAtLevel := 0
+		for j := i + 1; j < len(bars) && reach(bars[j]) <= band; j++ {
+			timeAtLevel = int(bars[j].Timestamp.Sub(bar.Timestamp).Minutes())
+		}
+
+		extreme := bar.Low
+		if dir < 0 {
+			extreme = bar.High
+		}
+		touch.TouchPrice = extreme
+		touch.DistancePercent = math.Round((extreme-level.Level)/level.Level*10000) / 100
+		touch.BouncePercent = math.Round(bestMove/level.Level*10000) / 100
+		touch.TimeAtLevel = timeAtLevel
+		touch.TouchType = models.SRTouchTest
+		if bestMove/level.Level*100 >= settings.bouncePercent {
+			touch.TouchType = models.SRTouchBounce
+			touch.BounceConfirmed = true
+		}
+		touches = append(touches, touch)
+	}
+
+	return touches
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// TestSRTouchRecording tests that touches are resolved as bounces or tests, breaks are recorded, rescans record
+// nothing twice and the level's statistics are updated incrementally
+func TestSRTouchRecording(t *testing.T) {
+	cfg := &config.Config{}
+	db := newTestDB(t)
+
+	// Bars above a $100 support: bar 30 dips to it on triple volume and bounces 3.5%, bar 45 dips to it and only
+	// recovers 1%, bar 58 closes below it
+	start := time.Now().UTC().Truncate(time.Minute).Add(-60 * 5 * time.Minute)
+	var bars []*models.PriceData
+	for i := 0; i < 60; i++ {
+		open, high, low, closePrice, volume := 103.0, 103.2, 102.5, 103.0, int64(1000)
+		switch {
+		case i == 30:
+			high, low, closePrice, volume = 101.5, 100.2, 101, 3000
+		case i > 30 && i <= 35:
+			high, low = 103.5, 101
+		case i == 45:
+			high, low, closePrice = 101, 100.3, 100.8
+		case i > 45 && i < 58:
+			high, low, closePrice = 101, 100.8, 100.9
+		case i >= 58:
+			high, low, closePrice = 100.9, 99.4, 99.5
+		}
+		bars = append(bars, &models.PriceData{
+			Symbol: "TEST", Timestamp: start.Add(time.Duration(i) * 5 * time.Minute),
+			Open: open, High: high, Low: low, Close: closePrice, Volume: volume,
+		})
+	}
+	if err := db.InsertPriceDataBatch(bars); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+
+	level := &models.SupportResistanceLevel{
+		Symbol: "TEST", Level: 100, LevelType: "support", Strength: 50, Touches: 3,
+		AvgBouncePercent: 2, MaxBouncePercent: 2.5, FirstTouch: start.Add(-time.Hour), LastTouch: start.Add(-time.Hour),
+		IsActive: true, LastValidated: start.Add(-time.Minute),
+	}
+	if err := db.InsertSupportResistanceLevel(level); err != nil {
+		t.Fatalf("InsertSupportResistanceLevel failed: %v", err)
+	}
+
+	cfg.SRTouches.Enabled = true
+	touches := NewSRTouchService(cfg, db, NewSupportResistanceService(db, nil))
+	if !touches.IsEnabled() {
+		t.Fatal("expected touch recording to be enabled")
+	}
+
+	// The second dip is still waiting for its bounce window while the bars after it are missing
+	if pending := detectSRTouches(level, bars[:50], touches.settings()); len(pending) != 1 || pending[0].TouchType != models.SRTouchBounce {
+		t.Errorf("expected only the first dip to be resolved, got %+v", pending)
+	}
+
+	recorded, err := touches.RecordSymbols([]string{"TEST"})
+	if err != nil {
+		t.Fatalf("RecordSymbols failed: %v", err)
+	}
+	if len(recorded) != 3 {
+		t.Fatalf("expected a bounce, a test and a break, got %d: %+v", len(recorded), recorded)
+	}
+	if touch := recorded[0]; touch.TouchType != models.SRTouchBounce || !touch.BounceConfirmed || touch.BouncePercent != 3.5 ||
+		touch.TouchPrice != 100.2 || !touch.VolumeSpike || touch.TimeAtLevel != 0 {
+		t.Errorf("unexpected bounce: %+v", touch)
+	}
+	if touch := recorded[1]; touch.TouchType != models.SRTouchTest || touch.BounceConfirmed || touch.BouncePercent != 1 ||
+		!touch.TouchTime.Equal(bars[45].Timestamp) {
+		t.Errorf("unexpected test: %+v", touch)
+	}
+	if touch := recorded[2]; touch.TouchType != models.SRTouchBreak || !touch.TouchTime.Equal(bars[58].Timestamp) || touch.DistancePercent != -0.5 {
+		t.Errorf("unexpected break: %+v", touch)
+	}
+
+	if again, err := touches.Record("TEST"); err != nil || len(again) != 0 {
+		t.Errorf("expected nothing new on a second scan, got %d, %v", len(again), err)
+	}
+
+	stored, err := db.GetSupportResistanceLevels(&models.SRDetectionFilter{Symbol: "TEST"})
+	if err != nil || len(stored) != 1 {
+		t.Fatalf("expected the level, got %d, %v", len(stored), err)
+	}
+	if got := stored[0]; got.Touches != 5 || got.MaxBouncePercent != 3.5 || !got.VolumeConfirmed ||
+		!got.LastTouch.Equal(bars[58].Timestamp) || got.AvgBouncePercent != 2.1 {
+		t.Errorf("unexpected level stats: %+v", got)
+	}
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// Points a zone earns for lining up with each confluence source
+var confluencePoints = map[string]float64{
+	models.ConfluenceRoundNumber:  10,
+	models.ConfluencePriorDayHigh: 15,
+	models.ConfluencePriorDayLow:  15,
+	models.ConfluenceVWAP:         10,
+	models.ConfluencePOC:          15,
+	models.ConfluenceHVN:          10,
+}
+
+// GetZones merges a symbol's active stored levels into zones scored against the current confluence references
+func (srs *SupportResistanceService) GetZones(symbol string) (*models.SRZoneResult, error) {
+	levels, err := srs.db.GetSupportResistanceLevels(&models.SRDetectionFilter{
+		Symbol:   symbol,
+		IsActive: boolPtr(true),
+		Limit:    1000,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get S/R levels: %w", err)
+	}
+
+	result := &models.SRZoneResult{
+		Symbol:         symbol,
+		ConfluenceRefs: srs.confluenceRefs(symbol, clockNow()),
+	}
+	result.SupportZones, result.ResistanceZones = srs.buildZones(levels, result.ConfluenceRefs)
+
+	return result, nil
+}
+
+// buildZones merges overlapping levels of the same type into zones and scores their confluence
+func (srs *SupportResistanceService) buildZones(levels []*models.SupportResistanceLevel, refs *models.SRConfluenceRefs) (support, resistance []*models.SRZone) {
+	halfWidth := srs.config.ZoneWidthPercent / 200.0
+
+	sorted := make([]*models.SupportResistanceLevel, len(levels))
+	copy(sorted, levels)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].LevelType != sorted[j].LevelType {
+			return sorted[i].LevelType < sorted[j].LevelType
+		}
+		return sorted[i].Level < sorted[j].Level
+	})
+
+	var zone *models.SRZone
+	flush := func() {
+		if zone == nil {
+			return
+		}
+		srs.scoreZone(zone, refs)
+		if zone.LevelType == "support" {
+			support = append(support, zone)
+		} else {
+			resistance = append(resistance, zone)
+		}
+	}
+
+	for _, level := range sorted {
+		low := level.Level * (1 - halfWidth)
+		high := level.Level * (1 + halfWidth)
+
+		// Bands that overlap the current zone extend it; anything else starts a new zone
+		if zone != nil && zone.LevelType == level.LevelType && low <= zone.High {
+			zone.High = math.Max(zone.High, high)
+			zone.Levels = append(zone.Levels, level)
+			continue
+		}
+
+		flush()
+		zone = &models.SRZone{
+			Symbol:    level.Symbol,
+			LevelType: level.LevelType,
+			Low:       low,
+			High:      high,
+			Levels:    []*models.SupportResistanceLevel{level},
+		}
+	}
+	flush()
+
+	return support, resistance
+}
+
+// scoreZone fills in the zone's center, touches, confluence and strength from its merged levels
+func (srs *SupportResistanceService) scoreZone(zone *models.SRZone, refs *models.SRConfluenceRefs) {
+	var weighted, totalStrength, total float64
+	zone.Touches = 0
+	zone.Strength = 0
+	for _, level := range zone.Levels {
+		weighted += level.Level * level.Strength
+		totalStrength += level.Strength
+		total += level.Level
+		zone.Touches += level.Touches
+		zone.Strength = math.Max(zone.Strength, level.Strength)
+	}
+
+	zone.Level = total / float64(len(zone.Levels))
+	if totalStrength > 0 {
+		zone.Level = weighted / totalStrength
+	}
+
+	tolerance := srs.config.ConfluenceTolerancePercent / 100.0
+	near := func(price float64) bool {
+		return price > 0 && price >= zone.Low*(1-tolerance) && price <= zone.High*(1+tolerance)
+	}
+
+	zone.Confluence = []string{}
+	zone.ConfluenceScore = 0
+	candidates := map[string]float64{models.ConfluenceRoundNumber: nearestRoundNumber(zone.Level)}
+	if refs != nil {
+		candidates[models.ConfluencePriorDayHigh] = refs.PriorDayHigh
+		candidates[models.ConfluencePriorDayLow] = refs.PriorDayLow
+		candidates[models.ConfluenceVWAP] = refs.VWAP
+		candidates[models.ConfluencePOC] = refs.PointOfControl
+		candidates[models.ConfluenceHVN] = nearestPrice(refs.HighVolumeNodes, zone.Level)
+	}
+	for _, source := range []string{models.ConfluenceRoundNumber, models.ConfluencePriorDayHigh, models.ConfluencePriorDayLow, models.ConfluenceVWAP, models.ConfluencePOC, models.ConfluenceHVN} {
+		if near(candidates[source]) {
+			zone.Confluence = append(zone.Confluence, source)
+			zone.ConfluenceScore += confluencePoints[source]
+		}
+	}
+
+	// Strongest level, plus up to 15 points for merged levels, plus confluence
+	mergeBonus := math.Min(15, float64(len(zone.Levels)-1)*5)
+	zone.ZoneStrength = math.Min(100, zone.Strength+mergeBonus+zone.ConfluenceScore)
+}
+
+// nearestRoundNumber returns the closest price traders are likely to watch, scaled to the price magnitude
+func nearestRoundNumber(price float64) float64 {
+	var step float64
+	switch {
+	case price <= 0:
+		return 0
+	case price < 10:
+		step = 0.5
+	case price < 50:
+		step = 1
+	case price < 200:
+		step = 5
+	case price < 1000:
+		step = 10
+	default:
+		step = 50
+	}
+	return math.Round(price/step) * step
+}
+
+// nearestPrice returns the price closest to target, or 0 if there are none
+func nearestPrice(prices []float64, target float64) float64 {
+	nearest := 0.0
+	for _, price := range prices {
+		if nearest == 0 || math.Abs(price-target) < math.Abs(nearest-target) {
+			nearest = price
+		}
+	}
+	return nearest
+}
+
+// confluenceRefs loads the prior day high/low, current session VWAP and volume profile nodes for a symbol
+func (srs *SupportResistanceService) confluenceRefs(symbol string, now time.Time) *models.SRConfluenceRefs {
+	refs := &models.SRConfluenceRefs{}
+
+	if srs.volumeProfile != nil {
+		if profile, err := srs.volumeProfile.Profile(symbol); err == nil {
+			refs.PointOfControl = profile.PointOfControl
+			refs.HighVolumeNodes = profile.HighVolumeNodes
+		}
+	}
+
+	daily, err := srs.db.GetPriceDataRangeTimeframe(symbol, now.AddDate(0, 0, -7), now, models.Timeframe1d)
+	if err != nil || len(daily) == 0 {
+		return refs
+	}
+
+	// Daily candles start at midnight US/Eastern; the latest one is today's session only if it's dated today
+	latest := daily[len(daily)-1]
+	ny, nm, nd := now.In(latest.Timestamp.Location()).Date()
+	ly, lm, ld := latest.Timestamp.Date()
+	prior := latest
+	if ny == ly && nm == lm && nd == ld {
+		prior = nil
+		if len(daily) > 1 {
+			prior = daily[len(daily)-2]
+		}
+
+		bars, err := srs.db.GetPriceDataRange(symbol, latest.Timestamp, now)
+		if err == nil {
+			refs.VWAP = sessionVWAP(bars)
+		}
+	}
+
+	if prior != nil {
+		refs.PriorDayHigh = prior.High
+		refs.PriorDayLow = prior.Low
+	}
+
+	return refs
+}
+
+// sessionVWAP calculates the volume weighted average of typical prices over the given bars
+func sessionVWAP(bars []*models.PriceData) float64 {
+	var value, volume float64
+	for _, bar := range bars {
+		typical := (bar.High + bar.Low + bar.Close) / 3
+		value += typical * float64(bar.Volume)
+		volume += float64(bar.Volume)
+	}
+	if volume == 0 {
+		return 0
+	}
+	return value / volume
+}
+
+// mergeDuplicateLevels folds stored levels into the strongest level of their zone and deactivates them.
+// It returns every level that changed: the kept levels and the deactivated duplicates.
+func (srs *SupportResistanceService) mergeDuplicateLevels(levels []*models.SupportResistanceLevel) []*models.SupportResistanceLevel {
+	support, resistance := srs.buildZones(levels, nil)
+
+	var changed []*models.SupportResistanceLevel
+	for _, zone := range append(support, resistance...) {
+		if len(zone.Levels) < 2 {
+			continue
+		}
+
+		key := zone.KeyLevel()
+		changed = append(changed, key)
+		for _, level := range zone.Levels {
+			if level == key {
+				continue
+			}
+			key.Touches = max(key.Touches, level.Touches)
+			if level.LastTouch.After(key.LastTouch) {
+				key.LastTouch = level.LastTouch
+			}
+			level.IsActive = false
+			changed = append(changed, level)
+		}
+	}
+
+	return changed
+}
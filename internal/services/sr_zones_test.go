@@ -0,0 +1,73 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestBuildZones tests that overlapping levels merge into one zone scored for round number, prior day and VWAP confluence
+func TestBuildZones(t *testing.T) {
+	service := NewSupportResistanceService(nil, nil)
+
+	levels := []*models.SupportResistanceLevel{
+		{Symbol: "TEST", Level: 100.2, LevelType: "support", Strength: 60, Touches: 3},
+		{Symbol: "TEST", Level: 99.8, LevelType: "support", Strength: 40, Touches: 4},
+		{Symbol: "TEST", Level: 93.1, LevelType: "support", Strength: 50, Touches: 3},
+		{Symbol: "TEST", Level: 100.1, LevelType: "resistance", Strength: 30, Touches: 3},
+	}
+	refs := &models.SRConfluenceRefs{PriorDayLow: 99.6, PriorDayHigh: 104, VWAP: 93.2}
+
+	support, resistance := service.buildZones(levels, refs)
+	if len(support) != 2 || len(resistance) != 1 {
+		t.Fatalf("expected 2 support and 1 resistance zones, got %d and %d", len(support), len(resistance))
+	}
+
+	lower, merged := support[0], support[1]
+	if len(merged.Levels) != 2 || merged.Touches != 7 || merged.Strength != 60 || merged.KeyLevel().Level != 100.2 {
+		t.Errorf("unexpected merged zone: %+v", merged)
+	}
+	if merged.Low >= 99.8 || merged.High <= 100.2 || !merged.Contains(100) {
+		t.Errorf("zone %.2f-%.2f should cover both levels", merged.Low, merged.High)
+	}
+	if merged.Level <= 100 || merged.Level >= 100.2 {
+		t.Errorf("expected a strength-weighted center between the levels, got %.3f", merged.Level)
+	}
+	if !merged.HasConfluence(models.ConfluenceRoundNumber) || !merged.HasConfluence(models.ConfluencePriorDayLow) || merged.HasConfluence(models.ConfluenceVWAP) {
+		t.Errorf("unexpected confluence: %v", merged.Confluence)
+	}
+	if merged.ConfluenceScore != 25 || merged.ZoneStrength != 90 {
+		t.Errorf("expected confluence 25 and zone strength 90, got %.1f and %.1f", merged.ConfluenceScore, merged.ZoneStrength)
+	}
+
+	if !lower.HasConfluence(models.ConfluenceVWAP) || lower.HasConfluence(models.ConfluenceRoundNumber) || lower.ZoneStrength != 60 {
+		t.Errorf("unexpected lower zone: confluence %v, strength %.1f", lower.Confluence, lower.ZoneStrength)
+	}
+
+	// Support and resistance never merge with each other
+	if len(resistance[0].Levels) != 1 {
+		t.Errorf("resistance zone should only hold the resistance level: %+v", resistance[0])
+	}
+}
+
+// TestMergeDuplicateLevels tests that stored levels inside one zone collapse into the strongest level
+func TestMergeDuplicateLevels(t *testing.T) {
+	service := NewSupportResistanceService(nil, nil)
+
+	now := time.Date(2025, 6, 2, 15, 0, 0, 0, time.UTC)
+	strong := &models.SupportResistanceLevel{ID: 1, Level: 50.0, LevelType: "support", Strength: 70, Touches: 3, LastTouch: now.Add(-time.Hour), IsActive: true}
+	duplicate := &models.SupportResistanceLevel{ID: 2, Level: 50.2, LevelType: "support", Strength: 40, Touches: 5, LastTouch: now, IsActive: true}
+	separate := &models.SupportResistanceLevel{ID: 3, Level: 55.0, LevelType: "support", Strength: 30, Touches: 3, LastTouch: now, IsActive: true}
+
+	changed := service.mergeDuplicateLevels([]*models.SupportResistanceLevel{duplicate, separate, strong})
+	if len(changed) != 2 || changed[0] != strong || changed[1] != duplicate {
+		t.Fatalf("expected the kept level and its duplicate to change, got %d levels", len(changed))
+	}
+	if duplicate.IsActive || !strong.IsActive || !separate.IsActive {
+		t.Errorf("unexpected active flags: strong %v, duplicate %v, separate %v", strong.IsActive, duplicate.IsActive, separate.IsActive)
+	}
+	if strong.Touches != 5 || !strong.LastTouch.Equal(now) {
+		t.Errorf("kept level should absorb touches and the last touch: %+v", strong)
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"log"
 	"market-watch-go/internal/database"
 )
@@ -21,18 +22,22 @@ func NewStockService(db *database.Database, polygonService *PolygonService, emaS
 	}
 }
 
-// RefreshAllStocks refreshes price and EMA data for all stocks
-func (ss *StockService) RefreshAllStocks() error {
+// RefreshAllStocks refreshes price and EMA data for all stocks, stopping early if ctx is done
+func (ss *StockService) RefreshAllStocks(ctx context.Context) error {
 	log.Printf("[STOCK_SERVICE] Starting stock refresh...")
 	
-	stocks, err := ss.db.GetStocks()
+	stocks, err := ss.db.WithContext(ctx).GetStocks()
 	if err != nil {
 		return err
 	}
 
 	updated := 0
 	for _, stock := range stocks {
-		if err := ss.RefreshStock(stock.Symbol); err != nil {
+		if err := ctx.Err(); err != nil {
+			log.Printf("[STOCK_SERVICE] Refresh stopped after %d stocks: %v", updated, err)
+			return err
+		}
+		if err := ss.RefreshStock(ctx, stock.Symbol); err != nil {
 			log.Printf("[STOCK_SERVICE] Failed to refresh %s: %v", stock.Symbol, err)
 			continue
 		}
@@ -44,15 +49,15 @@ func (ss *StockService) RefreshAllStocks() error {
 }
 
 // RefreshStock refreshes data for a single stock
-func (ss *StockService) RefreshStock(symbol string) error {
+func (ss *StockService) RefreshStock(ctx context.Context, symbol string) error {
 	// Fetch latest price
-	price, err := ss.emaService.GetLastPrice(symbol)
+	price, err := ss.emaService.GetLastPrice(ctx, symbol)
 	if err != nil {
 		return err
 	}
 
 	// Fetch EMAs
-	emas, err := ss.emaService.GetEMABatch(symbol, []int{9, 50, 200}, "day", 1)
+	emas, err := ss.emaService.GetEMABatch(ctx, symbol, []int{9, 50, 200}, "day", 1)
 	if err != nil {
 		return err
 	}
@@ -68,7 +73,7 @@ func (ss *StockService) RefreshStock(symbol string) error {
 		ema200 = ema.Results.Values[0].Value
 	}
 
-	return ss.db.RefreshStock(symbol, price, ema9, ema50, ema200)
+	return ss.db.WithContext(ctx).RefreshStock(symbol, price, ema9, ema50, ema200)
 }
 
 // DeleteStock deletes a stock from the stocks table
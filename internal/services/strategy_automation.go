@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+
+	"github.com/robfig/cron/v3"
+)
+
+// maxAutomationMatchesListed caps the stocks listed in an automation's notification
+const maxAutomationMatchesListed = 10
+
+// StrategyAutomationService runs the scans and alerts attached to watchlist strategies. Every minute it runs
+// the active automations whose schedule fired since their last run, against the strategy's member stocks,
+// and records each run.
+type StrategyAutomationService struct {
+	db            *database.Database
+	patterns      *PatternDetectionService
+	alertRules    *AlertRuleService
+	notifications *NotificationService
+	workers       *WorkerPool
+	location      *time.Location
+	schedules     *ScheduleControl
+	cron          *cron.Cron
+	mutex         sync.Mutex // serializes runs
+}
+
+// NewStrategyAutomationService creates a new strategy automation service; schedules are read in exchange time
+func NewStrategyAutomationService(db *database.Database, patterns *PatternDetectionService, alertRules *AlertRuleService, calendar *MarketCalendar) *StrategyAutomationService {
+	return &StrategyAutomationService{
+		db:         db,
+		patterns:   patterns,
+		alertRules: alertRules,
+		workers:    NewWorkerPool(1),
+		location:   calendar.Location(),
+		cron:       cron.New(cron.WithLocation(calendar.Location())),
+	}
+}
+
+// SetNotificationService records the stocks each run matched in the notification center
+func (sas *StrategyAutomationService) SetNotificationService(notifications *NotificationService) {
+	sas.notifications = notifications
+}
+
+// SetWorkerPool sets the pool member stocks are processed on; without one they are processed one at a time
+func (sas *StrategyAutomationService) SetWorkerPool(workers *WorkerPool) {
+	sas.workers = workers
+}
+
+// SetScheduleControl sets the control that can disable the automation checks
+func (sas *StrategyAutomationService) SetScheduleControl(schedules *ScheduleControl) {
+	sas.schedules = schedules
+}
+
+// Start checks for due automations every minute
+func (sas *StrategyAutomationService) Start() error {
+	if _, err := sas.cron.AddFunc("* * * * *", sas.scheduledRun); err != nil {
+		return fmt.Errorf("failed to schedule strategy automations: %w", err)
+	}
+	sas.cron.Start()
+	log.Printf("Strategy automation scheduler started")
+	return nil
+}
+
+// scheduledRun runs the due automations unless the automations schedule is disabled
+func (sas *StrategyAutomationService) scheduledRun() {
+	if sas.schedules.Skip(models.ScheduleAutomations) {
+		return
+	}
+	sas.RunDue(clockNow())
+}
+
+// Stop stops the scheduler and waits for a running automation to finish
+func (sas *StrategyAutomationService) Stop(ctx context.Context) error {
+	select {
+	case <-sas.cron.Stop().Done():
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for strategy automation to finish: %w", ctx.Err())
+	}
+}
+
+// Validate checks an automation, its cron schedule and that its strategy exists
+func (sas *StrategyAutomationService) Validate(automation *models.StrategyAutomation) error {
+	if err := automation.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+	if _, err := cron.ParseStandard(automation.Schedule); err != nil {
+		return fmt.Errorf("%w: invalid schedule %q: %w", ErrValidation, automation.Schedule, err)
+	}
+
+	exists, err := sas.db.StrategyExists(automation.StrategyID)
+	if err != nil {
+		return fmt.Errorf("failed to check strategy %d: %w", automation.StrategyID, err)
+	}
+	if !exists {
+		return fmt.Errorf("strategy %d %w", automation.StrategyID, database.ErrNotFound)
+	}
+	return nil
+}
+
+// IsDue reports whether an automation's schedule fired between its last run, or its creation, and now
+func (sas *StrategyAutomationService) IsDue(automation *models.StrategyAutomation, now time.Time) bool {
+	schedule, err := cron.ParseStandard(automation.Schedule)
+	if err != nil {
+		return false
+	}
+
+	since := automation.CreatedAt
+	if automation.LastRunAt != nil {
+		since = *automation.LastRunAt
+	}
+	return !schedule.Next(since.In(sas.location)).After(now)
+}
+
+// RunDue runs every active automation that is due; runs missed while the server was down are made up once
+func (sas *StrategyAutomationService) RunDue(now time.Time) []*models.StrategyAutomationRun {
+	automations, err := sas.db.GetStrategyAutomations(0, true)
+	if err != nil {
+		log.Printf("Failed to get strategy automations: %v", err)
+		return nil
+	}
+
+	var runs []*models.StrategyAutomationRun
+	for _, automation := range automations {
+		if !sas.IsDue(automation, now) {
+			continue
+		}
+
+		run, err := sas.Run(automation, models.AutomationTriggerScheduled)
+		if err != nil {
+			log.Printf("Failed to run strategy automation %d (%s): %v", automation.ID, automation.Name, err)
+			continue
+		}
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// Run runs an automation against its strategy's member stocks, notifies about the stocks it matched and
+// records the run
+func (sas *StrategyAutomationService) Run(automation *models.StrategyAutomation, trigger string) (*models.StrategyAutomationRun, error) {
+	sas.mutex.Lock()
+	defer sas.mutex.Unlock()
+
+	started := clockNow()
+	run := &models.StrategyAutomationRun{
+		AutomationID:   automation.ID,
+		AutomationName: automation.Name,
+		StrategyID:     automation.StrategyID,
+		Action:         automation.Action,
+		Trigger:        trigger,
+		Status:         models.AutomationRunSucceeded,
+		Matches:        []models.AutomationMatch{},
+		StartedAt:      started,
+	}
+
+	if err := sas.execute(automation, run); err != nil {
+		run.Status = models.AutomationRunFailed
+		run.Error = err.Error()
+	}
+	run.DurationMillis = float64(clockNow().Sub(started).Microseconds()) / 1000
+
+	if err := sas.db.InsertStrategyAutomationRun(run); err != nil {
+		return nil, err
+	}
+	if err := sas.db.SetStrategyAutomationLastRun(automation.ID, started); err != nil {
+		return nil, err
+	}
+	automation.LastRunAt = &started
+
+	log.Printf("Strategy automation %q ran on %d stocks: %d matched, %d failed", automation.Name, run.Symbols, len(run.Matches), len(run.Errors))
+	sas.notify(automation, run)
+	return run, nil
+}
+
+// execute runs an automation's action over the strategy's member stocks, filling in the run
+func (sas *StrategyAutomationService) execute(automation *models.StrategyAutomation, run *models.StrategyAutomationRun) error {
+	stocks, err := sas.db.GetStocksByStrategy(automation.StrategyID)
+	if err != nil {
+		return fmt.Errorf("failed to get strategy stocks: %w", err)
+	}
+	symbols := make([]string, 0, len(stocks))
+	for _, stock := range stocks {
+		symbols = append(symbols, stock.Symbol)
+	}
+	run.Symbols = len(symbols)
+
+	var task func(symbol string) ([]models.AutomationMatch, error)
+	switch automation.Action {
+	case models.AutomationActionPatternScan:
+		task = sas.scanTask(automation)
+	case models.AutomationActionAlert:
+		task, err = sas.alertTask(automation, run.StartedAt)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown action %q", automation.Action)
+	}
+
+	var mutex sync.Mutex
+	scan := sas.workers.Run(context.Background(), "Automation "+automation.Name, symbols, func(ctx context.Context, symbol string) error {
+		matches, err := task(symbol)
+		if err != nil {
+			return err
+		}
+		mutex.Lock()
+		run.Matches = append(run.Matches, matches...)
+		mutex.Unlock()
+		return nil
+	})
+
+	sort.Slice(run.Matches, func(i, j int) bool {
+		if run.Matches[i].Symbol != run.Matches[j].Symbol {
+			return run.Matches[i].Symbol < run.Matches[j].Symbol
+		}
+		return run.Matches[i].Detail < run.Matches[j].Detail
+	})
+	run.Errors = scan.Errors
+
+	if scan.Symbols > 0 && scan.Failed == scan.Symbols {
+		return fmt.Errorf("every stock failed")
+	}
+	return nil
+}
+
+// scanTask returns the task running a scan automation's pattern detectors on a member stock
+func (sas *StrategyAutomationService) scanTask(automation *models.StrategyAutomation) func(symbol string) ([]models.AutomationMatch, error) {
+	return func(symbol string) ([]models.AutomationMatch, error) {
+		found, err := sas.patterns.DetectPatterns(context.Background(), symbol, automation.ScanPatterns())
+		if err != nil {
+			return nil, err
+		}
+
+		matches := make([]models.AutomationMatch, 0, len(found))
+		for _, pattern := range found {
+			matches = append(matches, models.AutomationMatch{Symbol: symbol, Detail: pattern})
+		}
+		return matches, nil
+	}
+}
+
+// alertTask returns the task checking an alert automation's conditions on a member stock. Stocks matched
+// within the cooldown are left out, so a stock staying oversold is reported once per cooldown.
+func (sas *StrategyAutomationService) alertTask(automation *models.StrategyAutomation, now time.Time) (func(symbol string) ([]models.AutomationMatch, error), error) {
+	coolingDown := make(map[string]bool)
+	if automation.CooldownMinutes > 0 {
+		recent, err := sas.db.GetStrategyAutomationRuns(&models.StrategyAutomationRunFilter{
+			AutomationID: automation.ID,
+			Since:        now.Add(-time.Duration(automation.CooldownMinutes) * time.Minute),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get recent runs: %w", err)
+		}
+		for _, run := range recent {
+			for _, match := range run.Matches {
+				coolingDown[match.Symbol] = true
+			}
+		}
+	}
+
+	rule := automation.AlertRule()
+	return func(symbol string) ([]models.AutomationMatch, error) {
+		if coolingDown[symbol] {
+			return nil, nil
+		}
+
+		values, err := sas.alertRules.GetFieldValues(symbol)
+		if err != nil {
+			return nil, err
+		}
+		if !rule.Evaluate(values) {
+			return nil, nil
+		}
+
+		return []models.AutomationMatch{{
+			Symbol: symbol,
+			Detail: rule.Describe(),
+			Values: sas.alertRules.conditionValues(rule, values),
+		}}, nil
+	}, nil
+}
+
+// notify records the stocks a run matched in the notification center
+func (sas *StrategyAutomationService) notify(automation *models.StrategyAutomation, run *models.StrategyAutomationRun) {
+	if len(run.Matches) == 0 {
+		return
+	}
+
+	listed := make([]string, 0, maxAutomationMatchesListed)
+	symbols := make(map[string]bool)
+	for _, match := range run.Matches {
+		symbols[match.Symbol] = true
+		if len(listed) < maxAutomationMatchesListed {
+			listed = append(listed, fmt.Sprintf("%s (%s)", match.Symbol, match.Detail))
+		}
+	}
+	message := strings.Join(listed, ", ")
+	if more := len(run.Matches) - len(listed); more > 0 {
+		message += fmt.Sprintf(" and %d more", more)
+	}
+
+	notification := &models.Notification{
+		Category: models.NotificationPattern,
+		Severity: EmailSeverityLow,
+		Title:    fmt.Sprintf("🤖 %s: %d stocks matched", automation.Name, len(symbols)),
+		Message:  message,
+		Link:     fmt.Sprintf("/api/v1/watchlist/strategies/%d/automations/runs", automation.StrategyID),
+	}
+	if automation.Action == models.AutomationActionAlert {
+		notification.Category = models.NotificationAlert
+		notification.Severity = EmailSeverityMedium
+	}
+	if len(symbols) == 1 {
+		notification.Symbol = run.Matches[0].Symbol
+		notification.Title = fmt.Sprintf("🤖 %s: %s", automation.Name, run.Matches[0].Symbol)
+	}
+	sas.notifications.Notify(notification)
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// TestStrategyAutomationSchedule tests validation of automations and when their cron schedule makes them due
+func TestStrategyAutomationSchedule(t *testing.T) {
+	cfg := &config.Config{}
+	db := newTestDB(t)
+
+	strategy, err := db.CreateStrategy(models.Strategy{Name: "Oversold"})
+	if err != nil {
+		t.Fatalf("CreateStrategy failed: %v", err)
+	}
+	calendar := NewMarketCalendar(cfg.MarketHours)
+	service := NewStrategyAutomationService(db, nil, nil, calendar)
+
+	invalid := []*models.StrategyAutomation{
+		{StrategyID: strategy.ID, Name: "No action", Schedule: "0 17 * * *"},
+		{StrategyID: strategy.ID, Name: "Bad pattern", Action: "pattern_scan", Patterns: []string{"cup_handle"}, Schedule: "0 17 * * *"},
+		{StrategyID: strategy.ID, Name: "No conditions", Action: "alert", Schedule: "0 17 * * *"},
+		{StrategyID: strategy.ID, Name: "Bad schedule", Action: "pattern_scan", Schedule: "every day"},
+	}
+	for _, automation := range invalid {
+		if err := service.Validate(automation); !errors.Is(err, ErrValidation) {
+			t.Errorf("%s: expected a validation error, got %v", automation.Name, err)
+		}
+	}
+	missing := &models.StrategyAutomation{StrategyID: 999, Name: "Orphan", Action: "pattern_scan", Schedule: "0 17 * * *"}
+	if err := service.Validate(missing); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("expected an unknown strategy to be not found, got %v", err)
+	}
+
+	daily := &models.StrategyAutomation{StrategyID: strategy.ID, Name: "Wedges", Action: "PATTERN_SCAN", Patterns: []string{"Falling_Wedge"}, Schedule: "30 16 * * 1-5"}
+	if err := service.Validate(daily); err != nil {
+		t.Fatalf("expected a valid automation, got %v", err)
+	}
+	if daily.Action != models.AutomationActionPatternScan || daily.Patterns[0] != models.AutomationPatternFallingWedge {
+		t.Errorf("expected the action and patterns to be normalized, got %q %v", daily.Action, daily.Patterns)
+	}
+
+	// Created on a Tuesday morning, the schedule first fires at 16:30 exchange time that day
+	loc := calendar.Location()
+	daily.CreatedAt = time.Date(2025, time.March, 4, 9, 0, 0, 0, loc)
+	if service.IsDue(daily, time.Date(2025, time.March, 4, 16, 29, 0, 0, loc)) {
+		t.Error("expected the automation not to be due before its schedule fires")
+	}
+	if !service.IsDue(daily, time.Date(2025, time.March, 4, 16, 30, 0, 0, loc)) {
+		t.Error("expected the automation to be due once its schedule fires")
+	}
+	lastRun := time.Date(2025, time.March, 7, 16, 30, 0, 0, loc)
+	daily.LastRunAt = &lastRun
+	if service.IsDue(daily, time.Date(2025, time.March, 9, 20, 0, 0, 0, loc)) {
+		t.Error("expected a weekday schedule not to fire over the weekend")
+	}
+}
+
+// TestStrategyAutomationRun tests that runs cover the strategy's member stocks and are recorded per strategy
+func TestStrategyAutomationRun(t *testing.T) {
+	cfg := &config.Config{}
+	db := newTestDB(t)
+
+	strategy, err := db.CreateStrategy(models.Strategy{Name: "Swing"})
+	if err != nil {
+		t.Fatalf("CreateStrategy failed: %v", err)
+	}
+	for _, symbol := range []string{"AAPL", "MSFT"} {
+		if _, err := db.AddStock(models.Stock{Symbol: symbol, Strategies: []models.Strategy{{ID: strategy.ID}}}); err != nil {
+			t.Fatalf("AddStock failed: %v", err)
+		}
+	}
+
+	patterns := NewPatternDetectionService(db, nil, nil, nil, nil, nil, nil)
+	alerts := NewAlertRuleService(db, nil, nil)
+	service := NewStrategyAutomationService(db, patterns, alerts, NewMarketCalendar(cfg.MarketHours))
+
+	scan := &models.StrategyAutomation{StrategyID: strategy.ID, Name: "Scan", Action: "pattern_scan", Schedule: "0 17 * * *", IsActive: true}
+	oversold := &models.StrategyAutomation{StrategyID: strategy.ID, Name: "Oversold", Action: "alert", Schedule: "0 17 * * *", IsActive: true,
+		Conditions: []models.AlertCondition{{Field: models.AlertFieldRSI14, Operator: "<", Value: 30}}}
+	for _, automation := range []*models.StrategyAutomation{scan, oversold} {
+		if err := service.Validate(automation); err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		if err := db.InsertStrategyAutomation(automation); err != nil {
+			t.Fatalf("InsertStrategyAutomation failed: %v", err)
+		}
+	}
+
+	run, err := service.Run(scan, models.AutomationTriggerManual)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if run.Status != models.AutomationRunSucceeded || run.Symbols != 2 || len(run.Matches) != 0 {
+		t.Errorf("expected a scan of both members finding nothing, got %+v", run)
+	}
+
+	// Without price data every member fails, failing the run
+	run, err = service.Run(oversold, models.AutomationTriggerScheduled)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if run.Status != models.AutomationRunFailed || len(run.Errors) != 2 || run.Error == "" {
+		t.Errorf("expected the alert run to fail for both members, got %+v", run)
+	}
+
+	stored, err := db.GetStrategyAutomationByID(oversold.ID)
+	if err != nil || stored.LastRunAt == nil || stored.Conditions[0].Field != models.AlertFieldRSI14 {
+		t.Fatalf("expected the automation to round trip with its last run, got %+v (%v)", stored, err)
+	}
+
+	runs, err := db.GetStrategyAutomationRuns(&models.StrategyAutomationRunFilter{StrategyID: strategy.ID})
+	if err != nil || len(runs) != 2 {
+		t.Fatalf("expected both runs in the strategy's history, got %d (%v)", len(runs), err)
+	}
+	if runs[0].AutomationID != oversold.ID || runs[0].Trigger != models.AutomationTriggerScheduled || runs[0].Errors["AAPL"] == "" {
+		t.Errorf("expected the alert run first with its errors, got %+v", runs[0])
+	}
+
+	// Deleting the strategy deletes its automations and their history
+	if err := db.DeleteStrategy(strategy.ID); err != nil {
+		t.Fatalf("DeleteStrategy failed: %v", err)
+	}
+	remaining, _ := db.GetStrategyAutomations(0, false)
+	runs, _ = db.GetStrategyAutomationRuns(nil)
+	if len(remaining) != 0 || len(runs) != 0 {
+		t.Errorf("expected the strategy's automations and runs to be deleted, got %d and %d", len(remaining), len(runs))
+	}
+}
@@ -0,0 +1,437 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/models"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Time allowed to write a message to the client
+	streamWriteWait = 10 * time.Second
+	// Time allowed to read the next pong message from the client
+	streamPongWait = 60 * time.Second
+	// Send pings to the client with this period (must be less than pongWait)
+	streamPingPeriod = (streamPongWait * 9) / 10
+	// Maximum size of an inbound client message
+	streamMaxMessageSize = 4096
+	// Number of outbound messages buffered per client before it is dropped
+	streamSendBufferSize = 256
+)
+
+// Stream message types pushed to clients
+const (
+	StreamTypePrice        = "price"
+	StreamTypeVolume       = "volume"
+	StreamTypeIndicators   = "indicators"
+	StreamTypePattern      = "pattern_alert"
+	StreamTypeIndicator    = "indicator_alert"
+	StreamTypeNotification = "notification" // sent to every client
+	StreamTypeAck          = "ack"
+	StreamTypeError        = "error"
+)
+
+// StreamMessage represents a message pushed to WebSocket clients
+type StreamMessage struct {
+	Type      string      `json:"type"`
+	Symbol    string      `json:"symbol,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data,omitempty"`
+}
+
+// StreamRequest represents a subscription request sent by a client
+type StreamRequest struct {
+	Action  string   `json:"action"` // 'subscribe', 'unsubscribe'
+	Symbols []string `json:"symbols"`
+}
+
+// StreamingService fans out newly ingested market data to WebSocket clients
+type StreamingService struct {
+	taService *TechnicalAnalysisService
+	clients   map[*streamClient]bool
+	mutex     sync.RWMutex
+}
+
+// streamClient represents a single WebSocket connection and its subscriptions
+type streamClient struct {
+	service *StreamingService
+	conn    *websocket.Conn
+	send    chan []byte
+	symbols map[string]bool
+	all     bool
+	mutex   sync.RWMutex
+}
+
+// NewStreamingService creates a new streaming service
+func NewStreamingService(taService *TechnicalAnalysisService) *StreamingService {
+	return &StreamingService{
+		taService: taService,
+		clients:   make(map[*streamClient]bool),
+	}
+}
+
+// ServeClient registers a WebSocket connection and pumps messages until it closes.
+// Initial subscriptions can be provided; "*" subscribes to all symbols.
+func (ss *StreamingService) ServeClient(conn *websocket.Conn, symbols []string) {
+	client := &streamClient{
+		service: ss,
+		conn:    conn,
+		send:    make(chan []byte, streamSendBufferSize),
+		symbols: make(map[string]bool),
+	}
+	client.subscribe(symbols)
+
+	ss.mutex.Lock()
+	ss.clients[client] = true
+	ss.mutex.Unlock()
+
+	log.Printf("Streaming client connected from %s (clients: %d)", conn.RemoteAddr(), ss.ClientCount())
+
+	go client.writePump()
+	client.readPump()
+}
+
+// unregister removes a client and closes its send channel
+func (ss *StreamingService) unregister(client *streamClient) {
+	ss.mutex.Lock()
+	if _, ok := ss.clients[client]; ok {
+		delete(ss.clients, client)
+		close(client.send)
+	}
+	ss.mutex.Unlock()
+
+	log.Printf("Streaming client disconnected from %s (clients: %d)", client.conn.RemoteAddr(), ss.ClientCount())
+}
+
+// ClientCount returns the number of connected clients
+func (ss *StreamingService) ClientCount() int {
+	ss.mutex.RLock()
+	defer ss.mutex.RUnlock()
+	return len(ss.clients)
+}
+
+// HasSubscribers reports whether any client is subscribed to the symbol
+func (ss *StreamingService) HasSubscribers(symbol string) bool {
+	symbol = strings.ToUpper(symbol)
+
+	ss.mutex.RLock()
+	defer ss.mutex.RUnlock()
+
+	for client := range ss.clients {
+		if client.isSubscribed(symbol) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetStatus returns the current state of the streaming service
+func (ss *StreamingService) GetStatus() map[string]interface{} {
+	ss.mutex.RLock()
+	defer ss.mutex.RUnlock()
+
+	subscriptions := make(map[string]int)
+	for client := range ss.clients {
+		client.mutex.RLock()
+		if client.all {
+			subscriptions["*"]++
+		}
+		for symbol := range client.symbols {
+			subscriptions[symbol]++
+		}
+		client.mutex.RUnlock()
+	}
+
+	return map[string]interface{}{
+		"connected_clients": len(ss.clients),
+		"subscriptions":     subscriptions,
+	}
+}
+
+// Broadcast sends a message to every client subscribed to the message symbol
+func (ss *StreamingService) Broadcast(message *StreamMessage) {
+	if message.Timestamp.IsZero() {
+		message.Timestamp = time.Now()
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("Failed to marshal stream message: %v", err)
+		return
+	}
+
+	symbol := strings.ToUpper(message.Symbol)
+
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+
+	for client := range ss.clients {
+		if symbol != "" && !client.isSubscribed(symbol) {
+			continue
+		}
+
+		select {
+		case client.send <- payload:
+		default:
+			// Client is too slow to keep up, drop it
+			log.Printf("Streaming client %s is not keeping up, disconnecting", client.conn.RemoteAddr())
+			delete(ss.clients, client)
+			close(client.send)
+		}
+	}
+}
+
+// PublishPriceBars pushes newly ingested price bars and refreshed indicators
+func (ss *StreamingService) PublishPriceBars(symbol string, bars []*models.PriceData) {
+	if len(bars) == 0 || !ss.HasSubscribers(symbol) {
+		return
+	}
+
+	candles := make([]models.TradingViewCandle, 0, len(bars))
+	for _, bar := range bars {
+		candles = append(candles, bar.ToTradingViewCandle())
+	}
+
+	ss.Broadcast(&StreamMessage{
+		Type:   StreamTypePrice,
+		Symbol: symbol,
+		Data:   candles,
+	})
+
+	ss.PublishIndicators(symbol)
+}
+
+// PublishVolumeBars pushes newly ingested volume bars
+func (ss *StreamingService) PublishVolumeBars(symbol string, bars []*models.VolumeData) {
+	if len(bars) == 0 || !ss.HasSubscribers(symbol) {
+		return
+	}
+
+	ss.Broadcast(&StreamMessage{
+		Type:   StreamTypeVolume,
+		Symbol: symbol,
+		Data:   bars,
+	})
+}
+
+// PublishIndicators recalculates and pushes indicators for a symbol
+func (ss *StreamingService) PublishIndicators(symbol string) {
+	if ss.taService == nil || !ss.HasSubscribers(symbol) {
+		return
+	}
+
+	ss.taService.InvalidateCache(symbol)
+	indicators, err := ss.taService.GetIndicators(context.Background(), symbol)
+	if err != nil {
+		log.Printf("Failed to calculate indicators for stream %s: %v", symbol, err)
+		return
+	}
+
+	ss.Broadcast(&StreamMessage{
+		Type:   StreamTypeIndicators,
+		Symbol: symbol,
+		Data:   indicators,
+	})
+
+	// Push any indicator alerts triggered by the fresh values
+	thresholds := &models.IndicatorThresholds{
+		RSIOversold:   30,
+		RSIOverbought: 70,
+		VolumeSpike:   200,
+		MACDBullish:   true,
+		MACDBearish:   true,
+	}
+	alerts, err := ss.taService.CheckIndicatorAlerts(context.Background(), symbol, thresholds)
+	if err != nil {
+		log.Printf("Failed to check indicator alerts for stream %s: %v", symbol, err)
+		return
+	}
+	for _, alert := range alerts {
+		ss.PublishIndicatorAlert(alert)
+	}
+}
+
+// PublishPatternAlert pushes a pattern alert
+func (ss *StreamingService) PublishPatternAlert(alert *models.PatternAlert) {
+	ss.Broadcast(&StreamMessage{
+		Type:      StreamTypePattern,
+		Symbol:    alert.Symbol,
+		Timestamp: alert.TriggeredAt,
+		Data:      alert,
+	})
+}
+
+// PublishIndicatorAlert pushes an indicator alert
+func (ss *StreamingService) PublishIndicatorAlert(alert *models.IndicatorAlert) {
+	ss.Broadcast(&StreamMessage{
+		Type:      StreamTypeIndicator,
+		Symbol:    alert.Symbol,
+		Timestamp: alert.TriggeredAt,
+		Data:      alert,
+	})
+}
+
+// Stop closes all client connections
+func (ss *StreamingService) Stop() {
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+
+	for client := range ss.clients {
+		delete(ss.clients, client)
+		close(client.send)
+	}
+	log.Printf("Streaming service stopped")
+}
+
+// isSubscribed checks whether the client wants updates for a symbol
+func (c *streamClient) isSubscribed(symbol string) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.all || c.symbols[symbol]
+}
+
+// subscribe adds symbols to the client's subscriptions
+func (c *streamClient) subscribe(symbols []string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol == "" {
+			continue
+		}
+		if symbol == "*" {
+			c.all = true
+			continue
+		}
+		c.symbols[symbol] = true
+	}
+}
+
+// unsubscribe removes symbols from the client's subscriptions
+func (c *streamClient) unsubscribe(symbols []string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol == "*" {
+			c.all = false
+			continue
+		}
+		delete(c.symbols, symbol)
+	}
+}
+
+// subscriptions returns the client's current subscriptions
+func (c *streamClient) subscriptions() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	result := make([]string, 0, len(c.symbols)+1)
+	if c.all {
+		result = append(result, "*")
+	}
+	for symbol := range c.symbols {
+		result = append(result, symbol)
+	}
+	return result
+}
+
+// reply queues a message for this client only
+func (c *streamClient) reply(message *StreamMessage) {
+	message.Timestamp = time.Now()
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+
+	c.service.mutex.RLock()
+	defer c.service.mutex.RUnlock()
+
+	if _, ok := c.service.clients[c]; !ok {
+		return
+	}
+	select {
+	case c.send <- payload:
+	default:
+	}
+}
+
+// readPump processes subscription requests from the client
+func (c *streamClient) readPump() {
+	defer func() {
+		c.service.unregister(c)
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadLimit(streamMaxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(streamPongWait))
+	c.conn.SetPongHandler(func(string) error {
+		c.conn.SetReadDeadline(time.Now().Add(streamPongWait))
+		return nil
+	})
+
+	for {
+		var request StreamRequest
+		if err := c.conn.ReadJSON(&request); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				log.Printf("Streaming client read error: %v", err)
+			}
+			return
+		}
+
+		switch request.Action {
+		case "subscribe":
+			c.subscribe(request.Symbols)
+		case "unsubscribe":
+			c.unsubscribe(request.Symbols)
+		default:
+			c.reply(&StreamMessage{
+				Type: StreamTypeError,
+				Data: map[string]string{"message": "unknown action: " + request.Action},
+			})
+			continue
+		}
+
+		c.reply(&StreamMessage{
+			Type: StreamTypeAck,
+			Data: map[string]interface{}{"subscriptions": c.subscriptions()},
+		})
+	}
+}
+
+// writePump writes queued messages and keepalive pings to the client
+func (c *streamClient) writePump() {
+	ticker := time.NewTicker(streamPingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case payload, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// newStreamServer serves the streaming service over WebSockets, subscribing clients to the symbols query
+func newStreamServer(t *testing.T, ss *StreamingService) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("failed to upgrade: %v", err)
+			return
+		}
+		ss.ServeClient(conn, strings.Split(r.URL.Query().Get("symbols"), ","))
+	}))
+	t.Cleanup(func() {
+		ss.Stop()
+		server.Close()
+	})
+	return server
+}
+
+// dialStream connects a client to the stream server and waits until the service has registered it
+func dialStream(t *testing.T, ss *StreamingService, server *httptest.Server, symbols string) *websocket.Conn {
+	clients := ss.ClientCount()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "?symbols=" + symbols
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	waitForStream(t, func() bool { return ss.ClientCount() > clients })
+	return conn
+}
+
+// waitForStream polls until cond holds, failing the test after a few seconds
+func waitForStream(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the streaming service")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// readStream reads the next message pushed to a client
+func readStream(t *testing.T, conn *websocket.Conn) *StreamMessage {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var message StreamMessage
+	if err := conn.ReadJSON(&message); err != nil {
+		t.Fatalf("ReadJSON failed: %v", err)
+	}
+	return &message
+}
+
+// ackedSubscriptions returns the sorted subscriptions of an ack message
+func ackedSubscriptions(t *testing.T, message *StreamMessage) []string {
+	t.Helper()
+	data, _ := message.Data.(map[string]interface{})
+	if message.Type != StreamTypeAck || data == nil {
+		t.Fatalf("expected an ack, got %+v", message)
+	}
+	raw, _ := data["subscriptions"].([]interface{})
+	symbols := make([]string, 0, len(raw))
+	for _, symbol := range raw {
+		symbols = append(symbols, symbol.(string))
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// TestStreamingSubscribe tests clients subscribe and unsubscribe on connect and by request
+func TestStreamingSubscribe(t *testing.T) {
+	ss := NewStreamingService(nil)
+	server := newStreamServer(t, ss)
+	conn := dialStream(t, ss, server, "aapl")
+
+	if !ss.HasSubscribers("AAPL") || ss.HasSubscribers("MSFT") {
+		t.Fatal("expected only the symbols from the connection subscribed")
+	}
+
+	if err := conn.WriteJSON(StreamRequest{Action: "subscribe", Symbols: []string{" msft ", ""}}); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	if got := ackedSubscriptions(t, readStream(t, conn)); strings.Join(got, ",") != "AAPL,MSFT" {
+		t.Errorf("expected AAPL and MSFT subscribed, got %v", got)
+	}
+
+	if err := conn.WriteJSON(StreamRequest{Action: "unsubscribe", Symbols: []string{"AAPL"}}); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	if got := ackedSubscriptions(t, readStream(t, conn)); strings.Join(got, ",") != "MSFT" {
+		t.Errorf("expected only MSFT subscribed, got %v", got)
+	}
+	if ss.HasSubscribers("AAPL") || !ss.HasSubscribers("msft") {
+		t.Error("expected AAPL unsubscribed and MSFT subscribed")
+	}
+
+	if err := conn.WriteJSON(StreamRequest{Action: "replay"}); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	if message := readStream(t, conn); message.Type != StreamTypeError {
+		t.Errorf("expected an error for an unknown action, got %+v", message)
+	}
+
+	status := ss.GetStatus()
+	if status["connected_clients"] != 1 || status["subscriptions"].(map[string]int)["MSFT"] != 1 {
+		t.Errorf("unexpected status %v", status)
+	}
+
+	// Disconnecting unregisters the client
+	conn.Close()
+	waitForStream(t, func() bool { return ss.ClientCount() == 0 })
+	if ss.HasSubscribers("MSFT") {
+		t.Error("expected no subscribers after the client disconnected")
+	}
+}
+
+// TestStreamingBroadcast tests messages fan out to the clients subscribed to their symbol, or to every client
+// without one
+func TestStreamingBroadcast(t *testing.T) {
+	ss := NewStreamingService(nil)
+	server := newStreamServer(t, ss)
+	apple := dialStream(t, ss, server, "AAPL")
+	everything := dialStream(t, ss, server, "*")
+	microsoft := dialStream(t, ss, server, "MSFT")
+
+	ss.Broadcast(&StreamMessage{Type: StreamTypePrice, Symbol: "aapl", Data: map[string]float64{"close": 101}})
+	ss.Broadcast(&StreamMessage{Type: StreamTypeNotification, Data: "market open"})
+
+	for name, conn := range map[string]*websocket.Conn{"AAPL": apple, "*": everything} {
+		if message := readStream(t, conn); message.Type != StreamTypePrice || message.Symbol != "aapl" || message.Timestamp.IsZero() {
+			t.Errorf("%s: expected the AAPL price, got %+v", name, message)
+		}
+		if message := readStream(t, conn); message.Type != StreamTypeNotification {
+			t.Errorf("%s: expected the notification, got %+v", name, message)
+		}
+	}
+
+	// The MSFT client only gets the notification sent to everyone
+	if message := readStream(t, microsoft); message.Type != StreamTypeNotification || message.Data != "market open" {
+		t.Errorf("MSFT: expected only the notification, got %+v", message)
+	}
+}
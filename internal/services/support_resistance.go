@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"sort"
@@ -12,43 +13,65 @@ import (
 
 // SupportResistanceService handles support and resistance level detection
 type SupportResistanceService struct {
-	db        *database.Database
-	taService *TechnicalAnalysisService
-	config    *models.SRDetectionConfig
+	db            *database.Database
+	prices        PriceReader
+	taService     *TechnicalAnalysisService
+	config        *models.SRDetectionConfig
+	volumeProfile *VolumeProfileService
 }
 
 // NewSupportResistanceService creates a new S/R detection service
 func NewSupportResistanceService(db *database.Database, taService *TechnicalAnalysisService) *SupportResistanceService {
-	// Default configuration
-	config := &models.SRDetectionConfig{
-		MinTouches:                3,
-		LookbackDays:              30,
-		StrengthCalculation:       "weighted",
-		MinLevelDistancePercent:   1.0,
-		LevelPenetrationTolerance: 0.5,
-		PivotStrength:             5,
-		VolumeConfirmationRatio:   1.5,
-		MaxLevelAge:               60,
-		MinBouncePercent:          2.0,
-	}
-
 	return &SupportResistanceService{
 		db:        db,
+		prices:    db,
 		taService: taService,
-		config:    config,
+		config:    models.DefaultSRDetectionConfig(),
 	}
 }
 
-// DetectSupportResistanceLevels performs comprehensive S/R detection for a symbol
-func (srs *SupportResistanceService) DetectSupportResistanceLevels(symbol string) (*models.SRAnalysisResult, error) {
-	now := time.Now()
+// SetPriceReader sets where detection reads bars from, e.g. the price cache or only regular session bars
+func (srs *SupportResistanceService) SetPriceReader(prices PriceReader) {
+	srs.prices = prices
+}
+
+// withContext returns a copy of the service whose queries run under ctx
+func (srs *SupportResistanceService) withContext(ctx context.Context) *SupportResistanceService {
+	bound := *srs
+	bound.db = srs.db.WithContext(ctx)
+	bound.prices = bindPriceReader(ctx, srs.prices)
+	return &bound
+}
+
+// Config returns a copy of the detection settings
+func (srs *SupportResistanceService) Config() *models.SRDetectionConfig {
+	config := *srs.config
+	return &config
+}
+
+// SetConfig replaces the detection settings, e.g. with ones tuned through the settings API
+func (srs *SupportResistanceService) SetConfig(config *models.SRDetectionConfig) {
+	srs.config = config
+}
+
+// SetVolumeProfileService sets the service whose point of control and high-volume nodes count as zone confluence
+func (srs *SupportResistanceService) SetVolumeProfileService(volumeProfile *VolumeProfileService) {
+	srs.volumeProfile = volumeProfile
+}
+
+// DetectSupportResistanceLevels performs comprehensive S/R detection for a symbol on the given timeframe,
+// reading and storing levels under ctx
+func (srs *SupportResistanceService) DetectSupportResistanceLevels(ctx context.Context, symbol string, timeframe models.Timeframe) (*models.SRAnalysisResult, error) {
+	srs = srs.withContext(ctx)
+	now := clockNow()
 
 	// Get price data for analysis
-	priceData, err := srs.db.GetPriceData(&models.PriceDataFilter{
-		Symbol: symbol,
-		From:   now.AddDate(0, 0, -srs.config.LookbackDays),
-		To:     now,
-		Limit:  10000,
+	priceData, err := srs.prices.GetPriceData(&models.PriceDataFilter{
+		Symbol:    symbol,
+		From:      now.AddDate(0, 0, -srs.config.LookbackDays),
+		To:        now,
+		Timeframe: timeframe,
+		Limit:     10000,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get price data: %w", err)
@@ -85,6 +108,16 @@ func (srs *SupportResistanceService) DetectSupportResistanceLevels(symbol string
 		return nil, fmt.Errorf("failed to build analysis result: %w", err)
 	}
 
+	// Step 6: Merge overlapping levels into zones scored for confluence
+	result.ConfluenceRefs = srs.confluenceRefs(symbol, now)
+	result.SupportZones, result.ResistanceZones = srs.buildZones(validatedLevels, result.ConfluenceRefs)
+
+	// Step 7: Fibonacci retracements and extensions of the latest significant swing
+	result.FibonacciSwing, result.FibonacciLevels, err = srs.updateFibonacciLevels(symbol, timeframe, pivots, priceData)
+	if err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
 
@@ -119,7 +152,7 @@ func (srs *SupportResistanceService) detectPivotPoints(symbol string, priceData
 				Strength:  strength,
 				Volume:    current.Volume,
 				Confirmed: true,
-				CreatedAt: time.Now(),
+				CreatedAt: clockNow(),
 			}
 			pivots = append(pivots, pivot)
 		}
@@ -142,7 +175,7 @@ func (srs *SupportResistanceService) detectPivotPoints(symbol string, priceData
 				Strength:  strength,
 				Volume:    current.Volume,
 				Confirmed: true,
-				CreatedAt: time.Now(),
+				CreatedAt: clockNow(),
 			}
 			pivots = append(pivots, pivot)
 		}
@@ -234,7 +267,7 @@ func (srs *SupportResistanceService) createLevelFromCluster(cluster []*models.Pi
 		levelType = "resistance"
 	}
 
-	now := time.Now()
+	now := clockNow()
 	level := &models.SupportResistanceLevel{
 		Symbol:          cluster[0].Symbol,
 		Level:           avgPrice,
@@ -417,14 +450,8 @@ func (srs *SupportResistanceService) updateSRLevelsInDatabase(symbol string, lev
 		return fmt.Errorf("failed to get existing S/R levels: %w", err)
 	}
 
-	// Create maps for easier lookup
-	existingMap := make(map[string]*models.SupportResistanceLevel)
-	for _, existing := range existingLevels {
-		key := fmt.Sprintf("%.4f_%s", existing.Level, existing.LevelType)
-		existingMap[key] = existing
-	}
-
-	tolerance := srs.config.LevelPenetrationTolerance / 100.0
+	// Levels whose zone bands overlap are the same level
+	tolerance := srs.config.ZoneWidthPercent / 100.0
 
 	// Process new levels
 	for _, newLevel := range levels {
@@ -449,18 +476,28 @@ func (srs *SupportResistanceService) updateSRLevelsInDatabase(symbol string, lev
 			matchingLevel.MaxBouncePercent = newLevel.MaxBouncePercent
 			matchingLevel.VolumeConfirmed = newLevel.VolumeConfirmed
 			matchingLevel.AvgVolume = newLevel.AvgVolume
-			matchingLevel.LastValidated = time.Now()
+			matchingLevel.LastValidated = clockNow()
 
 			err := srs.db.UpdateSupportResistanceLevel(matchingLevel)
 			if err != nil {
 				return fmt.Errorf("failed to update S/R level: %w", err)
 			}
+			// Setups refer to the stored level detected again
+			newLevel.ID = matchingLevel.ID
 		} else {
 			// Insert new level
 			err := srs.db.InsertSupportResistanceLevel(newLevel)
 			if err != nil {
 				return fmt.Errorf("failed to insert S/R level: %w", err)
 			}
+			existingLevels = append(existingLevels, newLevel)
+		}
+	}
+
+	// Collapse duplicates accumulated by earlier runs
+	for _, level := range srs.mergeDuplicateLevels(existingLevels) {
+		if err := srs.db.UpdateSupportResistanceLevel(level); err != nil {
+			return fmt.Errorf("failed to merge S/R level: %w", err)
 		}
 	}
 
@@ -494,7 +531,7 @@ func (srs *SupportResistanceService) buildAnalysisResult(symbol string, levels [
 	keyLevels := srs.getKeyLevels(levels, 5)
 
 	// Get recent touches
-	recentTouches, err := srs.db.GetRecentSRLevelTouches(symbol, 24, 10)
+	recentTouches, err := srs.db.GetRecentSRLevelTouches(symbol, "", 24, 10)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get recent touches: %w", err)
 	}
@@ -507,7 +544,7 @@ func (srs *SupportResistanceService) buildAnalysisResult(symbol string, levels [
 
 	result := &models.SRAnalysisResult{
 		Symbol:            symbol,
-		AnalysisTime:      time.Now(),
+		AnalysisTime:      clockNow(),
 		SupportLevels:     supportLevels,
 		ResistanceLevels:  resistanceLevels,
 		CurrentPrice:      currentPrice,
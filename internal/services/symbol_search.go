@@ -0,0 +1,317 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// Symbol search defaults
+const (
+	DefaultSymbolSearchCacheTTL = 24 * time.Hour
+	DefaultSymbolSearchLimit    = 10
+	MaxSymbolSearchLimit        = 50
+	symbolSearchFetchLimit      = 100  // candidates requested from Polygon before ranking
+	maxSymbolSearchEntries      = 1000 // cached searches and lookups
+)
+
+// SymbolSearchService searches Polygon's reference tickers for the watchlist typeahead and checks symbols
+// before they are watched. Results are cached, since listings and company names rarely change.
+type SymbolSearchService struct {
+	cfg     *config.Config
+	client  *http.Client
+	enabled bool
+	ttl     time.Duration
+	mutex   sync.Mutex
+	cache   map[string]symbolSearchEntry
+}
+
+// symbolSearchEntry is a cached search result or lookup; a lookup of an unknown ticker caches no matches
+type symbolSearchEntry struct {
+	matches []models.SymbolMatch
+	expires time.Time
+}
+
+// polygonTicker is a ticker in Polygon's reference tickers
+type polygonTicker struct {
+	Ticker          string `json:"ticker"`
+	Name            string `json:"name"`
+	Market          string `json:"market"`
+	Type            string `json:"type"`
+	PrimaryExchange string `json:"primary_exchange"`
+	Active          bool   `json:"active"`
+}
+
+// NewSymbolSearchService creates a symbol search service from the symbol_search config
+func NewSymbolSearchService(cfg *config.Config) *SymbolSearchService {
+	ttl := cfg.SymbolSearch.CacheTTL
+	if ttl <= 0 {
+		ttl = DefaultSymbolSearchCacheTTL
+	}
+
+	timeout := cfg.Polygon.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	return &SymbolSearchService{
+		cfg:     cfg,
+		client:  &http.Client{Timeout: timeout},
+		enabled: cfg.SymbolSearch.Enabled,
+		ttl:     ttl,
+		cache:   make(map[string]symbolSearchEntry),
+	}
+}
+
+// IsEnabled reports whether symbols are searched and checked
+func (ss *SymbolSearchService) IsEnabled() bool {
+	return ss != nil && ss.enabled
+}
+
+// Search returns up to limit active tickers whose symbol or company name matches the query, best matches first
+func (ss *SymbolSearchService) Search(query string, limit int) ([]models.SymbolMatch, error) {
+	if !ss.IsEnabled() {
+		return nil, fmt.Errorf("%w: symbol search is disabled (set symbol_search.enabled)", ErrUnavailable)
+	}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("%w: a search query is required", ErrValidation)
+	}
+	if limit <= 0 {
+		limit = DefaultSymbolSearchLimit
+	}
+
+	key := "search:" + strings.ToUpper(query)
+	matches, ok := ss.cached(key)
+	if !ok {
+		params := url.Values{}
+		params.Set("search", query)
+		params.Set("active", "true")
+		params.Set("limit", strconv.Itoa(symbolSearchFetchLimit))
+
+		var resp struct {
+			Results []polygonTicker `json:"results"`
+		}
+		if _, err := ss.getJSON("/v3/reference/tickers", params, &resp); err != nil {
+			return nil, err
+		}
+
+		matches = make([]models.SymbolMatch, 0, len(resp.Results))
+		for _, ticker := range resp.Results {
+			matches = append(matches, ticker.match())
+		}
+		rankSymbolMatches(query, matches)
+		ss.store(key, matches)
+	}
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// Lookup returns a ticker's reference data, or nil when Polygon doesn't know the ticker
+func (ss *SymbolSearchService) Lookup(symbol string) (*models.SymbolMatch, error) {
+	symbol = strings.ToUpper(symbol)
+
+	key := "ticker:" + symbol
+	matches, ok := ss.cached(key)
+	if !ok {
+		var resp struct {
+			Results polygonTicker `json:"results"`
+		}
+		found, err := ss.getJSON("/v3/reference/tickers/"+url.PathEscape(symbol), url.Values{}, &resp)
+		if err != nil {
+			return nil, err
+		}
+
+		matches = nil
+		if found {
+			matches = []models.SymbolMatch{resp.Results.match()}
+		}
+		ss.store(key, matches)
+	}
+
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	match := matches[0]
+	return &match, nil
+}
+
+// CheckSymbol looks a symbol up and fails with ErrValidation unless it is a known, still traded ticker of the
+// asset class its prefix says
+func (ss *SymbolSearchService) CheckSymbol(symbol string) (*models.SymbolMatch, error) {
+	match, err := ss.Lookup(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if match == nil {
+		return nil, fmt.Errorf("%w: %s is not a known ticker", ErrValidation, symbol)
+	}
+	if !match.Active {
+		return nil, fmt.Errorf("%w: %s is no longer traded", ErrValidation, symbol)
+	}
+	if expected := models.AssetClassOf(symbol); match.AssetClass != expected {
+		return nil, fmt.Errorf("%w: %s is a %s ticker, not %s", ErrValidation, symbol, match.AssetClass, expected)
+	}
+	return match, nil
+}
+
+// cached returns an unexpired cache entry
+func (ss *SymbolSearchService) cached(key string) ([]models.SymbolMatch, bool) {
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+
+	entry, ok := ss.cache[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.matches, true
+}
+
+// store caches matches, dropping expired entries, or every entry if none have expired, once the cache is full
+func (ss *SymbolSearchService) store(key string, matches []models.SymbolMatch) {
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+
+	now := time.Now()
+	if len(ss.cache) >= maxSymbolSearchEntries {
+		for k, entry := range ss.cache {
+			if now.After(entry.expires) {
+				delete(ss.cache, k)
+			}
+		}
+		if len(ss.cache) >= maxSymbolSearchEntries {
+			ss.cache = make(map[string]symbolSearchEntry)
+		}
+	}
+	ss.cache[key] = symbolSearchEntry{matches: matches, expires: now.Add(ss.ttl)}
+}
+
+// getJSON performs a GET request against the Polygon API and decodes the JSON response; found is false
+// when Polygon answers 404
+func (ss *SymbolSearchService) getJSON(path string, params url.Values, out interface{}) (bool, error) {
+	params.Set("apiKey", ss.cfg.Polygon.APIKey)
+
+	req, err := http.NewRequest("GET", ss.cfg.Polygon.BaseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := ss.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("%w: failed to make request: %w", ErrUpstreamUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return false, nil
+	case http.StatusTooManyRequests:
+		return false, fmt.Errorf("%w: reference tickers request rejected", ErrUpstreamRateLimited)
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return false, fmt.Errorf("%w: API request failed with status %d: %s", ErrUpstreamUnavailable, resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return true, nil
+}
+
+// match converts a Polygon ticker, whose market is stocks, otc, crypto or fx, to a symbol match
+func (t polygonTicker) match() models.SymbolMatch {
+	class := models.AssetClassEquity
+	switch t.Market {
+	case "crypto":
+		class = models.AssetClassCrypto
+	case "fx":
+		class = models.AssetClassForex
+	}
+
+	return models.SymbolMatch{
+		Symbol:     t.Ticker,
+		Name:       t.Name,
+		AssetClass: class,
+		Type:       t.Type,
+		Exchange:   t.PrimaryExchange,
+		Active:     t.Active,
+	}
+}
+
+// rankSymbolMatches orders matches by how well they match the query: the exact ticker, then tickers starting
+// with the query, company names with a word starting with it, tickers and names containing it, and finally
+// tickers and names containing its letters in order. Crypto and FX tickers are compared without their prefix.
+func rankSymbolMatches(query string, matches []models.SymbolMatch) {
+	query = strings.ToUpper(strings.TrimSpace(query))
+
+	score := func(m models.SymbolMatch) int {
+		ticker := m.Symbol
+		if i := strings.Index(ticker, ":"); i >= 0 {
+			ticker = ticker[i+1:]
+		}
+		name := strings.ToUpper(m.Name)
+
+		switch {
+		case ticker == query || m.Symbol == query:
+			return 0
+		case strings.HasPrefix(ticker, query):
+			return 1
+		case hasWordPrefix(name, query):
+			return 2
+		case strings.Contains(ticker, query):
+			return 3
+		case strings.Contains(name, query):
+			return 4
+		case isSubsequence(query, ticker) || isSubsequence(query, name):
+			return 5
+		default:
+			return 6
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		si, sj := score(matches[i]), score(matches[j])
+		if si != sj {
+			return si < sj
+		}
+		if len(matches[i].Symbol) != len(matches[j].Symbol) {
+			return len(matches[i].Symbol) < len(matches[j].Symbol)
+		}
+		return matches[i].Symbol < matches[j].Symbol
+	})
+}
+
+// hasWordPrefix reports whether a word of s starts with prefix
+func hasWordPrefix(s, prefix string) bool {
+	for _, word := range strings.Fields(s) {
+		if strings.HasPrefix(word, prefix) {
+			return true
+		}
+	}
+	return strings.HasPrefix(s, prefix)
+}
+
+// isSubsequence reports whether the letters of sub appear in s in order, e.g. "APL" in "APPLE"
+func isSubsequence(sub, s string) bool {
+	i := 0
+	for j := 0; i < len(sub) && j < len(s); j++ {
+		if sub[i] == s[j] {
+			i++
+		}
+	}
+	return i == len(sub)
+}
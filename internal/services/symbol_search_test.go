@@ -0,0 +1,105 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// newTestSymbolSearch creates a symbol search service over a fake Polygon reference tickers API, counting requests
+func newTestSymbolSearch(t *testing.T) (*SymbolSearchService, *int32) {
+	t.Helper()
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		switch r.URL.Path {
+		case "/v3/reference/tickers":
+			if r.URL.Query().Get("search") != "apple" || r.URL.Query().Get("active") != "true" {
+				t.Errorf("unexpected search parameters: %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"results":[
+				{"ticker":"APLE","name":"Apple Hospitality REIT, Inc.","market":"stocks","type":"CS","active":true},
+				{"ticker":"PINE","name":"Pineapple Energy Inc.","market":"stocks","type":"CS","active":true},
+				{"ticker":"AAPL","name":"Apple Inc.","market":"stocks","type":"CS","primary_exchange":"XNAS","active":true}]}`)
+		case "/v3/reference/tickers/AAPL":
+			fmt.Fprint(w, `{"results":{"ticker":"AAPL","name":"Apple Inc.","market":"stocks","type":"CS","active":true}}`)
+		case "/v3/reference/tickers/X:BTCUSD":
+			fmt.Fprint(w, `{"results":{"ticker":"X:BTCUSD","name":"Bitcoin - United States Dollar","market":"crypto","active":true}}`)
+		case "/v3/reference/tickers/TWTR":
+			fmt.Fprint(w, `{"results":{"ticker":"TWTR","name":"Twitter, Inc.","market":"stocks","type":"CS","active":false}}`)
+		case "/v3/reference/tickers/BUSY":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := &config.Config{}
+	cfg.Polygon.BaseURL = server.URL
+	cfg.SymbolSearch.Enabled = true
+	return NewSymbolSearchService(cfg), &requests
+}
+
+// TestSymbolSearchRanksAndCaches tests that results are ranked by how well they match and repeated searches are
+// served from the cache
+func TestSymbolSearchRanksAndCaches(t *testing.T) {
+	search, requests := newTestSymbolSearch(t)
+
+	matches, err := search.Search("apple", 2)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(matches) != 2 || matches[0].Symbol != "AAPL" || matches[1].Symbol != "APLE" {
+		t.Fatalf("expected Apple Inc. first and the other name match second, got %+v", matches)
+	}
+	if matches[0].AssetClass != models.AssetClassEquity || matches[0].Exchange != "XNAS" {
+		t.Errorf("expected the ticker details to be kept, got %+v", matches[0])
+	}
+
+	if again, err := search.Search(" Apple ", 5); err != nil || len(again) != 3 {
+		t.Errorf("expected all 3 cached matches, got %d (%v)", len(again), err)
+	}
+	if *requests != 1 {
+		t.Errorf("expected the repeated search to be cached, got %d requests", *requests)
+	}
+
+	if _, err := search.Search("  ", 5); !errors.Is(err, ErrValidation) {
+		t.Errorf("expected an empty query to fail validation, got %v", err)
+	}
+}
+
+// TestSymbolSearchCheckSymbol tests that only known, active tickers of the right asset class pass the check
+func TestSymbolSearchCheckSymbol(t *testing.T) {
+	search, requests := newTestSymbolSearch(t)
+
+	if match, err := search.CheckSymbol("AAPL"); err != nil || match.Name != "Apple Inc." {
+		t.Errorf("expected AAPL to pass with its name, got %+v (%v)", match, err)
+	}
+	if match, err := search.CheckSymbol("X:BTCUSD"); err != nil || match.AssetClass != models.AssetClassCrypto {
+		t.Errorf("expected the crypto pair to pass, got %+v (%v)", match, err)
+	}
+
+	for _, symbol := range []string{"NOPE", "TWTR"} {
+		if _, err := search.CheckSymbol(symbol); !errors.Is(err, ErrValidation) {
+			t.Errorf("%s: expected a validation error, got %v", symbol, err)
+		}
+	}
+	if _, err := search.CheckSymbol("BUSY"); !errors.Is(err, ErrUpstreamRateLimited) {
+		t.Errorf("expected a rate limit error, got %v", err)
+	}
+
+	// Unknown tickers are cached too
+	before := *requests
+	search.CheckSymbol("NOPE")
+	if *requests != before {
+		t.Errorf("expected the unknown ticker lookup to be cached")
+	}
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Symbol stats defaults
+const (
+	DefaultSymbolStatsDays       = 20
+	DefaultSymbolStatsGapPercent = 1.0
+	defaultSymbolStatsTime       = "16:30"
+
+	symbolStatsATRPeriod = 14
+)
+
+// SymbolStatsService computes each watched symbol's daily trading statistics after the close: ATR, average
+// range, gap frequency and volume by session
+type SymbolStatsService struct {
+	db           *database.Database
+	calendar     *MarketCalendar
+	lookbackDays int
+	gapPercent   float64
+	at           string
+	schedules    *ScheduleControl
+	cron         *cron.Cron
+}
+
+// NewSymbolStatsService creates a new symbol stats service
+func NewSymbolStatsService(cfg *config.Config, db *database.Database, calendar *MarketCalendar) *SymbolStatsService {
+	ss := &SymbolStatsService{
+		db:           db,
+		calendar:     calendar,
+		lookbackDays: cfg.SymbolStats.LookbackDays,
+		gapPercent:   cfg.SymbolStats.GapPercent,
+		at:           cfg.SymbolStats.Time,
+		cron:         cron.New(cron.WithLocation(calendar.Location())),
+	}
+	if ss.lookbackDays <= 0 {
+		ss.lookbackDays = DefaultSymbolStatsDays
+	}
+	if ss.gapPercent <= 0 {
+		ss.gapPercent = DefaultSymbolStatsGapPercent
+	}
+	if ss.at == "" {
+		ss.at = defaultSymbolStatsTime
+	}
+
+	return ss
+}
+
+// SetScheduleControl sets the control that can disable the daily recomputation
+func (ss *SymbolStatsService) SetScheduleControl(schedules *ScheduleControl) {
+	ss.schedules = schedules
+}
+
+// Start schedules the daily recomputation on weekdays at the configured exchange time
+func (ss *SymbolStatsService) Start() error {
+	t, err := time.Parse("15:04", ss.at)
+	if err != nil {
+		return fmt.Errorf("invalid symbol stats time %q: must be HH:MM", ss.at)
+	}
+
+	spec := fmt.Sprintf("%d %d * * 1-5", t.Minute(), t.Hour())
+	if _, err := ss.cron.AddFunc(spec, ss.scheduledRefresh); err != nil {
+		return fmt.Errorf("failed to schedule symbol stats: %w", err)
+	}
+	log.Printf("Scheduled daily symbol stats (%s)", spec)
+
+	ss.cron.Start()
+	return nil
+}
+
+// Stop stops the schedule and waits for a running recomputation to finish
+func (ss *SymbolStatsService) Stop(ctx context.Context) error {
+	select {
+	case <-ss.cron.Stop().Done():
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for symbol stats to finish: %w", ctx.Err())
+	}
+}
+
+// scheduledRefresh recomputes every watched symbol's stats, skipping exchange holidays
+func (ss *SymbolStatsService) scheduledRefresh() {
+	if !ss.calendar.IsTradingDay(clockNow()) || ss.schedules.Skip(models.ScheduleSymbolStats) {
+		return
+	}
+
+	computed, err := ss.RefreshAll()
+	if err != nil {
+		log.Printf("Failed to compute symbol stats: %v", err)
+		return
+	}
+	log.Printf("Computed daily stats of %d symbols", computed)
+}
+
+// RefreshAll recomputes and stores every watched symbol's stats, returning how many were computed. Symbols
+// without enough history are skipped.
+func (ss *SymbolStatsService) RefreshAll() (int, error) {
+	symbols, err := ss.db.GetWatchedSymbols()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get watched symbols: %w", err)
+	}
+
+	computed := 0
+	for _, symbol := range symbols {
+		if _, err := ss.Refresh(symbol); err != nil {
+			log.Printf("Failed to compute %s stats: %v", symbol, err)
+			continue
+		}
+		computed++
+	}
+	return computed, nil
+}
+
+// Get returns a symbol's stored stats, computing them when they never were
+func (ss *SymbolStatsService) Get(symbol string) (*models.SymbolStats, error) {
+	stats, err := ss.db.GetSymbolStats(symbol)
+	if err != nil || stats != nil {
+		return stats, err
+	}
+	return ss.Refresh(symbol)
+}
+
+// Refresh recomputes and stores a symbol's stats
+func (ss *SymbolStatsService) Refresh(symbol string) (*models.SymbolStats, error) {
+	stats, err := ss.Compute(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if err := ss.db.UpsertSymbolStats(stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// Compute measures a symbol's regular session daily candles over the lookback days and averages its volume
+// in each session over the same days
+func (ss *SymbolStatsService) Compute(symbol string) (*models.SymbolStats, error) {
+	now := clockNow()
+	// Calendar days covering the candles, allowing for weekends and holidays
+	days := max(ss.lookbackDays, symbolStatsATRPeriod) + 1
+	from := now.AddDate(0, 0, -(days*7/5 + 10))
+
+	candles, err := NewRegularSessionReader(ss.db, ss.calendar).GetPriceData(&models.PriceDataFilter{
+		Symbol: symbol, From: from, To: now, Timeframe: models.Timeframe1d,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s daily bars: %w", symbol, err)
+	}
+	if len(candles) < 2 {
+		return nil, fmt.Errorf("daily history for %s %w; backfill it with POST /api/jobs/backfill", symbol, database.ErrNotFound)
+	}
+
+	stats := dailyStats(candles, ss.lookbackDays, ss.gapPercent)
+	stats.Symbol = symbol
+	stats.ComputedAt = now
+
+	// Session volumes are averaged over the same trading days as the ranges
+	window := candles[len(candles)-stats.Days:]
+	bars, err := ss.db.GetPriceDataRange(symbol, window[0].Timestamp, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s bars: %w", symbol, err)
+	}
+	volumes := make(map[models.MarketSession]int64)
+	for _, bar := range bars {
+		volumes[ss.calendar.BarSession(bar)] += bar.Volume
+	}
+	stats.AvgPreMarketVolume = volumes[models.SessionPreMarket] / int64(stats.Days)
+	stats.AvgRegularVolume = volumes[models.SessionRegular] / int64(stats.Days)
+	stats.AvgPostMarketVolume = volumes[models.SessionPostMarket] / int64(stats.Days)
+
+	return stats, nil
+}
+
+// dailyStats measures the ATR of all the daily candles, which must be oldest first, and the range and gaps of
+// up to the last lookback of them that follow another candle
+func dailyStats(candles []*models.PriceData, lookback int, gapPercent float64) *models.SymbolStats {
+	last := candles[len(candles)-1]
+	window := candles[max(len(candles)-lookback, 1):]
+
+	stats := &models.SymbolStats{
+		TradingDate: database.TradingDate(last.Timestamp),
+		Days:        len(window),
+		Close:       last.Close,
+		GapPercent:  gapPercent,
+	}
+
+	highs := make([]float64, len(candles))
+	lows := make([]float64, len(candles))
+	closes := make([]float64, len(candles))
+	for i, candle := range candles {
+		highs[i], lows[i], closes[i] = candle.High, candle.Low, candle.Close
+	}
+	stats.ATR14 = roundTo(averageTrueRange(highs, lows, closes, symbolStatsATRPeriod), 4)
+
+	totalRange, totalRangePercent, totalGap := 0.0, 0.0, 0.0
+	for i, candle := range window {
+		prev := candles[len(candles)-len(window)+i-1]
+
+		totalRange += candle.High - candle.Low
+		if candle.Close > 0 {
+			totalRangePercent += (candle.High - candle.Low) / candle.Close * 100
+		}
+
+		if prev.Close <= 0 {
+			continue
+		}
+		gap := (candle.Open - prev.Close) / prev.Close * 100
+		totalGap += math.Abs(gap)
+		switch {
+		case gap >= gapPercent:
+			stats.GapUpDays++
+		case gap <= -gapPercent:
+			stats.GapDownDays++
+		}
+	}
+
+	days := float64(stats.Days)
+	stats.AvgRange = roundTo(totalRange/days, 4)
+	stats.AvgRangePercent = roundTo(totalRangePercent/days, 2)
+	stats.AvgGapPercent = roundTo(totalGap/days, 2)
+	stats.GapFrequency = roundTo(float64(stats.GapUpDays+stats.GapDownDays)/days, 4)
+	if last.Close > 0 {
+		stats.ATRPercent = roundTo(stats.ATR14/last.Close*100, 2)
+	}
+
+	return stats
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// TestSymbolStats tests the ATR, range, gap and session volume stats of regular session daily candles
+func TestSymbolStats(t *testing.T) {
+	cfg := &config.Config{}
+	db := newTestDB(t)
+
+	calendar := NewMarketCalendar(cfg.MarketHours)
+	loc := calendar.Location()
+	at := func(day time.Time, hour, minute int) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc)
+	}
+
+	// Weekdays without holidays, each opening 2% above the prior close every fifth day and ranging 4 around
+	// the open, with a pre-market and a post-market bar
+	var days []time.Time
+	for day := time.Date(2025, 2, 24, 0, 0, 0, 0, loc); !day.After(time.Date(2025, 3, 31, 0, 0, 0, 0, loc)); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			days = append(days, day)
+		}
+	}
+
+	var bars []*models.PriceData
+	price, gapDays := 100.0, 0
+	for k, day := range days {
+		if k > 0 && k%5 == 0 {
+			price *= 1.02
+			if k >= len(days)-20 {
+				gapDays++
+			}
+		}
+		bar := func(ts time.Time, volume int64) *models.PriceData {
+			return &models.PriceData{Symbol: "TEST", Timestamp: ts, Open: price, High: price + 2, Low: price - 2, Close: price, Volume: volume}
+		}
+		bars = append(bars, bar(at(day, 8, 0), 100), bar(at(day, 9, 30), 1000), bar(at(day, 12, 0), 1000),
+			bar(at(day, 15, 55), 1000), bar(at(day, 16, 30), 50))
+	}
+	if err := db.InsertPriceDataBatch(bars); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+
+	SetClock(NewSimulatedClock(at(days[len(days)-1], 17, 0)))
+	t.Cleanup(func() { SetClock(nil) })
+
+	ss := NewSymbolStatsService(cfg, db, calendar)
+	stats, err := ss.Get("TEST")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if stats.TradingDate != "2025-03-31" || stats.Days != 20 || stats.AvgRange != 4 {
+		t.Errorf("unexpected range stats: %+v", stats)
+	}
+	if stats.GapUpDays != gapDays || stats.GapDownDays != 0 || stats.GapFrequency != float64(gapDays)/20 {
+		t.Errorf("expected %d gap up days, got %+v", gapDays, stats)
+	}
+	if stats.ATR14 < 4 || stats.ATR14 > 5 {
+		t.Errorf("expected an ATR just above the daily range, got %.4f", stats.ATR14)
+	}
+	if stats.AvgPreMarketVolume != 100 || stats.AvgRegularVolume != 3000 || stats.AvgPostMarketVolume != 50 {
+		t.Errorf("unexpected session volumes: %+v", stats)
+	}
+
+	// The computed stats are stored and served until recomputed
+	stored, err := db.GetSymbolStats("TEST")
+	if err != nil || stored == nil || stored.ATR14 != stats.ATR14 || stored.GapUpDays != gapDays {
+		t.Errorf("expected the stats to be stored, got %+v, %v", stored, err)
+	}
+	if computed, err := ss.RefreshAll(); err != nil || computed != 0 {
+		t.Errorf("expected no watched symbols to refresh, got %d, %v", computed, err)
+	}
+
+	if _, err := ss.Get("NONE"); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("expected a symbol without bars to be not found, got %v", err)
+	}
+}
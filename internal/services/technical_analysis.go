@@ -1,9 +1,11 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"math"
+	"strings"
 	"sync"
 	"time"
 
@@ -11,13 +13,20 @@ import (
 	"market-watch-go/internal/models"
 )
 
+// macdWarmupFactor multiplies the slow and signal periods into the bars loaded before a MACD series
+const macdWarmupFactor = 3
+
 // TechnicalAnalysisService provides technical analysis calculations
 type TechnicalAnalysisService struct {
 	db           *database.Database
+	prices       PriceReader
 	cache        map[string]*models.TechnicalIndicators
 	cacheExpiry  map[string]time.Time
 	mutex        sync.RWMutex
 	cacheTimeout time.Duration
+	macdFast     int
+	macdSlow     int
+	macdSignal   int
 }
 
 // TechnicalAnalysisConfig holds configuration for technical analysis
@@ -55,18 +64,59 @@ func NewTechnicalAnalysisService(db *database.Database, config *TechnicalAnalysi
 
 	return &TechnicalAnalysisService{
 		db:           db,
+		prices:       db,
 		cache:        make(map[string]*models.TechnicalIndicators),
 		cacheExpiry:  make(map[string]time.Time),
 		cacheTimeout: config.CacheTimeout,
+		macdFast:     defaultPeriod(config.MACDFast, 12),
+		macdSlow:     defaultPeriod(config.MACDSlow, 26),
+		macdSignal:   defaultPeriod(config.MACDSignal, 9),
+	}
+}
+
+// SetPriceCache makes indicator calculations read recent bars from the price cache
+func (tas *TechnicalAnalysisService) SetPriceCache(cache *PriceCache) {
+	tas.prices = cache
+}
+
+// defaultPeriod returns period, or fallback when period is not set
+func defaultPeriod(period, fallback int) int {
+	if period <= 0 {
+		return fallback
+	}
+	return period
+}
+
+// GetIndicators calculates all technical indicators for a symbol on 1-minute bars
+func (tas *TechnicalAnalysisService) GetIndicators(ctx context.Context, symbol string) (*models.TechnicalIndicators, error) {
+	return tas.GetIndicatorsForTimeframe(ctx, symbol, models.DefaultTimeframe)
+}
+
+// indicatorCacheKey returns the cache key for a symbol's indicators on a timeframe
+func indicatorCacheKey(symbol string, timeframe models.Timeframe) string {
+	if !timeframe.IsAggregated() {
+		return symbol
+	}
+	return symbol + "@" + string(timeframe)
+}
+
+// indicatorLookback returns how far back to load price data for a timeframe
+func indicatorLookback(timeframe models.Timeframe) time.Time {
+	if timeframe == models.Timeframe1d {
+		return clockNow().AddDate(-1, 0, 0) // Enough daily candles for MACD and Bollinger Bands
 	}
+	return clockNow().AddDate(0, 0, -60) // Last 60 days for better indicators
 }
 
-// GetIndicators calculates all technical indicators for a symbol
-func (tas *TechnicalAnalysisService) GetIndicators(symbol string) (*models.TechnicalIndicators, error) {
+// GetIndicatorsForTimeframe calculates all technical indicators for a symbol on the given timeframe; the bars
+// are read under ctx
+func (tas *TechnicalAnalysisService) GetIndicatorsForTimeframe(ctx context.Context, symbol string, timeframe models.Timeframe) (*models.TechnicalIndicators, error) {
+	cacheKey := indicatorCacheKey(symbol, timeframe)
+
 	// Check cache first
 	tas.mutex.RLock()
-	if cached, exists := tas.cache[symbol]; exists {
-		if expiry, hasExpiry := tas.cacheExpiry[symbol]; hasExpiry && time.Now().Before(expiry) {
+	if cached, exists := tas.cache[cacheKey]; exists {
+		if expiry, hasExpiry := tas.cacheExpiry[cacheKey]; hasExpiry && clockNow().Before(expiry) {
 			tas.mutex.RUnlock()
 			return cached, nil
 		}
@@ -75,16 +125,17 @@ func (tas *TechnicalAnalysisService) GetIndicators(symbol string) (*models.Techn
 
 	// Get price data
 	filter := &models.PriceDataFilter{
-		Symbol: symbol,
-		From:   time.Now().AddDate(0, 0, -60), // Last 60 days for better indicators
-		To:     time.Now(),
-		Limit:  10000,
+		Symbol:    symbol,
+		From:      indicatorLookback(timeframe),
+		To:        clockNow(),
+		Timeframe: timeframe,
+		Limit:     10000,
 	}
 
-	log.Printf("Fetching price data for symbol %s from %s to %s",
-		symbol, filter.From.Format("2006-01-02"), filter.To.Format("2006-01-02"))
+	log.Printf("Fetching %s price data for symbol %s from %s to %s",
+		timeframe, symbol, filter.From.Format("2006-01-02"), filter.To.Format("2006-01-02"))
 
-	priceData, err := tas.db.GetPriceData(filter)
+	priceData, err := bindPriceReader(ctx, tas.prices).GetPriceData(filter)
 	if err != nil {
 		log.Printf("Error fetching price data for symbol %s: %v", symbol, err)
 		return nil, fmt.Errorf("failed to get price data: %w", err)
@@ -97,7 +148,8 @@ func (tas *TechnicalAnalysisService) GetIndicators(symbol string) (*models.Techn
 			symbol, filter.From.Format("2006-01-02"), filter.To.Format("2006-01-02"))
 
 		// Check if symbol exists in watched symbols
-		watchedSymbols, err := tas.db.GetWatchedSymbols()
+		db := tas.db.WithContext(ctx)
+		watchedSymbols, err := db.GetWatchedSymbols()
 		if err != nil {
 			log.Printf("ERROR: Failed to get watched symbols: %v", err)
 		} else {
@@ -116,7 +168,7 @@ func (tas *TechnicalAnalysisService) GetIndicators(symbol string) (*models.Techn
 		}
 
 		// Check latest data for this symbol
-		latestPrice, err := tas.db.GetLatestPriceData(symbol)
+		latestPrice, err := db.GetLatestPriceData(symbol)
 		if err != nil {
 			log.Printf("ERROR: Failed to get latest price data for %s: %v", symbol, err)
 		} else if latestPrice != nil {
@@ -128,8 +180,8 @@ func (tas *TechnicalAnalysisService) GetIndicators(symbol string) (*models.Techn
 
 		return &models.TechnicalIndicators{
 			Symbol:    symbol,
-			Timestamp: time.Now(),
-			CreatedAt: time.Now(),
+			Timestamp: clockNow(),
+			CreatedAt: clockNow(),
 			// All indicator values will be zero/empty
 		}, nil
 	}
@@ -137,8 +189,8 @@ func (tas *TechnicalAnalysisService) GetIndicators(symbol string) (*models.Techn
 	// Calculate indicators
 	indicators := &models.TechnicalIndicators{
 		Symbol:    symbol,
-		Timestamp: time.Now(),
-		CreatedAt: time.Now(),
+		Timestamp: clockNow(),
+		CreatedAt: clockNow(),
 	}
 
 	// Extract price and volume arrays
@@ -163,8 +215,8 @@ func (tas *TechnicalAnalysisService) GetIndicators(symbol string) (*models.Techn
 	}
 
 	// Calculate MACD
-	if len(closes) >= 26 {
-		macd, signal, histogram := tas.calculateMACD(closes, 12, 26)
+	if len(closes) >= tas.macdSlow {
+		macd, signal, histogram := tas.calculateMACD(closes, tas.macdFast, tas.macdSlow, tas.macdSignal)
 		indicators.MACD = macd
 		indicators.MACDSignal = signal
 		indicators.MACDHistogram = histogram
@@ -186,10 +238,22 @@ func (tas *TechnicalAnalysisService) GetIndicators(symbol string) (*models.Techn
 	// Calculate Volume Ratio
 	indicators.VolumeRatio = tas.calculateVolumeRatio(volumes, 20)
 
+	// Calculate ATR
+	indicators.ATR14 = tas.calculateATR(highs, lows, closes, 14)
+
+	// Calculate Stochastic
+	indicators.StochK, indicators.StochD = tas.calculateStochastic(highs, lows, closes, 14, 3)
+
+	// Calculate ADX
+	indicators.ADX14, indicators.PlusDI, indicators.MinusDI = tas.calculateADX(highs, lows, closes, 14)
+
+	// Calculate OBV
+	indicators.OBV = tas.calculateOBV(closes, volumes)
+
 	// Cache the result
 	tas.mutex.Lock()
-	tas.cache[symbol] = indicators
-	tas.cacheExpiry[symbol] = time.Now().Add(tas.cacheTimeout)
+	tas.cache[cacheKey] = indicators
+	tas.cacheExpiry[cacheKey] = clockNow().Add(tas.cacheTimeout)
 	tas.mutex.Unlock()
 
 	return indicators, nil
@@ -234,22 +298,49 @@ func (tas *TechnicalAnalysisService) calculateRSI(prices []float64, period int)
 	return rsi
 }
 
-// calculateMACD calculates MACD, Signal, and Histogram
-func (tas *TechnicalAnalysisService) calculateMACD(prices []float64, fast, slow int) (float64, float64, float64) {
-	if len(prices) < slow {
+// calculateMACD calculates MACD, Signal, and Histogram for the latest bar
+func (tas *TechnicalAnalysisService) calculateMACD(prices []float64, fast, slow, signal int) (float64, float64, float64) {
+	macdSeries, signalSeries := tas.calculateMACDSeries(prices, fast, slow, signal)
+	if len(macdSeries) == 0 {
 		return 0, 0, 0
 	}
 
-	emaFast := tas.calculateEMA(prices, fast)
-	emaSlow := tas.calculateEMA(prices, slow)
-	macd := emaFast - emaSlow
+	macd := macdSeries[len(macdSeries)-1]
+	signalLine := signalSeries[len(signalSeries)-1]
+	if math.IsNaN(signalLine) {
+		// Not enough MACD history for a signal line yet
+		return macd, 0, 0
+	}
+
+	return macd, signalLine, macd - signalLine
+}
 
-	// For signal line, we'd need to calculate EMA of MACD values
-	// Simplified: using the current MACD value as signal for now
-	signalLine := macd * 0.9 // Simplified signal calculation
-	histogram := macd - signalLine
+// calculateMACDSeries calculates the MACD line and its signal line for every bar.
+// Both slices are aligned with prices; bars without enough history are NaN.
+func (tas *TechnicalAnalysisService) calculateMACDSeries(prices []float64, fast, slow, signal int) ([]float64, []float64) {
+	if len(prices) < slow {
+		return nil, nil
+	}
+
+	emaFast := tas.calculateEMASeries(prices, fast)
+	emaSlow := tas.calculateEMASeries(prices, slow)
+
+	macdSeries := make([]float64, len(prices))
+	for i := range prices {
+		macdSeries[i] = emaFast[i] - emaSlow[i]
+	}
+
+	// Signal line is the EMA of the MACD values, starting once the slow EMA is defined
+	signalSeries := make([]float64, len(prices))
+	for i := range signalSeries {
+		signalSeries[i] = math.NaN()
+	}
+	validMACD := macdSeries[slow-1:]
+	if len(validMACD) >= signal {
+		copy(signalSeries[slow-1:], tas.calculateEMASeries(validMACD, signal))
+	}
 
-	return macd, signalLine, histogram
+	return macdSeries, signalSeries
 }
 
 // calculateSMA calculates Simple Moving Average
@@ -283,6 +374,29 @@ func (tas *TechnicalAnalysisService) calculateEMA(prices []float64, period int)
 	return ema
 }
 
+// calculateEMASeries calculates the Exponential Moving Average for every bar.
+// Values before the first full period are NaN.
+func (tas *TechnicalAnalysisService) calculateEMASeries(prices []float64, period int) []float64 {
+	series := make([]float64, len(prices))
+	for i := range series {
+		series[i] = math.NaN()
+	}
+	if len(prices) < period {
+		return series
+	}
+
+	multiplier := 2.0 / float64(period+1)
+	ema := tas.calculateSMA(prices[:period], period) // Start with SMA
+	series[period-1] = ema
+
+	for i := period; i < len(prices); i++ {
+		ema = (prices[i] * multiplier) + (ema * (1 - multiplier))
+		series[i] = ema
+	}
+
+	return series
+}
+
 // calculateVWAP calculates Volume Weighted Average Price
 func (tas *TechnicalAnalysisService) calculateVWAP(highs, lows, closes []float64, volumes []int64, period int) float64 {
 	if len(closes) < period {
@@ -352,15 +466,148 @@ func (tas *TechnicalAnalysisService) calculateVolumeRatio(volumes []int64, perio
 	return currentVolume / avgVolume
 }
 
+// trueRange returns the true range of bar i, which must be at least 1
+func trueRange(highs, lows, closes []float64, i int) float64 {
+	return math.Max(highs[i]-lows[i], math.Max(math.Abs(highs[i]-closes[i-1]), math.Abs(lows[i]-closes[i-1])))
+}
+
+// calculateATR calculates Average True Range using Wilder's smoothing
+func (tas *TechnicalAnalysisService) calculateATR(highs, lows, closes []float64, period int) float64 {
+	return averageTrueRange(highs, lows, closes, period)
+}
+
+// averageTrueRange calculates Average True Range using Wilder's smoothing, or 0 without period+1 bars
+func averageTrueRange(highs, lows, closes []float64, period int) float64 {
+	if len(closes) < period+1 {
+		return 0
+	}
+
+	atr := 0.0
+	for i := 1; i <= period; i++ {
+		atr += trueRange(highs, lows, closes, i)
+	}
+	atr /= float64(period)
+
+	for i := period + 1; i < len(closes); i++ {
+		atr = (atr*float64(period-1) + trueRange(highs, lows, closes, i)) / float64(period)
+	}
+
+	return atr
+}
+
+// calculateStochastic calculates the Stochastic oscillator %K and its %D moving average
+func (tas *TechnicalAnalysisService) calculateStochastic(highs, lows, closes []float64, kPeriod, dPeriod int) (float64, float64) {
+	if len(closes) < kPeriod+dPeriod-1 {
+		return 0, 0
+	}
+
+	kValues := make([]float64, 0, dPeriod)
+	for end := len(closes) - dPeriod + 1; end <= len(closes); end++ {
+		highest := highs[end-kPeriod]
+		lowest := lows[end-kPeriod]
+		for i := end - kPeriod; i < end; i++ {
+			highest = math.Max(highest, highs[i])
+			lowest = math.Min(lowest, lows[i])
+		}
+
+		k := 50.0 // Flat range, no momentum either way
+		if highest > lowest {
+			k = (closes[end-1] - lowest) / (highest - lowest) * 100
+		}
+		kValues = append(kValues, k)
+	}
+
+	return kValues[len(kValues)-1], tas.calculateSMA(kValues, dPeriod)
+}
+
+// calculateADX calculates the Average Directional Index with +DI and -DI
+func (tas *TechnicalAnalysisService) calculateADX(highs, lows, closes []float64, period int) (float64, float64, float64) {
+	return averageDirectionalIndex(highs, lows, closes, period)
+}
+
+// averageDirectionalIndex calculates the Average Directional Index with +DI and -DI, or zeros without 2*period+1 bars
+func averageDirectionalIndex(highs, lows, closes []float64, period int) (float64, float64, float64) {
+	if len(closes) < 2*period+1 {
+		return 0, 0, 0
+	}
+
+	var smoothedTR, smoothedPlusDM, smoothedMinusDM float64
+	var adx, plusDI, minusDI, dxSum float64
+
+	for i := 1; i < len(closes); i++ {
+		upMove := highs[i] - highs[i-1]
+		downMove := lows[i-1] - lows[i]
+
+		plusDM, minusDM := 0.0, 0.0
+		if upMove > downMove && upMove > 0 {
+			plusDM = upMove
+		}
+		if downMove > upMove && downMove > 0 {
+			minusDM = downMove
+		}
+		tr := trueRange(highs, lows, closes, i)
+
+		if i <= period {
+			// Seed the smoothed sums with the first period
+			smoothedTR += tr
+			smoothedPlusDM += plusDM
+			smoothedMinusDM += minusDM
+			if i < period {
+				continue
+			}
+		} else {
+			smoothedTR = smoothedTR - smoothedTR/float64(period) + tr
+			smoothedPlusDM = smoothedPlusDM - smoothedPlusDM/float64(period) + plusDM
+			smoothedMinusDM = smoothedMinusDM - smoothedMinusDM/float64(period) + minusDM
+		}
+
+		plusDI, minusDI = 0, 0
+		if smoothedTR > 0 {
+			plusDI = 100 * smoothedPlusDM / smoothedTR
+			minusDI = 100 * smoothedMinusDM / smoothedTR
+		}
+
+		dx := 0.0
+		if plusDI+minusDI > 0 {
+			dx = 100 * math.Abs(plusDI-minusDI) / (plusDI + minusDI)
+		}
+
+		// ADX starts as the average of the first period DX values, then uses Wilder's smoothing
+		switch n := i - period + 1; {
+		case n < period:
+			dxSum += dx
+		case n == period:
+			adx = (dxSum + dx) / float64(period)
+		default:
+			adx = (adx*float64(period-1) + dx) / float64(period)
+		}
+	}
+
+	return adx, plusDI, minusDI
+}
+
+// calculateOBV calculates On-Balance Volume over the full series
+func (tas *TechnicalAnalysisService) calculateOBV(closes []float64, volumes []int64) float64 {
+	obv := 0.0
+	for i := 1; i < len(closes); i++ {
+		if closes[i] > closes[i-1] {
+			obv += float64(volumes[i])
+		} else if closes[i] < closes[i-1] {
+			obv -= float64(volumes[i])
+		}
+	}
+	return obv
+}
+
 // GetIndicatorsSummary returns a comprehensive summary of indicators for a symbol
-func (tas *TechnicalAnalysisService) GetIndicatorsSummary(symbol string) (*models.IndicatorSummary, error) {
-	indicators, err := tas.GetIndicators(symbol)
+func (tas *TechnicalAnalysisService) GetIndicatorsSummary(ctx context.Context, symbol string, timeframe models.Timeframe) (*models.IndicatorSummary, error) {
+	indicators, err := tas.GetIndicatorsForTimeframe(ctx, symbol, timeframe)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get current price from latest price data
-	latestPrice, err := tas.db.GetLatestPriceData(symbol)
+	latestPrice, err := tas.db.WithContext(ctx).GetLatestPriceData(symbol)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get latest price for %s: %w", symbol, err)
 	}
@@ -370,6 +617,11 @@ func (tas *TechnicalAnalysisService) GetIndicatorsSummary(symbol string) (*model
 		currentPrice = latestPrice.Close
 	}
 
+	atrPercent := 0.0
+	if currentPrice > 0 {
+		atrPercent = indicators.ATR14 / currentPrice * 100
+	}
+
 	summary := &models.IndicatorSummary{
 		Symbol:       symbol,
 		LastUpdate:   indicators.Timestamp,
@@ -396,6 +648,20 @@ func (tas *TechnicalAnalysisService) GetIndicatorsSummary(symbol string) (*model
 		Volume: &models.VolumeAnalysisData{
 			VWAP:        indicators.VWAP,
 			VolumeRatio: indicators.VolumeRatio,
+			OBV:         indicators.OBV,
+		},
+		Stochastic: &models.StochasticData{
+			K: indicators.StochK,
+			D: indicators.StochD,
+		},
+		TrendStrength: &models.TrendStrengthData{
+			ADX:     indicators.ADX14,
+			PlusDI:  indicators.PlusDI,
+			MinusDI: indicators.MinusDI,
+		},
+		Volatility: &models.VolatilityData{
+			ATR:        indicators.ATR14,
+			ATRPercent: atrPercent,
 		},
 		TrendDirection:   indicators.GetTrendDirection(),
 		OverallSentiment: indicators.GetOverallSentiment(currentPrice),
@@ -404,6 +670,52 @@ func (tas *TechnicalAnalysisService) GetIndicatorsSummary(symbol string) (*model
 	return summary, nil
 }
 
+// GetMACDSeries returns the MACD line, signal line and histogram for every bar between from and to
+func (tas *TechnicalAnalysisService) GetMACDSeries(ctx context.Context, symbol string, from, to time.Time, timeframe models.Timeframe) ([]*models.MACDPoint, error) {
+	// Load a fixed number of bars before the requested range so the EMAs are warmed up, however fine the timeframe
+	warmup, err := tas.db.WithContext(ctx).GetPriceDataBefore(symbol, from, (tas.macdSlow+tas.macdSignal)*macdWarmupFactor, timeframe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warm-up price data: %w", err)
+	}
+
+	filter := &models.PriceDataFilter{
+		Symbol:    symbol,
+		From:      from,
+		To:        to,
+		Timeframe: timeframe,
+	}
+
+	priceData, err := bindPriceReader(ctx, tas.prices).GetPriceData(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price data: %w", err)
+	}
+	priceData = append(warmup, priceData...)
+
+	closes := make([]float64, len(priceData))
+	for i, data := range priceData {
+		closes[i] = data.Close
+	}
+
+	macdSeries, signalSeries := tas.calculateMACDSeries(closes, tas.macdFast, tas.macdSlow, tas.macdSignal)
+
+	points := make([]*models.MACDPoint, 0)
+	for i := range macdSeries {
+		if priceData[i].Timestamp.Before(from) || math.IsNaN(signalSeries[i]) {
+			continue
+		}
+		points = append(points, &models.MACDPoint{
+			Timestamp: priceData[i].Timestamp,
+			MACDData: models.MACDData{
+				MACD:      macdSeries[i],
+				Signal:    signalSeries[i],
+				Histogram: macdSeries[i] - signalSeries[i],
+			},
+		})
+	}
+
+	return points, nil
+}
+
 // InvalidateCache removes cached indicators for a symbol
 func (tas *TechnicalAnalysisService) InvalidateCache(symbol string) {
 	tas.mutex.Lock()
@@ -411,6 +723,12 @@ func (tas *TechnicalAnalysisService) InvalidateCache(symbol string) {
 
 	delete(tas.cache, symbol)
 	delete(tas.cacheExpiry, symbol)
+	for key := range tas.cache {
+		if strings.HasPrefix(key, symbol+"@") {
+			delete(tas.cache, key)
+			delete(tas.cacheExpiry, key)
+		}
+	}
 	log.Printf("Invalidated cache for symbol: %s", symbol)
 }
 
@@ -419,7 +737,7 @@ func (tas *TechnicalAnalysisService) ClearExpiredCache() {
 	tas.mutex.Lock()
 	defer tas.mutex.Unlock()
 
-	now := time.Now()
+	now := clockNow()
 	for symbol, expiry := range tas.cacheExpiry {
 		if now.After(expiry) {
 			delete(tas.cache, symbol)
@@ -444,7 +762,7 @@ func (tas *TechnicalAnalysisService) GetCacheStatus() map[string]interface{} {
 		expiry := tas.cacheExpiry[symbol]
 		details[symbol] = map[string]interface{}{
 			"expires_at":     expiry,
-			"time_to_expiry": time.Until(expiry).String(),
+			"time_to_expiry": expiry.Sub(clockNow()).String(),
 		}
 	}
 
@@ -452,14 +770,14 @@ func (tas *TechnicalAnalysisService) GetCacheStatus() map[string]interface{} {
 }
 
 // CheckIndicatorAlerts checks if any indicator-based alerts should be triggered
-func (tas *TechnicalAnalysisService) CheckIndicatorAlerts(symbol string, thresholds *models.IndicatorThresholds) ([]*models.IndicatorAlert, error) {
-	indicators, err := tas.GetIndicators(symbol)
+func (tas *TechnicalAnalysisService) CheckIndicatorAlerts(ctx context.Context, symbol string, thresholds *models.IndicatorThresholds) ([]*models.IndicatorAlert, error) {
+	indicators, err := tas.GetIndicators(ctx, symbol)
 	if err != nil {
 		return nil, err
 	}
 
 	var alerts []*models.IndicatorAlert
-	now := time.Now()
+	now := clockNow()
 
 	// RSI Oversold Alert
 	if indicators.IsRSIOversold(thresholds.RSIOversold) {
@@ -540,12 +858,12 @@ func (tas *TechnicalAnalysisService) CheckIndicatorAlerts(symbol string, thresho
 }
 
 // UpdateIndicatorsForSymbol updates technical indicators for a specific symbol
-func (tas *TechnicalAnalysisService) UpdateIndicatorsForSymbol(symbol string) error {
+func (tas *TechnicalAnalysisService) UpdateIndicatorsForSymbol(ctx context.Context, symbol string) error {
 	// Invalidate cache to force fresh calculation
 	tas.InvalidateCache(symbol)
 
 	// Calculate fresh indicators
-	indicators, err := tas.GetIndicators(symbol)
+	indicators, err := tas.GetIndicators(ctx, symbol)
 	if err != nil {
 		return fmt.Errorf("failed to calculate indicators for %s: %w", symbol, err)
 	}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// TestCalculateMACD_SignalIsEMAOfMACD tests that the signal line is the EMA of the MACD series
+func TestCalculateMACD_SignalIsEMAOfMACD(t *testing.T) {
+	service := NewTechnicalAnalysisService(nil, nil)
+
+	prices := make([]float64, 60)
+	for i := range prices {
+		prices[i] = 100 + 5*math.Sin(float64(i)/4) + float64(i)*0.2
+	}
+
+	macdSeries, signalSeries := service.calculateMACDSeries(prices, 12, 26, 9)
+	if len(macdSeries) != len(prices) || len(signalSeries) != len(prices) {
+		t.Fatalf("expected series aligned with prices, got %d and %d", len(macdSeries), len(signalSeries))
+	}
+
+	// Signal needs slow+signal-1 bars of history
+	if !math.IsNaN(signalSeries[32]) || math.IsNaN(signalSeries[33]) {
+		t.Errorf("expected first signal value at index 33, got %v at 32 and %v at 33", signalSeries[32], signalSeries[33])
+	}
+
+	expectedSignal := service.calculateEMA(macdSeries[25:], 9)
+	macd, signal, histogram := service.calculateMACD(prices, 12, 26, 9)
+
+	if math.Abs(signal-expectedSignal) > 1e-9 {
+		t.Errorf("expected signal %.6f, got %.6f", expectedSignal, signal)
+	}
+	if math.Abs(macd-(service.calculateEMA(prices, 12)-service.calculateEMA(prices, 26))) > 1e-9 {
+		t.Errorf("unexpected MACD value %.6f", macd)
+	}
+	if math.Abs(histogram-(macd-signal)) > 1e-9 {
+		t.Errorf("expected histogram %.6f, got %.6f", macd-signal, histogram)
+	}
+}
+
+// TestVolatilityAndTrendIndicators tests ATR, Stochastic, ADX and OBV on a steady uptrend
+func TestVolatilityAndTrendIndicators(t *testing.T) {
+	service := NewTechnicalAnalysisService(nil, nil)
+
+	n := 50
+	highs := make([]float64, n)
+	lows := make([]float64, n)
+	closes := make([]float64, n)
+	volumes := make([]int64, n)
+	for i := 0; i < n; i++ {
+		closes[i] = 100 + float64(i)
+		highs[i] = closes[i] + 1
+		lows[i] = closes[i] - 1
+		volumes[i] = 1000
+	}
+
+	// Each bar gaps up by 1 with a range of 2, so every true range is 2
+	if atr := service.calculateATR(highs, lows, closes, 14); math.Abs(atr-2) > 1e-9 {
+		t.Errorf("expected ATR 2, got %.4f", atr)
+	}
+
+	// Close is always 1 below the 14-bar high and 14 above the low
+	k, d := service.calculateStochastic(highs, lows, closes, 14, 3)
+	if math.Abs(k-100*14.0/15.0) > 1e-9 || math.Abs(d-k) > 1e-9 {
+		t.Errorf("unexpected stochastic %%K %.4f %%D %.4f", k, d)
+	}
+
+	adx, plusDI, minusDI := service.calculateADX(highs, lows, closes, 14)
+	if adx < 90 || plusDI <= minusDI {
+		t.Errorf("expected strong uptrend, got ADX %.2f +DI %.2f -DI %.2f", adx, plusDI, minusDI)
+	}
+
+	if obv := service.calculateOBV(closes, volumes); obv != float64((n-1)*1000) {
+		t.Errorf("expected OBV %d, got %.0f", (n-1)*1000, obv)
+	}
+}
+
+// TestGetMACDSeries_LongWarmup tests the requested range is returned in full when the bars before it outnumber
+// a single query's worth, as 1-minute bars do
+func TestGetMACDSeries_LongWarmup(t *testing.T) {
+	db := newTestDB(t)
+
+	from := time.Date(2025, time.March, 4, 15, 0, 0, 0, time.UTC)
+	const warmupBars, rangeBars = 12000, 60
+	bars := make([]*models.PriceData, 0, warmupBars+rangeBars)
+	closes := make([]float64, 0, warmupBars+rangeBars)
+	for i := -warmupBars; i < rangeBars; i++ {
+		price := 100 + 5*math.Sin(float64(i)/7)
+		bars = append(bars, &models.PriceData{Symbol: "TEST", Timestamp: from.Add(time.Duration(i) * time.Minute),
+			Open: price, High: price + 0.5, Low: price - 0.5, Close: price, Volume: 1000})
+		closes = append(closes, price)
+	}
+	if err := db.InsertPriceDataBatch(bars); err != nil {
+		t.Fatalf("InsertPriceDataBatch failed: %v", err)
+	}
+
+	service := NewTechnicalAnalysisService(db, nil)
+	points, err := service.GetMACDSeries(context.Background(), "TEST", from, from.Add((rangeBars-1)*time.Minute), models.Timeframe1m)
+	if err != nil {
+		t.Fatalf("GetMACDSeries failed: %v", err)
+	}
+	if len(points) != rangeBars || !points[0].Timestamp.Equal(from) {
+		t.Fatalf("expected %d points from %s, got %d", rangeBars, from, len(points))
+	}
+
+	// The series matches one computed over the warm-up bars loaded before the range
+	loaded := closes[len(closes)-rangeBars-(26+9)*macdWarmupFactor:]
+	macdSeries, signalSeries := service.calculateMACDSeries(loaded, 12, 26, 9)
+	for i, point := range points {
+		j := len(loaded) - rangeBars + i
+		if math.Abs(point.MACD-macdSeries[j]) > 1e-9 || math.Abs(point.Signal-signalSeries[j]) > 1e-9 {
+			t.Fatalf("point %d: expected MACD %.6f signal %.6f, got %.6f and %.6f", i, macdSeries[j], signalSeries[j], point.MACD, point.Signal)
+		}
+	}
+
+	// Rolled-up candles are warmed up the same way
+	points, err = service.GetMACDSeries(context.Background(), "TEST", from, from.Add((rangeBars-1)*time.Minute), models.Timeframe5m)
+	if err != nil || len(points) != rangeBars/5 {
+		t.Errorf("expected %d 5m points, got %d (%v)", rangeBars/5, len(points), err)
+	}
+}
+
+// TestIndicatorsCancelled tests a cancelled context stops the indicator and MACD series loads
+func TestIndicatorsCancelled(t *testing.T) {
+	db := newTestDB(t)
+	from := time.Date(2025, time.March, 4, 15, 0, 0, 0, time.UTC)
+	if err := db.InsertPriceData(&models.PriceData{Symbol: "TEST", Timestamp: from, Open: 100, High: 101, Low: 99,
+		Close: 100, Volume: 1000}); err != nil {
+		t.Fatalf("InsertPriceData failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	service := NewTechnicalAnalysisService(db, nil)
+	if _, err := service.GetIndicators(ctx, "TEST"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the indicators load cancelled, got %v", err)
+	}
+	if _, err := service.GetMACDSeries(ctx, "TEST", from, from.Add(time.Hour), models.Timeframe1m); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the MACD series load cancelled, got %v", err)
+	}
+}
@@ -0,0 +1,447 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// Telegram rejects messages longer than 4096 characters
+const telegramMaxMessageLength = 4096
+
+// TelegramService sends alerts to a Telegram chat and answers simple bot commands
+type TelegramService struct {
+	config       *config.TelegramConfig
+	client       *http.Client
+	baseURL      string
+	pollTimeout  time.Duration
+	db           *database.Database
+	setupService *SetupDetectionService
+	offset       int64
+	polling      bool       // whether the command polling loop is running
+	mutex        sync.Mutex // guards polling
+	stop         chan struct{}
+	stopOnce     sync.Once
+	wg           sync.WaitGroup // tracks the command polling loop
+}
+
+// telegramResponse is the envelope returned by every Bot API method
+type telegramResponse struct {
+	OK          bool            `json:"ok"`
+	Description string          `json:"description"`
+	Result      json.RawMessage `json:"result"`
+}
+
+// telegramUpdate represents an incoming update from getUpdates
+type telegramUpdate struct {
+	UpdateID int64 `json:"update_id"`
+	Message  *struct {
+		Text string `json:"text"`
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+	} `json:"message"`
+}
+
+// NewTelegramService creates a new Telegram service
+func NewTelegramService(cfg *config.Config, db *database.Database, setupService *SetupDetectionService) *TelegramService {
+	baseURL := cfg.Telegram.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.telegram.org"
+	}
+
+	pollTimeout := cfg.Telegram.PollTimeout
+	if pollTimeout <= 0 {
+		pollTimeout = 30 * time.Second
+	}
+
+	return &TelegramService{
+		config:       &cfg.Telegram,
+		client:       &http.Client{Timeout: pollTimeout + 10*time.Second},
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		pollTimeout:  pollTimeout,
+		db:           db,
+		setupService: setupService,
+		stop:         make(chan struct{}),
+	}
+}
+
+// IsEnabled checks if the Telegram integration is enabled and configured
+func (ts *TelegramService) IsEnabled() bool {
+	return ts != nil && ts.config.Enabled && ts.config.BotToken != "" && ts.config.ChatID != ""
+}
+
+// SendMessage sends a plain text message to the configured chat
+func (ts *TelegramService) SendMessage(text string) error {
+	if !ts.IsEnabled() {
+		return fmt.Errorf("telegram integration is not configured")
+	}
+
+	// The limit counts characters, so cut on one rather than mid-way through a multi-byte character
+	if utf8.RuneCountInString(text) > telegramMaxMessageLength {
+		text = string([]rune(text)[:telegramMaxMessageLength-3]) + "..."
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"chat_id":                  ts.config.ChatID,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal telegram message: %w", err)
+	}
+
+	if _, err := ts.call(context.Background(), "sendMessage", payload); err != nil {
+		return fmt.Errorf("failed to send telegram message: %w", err)
+	}
+
+	return nil
+}
+
+// SendSetupAlert sends a trading setup alert to the configured chat
+func (ts *TelegramService) SendSetupAlert(setup *models.TradingSetup) error {
+	return ts.SendMessage(fmt.Sprintf("🚨 %s: %s\n%s", setup.Symbol, setupTitle(setup), formatSetupLevels(setup)))
+}
+
+// Start begins polling for bot commands in the background
+func (ts *TelegramService) Start() {
+	if !ts.IsEnabled() {
+		log.Printf("Telegram integration disabled")
+		return
+	}
+
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+	if ts.polling {
+		return
+	}
+	ts.polling = true
+
+	ts.wg.Add(1)
+	go func() {
+		defer ts.wg.Done()
+		ts.pollCommands()
+
+		ts.mutex.Lock()
+		ts.polling = false
+		ts.mutex.Unlock()
+	}()
+
+	log.Printf("Telegram bot started (chat %s)", ts.config.ChatID)
+}
+
+// SetConfig replaces the bot token, chat and enabled flag, e.g. after the config file is reloaded.
+// Command polling starts when the integration becomes enabled and stops when it is disabled.
+func (ts *TelegramService) SetConfig(cfg *config.TelegramConfig) {
+	ts.config = cfg
+	if ts.IsEnabled() {
+		ts.Start()
+	}
+}
+
+// Stop stops command polling and waits for the polling loop to exit
+func (ts *TelegramService) Stop(ctx context.Context) error {
+	ts.stopOnce.Do(func() { close(ts.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		ts.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Printf("Telegram service stopped")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for telegram polling to stop: %w", ctx.Err())
+	}
+}
+
+// pollCommands long-polls getUpdates until the service is stopped
+func (ts *TelegramService) pollCommands() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		<-ts.stop
+		cancel()
+	}()
+
+	for {
+		if !ts.IsEnabled() {
+			log.Printf("Telegram bot stopped: integration disabled")
+			return
+		}
+
+		updates, err := ts.getUpdates(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Failed to poll telegram updates: %v", err)
+
+			select {
+			case <-time.After(10 * time.Second):
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			ts.offset = update.UpdateID + 1
+			ts.handleUpdate(update)
+		}
+	}
+}
+
+// getUpdates fetches pending updates after the current offset
+func (ts *TelegramService) getUpdates(ctx context.Context) ([]telegramUpdate, error) {
+	params := url.Values{}
+	params.Set("offset", strconv.FormatInt(ts.offset, 10))
+	params.Set("timeout", strconv.Itoa(int(ts.pollTimeout.Seconds())))
+	params.Set("allowed_updates", `["message"]`)
+
+	result, err := ts.call(ctx, "getUpdates?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var updates []telegramUpdate
+	if err := json.Unmarshal(result, &updates); err != nil {
+		return nil, fmt.Errorf("failed to decode telegram updates: %w", err)
+	}
+
+	return updates, nil
+}
+
+// handleUpdate answers a command sent from the configured chat
+func (ts *TelegramService) handleUpdate(update telegramUpdate) {
+	if update.Message == nil || update.Message.Text == "" {
+		return
+	}
+
+	chatID := strconv.FormatInt(update.Message.Chat.ID, 10)
+	if chatID != ts.config.ChatID {
+		log.Printf("Ignoring telegram message from unauthorized chat %s", chatID)
+		return
+	}
+
+	reply := ts.HandleCommand(update.Message.Text)
+	if reply == "" {
+		return
+	}
+
+	if err := ts.SendMessage(reply); err != nil {
+		log.Printf("Failed to reply to telegram command: %v", err)
+	}
+}
+
+// HandleCommand executes a bot command and returns the reply text
+func (ts *TelegramService) HandleCommand(text string) string {
+	fields := strings.Fields(strings.TrimSpace(text))
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return ""
+	}
+
+	// Commands may be addressed to the bot in group chats, e.g. /setups@market_watch_bot
+	command := strings.ToLower(strings.SplitN(fields[0], "@", 2)[0])
+	args := fields[1:]
+
+	switch command {
+	case "/start", "/help":
+		return telegramHelpText
+	case "/watchlist":
+		return ts.watchlistReply()
+	case "/setups":
+		if len(args) == 0 {
+			return "Usage: /setups SYMBOL"
+		}
+		return ts.setupsReply(strings.ToUpper(args[0]))
+	case "/patterns":
+		return ts.patternsReply()
+	default:
+		return "Unknown command. Send /help for the list of commands."
+	}
+}
+
+const telegramHelpText = `Market Watch commands:
+/watchlist - watched symbols with latest prices
+/setups SYMBOL - detect trading setups for a symbol
+/patterns - active chart patterns`
+
+// watchlistReply lists watched symbols with their latest price
+func (ts *TelegramService) watchlistReply() string {
+	symbols, err := ts.db.GetWatchedSymbols()
+	if err != nil {
+		return fmt.Sprintf("Failed to get watchlist: %v", err)
+	}
+	if len(symbols) == 0 {
+		return "Watchlist is empty."
+	}
+
+	lines := []string{fmt.Sprintf("Watchlist (%d):", len(symbols))}
+	for _, symbol := range symbols {
+		latest, err := ts.db.GetLatestPriceData(symbol)
+		if err != nil || latest == nil {
+			lines = append(lines, fmt.Sprintf("%s - no data", symbol))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s $%.2f (%s)", symbol, latest.Close, latest.Timestamp.Format("Jan 2 15:04")))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// setupsReply runs setup detection for a symbol and summarizes the result
+func (ts *TelegramService) setupsReply(symbol string) string {
+	if ts.setupService == nil {
+		return "Setup detection is not available."
+	}
+
+	result, err := ts.setupService.DetectSetups(context.Background(), symbol)
+	if err != nil {
+		return fmt.Sprintf("Failed to detect setups for %s: %v", symbol, err)
+	}
+	if len(result.Errors) > 0 && len(result.SetupsFound) == 0 {
+		return fmt.Sprintf("Failed to detect setups for %s: %s", symbol, result.Errors[0])
+	}
+	if len(result.SetupsFound) == 0 {
+		return fmt.Sprintf("No setups found for %s.", symbol)
+	}
+
+	lines := []string{fmt.Sprintf("%s setups (%d):", symbol, len(result.SetupsFound))}
+	for _, setup := range result.SetupsFound {
+		lines = append(lines, "", setupTitle(setup), formatSetupLevels(setup))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// patternsReply lists active patterns of every supported type
+func (ts *TelegramService) patternsReply() string {
+	lines := make([]string, 0)
+	failed := make([]string, 0)
+
+	if patterns, err := ts.db.GetActiveHeadShouldersPatterns(); err == nil {
+		for _, p := range patterns {
+			name := "Inverse H&S"
+			if p.IsBearish() {
+				name = "H&S Top"
+			}
+			lines = append(lines, formatPatternLine(p.Symbol, name, p.ThesisComponents.CurrentPhase, p.ThesisComponents.CompletionPercent, p.NecklineLevel, p.CalculateTargetPrice()))
+		}
+	} else {
+		failed = append(failed, "head & shoulders")
+	}
+
+	if patterns, err := ts.db.GetActiveFallingWedgePatterns(); err == nil {
+		for _, p := range patterns {
+			lines = append(lines, formatPatternLine(p.Symbol, patternDisplayName(p.PatternType), p.ThesisComponents.CurrentPhase, p.ThesisComponents.CompletionPercent, p.BreakoutLevel, p.CalculateTargetPrice()))
+		}
+	} else {
+		failed = append(failed, "wedge")
+	}
+
+	if patterns, err := ts.db.GetActiveTrianglePatterns(); err == nil {
+		for _, p := range patterns {
+			lines = append(lines, formatPatternLine(p.Symbol, patternDisplayName(p.PatternType), p.ThesisComponents.CurrentPhase, p.ThesisComponents.CompletionPercent, p.BreakoutLevel, p.CalculateTargetPrice()))
+		}
+	} else {
+		failed = append(failed, "triangle")
+	}
+
+	if patterns, err := ts.db.GetActiveFlagPatterns(); err == nil {
+		for _, p := range patterns {
+			lines = append(lines, formatPatternLine(p.Symbol, patternDisplayName(p.PatternType), p.ThesisComponents.CurrentPhase, p.ThesisComponents.CompletionPercent, p.BreakoutLevel, p.CalculateTargetPrice()))
+		}
+	} else {
+		failed = append(failed, "flag")
+	}
+
+	reply := "No active patterns."
+	if len(lines) > 0 {
+		reply = fmt.Sprintf("Active patterns (%d):\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+	if len(failed) > 0 {
+		reply += fmt.Sprintf("\n(failed to load: %s)", strings.Join(failed, ", "))
+	}
+
+	return reply
+}
+
+// call invokes a Bot API method and returns its result payload
+func (ts *TelegramService) call(ctx context.Context, method string, payload []byte) (json.RawMessage, error) {
+	endpoint := fmt.Sprintf("%s/bot%s/%s", ts.baseURL, ts.config.BotToken, method)
+
+	httpMethod := http.MethodGet
+	if payload != nil {
+		httpMethod = http.MethodPost
+	}
+
+	req, err := http.NewRequestWithContext(ctx, httpMethod, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := ts.client.Do(req)
+	if err != nil {
+		// Avoid leaking the bot token, which is part of the request URL
+		return nil, fmt.Errorf("request to telegram %s failed", strings.SplitN(method, "?", 2)[0])
+	}
+	defer resp.Body.Close()
+
+	var response telegramResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode telegram response (status %d): %w", resp.StatusCode, err)
+	}
+	if !response.OK {
+		return nil, fmt.Errorf("telegram API error (status %d): %s", resp.StatusCode, response.Description)
+	}
+
+	return response.Result, nil
+}
+
+// setupTitle returns a one line description of a setup, e.g. "Support Bounce (bullish) - score 82.5"
+func setupTitle(setup *models.TradingSetup) string {
+	return fmt.Sprintf("%s (%s) - score %.1f", patternDisplayName(setup.SetupType), setup.Direction, setup.QualityScore)
+}
+
+// formatSetupLevels returns the entry, stop, target and risk/reward of a setup
+func formatSetupLevels(setup *models.TradingSetup) string {
+	return fmt.Sprintf("Entry $%.2f | Stop $%.2f | Target $%.2f | R/R %.1f",
+		setup.EntryPrice, setup.StopLoss, setup.Target1, setup.RiskRewardRatio)
+}
+
+// formatPatternLine returns a one line summary of an active pattern
+func formatPatternLine(symbol, name, phase string, completion, breakoutLevel, targetPrice float64) string {
+	return fmt.Sprintf("%s %s - %s %.0f%% (breakout $%.2f, target $%.2f)", symbol, name, phase, completion, breakoutLevel, targetPrice)
+}
+
+// patternDisplayName converts an identifier like "bull_flag" to "Bull Flag"
+func patternDisplayName(identifier string) string {
+	words := strings.Split(identifier, "_")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"market-watch-go/internal/config"
+)
+
+// TestTelegramSendMessage tests that messages are posted to the configured chat
+func TestTelegramSendMessage(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bottest-token/sendMessage" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{Telegram: config.TelegramConfig{
+		Enabled:  true,
+		BotToken: "test-token",
+		ChatID:   "12345",
+		BaseURL:  server.URL,
+	}}
+	ts := NewTelegramService(cfg, nil, nil)
+
+	if err := ts.SendMessage("hello"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	if received["chat_id"] != "12345" || received["text"] != "hello" {
+		t.Errorf("unexpected payload: %v", received)
+	}
+
+	// Long messages are cut to the limit on a character boundary
+	long := strings.Repeat("📈 AAPL → ", 1000)
+	if err := ts.SendMessage(long); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	text, _ := received["text"].(string)
+	if !utf8.ValidString(text) || utf8.RuneCountInString(text) != telegramMaxMessageLength || !strings.HasSuffix(text, "...") {
+		t.Errorf("expected valid UTF-8 of %d characters ending in ..., got %d characters (valid %v)",
+			telegramMaxMessageLength, utf8.RuneCountInString(text), utf8.ValidString(text))
+	}
+	if !strings.HasPrefix(long, strings.TrimSuffix(text, "...")) {
+		t.Error("expected the message start kept")
+	}
+}
+
+// TestTelegramHandleCommand tests command parsing for commands that need no data
+func TestTelegramHandleCommand(t *testing.T) {
+	ts := NewTelegramService(&config.Config{}, nil, nil)
+
+	if reply := ts.HandleCommand("/help@market_watch_bot"); reply != telegramHelpText {
+		t.Errorf("expected help text, got %q", reply)
+	}
+	if reply := ts.HandleCommand("/setups"); !strings.HasPrefix(reply, "Usage:") {
+		t.Errorf("expected usage for /setups without symbol, got %q", reply)
+	}
+	if reply := ts.HandleCommand("/setups aapl"); reply != "Setup detection is not available." {
+		t.Errorf("unexpected /setups reply: %q", reply)
+	}
+	if reply := ts.HandleCommand("hello"); reply != "" {
+		t.Errorf("expected no reply to plain text, got %q", reply)
+	}
+	if reply := ts.HandleCommand("/unknown"); !strings.HasPrefix(reply, "Unknown command") {
+		t.Errorf("unexpected reply to unknown command: %q", reply)
+	}
+}
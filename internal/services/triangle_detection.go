@@ -0,0 +1,360 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// TriangleDetectionService handles ascending and descending triangle detection and monitoring
+type TriangleDetectionService struct {
+	db           *database.Database
+	prices       PriceReader
+	taService    *TechnicalAnalysisService
+	emailService *EmailService
+	webhooks     *WebhookService
+	config       *models.TriangleConfig
+}
+
+// NewTriangleDetectionService creates a new triangle detection service
+func NewTriangleDetectionService(db *database.Database, taService *TechnicalAnalysisService, emailService *EmailService) *TriangleDetectionService {
+	return &TriangleDetectionService{
+		db:           db,
+		prices:       db,
+		taService:    taService,
+		emailService: emailService,
+		config:       models.DefaultTriangleConfig(),
+	}
+}
+
+// SetPriceReader sets where detection reads bars from, e.g. the price cache or only regular session bars
+func (tds *TriangleDetectionService) SetPriceReader(prices PriceReader) {
+	tds.prices = prices
+}
+
+// withContext returns a copy of the service whose queries run under ctx
+func (tds *TriangleDetectionService) withContext(ctx context.Context) *TriangleDetectionService {
+	bound := *tds
+	bound.db = tds.db.WithContext(ctx)
+	bound.prices = bindPriceReader(ctx, tds.prices)
+	return &bound
+}
+
+// Config returns a copy of the detection settings
+func (tds *TriangleDetectionService) Config() *models.TriangleConfig {
+	config := *tds.config
+	return &config
+}
+
+// SetConfig replaces the detection settings, e.g. with overrides from the config file
+func (tds *TriangleDetectionService) SetConfig(config *models.TriangleConfig) {
+	tds.config = config
+}
+
+// SetWebhookService sets the service that posts pattern lifecycle webhooks
+func (tds *TriangleDetectionService) SetWebhookService(webhooks *WebhookService) {
+	tds.webhooks = webhooks
+}
+
+// DetectTriangle detects ascending or descending triangle patterns for a symbol on the given timeframe
+func (tds *TriangleDetectionService) DetectTriangle(ctx context.Context, symbol string, timeframe models.Timeframe) (*models.TrianglePattern, error) {
+	tds = tds.withContext(ctx)
+	log.Printf("Detecting triangle pattern for %s on %s", symbol, timeframe)
+
+	endTime := clockNow()
+	startTime := endTime.Add(-tds.config.MaxPatternDuration)
+
+	priceData, err := tds.prices.GetPriceDataRangeTimeframe(symbol, startTime, endTime, timeframe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price data: %w", err)
+	}
+
+	if len(priceData) < 30 {
+		return nil, fmt.Errorf("%w: insufficient price data for pattern detection", ErrNoPattern)
+	}
+
+	highs, lows := findSwingPoints(priceData, 5)
+
+	// Ascending triangle: flat resistance over rising support
+	pattern := tds.analyzeTriangle(symbol, highs, lows, true, priceData)
+	if pattern == nil {
+		// Descending triangle: flat support under falling resistance
+		pattern = tds.analyzeTriangle(symbol, lows, highs, false, priceData)
+	}
+	if pattern == nil {
+		return nil, fmt.Errorf("%w: no triangle formation", ErrNoPattern)
+	}
+
+	// A formation found again by a later scan refreshes the stored pattern instead of adding a duplicate
+	stored, err := tds.db.GetTrianglePatternByFingerprint(symbol, pattern.Fingerprint())
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil {
+		if !stored.IsComplete {
+			stored.Refresh(pattern)
+			if err := tds.db.UpdateTrianglePattern(stored); err != nil {
+				return nil, err
+			}
+		}
+		log.Printf("%s pattern for %s already stored (ID: %d)", stored.PatternType, symbol, stored.ID)
+		return stored, nil
+	}
+
+	// Avoid storing the same active pattern on every scan
+	existing, err := tds.db.GetTrianglePatterns(&models.TriangleFilter{
+		Symbol:      symbol,
+		PatternType: pattern.PatternType,
+		IsComplete:  boolPtr(false),
+		Limit:       1,
+	})
+	if err == nil && len(existing) > 0 {
+		log.Printf("Active %s pattern already tracked for %s (ID: %d)", pattern.PatternType, symbol, existing[0].ID)
+		return existing[0], nil
+	}
+
+	if err := tds.db.InsertTrianglePattern(pattern); err != nil {
+		log.Printf("Warning: Failed to store pattern in database: %v", err)
+		return pattern, nil
+	}
+
+	log.Printf("Successfully detected and stored %s pattern for %s (ID: %d)", pattern.PatternType, symbol, pattern.ID)
+	emitPatternDetected(tds.webhooks, symbol, pattern)
+	notifyPatternDetected(tds.emailService, symbol, tds.patternName(pattern), pattern.BreakoutLevel, pattern.CalculateTargetPrice(), pattern.ThesisComponents.CompletionPercent, pattern)
+
+	return pattern, nil
+}
+
+// analyzeTriangle looks for a flat boundary (flat points) converging with a sloping boundary (sloping points)
+func (tds *TriangleDetectionService) analyzeTriangle(symbol string, flat, sloping []models.PatternPoint, bullish bool, priceData []*models.PriceData) *models.TrianglePattern {
+	// Try the widest run of flat touches first
+	for i := 0; i <= len(flat)-tds.config.MinTouchPoints; i++ {
+		touches := flat[i:]
+
+		level, ok := tds.flatLevel(touches)
+		if !ok {
+			continue
+		}
+
+		start := touches[0].Timestamp
+		var line []models.PatternPoint
+		for _, point := range sloping {
+			if point.Timestamp.After(start) {
+				line = append(line, point)
+			}
+		}
+		if len(line) < 2 {
+			continue
+		}
+
+		first, last := line[0], line[len(line)-1]
+		days := last.Timestamp.Sub(first.Timestamp).Hours() / 24
+		if days <= 0 {
+			continue
+		}
+
+		// Sloping line must converge toward the flat line
+		slopePerDay := (last.Price - first.Price) / first.Price * 100 / days
+		if bullish && (slopePerDay < tds.config.MinSlope || last.Price >= level) {
+			continue
+		}
+		if !bullish && (slopePerDay > -tds.config.MinSlope || last.Price <= level) {
+			continue
+		}
+
+		end := touches[len(touches)-1].Timestamp
+		if last.Timestamp.After(end) {
+			end = last.Timestamp
+		}
+
+		duration := end.Sub(start)
+		if duration < tds.config.MinPatternDuration || duration > tds.config.MaxPatternDuration {
+			continue
+		}
+
+		height := math.Abs(level - first.Price)
+		if height/level < tds.config.MinPatternHeight {
+			continue
+		}
+
+		return tds.buildTrianglePattern(symbol, touches, first, last, level, height, bullish, priceData, start, end)
+	}
+
+	return nil
+}
+
+// flatLevel returns the average price of the touches if they all lie within the flat tolerance
+func (tds *TriangleDetectionService) flatLevel(touches []models.PatternPoint) (float64, bool) {
+	minPrice, maxPrice, sum := math.MaxFloat64, 0.0, 0.0
+	for _, touch := range touches {
+		minPrice = math.Min(minPrice, touch.Price)
+		maxPrice = math.Max(maxPrice, touch.Price)
+		sum += touch.Price
+	}
+
+	level := sum / float64(len(touches))
+	if level == 0 || (maxPrice-minPrice)/level*100 > tds.config.FlatTolerance {
+		return 0, false
+	}
+
+	return level, true
+}
+
+// buildTrianglePattern constructs the complete pattern structure
+func (tds *TriangleDetectionService) buildTrianglePattern(symbol string, touches []models.PatternPoint, first, last models.PatternPoint, level, height float64, bullish bool, priceData []*models.PriceData, start, end time.Time) *models.TrianglePattern {
+	flatStart, flatEnd := touches[0], touches[len(touches)-1]
+	flatHours := flatEnd.Timestamp.Sub(flatStart.Timestamp).Hours()
+	slopingSlope := (last.Price - first.Price) / last.Timestamp.Sub(first.Timestamp).Hours()
+
+	flatSlope := 0.0
+	if flatHours > 0 {
+		flatSlope = (flatEnd.Price - flatStart.Price) / flatHours
+	}
+
+	pattern := &models.TrianglePattern{
+		Symbol:        symbol,
+		BreakoutLevel: level,
+		PatternWidth:  int64(end.Sub(start).Minutes()),
+		PatternHeight: height,
+		TouchPoints:   len(touches),
+		VolumeProfile: volumeProfileBetween(priceData, start, end),
+		DetectedAt:    clockNow(),
+		LastUpdated:   clockNow(),
+		CurrentPhase:  models.PhaseFormation,
+		IsComplete:    false,
+	}
+
+	if bullish {
+		pattern.PatternType = models.PatternAscendingTriangle
+		pattern.UpperTrendLine1, pattern.UpperTrendLine2 = flatStart, flatEnd
+		pattern.LowerTrendLine1, pattern.LowerTrendLine2 = first, last
+		pattern.UpperSlope, pattern.LowerSlope = flatSlope, slopingSlope
+	} else {
+		pattern.PatternType = models.PatternDescendingTriangle
+		pattern.UpperTrendLine1, pattern.UpperTrendLine2 = first, last
+		pattern.LowerTrendLine1, pattern.LowerTrendLine2 = flatStart, flatEnd
+		pattern.UpperSlope, pattern.LowerSlope = slopingSlope, flatSlope
+	}
+
+	pattern.ThesisComponents.InitializeTriangleThesis(bullish)
+	tds.evaluateInitialThesis(pattern, first, last)
+
+	return pattern
+}
+
+// evaluateInitialThesis completes the formation components observed at detection time
+func (tds *TriangleDetectionService) evaluateInitialThesis(pattern *models.TrianglePattern, first, last models.PatternPoint) {
+	thesis := &pattern.ThesisComponents
+
+	completeComponent(&thesis.FlatBoundary, 80.0,
+		fmt.Sprintf("Flat boundary at $%.2f", pattern.BreakoutLevel))
+	completeComponent(&thesis.SlopingBoundary, 75.0,
+		fmt.Sprintf("Sloping boundary from $%.2f to $%.2f", first.Price, last.Price))
+
+	if pattern.TouchPoints >= tds.config.MinTouchPoints {
+		completeComponent(&thesis.MinimumTouchPoints, 70.0+math.Min(30.0, float64(pattern.TouchPoints-tds.config.MinTouchPoints)*10),
+			fmt.Sprintf("%d touches of the flat boundary", pattern.TouchPoints))
+	}
+
+	if pattern.VolumeProfile == "decreasing" {
+		completeComponent(&thesis.VolumeContraction, 70.0, "Volume decreased during formation")
+	}
+
+	// Formation alerts are covered by the detection email
+	for _, component := range thesis.GetAllComponents() {
+		if component.IsCompleted {
+			component.NotificationSent = true
+		}
+	}
+
+	thesis.CalculateCompletion()
+	thesis.UpdatePhase()
+	pattern.CurrentPhase = thesis.CurrentPhase
+}
+
+// MonitorActivePatterns updates the thesis of all active triangle patterns
+func (tds *TriangleDetectionService) MonitorActivePatterns() error {
+	patterns, err := tds.db.GetActiveTrianglePatterns()
+	if err != nil {
+		return fmt.Errorf("failed to get active triangle patterns: %w", err)
+	}
+
+	log.Printf("Monitoring %d active triangle patterns", len(patterns))
+
+	for _, pattern := range patterns {
+		if err := tds.updatePatternThesis(pattern); err != nil {
+			log.Printf("Failed to update triangle thesis for %s: %v", pattern.Symbol, err)
+			continue
+		}
+
+		if err := tds.db.UpdateTrianglePattern(pattern); err != nil {
+			log.Printf("Failed to save updated triangle pattern for %s: %v", pattern.Symbol, err)
+		}
+	}
+
+	return nil
+}
+
+// updatePatternThesis updates breakout and target components based on the latest price
+func (tds *TriangleDetectionService) updatePatternThesis(pattern *models.TrianglePattern) error {
+	latest, err := tds.db.GetLatestPriceData(pattern.Symbol)
+	if err != nil {
+		return fmt.Errorf("failed to get latest price data: %w", err)
+	}
+	if latest == nil {
+		return fmt.Errorf("no price data available for symbol %s", pattern.Symbol)
+	}
+
+	thesis := &pattern.ThesisComponents
+	price := latest.Close
+	previousPhase := pattern.CurrentPhase
+
+	if pattern.IsBrokenOut(price) {
+		completeComponent(&thesis.BoundaryBreak, 85.0,
+			fmt.Sprintf("Price broke the flat boundary: $%.2f vs $%.2f", price, pattern.BreakoutLevel))
+		completeComponent(&thesis.PriceCloseBeyond, 85.0,
+			fmt.Sprintf("Close of $%.2f beyond $%.2f at %s", price, pattern.BreakoutLevel, latest.Timestamp.Format("2006-01-02 15:04")))
+
+		if ratio, err := latestVolumeRatio(tds.db, pattern.Symbol); err == nil && ratio >= tds.config.BreakoutVolumeRatio {
+			completeComponent(&thesis.VolumeConfirmation, 80.0,
+				fmt.Sprintf("Breakout volume %.1fx average", ratio))
+		}
+	}
+
+	if thesis.BoundaryBreak.IsCompleted {
+		target := pattern.CalculateTargetPrice()
+		partial := pattern.BreakoutLevel + (target-pattern.BreakoutLevel)*0.5
+
+		if (pattern.IsBullish() && price >= partial) || (!pattern.IsBullish() && price <= partial) {
+			completeComponent(&thesis.PartialTarget, 90.0, fmt.Sprintf("50%% target reached: $%.2f", partial))
+		}
+
+		if (pattern.IsBullish() && price >= target) || (!pattern.IsBullish() && price <= target) {
+			completeComponent(&thesis.FullTarget, 100.0, fmt.Sprintf("Full target reached: $%.2f", target))
+			pattern.IsComplete = true
+		}
+	}
+
+	thesis.CalculateCompletion()
+	thesis.UpdatePhase()
+	pattern.CurrentPhase = thesis.CurrentPhase
+	emitPhaseChange(tds.webhooks, pattern.Symbol, previousPhase, pattern.CurrentPhase, price, pattern)
+
+	notifyCompletedComponents(tds.emailService, pattern.Symbol, tds.patternName(pattern), pattern.CurrentPhase,
+		thesis.GetAllComponents(), pattern.CalculateTargetPrice(), pattern.BreakoutLevel, pattern)
+
+	return nil
+}
+
+// patternName returns the display name of the triangle type
+func (tds *TriangleDetectionService) patternName(pattern *models.TrianglePattern) string {
+	if pattern.IsBullish() {
+		return "Ascending Triangle"
+	}
+	return "Descending Triangle"
+}
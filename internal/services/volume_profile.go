@@ -0,0 +1,245 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// Volume profile defaults
+const (
+	defaultProfileWindowDays      = 20
+	defaultProfileBuckets         = 50
+	defaultValueAreaPercent       = 70.0
+	defaultHVNRatio               = 1.5
+	defaultProfileRefreshInterval = time.Hour
+)
+
+// VolumeProfileService buckets traded volume by price and keeps each symbol's latest profile
+type VolumeProfileService struct {
+	db               *database.Database
+	windowDays       int
+	buckets          int
+	valueAreaPercent float64
+	hvnRatio         float64
+	refreshInterval  time.Duration
+}
+
+// NewVolumeProfileService creates a new volume profile service
+func NewVolumeProfileService(cfg *config.Config, db *database.Database) *VolumeProfileService {
+	vps := &VolumeProfileService{
+		db:               db,
+		windowDays:       cfg.VolumeProfile.WindowDays,
+		buckets:          cfg.VolumeProfile.Buckets,
+		valueAreaPercent: cfg.VolumeProfile.ValueAreaPercent,
+		hvnRatio:         cfg.VolumeProfile.HVNRatio,
+		refreshInterval:  cfg.VolumeProfile.RefreshInterval,
+	}
+	if vps.windowDays <= 0 {
+		vps.windowDays = defaultProfileWindowDays
+	}
+	if vps.buckets <= 0 {
+		vps.buckets = defaultProfileBuckets
+	}
+	if vps.valueAreaPercent <= 0 {
+		vps.valueAreaPercent = defaultValueAreaPercent
+	}
+	if vps.hvnRatio <= 0 {
+		vps.hvnRatio = defaultHVNRatio
+	}
+	if vps.refreshInterval <= 0 {
+		vps.refreshInterval = defaultProfileRefreshInterval
+	}
+
+	return vps
+}
+
+// Profile returns the symbol's stored profile for the configured window, recomputing and storing it
+// when it is missing or older than the refresh interval
+func (vps *VolumeProfileService) Profile(symbol string) (*models.VolumeProfile, error) {
+	symbol = strings.ToUpper(symbol)
+
+	stored, err := vps.db.GetVolumeProfile(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil && stored.WindowDays == vps.windowDays && len(stored.Buckets) == vps.buckets &&
+		clockNow().Sub(stored.ComputedAt) < vps.refreshInterval {
+		return stored, nil
+	}
+
+	return vps.Refresh(symbol)
+}
+
+// Refresh computes the symbol's profile for the configured window and stores it
+func (vps *VolumeProfileService) Refresh(symbol string) (*models.VolumeProfile, error) {
+	profile, err := vps.Compute(symbol, vps.windowDays, vps.buckets)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := vps.db.UpsertVolumeProfile(profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// Compute builds a symbol's profile over the last windowDays calendar days without storing it.
+// Zero arguments use the configured window and bucket count.
+func (vps *VolumeProfileService) Compute(symbol string, windowDays, buckets int) (*models.VolumeProfile, error) {
+	if windowDays <= 0 {
+		windowDays = vps.windowDays
+	}
+	if buckets <= 0 {
+		buckets = vps.buckets
+	}
+	if buckets > models.MaxVolumeProfileBuckets {
+		return nil, fmt.Errorf("%w: buckets must be at most %d", ErrValidation, models.MaxVolumeProfileBuckets)
+	}
+
+	symbol = strings.ToUpper(symbol)
+	now := clockNow()
+	from := now.AddDate(0, 0, -windowDays)
+
+	bars, err := vps.db.GetPriceDataRange(symbol, from, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price data: %w", err)
+	}
+
+	profile := buildVolumeProfile(bars, buckets, vps.valueAreaPercent, vps.hvnRatio)
+	if profile == nil {
+		return nil, fmt.Errorf("no traded volume for %s in the last %d days: %w", symbol, windowDays, database.ErrNotFound)
+	}
+	profile.Symbol = symbol
+	profile.WindowDays = windowDays
+	profile.From = from
+	profile.To = now
+	profile.ComputedAt = now
+
+	return profile, nil
+}
+
+// buildVolumeProfile spreads each bar's volume evenly over the price buckets its range covers, then
+// finds the point of control, the value area and the high-volume nodes. It returns nil without volume.
+func buildVolumeProfile(bars []*models.PriceData, buckets int, valueAreaPercent, hvnRatio float64) *models.VolumeProfile {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, bar := range bars {
+		if bar.Volume <= 0 || bar.Low <= 0 {
+			continue
+		}
+		low = math.Min(low, bar.Low)
+		high = math.Max(high, bar.High)
+	}
+	if math.IsInf(low, 1) {
+		return nil
+	}
+	if high <= low {
+		// A single traded price still gets a band to live in
+		high = low * 1.001
+	}
+
+	size := (high - low) / float64(buckets)
+	volumes := make([]float64, buckets)
+	index := func(price float64) int {
+		return min(max(int((price-low)/size), 0), buckets-1)
+	}
+
+	total := 0.0
+	for _, bar := range bars {
+		if bar.Volume <= 0 || bar.Low <= 0 {
+			continue
+		}
+		volume := float64(bar.Volume)
+		total += volume
+
+		barLow, barHigh := bar.Low, math.Max(bar.High, bar.Low)
+		if barHigh == barLow {
+			volumes[index(barLow)] += volume
+			continue
+		}
+		for i := index(barLow); i <= index(barHigh); i++ {
+			bucketLow := low + float64(i)*size
+			overlap := math.Min(barHigh, bucketLow+size) - math.Max(barLow, bucketLow)
+			if overlap > 0 {
+				volumes[i] += volume * overlap / (barHigh - barLow)
+			}
+		}
+	}
+
+	profile := &models.VolumeProfile{
+		BucketSize:      size,
+		TotalVolume:     total,
+		HighVolumeNodes: []float64{},
+		Buckets:         make([]*models.VolumeProfileBucket, buckets),
+	}
+
+	poc := 0
+	for i, volume := range volumes {
+		bucketLow := low + float64(i)*size
+		profile.Buckets[i] = &models.VolumeProfileBucket{
+			PriceLow:  bucketLow,
+			PriceHigh: bucketLow + size,
+			Price:     bucketLow + size/2,
+			Volume:    volume,
+			Percent:   volume / total * 100,
+		}
+		if volume > volumes[poc] {
+			poc = i
+		}
+	}
+	profile.PointOfControl = profile.Buckets[poc].Price
+
+	// Value area: grow outward from the point of control toward the heavier side until it holds the target share
+	lo, hi := poc, poc
+	inValue := volumes[poc]
+	for inValue < total*valueAreaPercent/100 && (lo > 0 || hi < buckets-1) {
+		below, above := -1.0, -1.0
+		if lo > 0 {
+			below = volumes[lo-1]
+		}
+		if hi < buckets-1 {
+			above = volumes[hi+1]
+		}
+		if above >= below {
+			hi++
+			inValue += above
+		} else {
+			lo--
+			inValue += below
+		}
+	}
+	for i := lo; i <= hi; i++ {
+		profile.Buckets[i].InValue = true
+	}
+	profile.ValueAreaLow = profile.Buckets[lo].PriceLow
+	profile.ValueAreaHigh = profile.Buckets[hi].PriceHigh
+
+	// High-volume nodes: local peaks well above the average bucket; a plateau counts once
+	threshold := hvnRatio * total / float64(buckets)
+	var nodes []*models.VolumeProfileBucket
+	for i, volume := range volumes {
+		left, right := 0.0, 0.0
+		if i > 0 {
+			left = volumes[i-1]
+		}
+		if i < buckets-1 {
+			right = volumes[i+1]
+		}
+		if volume >= threshold && volume > left && volume >= right {
+			profile.Buckets[i].IsHVN = true
+			nodes = append(nodes, profile.Buckets[i])
+		}
+	}
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].Volume > nodes[j].Volume })
+	for _, node := range nodes {
+		profile.HighVolumeNodes = append(profile.HighVolumeNodes, node.Price)
+	}
+
+	return profile
+}
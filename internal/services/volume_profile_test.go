@@ -0,0 +1,54 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"market-watch-go/internal/models"
+)
+
+// TestBuildVolumeProfile tests volume spreading, the point of control, value area and high-volume nodes
+func TestBuildVolumeProfile(t *testing.T) {
+	var bars []*models.PriceData
+	for i := 0; i < 10; i++ {
+		bars = append(bars, &models.PriceData{Low: 100, High: 101, Close: 100.5, Volume: 1000})
+	}
+	for i := 0; i < 5; i++ {
+		bars = append(bars, &models.PriceData{Low: 105, High: 106, Close: 105.5, Volume: 800})
+	}
+	// A wide bar spreads thinly across every bucket
+	bars = append(bars, &models.PriceData{Low: 100, High: 106, Close: 103, Volume: 600})
+
+	profile := buildVolumeProfile(bars, 12, 70, 1.5)
+	if profile == nil {
+		t.Fatal("expected a profile")
+	}
+	if profile.TotalVolume != 14600 || math.Abs(profile.BucketSize-0.5) > 1e-9 {
+		t.Errorf("unexpected total %.0f or bucket size %.3f", profile.TotalVolume, profile.BucketSize)
+	}
+	if math.Abs(profile.Buckets[0].Volume-5050) > 1e-6 || math.Abs(profile.Buckets[5].Volume-50) > 1e-6 {
+		t.Errorf("unexpected bucket volumes: %.1f and %.1f", profile.Buckets[0].Volume, profile.Buckets[5].Volume)
+	}
+	if profile.PointOfControl != 100.25 {
+		t.Errorf("expected the point of control at 100.25, got %.2f", profile.PointOfControl)
+	}
+	if profile.ValueAreaLow != 100 || profile.ValueAreaHigh <= 101 || profile.ValueAreaHigh >= 105 {
+		t.Errorf("unexpected value area %.2f-%.2f", profile.ValueAreaLow, profile.ValueAreaHigh)
+	}
+	// The two-bucket plateau at the point of control counts as a single node
+	if len(profile.HighVolumeNodes) != 2 || profile.HighVolumeNodes[0] != 100.25 || profile.HighVolumeNodes[1] != 105.25 {
+		t.Errorf("unexpected high-volume nodes: %v", profile.HighVolumeNodes)
+	}
+
+	if buildVolumeProfile([]*models.PriceData{{Low: 10, High: 11}}, 10, 70, 1.5) != nil {
+		t.Error("expected no profile without volume")
+	}
+
+	// Zones at a high-volume node pick it up as confluence
+	service := NewSupportResistanceService(nil, nil)
+	levels := []*models.SupportResistanceLevel{{Symbol: "TEST", Level: 105.3, LevelType: "resistance", Strength: 50, Touches: 3}}
+	_, resistance := service.buildZones(levels, &models.SRConfluenceRefs{PointOfControl: profile.PointOfControl, HighVolumeNodes: profile.HighVolumeNodes})
+	if len(resistance) != 1 || !resistance[0].HasConfluence(models.ConfluenceHVN) || resistance[0].HasConfluence(models.ConfluencePOC) {
+		t.Errorf("expected high-volume node confluence only, got %v", resistance[0].Confluence)
+	}
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// Bulk watchlist operation limits
+const (
+	MaxBulkSymbols          = 500
+	DefaultBulkBackfillDays = 30
+	maxBulkBackfillDays     = 365
+	bulkBackfillDisabled    = -1
+)
+
+// WatchlistBulkService adds, removes and moves several watchlist symbols in one call. Each symbol is applied
+// in its own transaction, so a bad symbol is reported without failing the others.
+type WatchlistBulkService struct {
+	db     *database.Database
+	search *SymbolSearchService
+}
+
+// NewWatchlistBulkService creates a new bulk watchlist service
+func NewWatchlistBulkService(db *database.Database) *WatchlistBulkService {
+	return &WatchlistBulkService{db: db}
+}
+
+// SetSymbolSearch sets the service used to check symbols before they are added
+func (ws *WatchlistBulkService) SetSymbolSearch(search *SymbolSearchService) {
+	ws.search = search
+}
+
+// BackfillDays returns the days of history to backfill for a bulk add, 0 when none
+func BackfillDays(req *models.WatchlistBulkAddRequest) int {
+	switch {
+	case req.BackfillDays == bulkBackfillDisabled:
+		return 0
+	case req.BackfillDays <= 0:
+		return DefaultBulkBackfillDays
+	default:
+		return req.BackfillDays
+	}
+}
+
+// Add adds the symbols to the given strategies and starts collecting data for the new ones, which are
+// returned so their history can be backfilled. Symbols already on the watchlist get the strategies and tags
+// merged in.
+func (ws *WatchlistBulkService) Add(req *models.WatchlistBulkAddRequest) (*models.WatchlistBulkResult, []string, error) {
+	if err := validateBulkSymbols(req.Symbols); err != nil {
+		return nil, nil, err
+	}
+	if len(req.StrategyIDs) == 0 {
+		return nil, nil, fmt.Errorf("%w: at least one strategy ID must be provided", ErrValidation)
+	}
+	for _, id := range req.StrategyIDs {
+		if err := ws.checkStrategy(id); err != nil {
+			return nil, nil, err
+		}
+	}
+	if req.BackfillDays < bulkBackfillDisabled || req.BackfillDays > maxBulkBackfillDays {
+		return nil, nil, fmt.Errorf("%w: backfill_days must be between 1 and %d, or -1 to skip the backfill", ErrValidation, maxBulkBackfillDays)
+	}
+	tags, err := models.NormalizeTags(req.Tags)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	strategies := make([]models.Strategy, 0, len(req.StrategyIDs))
+	for _, id := range req.StrategyIDs {
+		strategies = append(strategies, models.Strategy{ID: id})
+	}
+
+	var added []string
+	result := ws.apply(req.Symbols, func(symbol string) (string, error) {
+		name, err := ws.checkSymbol(symbol)
+		if err != nil {
+			return "", err
+		}
+
+		status := models.BulkStatusUpdated
+		err = ws.db.WithTx(func(tx *database.DB) error {
+			existing, err := tx.GetStockBySymbol(symbol)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+			if _, err := tx.AddStock(models.Stock{Symbol: symbol, Name: name, Strategies: strategies, Tags: tags}); err != nil {
+				return err
+			}
+			if existing != nil {
+				return nil
+			}
+
+			// Start collecting data for the new symbol
+			status = models.BulkStatusAdded
+			return tx.AddWatchedSymbol(symbol, name)
+		})
+		if err != nil {
+			return "", err
+		}
+		if status == models.BulkStatusAdded {
+			added = append(added, symbol)
+		}
+		return status, nil
+	})
+
+	return result, added, nil
+}
+
+// Remove deletes the symbols from the watchlist or, with a strategy, from that strategy only, deleting
+// stocks left without one. With unwatch, data collection stops for the deleted stocks.
+func (ws *WatchlistBulkService) Remove(req *models.WatchlistBulkRemoveRequest) (*models.WatchlistBulkResult, error) {
+	if err := validateBulkSymbols(req.Symbols); err != nil {
+		return nil, err
+	}
+
+	return ws.apply(req.Symbols, func(symbol string) (string, error) {
+		stock, err := ws.stock(symbol)
+		if err != nil {
+			return "", err
+		}
+
+		err = ws.db.WithTx(func(tx *database.DB) error {
+			if req.StrategyID != 0 {
+				if !inStrategy(stock, req.StrategyID) {
+					return fmt.Errorf("not in strategy %d", req.StrategyID)
+				}
+				if err := tx.RemoveStockFromStrategy(stock.ID, req.StrategyID); err != nil {
+					return err
+				}
+				if len(stock.Strategies) > 1 {
+					return nil
+				}
+			}
+
+			if err := tx.DeleteStock(stock.ID); err != nil {
+				return err
+			}
+			if req.Unwatch {
+				if err := tx.RemoveWatchedSymbol(symbol); err != nil && !errors.Is(err, database.ErrNotFound) {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+		return models.BulkStatusRemoved, nil
+	}), nil
+}
+
+// Move moves the symbols out of one strategy, or all of their strategies, into another
+func (ws *WatchlistBulkService) Move(req *models.WatchlistBulkMoveRequest) (*models.WatchlistBulkResult, error) {
+	if err := validateBulkSymbols(req.Symbols); err != nil {
+		return nil, err
+	}
+	if req.ToStrategyID == 0 {
+		return nil, fmt.Errorf("%w: to_strategy_id is required", ErrValidation)
+	}
+	if err := ws.checkStrategy(req.ToStrategyID); err != nil {
+		return nil, err
+	}
+
+	return ws.apply(req.Symbols, func(symbol string) (string, error) {
+		stock, err := ws.stock(symbol)
+		if err != nil {
+			return "", err
+		}
+		if req.FromStrategyID != 0 && !inStrategy(stock, req.FromStrategyID) {
+			return "", fmt.Errorf("not in strategy %d", req.FromStrategyID)
+		}
+
+		err = ws.db.WithTx(func(tx *database.DB) error {
+			if req.FromStrategyID != 0 {
+				if err := tx.RemoveStockFromStrategy(stock.ID, req.FromStrategyID); err != nil {
+					return err
+				}
+			} else if err := tx.RemoveAllStockStrategies(stock.ID); err != nil {
+				return err
+			}
+
+			// A stock already in the target strategy keeps that association
+			if req.FromStrategyID != 0 && req.FromStrategyID != req.ToStrategyID && inStrategy(stock, req.ToStrategyID) {
+				return nil
+			}
+			return tx.AddStockToStrategy(stock.ID, req.ToStrategyID)
+		})
+		if err != nil {
+			return "", err
+		}
+		return models.BulkStatusMoved, nil
+	}), nil
+}
+
+// apply runs op for each distinct symbol, upper-cased and validated, and reports the outcome per symbol
+func (ws *WatchlistBulkService) apply(symbols []string, op func(symbol string) (string, error)) *models.WatchlistBulkResult {
+	result := &models.WatchlistBulkResult{Results: make([]models.WatchlistBulkItem, 0, len(symbols))}
+	seen := make(map[string]bool, len(symbols))
+
+	for _, raw := range symbols {
+		item := models.WatchlistBulkItem{Symbol: raw}
+		symbol, err := models.ValidateSymbol(raw)
+		if err == nil {
+			item.Symbol = symbol
+			if seen[symbol] {
+				continue
+			}
+			seen[symbol] = true
+			item.Status, err = op(symbol)
+		}
+
+		if err != nil {
+			item.Status = models.BulkStatusFailed
+			item.Error = err.Error()
+			result.Failed++
+		} else {
+			result.Succeeded++
+		}
+		result.Results = append(result.Results, item)
+	}
+
+	return result
+}
+
+// checkSymbol checks a symbol against the reference tickers when symbol search is enabled and returns its
+// company name; when the provider can't be reached the symbol is added unchecked
+func (ws *WatchlistBulkService) checkSymbol(symbol string) (string, error) {
+	if !ws.search.IsEnabled() {
+		return "", nil
+	}
+
+	match, err := ws.search.CheckSymbol(symbol)
+	if errors.Is(err, ErrValidation) {
+		return "", err
+	}
+	if err != nil {
+		log.Printf("Could not check %s against the reference tickers, adding it unchecked: %v", symbol, err)
+		return "", nil
+	}
+	return match.Name, nil
+}
+
+// checkStrategy fails with ErrValidation unless the strategy exists
+func (ws *WatchlistBulkService) checkStrategy(id int) error {
+	exists, err := ws.db.StrategyExists(id)
+	if err != nil {
+		return fmt.Errorf("failed to check strategy %d: %w", id, err)
+	}
+	if !exists {
+		return fmt.Errorf("%w: strategy with ID %d does not exist", ErrValidation, id)
+	}
+	return nil
+}
+
+// stock returns the watchlist stock of a symbol with its strategies
+func (ws *WatchlistBulkService) stock(symbol string) (*models.Stock, error) {
+	stock, err := ws.db.GetStockBySymbol(symbol)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && stock == nil) {
+		return nil, errors.New("not on the watchlist")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return stock, nil
+}
+
+// validateBulkSymbols checks the number of symbols in a bulk request
+func validateBulkSymbols(symbols []string) error {
+	if len(symbols) == 0 {
+		return fmt.Errorf("%w: at least one symbol is required", ErrValidation)
+	}
+	if len(symbols) > MaxBulkSymbols {
+		return fmt.Errorf("%w: at most %d symbols can be changed at once", ErrValidation, MaxBulkSymbols)
+	}
+	return nil
+}
+
+// inStrategy reports whether a stock belongs to a strategy
+func inStrategy(stock *models.Stock, strategyID int) bool {
+	for _, strategy := range stock.Strategies {
+		if strategy.ID == strategyID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"market-watch-go/internal/models"
+)
+
+// TestWatchlistBulkOperations tests per-symbol outcomes of bulk adds, moves and removes
+func TestWatchlistBulkOperations(t *testing.T) {
+	db := newTestDB(t)
+
+	tech, err := db.CreateStrategy(models.Strategy{Name: "Tech"})
+	if err != nil {
+		t.Fatalf("CreateStrategy failed: %v", err)
+	}
+	swing, err := db.CreateStrategy(models.Strategy{Name: "Swing"})
+	if err != nil {
+		t.Fatalf("CreateStrategy failed: %v", err)
+	}
+	if _, err := db.AddStock(models.Stock{Symbol: "AAPL", Strategies: []models.Strategy{{ID: swing.ID}}}); err != nil {
+		t.Fatalf("AddStock failed: %v", err)
+	}
+
+	service := NewWatchlistBulkService(db)
+	result, added, err := service.Add(&models.WatchlistBulkAddRequest{
+		Symbols:     []string{"aapl", "msft", "BAD/SYMBOL", "MSFT", "x:btcusd"},
+		StrategyIDs: []int{tech.ID},
+	})
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if result.Succeeded != 3 || result.Failed != 1 || len(result.Results) != 4 {
+		t.Fatalf("expected 3 symbols added or updated and 1 failure, got %+v", result)
+	}
+	if result.Results[0].Status != models.BulkStatusUpdated || result.Results[2].Status != models.BulkStatusFailed || result.Results[2].Error == "" {
+		t.Errorf("expected AAPL to be updated and the bad symbol to fail, got %+v", result.Results)
+	}
+	if len(added) != 2 || added[0] != "MSFT" || added[1] != "X:BTCUSD" {
+		t.Errorf("expected MSFT and X:BTCUSD to be newly added, got %v", added)
+	}
+	watched, _ := db.GetWatchedSymbols()
+	if len(watched) != 2 {
+		t.Errorf("expected the new symbols to be watched, got %v", watched)
+	}
+
+	if _, _, err := service.Add(&models.WatchlistBulkAddRequest{Symbols: []string{"NVDA"}, StrategyIDs: []int{999}}); !errors.Is(err, ErrValidation) {
+		t.Errorf("expected an unknown strategy to fail validation, got %v", err)
+	}
+
+	// AAPL leaves Swing for Tech, which it is already in; NVDA isn't on the watchlist
+	result, err = service.Move(&models.WatchlistBulkMoveRequest{Symbols: []string{"AAPL", "NVDA"}, FromStrategyID: swing.ID, ToStrategyID: tech.ID})
+	if err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if result.Succeeded != 1 || result.Results[1].Error != "not on the watchlist" {
+		t.Errorf("expected AAPL to move and NVDA to fail, got %+v", result.Results)
+	}
+	aapl, _ := db.GetStockBySymbol("AAPL")
+	if len(aapl.Strategies) != 1 || aapl.Strategies[0].ID != tech.ID {
+		t.Errorf("expected AAPL only in Tech, got %+v", aapl.Strategies)
+	}
+
+	result, err = service.Remove(&models.WatchlistBulkRemoveRequest{Symbols: []string{"MSFT", "X:BTCUSD"}, StrategyID: tech.ID, Unwatch: true})
+	if err != nil || result.Succeeded != 2 {
+		t.Fatalf("expected both symbols to be removed, got %+v (%v)", result, err)
+	}
+	if exists, _ := db.StockExists("MSFT"); exists {
+		t.Errorf("expected MSFT, left without a strategy, to be deleted")
+	}
+	if watched, _ := db.GetWatchedSymbols(); len(watched) != 0 {
+		t.Errorf("expected the removed symbols to be unwatched, got %v", watched)
+	}
+}
@@ -0,0 +1,529 @@
+package services
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+const (
+	defaultImportStrategy = "Imported"
+	defaultStrategyColor  = "#007bff"
+)
+
+// Stock and ETF tickers as the market data providers spell them, e.g. BRK.B
+var watchlistSymbolPattern = regexp.MustCompile(`^[A-Z][A-Z0-9.\-]{0,9}$`)
+
+// WatchlistTransferService imports and exports the watchlist as CSV or broker watchlist files
+type WatchlistTransferService struct {
+	db *database.Database
+}
+
+// NewWatchlistTransferService creates a new watchlist import/export service
+func NewWatchlistTransferService(db *database.Database) *WatchlistTransferService {
+	return &WatchlistTransferService{db: db}
+}
+
+// ValidateWatchlistFormat checks a watchlist file format, defaulting to CSV
+func ValidateWatchlistFormat(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "", models.WatchlistFormatCSV:
+		return models.WatchlistFormatCSV, nil
+	case models.WatchlistFormatTradingView, "tv":
+		return models.WatchlistFormatTradingView, nil
+	case models.WatchlistFormatThinkorswim, "tos":
+		return models.WatchlistFormatThinkorswim, nil
+	default:
+		return "", fmt.Errorf("unsupported watchlist format: %s (use csv, tradingview or thinkorswim)", format)
+	}
+}
+
+// Export writes the watchlist, or a single strategy of it, to w and returns the number of symbols written
+func (ws *WatchlistTransferService) Export(w io.Writer, format, strategy string) (int, error) {
+	format, err := ValidateWatchlistFormat(format)
+	if err != nil {
+		return 0, err
+	}
+
+	stocks, err := ws.db.GetStocks()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get watchlist stocks: %w", err)
+	}
+	if strategy != "" {
+		stocks = stocksInStrategy(stocks, strategy)
+	}
+
+	switch format {
+	case models.WatchlistFormatTradingView:
+		err = writeTradingViewWatchlist(w, stocks)
+	case models.WatchlistFormatThinkorswim:
+		err = writeThinkorswimWatchlist(w, stocks)
+	default:
+		err = writeCSVWatchlist(w, stocks)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to write watchlist: %w", err)
+	}
+
+	return len(stocks), nil
+}
+
+// Import reads a watchlist file and adds its symbols to the mapped strategies, creating strategies
+// that don't exist yet. The whole file is applied in one transaction.
+func (ws *WatchlistTransferService) Import(r io.Reader, opts *models.WatchlistImportOptions) (*models.WatchlistImportResult, error) {
+	format, err := ValidateWatchlistFormat(opts.Format)
+	if err != nil {
+		return nil, err
+	}
+	duplicates := opts.Duplicates
+	switch duplicates {
+	case "":
+		duplicates = models.DuplicateMerge
+	case models.DuplicateMerge, models.DuplicateSkip, models.DuplicateReplace:
+	default:
+		return nil, fmt.Errorf("unsupported duplicates mode: %s (use merge, skip or replace)", duplicates)
+	}
+	defaultStrategy := strings.TrimSpace(opts.Strategy)
+	if defaultStrategy == "" {
+		defaultStrategy = defaultImportStrategy
+	}
+
+	entries, rows, errs, err := parseWatchlist(r, format, defaultStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.WatchlistImportResult{
+		Format:            format,
+		Rows:              rows,
+		StrategiesCreated: []string{},
+		Errors:            errs,
+	}
+	entries, result.DuplicateRows = mergeWatchlistEntries(entries)
+
+	err = ws.db.WithTx(func(tx *database.DB) error {
+		strategyIDs, err := ws.strategyIDs(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			ids, err := ws.resolveStrategies(tx, entry.Strategies, strategyIDs, result)
+			if err != nil {
+				return err
+			}
+			if err := ws.importEntry(tx, entry, ids, duplicates, result); err != nil {
+				return fmt.Errorf("failed to import %s: %w", entry.Symbol, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// strategyIDs maps lowercase strategy names to their IDs
+func (ws *WatchlistTransferService) strategyIDs(tx *database.DB) (map[string]int, error) {
+	strategies, err := tx.GetStrategies()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get strategies: %w", err)
+	}
+
+	ids := make(map[string]int, len(strategies))
+	for _, strategy := range strategies {
+		ids[strings.ToLower(strategy.Name)] = strategy.ID
+	}
+	return ids, nil
+}
+
+// resolveStrategies returns the IDs of the named strategies, creating the missing ones
+func (ws *WatchlistTransferService) resolveStrategies(tx *database.DB, names []string, known map[string]int, result *models.WatchlistImportResult) ([]int, error) {
+	ids := make([]int, 0, len(names))
+	for _, name := range names {
+		if id, ok := known[strings.ToLower(name)]; ok {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+			continue
+		}
+
+		created, err := tx.CreateStrategy(models.Strategy{Name: name, Color: defaultStrategyColor})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create strategy %s: %w", name, err)
+		}
+		known[strings.ToLower(name)] = created.ID
+		result.StrategiesCreated = append(result.StrategiesCreated, name)
+		ids = append(ids, created.ID)
+	}
+	return ids, nil
+}
+
+// importEntry adds a new stock or applies the duplicates mode to an existing one
+func (ws *WatchlistTransferService) importEntry(tx *database.DB, entry *models.WatchlistImportEntry, strategyIDs []int, duplicates string, result *models.WatchlistImportResult) error {
+	existing, err := tx.GetStockBySymbol(entry.Symbol)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if existing == nil {
+		stock := models.Stock{Symbol: entry.Symbol, Name: entry.Name, Notes: entry.Notes}
+		for _, id := range strategyIDs {
+			stock.Strategies = append(stock.Strategies, models.Strategy{ID: id})
+		}
+		if _, err := tx.AddStock(stock); err != nil {
+			return err
+		}
+		// Start collecting data for the new symbol
+		if err := tx.AddWatchedSymbol(entry.Symbol, entry.Name); err != nil {
+			return err
+		}
+		result.Added++
+		return nil
+	}
+
+	if duplicates == models.DuplicateSkip {
+		result.Skipped++
+		return nil
+	}
+
+	current := make(map[int]bool, len(existing.Strategies))
+	for _, strategy := range existing.Strategies {
+		current[strategy.ID] = true
+	}
+
+	changed := false
+	if duplicates == models.DuplicateReplace {
+		if err := tx.RemoveAllStockStrategies(existing.ID); err != nil {
+			return err
+		}
+		current = map[int]bool{}
+		changed = true
+	}
+	for _, id := range strategyIDs {
+		if current[id] {
+			continue
+		}
+		if err := tx.AddStockToStrategy(existing.ID, id); err != nil {
+			return err
+		}
+		current[id] = true
+		changed = true
+	}
+
+	// Merging only fills empty notes; replacing overwrites them when the file has notes
+	if entry.Notes != "" && entry.Notes != existing.Notes && (duplicates == models.DuplicateReplace || existing.Notes == "") {
+		if err := tx.UpdateStockNotes(existing.ID, entry.Notes); err != nil {
+			return err
+		}
+		changed = true
+	}
+
+	if changed {
+		result.Updated++
+	} else {
+		result.Skipped++
+	}
+	return nil
+}
+
+// parseWatchlist reads the entries of a watchlist file. Rows with symbols that can't be imported are
+// reported in errs rather than failing the whole file.
+func parseWatchlist(r io.Reader, format, defaultStrategy string) (entries []*models.WatchlistImportEntry, rows int, errs []string, err error) {
+	add := func(raw, name, notes string, strategies []string) {
+		rows++
+		symbol, ok := normalizeWatchlistSymbol(raw)
+		if !ok {
+			errs = append(errs, fmt.Sprintf("row %d: unsupported symbol %q", rows, raw))
+			return
+		}
+		if len(strategies) == 0 {
+			strategies = []string{defaultStrategy}
+		}
+		entries = append(entries, &models.WatchlistImportEntry{Symbol: symbol, Name: name, Notes: notes, Strategies: strategies})
+	}
+
+	switch format {
+	case models.WatchlistFormatTradingView:
+		err = parseTradingViewWatchlist(r, add)
+	case models.WatchlistFormatThinkorswim:
+		err = parseThinkorswimWatchlist(r, defaultStrategy, add)
+	default:
+		err = parseCSVWatchlist(r, add)
+	}
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	return entries, rows, errs, nil
+}
+
+// parseCSVWatchlist reads a CSV with a header row naming at least a symbol column
+func parseCSVWatchlist(r io.Reader, add func(symbol, name, notes string, strategies []string)) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "symbol", "ticker":
+			columns["symbol"] = i
+		case "name", "description":
+			columns["name"] = i
+		case "strategies", "strategy", "categories", "category", "tags":
+			columns["strategies"] = i
+		case "notes", "note":
+			columns["notes"] = i
+		}
+	}
+	if _, ok := columns["symbol"]; !ok {
+		return fmt.Errorf("CSV header must include a symbol column")
+	}
+
+	cell := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read CSV row: %w", err)
+		}
+		if cell(record, "symbol") == "" {
+			continue
+		}
+		add(cell(record, "symbol"), cell(record, "name"), cell(record, "notes"), splitStrategies(cell(record, "strategies")))
+	}
+}
+
+// parseTradingViewWatchlist reads a TradingView export: symbols separated by commas or newlines, optionally
+// with exchange prefixes, and ###Name markers starting a section that maps to a strategy
+func parseTradingViewWatchlist(r io.Reader, add func(symbol, name, notes string, strategies []string)) error {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read watchlist: %w", err)
+	}
+
+	var section []string
+	for _, token := range strings.FieldsFunc(string(content), func(c rune) bool { return c == ',' || c == '\n' || c == '\r' }) {
+		token = strings.TrimSpace(token)
+		switch {
+		case token == "":
+		case strings.HasPrefix(token, "###"):
+			section = nil
+			if name := strings.TrimSpace(strings.TrimPrefix(token, "###")); name != "" {
+				section = []string{name}
+			}
+		default:
+			add(token, "", "", section)
+		}
+	}
+	return nil
+}
+
+// parseThinkorswimWatchlist reads a thinkorswim watchlist: one symbol per line, or an exported CSV whose
+// symbols are in the first column below a Symbol header. The whole file maps to one strategy.
+func parseThinkorswimWatchlist(r io.Reader, strategy string, add func(symbol, name, notes string, strategies []string)) error {
+	var lines [][]string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		record, err := csv.NewReader(strings.NewReader(line)).Read()
+		if err != nil {
+			record = []string{line}
+		}
+		lines = append(lines, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read watchlist: %w", err)
+	}
+
+	// Exports start with a title line before the column header
+	start := 0
+	for i, record := range lines {
+		if strings.EqualFold(strings.TrimSpace(record[0]), "symbol") {
+			start = i + 1
+			break
+		}
+	}
+
+	for _, record := range lines[start:] {
+		add(record[0], "", "", []string{strategy})
+	}
+	return nil
+}
+
+// normalizeWatchlistSymbol strips exchange prefixes and converts broker share class notation
+// (BRK/B) to the providers' form (BRK.B). Futures, options and indexes are rejected.
+func normalizeWatchlistSymbol(raw string) (string, bool) {
+	symbol := strings.ToUpper(strings.Trim(strings.TrimSpace(raw), `"'`))
+	if i := strings.LastIndex(symbol, ":"); i >= 0 {
+		symbol = symbol[i+1:]
+	}
+	if strings.Count(symbol, "/") == 1 && !strings.HasPrefix(symbol, "/") {
+		symbol = strings.Replace(symbol, "/", ".", 1)
+	}
+	return symbol, watchlistSymbolPattern.MatchString(symbol)
+}
+
+// splitStrategies splits a strategies cell on semicolons, pipes or commas
+func splitStrategies(cell string) []string {
+	var names []string
+	for _, name := range strings.FieldsFunc(cell, func(c rune) bool { return c == ';' || c == '|' || c == ',' }) {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// mergeWatchlistEntries folds rows repeating a symbol into its first row and returns the number folded
+func mergeWatchlistEntries(entries []*models.WatchlistImportEntry) ([]*models.WatchlistImportEntry, int) {
+	bySymbol := make(map[string]*models.WatchlistImportEntry, len(entries))
+	merged := make([]*models.WatchlistImportEntry, 0, len(entries))
+	duplicates := 0
+
+	for _, entry := range entries {
+		first, ok := bySymbol[entry.Symbol]
+		if !ok {
+			bySymbol[entry.Symbol] = entry
+			merged = append(merged, entry)
+			continue
+		}
+
+		duplicates++
+		for _, strategy := range entry.Strategies {
+			if !containsFold(first.Strategies, strategy) {
+				first.Strategies = append(first.Strategies, strategy)
+			}
+		}
+		if first.Name == "" {
+			first.Name = entry.Name
+		}
+		if first.Notes == "" {
+			first.Notes = entry.Notes
+		}
+	}
+
+	return merged, duplicates
+}
+
+// containsFold reports whether values contains s, ignoring case
+func containsFold(values []string, s string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// stocksInStrategy keeps the stocks assigned to the named strategy
+func stocksInStrategy(stocks []models.Stock, strategy string) []models.Stock {
+	var filtered []models.Stock
+	for _, stock := range stocks {
+		for _, s := range stock.Strategies {
+			if strings.EqualFold(s.Name, strategy) {
+				filtered = append(filtered, stock)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// writeCSVWatchlist writes symbol,name,strategies,notes rows with strategies joined by semicolons
+func writeCSVWatchlist(w io.Writer, stocks []models.Stock) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"symbol", "name", "strategies", "notes"}); err != nil {
+		return err
+	}
+	for _, stock := range stocks {
+		names := make([]string, 0, len(stock.Strategies))
+		for _, strategy := range stock.Strategies {
+			names = append(names, strategy.Name)
+		}
+		if err := writer.Write([]string{stock.Symbol, stock.Name, strings.Join(names, ";"), stock.Notes}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeTradingViewWatchlist writes one ###Strategy section per line. TradingView keeps a symbol in a
+// single section, so stocks in several strategies are listed under the first one.
+func writeTradingViewWatchlist(w io.Writer, stocks []models.Stock) error {
+	var unassigned []string
+	var order []string
+	sections := map[string][]string{}
+	for _, stock := range stocks {
+		if len(stock.Strategies) == 0 {
+			unassigned = append(unassigned, stock.Symbol)
+			continue
+		}
+		name := stock.Strategies[0].Name
+		if _, ok := sections[name]; !ok {
+			order = append(order, name)
+		}
+		sections[name] = append(sections[name], stock.Symbol)
+	}
+
+	if len(unassigned) > 0 {
+		if _, err := fmt.Fprintln(w, strings.Join(unassigned, ",")); err != nil {
+			return err
+		}
+	}
+	for _, name := range order {
+		// Commas would split the section name on import
+		line := "###" + strings.ReplaceAll(name, ",", " ") + "," + strings.Join(sections[name], ",")
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeThinkorswimWatchlist writes one symbol per line under a Symbol header, the layout thinkorswim imports
+func writeThinkorswimWatchlist(w io.Writer, stocks []models.Stock) error {
+	if _, err := fmt.Fprintln(w, "Symbol"); err != nil {
+		return err
+	}
+	for _, stock := range stocks {
+		// thinkorswim writes share classes with a slash
+		if _, err := fmt.Fprintln(w, strings.Replace(stock.Symbol, ".", "/", 1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
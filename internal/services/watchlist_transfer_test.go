@@ -0,0 +1,108 @@
+package services
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"market-watch-go/internal/models"
+)
+
+// TestParseWatchlistFormats tests symbol normalization and strategy mapping for each supported format
+func TestParseWatchlistFormats(t *testing.T) {
+	tests := []struct {
+		format  string
+		content string
+		want    map[string]string // symbol -> strategies joined by ';'
+		errors  int
+	}{
+		{
+			format:  models.WatchlistFormatCSV,
+			content: "Ticker,Description,Category,Notes\naapl,Apple,Tech;Core,long term\nBRK.B,Berkshire,,\n",
+			want:    map[string]string{"AAPL": "Tech;Core", "BRK.B": "Imported"},
+		},
+		{
+			format:  models.WatchlistFormatTradingView,
+			content: "SPY,###Tech,NASDAQ:AAPL,NASDAQ:MSFT\n###Energy,NYSE:XOM,CME_MINI:ES1!",
+			want:    map[string]string{"SPY": "Imported", "AAPL": "Tech", "MSFT": "Tech", "XOM": "Energy"},
+			errors:  1,
+		},
+		{
+			format:  models.WatchlistFormatThinkorswim,
+			content: "Watchlist 'Swing' as of 6/2/25\n\nSymbol,Last,Net Chng\nTSLA,180.5,+2.1\nBRK/B,410,-1\n/ES,5300,+4\n",
+			want:    map[string]string{"TSLA": "Imported", "BRK.B": "Imported"},
+			errors:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		entries, _, errs, err := parseWatchlist(strings.NewReader(tt.content), tt.format, defaultImportStrategy)
+		if err != nil {
+			t.Fatalf("%s: parseWatchlist failed: %v", tt.format, err)
+		}
+		if len(errs) != tt.errors {
+			t.Errorf("%s: expected %d errors, got %v", tt.format, tt.errors, errs)
+		}
+		got := map[string]string{}
+		for _, entry := range entries {
+			got[entry.Symbol] = strings.Join(entry.Strategies, ";")
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.format, tt.want, got)
+		}
+		for symbol, strategies := range tt.want {
+			if got[symbol] != strategies {
+				t.Errorf("%s: expected %s in %q, got %q", tt.format, symbol, strategies, got[symbol])
+			}
+		}
+	}
+}
+
+// TestWatchlistImportDuplicates tests strategy creation, in-file duplicates and the merge and skip modes
+func TestWatchlistImportDuplicates(t *testing.T) {
+	db := newTestDB(t)
+
+	service := NewWatchlistTransferService(db)
+	first := "symbol,strategies,notes\nAAPL,Tech,\nMSFT,Tech,\naapl,Core,breakout watch\n"
+	result, err := service.Import(strings.NewReader(first), &models.WatchlistImportOptions{})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if result.Added != 2 || result.DuplicateRows != 1 || len(result.StrategiesCreated) != 2 {
+		t.Errorf("unexpected first import: %+v", result)
+	}
+
+	// Merging adds the new strategy to AAPL; skipping leaves MSFT as it is
+	second := "###Swing,NASDAQ:AAPL,NVDA"
+	result, err = service.Import(strings.NewReader(second), &models.WatchlistImportOptions{Format: models.WatchlistFormatTradingView})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if result.Added != 1 || result.Updated != 1 {
+		t.Errorf("unexpected merge import: %+v", result)
+	}
+	result, err = service.Import(strings.NewReader("MSFT\n"), &models.WatchlistImportOptions{Format: models.WatchlistFormatThinkorswim, Strategy: "Swing", Duplicates: models.DuplicateSkip})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if result.Skipped != 1 || result.Updated != 0 {
+		t.Errorf("unexpected skip import: %+v", result)
+	}
+
+	aapl, err := db.GetStockBySymbol("AAPL")
+	if err != nil {
+		t.Fatalf("GetStockBySymbol failed: %v", err)
+	}
+	if len(aapl.Strategies) != 3 || aapl.Notes != "breakout watch" {
+		t.Errorf("expected AAPL in 3 strategies with notes, got %d strategies and %q", len(aapl.Strategies), aapl.Notes)
+	}
+
+	var buf bytes.Buffer
+	count, err := service.Export(&buf, models.WatchlistFormatCSV, "swing")
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if count != 2 || !strings.Contains(buf.String(), "NVDA,,Swing,") {
+		t.Errorf("unexpected Swing export (%d symbols):\n%s", count, buf.String())
+	}
+}
@@ -0,0 +1,321 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/database"
+	"market-watch-go/internal/models"
+)
+
+// Headers of webhook deliveries. The signature is "sha256=" and the hex HMAC-SHA256, keyed with the
+// endpoint's secret, of the timestamp header, a dot and the body, so receivers can reject replayed requests.
+const (
+	WebhookSignatureHeader = "X-Market-Watch-Signature"
+	WebhookTimestampHeader = "X-Market-Watch-Timestamp" // Unix seconds
+	WebhookEventHeader     = "X-Market-Watch-Event"
+	WebhookDeliveryHeader  = "X-Market-Watch-Delivery" // event ID, the same on every retry
+)
+
+// errWebhookRejected marks a delivery the endpoint refused in a way retrying won't fix
+var errWebhookRejected = errors.New("rejected by endpoint")
+
+// WebhookService posts signed pattern and setup lifecycle events to the configured endpoints, so external
+// automation can react without polling. Failed deliveries are retried with exponential backoff, then recorded
+// as dead letters that can be inspected and resent.
+type WebhookService struct {
+	config      *config.WebhooksConfig
+	db          *database.Database
+	client      *http.Client
+	maxAttempts int
+	retryDelay  time.Duration
+	stop        chan struct{}
+	stopOnce    sync.Once
+	wg          sync.WaitGroup // tracks deliveries in progress
+}
+
+// WebhookTestResult reports whether a test event reached an endpoint
+type WebhookTestResult struct {
+	URL       string `json:"url"`
+	Delivered bool   `json:"delivered"`
+	Error     string `json:"error,omitempty"`
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(cfg *config.Config, db *database.Database) *WebhookService {
+	maxAttempts := cfg.Webhooks.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+
+	retryDelay := cfg.Webhooks.RetryDelay
+	if retryDelay <= 0 {
+		retryDelay = 2 * time.Second
+	}
+
+	timeout := cfg.Webhooks.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &WebhookService{
+		config:      &cfg.Webhooks,
+		db:          db,
+		client:      &http.Client{Timeout: timeout},
+		maxAttempts: maxAttempts,
+		retryDelay:  retryDelay,
+		stop:        make(chan struct{}),
+	}
+}
+
+// IsEnabled checks if webhooks are enabled and have an endpoint
+func (ws *WebhookService) IsEnabled() bool {
+	return ws != nil && ws.config.Enabled && len(ws.config.Endpoints) > 0
+}
+
+// Emit posts an event to every endpoint subscribed to its type in the background and returns it, or nil when
+// webhooks are disabled. Delivery failures are logged and dead-lettered rather than returned, as a webhook
+// must not fail detection.
+func (ws *WebhookService) Emit(eventType, symbol string, data interface{}) *models.WebhookEvent {
+	if !ws.IsEnabled() {
+		return nil
+	}
+
+	event, body, err := newWebhookEvent(eventType, symbol, data)
+	if err != nil {
+		log.Printf("Failed to create %s webhook event for %s: %v", eventType, symbol, err)
+		return nil
+	}
+
+	for _, endpoint := range ws.config.Endpoints {
+		if !subscribes(endpoint, eventType) {
+			continue
+		}
+
+		ws.wg.Add(1)
+		go func(endpoint config.WebhookEndpoint) {
+			defer ws.wg.Done()
+			ws.deliver(endpoint, event, body)
+		}(endpoint)
+	}
+
+	return event
+}
+
+// SendTest posts a test event to every endpoint, whatever it subscribes to, and reports which received it
+func (ws *WebhookService) SendTest() ([]WebhookTestResult, error) {
+	if !ws.IsEnabled() {
+		return nil, fmt.Errorf("webhooks %w: enable them and configure an endpoint", ErrUnavailable)
+	}
+
+	event, body, err := newWebhookEvent(models.WebhookTest, "", map[string]string{"message": "Market Watch webhook test"})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]WebhookTestResult, 0, len(ws.config.Endpoints))
+	for _, endpoint := range ws.config.Endpoints {
+		result := WebhookTestResult{URL: endpoint.URL, Delivered: true}
+		if err := ws.post(endpoint, event, body); err != nil {
+			result.Delivered = false
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Redeliver resends a dead-lettered event once to its endpoint, deleting the dead letter when it is received
+func (ws *WebhookService) Redeliver(id int64) error {
+	letter, err := ws.db.GetWebhookDeadLetterByID(id)
+	if err != nil {
+		return err
+	}
+	if letter == nil {
+		return fmt.Errorf("webhook dead letter %d %w", id, database.ErrNotFound)
+	}
+
+	var endpoint *config.WebhookEndpoint
+	for i := range ws.config.Endpoints {
+		if ws.config.Endpoints[i].URL == letter.URL {
+			endpoint = &ws.config.Endpoints[i]
+		}
+	}
+	if endpoint == nil {
+		return fmt.Errorf("%w: %s is no longer a webhook endpoint", ErrValidation, letter.URL)
+	}
+
+	event := &models.WebhookEvent{ID: letter.EventID, Type: letter.EventType}
+	if err := ws.post(*endpoint, event, []byte(letter.Payload)); err != nil {
+		return fmt.Errorf("failed to redeliver webhook event %s: %w", letter.EventID, err)
+	}
+
+	return ws.db.DeleteWebhookDeadLetter(id)
+}
+
+// Stop stops retrying and waits for deliveries in progress; events still waiting for a retry are dead-lettered
+func (ws *WebhookService) Stop(ctx context.Context) error {
+	ws.stopOnce.Do(func() { close(ws.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		ws.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for webhook deliveries: %w", ctx.Err())
+	}
+}
+
+// deliver posts an event to an endpoint, retrying with exponential backoff, and dead-letters it when every
+// attempt fails or the endpoint rejects it
+func (ws *WebhookService) deliver(endpoint config.WebhookEndpoint, event *models.WebhookEvent, body []byte) {
+	delay := ws.retryDelay
+	attempts := 0
+
+	var err error
+	for attempts < ws.maxAttempts {
+		if attempts > 0 {
+			select {
+			case <-time.After(delay):
+				delay *= 2
+			case <-ws.stop:
+				ws.deadLetter(endpoint, event, body, attempts, fmt.Errorf("shut down before retrying: %w", err))
+				return
+			}
+		}
+
+		attempts++
+		if err = ws.post(endpoint, event, body); err == nil {
+			return
+		}
+		log.Printf("Webhook %s %s to %s failed (attempt %d of %d): %v", event.Type, event.ID, endpoint.URL, attempts, ws.maxAttempts, err)
+
+		if errors.Is(err, errWebhookRejected) {
+			break
+		}
+	}
+
+	ws.deadLetter(endpoint, event, body, attempts, err)
+}
+
+// post sends one signed delivery, failing unless the endpoint answers with a 2xx status
+func (ws *WebhookService) post(endpoint config.WebhookEndpoint, event *models.WebhookEvent, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := clockNow().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "market-watch-webhooks")
+	req.Header.Set(WebhookEventHeader, event.Type)
+	req.Header.Set(WebhookDeliveryHeader, event.ID)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	if endpoint.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhook(endpoint.Secret, timestamp, body))
+	}
+
+	resp, err := ws.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+	err = fmt.Errorf("endpoint returned status %d: %s", resp.StatusCode, bytes.TrimSpace(snippet))
+
+	// Client errors other than timeouts and rate limits won't succeed on a retry
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return fmt.Errorf("%w: %w", errWebhookRejected, err)
+	}
+	return err
+}
+
+// deadLetter records an event that could not be delivered to an endpoint
+func (ws *WebhookService) deadLetter(endpoint config.WebhookEndpoint, event *models.WebhookEvent, body []byte, attempts int, cause error) {
+	log.Printf("Webhook %s %s to %s dead-lettered after %d attempts: %v", event.Type, event.ID, endpoint.URL, attempts, cause)
+
+	letter := &models.WebhookDeadLetter{
+		EventID:   event.ID,
+		EventType: event.Type,
+		Symbol:    event.Symbol,
+		URL:       endpoint.URL,
+		Payload:   string(body),
+		Attempts:  attempts,
+		FailedAt:  clockNow(),
+	}
+	if cause != nil {
+		letter.LastError = cause.Error()
+	}
+	if err := ws.db.InsertWebhookDeadLetter(letter); err != nil {
+		log.Printf("Failed to record webhook dead letter for %s: %v", event.ID, err)
+	}
+}
+
+// SignWebhook returns the signature header value of a delivery: "sha256=" and the hex HMAC-SHA256 of the
+// timestamp, a dot and the body
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newWebhookEvent creates an event with a random ID and its JSON body
+func newWebhookEvent(eventType, symbol string, data interface{}) (*models.WebhookEvent, []byte, error) {
+	id := make([]byte, 12)
+	if _, err := rand.Read(id); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate event ID: %w", err)
+	}
+
+	event := &models.WebhookEvent{
+		ID:        "evt_" + hex.EncodeToString(id),
+		Type:      eventType,
+		Symbol:    symbol,
+		Timestamp: clockNow(),
+		Data:      data,
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal webhook event: %w", err)
+	}
+	return event, body, nil
+}
+
+// subscribes reports whether an endpoint receives an event type
+func subscribes(endpoint config.WebhookEndpoint, eventType string) bool {
+	if len(endpoint.Events) == 0 {
+		return true
+	}
+	for _, event := range endpoint.Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
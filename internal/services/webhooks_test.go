@@ -0,0 +1,100 @@
+package services
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// TestWebhookDelivery tests that events are signed, only sent to subscribed endpoints, retried, dead-lettered
+// after the last attempt and can be redelivered
+func TestWebhookDelivery(t *testing.T) {
+	cfg := &config.Config{}
+	db := newTestDB(t)
+
+	var mu sync.Mutex
+	var received []*models.WebhookEvent
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(WebhookTimestampHeader), 10, 64)
+		if r.Header.Get(WebhookSignatureHeader) != SignWebhook("secret", timestamp, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var event models.WebhookEvent
+		if err := json.Unmarshal(body, &event); err != nil || r.Header.Get(WebhookDeliveryHeader) != event.ID {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		received = append(received, &event)
+		mu.Unlock()
+	}))
+	defer receiver.Close()
+
+	failures, recovered := 0, false
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !recovered {
+			failures++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer failing.Close()
+
+	cfg.Webhooks = config.WebhooksConfig{
+		Enabled: true,
+		Endpoints: []config.WebhookEndpoint{
+			{URL: receiver.URL, Secret: "secret", Events: []string{models.WebhookPatternDetected}},
+			{URL: failing.URL, Events: []string{models.WebhookSetupTriggered}},
+		},
+		MaxAttempts: 3,
+		RetryDelay:  time.Millisecond,
+	}
+	webhooks := NewWebhookService(cfg, db)
+
+	data := &models.WebhookPatternData{Family: ChartFamilyTriangle, PatternID: 7, Direction: "bullish"}
+	event := webhooks.Emit(models.WebhookPatternDetected, "TEST", data)
+	webhooks.Emit(models.WebhookSetupTriggered, "TEST", &models.WebhookSetupData{Price: 101})
+	// Stopping would cut the retries short
+	webhooks.wg.Wait()
+
+	// The signed event only went to the endpoint subscribed to it
+	if len(received) != 1 || received[0].ID != event.ID || received[0].Type != models.WebhookPatternDetected || received[0].Symbol != "TEST" {
+		t.Fatalf("expected the pattern event to be received once, got %+v", received)
+	}
+
+	// The failing endpoint was tried every time, then the event was dead-lettered
+	if failures != 3 {
+		t.Errorf("expected 3 attempts, got %d", failures)
+	}
+	letters, err := db.GetWebhookDeadLetters(10)
+	if err != nil {
+		t.Fatalf("GetWebhookDeadLetters failed: %v", err)
+	}
+	if len(letters) != 1 || letters[0].URL != failing.URL || letters[0].EventType != models.WebhookSetupTriggered || letters[0].Attempts != 3 {
+		t.Fatalf("unexpected dead letters: %+v", letters)
+	}
+
+	// Once the endpoint recovers the dead letter is resent and removed
+	recovered = true
+	if err := webhooks.Redeliver(letters[0].ID); err != nil {
+		t.Fatalf("Redeliver failed: %v", err)
+	}
+	if failures != 3 {
+		t.Errorf("expected the redelivery to succeed, got %d failures", failures)
+	}
+	if remaining, _ := db.GetWebhookDeadLetters(10); len(remaining) != 0 {
+		t.Errorf("expected the dead letter to be removed, got %+v", remaining)
+	}
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"market-watch-go/internal/models"
+)
+
+// DefaultScanWorkers is how many symbols are processed at once when scanner.workers isn't set
+const DefaultScanWorkers = 4
+
+// SymbolTask processes one symbol of a scan
+type SymbolTask func(ctx context.Context, symbol string) error
+
+// WorkerPool runs tasks over many symbols with bounded concurrency. Pattern scans, indicator refreshes and
+// S/R detection share one pool, so the number of symbols being processed at once stays within its workers
+// even when several runs overlap. A failing or panicking symbol doesn't stop the rest of its run.
+type WorkerPool struct {
+	workers int
+	slots   chan struct{}
+}
+
+// NewWorkerPool creates a worker pool running up to workers symbols at once
+func NewWorkerPool(workers int) *WorkerPool {
+	if workers <= 0 {
+		workers = DefaultScanWorkers
+	}
+	return &WorkerPool{workers: workers, slots: make(chan struct{}, workers)}
+}
+
+// Workers returns how many symbols the pool processes at once
+func (wp *WorkerPool) Workers() int {
+	return wp.workers
+}
+
+// Run runs task for every symbol and waits for all of them. Symbols not yet started when ctx is done are
+// recorded as failed with its error.
+func (wp *WorkerPool) Run(ctx context.Context, name string, symbols []string, task SymbolTask) *models.ScanRun {
+	run := &models.ScanRun{
+		Task:      name,
+		Symbols:   len(symbols),
+		Errors:    make(map[string]string),
+		Workers:   wp.workers,
+		StartedAt: time.Now(),
+	}
+
+	var (
+		mutex sync.Mutex
+		wg    sync.WaitGroup
+	)
+	record := func(symbol string, err error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if err != nil {
+			run.Failed++
+			run.Errors[symbol] = err.Error()
+			return
+		}
+		run.Succeeded++
+	}
+
+	for _, symbol := range symbols {
+		if err := ctx.Err(); err != nil {
+			record(symbol, err)
+			continue
+		}
+		select {
+		case wp.slots <- struct{}{}:
+		case <-ctx.Done():
+			record(symbol, ctx.Err())
+			continue
+		}
+
+		wg.Add(1)
+		go func(symbol string) {
+			defer wg.Done()
+			defer func() { <-wp.slots }()
+			record(symbol, runSymbolTask(ctx, task, symbol))
+		}(symbol)
+	}
+	wg.Wait()
+
+	run.TotalMillis = float64(time.Since(run.StartedAt).Microseconds()) / 1000
+	log.Printf("%s finished for %d symbols in %.0fms on %d workers, %d failed",
+		name, run.Symbols, run.TotalMillis, run.Workers, run.Failed)
+	return run
+}
+
+// runSymbolTask runs a task, converting a panic into the symbol's error
+func runSymbolTask(ctx context.Context, task SymbolTask, symbol string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return task(ctx, symbol)
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestWorkerPoolRunBoundsConcurrency tests that overlapping runs share the pool's workers
+func TestWorkerPoolRunBoundsConcurrency(t *testing.T) {
+	wp := NewWorkerPool(2)
+
+	var running, peak int32
+	task := func(ctx context.Context, symbol string) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			run := wp.Run(context.Background(), "Pattern scan", []string{"AAPL", "MSFT", "NVDA", "TSLA"}, task)
+			if run.Succeeded != 4 || run.Failed != 0 || run.Workers != 2 {
+				t.Errorf("expected 4 symbols to succeed on 2 workers, got %+v", run)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("expected at most 2 symbols at once, got %d", peak)
+	}
+	if peak < 2 {
+		t.Errorf("expected symbols to run concurrently, got a peak of %d", peak)
+	}
+}
+
+// TestWorkerPoolRunIsolatesFailures tests that an error or a panic only fails its own symbol
+func TestWorkerPoolRunIsolatesFailures(t *testing.T) {
+	wp := NewWorkerPool(3)
+
+	run := wp.Run(context.Background(), "S/R detection", []string{"AAPL", "BAD", "BOOM", "MSFT"}, func(ctx context.Context, symbol string) error {
+		switch symbol {
+		case "BAD":
+			return errors.New("no data")
+		case "BOOM":
+			panic("index out of range")
+		}
+		return nil
+	})
+
+	if run.Symbols != 4 || run.Succeeded != 2 || run.Failed != 2 {
+		t.Fatalf("expected 2 of 4 symbols to succeed, got %+v", run)
+	}
+	if run.Errors["BAD"] != "no data" || run.Errors["BOOM"] != "panic: index out of range" {
+		t.Errorf("expected the error and the panic per symbol, got %v", run.Errors)
+	}
+	if run.TotalMillis < 0 || run.StartedAt.IsZero() {
+		t.Errorf("expected the run to be timed, got %+v", run)
+	}
+}
+
+// TestWorkerPoolRunCancelled tests that symbols not started before cancellation are reported as failed
+func TestWorkerPoolRunCancelled(t *testing.T) {
+	wp := NewWorkerPool(1)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var started int32
+	run := wp.Run(ctx, "Indicator refresh", []string{"AAPL", "MSFT", "NVDA"}, func(ctx context.Context, symbol string) error {
+		atomic.AddInt32(&started, 1)
+		cancel()
+		return nil
+	})
+
+	if started != 1 || run.Succeeded != 1 || run.Failed != 2 {
+		t.Fatalf("expected only the first symbol to run, got %d started and %+v", started, run)
+	}
+	if run.Errors["NVDA"] != context.Canceled.Error() {
+		t.Errorf("expected the remaining symbols to fail with the cancellation, got %v", run.Errors)
+	}
+}
+
+// TestNewWorkerPoolDefault tests that an unset worker count falls back to the default
+func TestNewWorkerPoolDefault(t *testing.T) {
+	if workers := NewWorkerPool(0).Workers(); workers != DefaultScanWorkers {
+		t.Errorf("expected %d workers, got %d", DefaultScanWorkers, workers)
+	}
+}
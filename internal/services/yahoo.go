@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"market-watch-go/internal/config"
+	"market-watch-go/internal/models"
+)
+
+// Yahoo only serves 5-minute bars for the last 60 days
+const yahooMaxIntradayDays = 60
+
+// YahooService fetches market data from the public Yahoo Finance chart API (no API key required)
+type YahooService struct {
+	client *http.Client
+	cfg    *config.Config
+}
+
+// YahooChartResponse represents the response from the Yahoo Finance chart API
+type YahooChartResponse struct {
+	Chart struct {
+		Result []YahooChartResult `json:"result"`
+		Error  *struct {
+			Code        string `json:"code"`
+			Description string `json:"description"`
+		} `json:"error"`
+	} `json:"chart"`
+}
+
+// YahooChartResult represents a single symbol's chart data
+type YahooChartResult struct {
+	Meta struct {
+		Symbol   string `json:"symbol"`
+		Currency string `json:"currency"`
+	} `json:"meta"`
+	Timestamp  []int64 `json:"timestamp"`
+	Indicators struct {
+		Quote []struct {
+			Open   []*float64 `json:"open"`
+			High   []*float64 `json:"high"`
+			Low    []*float64 `json:"low"`
+			Close  []*float64 `json:"close"`
+			Volume []*int64   `json:"volume"`
+		} `json:"quote"`
+	} `json:"indicators"`
+}
+
+// NewYahooService creates a new Yahoo Finance service
+func NewYahooService(cfg *config.Config) *YahooService {
+	timeout := cfg.MarketData.Yahoo.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	return &YahooService{
+		client: &http.Client{Timeout: timeout},
+		cfg:    cfg,
+	}
+}
+
+// Name returns the provider identifier
+func (ys *YahooService) Name() string {
+	return ProviderYahoo
+}
+
+// GetPriceAggregates fetches 5-minute OHLC bars for a symbol
+func (ys *YahooService) GetPriceAggregates(symbol string, from, to time.Time) ([]*models.PriceData, error) {
+	// Clamp to the intraday window Yahoo supports
+	earliest := time.Now().AddDate(0, 0, -yahooMaxIntradayDays+1)
+	if from.Before(earliest) {
+		from = earliest
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ys.client.Timeout)
+	defer cancel()
+
+	params := url.Values{}
+	params.Set("period1", fmt.Sprintf("%d", from.Unix()))
+	params.Set("period2", fmt.Sprintf("%d", to.Unix()))
+	params.Set("interval", "5m")
+	params.Set("includePrePost", fmt.Sprintf("%t", !ys.cfg.Collection.RegularHoursOnly))
+
+	requestURL := fmt.Sprintf("%s/v8/finance/chart/%s?%s", ys.baseURL(), url.PathEscape(yahooSymbol(symbol)), params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	// Yahoo rejects requests without a browser-like user agent
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; market-watch-go)")
+
+	resp, err := ys.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to make request: %w", ErrUpstreamUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%w: API request failed with status %d: %s", ErrUpstreamUnavailable, resp.StatusCode, string(body))
+	}
+
+	var chartResp YahooChartResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chartResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if chartResp.Chart.Error != nil {
+		return nil, fmt.Errorf("API response error: %s", chartResp.Chart.Error.Description)
+	}
+
+	if len(chartResp.Chart.Result) == 0 {
+		return nil, nil
+	}
+
+	priceData := convertYahooResult(symbol, &chartResp.Chart.Result[0])
+
+	log.Printf("Fetched %d price data points for %s from Yahoo (%s to %s)",
+		len(priceData), symbol, from.Format("2006-01-02"), to.Format("2006-01-02"))
+
+	return priceData, nil
+}
+
+// convertYahooResult converts a Yahoo chart result to price bars, skipping incomplete bars
+func convertYahooResult(symbol string, result *YahooChartResult) []*models.PriceData {
+	if len(result.Indicators.Quote) == 0 {
+		return nil
+	}
+	quote := result.Indicators.Quote[0]
+
+	var priceData []*models.PriceData
+	for i, ts := range result.Timestamp {
+		if i >= len(quote.Open) || i >= len(quote.High) || i >= len(quote.Low) || i >= len(quote.Close) || i >= len(quote.Volume) {
+			break
+		}
+		if quote.Open[i] == nil || quote.High[i] == nil || quote.Low[i] == nil || quote.Close[i] == nil {
+			continue
+		}
+
+		var volume int64
+		if quote.Volume[i] != nil {
+			volume = *quote.Volume[i]
+		}
+
+		priceData = append(priceData, &models.PriceData{
+			Symbol:    symbol,
+			Timestamp: time.Unix(ts, 0),
+			Open:      *quote.Open[i],
+			High:      *quote.High[i],
+			Low:       *quote.Low[i],
+			Close:     *quote.Close[i],
+			Volume:    volume,
+			CreatedAt: time.Now(),
+		})
+	}
+
+	return priceData
+}
+
+// GetAggregates fetches 5-minute volume bars for a symbol
+func (ys *YahooService) GetAggregates(symbol string, from, to time.Time) ([]*models.VolumeData, error) {
+	priceData, err := ys.GetPriceAggregates(symbol, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	volumeData := make([]*models.VolumeData, 0, len(priceData))
+	for _, pd := range priceData {
+		volumeData = append(volumeData, &models.VolumeData{
+			Symbol:    pd.Symbol,
+			Timestamp: pd.Timestamp,
+			Volume:    pd.Volume,
+			CreatedAt: pd.CreatedAt,
+		})
+	}
+
+	return volumeData, nil
+}
+
+// GetLatestAggregates fetches the most recent volume bars
+func (ys *YahooService) GetLatestAggregates(symbol string, minutes int) ([]*models.VolumeData, error) {
+	to := time.Now()
+	from := to.Add(-time.Duration(minutes) * time.Minute)
+
+	return ys.GetAggregates(symbol, from, to)
+}
+
+// GetLatestPriceAggregates fetches the most recent OHLC bars
+func (ys *YahooService) GetLatestPriceAggregates(symbol string, minutes int) ([]*models.PriceData, error) {
+	to := time.Now()
+	from := to.Add(-time.Duration(minutes) * time.Minute)
+
+	return ys.GetPriceAggregates(symbol, from, to)
+}
+
+// GetHistoricalData fetches historical volume bars over specified days
+func (ys *YahooService) GetHistoricalData(symbol string, days int) ([]*models.VolumeData, error) {
+	to := time.Now()
+	from := to.AddDate(0, 0, -days)
+
+	return ys.GetAggregates(symbol, from, to)
+}
+
+// GetHistoricalPriceData fetches historical OHLC bars over specified days
+func (ys *YahooService) GetHistoricalPriceData(symbol string, days int) ([]*models.PriceData, error) {
+	to := time.Now()
+	from := to.AddDate(0, 0, -days)
+
+	return ys.GetPriceAggregates(symbol, from, to)
+}
+
+// HealthCheck verifies Yahoo Finance is reachable
+func (ys *YahooService) HealthCheck() error {
+	to := time.Now()
+	from := to.AddDate(0, 0, -5)
+
+	if _, err := ys.GetPriceAggregates("AAPL", from, to); err != nil {
+		return fmt.Errorf("yahoo finance health check failed: %w", err)
+	}
+
+	return nil
+}
+
+// yahooSymbol converts Polygon's crypto and FX tickers to Yahoo's: X:BTCUSD is BTC-USD and C:EURUSD is EURUSD=X
+func yahooSymbol(symbol string) string {
+	switch models.AssetClassOf(symbol) {
+	case models.AssetClassCrypto:
+		pair := strings.TrimPrefix(symbol, models.CryptoSymbolPrefix)
+		if len(pair) > 3 {
+			return pair[:len(pair)-3] + "-" + pair[len(pair)-3:]
+		}
+		return pair
+	case models.AssetClassForex:
+		return strings.TrimPrefix(symbol, models.ForexSymbolPrefix) + "=X"
+	default:
+		return symbol
+	}
+}
+
+// baseURL returns the configured chart API base URL
+func (ys *YahooService) baseURL() string {
+	if ys.cfg.MarketData.Yahoo.BaseURL != "" {
+		return ys.cfg.MarketData.Yahoo.BaseURL
+	}
+	return "https://query1.finance.yahoo.com"
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestConvertYahooResult tests conversion of Yahoo chart data, including null bars
+func TestConvertYahooResult(t *testing.T) {
+	payload := `{"chart":{"result":[{
+		"meta":{"symbol":"AAPL","currency":"USD"},
+		"timestamp":[1718614800,1718615100,1718615400],
+		"indicators":{"quote":[{
+			"open":[210.1,null,211.0],
+			"high":[210.5,null,211.4],
+			"low":[209.8,null,210.7],
+			"close":[210.2,null,211.3],
+			"volume":[120000,null,null]
+		}]}
+	}],"error":null}}`
+
+	var resp YahooChartResponse
+	if err := json.Unmarshal([]byte(payload), &resp); err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+
+	bars := convertYahooResult("AAPL", &resp.Chart.Result[0])
+
+	if len(bars) != 2 {
+		t.Fatalf("expected 2 bars (null bar skipped), got %d", len(bars))
+	}
+
+	if bars[0].Close != 210.2 || bars[0].Volume != 120000 {
+		t.Errorf("unexpected first bar: %+v", bars[0])
+	}
+
+	if bars[1].Timestamp.Unix() != 1718615400 {
+		t.Errorf("expected second bar timestamp 1718615400, got %d", bars[1].Timestamp.Unix())
+	}
+
+	if bars[1].Volume != 0 {
+		t.Errorf("expected missing volume to default to 0, got %d", bars[1].Volume)
+	}
+}
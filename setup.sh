@@ -11,7 +11,7 @@ mkdir -p bin data web/static/lib
 # Build the application
 echo "🔨 Building application..."
 go mod tidy
-go build -o bin/market-watch cmd/server/main.go
+go build -o bin/market-watch .
 
 if [ $? -eq 0 ]; then
     echo "✅ Build successful!"
@@ -38,7 +38,7 @@ if grep -q "your_polygon_api_key_here" .env; then
     echo "   3. Copy your API key and paste it in .env file"
     echo ""
     echo "Then run the application with:"
-    echo "   go run cmd/server/main.go"
+    echo "   go run ."
     echo ""
     exit 0
 fi
@@ -55,7 +55,7 @@ echo "2. Run the application:"
 echo "   ./bin/market-watch"
 echo ""
 echo "Or run with Go:"
-echo "   go run cmd/server/main.go"
+echo "   go run ."
 echo ""
 echo "Dashboard will be available at: http://localhost:8080"
 echo "API will be available at: http://localhost:8080/api"
@@ -4,6 +4,7 @@ class MarketWatchDashboard {
         this.charts = {};
         this.symbols = [];
         this.currentTimeRange = '1D';
+        this.timeZone = 'UTC'; // from the display settings
         this.refreshInterval = null;
         this.updateInterval = 30000; // 30 seconds
         this.isLoading = false;
@@ -46,6 +47,8 @@ class MarketWatchDashboard {
     async init() {
         console.log('Dashboard init() started');
         
+        await this.loadDisplaySettings();
+
         console.log('Loading symbols...');
         await this.loadSymbols();
         console.log('Symbols loaded:', this.symbols);
@@ -69,10 +72,29 @@ class MarketWatchDashboard {
         
         console.log('Starting auto refresh...');
         this.startAutoRefresh();
+
+        console.log('Connecting to live stream...');
+        this.connectStream();
         
         console.log('Dashboard initialization complete');
     }
 
+    async loadDisplaySettings() {
+        try {
+            const response = await fetch('/api/settings/display');
+            if (response.ok) {
+                const settings = await response.json();
+                this.timeZone = settings.timezone || 'UTC';
+            }
+        } catch (error) {
+            console.warn('Failed to load display settings, showing UTC times:', error);
+        }
+    }
+
+    formatTime(date) {
+        return date.toLocaleTimeString([], { hour: '2-digit', minute: '2-digit', hour12: false, timeZone: this.timeZone });
+    }
+
     async loadSymbols() {
         try {
             // First, quick health check to see if backend is running
@@ -242,6 +264,10 @@ class MarketWatchDashboard {
             this.toggleSymbolManagement();
         });
 
+        document.getElementById('paper-run-btn').addEventListener('click', () => {
+            this.runPaperTradingCycle();
+        });
+
         // Window resize handler
         window.addEventListener('resize', () => {
             this.resizeCharts();
@@ -389,6 +415,9 @@ class MarketWatchDashboard {
             // Load support/resistance
             await this.loadSupportResistance();
             
+            // Load paper trading panel
+            await this.loadPaperTrading();
+            
             this.updateLastUpdateTime();
             console.log('Dashboard data loaded successfully');
         } catch (error) {
@@ -453,7 +482,7 @@ class MarketWatchDashboard {
 
         // Parse data
         const parsedData = data.map(d => ({
-            timestamp: new Date(d.timestamp),
+            timestamp: d.time !== undefined ? new Date(d.time * 1000) : new Date(d.timestamp),
             open: d.open_price || d.open,
             high: d.high_price || d.high,
             low: d.low_price || d.low,
@@ -636,7 +665,7 @@ class MarketWatchDashboard {
             .attr("class", "axis")
             .attr("transform", `translate(0,${height - 60})`)
             .call(d3.axisBottom(xScale)
-                .tickFormat(d3.timeFormat("%H:%M"))
+                .tickFormat(d => this.formatTime(d))
                 .tickSize(5))
             .selectAll("text")
             .style("fill", this.colors.textSecondary)
@@ -710,7 +739,7 @@ class MarketWatchDashboard {
                     tooltip.html(`
                         <div class="tooltip-row">
                             <span class="tooltip-label">${symbol}</span>
-                            <span class="tooltip-value">${dataPoint.timestamp.toLocaleTimeString()}</span>
+                            <span class="tooltip-value">${this.formatTime(dataPoint.timestamp)}</span>
                         </div>
                         <div class="tooltip-row">
                             <span class="tooltip-label">O:</span>
@@ -1088,6 +1117,148 @@ class MarketWatchDashboard {
         }
     }
 
+    async loadPaperTrading() {
+        try {
+            const signal = this.loadingController?.signal;
+            const [statusRes, tradesRes, equityRes] = await Promise.all([
+                fetch('/api/paper-trading/status', { signal }),
+                fetch('/api/paper-trading/trades?limit=20', { signal }),
+                fetch('/api/paper-trading/equity?days=30', { signal })
+            ]);
+
+            if (statusRes.ok) {
+                this.updatePaperTradingStatus(await statusRes.json());
+            }
+            if (tradesRes.ok) {
+                this.displayPaperTrades((await tradesRes.json()).trades || []);
+            }
+            if (equityRes.ok) {
+                this.drawPaperEquityCurve((await equityRes.json()).points || []);
+            }
+        } catch (error) {
+            if (error.name === 'AbortError') throw error;
+            console.error('Failed to load paper trading:', error);
+        }
+    }
+
+    updatePaperTradingStatus(status) {
+        const enabledEl = document.getElementById('paper-enabled');
+        if (enabledEl) {
+            enabledEl.textContent = status.enabled ? 'Auto' : 'Manual';
+            enabledEl.className = `badge ms-2 ${status.enabled ? 'bg-success' : 'bg-secondary'}`;
+        }
+
+        const returnClass = status.return_percent >= 0 ? 'text-success' : 'text-danger';
+        const equityEl = document.getElementById('paper-equity');
+        if (equityEl) {
+            equityEl.textContent = `$${status.equity.toLocaleString(undefined, { maximumFractionDigits: 0 })}`;
+        }
+        const returnEl = document.getElementById('paper-return');
+        if (returnEl) {
+            returnEl.textContent = `${status.return_percent >= 0 ? '+' : ''}${status.return_percent.toFixed(2)}%`;
+            returnEl.className = returnClass;
+        }
+        const openEl = document.getElementById('paper-open');
+        if (openEl) {
+            openEl.textContent = `${status.open_trades} / ${status.pending_trades}`;
+        }
+        const winRateEl = document.getElementById('paper-win-rate');
+        if (winRateEl) {
+            winRateEl.textContent = status.closed_trades > 0
+                ? `${status.win_rate.toFixed(0)}% (${status.closed_trades})`
+                : '--';
+        }
+    }
+
+    displayPaperTrades(trades) {
+        const tbody = document.getElementById('paper-trades');
+        if (!tbody) return;
+
+        if (trades.length === 0) {
+            tbody.innerHTML = '<tr><td colspan="5" class="text-muted">No paper trades yet</td></tr>';
+            return;
+        }
+
+        tbody.innerHTML = trades.map(trade => {
+            const pnl = trade.status === 'open' ? trade.unrealized_pnl : trade.realized_pnl;
+            const pnlText = (trade.status === 'open' || trade.status === 'closed')
+                ? `<span class="${pnl >= 0 ? 'text-success' : 'text-danger'}">$${(pnl || 0).toFixed(2)}</span>`
+                : '--';
+            const statusText = trade.exit_reason ? `${trade.status} (${trade.exit_reason})` : trade.status;
+            return `
+                <tr>
+                    <td>${trade.symbol} <small class="text-muted">${trade.side}</small></td>
+                    <td><small>${trade.setup_type}</small></td>
+                    <td><small>${statusText}</small></td>
+                    <td class="text-end">$${trade.entry_price.toFixed(2)}</td>
+                    <td class="text-end">${pnlText}</td>
+                </tr>
+            `;
+        }).join('');
+    }
+
+    drawPaperEquityCurve(points) {
+        const container = document.getElementById('paper-equity-chart');
+        if (!container) return;
+
+        container.innerHTML = '';
+        if (points.length < 2) {
+            container.innerHTML = '<div class="text-muted small">Equity curve appears after a few paper trading cycles</div>';
+            return;
+        }
+
+        const margin = { top: 10, right: 10, bottom: 20, left: 60 };
+        const width = container.clientWidth - margin.left - margin.right;
+        const height = container.clientHeight - margin.top - margin.bottom;
+
+        const data = points.map(p => ({ time: new Date(p.timestamp), equity: p.equity }));
+
+        const svg = d3.select(container).append('svg')
+            .attr('width', width + margin.left + margin.right)
+            .attr('height', height + margin.top + margin.bottom)
+            .append('g')
+            .attr('transform', `translate(${margin.left},${margin.top})`);
+
+        const xScale = d3.scaleTime()
+            .domain(d3.extent(data, d => d.time))
+            .range([0, width]);
+        const yScale = d3.scaleLinear()
+            .domain(d3.extent(data, d => d.equity))
+            .nice()
+            .range([height, 0]);
+
+        svg.append('g')
+            .attr('transform', `translate(0,${height})`)
+            .call(d3.axisBottom(xScale).ticks(4));
+        svg.append('g')
+            .call(d3.axisLeft(yScale).ticks(4).tickFormat(d => `$${d3.format(',.0f')(d)}`));
+
+        const last = data[data.length - 1].equity;
+        svg.append('path')
+            .datum(data)
+            .attr('fill', 'none')
+            .attr('stroke', last >= data[0].equity ? '#26a69a' : '#ef5350')
+            .attr('stroke-width', 1.5)
+            .attr('d', d3.line().x(d => xScale(d.time)).y(d => yScale(d.equity)));
+    }
+
+    async runPaperTradingCycle() {
+        try {
+            const response = await fetch('/api/paper-trading/run', { method: 'POST' });
+            const result = await response.json();
+
+            if (!response.ok) {
+                throw new Error(result.message || 'Failed to run paper trading cycle');
+            }
+
+            this.showSuccess(`Paper trading: took ${result.taken.length}, filled ${result.filled}, closed ${result.closed}`);
+            await this.loadPaperTrading();
+        } catch (error) {
+            console.error('Failed to run paper trading cycle:', error);
+            this.showError('Failed to run paper trading cycle');
+        }
+    }
+
     async forceCollection() {
         try {
             const response = await fetch('/api/collection/force', {
@@ -1294,7 +1465,7 @@ class MarketWatchDashboard {
                 
             } else {
                 const errorData = await response.json().catch(() => ({}));
-                const errorMessage = errorData.error || `Failed to add ${symbol}`;
+                const errorMessage = errorData.message || `Failed to add ${symbol}`;
                 this.showError(errorMessage);
                 console.error(`Failed to add ${symbol}:`, errorData);
             }
@@ -1328,7 +1499,7 @@ class MarketWatchDashboard {
                 
             } else {
                 const errorData = await response.json().catch(() => ({}));
-                const errorMessage = errorData.error || `Failed to remove ${symbol}`;
+                const errorMessage = errorData.message || `Failed to remove ${symbol}`;
                 this.showError(errorMessage);
                 console.error(`Failed to remove ${symbol}:`, errorData);
             }
@@ -1367,6 +1538,43 @@ class MarketWatchDashboard {
         }, this.updateInterval);
     }
 
+    connectStream() {
+        if (!window.WebSocket) return;
+
+        const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
+        const symbols = this.symbols.join(',');
+        this.stream = new WebSocket(`${protocol}//${window.location.host}/ws/stream?symbols=${encodeURIComponent(symbols)}`);
+
+        this.stream.onmessage = (event) => {
+            const message = JSON.parse(event.data);
+            if (message.type === 'price' && Array.isArray(message.data) && message.data.length > 0) {
+                const latest = message.data[message.data.length - 1];
+                this.updateLivePrice(message.symbol, latest);
+            } else if (message.type === 'notification' && window.notificationCenter) {
+                window.notificationCenter.add(message.data);
+            }
+        };
+
+        // Polling keeps charts fresh while disconnected; retry the stream periodically
+        this.stream.onclose = () => {
+            this.stream = null;
+            setTimeout(() => this.connectStream(), this.updateInterval);
+        };
+    }
+
+    updateLivePrice(symbol, candle) {
+        const fields = { open: candle.open, high: candle.high, low: candle.low, close: candle.close };
+        Object.entries(fields).forEach(([field, value]) => {
+            const el = document.getElementById(`${symbol}-${field}`);
+            if (el) el.textContent = `$${value.toFixed(2)}`;
+        });
+
+        const volumeEl = document.getElementById(`${symbol}-volume`);
+        if (volumeEl) volumeEl.textContent = this.formatVolume(candle.volume);
+
+        this.updateLastUpdateTime();
+    }
+
     updateLastUpdateTime() {
         const lastUpdateEl = document.getElementById('last-update');
         if (lastUpdateEl) {
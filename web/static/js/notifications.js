@@ -0,0 +1,130 @@
+// Notification Center JavaScript
+
+class NotificationCenter {
+    constructor() {
+        this.notifications = [];
+        this.unread = 0;
+        this.pollInterval = 60000; // fallback while the live stream is down
+
+        document.getElementById("notifications-mark-read").addEventListener("click", (e) => {
+            e.preventDefault();
+            this.markAllRead();
+        });
+        document.getElementById("notifications-clear").addEventListener("click", (e) => {
+            e.preventDefault();
+            this.clear();
+        });
+        document.getElementById("notifications-list").addEventListener("click", (e) => {
+            const item = e.target.closest("[data-notification-id]");
+            if (item) this.open(Number(item.dataset.notificationId), item.dataset.link);
+        });
+
+        this.load();
+        setInterval(() => this.load(), this.pollInterval);
+    }
+
+    async load() {
+        try {
+            const response = await fetch("/api/v1/notifications?limit=20");
+            if (!response.ok) throw new Error(response.statusText);
+            const data = await response.json();
+            this.notifications = data.notifications || [];
+            this.unread = data.unread || 0;
+            this.render();
+        } catch (err) {
+            console.error("Failed to load notifications:", err);
+        }
+    }
+
+    // add shows a notification pushed by the live stream
+    add(notification) {
+        if (this.notifications.some(n => n.id === notification.id)) return;
+        this.notifications.unshift(notification);
+        this.notifications = this.notifications.slice(0, 20);
+        this.unread++;
+        this.render();
+    }
+
+    async open(id, link) {
+        const notification = this.notifications.find(n => n.id === id);
+        if (notification && !notification.is_read) {
+            try {
+                await fetch(`/api/v1/notifications/${id}/read`, { method: "PUT" });
+                notification.is_read = true;
+                this.unread = Math.max(0, this.unread - 1);
+                this.render();
+            } catch (err) {
+                console.error("Failed to mark notification read:", err);
+            }
+        }
+        if (link && link !== window.location.pathname) {
+            window.location.href = link;
+        }
+    }
+
+    async markAllRead() {
+        try {
+            await fetch("/api/v1/notifications/read", { method: "POST" });
+            this.notifications.forEach(n => n.is_read = true);
+            this.unread = 0;
+            this.render();
+        } catch (err) {
+            console.error("Failed to mark notifications read:", err);
+        }
+    }
+
+    async clear() {
+        try {
+            await fetch("/api/v1/notifications", { method: "DELETE" });
+            this.notifications = [];
+            this.unread = 0;
+            this.render();
+        } catch (err) {
+            console.error("Failed to clear notifications:", err);
+        }
+    }
+
+    render() {
+        const badge = document.getElementById("notifications-badge");
+        badge.textContent = this.unread > 99 ? "99+" : this.unread;
+        badge.classList.toggle("d-none", this.unread === 0);
+
+        const list = document.getElementById("notifications-list");
+        if (this.notifications.length === 0) {
+            list.innerHTML = `<li class="dropdown-item-text text-muted small">No notifications</li>`;
+            return;
+        }
+
+        list.innerHTML = this.notifications.map(n => `
+          <li>
+            <a href="#" class="dropdown-item notification-item ${n.is_read ? "" : "notification-unread"}"
+               data-notification-id="${n.id}" data-link="${this.escape(n.link || "")}">
+              <div class="d-flex justify-content-between">
+                <span class="notification-title">
+                  <span class="notification-dot severity-${this.escape(n.severity)}"></span>${this.escape(n.title)}
+                </span>
+                <small class="text-muted ms-2">${this.formatAge(n.created_at)}</small>
+              </div>
+              <div class="notification-message small text-muted">${this.escape(n.message)}</div>
+            </a>
+          </li>`).join("");
+    }
+
+    formatAge(timestamp) {
+        const minutes = Math.floor((Date.now() - new Date(timestamp).getTime()) / 60000);
+        if (minutes < 1) return "now";
+        if (minutes < 60) return `${minutes}m`;
+        if (minutes < 1440) return `${Math.floor(minutes / 60)}h`;
+        return `${Math.floor(minutes / 1440)}d`;
+    }
+
+    escape(text) {
+        const div = document.createElement("div");
+        div.textContent = text == null ? "" : String(text);
+        return div.innerHTML.replace(/"/g, "&quot;");
+    }
+}
+
+document.addEventListener("DOMContentLoaded", () => {
+    window.notificationCenter = new NotificationCenter();
+});
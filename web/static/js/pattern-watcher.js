@@ -174,7 +174,7 @@ class PatternWatcher {
       console.log("Loading patterns...");
 
       // Use unified patterns endpoint
-      const response = await fetch("/api/patterns");
+      const response = await fetch("/api/patterns?limit=500");
 
       if (!response.ok) {
         throw new Error(`HTTP ${response.status}: ${response.statusText}`);
@@ -183,17 +183,13 @@ class PatternWatcher {
       const data = await response.json();
       console.log(`Loaded patterns:`, data);
 
-      // Extract patterns from the unified API response
-      let allPatterns = [];
-      if (data.patterns) {
-        // Combine head_shoulders and falling_wedge patterns
-        if (data.patterns.head_shoulders) {
-          allPatterns = allPatterns.concat(data.patterns.head_shoulders.map(p => ({...p, pattern_type: 'head_shoulders'})));
-        }
-        if (data.patterns.falling_wedge) {
-          allPatterns = allPatterns.concat(data.patterns.falling_wedge.map(p => ({...p, pattern_type: 'falling_wedge'})));
-        }
-      }
+      // Unwrap the paged pattern list; every pattern keeps its specific type
+      // (inverse_head_shoulders, rising_wedge, bull_flag, ...) alongside its family and direction
+      const allPatterns = (data.items || []).map(item => ({
+        ...item.pattern,
+        pattern_family: item.pattern_family,
+        direction: item.direction,
+      }));
 
       this.patterns = allPatterns;
       this.displayPatterns();
@@ -213,26 +209,33 @@ class PatternWatcher {
     try {
       this.showLoading("Scanning all symbols for patterns...");
 
-      // Use unified patterns scan endpoint
+      // The scan runs as a background job; poll it until it finishes
       const response = await fetch("/api/patterns/scan", {
         method: "POST",
       });
 
-      if (response.ok) {
-        const result = await response.json();
+      if (!response.ok) {
+        throw new Error("Pattern scan failed");
+      }
+
+      const queued = await response.json();
+      const job = await this.waitForJob(queued.job_id);
+      const found = Object.values(job.results || {}).reduce(
+        (sum, result) => sum + (result.patterns_found || 0),
+        0
+      );
+
+      if (job.status === "completed") {
         this.showSuccess(
-          `Pattern scan completed. ${
-            result.message || "Multiple patterns updated."
-          }`
+          `Pattern scan completed for ${job.total} symbols. ${found} patterns found.`
         );
-
-        // Refresh patterns after scan
-        setTimeout(() => {
-          this.loadPatterns();
-        }, 2000);
       } else {
-        throw new Error("Pattern scan failed");
+        this.showError(
+          `Pattern scan ${job.status}: ${job.failed} of ${job.total} symbols failed.`
+        );
       }
+
+      this.loadPatterns();
     } catch (error) {
       this.showError("Failed to scan patterns: " + error.message);
     } finally {
@@ -240,6 +243,25 @@ class PatternWatcher {
     }
   }
 
+  async waitForJob(jobId, intervalMs = 2000) {
+    for (;;) {
+      const response = await fetch(`/api/jobs/${jobId}`);
+      if (!response.ok) {
+        throw new Error(`Failed to get job status: HTTP ${response.status}`);
+      }
+
+      const job = await response.json();
+      if (["completed", "failed", "cancelled"].includes(job.status)) {
+        return job;
+      }
+
+      this.showLoading(
+        `Scanning patterns... ${job.completed}/${job.total} symbols`
+      );
+      await new Promise((resolve) => setTimeout(resolve, intervalMs));
+    }
+  }
+
   async detectPatternsForSelected() {
     if (!this.selectedSymbol) return;
 
@@ -258,8 +280,12 @@ class PatternWatcher {
 
         if (result.patterns_found > 0) {
           const patternTypes = [];
-          if (result.head_shoulders_pattern) patternTypes.push("Head & Shoulders");
+          if (result.head_shoulders_pattern) patternTypes.push("Inverse Head & Shoulders");
+          if (result.bearish_head_shoulders_pattern) patternTypes.push("Head & Shoulders");
           if (result.falling_wedge_pattern) patternTypes.push("Falling Wedge");
+          if (result.rising_wedge_pattern) patternTypes.push("Rising Wedge");
+          if (result.triangle_pattern) patternTypes.push(this.formatPatternType(result.triangle_pattern.pattern_type));
+          if (result.flag_pattern) patternTypes.push(this.formatPatternType(result.flag_pattern.pattern_type));
           
           this.showSuccess(`Pattern(s) detected for ${this.selectedSymbol}: ${patternTypes.join(", ")}`);
           await this.loadPatterns();
@@ -752,17 +778,29 @@ class PatternWatcher {
   formatPatternType(patternType) {
     switch (patternType) {
       case 'head_shoulders': return 'Head & Shoulders';
+      case 'inverse_head_shoulders': return 'Inverse Head & Shoulders';
       case 'falling_wedge': return 'Falling Wedge';
+      case 'rising_wedge': return 'Rising Wedge';
       case 'cup_handle': return 'Cup & Handle';
+      case 'ascending_triangle': return 'Ascending Triangle';
+      case 'descending_triangle': return 'Descending Triangle';
+      case 'bull_flag': return 'Bull Flag';
+      case 'bear_flag': return 'Bear Flag';
       default: return patternType.replace(/_/g, ' ').replace(/\b\w/g, l => l.toUpperCase());
     }
   }
 
   getPatternTypeIcon(patternType) {
     switch (patternType) {
-      case 'head_shoulders': return 'graph-up-arrow';
+      case 'head_shoulders': return 'graph-down-arrow';
+      case 'inverse_head_shoulders': return 'graph-up-arrow';
       case 'falling_wedge': return 'graph-down-arrow';
+      case 'rising_wedge': return 'graph-up-arrow';
       case 'cup_handle': return 'cup';
+      case 'ascending_triangle': return 'triangle';
+      case 'descending_triangle': return 'triangle';
+      case 'bull_flag': return 'flag-fill';
+      case 'bear_flag': return 'flag';
       default: return 'graph-up';
     }
   }
@@ -0,0 +1,131 @@
+// Sector Strength JavaScript
+
+class SectorStrengthView {
+    constructor() {
+        this.expanded = new Set();
+        this.report = null;
+
+        document.getElementById("sectors-form").addEventListener("submit", (e) => {
+            e.preventDefault();
+            this.load();
+        });
+
+        this.load();
+    }
+
+    async load() {
+        const params = new URLSearchParams();
+        const benchmark = document.getElementById("benchmark-input").value.trim();
+        const windows = document.getElementById("windows-input").value.trim();
+        if (benchmark) params.set("benchmark", benchmark);
+        if (windows) params.set("windows", windows);
+
+        const error = document.getElementById("sectors-error");
+        error.classList.add("d-none");
+
+        try {
+            const response = await fetch(`/api/v1/analytics/sectors?${params}`);
+            const data = await response.json();
+            if (!response.ok) {
+                throw new Error(data.message || response.statusText);
+            }
+            this.report = data;
+            this.render();
+        } catch (err) {
+            error.textContent = "Failed to load sector strength: " + err.message;
+            error.classList.remove("d-none");
+            document.getElementById("sectors-body").innerHTML = "";
+        }
+    }
+
+    render() {
+        const report = this.report;
+        const windows = report.windows;
+        const sectors = report.sectors || [];
+
+        document.getElementById("sectors-subtitle").textContent =
+            `Watchlist stocks vs ${report.benchmark} (` +
+            windows.map(w => `${w}d ${this.formatPercent(report.benchmark_returns[w])}`).join(", ") +
+            `) as of ${new Date(report.generated_at).toLocaleString()}`;
+
+        document.getElementById("sectors-head").innerHTML = `
+          <tr>
+            <th style="width: 60px;">#</th>
+            <th>Sector</th>
+            ${windows.map(w => `<th class="text-end">RS ${w}d</th>`).join("")}
+            <th class="text-end">Score</th>
+          </tr>`;
+
+        const rows = [];
+        if (sectors.length === 0) {
+            rows.push(`<tr><td colspan="${windows.length + 3}" class="text-center text-muted p-3">No watchlist stocks with daily history</td></tr>`);
+        }
+        for (const sector of sectors) {
+            const open = this.expanded.has(sector.sector);
+            rows.push(`
+              <tr class="sector-row" data-sector="${this.escapeHtml(sector.sector)}" style="cursor: pointer;">
+                <td>${sector.rank}</td>
+                <td>
+                  <i class="bi ${open ? "bi-chevron-down" : "bi-chevron-right"} me-1"></i>
+                  <span class="fw-semibold">${this.escapeHtml(sector.sector)}</span>
+                  <span class="badge bg-secondary ms-1">${sector.stocks.length}</span>
+                </td>
+                ${windows.map(w => this.percentCell(sector.relative_strength[w])).join("")}
+                ${this.percentCell(sector.score, true)}
+              </tr>`);
+
+            if (!open) continue;
+            for (const stock of sector.stocks) {
+                rows.push(`
+                  <tr class="small">
+                    <td></td>
+                    <td class="ps-4">${this.escapeHtml(stock.symbol)}</td>
+                    ${windows.map(w => this.percentCell(stock.relative_strength[w], false, stock.returns[w])).join("")}
+                    ${this.percentCell(stock.score)}
+                  </tr>`);
+            }
+        }
+        document.getElementById("sectors-body").innerHTML = rows.join("");
+
+        document.querySelectorAll(".sector-row").forEach(row => {
+            row.addEventListener("click", () => {
+                const sector = row.dataset.sector;
+                if (this.expanded.has(sector)) {
+                    this.expanded.delete(sector);
+                } else {
+                    this.expanded.add(sector);
+                }
+                this.render();
+            });
+        });
+
+        const skipped = report.skipped || [];
+        document.getElementById("sectors-skipped").textContent = skipped.length > 0
+            ? `Not enough daily history: ${skipped.join(", ")}`
+            : "";
+    }
+
+    percentCell(value, bold = false, change = undefined) {
+        if (value === undefined || value === null) {
+            return `<td class="text-end text-muted">-</td>`;
+        }
+        const color = value >= 0 ? "text-success" : "text-danger";
+        const title = change !== undefined ? ` title="Return ${this.formatPercent(change)}"` : "";
+        return `<td class="text-end ${color}${bold ? " fw-bold" : ""}"${title}>${this.formatPercent(value)}</td>`;
+    }
+
+    formatPercent(value) {
+        if (value === undefined || value === null) return "-";
+        return `${value >= 0 ? "+" : ""}${value.toFixed(2)}%`;
+    }
+
+    escapeHtml(text) {
+        const div = document.createElement("div");
+        div.textContent = text || '';
+        return div.innerHTML;
+    }
+}
+
+document.addEventListener("DOMContentLoaded", () => {
+    new SectorStrengthView();
+});
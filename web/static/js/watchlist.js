@@ -8,6 +8,8 @@ class WatchlistManager {
         this.selectedStrategyId = null;
         this.currentSort = { field: 'symbol', direction: 'asc' };
         this.searchTerm = '';
+        this.newsSymbol = null;
+        this.savedScreens = [];
 
         this.init();
     }
@@ -72,6 +74,30 @@ class WatchlistManager {
         document.getElementById("save-strategy-btn").addEventListener("click", () => {
             this.saveStrategy();
         });
+
+        // Screener
+        document.getElementById("open-screener-btn").addEventListener("click", () => {
+            this.openScreener();
+        });
+        document.getElementById("run-screener-btn").addEventListener("click", () => {
+            this.runScreener();
+        });
+        document.getElementById("save-screener-btn").addEventListener("click", () => {
+            this.saveScreen();
+        });
+        document.getElementById("screener-saved").addEventListener("change", (e) => {
+            const screen = this.savedScreens.find(s => s.id === parseInt(e.target.value));
+            if (screen) {
+                this.fillScreenerForm(screen.criteria);
+            }
+        });
+
+        // Fetch latest news button
+        document.getElementById("refresh-news-btn").addEventListener("click", () => {
+            if (this.newsSymbol) {
+                this.loadNews(this.newsSymbol, true);
+            }
+        });
     }
 
     // Strategies Management
@@ -227,7 +253,8 @@ class WatchlistManager {
                 stock.symbol.toLowerCase().includes(this.searchTerm) ||
                 (stock.name && stock.name.toLowerCase().includes(this.searchTerm)) ||
                 (stock.notes && stock.notes.toLowerCase().includes(this.searchTerm)) ||
-                (stock.tags && stock.tags.toLowerCase().includes(this.searchTerm))
+                (stock.reference && stock.reference.sector && stock.reference.sector.toLowerCase().includes(this.searchTerm)) ||
+                (stock.tags && stock.tags.some(tag => tag.includes(this.searchTerm)))
             );
         }
 
@@ -355,8 +382,8 @@ class WatchlistManager {
             : '<span class="text-muted small">No associated strategies</span>';
 
         const tags = stock.tags ?
-            stock.tags.split(',').map(tag =>
-                `<span class="tag-pill">${tag.trim()}</span>`
+            stock.tags.map(tag =>
+                `<span class="tag-pill">${this.escapeHtml(tag)}</span>`
             ).join('') : '';
 
         const addedDate = new Date(stock.added_at).toLocaleDateString();
@@ -368,6 +395,7 @@ class WatchlistManager {
         </td>
         <td>
           <div>${stock.name || stock.symbol}</div>
+          ${stock.reference && stock.reference.sector ? `<div class="text-muted small">${stock.reference.sector}</div>` : ''}
           ${tags ? `<div class="stock-tags mt-1">${tags}</div>` : ''}
         </td>
         <td>${strategyBadge}</td>
@@ -470,7 +498,7 @@ class WatchlistManager {
         const symbol = document.getElementById("stock-symbol").value.trim().toUpperCase();
         const name = document.getElementById("stock-name").value.trim();
         const strategyId = document.getElementById("stock-strategy").value || null;
-        const tags = document.getElementById("stock-tags").value.trim();
+        const tags = this.parseTags(document.getElementById("stock-tags").value);
         const notes = document.getElementById("stock-notes").value.trim();
 
         if (!symbol) {
@@ -493,7 +521,7 @@ class WatchlistManager {
 
             if (!response.ok) {
                 const error = await response.json();
-                throw new Error(error.details || "Failed to add stock");
+                throw new Error(error.message || "Failed to add stock");
             }
 
             this.showSuccess(`${symbol} added to watchlist`);
@@ -529,7 +557,7 @@ class WatchlistManager {
 
             if (!response.ok) {
                 const error = await response.json();
-                throw new Error(error.details || "Failed to create strategy");
+                throw new Error(error.message || "Failed to create strategy");
             }
 
             this.showSuccess(`Strategy "${name}" created`);
@@ -555,7 +583,7 @@ class WatchlistManager {
         document.getElementById("edit-stock-symbol").value = stock.symbol;
         document.getElementById("edit-stock-name").value = stock.name || '';
         document.getElementById("edit-stock-strategy").value = stock.strategy_id || '';
-        document.getElementById("edit-stock-tags").value = stock.tags || '';
+        document.getElementById("edit-stock-tags").value = (stock.tags || []).join(', ');
         document.getElementById("edit-stock-notes").value = stock.notes || '';
 
         // Show modal
@@ -567,7 +595,7 @@ class WatchlistManager {
         const id = document.getElementById("edit-stock-id").value;
         const name = document.getElementById("edit-stock-name").value.trim();
         const strategyId = document.getElementById("edit-stock-strategy").value || null;
-        const tags = document.getElementById("edit-stock-tags").value.trim();
+        const tags = this.parseTags(document.getElementById("edit-stock-tags").value);
         const notes = document.getElementById("edit-stock-notes").value.trim();
 
         try {
@@ -584,7 +612,7 @@ class WatchlistManager {
 
             if (!response.ok) {
                 const error = await response.json();
-                throw new Error(error.details || "Failed to update stock");
+                throw new Error(error.message || "Failed to update stock");
             }
 
             this.showSuccess("Stock updated successfully");
@@ -618,7 +646,7 @@ class WatchlistManager {
 
             if (!response.ok) {
                 const error = await response.json();
-                throw new Error(error.details || "Failed to delete stock");
+                throw new Error(error.message || "Failed to delete stock");
             }
 
             this.showSuccess(`${stock.symbol} removed from watchlist`);
@@ -651,7 +679,7 @@ class WatchlistManager {
 
             if (!response.ok) {
                 const error = await response.json();
-                throw new Error(error.details || "Failed to delete strategy");
+                throw new Error(error.message || "Failed to delete strategy");
             }
 
             // Show success message with undo option
@@ -686,7 +714,7 @@ class WatchlistManager {
 
             if (!response.ok) {
                 const error = await response.json();
-                throw new Error(error.details || "Failed to restore strategy");
+                throw new Error(error.message || "Failed to restore strategy");
             }
 
             this.showSuccess(`Strategy "${strategyData.name}" restored`);
@@ -707,7 +735,7 @@ class WatchlistManager {
             const response = await fetch("/api/watchlist/refresh", { method: "POST" });
             if (!response.ok) {
                 const error = await response.json();
-                throw new Error(error.details || "Failed to refresh prices");
+                throw new Error(error.message || "Failed to refresh prices");
             }
             this.showSuccess("Prices and EMAs refreshed");
             await this.loadStocks();
@@ -722,11 +750,224 @@ class WatchlistManager {
     }
 
     viewStockDetails(id) {
-        // This could open a detailed view or redirect to a stock analysis page
         const stock = this.stocks.find(s => s.id === id);
         if (!stock) return;
-        // Implement modal or redirect logic here
-        this.showInfo(`Details for ${stock.symbol} coming soon!`);
+
+        this.newsSymbol = stock.symbol;
+        document.getElementById("news-symbol").textContent = stock.symbol;
+        document.getElementById("news-sentiment").innerHTML = '';
+        document.getElementById("news-articles").innerHTML = '<div class="text-center text-muted p-3">Loading news...</div>';
+
+        const modal = bootstrap.Modal.getOrCreateInstance(document.getElementById("stock-news-modal"));
+        modal.show();
+
+        this.loadNews(stock.symbol, false);
+    }
+
+    // News Panel
+
+    async loadNews(symbol, refresh) {
+        try {
+            const response = await fetch(`/api/news/${encodeURIComponent(symbol)}?limit=20${refresh ? '&refresh=true' : ''}`);
+            if (!response.ok) {
+                const error = await response.json().catch(() => ({}));
+                throw new Error(error.message || `HTTP ${response.status}`);
+            }
+            const data = await response.json();
+            if (symbol !== this.newsSymbol) return;
+            this.displayNews(data);
+        } catch (error) {
+            console.error("Failed to load news:", error);
+            document.getElementById("news-articles").innerHTML =
+                `<div class="text-center text-danger p-3">Failed to load news: ${this.escapeHtml(error.message)}</div>`;
+        }
+    }
+
+    displayNews(data) {
+        const sentiment = data.sentiment;
+        if (sentiment) {
+            document.getElementById("news-sentiment").innerHTML = `
+        <span class="small text-muted me-2">Last 7 days:</span>
+        <span class="badge news-sentiment-positive">${sentiment.positive} positive</span>
+        <span class="badge news-sentiment-neutral">${sentiment.neutral} neutral</span>
+        <span class="badge news-sentiment-negative">${sentiment.negative} negative</span>
+      `;
+        }
+
+        const container = document.getElementById("news-articles");
+        if (!data.articles || data.articles.length === 0) {
+            container.innerHTML = `
+        <div class="text-center text-muted p-3">
+          <i class="bi bi-newspaper opacity-25 display-4"></i>
+          <p class="mt-3">No headlines stored yet. Use Fetch Latest to load them.</p>
+        </div>
+      `;
+            return;
+        }
+
+        container.innerHTML = data.articles.map(article => `
+      <div class="news-article">
+        <div class="d-flex justify-content-between align-items-start">
+          <a href="${this.escapeHtml(article.url)}" target="_blank" rel="noopener" class="fw-semibold">
+            ${this.escapeHtml(article.title)}
+          </a>
+          <span class="badge news-sentiment-${article.sentiment} ms-2">${article.sentiment}</span>
+        </div>
+        <div class="small text-muted">
+          ${this.escapeHtml(article.publisher)} &middot; ${new Date(article.published_at).toLocaleString()}
+        </div>
+        ${article.description ? `<div class="small mt-1">${this.escapeHtml(article.description)}</div>` : ''}
+      </div>
+    `).join('');
+    }
+
+    // Screener
+
+    async openScreener() {
+        const strategySelect = document.getElementById("screener-strategy");
+        strategySelect.innerHTML = this.strategies.map(strategy =>
+            `<option value="${strategy.id}">${this.escapeHtml(strategy.name)}</option>`
+        ).join('');
+
+        bootstrap.Modal.getOrCreateInstance(document.getElementById("screener-modal")).show();
+
+        try {
+            const response = await fetch("/api/screener/screens");
+            if (!response.ok) throw new Error(`HTTP ${response.status}`);
+            const data = await response.json();
+            this.savedScreens = data.screens || [];
+            document.getElementById("screener-saved").innerHTML = '<option value="">Custom criteria</option>' +
+                this.savedScreens.map(screen => `<option value="${screen.id}">${this.escapeHtml(screen.name)}</option>`).join('');
+        } catch (error) {
+            console.error("Failed to load saved screens:", error);
+        }
+    }
+
+    screenerCriteria() {
+        const number = (id) => parseFloat(document.getElementById(id).value) || 0;
+        return {
+            universe: document.getElementById("screener-universe").value,
+            min_price: number("screener-min-price"),
+            min_avg_volume: Math.round(number("screener-min-avg-volume")),
+            rsi_below: number("screener-rsi-below"),
+            rsi_above: number("screener-rsi-above"),
+            min_volume_ratio: number("screener-volume-ratio"),
+            near_level_percent: number("screener-near-level"),
+            match_any: document.getElementById("screener-match-any").checked
+        };
+    }
+
+    fillScreenerForm(criteria) {
+        const value = (v) => v ? v : '';
+        document.getElementById("screener-universe").value = criteria.universe || 'market';
+        document.getElementById("screener-min-price").value = value(criteria.min_price);
+        document.getElementById("screener-min-avg-volume").value = value(criteria.min_avg_volume);
+        document.getElementById("screener-rsi-below").value = value(criteria.rsi_below);
+        document.getElementById("screener-rsi-above").value = value(criteria.rsi_above);
+        document.getElementById("screener-volume-ratio").value = value(criteria.min_volume_ratio);
+        document.getElementById("screener-near-level").value = value(criteria.near_level_percent);
+        document.getElementById("screener-match-any").checked = !!criteria.match_any;
+    }
+
+    async runScreener() {
+        const status = document.getElementById("screener-status");
+        const tbody = document.getElementById("screener-results");
+        status.textContent = "Scanning... the first run loads several weeks of daily bars and can take a minute.";
+        tbody.innerHTML = '';
+
+        try {
+            const response = await fetch("/api/screener/run", {
+                method: "POST",
+                headers: { "Content-Type": "application/json" },
+                body: JSON.stringify({ criteria: this.screenerCriteria() })
+            });
+            const data = await response.json();
+            if (!response.ok) throw new Error(data.message || `HTTP ${response.status}`);
+
+            status.textContent = `${data.count} matches out of ${data.scanned} symbols (daily bars as of ${new Date(data.as_of).toLocaleDateString()})`;
+            tbody.innerHTML = data.results.map(result => this.createScreenerRow(result)).join('');
+        } catch (error) {
+            status.textContent = '';
+            this.showError("Screener failed: " + error.message);
+        }
+    }
+
+    createScreenerRow(result) {
+        const changeClass = result.change_percent > 0 ? 'price-positive' : result.change_percent < 0 ? 'price-negative' : 'price-neutral';
+        const action = result.in_watchlist
+            ? '<span class="text-muted small">Watching</span>'
+            : `<button class="btn btn-outline-success btn-sm" onclick="window.watchlist.addScreenerResult('${result.symbol}', this)">
+            <i class="bi bi-plus-circle"></i> Add
+          </button>`;
+
+        return `
+      <tr>
+        <td><strong>${result.symbol}</strong></td>
+        <td>$${result.price.toFixed(2)}</td>
+        <td class="${changeClass}">${result.change_percent.toFixed(2)}%</td>
+        <td>${this.formatVolume(result.volume)} <span class="small text-muted">(${result.volume_ratio.toFixed(1)}x)</span></td>
+        <td>${result.rsi.toFixed(1)}</td>
+        <td class="small">${result.matches.map(match => this.escapeHtml(match)).join('<br>')}</td>
+        <td>${action}</td>
+      </tr>
+    `;
+    }
+
+    async addScreenerResult(symbol, button) {
+        const strategyId = parseInt(document.getElementById("screener-strategy").value);
+        if (!strategyId) {
+            this.showError("Create a strategy first to add screener results");
+            return;
+        }
+
+        try {
+            const response = await fetch("/api/screener/watch", {
+                method: "POST",
+                headers: { "Content-Type": "application/json" },
+                body: JSON.stringify({ symbol, strategy_id: strategyId })
+            });
+            const data = await response.json();
+            if (!response.ok) throw new Error(data.message || `HTTP ${response.status}`);
+
+            button.outerHTML = '<span class="text-muted small">Watching</span>';
+            this.showSuccess(`${symbol} added to watchlist`);
+            await Promise.all([this.loadStocks(), this.loadStrategies()]);
+        } catch (error) {
+            this.showError(`Failed to add ${symbol}: ${error.message}`);
+        }
+    }
+
+    async saveScreen() {
+        const name = document.getElementById("screener-save-name").value.trim();
+        if (!name) {
+            this.showError("Enter a name to save the screen");
+            return;
+        }
+
+        try {
+            const response = await fetch("/api/screener/screens", {
+                method: "POST",
+                headers: { "Content-Type": "application/json" },
+                body: JSON.stringify({ name, criteria: this.screenerCriteria() })
+            });
+            const data = await response.json();
+            if (!response.ok) throw new Error(data.message || `HTTP ${response.status}`);
+
+            this.showSuccess(`Screen "${name}" saved`);
+            await this.openScreener();
+        } catch (error) {
+            this.showError("Failed to save screen: " + error.message);
+        }
+    }
+
+    parseTags(value) {
+        return value.split(',').map(tag => tag.trim().toLowerCase()).filter(tag => tag);
+    }
+
+    escapeHtml(text) {
+        const div = document.createElement("div");
+        div.textContent = text || '';
+        return div.innerHTML;
     }
 
     // --- User Feedback Helpers ---